@@ -0,0 +1,233 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"os"
+	"os/signal"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/mutagen-io/mutagen/cmd"
+
+	"github.com/mutagen-io/mutagen/pkg/agent"
+	forwardingremote "github.com/mutagen-io/mutagen/pkg/forwarding/endpoint/remote"
+	"github.com/mutagen-io/mutagen/pkg/logging"
+	"github.com/mutagen-io/mutagen/pkg/mutagen"
+	synchronizationremote "github.com/mutagen-io/mutagen/pkg/synchronization/endpoint/remote"
+)
+
+const (
+	// listenHandshakeTimeout is the maximum amount of time allowed for a client
+	// to complete authentication and handshaking.
+	listenHandshakeTimeout = 10 * time.Second
+	// listenMaximumPendingConnections is the maximum number of connections
+	// that may be undergoing authentication and handshaking at any one time.
+	// Connections accepted beyond this limit are closed immediately.
+	listenMaximumPendingConnections = 32
+)
+
+// handshakeConnection performs authentication and handshaking on a TCP
+// connection, returning the resulting encrypted stream and the requested mode.
+func handshakeConnection(connection net.Conn, key []byte) (net.Conn, string, error) {
+	// Bound the time allowed for authentication and handshaking.
+	if err := connection.SetDeadline(time.Now().Add(listenHandshakeTimeout)); err != nil {
+		return nil, "", fmt.Errorf("unable to set handshake deadline: %w", err)
+	}
+
+	// Perform authentication.
+	stream, mode, err := agent.TCPServerAuthenticate(connection, key)
+	if err != nil {
+		return nil, "", fmt.Errorf("authentication failed: %w", err)
+	}
+
+	// Perform an agent handshake.
+	if err := agent.ServerHandshake(stream); err != nil {
+		return nil, "", fmt.Errorf("server handshake failed: %w", err)
+	}
+
+	// Perform a version handshake.
+	if err := mutagen.ServerVersionHandshake(stream); err != nil {
+		return nil, "", fmt.Errorf("version handshake error: %w", err)
+	}
+
+	// Clear the deadline.
+	if err := connection.SetDeadline(time.Time{}); err != nil {
+		return nil, "", fmt.Errorf("unable to clear handshake deadline: %w", err)
+	}
+
+	// Success.
+	return stream, mode, nil
+}
+
+// serveConnection authenticates and serves a single TCP connection. If pending
+// is non-nil, then a value is received from it once authentication and
+// handshaking have completed (successfully or otherwise), releasing the slot
+// that the connection occupied.
+func serveConnection(logger *logging.Logger, connection net.Conn, key []byte, pending chan struct{}) error {
+	// Ensure that the connection is closed when we're done.
+	defer connection.Close()
+
+	// Perform authentication and handshaking.
+	stream, mode, err := handshakeConnection(connection, key)
+	if pending != nil {
+		<-pending
+	}
+	if err != nil {
+		return err
+	}
+
+	// Serve the requested endpoint type.
+	logger.Infof("Serving %s for %s", mode, connection.RemoteAddr())
+	switch mode {
+	case agent.CommandSynchronizer:
+		return synchronizationremote.ServeEndpoint(logger, stream)
+	case agent.CommandForwarder:
+		return forwardingremote.ServeEndpoint(logger, stream)
+	default:
+		return fmt.Errorf("unsupported mode: %s", mode)
+	}
+}
+
+// loadKey loads the pre-shared key, either from the specified file or (if no
+// file is specified) from the environment. The flag name is used for error
+// reporting.
+func loadKey(keyFile, flag string) ([]byte, error) {
+	if keyFile != "" {
+		key, err := agent.LoadTCPKey(keyFile)
+		if err != nil {
+			return nil, fmt.Errorf("unable to load key: %w", err)
+		}
+		return key, nil
+	} else if value, ok := os.LookupEnv(agent.TCPKeyEnvironmentVariable); ok {
+		key, err := agent.ParseTCPKey([]byte(value))
+		if err != nil {
+			return nil, fmt.Errorf("invalid key in environment: %w", err)
+		}
+		return key, nil
+	}
+	return nil, fmt.Errorf("no key specified (use --%s or %s)", flag, agent.TCPKeyEnvironmentVariable)
+}
+
+// listenMain is the entry point for the listen command.
+func listenMain(_ *cobra.Command, _ []string) error {
+	// Create a channel to track termination signals. We do this before creating
+	// and starting other infrastructure so that we can ensure things terminate
+	// smoothly, not mid-initialization.
+	signalTermination := make(chan os.Signal, 1)
+	signal.Notify(signalTermination, cmd.TerminationSignals...)
+
+	// Set up a logger on the standard error stream.
+	logLevel := logging.LevelInfo
+	if listenConfiguration.logLevel != "" {
+		if l, ok := logging.NameToLevel(listenConfiguration.logLevel); !ok {
+			return fmt.Errorf("invalid log level specified: %s", listenConfiguration.logLevel)
+		} else {
+			logLevel = l
+		}
+	}
+	logger := logging.NewLogger(logLevel, os.Stderr)
+
+	// Load the pre-shared key.
+	key, err := loadKey(listenConfiguration.keyFile, agent.FlagListenKeyFile)
+	if err != nil {
+		return err
+	}
+
+	// Validate the listening address.
+	if listenConfiguration.address == "" {
+		return errors.New("no listening address specified")
+	}
+
+	// Set up regular housekeeping and defer its shutdown.
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go housekeepRegularly(ctx, logger.Sublogger("housekeeping"))
+
+	// Create the listener and defer its closure.
+	listener, err := net.Listen("tcp", listenConfiguration.address)
+	if err != nil {
+		return fmt.Errorf("unable to create listener: %w", err)
+	}
+	defer listener.Close()
+	logger.Info("Listening on", listener.Addr())
+
+	// Accept and serve connections in a background Goroutine, limiting the
+	// number of connections that can be pending authentication so that
+	// unauthenticated clients can't exhaust resources.
+	listenerTermination := make(chan error, 1)
+	go func() {
+		connectionLogger := logger.Sublogger("connection")
+		pending := make(chan struct{}, listenMaximumPendingConnections)
+		for {
+			connection, err := listener.Accept()
+			if err != nil {
+				listenerTermination <- err
+				return
+			}
+			select {
+			case pending <- struct{}{}:
+			default:
+				connectionLogger.Warnf("Rejecting connection from %s: too many pending connections", connection.RemoteAddr())
+				connection.Close()
+				continue
+			}
+			go func() {
+				if err := serveConnection(connectionLogger, connection, key, pending); err != nil {
+					connectionLogger.Warnf("Connection from %s terminated: %v", connection.RemoteAddr(), err)
+				}
+			}()
+		}
+	}()
+
+	// Wait for termination from a signal or the listener.
+	select {
+	case sig := <-signalTermination:
+		return fmt.Errorf("terminated by signal: %s", sig)
+	case err := <-listenerTermination:
+		return fmt.Errorf("listener terminated: %w", err)
+	}
+}
+
+// listenCommand is the listen command.
+var listenCommand = &cobra.Command{
+	Use:          agent.CommandListen,
+	Short:        "Run the agent persistently, serving endpoints over TCP",
+	Args:         cmd.DisallowArguments,
+	RunE:         listenMain,
+	SilenceUsage: true,
+}
+
+// listenConfiguration stores configuration for the listen command.
+var listenConfiguration struct {
+	// help indicates whether or not to show help information and exit.
+	help bool
+	// address is the TCP address on which to listen.
+	address string
+	// keyFile is the path to the pre-shared key file.
+	keyFile string
+	// logLevel indicates the log level to use.
+	logLevel string
+}
+
+func init() {
+	// Grab a handle for the command line flags.
+	flags := listenCommand.Flags()
+
+	// Disable alphabetical sorting of flags in help output.
+	flags.SortFlags = false
+
+	// Manually add a help flag to override the default message. Cobra will
+	// still implement its logic automatically.
+	flags.BoolVarP(&listenConfiguration.help, "help", "h", false, "Show help information")
+
+	// Wire up listener flags.
+	flags.StringVar(&listenConfiguration.address, agent.FlagListenAddress, "", "Specify the TCP address on which to listen (e.g. :7820)")
+	flags.StringVar(&listenConfiguration.keyFile, agent.FlagListenKeyFile, "", "Specify the path to the pre-shared key file")
+
+	// Wire up logging flags.
+	flags.StringVar(&listenConfiguration.logLevel, agent.FlagLogLevel, "", "Set the log level")
+}
@@ -1,10 +1,15 @@
 package main
 
 import (
+	"errors"
+	"fmt"
 	"os"
+	"path/filepath"
 
 	"github.com/spf13/cobra"
 
+	"github.com/mutagen-io/mutagen/pkg/agent"
+	"github.com/mutagen-io/mutagen/pkg/filesystem"
 	"github.com/mutagen-io/mutagen/pkg/mutagen"
 )
 
@@ -20,19 +25,38 @@ func rootMain(command *cobra.Command, _ []string) error {
 	return nil
 }
 
+// rootPersistentPreRunE performs setup common to all agent commands.
+func rootPersistentPreRunE(_ *cobra.Command, _ []string) error {
+	// If a data directory has been specified, then validate it and make it the
+	// Mutagen data directory for this process.
+	if rootConfiguration.dataDirectory != "" {
+		if !filepath.IsAbs(rootConfiguration.dataDirectory) {
+			return errors.New("data directory path is not absolute")
+		} else if err := os.Setenv(filesystem.MutagenDataDirectoryEnvironmentVariable, rootConfiguration.dataDirectory); err != nil {
+			return fmt.Errorf("unable to set data directory: %w", err)
+		}
+	}
+
+	// Success.
+	return nil
+}
+
 // rootCommand is the root command.
 var rootCommand = &cobra.Command{
-	Use:          "mutagen-agent",
-	Version:      mutagen.Version,
-	Short:        "The Mutagen agent should not be invoked by human beings",
-	RunE:         rootMain,
-	SilenceUsage: true,
+	Use:               "mutagen-agent",
+	Version:           mutagen.Version,
+	Short:             "The Mutagen agent should not be invoked by human beings",
+	RunE:              rootMain,
+	PersistentPreRunE: rootPersistentPreRunE,
+	SilenceUsage:      true,
 }
 
 // rootConfiguration stores configuration for the root command.
 var rootConfiguration struct {
 	// help indicates whether or not to show help information and exit.
 	help bool
+	// dataDirectory is the Mutagen data directory to use, if any.
+	dataDirectory string
 }
 
 func init() {
@@ -57,12 +81,19 @@ func init() {
 	// still implement its logic automatically.
 	flags.BoolVarP(&rootConfiguration.help, "help", "h", false, "Show help information")
 
+	// Wire up data directory flags. These apply to all commands.
+	rootCommand.PersistentFlags().StringVar(&rootConfiguration.dataDirectory, agent.FlagDataDirectory, "", "Specify the Mutagen data directory")
+
 	// Register commands. We do this here (rather than in individual init
 	// functions) so that we can control the order.
 	rootCommand.AddCommand(
 		installCommand,
+		upgradeCommand,
 		synchronizerCommand,
 		forwarderCommand,
+		multiplexerCommand,
+		listenCommand,
+		rendezvousCommand,
 		versionCommand,
 		legalCommand,
 	)
@@ -0,0 +1,153 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/signal"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/mutagen-io/mutagen/cmd"
+
+	"github.com/mutagen-io/mutagen/pkg/agent"
+	forwardingremote "github.com/mutagen-io/mutagen/pkg/forwarding/endpoint/remote"
+	"github.com/mutagen-io/mutagen/pkg/logging"
+	"github.com/mutagen-io/mutagen/pkg/multiplexing"
+	"github.com/mutagen-io/mutagen/pkg/mutagen"
+	synchronizationremote "github.com/mutagen-io/mutagen/pkg/synchronization/endpoint/remote"
+)
+
+const (
+	// multiplexerModeTimeout is the maximum amount of time allowed for a
+	// client to request a mode on a newly opened stream.
+	multiplexerModeTimeout = 10 * time.Second
+)
+
+// serveMultiplexedStream serves a single endpoint on a multiplexed stream.
+func serveMultiplexedStream(logger *logging.Logger, stream *multiplexing.Stream) error {
+	// Ensure that the stream is closed when we're done.
+	defer stream.Close()
+
+	// Receive the requested mode.
+	if err := stream.SetReadDeadline(time.Now().Add(multiplexerModeTimeout)); err != nil {
+		return fmt.Errorf("unable to set mode deadline: %w", err)
+	}
+	mode, err := agent.ReceiveMode(stream)
+	if err != nil {
+		return err
+	}
+	if err := stream.SetReadDeadline(time.Time{}); err != nil {
+		return fmt.Errorf("unable to clear mode deadline: %w", err)
+	}
+
+	// Serve the requested endpoint type.
+	switch mode {
+	case agent.CommandSynchronizer:
+		return synchronizationremote.ServeEndpoint(logger, stream)
+	case agent.CommandForwarder:
+		return forwardingremote.ServeEndpoint(logger, stream)
+	default:
+		return fmt.Errorf("unsupported mode: %s", mode)
+	}
+}
+
+// multiplexerMain is the entry point for the multiplexer command.
+func multiplexerMain(_ *cobra.Command, _ []string) error {
+	// Create a channel to track termination signals. We do this before creating
+	// and starting other infrastructure so that we can ensure things terminate
+	// smoothly, not mid-initialization.
+	signalTermination := make(chan os.Signal, 1)
+	signal.Notify(signalTermination, cmd.TerminationSignals...)
+
+	// Set up a logger on the standard error stream.
+	logLevel := logging.LevelInfo
+	if multiplexerConfiguration.logLevel != "" {
+		if l, ok := logging.NameToLevel(multiplexerConfiguration.logLevel); !ok {
+			return fmt.Errorf("invalid log level specified: %s", multiplexerConfiguration.logLevel)
+		} else {
+			logLevel = l
+		}
+	}
+	logger := logging.NewLogger(logLevel, os.Stderr)
+
+	// Set up regular housekeeping and defer its shutdown.
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go housekeepRegularly(ctx, logger.Sublogger("housekeeping"))
+
+	// Create a stream using standard input/output.
+	stream := newStdioStream()
+
+	// Perform an agent handshake.
+	if err := agent.ServerHandshake(stream); err != nil {
+		return fmt.Errorf("server handshake failed: %w", err)
+	}
+
+	// Perform a version handshake.
+	if err := mutagen.ServerVersionHandshake(stream); err != nil {
+		return fmt.Errorf("version handshake error: %w", err)
+	}
+
+	// Multiplex standard input/output.
+	multiplexer := multiplexing.Multiplex(multiplexing.NewCarrierFromStream(stream), true, nil)
+
+	// Accept and serve streams in a background Goroutine.
+	multiplexerTermination := make(chan error, 1)
+	go func() {
+		streamLogger := logger.Sublogger("stream")
+		for {
+			stream, err := multiplexer.AcceptStream(ctx)
+			if err != nil {
+				multiplexerTermination <- err
+				return
+			}
+			go func() {
+				if err := serveMultiplexedStream(streamLogger, stream); err != nil {
+					streamLogger.Debug("Stream terminated:", err)
+				}
+			}()
+		}
+	}()
+
+	// Wait for termination from a signal or the multiplexer.
+	select {
+	case sig := <-signalTermination:
+		return fmt.Errorf("terminated by signal: %s", sig)
+	case err := <-multiplexerTermination:
+		return fmt.Errorf("multiplexer terminated: %w", err)
+	}
+}
+
+// multiplexerCommand is the multiplexer command.
+var multiplexerCommand = &cobra.Command{
+	Use:          agent.CommandMultiplexer,
+	Short:        "Run the agent in multiplexer mode, serving multiple endpoints",
+	Args:         cmd.DisallowArguments,
+	RunE:         multiplexerMain,
+	SilenceUsage: true,
+}
+
+// multiplexerConfiguration stores configuration for the multiplexer command.
+var multiplexerConfiguration struct {
+	// help indicates whether or not to show help information and exit.
+	help bool
+	// logLevel indicates the log level to use.
+	logLevel string
+}
+
+func init() {
+	// Grab a handle for the command line flags.
+	flags := multiplexerCommand.Flags()
+
+	// Disable alphabetical sorting of flags in help output.
+	flags.SortFlags = false
+
+	// Manually add a help flag to override the default message. Cobra will
+	// still implement its logic automatically.
+	flags.BoolVarP(&multiplexerConfiguration.help, "help", "h", false, "Show help information")
+
+	// Wire up logging flags.
+	flags.StringVar(&multiplexerConfiguration.logLevel, agent.FlagLogLevel, "", "Set the log level")
+}
@@ -0,0 +1,157 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"os"
+	"os/signal"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/mutagen-io/mutagen/cmd"
+
+	"github.com/mutagen-io/mutagen/pkg/agent"
+	"github.com/mutagen-io/mutagen/pkg/logging"
+	"github.com/mutagen-io/mutagen/pkg/url"
+)
+
+const (
+	// rendezvousInitialRetryDelay is the initial delay between failed attempts
+	// to connect to the rendezvous listener.
+	rendezvousInitialRetryDelay = time.Second
+	// rendezvousMaximumRetryDelay is the maximum delay between failed attempts
+	// to connect to the rendezvous listener.
+	rendezvousMaximumRetryDelay = 30 * time.Second
+)
+
+// dialRendezvousRegularly maintains a pending connection to the rendezvous
+// listener at the specified address, serving each connection once it's claimed
+// and immediately dialing a replacement. It runs until the context is
+// cancelled.
+func dialRendezvousRegularly(ctx context.Context, logger *logging.Logger, address, name string, key []byte) {
+	// Create a dialer.
+	dialer := &net.Dialer{}
+
+	// Loop until cancelled.
+	retryDelay := rendezvousInitialRetryDelay
+	for {
+		// Dial the listener and wait for the connection to be claimed. If
+		// that's successful, then serve the connection in the background.
+		connection, err := dialer.DialContext(ctx, "tcp", address)
+		if err == nil {
+			logger.Debug("Connected to rendezvous listener, awaiting claim")
+			if err = agent.RendezvousAwaitClaim(connection, name, key); err == nil {
+				retryDelay = rendezvousInitialRetryDelay
+				go func() {
+					if err := serveConnection(logger, connection, key, nil); err != nil {
+						logger.Warnf("Connection to %s terminated: %v", connection.RemoteAddr(), err)
+					}
+				}()
+				continue
+			}
+			connection.Close()
+		}
+
+		// Handle the failure by waiting before retrying.
+		logger.Warnf("Rendezvous failed (retrying in %v): %v", retryDelay, err)
+		select {
+		case <-time.After(retryDelay):
+		case <-ctx.Done():
+			return
+		}
+		if retryDelay *= 2; retryDelay > rendezvousMaximumRetryDelay {
+			retryDelay = rendezvousMaximumRetryDelay
+		}
+	}
+}
+
+// rendezvousMain is the entry point for the rendezvous command.
+func rendezvousMain(_ *cobra.Command, _ []string) error {
+	// Create a channel to track termination signals. We do this before creating
+	// and starting other infrastructure so that we can ensure things terminate
+	// smoothly, not mid-initialization.
+	signalTermination := make(chan os.Signal, 1)
+	signal.Notify(signalTermination, cmd.TerminationSignals...)
+
+	// Set up a logger on the standard error stream.
+	logLevel := logging.LevelInfo
+	if rendezvousConfiguration.logLevel != "" {
+		if l, ok := logging.NameToLevel(rendezvousConfiguration.logLevel); !ok {
+			return fmt.Errorf("invalid log level specified: %s", rendezvousConfiguration.logLevel)
+		} else {
+			logLevel = l
+		}
+	}
+	logger := logging.NewLogger(logLevel, os.Stderr)
+
+	// Load the pre-shared key.
+	key, err := loadKey(rendezvousConfiguration.keyFile, agent.FlagRendezvousKeyFile)
+	if err != nil {
+		return err
+	}
+
+	// Validate the rendezvous address and name.
+	if rendezvousConfiguration.address == "" {
+		return errors.New("no rendezvous address specified")
+	} else if !url.IsValidRendezvousName(rendezvousConfiguration.name) {
+		return fmt.Errorf("invalid rendezvous name: %s", rendezvousConfiguration.name)
+	}
+
+	// Set up regular housekeeping and rendezvous, and defer their shutdown.
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go housekeepRegularly(ctx, logger.Sublogger("housekeeping"))
+	go dialRendezvousRegularly(ctx, logger.Sublogger("rendezvous"),
+		rendezvousConfiguration.address, rendezvousConfiguration.name, key,
+	)
+
+	// Wait for termination.
+	sig := <-signalTermination
+	return fmt.Errorf("terminated by signal: %s", sig)
+}
+
+// rendezvousCommand is the rendezvous command.
+var rendezvousCommand = &cobra.Command{
+	Use:          agent.CommandRendezvous,
+	Short:        "Run the agent persistently, dialing out to a daemon's rendezvous listener",
+	Args:         cmd.DisallowArguments,
+	RunE:         rendezvousMain,
+	SilenceUsage: true,
+}
+
+// rendezvousConfiguration stores configuration for the rendezvous command.
+var rendezvousConfiguration struct {
+	// help indicates whether or not to show help information and exit.
+	help bool
+	// address is the TCP address of the daemon's rendezvous listener.
+	address string
+	// name is the name under which to register.
+	name string
+	// keyFile is the path to the pre-shared key file.
+	keyFile string
+	// logLevel indicates the log level to use.
+	logLevel string
+}
+
+func init() {
+	// Grab a handle for the command line flags.
+	flags := rendezvousCommand.Flags()
+
+	// Disable alphabetical sorting of flags in help output.
+	flags.SortFlags = false
+
+	// Manually add a help flag to override the default message. Cobra will
+	// still implement its logic automatically.
+	flags.BoolVarP(&rendezvousConfiguration.help, "help", "h", false, "Show help information")
+
+	// Wire up rendezvous flags.
+	flags.StringVar(&rendezvousConfiguration.address, agent.FlagRendezvousAddress, "", "Specify the TCP address of the daemon's rendezvous listener")
+	flags.StringVar(&rendezvousConfiguration.name, agent.FlagRendezvousName, "", "Specify the name under which to register")
+	flags.StringVar(&rendezvousConfiguration.keyFile, agent.FlagRendezvousKeyFile, "", "Specify the path to the pre-shared key file")
+
+	// Wire up logging flags.
+	flags.StringVar(&rendezvousConfiguration.logLevel, agent.FlagLogLevel, "", "Set the log level")
+}
@@ -0,0 +1,77 @@
+package main
+
+import (
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"fmt"
+
+	"github.com/spf13/cobra"
+
+	"github.com/mutagen-io/mutagen/cmd"
+
+	"github.com/mutagen-io/mutagen/pkg/agent"
+)
+
+// upgradeMain is the entry point for the upgrade command.
+func upgradeMain(_ *cobra.Command, _ []string) error {
+	// Decode and validate arguments.
+	url, err := base64.RawURLEncoding.DecodeString(upgradeConfiguration.url)
+	if err != nil {
+		return fmt.Errorf("invalid URL encoding: %w", err)
+	} else if len(url) == 0 {
+		return errors.New("empty URL")
+	}
+	digest, err := hex.DecodeString(upgradeConfiguration.digest)
+	if err != nil {
+		return fmt.Errorf("invalid digest encoding: %w", err)
+	} else if len(digest) == 0 {
+		return errors.New("empty digest")
+	}
+
+	// Perform the upgrade.
+	if err := agent.Upgrade(string(url), digest, upgradeConfiguration.version); err != nil {
+		return fmt.Errorf("upgrade error: %w", err)
+	}
+
+	// Success.
+	return nil
+}
+
+// upgradeCommand is the upgrade command.
+var upgradeCommand = &cobra.Command{
+	Use:          agent.CommandUpgrade,
+	Short:        "Perform in-place agent upgrade",
+	Args:         cmd.DisallowArguments,
+	RunE:         upgradeMain,
+	SilenceUsage: true,
+}
+
+// upgradeConfiguration stores configuration for the upgrade command.
+var upgradeConfiguration struct {
+	// help indicates whether or not to show help information and exit.
+	help bool
+	// url is the base64url-encoded download URL.
+	url string
+	// digest is the hex-encoded SHA-256 digest of the agent executable.
+	digest string
+	// version is the expected agent version.
+	version string
+}
+
+func init() {
+	// Grab a handle for the command line flags.
+	flags := upgradeCommand.Flags()
+
+	// Disable alphabetical sorting of flags in help output.
+	flags.SortFlags = false
+
+	// Manually add a help flag to override the default message. Cobra will
+	// still implement its logic automatically.
+	flags.BoolVarP(&upgradeConfiguration.help, "help", "h", false, "Show help information")
+
+	// Wire up upgrade flags.
+	flags.StringVar(&upgradeConfiguration.url, agent.FlagUpgradeURL, "", "Specify the encoded download URL")
+	flags.StringVar(&upgradeConfiguration.digest, agent.FlagUpgradeDigest, "", "Specify the expected executable digest")
+	flags.StringVar(&upgradeConfiguration.version, agent.FlagUpgradeVersion, "", "Specify the expected executable version")
+}
@@ -1,7 +1,9 @@
 package daemon
 
 import (
+	"context"
 	"fmt"
+	"net"
 	"os"
 	"os/signal"
 
@@ -11,6 +13,8 @@ import (
 
 	"github.com/mutagen-io/mutagen/cmd"
 
+	"github.com/mutagen-io/mutagen/pkg/agent"
+	"github.com/mutagen-io/mutagen/pkg/connectivity"
 	"github.com/mutagen-io/mutagen/pkg/daemon"
 	"github.com/mutagen-io/mutagen/pkg/forwarding"
 	"github.com/mutagen-io/mutagen/pkg/grpcutil"
@@ -22,12 +26,32 @@ import (
 	synchronizationsvc "github.com/mutagen-io/mutagen/pkg/service/synchronization"
 	"github.com/mutagen-io/mutagen/pkg/synchronization"
 
+	_ "github.com/mutagen-io/mutagen/pkg/forwarding/protocols/azure"
 	_ "github.com/mutagen-io/mutagen/pkg/forwarding/protocols/docker"
+	_ "github.com/mutagen-io/mutagen/pkg/forwarding/protocols/exec"
+	_ "github.com/mutagen-io/mutagen/pkg/forwarding/protocols/kubernetes"
 	_ "github.com/mutagen-io/mutagen/pkg/forwarding/protocols/local"
+	_ "github.com/mutagen-io/mutagen/pkg/forwarding/protocols/lxd"
+	_ "github.com/mutagen-io/mutagen/pkg/forwarding/protocols/plugin"
+	_ "github.com/mutagen-io/mutagen/pkg/forwarding/protocols/rendezvous"
 	_ "github.com/mutagen-io/mutagen/pkg/forwarding/protocols/ssh"
+	_ "github.com/mutagen-io/mutagen/pkg/forwarding/protocols/tcp"
+	_ "github.com/mutagen-io/mutagen/pkg/forwarding/protocols/teleport"
+	_ "github.com/mutagen-io/mutagen/pkg/forwarding/protocols/wsl"
+	_ "github.com/mutagen-io/mutagen/pkg/synchronization/protocols/azure"
 	_ "github.com/mutagen-io/mutagen/pkg/synchronization/protocols/docker"
+	_ "github.com/mutagen-io/mutagen/pkg/synchronization/protocols/exec"
+	_ "github.com/mutagen-io/mutagen/pkg/synchronization/protocols/kubernetes"
 	_ "github.com/mutagen-io/mutagen/pkg/synchronization/protocols/local"
+	_ "github.com/mutagen-io/mutagen/pkg/synchronization/protocols/lxd"
+	_ "github.com/mutagen-io/mutagen/pkg/synchronization/protocols/plugin"
+	_ "github.com/mutagen-io/mutagen/pkg/synchronization/protocols/rendezvous"
+	_ "github.com/mutagen-io/mutagen/pkg/synchronization/protocols/s3"
+	_ "github.com/mutagen-io/mutagen/pkg/synchronization/protocols/sftp"
 	_ "github.com/mutagen-io/mutagen/pkg/synchronization/protocols/ssh"
+	_ "github.com/mutagen-io/mutagen/pkg/synchronization/protocols/tcp"
+	_ "github.com/mutagen-io/mutagen/pkg/synchronization/protocols/teleport"
+	_ "github.com/mutagen-io/mutagen/pkg/synchronization/protocols/wsl"
 )
 
 // runMain is the entry point for the run command.
@@ -70,6 +94,16 @@ func runMain(_ *cobra.Command, _ []string) error {
 	}
 	defer synchronizationManager.Shutdown()
 
+	// Start monitoring for network configuration changes and system wake
+	// events, which are likely to have invalidated existing connections, and
+	// defer termination of monitoring.
+	connectivityCtx, stopConnectivityMonitoring := context.WithCancel(context.Background())
+	defer stopConnectivityMonitoring()
+	go connectivity.Monitor(connectivityCtx, logger.Sublogger("connectivity"), func(_ connectivity.Change) {
+		forwardingManager.HandleConnectivityChange()
+		synchronizationManager.HandleConnectivityChange()
+	})
+
 	// Create the gRPC server and defer its termination. We use a hard stop
 	// rather than a graceful stop so that it doesn't hang on open requests.
 	server := grpc.NewServer(
@@ -116,8 +150,25 @@ func runMain(_ *cobra.Command, _ []string) error {
 		serverErrors <- server.Serve(listener)
 	}()
 
-	// Wait for termination from a signal, the daemon service, or the gRPC
-	// server. We treat termination via the daemon service as a non-error.
+	// If a rendezvous address has been specified, then create the rendezvous
+	// listener (which agents behind NAT or firewalls can dial out to), defer
+	// its closure, and serve it in the background.
+	rendezvousErrors := make(chan error, 1)
+	if address := os.Getenv("MUTAGEN_RENDEZVOUS_ADDRESS"); address != "" {
+		rendezvousListener, err := net.Listen("tcp", address)
+		if err != nil {
+			return fmt.Errorf("unable to create rendezvous listener: %w", err)
+		}
+		defer rendezvousListener.Close()
+		logger.Info("Accepting agent rendezvous connections on", rendezvousListener.Addr())
+		go func() {
+			rendezvousErrors <- agent.DefaultRendezvous.Serve(logger.Sublogger("rendezvous"), rendezvousListener)
+		}()
+	}
+
+	// Wait for termination from a signal, the daemon service, the gRPC server,
+	// or the rendezvous listener. We treat termination via the daemon service
+	// as a non-error.
 	select {
 	case sig := <-signalTermination:
 		logger.Info("Terminating due to signal:", sig)
@@ -128,6 +179,9 @@ func runMain(_ *cobra.Command, _ []string) error {
 	case err = <-serverErrors:
 		logger.Error("Daemon server failure:", err)
 		return fmt.Errorf("daemon server termination: %w", err)
+	case err = <-rendezvousErrors:
+		logger.Error("Rendezvous listener failure:", err)
+		return fmt.Errorf("rendezvous listener termination: %w", err)
 	}
 }
 
@@ -7,6 +7,8 @@ import (
 	"os"
 	"strings"
 
+	"github.com/dustin/go-humanize"
+
 	"github.com/spf13/cobra"
 
 	"google.golang.org/grpc"
@@ -26,14 +28,20 @@ import (
 
 // loadAndValidateGlobalSynchronizationConfiguration loads a YAML-based global
 // configuration, extracts the forwarding component, converts it to a Protocol
-// Buffers session configuration, and validates it.
-func loadAndValidateGlobalForwardingConfiguration(path string) (*forwarding.Configuration, error) {
+// Buffers session configuration, and validates it. It also applies the global
+// configuration's SSH settings to the specified URLs.
+func loadAndValidateGlobalForwardingConfiguration(path string, urls ...*url.URL) (*forwarding.Configuration, error) {
 	// Load the YAML configuration.
 	yamlConfiguration, err := global.LoadConfiguration(path)
 	if err != nil {
 		return nil, err
 	}
 
+	// Apply SSH settings to URLs.
+	for _, u := range urls {
+		yamlConfiguration.ConfigureURL(u)
+	}
+
 	// Convert the YAML configuration to a Protocol Buffers representation and
 	// validate it.
 	configuration := yamlConfiguration.Forwarding.Defaults.ToInternal()
@@ -140,7 +148,7 @@ func createMain(_ *cobra.Command, arguments []string) error {
 		}
 
 		// Attempt to load the file. We allow it to not exist.
-		globalConfiguration, err := loadAndValidateGlobalForwardingConfiguration(globalConfigurationPath)
+		globalConfiguration, err := loadAndValidateGlobalForwardingConfiguration(globalConfigurationPath, source, destination)
 		if err != nil {
 			if !os.IsNotExist(err) {
 				return fmt.Errorf("unable to load global configuration: %w", err)
@@ -242,13 +250,92 @@ func createMain(_ *cobra.Command, arguments []string) error {
 		}
 	}
 
+	// Validate and convert TLS mode specifications.
+	var tlsMode, tlsModeSource, tlsModeDestination forwarding.TLSMode
+	if createConfiguration.tlsMode != "" {
+		if err := tlsMode.UnmarshalText([]byte(createConfiguration.tlsMode)); err != nil {
+			return fmt.Errorf("unable to parse TLS mode: %w", err)
+		}
+	}
+	if createConfiguration.tlsModeSource != "" {
+		if err := tlsModeSource.UnmarshalText([]byte(createConfiguration.tlsModeSource)); err != nil {
+			return fmt.Errorf("unable to parse TLS mode for source: %w", err)
+		}
+	}
+	if createConfiguration.tlsModeDestination != "" {
+		if err := tlsModeDestination.UnmarshalText([]byte(createConfiguration.tlsModeDestination)); err != nil {
+			return fmt.Errorf("unable to parse TLS mode for destination: %w", err)
+		}
+	}
+
+	// Validate and convert PROXY protocol mode specifications.
+	var proxyProtocolModeSource, proxyProtocolModeDestination forwarding.ProxyProtocolMode
+	if createConfiguration.proxyProtocolModeSource != "" {
+		if err := proxyProtocolModeSource.UnmarshalText([]byte(createConfiguration.proxyProtocolModeSource)); err != nil {
+			return fmt.Errorf("unable to parse PROXY protocol mode for source: %w", err)
+		}
+	}
+	if createConfiguration.proxyProtocolModeDestination != "" {
+		if err := proxyProtocolModeDestination.UnmarshalText([]byte(createConfiguration.proxyProtocolModeDestination)); err != nil {
+			return fmt.Errorf("unable to parse PROXY protocol mode for destination: %w", err)
+		}
+	}
+
+	// Validate and convert the maximum upload rate.
+	var maximumUploadRate uint64
+	if createConfiguration.maximumUploadRate != "" {
+		if r, err := humanize.ParseBytes(createConfiguration.maximumUploadRate); err != nil {
+			return fmt.Errorf("unable to parse maximum upload rate: %w", err)
+		} else {
+			maximumUploadRate = r
+		}
+	}
+
+	// Validate and convert the maximum download rate.
+	var maximumDownloadRate uint64
+	if createConfiguration.maximumDownloadRate != "" {
+		if r, err := humanize.ParseBytes(createConfiguration.maximumDownloadRate); err != nil {
+			return fmt.Errorf("unable to parse maximum download rate: %w", err)
+		} else {
+			maximumDownloadRate = r
+		}
+	}
+
+	// Normalize TLS file paths, since they'll be resolved by the daemon.
+	tlsPaths := []*string{
+		&createConfiguration.tlsCertificateSource,
+		&createConfiguration.tlsKeySource,
+		&createConfiguration.tlsCertificateAuthorityDestination,
+	}
+	for _, path := range tlsPaths {
+		if *path != "" {
+			if normalized, err := filesystem.Normalize(*path); err != nil {
+				return fmt.Errorf("unable to normalize TLS file path: %w", err)
+			} else {
+				*path = normalized
+			}
+		}
+	}
+
 	// Create the command line configuration and merge it into our cumulative
 	// configuration.
 	configuration = forwarding.MergeConfigurations(configuration, &forwarding.Configuration{
-		SocketOverwriteMode:  socketOverwriteMode,
-		SocketOwner:          createConfiguration.socketOwner,
-		SocketGroup:          createConfiguration.socketGroup,
-		SocketPermissionMode: uint32(socketPermissionMode),
+		HttpRoutes:               createConfiguration.httpRoutes,
+		MaximumConnections:       createConfiguration.maximumConnections,
+		HealthCheckInterval:      createConfiguration.healthCheckInterval,
+		HealthCheckPath:          createConfiguration.healthCheckPath,
+		BindRetryInterval:        createConfiguration.bindRetryInterval,
+		BindRetryMaximumInterval: createConfiguration.bindRetryMaximumInterval,
+		ReconnectHoldTimeout:     createConfiguration.reconnectHoldTimeout,
+		MaximumUploadRate:        maximumUploadRate,
+		MaximumDownloadRate:      maximumDownloadRate,
+		MdnsServiceName:          createConfiguration.mdnsServiceName,
+		MdnsServiceType:          createConfiguration.mdnsServiceType,
+		SocketOverwriteMode:      socketOverwriteMode,
+		SocketOwner:              createConfiguration.socketOwner,
+		SocketGroup:              createConfiguration.socketGroup,
+		SocketPermissionMode:     uint32(socketPermissionMode),
+		TlsMode:                  tlsMode,
 	})
 
 	// Create the creation specification.
@@ -261,12 +348,22 @@ func createMain(_ *cobra.Command, arguments []string) error {
 			SocketOwner:          createConfiguration.socketOwnerSource,
 			SocketGroup:          createConfiguration.socketGroupSource,
 			SocketPermissionMode: uint32(socketPermissionModeSource),
+			TlsMode:              tlsModeSource,
+			TlsCertificate:       createConfiguration.tlsCertificateSource,
+			TlsKey:               createConfiguration.tlsKeySource,
+			ProxyProtocolMode:    proxyProtocolModeSource,
+			AllowedSources:       createConfiguration.allowedSources,
+			AdditionalAddresses:  createConfiguration.additionalSourceAddresses,
 		},
 		ConfigurationDestination: &forwarding.Configuration{
-			SocketOverwriteMode:  socketOverwriteModeDestination,
-			SocketOwner:          createConfiguration.socketOwnerDestination,
-			SocketGroup:          createConfiguration.socketGroupDestination,
-			SocketPermissionMode: uint32(socketPermissionModeDestination),
+			SocketOverwriteMode:     socketOverwriteModeDestination,
+			SocketOwner:             createConfiguration.socketOwnerDestination,
+			SocketGroup:             createConfiguration.socketGroupDestination,
+			SocketPermissionMode:    uint32(socketPermissionModeDestination),
+			TlsMode:                 tlsModeDestination,
+			TlsServerName:           createConfiguration.tlsServerNameDestination,
+			TlsCertificateAuthority: createConfiguration.tlsCertificateAuthorityDestination,
+			ProxyProtocolMode:       proxyProtocolModeDestination,
 		},
 		Name:   createConfiguration.name,
 		Labels: labels,
@@ -318,6 +415,38 @@ var createConfiguration struct {
 	// configurationFile specifies a file from which to load configuration. It
 	// should be a path relative to the working directory.
 	configurationFile string
+	// httpRoutes are the HTTP routing rules for the session.
+	httpRoutes []string
+	// maximumConnections specifies the maximum number of simultaneous
+	// connections for the session.
+	maximumConnections uint64
+	// healthCheckInterval specifies the interval (in seconds) at which health
+	// checks are performed against the destination.
+	healthCheckInterval uint32
+	// healthCheckPath specifies the request path for HTTP health checks.
+	healthCheckPath string
+	// bindRetryInterval specifies the interval (in seconds) to wait before
+	// retrying failed source listener binding.
+	bindRetryInterval uint32
+	// bindRetryMaximumInterval specifies the maximum interval (in seconds) to
+	// wait between source listener binding attempts when backing off.
+	bindRetryMaximumInterval uint32
+	// reconnectHoldTimeout specifies the maximum amount of time (in seconds)
+	// that incoming connections are held while the destination reconnects.
+	reconnectHoldTimeout uint32
+	// maximumUploadRate specifies the maximum rate (per second) at which data
+	// is forwarded from source to destination, in human-friendly byte format.
+	maximumUploadRate string
+	// maximumDownloadRate specifies the maximum rate (per second) at which
+	// data is forwarded from destination to source, in human-friendly byte
+	// format.
+	maximumDownloadRate string
+	// mdnsServiceName specifies the service instance name under which the
+	// source listener should be advertised via mDNS.
+	mdnsServiceName string
+	// mdnsServiceType specifies the DNS-SD service type under which the source
+	// listener should be advertised via mDNS.
+	mdnsServiceType string
 	// socketOverwriteMode specifies the socket overwrite mode to use for the
 	// session.
 	socketOverwriteMode string
@@ -363,6 +492,39 @@ var createConfiguration struct {
 	// use for new Unix domain socket listeners on destination, taking priority
 	// over socketPermissionMode on destination if specified.
 	socketPermissionModeDestination string
+	// tlsMode specifies the TLS mode to use for the session, with
+	// endpoint-specific specifications taking priority.
+	tlsMode string
+	// tlsModeSource specifies the TLS mode to use for the source, taking
+	// priority over tlsMode on source if specified.
+	tlsModeSource string
+	// tlsModeDestination specifies the TLS mode to use for the destination,
+	// taking priority over tlsMode on destination if specified.
+	tlsModeDestination string
+	// tlsCertificateSource specifies the certificate path to use when
+	// terminating TLS on source.
+	tlsCertificateSource string
+	// tlsKeySource specifies the private key path to use when terminating TLS
+	// on source.
+	tlsKeySource string
+	// tlsServerNameDestination specifies the server name to use when
+	// originating TLS on destination.
+	tlsServerNameDestination string
+	// tlsCertificateAuthorityDestination specifies the certificate authority
+	// bundle path to use when originating TLS on destination.
+	tlsCertificateAuthorityDestination string
+	// proxyProtocolModeSource specifies the PROXY protocol mode to use for
+	// consuming headers from incoming connections on source.
+	proxyProtocolModeSource string
+	// proxyProtocolModeDestination specifies the PROXY protocol mode to use
+	// for emitting headers on outgoing connections on destination.
+	proxyProtocolModeDestination string
+	// allowedSources specifies the client addresses permitted to connect to
+	// the source listener.
+	allowedSources []string
+	// additionalSourceAddresses specifies additional addresses on which the
+	// source listener should accept connections.
+	additionalSourceAddresses []string
 }
 
 func init() {
@@ -387,10 +549,35 @@ func init() {
 	flags.BoolVar(&createConfiguration.noGlobalConfiguration, "no-global-configuration", false, "Ignore the global configuration file")
 	flags.StringVarP(&createConfiguration.configurationFile, "configuration-file", "c", "", "Specify a file from which to load additional default configuration")
 
+	// Wire up HTTP flags.
+	flags.StringSliceVar(&createConfiguration.httpRoutes, "http-route", nil, "Specify HTTP routing rules (<host>[/<prefix>]=<target> or /<prefix>=<target>)")
+
+	// Wire up connection flags.
+	flags.Uint64Var(&createConfiguration.maximumConnections, "max-connections", 0, "Specify the maximum number of simultaneous connections")
+
+	// Wire up health check flags.
+	flags.Uint32Var(&createConfiguration.healthCheckInterval, "health-check-interval", 0, "Specify destination health check interval in seconds")
+	flags.StringVar(&createConfiguration.healthCheckPath, "health-check-path", "", "Specify request path for HTTP destination health checks")
+
+	// Wire up bind retry flags.
+	flags.Uint32Var(&createConfiguration.bindRetryInterval, "bind-retry-interval", 0, "Specify source listener bind retry interval in seconds")
+	flags.Uint32Var(&createConfiguration.bindRetryMaximumInterval, "bind-retry-maximum-interval", 0, "Specify maximum source listener bind retry interval in seconds (enables exponential backoff)")
+
+	// Wire up reconnection flags.
+	flags.Uint32Var(&createConfiguration.reconnectHoldTimeout, "reconnect-hold-timeout", 0, "Specify how long (in seconds) to hold incoming connections while the destination reconnects")
+
+	// Wire up rate limiting flags.
+	flags.StringVar(&createConfiguration.maximumUploadRate, "max-upload-rate", "", "Specify the maximum per-second rate of data forwarded from source to destination (e.g. 1MB)")
+	flags.StringVar(&createConfiguration.maximumDownloadRate, "max-download-rate", "", "Specify the maximum per-second rate of data forwarded from destination to source (e.g. 1MB)")
+
+	// Wire up mDNS advertisement flags.
+	flags.StringVar(&createConfiguration.mdnsServiceName, "mdns-name", "", "Advertise the source listener via mDNS with the specified service name")
+	flags.StringVar(&createConfiguration.mdnsServiceType, "mdns-type", "", "Specify the DNS-SD service type for mDNS advertisement (defaults to _http._tcp)")
+
 	// Wire up socket flags.
-	flags.StringVar(&createConfiguration.socketOverwriteMode, "socket-overwrite-mode", "", "Specify socket overwrite mode (leave|overwrite)")
-	flags.StringVar(&createConfiguration.socketOverwriteModeSource, "socket-overwrite-mode-source", "", "Specify socket overwrite mode for source (leave|overwrite)")
-	flags.StringVar(&createConfiguration.socketOverwriteModeDestination, "socket-overwrite-mode-destination", "", "Specify socket overwrite mode for destination (leave|overwrite)")
+	flags.StringVar(&createConfiguration.socketOverwriteMode, "socket-overwrite-mode", "", "Specify socket overwrite mode (leave|overwrite|stale)")
+	flags.StringVar(&createConfiguration.socketOverwriteModeSource, "socket-overwrite-mode-source", "", "Specify socket overwrite mode for source (leave|overwrite|stale)")
+	flags.StringVar(&createConfiguration.socketOverwriteModeDestination, "socket-overwrite-mode-destination", "", "Specify socket overwrite mode for destination (leave|overwrite|stale)")
 	flags.StringVar(&createConfiguration.socketOwner, "socket-owner", "", "Specify socket owner")
 	flags.StringVar(&createConfiguration.socketOwnerSource, "socket-owner-source", "", "Specify socket owner for source")
 	flags.StringVar(&createConfiguration.socketOwnerDestination, "socket-owner-destination", "", "Specify socket owner for destination")
@@ -400,4 +587,23 @@ func init() {
 	flags.StringVar(&createConfiguration.socketPermissionMode, "socket-permission-mode", "", "Specify socket permission mode")
 	flags.StringVar(&createConfiguration.socketPermissionModeSource, "socket-permission-mode-source", "", "Specify socket permission mode for source")
 	flags.StringVar(&createConfiguration.socketPermissionModeDestination, "socket-permission-mode-destination", "", "Specify socket permission mode for destination")
+
+	// Wire up TLS flags.
+	flags.StringVar(&createConfiguration.tlsMode, "tls-mode", "", "Specify TLS mode (disabled|enabled)")
+	flags.StringVar(&createConfiguration.tlsModeSource, "tls-mode-source", "", "Specify TLS mode for source (disabled|enabled)")
+	flags.StringVar(&createConfiguration.tlsModeDestination, "tls-mode-destination", "", "Specify TLS mode for destination (disabled|enabled)")
+	flags.StringVar(&createConfiguration.tlsCertificateSource, "tls-certificate-source", "", "Specify TLS certificate for source (defaults to a development certificate)")
+	flags.StringVar(&createConfiguration.tlsKeySource, "tls-key-source", "", "Specify TLS private key for source")
+	flags.StringVar(&createConfiguration.tlsServerNameDestination, "tls-server-name-destination", "", "Specify TLS server name for destination")
+	flags.StringVar(&createConfiguration.tlsCertificateAuthorityDestination, "tls-certificate-authority-destination", "", "Specify TLS certificate authority bundle for destination")
+
+	// Wire up access control flags.
+	flags.StringSliceVar(&createConfiguration.allowedSources, "allowed-source", nil, "Specify client addresses (IP addresses or CIDR blocks) allowed to connect to the source listener")
+
+	// Wire up listening flags.
+	flags.StringSliceVar(&createConfiguration.additionalSourceAddresses, "additional-source-address", nil, "Specify additional addresses (host:port) on which the source listener should accept connections")
+
+	// Wire up PROXY protocol flags.
+	flags.StringVar(&createConfiguration.proxyProtocolModeSource, "proxy-protocol-source", "", "Specify PROXY protocol mode for consuming headers on source (disabled|enabled)")
+	flags.StringVar(&createConfiguration.proxyProtocolModeDestination, "proxy-protocol-destination", "", "Specify PROXY protocol mode for emitting headers on destination (disabled|enabled)")
 }
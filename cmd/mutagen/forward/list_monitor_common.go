@@ -2,6 +2,7 @@ package forward
 
 import (
 	"fmt"
+	"time"
 
 	"github.com/dustin/go-humanize"
 
@@ -63,6 +64,56 @@ func printEndpoint(name string, url *url.URL, configuration *forwarding.Configur
 			socketPermissionModeDescription = fmt.Sprintf("%#o", configuration.SocketPermissionMode)
 		}
 		fmt.Println("\t\tSocket permission mode:", socketPermissionModeDescription)
+
+		// Compute and print the TLS mode.
+		tlsModeDescription := configuration.TlsMode.Description()
+		if configuration.TlsMode.IsDefault() {
+			tlsModeDescription += fmt.Sprintf(" (%s)", version.DefaultTLSMode().Description())
+		}
+		fmt.Println("\t\tTLS mode:", tlsModeDescription)
+
+		// Print any TLS file and server name settings.
+		if configuration.TlsCertificate != "" {
+			fmt.Println("\t\tTLS certificate:", configuration.TlsCertificate)
+			fmt.Println("\t\tTLS key:", configuration.TlsKey)
+		}
+		if configuration.TlsServerName != "" {
+			fmt.Println("\t\tTLS server name:", configuration.TlsServerName)
+		}
+		if configuration.TlsCertificateAuthority != "" {
+			fmt.Println("\t\tTLS certificate authority:", configuration.TlsCertificateAuthority)
+		}
+
+		// Print any bind retry settings.
+		if configuration.BindRetryInterval != 0 {
+			fmt.Printf("\t\tBind retry interval: %d seconds\n", configuration.BindRetryInterval)
+		}
+		if configuration.BindRetryMaximumInterval != 0 {
+			fmt.Printf("\t\tBind retry maximum interval: %d seconds\n", configuration.BindRetryMaximumInterval)
+		}
+
+		// Compute and print the PROXY protocol mode.
+		proxyProtocolModeDescription := configuration.ProxyProtocolMode.Description()
+		if configuration.ProxyProtocolMode.IsDefault() {
+			proxyProtocolModeDescription += fmt.Sprintf(" (%s)", forwarding.ProxyProtocolMode_ProxyProtocolModeDisabled.Description())
+		}
+		fmt.Println("\t\tPROXY protocol mode:", proxyProtocolModeDescription)
+
+		// Print allowed sources, if any.
+		if len(configuration.AllowedSources) > 0 {
+			fmt.Println("\t\tAllowed sources:")
+			for _, source := range configuration.AllowedSources {
+				fmt.Printf("\t\t\t%s\n", source)
+			}
+		}
+
+		// Print additional addresses, if any.
+		if len(configuration.AdditionalAddresses) > 0 {
+			fmt.Println("\t\tAdditional addresses:")
+			for _, address := range configuration.AdditionalAddresses {
+				fmt.Printf("\t\t\t%s\n", address)
+			}
+		}
 	}
 
 	// At this point, there's no other status information that will be displayed
@@ -74,6 +125,39 @@ func printEndpoint(name string, url *url.URL, configuration *forwarding.Configur
 
 	// Print connection status.
 	fmt.Println("\tConnected:", common.FormatConnectionStatus(state.Connected))
+
+	// Print binding retry status, if any.
+	if !state.Connected && state.BindFailures > 0 {
+		bindStatus := fmt.Sprintf("%d failed attempts", state.BindFailures)
+		if state.NextBindAttempt != nil {
+			bindStatus += fmt.Sprintf(", retrying at %s",
+				state.NextBindAttempt.AsTime().Local().Format(time.RFC1123),
+			)
+		}
+		fmt.Println("\tBinding:", bindStatus)
+	}
+
+	// Print the bound address, if any.
+	if state.BoundAddress != "" {
+		fmt.Println("\tBound address:", state.BoundAddress)
+	}
+
+	// Print individual listener statuses, if any.
+	if len(state.Listeners) > 0 {
+		fmt.Println("\tListeners:")
+		for _, listener := range state.Listeners {
+			if listener.Error != "" {
+				color.Red("\t\t%s: %s\n", listener.Address, listener.Error)
+			} else {
+				fmt.Printf("\t\t%s: %s\n", listener.Address, listener.BoundAddress)
+			}
+		}
+	}
+
+	// Print the health check error, if any.
+	if state.HealthCheckError != "" {
+		color.Red("\tDestination unreachable: %s\n", state.HealthCheckError)
+	}
 }
 
 // printSession prints the configuration and status of a forwarding session and
@@ -102,11 +186,48 @@ func printSession(state *forwarding.State, mode common.SessionDisplayMode) {
 			}
 		}
 
-		// Print the configuration header and configuration.
-		// TODO: Implement this in the future if we have any session-level
-		// configuration behaviors. We currently only have endpoint-level
-		// configuration behaviors and thus we don't print any session-level
-		// configuration information like we do for synchronization.
+		// Print the configuration header and configuration. Session-level
+		// configuration is only present for some sessions, so we only print
+		// the section if it's non-empty.
+		configuration := state.Session.Configuration
+		if len(configuration.HttpRoutes) > 0 || configuration.MaximumConnections > 0 ||
+			configuration.HealthCheckInterval > 0 || configuration.ReconnectHoldTimeout > 0 ||
+			configuration.MaximumUploadRate > 0 || configuration.MaximumDownloadRate > 0 ||
+			configuration.MdnsServiceName != "" {
+			fmt.Println("Configuration:")
+			if len(configuration.HttpRoutes) > 0 {
+				fmt.Println("\tHTTP routes:")
+				for _, route := range configuration.HttpRoutes {
+					fmt.Printf("\t\t%s\n", route)
+				}
+			}
+			if configuration.MaximumConnections > 0 {
+				fmt.Println("\tMaximum connections:", configuration.MaximumConnections)
+			}
+			if configuration.HealthCheckInterval > 0 {
+				healthCheck := fmt.Sprintf("every %d seconds", configuration.HealthCheckInterval)
+				if configuration.HealthCheckPath != "" {
+					healthCheck += fmt.Sprintf(" (HTTP GET %s)", configuration.HealthCheckPath)
+				}
+				fmt.Println("\tHealth check:", healthCheck)
+			}
+			if configuration.ReconnectHoldTimeout > 0 {
+				fmt.Printf("\tReconnect hold timeout: %d seconds\n", configuration.ReconnectHoldTimeout)
+			}
+			if configuration.MaximumUploadRate > 0 {
+				fmt.Printf("\tMaximum upload rate: %s/s\n", humanize.Bytes(configuration.MaximumUploadRate))
+			}
+			if configuration.MaximumDownloadRate > 0 {
+				fmt.Printf("\tMaximum download rate: %s/s\n", humanize.Bytes(configuration.MaximumDownloadRate))
+			}
+			if configuration.MdnsServiceName != "" {
+				serviceType := configuration.MdnsServiceType
+				if serviceType == "" {
+					serviceType = "_http._tcp"
+				}
+				fmt.Printf("\tmDNS advertisement: %s (%s)\n", configuration.MdnsServiceName, serviceType)
+			}
+		}
 	}
 
 	// Compute and print source-specific configuration.
@@ -161,4 +282,18 @@ func printSession(state *forwarding.State, mode common.SessionDisplayMode) {
 			humanize.Bytes(state.TotalInboundData),
 		)
 	}
+
+	// Print cumulative statistics in long listing mode.
+	if mode == common.SessionDisplayModeListLong && state.Statistics != nil {
+		lastConnection := "never"
+		if state.Statistics.LastConnectionTime != nil {
+			lastConnection = state.Statistics.LastConnectionTime.AsTime().Local().Format(time.RFC1123)
+		}
+		fmt.Printf("Statistics: %d connections, %s outbound, %s inbound, last connection %s\n",
+			state.Statistics.TotalConnections,
+			humanize.Bytes(state.Statistics.TotalOutboundData),
+			humanize.Bytes(state.Statistics.TotalInboundData),
+			lastConnection,
+		)
+	}
 }
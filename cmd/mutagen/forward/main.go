@@ -48,6 +48,7 @@ func init() {
 		monitorCommand,
 		pauseCommand,
 		resumeCommand,
+		portCommand,
 		terminateCommand,
 	)
 }
@@ -25,9 +25,9 @@ import (
 	forwardingsvc "github.com/mutagen-io/mutagen/pkg/service/forwarding"
 )
 
-// computeMonitorStatusLine constructs a monitoring status line for a forwarding
-// session.
-func computeMonitorStatusLine(state *forwarding.State) string {
+// ComputeMonitorStatusLine constructs a monitoring status line for a
+// forwarding session.
+func ComputeMonitorStatusLine(state *forwarding.State) string {
 	// Build the status line.
 	var status string
 	if state.Session.Paused {
@@ -57,6 +57,84 @@ func computeMonitorStatusLine(state *forwarding.State) string {
 	return status
 }
 
+// connectionReportInterval is the minimum interval between per-connection
+// statistics reports.
+const connectionReportInterval = time.Second
+
+// connectionSample records the data transfer totals for a connection at the
+// time of a per-connection statistics report.
+type connectionSample struct {
+	// outbound is the amount of outbound data transferred.
+	outbound uint64
+	// inbound is the amount of inbound data transferred.
+	inbound uint64
+}
+
+// formatThroughput formats a data transfer rate given the amount of data
+// transferred over the specified interval.
+func formatThroughput(amount uint64, interval time.Duration) string {
+	if interval <= 0 {
+		return "unknown"
+	}
+	return humanize.Bytes(uint64(float64(amount)/interval.Seconds())) + "/s"
+}
+
+// printConnectionReport prints per-connection statistics for a forwarding
+// session. Throughput is computed relative to the samples recorded by the
+// previous report (taken at the specified time), or relative to the opening of
+// the connection if it wasn't present in the previous report. It returns the
+// samples for the current report.
+func printConnectionReport(
+	state *forwarding.State,
+	previous map[uint64]connectionSample,
+	previousTime, now time.Time,
+) map[uint64]connectionSample {
+	// Print the report header.
+	fmt.Printf("%s: %d open connections\n", now.Format(time.RFC1123), len(state.Connections))
+
+	// Print statistics for each connection and record samples.
+	samples := make(map[uint64]connectionSample, len(state.Connections))
+	for _, connection := range state.Connections {
+		// Compute the baseline for throughput.
+		opened := connection.Opened.AsTime()
+		baseline, ok := previous[connection.Identifier]
+		interval := now.Sub(previousTime)
+		if !ok {
+			interval = now.Sub(opened)
+		}
+
+		// Format the target and round-trip time.
+		target := connection.Target
+		if target == "" {
+			target = "(connecting)"
+		}
+		roundTripTime := "unknown"
+		if connection.RoundTripTime != nil {
+			roundTripTime = connection.RoundTripTime.AsDuration().Round(time.Microsecond).String()
+		}
+
+		// Print the connection statistics.
+		fmt.Printf("\t#%d %s -> %s: open %s, RTT %s, %s outbound, %s inbound\n",
+			connection.Identifier,
+			connection.Source,
+			target,
+			now.Sub(opened).Round(time.Second),
+			roundTripTime,
+			formatThroughput(connection.OutboundData-baseline.outbound, interval),
+			formatThroughput(connection.InboundData-baseline.inbound, interval),
+		)
+
+		// Record the sample.
+		samples[connection.Identifier] = connectionSample{
+			outbound: connection.OutboundData,
+			inbound:  connection.InboundData,
+		}
+	}
+
+	// Done.
+	return samples
+}
+
 // monitorMain is the entry point for the monitor command.
 func monitorMain(_ *cobra.Command, arguments []string) error {
 	// Create the session selection specification that will select our initial
@@ -97,10 +175,11 @@ func monitorMain(_ *cobra.Command, arguments []string) error {
 		Selection: selection,
 	}
 
-	// If no template has been specified, then create a status line printer with
-	// bold text and defer a line break operation.
+	// If no template has been specified and per-connection statistics haven't
+	// been requested, then create a status line printer with bold text and
+	// defer a line break operation.
 	var statusLinePrinter *cmd.StatusLinePrinter
-	if template == nil {
+	if template == nil && !monitorConfiguration.connections {
 		statusLinePrinter = &cmd.StatusLinePrinter{
 			Color: color.New(color.Bold),
 		}
@@ -110,6 +189,10 @@ func monitorMain(_ *cobra.Command, arguments []string) error {
 	// Track the last update time.
 	var lastUpdateTime time.Time
 
+	// Track the last per-connection statistics report time and samples.
+	var lastConnectionReportTime time.Time
+	var connectionSamples map[uint64]connectionSample
+
 	// Track whether or not we've identified an individual session in the
 	// non-templated case.
 	var identifiedSingleTargetSession bool
@@ -180,8 +263,18 @@ func monitorMain(_ *cobra.Command, arguments []string) error {
 			return err
 		}
 
+		// If per-connection statistics have been requested, then print a report
+		// if sufficient time has elapsed since the last one.
+		if monitorConfiguration.connections {
+			if reportTime := time.Now(); reportTime.Sub(lastConnectionReportTime) >= connectionReportInterval {
+				connectionSamples = printConnectionReport(state, connectionSamples, lastConnectionReportTime, reportTime)
+				lastConnectionReportTime = reportTime
+			}
+			continue
+		}
+
 		// Compute the status line.
-		statusLine := computeMonitorStatusLine(state)
+		statusLine := ComputeMonitorStatusLine(state)
 
 		// Print the status line.
 		statusLinePrinter.Print(statusLine)
@@ -202,6 +295,9 @@ var monitorConfiguration struct {
 	help bool
 	// long indicates whether or not to use long-format monitoring.
 	long bool
+	// connections indicates whether or not to display per-connection
+	// statistics.
+	connections bool
 	// labelSelector encodes a label selector to be used in identifying which
 	// sessions should be paused.
 	labelSelector string
@@ -222,6 +318,7 @@ func init() {
 
 	// Wire up monitor flags.
 	flags.BoolVarP(&monitorConfiguration.long, "long", "l", false, "Show detailed session information")
+	flags.BoolVar(&monitorConfiguration.connections, "connections", false, "Show per-connection statistics (round-trip time, throughput, and duration)")
 	flags.StringVar(&monitorConfiguration.labelSelector, "label-selector", "", "Monitor the most recently created session matching the specified label selector")
 
 	// Wire up templating flags.
@@ -0,0 +1,101 @@
+package forward
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+
+	"github.com/spf13/cobra"
+
+	"github.com/mutagen-io/mutagen/cmd/mutagen/daemon"
+
+	"github.com/mutagen-io/mutagen/pkg/grpcutil"
+	"github.com/mutagen-io/mutagen/pkg/selection"
+	forwardingsvc "github.com/mutagen-io/mutagen/pkg/service/forwarding"
+)
+
+// portMain is the entry point for the port command.
+func portMain(_ *cobra.Command, arguments []string) error {
+	// Validate arguments and create the session selection specification.
+	if len(arguments) != 1 {
+		return errors.New("a single session must be specified")
+	}
+	selection := &selection.Selection{
+		Specifications: arguments,
+	}
+	if err := selection.EnsureValid(); err != nil {
+		return fmt.Errorf("invalid session selection specification: %w", err)
+	}
+
+	// Connect to the daemon and defer closure of the connection.
+	daemonConnection, err := daemon.Connect(true, true)
+	if err != nil {
+		return fmt.Errorf("unable to connect to daemon: %w", err)
+	}
+	defer daemonConnection.Close()
+
+	// Perform the list operation.
+	forwardingService := forwardingsvc.NewForwardingClient(daemonConnection)
+	request := &forwardingsvc.ListRequest{
+		Selection: selection,
+	}
+	response, err := forwardingService.List(context.Background(), request)
+	if err != nil {
+		return grpcutil.PeelAwayRPCErrorLayer(err)
+	} else if err = response.EnsureValid(); err != nil {
+		return fmt.Errorf("invalid list response received: %w", err)
+	} else if len(response.SessionStates) != 1 {
+		return errors.New("invalid number of session states returned")
+	}
+
+	// Extract the bound source address.
+	address := response.SessionStates[0].SourceState.BoundAddress
+	if address == "" {
+		return errors.New("session source is not currently listening")
+	}
+
+	// Print the address or port.
+	if portConfiguration.address {
+		fmt.Println(address)
+	} else if _, port, err := net.SplitHostPort(address); err != nil {
+		return fmt.Errorf("session source is not listening on a port (%s)", address)
+	} else {
+		fmt.Println(port)
+	}
+
+	// Success.
+	return nil
+}
+
+// portCommand is the port command.
+var portCommand = &cobra.Command{
+	Use:          "port <session>",
+	Short:        "Show the port on which a forwarding session's source is listening",
+	RunE:         portMain,
+	SilenceUsage: true,
+}
+
+// portConfiguration stores configuration for the port command.
+var portConfiguration struct {
+	// help indicates whether or not to show help information and exit.
+	help bool
+	// address indicates whether or not to show the full bound address rather
+	// than just the port.
+	address bool
+}
+
+func init() {
+	// Grab a handle for the command line flags.
+	flags := portCommand.Flags()
+
+	// Disable alphabetical sorting of flags in help output.
+	flags.SortFlags = false
+
+	// Manually add a help flag to override the default message. Cobra will
+	// still implement its logic automatically.
+	flags.BoolVarP(&portConfiguration.help, "help", "h", false, "Show help information")
+
+	// Wire up port flags.
+	flags.BoolVar(&portConfiguration.address, "address", false, "Show the full bound address rather than just the port")
+}
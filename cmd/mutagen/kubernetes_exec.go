@@ -0,0 +1,101 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/spf13/cobra"
+
+	"k8s.io/klog/v2"
+
+	"github.com/mutagen-io/mutagen/cmd"
+
+	"github.com/mutagen-io/mutagen/pkg/kubernetes"
+)
+
+// kubernetesExecMain is the entry point for the Kubernetes exec helper command.
+func kubernetesExecMain(_ *cobra.Command, arguments []string) error {
+	// Silence logging from the Kubernetes client libraries, which would
+	// otherwise be interleaved with the command's error output.
+	klog.LogToStderr(false)
+	klog.SetOutput(io.Discard)
+
+	// Set up the target.
+	target := &kubernetes.Target{
+		Namespace:  kubernetesExecConfiguration.namespace,
+		Pod:        kubernetesExecConfiguration.pod,
+		Deployment: kubernetesExecConfiguration.deployment,
+		Selector:   kubernetesExecConfiguration.selector,
+		Container:  kubernetesExecConfiguration.container,
+	}
+
+	// Create the client.
+	client, err := kubernetes.NewClient(kubernetesExecConfiguration.context)
+	if err != nil {
+		cmd.Error(err)
+		os.Exit(kubernetes.HelperFailureExitCode)
+	}
+
+	// Run the command.
+	status, err := client.Run(context.Background(), target, arguments, os.Stdin, os.Stdout, os.Stderr)
+	if err != nil {
+		cmd.Error(fmt.Errorf("unable to run command: %w", err))
+		os.Exit(kubernetes.HelperFailureExitCode)
+	}
+
+	// Forward the command's exit status.
+	os.Exit(status)
+	return nil
+}
+
+// kubernetesExecCommand is the Kubernetes exec helper command. It's an internal
+// command used by the Kubernetes transport and isn't intended for direct use.
+var kubernetesExecCommand = &cobra.Command{
+	Use:          kubernetes.HelperCommandName + " -- <command> [<argument>...]",
+	Short:        "Run a command inside a Kubernetes container",
+	Args:         cobra.MinimumNArgs(1),
+	RunE:         kubernetesExecMain,
+	Hidden:       true,
+	SilenceUsage: true,
+}
+
+// kubernetesExecConfiguration stores configuration for the Kubernetes exec
+// helper command.
+var kubernetesExecConfiguration struct {
+	// help indicates whether or not to show help information and exit.
+	help bool
+	// namespace is the pod namespace.
+	namespace string
+	// pod is the pod name.
+	pod string
+	// deployment is the name of the deployment whose pods should be targeted.
+	deployment string
+	// selector is the label selector identifying the pods to target.
+	selector string
+	// container is the container name.
+	container string
+	// context is the kubeconfig context.
+	context string
+}
+
+func init() {
+	// Grab a handle for the command line flags.
+	flags := kubernetesExecCommand.Flags()
+
+	// Disable alphabetical sorting of flags in help output.
+	flags.SortFlags = false
+
+	// Manually add a help flag to override the default message. Cobra will
+	// still implement its logic automatically.
+	flags.BoolVarP(&kubernetesExecConfiguration.help, "help", "h", false, "Show help information")
+
+	// Wire up target flags.
+	flags.StringVar(&kubernetesExecConfiguration.namespace, kubernetes.HelperFlagNamespace, "", "Specify the pod namespace")
+	flags.StringVar(&kubernetesExecConfiguration.pod, kubernetes.HelperFlagPod, "", "Specify the pod name")
+	flags.StringVar(&kubernetesExecConfiguration.deployment, kubernetes.HelperFlagDeployment, "", "Specify the deployment whose pods should be targeted")
+	flags.StringVar(&kubernetesExecConfiguration.selector, kubernetes.HelperFlagSelector, "", "Specify the label selector identifying the pods to target")
+	flags.StringVar(&kubernetesExecConfiguration.container, kubernetes.HelperFlagContainer, "", "Specify the container name")
+	flags.StringVar(&kubernetesExecConfiguration.context, kubernetes.HelperFlagContext, "", "Specify the kubeconfig context")
+}
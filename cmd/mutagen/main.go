@@ -73,6 +73,8 @@ func init() {
 		forward.ForwardCommand,
 		project.ProjectCommand,
 		daemon.DaemonCommand,
+		sshNativeCommand,
+		kubernetesExecCommand,
 		versionCommand,
 		legalCommand,
 		generateCommand,
@@ -0,0 +1,135 @@
+package project
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"runtime"
+
+	"github.com/mutagen-io/mutagen/pkg/filesystem/locking"
+	"github.com/mutagen-io/mutagen/pkg/identifier"
+	"github.com/mutagen-io/mutagen/pkg/project"
+)
+
+// computeConfigurationFileName computes the name of the project configuration
+// file based on the specified project file path and project name (either of
+// which may be empty, but not both non-empty) and ensures that our working
+// directory is that in which the file resides. This is required for relative
+// paths (including relative synchronization paths and relative Unix Domain
+// Socket paths) to be resolved relative to the project configuration file.
+func computeConfigurationFileName(projectFile, name string) (string, error) {
+	// Handle project file specifications.
+	if projectFile != "" {
+		if name != "" {
+			return "", errors.New("project file and project name cannot both be specified")
+		}
+		directory, configurationFileName := filepath.Split(projectFile)
+		if directory != "" {
+			if err := os.Chdir(directory); err != nil {
+				return "", fmt.Errorf("unable to switch to target directory: %w", err)
+			}
+		}
+		return configurationFileName, nil
+	}
+
+	// Handle project name specifications.
+	if name != "" {
+		if !project.IsValidName(name) {
+			return "", fmt.Errorf("invalid project name: %s", name)
+		}
+		return project.NamedConfigurationFileName(name), nil
+	}
+
+	// Use the default configuration file name.
+	return project.DefaultConfigurationFileName, nil
+}
+
+// projectLock is a held project lock.
+type projectLock struct {
+	// path is the path to the lock file.
+	path string
+	// locker is the underlying locker.
+	locker *locking.Locker
+	// removeOnRelease indicates whether or not the lock file should be removed
+	// when the lock is released.
+	removeOnRelease bool
+}
+
+// lockProject creates and acquires the lock for the project with the specified
+// configuration file name. The lock must be released using release.
+func lockProject(configurationFileName string) (*projectLock, error) {
+	// Compute the lock path.
+	path := configurationFileName + project.LockFileExtension
+
+	// Create the locker.
+	locker, err := locking.NewLocker(path, 0600)
+	if err != nil {
+		return nil, fmt.Errorf("unable to create project locker: %w", err)
+	}
+
+	// Acquire the lock.
+	if err := locker.Lock(true); err != nil {
+		locker.Close()
+		return nil, fmt.Errorf("unable to acquire project lock: %w", err)
+	}
+
+	// Success.
+	return &projectLock{path: path, locker: locker}, nil
+}
+
+// contents reads the full contents of the lock file.
+func (l *projectLock) contents() ([]byte, error) {
+	buffer := &bytes.Buffer{}
+	if _, err := buffer.ReadFrom(l.locker); err != nil {
+		return nil, fmt.Errorf("unable to read project lock: %w", err)
+	}
+	return buffer.Bytes(), nil
+}
+
+// readIdentifier reads the project identifier of a running project from the
+// lock file. If the lock file is empty, then we can assume that we created it
+// when we created the lock, so it's marked for removal and an error is
+// returned.
+func (l *projectLock) readIdentifier() (string, error) {
+	// Read the lock file contents.
+	contents, err := l.contents()
+	if err != nil {
+		return "", err
+	} else if len(contents) == 0 {
+		l.removeOnRelease = true
+		return "", errors.New("project not running")
+	}
+	projectIdentifier := string(contents)
+
+	// Ensure that the project identifier is valid.
+	if !identifier.IsValid(projectIdentifier) {
+		return "", errors.New("invalid project identifier found in project lock")
+	}
+
+	// Success.
+	return projectIdentifier, nil
+}
+
+// release releases and closes the lock, removing the lock file if it's been
+// marked for removal. On Windows systems, we can't remove the lock file if
+// it's locked or even just opened, so we truncate the lock file before
+// releasing it and only remove it after closing it. Truncation ensures that
+// any other process that opens or acquires the lock file before we manage to
+// remove it will simply see an empty lock file, which it will ignore or
+// attempt to remove.
+func (l *projectLock) release() {
+	if l.removeOnRelease {
+		if runtime.GOOS == "windows" {
+			l.locker.Truncate(0)
+		} else {
+			os.Remove(l.path)
+		}
+	}
+	l.locker.Unlock()
+	l.locker.Close()
+	if l.removeOnRelease && runtime.GOOS == "windows" {
+		os.Remove(l.path)
+	}
+}
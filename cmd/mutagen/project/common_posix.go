@@ -8,13 +8,17 @@ import (
 )
 
 // runInShell runs the specified command using the system shell. On POSIX
-// systems, this is /bin/sh.
-func runInShell(command string) error {
+// systems, this is /bin/sh. If environment is non-empty, then its entries are
+// added to the inherited environment.
+func runInShell(command string, environment []string) error {
 	// Set up the process.
 	process := exec.Command("/bin/sh", "-c", command)
 	process.Stdin = os.Stdin
 	process.Stdout = os.Stdout
 	process.Stderr = os.Stderr
+	if len(environment) > 0 {
+		process.Env = append(os.Environ(), environment...)
+	}
 
 	// Run the process and wait for its completion.
 	return process.Run()
@@ -6,8 +6,10 @@ import (
 )
 
 // runInShell runs the specified command using the system shell. On Windows
-// systems, this is %COMSPEC% (with a fallback to cmd.exe if unspecified).
-func runInShell(command string) error {
+// systems, this is %COMSPEC% (with a fallback to cmd.exe if unspecified). If
+// environment is non-empty, then its entries are added to the inherited
+// environment.
+func runInShell(command string, environment []string) error {
 	// Determine the shell to use.
 	shell := os.Getenv("COMSPEC")
 	if shell == "" {
@@ -19,6 +21,9 @@ func runInShell(command string) error {
 	process.Stdin = os.Stdin
 	process.Stdout = os.Stdout
 	process.Stderr = os.Stderr
+	if len(environment) > 0 {
+		process.Env = append(os.Environ(), environment...)
+	}
 
 	// Run the process and wait for its completion.
 	return process.Run()
@@ -1,12 +1,7 @@
 package project
 
 import (
-	"bytes"
-	"errors"
 	"fmt"
-	"os"
-	"path/filepath"
-	"runtime"
 
 	"github.com/spf13/cobra"
 
@@ -16,8 +11,6 @@ import (
 	"github.com/mutagen-io/mutagen/cmd/mutagen/daemon"
 	"github.com/mutagen-io/mutagen/cmd/mutagen/sync"
 
-	"github.com/mutagen-io/mutagen/pkg/filesystem/locking"
-	"github.com/mutagen-io/mutagen/pkg/identifier"
 	"github.com/mutagen-io/mutagen/pkg/project"
 	"github.com/mutagen-io/mutagen/pkg/selection"
 )
@@ -25,76 +18,23 @@ import (
 // flushMain is the entry point for the flush command.
 func flushMain(_ *cobra.Command, _ []string) error {
 	// Compute the name of the configuration file and ensure that our working
-	// directory is that in which the file resides. This is required for
-	// relative paths (including relative synchronization paths and relative
-	// Unix Domain Socket paths) to be resolved relative to the project
-	// configuration file.
-	configurationFileName := project.DefaultConfigurationFileName
-	if flushConfiguration.projectFile != "" {
-		var directory string
-		directory, configurationFileName = filepath.Split(flushConfiguration.projectFile)
-		if directory != "" {
-			if err := os.Chdir(directory); err != nil {
-				return fmt.Errorf("unable to switch to target directory: %w", err)
-			}
-		}
+	// directory is that in which the file resides.
+	configurationFileName, err := computeConfigurationFileName(flushConfiguration.projectFile, flushConfiguration.name)
+	if err != nil {
+		return err
 	}
 
-	// Compute the lock path.
-	lockPath := configurationFileName + project.LockFileExtension
-
-	// Track whether or not we should remove the lock file on return.
-	var removeLockFileOnReturn bool
-
-	// Create a locker and defer its closure and potential removal. On Windows
-	// systems, we have to handle this removal after the file is closed.
-	locker, err := locking.NewLocker(lockPath, 0600)
+	// Acquire the project lock and defer its release.
+	lock, err := lockProject(configurationFileName)
 	if err != nil {
-		return fmt.Errorf("unable to create project locker: %w", err)
+		return err
 	}
-	defer func() {
-		locker.Close()
-		if removeLockFileOnReturn && runtime.GOOS == "windows" {
-			os.Remove(lockPath)
-		}
-	}()
-
-	// Acquire the project lock and defer its release and potential removal. On
-	// Windows systems, we can't remove the lock file if it's locked or even
-	// just opened, so we handle removal for Windows systems after we close the
-	// lock file (see above). In this case, we truncate the lock file before
-	// releasing it to ensure that any other process that opens or acquires the
-	// lock file before we manage to remove it will simply see an empty lock
-	// file, which it will ignore or attempt to remove.
-	if err := locker.Lock(true); err != nil {
-		return fmt.Errorf("unable to acquire project lock: %w", err)
-	}
-	defer func() {
-		if removeLockFileOnReturn {
-			if runtime.GOOS == "windows" {
-				locker.Truncate(0)
-			} else {
-				os.Remove(lockPath)
-			}
-		}
-		locker.Unlock()
-	}()
-
-	// Read the project identifier from the lock file. If the lock file is
-	// empty, then we can assume that we created it when we created the lock and
-	// just remove it.
-	buffer := &bytes.Buffer{}
-	if length, err := buffer.ReadFrom(locker); err != nil {
-		return fmt.Errorf("unable to read project lock: %w", err)
-	} else if length == 0 {
-		removeLockFileOnReturn = true
-		return errors.New("project not running")
-	}
-	projectIdentifier := buffer.String()
+	defer lock.release()
 
-	// Ensure that the project identifier is valid.
-	if !identifier.IsValid(projectIdentifier) {
-		return errors.New("invalid project identifier found in project lock")
+	// Read the project identifier.
+	projectIdentifier, err := lock.readIdentifier()
+	if err != nil {
+		return err
 	}
 
 	// Connect to the daemon and defer closure of the connection.
@@ -133,6 +73,8 @@ var flushConfiguration struct {
 	help bool
 	// projectFile is the path to the project file, if non-default.
 	projectFile string
+	// name is the name of the project, if non-default.
+	name string
 	// skipWait indicates whether or not the flush operation should block until
 	// a synchronization cycle completes for each sesion requested.
 	skipWait bool
@@ -151,6 +93,7 @@ func init() {
 
 	// Wire up project file flags.
 	flags.StringVarP(&flushConfiguration.projectFile, "project-file", "f", "", "Specify project file")
+	flags.StringVarP(&flushConfiguration.name, "name", "n", "", "Specify project name (uses mutagen.<name>.yml)")
 
 	// Wire up flush flags.
 	flags.BoolVar(&flushConfiguration.skipWait, "skip-wait", false, "Avoid waiting for the resulting synchronization cycle(s) to complete")
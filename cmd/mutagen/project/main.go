@@ -6,9 +6,18 @@ import (
 	"github.com/fatih/color"
 
 	// Explicitly import packages that need to register protocol handlers.
+	_ "github.com/mutagen-io/mutagen/pkg/forwarding/protocols/azure"
 	_ "github.com/mutagen-io/mutagen/pkg/forwarding/protocols/docker"
+	_ "github.com/mutagen-io/mutagen/pkg/forwarding/protocols/exec"
+	_ "github.com/mutagen-io/mutagen/pkg/forwarding/protocols/kubernetes"
 	_ "github.com/mutagen-io/mutagen/pkg/forwarding/protocols/local"
+	_ "github.com/mutagen-io/mutagen/pkg/forwarding/protocols/lxd"
+	_ "github.com/mutagen-io/mutagen/pkg/forwarding/protocols/plugin"
+	_ "github.com/mutagen-io/mutagen/pkg/forwarding/protocols/rendezvous"
 	_ "github.com/mutagen-io/mutagen/pkg/forwarding/protocols/ssh"
+	_ "github.com/mutagen-io/mutagen/pkg/forwarding/protocols/tcp"
+	_ "github.com/mutagen-io/mutagen/pkg/forwarding/protocols/teleport"
+	_ "github.com/mutagen-io/mutagen/pkg/forwarding/protocols/wsl"
 )
 
 // projectMain is the entry point for the project command.
@@ -53,6 +62,7 @@ func init() {
 		startCommand,
 		runCommand,
 		listCommand,
+		monitorCommand,
 		flushCommand,
 		pauseCommand,
 		resumeCommand,
@@ -0,0 +1,228 @@
+package project
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/fatih/color"
+
+	"github.com/mutagen-io/mutagen/cmd"
+	"github.com/mutagen-io/mutagen/cmd/mutagen/common"
+	"github.com/mutagen-io/mutagen/cmd/mutagen/daemon"
+	"github.com/mutagen-io/mutagen/cmd/mutagen/forward"
+	synccmd "github.com/mutagen-io/mutagen/cmd/mutagen/sync"
+
+	"github.com/mutagen-io/mutagen/pkg/forwarding"
+	"github.com/mutagen-io/mutagen/pkg/grpcutil"
+	"github.com/mutagen-io/mutagen/pkg/project"
+	"github.com/mutagen-io/mutagen/pkg/selection"
+	forwardingsvc "github.com/mutagen-io/mutagen/pkg/service/forwarding"
+	synchronizationsvc "github.com/mutagen-io/mutagen/pkg/service/synchronization"
+	"github.com/mutagen-io/mutagen/pkg/synchronization"
+)
+
+// monitorMain is the entry point for the monitor command.
+func monitorMain(_ *cobra.Command, _ []string) error {
+	// Compute the name of the configuration file and ensure that our working
+	// directory is that in which the file resides.
+	configurationFileName, err := computeConfigurationFileName(monitorConfiguration.projectFile, monitorConfiguration.name)
+	if err != nil {
+		return err
+	}
+
+	// Acquire the project lock and read the project identifier. We don't hold
+	// the project lock while monitoring since that would block other project
+	// operations.
+	lock, err := lockProject(configurationFileName)
+	if err != nil {
+		return err
+	}
+	projectIdentifier, err := lock.readIdentifier()
+	lock.release()
+	if err != nil {
+		return err
+	}
+
+	// Connect to the daemon and defer closure of the connection.
+	daemonConnection, err := daemon.Connect(true, true)
+	if err != nil {
+		return fmt.Errorf("unable to connect to daemon: %w", err)
+	}
+	defer daemonConnection.Close()
+
+	// Compute the selection that we're going to use to monitor sessions.
+	selection := &selection.Selection{
+		LabelSelector: fmt.Sprintf("%s=%s", project.LabelKey, projectIdentifier),
+	}
+
+	// Create session service clients.
+	forwardingService := forwardingsvc.NewForwardingClient(daemonConnection)
+	synchronizationService := synchronizationsvc.NewSynchronizationClient(daemonConnection)
+
+	// Create a context to regulate polling and defer its cancellation.
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	// Create channels to receive the latest session states and polling errors.
+	forwardingStates := make(chan []*forwarding.State, 1)
+	synchronizationStates := make(chan []*synchronization.State, 1)
+	pollingErrors := make(chan error, 2)
+
+	// Poll for forwarding session states in the background.
+	go func() {
+		request := &forwardingsvc.ListRequest{Selection: selection}
+		var lastUpdateTime time.Time
+		for {
+			// Regulate the update frequency.
+			now := time.Now()
+			if timeSinceLastUpdate := now.Sub(lastUpdateTime); timeSinceLastUpdate < common.MinimumMonitorUpdateInterval {
+				time.Sleep(common.MinimumMonitorUpdateInterval - timeSinceLastUpdate)
+			}
+			lastUpdateTime = now
+
+			// Perform a list operation.
+			response, err := forwardingService.List(ctx, request)
+			if err != nil {
+				pollingErrors <- fmt.Errorf("forwarding session list failed: %w", grpcutil.PeelAwayRPCErrorLayer(err))
+				return
+			} else if err = response.EnsureValid(); err != nil {
+				pollingErrors <- fmt.Errorf("invalid forwarding session list response received: %w", err)
+				return
+			}
+
+			// Update the state tracking index.
+			request.PreviousStateIndex = response.StateIndex
+
+			// Replace any unconsumed states with the latest states.
+			select {
+			case <-forwardingStates:
+			default:
+			}
+			forwardingStates <- response.SessionStates
+		}
+	}()
+
+	// Poll for synchronization session states in the background.
+	go func() {
+		request := &synchronizationsvc.ListRequest{Selection: selection}
+		var lastUpdateTime time.Time
+		for {
+			// Regulate the update frequency.
+			now := time.Now()
+			if timeSinceLastUpdate := now.Sub(lastUpdateTime); timeSinceLastUpdate < common.MinimumMonitorUpdateInterval {
+				time.Sleep(common.MinimumMonitorUpdateInterval - timeSinceLastUpdate)
+			}
+			lastUpdateTime = now
+
+			// Perform a list operation.
+			response, err := synchronizationService.List(ctx, request)
+			if err != nil {
+				pollingErrors <- fmt.Errorf("synchronization session list failed: %w", grpcutil.PeelAwayRPCErrorLayer(err))
+				return
+			} else if err = response.EnsureValid(); err != nil {
+				pollingErrors <- fmt.Errorf("invalid synchronization session list response received: %w", err)
+				return
+			}
+
+			// Update the state tracking index.
+			request.PreviousStateIndex = response.StateIndex
+
+			// Replace any unconsumed states with the latest states.
+			select {
+			case <-synchronizationStates:
+			default:
+			}
+			synchronizationStates <- response.SessionStates
+		}
+	}()
+
+	// Create a status block printer with bold text.
+	statusBlockPrinter := &cmd.StatusBlockPrinter{
+		Color: color.New(color.Bold),
+	}
+
+	// Loop and print combined monitoring information indefinitely.
+	var forwardingSessions []*forwarding.State
+	var synchronizationSessions []*synchronization.State
+	for {
+		// Wait for a state update.
+		select {
+		case forwardingSessions = <-forwardingStates:
+		case synchronizationSessions = <-synchronizationStates:
+		case err := <-pollingErrors:
+			return err
+		}
+
+		// Compute the status lines.
+		lines := []string{"Forwarding sessions:"}
+		if len(forwardingSessions) == 0 {
+			lines = append(lines, "  None")
+		}
+		for _, state := range forwardingSessions {
+			lines = append(lines, fmt.Sprintf("  %s: %s",
+				monitorSessionName(state.Session.Name, state.Session.Identifier),
+				forward.ComputeMonitorStatusLine(state),
+			))
+		}
+		lines = append(lines, "Synchronization sessions:")
+		if len(synchronizationSessions) == 0 {
+			lines = append(lines, "  None")
+		}
+		for _, state := range synchronizationSessions {
+			lines = append(lines, fmt.Sprintf("  %s: %s",
+				monitorSessionName(state.Session.Name, state.Session.Identifier),
+				synccmd.ComputeMonitorStatusLine(state),
+			))
+		}
+
+		// Print the status lines.
+		statusBlockPrinter.Print(lines)
+	}
+}
+
+// monitorSessionName returns the name to display for a session in the monitor
+// command, falling back to the session identifier for unnamed sessions.
+func monitorSessionName(name, identifier string) string {
+	if name != "" {
+		return name
+	}
+	return identifier
+}
+
+// monitorCommand is the monitor command.
+var monitorCommand = &cobra.Command{
+	Use:          "monitor",
+	Short:        "Display streaming status information for project sessions " + color.YellowString("[Deprecated]"),
+	Args:         cmd.DisallowArguments,
+	RunE:         monitorMain,
+	SilenceUsage: true,
+}
+
+// monitorConfiguration stores configuration for the monitor command.
+var monitorConfiguration struct {
+	// help indicates whether or not to show help information and exit.
+	help bool
+	// projectFile is the path to the project file, if non-default.
+	projectFile string
+	// name is the name of the project, if non-default.
+	name string
+}
+
+func init() {
+	// Grab a handle for the command line flags.
+	flags := monitorCommand.Flags()
+
+	// Disable alphabetical sorting of flags in help output.
+	flags.SortFlags = false
+
+	// Manually add a help flag to override the default message. Cobra will
+	// still implement its logic automatically.
+	flags.BoolVarP(&monitorConfiguration.help, "help", "h", false, "Show help information")
+
+	// Wire up project file flags.
+	flags.StringVarP(&monitorConfiguration.projectFile, "project-file", "f", "", "Specify project file")
+	flags.StringVarP(&monitorConfiguration.name, "name", "n", "", "Specify project name (uses mutagen.<name>.yml)")
+}
@@ -1,12 +1,7 @@
 package project
 
 import (
-	"bytes"
-	"errors"
 	"fmt"
-	"os"
-	"path/filepath"
-	"runtime"
 
 	"github.com/spf13/cobra"
 
@@ -17,8 +12,6 @@ import (
 	"github.com/mutagen-io/mutagen/cmd/mutagen/forward"
 	"github.com/mutagen-io/mutagen/cmd/mutagen/sync"
 
-	"github.com/mutagen-io/mutagen/pkg/filesystem/locking"
-	"github.com/mutagen-io/mutagen/pkg/identifier"
 	"github.com/mutagen-io/mutagen/pkg/project"
 	"github.com/mutagen-io/mutagen/pkg/selection"
 )
@@ -26,80 +19,33 @@ import (
 // pauseMain is the entry point for the pause command.
 func pauseMain(_ *cobra.Command, _ []string) error {
 	// Compute the name of the configuration file and ensure that our working
-	// directory is that in which the file resides. This is required for
-	// relative paths (including relative synchronization paths and relative
-	// Unix Domain Socket paths) to be resolved relative to the project
-	// configuration file.
-	configurationFileName := project.DefaultConfigurationFileName
-	if pauseConfiguration.projectFile != "" {
-		var directory string
-		directory, configurationFileName = filepath.Split(pauseConfiguration.projectFile)
-		if directory != "" {
-			if err := os.Chdir(directory); err != nil {
-				return fmt.Errorf("unable to switch to target directory: %w", err)
-			}
-		}
+	// directory is that in which the file resides.
+	configurationFileName, err := computeConfigurationFileName(pauseConfiguration.projectFile, pauseConfiguration.name)
+	if err != nil {
+		return err
 	}
 
-	// Compute the lock path.
-	lockPath := configurationFileName + project.LockFileExtension
-
-	// Track whether or not we should remove the lock file on return.
-	var removeLockFileOnReturn bool
-
-	// Create a locker and defer its closure and potential removal. On Windows
-	// systems, we have to handle this removal after the file is closed.
-	locker, err := locking.NewLocker(lockPath, 0600)
+	// Acquire the project lock and defer its release.
+	lock, err := lockProject(configurationFileName)
 	if err != nil {
-		return fmt.Errorf("unable to create project locker: %w", err)
-	}
-	defer func() {
-		locker.Close()
-		if removeLockFileOnReturn && runtime.GOOS == "windows" {
-			os.Remove(lockPath)
-		}
-	}()
-
-	// Acquire the project lock and defer its release and potential removal. On
-	// Windows systems, we can't remove the lock file if it's locked or even
-	// just opened, so we handle removal for Windows systems after we close the
-	// lock file (see above). In this case, we truncate the lock file before
-	// releasing it to ensure that any other process that opens or acquires the
-	// lock file before we manage to remove it will simply see an empty lock
-	// file, which it will ignore or attempt to remove.
-	if err := locker.Lock(true); err != nil {
-		return fmt.Errorf("unable to acquire project lock: %w", err)
+		return err
 	}
-	defer func() {
-		if removeLockFileOnReturn {
-			if runtime.GOOS == "windows" {
-				locker.Truncate(0)
-			} else {
-				os.Remove(lockPath)
-			}
-		}
-		locker.Unlock()
-	}()
-
-	// Read the project identifier from the lock file. If the lock file is
-	// empty, then we can assume that we created it when we created the lock and
-	// just remove it.
-	buffer := &bytes.Buffer{}
-	if length, err := buffer.ReadFrom(locker); err != nil {
-		return fmt.Errorf("unable to read project lock: %w", err)
-	} else if length == 0 {
-		removeLockFileOnReturn = true
-		return errors.New("project not running")
+	defer lock.release()
+
+	// Read the project identifier.
+	projectIdentifier, err := lock.readIdentifier()
+	if err != nil {
+		return err
 	}
-	projectIdentifier := buffer.String()
 
-	// Ensure that the project identifier is valid.
-	if !identifier.IsValid(projectIdentifier) {
-		return errors.New("invalid project identifier found in project lock")
+	// Parse variable overrides.
+	overrides, err := project.ParseVariableOverrides(pauseConfiguration.variables)
+	if err != nil {
+		return fmt.Errorf("unable to parse variable overrides: %w", err)
 	}
 
 	// Load the configuration file.
-	configuration, err := project.LoadConfiguration(configurationFileName)
+	configuration, err := project.LoadConfiguration(configurationFileName, overrides)
 	if err != nil {
 		return fmt.Errorf("unable to load configuration file: %w", err)
 	}
@@ -107,7 +53,7 @@ func pauseMain(_ *cobra.Command, _ []string) error {
 	// Perform pre-pause commands.
 	for _, command := range configuration.BeforePause {
 		fmt.Println(">", command)
-		if err := runInShell(command); err != nil {
+		if err := runInShell(command, nil); err != nil {
 			return fmt.Errorf("pre-pause command failed: %w", err)
 		}
 	}
@@ -137,7 +83,7 @@ func pauseMain(_ *cobra.Command, _ []string) error {
 	// Perform post-pause commands.
 	for _, command := range configuration.AfterPause {
 		fmt.Println(">", command)
-		if err := runInShell(command); err != nil {
+		if err := runInShell(command, nil); err != nil {
 			return fmt.Errorf("post-pause command failed: %w", err)
 		}
 	}
@@ -161,6 +107,10 @@ var pauseConfiguration struct {
 	help bool
 	// projectFile is the path to the project file, if non-default.
 	projectFile string
+	// name is the name of the project, if non-default.
+	name string
+	// variables are variable overrides of the form name=value.
+	variables []string
 }
 
 func init() {
@@ -176,4 +126,6 @@ func init() {
 
 	// Wire up project file flags.
 	flags.StringVarP(&pauseConfiguration.projectFile, "project-file", "f", "", "Specify project file")
+	flags.StringVarP(&pauseConfiguration.name, "name", "n", "", "Specify project name (uses mutagen.<name>.yml)")
+	flags.StringArrayVar(&pauseConfiguration.variables, "set", nil, "Override a project variable (name=value)")
 }
@@ -1,12 +1,9 @@
 package project
 
 import (
-	"bytes"
 	"errors"
 	"fmt"
 	"os"
-	"path/filepath"
-	"runtime"
 
 	"github.com/spf13/cobra"
 
@@ -18,7 +15,6 @@ import (
 	"github.com/mutagen-io/mutagen/cmd/mutagen/sync"
 
 	"github.com/mutagen-io/mutagen/pkg/configuration/global"
-	"github.com/mutagen-io/mutagen/pkg/filesystem/locking"
 	"github.com/mutagen-io/mutagen/pkg/forwarding"
 	"github.com/mutagen-io/mutagen/pkg/identifier"
 	"github.com/mutagen-io/mutagen/pkg/project"
@@ -32,74 +28,31 @@ import (
 // startMain is the entry point for the start command.
 func startMain(_ *cobra.Command, _ []string) error {
 	// Compute the name of the configuration file and ensure that our working
-	// directory is that in which the file resides. This is required for
-	// relative paths (including relative synchronization paths and relative
-	// Unix Domain Socket paths) to be resolved relative to the project
-	// configuration file.
-	configurationFileName := project.DefaultConfigurationFileName
-	if startConfiguration.projectFile != "" {
-		var directory string
-		directory, configurationFileName = filepath.Split(startConfiguration.projectFile)
-		if directory != "" {
-			if err := os.Chdir(directory); err != nil {
-				return fmt.Errorf("unable to switch to target directory: %w", err)
-			}
-		}
+	// directory is that in which the file resides.
+	configurationFileName, err := computeConfigurationFileName(startConfiguration.projectFile, startConfiguration.name)
+	if err != nil {
+		return err
 	}
 
-	// Compute the lock path.
-	lockPath := configurationFileName + project.LockFileExtension
-
-	// Track whether or not we should remove the lock file on return.
-	var removeLockFileOnReturn bool
-
-	// Create a locker and defer its closure and potential removal. On Windows
-	// systems, we have to handle this removal after the file is closed.
-	locker, err := locking.NewLocker(lockPath, 0600)
+	// Acquire the project lock and defer its release.
+	lock, err := lockProject(configurationFileName)
 	if err != nil {
-		return fmt.Errorf("unable to create project locker: %w", err)
-	}
-	defer func() {
-		locker.Close()
-		if removeLockFileOnReturn && runtime.GOOS == "windows" {
-			os.Remove(lockPath)
-		}
-	}()
-
-	// Acquire the project lock and defer its release and potential removal. On
-	// Windows systems, we can't remove the lock file if it's locked or even
-	// just opened, so we handle removal for Windows systems after we close the
-	// lock file (see above). In this case, we truncate the lock file before
-	// releasing it to ensure that any other process that opens or acquires the
-	// lock file before we manage to remove it will simply see an empty lock
-	// file, which it will ignore or attempt to remove.
-	if err := locker.Lock(true); err != nil {
-		return fmt.Errorf("unable to acquire project lock: %w", err)
+		return err
 	}
-	defer func() {
-		if removeLockFileOnReturn {
-			if runtime.GOOS == "windows" {
-				locker.Truncate(0)
-			} else {
-				os.Remove(lockPath)
-			}
-		}
-		locker.Unlock()
-	}()
+	defer lock.release()
 
 	// Read the full contents of the lock file and ensure that it's empty.
-	buffer := &bytes.Buffer{}
-	if length, err := buffer.ReadFrom(locker); err != nil {
-		return fmt.Errorf("unable to read project lock: %w", err)
-	} else if length != 0 {
+	if contents, err := lock.contents(); err != nil {
+		return err
+	} else if len(contents) != 0 {
 		return errors.New("project already running")
 	}
 
 	// At this point we know that there was no previous project running, but we
-	// haven't yet created any resources, so defer removal of the lock file that
-	// we've created in case we run into any errors loading configuration
+	// haven't yet created any resources, so mark the lock file that we've
+	// created for removal in case we run into any errors loading configuration
 	// information.
-	removeLockFileOnReturn = true
+	lock.removeOnRelease = true
 
 	// Create a unique project identifier.
 	identifier, err := identifier.New(identifier.PrefixProject)
@@ -108,12 +61,18 @@ func startMain(_ *cobra.Command, _ []string) error {
 	}
 
 	// Write the project identifier to the lock file.
-	if _, err := locker.Write([]byte(identifier)); err != nil {
+	if _, err := lock.locker.Write([]byte(identifier)); err != nil {
 		return fmt.Errorf("unable to write project identifier: %w", err)
 	}
 
+	// Parse variable overrides.
+	overrides, err := project.ParseVariableOverrides(startConfiguration.variables)
+	if err != nil {
+		return fmt.Errorf("unable to parse variable overrides: %w", err)
+	}
+
 	// Load the configuration file.
-	configuration, err := project.LoadConfiguration(configurationFileName)
+	configuration, err := project.LoadConfiguration(configurationFileName, overrides)
 	if err != nil {
 		return fmt.Errorf("unable to load configuration file: %w", err)
 	}
@@ -121,6 +80,7 @@ func startMain(_ *cobra.Command, _ []string) error {
 	// Unless disabled, attempt to load configuration from the global
 	// configuration file and use it as the base for our core session
 	// configurations.
+	globalConfiguration := &global.Configuration{}
 	globalConfigurationForwarding := &forwarding.Configuration{}
 	globalConfigurationSynchronization := &synchronization.Configuration{}
 	if !startConfiguration.noGlobalConfiguration {
@@ -131,12 +91,12 @@ func startMain(_ *cobra.Command, _ []string) error {
 		}
 
 		// Attempt to load and validate the file. We allow it to not exist.
-		globalConfiguration, err := global.LoadConfiguration(globalConfigurationPath)
-		if err != nil {
+		if c, err := global.LoadConfiguration(globalConfigurationPath); err != nil {
 			if !os.IsNotExist(err) {
 				return fmt.Errorf("unable to load global configuration: %w", err)
 			}
 		} else {
+			globalConfiguration = c
 			globalConfigurationForwarding = globalConfiguration.Forwarding.Defaults.ToInternal()
 			if err := globalConfigurationForwarding.EnsureValid(false); err != nil {
 				return fmt.Errorf("invalid global forwarding configuration: %w", err)
@@ -236,6 +196,8 @@ func startMain(_ *cobra.Command, _ []string) error {
 		if err != nil {
 			return fmt.Errorf("unable to parse forwarding destination URL (%s): %v", destination, err)
 		}
+		globalConfiguration.ConfigureURL(sourceURL)
+		globalConfiguration.ConfigureURL(destinationURL)
 
 		// Compute configuration.
 		configuration := session.Configuration.ToInternal()
@@ -307,6 +269,8 @@ func startMain(_ *cobra.Command, _ []string) error {
 		if err != nil {
 			return fmt.Errorf("unable to parse synchronization beta URL (%s): %v", beta, err)
 		}
+		globalConfiguration.ConfigureURL(alphaURL)
+		globalConfiguration.ConfigureURL(betaURL)
 
 		// Compute configuration.
 		configuration := session.Configuration.ToInternal()
@@ -360,12 +324,12 @@ func startMain(_ *cobra.Command, _ []string) error {
 
 	// At this point, we're going to try to create resources, so we need to
 	// maintain the lock file in case even some of them are successful.
-	removeLockFileOnReturn = false
+	lock.removeOnRelease = false
 
 	// Perform pre-creation commands.
 	for _, command := range configuration.BeforeCreate {
 		fmt.Println(">", command)
-		if err := runInShell(command); err != nil {
+		if err := runInShell(command, nil); err != nil {
 			return fmt.Errorf("pre-create command failed: %w", err)
 		}
 	}
@@ -403,7 +367,7 @@ func startMain(_ *cobra.Command, _ []string) error {
 	// Perform post-creation commands.
 	for _, command := range configuration.AfterCreate {
 		fmt.Println(">", command)
-		if err := runInShell(command); err != nil {
+		if err := runInShell(command, nil); err != nil {
 			return fmt.Errorf("post-create command failed: %w", err)
 		}
 	}
@@ -427,6 +391,10 @@ var startConfiguration struct {
 	help bool
 	// projectFile is the path to the project file, if non-default.
 	projectFile string
+	// name is the name of the project, if non-default.
+	name string
+	// variables are variable overrides of the form name=value.
+	variables []string
 	// paused indicates whether or not to create sessions in a pre-paused state.
 	paused bool
 	// noGlobalConfiguration specifies whether or not the global configuration
@@ -447,6 +415,8 @@ func init() {
 
 	// Wire up project file flags.
 	flags.StringVarP(&startConfiguration.projectFile, "project-file", "f", "", "Specify project file")
+	flags.StringVarP(&startConfiguration.name, "name", "n", "", "Specify project name (uses mutagen.<name>.yml)")
+	flags.StringArrayVar(&startConfiguration.variables, "set", nil, "Override a project variable (name=value)")
 
 	// Wire up paused flags.
 	flags.BoolVarP(&startConfiguration.paused, "paused", "p", false, "Create the session pre-paused")
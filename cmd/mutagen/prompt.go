@@ -12,13 +12,41 @@ import (
 	promptingsvc "github.com/mutagen-io/mutagen/pkg/service/prompting"
 )
 
+// askpassPromptEnvironmentVariable is the environment variable used by OpenSSH
+// to indicate the type of prompt being requested from an SSH_ASKPASS program.
+const askpassPromptEnvironmentVariable = "SSH_ASKPASS_PROMPT"
+
+// prompt performs a prompt request against the daemon and returns the response.
+func prompt(request *promptingsvc.PromptRequest) (string, error) {
+	// Connect to the daemon and defer closure of the connection.
+	daemonConnection, err := daemon.Connect(false, true)
+	if err != nil {
+		return "", fmt.Errorf("unable to connect to daemon: %w", err)
+	}
+	defer daemonConnection.Close()
+
+	// Create a prompt service client.
+	promptingService := promptingsvc.NewPromptingClient(daemonConnection)
+
+	// Invoke prompt.
+	response, err := promptingService.Prompt(context.Background(), request)
+	if err != nil {
+		return "", fmt.Errorf("unable to invoke prompt: %w", err)
+	} else if err = response.EnsureValid(); err != nil {
+		return "", fmt.Errorf("invalid prompt response: %w", err)
+	}
+
+	// Success.
+	return response.Response, nil
+}
+
 // promptMain is the entry point for prompting.
 func promptMain(arguments []string) error {
 	// Extract prompt.
 	if len(arguments) != 1 {
 		return errors.New("invalid number of arguments")
 	}
-	prompt := arguments[0]
+	message := arguments[0]
 
 	// Extract environment parameters.
 	prompter := os.Getenv(promptingpkg.PrompterEnvironmentVariable)
@@ -26,30 +54,23 @@ func promptMain(arguments []string) error {
 		return errors.New("no prompter specified")
 	}
 
-	// Connect to the daemon and defer closure of the connection.
-	daemonConnection, err := daemon.Connect(false, true)
-	if err != nil {
-		return fmt.Errorf("unable to connect to daemon: %w", err)
-	}
-	defer daemonConnection.Close()
-
-	// Create a prompt service client.
-	promptingService := promptingsvc.NewPromptingClient(daemonConnection)
-
-	// Invoke prompt.
+	// Perform prompting. If OpenSSH is only requesting that a notification be
+	// displayed (e.g. when asking for a hardware authenticator to be touched),
+	// then it doesn't expect a response, so we display the prompt as a message.
 	request := &promptingsvc.PromptRequest{
 		Prompter: prompter,
-		Prompt:   prompt,
+		Prompt:   message,
+		Message:  os.Getenv(askpassPromptEnvironmentVariable) == "none",
 	}
-	response, err := promptingService.Prompt(context.Background(), request)
+	response, err := prompt(request)
 	if err != nil {
-		return fmt.Errorf("unable to invoke prompt: %w", err)
-	} else if err = response.EnsureValid(); err != nil {
-		return fmt.Errorf("invalid prompt response: %w", err)
+		return err
+	} else if request.Message {
+		return nil
 	}
 
 	// Print the response.
-	fmt.Println(response.Response)
+	fmt.Println(response)
 
 	// Success.
 	return nil
@@ -0,0 +1,163 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"golang.org/x/crypto/ssh"
+
+	"github.com/mutagen-io/mutagen/cmd"
+
+	promptingsvc "github.com/mutagen-io/mutagen/pkg/service/prompting"
+	"github.com/mutagen-io/mutagen/pkg/ssh/native"
+)
+
+// sshNativeConnect connects to the remote specified by the native SSH helper
+// configuration. If connection fails, then it terminates the process with the
+// same exit code that OpenSSH would use.
+func sshNativeConnect() *ssh.Client {
+	// Set up the target.
+	target := &native.Target{
+		User: sshNativeConfiguration.user,
+		Host: sshNativeConfiguration.host,
+		Port: sshNativeConfiguration.port,
+	}
+
+	// Set up connection options.
+	options := &native.Options{
+		ConnectTimeout:    time.Duration(sshNativeConfiguration.connectTimeout) * time.Second,
+		KeepaliveInterval: time.Duration(sshNativeConfiguration.keepaliveInterval) * time.Second,
+	}
+	if prompter := sshNativeConfiguration.prompter; prompter != "" {
+		options.Prompter = func(message string) (string, error) {
+			return prompt(&promptingsvc.PromptRequest{
+				Prompter: prompter,
+				Prompt:   message,
+			})
+		}
+	}
+
+	// Connect.
+	client, err := native.Dial(target, options)
+	if err != nil {
+		cmd.Error(fmt.Errorf("unable to connect to %s: %w", target.Host, err))
+		os.Exit(native.HelperConnectionFailureExitCode)
+	}
+
+	// Success.
+	return client
+}
+
+// sshNativeRunMain is the entry point for the run subcommand of the native SSH
+// helper command.
+func sshNativeRunMain(_ *cobra.Command, arguments []string) error {
+	// Connect to the remote.
+	client := sshNativeConnect()
+
+	// Run the command.
+	status, err := native.Run(client, arguments[0], sshNativeConfiguration.forwardAgent, os.Stdin, os.Stdout, os.Stderr)
+	client.Close()
+	if err != nil {
+		cmd.Error(fmt.Errorf("unable to run command: %w", err))
+		os.Exit(native.HelperConnectionFailureExitCode)
+	}
+
+	// Forward the command's exit status.
+	os.Exit(status)
+	return nil
+}
+
+// sshNativeCopyMain is the entry point for the copy subcommand of the native
+// SSH helper command.
+func sshNativeCopyMain(_ *cobra.Command, arguments []string) error {
+	// Connect to the remote and defer closure of the connection.
+	client := sshNativeConnect()
+	defer client.Close()
+
+	// Perform the copy.
+	if err := native.Copy(client, arguments[0], arguments[1]); err != nil {
+		return fmt.Errorf("unable to copy file: %w", err)
+	}
+
+	// Success.
+	return nil
+}
+
+// sshNativeCommand is the native SSH helper command. It's an internal command
+// used by the native SSH transport and isn't intended for direct use.
+var sshNativeCommand = &cobra.Command{
+	Use:          native.HelperCommandName,
+	Short:        "Run the native SSH client",
+	Args:         cmd.DisallowArguments,
+	Hidden:       true,
+	SilenceUsage: true,
+}
+
+// sshNativeRunCommand is the run subcommand of the native SSH helper command.
+var sshNativeRunCommand = &cobra.Command{
+	Use:          native.HelperRunCommandName + " <command>",
+	Short:        "Run a command on the remote",
+	Args:         cobra.ExactArgs(1),
+	RunE:         sshNativeRunMain,
+	SilenceUsage: true,
+}
+
+// sshNativeCopyCommand is the copy subcommand of the native SSH helper command.
+var sshNativeCopyCommand = &cobra.Command{
+	Use:          native.HelperCopyCommandName + " <local-path> <remote-name>",
+	Short:        "Copy a file to the remote",
+	Args:         cobra.ExactArgs(2),
+	RunE:         sshNativeCopyMain,
+	SilenceUsage: true,
+}
+
+// sshNativeConfiguration stores configuration for the native SSH helper
+// command.
+var sshNativeConfiguration struct {
+	// help indicates whether or not to show help information and exit.
+	help bool
+	// user is the remote user.
+	user string
+	// host is the remote host.
+	host string
+	// port is the remote port.
+	port uint16
+	// prompter is the prompter identifier to use for prompting.
+	prompter string
+	// connectTimeout is the connection timeout in seconds.
+	connectTimeout uint64
+	// keepaliveInterval is the keepalive interval in seconds.
+	keepaliveInterval uint64
+	// forwardAgent indicates whether or not to forward the SSH agent.
+	forwardAgent bool
+}
+
+func init() {
+	// Grab a handle for the command line flags.
+	flags := sshNativeCommand.PersistentFlags()
+
+	// Disable alphabetical sorting of flags in help output.
+	flags.SortFlags = false
+
+	// Manually add a help flag to override the default message. Cobra will
+	// still implement its logic automatically.
+	flags.BoolVarP(&sshNativeConfiguration.help, "help", "h", false, "Show help information")
+
+	// Wire up connection flags.
+	flags.StringVar(&sshNativeConfiguration.user, native.HelperFlagUser, "", "Specify the remote user")
+	flags.StringVar(&sshNativeConfiguration.host, native.HelperFlagHost, "", "Specify the remote host")
+	flags.Uint16Var(&sshNativeConfiguration.port, native.HelperFlagPort, 0, "Specify the remote port")
+	flags.StringVar(&sshNativeConfiguration.prompter, native.HelperFlagPrompter, "", "Specify the prompter")
+	flags.Uint64Var(&sshNativeConfiguration.connectTimeout, native.HelperFlagConnectTimeout, 0, "Specify the connection timeout in seconds")
+	flags.Uint64Var(&sshNativeConfiguration.keepaliveInterval, native.HelperFlagKeepaliveInterval, 0, "Specify the keepalive interval in seconds")
+	flags.BoolVar(&sshNativeConfiguration.forwardAgent, native.HelperFlagForwardAgent, false, "Enable SSH agent forwarding")
+
+	// Register commands.
+	sshNativeCommand.AddCommand(
+		sshNativeRunCommand,
+		sshNativeCopyCommand,
+	)
+}
@@ -0,0 +1,101 @@
+package sync
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/spf13/cobra"
+
+	"github.com/mutagen-io/mutagen/cmd"
+	"github.com/mutagen-io/mutagen/cmd/mutagen/daemon"
+
+	"github.com/mutagen-io/mutagen/pkg/grpcutil"
+	"github.com/mutagen-io/mutagen/pkg/selection"
+	promptingsvc "github.com/mutagen-io/mutagen/pkg/service/prompting"
+	synchronizationsvc "github.com/mutagen-io/mutagen/pkg/service/synchronization"
+)
+
+// acceptRootChangeMain is the entry point for the accept-root-change command.
+func acceptRootChangeMain(_ *cobra.Command, arguments []string) error {
+	// Extract the session specification.
+	if len(arguments) != 1 {
+		return errors.New("a single session must be specified")
+	}
+
+	// Create session selection specification.
+	selection := &selection.Selection{
+		Specifications: arguments,
+	}
+	if err := selection.EnsureValid(); err != nil {
+		return fmt.Errorf("invalid session selection specification: %w", err)
+	}
+
+	// Connect to the daemon and defer closure of the connection.
+	daemonConnection, err := daemon.Connect(true, true)
+	if err != nil {
+		return fmt.Errorf("unable to connect to daemon: %w", err)
+	}
+	defer daemonConnection.Close()
+
+	// Initiate command line prompting.
+	statusLinePrinter := &cmd.StatusLinePrinter{}
+	promptingCtx, promptingCancel := context.WithCancel(context.Background())
+	prompter, promptingErrors, err := promptingsvc.Host(
+		promptingCtx, promptingsvc.NewPromptingClient(daemonConnection),
+		&cmd.StatusLinePrompter{Printer: statusLinePrinter}, true,
+	)
+	if err != nil {
+		promptingCancel()
+		return fmt.Errorf("unable to initiate prompting: %w", err)
+	}
+
+	// Perform the accept root change operation, cancel prompting, and handle
+	// errors.
+	synchronizationService := synchronizationsvc.NewSynchronizationClient(daemonConnection)
+	request := &synchronizationsvc.AcceptRootChangeRequest{
+		Prompter:  prompter,
+		Selection: selection,
+	}
+	response, err := synchronizationService.AcceptRootChange(context.Background(), request)
+	promptingCancel()
+	<-promptingErrors
+	if err != nil {
+		statusLinePrinter.BreakIfPopulated()
+		return grpcutil.PeelAwayRPCErrorLayer(err)
+	} else if err = response.EnsureValid(); err != nil {
+		statusLinePrinter.BreakIfPopulated()
+		return fmt.Errorf("invalid accept root change response received: %w", err)
+	}
+
+	// Success.
+	statusLinePrinter.Clear()
+	return nil
+}
+
+// acceptRootChangeCommand is the accept-root-change command.
+var acceptRootChangeCommand = &cobra.Command{
+	Use:          "accept-root-change <session>",
+	Short:        "Approve the change that halted a synchronization session and resume it",
+	RunE:         acceptRootChangeMain,
+	SilenceUsage: true,
+}
+
+// acceptRootChangeConfiguration stores configuration for the
+// accept-root-change command.
+var acceptRootChangeConfiguration struct {
+	// help indicates whether or not to show help information and exit.
+	help bool
+}
+
+func init() {
+	// Grab a handle for the command line flags.
+	flags := acceptRootChangeCommand.Flags()
+
+	// Disable alphabetical sorting of flags in help output.
+	flags.SortFlags = false
+
+	// Manually add a help flag to override the default message. Cobra will
+	// still implement its logic automatically.
+	flags.BoolVarP(&acceptRootChangeConfiguration.help, "help", "h", false, "Show help information")
+}
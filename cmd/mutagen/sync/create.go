@@ -30,14 +30,20 @@ import (
 
 // loadAndValidateGlobalSynchronizationConfiguration loads a YAML-based global
 // configuration, extracts the synchronization component, converts it to a
-// Protocol Buffers session configuration, and validates it.
-func loadAndValidateGlobalSynchronizationConfiguration(path string) (*synchronization.Configuration, error) {
+// Protocol Buffers session configuration, and validates it. It also applies the
+// global configuration's SSH settings to the specified URLs.
+func loadAndValidateGlobalSynchronizationConfiguration(path string, urls ...*url.URL) (*synchronization.Configuration, error) {
 	// Load the YAML configuration.
 	yamlConfiguration, err := global.LoadConfiguration(path)
 	if err != nil {
 		return nil, err
 	}
 
+	// Apply SSH settings to URLs.
+	for _, u := range urls {
+		yamlConfiguration.ConfigureURL(u)
+	}
+
 	// Convert the YAML configuration to a Protocol Buffers representation and
 	// validate it.
 	configuration := yamlConfiguration.Synchronization.Defaults.ToInternal()
@@ -144,7 +150,7 @@ func createMain(_ *cobra.Command, arguments []string) error {
 		}
 
 		// Attempt to load the file. We allow it to not exist.
-		globalConfiguration, err := loadAndValidateGlobalSynchronizationConfiguration(globalConfigurationPath)
+		globalConfiguration, err := loadAndValidateGlobalSynchronizationConfiguration(globalConfigurationPath, alpha, beta)
 		if err != nil {
 			if !os.IsNotExist(err) {
 				return fmt.Errorf("unable to load global configuration: %w", err)
@@ -185,6 +191,74 @@ func createMain(_ *cobra.Command, arguments []string) error {
 		}
 	}
 
+	// Validate and convert the maximum total staging size.
+	var maximumStagingTotalSize uint64
+	if createConfiguration.maximumStagingTotalSize != "" {
+		if s, err := humanize.ParseBytes(createConfiguration.maximumStagingTotalSize); err != nil {
+			return fmt.Errorf("unable to parse maximum total staging size: %w", err)
+		} else {
+			maximumStagingTotalSize = s
+		}
+	}
+
+	// Validate and convert memory budget specifications.
+	var memoryBudget, memoryBudgetAlpha, memoryBudgetBeta uint64
+	if createConfiguration.memoryBudget != "" {
+		if b, err := humanize.ParseBytes(createConfiguration.memoryBudget); err != nil {
+			return fmt.Errorf("unable to parse memory budget: %w", err)
+		} else {
+			memoryBudget = b
+		}
+	}
+	if createConfiguration.memoryBudgetAlpha != "" {
+		if b, err := humanize.ParseBytes(createConfiguration.memoryBudgetAlpha); err != nil {
+			return fmt.Errorf("unable to parse memory budget for alpha: %w", err)
+		} else {
+			memoryBudgetAlpha = b
+		}
+	}
+	if createConfiguration.memoryBudgetBeta != "" {
+		if b, err := humanize.ParseBytes(createConfiguration.memoryBudgetBeta); err != nil {
+			return fmt.Errorf("unable to parse memory budget for beta: %w", err)
+		} else {
+			memoryBudgetBeta = b
+		}
+	}
+
+	// Validate and convert the maximum trash size.
+	var maximumTrashSize uint64
+	if createConfiguration.maximumTrashSize != "" {
+		if s, err := humanize.ParseBytes(createConfiguration.maximumTrashSize); err != nil {
+			return fmt.Errorf("unable to parse maximum trash size: %w", err)
+		} else {
+			maximumTrashSize = s
+		}
+	}
+
+	// Validate and convert minimum free space specifications.
+	var minimumFreeSpace, minimumFreeSpaceAlpha, minimumFreeSpaceBeta uint64
+	if createConfiguration.minimumFreeSpace != "" {
+		if s, err := humanize.ParseBytes(createConfiguration.minimumFreeSpace); err != nil {
+			return fmt.Errorf("unable to parse minimum free space: %w", err)
+		} else {
+			minimumFreeSpace = s
+		}
+	}
+	if createConfiguration.minimumFreeSpaceAlpha != "" {
+		if s, err := humanize.ParseBytes(createConfiguration.minimumFreeSpaceAlpha); err != nil {
+			return fmt.Errorf("unable to parse minimum free space for alpha: %w", err)
+		} else {
+			minimumFreeSpaceAlpha = s
+		}
+	}
+	if createConfiguration.minimumFreeSpaceBeta != "" {
+		if s, err := humanize.ParseBytes(createConfiguration.minimumFreeSpaceBeta); err != nil {
+			return fmt.Errorf("unable to parse minimum free space for beta: %w", err)
+		} else {
+			minimumFreeSpaceBeta = s
+		}
+	}
+
 	// Validate and convert probe mode specifications.
 	var probeMode, probeModeAlpha, probeModeBeta behavior.ProbeMode
 	if createConfiguration.probeMode != "" {
@@ -239,6 +313,40 @@ func createMain(_ *cobra.Command, arguments []string) error {
 		}
 	}
 
+	// Validate and convert the Unicode normalization mode specification.
+	var unicodeNormalizationMode core.UnicodeNormalizationMode
+	if createConfiguration.unicodeNormalizationMode != "" {
+		if err := unicodeNormalizationMode.UnmarshalText([]byte(createConfiguration.unicodeNormalizationMode)); err != nil {
+			return fmt.Errorf("unable to parse Unicode normalization mode: %w", err)
+		}
+	}
+
+	// Validate and convert the mirror enforcement mode specification.
+	var mirrorEnforcementMode synchronization.MirrorEnforcementMode
+	if createConfiguration.mirrorEnforcementMode != "" {
+		if err := mirrorEnforcementMode.UnmarshalText([]byte(createConfiguration.mirrorEnforcementMode)); err != nil {
+			return fmt.Errorf("unable to parse mirror enforcement mode: %w", err)
+		}
+	}
+
+	// Validate and convert replacement mode specifications.
+	var replacementMode, replacementModeAlpha, replacementModeBeta core.ReplacementMode
+	if createConfiguration.replacementMode != "" {
+		if err := replacementMode.UnmarshalText([]byte(createConfiguration.replacementMode)); err != nil {
+			return fmt.Errorf("unable to parse replacement mode: %w", err)
+		}
+	}
+	if createConfiguration.replacementModeAlpha != "" {
+		if err := replacementModeAlpha.UnmarshalText([]byte(createConfiguration.replacementModeAlpha)); err != nil {
+			return fmt.Errorf("unable to parse replacement mode for alpha: %w", err)
+		}
+	}
+	if createConfiguration.replacementModeBeta != "" {
+		if err := replacementModeBeta.UnmarshalText([]byte(createConfiguration.replacementModeBeta)); err != nil {
+			return fmt.Errorf("unable to parse replacement mode for beta: %w", err)
+		}
+	}
+
 	// Validate and convert the symbolic link mode specification.
 	var symbolicLinkMode core.SymbolicLinkMode
 	if createConfiguration.symbolicLinkMode != "" {
@@ -382,21 +490,39 @@ func createMain(_ *cobra.Command, arguments []string) error {
 	// Create the command line configuration and merge it into our cumulative
 	// configuration.
 	configuration = synchronization.MergeConfigurations(configuration, &synchronization.Configuration{
-		SynchronizationMode:    synchronizationMode,
-		MaximumEntryCount:      createConfiguration.maximumEntryCount,
-		MaximumStagingFileSize: maximumStagingFileSize,
-		ProbeMode:              probeMode,
-		ScanMode:               scanMode,
-		StageMode:              stageMode,
-		SymbolicLinkMode:       symbolicLinkMode,
-		WatchMode:              watchMode,
-		WatchPollingInterval:   createConfiguration.watchPollingInterval,
-		Ignores:                createConfiguration.ignores,
-		IgnoreVCSMode:          ignoreVCSMode,
-		DefaultFileMode:        uint32(defaultFileMode),
-		DefaultDirectoryMode:   uint32(defaultDirectoryMode),
-		DefaultOwner:           createConfiguration.defaultOwner,
-		DefaultGroup:           createConfiguration.defaultGroup,
+		SynchronizationMode:       synchronizationMode,
+		MaximumEntryCount:         createConfiguration.maximumEntryCount,
+		MaximumStagingFileSize:    maximumStagingFileSize,
+		MaximumStagingTotalSize:   maximumStagingTotalSize,
+		ProbeMode:                 probeMode,
+		ScanMode:                  scanMode,
+		ScanConcurrency:           createConfiguration.scanConcurrency,
+		MemoryBudget:              memoryBudget,
+		AgentNiceness:             createConfiguration.agentNiceness,
+		ModificationTimeTolerance: createConfiguration.modificationTimeTolerance,
+		StageMode:                 stageMode,
+		UnicodeNormalizationMode:  unicodeNormalizationMode,
+		ReplacementMode:           replacementMode,
+		MirrorEnforcementMode:     mirrorEnforcementMode,
+		SymbolicLinkMode:          symbolicLinkMode,
+		WatchMode:                 watchMode,
+		WatchPollingInterval:      createConfiguration.watchPollingInterval,
+		MinimumCycleInterval:      createConfiguration.minimumCycleInterval,
+		Ignores:                   createConfiguration.ignores,
+		IgnoreVCSMode:             ignoreVCSMode,
+		IgnoreVCSIgnores:          createConfiguration.ignoreVCSIgnores,
+		DefaultFileMode:           uint32(defaultFileMode),
+		DefaultDirectoryMode:      uint32(defaultDirectoryMode),
+		DefaultOwner:              createConfiguration.defaultOwner,
+		DefaultGroup:              createConfiguration.defaultGroup,
+		AfterSync:                 createConfiguration.afterSync,
+		BeforeApply:               createConfiguration.beforeApply,
+		DeletionThreshold:         createConfiguration.deletionThreshold,
+		TrashDirectory:            createConfiguration.trashDirectory,
+		TrashRetentionPeriod:      createConfiguration.trashRetentionPeriod,
+		MaximumTrashSize:          maximumTrashSize,
+		VersionCount:              createConfiguration.versionCount,
+		MinimumFreeSpace:          minimumFreeSpace,
 	})
 
 	// Create the creation specification.
@@ -405,26 +531,46 @@ func createMain(_ *cobra.Command, arguments []string) error {
 		Beta:          beta,
 		Configuration: configuration,
 		ConfigurationAlpha: &synchronization.Configuration{
-			ProbeMode:            probeModeAlpha,
-			ScanMode:             scanModeAlpha,
-			StageMode:            stageModeAlpha,
-			WatchMode:            watchModeAlpha,
-			WatchPollingInterval: createConfiguration.watchPollingIntervalAlpha,
-			DefaultFileMode:      uint32(defaultFileModeAlpha),
-			DefaultDirectoryMode: uint32(defaultDirectoryModeAlpha),
-			DefaultOwner:         createConfiguration.defaultOwnerAlpha,
-			DefaultGroup:         createConfiguration.defaultGroupAlpha,
+			ProbeMode:                 probeModeAlpha,
+			ScanMode:                  scanModeAlpha,
+			ScanConcurrency:           createConfiguration.scanConcurrencyAlpha,
+			MemoryBudget:              memoryBudgetAlpha,
+			AgentNiceness:             createConfiguration.agentNicenessAlpha,
+			ModificationTimeTolerance: createConfiguration.modificationTimeToleranceAlpha,
+			StageMode:                 stageModeAlpha,
+			ReplacementMode:           replacementModeAlpha,
+			WatchMode:                 watchModeAlpha,
+			WatchPollingInterval:      createConfiguration.watchPollingIntervalAlpha,
+			DefaultFileMode:           uint32(defaultFileModeAlpha),
+			DefaultDirectoryMode:      uint32(defaultDirectoryModeAlpha),
+			DefaultOwner:              createConfiguration.defaultOwnerAlpha,
+			DefaultGroup:              createConfiguration.defaultGroupAlpha,
+			AfterSync:                 createConfiguration.afterSyncAlpha,
+			BeforeApply:               createConfiguration.beforeApplyAlpha,
+			TrashDirectory:            createConfiguration.trashDirectoryAlpha,
+			VersionCount:              createConfiguration.versionCountAlpha,
+			MinimumFreeSpace:          minimumFreeSpaceAlpha,
 		},
 		ConfigurationBeta: &synchronization.Configuration{
-			ProbeMode:            probeModeBeta,
-			ScanMode:             scanModeBeta,
-			StageMode:            stageModeBeta,
-			WatchMode:            watchModeBeta,
-			WatchPollingInterval: createConfiguration.watchPollingIntervalBeta,
-			DefaultFileMode:      uint32(defaultFileModeBeta),
-			DefaultDirectoryMode: uint32(defaultDirectoryModeBeta),
-			DefaultOwner:         createConfiguration.defaultOwnerBeta,
-			DefaultGroup:         createConfiguration.defaultGroupBeta,
+			ProbeMode:                 probeModeBeta,
+			ScanMode:                  scanModeBeta,
+			ScanConcurrency:           createConfiguration.scanConcurrencyBeta,
+			MemoryBudget:              memoryBudgetBeta,
+			AgentNiceness:             createConfiguration.agentNicenessBeta,
+			ModificationTimeTolerance: createConfiguration.modificationTimeToleranceBeta,
+			StageMode:                 stageModeBeta,
+			ReplacementMode:           replacementModeBeta,
+			WatchMode:                 watchModeBeta,
+			WatchPollingInterval:      createConfiguration.watchPollingIntervalBeta,
+			DefaultFileMode:           uint32(defaultFileModeBeta),
+			DefaultDirectoryMode:      uint32(defaultDirectoryModeBeta),
+			DefaultOwner:              createConfiguration.defaultOwnerBeta,
+			DefaultGroup:              createConfiguration.defaultGroupBeta,
+			AfterSync:                 createConfiguration.afterSyncBeta,
+			BeforeApply:               createConfiguration.beforeApplyBeta,
+			TrashDirectory:            createConfiguration.trashDirectoryBeta,
+			VersionCount:              createConfiguration.versionCountBeta,
+			MinimumFreeSpace:          minimumFreeSpaceBeta,
 		},
 		Name:   createConfiguration.name,
 		Labels: labels,
@@ -484,6 +630,10 @@ var createConfiguration struct {
 	// maximumStagingFileSize is the maximum file size that endpoints will
 	// stage. It can be specified in human-friendly units.
 	maximumStagingFileSize string
+	// maximumStagingTotalSize is the maximum total size of files that
+	// endpoints will stage in a single synchronization cycle. It can be
+	// specified in human-friendly units.
+	maximumStagingTotalSize string
 	// probeMode specifies the filesystem probing mode to use for the session.
 	probeMode string
 	// probeModeAlpha specifies the filesystem probing mode to use for the
@@ -500,6 +650,44 @@ var createConfiguration struct {
 	// scanModeBeta specifies the scan mode to use for the session, taking
 	// priority over scanMode on beta if specified.
 	scanModeBeta string
+	// scanConcurrency specifies the maximum number of Goroutines that
+	// endpoints will use to walk and hash content during scans.
+	scanConcurrency uint32
+	// scanConcurrencyAlpha specifies the scan concurrency to use for the
+	// session, taking priority over scanConcurrency on alpha if specified.
+	scanConcurrencyAlpha uint32
+	// scanConcurrencyBeta specifies the scan concurrency to use for the
+	// session, taking priority over scanConcurrency on beta if specified.
+	scanConcurrencyBeta uint32
+	// memoryBudget specifies the approximate maximum amount of memory that
+	// endpoint scan caches should occupy before they're spilled to disk.
+	// It can be specified in human-friendly units.
+	memoryBudget string
+	// memoryBudgetAlpha specifies the memory budget to use for the session,
+	// taking priority over memoryBudget on alpha if specified.
+	memoryBudgetAlpha string
+	// memoryBudgetBeta specifies the memory budget to use for the session,
+	// taking priority over memoryBudget on beta if specified.
+	memoryBudgetBeta string
+	// agentNiceness specifies the scheduling niceness for agent processes.
+	agentNiceness uint32
+	// agentNicenessAlpha specifies the agent niceness to use for the session,
+	// taking priority over agentNiceness on alpha if specified.
+	agentNicenessAlpha uint32
+	// agentNicenessBeta specifies the agent niceness to use for the session,
+	// taking priority over agentNiceness on beta if specified.
+	agentNicenessBeta uint32
+	// modificationTimeTolerance specifies the tolerance (in milliseconds) used
+	// when comparing file modification times.
+	modificationTimeTolerance uint32
+	// modificationTimeToleranceAlpha specifies the modification time tolerance
+	// to use for the session, taking priority over modificationTimeTolerance on
+	// alpha if specified.
+	modificationTimeToleranceAlpha uint32
+	// modificationTimeToleranceBeta specifies the modification time tolerance
+	// to use for the session, taking priority over modificationTimeTolerance on
+	// beta if specified.
+	modificationTimeToleranceBeta uint32
 	// stageMode specifies the file staging mode to use for the session.
 	stageMode string
 	// stageModeAlpha specifies the file staging mode to use for the session,
@@ -508,6 +696,21 @@ var createConfiguration struct {
 	// stageModeBeta specifies the file staging mode to use for the session,
 	// taking priority over stageMode on beta if specified.
 	stageModeBeta string
+	// unicodeNormalizationMode specifies the Unicode normalization mode to use
+	// for the session.
+	unicodeNormalizationMode string
+	// mirrorEnforcementMode specifies the mirror enforcement mode to use for
+	// the session.
+	mirrorEnforcementMode string
+	// replacementMode specifies the file replacement mode to use for the
+	// session.
+	replacementMode string
+	// replacementModeAlpha specifies the file replacement mode to use for the
+	// session, taking priority over replacementMode on alpha if specified.
+	replacementModeAlpha string
+	// replacementModeBeta specifies the file replacement mode to use for the
+	// session, taking priority over replacementMode on beta if specified.
+	replacementModeBeta string
 	// symbolicLinkMode specifies the symbolic link handling mode to use for
 	// the session.
 	symbolicLinkMode string
@@ -530,6 +733,9 @@ var createConfiguration struct {
 	// poll-based or hybrid watching, taking priority over watchPollingInterval
 	// on beta if specified.
 	watchPollingIntervalBeta uint32
+	// minimumCycleInterval specifies the minimum interval (in seconds) between
+	// the starts of consecutive event-triggered synchronization cycles.
+	minimumCycleInterval uint32
 	// ignores is the list of ignore specifications for the session.
 	ignores []string
 	// ignoreVCS specifies whether or not to enable VCS ignores for the session.
@@ -537,6 +743,9 @@ var createConfiguration struct {
 	// noIgnoreVCS specifies whether or not to disable VCS ignores for the
 	// session.
 	noIgnoreVCS bool
+	// ignoreVCSIgnores specifies whether or not to import patterns from Git
+	// ignore files found within the alpha synchronization root.
+	ignoreVCSIgnores bool
 	// defaultFileMode specifies the default permission mode to use for new
 	// files in "portable" permission propagation mode, with endpoint-specific
 	// specifications taking priority.
@@ -589,6 +798,66 @@ var createConfiguration struct {
 	// permission propagation mode, taking priority over defaultGroup on beta if
 	// specified.
 	defaultGroupBeta string
+	// afterSync specifies commands to run on both endpoints after a
+	// synchronization cycle propagates changes to them.
+	afterSync []string
+	// afterSyncAlpha specifies commands to run on alpha after a
+	// synchronization cycle propagates changes to it.
+	afterSyncAlpha []string
+	// afterSyncBeta specifies commands to run on beta after a synchronization
+	// cycle propagates changes to it.
+	afterSyncBeta []string
+	// beforeApply specifies commands to run on both endpoints before changes
+	// are applied to them.
+	beforeApply []string
+	// beforeApplyAlpha specifies commands to run on alpha before changes are
+	// applied to it.
+	beforeApplyAlpha []string
+	// beforeApplyBeta specifies commands to run on beta before changes are
+	// applied to it.
+	beforeApplyBeta []string
+	// deletionThreshold specifies the maximum percentage of previously
+	// synchronized entries that a single synchronization cycle may delete from
+	// either endpoint before the session is halted.
+	deletionThreshold uint32
+	// trashDirectory specifies a directory on both endpoints in which files
+	// that are removed or overwritten by synchronization should be retained,
+	// with endpoint-specific specifications taking priority.
+	trashDirectory string
+	// trashDirectoryAlpha specifies a directory on alpha in which files that
+	// are removed or overwritten by synchronization should be retained, taking
+	// priority over trashDirectory on alpha if specified.
+	trashDirectoryAlpha string
+	// trashDirectoryBeta specifies a directory on beta in which files that are
+	// removed or overwritten by synchronization should be retained, taking
+	// priority over trashDirectory on beta if specified.
+	trashDirectoryBeta string
+	// trashRetentionPeriod specifies the period (in seconds) for which files
+	// should be retained in trash directories.
+	trashRetentionPeriod uint32
+	// maximumTrashSize specifies the maximum total size of files that should
+	// be retained in each trash directory.
+	maximumTrashSize string
+	// versionCount specifies the number of previous versions of each removed
+	// or overwritten file to keep on both endpoints.
+	versionCount uint32
+	// versionCountAlpha specifies the number of previous versions of each
+	// removed or overwritten file to keep on alpha. It takes priority over
+	// versionCount on alpha if specified.
+	versionCountAlpha uint32
+	// versionCountBeta specifies the number of previous versions of each
+	// removed or overwritten file to keep on beta. It takes priority over
+	// versionCount on beta if specified.
+	versionCountBeta uint32
+	// minimumFreeSpace specifies the amount of disk space that must remain
+	// available on endpoints after transitions are applied.
+	minimumFreeSpace string
+	// minimumFreeSpaceAlpha specifies the minimum free space for alpha, taking
+	// priority over minimumFreeSpace if specified.
+	minimumFreeSpaceAlpha string
+	// minimumFreeSpaceBeta specifies the minimum free space for beta, taking
+	// priority over minimumFreeSpace if specified.
+	minimumFreeSpaceBeta string
 }
 
 func init() {
@@ -617,18 +886,36 @@ func init() {
 	flags.StringVarP(&createConfiguration.synchronizationMode, "sync-mode", "m", "", "Specify synchronization mode (two-way-safe|two-way-resolved|one-way-safe|one-way-replica)")
 	flags.Uint64Var(&createConfiguration.maximumEntryCount, "max-entry-count", 0, "Specify the maximum number of entries that endpoints will manage")
 	flags.StringVar(&createConfiguration.maximumStagingFileSize, "max-staging-file-size", "", "Specify the maximum (individual) file size that endpoints will stage")
+	flags.StringVar(&createConfiguration.maximumStagingTotalSize, "max-staging-total-size", "", "Specify the maximum total file size that endpoints will stage per synchronization cycle")
 	flags.StringVar(&createConfiguration.probeMode, "probe-mode", "", "Specify probe mode (probe|assume)")
 	flags.StringVar(&createConfiguration.probeModeAlpha, "probe-mode-alpha", "", "Specify probe mode for alpha (probe|assume)")
 	flags.StringVar(&createConfiguration.probeModeBeta, "probe-mode-beta", "", "Specify probe mode for beta (probe|assume)")
 	flags.StringVar(&createConfiguration.scanMode, "scan-mode", "", "Specify scan mode (full|accelerated)")
 	flags.StringVar(&createConfiguration.scanModeAlpha, "scan-mode-alpha", "", "Specify scan mode for alpha (full|accelerated)")
 	flags.StringVar(&createConfiguration.scanModeBeta, "scan-mode-beta", "", "Specify scan mode for beta (full|accelerated)")
+	flags.Uint32Var(&createConfiguration.scanConcurrency, "scan-concurrency", 0, "Specify the maximum number of concurrent scanning workers")
+	flags.Uint32Var(&createConfiguration.scanConcurrencyAlpha, "scan-concurrency-alpha", 0, "Specify the maximum number of concurrent scanning workers for alpha")
+	flags.Uint32Var(&createConfiguration.scanConcurrencyBeta, "scan-concurrency-beta", 0, "Specify the maximum number of concurrent scanning workers for beta")
+	flags.StringVar(&createConfiguration.memoryBudget, "memory-budget", "", "Specify the approximate memory usage above which endpoints will spill state to disk")
+	flags.StringVar(&createConfiguration.memoryBudgetAlpha, "memory-budget-alpha", "", "Specify the approximate memory usage above which alpha will spill state to disk")
+	flags.StringVar(&createConfiguration.memoryBudgetBeta, "memory-budget-beta", "", "Specify the approximate memory usage above which beta will spill state to disk")
+	flags.Uint32Var(&createConfiguration.agentNiceness, "agent-niceness", 0, "Specify the scheduling niceness (1-19) for agent processes")
+	flags.Uint32Var(&createConfiguration.agentNicenessAlpha, "agent-niceness-alpha", 0, "Specify the scheduling niceness (1-19) for an alpha agent process")
+	flags.Uint32Var(&createConfiguration.agentNicenessBeta, "agent-niceness-beta", 0, "Specify the scheduling niceness (1-19) for a beta agent process")
+	flags.Uint32Var(&createConfiguration.modificationTimeTolerance, "mtime-tolerance", 0, "Specify the tolerance (in milliseconds) for modification time comparisons")
+	flags.Uint32Var(&createConfiguration.modificationTimeToleranceAlpha, "mtime-tolerance-alpha", 0, "Specify the tolerance (in milliseconds) for modification time comparisons on alpha")
+	flags.Uint32Var(&createConfiguration.modificationTimeToleranceBeta, "mtime-tolerance-beta", 0, "Specify the tolerance (in milliseconds) for modification time comparisons on beta")
 	flags.StringVar(&createConfiguration.stageMode, "stage-mode", "", "Specify staging mode (mutagen|neighboring)")
 	flags.StringVar(&createConfiguration.stageModeAlpha, "stage-mode-alpha", "", "Specify staging mode for alpha (mutagen|neighboring)")
 	flags.StringVar(&createConfiguration.stageModeBeta, "stage-mode-beta", "", "Specify staging mode for beta (mutagen|neighboring)")
+	flags.StringVar(&createConfiguration.unicodeNormalizationMode, "unicode-normalization", "", "Specify Unicode normalization mode (none|nfc|nfd)")
+	flags.StringVar(&createConfiguration.mirrorEnforcementMode, "mirror-enforcement", "", "Specify mirror enforcement mode for one-way-replica sessions (standard|strict)")
+	flags.StringVar(&createConfiguration.replacementMode, "replacement-mode", "", "Specify file replacement mode (rename|atomic)")
+	flags.StringVar(&createConfiguration.replacementModeAlpha, "replacement-mode-alpha", "", "Specify file replacement mode for alpha (rename|atomic)")
+	flags.StringVar(&createConfiguration.replacementModeBeta, "replacement-mode-beta", "", "Specify file replacement mode for beta (rename|atomic)")
 
 	// Wire up symbolic link flags.
-	flags.StringVar(&createConfiguration.symbolicLinkMode, "symlink-mode", "", "Specify symlink mode (ignore|portable|posix-raw)")
+	flags.StringVar(&createConfiguration.symbolicLinkMode, "symlink-mode", "", "Specify symlink mode (ignore|portable|posix-raw|rewrite-absolute|portable-windows|follow)")
 
 	// Wire up watch flags.
 	flags.StringVar(&createConfiguration.watchMode, "watch-mode", "", "Specify watch mode (portable|force-poll|no-watch)")
@@ -637,11 +924,13 @@ func init() {
 	flags.Uint32Var(&createConfiguration.watchPollingInterval, "watch-polling-interval", 0, "Specify watch polling interval in seconds")
 	flags.Uint32Var(&createConfiguration.watchPollingIntervalAlpha, "watch-polling-interval-alpha", 0, "Specify watch polling interval in seconds for alpha")
 	flags.Uint32Var(&createConfiguration.watchPollingIntervalBeta, "watch-polling-interval-beta", 0, "Specify watch polling interval in seconds for beta")
+	flags.Uint32Var(&createConfiguration.minimumCycleInterval, "minimum-cycle-interval", 0, "Specify the minimum interval in seconds between event-triggered synchronization cycles")
 
 	// Wire up ignore flags.
 	flags.StringSliceVarP(&createConfiguration.ignores, "ignore", "i", nil, "Specify ignore paths")
 	flags.BoolVar(&createConfiguration.ignoreVCS, "ignore-vcs", false, "Ignore VCS directories")
 	flags.BoolVar(&createConfiguration.noIgnoreVCS, "no-ignore-vcs", false, "Propagate VCS directories")
+	flags.BoolVar(&createConfiguration.ignoreVCSIgnores, "ignore-vcs-ignores", false, "Import ignore patterns from .gitignore files on alpha")
 
 	// Wire up permission flags.
 	flags.StringVar(&createConfiguration.defaultFileMode, "default-file-mode", "", "Specify default file permission mode")
@@ -656,4 +945,26 @@ func init() {
 	flags.StringVar(&createConfiguration.defaultGroup, "default-group", "", "Specify default file/directory group")
 	flags.StringVar(&createConfiguration.defaultGroupAlpha, "default-group-alpha", "", "Specify default file/directory group for alpha")
 	flags.StringVar(&createConfiguration.defaultGroupBeta, "default-group-beta", "", "Specify default file/directory group for beta")
+
+	// Wire up hook flags.
+	flags.StringArrayVar(&createConfiguration.afterSync, "after-sync", nil, "Specify a command to run on both endpoints after changes are propagated")
+	flags.StringArrayVar(&createConfiguration.afterSyncAlpha, "after-sync-alpha", nil, "Specify a command to run on alpha after changes are propagated")
+	flags.StringArrayVar(&createConfiguration.afterSyncBeta, "after-sync-beta", nil, "Specify a command to run on beta after changes are propagated")
+	flags.StringArrayVar(&createConfiguration.beforeApply, "before-apply", nil, "Specify a command to run on both endpoints before changes are applied (non-zero exit aborts)")
+	flags.StringArrayVar(&createConfiguration.beforeApplyAlpha, "before-apply-alpha", nil, "Specify a command to run on alpha before changes are applied (non-zero exit aborts)")
+	flags.StringArrayVar(&createConfiguration.beforeApplyBeta, "before-apply-beta", nil, "Specify a command to run on beta before changes are applied (non-zero exit aborts)")
+
+	// Wire up safety flags.
+	flags.Uint32Var(&createConfiguration.deletionThreshold, "deletion-threshold", 0, "Specify the maximum percentage of synchronized entries that a cycle may delete before halting (1-100)")
+	flags.StringVar(&createConfiguration.trashDirectory, "trash-directory", "", "Specify a directory in which to retain removed and overwritten files")
+	flags.StringVar(&createConfiguration.trashDirectoryAlpha, "trash-directory-alpha", "", "Specify a directory in which to retain removed and overwritten files on alpha")
+	flags.StringVar(&createConfiguration.trashDirectoryBeta, "trash-directory-beta", "", "Specify a directory in which to retain removed and overwritten files on beta")
+	flags.Uint32Var(&createConfiguration.trashRetentionPeriod, "trash-retention", 0, "Specify the trash retention period in seconds")
+	flags.StringVar(&createConfiguration.maximumTrashSize, "max-trash-size", "", "Specify the maximum total size of files retained in the trash")
+	flags.Uint32Var(&createConfiguration.versionCount, "version-count", 0, "Specify the number of previous file versions to keep")
+	flags.Uint32Var(&createConfiguration.versionCountAlpha, "version-count-alpha", 0, "Specify the number of previous file versions to keep on alpha")
+	flags.Uint32Var(&createConfiguration.versionCountBeta, "version-count-beta", 0, "Specify the number of previous file versions to keep on beta")
+	flags.StringVar(&createConfiguration.minimumFreeSpace, "min-free-space", "", "Specify the disk space that must remain available on endpoints after changes are applied")
+	flags.StringVar(&createConfiguration.minimumFreeSpaceAlpha, "min-free-space-alpha", "", "Specify the disk space that must remain available on alpha after changes are applied")
+	flags.StringVar(&createConfiguration.minimumFreeSpaceBeta, "min-free-space-beta", "", "Specify the disk space that must remain available on beta after changes are applied")
 }
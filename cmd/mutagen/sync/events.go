@@ -0,0 +1,109 @@
+package sync
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	"github.com/mutagen-io/mutagen/cmd/mutagen/daemon"
+
+	"github.com/mutagen-io/mutagen/pkg/grpcutil"
+	selectionpkg "github.com/mutagen-io/mutagen/pkg/selection"
+	synchronizationsvc "github.com/mutagen-io/mutagen/pkg/service/synchronization"
+	"github.com/mutagen-io/mutagen/pkg/synchronization/core"
+)
+
+// printPathChanges prints path changes for an endpoint, one per line, in the
+// form "<session> <endpoint> <kind> <path>".
+func printPathChanges(session, endpoint string, changes []*core.PathChange) {
+	for _, change := range changes {
+		path := change.Path
+		if path == "" {
+			path = "."
+		}
+		fmt.Println(session, endpoint, strings.ToLower(change.Kind.Description()), path)
+	}
+}
+
+// eventsMain is the entry point for the events command.
+func eventsMain(_ *cobra.Command, arguments []string) error {
+	// Create session selection specification that will select all sessions if
+	// no other criteria are provided.
+	selection := &selectionpkg.Selection{
+		All:            len(arguments) == 0 && eventsConfiguration.labelSelector == "",
+		Specifications: arguments,
+		LabelSelector:  eventsConfiguration.labelSelector,
+	}
+	if err := selection.EnsureValid(); err != nil {
+		return fmt.Errorf("invalid session selection specification: %w", err)
+	}
+
+	// Connect to the daemon and defer closure of the connection.
+	daemonConnection, err := daemon.Connect(true, true)
+	if err != nil {
+		return fmt.Errorf("unable to connect to daemon: %w", err)
+	}
+	defer daemonConnection.Close()
+
+	// Create a session service client.
+	synchronizationService := synchronizationsvc.NewSynchronizationClient(daemonConnection)
+
+	// Start the event stream.
+	stream, err := synchronizationService.Events(context.Background(), &synchronizationsvc.EventsRequest{
+		Selection: selection,
+	})
+	if err != nil {
+		return fmt.Errorf("unable to start event stream: %w", grpcutil.PeelAwayRPCErrorLayer(err))
+	}
+
+	// Print events until the stream terminates.
+	for {
+		response, err := stream.Recv()
+		if err != nil {
+			if errors.Is(err, io.EOF) {
+				return nil
+			}
+			return fmt.Errorf("event stream failed: %w", grpcutil.PeelAwayRPCErrorLayer(err))
+		} else if err = response.EnsureValid(); err != nil {
+			return fmt.Errorf("invalid events response received: %w", err)
+		}
+		printPathChanges(response.Event.Session, "alpha", response.Event.AlphaChanges)
+		printPathChanges(response.Event.Session, "beta", response.Event.BetaChanges)
+	}
+}
+
+// eventsCommand is the events command.
+var eventsCommand = &cobra.Command{
+	Use:          "events [<session>...]",
+	Short:        "Stream the paths changed on each endpoint by synchronization",
+	RunE:         eventsMain,
+	SilenceUsage: true,
+}
+
+// eventsConfiguration stores configuration for the events command.
+var eventsConfiguration struct {
+	// help indicates whether or not to show help information and exit.
+	help bool
+	// labelSelector encodes a label selector to be used in identifying which
+	// sessions should be streamed.
+	labelSelector string
+}
+
+func init() {
+	// Grab a handle for the command line flags.
+	flags := eventsCommand.Flags()
+
+	// Disable alphabetical sorting of flags in help output.
+	flags.SortFlags = false
+
+	// Manually add a help flag to override the default message. Cobra will
+	// still implement its logic automatically.
+	flags.BoolVarP(&eventsConfiguration.help, "help", "h", false, "Show help information")
+
+	// Wire up events flags.
+	flags.StringVar(&eventsConfiguration.labelSelector, "label-selector", "", "Stream events for sessions matching the specified label selector")
+}
@@ -3,6 +3,7 @@ package sync
 import (
 	"fmt"
 	"math"
+	"time"
 
 	"github.com/dustin/go-humanize"
 
@@ -52,6 +53,14 @@ func formatSymbolicLinkCount(count uint64) string {
 	return fmt.Sprintf("%d symbolic links", count)
 }
 
+// formatCapability formats a boolean filesystem capability for display.
+func formatCapability(supported bool) string {
+	if supported {
+		return "Yes"
+	}
+	return "No"
+}
+
 // formatPath formats a path for display.
 func formatPath(path string) string {
 	if path == "" {
@@ -127,6 +136,51 @@ func printEndpoint(name string, url *url.URL, configuration *synchronization.Con
 		}
 		fmt.Println("\t\tScan mode:", scanModeDescription)
 
+		// Compute and print the scan concurrency.
+		var scanConcurrencyDescription string
+		if configuration.ScanConcurrency == 0 {
+			scanConcurrencyDescription = fmt.Sprintf("Default (%d)", version.DefaultScanConcurrency())
+		} else {
+			scanConcurrencyDescription = fmt.Sprint(configuration.ScanConcurrency)
+		}
+		fmt.Println("\t\tScan concurrency:", scanConcurrencyDescription)
+
+		// Compute and print the memory budget.
+		var memoryBudgetDescription string
+		if configuration.MemoryBudget == 0 {
+			memoryBudgetDescription = fmt.Sprintf("Default (%s)", humanize.Bytes(version.DefaultMemoryBudget()))
+		} else {
+			memoryBudgetDescription = fmt.Sprintf(
+				"%d (%s)",
+				configuration.MemoryBudget,
+				humanize.Bytes(configuration.MemoryBudget),
+			)
+		}
+		fmt.Println("\t\tMemory budget:", memoryBudgetDescription)
+
+		// Compute and print the agent niceness.
+		agentNicenessDescription := "Default (unadjusted)"
+		if configuration.AgentNiceness != 0 {
+			agentNicenessDescription = fmt.Sprintf("%d", configuration.AgentNiceness)
+		}
+		fmt.Println("\t\tAgent niceness:", agentNicenessDescription)
+
+		// Compute and print the modification time tolerance.
+		var modificationTimeToleranceDescription string
+		if configuration.ModificationTimeTolerance == 0 {
+			modificationTimeToleranceDescription = fmt.Sprintf("Default (%d ms)", version.DefaultModificationTimeTolerance())
+		} else {
+			modificationTimeToleranceDescription = fmt.Sprintf("%d ms", configuration.ModificationTimeTolerance)
+		}
+		fmt.Println("\t\tModification time tolerance:", modificationTimeToleranceDescription)
+
+		// Compute and print the replacement mode.
+		replacementModeDescription := configuration.ReplacementMode.Description()
+		if configuration.ReplacementMode.IsDefault() {
+			replacementModeDescription += fmt.Sprintf(" (%s)", version.DefaultReplacementMode().Description())
+		}
+		fmt.Println("\t\tReplacement mode:", replacementModeDescription)
+
 		// Compute and print the staging mode.
 		stageModeDescription := configuration.StageMode.Description()
 		if configuration.StageMode.IsDefault() {
@@ -165,6 +219,71 @@ func printEndpoint(name string, url *url.URL, configuration *synchronization.Con
 			defaultGroupDescription = configuration.DefaultGroup
 		}
 		fmt.Println("\t\tDefault file/directory group:", defaultGroupDescription)
+
+		// Print before-apply hooks.
+		if len(configuration.BeforeApply) > 0 {
+			fmt.Println("\t\tBefore-apply hooks:")
+			for _, command := range configuration.BeforeApply {
+				fmt.Printf("\t\t\t%s\n", command)
+			}
+		} else {
+			fmt.Println("\t\tBefore-apply hooks: None")
+		}
+
+		// Print after-sync hooks.
+		if len(configuration.AfterSync) > 0 {
+			fmt.Println("\t\tAfter-sync hooks:")
+			for _, command := range configuration.AfterSync {
+				fmt.Printf("\t\t\t%s\n", command)
+			}
+		} else {
+			fmt.Println("\t\tAfter-sync hooks: None")
+		}
+
+		// Print trash configuration.
+		if configuration.TrashDirectory != "" {
+			fmt.Println("\t\tTrash directory:", configuration.TrashDirectory)
+			var trashRetentionPeriodDescription string
+			if configuration.TrashRetentionPeriod == 0 {
+				trashRetentionPeriodDescription = fmt.Sprintf("Default (%d seconds)", version.DefaultTrashRetentionPeriod())
+			} else {
+				trashRetentionPeriodDescription = fmt.Sprintf("%d seconds", configuration.TrashRetentionPeriod)
+			}
+			fmt.Println("\t\tTrash retention period:", trashRetentionPeriodDescription)
+			var maximumTrashSizeDescription string
+			if configuration.MaximumTrashSize == 0 {
+				maximumTrashSizeDescription = fmt.Sprintf("Default (%s)", humanize.Bytes(version.DefaultMaximumTrashSize()))
+			} else {
+				maximumTrashSizeDescription = fmt.Sprintf(
+					"%d (%s)",
+					configuration.MaximumTrashSize,
+					humanize.Bytes(configuration.MaximumTrashSize),
+				)
+			}
+			fmt.Println("\t\tMaximum trash size:", maximumTrashSizeDescription)
+		} else {
+			fmt.Println("\t\tTrash directory: None")
+		}
+
+		// Print versioning configuration.
+		if configuration.VersionCount > 0 {
+			fmt.Println("\t\tFile versions kept:", configuration.VersionCount)
+		} else {
+			fmt.Println("\t\tFile versioning: Disabled")
+		}
+
+		// Compute and print the minimum free space.
+		var minimumFreeSpaceDescription string
+		if configuration.MinimumFreeSpace == 0 {
+			minimumFreeSpaceDescription = fmt.Sprintf("Default (%s)", humanize.Bytes(version.DefaultMinimumFreeSpace()))
+		} else {
+			minimumFreeSpaceDescription = fmt.Sprintf(
+				"%d (%s)",
+				configuration.MinimumFreeSpace,
+				humanize.Bytes(configuration.MinimumFreeSpace),
+			)
+		}
+		fmt.Println("\t\tMinimum free space:", minimumFreeSpaceDescription)
 	}
 
 	// At this point, there's no other status information that will be displayed
@@ -186,6 +305,17 @@ func printEndpoint(name string, url *url.URL, configuration *synchronization.Con
 		)
 	}
 
+	// Print filesystem capabilities, if available.
+	if mode == common.SessionDisplayModeListLong && state.Capabilities != nil {
+		fmt.Println("\tFilesystem capabilities:")
+		fmt.Println("\t\tCase sensitive:", formatCapability(state.Capabilities.CaseSensitive))
+		fmt.Println("\t\tSymbolic links:", formatCapability(state.Capabilities.SymbolicLinks))
+		fmt.Println("\t\tPreserves executability:", formatCapability(state.Capabilities.PreservesExecutability))
+		fmt.Println("\t\tModification time granularity:",
+			time.Duration(state.Capabilities.ModificationTimeGranularity),
+		)
+	}
+
 	// Print scan problems, if any.
 	if len(state.ScanProblems) > 0 {
 		if mode == common.SessionDisplayModeList {
@@ -266,6 +396,47 @@ func printConflicts(conflicts []*core.Conflict, excludedConflicts uint64) {
 	}
 }
 
+// printMirrorViolations prints a list of mirror violations.
+func printMirrorViolations(violations []string, excludedViolations, totalViolations uint64) {
+	// Print the header.
+	color.Red("Mirror violations (%d total, reverted):\n", totalViolations)
+
+	// Print violations.
+	for _, v := range violations {
+		color.Red("\t%s\n", formatPath(v))
+	}
+
+	// Print excluded violations.
+	if excludedViolations > 0 {
+		color.Red("\t...+%d more...\n", excludedViolations)
+	}
+}
+
+// formatCycleStatistics formats synchronization cycle statistics for display.
+func formatCycleStatistics(statistics *synchronization.CycleStatistics) string {
+	result := fmt.Sprintf("%s, %d staged file(s) (%s), %s transferred",
+		time.Duration(statistics.Duration).Round(time.Millisecond),
+		statistics.StagedFiles,
+		humanize.Bytes(statistics.StagedSize),
+		humanize.Bytes(statistics.TransferredSize),
+	)
+	if ratio, ok := statistics.DeltaCompressionRatio(); ok {
+		result += fmt.Sprintf(", %.2fx delta compression", ratio)
+	}
+	return result
+}
+
+// printStatistics prints session synchronization statistics.
+func printStatistics(statistics *synchronization.SessionStatistics) {
+	fmt.Println("Statistics:")
+	fmt.Println("\tSuccessful cycles:", statistics.Cycles)
+	fmt.Println("\tLast cycle:", formatCycleStatistics(statistics.LastCycle))
+	fmt.Println("\tAll cycles:", formatCycleStatistics(statistics.Total))
+	fmt.Println("\tAverage cycle duration:",
+		(time.Duration(statistics.Total.Duration) / time.Duration(statistics.Cycles)).Round(time.Millisecond),
+	)
+}
+
 // printSession prints the configuration and status of a synchronization
 // session and its endpoints.
 func printSession(state *synchronization.State, mode common.SessionDisplayMode) {
@@ -335,6 +506,22 @@ func printSession(state *synchronization.State, mode common.SessionDisplayMode)
 		}
 		fmt.Println("\tMaximum staging file size:", maximumStagingFileSizeDescription)
 
+		// Compute and print maximum total staging size.
+		var maximumStagingTotalSizeDescription string
+		if configuration.MaximumStagingTotalSize == 0 {
+			maximumStagingTotalSizeDescription = fmt.Sprintf(
+				"Default (%s)",
+				humanize.Bytes(state.Session.Version.DefaultMaximumStagingTotalSize()),
+			)
+		} else {
+			maximumStagingTotalSizeDescription = fmt.Sprintf(
+				"%d (%s)",
+				configuration.MaximumStagingTotalSize,
+				humanize.Bytes(configuration.MaximumStagingTotalSize),
+			)
+		}
+		fmt.Println("\tMaximum total staging size:", maximumStagingTotalSizeDescription)
+
 		// Compute and print symlink mode.
 		symbolicLinkModeDescription := configuration.SymbolicLinkMode.Description()
 		if configuration.SymbolicLinkMode.IsDefault() {
@@ -343,6 +530,41 @@ func printSession(state *synchronization.State, mode common.SessionDisplayMode)
 		}
 		fmt.Println("\tSymbolic link mode:", symbolicLinkModeDescription)
 
+		// Compute and print Unicode normalization mode.
+		unicodeNormalizationModeDescription := configuration.UnicodeNormalizationMode.Description()
+		if configuration.UnicodeNormalizationMode.IsDefault() {
+			defaultUnicodeNormalizationMode := state.Session.Version.DefaultUnicodeNormalizationMode()
+			unicodeNormalizationModeDescription += fmt.Sprintf(" (%s)", defaultUnicodeNormalizationMode.Description())
+		}
+		fmt.Println("\tUnicode normalization mode:", unicodeNormalizationModeDescription)
+
+		// Compute and print mirror enforcement mode.
+		mirrorEnforcementModeDescription := configuration.MirrorEnforcementMode.Description()
+		if configuration.MirrorEnforcementMode.IsDefault() {
+			defaultMirrorEnforcementMode := state.Session.Version.DefaultMirrorEnforcementMode()
+			mirrorEnforcementModeDescription += fmt.Sprintf(" (%s)", defaultMirrorEnforcementMode.Description())
+		}
+		fmt.Println("\tMirror enforcement mode:", mirrorEnforcementModeDescription)
+
+		// Compute and print the deletion threshold.
+		var deletionThresholdDescription string
+		if configuration.DeletionThreshold == 0 {
+			deletionThresholdDescription = fmt.Sprintf(
+				"Default (%d%%)",
+				state.Session.Version.DefaultDeletionThreshold(),
+			)
+		} else {
+			deletionThresholdDescription = fmt.Sprintf("%d%%", configuration.DeletionThreshold)
+		}
+		fmt.Println("\tDeletion threshold:", deletionThresholdDescription)
+
+		// Compute and print the minimum cycle interval.
+		minimumCycleIntervalDescription := "None"
+		if configuration.MinimumCycleInterval != 0 {
+			minimumCycleIntervalDescription = fmt.Sprintf("%d seconds", configuration.MinimumCycleInterval)
+		}
+		fmt.Println("\tMinimum cycle interval:", minimumCycleIntervalDescription)
+
 		// Compute and print the VCS ignore mode.
 		ignoreVCSModeDescription := configuration.IgnoreVCSMode.Description()
 		if configuration.IgnoreVCSMode.IsDefault() {
@@ -351,6 +573,9 @@ func printSession(state *synchronization.State, mode common.SessionDisplayMode)
 		}
 		fmt.Println("\tIgnore VCS mode:", ignoreVCSModeDescription)
 
+		// Print whether or not VCS ignore files are imported.
+		fmt.Println("\tImport VCS ignore files:", configuration.IgnoreVCSIgnores)
+
 		// Print default ignores. Since this field is deprecated, we don't print
 		// it if it's not set.
 		if len(configuration.DefaultIgnores) > 0 {
@@ -402,6 +627,12 @@ func printSession(state *synchronization.State, mode common.SessionDisplayMode)
 		return
 	}
 
+	// Print statistics, if any cycles have been recorded.
+	if mode == common.SessionDisplayModeListLong && !state.Session.Paused &&
+		state.Statistics != nil && state.Statistics.Cycles > 0 {
+		printStatistics(state.Statistics)
+	}
+
 	// Print conflicts, if any.
 	if len(state.Conflicts) > 0 {
 		if mode == common.SessionDisplayModeList {
@@ -411,6 +642,15 @@ func printSession(state *synchronization.State, mode common.SessionDisplayMode)
 		}
 	}
 
+	// Print mirror violations, if any.
+	if len(state.MirrorViolations) > 0 {
+		if mode == common.SessionDisplayModeList {
+			color.Red("Mirror violations: %d\n", state.TotalMirrorViolations)
+		} else if mode == common.SessionDisplayModeListLong {
+			printMirrorViolations(state.MirrorViolations, state.ExcludedMirrorViolations, state.TotalMirrorViolations)
+		}
+	}
+
 	// Print the last error, if any.
 	if state.LastError != "" {
 		color.Red("Last error: %s\n", state.LastError)
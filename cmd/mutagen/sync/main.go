@@ -46,9 +46,16 @@ func init() {
 		createCommand,
 		listCommand,
 		monitorCommand,
+		eventsCommand,
 		flushCommand,
+		verifyCommand,
+		repairCommand,
+		versionsCommand,
+		statusCommand,
+		problemsCommand,
 		pauseCommand,
 		resumeCommand,
+		acceptRootChangeCommand,
 		resetCommand,
 		terminateCommand,
 	)
@@ -27,9 +27,9 @@ import (
 	"github.com/mutagen-io/mutagen/pkg/synchronization/rsync"
 )
 
-// computeMonitorStatusLine constructs a monitoring status line for a
+// ComputeMonitorStatusLine constructs a monitoring status line for a
 // synchronization session.
-func computeMonitorStatusLine(state *synchronization.State) string {
+func ComputeMonitorStatusLine(state *synchronization.State) string {
 	// Build the status line.
 	var status string
 	if state.Session.Paused {
@@ -49,6 +49,11 @@ func computeMonitorStatusLine(state *synchronization.State) string {
 			status += color.YellowString("[!] ")
 		}
 
+		// Add a mirror violation flag if violations were reverted.
+		if len(state.MirrorViolations) > 0 {
+			status += color.YellowString("[M] ")
+		}
+
 		// Add an error flag if there is one present.
 		if state.LastError != "" {
 			status += color.RedString("[X] ")
@@ -231,7 +236,7 @@ func monitorMain(_ *cobra.Command, arguments []string) error {
 		}
 
 		// Compute the status line.
-		statusLine := computeMonitorStatusLine(state)
+		statusLine := ComputeMonitorStatusLine(state)
 
 		// Print the status line.
 		statusLinePrinter.Print(statusLine)
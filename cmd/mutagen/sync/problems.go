@@ -0,0 +1,215 @@
+package sync
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+
+	"github.com/fatih/color"
+
+	"github.com/spf13/cobra"
+
+	"github.com/mutagen-io/mutagen/cmd/mutagen/common/templating"
+	"github.com/mutagen-io/mutagen/cmd/mutagen/daemon"
+
+	synchronizationmodels "github.com/mutagen-io/mutagen/pkg/api/models/synchronization"
+	"github.com/mutagen-io/mutagen/pkg/grpcutil"
+	"github.com/mutagen-io/mutagen/pkg/selection"
+	synchronizationsvc "github.com/mutagen-io/mutagen/pkg/service/synchronization"
+	"github.com/mutagen-io/mutagen/pkg/synchronization"
+	"github.com/mutagen-io/mutagen/pkg/synchronization/core"
+)
+
+const (
+	// problemsPageSize is the number of entries requested per list when paging
+	// through problem and conflict lists.
+	problemsPageSize = 1000
+)
+
+// listPageComplete determines whether or not a page of a list (starting at the
+// specified offset) reaches the end of the list.
+func listPageComplete(offset uint64, length int, excluded uint64) bool {
+	return offset+uint64(length) >= uint64(length)+excluded
+}
+
+// statePageComplete determines whether or not a page of session state
+// (starting at the specified offset) reaches the end of all of its lists.
+func statePageComplete(page *synchronization.State, offset uint64) bool {
+	return listPageComplete(offset, len(page.Conflicts), page.ExcludedConflicts) &&
+		listPageComplete(offset, len(page.MirrorViolations), page.ExcludedMirrorViolations) &&
+		listPageComplete(offset, len(page.AlphaState.ScanProblems), page.AlphaState.ExcludedScanProblems) &&
+		listPageComplete(offset, len(page.AlphaState.TransitionProblems), page.AlphaState.ExcludedTransitionProblems) &&
+		listPageComplete(offset, len(page.BetaState.ScanProblems), page.BetaState.ExcludedScanProblems) &&
+		listPageComplete(offset, len(page.BetaState.TransitionProblems), page.BetaState.ExcludedTransitionProblems)
+}
+
+// appendStatePage appends the conflict, problem, and mirror violation lists
+// from a page of session state to an accumulated state.
+func appendStatePage(accumulated, page *synchronization.State) {
+	accumulated.Conflicts = append(accumulated.Conflicts, page.Conflicts...)
+	accumulated.MirrorViolations = append(accumulated.MirrorViolations, page.MirrorViolations...)
+	accumulated.AlphaState.ScanProblems = append(accumulated.AlphaState.ScanProblems, page.AlphaState.ScanProblems...)
+	accumulated.AlphaState.TransitionProblems = append(accumulated.AlphaState.TransitionProblems, page.AlphaState.TransitionProblems...)
+	accumulated.BetaState.ScanProblems = append(accumulated.BetaState.ScanProblems, page.BetaState.ScanProblems...)
+	accumulated.BetaState.TransitionProblems = append(accumulated.BetaState.TransitionProblems, page.BetaState.TransitionProblems...)
+}
+
+// printProblems prints a list of problems.
+func printProblems(header string, problems []*core.Problem, excluded uint64) {
+	if len(problems) == 0 && excluded == 0 {
+		return
+	}
+	color.Red("%s:\n", header)
+	for _, p := range problems {
+		color.Red("\t%s: %v\n", formatPath(p.Path), p.Error)
+	}
+	if excluded > 0 {
+		color.Red("\t...+%d more...\n", excluded)
+	}
+}
+
+// problemsMain is the entry point for the problems command.
+func problemsMain(_ *cobra.Command, arguments []string) error {
+	// Extract the session specification.
+	if len(arguments) != 1 {
+		return errors.New("a single session must be specified")
+	}
+
+	// Create session selection specification.
+	selection := &selection.Selection{
+		Specifications: arguments,
+	}
+	if err := selection.EnsureValid(); err != nil {
+		return fmt.Errorf("invalid session selection specification: %w", err)
+	}
+
+	// Load the formatting template (if any has been specified).
+	template, err := problemsConfiguration.TemplateFlags.LoadTemplate()
+	if err != nil {
+		return fmt.Errorf("unable to load formatting template: %w", err)
+	}
+
+	// Connect to the daemon and defer closure of the connection.
+	daemonConnection, err := daemon.Connect(true, true)
+	if err != nil {
+		return fmt.Errorf("unable to connect to daemon: %w", err)
+	}
+	defer daemonConnection.Close()
+
+	// Determine the page size. If we're not listing all entries, then we use
+	// the default limits and perform only a single request.
+	var limit uint64
+	if problemsConfiguration.all {
+		limit = problemsPageSize
+	}
+
+	// Perform list operations, paging through lists if requested. Because each
+	// page is a separate listing, lists may shift slightly between pages if the
+	// session state changes while paging.
+	synchronizationService := synchronizationsvc.NewSynchronizationClient(daemonConnection)
+	var state *synchronization.State
+	for offset := uint64(0); ; offset += limit {
+		request := &synchronizationsvc.ListRequest{
+			Selection:  selection,
+			ListOffset: offset,
+			ListLimit:  limit,
+		}
+		response, err := synchronizationService.List(context.Background(), request)
+		if err != nil {
+			return grpcutil.PeelAwayRPCErrorLayer(err)
+		} else if err = response.EnsureValid(); err != nil {
+			return fmt.Errorf("invalid list response received: %w", err)
+		} else if len(response.SessionStates) != 1 {
+			return errors.New("invalid number of session states received")
+		}
+		page := response.SessionStates[0]
+
+		// If we're not paging, then we're done.
+		if !problemsConfiguration.all {
+			state = page
+			break
+		}
+
+		// Accumulate the page.
+		complete := statePageComplete(page, offset)
+		if state == nil {
+			state = page
+		} else {
+			appendStatePage(state, page)
+		}
+		if complete {
+			state.ExcludedConflicts = 0
+			state.ExcludedMirrorViolations = 0
+			state.AlphaState.ExcludedScanProblems = 0
+			state.AlphaState.ExcludedTransitionProblems = 0
+			state.BetaState.ExcludedScanProblems = 0
+			state.BetaState.ExcludedTransitionProblems = 0
+			break
+		}
+	}
+
+	// If a template was specified, then use that to format output with public
+	// model types, otherwise use custom formatting code.
+	if template != nil {
+		sessions := synchronizationmodels.ExportSessions([]*synchronization.State{state})
+		if err := template.Execute(os.Stdout, sessions); err != nil {
+			return fmt.Errorf("unable to execute formatting template: %w", err)
+		}
+		return nil
+	}
+
+	// Print lists.
+	printProblems("Alpha scan problems", state.AlphaState.ScanProblems, state.AlphaState.ExcludedScanProblems)
+	printProblems("Alpha transition problems", state.AlphaState.TransitionProblems, state.AlphaState.ExcludedTransitionProblems)
+	printProblems("Beta scan problems", state.BetaState.ScanProblems, state.BetaState.ExcludedScanProblems)
+	printProblems("Beta transition problems", state.BetaState.TransitionProblems, state.BetaState.ExcludedTransitionProblems)
+	if len(state.Conflicts) > 0 {
+		printConflicts(state.Conflicts, state.ExcludedConflicts)
+	}
+	if len(state.AlphaState.ScanProblems) == 0 && len(state.AlphaState.TransitionProblems) == 0 &&
+		len(state.BetaState.ScanProblems) == 0 && len(state.BetaState.TransitionProblems) == 0 &&
+		len(state.Conflicts) == 0 {
+		fmt.Println("No problems or conflicts")
+	}
+
+	// Success.
+	return nil
+}
+
+// problemsCommand is the problems command.
+var problemsCommand = &cobra.Command{
+	Use:          "problems <session>",
+	Short:        "List problems and conflicts for a synchronization session",
+	RunE:         problemsMain,
+	SilenceUsage: true,
+}
+
+// problemsConfiguration stores configuration for the problems command.
+var problemsConfiguration struct {
+	// help indicates whether or not to show help information and exit.
+	help bool
+	// all indicates whether or not to list all problems and conflicts rather
+	// than a truncated subset.
+	all bool
+	// TemplateFlags store custom templating behavior.
+	templating.TemplateFlags
+}
+
+func init() {
+	// Grab a handle for the command line flags.
+	flags := problemsCommand.Flags()
+
+	// Disable alphabetical sorting of flags in help output.
+	flags.SortFlags = false
+
+	// Manually add a help flag to override the default message. Cobra will
+	// still implement its logic automatically.
+	flags.BoolVarP(&problemsConfiguration.help, "help", "h", false, "Show help information")
+
+	// Wire up problems flags.
+	flags.BoolVar(&problemsConfiguration.all, "all", false, "List all problems and conflicts without truncation")
+
+	// Wire up templating flags.
+	problemsConfiguration.TemplateFlags.Register(flags)
+}
@@ -0,0 +1,166 @@
+package sync
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"path"
+	"path/filepath"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	"github.com/mutagen-io/mutagen/cmd"
+	"github.com/mutagen-io/mutagen/cmd/mutagen/daemon"
+
+	"github.com/mutagen-io/mutagen/pkg/grpcutil"
+	"github.com/mutagen-io/mutagen/pkg/selection"
+	promptingsvc "github.com/mutagen-io/mutagen/pkg/service/prompting"
+	synchronizationsvc "github.com/mutagen-io/mutagen/pkg/service/synchronization"
+	"github.com/mutagen-io/mutagen/pkg/synchronization/core"
+)
+
+// normalizeRepairPath converts a user-specified path into a root-relative
+// synchronization path.
+func normalizeRepairPath(value string) (string, error) {
+	// Convert to forward slashes and clean the path.
+	cleaned := path.Clean(filepath.ToSlash(value))
+
+	// Reject paths that aren't relative to the synchronization root.
+	if path.IsAbs(cleaned) {
+		return "", errors.New("path is not relative to synchronization root")
+	} else if cleaned == ".." || strings.HasPrefix(cleaned, "../") {
+		return "", errors.New("path is outside synchronization root")
+	}
+
+	// Handle the case of the synchronization root.
+	if cleaned == "." {
+		return "", nil
+	}
+
+	// Perform a final validation.
+	if err := core.ValidatePath(cleaned); err != nil {
+		return "", err
+	}
+
+	// Success.
+	return cleaned, nil
+}
+
+// repairMain is the entry point for the repair command.
+func repairMain(_ *cobra.Command, arguments []string) error {
+	// Extract the session specification.
+	if len(arguments) < 1 {
+		return errors.New("no session specified")
+	}
+	specification := arguments[0]
+
+	// Extract and normalize paths. If no paths have been specified, then
+	// repair the entire synchronization root.
+	paths := []string{""}
+	if len(arguments) > 1 {
+		paths = paths[:0]
+		for _, argument := range arguments[1:] {
+			normalized, err := normalizeRepairPath(argument)
+			if err != nil {
+				return fmt.Errorf("invalid path (%s): %w", argument, err)
+			}
+			paths = append(paths, normalized)
+		}
+	}
+
+	// Create session selection specification.
+	selection := &selection.Selection{
+		Specifications: []string{specification},
+	}
+	if err := selection.EnsureValid(); err != nil {
+		return fmt.Errorf("invalid session selection specification: %w", err)
+	}
+
+	// Connect to the daemon and defer closure of the connection.
+	daemonConnection, err := daemon.Connect(true, true)
+	if err != nil {
+		return fmt.Errorf("unable to connect to daemon: %w", err)
+	}
+	defer daemonConnection.Close()
+
+	// Initiate command line messaging.
+	statusLinePrinter := &cmd.StatusLinePrinter{}
+	promptingCtx, promptingCancel := context.WithCancel(context.Background())
+	prompter, promptingErrors, err := promptingsvc.Host(
+		promptingCtx, promptingsvc.NewPromptingClient(daemonConnection),
+		&cmd.StatusLinePrompter{Printer: statusLinePrinter}, false,
+	)
+	if err != nil {
+		promptingCancel()
+		return fmt.Errorf("unable to initiate prompting: %w", err)
+	}
+
+	// Perform the repair operation, cancel prompting, and handle errors.
+	synchronizationService := synchronizationsvc.NewSynchronizationClient(daemonConnection)
+	request := &synchronizationsvc.RepairRequest{
+		Prompter:  prompter,
+		Selection: selection,
+		Paths:     paths,
+	}
+	response, err := synchronizationService.Repair(context.Background(), request)
+	promptingCancel()
+	<-promptingErrors
+	if err != nil {
+		statusLinePrinter.BreakIfPopulated()
+		return grpcutil.PeelAwayRPCErrorLayer(err)
+	} else if err = response.EnsureValid(); err != nil {
+		statusLinePrinter.BreakIfPopulated()
+		return fmt.Errorf("invalid repair response received: %w", err)
+	}
+	statusLinePrinter.Clear()
+
+	// Print results.
+	var unrepairable bool
+	for _, result := range response.Results {
+		if len(result.Repaired) == 0 && len(result.Unrepairable) == 0 {
+			fmt.Printf("Session %s: No corruption found\n", result.Session)
+			continue
+		}
+		fmt.Printf("Session %s:\n", result.Session)
+		printDivergences("Restored to last synchronized state", result.Repaired)
+		printDivergences("Unable to repair (modified on both endpoints or by source)", result.Unrepairable)
+		if len(result.Unrepairable) > 0 {
+			unrepairable = true
+		}
+	}
+
+	// If any paths couldn't be repaired, then indicate failure.
+	if unrepairable {
+		return errors.New("some paths could not be repaired")
+	}
+
+	// Success.
+	return nil
+}
+
+// repairCommand is the repair command.
+var repairCommand = &cobra.Command{
+	Use:          "repair <session> [<path>...]",
+	Short:        "Re-hash endpoint contents and restore corrupted content",
+	RunE:         repairMain,
+	SilenceUsage: true,
+}
+
+// repairConfiguration stores configuration for the repair command.
+var repairConfiguration struct {
+	// help indicates whether or not to show help information and exit.
+	help bool
+}
+
+func init() {
+	// Grab a handle for the command line flags.
+	flags := repairCommand.Flags()
+
+	// Disable alphabetical sorting of flags in help output.
+	flags.SortFlags = false
+
+	// Manually add a help flag to override the default message. Cobra will
+	// still implement its logic automatically.
+	flags.BoolVarP(&repairConfiguration.help, "help", "h", false, "Show help information")
+}
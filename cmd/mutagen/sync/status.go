@@ -0,0 +1,105 @@
+package sync
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/spf13/cobra"
+
+	"github.com/dustin/go-humanize"
+
+	"github.com/mutagen-io/mutagen/cmd/mutagen/daemon"
+
+	"github.com/mutagen-io/mutagen/pkg/grpcutil"
+	"github.com/mutagen-io/mutagen/pkg/selection"
+	synchronizationsvc "github.com/mutagen-io/mutagen/pkg/service/synchronization"
+	"github.com/mutagen-io/mutagen/pkg/synchronization"
+)
+
+// statusMain is the entry point for the status command.
+func statusMain(_ *cobra.Command, arguments []string) error {
+	// Extract the session specification and path.
+	if len(arguments) != 2 {
+		return errors.New("session and path must be specified")
+	}
+	specification := arguments[0]
+	path, err := normalizeRepairPath(arguments[1])
+	if err != nil {
+		return fmt.Errorf("invalid path (%s): %w", arguments[1], err)
+	}
+
+	// Create session selection specification.
+	selection := &selection.Selection{
+		Specifications: []string{specification},
+	}
+	if err := selection.EnsureValid(); err != nil {
+		return fmt.Errorf("invalid session selection specification: %w", err)
+	}
+
+	// Connect to the daemon and defer closure of the connection.
+	daemonConnection, err := daemon.Connect(true, true)
+	if err != nil {
+		return fmt.Errorf("unable to connect to daemon: %w", err)
+	}
+	defer daemonConnection.Close()
+
+	// Perform the path status operation.
+	synchronizationService := synchronizationsvc.NewSynchronizationClient(daemonConnection)
+	request := &synchronizationsvc.PathStatusRequest{
+		Selection: selection,
+		Path:      path,
+	}
+	response, err := synchronizationService.PathStatus(context.Background(), request)
+	if err != nil {
+		return grpcutil.PeelAwayRPCErrorLayer(err)
+	} else if err = response.EnsureValid(); err != nil {
+		return fmt.Errorf("invalid path status response received: %w", err)
+	}
+
+	// Print results.
+	for _, result := range response.Results {
+		description := result.Status.Description()
+		switch result.Status {
+		case synchronization.PathStatus_PathStatusStaging:
+			if progress := result.StagingProgress; progress != nil && progress.ExpectedSize > 0 {
+				description += fmt.Sprintf(" (%s/%s)",
+					humanize.Bytes(progress.ReceivedSize),
+					humanize.Bytes(progress.ExpectedSize),
+				)
+			}
+		case synchronization.PathStatus_PathStatusProblematic:
+			description += ": " + result.Problem
+		}
+		fmt.Printf("Session %s: %s\n", result.Session, description)
+	}
+
+	// Success.
+	return nil
+}
+
+// statusCommand is the status command.
+var statusCommand = &cobra.Command{
+	Use:          "status <session> <path>",
+	Short:        "Show the synchronization status of a path",
+	RunE:         statusMain,
+	SilenceUsage: true,
+}
+
+// statusConfiguration stores configuration for the status command.
+var statusConfiguration struct {
+	// help indicates whether or not to show help information and exit.
+	help bool
+}
+
+func init() {
+	// Grab a handle for the command line flags.
+	flags := statusCommand.Flags()
+
+	// Disable alphabetical sorting of flags in help output.
+	flags.SortFlags = false
+
+	// Manually add a help flag to override the default message. Cobra will
+	// still implement its logic automatically.
+	flags.BoolVarP(&statusConfiguration.help, "help", "h", false, "Show help information")
+}
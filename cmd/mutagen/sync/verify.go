@@ -0,0 +1,136 @@
+package sync
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/spf13/cobra"
+
+	"github.com/mutagen-io/mutagen/cmd"
+	"github.com/mutagen-io/mutagen/cmd/mutagen/daemon"
+
+	"github.com/mutagen-io/mutagen/pkg/grpcutil"
+	"github.com/mutagen-io/mutagen/pkg/selection"
+	promptingsvc "github.com/mutagen-io/mutagen/pkg/service/prompting"
+	synchronizationsvc "github.com/mutagen-io/mutagen/pkg/service/synchronization"
+)
+
+// printDivergences prints a list of divergent paths under a heading, if any
+// are present.
+func printDivergences(heading string, paths []string) {
+	if len(paths) == 0 {
+		return
+	}
+	fmt.Printf("\t%s:\n", heading)
+	for _, path := range paths {
+		fmt.Printf("\t\t%s\n", formatPath(path))
+	}
+}
+
+// verifyMain is the entry point for the verify command.
+func verifyMain(_ *cobra.Command, arguments []string) error {
+	// Create session selection specification.
+	selection := &selection.Selection{
+		All:            verifyConfiguration.all,
+		Specifications: arguments,
+		LabelSelector:  verifyConfiguration.labelSelector,
+	}
+	if err := selection.EnsureValid(); err != nil {
+		return fmt.Errorf("invalid session selection specification: %w", err)
+	}
+
+	// Connect to the daemon and defer closure of the connection.
+	daemonConnection, err := daemon.Connect(true, true)
+	if err != nil {
+		return fmt.Errorf("unable to connect to daemon: %w", err)
+	}
+	defer daemonConnection.Close()
+
+	// Initiate command line messaging.
+	statusLinePrinter := &cmd.StatusLinePrinter{}
+	promptingCtx, promptingCancel := context.WithCancel(context.Background())
+	prompter, promptingErrors, err := promptingsvc.Host(
+		promptingCtx, promptingsvc.NewPromptingClient(daemonConnection),
+		&cmd.StatusLinePrompter{Printer: statusLinePrinter}, false,
+	)
+	if err != nil {
+		promptingCancel()
+		return fmt.Errorf("unable to initiate prompting: %w", err)
+	}
+
+	// Perform the verify operation, cancel prompting, and handle errors.
+	synchronizationService := synchronizationsvc.NewSynchronizationClient(daemonConnection)
+	request := &synchronizationsvc.VerifyRequest{
+		Prompter:  prompter,
+		Selection: selection,
+	}
+	response, err := synchronizationService.Verify(context.Background(), request)
+	promptingCancel()
+	<-promptingErrors
+	if err != nil {
+		statusLinePrinter.BreakIfPopulated()
+		return grpcutil.PeelAwayRPCErrorLayer(err)
+	} else if err = response.EnsureValid(); err != nil {
+		statusLinePrinter.BreakIfPopulated()
+		return fmt.Errorf("invalid verify response received: %w", err)
+	}
+	statusLinePrinter.Clear()
+
+	// Print results.
+	var divergent bool
+	for _, result := range response.Results {
+		if result.Consistent() {
+			fmt.Printf("Session %s: Consistent\n", result.Session)
+			continue
+		}
+		divergent = true
+		fmt.Printf("Session %s: Divergent\n", result.Session)
+		printDivergences("Alpha differs from last synchronized state", result.AlphaDivergences)
+		printDivergences("Beta differs from last synchronized state", result.BetaDivergences)
+		printDivergences("Alpha and beta differ", result.EndpointDivergences)
+	}
+
+	// If any divergence was found, then indicate failure.
+	if divergent {
+		return errors.New("divergence detected")
+	}
+
+	// Success.
+	return nil
+}
+
+// verifyCommand is the verify command.
+var verifyCommand = &cobra.Command{
+	Use:          "verify [<session>...]",
+	Short:        "Re-hash endpoint contents and report divergence",
+	RunE:         verifyMain,
+	SilenceUsage: true,
+}
+
+// verifyConfiguration stores configuration for the verify command.
+var verifyConfiguration struct {
+	// help indicates whether or not to show help information and exit.
+	help bool
+	// all indicates whether or not all sessions should be verified.
+	all bool
+	// labelSelector encodes a label selector to be used in identifying which
+	// sessions should be verified.
+	labelSelector string
+}
+
+func init() {
+	// Grab a handle for the command line flags.
+	flags := verifyCommand.Flags()
+
+	// Disable alphabetical sorting of flags in help output.
+	flags.SortFlags = false
+
+	// Manually add a help flag to override the default message. Cobra will
+	// still implement its logic automatically.
+	flags.BoolVarP(&verifyConfiguration.help, "help", "h", false, "Show help information")
+
+	// Wire up verify flags.
+	flags.BoolVarP(&verifyConfiguration.all, "all", "a", false, "Verify all sessions")
+	flags.StringVar(&verifyConfiguration.labelSelector, "label-selector", "", "Verify sessions matching the specified label selector")
+}
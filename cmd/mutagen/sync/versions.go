@@ -0,0 +1,150 @@
+package sync
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/dustin/go-humanize"
+
+	"github.com/mutagen-io/mutagen/cmd"
+	"github.com/mutagen-io/mutagen/cmd/mutagen/daemon"
+
+	"github.com/mutagen-io/mutagen/pkg/grpcutil"
+	"github.com/mutagen-io/mutagen/pkg/selection"
+	promptingsvc "github.com/mutagen-io/mutagen/pkg/service/prompting"
+	synchronizationsvc "github.com/mutagen-io/mutagen/pkg/service/synchronization"
+)
+
+// versionsMain is the entry point for the versions command.
+func versionsMain(_ *cobra.Command, arguments []string) error {
+	// Extract the session specification and path.
+	if len(arguments) != 2 {
+		return errors.New("session and path must be specified")
+	}
+	specification := arguments[0]
+	path, err := normalizeRepairPath(arguments[1])
+	if err != nil {
+		return fmt.Errorf("invalid path (%s): %w", arguments[1], err)
+	} else if path == "" {
+		return errors.New("versioning not supported for synchronization root")
+	}
+
+	// Create session selection specification.
+	selection := &selection.Selection{
+		Specifications: []string{specification},
+	}
+	if err := selection.EnsureValid(); err != nil {
+		return fmt.Errorf("invalid session selection specification: %w", err)
+	}
+
+	// Connect to the daemon and defer closure of the connection.
+	daemonConnection, err := daemon.Connect(true, true)
+	if err != nil {
+		return fmt.Errorf("unable to connect to daemon: %w", err)
+	}
+	defer daemonConnection.Close()
+
+	// Initiate command line messaging.
+	statusLinePrinter := &cmd.StatusLinePrinter{}
+	promptingCtx, promptingCancel := context.WithCancel(context.Background())
+	prompter, promptingErrors, err := promptingsvc.Host(
+		promptingCtx, promptingsvc.NewPromptingClient(daemonConnection),
+		&cmd.StatusLinePrompter{Printer: statusLinePrinter}, false,
+	)
+	if err != nil {
+		promptingCancel()
+		return fmt.Errorf("unable to initiate prompting: %w", err)
+	}
+
+	// Perform the versions operation, cancel prompting, and handle errors.
+	synchronizationService := synchronizationsvc.NewSynchronizationClient(daemonConnection)
+	request := &synchronizationsvc.VersionsRequest{
+		Prompter:  prompter,
+		Selection: selection,
+		Path:      path,
+		Restore:   versionsConfiguration.restore,
+		Alpha:     versionsConfiguration.alpha,
+	}
+	response, err := synchronizationService.Versions(context.Background(), request)
+	promptingCancel()
+	<-promptingErrors
+	if err != nil {
+		statusLinePrinter.BreakIfPopulated()
+		return grpcutil.PeelAwayRPCErrorLayer(err)
+	} else if err = response.EnsureValid(); err != nil {
+		statusLinePrinter.BreakIfPopulated()
+		return fmt.Errorf("invalid versions response received: %w", err)
+	}
+	statusLinePrinter.Clear()
+
+	// Print results.
+	var problem bool
+	for _, result := range response.Results {
+		if result.Problem != "" {
+			fmt.Printf("Session %s: %s\n", result.Session, result.Problem)
+			problem = true
+			continue
+		}
+		if result.Restored {
+			fmt.Printf("Session %s: Restored version %s\n", result.Session, versionsConfiguration.restore)
+		}
+		if len(result.Versions) == 0 {
+			fmt.Printf("Session %s: No versions available\n", result.Session)
+			continue
+		}
+		fmt.Printf("Session %s:\n", result.Session)
+		for _, version := range result.Versions {
+			fmt.Printf("\t%s\t%s\t%s\n",
+				version.Identifier,
+				version.CreationTime.AsTime().Local().Format(time.RFC3339),
+				humanize.Bytes(version.Size),
+			)
+		}
+	}
+
+	// If any problems were encountered, then indicate failure.
+	if problem {
+		return errors.New("unable to complete versions operation")
+	}
+
+	// Success.
+	return nil
+}
+
+// versionsCommand is the versions command.
+var versionsCommand = &cobra.Command{
+	Use:          "versions <session> <path>",
+	Short:        "List and restore retained versions of a file",
+	RunE:         versionsMain,
+	SilenceUsage: true,
+}
+
+// versionsConfiguration stores configuration for the versions command.
+var versionsConfiguration struct {
+	// help indicates whether or not to show help information and exit.
+	help bool
+	// restore specifies the identifier of a version to restore.
+	restore string
+	// alpha indicates that versions should be listed on alpha instead of beta.
+	alpha bool
+}
+
+func init() {
+	// Grab a handle for the command line flags.
+	flags := versionsCommand.Flags()
+
+	// Disable alphabetical sorting of flags in help output.
+	flags.SortFlags = false
+
+	// Manually add a help flag to override the default message. Cobra will
+	// still implement its logic automatically.
+	flags.BoolVarP(&versionsConfiguration.help, "help", "h", false, "Show help information")
+
+	// Wire up versions flags.
+	flags.StringVar(&versionsConfiguration.restore, "restore", "", "Restore the specified version")
+	flags.BoolVar(&versionsConfiguration.alpha, "alpha", false, "Operate on alpha instead of beta")
+}
@@ -87,6 +87,58 @@ func (p *StatusLinePrinter) BreakIfPopulated() {
 	}
 }
 
+// statusBlockCursorUpFormat is the format string used to return the cursor to
+// the beginning of a line a specified number of lines above the current line.
+const statusBlockCursorUpFormat = "\r\x1b[%dA"
+
+// StatusBlockPrinter provides printing facilities for dynamically updating
+// multi-line status displays in the console. Each line of the block is
+// formatted in the same manner as a StatusLinePrinter status line.
+type StatusBlockPrinter struct {
+	// Color, if non-nil, will be used for colorizing output (if possible).
+	Color *color.Color
+	// lines is the number of lines printed by the last call to Print.
+	lines int
+}
+
+// Print prints the specified lines as a block, overwriting any block previously
+// printed by the printer. Color escape sequences are supported. Lines will be
+// truncated to a platform-dependent maximum length and padded appropriately.
+// The cursor is left at the beginning of the line following the block.
+func (p *StatusBlockPrinter) Print(lines []string) {
+	// Use a color-supporting output stream to ensure that both color and
+	// cursor movement escape sequences are properly handled.
+	output := color.Output
+
+	// Return the cursor to the start of the previously printed block.
+	if p.lines > 0 {
+		fmt.Fprintf(output, statusBlockCursorUpFormat, p.lines)
+	}
+
+	// Print the lines.
+	for _, line := range lines {
+		if p.Color != nil {
+			p.Color.Fprintf(output, statusLineFormat, line)
+		} else {
+			fmt.Fprintf(output, statusLineFormat, line)
+		}
+		fmt.Fprintln(output)
+	}
+
+	// Clear out any remaining lines from the previous block and return the
+	// cursor to the line following the block.
+	for i := len(lines); i < p.lines; i++ {
+		fmt.Fprintf(output, statusLineFormat, "")
+		fmt.Fprintln(output)
+	}
+	if excess := p.lines - len(lines); excess > 0 {
+		fmt.Fprintf(output, statusBlockCursorUpFormat, excess)
+	}
+
+	// Record the number of lines printed.
+	p.lines = len(lines)
+}
+
 // StatusLinePrompter adapts a StatusLinePrinter to act as a Mutagen prompter.
 // The printer will be used to perform messaging and PromptCommandLine will be
 // used to perform prompting.
@@ -2,7 +2,11 @@ package agent
 
 import (
 	"archive/tar"
+	"bytes"
 	"compress/gzip"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
 	"errors"
 	"fmt"
 	"io"
@@ -18,8 +22,34 @@ import (
 const (
 	// BundleName is the base name of the agent bundle.
 	BundleName = "mutagen-agents.tar.gz"
+	// BundleManifestName is the name of the manifest entry within the agent
+	// bundle.
+	BundleManifestName = "manifest.json"
+	// DownloadURLEnvironmentVariable is the environment variable used to
+	// override the URL template from which agent executables that aren't
+	// included in the agent bundle are downloaded. It supports the same
+	// placeholders as UpgradeURLEnvironmentVariable.
+	DownloadURLEnvironmentVariable = "MUTAGEN_AGENT_DOWNLOAD_URL"
+
+	// maximumBundleManifestSize is the maximum allowed size for the agent
+	// bundle manifest.
+	maximumBundleManifestSize = 1024 * 1024
 )
 
+// BundleManifest describes the agent executables produced by a build, including
+// those that were excluded from the agent bundle to reduce its size.
+type BundleManifest struct {
+	// DownloadURL is the URL template from which agent executables that aren't
+	// included in the bundle can be downloaded. It supports the same
+	// placeholders as UpgradeURLEnvironmentVariable. It may be empty, in which
+	// case DownloadURLEnvironmentVariable must be set in order to download
+	// excluded executables.
+	DownloadURL string `json:"downloadURL,omitempty"`
+	// Digests maps platform names (of the form GOOS_GOARCH) to the hex-encoded
+	// SHA-256 digests of the corresponding agent executables.
+	Digests map[string]string `json:"digests"`
+}
+
 // BundleLocation encodes an expected location for the agent bundle.
 type BundleLocation uint8
 
@@ -49,7 +79,9 @@ var ExpectedBundleLocation BundleLocation
 // to only the user, will have an appropriate extension for the target platform,
 // and will have the executability bit set if it makes sense. The path to the
 // extracted file will be returned, and the caller is responsible for cleaning
-// up the file if this function returns a nil error.
+// up the file if this function returns a nil error. If the agent executable for
+// the platform was excluded from the bundle, but is listed in the bundle
+// manifest, then it will be downloaded (and cached) on demand.
 func ExecutableForPlatform(goos, goarch, outputPath string) (string, error) {
 	// Compute the path to the location in which we expect to find the agent
 	// bundle.
@@ -112,28 +144,56 @@ func ExecutableForPlatform(goos, goarch, outputPath string) (string, error) {
 	// Create an archive reader.
 	bundleArchive := tar.NewReader(bundleDecompressor)
 
-	// Scan until we find a matching header.
+	// Scan until we find a matching header, loading the manifest if we
+	// encounter it along the way.
 	var header *tar.Header
+	var manifest *BundleManifest
 	for {
 		if h, err := bundleArchive.Next(); err != nil {
 			if err == io.EOF {
 				break
 			}
 			return "", fmt.Errorf("unable to read archive header: %w", err)
+		} else if h.Name == BundleManifestName {
+			if h.Size > maximumBundleManifestSize {
+				return "", errors.New("agent bundle manifest too large")
+			}
+			manifest = &BundleManifest{}
+			if err := json.NewDecoder(bundleArchive).Decode(manifest); err != nil {
+				return "", fmt.Errorf("unable to decode agent bundle manifest: %w", err)
+			}
 		} else if h.Name == fmt.Sprintf("%s_%s", goos, goarch) {
 			header = h
 			break
 		}
 	}
 
-	// Check if we have a valid header. If not, there was no match.
-	if header == nil {
-		return "", errors.New("unsupported platform")
+	// If the bundle contains the executable, then extract it.
+	if header != nil {
+		return writeExecutable(bundleArchive, header.Size, goos, outputPath)
+	}
+
+	// Otherwise, if the manifest lists a digest for the platform, then the
+	// executable was excluded from the bundle and needs to be downloaded.
+	if manifest != nil {
+		if digest, ok := manifest.Digests[fmt.Sprintf("%s_%s", goos, goarch)]; ok {
+			return downloadExecutable(manifest, digest, goos, goarch, outputPath)
+		}
 	}
 
+	// Otherwise the platform isn't supported.
+	return "", errors.New("unsupported platform")
+}
+
+// writeExecutable writes an agent executable for the specified target
+// operating system with the specified content and size. If no output path is
+// specified, then the executable will be written to a temporary location. The
+// path to the executable is returned.
+func writeExecutable(content io.Reader, size int64, goos, outputPath string) (string, error) {
 	// If an output path has been specified, then open the path for writing,
 	// otherwise create a temporary file.
 	var file *os.File
+	var err error
 	if outputPath != "" {
 		file, err = os.OpenFile(outputPath, os.O_WRONLY|os.O_CREATE|os.O_TRUNC, 0600)
 	} else {
@@ -144,7 +204,7 @@ func ExecutableForPlatform(goos, goarch, outputPath string) (string, error) {
 	}
 
 	// Copy data into the file.
-	if _, err := io.CopyN(file, bundleArchive, header.Size); err != nil {
+	if _, err := io.CopyN(file, content, size); err != nil {
 		file.Close()
 		os.Remove(file.Name())
 		return "", fmt.Errorf("unable to copy agent data: %w", err)
@@ -173,3 +233,71 @@ func ExecutableForPlatform(goos, goarch, outputPath string) (string, error) {
 	// Success.
 	return file.Name(), nil
 }
+
+// fileHasDigest returns whether or not the file at the specified path exists
+// and has the specified SHA-256 digest.
+func fileHasDigest(path string, digest []byte) bool {
+	file, err := os.Open(path)
+	if err != nil {
+		return false
+	}
+	defer file.Close()
+	hasher := sha256.New()
+	if _, err := io.Copy(hasher, file); err != nil {
+		return false
+	}
+	return bytes.Equal(hasher.Sum(nil), digest)
+}
+
+// downloadExecutable retrieves an agent executable that was excluded from the
+// agent bundle and writes it using the same semantics as writeExecutable.
+// Downloaded executables are verified against the digest from the manifest and
+// cached in the Mutagen data directory.
+func downloadExecutable(manifest *BundleManifest, digest, goos, goarch, outputPath string) (string, error) {
+	// Decode the expected digest.
+	expected, err := hex.DecodeString(digest)
+	if err != nil || len(expected) != sha256.Size {
+		return "", errors.New("invalid digest in agent bundle manifest")
+	}
+
+	// Compute (and create) the cache directory and the cache path.
+	cacheDirectory, err := filesystem.Mutagen(true, filesystem.MutagenAgentsDirectoryName, mutagen.Version)
+	if err != nil {
+		return "", fmt.Errorf("unable to compute agent cache directory: %w", err)
+	}
+	cachePath := filepath.Join(cacheDirectory, fmt.Sprintf("%s_%s", goos, goarch))
+
+	// If there's no valid cached executable, then download one.
+	if !fileHasDigest(cachePath, expected) {
+		// Determine the URL template, giving precedence to the environment.
+		template := os.Getenv(DownloadURLEnvironmentVariable)
+		if template == "" {
+			template = manifest.DownloadURL
+		}
+		if template == "" {
+			return "", errors.New("agent not included in bundle and no download URL specified")
+		}
+
+		// Download the executable and move it into the cache.
+		temporary := filepath.Join(cacheDirectory, filesystem.TemporaryNamePrefix+fmt.Sprintf("%s_%s", goos, goarch))
+		os.Remove(temporary)
+		if err := download(expandURLTemplate(template, goos, goarch), temporary, expected); err != nil {
+			return "", fmt.Errorf("unable to download agent: %w", err)
+		} else if err = os.Rename(temporary, cachePath); err != nil {
+			os.Remove(temporary)
+			return "", fmt.Errorf("unable to cache downloaded agent: %w", err)
+		}
+	}
+
+	// Write the cached executable to the output location.
+	cached, err := os.Open(cachePath)
+	if err != nil {
+		return "", fmt.Errorf("unable to open cached agent: %w", err)
+	}
+	defer cached.Close()
+	metadata, err := cached.Stat()
+	if err != nil {
+		return "", fmt.Errorf("unable to query cached agent metadata: %w", err)
+	}
+	return writeExecutable(cached, metadata.Size(), goos, outputPath)
+}
@@ -1,6 +1,7 @@
 package agent
 
 import (
+	"crypto/sha256"
 	"os"
 	"path/filepath"
 	"runtime"
@@ -63,3 +64,26 @@ func TestExecutableForPlatformWithOutputPath(t *testing.T) {
 		t.Error("unable to remove agent executable:", err)
 	}
 }
+
+// TestFileHasDigest tests fileHasDigest.
+func TestFileHasDigest(t *testing.T) {
+	// Create a file with known content.
+	content := []byte("agent executable content")
+	path := filepath.Join(t.TempDir(), "agent")
+	if err := os.WriteFile(path, content, 0600); err != nil {
+		t.Fatal("unable to create test file:", err)
+	}
+	digest := sha256.Sum256(content)
+	incorrectDigest := sha256.Sum256([]byte("other content"))
+
+	// Test matching, mismatched, and missing cases.
+	if !fileHasDigest(path, digest[:]) {
+		t.Error("file does not match its digest")
+	}
+	if fileHasDigest(path, incorrectDigest[:]) {
+		t.Error("file matches incorrect digest")
+	}
+	if fileHasDigest(filepath.Join(filepath.Dir(path), "missing"), digest[:]) {
+		t.Error("missing file matches digest")
+	}
+}
@@ -3,12 +3,56 @@ package agent
 const (
 	// CommandInstall is the name of the agent installation command.
 	CommandInstall = "install"
+	// CommandUpgrade is the name of the agent in-place upgrade command.
+	CommandUpgrade = "upgrade"
 	// CommandForwarder is the name of the agent forwarder command.
 	CommandForwarder = "forwarder"
 	// CommandSynchronizer is the name of the agent synchronizer command.
 	CommandSynchronizer = "synchronizer"
+	// CommandMultiplexer is the name of the agent multiplexer command, which
+	// serves multiple endpoints over a single multiplexed connection.
+	CommandMultiplexer = "multiplexer"
+	// CommandListen is the name of the agent TCP listener command.
+	CommandListen = "listen"
+	// CommandRendezvous is the name of the agent command that dials out to a
+	// daemon's rendezvous listener.
+	CommandRendezvous = "rendezvous"
 
 	// FlagLogLevel is the flag for specifying the log level for the forwarder
 	// and synchronizer commands (without the preceding double-dash).
 	FlagLogLevel = "log-level"
+	// FlagDataDirectory is the flag for specifying the Mutagen data directory
+	// used by agent commands (without the preceding double-dash). If
+	// unspecified, the default data directory is used.
+	FlagDataDirectory = "data-directory"
+	// FlagUpgradeURL is the flag for specifying the base64url-encoded (without
+	// padding) download URL for the upgrade command (without the preceding
+	// double-dash). The URL is encoded so that it can be passed through remote
+	// shells without quoting.
+	FlagUpgradeURL = "url"
+	// FlagUpgradeDigest is the flag for specifying the hex-encoded SHA-256
+	// digest of the agent executable for the upgrade command (without the
+	// preceding double-dash).
+	FlagUpgradeDigest = "digest"
+	// FlagUpgradeVersion is the flag for specifying the expected version of the
+	// agent executable for the upgrade command (without the preceding
+	// double-dash).
+	FlagUpgradeVersion = "version"
+	// FlagListenAddress is the flag for specifying the TCP address on which
+	// the listen command should listen (without the preceding double-dash).
+	FlagListenAddress = "address"
+	// FlagListenKeyFile is the flag for specifying the path to the pre-shared
+	// key file for the listen command (without the preceding double-dash).
+	FlagListenKeyFile = "key-file"
+	// FlagRendezvousAddress is the flag for specifying the TCP address of the
+	// daemon's rendezvous listener for the rendezvous command (without the
+	// preceding double-dash).
+	FlagRendezvousAddress = "address"
+	// FlagRendezvousName is the flag for specifying the name under which the
+	// rendezvous command should register (without the preceding double-dash).
+	FlagRendezvousName = "name"
+	// FlagRendezvousKeyFile is the flag for specifying the path to the
+	// pre-shared key file for the rendezvous command (without the preceding
+	// double-dash).
+	FlagRendezvousKeyFile = "key-file"
 )
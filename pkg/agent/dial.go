@@ -27,45 +27,70 @@ const (
 	agentErrorInMemoryCutoff = 32 * 1024
 )
 
+// agentInvocationPath computes the path of an agent executable on the remote by
+// joining the specified components onto the Mutagen agents directory. If no
+// data directory is specified, then the path is relative to the user's home
+// directory on the remote and uses the default data directory name. Unless the
+// remote is known to be a cmd.exe environment, the path is constructed using
+// forward slashes.
+//
+// HACK: We're assuming that none of these path components have spaces in them,
+// but since we control (or validate) all of them, this is probably okay.
+func agentInvocationPath(dataDirectory string, cmdExe bool, components ...string) string {
+	pathSeparator := "/"
+	if cmdExe {
+		pathSeparator = "\\"
+		dataDirectory = strings.ReplaceAll(dataDirectory, "/", pathSeparator)
+	}
+	if dataDirectory == "" {
+		dataDirectory = filesystem.MutagenDataDirectoryName
+		if mutagen.DevelopmentModeEnabled {
+			dataDirectory = filesystem.MutagenDataDirectoryDevelopmentName
+		}
+	}
+	return strings.Join(append([]string{
+		dataDirectory,
+		filesystem.MutagenAgentsDirectoryName,
+	}, components...), pathSeparator)
+}
+
+// dataDirectoryFlag returns the command line flag (with a leading space) used
+// to specify the data directory for agent commands, or an empty string if no
+// data directory is specified.
+func dataDirectoryFlag(dataDirectory string) string {
+	if dataDirectory == "" {
+		return ""
+	}
+	return fmt.Sprintf(" --%s=%s", FlagDataDirectory, dataDirectory)
+}
+
 // connect connects to an agent-based endpoint using the specified transport,
-// connection mode, and prompter. It accepts a hint as to whether or not the
-// remote environment is cmd.exe-based and returns hints as to whether or not
-// installation should be attempted and whether or not the remote environment is
-// cmd.exe-based.
-func connect(logger *logging.Logger, transport Transport, mode, prompter string, cmdExe bool) (io.ReadWriteCloser, bool, bool, error) {
+// connection mode, prompter, and remote data directory. It accepts a hint as to
+// whether or not the remote environment is cmd.exe-based and returns hints as
+// to whether or not installation should be attempted and whether or not the
+// remote environment is cmd.exe-based.
+func connect(logger *logging.Logger, transport Transport, mode, prompter, dataDirectory string, cmdExe bool) (io.ReadWriteCloser, bool, bool, error) {
 	// Compute the agent invocation command, relative to the user's home
-	// directory on the remote. Unless we have reason to assume that this is a
-	// cmd.exe environment, we construct a path using forward slashes. This will
-	// work for all POSIX systems and POSIX-like environments on Windows. If we
-	// know we're hitting a cmd.exe environment, then we use backslashes,
-	// otherwise the invocation won't work. Watching for cmd.exe to fail on
-	// commands with forward slashes is actually the way that we detect cmd.exe
-	// environments.
-	//
-	// HACK: We're assuming that none of these path components have spaces in
-	// them, but since we control all of them, this is probably okay.
+	// directory on the remote (unless a data directory is specified). Unless we
+	// have reason to assume that this is a cmd.exe environment, we construct a
+	// path using forward slashes. This will work for all POSIX systems and
+	// POSIX-like environments on Windows. If we know we're hitting a cmd.exe
+	// environment, then we use backslashes, otherwise the invocation won't
+	// work. Watching for cmd.exe to fail on commands with forward slashes is
+	// actually the way that we detect cmd.exe environments.
 	//
 	// HACK: When invoking on Windows systems (whether inside a POSIX
 	// environment or cmd.exe), we can leave the "exe" suffix off the target
 	// name. Fortunately this allows us to also avoid having to try the
 	// combination of forward slashes + ".exe" for Windows POSIX environments.
-	pathSeparator := "/"
-	if cmdExe {
-		pathSeparator = "\\"
-	}
-	dataDirectoryName := filesystem.MutagenDataDirectoryName
-	if mutagen.DevelopmentModeEnabled {
-		dataDirectoryName = filesystem.MutagenDataDirectoryDevelopmentName
-	}
-	agentInvocationPath := strings.Join([]string{
-		dataDirectoryName,
-		filesystem.MutagenAgentsDirectoryName,
-		mutagen.Version,
-		BaseName,
-	}, pathSeparator)
+	agentPath := agentInvocationPath(dataDirectory, cmdExe, mutagen.Version, BaseName)
 
 	// Compute the command to invoke.
-	command := fmt.Sprintf("%s %s --%s=%s", agentInvocationPath, mode, FlagLogLevel, logger.Level())
+	command := fmt.Sprintf("%s %s --%s=%s%s",
+		agentPath, mode,
+		FlagLogLevel, logger.Level(),
+		dataDirectoryFlag(dataDirectory),
+	)
 
 	// Set up (but do not start) an agent process.
 	message := "Connecting to agent (POSIX)..."
@@ -175,21 +200,24 @@ func connect(logger *logging.Logger, transport Transport, mode, prompter string,
 }
 
 // Dial connects to an agent-based endpoint using the specified transport,
-// connection mode, and prompter.
-func Dial(logger *logging.Logger, transport Transport, mode, prompter string) (io.ReadWriteCloser, error) {
+// connection mode, and prompter. If a data directory is specified, then it's
+// used as the Mutagen data directory (and thus the agent installation location)
+// on the remote instead of the default data directory in the user's home
+// directory. It must be an absolute path.
+func Dial(logger *logging.Logger, transport Transport, mode, prompter, dataDirectory string) (io.ReadWriteCloser, error) {
 	// Validate that the mode is sane.
-	if !(mode == CommandSynchronizer || mode == CommandForwarder) {
+	if !(mode == CommandSynchronizer || mode == CommandForwarder || mode == CommandMultiplexer) {
 		return nil, errors.New("invalid agent dial mode")
 	}
 
 	// Attempt a connection. If this fails but we detect a Windows cmd.exe
 	// environment in the process, then re-attempt a connection under the
 	// cmd.exe assumption.
-	stream, tryInstall, cmdExe, err := connect(logger, transport, mode, prompter, false)
+	stream, tryInstall, cmdExe, err := connect(logger, transport, mode, prompter, dataDirectory, false)
 	if err == nil {
 		return stream, nil
 	} else if cmdExe {
-		stream, tryInstall, cmdExe, err = connect(logger, transport, mode, prompter, true)
+		stream, tryInstall, cmdExe, err = connect(logger, transport, mode, prompter, dataDirectory, true)
 		if err == nil {
 			return stream, nil
 		}
@@ -201,13 +229,18 @@ func Dial(logger *logging.Logger, transport Transport, mode, prompter string) (i
 		return nil, err
 	}
 
-	// Attempt to install.
-	if err := install(logger, transport, prompter); err != nil {
-		return nil, fmt.Errorf("unable to install agent: %w", err)
+	// Attempt an in-place upgrade of an existing agent installation, which
+	// avoids copying the agent executable over the transport. If that isn't
+	// possible, then fall back to a full installation.
+	if err := upgrade(logger, transport, prompter, dataDirectory); err != nil {
+		logger.Debug("Unable to perform in-place agent upgrade:", err)
+		if err := install(logger, transport, prompter, dataDirectory); err != nil {
+			return nil, fmt.Errorf("unable to install agent: %w", err)
+		}
 	}
 
 	// Re-attempt connectivity.
-	stream, _, _, err = connect(logger, transport, mode, prompter, cmdExe)
+	stream, _, _, err = connect(logger, transport, mode, prompter, dataDirectory, cmdExe)
 	if err != nil {
 		return nil, err
 	}
@@ -1,3 +1,37 @@
 package agent
 
-// TODO: Implement.
+import (
+	"testing"
+
+	"github.com/mutagen-io/mutagen/pkg/filesystem"
+	"github.com/mutagen-io/mutagen/pkg/mutagen"
+)
+
+// TestAgentInvocationPath tests agentInvocationPath.
+func TestAgentInvocationPath(t *testing.T) {
+	// Compute the expected default data directory name.
+	defaultDataDirectory := filesystem.MutagenDataDirectoryName
+	if mutagen.DevelopmentModeEnabled {
+		defaultDataDirectory = filesystem.MutagenDataDirectoryDevelopmentName
+	}
+
+	// Set up test cases.
+	testCases := []struct {
+		dataDirectory string
+		cmdExe        bool
+		expected      string
+	}{
+		{"", false, defaultDataDirectory + "/agents/" + BaseName},
+		{"", true, defaultDataDirectory + "\\agents\\" + BaseName},
+		{"/tmp/.mutagen", false, "/tmp/.mutagen/agents/" + BaseName},
+		{"C:/mutagen", true, "C:\\mutagen\\agents\\" + BaseName},
+		{`C:\mutagen`, true, "C:\\mutagen\\agents\\" + BaseName},
+	}
+
+	// Process test cases.
+	for i, testCase := range testCases {
+		if path := agentInvocationPath(testCase.dataDirectory, testCase.cmdExe, BaseName); path != testCase.expected {
+			t.Errorf("test index %d: path does not match expected: %s != %s", i, path, testCase.expected)
+		}
+	}
+}
@@ -4,6 +4,7 @@ import (
 	"fmt"
 	"os"
 	"runtime"
+	"strings"
 
 	"github.com/google/uuid"
 
@@ -32,13 +33,20 @@ func Install() error {
 		return fmt.Errorf("unable to relocate agent executable: %w", err)
 	}
 
+	// Record a copy of the agent for use in future in-place upgrades. Failure
+	// here isn't fatal, since it only means that the next upgrade will require
+	// a full installation.
+	updateUpgrader(destination)
+
 	// Success.
 	return nil
 }
 
 // install attempts to probe an endpoint and install the appropriate agent
-// binary over the specified transport.
-func install(logger *logging.Logger, transport Transport, prompter string) error {
+// binary over the specified transport. If a data directory is specified, then
+// the agent is copied to and installed within that directory rather than the
+// user's home directory.
+func install(logger *logging.Logger, transport Transport, prompter, dataDirectory string) error {
 	// Detect the target platform.
 	goos, goarch, posix, err := probe(transport, prompter)
 	if err != nil {
@@ -75,6 +83,23 @@ func install(logger *logging.Logger, transport Transport, prompter string) error
 	if posix {
 		destination = "." + destination
 	}
+
+	// If a data directory has been specified, then ensure that it exists and
+	// copy the agent into it using an absolute path.
+	if dataDirectory != "" {
+		var mkdirCommand string
+		if posix {
+			mkdirCommand = fmt.Sprintf("mkdir -p %s", dataDirectory)
+			destination = strings.TrimSuffix(dataDirectory, "/") + "/" + destination
+		} else {
+			dataDirectory = strings.ReplaceAll(dataDirectory, "/", "\\")
+			mkdirCommand = fmt.Sprintf("cmd.exe /c if not exist %s mkdir %s", dataDirectory, dataDirectory)
+			destination = strings.TrimSuffix(dataDirectory, "\\") + "\\" + destination
+		}
+		if err := run(transport, mkdirCommand); err != nil {
+			return fmt.Errorf("unable to create data directory: %w", err)
+		}
+	}
 	if err = transport.Copy(agentExecutable, destination); err != nil {
 		return fmt.Errorf("unable to copy agent binary: %w", err)
 	}
@@ -100,10 +125,10 @@ func install(logger *logging.Logger, transport Transport, prompter string) error
 		return fmt.Errorf("unable to message prompter: %w", err)
 	}
 	var installCommand string
-	if posix {
+	if posix && dataDirectory == "" {
 		installCommand = fmt.Sprintf("./%s %s", destination, CommandInstall)
 	} else {
-		installCommand = fmt.Sprintf("%s %s", destination, CommandInstall)
+		installCommand = fmt.Sprintf("%s %s%s", destination, CommandInstall, dataDirectoryFlag(dataDirectory))
 	}
 	if err := run(transport, installCommand); err != nil {
 		return fmt.Errorf("unable to invoke agent installation: %w", err)
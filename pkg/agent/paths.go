@@ -32,3 +32,20 @@ func installPath() (string, error) {
 	// Compute the installation path.
 	return filepath.Join(parent, executableName), nil
 }
+
+// upgraderPath computes and creates the parent directories of the path where a
+// version-independent copy of the most recently installed agent binary is kept.
+// This copy is used to perform in-place upgrades of the agent.
+func upgraderPath() (string, error) {
+	// Compute (and create) the path to the agents directory.
+	parent, err := filesystem.Mutagen(true, filesystem.MutagenAgentsDirectoryName)
+	if err != nil {
+		return "", fmt.Errorf("unable to compute agents directory: %w", err)
+	}
+
+	// Compute the target executable name.
+	executableName := process.ExecutableName(BaseName, runtime.GOOS)
+
+	// Compute the upgrader path.
+	return filepath.Join(parent, executableName), nil
+}
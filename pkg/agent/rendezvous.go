@@ -0,0 +1,412 @@
+package agent
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"sync"
+	"time"
+
+	"github.com/mutagen-io/mutagen/pkg/logging"
+	"github.com/mutagen-io/mutagen/pkg/url"
+)
+
+const (
+	// rendezvousMaximumPending is the maximum number of pending (unclaimed)
+	// connections that will be held for any single rendezvous name.
+	rendezvousMaximumPending = 4
+	// rendezvousClaim is the byte sent to a pending rendezvous connection to
+	// indicate that it has been claimed and that authentication will follow.
+	rendezvousClaim = 1
+	// rendezvousRegistrationLabel is the label used when computing
+	// registration authentication codes.
+	rendezvousRegistrationLabel = "mutagen-agent-rendezvous-registration"
+	// rendezvousNonceSize is the size of the nonces used in registrations.
+	rendezvousNonceSize = 32
+)
+
+// rendezvousAuthenticationCode computes a registration authentication code
+// using the specified key, nonce, and name.
+func rendezvousAuthenticationCode(key, nonce []byte, name string) []byte {
+	mac := hmac.New(sha256.New, key)
+	mac.Write([]byte(rendezvousRegistrationLabel))
+	mac.Write(nonce)
+	mac.Write([]byte(name))
+	return mac.Sum(nil)
+}
+
+// rendezvousRegistration is a received rendezvous registration.
+type rendezvousRegistration struct {
+	// name is the registered name.
+	name string
+	// nonce is the registration nonce.
+	nonce []byte
+	// code is the registration authentication code.
+	code []byte
+}
+
+// verify returns whether or not the registration was created using the
+// specified pre-shared key.
+func (r *rendezvousRegistration) verify(key []byte) bool {
+	return hmac.Equal(r.code, rendezvousAuthenticationCode(key, r.nonce, r.name))
+}
+
+// rendezvousRegister sends a rendezvous registration for the specified name,
+// authenticated using the specified pre-shared key. The registration consists
+// of a single length byte, the name, a random nonce, and an authentication code
+// covering the nonce and name.
+func rendezvousRegister(connection io.Writer, name string, key []byte) error {
+	// Generate a nonce.
+	nonce := make([]byte, rendezvousNonceSize)
+	if _, err := rand.Read(nonce); err != nil {
+		return fmt.Errorf("unable to generate registration nonce: %w", err)
+	}
+
+	// Send the registration.
+	message := make([]byte, 0, 1+len(name)+rendezvousNonceSize+sha256.Size)
+	message = append(message, byte(len(name)))
+	message = append(message, name...)
+	message = append(message, nonce...)
+	message = append(message, rendezvousAuthenticationCode(key, nonce, name)...)
+	if _, err := connection.Write(message); err != nil {
+		return fmt.Errorf("unable to send registration: %w", err)
+	}
+	return nil
+}
+
+// rendezvousReceiveRegistration receives and validates the format of a
+// rendezvous registration. The registration can't be authenticated until a
+// pre-shared key is known for its name.
+func rendezvousReceiveRegistration(connection io.Reader) (*rendezvousRegistration, error) {
+	var length [1]byte
+	if _, err := io.ReadFull(connection, length[:]); err != nil {
+		return nil, fmt.Errorf("unable to receive registration length: %w", err)
+	}
+	message := make([]byte, int(length[0])+rendezvousNonceSize+sha256.Size)
+	if _, err := io.ReadFull(connection, message); err != nil {
+		return nil, fmt.Errorf("unable to receive registration: %w", err)
+	}
+	registration := &rendezvousRegistration{
+		name:  string(message[:length[0]]),
+		nonce: message[length[0] : int(length[0])+rendezvousNonceSize],
+		code:  message[int(length[0])+rendezvousNonceSize:],
+	}
+	if !url.IsValidRendezvousName(registration.name) {
+		return nil, errors.New("invalid registration name")
+	}
+	return registration, nil
+}
+
+// RendezvousAwaitClaim registers an agent-initiated connection with a
+// rendezvous listener under the specified name, authenticating the
+// registration using the specified pre-shared key, and then blocks until the
+// connection is claimed by a session. Once this function returns successfully,
+// the connection should be treated as a newly accepted connection to an agent
+// TCP listener, i.e. TCPServerAuthenticate and the subsequent handshakes should
+// be performed.
+func RendezvousAwaitClaim(connection net.Conn, name string, key []byte) error {
+	// Validate the name.
+	if !url.IsValidRendezvousName(name) {
+		return fmt.Errorf("invalid rendezvous name: %s", name)
+	}
+
+	// Send the registration.
+	if err := connection.SetWriteDeadline(time.Now().Add(tcpAuthenticationTimeout)); err != nil {
+		return fmt.Errorf("unable to set registration deadline: %w", err)
+	} else if err = rendezvousRegister(connection, name, key); err != nil {
+		return err
+	} else if err = connection.SetWriteDeadline(time.Time{}); err != nil {
+		return fmt.Errorf("unable to clear registration deadline: %w", err)
+	}
+
+	// Wait for the connection to be claimed. There's no deadline here since
+	// connections may legitimately remain pending indefinitely.
+	var claim [1]byte
+	if _, err := io.ReadFull(connection, claim[:]); err != nil {
+		return fmt.Errorf("unable to receive claim: %w", err)
+	} else if claim[0] != rendezvousClaim {
+		return errors.New("invalid claim received")
+	}
+
+	// Success.
+	return nil
+}
+
+// rendezvousCandidate is a pending rendezvous connection.
+type rendezvousCandidate struct {
+	// connection is the pending connection.
+	connection net.Conn
+	// registration is the connection's registration.
+	registration *rendezvousRegistration
+	// verified indicates whether or not the registration has been verified
+	// against a known pre-shared key.
+	verified bool
+}
+
+// rendezvousClaimant represents a claim operation waiting for a connection.
+type rendezvousClaimant struct {
+	// key is the pre-shared key used by the claim operation.
+	key []byte
+}
+
+// Rendezvous manages connections dialed by agents that can't accept incoming
+// connections (e.g. because they're behind NAT or a firewall). Agents register
+// pending connections under a name, and sessions then claim those connections
+// by name. Registrations are authenticated using the pre-shared key, which the
+// rendezvous manager learns for each name from pending claim operations and
+// from successful claims. Registrations that can't be verified can't displace
+// other pending connections. Rendezvous is safe for concurrent usage.
+type Rendezvous struct {
+	// pendingLock serializes access to pending, keys, claimants, and arrival.
+	pendingLock sync.Mutex
+	// pending maps rendezvous names to pending connections, ordered from
+	// oldest to newest.
+	pending map[string][]*rendezvousCandidate
+	// keys maps rendezvous names to pre-shared keys that have been verified
+	// against registrations for those names.
+	keys map[string][]byte
+	// claimants maps rendezvous names to the set of claim operations currently
+	// in progress for those names.
+	claimants map[string]map[*rendezvousClaimant]bool
+	// arrival is closed (and replaced) whenever a new pending connection
+	// becomes available.
+	arrival chan struct{}
+}
+
+// NewRendezvous creates a new rendezvous manager.
+func NewRendezvous() *Rendezvous {
+	return &Rendezvous{
+		pending:   make(map[string][]*rendezvousCandidate),
+		keys:      make(map[string][]byte),
+		claimants: make(map[string]map[*rendezvousClaimant]bool),
+		arrival:   make(chan struct{}),
+	}
+}
+
+// Serve accepts and registers agent connections from the specified listener
+// until the listener fails or is closed, logging to the specified logger. It
+// always returns a non-nil error. Any pending connections are closed before
+// this method returns.
+func (r *Rendezvous) Serve(logger *logging.Logger, listener net.Listener) error {
+	// Ensure that pending connections are closed when we're done.
+	defer func() {
+		r.pendingLock.Lock()
+		for name, candidates := range r.pending {
+			for _, candidate := range candidates {
+				candidate.connection.Close()
+			}
+			delete(r.pending, name)
+		}
+		r.pendingLock.Unlock()
+	}()
+
+	// Accept connections and register them in the background.
+	for {
+		connection, err := listener.Accept()
+		if err != nil {
+			return err
+		}
+		go func() {
+			if err := r.register(logger, connection); err != nil {
+				logger.Debugf("Unable to register connection from %s: %v", connection.RemoteAddr(), err)
+				connection.Close()
+			}
+		}()
+	}
+}
+
+// register receives the registration for a connection and adds it to the set
+// of pending connections.
+func (r *Rendezvous) register(logger *logging.Logger, connection net.Conn) error {
+	// Receive the registration.
+	if err := connection.SetReadDeadline(time.Now().Add(tcpAuthenticationTimeout)); err != nil {
+		return fmt.Errorf("unable to set registration deadline: %w", err)
+	}
+	registration, err := rendezvousReceiveRegistration(connection)
+	if err != nil {
+		return err
+	}
+	if err := connection.SetReadDeadline(time.Time{}); err != nil {
+		return fmt.Errorf("unable to clear registration deadline: %w", err)
+	}
+	candidate := &rendezvousCandidate{connection: connection, registration: registration}
+	name := registration.name
+
+	// Lock the pending set and defer its release.
+	r.pendingLock.Lock()
+	defer r.pendingLock.Unlock()
+
+	// Attempt to verify the registration using the keys known for this name.
+	// Registrations that don't verify are still accepted (since the key may
+	// have changed), but they remain unverified.
+	if key, ok := r.keys[name]; ok && registration.verify(key) {
+		candidate.verified = true
+	} else {
+		for claimant := range r.claimants[name] {
+			if registration.verify(claimant.key) {
+				candidate.verified = true
+				break
+			}
+		}
+	}
+
+	// If the pending set for this name is full, then make room. Verified
+	// registrations may displace the oldest unverified connection (or, failing
+	// that, the oldest verified connection), but unverified registrations may
+	// not displace anything.
+	candidates := r.pending[name]
+	if len(candidates) >= rendezvousMaximumPending {
+		if !candidate.verified {
+			return errors.New("too many pending connections")
+		}
+		evict := 0
+		for i, c := range candidates {
+			if !c.verified {
+				evict = i
+				break
+			}
+		}
+		candidates[evict].connection.Close()
+		candidates = append(candidates[:evict:evict], candidates[evict+1:]...)
+	}
+
+	// Add the connection to the pending set and signal its arrival.
+	r.pending[name] = append(candidates, candidate)
+	close(r.arrival)
+	r.arrival = make(chan struct{})
+
+	// Log the registration.
+	logger.Debugf("Registered connection from %s as \"%s\" (verified: %t)",
+		connection.RemoteAddr(), name, candidate.verified,
+	)
+
+	// Success.
+	return nil
+}
+
+// take removes and returns the newest pending connection for the specified
+// name whose registration verifies against the specified key, as well as the
+// current arrival notification channel. If a registration verifies, then the
+// key is recorded for the name and any pending connections whose
+// registrations don't verify are closed and removed.
+func (r *Rendezvous) take(name string, key []byte) (net.Conn, <-chan struct{}) {
+	// Lock the pending set and defer its release.
+	r.pendingLock.Lock()
+	defer r.pendingLock.Unlock()
+
+	// Find the newest verifiable candidate.
+	candidates := r.pending[name]
+	index := -1
+	for i := len(candidates) - 1; i >= 0; i-- {
+		if candidates[i].registration.verify(key) {
+			index = i
+			break
+		}
+	}
+	if index == -1 {
+		return nil, r.arrival
+	}
+	connection := candidates[index].connection
+
+	// Record the key and filter the remaining candidates.
+	r.keys[name] = key
+	var remaining []*rendezvousCandidate
+	for i, candidate := range candidates {
+		if i == index {
+			continue
+		} else if candidate.registration.verify(key) {
+			candidate.verified = true
+			remaining = append(remaining, candidate)
+		} else {
+			candidate.connection.Close()
+		}
+	}
+	if len(remaining) == 0 {
+		delete(r.pending, name)
+	} else {
+		r.pending[name] = remaining
+	}
+
+	// Done.
+	return connection, r.arrival
+}
+
+// Claim claims a pending connection registered under the specified name,
+// waiting for one to arrive if necessary, and then performs authentication
+// using the specified pre-shared key, requests the specified agent mode, and
+// performs agent and version handshakes. Only connections whose registrations
+// verify against the pre-shared key are claimed, and if authentication or
+// handshaking fails on a claimed connection, then the next candidate is tried.
+// The resulting connection is encrypted and ready for use as an endpoint
+// stream.
+func (r *Rendezvous) Claim(ctx context.Context, name string, key []byte, mode string) (net.Conn, error) {
+	// Register the claim operation so that registrations arriving while it's
+	// in progress can be verified, and defer its removal.
+	claimant := &rendezvousClaimant{key: key}
+	r.pendingLock.Lock()
+	if r.claimants[name] == nil {
+		r.claimants[name] = make(map[*rendezvousClaimant]bool)
+	}
+	r.claimants[name][claimant] = true
+	r.pendingLock.Unlock()
+	defer func() {
+		r.pendingLock.Lock()
+		if delete(r.claimants[name], claimant); len(r.claimants[name]) == 0 {
+			delete(r.claimants, name)
+		}
+		r.pendingLock.Unlock()
+	}()
+
+	// Loop until a connection is successfully claimed or cancellation occurs.
+	var lastErr error
+	for {
+		// Grab the newest verifiable pending connection, if any, as well as
+		// the arrival notification channel.
+		connection, arrival := r.take(name, key)
+
+		// If there's no pending connection, then wait for one to arrive.
+		if connection == nil {
+			select {
+			case <-arrival:
+				continue
+			case <-ctx.Done():
+				if lastErr != nil {
+					return nil, fmt.Errorf("no agent connection available for \"%s\" (last error: %v): %w", name, lastErr, ctx.Err())
+				}
+				return nil, fmt.Errorf("no agent connection available for \"%s\": %w", name, ctx.Err())
+			}
+		}
+
+		// Send the claim. If this fails, then the connection has likely gone
+		// stale while pending, so we move on to the next candidate.
+		if err := connection.SetWriteDeadline(time.Now().Add(tcpAuthenticationTimeout)); err != nil {
+			connection.Close()
+			continue
+		} else if _, err = connection.Write([]byte{rendezvousClaim}); err != nil {
+			connection.Close()
+			continue
+		}
+
+		// Perform handshaking. If this fails (e.g. because the registration
+		// was replayed by a party without the key), then we move on to the
+		// next candidate.
+		stream, err := tcpClientHandshake(connection, key, mode)
+		if err != nil {
+			connection.Close()
+			lastErr = err
+			continue
+		}
+
+		// Success.
+		return stream, nil
+	}
+}
+
+// DefaultRendezvous is the rendezvous manager used by the daemon and by the
+// rendezvous protocol handlers.
+var DefaultRendezvous = NewRendezvous()
@@ -0,0 +1,258 @@
+package agent
+
+import (
+	"context"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/mutagen-io/mutagen/pkg/mutagen"
+)
+
+// startRendezvousAgent dials the specified rendezvous listener in the manner
+// of an agent in the background, performs server-side handshaking once
+// claimed, and reports the requested mode or any error.
+func startRendezvousAgent(address, name string, key []byte) (<-chan string, <-chan error) {
+	agentResults := make(chan string, 1)
+	agentErrors := make(chan error, 1)
+	go func() {
+		connection, err := net.Dial("tcp", address)
+		if err != nil {
+			agentErrors <- err
+			return
+		}
+		defer connection.Close()
+		if err := RendezvousAwaitClaim(connection, name, key); err != nil {
+			agentErrors <- err
+			return
+		}
+		stream, mode, err := TCPServerAuthenticate(connection, key)
+		if err != nil {
+			agentErrors <- err
+			return
+		} else if err = ServerHandshake(stream); err != nil {
+			agentErrors <- err
+			return
+		} else if err = mutagen.ServerVersionHandshake(stream); err != nil {
+			agentErrors <- err
+			return
+		}
+		agentResults <- mode
+	}()
+	return agentResults, agentErrors
+}
+
+// registerRendezvousConnection dials the specified rendezvous listener and
+// sends a registration for the specified name using the specified key, without
+// waiting for a claim.
+func registerRendezvousConnection(t *testing.T, address, name string, key []byte) net.Conn {
+	t.Helper()
+	connection, err := net.Dial("tcp", address)
+	if err != nil {
+		t.Fatal("unable to connect to rendezvous listener:", err)
+	}
+	t.Cleanup(func() { connection.Close() })
+	if err := rendezvousRegister(connection, name, key); err != nil {
+		t.Fatal("unable to register connection:", err)
+	}
+	return connection
+}
+
+// waitForRendezvous polls until the specified condition (evaluated with the
+// rendezvous lock held) is true or a timeout occurs.
+func waitForRendezvous(t *testing.T, rendezvous *Rendezvous, condition func() bool) {
+	t.Helper()
+	for deadline := time.Now().Add(10 * time.Second); time.Now().Before(deadline); {
+		rendezvous.pendingLock.Lock()
+		satisfied := condition()
+		rendezvous.pendingLock.Unlock()
+		if satisfied {
+			return
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+	t.Fatal("timed out waiting for rendezvous state")
+}
+
+// ensureClosed verifies that the remote end of a connection has been closed.
+func ensureClosed(t *testing.T, connection net.Conn) {
+	t.Helper()
+	connection.SetReadDeadline(time.Now().Add(10 * time.Second))
+	var buffer [1]byte
+	if _, err := connection.Read(buffer[:]); err == nil {
+		t.Error("connection received data instead of closure")
+	} else if netErr, ok := err.(net.Error); ok && netErr.Timeout() {
+		t.Error("connection not closed")
+	}
+}
+
+// TestRendezvous tests registration and claiming of rendezvous connections.
+func TestRendezvous(t *testing.T) {
+	// Create a rendezvous listener and defer its closure.
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal("unable to create listener:", err)
+	}
+	defer listener.Close()
+
+	// Serve rendezvous connections in the background.
+	rendezvous := NewRendezvous()
+	go rendezvous.Serve(nil, listener)
+
+	// Set up the pre-shared key.
+	key := []byte("0123456789abcdef")
+
+	// Start an agent.
+	agentResults, agentErrors := startRendezvousAgent(listener.Addr().String(), "agent", key)
+
+	// Claim the connection.
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	connection, err := rendezvous.Claim(ctx, "agent", key, CommandSynchronizer)
+	if err != nil {
+		t.Fatal("unable to claim connection:", err)
+	}
+	connection.Close()
+
+	// Verify the agent-side results.
+	select {
+	case mode := <-agentResults:
+		if mode != CommandSynchronizer {
+			t.Error("agent mode does not match expected:", mode, "!=", CommandSynchronizer)
+		}
+	case err := <-agentErrors:
+		t.Error("agent-side rendezvous failed:", err)
+	}
+
+	// Verify that claiming a name with no pending connections respects
+	// cancellation.
+	ctx, cancel = context.WithTimeout(context.Background(), 100*time.Millisecond)
+	defer cancel()
+	if _, err := rendezvous.Claim(ctx, "other", key, CommandSynchronizer); err == nil {
+		t.Error("claim succeeded without pending connection")
+	}
+}
+
+// TestRendezvousUnverifiedRegistrations tests that registrations created
+// without the pre-shared key can't displace legitimate connections and aren't
+// claimed.
+func TestRendezvousUnverifiedRegistrations(t *testing.T) {
+	// Create a rendezvous listener and defer its closure.
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal("unable to create listener:", err)
+	}
+	defer listener.Close()
+	address := listener.Addr().String()
+
+	// Serve rendezvous connections in the background.
+	rendezvous := NewRendezvous()
+	go rendezvous.Serve(nil, listener)
+
+	// Set up keys.
+	key := []byte("0123456789abcdef")
+	forgedKey := []byte("fedcba9876543210")
+
+	// Fill the pending set with forged registrations.
+	var forged []net.Conn
+	for i := 0; i < rendezvousMaximumPending; i++ {
+		forged = append(forged, registerRendezvousConnection(t, address, "agent", forgedKey))
+	}
+	waitForRendezvous(t, rendezvous, func() bool {
+		return len(rendezvous.pending["agent"]) == rendezvousMaximumPending
+	})
+
+	// Verify that an additional forged registration is rejected rather than
+	// evicting an existing connection.
+	ensureClosed(t, registerRendezvousConnection(t, address, "agent", forgedKey))
+
+	// Start a claim in the background and wait for it to begin waiting.
+	claimResults := make(chan error, 1)
+	go func() {
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+		defer cancel()
+		connection, err := rendezvous.Claim(ctx, "agent", key, CommandForwarder)
+		if err == nil {
+			connection.Close()
+		}
+		claimResults <- err
+	}()
+	waitForRendezvous(t, rendezvous, func() bool {
+		return len(rendezvous.claimants["agent"]) == 1
+	})
+
+	// Start a legitimate agent, which should be able to displace a forged
+	// registration and be claimed.
+	agentResults, agentErrors := startRendezvousAgent(address, "agent", key)
+	if err := <-claimResults; err != nil {
+		t.Fatal("unable to claim connection:", err)
+	}
+	select {
+	case mode := <-agentResults:
+		if mode != CommandForwarder {
+			t.Error("agent mode does not match expected:", mode, "!=", CommandForwarder)
+		}
+	case err := <-agentErrors:
+		t.Error("agent-side rendezvous failed:", err)
+	}
+
+	// Verify that the forged connections were closed once the key was
+	// verified.
+	for _, connection := range forged {
+		ensureClosed(t, connection)
+	}
+}
+
+// TestRendezvousClaimFallback tests that claiming moves on to the next
+// candidate if authentication fails on a claimed connection.
+func TestRendezvousClaimFallback(t *testing.T) {
+	// Create a rendezvous listener and defer its closure.
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal("unable to create listener:", err)
+	}
+	defer listener.Close()
+	address := listener.Addr().String()
+
+	// Serve rendezvous connections in the background.
+	rendezvous := NewRendezvous()
+	go rendezvous.Serve(nil, listener)
+
+	// Set up the pre-shared key.
+	key := []byte("0123456789abcdef")
+
+	// Start a legitimate agent and wait for it to register.
+	agentResults, agentErrors := startRendezvousAgent(address, "agent", key)
+	waitForRendezvous(t, rendezvous, func() bool {
+		return len(rendezvous.pending["agent"]) == 1
+	})
+
+	// Register a newer connection with a valid registration (as if replayed)
+	// that closes once claimed, and wait for it to register.
+	replayed := registerRendezvousConnection(t, address, "agent", key)
+	waitForRendezvous(t, rendezvous, func() bool {
+		return len(rendezvous.pending["agent"]) == 2
+	})
+	go func() {
+		var claim [1]byte
+		replayed.Read(claim[:])
+		replayed.Close()
+	}()
+
+	// Claim a connection, which should fall back to the legitimate agent.
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	connection, err := rendezvous.Claim(ctx, "agent", key, CommandSynchronizer)
+	if err != nil {
+		t.Fatal("unable to claim connection:", err)
+	}
+	connection.Close()
+	select {
+	case mode := <-agentResults:
+		if mode != CommandSynchronizer {
+			t.Error("agent mode does not match expected:", mode, "!=", CommandSynchronizer)
+		}
+	case err := <-agentErrors:
+		t.Error("agent-side rendezvous failed:", err)
+	}
+}
@@ -0,0 +1,233 @@
+package agent
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+	"sync"
+	"time"
+
+	"google.golang.org/protobuf/proto"
+
+	"github.com/mutagen-io/mutagen/pkg/logging"
+	"github.com/mutagen-io/mutagen/pkg/multiplexing"
+	"github.com/mutagen-io/mutagen/pkg/url"
+)
+
+const (
+	// sharedStreamOpenTimeout is the maximum amount of time allowed for opening
+	// a logical stream on a shared connection.
+	sharedStreamOpenTimeout = 30 * time.Second
+)
+
+// SendMode transmits the requested agent mode on a logical stream of a
+// multiplexed agent connection.
+func SendMode(stream io.Writer, mode string) error {
+	modeByte, ok := wireModes[mode]
+	if !ok {
+		return fmt.Errorf("unsupported agent mode: %s", mode)
+	} else if _, err := stream.Write([]byte{modeByte}); err != nil {
+		return fmt.Errorf("unable to send mode: %w", err)
+	}
+	return nil
+}
+
+// ReceiveMode receives the requested agent mode on a logical stream of a
+// multiplexed agent connection.
+func ReceiveMode(stream io.Reader) (string, error) {
+	var modeByte [1]byte
+	if _, err := io.ReadFull(stream, modeByte[:]); err != nil {
+		return "", fmt.Errorf("unable to receive mode: %w", err)
+	}
+	for mode, b := range wireModes {
+		if b == modeByte[0] {
+			return mode, nil
+		}
+	}
+	return "", errors.New("unknown mode requested")
+}
+
+// sharedConnection is a multiplexed agent connection that's shared between all
+// sessions targeting the same remote.
+type sharedConnection struct {
+	// references is the number of outstanding references to the connection.
+	// It's protected by sharedConnectionsLock.
+	references int
+	// dialLock serializes dialing operations for the connection and guards
+	// access to multiplexer.
+	dialLock sync.Mutex
+	// multiplexer is the multiplexer for the underlying agent connection. It
+	// may be nil if the connection hasn't been established yet.
+	multiplexer *multiplexing.Multiplexer
+}
+
+// sharedConnectionsLock serializes access to sharedConnections and to the
+// reference counts of the connections that it contains.
+var sharedConnectionsLock sync.Mutex
+
+// sharedConnections maps remote identities to shared connections.
+var sharedConnections = make(map[string]*sharedConnection)
+
+// isShareable determines whether or not agent connections for the specified
+// URL can be shared. Plugin transports and exec transports with command
+// templates receive the agent mode and endpoint path for each session (via
+// environment variables or template data, respectively), neither of which can
+// be honored by a shared connection, whose agent always runs in multiplexer
+// mode on behalf of all sessions.
+func isShareable(target *url.URL) bool {
+	switch target.Protocol {
+	case url.Protocol_Plugin:
+		return false
+	case url.Protocol_Exec:
+		return !strings.Contains(target.Host, "{{")
+	default:
+		return true
+	}
+}
+
+// sharedConnectionIdentity computes the identity used to determine whether or
+// not an agent connection can be shared for the specified URL and agent
+// niceness. It's based on all URL components that affect how the remote is
+// reached, excluding the kind and path, which vary between sessions using the
+// same remote. Since niceness applies to the entire agent process, sessions
+// requesting different niceness values can't share a connection.
+func sharedConnectionIdentity(target *url.URL, niceness uint32) (string, error) {
+	// Create a copy of the URL with session-specific components removed.
+	identity := &url.URL{
+		Protocol:    target.Protocol,
+		User:        target.User,
+		Host:        target.Host,
+		Port:        target.Port,
+		Environment: target.Environment,
+		Parameters:  target.Parameters,
+	}
+
+	// Encode the identity.
+	encoded, err := proto.MarshalOptions{Deterministic: true}.Marshal(identity)
+	if err != nil {
+		return "", fmt.Errorf("unable to encode URL: %w", err)
+	}
+	return fmt.Sprintf("%d:%s", niceness, encoded), nil
+}
+
+// sharedStream is a logical stream on a shared connection that releases its
+// reference to the connection when closed.
+type sharedStream struct {
+	// Stream is the underlying multiplexed stream.
+	*multiplexing.Stream
+	// release releases the stream's reference to the shared connection.
+	release func()
+	// releaseOnce ensures that release is only invoked once.
+	releaseOnce sync.Once
+}
+
+// Close implements io.Closer.Close.
+func (s *sharedStream) Close() error {
+	err := s.Stream.Close()
+	s.releaseOnce.Do(s.release)
+	return err
+}
+
+// open opens a logical stream for the specified agent mode, dialing the
+// underlying agent connection if necessary.
+func (c *sharedConnection) open(logger *logging.Logger, transport Transport, mode, prompter, dataDirectory string) (*multiplexing.Stream, error) {
+	// Lock the connection for dialing and defer its release.
+	c.dialLock.Lock()
+	defer c.dialLock.Unlock()
+
+	// If there's no existing connection (or it's failed), then dial a new one.
+	if c.multiplexer != nil {
+		select {
+		case <-c.multiplexer.Closed():
+			c.multiplexer = nil
+		default:
+		}
+	}
+	if c.multiplexer == nil {
+		stream, err := Dial(logger, transport, CommandMultiplexer, prompter, dataDirectory)
+		if err != nil {
+			return nil, err
+		}
+		c.multiplexer = multiplexing.Multiplex(multiplexing.NewCarrierFromStream(stream), false, nil)
+	}
+
+	// Open a logical stream.
+	ctx, cancel := context.WithTimeout(context.Background(), sharedStreamOpenTimeout)
+	defer cancel()
+	stream, err := c.multiplexer.OpenStream(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("unable to open stream on shared connection: %w", err)
+	}
+
+	// Request the mode.
+	if err := SendMode(stream, mode); err != nil {
+		stream.Close()
+		return nil, err
+	}
+
+	// Success.
+	return stream, nil
+}
+
+// DialShared connects to an agent-based endpoint in the same manner as Dial,
+// except that it multiplexes the endpoint over an agent connection that's
+// shared with all other endpoints targeting the same remote (as determined by
+// the specified URL) and requesting the same agent niceness (which should be
+// 0 if the endpoint doesn't adjust niceness). If no such connection exists,
+// then one is established using the specified transport, prompter, and data
+// directory. The shared connection is closed once all streams using it have
+// been closed. If the URL's transport can't support shared connections, then
+// DialShared falls back to a dedicated connection established with Dial.
+func DialShared(logger *logging.Logger, target *url.URL, transport Transport, mode string, niceness uint32, prompter, dataDirectory string) (io.ReadWriteCloser, error) {
+	// Validate that the mode is sane.
+	if !(mode == CommandSynchronizer || mode == CommandForwarder) {
+		return nil, errors.New("invalid agent dial mode")
+	}
+
+	// If the connection can't be shared, then use a dedicated connection.
+	if !isShareable(target) {
+		return Dial(logger, transport, mode, prompter, dataDirectory)
+	}
+
+	// Compute the remote identity.
+	identity, err := sharedConnectionIdentity(target, niceness)
+	if err != nil {
+		return nil, fmt.Errorf("unable to compute remote identity: %w", err)
+	}
+
+	// Grab (or create) the shared connection and register a reference to it.
+	sharedConnectionsLock.Lock()
+	connection, ok := sharedConnections[identity]
+	if !ok {
+		connection = &sharedConnection{}
+		sharedConnections[identity] = connection
+	}
+	connection.references++
+	sharedConnectionsLock.Unlock()
+
+	// Create a function to release the reference, closing the connection if
+	// it's no longer referenced. If the reference count has reached zero, then
+	// no other caller can be dialing, so it's safe to access the multiplexer.
+	release := func() {
+		sharedConnectionsLock.Lock()
+		defer sharedConnectionsLock.Unlock()
+		if connection.references--; connection.references == 0 {
+			delete(sharedConnections, identity)
+			if connection.multiplexer != nil {
+				connection.multiplexer.Close()
+			}
+		}
+	}
+
+	// Open a stream.
+	stream, err := connection.open(logger, transport, mode, prompter, dataDirectory)
+	if err != nil {
+		release()
+		return nil, err
+	}
+
+	// Success.
+	return &sharedStream{Stream: stream, release: release}, nil
+}
@@ -0,0 +1,132 @@
+package agent
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/mutagen-io/mutagen/pkg/url"
+)
+
+// TestModeTransmission tests SendMode and ReceiveMode.
+func TestModeTransmission(t *testing.T) {
+	// Set up test cases.
+	testCases := []struct {
+		mode        string
+		expectError bool
+	}{
+		{CommandSynchronizer, false},
+		{CommandForwarder, false},
+		{CommandMultiplexer, true},
+		{"unknown", true},
+	}
+
+	// Process test cases.
+	for i, testCase := range testCases {
+		buffer := &bytes.Buffer{}
+		if err := SendMode(buffer, testCase.mode); err != nil {
+			if !testCase.expectError {
+				t.Errorf("test index %d: unexpected error sending mode: %v", i, err)
+			}
+			continue
+		} else if testCase.expectError {
+			t.Errorf("test index %d: error expected", i)
+			continue
+		}
+		if mode, err := ReceiveMode(buffer); err != nil {
+			t.Errorf("test index %d: unable to receive mode: %v", i, err)
+		} else if mode != testCase.mode {
+			t.Errorf("test index %d: received mode does not match expected: %s != %s", i, mode, testCase.mode)
+		}
+	}
+}
+
+// TestIsShareable tests isShareable.
+func TestIsShareable(t *testing.T) {
+	// Set up test cases.
+	testCases := []struct {
+		target   *url.URL
+		expected bool
+	}{
+		{&url.URL{Protocol: url.Protocol_SSH, Host: "host", Path: "/path"}, true},
+		{&url.URL{Protocol: url.Protocol_Docker, Host: "container", Path: "/path"}, true},
+		{&url.URL{Protocol: url.Protocol_Exec, Host: "kubectl exec -i pod --", Path: "/path"}, true},
+		{&url.URL{Protocol: url.Protocol_Exec, Host: "wrapper {{.Path}} {{.AgentCommand}}", Path: "/path"}, false},
+		{&url.URL{Protocol: url.Protocol_Exec, Host: "wrapper {{.Mode}} {{.AgentCommand}}", Path: "/path"}, false},
+		{&url.URL{Protocol: url.Protocol_Plugin, Host: "example:target", Path: "/path"}, false},
+	}
+
+	// Process test cases.
+	for i, testCase := range testCases {
+		if shareable := isShareable(testCase.target); shareable != testCase.expected {
+			t.Errorf("test index %d: shareability does not match expected: %t != %t", i, shareable, testCase.expected)
+		}
+	}
+}
+
+// TestSharedConnectionIdentity tests sharedConnectionIdentity.
+func TestSharedConnectionIdentity(t *testing.T) {
+	// Set up test cases.
+	testCases := []struct {
+		first          *url.URL
+		firstNiceness  uint32
+		second         *url.URL
+		secondNiceness uint32
+		expected       bool
+	}{
+		{
+			&url.URL{Protocol: url.Protocol_SSH, User: "user", Host: "host", Path: "/first"}, 0,
+			&url.URL{Protocol: url.Protocol_SSH, User: "user", Host: "host", Path: "/second"}, 0,
+			true,
+		},
+		{
+			&url.URL{Protocol: url.Protocol_SSH, Host: "host", Path: "/path"}, 0,
+			&url.URL{Kind: url.Kind_Forwarding, Protocol: url.Protocol_SSH, Host: "host", Path: "tcp:localhost:8080"}, 0,
+			true,
+		},
+		{
+			&url.URL{Protocol: url.Protocol_SSH, Host: "host", Path: "/path"}, 0,
+			&url.URL{Protocol: url.Protocol_SSH, Host: "other", Path: "/path"}, 0,
+			false,
+		},
+		{
+			&url.URL{Protocol: url.Protocol_SSH, Host: "host", Port: 22, Path: "/path"}, 0,
+			&url.URL{Protocol: url.Protocol_SSH, Host: "host", Port: 2222, Path: "/path"}, 0,
+			false,
+		},
+		{
+			&url.URL{Protocol: url.Protocol_SSH, Host: "host", Path: "/first"}, 10,
+			&url.URL{Protocol: url.Protocol_SSH, Host: "host", Path: "/second"}, 10,
+			true,
+		},
+		{
+			&url.URL{Protocol: url.Protocol_SSH, Host: "host", Path: "/path"}, 10,
+			&url.URL{Kind: url.Kind_Forwarding, Protocol: url.Protocol_SSH, Host: "host", Path: "tcp:localhost:8080"}, 0,
+			false,
+		},
+		{
+			&url.URL{Protocol: url.Protocol_Docker, Host: "container", Path: "/path", Environment: map[string]string{"DOCKER_HOST": "a"}}, 0,
+			&url.URL{Protocol: url.Protocol_Docker, Host: "container", Path: "/path", Environment: map[string]string{"DOCKER_HOST": "b"}}, 0,
+			false,
+		},
+		{
+			&url.URL{Protocol: url.Protocol_Exec, Host: "kubectl exec -i pod --", Path: "/first"}, 0,
+			&url.URL{Protocol: url.Protocol_Exec, Host: "kubectl exec -i pod --", Path: "/second"}, 0,
+			true,
+		},
+	}
+
+	// Process test cases.
+	for i, testCase := range testCases {
+		first, err := sharedConnectionIdentity(testCase.first, testCase.firstNiceness)
+		if err != nil {
+			t.Fatalf("test index %d: unable to compute first identity: %v", i, err)
+		}
+		second, err := sharedConnectionIdentity(testCase.second, testCase.secondNiceness)
+		if err != nil {
+			t.Fatalf("test index %d: unable to compute second identity: %v", i, err)
+		}
+		if (first == second) != testCase.expected {
+			t.Errorf("test index %d: identity equality does not match expected: %t", i, testCase.expected)
+		}
+	}
+}
@@ -0,0 +1,264 @@
+package agent
+
+import (
+	"bytes"
+	"context"
+	"crypto/ed25519"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"errors"
+	"fmt"
+	"io"
+	"math/big"
+	"net"
+	"os"
+	"time"
+
+	"golang.org/x/crypto/hkdf"
+
+	"github.com/mutagen-io/mutagen/pkg/mutagen"
+)
+
+const (
+	// TCPKeyEnvironmentVariable is the environment variable that can be used
+	// to specify the pre-shared key for the listen command if no key file is
+	// specified.
+	TCPKeyEnvironmentVariable = "MUTAGEN_AGENT_KEY"
+	// tcpKeyMinimumLength is the minimum allowed length for pre-shared keys.
+	tcpKeyMinimumLength = 16
+	// tcpKeyFileMaximumSize is the maximum allowed size for pre-shared key
+	// files.
+	tcpKeyFileMaximumSize = 4096
+	// tcpAuthenticationTimeout is the maximum amount of time allowed for
+	// authentication and handshaking on TCP connections.
+	tcpAuthenticationTimeout = 10 * time.Second
+	// tcpIdentityLabel is the label used when deriving the TLS identity key
+	// from the pre-shared key.
+	tcpIdentityLabel = "mutagen-agent-tcp-identity"
+	// tcpCertificateCommonName is the common name used for TLS identity
+	// certificates. It's purely informational, since peers are authenticated
+	// by their public keys.
+	tcpCertificateCommonName = "mutagen-agent"
+)
+
+// wireModes maps agent modes to their wire representations, as used by TCP
+// and multiplexed connections.
+var wireModes = map[string]byte{
+	CommandSynchronizer: 1,
+	CommandForwarder:    2,
+}
+
+// ParseTCPKey validates and normalizes a pre-shared key, trimming any
+// surrounding whitespace.
+func ParseTCPKey(key []byte) ([]byte, error) {
+	key = bytes.TrimSpace(key)
+	if len(key) < tcpKeyMinimumLength {
+		return nil, fmt.Errorf("key too short (must be at least %d bytes)", tcpKeyMinimumLength)
+	}
+	return key, nil
+}
+
+// LoadTCPKey loads and validates a pre-shared key from the specified file.
+func LoadTCPKey(path string) ([]byte, error) {
+	// Open the file and defer its closure.
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("unable to open key file: %w", err)
+	}
+	defer file.Close()
+
+	// Read the key, watching for excessively large files.
+	key, err := io.ReadAll(io.LimitReader(file, tcpKeyFileMaximumSize+1))
+	if err != nil {
+		return nil, fmt.Errorf("unable to read key file: %w", err)
+	} else if len(key) > tcpKeyFileMaximumSize {
+		return nil, errors.New("key file too large")
+	}
+
+	// Validate the key.
+	return ParseTCPKey(key)
+}
+
+// tcpIdentity derives the TLS identity for TCP agent connections from the
+// specified pre-shared key. Both sides of a connection derive the same Ed25519
+// key pair (using HKDF-SHA256), present a self-signed certificate for it, and
+// require that the peer present a certificate for the same public key. Since
+// TLS requires that each peer prove possession of the corresponding private
+// key, this constitutes mutual authentication based on the pre-shared key.
+func tcpIdentity(key []byte) (tls.Certificate, ed25519.PublicKey, error) {
+	// Derive the key pair.
+	seed := make([]byte, ed25519.SeedSize)
+	if _, err := io.ReadFull(hkdf.New(sha256.New, key, nil, []byte(tcpIdentityLabel)), seed); err != nil {
+		return tls.Certificate{}, nil, fmt.Errorf("unable to derive identity key: %w", err)
+	}
+	privateKey := ed25519.NewKeyFromSeed(seed)
+	publicKey := privateKey.Public().(ed25519.PublicKey)
+
+	// Create a self-signed certificate. Its validity period is irrelevant
+	// because certificates are verified solely by their public keys.
+	template := &x509.Certificate{
+		SerialNumber: big.NewInt(1),
+		Subject:      pkix.Name{CommonName: tcpCertificateCommonName},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(24 * time.Hour),
+		KeyUsage:     x509.KeyUsageDigitalSignature,
+		ExtKeyUsage:  []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth, x509.ExtKeyUsageClientAuth},
+	}
+	certificate, err := x509.CreateCertificate(rand.Reader, template, template, publicKey, privateKey)
+	if err != nil {
+		return tls.Certificate{}, nil, fmt.Errorf("unable to create identity certificate: %w", err)
+	}
+
+	// Success.
+	return tls.Certificate{Certificate: [][]byte{certificate}, PrivateKey: privateKey}, publicKey, nil
+}
+
+// tcpTLSConfiguration creates the TLS configuration for TCP agent connections
+// using the specified pre-shared key. The same configuration is suitable for
+// use by both clients and servers. It requires TLS 1.3 and mutual
+// authentication using the identity derived by tcpIdentity.
+func tcpTLSConfiguration(key []byte) (*tls.Config, error) {
+	// Derive the identity.
+	certificate, publicKey, err := tcpIdentity(key)
+	if err != nil {
+		return nil, err
+	}
+
+	// Create the configuration. We disable standard certificate verification
+	// (which would require a certificate authority and server name) and
+	// instead verify that the peer's certificate matches our public key.
+	return &tls.Config{
+		MinVersion:         tls.VersionTLS13,
+		Certificates:       []tls.Certificate{certificate},
+		ClientAuth:         tls.RequireAnyClientCert,
+		InsecureSkipVerify: true,
+		VerifyPeerCertificate: func(certificates [][]byte, _ [][]*x509.Certificate) error {
+			if len(certificates) != 1 {
+				return errors.New("peer did not present exactly one certificate")
+			}
+			peer, err := x509.ParseCertificate(certificates[0])
+			if err != nil {
+				return fmt.Errorf("unable to parse peer certificate: %w", err)
+			}
+			if peerKey, ok := peer.PublicKey.(ed25519.PublicKey); !ok || !publicKey.Equal(peerKey) {
+				return errors.New("peer identity does not match pre-shared key")
+			}
+			return nil
+		},
+	}, nil
+}
+
+// tcpClientAuthenticate performs client-side mutual authentication on a TCP
+// connection using the specified pre-shared key and requests the specified
+// agent mode. On success, it returns a TLS connection wrapping the connection,
+// which should be used for all subsequent traffic.
+func tcpClientAuthenticate(connection net.Conn, key []byte, mode string) (net.Conn, error) {
+	// Create the TLS configuration.
+	configuration, err := tcpTLSConfiguration(key)
+	if err != nil {
+		return nil, fmt.Errorf("unable to create TLS configuration: %w", err)
+	}
+
+	// Perform the TLS handshake.
+	stream := tls.Client(connection, configuration)
+	if err := stream.Handshake(); err != nil {
+		return nil, fmt.Errorf("TLS handshake failed (key may be incorrect): %w", err)
+	}
+
+	// Request the mode.
+	if err := SendMode(stream, mode); err != nil {
+		return nil, err
+	}
+
+	// Success.
+	return stream, nil
+}
+
+// TCPServerAuthenticate performs server-side mutual authentication on a TCP
+// connection using the specified pre-shared key. On success, it returns a TLS
+// connection wrapping the connection, as well as the agent mode requested by
+// the client. The underlying connection should not be used directly after
+// successful authentication.
+func TCPServerAuthenticate(connection net.Conn, key []byte) (net.Conn, string, error) {
+	// Create the TLS configuration.
+	configuration, err := tcpTLSConfiguration(key)
+	if err != nil {
+		return nil, "", fmt.Errorf("unable to create TLS configuration: %w", err)
+	}
+
+	// Perform the TLS handshake.
+	stream := tls.Server(connection, configuration)
+	if err := stream.Handshake(); err != nil {
+		return nil, "", fmt.Errorf("TLS handshake failed: %w", err)
+	}
+
+	// Receive the requested mode.
+	mode, err := ReceiveMode(stream)
+	if err != nil {
+		return nil, "", err
+	}
+
+	// Success.
+	return stream, mode, nil
+}
+
+// tcpClientHandshake performs authentication using the specified pre-shared
+// key, requests the specified mode, and performs agent and version handshakes
+// on an established connection. On success, it returns the TLS connection
+// wrapping the connection. It does not close the connection on failure.
+func tcpClientHandshake(connection net.Conn, key []byte, mode string) (net.Conn, error) {
+	// Bound the time allowed for authentication and handshaking.
+	if err := connection.SetDeadline(time.Now().Add(tcpAuthenticationTimeout)); err != nil {
+		return nil, fmt.Errorf("unable to set handshake deadline: %w", err)
+	}
+
+	// Perform authentication.
+	stream, err := tcpClientAuthenticate(connection, key, mode)
+	if err != nil {
+		return nil, fmt.Errorf("authentication failed: %w", err)
+	}
+
+	// Perform an agent handshake.
+	if err := ClientHandshake(stream); err != nil {
+		return nil, fmt.Errorf("handshake failed: %w", err)
+	}
+
+	// Perform a version handshake.
+	if err := mutagen.ClientVersionHandshake(stream); err != nil {
+		return nil, fmt.Errorf("version handshake error: %w", err)
+	}
+
+	// Clear the deadline.
+	if err := connection.SetDeadline(time.Time{}); err != nil {
+		return nil, fmt.Errorf("unable to clear handshake deadline: %w", err)
+	}
+
+	// Success.
+	return stream, nil
+}
+
+// DialTCP connects to an agent listening on the specified TCP address,
+// performs authentication using the specified pre-shared key, requests the
+// specified mode, and performs agent and version handshakes. The resulting
+// connection is encrypted and ready for use as an endpoint stream.
+func DialTCP(ctx context.Context, address string, key []byte, mode string) (net.Conn, error) {
+	// Dial the agent.
+	dialer := &net.Dialer{}
+	connection, err := dialer.DialContext(ctx, "tcp", address)
+	if err != nil {
+		return nil, fmt.Errorf("unable to connect to agent: %w", err)
+	}
+
+	// Perform handshaking.
+	stream, err := tcpClientHandshake(connection, key, mode)
+	if err != nil {
+		connection.Close()
+		return nil, err
+	}
+
+	// Success.
+	return stream, nil
+}
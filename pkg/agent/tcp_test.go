@@ -0,0 +1,186 @@
+package agent
+
+import (
+	"crypto/tls"
+	"io"
+	"net"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+// TestLoadTCPKey tests LoadTCPKey.
+func TestLoadTCPKey(t *testing.T) {
+	// Set up test cases.
+	testCases := []struct {
+		content     string
+		expected    string
+		expectError bool
+	}{
+		{"", "", true},
+		{"short", "", true},
+		{"0123456789abcdef", "0123456789abcdef", false},
+		{"  0123456789abcdef\n", "0123456789abcdef", false},
+	}
+
+	// Process test cases.
+	directory := t.TempDir()
+	for i, testCase := range testCases {
+		path := filepath.Join(directory, "key")
+		if err := os.WriteFile(path, []byte(testCase.content), 0600); err != nil {
+			t.Fatalf("test index %d: unable to write key file: %v", i, err)
+		}
+		key, err := LoadTCPKey(path)
+		if err != nil && !testCase.expectError {
+			t.Errorf("test index %d: unexpected error: %v", i, err)
+		} else if err == nil && testCase.expectError {
+			t.Errorf("test index %d: error expected", i)
+		} else if err == nil && string(key) != testCase.expected {
+			t.Errorf("test index %d: key does not match expected: %q != %q", i, key, testCase.expected)
+		}
+	}
+}
+
+// tcpConnectionPair creates a pair of connected loopback TCP connections. We
+// can't use net.Pipe for testing TLS connections because it doesn't provide
+// buffering and TLS 1.3 peers may write concurrently during handshakes.
+func tcpConnectionPair(t *testing.T) (net.Conn, net.Conn) {
+	t.Helper()
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal("unable to create listener:", err)
+	}
+	defer listener.Close()
+	client, err := net.Dial("tcp", listener.Addr().String())
+	if err != nil {
+		t.Fatal("unable to dial listener:", err)
+	}
+	server, err := listener.Accept()
+	if err != nil {
+		client.Close()
+		t.Fatal("unable to accept connection:", err)
+	}
+	return client, server
+}
+
+// TestTCPAuthentication tests TCP authentication.
+func TestTCPAuthentication(t *testing.T) {
+	// Set up test cases.
+	testCases := []struct {
+		clientKey   string
+		serverKey   string
+		mode        string
+		expectError bool
+	}{
+		{"0123456789abcdef", "0123456789abcdef", CommandSynchronizer, false},
+		{"0123456789abcdef", "0123456789abcdef", CommandForwarder, false},
+		{"0123456789abcdef", "fedcba9876543210", CommandSynchronizer, true},
+	}
+
+	// Process test cases.
+	for i, testCase := range testCases {
+		// Create a connection pair.
+		client, server := tcpConnectionPair(t)
+
+		// Perform server authentication in the background. If authentication
+		// fails, then close the server connection so that the client observes
+		// the failure. Otherwise, echo a single message over the stream.
+		serverResults := make(chan string, 1)
+		go func() {
+			stream, mode, err := TCPServerAuthenticate(server, []byte(testCase.serverKey))
+			if err != nil {
+				server.Close()
+			} else {
+				message := make([]byte, 5)
+				if _, err := io.ReadFull(stream, message); err == nil {
+					stream.Write(message)
+				}
+			}
+			serverResults <- mode
+		}()
+
+		// Perform client authentication and verify the results.
+		stream, err := tcpClientAuthenticate(client, []byte(testCase.clientKey), testCase.mode)
+		if err != nil && !testCase.expectError {
+			t.Errorf("test index %d: unexpected error: %v", i, err)
+		} else if err == nil && testCase.expectError {
+			t.Errorf("test index %d: error expected", i)
+		}
+
+		// If authentication succeeded, then verify that TLS 1.3 was used and
+		// that the streams interoperate.
+		if err == nil {
+			if version := stream.(*tls.Conn).ConnectionState().Version; version != tls.VersionTLS13 {
+				t.Errorf("test index %d: unexpected TLS version: %x", i, version)
+			}
+			echo := make([]byte, 5)
+			if _, err := stream.Write([]byte("hello")); err != nil {
+				t.Errorf("test index %d: unable to write to stream: %v", i, err)
+			} else if _, err := io.ReadFull(stream, echo); err != nil {
+				t.Errorf("test index %d: unable to read from stream: %v", i, err)
+			} else if string(echo) != "hello" {
+				t.Errorf("test index %d: echo does not match expected: %q", i, echo)
+			}
+		}
+		if mode := <-serverResults; !testCase.expectError && mode != testCase.mode {
+			t.Errorf("test index %d: mode does not match expected: %s != %s", i, mode, testCase.mode)
+		}
+
+		// Close the connections.
+		client.Close()
+		server.Close()
+	}
+}
+
+// TestTCPIdentity tests that tcpIdentity derives identities deterministically
+// from pre-shared keys.
+func TestTCPIdentity(t *testing.T) {
+	// Derive identities.
+	_, first, err := tcpIdentity([]byte("0123456789abcdef"))
+	if err != nil {
+		t.Fatal("unable to derive first identity:", err)
+	}
+	_, second, err := tcpIdentity([]byte("0123456789abcdef"))
+	if err != nil {
+		t.Fatal("unable to derive second identity:", err)
+	}
+	_, other, err := tcpIdentity([]byte("fedcba9876543210"))
+	if err != nil {
+		t.Fatal("unable to derive other identity:", err)
+	}
+
+	// Verify identity equality.
+	if !first.Equal(second) {
+		t.Error("identities derived from the same key differ")
+	}
+	if first.Equal(other) {
+		t.Error("identities derived from different keys match")
+	}
+}
+
+// TestTCPServerAuthenticateRejectsAnonymousClient tests that
+// TCPServerAuthenticate rejects TLS clients that don't present a certificate.
+func TestTCPServerAuthenticateRejectsAnonymousClient(t *testing.T) {
+	// Create a connection pair and defer its closure.
+	client, server := tcpConnectionPair(t)
+	defer client.Close()
+	defer server.Close()
+
+	// Attempt to connect without presenting a certificate. If the handshake
+	// completes from the client's perspective, then attempt to request a mode.
+	go func() {
+		stream := tls.Client(client, &tls.Config{
+			MinVersion:         tls.VersionTLS13,
+			InsecureSkipVerify: true,
+		})
+		if stream.Handshake() == nil {
+			SendMode(stream, CommandSynchronizer)
+		}
+		client.Close()
+	}()
+
+	// Verify that authentication fails.
+	if _, _, err := TCPServerAuthenticate(server, []byte("0123456789abcdef")); err == nil {
+		t.Error("anonymous client authenticated successfully")
+	}
+}
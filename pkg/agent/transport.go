@@ -16,8 +16,9 @@ import (
 type Transport interface {
 	// Copy copies the specified local file (which is guaranteed to exist and be
 	// a file) to the remote. The provided local path will be absolute. The
-	// remote path will be a filename (i.e. without path separators) that should
-	// be treated as being relative to the user's home directory.
+	// remote path will either be a filename (i.e. without path separators) that
+	// should be treated as being relative to the user's home directory or an
+	// absolute path (if an agent data directory has been specified).
 	Copy(localPath, remoteName string) error
 	// Command creates (but does not start) a process that will invoke the
 	// specified command on the specified remote. It should not re-direct any of
@@ -1,2 +1,3 @@
-// Package docker provides the Docker transport implementation.
+// Package docker provides the Docker transport implementation, which is also
+// used for Docker-compatible command line interfaces such as nerdctl.
 package docker
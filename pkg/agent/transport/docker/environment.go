@@ -4,20 +4,19 @@ import (
 	"strings"
 
 	"github.com/mutagen-io/mutagen/pkg/environment"
-	"github.com/mutagen-io/mutagen/pkg/url"
 )
 
-// setDockerVariables updates a base environment specification by setting Docker
-// environment variables to match those from a Docker URL. Any known Docker
-// environment variables that aren't present in the URL's variables are filtered
-// from the environment.
-func setDockerVariables(base []string, variables map[string]string) []string {
+// setLockedVariables updates a base environment specification by setting the
+// specified environment variables to match those locked in to a URL. Any of the
+// specified environment variables that aren't present in the URL's variables
+// are filtered from the environment.
+func setLockedVariables(base, names []string, variables map[string]string) []string {
 	// Convert the base environment to a map for easier manipulation.
 	result := environment.ToMap(base)
 
-	// Populate Docker environment variables. If a given variable wasn't stored
-	// in the URL, then remove it from the environment.
-	for _, variable := range url.DockerEnvironmentVariables {
+	// Populate environment variables. If a given variable wasn't stored in the
+	// URL, then remove it from the environment.
+	for _, variable := range names {
 		if value, ok := variables[variable]; ok {
 			result[variable] = value
 		} else {
@@ -0,0 +1,24 @@
+package docker
+
+import (
+	"github.com/mutagen-io/mutagen/pkg/agent"
+	"github.com/mutagen-io/mutagen/pkg/nerdctl"
+	"github.com/mutagen-io/mutagen/pkg/url"
+)
+
+// NewNerdctlTransport creates a new transport that targets containers managed
+// by containerd using nerdctl. Since nerdctl's command line interface is
+// compatible with that of Docker, this transport shares its implementation with
+// the Docker transport. The containerd connection is controlled exclusively by
+// the environment variables locked in to the URL, so no parameters are
+// accepted.
+func NewNerdctlTransport(container, user string, environment map[string]string, prompter string) (agent.Transport, error) {
+	return &dockerTransport{
+		cli:                  nerdctl.Command,
+		environmentVariables: url.NerdctlEnvironmentVariables,
+		container:            container,
+		user:                 user,
+		environment:          environment,
+		prompter:             prompter,
+	}, nil
+}
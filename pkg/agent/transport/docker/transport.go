@@ -6,6 +6,7 @@ import (
 	"fmt"
 	"os"
 	"os/exec"
+	"strconv"
 	"strings"
 	"unicode/utf8"
 
@@ -15,6 +16,7 @@ import (
 	"github.com/mutagen-io/mutagen/pkg/docker"
 	"github.com/mutagen-io/mutagen/pkg/process"
 	"github.com/mutagen-io/mutagen/pkg/prompting"
+	"github.com/mutagen-io/mutagen/pkg/url"
 )
 
 // windowsContainerNotification is a prompt about copying files into Windows
@@ -26,14 +28,29 @@ Hyper-V doesn't support copying files into running containers.
 
 Would you like to continue? (yes/no)? `
 
-// dockerTransport implements the agent.Transport interface using Docker.
+// dockerTransport implements the agent.Transport interface using Docker or a
+// Docker-compatible command line interface (such as nerdctl).
 type dockerTransport struct {
+	// cli creates commands for the command line interface in use.
+	cli func(context.Context, ...string) (*exec.Cmd, error)
+	// environmentVariables are the names of the environment variables that are
+	// locked in to URLs for the command line interface in use.
+	environmentVariables []string
 	// container is the target container name.
 	container string
-	// user is the container user under which agents should be invoked.
+	// user is the container user under which agents should be invoked. It may
+	// be a username or UID, optionally followed by a group or GID.
 	user string
+	// workingDirectory is the directory inside the container to use as the
+	// working directory for agent commands. If empty, the user's home directory
+	// is used. It doesn't affect the location of agent binaries, which always
+	// reside within the user's home directory.
+	workingDirectory string
+	// privileged indicates whether or not agent commands should be executed
+	// with extended privileges.
+	privileged bool
 	// environment is the collection of environment variables that need to be
-	// set for the Docker executable.
+	// set for the command line interface executable.
 	environment map[string]string
 	// daemonConnectionFlags are the top-level flags used to control the daemon
 	// connection. They are reconstituted from URL parameters.
@@ -66,7 +83,19 @@ type dockerTransport struct {
 
 // NewTransport creates a new Docker transport using the specified parameters.
 func NewTransport(container, user string, environment, parameters map[string]string, prompter string) (agent.Transport, error) {
-	// Convert URL parameters to top-level daemon connection flags.
+	// Extract exec flags from URL parameters.
+	execFlags, parameters, err := docker.LoadExecFlagsFromURLParameters(parameters)
+	if err != nil {
+		return nil, fmt.Errorf("unable to compute Docker exec flags: %w", err)
+	} else if execFlags.User != "" {
+		if user != "" {
+			return nil, errors.New("user specified both in URL and as parameter")
+		}
+		user = execFlags.User
+	}
+
+	// Convert the remaining URL parameters to top-level daemon connection
+	// flags.
 	daemonConnectionFlags, err := docker.LoadDaemonConnectionFlagsFromURLParameters(parameters)
 	if err != nil {
 		return nil, fmt.Errorf("unable to compute Docker daemon connection flags: %w", err)
@@ -74,8 +103,12 @@ func NewTransport(container, user string, environment, parameters map[string]str
 
 	// Success.
 	return &dockerTransport{
+		cli:                   docker.Command,
+		environmentVariables:  url.DockerEnvironmentVariables,
 		container:             container,
 		user:                  user,
+		workingDirectory:      execFlags.Workdir,
+		privileged:            execFlags.Privileged,
 		environment:           environment,
 		daemonConnectionFlags: daemonConnectionFlags.ToFlags(),
 		prompter:              prompter,
@@ -95,6 +128,12 @@ func (t *dockerTransport) command(command, workingDirectory, user string) (*exec
 	// with standard input attached) fashion.
 	dockerArguments = append(dockerArguments, "exec", "--interactive")
 
+	// If requested, tell Docker to execute the command with extended
+	// privileges.
+	if t.privileged {
+		dockerArguments = append(dockerArguments, "--privileged")
+	}
+
 	// If specified, tell Docker which user should be used to execute commands
 	// inside the container.
 	if user != "" {
@@ -120,7 +159,7 @@ func (t *dockerTransport) command(command, workingDirectory, user string) (*exec
 	dockerArguments = append(dockerArguments, strings.Split(command, " ")...)
 
 	// Create the command.
-	dockerCommand, err := docker.Command(context.Background(), dockerArguments...)
+	dockerCommand, err := t.cli(context.Background(), dockerArguments...)
 	if err != nil {
 		return nil, err
 	}
@@ -131,8 +170,8 @@ func (t *dockerTransport) command(command, workingDirectory, user string) (*exec
 	// Create a copy of the current environment.
 	environment := os.Environ()
 
-	// Set Docker environment variables.
-	environment = setDockerVariables(environment, t.environment)
+	// Set locked-in environment variables.
+	environment = setLockedVariables(environment, t.environmentVariables, t.environment)
 
 	// Set SSH prompting environment variables. This is necessary to fully
 	// support Docker's SSH protocol, which shells out to OpenSSH and thus may
@@ -149,6 +188,23 @@ func (t *dockerTransport) command(command, workingDirectory, user string) (*exec
 	return dockerCommand, nil
 }
 
+// usernameMatches returns whether or not a probed POSIX username matches the
+// user specification provided to the transport, which may be empty (indicating
+// the default user) or take the form user[:group]. Numeric user IDs can't be
+// compared against the probed username, so they are assumed to match.
+func usernameMatches(probed, specified string) bool {
+	if specified == "" {
+		return true
+	}
+	if colon := strings.IndexByte(specified, ':'); colon != -1 {
+		specified = specified[:colon]
+	}
+	if _, err := strconv.ParseUint(specified, 10, 32); err == nil {
+		return true
+	}
+	return probed == specified
+}
+
 // probeContainer ensures that the containerIsWindows and containerHomeDirectory
 // fields are populated. It is idempotent. If probing previously failed, probing
 // will simply return an error indicating the previous failure.
@@ -250,7 +306,7 @@ func (t *dockerTransport) probeContainer() error {
 		} else if u := strings.TrimSpace(string(usernameBytes)); u == "" {
 			t.containerProbeError = errors.New("empty POSIX username")
 			return t.containerProbeError
-		} else if t.user != "" && u != t.user {
+		} else if !usernameMatches(u, t.user) {
 			t.containerProbeError = errors.New("probed POSIX username does not match specified")
 			return t.containerProbeError
 		} else {
@@ -299,7 +355,7 @@ func (t *dockerTransport) changeContainerStatus(stop bool) error {
 	}
 
 	// Create the command.
-	dockerCommand, err := docker.Command(context.Background(), dockerArguments...)
+	dockerCommand, err := t.cli(context.Background(), dockerArguments...)
 	if err != nil {
 		return fmt.Errorf("unable to set up Docker invocation: %w", err)
 	}
@@ -310,8 +366,8 @@ func (t *dockerTransport) changeContainerStatus(stop bool) error {
 	// Create a copy of the current environment.
 	environment := os.Environ()
 
-	// Set Docker environment variables.
-	environment = setDockerVariables(environment, t.environment)
+	// Set locked-in environment variables.
+	environment = setLockedVariables(environment, t.environmentVariables, t.environment)
 
 	// Set SSH prompting environment variables. This is necessary to fully
 	// support Docker's SSH protocol, which shells out to OpenSSH and thus may
@@ -361,9 +417,12 @@ func (t *dockerTransport) Copy(localPath, remoteName string) error {
 	// in their presence. The only case on Windows where \\ has special meaning
 	// is with UNC paths, an in that case they only occur at the beginning of a
 	// path, which they won't in this case since we've verified that the home
-	// directory is non-empty.
+	// directory is non-empty. If the remote name is already an absolute path,
+	// then we use it directly.
 	var containerPath string
-	if t.containerIsWindows {
+	if strings.ContainsAny(remoteName, "/\\") {
+		containerPath = fmt.Sprintf("%s:%s", t.container, remoteName)
+	} else if t.containerIsWindows {
 		containerPath = fmt.Sprintf("%s:%s\\%s",
 			t.container,
 			t.containerHomeDirectory,
@@ -385,7 +444,7 @@ func (t *dockerTransport) Copy(localPath, remoteName string) error {
 	dockerArguments = append(dockerArguments, "cp", localPath, containerPath)
 
 	// Create the command.
-	dockerCommand, err := docker.Command(context.Background(), dockerArguments...)
+	dockerCommand, err := t.cli(context.Background(), dockerArguments...)
 	if err != nil {
 		return fmt.Errorf("unable to set up Docker invocation: %w", err)
 	}
@@ -396,8 +455,8 @@ func (t *dockerTransport) Copy(localPath, remoteName string) error {
 	// Create a copy of the current environment.
 	environment := os.Environ()
 
-	// Set Docker environment variables.
-	environment = setDockerVariables(environment, t.environment)
+	// Set locked-in environment variables.
+	environment = setLockedVariables(environment, t.environmentVariables, t.environment)
 
 	// Set SSH prompting environment variables. This is necessary to fully
 	// support Docker's SSH protocol, which shells out to OpenSSH and thus may
@@ -470,8 +529,37 @@ func (t *dockerTransport) Command(command string) (*exec.Cmd, error) {
 		return nil, fmt.Errorf("unable to probe container: %w", err)
 	}
 
-	// Generate the command.
-	return t.command(command, t.containerHomeDirectory, "")
+	// If no working directory has been specified, then run the command in the
+	// home directory, where the agent package's relative paths resolve.
+	if t.workingDirectory == "" {
+		return t.command(command, t.containerHomeDirectory, "")
+	}
+
+	// Otherwise run the command in the specified working directory, resolving
+	// home-relative paths explicitly.
+	return t.command(t.resolveHomeRelativePaths(command), t.workingDirectory, "")
+}
+
+// resolveHomeRelativePaths converts the home-relative paths that the agent
+// package uses in commands (agent binaries in the data directory and agent
+// binaries copied for installation, which begin with either a dot or the agent
+// base name) to absolute paths within the container home directory.
+func (t *dockerTransport) resolveHomeRelativePaths(command string) string {
+	separator := "/"
+	if t.containerIsWindows {
+		separator = "\\"
+	}
+	arguments := strings.Split(command, " ")
+	for i, argument := range arguments {
+		if argument == "." || argument == ".." {
+			continue
+		} else if !strings.HasPrefix(argument, ".") && !strings.HasPrefix(argument, agent.BaseName) {
+			continue
+		}
+		argument = strings.TrimPrefix(argument, "."+separator)
+		arguments[i] = strings.TrimSuffix(t.containerHomeDirectory, separator) + separator + argument
+	}
+	return strings.Join(arguments, " ")
 }
 
 // ClassifyError implements the ClassifyError method of agent.Transport.
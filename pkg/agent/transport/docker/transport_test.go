@@ -1,3 +1,114 @@
 package docker
 
-// TODO: Implement.
+import (
+	"context"
+	"os/exec"
+	"strings"
+	"testing"
+)
+
+// TestUsernameMatches tests usernameMatches.
+func TestUsernameMatches(t *testing.T) {
+	// Set up test cases.
+	testCases := []struct {
+		probed    string
+		specified string
+		expected  bool
+	}{
+		{"root", "", true},
+		{"root", "root", true},
+		{"root", "user", false},
+		{"user", "user:group", true},
+		{"user", "other:group", false},
+		{"user", "1000", true},
+		{"user", "1000:1000", true},
+	}
+
+	// Process test cases.
+	for i, testCase := range testCases {
+		if result := usernameMatches(testCase.probed, testCase.specified); result != testCase.expected {
+			t.Errorf("test index %d: result does not match expected: %t != %t", i, result, testCase.expected)
+		}
+	}
+}
+
+// TestNewTransportUserConflict tests that NewTransport rejects a user specified
+// both in the URL and as a parameter.
+func TestNewTransportUserConflict(t *testing.T) {
+	if _, err := NewTransport("container", "user", nil, map[string]string{"user": "other"}, ""); err == nil {
+		t.Error("transport creation succeeded with conflicting user specifications")
+	}
+}
+
+// TestCommandWorkingDirectory tests that commands are executed in the
+// specified working directory while agent paths remain within the home
+// directory.
+func TestCommandWorkingDirectory(t *testing.T) {
+	// Set up test cases.
+	testCases := []struct {
+		windows          bool
+		home             string
+		workingDirectory string
+		command          string
+		expected         string
+	}{
+		{
+			false, "/home/user", "",
+			".mutagen/agents/1.0.0/mutagen-agent synchronizer",
+			"--workdir /home/user container .mutagen/agents/1.0.0/mutagen-agent synchronizer",
+		},
+		{
+			false, "/home/user", "/src",
+			".mutagen/agents/1.0.0/mutagen-agent synchronizer",
+			"--workdir /src container /home/user/.mutagen/agents/1.0.0/mutagen-agent synchronizer",
+		},
+		{
+			false, "/home/user/", "/src",
+			"./.mutagen-agent1234 install",
+			"--workdir /src container /home/user/.mutagen-agent1234 install",
+		},
+		{
+			false, "/home/user", "/src",
+			"chmod +x .mutagen-agent1234",
+			"--workdir /src container chmod +x /home/user/.mutagen-agent1234",
+		},
+		{
+			false, "/home/user", "/src",
+			"/data/agents/1.0.0/mutagen-agent synchronizer --data-directory=/data",
+			"--workdir /src container /data/agents/1.0.0/mutagen-agent synchronizer --data-directory=/data",
+		},
+		{
+			true, "C:\\Users\\user", "C:\\src",
+			"mutagen-agent1234.exe install",
+			"--workdir C:\\src container C:\\Users\\user\\mutagen-agent1234.exe install",
+		},
+		{
+			true, "C:\\Users\\user", "C:\\src",
+			".mutagen\\agents\\1.0.0\\mutagen-agent synchronizer",
+			"--workdir C:\\src container C:\\Users\\user\\.mutagen\\agents\\1.0.0\\mutagen-agent synchronizer",
+		},
+	}
+
+	// Process test cases.
+	for i, testCase := range testCases {
+		transport := &dockerTransport{
+			cli: func(_ context.Context, arguments ...string) (*exec.Cmd, error) {
+				return exec.Command("docker", arguments...), nil
+			},
+			container:              "container",
+			workingDirectory:       testCase.workingDirectory,
+			containerProbed:        true,
+			containerIsWindows:     testCase.windows,
+			containerHomeDirectory: testCase.home,
+		}
+		command, err := transport.Command(testCase.command)
+		if err != nil {
+			t.Fatalf("test index %d: unable to create command: %v", i, err)
+		}
+		arguments := strings.Join(command.Args[1:], " ")
+		expected := "exec --interactive " + testCase.expected
+		if arguments != expected {
+			t.Errorf("test index %d: arguments do not match expected: %s != %s", i, arguments, expected)
+		}
+	}
+}
@@ -0,0 +1,4 @@
+// Package exec provides an agent transport implementation that executes
+// commands on the remote using an arbitrary user-specified command prefix (e.g.
+// "kubectl exec -i pod --").
+package exec
@@ -0,0 +1,224 @@
+package exec
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"strings"
+	"text/template"
+
+	"github.com/mutagen-io/mutagen/pkg/agent"
+	"github.com/mutagen-io/mutagen/pkg/agent/transport"
+	"github.com/mutagen-io/mutagen/pkg/environment"
+	"github.com/mutagen-io/mutagen/pkg/process"
+	"github.com/mutagen-io/mutagen/pkg/url"
+)
+
+// agentCommandPlaceholder is the command template argument that, when used as
+// a standalone argument, expands to the lexed agent command arguments.
+const agentCommandPlaceholder = "{{.AgentCommand}}"
+
+// CommandData is the data available to command templates.
+type CommandData struct {
+	// AgentCommand is the command to be invoked on the remote. If the
+	// {{.AgentCommand}} placeholder is used as a standalone argument, then it
+	// expands to the individual arguments of the command, otherwise it expands
+	// to the space-separated command.
+	AgentCommand string
+	// Mode is the agent mode for which the transport is being used (i.e.
+	// synchronizer or forwarder).
+	Mode string
+	// Path is the synchronization root path or forwarding endpoint.
+	Path string
+}
+
+// execTransport implements the agent.Transport interface using a user-specified
+// command prefix or command template.
+type execTransport struct {
+	// command is the lexed command prefix or command template used to execute
+	// commands on the remote.
+	command []string
+	// templates are the parsed templates for the arguments in command. Entries
+	// are nil for arguments that don't contain template actions. If nil, then
+	// command is a command prefix to which commands are appended.
+	templates []*template.Template
+	// mode is the agent mode for which the transport is being used.
+	mode string
+	// path is the synchronization root path or forwarding endpoint.
+	path string
+	// copyCommand is the lexed copy command template used to copy files to the
+	// remote. If empty, copying isn't supported and the agent must already be
+	// installed on the remote.
+	copyCommand []string
+	// environment is the collection of environment variables that need to be
+	// set for transport commands, overriding those inherited from the current
+	// process.
+	environment map[string]string
+}
+
+// NewTransport creates a new exec transport using the specified command, copy
+// command template, and environment variables, as well as the agent mode and
+// the path for the endpoint. The command and copy command template are lexed by
+// splitting on whitespace. If the command contains template actions (which may
+// reference the fields of CommandData), then it's treated as a template for the
+// full command and must reference the agent command, otherwise it's treated as
+// a prefix to which the agent command is appended. The copy command template
+// may be empty, in which case the agent can't be installed automatically.
+func NewTransport(command, copyCommand string, environment map[string]string, mode, path string) (agent.Transport, error) {
+	// Lex and validate the command.
+	commandArguments := strings.Fields(command)
+	if len(commandArguments) == 0 {
+		return nil, errors.New("empty command")
+	}
+
+	// If the command contains template actions, then parse and validate the
+	// relevant arguments. We execute each template once with sample data to
+	// catch references to unknown fields.
+	var templates []*template.Template
+	if strings.Contains(command, "{{") {
+		if !strings.Contains(command, ".AgentCommand") {
+			return nil, errors.New("command template doesn't reference agent command")
+		}
+		templates = make([]*template.Template, len(commandArguments))
+		for i, argument := range commandArguments {
+			if argument == agentCommandPlaceholder || !strings.Contains(argument, "{{") {
+				continue
+			}
+			argumentTemplate, err := template.New("argument").Parse(argument)
+			if err != nil {
+				return nil, fmt.Errorf("unable to parse command template argument: %w", err)
+			} else if err = argumentTemplate.Execute(io.Discard, &CommandData{}); err != nil {
+				return nil, fmt.Errorf("invalid command template argument: %w", err)
+			}
+			templates[i] = argumentTemplate
+		}
+	}
+
+	// Lex and validate the copy command template, if any.
+	var copyArguments []string
+	if copyCommand != "" {
+		if !url.IsValidExecCopyCommand(copyCommand) {
+			return nil, errors.New("invalid copy command")
+		}
+		copyArguments = strings.Fields(copyCommand)
+	}
+
+	// Create the transport.
+	return &execTransport{
+		command:     commandArguments,
+		templates:   templates,
+		mode:        mode,
+		path:        path,
+		copyCommand: copyArguments,
+		environment: environment,
+	}, nil
+}
+
+// client creates a local command using the specified lexed command and
+// arguments.
+func (t *execTransport) client(command []string) *exec.Cmd {
+	// Create the command.
+	result := exec.Command(command[0], command[1:]...)
+
+	// Set the process attributes.
+	result.SysProcAttr = transport.ProcessAttributes()
+
+	// Set the environment for the command, if necessary.
+	if len(t.environment) > 0 {
+		variables := environment.ToMap(os.Environ())
+		for name, value := range t.environment {
+			variables[name] = value
+		}
+		result.Env = environment.FromMap(variables)
+	}
+
+	// Done.
+	return result
+}
+
+// Copy implements the Copy method of agent.Transport.
+func (t *execTransport) Copy(localPath, remoteName string) error {
+	// Ensure that a copy command has been specified.
+	if t.copyCommand == nil {
+		return fmt.Errorf("no copy command specified (set the %s parameter or pre-install the agent)", url.ExecCopyParameter)
+	}
+
+	// Substitute the placeholders. We do this after lexing the template so
+	// that paths containing whitespace are passed as single arguments.
+	replacer := strings.NewReplacer(
+		url.ExecCopyLocalPlaceholder, localPath,
+		url.ExecCopyRemotePlaceholder, remoteName,
+	)
+	arguments := make([]string, len(t.copyCommand))
+	for i, argument := range t.copyCommand {
+		arguments[i] = replacer.Replace(argument)
+	}
+
+	// Run the operation.
+	if output, err := t.client(arguments).CombinedOutput(); err != nil {
+		if len(output) > 0 {
+			return fmt.Errorf("unable to run copy command: %w (%s)", err, strings.TrimSpace(string(output)))
+		}
+		return fmt.Errorf("unable to run copy command: %w", err)
+	}
+
+	// Success.
+	return nil
+}
+
+// Command implements the Command method of agent.Transport.
+func (t *execTransport) Command(command string) (*exec.Cmd, error) {
+	// If the command isn't templated, then lex the command that we want to run
+	// and append it to the command prefix. All agent.Transport interfaces only
+	// need to support commands that can be lexed by splitting on spaces.
+	if t.templates == nil {
+		arguments := make([]string, 0, len(t.command)+strings.Count(command, " ")+1)
+		arguments = append(arguments, t.command...)
+		arguments = append(arguments, strings.Split(command, " ")...)
+		return t.client(arguments), nil
+	}
+
+	// Otherwise, expand the command template.
+	data := &CommandData{
+		AgentCommand: command,
+		Mode:         t.mode,
+		Path:         t.path,
+	}
+	arguments := make([]string, 0, len(t.command))
+	for i, argument := range t.command {
+		if argument == agentCommandPlaceholder {
+			arguments = append(arguments, strings.Split(command, " ")...)
+		} else if t.templates[i] != nil {
+			var expanded strings.Builder
+			if err := t.templates[i].Execute(&expanded, data); err != nil {
+				return nil, fmt.Errorf("unable to expand command template: %w", err)
+			}
+			arguments = append(arguments, expanded.String())
+		} else {
+			arguments = append(arguments, argument)
+		}
+	}
+
+	// Create the command.
+	return t.client(arguments), nil
+}
+
+// ClassifyError implements the ClassifyError method of agent.Transport.
+func (t *execTransport) ClassifyError(processState *os.ProcessState, errorOutput string) (bool, bool, error) {
+	// We don't know how the command prefix executes commands on the remote, so
+	// we rely on the conventional POSIX shell exit codes (which most execution
+	// mechanisms propagate) and fall back to Windows error output.
+	if process.IsPOSIXShellInvalidCommand(processState) ||
+		process.IsPOSIXShellCommandNotFound(processState) {
+		return true, false, nil
+	} else if process.OutputIsWindowsInvalidCommand(errorOutput) {
+		return false, true, nil
+	} else if process.OutputIsWindowsCommandNotFound(errorOutput) {
+		return true, true, nil
+	}
+
+	// Otherwise, we can't classify the error.
+	return false, false, errors.New("unknown process exit error")
+}
@@ -0,0 +1,255 @@
+package exec
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"testing"
+)
+
+// TestNewTransport tests NewTransport.
+func TestNewTransport(t *testing.T) {
+	// Set up test cases.
+	testCases := []struct {
+		command     string
+		copyCommand string
+		expectError bool
+	}{
+		{"", "", true},
+		{" ", "", true},
+		{"kubectl exec -i pod --", "", false},
+		{"kubectl exec -i pod --", "kubectl cp {local} pod:{remote}", false},
+		{"kubectl exec -i pod --", "kubectl cp {local} pod:/tmp", true},
+		{"kubectl exec -i pod -- {{.AgentCommand}}", "", false},
+		{"wrapper --mode={{.Mode}} -- {{.AgentCommand}}", "", false},
+		{"wrapper --mode={{.Mode}} --", "", true},
+		{"wrapper --mode={{.Unknown}} -- {{.AgentCommand}}", "", true},
+		{"wrapper {{ .Path }} {{.AgentCommand}}", "", true},
+		{"ssh {{.Host}} -- {{.AgentCommand}}", "", true},
+		{"wrapper --mode={{.Mode -- {{.AgentCommand}}", "", true},
+		{"wrapper --path={{.Path}}{{end}} -- {{.AgentCommand}}", "", true},
+		{"wrapper --path={{.Path.Missing}} -- {{.AgentCommand}}", "", true},
+	}
+
+	// Process test cases.
+	for i, testCase := range testCases {
+		_, err := NewTransport(testCase.command, testCase.copyCommand, nil, "synchronizer", "/var/www")
+		if err != nil && !testCase.expectError {
+			t.Errorf("test index %d: unexpected error: %v", i, err)
+		} else if err == nil && testCase.expectError {
+			t.Errorf("test index %d: error expected", i)
+		}
+	}
+}
+
+// TestCommand tests that commands are appended to the command prefix.
+func TestCommand(t *testing.T) {
+	// Create a transport.
+	transport, err := NewTransport("kubectl  exec -i pod --", "", nil, "synchronizer", "/var/www")
+	if err != nil {
+		t.Fatal("unable to create transport:", err)
+	}
+
+	// Create a command and verify its arguments.
+	command, err := transport.Command("mutagen-agent synchronizer")
+	if err != nil {
+		t.Fatal("unable to create command:", err)
+	}
+	expected := []string{"kubectl", "exec", "-i", "pod", "--", "mutagen-agent", "synchronizer"}
+	if len(command.Args) != len(expected) {
+		t.Fatal("argument count mismatch:", command.Args)
+	}
+	for i, argument := range expected {
+		if command.Args[i] != argument {
+			t.Errorf("argument %d mismatch: %s != %s", i, command.Args[i], argument)
+		}
+	}
+}
+
+// TestCopyWithoutCopyCommand tests that Copy fails without a copy command.
+func TestCopyWithoutCopyCommand(t *testing.T) {
+	// Create a transport.
+	transport, err := NewTransport("kubectl exec -i pod --", "", nil, "synchronizer", "/var/www")
+	if err != nil {
+		t.Fatal("unable to create transport:", err)
+	}
+
+	// Attempt a copy.
+	if transport.Copy("agent", "mutagen-agent") == nil {
+		t.Error("copy succeeded without copy command")
+	}
+}
+
+// TestCommandEnvironment tests that transport commands have the specified
+// environment variables set.
+func TestCommandEnvironment(t *testing.T) {
+	// Create a transport.
+	transport, err := NewTransport("kubectl exec -i pod --", "", map[string]string{
+		"KUBECONFIG": "/path/to/kubeconfig",
+	}, "synchronizer", "/var/www")
+	if err != nil {
+		t.Fatal("unable to create transport:", err)
+	}
+
+	// Create a command and verify its environment.
+	command, err := transport.Command("mutagen-agent synchronizer")
+	if err != nil {
+		t.Fatal("unable to create command:", err)
+	}
+	var found bool
+	for _, variable := range command.Env {
+		if variable == "KUBECONFIG=/path/to/kubeconfig" {
+			found = true
+		} else if strings.HasPrefix(variable, "KUBECONFIG=") {
+			t.Error("environment contains conflicting variable:", variable)
+		}
+	}
+	if !found {
+		t.Error("environment does not contain specified variable")
+	}
+}
+
+// TestCommandTemplate tests that command templates are expanded.
+func TestCommandTemplate(t *testing.T) {
+	// Set up test cases.
+	testCases := []struct {
+		command  string
+		expected []string
+	}{
+		{
+			"kubectl exec -i pod -- {{.AgentCommand}}",
+			[]string{"kubectl", "exec", "-i", "pod", "--", "mutagen-agent", "synchronizer"},
+		},
+		{
+			"wrapper --mode={{.Mode}} --path={{.Path}} {{.AgentCommand}} --wrapped",
+			[]string{"wrapper", "--mode=synchronizer", "--path=/var/www", "mutagen-agent", "synchronizer", "--wrapped"},
+		},
+		{
+			"sh -c exec_{{.AgentCommand}}",
+			[]string{"sh", "-c", "exec_mutagen-agent synchronizer"},
+		},
+		{
+			"wrapper {{.Mode}}:{{.Path}} {{.AgentCommand}}",
+			[]string{"wrapper", "synchronizer:/var/www", "mutagen-agent", "synchronizer"},
+		},
+	}
+
+	// Process test cases.
+	for i, testCase := range testCases {
+		transport, err := NewTransport(testCase.command, "", nil, "synchronizer", "/var/www")
+		if err != nil {
+			t.Errorf("test index %d: unable to create transport: %v", i, err)
+			continue
+		}
+		command, err := transport.Command("mutagen-agent synchronizer")
+		if err != nil {
+			t.Errorf("test index %d: unable to create command: %v", i, err)
+			continue
+		}
+		if len(command.Args) != len(testCase.expected) {
+			t.Errorf("test index %d: argument count mismatch: %v", i, command.Args)
+			continue
+		}
+		for a, argument := range testCase.expected {
+			if command.Args[a] != argument {
+				t.Errorf("test index %d: argument %d mismatch: %s != %s", i, a, command.Args[a], argument)
+			}
+		}
+	}
+}
+
+// TestCopy tests that copy command placeholders are substituted and that copy
+// command failures are reported.
+func TestCopy(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip()
+	}
+
+	// Create a source file in a directory whose path contains whitespace.
+	directory := filepath.Join(t.TempDir(), "source directory")
+	if err := os.Mkdir(directory, 0700); err != nil {
+		t.Fatal("unable to create source directory:", err)
+	}
+	source := filepath.Join(directory, "mutagen-agent")
+	if err := os.WriteFile(source, []byte("agent"), 0600); err != nil {
+		t.Fatal("unable to create source file:", err)
+	}
+
+	// Perform a copy and verify that the file was copied.
+	destination := filepath.Join(t.TempDir(), "destination")
+	transport, err := NewTransport("sh -c", "cp {local} {remote}", nil, "synchronizer", "/var/www")
+	if err != nil {
+		t.Fatal("unable to create transport:", err)
+	}
+	if err := transport.Copy(source, destination); err != nil {
+		t.Fatal("unable to copy:", err)
+	} else if contents, err := os.ReadFile(destination); err != nil {
+		t.Fatal("unable to read destination:", err)
+	} else if string(contents) != "agent" {
+		t.Error("destination contents do not match expected")
+	}
+
+	// Verify that copy failures are reported with their output.
+	if err := transport.Copy(filepath.Join(directory, "missing"), destination); err == nil {
+		t.Error("copy of missing file succeeded")
+	} else if !strings.Contains(err.Error(), "unable to run copy command") {
+		t.Error("error does not describe copy failure:", err)
+	} else if !strings.Contains(err.Error(), "missing") {
+		t.Error("error does not include copy command output:", err)
+	}
+}
+
+// TestClassifyError tests exec transport error classification.
+func TestClassifyError(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip()
+	}
+
+	// Set up test cases.
+	testCases := []struct {
+		// exitCode is the process exit code.
+		exitCode int
+		// errorOutput is the process error output.
+		errorOutput string
+		// expectedTryInstall is the expected installation recommendation.
+		expectedTryInstall bool
+		// expectedCmdExe is the expected cmd.exe detection result.
+		expectedCmdExe bool
+		// expectFailure indicates whether or not classification should fail.
+		expectFailure bool
+	}{
+		{126, "", true, false, false},
+		{127, "", true, false, false},
+		{1, "'mutagen-agent' is not recognized as an internal or external command,", false, true, false},
+		{1, "The system cannot find the path specified.", true, true, false},
+		{1, "error: unable to upgrade connection: container not found", false, false, true},
+		{137, "", false, false, true},
+	}
+
+	// Process test cases.
+	transport, err := NewTransport("kubectl exec -i pod --", "", nil, "synchronizer", "/var/www")
+	if err != nil {
+		t.Fatal("unable to create transport:", err)
+	}
+	for i, testCase := range testCases {
+		process := exec.Command("/bin/sh", "-c", fmt.Sprintf("exit %d", testCase.exitCode))
+		if err := process.Run(); err == nil {
+			t.Fatalf("test index %d: process succeeded unexpectedly", i)
+		}
+		tryInstall, cmdExe, err := transport.ClassifyError(process.ProcessState, testCase.errorOutput)
+		if err != nil {
+			if !testCase.expectFailure {
+				t.Errorf("test index %d: unexpected classification failure: %v", i, err)
+			}
+		} else if testCase.expectFailure {
+			t.Errorf("test index %d: classification succeeded unexpectedly", i)
+		} else if tryInstall != testCase.expectedTryInstall || cmdExe != testCase.expectedCmdExe {
+			t.Errorf("test index %d: classification does not match expected: (%t, %t) != (%t, %t)",
+				i, tryInstall, cmdExe, testCase.expectedTryInstall, testCase.expectedCmdExe,
+			)
+		}
+	}
+}
@@ -0,0 +1,3 @@
+// Package kubernetes provides the Kubernetes transport implementation, which
+// executes commands inside pod containers using the Kubernetes exec API.
+package kubernetes
@@ -0,0 +1,205 @@
+package kubernetes
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"strings"
+
+	"github.com/mutagen-io/mutagen/pkg/agent"
+	"github.com/mutagen-io/mutagen/pkg/agent/transport"
+	"github.com/mutagen-io/mutagen/pkg/environment"
+	"github.com/mutagen-io/mutagen/pkg/kubernetes"
+	"github.com/mutagen-io/mutagen/pkg/process"
+	"github.com/mutagen-io/mutagen/pkg/url"
+)
+
+const (
+	// commandScript is the shell script used to invoke commands inside the
+	// container. The exec API doesn't support specifying a working directory,
+	// so we use a shell to switch to the user's home directory (against which
+	// agent paths are resolved) before executing the command, which is passed
+	// as the script's positional arguments.
+	commandScript = `cd "${HOME:-/}" && exec "$@"`
+	// copyScript is the shell script used to copy files into the container.
+	// The file contents are streamed over standard input, which avoids any
+	// dependency on tar being present inside the container. The
+	// destination path is passed as the script's first positional argument and
+	// is resolved relative to the user's home directory.
+	copyScript = `cd "${HOME:-/}" && cat > "$1" && chmod 755 "$1"`
+)
+
+// kubernetesTransport implements the agent.Transport interface using the
+// Kubernetes exec API. Rather than running the client in-process, it invokes
+// the current (mutagen) executable's hidden Kubernetes exec helper command,
+// which allows it to provide the process-based semantics required by
+// agent.Transport. Only containers with a POSIX shell are supported.
+type kubernetesTransport struct {
+	// target is the target specification. If the pod is specified by
+	// deployment or label selector, then it's resolved by the helper on each
+	// invocation, so reconnections will target the current pod.
+	target *url.KubernetesTarget
+	// context is the kubeconfig context to use. If empty, then the current
+	// context is used.
+	context string
+	// environment is the collection of environment variables that need to be
+	// set for the Kubernetes client.
+	environment map[string]string
+}
+
+// NewTransport creates a new Kubernetes transport using the specified target
+// (of the form namespace/pod/container, see url.ParseKubernetesTarget),
+// environment variables, and URL parameters.
+func NewTransport(target string, environment, parameters map[string]string) (agent.Transport, error) {
+	// Parse the target.
+	parsed, err := url.ParseKubernetesTarget(target)
+	if err != nil {
+		return nil, fmt.Errorf("invalid target: %w", err)
+	}
+
+	// Create the transport.
+	return &kubernetesTransport{
+		target:      parsed,
+		context:     parameters[url.KubernetesContextParameter],
+		environment: environment,
+	}, nil
+}
+
+// setKubernetesVariables updates a base environment specification by setting
+// Kubernetes client environment variables to match those from a Kubernetes
+// URL. Any known Kubernetes client environment variables that aren't present
+// in the URL's variables are filtered from the environment.
+func setKubernetesVariables(base []string, variables map[string]string) []string {
+	// Convert the base environment to a map for easier manipulation.
+	result := environment.ToMap(base)
+
+	// Populate Kubernetes client environment variables. If a given variable wasn't
+	// stored in the URL, then remove it from the environment.
+	for _, variable := range url.KubernetesEnvironmentVariables {
+		if value, ok := variables[variable]; ok {
+			result[variable] = value
+		} else {
+			delete(result, variable)
+		}
+	}
+
+	// Done.
+	return environment.FromMap(result)
+}
+
+// helperArguments computes the Kubernetes exec helper arguments needed to run
+// the specified shell script inside the container with the specified positional
+// arguments.
+func (t *kubernetesTransport) helperArguments(script string, arguments ...string) []string {
+	// Set up the target flags.
+	result := []string{
+		kubernetes.HelperCommandName,
+		fmt.Sprintf("--%s=%s", kubernetes.HelperFlagNamespace, t.target.Namespace),
+	}
+	if t.target.Deployment != "" {
+		result = append(result, fmt.Sprintf("--%s=%s", kubernetes.HelperFlagDeployment, t.target.Deployment))
+	} else if t.target.Selector != "" {
+		result = append(result, fmt.Sprintf("--%s=%s", kubernetes.HelperFlagSelector, t.target.Selector))
+	} else {
+		result = append(result, fmt.Sprintf("--%s=%s", kubernetes.HelperFlagPod, t.target.Pod))
+	}
+	result = append(result, fmt.Sprintf("--%s=%s", kubernetes.HelperFlagContainer, t.target.Container))
+	if t.context != "" {
+		result = append(result, fmt.Sprintf("--%s=%s", kubernetes.HelperFlagContext, t.context))
+	}
+
+	// Add the shell invocation. The "sh" argument following the script sets
+	// the script's $0 value.
+	result = append(result, "--", "sh", "-c", script, "sh")
+	return append(result, arguments...)
+}
+
+// command creates a Kubernetes exec helper command that runs the specified
+// shell script inside the container with the specified positional arguments.
+func (t *kubernetesTransport) command(script string, arguments ...string) (*exec.Cmd, error) {
+	// Compute the path to the current (mutagen) executable.
+	mutagenPath, err := os.Executable()
+	if err != nil {
+		return nil, fmt.Errorf("unable to determine executable path: %w", err)
+	}
+
+	// Create the process.
+	helper := exec.Command(mutagenPath, t.helperArguments(script, arguments...)...)
+
+	// Force it to run detached.
+	helper.SysProcAttr = transport.ProcessAttributes()
+
+	// Set the environment for the command.
+	helper.Env = setKubernetesVariables(os.Environ(), t.environment)
+
+	// Done.
+	return helper, nil
+}
+
+// Copy implements the Copy method of agent.Transport.
+func (t *kubernetesTransport) Copy(localPath, remoteName string) error {
+	// Open the local file and defer its closure.
+	file, err := os.Open(localPath)
+	if err != nil {
+		return fmt.Errorf("unable to open local file: %w", err)
+	}
+	defer file.Close()
+
+	// Set up the copy command.
+	command, err := t.command(copyScript, remoteName)
+	if err != nil {
+		return fmt.Errorf("unable to set up copy invocation: %w", err)
+	}
+	command.Stdin = file
+
+	// Run the operation.
+	if output, err := command.CombinedOutput(); err != nil {
+		if len(output) > 0 {
+			return fmt.Errorf("unable to run copy process: %w (%s)", err, strings.TrimSpace(string(output)))
+		}
+		return fmt.Errorf("unable to run copy process: %w", err)
+	}
+
+	// Success.
+	return nil
+}
+
+// Command implements the Command method of agent.Transport.
+func (t *kubernetesTransport) Command(command string) (*exec.Cmd, error) {
+	// Lex the command that we want to run and pass it as the positional
+	// arguments for the invocation script. All agent.Transport interfaces only
+	// need to support commands that can be lexed by splitting on spaces.
+	return t.command(commandScript, strings.Split(command, " ")...)
+}
+
+// ClassifyError implements the ClassifyError method of agent.Transport.
+func (t *kubernetesTransport) ClassifyError(processState *os.ProcessState, errorOutput string) (bool, bool, error) {
+	// If the helper couldn't reach the container (or couldn't determine the
+	// command's exit status), then there's nothing that installation can fix.
+	// The helper's error output will be included by the caller.
+	if processState.ExitCode() == kubernetes.HelperFailureExitCode {
+		return false, false, errors.New("unable to execute command in container")
+	}
+
+	// If the container runtime couldn't find the shell used by our invocation
+	// script, then the container isn't supported. Runtimes report this using
+	// the same exit codes as a missing command, so we have to check the error
+	// output to distinguish it from a missing agent.
+	if strings.Contains(errorOutput, "executable file not found") {
+		return false, false, errors.New("container does not provide a POSIX shell")
+	}
+
+	// The exec API propagates the exit code of the remote process, and our
+	// invocation script executes commands using a POSIX shell, so we can rely
+	// on the conventional POSIX shell exit codes. Either indicates that the
+	// agent needs to be (re-)installed. Only POSIX containers are supported,
+	// so the remote is never a cmd.exe environment.
+	if process.IsPOSIXShellCommandNotFound(processState) ||
+		process.IsPOSIXShellInvalidCommand(processState) {
+		return true, false, nil
+	}
+
+	// Otherwise, we can't classify the error.
+	return false, false, errors.New("unknown process exit error")
+}
@@ -0,0 +1,314 @@
+package kubernetes
+
+import (
+	"errors"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"reflect"
+	"runtime"
+	"strings"
+	"testing"
+
+	"github.com/mutagen-io/mutagen/pkg/kubernetes"
+	"github.com/mutagen-io/mutagen/pkg/url"
+)
+
+// TestMain is the entry point for transport tests. Since the transport invokes
+// the current executable as its helper, it also acts as a stand-in for the
+// Kubernetes exec helper, executing the command following the "--" argument
+// locally and recording its arguments to the file specified by the
+// FAKE_KUBERNETES_LOG environment variable.
+func TestMain(m *testing.M) {
+	// Handle helper invocations.
+	if len(os.Args) > 1 && os.Args[1] == kubernetes.HelperCommandName {
+		os.WriteFile(os.Getenv("FAKE_KUBERNETES_LOG"), []byte(strings.Join(os.Args[1:], " ")), 0600)
+		arguments := os.Args[2:]
+		for len(arguments) > 0 && arguments[0] != "--" {
+			arguments = arguments[1:]
+		}
+		command := exec.Command(arguments[1], arguments[2:]...)
+		command.Stdin = os.Stdin
+		command.Stdout = os.Stdout
+		command.Stderr = os.Stderr
+		if err := command.Run(); err != nil {
+			var exitErr *exec.ExitError
+			if errors.As(err, &exitErr) {
+				os.Exit(exitErr.ExitCode())
+			}
+			os.Exit(kubernetes.HelperFailureExitCode)
+		}
+		os.Exit(0)
+	}
+
+	// Run tests.
+	os.Exit(m.Run())
+}
+
+// setupFakeHelper sets the home directory to a temporary directory for use
+// with the helper stand-in, returning the home directory and the path to the
+// argument log.
+func setupFakeHelper(t *testing.T) (string, string) {
+	t.Helper()
+	if runtime.GOOS == "windows" {
+		t.Skip()
+	}
+	home := t.TempDir()
+	log := filepath.Join(t.TempDir(), "log")
+	t.Setenv("HOME", home)
+	t.Setenv("FAKE_KUBERNETES_LOG", log)
+	return home, log
+}
+
+// TestNewTransportInvalidTarget tests that NewTransport rejects invalid
+// targets.
+func TestNewTransportInvalidTarget(t *testing.T) {
+	for i, target := range []string{"", "pod", "default/pod", "default/pod/container/extra"} {
+		if _, err := NewTransport(target, nil, nil); err == nil {
+			t.Errorf("test index %d: transport creation succeeded unexpectedly", i)
+		}
+	}
+}
+
+// TestCommand tests that commands are executed via the Kubernetes exec helper
+// in the user's home directory with the appropriate flags.
+func TestCommand(t *testing.T) {
+	home, log := setupFakeHelper(t)
+
+	// Create the transport.
+	transport, err := NewTransport("default/web-0/app", nil, map[string]string{
+		url.KubernetesContextParameter: "staging",
+	})
+	if err != nil {
+		t.Fatal("unable to create transport:", err)
+	}
+
+	// Run a command and verify that it runs in the home directory.
+	command, err := transport.Command("pwd")
+	if err != nil {
+		t.Fatal("unable to create command:", err)
+	}
+	output, err := command.Output()
+	if err != nil {
+		t.Fatal("unable to run command:", err)
+	}
+	resolvedHome, err := filepath.EvalSymlinks(home)
+	if err != nil {
+		t.Fatal("unable to resolve home directory:", err)
+	}
+	if directory := strings.TrimSpace(string(output)); directory != resolvedHome && directory != home {
+		t.Error("command run in unexpected directory:", directory)
+	}
+
+	// Verify the helper arguments.
+	arguments, err := os.ReadFile(log)
+	if err != nil {
+		t.Fatal("unable to read helper arguments:", err)
+	}
+	expected := kubernetes.HelperCommandName +
+		" --namespace=default --pod=web-0 --container=app --context=staging -- sh -c " +
+		commandScript + " sh pwd"
+	if actual := strings.TrimSpace(string(arguments)); actual != expected {
+		t.Error("helper arguments do not match expected:", actual, "!=", expected)
+	}
+}
+
+// TestHelperArguments tests that pod specifications are translated to the
+// appropriate helper flags.
+func TestHelperArguments(t *testing.T) {
+	// Set up test cases.
+	testCases := []struct {
+		// target is the URL target.
+		target string
+		// expectedFlags are the expected target flags.
+		expectedFlags []string
+	}{
+		{"default/web-0/app", []string{"--namespace=default", "--pod=web-0", "--container=app"}},
+		{"default/deployment:web/app", []string{"--namespace=default", "--deployment=web", "--container=app"}},
+		{
+			"default/selector:app.kubernetes.io%2Fname=web,tier!=db/app",
+			[]string{"--namespace=default", "--selector=app.kubernetes.io/name=web,tier!=db", "--container=app"},
+		},
+	}
+
+	// Process test cases.
+	for i, testCase := range testCases {
+		transport, err := NewTransport(testCase.target, nil, nil)
+		if err != nil {
+			t.Fatalf("test index %d: unable to create transport: %v", i, err)
+		}
+		arguments := transport.(*kubernetesTransport).helperArguments(commandScript, "true")
+		expected := append([]string{kubernetes.HelperCommandName}, testCase.expectedFlags...)
+		expected = append(expected, "--", "sh", "-c", commandScript, "sh", "true")
+		if !reflect.DeepEqual(arguments, expected) {
+			t.Errorf("test index %d: helper arguments do not match expected: %v != %v", i, arguments, expected)
+		}
+	}
+}
+
+// TestCopy tests that files are copied into the user's home directory with
+// executable permissions.
+func TestCopy(t *testing.T) {
+	home, _ := setupFakeHelper(t)
+
+	// Create the transport.
+	transport, err := NewTransport("default/web-0/app", nil, nil)
+	if err != nil {
+		t.Fatal("unable to create transport:", err)
+	}
+
+	// Create a file to copy.
+	source := filepath.Join(t.TempDir(), "agent")
+	contents := []byte("agent contents")
+	if err := os.WriteFile(source, contents, 0600); err != nil {
+		t.Fatal("unable to create source file:", err)
+	}
+
+	// Perform the copy and verify the result.
+	if err := transport.Copy(source, ".mutagen-agent"); err != nil {
+		t.Fatal("unable to copy file:", err)
+	}
+	destination := filepath.Join(home, ".mutagen-agent")
+	if copied, err := os.ReadFile(destination); err != nil {
+		t.Fatal("unable to read copied file:", err)
+	} else if !reflect.DeepEqual(copied, contents) {
+		t.Error("copied file contents do not match expected")
+	}
+	if info, err := os.Stat(destination); err != nil {
+		t.Fatal("unable to query copied file:", err)
+	} else if info.Mode()&0111 == 0 {
+		t.Error("copied file is not executable")
+	}
+
+	// Verify that copying to an invalid location fails.
+	if err := transport.Copy(source, "nonexistent/agent"); err == nil {
+		t.Error("copy to invalid location succeeded unexpectedly")
+	}
+}
+
+// TestClassifyError tests that missing agents are classified as requiring
+// installation and that helper and shell failures aren't.
+func TestClassifyError(t *testing.T) {
+	home, _ := setupFakeHelper(t)
+
+	// Create the transport.
+	transport, err := NewTransport("default/web-0/app", nil, nil)
+	if err != nil {
+		t.Fatal("unable to create transport:", err)
+	}
+
+	// Attempt to run a nonexistent agent and classify the error.
+	command, err := transport.Command(".mutagen/agents/0.0.0/mutagen-agent synchronizer")
+	if err != nil {
+		t.Fatal("unable to create command:", err)
+	}
+	if err := command.Run(); err == nil {
+		t.Fatal("nonexistent agent invocation succeeded unexpectedly")
+	}
+	if install, cmdExe, err := transport.ClassifyError(command.ProcessState, ""); err != nil {
+		t.Error("unable to classify error:", err)
+	} else if !install {
+		t.Error("error not classified as requiring installation")
+	} else if cmdExe {
+		t.Error("error classified as cmd.exe environment")
+	}
+
+	// Verify that other failures aren't classified.
+	if command, err = transport.Command("false"); err != nil {
+		t.Fatal("unable to create command:", err)
+	} else if err := command.Run(); err == nil {
+		t.Fatal("failing command succeeded unexpectedly")
+	} else if _, _, err := transport.ClassifyError(command.ProcessState, ""); err == nil {
+		t.Error("generic failure classified unexpectedly")
+	}
+
+	// Verify that helper failures aren't classified as requiring installation.
+	// We simulate these using a script that exits with the helper's failure
+	// exit code.
+	script := filepath.Join(home, "fail.sh")
+	if err := os.WriteFile(script, []byte("exit 255\n"), 0600); err != nil {
+		t.Fatal("unable to create failure script:", err)
+	}
+	if command, err = transport.Command("sh fail.sh"); err != nil {
+		t.Fatal("unable to create command:", err)
+	} else if err := command.Run(); err == nil {
+		t.Fatal("failing command succeeded unexpectedly")
+	} else if install, _, err := transport.ClassifyError(command.ProcessState, "Error: pod web-0 not found"); err == nil {
+		t.Error("helper failure classified unexpectedly")
+	} else if install {
+		t.Error("helper failure classified as requiring installation")
+	}
+
+	// Verify that a missing shell isn't classified as requiring installation.
+	command, err = transport.Command(".mutagen/agents/0.0.0/mutagen-agent synchronizer")
+	if err != nil {
+		t.Fatal("unable to create command:", err)
+	}
+	if err := command.Run(); err == nil {
+		t.Fatal("nonexistent agent invocation succeeded unexpectedly")
+	}
+	errorOutput := `OCI runtime exec failed: exec failed: exec: "sh": executable file not found in $PATH: unknown`
+	if install, _, err := transport.ClassifyError(command.ProcessState, errorOutput); err == nil {
+		t.Error("missing shell classified unexpectedly")
+	} else if install {
+		t.Error("missing shell classified as requiring installation")
+	}
+}
+
+// TestHelperEnvironment tests that the helper is invoked as the current
+// executable with locked-in Kubernetes client environment variables.
+func TestHelperEnvironment(t *testing.T) {
+	t.Setenv("KUBECONFIG", "/base")
+
+	// Create the transport.
+	transport, err := NewTransport("default/web-0/app", map[string]string{"KUBECONFIG": "/locked"}, nil)
+	if err != nil {
+		t.Fatal("unable to create transport:", err)
+	}
+
+	// Create a command and verify its executable and environment.
+	command, err := transport.Command("true")
+	if err != nil {
+		t.Fatal("unable to create command:", err)
+	}
+	if executable, err := os.Executable(); err != nil {
+		t.Fatal("unable to determine executable path:", err)
+	} else if command.Path != executable {
+		t.Error("helper path does not match executable:", command.Path, "!=", executable)
+	}
+	if !containsAll(command.Env, "KUBECONFIG=/locked") {
+		t.Error("locked-in variable not set for helper")
+	}
+}
+
+// TestSetKubernetesVariables tests that locked-in Kubernetes client
+// environment variables override and filter the base environment.
+func TestSetKubernetesVariables(t *testing.T) {
+	base := []string{"KUBECONFIG=/base", "OTHER=value"}
+	if result := setKubernetesVariables(base, map[string]string{"KUBECONFIG": "/locked"}); !containsAll(result, "KUBECONFIG=/locked", "OTHER=value") {
+		t.Error("locked-in variable not set:", result)
+	}
+	for _, variable := range setKubernetesVariables(base, nil) {
+		if strings.HasPrefix(variable, "KUBECONFIG=") {
+			t.Error("unlocked variable not filtered:", variable)
+		}
+	}
+}
+
+// containsAll returns whether or not a list contains all of the specified
+// values.
+func containsAll(list []string, values ...string) bool {
+	for _, value := range values {
+		var found bool
+		for _, entry := range list {
+			if entry == value {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return false
+		}
+	}
+	return true
+}
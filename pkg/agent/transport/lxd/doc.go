@@ -0,0 +1,3 @@
+// Package lxd provides the LXD transport implementation, which also supports
+// Incus.
+package lxd
@@ -0,0 +1,279 @@
+package lxd
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/mutagen-io/mutagen/pkg/agent"
+	"github.com/mutagen-io/mutagen/pkg/agent/transport"
+	"github.com/mutagen-io/mutagen/pkg/environment"
+	"github.com/mutagen-io/mutagen/pkg/lxd"
+	"github.com/mutagen-io/mutagen/pkg/process"
+	"github.com/mutagen-io/mutagen/pkg/url"
+)
+
+const (
+	// defaultUser is the user under which LXD executes commands if no user is
+	// specified.
+	defaultUser = "root"
+	// agentMode is the file mode used for the agent binary when copying it
+	// into an instance.
+	agentMode = "0755"
+)
+
+// lxdTransport implements the agent.Transport interface using LXD.
+type lxdTransport struct {
+	// instance is the target instance name.
+	instance string
+	// user is the instance user under which agents should be invoked.
+	user string
+	// environment is the collection of environment variables that need to be
+	// set for the LXD client.
+	environment map[string]string
+	// instanceProbed indicates whether or not instance probing has occurred.
+	// If true, then either the instance user fields will be populated or
+	// instanceProbeError will be non-nil.
+	instanceProbed bool
+	// instanceUserID is the numeric user ID of the user inside the instance.
+	instanceUserID string
+	// instanceGroupID is the numeric ID of the user's default group inside the
+	// instance.
+	instanceGroupID string
+	// instanceHomeDirectory is the path to the user's home directory within
+	// the instance.
+	instanceHomeDirectory string
+	// instanceProbeError tracks any error that arose when probing the
+	// instance.
+	instanceProbeError error
+}
+
+// NewTransport creates a new LXD transport using the specified parameters.
+func NewTransport(instance, user string, environment map[string]string) (agent.Transport, error) {
+	return &lxdTransport{
+		instance:    instance,
+		user:        user,
+		environment: environment,
+	}, nil
+}
+
+// setLXDVariables updates a base environment specification by setting LXD
+// environment variables to match those from an LXD URL. Any known LXD
+// environment variables that aren't present in the URL's variables are filtered
+// from the environment.
+func setLXDVariables(base []string, variables map[string]string) []string {
+	// Convert the base environment to a map for easier manipulation.
+	result := environment.ToMap(base)
+
+	// Populate LXD environment variables. If a given variable wasn't stored in
+	// the URL, then remove it from the environment.
+	for _, variable := range url.LXDEnvironmentVariables {
+		if value, ok := variables[variable]; ok {
+			result[variable] = value
+		} else {
+			delete(result, variable)
+		}
+	}
+
+	// Done.
+	return environment.FromMap(result)
+}
+
+// parsePasswdEntry parses a passwd database entry (as printed by getent) and
+// extracts the user ID, group ID, and home directory.
+func parsePasswdEntry(entry string) (string, string, string, error) {
+	// Split the entry into its fields.
+	fields := strings.Split(strings.TrimSpace(entry), ":")
+	if len(fields) != 7 {
+		return "", "", "", errors.New("invalid passwd entry")
+	}
+
+	// Extract and validate the relevant fields.
+	userID, groupID, home := fields[2], fields[3], fields[5]
+	if userID == "" {
+		return "", "", "", errors.New("empty user ID")
+	} else if groupID == "" {
+		return "", "", "", errors.New("empty group ID")
+	} else if home == "" {
+		return "", "", "", errors.New("empty home directory")
+	}
+
+	// Success.
+	return userID, groupID, home, nil
+}
+
+// client creates an LXD client command with the specified arguments.
+func (t *lxdTransport) client(arguments ...string) (*exec.Cmd, error) {
+	// Create the command.
+	command, err := lxd.Command(context.Background(), arguments...)
+	if err != nil {
+		return nil, err
+	}
+
+	// Set the process attributes.
+	command.SysProcAttr = transport.ProcessAttributes()
+
+	// Set the environment for the command.
+	command.Env = setLXDVariables(os.Environ(), t.environment)
+
+	// Done.
+	return command, nil
+}
+
+// command is an underlying command generation function that allows
+// specification of the working directory inside the instance. If probed is
+// true, then the command will run as the probed user (if a user was specified
+// in the URL), otherwise it will run as the default (root) user.
+func (t *lxdTransport) command(command, workingDirectory string, probed bool) (*exec.Cmd, error) {
+	// Tell LXD that we want to execute a command without a pseudo-terminal.
+	lxdArguments := []string{"exec", t.instance, "--mode=non-interactive"}
+
+	// If requested, tell LXD which user should be used to execute commands
+	// inside the instance. LXD only accepts numeric IDs and doesn't set any
+	// user-related environment variables, so we do that as well.
+	if probed && t.user != "" {
+		lxdArguments = append(lxdArguments,
+			"--user", t.instanceUserID,
+			"--group", t.instanceGroupID,
+			"--env", "HOME="+t.instanceHomeDirectory,
+			"--env", "USER="+t.user,
+		)
+	}
+
+	// If specified, tell LXD which directory should be used as the working
+	// directory inside the instance.
+	if workingDirectory != "" {
+		lxdArguments = append(lxdArguments, "--cwd", workingDirectory)
+	}
+
+	// Lex the command that we want to run since LXD, unlike SSH, wants the
+	// commands and arguments separately instead of as a single argument. All
+	// agent.Transport interfaces only need to support commands that can be
+	// lexed by splitting on spaces.
+	lxdArguments = append(lxdArguments, "--")
+	lxdArguments = append(lxdArguments, strings.Split(command, " ")...)
+
+	// Create the command.
+	return t.client(lxdArguments...)
+}
+
+// probeInstance ensures that the instance user fields are populated. It is
+// idempotent. If probing previously failed, probing will simply return an
+// error indicating the previous failure.
+func (t *lxdTransport) probeInstance() error {
+	// Watch for previous errors.
+	if t.instanceProbeError != nil {
+		return fmt.Errorf("previous instance probing failed: %w", t.instanceProbeError)
+	}
+
+	// Check if we've already probed. If not, then we're going to probe, so mark
+	// it as complete (even if it isn't ultimately successful).
+	if t.instanceProbed {
+		return nil
+	}
+	t.instanceProbed = true
+
+	// Determine the user to probe.
+	user := t.user
+	if user == "" {
+		user = defaultUser
+	}
+
+	// Query the user's passwd entry. LXD instances are always Linux-based, so
+	// we don't need to consider Windows.
+	if command, err := t.command("getent passwd "+user, "", false); err != nil {
+		return fmt.Errorf("unable to set up LXD invocation: %w", err)
+	} else if entryBytes, err := command.Output(); err != nil {
+		t.instanceProbeError = fmt.Errorf("unable to query user information: %w", err)
+		return t.instanceProbeError
+	} else if !utf8.Valid(entryBytes) {
+		t.instanceProbeError = errors.New("non-UTF-8 user information")
+		return t.instanceProbeError
+	} else if userID, groupID, home, err := parsePasswdEntry(string(entryBytes)); err != nil {
+		t.instanceProbeError = fmt.Errorf("unable to parse user information: %w", err)
+		return t.instanceProbeError
+	} else {
+		t.instanceUserID = userID
+		t.instanceGroupID = groupID
+		t.instanceHomeDirectory = home
+	}
+
+	// Success.
+	return nil
+}
+
+// Copy implements the Copy method of agent.Transport.
+func (t *lxdTransport) Copy(localPath, remoteName string) error {
+	// Ensure that the instance has been probed.
+	if err := t.probeInstance(); err != nil {
+		return fmt.Errorf("unable to probe instance: %w", err)
+	}
+
+	// Compute the path inside the instance. LXD expects the instance name to be
+	// prefixed to the absolute path inside the instance. If the remote name is
+	// already an absolute path, then we use it directly.
+	var instancePath string
+	if strings.HasPrefix(remoteName, "/") {
+		instancePath = t.instance + remoteName
+	} else {
+		instancePath = fmt.Sprintf("%s%s/%s",
+			t.instance,
+			strings.TrimSuffix(t.instanceHomeDirectory, "/"),
+			remoteName,
+		)
+	}
+
+	// Set up the copy command. Unlike Docker, LXD allows us to set ownership
+	// and permissions as part of the copy operation.
+	command, err := t.client(
+		"file", "push",
+		"--uid", t.instanceUserID,
+		"--gid", t.instanceGroupID,
+		"--mode", agentMode,
+		localPath, instancePath,
+	)
+	if err != nil {
+		return fmt.Errorf("unable to set up LXD invocation: %w", err)
+	}
+
+	// Run the operation.
+	if output, err := command.CombinedOutput(); err != nil {
+		if len(output) > 0 {
+			return fmt.Errorf("unable to run LXD copy command: %w (%s)", err, strings.TrimSpace(string(output)))
+		}
+		return fmt.Errorf("unable to run LXD copy command: %w", err)
+	}
+
+	// Success.
+	return nil
+}
+
+// Command implements the Command method of agent.Transport.
+func (t *lxdTransport) Command(command string) (*exec.Cmd, error) {
+	// Ensure that the instance has been probed.
+	if err := t.probeInstance(); err != nil {
+		return nil, fmt.Errorf("unable to probe instance: %w", err)
+	}
+
+	// Generate the command.
+	return t.command(command, t.instanceHomeDirectory, true)
+}
+
+// ClassifyError implements the ClassifyError method of agent.Transport.
+func (t *lxdTransport) ClassifyError(processState *os.ProcessState, errorOutput string) (bool, bool, error) {
+	// LXD executes commands directly (without a shell), but it returns the
+	// conventional POSIX shell exit codes if the command can't be found or
+	// executed. Either indicates that the agent needs to be (re-)installed.
+	// LXD instances are always Linux-based, so the remote is never Windows.
+	if process.IsPOSIXShellCommandNotFound(processState) ||
+		process.IsPOSIXShellInvalidCommand(processState) {
+		return true, false, nil
+	}
+
+	// Otherwise, we can't classify the error.
+	return false, false, errors.New("unknown process exit error")
+}
@@ -0,0 +1,305 @@
+package lxd
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"reflect"
+	"runtime"
+	"sort"
+	"strings"
+	"testing"
+
+	"github.com/mutagen-io/mutagen/pkg/url"
+)
+
+// TestParsePasswdEntry tests parsePasswdEntry.
+func TestParsePasswdEntry(t *testing.T) {
+	// Set up test cases.
+	testCases := []struct {
+		entry         string
+		expectFailure bool
+		userID        string
+		groupID       string
+		home          string
+	}{
+		{"", true, "", "", ""},
+		{"root:x:0:0:root:/root", true, "", "", ""},
+		{"root:x::0:root:/root:/bin/bash", true, "", "", ""},
+		{"root:x:0::root:/root:/bin/bash", true, "", "", ""},
+		{"root:x:0:0:root::/bin/bash", true, "", "", ""},
+		{"root:x:0:0:root:/root:/bin/bash\n", false, "0", "0", "/root"},
+		{"ubuntu:x:1000:1001:Ubuntu:/home/ubuntu:/bin/bash", false, "1000", "1001", "/home/ubuntu"},
+	}
+
+	// Process test cases.
+	for i, testCase := range testCases {
+		userID, groupID, home, err := parsePasswdEntry(testCase.entry)
+		if err != nil {
+			if !testCase.expectFailure {
+				t.Errorf("test index %d: parsing failed unexpectedly: %v", i, err)
+			}
+			continue
+		} else if testCase.expectFailure {
+			t.Errorf("test index %d: parsing succeeded unexpectedly", i)
+			continue
+		}
+		if userID != testCase.userID {
+			t.Errorf("test index %d: user ID does not match expected: %s != %s", i, userID, testCase.userID)
+		}
+		if groupID != testCase.groupID {
+			t.Errorf("test index %d: group ID does not match expected: %s != %s", i, groupID, testCase.groupID)
+		}
+		if home != testCase.home {
+			t.Errorf("test index %d: home directory does not match expected: %s != %s", i, home, testCase.home)
+		}
+	}
+}
+
+// fakeLXC is an LXD client stand-in that logs its arguments to the file
+// specified by the FAKE_LXC_LOG environment variable. It reports passwd entries
+// for the root and alice users and succeeds for all other operations.
+const fakeLXC = `#!/bin/sh
+echo "$*" >> "$FAKE_LXC_LOG"
+case "$*" in
+exec*"-- getent passwd root")
+	echo "root:x:0:0:root:/root:/bin/bash"
+	;;
+exec*"-- getent passwd alice")
+	echo "alice:x:1000:1001:Alice:/home/alice:/bin/bash"
+	;;
+exec*"-- getent passwd "*)
+	exit 2
+	;;
+esac
+exit 0
+`
+
+// TestTransportURLInvocations tests the LXD client invocations that the LXD
+// transport performs for LXD URLs.
+func TestTransportURLInvocations(t *testing.T) {
+	// Install the LXD client stand-in.
+	if runtime.GOOS == "windows" {
+		t.Skip()
+	}
+	directory := t.TempDir()
+	if err := os.WriteFile(filepath.Join(directory, "lxc"), []byte(fakeLXC), 0700); err != nil {
+		t.Fatal("unable to create fake LXD client:", err)
+	}
+	t.Setenv("MUTAGEN_LXD_PATH", directory)
+
+	// Set up test cases.
+	testCases := []struct {
+		// raw is the raw URL.
+		raw string
+		// kind is the URL kind.
+		kind url.Kind
+		// environment is the environment to use when parsing the URL.
+		environment map[string]string
+		// expectedProbe is the expected user probing invocation.
+		expectedProbe string
+		// expectedCommand are the expected agent command arguments.
+		expectedCommand []string
+		// expectedCopy is the expected copy invocation.
+		expectedCopy string
+		// expectedVariables are the expected LXD environment variables.
+		expectedVariables []string
+	}{
+		{
+			raw:           "lxd://instance/~/project",
+			kind:          url.Kind_Synchronization,
+			expectedProbe: "exec instance --mode=non-interactive -- getent passwd root",
+			expectedCommand: []string{
+				"exec", "instance", "--mode=non-interactive",
+				"--cwd", "/root",
+				"--", "mutagen-agent", "synchronizer",
+			},
+			expectedCopy: "file push --uid 0 --gid 0 --mode 0755 /agent instance/root/.mutagen-agent",
+		},
+		{
+			raw:           "lxd://alice@remote:instance/~/project",
+			kind:          url.Kind_Synchronization,
+			environment:   map[string]string{"LXD_CONF": "/lxd"},
+			expectedProbe: "exec remote:instance --mode=non-interactive -- getent passwd alice",
+			expectedCommand: []string{
+				"exec", "remote:instance", "--mode=non-interactive",
+				"--user", "1000", "--group", "1001",
+				"--env", "HOME=/home/alice", "--env", "USER=alice",
+				"--cwd", "/home/alice",
+				"--", "mutagen-agent", "synchronizer",
+			},
+			expectedCopy:      "file push --uid 1000 --gid 1001 --mode 0755 /agent remote:instance/home/alice/.mutagen-agent",
+			expectedVariables: []string{"LXD_CONF=/lxd"},
+		},
+		{
+			raw:  "lxd://alice@instance:tcp:localhost:8080",
+			kind: url.Kind_Forwarding,
+			environment: map[string]string{
+				"INCUS_CONF":                  "/incus",
+				"MUTAGEN_SOURCE_INCUS_CONF":   "/incus-source",
+				"MUTAGEN_DESTINATION_LXD_DIR": "/lxd",
+			},
+			expectedProbe: "exec instance --mode=non-interactive -- getent passwd alice",
+			expectedCommand: []string{
+				"exec", "instance", "--mode=non-interactive",
+				"--user", "1000", "--group", "1001",
+				"--env", "HOME=/home/alice", "--env", "USER=alice",
+				"--cwd", "/home/alice",
+				"--", "mutagen-agent", "synchronizer",
+			},
+			expectedCopy:      "file push --uid 1000 --gid 1001 --mode 0755 /agent instance/home/alice/.mutagen-agent",
+			expectedVariables: []string{"INCUS_CONF=/incus-source"},
+		},
+	}
+
+	// Process test cases.
+	for i, testCase := range testCases {
+		// Set up an invocation log.
+		log := filepath.Join(t.TempDir(), "log")
+		t.Setenv("FAKE_LXC_LOG", log)
+
+		// Set up the environment and parse the URL.
+		for _, variable := range url.LXDEnvironmentVariables {
+			t.Setenv(variable, "")
+			os.Unsetenv(variable)
+		}
+		for variable, value := range testCase.environment {
+			t.Setenv(variable, value)
+		}
+		target, err := url.Parse(testCase.raw, testCase.kind, true)
+		if err != nil {
+			t.Fatalf("test index %d: unable to parse URL: %v", i, err)
+		}
+
+		// Create the transport in the same manner as the protocol handlers.
+		transport, err := NewTransport(target.Host, target.User, target.Environment)
+		if err != nil {
+			t.Fatalf("test index %d: unable to create transport: %v", i, err)
+		}
+
+		// Verify command construction.
+		command, err := transport.Command("mutagen-agent synchronizer")
+		if err != nil {
+			t.Fatalf("test index %d: unable to create command: %v", i, err)
+		}
+		if !reflect.DeepEqual(command.Args[1:], testCase.expectedCommand) {
+			t.Errorf("test index %d: command arguments do not match expected: %v != %v",
+				i, command.Args[1:], testCase.expectedCommand,
+			)
+		}
+		var variables []string
+		for _, variable := range command.Env {
+			if strings.HasPrefix(variable, "LXD_") || strings.HasPrefix(variable, "INCUS_") {
+				variables = append(variables, variable)
+			}
+		}
+		sort.Strings(variables)
+		if !reflect.DeepEqual(variables, testCase.expectedVariables) {
+			t.Errorf("test index %d: LXD variables do not match expected: %v != %v",
+				i, variables, testCase.expectedVariables,
+			)
+		}
+
+		// Verify copy invocation. The instance should only have been probed
+		// once across both operations.
+		if err := transport.Copy("/agent", ".mutagen-agent"); err != nil {
+			t.Fatalf("test index %d: unable to copy: %v", i, err)
+		}
+		invocations, err := os.ReadFile(log)
+		if err != nil {
+			t.Fatalf("test index %d: unable to read invocation log: %v", i, err)
+		}
+		expectedInvocations := testCase.expectedProbe + "\n" + testCase.expectedCopy + "\n"
+		if string(invocations) != expectedInvocations {
+			t.Errorf("test index %d: invocations do not match expected: %q != %q",
+				i, invocations, expectedInvocations,
+			)
+		}
+	}
+}
+
+// TestTransportProbeFailure tests that the LXD transport fails if the instance
+// user can't be probed and that it doesn't re-attempt probing.
+func TestTransportProbeFailure(t *testing.T) {
+	// Install the LXD client stand-in.
+	if runtime.GOOS == "windows" {
+		t.Skip()
+	}
+	directory := t.TempDir()
+	if err := os.WriteFile(filepath.Join(directory, "lxc"), []byte(fakeLXC), 0700); err != nil {
+		t.Fatal("unable to create fake LXD client:", err)
+	}
+	t.Setenv("MUTAGEN_LXD_PATH", directory)
+	log := filepath.Join(t.TempDir(), "log")
+	t.Setenv("FAKE_LXC_LOG", log)
+
+	// Create a transport for an unknown user.
+	transport, err := NewTransport("instance", "bob", nil)
+	if err != nil {
+		t.Fatal("unable to create transport:", err)
+	}
+
+	// Verify that command creation and copying fail.
+	if _, err := transport.Command("mutagen-agent synchronizer"); err == nil {
+		t.Error("command created for unknown user")
+	} else if !strings.Contains(err.Error(), "unable to query user information") {
+		t.Error("error does not describe probe failure:", err)
+	}
+	if err := transport.Copy("/agent", ".mutagen-agent"); err == nil {
+		t.Error("copy succeeded for unknown user")
+	} else if !strings.Contains(err.Error(), "previous instance probing failed") {
+		t.Error("error does not describe previous probe failure:", err)
+	}
+
+	// Verify that probing was only attempted once.
+	if invocations, err := os.ReadFile(log); err != nil {
+		t.Fatal("unable to read invocation log:", err)
+	} else if string(invocations) != "exec instance --mode=non-interactive -- getent passwd bob\n" {
+		t.Errorf("unexpected invocations: %q", invocations)
+	}
+}
+
+// TestTransportClassifyError tests LXD transport error classification.
+func TestTransportClassifyError(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip()
+	}
+
+	// Set up test cases.
+	testCases := []struct {
+		// exitCode is the process exit code.
+		exitCode int
+		// expectFailure indicates whether or not classification should fail.
+		expectFailure bool
+	}{
+		{126, false},
+		{127, false},
+		{1, true},
+		{255, true},
+	}
+
+	// Process test cases.
+	transport, err := NewTransport("instance", "", nil)
+	if err != nil {
+		t.Fatal("unable to create transport:", err)
+	}
+	for i, testCase := range testCases {
+		process := exec.Command("/bin/sh", "-c", fmt.Sprintf("exit %d", testCase.exitCode))
+		if err := process.Run(); err == nil {
+			t.Fatalf("test index %d: process succeeded unexpectedly", i)
+		}
+		tryInstall, cmdExe, err := transport.ClassifyError(process.ProcessState, "")
+		if err != nil {
+			if !testCase.expectFailure {
+				t.Errorf("test index %d: unexpected classification failure: %v", i, err)
+			}
+		} else if testCase.expectFailure {
+			t.Errorf("test index %d: classification succeeded unexpectedly", i)
+		} else if !tryInstall {
+			t.Errorf("test index %d: installation not recommended", i)
+		} else if cmdExe {
+			t.Errorf("test index %d: cmd.exe environment detected", i)
+		}
+	}
+}
@@ -0,0 +1,42 @@
+// Package plugin provides an agent transport implementation that delegates
+// remote operations to external protocol handler plugins, allowing third
+// parties to add new endpoint protocols without modifying Mutagen.
+//
+// A plugin is an executable named mutagen-transport-<name> that's discoverable
+// via the PATH environment variable and which is selected using URLs of the
+// form plugin+<name>://<target>/<path> (or plugin+<name>://<target>:<endpoint>
+// for forwarding URLs). The target is opaque to Mutagen and is passed verbatim
+// to the plugin. It can't begin with '-' or contain '/', ':', or whitespace, so
+// plugins that need to identify ports must use a syntax other than host:port
+// (e.g. host_port). Plugins must support the following invocations:
+//
+//	mutagen-transport-<name> copy <target> <local-path> <remote-path>
+//
+// The copy operation copies the local file at local-path (which is always
+// absolute) to remote-path on the target. The remote path is either absolute
+// or relative to the remote user's home directory. The plugin should exit with
+// a zero exit code on success and a non-zero exit code on failure, in which
+// case any output will be included in error messages.
+//
+//	mutagen-transport-<name> exec <target> <command>
+//
+// The exec operation runs the specified command (provided as a single argument
+// that can be lexed by splitting on spaces) on the target with the remote
+// user's home directory as the working directory. The plugin must connect its
+// standard input, output, and error streams to those of the remote command
+// without buffering or modification, and it must exit with the remote
+// command's exit code. In particular, the conventional POSIX shell exit codes
+// of 126 (command not executable) and 127 (command not found) must be
+// propagated so that Mutagen can detect when the agent needs to be installed.
+// For Windows targets, cmd.exe error output must be propagated as well.
+//
+// In addition to the plugin's inherited environment, the following environment
+// variables are set for all invocations:
+//
+//	MUTAGEN_TRANSPORT_PROTOCOL_VERSION: the version of this protocol (1)
+//	MUTAGEN_TRANSPORT_MODE: the agent mode (synchronizer or forwarder)
+//	MUTAGEN_TRANSPORT_PATH: the synchronization root or forwarding endpoint
+//
+// Plugins should reject invocations with unknown operations or unsupported
+// protocol versions by exiting with a non-zero exit code.
+package plugin
@@ -0,0 +1,129 @@
+package plugin
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"strings"
+
+	"github.com/mutagen-io/mutagen/pkg/agent"
+	"github.com/mutagen-io/mutagen/pkg/agent/transport"
+	"github.com/mutagen-io/mutagen/pkg/process"
+	"github.com/mutagen-io/mutagen/pkg/url"
+)
+
+const (
+	// ExecutablePrefix is the prefix used for plugin executable names.
+	ExecutablePrefix = "mutagen-transport-"
+	// ProtocolVersion is the version of the plugin protocol.
+	ProtocolVersion = "1"
+
+	// operationCopy is the plugin operation used to copy files to the remote.
+	operationCopy = "copy"
+	// operationExec is the plugin operation used to run commands on the
+	// remote.
+	operationExec = "exec"
+)
+
+// FindExecutable locates the executable for the plugin with the specified
+// name using the PATH environment variable.
+func FindExecutable(name string) (string, error) {
+	if !url.IsValidPluginName(name) {
+		return "", fmt.Errorf("invalid plugin name: %s", name)
+	}
+	path, err := exec.LookPath(ExecutablePrefix + name)
+	if err != nil {
+		return "", fmt.Errorf("unable to locate plugin executable: %w", err)
+	}
+	return path, nil
+}
+
+// pluginTransport implements the agent.Transport interface using an external
+// protocol handler plugin.
+type pluginTransport struct {
+	// executable is the path to the plugin executable.
+	executable string
+	// target is the opaque plugin target.
+	target string
+	// environment is the environment to use for plugin invocations.
+	environment []string
+}
+
+// NewTransport creates a new plugin transport using the plugin with the
+// specified name and the specified target, as well as the agent mode and the
+// path for the endpoint.
+func NewTransport(name, target, mode, path string) (agent.Transport, error) {
+	// Locate the plugin executable.
+	executable, err := FindExecutable(name)
+	if err != nil {
+		return nil, err
+	}
+
+	// Compute the plugin environment.
+	environment := append(os.Environ(),
+		"MUTAGEN_TRANSPORT_PROTOCOL_VERSION="+ProtocolVersion,
+		"MUTAGEN_TRANSPORT_MODE="+mode,
+		"MUTAGEN_TRANSPORT_PATH="+path,
+	)
+
+	// Create the transport.
+	return &pluginTransport{
+		executable:  executable,
+		target:      target,
+		environment: environment,
+	}, nil
+}
+
+// client creates a plugin invocation for the specified operation and
+// arguments.
+func (t *pluginTransport) client(operation string, arguments ...string) *exec.Cmd {
+	// Create the command.
+	result := exec.Command(t.executable, append([]string{operation, t.target}, arguments...)...)
+
+	// Set the process attributes.
+	result.SysProcAttr = transport.ProcessAttributes()
+
+	// Set the environment.
+	result.Env = t.environment
+
+	// Done.
+	return result
+}
+
+// Copy implements the Copy method of agent.Transport.
+func (t *pluginTransport) Copy(localPath, remoteName string) error {
+	// Run the operation.
+	if output, err := t.client(operationCopy, localPath, remoteName).CombinedOutput(); err != nil {
+		if len(output) > 0 {
+			return fmt.Errorf("unable to run plugin copy operation: %w (%s)", err, strings.TrimSpace(string(output)))
+		}
+		return fmt.Errorf("unable to run plugin copy operation: %w", err)
+	}
+
+	// Success.
+	return nil
+}
+
+// Command implements the Command method of agent.Transport.
+func (t *pluginTransport) Command(command string) (*exec.Cmd, error) {
+	return t.client(operationExec, command), nil
+}
+
+// ClassifyError implements the ClassifyError method of agent.Transport.
+func (t *pluginTransport) ClassifyError(processState *os.ProcessState, errorOutput string) (bool, bool, error) {
+	// Plugins are required to propagate the conventional POSIX shell exit
+	// codes and Windows error output, so we can classify errors in the same
+	// manner as other shell-based transports.
+	if process.IsPOSIXShellInvalidCommand(processState) ||
+		process.IsPOSIXShellCommandNotFound(processState) {
+		return true, false, nil
+	} else if process.OutputIsWindowsInvalidCommand(errorOutput) {
+		return false, true, nil
+	} else if process.OutputIsWindowsCommandNotFound(errorOutput) {
+		return true, true, nil
+	}
+
+	// Otherwise, we can't classify the error.
+	return false, false, errors.New("unknown process exit error")
+}
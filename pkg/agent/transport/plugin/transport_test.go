@@ -0,0 +1,102 @@
+package plugin
+
+import (
+	"os"
+	"path/filepath"
+	"runtime"
+	"testing"
+)
+
+// installTestPlugin creates a dummy plugin executable with the specified name
+// in a temporary directory and sets the PATH environment variable to include
+// only that directory.
+func installTestPlugin(t *testing.T, name string) string {
+	// Plugin discovery on Windows relies on executable extensions, so we only
+	// run these tests on POSIX systems.
+	if runtime.GOOS == "windows" {
+		t.Skip()
+	}
+
+	// Create the executable.
+	directory := t.TempDir()
+	executable := filepath.Join(directory, ExecutablePrefix+name)
+	if err := os.WriteFile(executable, []byte("#!/bin/sh\nexit 0\n"), 0700); err != nil {
+		t.Fatal("unable to create plugin executable:", err)
+	}
+
+	// Set the search path.
+	t.Setenv("PATH", directory)
+
+	// Done.
+	return executable
+}
+
+// TestFindExecutable tests FindExecutable.
+func TestFindExecutable(t *testing.T) {
+	// Install a test plugin.
+	executable := installTestPlugin(t, "test")
+
+	// Set up test cases.
+	testCases := []struct {
+		name        string
+		expectError bool
+	}{
+		{"", true},
+		{"Test", true},
+		{"../test", true},
+		{"missing", true},
+		{"test", false},
+	}
+
+	// Process test cases.
+	for i, testCase := range testCases {
+		path, err := FindExecutable(testCase.name)
+		if err != nil && !testCase.expectError {
+			t.Errorf("test index %d: unexpected error: %v", i, err)
+		} else if err == nil && testCase.expectError {
+			t.Errorf("test index %d: error expected", i)
+		} else if err == nil && path != executable {
+			t.Errorf("test index %d: executable path does not match expected: %s != %s", i, path, executable)
+		}
+	}
+}
+
+// TestCommand tests that commands are passed to the plugin's exec operation.
+func TestCommand(t *testing.T) {
+	// Install a test plugin.
+	installTestPlugin(t, "test")
+
+	// Create a transport.
+	transport, err := NewTransport("test", "user@host", "synchronizer", "/var/www")
+	if err != nil {
+		t.Fatal("unable to create transport:", err)
+	}
+
+	// Create a command and verify its arguments.
+	command, err := transport.Command("mutagen-agent synchronizer")
+	if err != nil {
+		t.Fatal("unable to create command:", err)
+	}
+	expected := []string{ExecutablePrefix + "test", "exec", "user@host", "mutagen-agent synchronizer"}
+	if len(command.Args) != len(expected) {
+		t.Fatal("argument count mismatch:", command.Args)
+	}
+	for i, argument := range expected[1:] {
+		if command.Args[i+1] != argument {
+			t.Errorf("argument %d mismatch: %s != %s", i+1, command.Args[i+1], argument)
+		}
+	}
+
+	// Verify that protocol information is provided in the environment.
+	var foundVersion, foundMode bool
+	for _, variable := range command.Env {
+		if variable == "MUTAGEN_TRANSPORT_PROTOCOL_VERSION="+ProtocolVersion {
+			foundVersion = true
+		} else if variable == "MUTAGEN_TRANSPORT_MODE=synchronizer" {
+			foundMode = true
+		}
+	}
+	if !foundVersion || !foundMode {
+		t.Error("protocol information missing from environment")
+	}
+}
@@ -0,0 +1,236 @@
+package ssh
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+
+	"github.com/mutagen-io/mutagen/pkg/agent"
+	"github.com/mutagen-io/mutagen/pkg/agent/transport"
+	"github.com/mutagen-io/mutagen/pkg/azure"
+	"github.com/mutagen-io/mutagen/pkg/environment"
+	"github.com/mutagen-io/mutagen/pkg/ssh"
+	"github.com/mutagen-io/mutagen/pkg/url"
+)
+
+// azureTransport implements the agent.Transport interface using the Azure
+// CLI's SSH extension (az ssh vm), which handles Azure AD authentication and
+// certificate issuance before invoking OpenSSH.
+type azureTransport struct {
+	// user is the local user on the virtual machine under which agents should
+	// be invoked. If empty, Azure AD authentication is used.
+	user string
+	// vm is the target virtual machine name.
+	vm string
+	// environment is the collection of environment variables that need to be
+	// set for the Azure CLI.
+	environment map[string]string
+	// prompter is the prompter identifier to use for prompting.
+	prompter string
+}
+
+// NewAzureTransport creates a new SSH transport that reaches Azure virtual
+// machines using the Azure CLI. The resource group containing the virtual
+// machine is determined by the Azure CLI's configuration (which may be
+// specified via the AZURE_DEFAULTS_GROUP environment variable).
+func NewAzureTransport(user, vm string, environment map[string]string, prompter string) (agent.Transport, error) {
+	return &azureTransport{
+		user:        user,
+		vm:          vm,
+		environment: environment,
+		prompter:    prompter,
+	}, nil
+}
+
+// setAzureVariables updates a base environment specification by setting Azure
+// CLI environment variables to match those from an Azure URL. Any known Azure
+// CLI environment variables that aren't present in the URL's variables are
+// filtered from the environment.
+func setAzureVariables(base []string, variables map[string]string) []string {
+	// Convert the base environment to a map for easier manipulation.
+	result := environment.ToMap(base)
+
+	// Populate Azure CLI environment variables. If a given variable wasn't
+	// stored in the URL, then remove it from the environment.
+	for _, variable := range url.AzureEnvironmentVariables {
+		if value, ok := variables[variable]; ok {
+			result[variable] = value
+		} else {
+			delete(result, variable)
+		}
+	}
+
+	// Done.
+	return environment.FromMap(result)
+}
+
+// findConfigurationHost extracts the first host alias from an OpenSSH client
+// configuration, such as that generated by az ssh config.
+func findConfigurationHost(configuration string) (string, error) {
+	scanner := bufio.NewScanner(strings.NewReader(configuration))
+	for scanner.Scan() {
+		fields := strings.Fields(scanner.Text())
+		if len(fields) >= 2 && strings.EqualFold(fields[0], "Host") {
+			return fields[1], nil
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return "", fmt.Errorf("unable to read configuration: %w", err)
+	}
+	return "", errors.New("no host found in configuration")
+}
+
+// processEnvironment computes the environment for Azure CLI and OpenSSH
+// processes.
+func (t *azureTransport) processEnvironment() ([]string, error) {
+	// Create a copy of the current environment.
+	result := os.Environ()
+
+	// Set Azure CLI environment variables.
+	result = setAzureVariables(result, t.environment)
+
+	// Add locale environment variables.
+	result = addLocaleVariables(result)
+
+	// Set prompting environment variables, since the Azure CLI invokes OpenSSH
+	// and OpenSSH may require prompting.
+	return SetPrompterVariables(result, t.prompter)
+}
+
+// azureCommand creates an Azure CLI command with the specified arguments.
+func (t *azureTransport) azureCommand(arguments ...string) (*exec.Cmd, error) {
+	// Create the process.
+	command, err := azure.Command(context.Background(), arguments...)
+	if err != nil {
+		return nil, err
+	}
+
+	// Force it to run detached.
+	command.SysProcAttr = transport.ProcessAttributes()
+
+	// Set the environment.
+	if command.Env, err = t.processEnvironment(); err != nil {
+		return nil, fmt.Errorf("unable to create process environment: %w", err)
+	}
+
+	// Done.
+	return command, nil
+}
+
+// Copy implements the Copy method of agent.Transport.
+func (t *azureTransport) Copy(localPath, remoteName string) error {
+	// As with the standard SSH transport, we run SCP in the source directory
+	// to avoid path format issues on Windows.
+	if !filepath.IsAbs(localPath) {
+		return errors.New("scp source path must be absolute")
+	}
+	workingDirectory, sourceBase := filepath.Split(localPath)
+
+	// The Azure CLI doesn't provide a copy command, so we have it generate an
+	// OpenSSH client configuration (including any short-lived certificates
+	// required for authentication) in a temporary directory and then invoke
+	// SCP using that configuration.
+	temporaryDirectory, err := os.MkdirTemp("", "mutagen-azure")
+	if err != nil {
+		return fmt.Errorf("unable to create temporary directory: %w", err)
+	}
+	defer os.RemoveAll(temporaryDirectory)
+	configurationPath := filepath.Join(temporaryDirectory, "config")
+	configArguments := []string{
+		"ssh", "config",
+		"--name", t.vm,
+		"--file", configurationPath,
+		"--keys-destination-folder", filepath.Join(temporaryDirectory, "keys"),
+	}
+	if t.user != "" {
+		configArguments = append(configArguments, "--local-user", t.user)
+	}
+	configCommand, err := t.azureCommand(configArguments...)
+	if err != nil {
+		return fmt.Errorf("unable to set up Azure CLI invocation: %w", err)
+	}
+	if output, err := configCommand.CombinedOutput(); err != nil {
+		if len(output) > 0 {
+			return fmt.Errorf("unable to generate SSH configuration: %w (%s)", err, strings.TrimSpace(string(output)))
+		}
+		return fmt.Errorf("unable to generate SSH configuration: %w", err)
+	}
+
+	// Identify the host alias in the generated configuration.
+	configuration, err := os.ReadFile(configurationPath)
+	if err != nil {
+		return fmt.Errorf("unable to read generated SSH configuration: %w", err)
+	}
+	host, err := findConfigurationHost(string(configuration))
+	if err != nil {
+		return fmt.Errorf("unable to identify host in generated SSH configuration: %w", err)
+	}
+
+	// Set up arguments.
+	var scpArguments []string
+	scpArguments = append(scpArguments, "-F", configurationPath)
+	scpArguments = append(scpArguments, ssh.CompressionFlag())
+	scpArguments = append(scpArguments, ssh.ConnectTimeoutFlag(connectTimeoutSeconds))
+	scpArguments = append(scpArguments, ssh.ServerAliveFlags(serverAliveIntervalSeconds, serverAliveCountMax)...)
+	scpArguments = append(scpArguments, sourceBase, fmt.Sprintf("%s:%s", host, remoteName))
+
+	// Create the process.
+	scpCommand, err := ssh.SCPCommand(context.Background(), scpArguments...)
+	if err != nil {
+		return fmt.Errorf("unable to set up SCP invocation: %w", err)
+	}
+
+	// Set the working directory.
+	scpCommand.Dir = workingDirectory
+
+	// Set the process attributes.
+	scpCommand.SysProcAttr = transport.ProcessAttributes()
+
+	// Set the environment.
+	if scpCommand.Env, err = t.processEnvironment(); err != nil {
+		return fmt.Errorf("unable to create process environment: %w", err)
+	}
+
+	// Run the operation.
+	if err = scpCommand.Run(); err != nil {
+		return fmt.Errorf("unable to run SCP process: %w", err)
+	}
+
+	// Success.
+	return nil
+}
+
+// Command implements the Command method of agent.Transport.
+func (t *azureTransport) Command(command string) (*exec.Cmd, error) {
+	// Set up arguments. Arguments following "--" are passed directly to
+	// OpenSSH, with the command as the final argument.
+	azureArguments := []string{"ssh", "vm", "--name", t.vm}
+	if t.user != "" {
+		azureArguments = append(azureArguments, "--local-user", t.user)
+	}
+	azureArguments = append(azureArguments, "--")
+	azureArguments = append(azureArguments, ssh.ConnectTimeoutFlag(connectTimeoutSeconds))
+	azureArguments = append(azureArguments, ssh.ServerAliveFlags(serverAliveIntervalSeconds, serverAliveCountMax)...)
+	azureArguments = append(azureArguments, command)
+
+	// Create the process.
+	azureCommand, err := t.azureCommand(azureArguments...)
+	if err != nil {
+		return nil, fmt.Errorf("unable to set up Azure CLI invocation: %w", err)
+	}
+
+	// Done.
+	return azureCommand, nil
+}
+
+// ClassifyError implements the ClassifyError method of agent.Transport.
+func (t *azureTransport) ClassifyError(processState *os.ProcessState, errorOutput string) (bool, bool, error) {
+	// The Azure CLI propagates OpenSSH's exit code and error output, so we can
+	// use the same classification as the OpenSSH-based transport.
+	return classifyError(processState, errorOutput)
+}
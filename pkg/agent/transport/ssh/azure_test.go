@@ -0,0 +1,356 @@
+package ssh
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"reflect"
+	"runtime"
+	"sort"
+	"strings"
+	"testing"
+
+	"github.com/mutagen-io/mutagen/pkg/ssh"
+	"github.com/mutagen-io/mutagen/pkg/url"
+)
+
+// TestFindConfigurationHost tests findConfigurationHost.
+func TestFindConfigurationHost(t *testing.T) {
+	// Set up test cases.
+	testCases := []struct {
+		configuration string
+		expectFailure bool
+		expected      string
+	}{
+		{"", true, ""},
+		{"# Comment\n\tUser azureuser\n", true, ""},
+		{"Host resources-vm-1\n\tUser azureuser\n\tHostName 10.0.0.4\nHost 10.0.0.4\n", false, "resources-vm-1"},
+		{"  host alias other\n", false, "alias"},
+	}
+
+	// Process test cases.
+	for i, testCase := range testCases {
+		if host, err := findConfigurationHost(testCase.configuration); err != nil {
+			if !testCase.expectFailure {
+				t.Errorf("test index %d: unexpected failure: %v", i, err)
+			}
+		} else if testCase.expectFailure {
+			t.Errorf("test index %d: unexpected success", i)
+		} else if host != testCase.expected {
+			t.Errorf("test index %d: host does not match expected: %s != %s", i, host, testCase.expected)
+		}
+	}
+}
+
+// TestAzureTransportURLCommands tests the Azure CLI commands that the Azure
+// transport creates for Azure URLs.
+func TestAzureTransportURLCommands(t *testing.T) {
+	// Install an Azure CLI stand-in.
+	if runtime.GOOS == "windows" {
+		t.Skip()
+	}
+	directory := t.TempDir()
+	if err := os.WriteFile(filepath.Join(directory, "az"), []byte("#!/bin/sh\n"), 0700); err != nil {
+		t.Fatal("unable to create fake Azure CLI:", err)
+	}
+	t.Setenv("MUTAGEN_AZURE_PATH", directory)
+
+	// Compute the OpenSSH flags that should precede the command.
+	sshFlags := append(
+		[]string{"--", ssh.ConnectTimeoutFlag(connectTimeoutSeconds)},
+		ssh.ServerAliveFlags(serverAliveIntervalSeconds, serverAliveCountMax)...,
+	)
+
+	// Set up test cases.
+	testCases := []struct {
+		// raw is the raw URL.
+		raw string
+		// kind is the URL kind.
+		kind url.Kind
+		// environment is the environment to use when parsing the URL.
+		environment map[string]string
+		// expectedArguments are the expected Azure CLI arguments preceding the
+		// OpenSSH flags.
+		expectedArguments []string
+		// expectedVariables are the expected Azure CLI environment variables.
+		expectedVariables []string
+	}{
+		{
+			raw:               "azure://vm/~/project",
+			kind:              url.Kind_Synchronization,
+			expectedArguments: []string{"ssh", "vm", "--name", "vm"},
+		},
+		{
+			raw:               "azure://azureuser@vm/~/project",
+			kind:              url.Kind_Synchronization,
+			environment:       map[string]string{"AZURE_DEFAULTS_GROUP": "resources"},
+			expectedArguments: []string{"ssh", "vm", "--name", "vm", "--local-user", "azureuser"},
+			expectedVariables: []string{"AZURE_DEFAULTS_GROUP=resources"},
+		},
+		{
+			raw:  "azure://vm/~/project",
+			kind: url.Kind_Synchronization,
+			environment: map[string]string{
+				"AZURE_DEFAULTS_GROUP":               "resources",
+				"MUTAGEN_ALPHA_AZURE_DEFAULTS_GROUP": "other-resources",
+				"AZURE_CONFIG_DIR":                   "/azure",
+			},
+			expectedArguments: []string{"ssh", "vm", "--name", "vm"},
+			expectedVariables: []string{
+				"AZURE_CONFIG_DIR=/azure",
+				"AZURE_DEFAULTS_GROUP=other-resources",
+			},
+		},
+		{
+			raw:               "azure://azureuser@vm:tcp:localhost:8080",
+			kind:              url.Kind_Forwarding,
+			expectedArguments: []string{"ssh", "vm", "--name", "vm", "--local-user", "azureuser"},
+		},
+	}
+
+	// Process test cases.
+	for i, testCase := range testCases {
+		// Set up the environment and parse the URL.
+		for _, variable := range append(url.AzureEnvironmentVariables, "MUTAGEN_ALPHA_AZURE_DEFAULTS_GROUP") {
+			t.Setenv(variable, "")
+			os.Unsetenv(variable)
+		}
+		for variable, value := range testCase.environment {
+			t.Setenv(variable, value)
+		}
+		target, err := url.Parse(testCase.raw, testCase.kind, true)
+		if err != nil {
+			t.Fatalf("test index %d: unable to parse URL: %v", i, err)
+		}
+
+		// Create the transport in the same manner as the protocol handlers.
+		transport, err := NewAzureTransport(target.User, target.Host, target.Environment, "")
+		if err != nil {
+			t.Fatalf("test index %d: unable to create transport: %v", i, err)
+		}
+
+		// Verify command construction.
+		command, err := transport.Command("mutagen-agent synchronizer")
+		if err != nil {
+			t.Fatalf("test index %d: unable to create command: %v", i, err)
+		}
+		expectedArguments := append(append(testCase.expectedArguments, sshFlags...), "mutagen-agent synchronizer")
+		if !reflect.DeepEqual(command.Args[1:], expectedArguments) {
+			t.Errorf("test index %d: command arguments do not match expected: %v != %v",
+				i, command.Args[1:], expectedArguments,
+			)
+		}
+		var variables []string
+		for _, variable := range command.Env {
+			if strings.HasPrefix(variable, "AZURE_") {
+				variables = append(variables, variable)
+			}
+		}
+		sort.Strings(variables)
+		if !reflect.DeepEqual(variables, testCase.expectedVariables) {
+			t.Errorf("test index %d: Azure CLI variables do not match expected: %v != %v",
+				i, variables, testCase.expectedVariables,
+			)
+		}
+	}
+}
+
+// fakeAzureCLI is an Azure CLI stand-in that logs its arguments to the file
+// specified by the FAKE_AZURE_LOG environment variable. Its ssh config command
+// writes an OpenSSH configuration for the host alias specified by the
+// FAKE_AZURE_HOST environment variable, failing if the alias is empty.
+const fakeAzureCLI = `#!/bin/sh
+echo "$*" >> "$FAKE_AZURE_LOG"
+if [ "$1 $2" != "ssh config" ]; then
+	exit 1
+fi
+if [ -z "$FAKE_AZURE_HOST" ]; then
+	echo "ERROR: (ResourceNotFound) The Resource was not found." >&2
+	exit 1
+fi
+while [ $# -gt 0 ]; do
+	if [ "$1" = "--file" ]; then
+		printf "Host %s\n\tUser azureuser\n" "$FAKE_AZURE_HOST" > "$2"
+	fi
+	shift
+done
+`
+
+// fakeSCP is an scp stand-in that logs its arguments to the file specified by
+// the FAKE_SCP_LOG environment variable.
+const fakeSCP = `#!/bin/sh
+echo "$*" > "$FAKE_SCP_LOG"
+`
+
+// TestAzureTransportCopy tests the Azure CLI and SCP invocations performed by
+// the Azure transport's Copy method.
+func TestAzureTransportCopy(t *testing.T) {
+	// Install Azure CLI and SCP stand-ins.
+	if runtime.GOOS == "windows" {
+		t.Skip()
+	}
+	directory := t.TempDir()
+	if err := os.WriteFile(filepath.Join(directory, "az"), []byte(fakeAzureCLI), 0700); err != nil {
+		t.Fatal("unable to create fake Azure CLI:", err)
+	} else if err := os.WriteFile(filepath.Join(directory, "scp"), []byte(fakeSCP), 0700); err != nil {
+		t.Fatal("unable to create fake SCP:", err)
+	}
+	t.Setenv("MUTAGEN_AZURE_PATH", directory)
+	t.Setenv("MUTAGEN_SSH_PATH", directory)
+
+	// Set up test cases.
+	testCases := []struct {
+		// user is the local user.
+		user string
+		// host is the host alias to generate in the OpenSSH configuration. If
+		// empty, configuration generation fails.
+		host string
+		// expectedConfigArguments are the expected leading arguments for the
+		// configuration generation command, which are followed by paths in a
+		// temporary directory.
+		expectedConfigArguments []string
+		// expectedTrailingArguments are the expected trailing arguments for the
+		// configuration generation command.
+		expectedTrailingArguments []string
+		// expectedError is a substring of the expected error, if any.
+		expectedError string
+	}{
+		{
+			host:                    "resources-vm",
+			expectedConfigArguments: []string{"ssh", "config", "--name", "vm"},
+		},
+		{
+			user:                      "azureuser",
+			host:                      "resources-vm",
+			expectedConfigArguments:   []string{"ssh", "config", "--name", "vm"},
+			expectedTrailingArguments: []string{"--local-user", "azureuser"},
+		},
+		{
+			expectedConfigArguments: []string{"ssh", "config", "--name", "vm"},
+			expectedError:           "ResourceNotFound",
+		},
+	}
+
+	// Process test cases.
+	for i, testCase := range testCases {
+		// Set up the stand-in behavior.
+		logs := t.TempDir()
+		azureLog := filepath.Join(logs, "az")
+		scpLog := filepath.Join(logs, "scp")
+		t.Setenv("FAKE_AZURE_LOG", azureLog)
+		t.Setenv("FAKE_AZURE_HOST", testCase.host)
+		t.Setenv("FAKE_SCP_LOG", scpLog)
+
+		// Perform the copy.
+		transport, err := NewAzureTransport(testCase.user, "vm", nil, "")
+		if err != nil {
+			t.Fatalf("test index %d: unable to create transport: %v", i, err)
+		}
+		err = transport.Copy(filepath.Join(t.TempDir(), "mutagen-agent"), ".mutagen-agent")
+		if testCase.expectedError == "" && err != nil {
+			t.Fatalf("test index %d: copy failed: %v", i, err)
+		} else if testCase.expectedError != "" {
+			if err == nil {
+				t.Errorf("test index %d: copy succeeded unexpectedly", i)
+			} else if !strings.Contains(err.Error(), testCase.expectedError) {
+				t.Errorf("test index %d: error does not match expected: %v", i, err)
+			}
+		}
+
+		// Verify the configuration generation invocation.
+		contents, err := os.ReadFile(azureLog)
+		if err != nil {
+			t.Fatalf("test index %d: unable to read Azure CLI log: %v", i, err)
+		}
+		arguments := strings.Fields(string(contents))
+		leading := len(testCase.expectedConfigArguments)
+		trailing := len(testCase.expectedTrailingArguments)
+		if len(arguments) != leading+4+trailing {
+			t.Fatalf("test index %d: unexpected configuration arguments: %v", i, arguments)
+		}
+		if !reflect.DeepEqual(arguments[:leading], testCase.expectedConfigArguments) {
+			t.Errorf("test index %d: leading configuration arguments do not match expected: %v", i, arguments)
+		} else if arguments[leading] != "--file" || arguments[leading+2] != "--keys-destination-folder" {
+			t.Errorf("test index %d: configuration paths not specified: %v", i, arguments)
+		} else if filepath.Dir(arguments[leading+1]) != filepath.Dir(arguments[leading+3]) {
+			t.Errorf("test index %d: configuration paths in different directories: %v", i, arguments)
+		} else if trailing > 0 && !reflect.DeepEqual(arguments[leading+4:], testCase.expectedTrailingArguments) {
+			t.Errorf("test index %d: trailing configuration arguments do not match expected: %v", i, arguments)
+		}
+		configurationPath := arguments[leading+1]
+
+		// Verify the SCP invocation.
+		contents, err = os.ReadFile(scpLog)
+		if testCase.expectedError != "" {
+			if err == nil {
+				t.Errorf("test index %d: SCP invoked after failed configuration generation", i)
+			}
+			continue
+		} else if err != nil {
+			t.Fatalf("test index %d: unable to read SCP log: %v", i, err)
+		}
+		expectedSCPArguments := append(
+			[]string{"-F", configurationPath, ssh.CompressionFlag(), ssh.ConnectTimeoutFlag(connectTimeoutSeconds)},
+			ssh.ServerAliveFlags(serverAliveIntervalSeconds, serverAliveCountMax)...,
+		)
+		expectedSCPArguments = append(expectedSCPArguments, "mutagen-agent", testCase.host+":.mutagen-agent")
+		if arguments := strings.Fields(string(contents)); !reflect.DeepEqual(arguments, expectedSCPArguments) {
+			t.Errorf("test index %d: SCP arguments do not match expected: %v != %v",
+				i, arguments, expectedSCPArguments,
+			)
+		}
+	}
+}
+
+// TestAzureTransportClassifyError tests Azure transport error classification.
+func TestAzureTransportClassifyError(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip()
+	}
+
+	// Set up test cases.
+	testCases := []struct {
+		// exitCode is the process exit code.
+		exitCode int
+		// errorOutput is the process error output.
+		errorOutput string
+		// expectedTryInstall is the expected installation recommendation.
+		expectedTryInstall bool
+		// expectedCmdExe is the expected cmd.exe detection result.
+		expectedCmdExe bool
+		// expectFailure indicates whether or not classification should fail.
+		expectFailure bool
+	}{
+		{126, "sh: .mutagen/agents/mutagen-agent: cannot execute binary file", true, false, false},
+		{127, "sh: .mutagen/agents/mutagen-agent: not found", true, false, false},
+		{1, "'.mutagen' is not recognized as an internal or external command,", false, true, false},
+		{1, "The system cannot find the path specified.", true, true, false},
+		{255, "ssh: connect to host 10.0.0.4 port 22: Connection timed out", false, false, true},
+		{1, "ERROR: (AuthorizationFailed) The client does not have authorization", false, false, true},
+	}
+
+	// Process test cases.
+	transport, err := NewAzureTransport("", "vm", nil, "")
+	if err != nil {
+		t.Fatal("unable to create transport:", err)
+	}
+	for i, testCase := range testCases {
+		process := exec.Command("/bin/sh", "-c", fmt.Sprintf("exit %d", testCase.exitCode))
+		if err := process.Run(); err == nil {
+			t.Fatalf("test index %d: process succeeded unexpectedly", i)
+		}
+		tryInstall, cmdExe, err := transport.ClassifyError(process.ProcessState, testCase.errorOutput)
+		if err != nil {
+			if !testCase.expectFailure {
+				t.Errorf("test index %d: unexpected classification failure: %v", i, err)
+			}
+		} else if testCase.expectFailure {
+			t.Errorf("test index %d: classification succeeded unexpectedly", i)
+		} else if tryInstall != testCase.expectedTryInstall || cmdExe != testCase.expectedCmdExe {
+			t.Errorf("test index %d: classification does not match expected: (%t, %t) != (%t, %t)",
+				i, tryInstall, cmdExe, testCase.expectedTryInstall, testCase.expectedCmdExe,
+			)
+		}
+	}
+}
@@ -0,0 +1,134 @@
+package ssh
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+
+	"github.com/mutagen-io/mutagen/pkg/agent"
+	"github.com/mutagen-io/mutagen/pkg/agent/transport"
+	"github.com/mutagen-io/mutagen/pkg/ssh/native"
+)
+
+// nativeTransport implements the agent.Transport interface using the native Go
+// SSH client. Rather than running the client in-process, it invokes the current
+// (mutagen) executable's hidden native SSH helper command, which allows it to
+// provide the process-based semantics required by agent.Transport.
+type nativeTransport struct {
+	// user is the SSH user under which agents should be invoked.
+	user string
+	// host is the target host.
+	host string
+	// port is the target port.
+	port uint16
+	// forwardAgent indicates whether or not SSH agent forwarding should be
+	// enabled for commands.
+	forwardAgent bool
+	// prompter is the prompter identifier to use for prompting.
+	prompter string
+}
+
+// NewNativeTransport creates a new SSH transport that uses the native Go SSH
+// client instead of an installed OpenSSH client.
+func NewNativeTransport(user, host string, port uint16, forwardAgent bool, prompter string) (agent.Transport, error) {
+	return &nativeTransport{
+		user:         user,
+		host:         host,
+		port:         port,
+		forwardAgent: forwardAgent,
+		prompter:     prompter,
+	}, nil
+}
+
+// helperCommand creates a command that invokes the native SSH helper with the
+// specified subcommand, additional flags, and arguments.
+func (t *nativeTransport) helperCommand(subcommand string, flags []string, arguments ...string) (*exec.Cmd, error) {
+	// Compute the path to the current (mutagen) executable.
+	mutagenPath, err := os.Executable()
+	if err != nil {
+		return nil, fmt.Errorf("unable to determine executable path: %w", err)
+	}
+
+	// Set up arguments.
+	helperArguments := []string{
+		native.HelperCommandName,
+		subcommand,
+		fmt.Sprintf("--%s=%s", native.HelperFlagHost, t.host),
+		fmt.Sprintf("--%s=%d", native.HelperFlagConnectTimeout, connectTimeoutSeconds),
+		fmt.Sprintf("--%s=%d", native.HelperFlagKeepaliveInterval, serverAliveIntervalSeconds*serverAliveCountMax),
+	}
+	if t.user != "" {
+		helperArguments = append(helperArguments, fmt.Sprintf("--%s=%s", native.HelperFlagUser, t.user))
+	}
+	if t.port != 0 {
+		helperArguments = append(helperArguments, fmt.Sprintf("--%s=%d", native.HelperFlagPort, t.port))
+	}
+	if t.prompter != "" {
+		helperArguments = append(helperArguments, fmt.Sprintf("--%s=%s", native.HelperFlagPrompter, t.prompter))
+	}
+	helperArguments = append(helperArguments, flags...)
+	helperArguments = append(helperArguments, "--")
+	helperArguments = append(helperArguments, arguments...)
+
+	// Create the process.
+	helper := exec.Command(mutagenPath, helperArguments...)
+
+	// Force it to run detached.
+	helper.SysProcAttr = transport.ProcessAttributes()
+
+	// Set the environment. We add locale variables for consistency with the
+	// OpenSSH-based transport, though the native client doesn't forward them.
+	helper.Env = addLocaleVariables(os.Environ())
+
+	// Done.
+	return helper, nil
+}
+
+// Copy implements the Copy method of agent.Transport.
+func (t *nativeTransport) Copy(localPath, remoteName string) error {
+	// Validate the local path. This is guaranteed by the Transport interface,
+	// but the helper command runs with a different working directory, so it's
+	// worth enforcing as an invariant.
+	if !filepath.IsAbs(localPath) {
+		return errors.New("copy source path must be absolute")
+	}
+
+	// Create the process.
+	helper, err := t.helperCommand(native.HelperCopyCommandName, nil, localPath, remoteName)
+	if err != nil {
+		return fmt.Errorf("unable to set up copy invocation: %w", err)
+	}
+
+	// Run the operation.
+	if output, err := helper.CombinedOutput(); err != nil {
+		if len(output) > 0 {
+			return fmt.Errorf("unable to run copy process: %w (%s)", err, output)
+		}
+		return fmt.Errorf("unable to run copy process: %w", err)
+	}
+
+	// Success.
+	return nil
+}
+
+// Command implements the Command method of agent.Transport.
+func (t *nativeTransport) Command(command string) (*exec.Cmd, error) {
+	var flags []string
+	if t.forwardAgent {
+		flags = append(flags, "--"+native.HelperFlagForwardAgent)
+	}
+	helper, err := t.helperCommand(native.HelperRunCommandName, flags, command)
+	if err != nil {
+		return nil, fmt.Errorf("unable to set up SSH invocation: %w", err)
+	}
+	return helper, nil
+}
+
+// ClassifyError implements the ClassifyError method of agent.Transport.
+func (t *nativeTransport) ClassifyError(processState *os.ProcessState, errorOutput string) (bool, bool, error) {
+	// The helper faithfully returns remote exit codes and error output, so we
+	// can use the same classification as the OpenSSH-based transport.
+	return classifyError(processState, errorOutput)
+}
@@ -0,0 +1,336 @@
+package ssh
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/mutagen-io/mutagen/pkg/agent"
+	"github.com/mutagen-io/mutagen/pkg/agent/transport"
+	"github.com/mutagen-io/mutagen/pkg/environment"
+	"github.com/mutagen-io/mutagen/pkg/prompting"
+	"github.com/mutagen-io/mutagen/pkg/teleport"
+	"github.com/mutagen-io/mutagen/pkg/url"
+)
+
+// loginPromptDelay is the period of output inactivity after which a partial
+// line of login output is treated as a prompt. It's also the period for which
+// output continues to be relayed after the login process exits.
+const loginPromptDelay = 250 * time.Millisecond
+
+// teleportTransport implements the agent.Transport interface using the
+// Teleport client (tsh).
+type teleportTransport struct {
+	// user is the login on the node under which agents should be invoked. If
+	// empty, tsh's default login is used.
+	user string
+	// node is the target node.
+	node string
+	// environment is the collection of environment variables that need to be
+	// set for tsh.
+	environment map[string]string
+	// prompter is the prompter identifier to use for prompting.
+	prompter string
+	// authenticated indicates whether or not a valid Teleport session has been
+	// confirmed.
+	authenticated bool
+}
+
+// NewTeleportTransport creates a new SSH transport that reaches nodes managed by
+// Teleport using tsh. If tsh's session has expired, then the transport will
+// perform a login, relaying any prompts and messages (such as single sign-on
+// or headless authentication URLs) using the specified prompter.
+func NewTeleportTransport(user, node string, environment map[string]string, prompter string) (agent.Transport, error) {
+	return &teleportTransport{
+		user:        user,
+		node:        node,
+		environment: environment,
+		prompter:    prompter,
+	}, nil
+}
+
+// setTeleportVariables updates a base environment specification by setting
+// Teleport environment variables to match those from a Teleport URL. Any known
+// Teleport environment variables that aren't present in the URL's variables are
+// filtered from the environment.
+func setTeleportVariables(base []string, variables map[string]string) []string {
+	// Convert the base environment to a map for easier manipulation.
+	result := environment.ToMap(base)
+
+	// Populate Teleport environment variables. If a given variable wasn't
+	// stored in the URL, then remove it from the environment.
+	for _, variable := range url.TeleportEnvironmentVariables {
+		if value, ok := variables[variable]; ok {
+			result[variable] = value
+		} else {
+			delete(result, variable)
+		}
+	}
+
+	// Done.
+	return environment.FromMap(result)
+}
+
+// relayLoginPrompts relays output from an interactive login process using the
+// specified prompter. Complete lines of output are relayed as messages, while
+// partial lines that end with a colon and aren't followed by further output
+// within loginPromptDelay are treated as prompts, with responses written to the
+// provided input. It returns once the output is exhausted. If it returns early
+// due to an error, then it will continue to drain output in the background.
+func relayLoginPrompts(prompter string, output io.Reader, input io.Writer) error {
+	// Read output in a background Goroutine so that we can detect inactivity.
+	chunks := make(chan []byte)
+	var readErr error
+	go func() {
+		for {
+			buffer := make([]byte, 4096)
+			n, err := output.Read(buffer)
+			if n > 0 {
+				chunks <- buffer[:n]
+			}
+			if err != nil {
+				readErr = err
+				close(chunks)
+				return
+			}
+		}
+	}()
+
+	// Ensure that output continues to be drained if we return early.
+	defer func() {
+		go func() {
+			for range chunks {
+			}
+		}()
+	}()
+
+	// Relay output.
+	var pending string
+	for {
+		// If the remaining partial line looks like a prompt, then start a timer
+		// to detect when output stops.
+		var timeout <-chan time.Time
+		if strings.HasSuffix(strings.TrimSpace(pending), ":") {
+			timeout = time.After(loginPromptDelay)
+		}
+
+		// Wait for output or for the prompt timeout.
+		select {
+		case chunk, ok := <-chunks:
+			// Handle output termination.
+			if !ok {
+				if readErr != io.EOF {
+					return fmt.Errorf("unable to read output: %w", readErr)
+				}
+				if line := strings.TrimSpace(pending); line != "" {
+					if err := prompting.Message(prompter, line); err != nil {
+						return fmt.Errorf("unable to relay message: %w", err)
+					}
+				}
+				return nil
+			}
+
+			// Relay any complete lines as messages. Terminals may terminate
+			// lines with carriage returns, so we trim those as well.
+			pending += string(chunk)
+			for {
+				newline := strings.IndexByte(pending, '\n')
+				if newline == -1 {
+					break
+				}
+				if line := strings.TrimSpace(pending[:newline]); line != "" {
+					if err := prompting.Message(prompter, line); err != nil {
+						return fmt.Errorf("unable to relay message: %w", err)
+					}
+				}
+				pending = pending[newline+1:]
+			}
+		case <-timeout:
+			// Relay the prompt and forward the response.
+			response, err := prompting.Prompt(prompter, pending)
+			if err != nil {
+				return fmt.Errorf("unable to relay prompt: %w", err)
+			}
+			if _, err := io.WriteString(input, response+"\n"); err != nil {
+				return fmt.Errorf("unable to forward response: %w", err)
+			}
+			pending = ""
+		}
+	}
+}
+
+// tshCommand creates a tsh command with the specified arguments.
+func (t *teleportTransport) tshCommand(arguments ...string) (*exec.Cmd, error) {
+	// Create the process.
+	command, err := teleport.Command(context.Background(), arguments...)
+	if err != nil {
+		return nil, err
+	}
+
+	// Force it to run detached.
+	command.SysProcAttr = transport.ProcessAttributes()
+
+	// Set the environment.
+	command.Env = setTeleportVariables(os.Environ(), t.environment)
+
+	// Done.
+	return command, nil
+}
+
+// login performs a tsh login, relaying prompts and messages using the
+// transport's prompter. Since tsh reads credentials directly from its
+// terminal, the login process is attached to a pseudo-terminal on platforms
+// that support it.
+func (t *teleportTransport) login() error {
+	// Create the login process and attach its standard streams.
+	login, err := t.tshCommand("login")
+	if err != nil {
+		return fmt.Errorf("unable to set up tsh invocation: %w", err)
+	}
+	streams, started, err := attachLoginStreams(login)
+	if err != nil {
+		return fmt.Errorf("unable to set up login streams: %w", err)
+	}
+
+	// Start the login process.
+	err = login.Start()
+	started()
+	if err != nil {
+		streams.Close()
+		return fmt.Errorf("unable to start login process: %w", err)
+	}
+
+	// Wait for the login process to exit in a background Goroutine. Processes
+	// spawned by tsh (e.g. web browsers for single sign-on) may inherit its
+	// streams, so we can't rely on output being exhausted when tsh exits.
+	// Instead, we close our end of the streams shortly after exit.
+	exited := make(chan error, 1)
+	go func() {
+		err := login.Wait()
+		time.Sleep(loginPromptDelay)
+		streams.Close()
+		exited <- err
+	}()
+
+	// Relay prompts until the login process exits. If relaying fails, then
+	// terminate the login process.
+	if err := relayLoginPrompts(t.prompter, streams, streams); err != nil {
+		login.Process.Kill()
+		<-exited
+		return err
+	}
+
+	// Check the login result.
+	if err := <-exited; err != nil {
+		return fmt.Errorf("login process failed: %w", err)
+	}
+
+	// Success.
+	return nil
+}
+
+// ensureAuthenticated verifies that tsh has a valid session, performing a login
+// if necessary.
+func (t *teleportTransport) ensureAuthenticated() error {
+	// If we've already verified authentication, then we're done.
+	if t.authenticated {
+		return nil
+	}
+
+	// Check whether or not the current session is valid. tsh status will exit
+	// with a non-zero exit code if there's no active session or if the session
+	// has expired. Any other failure indicates that tsh couldn't be run at all,
+	// in which case a login won't help.
+	status, err := t.tshCommand("status")
+	if err != nil {
+		return fmt.Errorf("unable to set up tsh invocation: %w", err)
+	} else if err = status.Run(); err == nil {
+		t.authenticated = true
+		return nil
+	} else if _, ok := err.(*exec.ExitError); !ok {
+		return fmt.Errorf("unable to run tsh status: %w", err)
+	}
+
+	// Perform a login.
+	if err := t.login(); err != nil {
+		return fmt.Errorf("unable to perform Teleport login: %w", err)
+	}
+
+	// Success.
+	t.authenticated = true
+	return nil
+}
+
+// target computes the tsh target specification for the node.
+func (t *teleportTransport) target() string {
+	if t.user != "" {
+		return fmt.Sprintf("%s@%s", t.user, t.node)
+	}
+	return t.node
+}
+
+// Copy implements the Copy method of agent.Transport.
+func (t *teleportTransport) Copy(localPath, remoteName string) error {
+	// Ensure that we have a valid Teleport session.
+	if err := t.ensureAuthenticated(); err != nil {
+		return err
+	}
+
+	// As with the standard SSH transport, we run the copy in the source
+	// directory to avoid path format issues on Windows and rely on the default
+	// destination directory being the user's home directory.
+	if !filepath.IsAbs(localPath) {
+		return errors.New("scp source path must be absolute")
+	}
+	workingDirectory, sourceBase := filepath.Split(localPath)
+
+	// Create the process.
+	scpCommand, err := t.tshCommand("scp", sourceBase, fmt.Sprintf("%s:%s", t.target(), remoteName))
+	if err != nil {
+		return fmt.Errorf("unable to set up tsh invocation: %w", err)
+	}
+
+	// Set the working directory.
+	scpCommand.Dir = workingDirectory
+
+	// Run the operation.
+	if output, err := scpCommand.CombinedOutput(); err != nil {
+		if len(output) > 0 {
+			return fmt.Errorf("unable to run tsh copy process: %w (%s)", err, strings.TrimSpace(string(output)))
+		}
+		return fmt.Errorf("unable to run tsh copy process: %w", err)
+	}
+
+	// Success.
+	return nil
+}
+
+// Command implements the Command method of agent.Transport.
+func (t *teleportTransport) Command(command string) (*exec.Cmd, error) {
+	// Ensure that we have a valid Teleport session.
+	if err := t.ensureAuthenticated(); err != nil {
+		return nil, err
+	}
+
+	// Create the process.
+	sshCommand, err := t.tshCommand("ssh", t.target(), command)
+	if err != nil {
+		return nil, fmt.Errorf("unable to set up tsh invocation: %w", err)
+	}
+
+	// Done.
+	return sshCommand, nil
+}
+
+// ClassifyError implements the ClassifyError method of agent.Transport.
+func (t *teleportTransport) ClassifyError(processState *os.ProcessState, errorOutput string) (bool, bool, error) {
+	// tsh propagates remote exit codes and error output, so we can use the same
+	// classification as the OpenSSH-based transport.
+	return classifyError(processState, errorOutput)
+}
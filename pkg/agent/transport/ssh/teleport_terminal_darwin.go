@@ -0,0 +1,56 @@
+package ssh
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"unsafe"
+
+	"golang.org/x/sys/unix"
+)
+
+// openPseudoTerminal opens a new pseudo-terminal with input echoing disabled,
+// returning its controlling and subordinate sides. The controlling side is
+// opened in non-blocking mode so that closing it interrupts pending reads.
+func openPseudoTerminal() (*os.File, *os.File, error) {
+	// Open the controlling side.
+	fd, err := unix.Open("/dev/ptmx", unix.O_RDWR|unix.O_NOCTTY|unix.O_CLOEXEC|unix.O_NONBLOCK, 0)
+	if err != nil {
+		return nil, nil, fmt.Errorf("unable to open pseudo-terminal: %w", err)
+	}
+	controller := os.NewFile(uintptr(fd), "/dev/ptmx")
+
+	// Grant access to and unlock the subordinate side.
+	if err := unix.IoctlSetInt(fd, unix.TIOCPTYGRANT, 0); err != nil {
+		controller.Close()
+		return nil, nil, fmt.Errorf("unable to grant pseudo-terminal access: %w", err)
+	} else if err = unix.IoctlSetInt(fd, unix.TIOCPTYUNLK, 0); err != nil {
+		controller.Close()
+		return nil, nil, fmt.Errorf("unable to unlock pseudo-terminal: %w", err)
+	}
+
+	// Determine the path to the subordinate side.
+	var name [128]byte
+	if _, _, errno := unix.Syscall(unix.SYS_IOCTL, uintptr(fd), uintptr(unix.TIOCPTYGNAME), uintptr(unsafe.Pointer(&name[0]))); errno != 0 {
+		controller.Close()
+		return nil, nil, fmt.Errorf("unable to determine pseudo-terminal name: %w", errno)
+	}
+	path := string(name[:bytes.IndexByte(name[:], 0)])
+
+	// Open the subordinate side.
+	terminal, err := os.OpenFile(path, os.O_RDWR|unix.O_NOCTTY, 0)
+	if err != nil {
+		controller.Close()
+		return nil, nil, fmt.Errorf("unable to open pseudo-terminal device: %w", err)
+	}
+
+	// Disable input echoing.
+	if err := disableEcho(terminal, unix.TIOCGETA, unix.TIOCSETA); err != nil {
+		terminal.Close()
+		controller.Close()
+		return nil, nil, fmt.Errorf("unable to disable pseudo-terminal echo: %w", err)
+	}
+
+	// Success.
+	return controller, terminal, nil
+}
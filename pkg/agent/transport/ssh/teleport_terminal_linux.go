@@ -0,0 +1,49 @@
+package ssh
+
+import (
+	"fmt"
+	"os"
+	"strconv"
+
+	"golang.org/x/sys/unix"
+)
+
+// openPseudoTerminal opens a new pseudo-terminal with input echoing disabled,
+// returning its controlling and subordinate sides. The controlling side is
+// opened in non-blocking mode so that closing it interrupts pending reads.
+func openPseudoTerminal() (*os.File, *os.File, error) {
+	// Open the controlling side.
+	fd, err := unix.Open("/dev/ptmx", unix.O_RDWR|unix.O_NOCTTY|unix.O_CLOEXEC|unix.O_NONBLOCK, 0)
+	if err != nil {
+		return nil, nil, fmt.Errorf("unable to open pseudo-terminal: %w", err)
+	}
+	controller := os.NewFile(uintptr(fd), "/dev/ptmx")
+
+	// Unlock the subordinate side and determine its path.
+	if err := unix.IoctlSetPointerInt(fd, unix.TIOCSPTLCK, 0); err != nil {
+		controller.Close()
+		return nil, nil, fmt.Errorf("unable to unlock pseudo-terminal: %w", err)
+	}
+	index, err := unix.IoctlGetUint32(fd, unix.TIOCGPTN)
+	if err != nil {
+		controller.Close()
+		return nil, nil, fmt.Errorf("unable to determine pseudo-terminal index: %w", err)
+	}
+
+	// Open the subordinate side.
+	terminal, err := os.OpenFile("/dev/pts/"+strconv.FormatUint(uint64(index), 10), os.O_RDWR|unix.O_NOCTTY, 0)
+	if err != nil {
+		controller.Close()
+		return nil, nil, fmt.Errorf("unable to open pseudo-terminal device: %w", err)
+	}
+
+	// Disable input echoing.
+	if err := disableEcho(terminal, unix.TCGETS, unix.TCSETS); err != nil {
+		terminal.Close()
+		controller.Close()
+		return nil, nil, fmt.Errorf("unable to disable pseudo-terminal echo: %w", err)
+	}
+
+	// Success.
+	return controller, terminal, nil
+}
@@ -0,0 +1,71 @@
+//go:build !linux && !darwin
+
+package ssh
+
+import (
+	"errors"
+	"io"
+	"os"
+	"os/exec"
+)
+
+// loginPipes provides access to the pipes attached to a tsh login process.
+type loginPipes struct {
+	// output is the read end of the process' output pipe.
+	output *os.File
+	// input is the write end of the process' input pipe.
+	input *os.File
+}
+
+// Read implements io.Reader.Read. Once the pipe has been closed (either
+// locally or because the process has exited), Read returns io.EOF.
+func (p *loginPipes) Read(buffer []byte) (int, error) {
+	n, err := p.output.Read(buffer)
+	if errors.Is(err, os.ErrClosed) {
+		err = io.EOF
+	}
+	return n, err
+}
+
+// Write implements io.Writer.Write.
+func (p *loginPipes) Write(buffer []byte) (int, error) {
+	return p.input.Write(buffer)
+}
+
+// Close implements io.Closer.Close.
+func (p *loginPipes) Close() error {
+	p.input.Close()
+	return p.output.Close()
+}
+
+// attachLoginStreams attaches the standard streams of a tsh login process to
+// pipes, returning the local ends of the pipes (which carry the process'
+// output and accept responses), as well as a function that should be invoked
+// once the process has been started. Pseudo-terminals aren't supported on this
+// platform, so prompts that tsh reads directly from a terminal will fail, but
+// single sign-on and headless flows (which only require relaying messages)
+// will work.
+func attachLoginStreams(command *exec.Cmd) (io.ReadWriteCloser, func(), error) {
+	// Create the pipes.
+	output, outputWriter, err := os.Pipe()
+	if err != nil {
+		return nil, nil, err
+	}
+	inputReader, input, err := os.Pipe()
+	if err != nil {
+		output.Close()
+		outputWriter.Close()
+		return nil, nil, err
+	}
+
+	// Attach the process to the pipes.
+	command.Stdin = inputReader
+	command.Stdout = outputWriter
+	command.Stderr = outputWriter
+
+	// Done.
+	return &loginPipes{output, input}, func() {
+		inputReader.Close()
+		outputWriter.Close()
+	}, nil
+}
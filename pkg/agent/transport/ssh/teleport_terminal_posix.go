@@ -0,0 +1,65 @@
+//go:build linux || darwin
+
+package ssh
+
+import (
+	"errors"
+	"io"
+	"os"
+	"os/exec"
+	"syscall"
+
+	"golang.org/x/sys/unix"
+)
+
+// loginTerminal is the controlling side of a pseudo-terminal attached to a tsh
+// login process.
+type loginTerminal struct {
+	*os.File
+}
+
+// Read implements io.Reader.Read. Once the terminal has been closed (either
+// locally or because all processes attached to it have exited), Read returns
+// io.EOF.
+func (t *loginTerminal) Read(buffer []byte) (int, error) {
+	n, err := t.File.Read(buffer)
+	if errors.Is(err, syscall.EIO) || errors.Is(err, os.ErrClosed) {
+		err = io.EOF
+	}
+	return n, err
+}
+
+// attachLoginStreams attaches the standard streams of a tsh login process to
+// a new pseudo-terminal, which also becomes the process' controlling terminal,
+// since tsh reads credentials directly from its terminal. It returns the
+// controlling side of the terminal, which carries the process' output and
+// accepts responses, as well as a function that should be invoked once the
+// process has been started.
+func attachLoginStreams(command *exec.Cmd) (io.ReadWriteCloser, func(), error) {
+	// Open the pseudo-terminal.
+	controller, terminal, err := openPseudoTerminal()
+	if err != nil {
+		return nil, nil, err
+	}
+
+	// Attach the process to the terminal.
+	command.Stdin = terminal
+	command.Stdout = terminal
+	command.Stderr = terminal
+	command.SysProcAttr = &syscall.SysProcAttr{Setsid: true, Setctty: true}
+
+	// Done.
+	return &loginTerminal{controller}, func() { terminal.Close() }, nil
+}
+
+// disableEcho disables input echoing on the specified terminal so that relayed
+// responses aren't reflected in the terminal output.
+func disableEcho(terminal *os.File, getRequest, setRequest uint) error {
+	fd := int(terminal.Fd())
+	attributes, err := unix.IoctlGetTermios(fd, getRequest)
+	if err != nil {
+		return err
+	}
+	attributes.Lflag &^= unix.ECHO
+	return unix.IoctlSetTermios(fd, setRequest, attributes)
+}
@@ -0,0 +1,427 @@
+package ssh
+
+import (
+	"bufio"
+	"io"
+	"os"
+	"path/filepath"
+	"reflect"
+	"runtime"
+	"sort"
+	"strings"
+	"testing"
+
+	"github.com/mutagen-io/mutagen/pkg/prompting"
+	"github.com/mutagen-io/mutagen/pkg/url"
+)
+
+// fakeTSH is a tsh stand-in. Its status command succeeds only if the file
+// specified by the FAKE_TSH_SESSION environment variable exists, and its login
+// command creates that file if the password "secret" is provided. The login
+// command reads the password from its terminal (if it has one) to mirror tsh's
+// behavior. If the FAKE_TSH_LOG environment variable is set, then each
+// invocation's arguments are appended to the file that it specifies.
+const fakeTSH = `#!/bin/sh
+if [ -n "$FAKE_TSH_LOG" ]; then
+	echo "$*" >> "$FAKE_TSH_LOG"
+fi
+case "$1" in
+status)
+	if [ -f "$FAKE_TSH_SESSION" ]; then
+		echo "> Profile URL: https://teleport.example.com"
+		exit 0
+	fi
+	echo "Not logged in." >&2
+	exit 1
+	;;
+login)
+	echo "Logging in to teleport.example.com"
+	printf "Enter password for Teleport user alice: "
+	if [ -t 0 ]; then
+		read -r password < /dev/tty
+	else
+		read -r password
+	fi
+	if [ "$password" != "secret" ]; then
+		echo "Invalid password." >&2
+		exit 1
+	fi
+	touch "$FAKE_TSH_SESSION"
+	echo "> Profile URL: https://teleport.example.com"
+	exit 0
+	;;
+esac
+exit 0
+`
+
+// setupFakeTSH installs fakeTSH as the tsh command, optionally with an existing
+// session.
+func setupFakeTSH(t *testing.T, loggedIn bool) {
+	t.Helper()
+	if runtime.GOOS == "windows" {
+		t.Skip()
+	}
+	directory := t.TempDir()
+	if err := os.WriteFile(filepath.Join(directory, "tsh"), []byte(fakeTSH), 0700); err != nil {
+		t.Fatal("unable to create fake tsh:", err)
+	}
+	t.Setenv("MUTAGEN_TELEPORT_PATH", directory)
+	session := filepath.Join(directory, "session")
+	t.Setenv("FAKE_TSH_SESSION", session)
+	if loggedIn {
+		if err := os.WriteFile(session, nil, 0600); err != nil {
+			t.Fatal("unable to create fake session:", err)
+		}
+	}
+}
+
+// recordingPrompter is a prompting.Prompter that records messages and prompts
+// and responds to prompts with a fixed response.
+type recordingPrompter struct {
+	// messages are the recorded messages.
+	messages []string
+	// prompts are the recorded prompts.
+	prompts []string
+	// response is the response to provide to prompts.
+	response string
+}
+
+// Message implements prompting.Prompter.Message.
+func (p *recordingPrompter) Message(message string) error {
+	p.messages = append(p.messages, message)
+	return nil
+}
+
+// Prompt implements prompting.Prompter.Prompt.
+func (p *recordingPrompter) Prompt(prompt string) (string, error) {
+	p.prompts = append(p.prompts, prompt)
+	return p.response, nil
+}
+
+// registerRecordingPrompter registers a recordingPrompter with the specified
+// response, unregistering it when the test completes.
+func registerRecordingPrompter(t *testing.T, response string) (*recordingPrompter, string) {
+	t.Helper()
+	prompter := &recordingPrompter{response: response}
+	identifier, err := prompting.RegisterPrompter(prompter)
+	if err != nil {
+		t.Fatal("unable to register prompter:", err)
+	}
+	t.Cleanup(func() { prompting.UnregisterPrompter(identifier) })
+	return prompter, identifier
+}
+
+// TestRelayLoginPrompts tests relayLoginPrompts.
+func TestRelayLoginPrompts(t *testing.T) {
+	// Register a recording prompter.
+	prompter, identifier := registerRecordingPrompter(t, "secret")
+
+	// Simulate a login process that prints a message, waits for a password,
+	// and then prints a final message.
+	output, outputWriter := io.Pipe()
+	inputReader, input := io.Pipe()
+	responses := make(chan string, 1)
+	go func() {
+		io.WriteString(outputWriter, "Logging in to teleport.example.com\r\n\r\nEnter password for Teleport user alice: ")
+		response, _ := bufio.NewReader(inputReader).ReadString('\n')
+		responses <- response
+		io.WriteString(outputWriter, "\r\n> Profile URL: https://teleport.example.com\r\n")
+		outputWriter.Close()
+	}()
+
+	// Relay prompts.
+	if err := relayLoginPrompts(identifier, output, input); err != nil {
+		t.Fatal("unable to relay prompts:", err)
+	}
+
+	// Verify messages.
+	expectedMessages := []string{
+		"Logging in to teleport.example.com",
+		"> Profile URL: https://teleport.example.com",
+	}
+	if len(prompter.messages) != len(expectedMessages) {
+		t.Fatalf("unexpected message count: %d != %d", len(prompter.messages), len(expectedMessages))
+	}
+	for i, message := range prompter.messages {
+		if message != expectedMessages[i] {
+			t.Errorf("message %d does not match expected: %s != %s", i, message, expectedMessages[i])
+		}
+	}
+
+	// Verify prompts and responses.
+	if len(prompter.prompts) != 1 {
+		t.Fatal("unexpected prompt count:", len(prompter.prompts))
+	} else if prompter.prompts[0] != "Enter password for Teleport user alice: " {
+		t.Error("unexpected prompt:", prompter.prompts[0])
+	}
+	if response := <-responses; response != "secret\n" {
+		t.Error("unexpected response:", response)
+	}
+}
+
+// TestRelayLoginPromptsWithoutPrompter tests that relayLoginPrompts fails if a
+// prompt is encountered without a prompter.
+func TestRelayLoginPromptsWithoutPrompter(t *testing.T) {
+	output, outputWriter := io.Pipe()
+	defer outputWriter.Close()
+	go io.WriteString(outputWriter, "Logging in\nEnter password for Teleport user alice: ")
+	if err := relayLoginPrompts("", output, io.Discard); err == nil {
+		t.Error("prompt relay succeeded without prompter")
+	}
+}
+
+// TestTeleportTransportLogin tests that the Teleport transport performs a login
+// with relayed prompts if there's no valid tsh session.
+func TestTeleportTransportLogin(t *testing.T) {
+	// Set up tsh and a prompter.
+	setupFakeTSH(t, false)
+	prompter, identifier := registerRecordingPrompter(t, "secret")
+
+	// Create a command, which should trigger a login.
+	transport, err := NewTeleportTransport("alice", "node", nil, identifier)
+	if err != nil {
+		t.Fatal("unable to create transport:", err)
+	}
+	if _, err := transport.Command("true"); err != nil {
+		t.Fatal("unable to create command:", err)
+	}
+
+	// Verify that the password prompt was relayed and that the login output
+	// was relayed as messages.
+	if len(prompter.prompts) != 1 {
+		t.Fatal("unexpected prompt count:", len(prompter.prompts))
+	} else if !strings.HasPrefix(prompter.prompts[0], "Enter password for Teleport user alice:") {
+		t.Error("unexpected prompt:", prompter.prompts[0])
+	}
+	messages := strings.Join(prompter.messages, "\n")
+	if !strings.Contains(messages, "Logging in to teleport.example.com") {
+		t.Error("login message not relayed:", prompter.messages)
+	} else if strings.Contains(messages, "secret") {
+		t.Error("response echoed in messages:", prompter.messages)
+	}
+
+	// Verify that the session is reused without further prompting.
+	if _, err := transport.Command("true"); err != nil {
+		t.Fatal("unable to create second command:", err)
+	} else if len(prompter.prompts) != 1 {
+		t.Error("unexpected additional prompts:", prompter.prompts[1:])
+	}
+}
+
+// TestTeleportTransportEnsureAuthenticated tests how the Teleport transport
+// classifies tsh status results when deciding whether or not to log in.
+func TestTeleportTransportEnsureAuthenticated(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip()
+	}
+
+	// Set up test cases.
+	testCases := []struct {
+		// unusable indicates that tsh should be replaced by an executable that
+		// can't be run.
+		unusable bool
+		// loggedIn indicates whether or not tsh should have a valid session.
+		loggedIn bool
+		// prompter indicates whether or not a prompter should be used.
+		prompter bool
+		// response is the response to provide to prompts.
+		response string
+		// expectedPrompts is the expected number of prompts.
+		expectedPrompts int
+		// expectedError is a substring of the expected error, if any.
+		expectedError string
+	}{
+		{loggedIn: true},
+		{loggedIn: true, prompter: true, response: "secret"},
+		{prompter: true, response: "secret", expectedPrompts: 1},
+		{prompter: true, response: "incorrect", expectedPrompts: 1, expectedError: "unable to perform Teleport login"},
+		{expectedError: "unable to perform Teleport login"},
+		{unusable: true, prompter: true, response: "secret", expectedError: "unable to run tsh status"},
+	}
+
+	// Process test cases.
+	for i, testCase := range testCases {
+		// Set up tsh.
+		setupFakeTSH(t, testCase.loggedIn)
+		if testCase.unusable {
+			directory := t.TempDir()
+			if err := os.WriteFile(filepath.Join(directory, "tsh"), []byte("#!/nonexistent/interpreter\n"), 0700); err != nil {
+				t.Fatalf("test index %d: unable to create unusable tsh: %v", i, err)
+			}
+			t.Setenv("MUTAGEN_TELEPORT_PATH", directory)
+		}
+
+		// Set up the prompter.
+		var prompter *recordingPrompter
+		var identifier string
+		if testCase.prompter {
+			prompter, identifier = registerRecordingPrompter(t, testCase.response)
+		}
+
+		// Create the transport and verify authentication behavior.
+		transport := &teleportTransport{user: "alice", node: "node", prompter: identifier}
+		err := transport.ensureAuthenticated()
+		if testCase.expectedError == "" {
+			if err != nil {
+				t.Errorf("test index %d: authentication failed: %v", i, err)
+			} else if !transport.authenticated {
+				t.Errorf("test index %d: authentication not recorded", i)
+			}
+		} else if err == nil {
+			t.Errorf("test index %d: authentication succeeded unexpectedly", i)
+		} else if !strings.Contains(err.Error(), testCase.expectedError) {
+			t.Errorf("test index %d: error does not match expected: %v", i, err)
+		} else if transport.authenticated {
+			t.Errorf("test index %d: failed authentication recorded", i)
+		}
+		if prompter != nil && len(prompter.prompts) != testCase.expectedPrompts {
+			t.Errorf("test index %d: prompt count does not match expected: %d != %d",
+				i, len(prompter.prompts), testCase.expectedPrompts,
+			)
+		}
+	}
+}
+
+// TestTeleportTransportURLInvocations tests the tsh invocations that the
+// Teleport transport performs for Teleport URLs.
+func TestTeleportTransportURLInvocations(t *testing.T) {
+	// Set up test cases.
+	testCases := []struct {
+		// raw is the raw URL.
+		raw string
+		// kind is the URL kind.
+		kind url.Kind
+		// environment is the environment to use when parsing the URL.
+		environment map[string]string
+		// expectedTarget is the expected tsh target specification.
+		expectedTarget string
+		// expectedVariables are the expected Teleport environment variables.
+		expectedVariables []string
+	}{
+		{
+			raw:            "teleport://node/~/project",
+			kind:           url.Kind_Synchronization,
+			expectedTarget: "node",
+		},
+		{
+			raw:  "teleport://alice@node/~/project",
+			kind: url.Kind_Synchronization,
+			environment: map[string]string{
+				"TELEPORT_PROXY":   "teleport.example.com:443",
+				"TELEPORT_CLUSTER": "leaf",
+			},
+			expectedTarget: "alice@node",
+			expectedVariables: []string{
+				"TELEPORT_CLUSTER=leaf",
+				"TELEPORT_PROXY=teleport.example.com:443",
+			},
+		},
+		{
+			raw:  "teleport://node/~/project",
+			kind: url.Kind_Synchronization,
+			environment: map[string]string{
+				"TELEPORT_USER":                "alice",
+				"TELEPORT_LOGIN":               "root",
+				"MUTAGEN_ALPHA_TELEPORT_LOGIN": "admin",
+				"TELEPORT_HOME":                "/teleport",
+			},
+			expectedTarget: "node",
+			expectedVariables: []string{
+				"TELEPORT_HOME=/teleport",
+				"TELEPORT_LOGIN=admin",
+				"TELEPORT_USER=alice",
+			},
+		},
+		{
+			raw:  "teleport://root@node:tcp:localhost:8080",
+			kind: url.Kind_Forwarding,
+			environment: map[string]string{
+				"TELEPORT_PROXY": "teleport.example.com",
+			},
+			expectedTarget:    "root@node",
+			expectedVariables: []string{"TELEPORT_PROXY=teleport.example.com"},
+		},
+	}
+
+	// Process test cases.
+	for i, testCase := range testCases {
+		// Set up tsh with a valid session and an invocation log.
+		setupFakeTSH(t, true)
+		log := filepath.Join(t.TempDir(), "log")
+		t.Setenv("FAKE_TSH_LOG", log)
+
+		// Set up the environment and parse the URL.
+		for _, variable := range append(url.TeleportEnvironmentVariables, "MUTAGEN_ALPHA_TELEPORT_LOGIN") {
+			t.Setenv(variable, "")
+			os.Unsetenv(variable)
+		}
+		for variable, value := range testCase.environment {
+			t.Setenv(variable, value)
+		}
+		target, err := url.Parse(testCase.raw, testCase.kind, true)
+		if err != nil {
+			t.Fatalf("test index %d: unable to parse URL: %v", i, err)
+		}
+
+		// Create the transport in the same manner as the protocol handlers.
+		transport, err := NewTeleportTransport(target.User, target.Host, target.Environment, "")
+		if err != nil {
+			t.Fatalf("test index %d: unable to create transport: %v", i, err)
+		}
+
+		// Verify command construction.
+		command, err := transport.Command("mutagen-agent synchronizer")
+		if err != nil {
+			t.Fatalf("test index %d: unable to create command: %v", i, err)
+		}
+		expectedArguments := []string{"ssh", testCase.expectedTarget, "mutagen-agent synchronizer"}
+		if !reflect.DeepEqual(command.Args[1:], expectedArguments) {
+			t.Errorf("test index %d: command arguments do not match expected: %v != %v",
+				i, command.Args[1:], expectedArguments,
+			)
+		}
+		var variables []string
+		for _, variable := range command.Env {
+			if strings.HasPrefix(variable, "TELEPORT_") {
+				variables = append(variables, variable)
+			}
+		}
+		sort.Strings(variables)
+		if !reflect.DeepEqual(variables, testCase.expectedVariables) {
+			t.Errorf("test index %d: Teleport variables do not match expected: %v != %v",
+				i, variables, testCase.expectedVariables,
+			)
+		}
+
+		// Verify copy invocation. The session status should only have been
+		// checked once across both operations.
+		if err := transport.Copy(filepath.Join(t.TempDir(), "mutagen-agent"), ".mutagen-agent"); err != nil {
+			t.Fatalf("test index %d: unable to copy: %v", i, err)
+		}
+		invocations, err := os.ReadFile(log)
+		if err != nil {
+			t.Fatalf("test index %d: unable to read invocation log: %v", i, err)
+		}
+		expectedInvocations := "status\nscp mutagen-agent " + testCase.expectedTarget + ":.mutagen-agent\n"
+		if string(invocations) != expectedInvocations {
+			t.Errorf("test index %d: invocations do not match expected: %q != %q",
+				i, invocations, expectedInvocations,
+			)
+		}
+	}
+}
+
+// TestSetTeleportVariables tests that locked-in Teleport environment variables
+// override and filter the base environment.
+func TestSetTeleportVariables(t *testing.T) {
+	base := []string{"TELEPORT_PROXY=base.example.com", "TELEPORT_USER=bob", "OTHER=value"}
+	result := setTeleportVariables(base, map[string]string{"TELEPORT_PROXY": "locked.example.com"})
+	variables := strings.Join(result, "\n")
+	if !strings.Contains(variables, "TELEPORT_PROXY=locked.example.com") {
+		t.Error("locked-in variable not set:", result)
+	} else if strings.Contains(variables, "TELEPORT_USER=") {
+		t.Error("unlocked variable not filtered:", result)
+	} else if !strings.Contains(variables, "OTHER=value") {
+		t.Error("unrelated variable removed:", result)
+	}
+}
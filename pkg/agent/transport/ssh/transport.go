@@ -47,17 +47,21 @@ type sshTransport struct {
 	host string
 	// port is the target port.
 	port uint16
+	// forwardAgent indicates whether or not SSH agent forwarding should be
+	// enabled for commands.
+	forwardAgent bool
 	// prompter is the prompter identifier to use for prompting.
 	prompter string
 }
 
 // NewTransport creates a new SSH transport using the specified parameters.
-func NewTransport(user, host string, port uint16, prompter string) (agent.Transport, error) {
+func NewTransport(user, host string, port uint16, forwardAgent bool, prompter string) (agent.Transport, error) {
 	return &sshTransport{
-		user:     user,
-		host:     host,
-		port:     port,
-		prompter: prompter,
+		user:         user,
+		host:         host,
+		port:         port,
+		forwardAgent: forwardAgent,
+		prompter:     prompter,
 	}, nil
 }
 
@@ -157,6 +161,9 @@ func (t *sshTransport) Command(command string) (*exec.Cmd, error) {
 	if t.port != 0 {
 		sshArguments = append(sshArguments, "-p", fmt.Sprintf("%d", t.port))
 	}
+	if t.forwardAgent {
+		sshArguments = append(sshArguments, ssh.ForwardAgentFlag())
+	}
 	sshArguments = append(sshArguments, target, command)
 
 	// Create the process.
@@ -189,6 +196,11 @@ func (t *sshTransport) Command(command string) (*exec.Cmd, error) {
 
 // ClassifyError implements the ClassifyError method of agent.Transport.
 func (t *sshTransport) ClassifyError(processState *os.ProcessState, errorOutput string) (bool, bool, error) {
+	return classifyError(processState, errorOutput)
+}
+
+// classifyError implements error classification for SSH-based transports.
+func classifyError(processState *os.ProcessState, errorOutput string) (bool, bool, error) {
 	// SSH faithfully returns exit codes and error output, so we can use direct
 	// methods for testing and classification. Note that we may get POSIX-like
 	// error codes back even from Windows remotes, but that indicates a POSIX
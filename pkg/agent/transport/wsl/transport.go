@@ -0,0 +1,126 @@
+package wsl
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"strings"
+
+	"github.com/mutagen-io/mutagen/pkg/agent"
+	"github.com/mutagen-io/mutagen/pkg/agent/transport"
+	"github.com/mutagen-io/mutagen/pkg/process"
+	"github.com/mutagen-io/mutagen/pkg/wsl"
+)
+
+// wslTransport implements the agent.Transport interface using the Windows
+// Subsystem for Linux.
+type wslTransport struct {
+	// distribution is the target distribution name.
+	distribution string
+	// user is the distribution user under which agents should be invoked. If
+	// empty, the distribution's default user is used.
+	user string
+}
+
+// NewTransport creates a new WSL transport using the specified parameters.
+func NewTransport(distribution, user string) (agent.Transport, error) {
+	return &wslTransport{
+		distribution: distribution,
+		user:         user,
+	}, nil
+}
+
+// wslArguments computes the WSL arguments needed to execute the specified
+// command and arguments inside the distribution.
+func (t *wslTransport) wslArguments(arguments ...string) []string {
+	result := []string{"--distribution", t.distribution}
+	if t.user != "" {
+		result = append(result, "--user", t.user)
+	}
+	result = append(result, "--cd", "~", "--exec")
+	return append(result, arguments...)
+}
+
+// command creates a command that executes the specified command and arguments
+// inside the distribution. The command is executed directly (without a shell)
+// with the user's home directory as the working directory.
+func (t *wslTransport) command(arguments ...string) (*exec.Cmd, error) {
+	// Create the command.
+	command, err := wsl.Command(context.Background(), t.wslArguments(arguments...)...)
+	if err != nil {
+		return nil, err
+	}
+
+	// Set the process attributes.
+	command.SysProcAttr = transport.ProcessAttributes()
+
+	// Done.
+	return command, nil
+}
+
+// Copy implements the Copy method of agent.Transport.
+func (t *wslTransport) Copy(localPath, remoteName string) error {
+	// Validate the remote name, since we need to embed it in a shell command.
+	// It may either be a plain name or an absolute path.
+	if strings.Contains(remoteName, "'") {
+		return errors.New("invalid remote name")
+	} else if strings.Contains(remoteName, "/") && !strings.HasPrefix(remoteName, "/") {
+		return errors.New("invalid remote name")
+	}
+
+	// Open the local file and defer its closure.
+	file, err := os.Open(localPath)
+	if err != nil {
+		return fmt.Errorf("unable to open file: %w", err)
+	}
+	defer file.Close()
+
+	// Stream the file into the distribution and mark it as executable. We use
+	// this approach rather than relying on the distribution's mount of the
+	// Windows filesystem because the mount location is configurable (and may
+	// be disabled entirely).
+	script := fmt.Sprintf("cat > '%s' && chmod 755 '%s'", remoteName, remoteName)
+	command, err := t.command("sh", "-c", script)
+	if err != nil {
+		return fmt.Errorf("unable to set up WSL invocation: %w", err)
+	}
+	command.Stdin = file
+
+	// Run the operation.
+	if output, err := command.CombinedOutput(); err != nil {
+		if len(output) > 0 {
+			return fmt.Errorf("unable to run WSL copy command: %w (%s)", err, strings.TrimSpace(string(output)))
+		}
+		return fmt.Errorf("unable to run WSL copy command: %w", err)
+	}
+
+	// Success.
+	return nil
+}
+
+// Command implements the Command method of agent.Transport.
+func (t *wslTransport) Command(command string) (*exec.Cmd, error) {
+	// Lex the command that we want to run since WSL, unlike SSH, wants the
+	// commands and arguments separately instead of as a single argument. All
+	// agent.Transport interfaces only need to support commands that can be
+	// lexed by splitting on spaces.
+	return t.command(strings.Split(command, " ")...)
+}
+
+// ClassifyError implements the ClassifyError method of agent.Transport.
+func (t *wslTransport) ClassifyError(processState *os.ProcessState, errorOutput string) (bool, bool, error) {
+	// WSL distributions are always Linux-based, so the remote is never
+	// Windows. If the command isn't found, then WSL reports a failed execvpe
+	// call (with a generic exit code), though we also watch for the
+	// conventional POSIX shell exit codes.
+	if process.IsPOSIXShellCommandNotFound(processState) ||
+		process.IsPOSIXShellInvalidCommand(processState) ||
+		strings.Contains(errorOutput, "execvpe") {
+		return true, false, nil
+	}
+
+	// Otherwise, we can't classify the error.
+	return false, false, errors.New("unknown process exit error")
+}
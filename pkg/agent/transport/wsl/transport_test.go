@@ -0,0 +1,166 @@
+package wsl
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"reflect"
+	"runtime"
+	"strings"
+	"testing"
+
+	"github.com/mutagen-io/mutagen/pkg/url"
+)
+
+// TestCopyInvalidRemoteName tests that Copy rejects remote names that can't be
+// safely embedded in the copy command.
+func TestCopyInvalidRemoteName(t *testing.T) {
+	// Create a transport.
+	transport, err := NewTransport("Ubuntu", "")
+	if err != nil {
+		t.Fatal("unable to create transport:", err)
+	}
+
+	// Process test cases.
+	for i, name := range []string{"agent'name", "directory/agent"} {
+		if err := transport.Copy("agent", name); err == nil {
+			t.Errorf("test index %d: copy succeeded with invalid remote name", i)
+		} else if err.Error() != "invalid remote name" {
+			t.Errorf("test index %d: unexpected error: %v", i, err)
+		}
+	}
+}
+
+// TestTransportArguments tests the WSL arguments that the WSL transport
+// computes for agent commands targeting WSL URLs.
+func TestTransportArguments(t *testing.T) {
+	// Set up test cases.
+	testCases := []struct {
+		// raw is the raw URL.
+		raw string
+		// kind is the URL kind.
+		kind url.Kind
+		// command is the agent command.
+		command string
+		// expected are the expected WSL arguments.
+		expected []string
+	}{
+		{
+			"wsl://Ubuntu/~/project",
+			url.Kind_Synchronization,
+			"mutagen-agent synchronizer --log-level=info",
+			[]string{"--distribution", "Ubuntu", "--cd", "~", "--exec", "mutagen-agent", "synchronizer", "--log-level=info"},
+		},
+		{
+			"wsl://alice@Ubuntu-22.04/~/project",
+			url.Kind_Synchronization,
+			"uname -s -m",
+			[]string{"--distribution", "Ubuntu-22.04", "--user", "alice", "--cd", "~", "--exec", "uname", "-s", "-m"},
+		},
+		{
+			"wsl://root@Debian:tcp:localhost:8080",
+			url.Kind_Forwarding,
+			".mutagen/agents/mutagen-agent forwarder",
+			[]string{"--distribution", "Debian", "--user", "root", "--cd", "~", "--exec", ".mutagen/agents/mutagen-agent", "forwarder"},
+		},
+	}
+
+	// Process test cases.
+	for i, testCase := range testCases {
+		target, err := url.Parse(testCase.raw, testCase.kind, true)
+		if err != nil {
+			t.Fatalf("test index %d: unable to parse URL: %v", i, err)
+		}
+		transport, err := NewTransport(target.Host, target.User)
+		if err != nil {
+			t.Fatalf("test index %d: unable to create transport: %v", i, err)
+		}
+		arguments := transport.(*wslTransport).wslArguments(strings.Split(testCase.command, " ")...)
+		if !reflect.DeepEqual(arguments, testCase.expected) {
+			t.Errorf("test index %d: arguments do not match expected: %v != %v",
+				i, arguments, testCase.expected,
+			)
+		}
+	}
+}
+
+// TestTransportMissingWSL tests that the WSL transport fails cleanly if the WSL
+// command can't be found.
+func TestTransportMissingWSL(t *testing.T) {
+	// Ensure that the WSL command can't be found. On non-Windows platforms,
+	// this is always the case.
+	t.Setenv("PATH", t.TempDir())
+	t.Setenv("SystemRoot", "")
+
+	// Create a transport.
+	transport, err := NewTransport("Ubuntu", "")
+	if err != nil {
+		t.Fatal("unable to create transport:", err)
+	}
+
+	// Verify that command creation fails.
+	if _, err := transport.Command("mutagen-agent synchronizer"); err == nil {
+		t.Error("command created without WSL command")
+	} else if !strings.Contains(err.Error(), "'wsl' command") {
+		t.Error("error does not identify WSL command:", err)
+	}
+
+	// Verify that copying fails.
+	agent := filepath.Join(t.TempDir(), "mutagen-agent")
+	if err := os.WriteFile(agent, nil, 0600); err != nil {
+		t.Fatal("unable to create agent:", err)
+	}
+	if err := transport.Copy(agent, "mutagen-agent"); err == nil {
+		t.Error("copy succeeded without WSL command")
+	} else if !strings.Contains(err.Error(), "'wsl' command") {
+		t.Error("error does not identify WSL command:", err)
+	}
+}
+
+// TestTransportClassifyError tests WSL transport error classification.
+func TestTransportClassifyError(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip()
+	}
+
+	// Set up test cases.
+	testCases := []struct {
+		// exitCode is the process exit code.
+		exitCode int
+		// errorOutput is the process error output.
+		errorOutput string
+		// expectFailure indicates whether or not classification should fail.
+		expectFailure bool
+	}{
+		{1, "<3>WSL (8) ERROR: CreateProcessParseCommon:... execvpe(mutagen-agent) failed: No such file or directory", false},
+		{126, "", false},
+		{127, "", false},
+		{1, "'mutagen-agent' is not recognized as an internal or external command,", true},
+		{1, "There is no distribution with the supplied name.", true},
+	}
+
+	// Process test cases.
+	transport, err := NewTransport("Ubuntu", "")
+	if err != nil {
+		t.Fatal("unable to create transport:", err)
+	}
+	for i, testCase := range testCases {
+		process := exec.Command("/bin/sh", "-c", fmt.Sprintf("exit %d", testCase.exitCode))
+		if err := process.Run(); err == nil {
+			t.Fatalf("test index %d: process succeeded unexpectedly", i)
+		}
+		tryInstall, cmdExe, err := transport.ClassifyError(process.ProcessState, testCase.errorOutput)
+		if err != nil {
+			if !testCase.expectFailure {
+				t.Errorf("test index %d: unexpected classification failure: %v", i, err)
+			}
+		} else if testCase.expectFailure {
+			t.Errorf("test index %d: classification succeeded unexpectedly", i)
+		} else if !tryInstall {
+			t.Errorf("test index %d: installation not recommended", i)
+		} else if cmdExe {
+			t.Errorf("test index %d: cmd.exe environment detected", i)
+		}
+	}
+}
@@ -0,0 +1,307 @@
+package agent
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"time"
+
+	"github.com/mutagen-io/mutagen/pkg/filesystem"
+	"github.com/mutagen-io/mutagen/pkg/logging"
+	"github.com/mutagen-io/mutagen/pkg/mutagen"
+	"github.com/mutagen-io/mutagen/pkg/process"
+	"github.com/mutagen-io/mutagen/pkg/prompting"
+)
+
+const (
+	// UpgradeURLEnvironmentVariable is the environment variable used to
+	// specify the URL template from which remote agents should download agent
+	// executables when performing in-place upgrades. The "{version}", "{goos}",
+	// and "{goarch}" placeholders in the template are replaced with the target
+	// Mutagen version, operating system, and architecture, respectively. If the
+	// variable is unset, then in-place upgrades are disabled.
+	UpgradeURLEnvironmentVariable = "MUTAGEN_AGENT_UPGRADE_URL"
+
+	// upgradeDownloadTimeout is the maximum amount of time allowed for
+	// downloading an agent executable during an in-place upgrade.
+	upgradeDownloadTimeout = 5 * time.Minute
+	// upgradeMaximumExecutableSize is the maximum agent executable size that
+	// will be accepted during an in-place upgrade.
+	upgradeMaximumExecutableSize = 256 * 1024 * 1024
+	// upgradeTemporaryNamePrefix is the name prefix used for downloaded agent
+	// executables and backups during in-place upgrades.
+	upgradeTemporaryNamePrefix = filesystem.TemporaryNamePrefix + "agent-upgrade"
+)
+
+// expandURLTemplate expands the placeholders in an agent download URL template
+// for the current Mutagen version and the specified platform.
+func expandURLTemplate(template, goos, goarch string) string {
+	return strings.NewReplacer(
+		"{version}", mutagen.Version,
+		"{goos}", goos,
+		"{goarch}", goarch,
+	).Replace(template)
+}
+
+// copyExecutable copies an executable to a temporary file in the specified
+// directory and returns the path to the copy. The caller is responsible for
+// removing the copy if this function returns a nil error.
+func copyExecutable(source, directory string) (string, error) {
+	// Open the source.
+	input, err := os.Open(source)
+	if err != nil {
+		return "", fmt.Errorf("unable to open executable: %w", err)
+	}
+	defer input.Close()
+
+	// Create the copy.
+	output, err := os.CreateTemp(directory, process.ExecutableName(upgradeTemporaryNamePrefix+".*", runtime.GOOS))
+	if err != nil {
+		return "", fmt.Errorf("unable to create executable copy: %w", err)
+	}
+
+	// Copy the content and mark the copy as executable.
+	if _, err := io.Copy(output, input); err != nil {
+		output.Close()
+		os.Remove(output.Name())
+		return "", fmt.Errorf("unable to copy executable: %w", err)
+	} else if err = output.Close(); err != nil {
+		os.Remove(output.Name())
+		return "", fmt.Errorf("unable to close executable copy: %w", err)
+	} else if err = os.Chmod(output.Name(), 0700); err != nil {
+		os.Remove(output.Name())
+		return "", fmt.Errorf("unable to make executable copy executable: %w", err)
+	}
+
+	// Success.
+	return output.Name(), nil
+}
+
+// updateUpgrader replaces the version-independent copy of the agent used for
+// in-place upgrades with a copy of the specified agent executable.
+func updateUpgrader(source string) error {
+	// Compute the upgrader path.
+	upgrader, err := upgraderPath()
+	if err != nil {
+		return fmt.Errorf("unable to compute upgrader path: %w", err)
+	}
+
+	// Create a copy of the executable alongside the upgrader and then swap it
+	// into place. On Windows this will fail if the upgrader is running, which
+	// is fine, since the existing upgrader will continue to function.
+	temporary, err := copyExecutable(source, filepath.Dir(upgrader))
+	if err != nil {
+		return err
+	}
+	if err := filesystem.Rename(nil, temporary, nil, upgrader, true); err != nil {
+		os.Remove(temporary)
+		return fmt.Errorf("unable to relocate upgrader: %w", err)
+	}
+
+	// Success.
+	return nil
+}
+
+// download downloads the executable at the specified URL to the specified path
+// and verifies that its SHA-256 digest matches the expected digest. If
+// download or verification fails, then no file will exist at the path.
+func download(url, path string, digest []byte) error {
+	// Perform the request.
+	client := &http.Client{Timeout: upgradeDownloadTimeout}
+	response, err := client.Get(url)
+	if err != nil {
+		return fmt.Errorf("unable to perform request: %w", err)
+	}
+	defer response.Body.Close()
+	if response.StatusCode != http.StatusOK {
+		return fmt.Errorf("server returned non-OK status: %s", response.Status)
+	}
+
+	// Create the output file.
+	file, err := os.OpenFile(path, os.O_WRONLY|os.O_CREATE|os.O_EXCL, 0700)
+	if err != nil {
+		return fmt.Errorf("unable to create output file: %w", err)
+	}
+
+	// Copy the content while computing its digest, being careful to bound the
+	// amount of data that we'll accept.
+	hasher := sha256.New()
+	copied, err := io.Copy(
+		io.MultiWriter(file, hasher),
+		io.LimitReader(response.Body, upgradeMaximumExecutableSize+1),
+	)
+	if err != nil {
+		file.Close()
+		os.Remove(path)
+		return fmt.Errorf("unable to download executable: %w", err)
+	} else if copied > upgradeMaximumExecutableSize {
+		file.Close()
+		os.Remove(path)
+		return errors.New("executable too large")
+	} else if err = file.Close(); err != nil {
+		os.Remove(path)
+		return fmt.Errorf("unable to close output file: %w", err)
+	}
+
+	// Verify the digest.
+	if !bytes.Equal(hasher.Sum(nil), digest) {
+		os.Remove(path)
+		return errors.New("executable digest does not match expected")
+	}
+
+	// Success.
+	return nil
+}
+
+// verifyVersion verifies that the agent executable at the specified path runs
+// and reports the expected version.
+func verifyVersion(path, version string) error {
+	output, err := exec.Command(path, "version").Output()
+	if err != nil {
+		return fmt.Errorf("unable to invoke executable: %w", err)
+	} else if reported := strings.TrimSpace(string(output)); reported != version {
+		return fmt.Errorf("executable version (%s) does not match expected (%s)", reported, version)
+	}
+	return nil
+}
+
+// Upgrade downloads the agent executable with the specified version from the
+// specified URL, verifies it against the specified SHA-256 digest and version,
+// and then installs it to the appropriate location for an agent binary with
+// that version. If an executable already exists at that location, then it is
+// restored in the event that installation fails.
+func Upgrade(url string, digest []byte, version string) error {
+	// Validate the version, since it's used as a path component.
+	if version == "" || strings.ContainsAny(version, `/\`) || version == "." || version == ".." {
+		return errors.New("invalid version")
+	}
+
+	// Compute (and create) the installation directory.
+	parent, err := filesystem.Mutagen(true, filesystem.MutagenAgentsDirectoryName, version)
+	if err != nil {
+		return fmt.Errorf("unable to compute installation directory: %w", err)
+	}
+	destination := filepath.Join(parent, process.ExecutableName(BaseName, runtime.GOOS))
+
+	// Download and verify the executable. We place it in the installation
+	// directory to ensure that relocation won't cross devices.
+	temporary := filepath.Join(parent, process.ExecutableName(upgradeTemporaryNamePrefix, runtime.GOOS))
+	os.Remove(temporary)
+	if err := download(url, temporary, digest); err != nil {
+		return fmt.Errorf("unable to download agent: %w", err)
+	}
+	defer os.Remove(temporary)
+	if err := verifyVersion(temporary, version); err != nil {
+		return fmt.Errorf("unable to verify agent: %w", err)
+	}
+
+	// If an executable already exists at the destination, then move it aside
+	// so that it can be restored if installation fails.
+	backup := filepath.Join(parent, upgradeTemporaryNamePrefix+"-backup")
+	os.Remove(backup)
+	var haveBackup bool
+	if err := os.Rename(destination, backup); err == nil {
+		haveBackup = true
+	} else if !os.IsNotExist(err) {
+		return fmt.Errorf("unable to back up existing agent: %w", err)
+	}
+
+	// Relocate the executable, rolling back on failure.
+	if err := os.Rename(temporary, destination); err != nil {
+		if haveBackup {
+			if restoreErr := os.Rename(backup, destination); restoreErr != nil {
+				return fmt.Errorf("unable to relocate agent (%v) or restore previous agent: %w", err, restoreErr)
+			}
+		}
+		return fmt.Errorf("unable to relocate agent: %w", err)
+	}
+
+	// Remove the backup.
+	if haveBackup {
+		os.Remove(backup)
+	}
+
+	// Record the new executable for use in future in-place upgrades. Failure
+	// here isn't fatal, since the existing upgrader will remain usable.
+	updateUpgrader(destination)
+
+	// Success.
+	return nil
+}
+
+// upgrade attempts to perform an in-place upgrade of the agent on the remote by
+// instructing an existing agent installation to download, verify, and install
+// the agent executable for the current version. This avoids copying the agent
+// executable over the transport, but it requires that the remote have an agent
+// installation (in the specified data directory, if any) that supports in-place
+// upgrades, that an upgrade URL template be specified in the environment, and
+// that the remote be able to access the resulting URL.
+func upgrade(logger *logging.Logger, transport Transport, prompter, dataDirectory string) error {
+	// Check whether or not an upgrade URL template has been specified.
+	template := os.Getenv(UpgradeURLEnvironmentVariable)
+	if template == "" {
+		return errors.New("no upgrade URL specified")
+	}
+
+	// Detect the target platform.
+	goos, goarch, posix, err := probe(transport, prompter)
+	if err != nil {
+		return fmt.Errorf("unable to probe remote platform: %w", err)
+	}
+
+	// Extract the agent executable for the target platform and compute its
+	// digest. The remote will verify that the downloaded executable is
+	// identical to the one that we would have copied.
+	if err := prompting.Message(prompter, "Extracting agent..."); err != nil {
+		return fmt.Errorf("unable to message prompter: %w", err)
+	}
+	agentExecutable, err := ExecutableForPlatform(goos, goarch, "")
+	if err != nil {
+		return fmt.Errorf("unable to get agent for platform: %w", err)
+	}
+	defer os.Remove(agentExecutable)
+	agentFile, err := os.Open(agentExecutable)
+	if err != nil {
+		return fmt.Errorf("unable to open agent executable: %w", err)
+	}
+	hasher := sha256.New()
+	_, err = io.Copy(hasher, agentFile)
+	agentFile.Close()
+	if err != nil {
+		return fmt.Errorf("unable to compute agent digest: %w", err)
+	}
+
+	// Compute the download URL.
+	url := expandURLTemplate(template, goos, goarch)
+	logger.Debug("Attempting in-place agent upgrade from", url)
+
+	// Invoke the upgrade using the existing agent installation. The URL is
+	// encoded so that the command remains lexable by splitting on spaces.
+	if err := prompting.Message(prompter, "Upgrading agent..."); err != nil {
+		return fmt.Errorf("unable to message prompter: %w", err)
+	}
+	upgradeCommand := fmt.Sprintf("%s %s --%s=%s --%s=%s --%s=%s%s",
+		agentInvocationPath(dataDirectory, !posix, BaseName),
+		CommandUpgrade,
+		FlagUpgradeURL, base64.RawURLEncoding.EncodeToString([]byte(url)),
+		FlagUpgradeDigest, hex.EncodeToString(hasher.Sum(nil)),
+		FlagUpgradeVersion, mutagen.Version,
+		dataDirectoryFlag(dataDirectory),
+	)
+	if err := run(transport, upgradeCommand); err != nil {
+		return fmt.Errorf("unable to invoke agent upgrade: %w", err)
+	}
+
+	// Success.
+	return nil
+}
@@ -0,0 +1,64 @@
+package agent
+
+import (
+	"crypto/sha256"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+// TestDownload tests download.
+func TestDownload(t *testing.T) {
+	// Create a server that serves fixed content at a single path.
+	content := []byte("agent executable content")
+	server := httptest.NewServer(http.HandlerFunc(func(writer http.ResponseWriter, request *http.Request) {
+		if request.URL.Path != "/agent" {
+			http.NotFound(writer, request)
+			return
+		}
+		writer.Write(content)
+	}))
+	defer server.Close()
+
+	// Compute the expected and an incorrect digest.
+	digest := sha256.Sum256(content)
+	incorrectDigest := sha256.Sum256([]byte("other content"))
+
+	// Set up test cases.
+	testCases := []struct {
+		path        string
+		digest      []byte
+		expectError bool
+	}{
+		{"/agent", digest[:], false},
+		{"/agent", incorrectDigest[:], true},
+		{"/missing", digest[:], true},
+	}
+
+	// Process test cases.
+	for i, testCase := range testCases {
+		path := filepath.Join(t.TempDir(), "agent")
+		err := download(server.URL+testCase.path, path, testCase.digest)
+		if err != nil && !testCase.expectError {
+			t.Errorf("test index %d: unexpected error: %v", i, err)
+		} else if err == nil && testCase.expectError {
+			t.Errorf("test index %d: error expected", i)
+		}
+		if _, statErr := os.Lstat(path); testCase.expectError && !os.IsNotExist(statErr) {
+			t.Errorf("test index %d: output file exists after failed download", i)
+		} else if !testCase.expectError && statErr != nil {
+			t.Errorf("test index %d: unable to query output file: %v", i, statErr)
+		}
+	}
+}
+
+// TestUpgradeInvalidVersion tests that Upgrade rejects invalid versions.
+func TestUpgradeInvalidVersion(t *testing.T) {
+	for _, version := range []string{"", ".", "..", "0.1/../..", `0.1\0.2`} {
+		if err := Upgrade("http://localhost/agent", []byte{0}, version); err == nil {
+			t.Errorf("upgrade succeeded with invalid version: %q", version)
+		}
+	}
+}
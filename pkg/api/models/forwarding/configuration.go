@@ -1,12 +1,63 @@
 package forwarding
 
 import (
+	"github.com/mutagen-io/mutagen/pkg/api/models/types"
 	"github.com/mutagen-io/mutagen/pkg/filesystem"
 	"github.com/mutagen-io/mutagen/pkg/forwarding"
 )
 
 // Configuration represents forwarding session configuration.
 type Configuration struct {
+	// HTTP contains parameters related to HTTP forwarding sessions.
+	HTTP struct {
+		// Routes are the routing rules used to select the target for each
+		// request.
+		Routes []string `json:"routes,omitempty" yaml:"routes" mapstructure:"routes"`
+	} `json:"http" yaml:"http" mapstructure:"http"`
+	// MaximumConnections is the maximum number of connections that may be
+	// forwarded simultaneously.
+	MaximumConnections uint64 `json:"maximumConnections,omitempty" yaml:"maximumConnections" mapstructure:"maximumConnections"`
+	// HealthCheck contains parameters related to destination health checks.
+	HealthCheck struct {
+		// Interval is the interval (in seconds) at which health checks are
+		// performed.
+		Interval uint32 `json:"interval,omitempty" yaml:"interval" mapstructure:"interval"`
+		// Path is the request path used for HTTP health checks.
+		Path string `json:"path,omitempty" yaml:"path" mapstructure:"path"`
+	} `json:"healthCheck" yaml:"healthCheck" mapstructure:"healthCheck"`
+	// BindRetry contains parameters related to retrying source listener
+	// binding.
+	BindRetry struct {
+		// Interval is the interval (in seconds) to wait before retrying a
+		// failed binding attempt.
+		Interval uint32 `json:"interval,omitempty" yaml:"interval" mapstructure:"interval"`
+		// MaximumInterval is the maximum interval (in seconds) to wait between
+		// binding attempts when backing off.
+		MaximumInterval uint32 `json:"maximumInterval,omitempty" yaml:"maximumInterval" mapstructure:"maximumInterval"`
+	} `json:"bindRetry" yaml:"bindRetry" mapstructure:"bindRetry"`
+	// ReconnectHoldTimeout is the maximum amount of time (in seconds) that
+	// incoming connections are held while the destination is reconnecting.
+	ReconnectHoldTimeout uint32 `json:"reconnectHoldTimeout,omitempty" yaml:"reconnectHoldTimeout" mapstructure:"reconnectHoldTimeout"`
+	// RateLimit contains parameters related to limiting the rate of forwarded
+	// data.
+	RateLimit struct {
+		// Upload is the maximum rate (per second) at which data is forwarded
+		// from source to destination.
+		Upload types.ByteSize `json:"upload,omitempty" yaml:"upload" mapstructure:"upload"`
+		// Download is the maximum rate (per second) at which data is forwarded
+		// from destination to source.
+		Download types.ByteSize `json:"download,omitempty" yaml:"download" mapstructure:"download"`
+	} `json:"rateLimit" yaml:"rateLimit" mapstructure:"rateLimit"`
+	// MDNS contains parameters related to mDNS advertisement of the source
+	// listener.
+	MDNS struct {
+		// ServiceName is the service instance name under which the source
+		// listener is advertised.
+		ServiceName string `json:"serviceName,omitempty" yaml:"serviceName" mapstructure:"serviceName"`
+		// ServiceType is the DNS-SD service type under which the source
+		// listener is advertised.
+		ServiceType string `json:"serviceType,omitempty" yaml:"serviceType" mapstructure:"serviceType"`
+	} `json:"mdns" yaml:"mdns" mapstructure:"mdns"`
 	// Socket contains parameters related to Unix domain socket handling.
 	Socket struct {
 		// OverwriteMode specifies the default socket overwrite mode to use for
@@ -22,16 +73,83 @@ type Configuration struct {
 		// listener sockets.
 		PermissionMode filesystem.Mode `json:"permissionMode,omitempty" yaml:"permissionMode" mapstructure:"permissionMode"`
 	} `json:"socket" yaml:"socket" mapstructure:"socket"`
+	// TLS contains parameters related to TLS termination and origination.
+	TLS struct {
+		// Mode specifies whether or not TLS should be terminated (for source
+		// endpoints) or originated (for destination endpoints).
+		Mode forwarding.TLSMode `json:"mode,omitempty" yaml:"mode" mapstructure:"mode"`
+		// Certificate specifies the path to a PEM-encoded certificate to use
+		// when terminating TLS.
+		Certificate string `json:"certificate,omitempty" yaml:"certificate" mapstructure:"certificate"`
+		// Key specifies the path to the PEM-encoded private key corresponding
+		// to Certificate.
+		Key string `json:"key,omitempty" yaml:"key" mapstructure:"key"`
+		// ServerName specifies the server name to use when originating TLS.
+		ServerName string `json:"serverName,omitempty" yaml:"serverName" mapstructure:"serverName"`
+		// CertificateAuthority specifies the path to a PEM-encoded certificate
+		// authority bundle to use when originating TLS.
+		CertificateAuthority string `json:"certificateAuthority,omitempty" yaml:"certificateAuthority" mapstructure:"certificateAuthority"`
+	} `json:"tls" yaml:"tls" mapstructure:"tls"`
+	// ProxyProtocol specifies whether or not PROXY protocol headers should be
+	// consumed (for source endpoints) or emitted (for destination endpoints).
+	ProxyProtocol forwarding.ProxyProtocolMode `json:"proxyProtocol,omitempty" yaml:"proxyProtocol" mapstructure:"proxyProtocol"`
+	// AllowedSources specifies the client addresses (IP addresses or CIDR
+	// blocks) permitted to connect to TCP listeners.
+	AllowedSources []string `json:"allowedSources,omitempty" yaml:"allowedSources" mapstructure:"allowedSources"`
+	// AdditionalAddresses specifies additional addresses on which TCP
+	// listeners should accept connections.
+	AdditionalAddresses []string `json:"additionalAddresses,omitempty" yaml:"additionalAddresses" mapstructure:"additionalAddresses"`
 }
 
 // loadFromInternal sets a configuration to match an internal Protocol Buffers
 // representation. The configuration must be valid.
 func (c *Configuration) loadFromInternal(configuration *forwarding.Configuration) {
+	// Propagate HTTP configuration.
+	c.HTTP.Routes = configuration.HttpRoutes
+
+	// Propagate connection configuration.
+	c.MaximumConnections = configuration.MaximumConnections
+
+	// Propagate health check configuration.
+	c.HealthCheck.Interval = configuration.HealthCheckInterval
+	c.HealthCheck.Path = configuration.HealthCheckPath
+
+	// Propagate bind retry configuration.
+	c.BindRetry.Interval = configuration.BindRetryInterval
+	c.BindRetry.MaximumInterval = configuration.BindRetryMaximumInterval
+
+	// Propagate reconnection configuration.
+	c.ReconnectHoldTimeout = configuration.ReconnectHoldTimeout
+
+	// Propagate rate limiting configuration.
+	c.RateLimit.Upload = types.ByteSize(configuration.MaximumUploadRate)
+	c.RateLimit.Download = types.ByteSize(configuration.MaximumDownloadRate)
+
+	// Propagate mDNS configuration.
+	c.MDNS.ServiceName = configuration.MdnsServiceName
+	c.MDNS.ServiceType = configuration.MdnsServiceType
+
 	// Propagate socket configuration.
 	c.Socket.OverwriteMode = configuration.SocketOverwriteMode
 	c.Socket.Owner = configuration.SocketOwner
 	c.Socket.Group = configuration.SocketGroup
 	c.Socket.PermissionMode = filesystem.Mode(configuration.SocketPermissionMode)
+
+	// Propagate TLS configuration.
+	c.TLS.Mode = configuration.TlsMode
+	c.TLS.Certificate = configuration.TlsCertificate
+	c.TLS.Key = configuration.TlsKey
+	c.TLS.ServerName = configuration.TlsServerName
+	c.TLS.CertificateAuthority = configuration.TlsCertificateAuthority
+
+	// Propagate PROXY protocol configuration.
+	c.ProxyProtocol = configuration.ProxyProtocolMode
+
+	// Propagate access control configuration.
+	c.AllowedSources = configuration.AllowedSources
+
+	// Propagate listening configuration.
+	c.AdditionalAddresses = configuration.AdditionalAddresses
 }
 
 // ToInternal converts a public configuration representation to an internal
@@ -39,9 +157,28 @@ func (c *Configuration) loadFromInternal(configuration *forwarding.Configuration
 // configuration.
 func (c *Configuration) ToInternal() *forwarding.Configuration {
 	return &forwarding.Configuration{
-		SocketOverwriteMode:  c.Socket.OverwriteMode,
-		SocketOwner:          c.Socket.Owner,
-		SocketGroup:          c.Socket.Group,
-		SocketPermissionMode: uint32(c.Socket.PermissionMode),
+		HttpRoutes:               c.HTTP.Routes,
+		MaximumConnections:       c.MaximumConnections,
+		HealthCheckInterval:      c.HealthCheck.Interval,
+		HealthCheckPath:          c.HealthCheck.Path,
+		BindRetryInterval:        c.BindRetry.Interval,
+		BindRetryMaximumInterval: c.BindRetry.MaximumInterval,
+		ReconnectHoldTimeout:     c.ReconnectHoldTimeout,
+		MaximumUploadRate:        uint64(c.RateLimit.Upload),
+		MaximumDownloadRate:      uint64(c.RateLimit.Download),
+		MdnsServiceName:          c.MDNS.ServiceName,
+		MdnsServiceType:          c.MDNS.ServiceType,
+		SocketOverwriteMode:      c.Socket.OverwriteMode,
+		SocketOwner:              c.Socket.Owner,
+		SocketGroup:              c.Socket.Group,
+		SocketPermissionMode:     uint32(c.Socket.PermissionMode),
+		TlsMode:                  c.TLS.Mode,
+		TlsCertificate:           c.TLS.Certificate,
+		TlsKey:                   c.TLS.Key,
+		TlsServerName:            c.TLS.ServerName,
+		TlsCertificateAuthority:  c.TLS.CertificateAuthority,
+		ProxyProtocolMode:        c.ProxyProtocol,
+		AllowedSources:           c.AllowedSources,
+		AdditionalAddresses:      c.AdditionalAddresses,
 	}
 }
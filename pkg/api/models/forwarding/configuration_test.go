@@ -4,27 +4,78 @@ import (
 	"os"
 	"testing"
 
+	"github.com/mutagen-io/mutagen/pkg/comparison"
 	"github.com/mutagen-io/mutagen/pkg/encoding"
 	"github.com/mutagen-io/mutagen/pkg/forwarding"
 )
 
 const (
 	testYAMLConfiguration = `
+http:
+  routes:
+    - "api.localhost=api:3000"
+    - "/static=web:80"
+maximumConnections: 64
+healthCheck:
+  interval: 30
+  path: "/healthz"
+bindRetry:
+  interval: 1
+  maximumInterval: 30
+reconnectHoldTimeout: 60
+rateLimit:
+  upload: "1 MB"
+  download: 500000
+mdns:
+  serviceName: "Development Server"
+  serviceType: "_http._tcp"
 socket:
   overwriteMode: "overwrite"
   owner: "george"
   group: "presidents"
   permissionMode: 0600
+tls:
+  mode: "enabled"
+  certificate: "/etc/certs/server.crt"
+  key: "/etc/certs/server.key"
+  serverName: "api.internal"
+  certificateAuthority: "/etc/certs/ca.pem"
+proxyProtocol: enabled
+allowedSources:
+  - "192.168.1.0/24"
+  - "10.0.0.5"
+additionalAddresses:
+  - "[::1]:8080"
+  - "192.168.1.10:8080"
 `
 )
 
 // expectedConfiguration is the configuration that's expected based on the
 // human-readable configuration given above.
 var expectedConfiguration = &forwarding.Configuration{
-	SocketOverwriteMode:  forwarding.SocketOverwriteMode_SocketOverwriteModeOverwrite,
-	SocketOwner:          "george",
-	SocketGroup:          "presidents",
-	SocketPermissionMode: 0600,
+	HttpRoutes:               []string{"api.localhost=api:3000", "/static=web:80"},
+	MaximumConnections:       64,
+	HealthCheckInterval:      30,
+	HealthCheckPath:          "/healthz",
+	BindRetryInterval:        1,
+	BindRetryMaximumInterval: 30,
+	ReconnectHoldTimeout:     60,
+	MaximumUploadRate:        1000000,
+	MaximumDownloadRate:      500000,
+	MdnsServiceName:          "Development Server",
+	MdnsServiceType:          "_http._tcp",
+	SocketOverwriteMode:      forwarding.SocketOverwriteMode_SocketOverwriteModeOverwrite,
+	SocketOwner:              "george",
+	SocketGroup:              "presidents",
+	SocketPermissionMode:     0600,
+	TlsMode:                  forwarding.TLSMode_TLSModeEnabled,
+	TlsCertificate:           "/etc/certs/server.crt",
+	TlsKey:                   "/etc/certs/server.key",
+	TlsServerName:            "api.internal",
+	TlsCertificateAuthority:  "/etc/certs/ca.pem",
+	ProxyProtocolMode:        forwarding.ProxyProtocolMode_ProxyProtocolModeEnabled,
+	AllowedSources:           []string{"192.168.1.0/24", "10.0.0.5"},
+	AdditionalAddresses:      []string{"[::1]:8080", "192.168.1.10:8080"},
 }
 
 // TestLoadConfiguration tests loading a YAML-based session configuration.
@@ -55,6 +106,39 @@ func TestLoadConfiguration(t *testing.T) {
 	}
 
 	// Verify that the configuration matches what's expected.
+	if !comparison.StringSlicesEqual(configuration.HttpRoutes, expectedConfiguration.HttpRoutes) {
+		t.Error("HTTP routes mismatch:", configuration.HttpRoutes, "!=", expectedConfiguration.HttpRoutes)
+	}
+	if configuration.MaximumConnections != expectedConfiguration.MaximumConnections {
+		t.Error("maximum connections mismatch:", configuration.MaximumConnections, "!=", expectedConfiguration.MaximumConnections)
+	}
+	if configuration.HealthCheckInterval != expectedConfiguration.HealthCheckInterval {
+		t.Error("health check interval mismatch:", configuration.HealthCheckInterval, "!=", expectedConfiguration.HealthCheckInterval)
+	}
+	if configuration.HealthCheckPath != expectedConfiguration.HealthCheckPath {
+		t.Error("health check path mismatch:", configuration.HealthCheckPath, "!=", expectedConfiguration.HealthCheckPath)
+	}
+	if configuration.BindRetryInterval != expectedConfiguration.BindRetryInterval {
+		t.Error("bind retry interval mismatch:", configuration.BindRetryInterval, "!=", expectedConfiguration.BindRetryInterval)
+	}
+	if configuration.BindRetryMaximumInterval != expectedConfiguration.BindRetryMaximumInterval {
+		t.Error("bind retry maximum interval mismatch:", configuration.BindRetryMaximumInterval, "!=", expectedConfiguration.BindRetryMaximumInterval)
+	}
+	if configuration.ReconnectHoldTimeout != expectedConfiguration.ReconnectHoldTimeout {
+		t.Error("reconnect hold timeout mismatch:", configuration.ReconnectHoldTimeout, "!=", expectedConfiguration.ReconnectHoldTimeout)
+	}
+	if configuration.MaximumUploadRate != expectedConfiguration.MaximumUploadRate {
+		t.Error("maximum upload rate mismatch:", configuration.MaximumUploadRate, "!=", expectedConfiguration.MaximumUploadRate)
+	}
+	if configuration.MaximumDownloadRate != expectedConfiguration.MaximumDownloadRate {
+		t.Error("maximum download rate mismatch:", configuration.MaximumDownloadRate, "!=", expectedConfiguration.MaximumDownloadRate)
+	}
+	if configuration.MdnsServiceName != expectedConfiguration.MdnsServiceName {
+		t.Error("mDNS service name mismatch:", configuration.MdnsServiceName, "!=", expectedConfiguration.MdnsServiceName)
+	}
+	if configuration.MdnsServiceType != expectedConfiguration.MdnsServiceType {
+		t.Error("mDNS service type mismatch:", configuration.MdnsServiceType, "!=", expectedConfiguration.MdnsServiceType)
+	}
 	if configuration.SocketOverwriteMode != expectedConfiguration.SocketOverwriteMode {
 		t.Error("socket overwrite mode mismatch:", configuration.SocketOverwriteMode, "!=", expectedConfiguration.SocketOverwriteMode)
 	}
@@ -67,6 +151,30 @@ func TestLoadConfiguration(t *testing.T) {
 	if configuration.SocketPermissionMode != expectedConfiguration.SocketPermissionMode {
 		t.Errorf("socket permission mode mismatch: %o != %o", configuration.SocketPermissionMode, expectedConfiguration.SocketPermissionMode)
 	}
+	if configuration.TlsMode != expectedConfiguration.TlsMode {
+		t.Error("TLS mode mismatch:", configuration.TlsMode, "!=", expectedConfiguration.TlsMode)
+	}
+	if configuration.TlsCertificate != expectedConfiguration.TlsCertificate {
+		t.Error("TLS certificate mismatch:", configuration.TlsCertificate, "!=", expectedConfiguration.TlsCertificate)
+	}
+	if configuration.TlsKey != expectedConfiguration.TlsKey {
+		t.Error("TLS key mismatch:", configuration.TlsKey, "!=", expectedConfiguration.TlsKey)
+	}
+	if configuration.TlsServerName != expectedConfiguration.TlsServerName {
+		t.Error("TLS server name mismatch:", configuration.TlsServerName, "!=", expectedConfiguration.TlsServerName)
+	}
+	if configuration.TlsCertificateAuthority != expectedConfiguration.TlsCertificateAuthority {
+		t.Error("TLS certificate authority mismatch:", configuration.TlsCertificateAuthority, "!=", expectedConfiguration.TlsCertificateAuthority)
+	}
+	if configuration.ProxyProtocolMode != expectedConfiguration.ProxyProtocolMode {
+		t.Error("PROXY protocol mismatch:", configuration.ProxyProtocolMode, "!=", expectedConfiguration.ProxyProtocolMode)
+	}
+	if !comparison.StringSlicesEqual(configuration.AllowedSources, expectedConfiguration.AllowedSources) {
+		t.Error("allowed sources mismatch:", configuration.AllowedSources, "!=", expectedConfiguration.AllowedSources)
+	}
+	if !comparison.StringSlicesEqual(configuration.AdditionalAddresses, expectedConfiguration.AdditionalAddresses) {
+		t.Error("additional addresses mismatch:", configuration.AdditionalAddresses, "!=", expectedConfiguration.AdditionalAddresses)
+	}
 }
 
 // TODO: Expand tests, including testing for invalid configurations.
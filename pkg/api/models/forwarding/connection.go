@@ -0,0 +1,54 @@
+package forwarding
+
+import (
+	"time"
+
+	"github.com/mutagen-io/mutagen/pkg/forwarding"
+)
+
+// Connection represents the state of an individual forwarded connection.
+type Connection struct {
+	// Identifier is the identifier of the connection within the session.
+	Identifier uint64 `json:"identifier"`
+	// Source is the address of the client that opened the connection.
+	Source string `json:"source"`
+	// Target is the address to which the connection is forwarded, if known.
+	Target string `json:"target,omitempty"`
+	// Opened is the time at which the connection was accepted.
+	Opened string `json:"opened"`
+	// RoundTripTime is the estimated round-trip time (in seconds) to the
+	// target, if known.
+	RoundTripTime float64 `json:"roundTripTime,omitempty"`
+	// OutboundData is the amount of data (in bytes) that has been transmitted
+	// from source to destination on the connection.
+	OutboundData uint64 `json:"outboundData"`
+	// InboundData is the amount of data (in bytes) that has been transmitted
+	// from destination to source on the connection.
+	InboundData uint64 `json:"inboundData"`
+}
+
+// newConnectionsFromInternal creates a list of connection representations from
+// internal Protocol Buffers representations.
+func newConnectionsFromInternal(connections []*forwarding.ConnectionState) []Connection {
+	// If there are no connections, then return a nil list.
+	if len(connections) == 0 {
+		return nil
+	}
+
+	// Perform conversion.
+	result := make([]Connection, len(connections))
+	for c, connection := range connections {
+		result[c] = Connection{
+			Identifier:   connection.Identifier,
+			Source:       connection.Source,
+			Target:       connection.Target,
+			Opened:       connection.Opened.AsTime().Format(time.RFC3339Nano),
+			OutboundData: connection.OutboundData,
+			InboundData:  connection.InboundData,
+		}
+		if connection.RoundTripTime != nil {
+			result[c].RoundTripTime = connection.RoundTripTime.AsDuration().Seconds()
+		}
+	}
+	return result
+}
@@ -1,6 +1,8 @@
 package forwarding
 
 import (
+	"time"
+
 	"github.com/mutagen-io/mutagen/pkg/forwarding"
 	"github.com/mutagen-io/mutagen/pkg/url"
 )
@@ -26,13 +28,41 @@ type Endpoint struct {
 	// Connected indicates whether or not the controller is currently connected
 	// to the endpoint.
 	Connected bool `json:"connected"`
+	// BindFailures is the number of consecutive failed attempts to bind the
+	// endpoint's listener.
+	BindFailures uint64 `json:"bindFailures,omitempty"`
+	// NextBindAttempt is the time at which the next attempt to bind the
+	// endpoint's listener will be made, if any.
+	NextBindAttempt string `json:"nextBindAttempt,omitempty"`
 	// EndpointState stores state fields relevant to connected endpoints. It is
 	// non-nil if and only if the endpoint is connected.
 	*EndpointState
 }
 
 // EndpointState encodes the current state of a forwarding endpoint.
-type EndpointState struct{}
+type EndpointState struct {
+	// BoundAddress is the address on which the endpoint is listening, if the
+	// endpoint is a listener.
+	BoundAddress string `json:"boundAddress,omitempty"`
+	// HealthCheckError is the error from the most recent health check, if that
+	// check failed.
+	HealthCheckError string `json:"healthCheckError,omitempty"`
+	// Listeners are the states of the individual listeners underlying the
+	// endpoint, if the endpoint listens on additional addresses.
+	Listeners []ListenerState `json:"listeners,omitempty"`
+}
+
+// ListenerState encodes the state of an individual listener underlying a
+// forwarding endpoint.
+type ListenerState struct {
+	// Address is the configured listening address.
+	Address string `json:"address"`
+	// BoundAddress is the address on which the listener is listening, if it
+	// was bound successfully.
+	BoundAddress string `json:"boundAddress,omitempty"`
+	// Error is the error that prevented binding the listener, if any.
+	Error string `json:"error,omitempty"`
+}
 
 // loadFromInternal sets an Endpoint to match internal Protocol Buffers
 // representations. All parameters must be valid.
@@ -52,10 +82,26 @@ func (e *Endpoint) loadFromInternal(url *url.URL, configuration *forwarding.Conf
 	// Propagate connectivity.
 	e.Connected = state.Connected
 
+	// Propagate binding state.
+	e.BindFailures = state.BindFailures
+	if state.NextBindAttempt != nil {
+		e.NextBindAttempt = state.NextBindAttempt.AsTime().Format(time.RFC3339Nano)
+	}
+
 	// Propagate other state fields.
 	if !e.Connected {
 		e.EndpointState = nil
 	} else {
-		e.EndpointState = &EndpointState{}
+		e.EndpointState = &EndpointState{
+			BoundAddress:     state.BoundAddress,
+			HealthCheckError: state.HealthCheckError,
+		}
+		for _, listener := range state.Listeners {
+			e.EndpointState.Listeners = append(e.EndpointState.Listeners, ListenerState{
+				Address:      listener.Address,
+				BoundAddress: listener.BoundAddress,
+				Error:        listener.Error,
+			})
+		}
 	}
 }
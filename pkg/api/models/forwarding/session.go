@@ -52,6 +52,11 @@ type SessionState struct {
 	// TotalInboundData is the total amount of data (in bytes) that has been
 	// transmitted from destination to source across all forwarded connections.
 	TotalInboundData uint64 `json:"totalInboundData"`
+	// Statistics contains forwarding statistics accumulated by the session
+	// across all connectivity cycles.
+	Statistics *SessionStatistics `json:"statistics,omitempty"`
+	// Connections are the states of currently open connections.
+	Connections []Connection `json:"connections,omitempty"`
 }
 
 // loadFromInternal sets a session to match an internal Protocol Buffers session
@@ -96,6 +101,8 @@ func (s *Session) loadFromInternal(state *forwarding.State) {
 			TotalConnections:  state.TotalConnections,
 			TotalOutboundData: state.TotalOutboundData,
 			TotalInboundData:  state.TotalInboundData,
+			Statistics:        newSessionStatisticsFromInternal(state.Statistics),
+			Connections:       newConnectionsFromInternal(state.Connections),
 		}
 	}
 }
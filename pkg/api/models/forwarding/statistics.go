@@ -0,0 +1,44 @@
+package forwarding
+
+import (
+	"time"
+
+	"github.com/mutagen-io/mutagen/pkg/forwarding"
+)
+
+// SessionStatistics represents forwarding statistics accumulated by a session
+// across all connectivity cycles.
+type SessionStatistics struct {
+	// TotalConnections is the total number of connections that have been
+	// opened and forwarded.
+	TotalConnections uint64 `json:"totalConnections"`
+	// TotalOutboundData is the total amount of data (in bytes) that has been
+	// transmitted from source to destination.
+	TotalOutboundData uint64 `json:"totalOutboundData"`
+	// TotalInboundData is the total amount of data (in bytes) that has been
+	// transmitted from destination to source.
+	TotalInboundData uint64 `json:"totalInboundData"`
+	// LastConnectionTime is the time at which the most recent connection was
+	// opened, if any.
+	LastConnectionTime string `json:"lastConnectionTime,omitempty"`
+}
+
+// newSessionStatisticsFromInternal creates a new session statistics
+// representation from an internal Protocol Buffers representation.
+func newSessionStatisticsFromInternal(statistics *forwarding.SessionStatistics) *SessionStatistics {
+	// If the statistics are nil, then return nil statistics.
+	if statistics == nil {
+		return nil
+	}
+
+	// Perform conversion.
+	result := &SessionStatistics{
+		TotalConnections:  statistics.TotalConnections,
+		TotalOutboundData: statistics.TotalOutboundData,
+		TotalInboundData:  statistics.TotalInboundData,
+	}
+	if statistics.LastConnectionTime != nil {
+		result.LastConnectionTime = statistics.LastConnectionTime.AsTime().Format(time.RFC3339Nano)
+	}
+	return result
+}
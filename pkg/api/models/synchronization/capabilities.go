@@ -0,0 +1,39 @@
+package synchronization
+
+import (
+	"github.com/mutagen-io/mutagen/pkg/synchronization"
+)
+
+// FilesystemCapabilities represents the capabilities of the filesystem on which
+// an endpoint's synchronization root resides.
+type FilesystemCapabilities struct {
+	// CaseSensitive indicates whether or not the filesystem distinguishes
+	// between names that differ only in case.
+	CaseSensitive bool `json:"caseSensitive"`
+	// SymbolicLinks indicates whether or not the filesystem supports the
+	// creation of symbolic links.
+	SymbolicLinks bool `json:"symbolicLinks"`
+	// PreservesExecutability indicates whether or not the filesystem preserves
+	// POSIX executability bits.
+	PreservesExecutability bool `json:"preservesExecutability"`
+	// ModificationTimeGranularity is the granularity (in nanoseconds) with
+	// which the filesystem records modification times.
+	ModificationTimeGranularity uint64 `json:"modificationTimeGranularity"`
+}
+
+// newFilesystemCapabilitiesFromInternal creates a new filesystem capabilities
+// representation from an internal Protocol Buffers representation.
+func newFilesystemCapabilitiesFromInternal(capabilities *synchronization.FilesystemCapabilities) *FilesystemCapabilities {
+	// If the capabilities are nil, then return nil capabilities.
+	if capabilities == nil {
+		return nil
+	}
+
+	// Perform conversion.
+	return &FilesystemCapabilities{
+		CaseSensitive:               capabilities.CaseSensitive,
+		SymbolicLinks:               capabilities.SymbolicLinks,
+		PreservesExecutability:      capabilities.PreservesExecutability,
+		ModificationTimeGranularity: capabilities.ModificationTimeGranularity,
+	}
+}
@@ -18,12 +18,35 @@ type Configuration struct {
 	// MaximumStagingFileSize is the maximum (individual) file size that
 	// endpoints will stage. It can be specified in human-friendly units.
 	MaximumStagingFileSize types.ByteSize `json:"maxStagingFileSize,omitempty" yaml:"maxStagingFileSize" mapstructure:"maxStagingFileSize"`
+	// MaximumStagingTotalSize is the maximum total size of files that
+	// endpoints will stage in a single synchronization cycle. It can be
+	// specified in human-friendly units.
+	MaximumStagingTotalSize types.ByteSize `json:"maxStagingTotalSize,omitempty" yaml:"maxStagingTotalSize" mapstructure:"maxStagingTotalSize"`
 	// ProbeMode specifies the filesystem probing mode.
 	ProbeMode behavior.ProbeMode `json:"probeMode,omitempty" yaml:"probeMode" mapstructure:"probeMode"`
 	// ScanMode specifies the filesystem scanning mode.
 	ScanMode synchronization.ScanMode `json:"scanMode,omitempty" yaml:"scanMode" mapstructure:"scanMode"`
+	// ScanConcurrency specifies the maximum number of Goroutines that
+	// endpoints will use to walk and hash content during scans.
+	ScanConcurrency uint32 `json:"scanConcurrency,omitempty" yaml:"scanConcurrency" mapstructure:"scanConcurrency"`
+	// MemoryBudget specifies the approximate maximum amount of memory that
+	// endpoint scan caches should occupy before they're spilled to disk.
+	// It can be specified in human-friendly units.
+	MemoryBudget types.ByteSize `json:"memoryBudget,omitempty" yaml:"memoryBudget" mapstructure:"memoryBudget"`
+	// AgentNiceness specifies the scheduling niceness (1-19) that agent
+	// processes should adopt.
+	AgentNiceness uint32 `json:"agentNiceness,omitempty" yaml:"agentNiceness" mapstructure:"agentNiceness"`
+	// ModificationTimeTolerance specifies the tolerance (in milliseconds)
+	// used when comparing file modification times for cache validity.
+	ModificationTimeTolerance uint32 `json:"mtimeTolerance,omitempty" yaml:"mtimeTolerance" mapstructure:"mtimeTolerance"`
 	// StageMode specifies the filesystem staging mode.
 	StageMode synchronization.StageMode `json:"stageMode,omitempty" yaml:"stageMode" mapstructure:"stageMode"`
+	// UnicodeNormalization specifies the Unicode normalization mode.
+	UnicodeNormalization core.UnicodeNormalizationMode `json:"unicodeNormalization,omitempty" yaml:"unicodeNormalization" mapstructure:"unicodeNormalization"`
+	// ReplacementMode specifies the file replacement mode.
+	ReplacementMode core.ReplacementMode `json:"replacementMode,omitempty" yaml:"replacementMode" mapstructure:"replacementMode"`
+	// MirrorEnforcement specifies the mirror enforcement mode.
+	MirrorEnforcement synchronization.MirrorEnforcementMode `json:"mirrorEnforcement,omitempty" yaml:"mirrorEnforcement" mapstructure:"mirrorEnforcement"`
 	// Ignore contains parameters related to synchronization ignore
 	// specifications.
 	Ignore struct {
@@ -31,6 +54,9 @@ type Configuration struct {
 		Paths []string `json:"paths,omitempty" yaml:"paths" mapstructure:"paths"`
 		// VCS specifies the VCS ignore mode.
 		VCS core.IgnoreVCSMode `json:"vcs,omitempty" yaml:"vcs" mapstructure:"vcs"`
+		// VCSIgnores specifies whether or not to import patterns from Git
+		// ignore files found within the alpha synchronization root.
+		VCSIgnores bool `json:"vcsIgnores,omitempty" yaml:"vcsIgnores" mapstructure:"vcsIgnores"`
 	} `json:"ignore" yaml:"ignore" mapstructure:"ignore"`
 	// Symlink contains parameters related to symbolic link handling.
 	Symlink struct {
@@ -45,6 +71,10 @@ type Configuration struct {
 		// file monitoring. A value of 0 specifies that Mutagen's internal
 		// default interval should be used.
 		PollingInterval uint32 `json:"pollingInterval,omitempty" yaml:"pollingInterval" mapstructure:"pollingInterval"`
+		// MinimumCycleInterval specifies the minimum interval (in seconds)
+		// between the starts of consecutive event-triggered synchronization
+		// cycles. A value of 0 disables the minimum interval.
+		MinimumCycleInterval uint32 `json:"minimumCycleInterval,omitempty" yaml:"minimumCycleInterval" mapstructure:"minimumCycleInterval"`
 	} `json:"watch" yaml:"watch" mapstructure:"watch"`
 	// Permissions contains parameters related to permission handling.
 	Permissions struct {
@@ -63,6 +93,40 @@ type Configuration struct {
 		// permission propagation mode.
 		DefaultGroup string `json:"defaultGroup,omitempty" yaml:"defaultGroup" mapstructure:"defaultGroup"`
 	} `json:"permissions" yaml:"permissions" mapstructure:"permissions"`
+	// Hooks contains parameters related to synchronization hooks.
+	Hooks struct {
+		// AfterSync specifies commands to run on an endpoint after a
+		// synchronization cycle propagates changes to it.
+		AfterSync []string `json:"afterSync,omitempty" yaml:"afterSync" mapstructure:"afterSync"`
+		// BeforeApply specifies commands to run on an endpoint before changes
+		// are applied to it. If any command fails, then changes are not
+		// applied.
+		BeforeApply []string `json:"beforeApply,omitempty" yaml:"beforeApply" mapstructure:"beforeApply"`
+	} `json:"hooks" yaml:"hooks" mapstructure:"hooks"`
+	// Safety contains parameters related to synchronization safety checks.
+	Safety struct {
+		// DeletionThreshold specifies the maximum percentage of previously
+		// synchronized entries that a single synchronization cycle may delete
+		// from either endpoint before the session is halted.
+		DeletionThreshold uint32 `json:"deletionThreshold,omitempty" yaml:"deletionThreshold" mapstructure:"deletionThreshold"`
+		// TrashDirectory specifies a directory on the endpoint in which files
+		// that are removed or overwritten by synchronization are retained.
+		TrashDirectory string `json:"trashDirectory,omitempty" yaml:"trashDirectory" mapstructure:"trashDirectory"`
+		// TrashRetentionPeriod specifies the period (in seconds) for which
+		// files are retained in the trash directory.
+		TrashRetentionPeriod uint32 `json:"trashRetentionPeriod,omitempty" yaml:"trashRetentionPeriod" mapstructure:"trashRetentionPeriod"`
+		// MaximumTrashSize specifies the maximum total size of files retained
+		// in the trash directory. It can be specified in human-friendly units.
+		MaximumTrashSize types.ByteSize `json:"maxTrashSize,omitempty" yaml:"maxTrashSize" mapstructure:"maxTrashSize"`
+		// VersionCount specifies the number of previous versions of each
+		// removed or overwritten file to keep on the endpoint. A value of 0
+		// disables file versioning.
+		VersionCount uint32 `json:"versionCount,omitempty" yaml:"versionCount" mapstructure:"versionCount"`
+		// MinimumFreeSpace specifies the amount of disk space that must remain
+		// available on the endpoint after a transition is applied. It can be
+		// specified in human-friendly units.
+		MinimumFreeSpace types.ByteSize `json:"minFreeSpace,omitempty" yaml:"minFreeSpace" mapstructure:"minFreeSpace"`
+	} `json:"safety" yaml:"safety" mapstructure:"safety"`
 }
 
 // loadFromInternal sets a configuration to match an internal
@@ -72,15 +136,24 @@ func (c *Configuration) loadFromInternal(configuration *synchronization.Configur
 	c.Mode = configuration.SynchronizationMode
 	c.MaximumEntryCount = configuration.MaximumEntryCount
 	c.MaximumStagingFileSize = types.ByteSize(configuration.MaximumStagingFileSize)
+	c.MaximumStagingTotalSize = types.ByteSize(configuration.MaximumStagingTotalSize)
 	c.ProbeMode = configuration.ProbeMode
 	c.ScanMode = configuration.ScanMode
+	c.ScanConcurrency = configuration.ScanConcurrency
+	c.MemoryBudget = types.ByteSize(configuration.MemoryBudget)
+	c.AgentNiceness = configuration.AgentNiceness
+	c.ModificationTimeTolerance = configuration.ModificationTimeTolerance
 	c.StageMode = configuration.StageMode
+	c.UnicodeNormalization = configuration.UnicodeNormalizationMode
+	c.ReplacementMode = configuration.ReplacementMode
+	c.MirrorEnforcement = configuration.MirrorEnforcementMode
 
 	// Propagate ignore configuration.
 	c.Ignore.Paths = make([]string, 0, len(configuration.DefaultIgnores)+len(configuration.Ignores))
 	c.Ignore.Paths = append(c.Ignore.Paths, configuration.DefaultIgnores...)
 	c.Ignore.Paths = append(c.Ignore.Paths, configuration.Ignores...)
 	c.Ignore.VCS = configuration.IgnoreVCSMode
+	c.Ignore.VCSIgnores = configuration.IgnoreVCSIgnores
 
 	// Propagate symbolic link configuration.
 	c.Symlink.Mode = configuration.SymbolicLinkMode
@@ -88,12 +161,25 @@ func (c *Configuration) loadFromInternal(configuration *synchronization.Configur
 	// Propagate watch configuration.
 	c.Watch.Mode = configuration.WatchMode
 	c.Watch.PollingInterval = configuration.WatchPollingInterval
+	c.Watch.MinimumCycleInterval = configuration.MinimumCycleInterval
 
 	// Propagate permission configuration.
 	c.Permissions.DefaultFileMode = filesystem.Mode(configuration.DefaultFileMode)
 	c.Permissions.DefaultDirectoryMode = filesystem.Mode(configuration.DefaultDirectoryMode)
 	c.Permissions.DefaultOwner = configuration.DefaultOwner
 	c.Permissions.DefaultGroup = configuration.DefaultGroup
+
+	// Propagate hook configuration.
+	c.Hooks.AfterSync = configuration.AfterSync
+	c.Hooks.BeforeApply = configuration.BeforeApply
+
+	// Propagate safety configuration.
+	c.Safety.DeletionThreshold = configuration.DeletionThreshold
+	c.Safety.TrashDirectory = configuration.TrashDirectory
+	c.Safety.TrashRetentionPeriod = configuration.TrashRetentionPeriod
+	c.Safety.MaximumTrashSize = types.ByteSize(configuration.MaximumTrashSize)
+	c.Safety.VersionCount = configuration.VersionCount
+	c.Safety.MinimumFreeSpace = types.ByteSize(configuration.MinimumFreeSpace)
 }
 
 // ToInternal converts a public configuration representation to an internal
@@ -101,20 +187,38 @@ func (c *Configuration) loadFromInternal(configuration *synchronization.Configur
 // configuration.
 func (c *Configuration) ToInternal() *synchronization.Configuration {
 	return &synchronization.Configuration{
-		SynchronizationMode:    c.Mode,
-		MaximumEntryCount:      c.MaximumEntryCount,
-		MaximumStagingFileSize: uint64(c.MaximumStagingFileSize),
-		ProbeMode:              c.ProbeMode,
-		ScanMode:               c.ScanMode,
-		StageMode:              c.StageMode,
-		SymbolicLinkMode:       c.Symlink.Mode,
-		WatchMode:              c.Watch.Mode,
-		WatchPollingInterval:   c.Watch.PollingInterval,
-		Ignores:                c.Ignore.Paths,
-		IgnoreVCSMode:          c.Ignore.VCS,
-		DefaultFileMode:        uint32(c.Permissions.DefaultFileMode),
-		DefaultDirectoryMode:   uint32(c.Permissions.DefaultDirectoryMode),
-		DefaultOwner:           c.Permissions.DefaultOwner,
-		DefaultGroup:           c.Permissions.DefaultGroup,
+		SynchronizationMode:       c.Mode,
+		MaximumEntryCount:         c.MaximumEntryCount,
+		MaximumStagingFileSize:    uint64(c.MaximumStagingFileSize),
+		MaximumStagingTotalSize:   uint64(c.MaximumStagingTotalSize),
+		ProbeMode:                 c.ProbeMode,
+		ScanMode:                  c.ScanMode,
+		ScanConcurrency:           c.ScanConcurrency,
+		MemoryBudget:              uint64(c.MemoryBudget),
+		AgentNiceness:             c.AgentNiceness,
+		ModificationTimeTolerance: c.ModificationTimeTolerance,
+		StageMode:                 c.StageMode,
+		UnicodeNormalizationMode:  c.UnicodeNormalization,
+		ReplacementMode:           c.ReplacementMode,
+		MirrorEnforcementMode:     c.MirrorEnforcement,
+		SymbolicLinkMode:          c.Symlink.Mode,
+		WatchMode:                 c.Watch.Mode,
+		WatchPollingInterval:      c.Watch.PollingInterval,
+		MinimumCycleInterval:      c.Watch.MinimumCycleInterval,
+		Ignores:                   c.Ignore.Paths,
+		IgnoreVCSMode:             c.Ignore.VCS,
+		IgnoreVCSIgnores:          c.Ignore.VCSIgnores,
+		DefaultFileMode:           uint32(c.Permissions.DefaultFileMode),
+		DefaultDirectoryMode:      uint32(c.Permissions.DefaultDirectoryMode),
+		DefaultOwner:              c.Permissions.DefaultOwner,
+		DefaultGroup:              c.Permissions.DefaultGroup,
+		AfterSync:                 c.Hooks.AfterSync,
+		BeforeApply:               c.Hooks.BeforeApply,
+		DeletionThreshold:         c.Safety.DeletionThreshold,
+		TrashDirectory:            c.Safety.TrashDirectory,
+		TrashRetentionPeriod:      c.Safety.TrashRetentionPeriod,
+		MaximumTrashSize:          uint64(c.Safety.MaximumTrashSize),
+		VersionCount:              c.Safety.VersionCount,
+		MinimumFreeSpace:          uint64(c.Safety.MinimumFreeSpace),
 	}
 }
@@ -17,6 +17,8 @@ maxEntryCount: 500
 maxStagingFileSize: "1000 GB"
 probeMode: "assume"
 scanMode: "accelerated"
+scanConcurrency: 4
+mtimeTolerance: 2000
 stageMode: "neighboring"
 
 symlink:
@@ -25,6 +27,7 @@ symlink:
 watch:
   mode: "force-poll"
   pollingInterval: 5
+  minimumCycleInterval: 2
 
 ignore:
   paths:
@@ -46,13 +49,16 @@ var expectedConfiguration = &synchronization.Configuration{
 	SynchronizationMode: core.SynchronizationMode_SynchronizationModeTwoWayResolved,
 	MaximumEntryCount:   500,
 	// TODO: This will mis-match.
-	MaximumStagingFileSize: 1000000000000,
-	ProbeMode:              behavior.ProbeMode_ProbeModeAssume,
-	ScanMode:               synchronization.ScanMode_ScanModeAccelerated,
-	StageMode:              synchronization.StageMode_StageModeNeighboring,
-	SymbolicLinkMode:       core.SymbolicLinkMode_SymbolicLinkModePortable,
-	WatchMode:              synchronization.WatchMode_WatchModeForcePoll,
-	WatchPollingInterval:   5,
+	MaximumStagingFileSize:    1000000000000,
+	ProbeMode:                 behavior.ProbeMode_ProbeModeAssume,
+	ScanMode:                  synchronization.ScanMode_ScanModeAccelerated,
+	ScanConcurrency:           4,
+	ModificationTimeTolerance: 2000,
+	StageMode:                 synchronization.StageMode_StageModeNeighboring,
+	SymbolicLinkMode:          core.SymbolicLinkMode_SymbolicLinkModePortable,
+	WatchMode:                 synchronization.WatchMode_WatchModeForcePoll,
+	WatchPollingInterval:      5,
+	MinimumCycleInterval:      2,
 	Ignores: []string{
 		"ignore/this/**",
 		"!ignore/this/that",
@@ -107,6 +113,12 @@ func TestLoadConfiguration(t *testing.T) {
 	if configuration.ScanMode != expectedConfiguration.ScanMode {
 		t.Error("scan mode mismatch:", configuration.ScanMode, "!=", expectedConfiguration.ScanMode)
 	}
+	if configuration.ScanConcurrency != expectedConfiguration.ScanConcurrency {
+		t.Error("scan concurrency mismatch:", configuration.ScanConcurrency, "!=", expectedConfiguration.ScanConcurrency)
+	}
+	if configuration.ModificationTimeTolerance != expectedConfiguration.ModificationTimeTolerance {
+		t.Error("modification time tolerance mismatch:", configuration.ModificationTimeTolerance, "!=", expectedConfiguration.ModificationTimeTolerance)
+	}
 	if configuration.StageMode != expectedConfiguration.StageMode {
 		t.Error("stage mode mismatch:", configuration.StageMode, "!=", expectedConfiguration.StageMode)
 	}
@@ -119,6 +131,9 @@ func TestLoadConfiguration(t *testing.T) {
 	if configuration.WatchPollingInterval != expectedConfiguration.WatchPollingInterval {
 		t.Error("watch polling interval mismatch:", configuration.WatchPollingInterval, "!=", expectedConfiguration.WatchPollingInterval)
 	}
+	if configuration.MinimumCycleInterval != expectedConfiguration.MinimumCycleInterval {
+		t.Error("minimum cycle interval mismatch:", configuration.MinimumCycleInterval, "!=", expectedConfiguration.MinimumCycleInterval)
+	}
 	if len(configuration.Ignores) != len(expectedConfiguration.Ignores) {
 		t.Error("ignore count mismatch:", len(configuration.Ignores), "!=", len(expectedConfiguration.Ignores))
 	} else {
@@ -70,6 +70,9 @@ type EndpointState struct {
 	// StagingProgress is the rsync staging progress. It is non-nil if and only
 	// if the endpoint is currently staging files.
 	StagingProgress *ReceiverState `json:"stagingProgress,omitempty"`
+	// Capabilities are the probed capabilities of the filesystem on which the
+	// synchronization root resides. They may be nil if probing failed.
+	Capabilities *FilesystemCapabilities `json:"capabilities,omitempty"`
 }
 
 // loadFromInternal sets an Endpoint to match internal Protocol Buffers
@@ -105,6 +108,7 @@ func (e *Endpoint) loadFromInternal(url *url.URL, configuration *synchronization
 			TransitionProblems:         exportProblems(state.TransitionProblems),
 			ExcludedTransitionProblems: state.ExcludedTransitionProblems,
 			StagingProgress:            newReceiverStateFromInternalReceiverState(state.StagingProgress),
+			Capabilities:               newFilesystemCapabilitiesFromInternal(state.Capabilities),
 		}
 	}
 }
@@ -20,6 +20,9 @@ type ReceiverState struct {
 	// TotalReceivedSize is the total number of bytes that have been received
 	// for all paths from both block and data operations.
 	TotalReceivedSize uint64 `json:"totalReceivedSize"`
+	// TotalDataSize is the total number of bytes that have been received for
+	// all paths via data operations.
+	TotalDataSize uint64 `json:"totalDataSize"`
 }
 
 // newReceiverStateFromInternalReceiverState creates a new receiver state
@@ -39,5 +42,6 @@ func newReceiverStateFromInternalReceiverState(state *rsync.ReceiverState) *Rece
 		ReceivedFiles:     state.ReceivedFiles,
 		ExpectedFiles:     state.ExpectedFiles,
 		TotalReceivedSize: state.TotalReceivedSize,
+		TotalDataSize:     state.TotalDataSize,
 	}
 }
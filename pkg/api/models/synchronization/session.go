@@ -51,6 +51,8 @@ type SessionState struct {
 	// Conflicts due to truncation. This value can only be non-zero if conflicts
 	// is non-empty.
 	ExcludedConflicts uint64 `json:"excludedConflicts,omitempty"`
+	// Statistics are the synchronization statistics accumulated by the session.
+	Statistics *SessionStatistics `json:"statistics,omitempty"`
 }
 
 // loadFromInternal sets a session to match an internal Protocol Buffers session
@@ -94,6 +96,7 @@ func (s *Session) loadFromInternal(state *synchronization.State) {
 			SuccessfulCycles:  state.SuccessfulCycles,
 			Conflicts:         exportConflicts(state.Conflicts),
 			ExcludedConflicts: state.ExcludedConflicts,
+			Statistics:        newSessionStatisticsFromInternal(state.Statistics),
 		}
 	}
 }
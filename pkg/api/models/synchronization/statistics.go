@@ -0,0 +1,66 @@
+package synchronization
+
+import (
+	"github.com/mutagen-io/mutagen/pkg/synchronization"
+)
+
+// CycleStatistics represents statistics for one or more synchronization
+// cycles.
+type CycleStatistics struct {
+	// Duration is the time (in nanoseconds) spent performing the cycle(s).
+	Duration uint64 `json:"duration"`
+	// StagedFiles is the number of files staged across both endpoints.
+	StagedFiles uint64 `json:"stagedFiles"`
+	// StagedSize is the total size of the files staged across both endpoints.
+	StagedSize uint64 `json:"stagedSize"`
+	// TransferredSize is the number of bytes of literal file data transferred
+	// to stage files.
+	TransferredSize uint64 `json:"transferredSize"`
+}
+
+// newCycleStatisticsFromInternal creates a new cycle statistics representation
+// from an internal Protocol Buffers representation.
+func newCycleStatisticsFromInternal(statistics *synchronization.CycleStatistics) *CycleStatistics {
+	// If the statistics are nil, then return nil statistics.
+	if statistics == nil {
+		return nil
+	}
+
+	// Perform conversion.
+	return &CycleStatistics{
+		Duration:        statistics.Duration,
+		StagedFiles:     statistics.StagedFiles,
+		StagedSize:      statistics.StagedSize,
+		TransferredSize: statistics.TransferredSize,
+	}
+}
+
+// SessionStatistics represents synchronization statistics accumulated by a
+// session.
+type SessionStatistics struct {
+	// Cycles is the number of successful synchronization cycles that have been
+	// performed.
+	Cycles uint64 `json:"cycles"`
+	// LastCycle contains statistics for the most recent successful
+	// synchronization cycle.
+	LastCycle *CycleStatistics `json:"lastCycle,omitempty"`
+	// Total contains statistics accumulated across all successful
+	// synchronization cycles.
+	Total *CycleStatistics `json:"total,omitempty"`
+}
+
+// newSessionStatisticsFromInternal creates a new session statistics
+// representation from an internal Protocol Buffers representation.
+func newSessionStatisticsFromInternal(statistics *synchronization.SessionStatistics) *SessionStatistics {
+	// If the statistics are nil, then return nil statistics.
+	if statistics == nil {
+		return nil
+	}
+
+	// Perform conversion.
+	return &SessionStatistics{
+		Cycles:    statistics.Cycles,
+		LastCycle: newCycleStatisticsFromInternal(statistics.LastCycle),
+		Total:     newCycleStatisticsFromInternal(statistics.Total),
+	}
+}
@@ -0,0 +1,38 @@
+package azure
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+)
+
+// CommandPath returns the absolute path specification to use for invoking the
+// Azure CLI. It will use the MUTAGEN_AZURE_PATH environment variable if
+// provided, otherwise falling back to a search of the user's path.
+func CommandPath() (string, error) {
+	// If MUTAGEN_AZURE_PATH is specified, then use it to perform the lookup.
+	// We can't use process.FindCommand here because the Azure CLI is
+	// installed as a batch script (az.cmd) on Windows, so we rely on LookPath
+	// to perform extension resolution.
+	if searchPath := os.Getenv("MUTAGEN_AZURE_PATH"); searchPath != "" {
+		return exec.LookPath(filepath.Join(searchPath, "az"))
+	}
+
+	// Otherwise search the user's path.
+	return exec.LookPath("az")
+}
+
+// Command prepares (but does not start) an Azure CLI command with the specified
+// arguments and scoped to lifetime of the provided context.
+func Command(ctx context.Context, args ...string) (*exec.Cmd, error) {
+	// Identify the command path.
+	commandPath, err := CommandPath()
+	if err != nil {
+		return nil, fmt.Errorf("unable to identify 'az' command: %w", err)
+	}
+
+	// Create the command.
+	return exec.CommandContext(ctx, commandPath, args...), nil
+}
@@ -0,0 +1,2 @@
+// Package azure provides utility functions for interfacing with the Azure CLI.
+package azure
@@ -1,9 +1,12 @@
 package global
 
 import (
+	"fmt"
+
 	"github.com/mutagen-io/mutagen/pkg/api/models/forwarding"
 	"github.com/mutagen-io/mutagen/pkg/api/models/synchronization"
 	"github.com/mutagen-io/mutagen/pkg/encoding"
+	"github.com/mutagen-io/mutagen/pkg/url"
 )
 
 // Configuration is the global YAML configuration object type.
@@ -18,6 +21,20 @@ type Configuration struct {
 		// Defaults are the global synchronization configuration defaults.
 		Defaults synchronization.Configuration `yaml:"defaults"`
 	} `yaml:"sync"`
+	// SSH is the global SSH configuration.
+	SSH struct {
+		// Client is the SSH client implementation to use for SSH URLs that
+		// don't already specify one. If empty, the OpenSSH client is used.
+		Client string `yaml:"client"`
+	} `yaml:"ssh"`
+	// Agent is the global agent configuration.
+	Agent struct {
+		// Directory is the directory to use as the Mutagen data directory (and
+		// thus the agent installation location) on remotes for agent-based URLs
+		// that don't already specify one. If empty, the default data directory
+		// inside the remote user's home directory is used.
+		Directory string `yaml:"directory"`
+	} `yaml:"agent"`
 }
 
 // LoadConfiguration attempts to load a YAML-based Mutagen global configuration
@@ -31,6 +48,45 @@ func LoadConfiguration(path string) (*Configuration, error) {
 		return nil, err
 	}
 
+	// Validate the SSH client specification.
+	if result.SSH.Client != "" && !url.IsValidSSHClient(result.SSH.Client) {
+		return nil, fmt.Errorf("invalid SSH client: %s", result.SSH.Client)
+	}
+
+	// Validate the agent directory specification.
+	if result.Agent.Directory != "" && !url.IsValidAgentDirectory(result.Agent.Directory) {
+		return nil, fmt.Errorf("invalid agent directory: %s", result.Agent.Directory)
+	}
+
 	// Success.
 	return result, nil
 }
+
+// ConfigureURL applies the configuration's URL-related settings to the
+// specified URL. The SSH client setting is only applied to SSH URLs and the
+// agent directory setting is only applied to agent-based URLs. Neither setting
+// is applied if it's not configured or if the URL already specifies a value.
+func (c *Configuration) ConfigureURL(u *url.URL) {
+	// Apply the SSH client setting, if applicable.
+	if c.SSH.Client != "" && u.Protocol == url.Protocol_SSH {
+		if _, ok := u.Parameters[url.SSHClientParameter]; !ok {
+			setParameter(u, url.SSHClientParameter, c.SSH.Client)
+		}
+	}
+
+	// Apply the agent directory setting, if applicable.
+	if c.Agent.Directory != "" && u.Protocol.UsesAgent() {
+		if _, ok := u.Parameters[url.AgentDirectoryParameter]; !ok {
+			setParameter(u, url.AgentDirectoryParameter, c.Agent.Directory)
+		}
+	}
+}
+
+// setParameter sets a parameter on a URL, creating its parameter map if
+// necessary.
+func setParameter(u *url.URL, name, value string) {
+	if u.Parameters == nil {
+		u.Parameters = make(map[string]string, 1)
+	}
+	u.Parameters[name] = value
+}
@@ -1,3 +1,75 @@
 package global
 
-// TODO: Implement.
+import (
+	"testing"
+
+	"github.com/mutagen-io/mutagen/pkg/url"
+)
+
+// TestConfigurationConfigureURL tests Configuration.ConfigureURL.
+func TestConfigurationConfigureURL(t *testing.T) {
+	// Set up test cases.
+	testCases := []struct {
+		client   string
+		url      *url.URL
+		expected string
+	}{
+		{"", &url.URL{Protocol: url.Protocol_SSH}, ""},
+		{url.SSHClientNative, &url.URL{Protocol: url.Protocol_Local}, ""},
+		{url.SSHClientNative, &url.URL{Protocol: url.Protocol_Docker}, ""},
+		{url.SSHClientNative, &url.URL{Protocol: url.Protocol_SSH}, url.SSHClientNative},
+		{
+			url.SSHClientNative,
+			&url.URL{
+				Protocol:   url.Protocol_SSH,
+				Parameters: map[string]string{url.SSHClientParameter: url.SSHClientOpenSSH},
+			},
+			url.SSHClientOpenSSH,
+		},
+	}
+
+	// Process test cases.
+	for i, testCase := range testCases {
+		configuration := &Configuration{}
+		configuration.SSH.Client = testCase.client
+		configuration.ConfigureURL(testCase.url)
+		if client := testCase.url.Parameters[url.SSHClientParameter]; client != testCase.expected {
+			t.Errorf("test index %d: SSH client does not match expected: %q != %q", i, client, testCase.expected)
+		}
+	}
+}
+
+// TestConfigurationConfigureURLAgentDirectory tests Configuration.ConfigureURL
+// with agent directory settings.
+func TestConfigurationConfigureURLAgentDirectory(t *testing.T) {
+	// Set up test cases.
+	testCases := []struct {
+		directory string
+		url       *url.URL
+		expected  string
+	}{
+		{"", &url.URL{Protocol: url.Protocol_SSH}, ""},
+		{"/tmp/.mutagen", &url.URL{Protocol: url.Protocol_Local}, ""},
+		{"/tmp/.mutagen", &url.URL{Protocol: url.Protocol_SFTP}, ""},
+		{"/tmp/.mutagen", &url.URL{Protocol: url.Protocol_SSH}, "/tmp/.mutagen"},
+		{"/tmp/.mutagen", &url.URL{Protocol: url.Protocol_Docker}, "/tmp/.mutagen"},
+		{
+			"/tmp/.mutagen",
+			&url.URL{
+				Protocol:   url.Protocol_LXD,
+				Parameters: map[string]string{url.AgentDirectoryParameter: "/srv/.mutagen"},
+			},
+			"/srv/.mutagen",
+		},
+	}
+
+	// Process test cases.
+	for i, testCase := range testCases {
+		configuration := &Configuration{}
+		configuration.Agent.Directory = testCase.directory
+		configuration.ConfigureURL(testCase.url)
+		if directory := testCase.url.Parameters[url.AgentDirectoryParameter]; directory != testCase.expected {
+			t.Errorf("test index %d: agent directory does not match expected: %q != %q", i, directory, testCase.expected)
+		}
+	}
+}
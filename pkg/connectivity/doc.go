@@ -0,0 +1,7 @@
+// Package connectivity provides facilities for detecting host network
+// configuration changes and system sleep/wake transitions, both of which tend to
+// silently invalidate existing network connections. Changes are detected using
+// platform notification mechanisms (netlink on Linux, SCNetworkReachability and
+// IOKit power notifications on macOS, and address change notifications and
+// power broadcasts on Windows), with polling used on other platforms.
+package connectivity
@@ -0,0 +1,315 @@
+package connectivity
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/mutagen-io/mutagen/pkg/logging"
+)
+
+const (
+	// monitoringInterval is the interval at which network configuration is
+	// checked for changes on platforms where notifications are unavailable.
+	monitoringInterval = 2 * time.Second
+	// wakeThreshold is the amount by which the wall clock time between two
+	// consecutive checks must exceed monitoringInterval in order for the gap to
+	// be attributed to a system suspension.
+	wakeThreshold = 10 * time.Second
+)
+
+// Change represents a detected connectivity change.
+type Change uint8
+
+const (
+	// ChangeNetwork indicates that the host network configuration changed.
+	ChangeNetwork Change = iota
+	// ChangeWake indicates that the system resumed from suspension.
+	ChangeWake
+)
+
+// String returns a human-readable description of the change.
+func (c Change) String() string {
+	switch c {
+	case ChangeNetwork:
+		return "network configuration change"
+	case ChangeWake:
+		return "system wake"
+	default:
+		return "unknown change"
+	}
+}
+
+// detector implements the change detection logic used by Monitor.
+type detector struct {
+	// lastCheck is the wall clock time of the last check.
+	lastCheck time.Time
+	// lastFingerprint is the network configuration fingerprint computed during
+	// the last check.
+	lastFingerprint string
+}
+
+// newDetector creates a new detector with the specified initial check time and
+// network configuration fingerprint.
+func newDetector(now time.Time, fingerprint string) *detector {
+	return &detector{
+		lastCheck:       now.Round(0),
+		lastFingerprint: fingerprint,
+	}
+}
+
+// check records the specified check time and network configuration fingerprint
+// and returns any change detected since the previous check. A system wake is
+// detected by comparing elapsed wall clock time against the expected interval,
+// since the monotonic clock used to drive checks doesn't advance while the
+// system is suspended on most platforms.
+func (d *detector) check(now time.Time, fingerprint string) (Change, bool) {
+	// Strip any monotonic clock reading so that we compare wall clock time.
+	now = now.Round(0)
+
+	// Record the new state and extract the old.
+	lastCheck, lastFingerprint := d.lastCheck, d.lastFingerprint
+	d.lastCheck, d.lastFingerprint = now, fingerprint
+
+	// Check for a system wake first, since that will often be accompanied by a
+	// network configuration change anyway.
+	if now.Sub(lastCheck) > monitoringInterval+wakeThreshold {
+		return ChangeWake, true
+	} else if fingerprint != lastFingerprint {
+		return ChangeNetwork, true
+	}
+	return 0, false
+}
+
+// virtualInterfacePrefixes are the name prefixes of virtual network
+// interfaces (such as those created by container runtimes, hypervisors, and
+// bridges) whose configuration changes don't affect external connectivity.
+var virtualInterfacePrefixes = []string{
+	"br-",
+	"cali",
+	"cni",
+	"docker",
+	"flannel",
+	"lxcbr",
+	"lxdbr",
+	"podman",
+	"vboxnet",
+	"veth",
+	"virbr",
+	"vmnet",
+	"vEthernet",
+}
+
+// isVirtualInterface determines whether or not a network interface name
+// corresponds to a virtual interface.
+func isVirtualInterface(name string) bool {
+	for _, prefix := range virtualInterfacePrefixes {
+		if strings.HasPrefix(name, prefix) {
+			return true
+		}
+	}
+	return false
+}
+
+// fingerprintAddress computes the fingerprint component for a network address.
+// Global IPv6 addresses are reduced to their /64 prefix because temporary
+// (privacy) addresses are regenerated periodically within the same prefix and
+// their rotation doesn't represent a connectivity change.
+func fingerprintAddress(address net.Addr) string {
+	network, ok := address.(*net.IPNet)
+	if !ok || network.IP.To4() != nil || network.IP.IsLinkLocalUnicast() {
+		return address.String()
+	}
+	prefix := &net.IPNet{IP: network.IP.Mask(net.CIDRMask(64, 128)), Mask: net.CIDRMask(64, 128)}
+	return prefix.String()
+}
+
+// fingerprint computes a fingerprint of the host's network configuration based
+// on the addresses assigned to active, non-loopback, non-virtual network
+// interfaces.
+func fingerprint() (string, error) {
+	// Query network interfaces.
+	interfaces, err := net.Interfaces()
+	if err != nil {
+		return "", fmt.Errorf("unable to query network interfaces: %w", err)
+	}
+
+	// Record the addresses of active, non-loopback, non-virtual interfaces.
+	// Since multiple addresses can map to the same fingerprint component, we
+	// de-duplicate entries.
+	entries := make(map[string]bool)
+	for _, i := range interfaces {
+		if i.Flags&net.FlagUp == 0 || i.Flags&net.FlagLoopback != 0 || isVirtualInterface(i.Name) {
+			continue
+		}
+		addresses, err := i.Addrs()
+		if err != nil {
+			return "", fmt.Errorf("unable to query addresses for interface %s: %w", i.Name, err)
+		}
+		for _, a := range addresses {
+			entries[i.Name+"="+fingerprintAddress(a)] = true
+		}
+	}
+
+	// Sort the entries so that the fingerprint doesn't depend on enumeration
+	// order and combine them.
+	sorted := make([]string, 0, len(entries))
+	for entry := range entries {
+		sorted = append(sorted, entry)
+	}
+	sort.Strings(sorted)
+	return strings.Join(sorted, ","), nil
+}
+
+// notification represents a platform notification of a potential connectivity
+// change.
+type notification uint8
+
+const (
+	// notificationAddresses indicates that network interface or address
+	// configuration may have changed. These notifications are frequently
+	// delivered for changes that don't affect connectivity (e.g. virtual
+	// interface churn or IPv6 temporary address rotation), so they're filtered
+	// using the network configuration fingerprint.
+	notificationAddresses notification = iota
+	// notificationRouting indicates that default routing or reachability has
+	// changed. These notifications are always reported as network changes.
+	notificationRouting
+	// notificationWake indicates that the system resumed from suspension.
+	notificationWake
+)
+
+const (
+	// notificationSettleDelay is the amount of time that Monitor waits after a
+	// notification for further notifications before processing it, since
+	// notifications tend to arrive in bursts.
+	notificationSettleDelay = time.Second
+	// notificationBufferSize is the buffer size for notification channels.
+	notificationBufferSize = 16
+)
+
+// errNotificationsUnsupported indicates that connectivity change notifications
+// aren't supported on the current platform.
+var errNotificationsUnsupported = errors.New("notifications unsupported on this platform")
+
+// notify performs a non-blocking send of a notification to a channel. If the
+// channel is full, then the notification is dropped, since the pending
+// notifications will already trigger a check.
+func notify(notifications chan<- notification, n notification) {
+	select {
+	case notifications <- n:
+	default:
+	}
+}
+
+// Monitor watches for host network configuration changes and system wake
+// events, invoking the specified handler for each detected change. It runs
+// until the provided context is cancelled. Where supported, platform
+// notification mechanisms are used to detect changes, otherwise network
+// configuration is polled. Failures to query network configuration are logged
+// but otherwise ignored.
+func Monitor(ctx context.Context, logger *logging.Logger, handler func(Change)) {
+	// Compute the initial fingerprint.
+	last, err := fingerprint()
+	if err != nil {
+		logger.Warn("Unable to compute initial network fingerprint:", err)
+	}
+
+	// Start platform notifications, falling back to polling if they're not
+	// available.
+	notifications := make(chan notification, notificationBufferSize)
+	if err := startNotifications(ctx, logger, notifications); err != nil {
+		if err != errNotificationsUnsupported {
+			logger.Warn("Unable to start connectivity notifications, falling back to polling:", err)
+		}
+		poll(ctx, logger, last, handler)
+		return
+	}
+
+	// Process notifications until cancelled.
+	for {
+		// Wait for a notification.
+		var pending notification
+		select {
+		case <-ctx.Done():
+			return
+		case pending = <-notifications:
+		}
+
+		// Wait for the notification burst to settle, tracking the most
+		// significant notification received.
+		settle := time.NewTimer(notificationSettleDelay)
+	Settle:
+		for {
+			select {
+			case <-ctx.Done():
+				settle.Stop()
+				return
+			case n := <-notifications:
+				if n > pending {
+					pending = n
+				}
+			case <-settle.C:
+				break Settle
+			}
+		}
+
+		// Update the fingerprint and determine whether or not the notification
+		// represents a change.
+		current, err := fingerprint()
+		if err != nil {
+			logger.Debug("Unable to compute network fingerprint:", err)
+			current = last
+		}
+		var change Change
+		switch pending {
+		case notificationWake:
+			change = ChangeWake
+		case notificationRouting:
+			change = ChangeNetwork
+		default:
+			if current == last {
+				continue
+			}
+			change = ChangeNetwork
+		}
+		last = current
+
+		// Report the change.
+		logger.Info("Detected", change)
+		handler(change)
+	}
+}
+
+// poll implements connectivity monitoring by periodically polling network
+// configuration. It's used on platforms where notifications are unavailable.
+func poll(ctx context.Context, logger *logging.Logger, initial string, handler func(Change)) {
+	// Create the detector.
+	detector := newDetector(time.Now(), initial)
+
+	// Create a ticker to regulate checks and defer its shutdown.
+	ticker := time.NewTicker(monitoringInterval)
+	defer ticker.Stop()
+
+	// Loop and wait for the ticker or cancellation.
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			current, err := fingerprint()
+			if err != nil {
+				logger.Debug("Unable to compute network fingerprint:", err)
+				current = detector.lastFingerprint
+			}
+			if change, changed := detector.check(time.Now(), current); changed {
+				logger.Info("Detected", change)
+				handler(change)
+			}
+		}
+	}
+}
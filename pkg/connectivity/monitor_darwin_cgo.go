@@ -0,0 +1,230 @@
+//go:build darwin && cgo
+
+package connectivity
+
+/*
+#cgo LDFLAGS: -framework CoreFoundation -framework SystemConfiguration -framework IOKit
+
+#include <stdint.h>
+#include <stdlib.h>
+#include <string.h>
+#include <netinet/in.h>
+#include <CoreFoundation/CoreFoundation.h>
+#include <SystemConfiguration/SystemConfiguration.h>
+#include <IOKit/IOMessage.h>
+#include <IOKit/pwr_mgt/IOPMLib.h>
+
+extern void connectivityReachabilityChanged(uintptr_t handle);
+extern void connectivitySystemWoke(uintptr_t handle);
+
+// powerContext is the context for system power notifications.
+typedef struct {
+	uintptr_t handle;
+	io_connect_t rootPort;
+} powerContext;
+
+// monitorState tracks the resources used for monitoring.
+typedef struct {
+	CFRunLoopRef runLoop;
+	CFRunLoopSourceRef stopSource;
+	SCNetworkReachabilityRef reachability;
+	powerContext *power;
+	IONotificationPortRef notificationPort;
+	io_object_t notifier;
+} monitorState;
+
+static void reachabilityCallback(SCNetworkReachabilityRef target, SCNetworkReachabilityFlags flags, void *info) {
+	connectivityReachabilityChanged((uintptr_t)info);
+}
+
+static void powerCallback(void *refcon, io_service_t service, natural_t messageType, void *messageArgument) {
+	powerContext *context = (powerContext *)refcon;
+	switch (messageType) {
+	case kIOMessageCanSystemSleep:
+	case kIOMessageSystemWillSleep:
+		IOAllowPowerChange(context->rootPort, (intptr_t)messageArgument);
+		break;
+	case kIOMessageSystemHasPoweredOn:
+		connectivitySystemWoke(context->handle);
+		break;
+	}
+}
+
+static void stopPerform(void *info) {
+	CFRunLoopStop(CFRunLoopGetCurrent());
+}
+
+static void stopMonitoring(monitorState *state) {
+	if (state->reachability != NULL) {
+		SCNetworkReachabilityUnscheduleFromRunLoop(state->reachability, state->runLoop, kCFRunLoopDefaultMode);
+		SCNetworkReachabilitySetCallback(state->reachability, NULL, NULL);
+		CFRelease(state->reachability);
+		state->reachability = NULL;
+	}
+	if (state->power != NULL) {
+		if (state->notificationPort != NULL) {
+			CFRunLoopRemoveSource(state->runLoop, IONotificationPortGetRunLoopSource(state->notificationPort), kCFRunLoopDefaultMode);
+		}
+		if (state->notifier != 0) {
+			IODeregisterForSystemPower(&state->notifier);
+		}
+		if (state->power->rootPort != MACH_PORT_NULL) {
+			IOServiceClose(state->power->rootPort);
+		}
+		if (state->notificationPort != NULL) {
+			IONotificationPortDestroy(state->notificationPort);
+			state->notificationPort = NULL;
+		}
+		free(state->power);
+		state->power = NULL;
+	}
+	if (state->stopSource != NULL) {
+		CFRunLoopRemoveSource(state->runLoop, state->stopSource, kCFRunLoopDefaultMode);
+		CFRelease(state->stopSource);
+		state->stopSource = NULL;
+	}
+}
+
+static int startMonitoring(uintptr_t handle, monitorState *state) {
+	// Record the current run loop.
+	state->runLoop = CFRunLoopGetCurrent();
+
+	// Create the source used to stop the run loop. Unlike CFRunLoopStop, a
+	// signal sent to this source before the run loop starts won't be lost.
+	CFRunLoopSourceContext stopContext;
+	memset(&stopContext, 0, sizeof(stopContext));
+	stopContext.perform = stopPerform;
+	state->stopSource = CFRunLoopSourceCreate(kCFAllocatorDefault, 0, &stopContext);
+	if (state->stopSource == NULL) {
+		return 1;
+	}
+	CFRunLoopAddSource(state->runLoop, state->stopSource, kCFRunLoopDefaultMode);
+
+	// Monitor reachability of the default route.
+	struct sockaddr_in address;
+	memset(&address, 0, sizeof(address));
+	address.sin_len = sizeof(address);
+	address.sin_family = AF_INET;
+	state->reachability = SCNetworkReachabilityCreateWithAddress(kCFAllocatorDefault, (const struct sockaddr *)&address);
+	if (state->reachability == NULL) {
+		stopMonitoring(state);
+		return 2;
+	}
+	SCNetworkReachabilityContext reachabilityContext;
+	memset(&reachabilityContext, 0, sizeof(reachabilityContext));
+	reachabilityContext.info = (void *)handle;
+	if (!SCNetworkReachabilitySetCallback(state->reachability, reachabilityCallback, &reachabilityContext)) {
+		stopMonitoring(state);
+		return 3;
+	}
+	if (!SCNetworkReachabilityScheduleWithRunLoop(state->reachability, state->runLoop, kCFRunLoopDefaultMode)) {
+		stopMonitoring(state);
+		return 4;
+	}
+
+	// Monitor system power transitions.
+	state->power = calloc(1, sizeof(powerContext));
+	if (state->power == NULL) {
+		stopMonitoring(state);
+		return 5;
+	}
+	state->power->handle = handle;
+	state->power->rootPort = IORegisterForSystemPower(state->power, &state->notificationPort, powerCallback, &state->notifier);
+	if (state->power->rootPort == MACH_PORT_NULL) {
+		stopMonitoring(state);
+		return 6;
+	}
+	CFRunLoopAddSource(state->runLoop, IONotificationPortGetRunLoopSource(state->notificationPort), kCFRunLoopDefaultMode);
+
+	// Success.
+	return 0;
+}
+
+static void signalStop(monitorState *state) {
+	CFRunLoopSourceSignal(state->stopSource);
+	CFRunLoopWakeUp(state->runLoop);
+}
+*/
+import "C"
+
+import (
+	"context"
+	"errors"
+	"runtime"
+	"runtime/cgo"
+	"unsafe"
+
+	"github.com/mutagen-io/mutagen/pkg/logging"
+)
+
+// monitoringStartErrors maps monitoring start error codes to descriptions.
+var monitoringStartErrors = map[C.int]string{
+	1: "unable to create run loop stop source",
+	2: "unable to create reachability target",
+	3: "unable to set reachability callback",
+	4: "unable to schedule reachability monitoring",
+	5: "unable to allocate power notification context",
+	6: "unable to register for system power notifications",
+}
+
+// runNotifications sets up reachability and power notifications on a
+// dedicated run loop and services that run loop until cancelled. The outcome of
+// setup is reported via the provided channel.
+func runNotifications(ctx context.Context, notifications chan<- notification, started chan<- error) {
+	// Lock the Goroutine to its current thread, since run loops are bound to
+	// threads.
+	runtime.LockOSThread()
+	defer runtime.UnlockOSThread()
+
+	// Create a handle that callbacks can use to access the notification
+	// channel and defer its release. It's safe to release the handle once the
+	// run loop exits, since callbacks are only invoked by the run loop.
+	handle := cgo.NewHandle(notifications)
+	defer handle.Delete()
+
+	// Allocate the monitoring state in C memory and defer its release.
+	state := (*C.monitorState)(C.calloc(1, C.sizeof_monitorState))
+	if state == nil {
+		started <- errors.New("unable to allocate monitoring state")
+		return
+	}
+	defer C.free(unsafe.Pointer(state))
+
+	// Start monitoring.
+	if result := C.startMonitoring(C.uintptr_t(handle), state); result != 0 {
+		started <- errors.New(monitoringStartErrors[result])
+		return
+	}
+	close(started)
+
+	// Signal the run loop to stop on cancellation.
+	done := make(chan struct{})
+	signalerDone := make(chan struct{})
+	go func() {
+		defer close(signalerDone)
+		select {
+		case <-ctx.Done():
+			C.signalStop(state)
+		case <-done:
+		}
+	}()
+
+	// Run the run loop until it's stopped.
+	C.CFRunLoopRun()
+
+	// Ensure that the stop signaler has exited (since it accesses the
+	// monitoring state) and terminate monitoring.
+	close(done)
+	<-signalerDone
+	C.stopMonitoring(state)
+}
+
+// startNotifications starts platform connectivity notifications, which are
+// delivered until the provided context is cancelled. On macOS, network changes
+// are monitored using SCNetworkReachability and system wake is detected using
+// IOKit power notifications.
+func startNotifications(ctx context.Context, _ *logging.Logger, notifications chan<- notification) error {
+	started := make(chan error, 1)
+	go runNotifications(ctx, notifications, started)
+	return <-started
+}
@@ -0,0 +1,30 @@
+//go:build darwin && cgo
+
+package connectivity
+
+// Since this file uses cgo exports, its preamble may only contain declarations.
+
+/*
+#include <stdint.h>
+*/
+import "C"
+
+import (
+	"runtime/cgo"
+)
+
+// connectivityReachabilityChanged is the reachability change callback invoked
+// by the notification run loop.
+//
+//export connectivityReachabilityChanged
+func connectivityReachabilityChanged(handle C.uintptr_t) {
+	notify(cgo.Handle(handle).Value().(chan<- notification), notificationRouting)
+}
+
+// connectivitySystemWoke is the system wake callback invoked by the
+// notification run loop.
+//
+//export connectivitySystemWoke
+func connectivitySystemWoke(handle C.uintptr_t) {
+	notify(cgo.Handle(handle).Value().(chan<- notification), notificationWake)
+}
@@ -0,0 +1,161 @@
+package connectivity
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"syscall"
+	"time"
+
+	"golang.org/x/sys/unix"
+
+	"github.com/mutagen-io/mutagen/pkg/logging"
+)
+
+const (
+	// netlinkGroups are the netlink multicast groups to which we subscribe.
+	netlinkGroups = unix.RTMGRP_LINK |
+		unix.RTMGRP_IPV4_IFADDR | unix.RTMGRP_IPV6_IFADDR |
+		unix.RTMGRP_IPV4_ROUTE | unix.RTMGRP_IPV6_ROUTE
+	// netlinkBufferSize is the buffer size used for reading netlink messages.
+	netlinkBufferSize = 64 * 1024
+
+	// wakeCheckInterval is the interval at which the boot and monotonic clocks
+	// are compared to detect system suspension.
+	wakeCheckInterval = 5 * time.Second
+	// suspensionThreshold is the amount by which the boot clock must advance
+	// beyond the monotonic clock between two wake checks in order for the
+	// difference to be attributed to a system suspension.
+	suspensionThreshold = time.Second
+)
+
+// classifyNetlinkMessage determines the notification corresponding to a netlink
+// message. Route changes are only considered if they affect default routes in
+// the main routing table, since other route changes (e.g. those made by
+// container runtimes) rarely affect external connectivity.
+func classifyNetlinkMessage(message *syscall.NetlinkMessage) (notification, bool) {
+	switch message.Header.Type {
+	case unix.RTM_NEWLINK, unix.RTM_DELLINK, unix.RTM_NEWADDR, unix.RTM_DELADDR:
+		return notificationAddresses, true
+	case unix.RTM_NEWROUTE, unix.RTM_DELROUTE:
+		// The route message header begins with the address family, destination
+		// prefix length, source prefix length, type of service, and table
+		// identifier, each encoded as a single byte.
+		if len(message.Data) < unix.SizeofRtMsg {
+			return 0, false
+		}
+		if message.Data[1] == 0 && message.Data[4] == unix.RT_TABLE_MAIN {
+			return notificationRouting, true
+		}
+	}
+	return 0, false
+}
+
+// watchNetlink reads messages from a netlink socket and forwards corresponding
+// notifications until the socket is closed.
+func watchNetlink(socket *os.File, notifications chan<- notification) {
+	buffer := make([]byte, netlinkBufferSize)
+	for {
+		n, err := socket.Read(buffer)
+		if err != nil {
+			// If the socket's receive buffer overflowed, then notifications
+			// were lost, so conservatively treat the overflow as a change.
+			if errors.Is(err, unix.ENOBUFS) {
+				notify(notifications, notificationAddresses)
+				continue
+			}
+			return
+		}
+		messages, err := syscall.ParseNetlinkMessage(buffer[:n])
+		if err != nil {
+			continue
+		}
+		for m := range messages {
+			if kind, ok := classifyNetlinkMessage(&messages[m]); ok {
+				notify(notifications, kind)
+			}
+		}
+	}
+}
+
+// suspendedDuration returns the total amount of time that the system has spent
+// suspended since boot, computed as the difference between the boot clock
+// (which advances during suspension) and the monotonic clock (which doesn't).
+// The monotonic clock is read first so that the time between the two reads
+// doesn't yield a negative duration.
+func suspendedDuration() (time.Duration, error) {
+	var monotonic, boot unix.Timespec
+	if err := unix.ClockGettime(unix.CLOCK_MONOTONIC, &monotonic); err != nil {
+		return 0, fmt.Errorf("unable to read monotonic clock: %w", err)
+	} else if err = unix.ClockGettime(unix.CLOCK_BOOTTIME, &boot); err != nil {
+		return 0, fmt.Errorf("unable to read boot clock: %w", err)
+	}
+	return time.Duration(boot.Nano() - monotonic.Nano()), nil
+}
+
+// watchWake periodically checks for system suspension and forwards wake
+// notifications until cancelled. Linux doesn't provide a wake notification
+// mechanism that doesn't require a D-Bus connection to the login manager, but
+// comparing clocks is cheap and doesn't require querying network configuration.
+func watchWake(ctx context.Context, logger *logging.Logger, initial time.Duration, notifications chan<- notification) {
+	// Create a ticker to regulate checks and defer its shutdown.
+	ticker := time.NewTicker(wakeCheckInterval)
+	defer ticker.Stop()
+
+	// Loop and wait for the ticker or cancellation.
+	last := initial
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			current, err := suspendedDuration()
+			if err != nil {
+				logger.Debug("Unable to compute suspended duration:", err)
+				continue
+			}
+			if current-last > suspensionThreshold {
+				notify(notifications, notificationWake)
+			}
+			last = current
+		}
+	}
+}
+
+// startNotifications starts platform connectivity notifications, which are
+// delivered until the provided context is cancelled. On Linux, network changes
+// are monitored using netlink route and address notifications.
+func startNotifications(ctx context.Context, logger *logging.Logger, notifications chan<- notification) error {
+	// Create and bind the netlink socket.
+	descriptor, err := unix.Socket(unix.AF_NETLINK, unix.SOCK_RAW|unix.SOCK_CLOEXEC|unix.SOCK_NONBLOCK, unix.NETLINK_ROUTE)
+	if err != nil {
+		return fmt.Errorf("unable to create netlink socket: %w", err)
+	}
+	if err := unix.Bind(descriptor, &unix.SockaddrNetlink{Family: unix.AF_NETLINK, Groups: netlinkGroups}); err != nil {
+		unix.Close(descriptor)
+		return fmt.Errorf("unable to bind netlink socket: %w", err)
+	}
+
+	// Wrap the socket in a file so that reads are integrated with the runtime
+	// poller and can be interrupted by closure.
+	socket := os.NewFile(uintptr(descriptor), "netlink")
+
+	// Compute the initial suspended duration.
+	suspended, err := suspendedDuration()
+	if err != nil {
+		socket.Close()
+		return err
+	}
+
+	// Start monitoring and close the socket on cancellation.
+	go watchNetlink(socket, notifications)
+	go watchWake(ctx, logger, suspended, notifications)
+	go func() {
+		<-ctx.Done()
+		socket.Close()
+	}()
+
+	// Success.
+	return nil
+}
@@ -0,0 +1,75 @@
+package connectivity
+
+import (
+	"context"
+	"io"
+	"syscall"
+	"testing"
+
+	"golang.org/x/sys/unix"
+
+	"github.com/mutagen-io/mutagen/pkg/logging"
+)
+
+// TestClassifyNetlinkMessage tests classifyNetlinkMessage.
+func TestClassifyNetlinkMessage(t *testing.T) {
+	// Set up test cases.
+	testCases := []struct {
+		// messageType is the netlink message type.
+		messageType uint16
+		// data is the netlink message payload.
+		data []byte
+		// expected is the expected notification.
+		expected notification
+		// expectedOk indicates whether or not a notification is expected.
+		expectedOk bool
+	}{
+		{unix.RTM_NEWADDR, nil, notificationAddresses, true},
+		{unix.RTM_DELADDR, nil, notificationAddresses, true},
+		{unix.RTM_NEWLINK, nil, notificationAddresses, true},
+		{unix.RTM_DELLINK, nil, notificationAddresses, true},
+		{unix.RTM_NEWROUTE, []byte{unix.AF_INET, 0, 0, 0, unix.RT_TABLE_MAIN, 0, 0, 0, 0, 0, 0, 0}, notificationRouting, true},
+		{unix.RTM_DELROUTE, []byte{unix.AF_INET6, 0, 0, 0, unix.RT_TABLE_MAIN, 0, 0, 0, 0, 0, 0, 0}, notificationRouting, true},
+		{unix.RTM_NEWROUTE, []byte{unix.AF_INET, 16, 0, 0, unix.RT_TABLE_MAIN, 0, 0, 0, 0, 0, 0, 0}, 0, false},
+		{unix.RTM_NEWROUTE, []byte{unix.AF_INET, 0, 0, 0, unix.RT_TABLE_LOCAL, 0, 0, 0, 0, 0, 0, 0}, 0, false},
+		{unix.RTM_NEWROUTE, []byte{unix.AF_INET, 0}, 0, false},
+		{unix.RTM_NEWNEIGH, nil, 0, false},
+	}
+
+	// Process test cases.
+	for i, testCase := range testCases {
+		message := &syscall.NetlinkMessage{
+			Header: syscall.NlMsghdr{Type: testCase.messageType},
+			Data:   testCase.data,
+		}
+		n, ok := classifyNetlinkMessage(message)
+		if ok != testCase.expectedOk {
+			t.Errorf("test index %d: notification presence (%t) does not match expected (%t)",
+				i, ok, testCase.expectedOk,
+			)
+		} else if ok && n != testCase.expected {
+			t.Errorf("test index %d: notification (%d) does not match expected (%d)",
+				i, n, testCase.expected,
+			)
+		}
+	}
+}
+
+// TestSuspendedDuration tests that suspendedDuration succeeds and is sane.
+func TestSuspendedDuration(t *testing.T) {
+	if suspended, err := suspendedDuration(); err != nil {
+		t.Fatal("unable to compute suspended duration:", err)
+	} else if suspended < 0 {
+		t.Error("suspended duration is negative:", suspended)
+	}
+}
+
+// TestStartNotifications tests that netlink notifications can be started.
+func TestStartNotifications(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	notifications := make(chan notification, notificationBufferSize)
+	if err := startNotifications(ctx, logging.NewLogger(logging.LevelDisabled, io.Discard), notifications); err != nil {
+		t.Fatal("unable to start notifications:", err)
+	}
+}
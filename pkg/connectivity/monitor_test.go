@@ -0,0 +1,151 @@
+package connectivity
+
+import (
+	"context"
+	"io"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/mutagen-io/mutagen/pkg/logging"
+)
+
+// TestDetector tests change detection logic.
+func TestDetector(t *testing.T) {
+	// Create a detector.
+	start := time.Unix(1600000000, 0)
+	detector := newDetector(start, "en0=192.168.1.2/24")
+
+	// Ensure that a regular check with no changes isn't flagged.
+	now := start.Add(monitoringInterval)
+	if _, changed := detector.check(now, "en0=192.168.1.2/24"); changed {
+		t.Error("change detected without network or clock change")
+	}
+
+	// Ensure that an address change is detected.
+	now = now.Add(monitoringInterval)
+	if change, changed := detector.check(now, "en0=10.0.0.2/8"); !changed {
+		t.Error("network change not detected")
+	} else if change != ChangeNetwork {
+		t.Error("incorrect change type detected:", change)
+	}
+
+	// Ensure that the new configuration is treated as the baseline.
+	now = now.Add(monitoringInterval)
+	if _, changed := detector.check(now, "en0=10.0.0.2/8"); changed {
+		t.Error("change detected after baseline update")
+	}
+
+	// Ensure that a large wall clock gap is detected as a wake.
+	now = now.Add(time.Hour)
+	if change, changed := detector.check(now, "en0=10.0.0.2/8"); !changed {
+		t.Error("wake not detected")
+	} else if change != ChangeWake {
+		t.Error("incorrect change type detected:", change)
+	}
+}
+
+// TestFingerprint tests that the network configuration fingerprint can be
+// computed and is stable.
+func TestFingerprint(t *testing.T) {
+	first, err := fingerprint()
+	if err != nil {
+		t.Fatal("unable to compute fingerprint:", err)
+	}
+	second, err := fingerprint()
+	if err != nil {
+		t.Fatal("unable to compute fingerprint:", err)
+	}
+	if first != second {
+		t.Error("fingerprint unstable between consecutive computations")
+	}
+}
+
+// TestIsVirtualInterface tests isVirtualInterface.
+func TestIsVirtualInterface(t *testing.T) {
+	// Set up test cases.
+	testCases := []struct {
+		name     string
+		expected bool
+	}{
+		{"en0", false},
+		{"eth0", false},
+		{"wlan0", false},
+		{"utun3", false},
+		{"docker0", true},
+		{"br-1a2b3c4d5e6f", true},
+		{"veth12ab34c", true},
+		{"vEthernet (WSL)", true},
+	}
+
+	// Process test cases.
+	for i, testCase := range testCases {
+		if virtual := isVirtualInterface(testCase.name); virtual != testCase.expected {
+			t.Errorf("test index %d: virtual interface status (%t) does not match expected (%t)",
+				i, virtual, testCase.expected,
+			)
+		}
+	}
+}
+
+// TestFingerprintAddress tests fingerprintAddress.
+func TestFingerprintAddress(t *testing.T) {
+	// Set up test cases.
+	testCases := []struct {
+		address  string
+		expected string
+	}{
+		{"192.168.1.2/24", "192.168.1.2/24"},
+		{"fe80::1234:5678:9abc:def0/64", "fe80::1234:5678:9abc:def0/64"},
+		{"2001:db8:1:2:1234:5678:9abc:def0/64", "2001:db8:1:2::/64"},
+		{"2001:db8:1:2:aaaa:bbbb:cccc:dddd/64", "2001:db8:1:2::/64"},
+	}
+
+	// Process test cases.
+	for i, testCase := range testCases {
+		ip, network, err := net.ParseCIDR(testCase.address)
+		if err != nil {
+			t.Fatalf("test index %d: unable to parse address: %v", i, err)
+		}
+		network.IP = ip
+		if fingerprint := fingerprintAddress(network); fingerprint != testCase.expected {
+			t.Errorf("test index %d: address fingerprint (%s) does not match expected (%s)",
+				i, fingerprint, testCase.expected,
+			)
+		}
+	}
+}
+
+// TestNotify tests that notify doesn't block when a channel is full.
+func TestNotify(t *testing.T) {
+	notifications := make(chan notification, 1)
+	notify(notifications, notificationAddresses)
+	notify(notifications, notificationWake)
+	if n := <-notifications; n != notificationAddresses {
+		t.Error("unexpected notification received:", n)
+	}
+	select {
+	case n := <-notifications:
+		t.Error("dropped notification received:", n)
+	default:
+	}
+}
+
+// TestMonitorCancellation tests that Monitor returns once cancelled.
+func TestMonitorCancellation(t *testing.T) {
+	// Start monitoring.
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan struct{})
+	go func() {
+		Monitor(ctx, logging.NewLogger(logging.LevelDisabled, io.Discard), func(_ Change) {})
+		close(done)
+	}()
+
+	// Cancel monitoring and ensure that it terminates.
+	cancel()
+	select {
+	case <-done:
+	case <-time.After(5 * time.Second):
+		t.Fatal("monitoring did not terminate after cancellation")
+	}
+}
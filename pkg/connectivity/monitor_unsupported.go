@@ -0,0 +1,15 @@
+//go:build !linux && !windows && !(darwin && cgo)
+
+package connectivity
+
+import (
+	"context"
+
+	"github.com/mutagen-io/mutagen/pkg/logging"
+)
+
+// startNotifications starts platform connectivity notifications. On this
+// platform, notifications are unsupported and Monitor falls back to polling.
+func startNotifications(_ context.Context, _ *logging.Logger, _ chan<- notification) error {
+	return errNotificationsUnsupported
+}
@@ -0,0 +1,318 @@
+package connectivity
+
+import (
+	"context"
+	"fmt"
+	"runtime"
+	"sync"
+	"unsafe"
+
+	"golang.org/x/sys/windows"
+
+	"github.com/mutagen-io/mutagen/pkg/logging"
+)
+
+const (
+	// wmClose is the WM_CLOSE window message.
+	wmClose = 0x0010
+	// wmDestroy is the WM_DESTROY window message.
+	wmDestroy = 0x0002
+	// wmPowerBroadcast is the WM_POWERBROADCAST window message.
+	wmPowerBroadcast = 0x0218
+	// pbtAPMResumeSuspend is the PBT_APMRESUMESUSPEND power event, which is
+	// delivered if the system resumes due to user activity.
+	pbtAPMResumeSuspend = 0x0007
+	// pbtAPMResumeAutomatic is the PBT_APMRESUMEAUTOMATIC power event, which is
+	// delivered whenever the system resumes.
+	pbtAPMResumeAutomatic = 0x0012
+
+	// addressCancellationTimeout is the maximum amount of time (in
+	// milliseconds) to wait for a cancelled address change request to complete.
+	addressCancellationTimeout = 1000
+
+	// powerWindowClassName is the window class name used for the hidden window
+	// that receives power broadcasts.
+	powerWindowClassName = "MutagenConnectivityMonitor"
+)
+
+var (
+	// iphlpapi is the IP helper API DLL.
+	iphlpapi = windows.NewLazySystemDLL("iphlpapi.dll")
+	// notifyAddrChange is the NotifyAddrChange function.
+	notifyAddrChange = iphlpapi.NewProc("NotifyAddrChange")
+	// cancelIPChangeNotify is the CancelIPChangeNotify function.
+	cancelIPChangeNotify = iphlpapi.NewProc("CancelIPChangeNotify")
+
+	// kernel32 is the kernel32 DLL.
+	kernel32 = windows.NewLazySystemDLL("kernel32.dll")
+	// getModuleHandleW is the GetModuleHandleW function.
+	getModuleHandleW = kernel32.NewProc("GetModuleHandleW")
+
+	// user32 is the user32 DLL.
+	user32 = windows.NewLazySystemDLL("user32.dll")
+	// registerClassExW is the RegisterClassExW function.
+	registerClassExW = user32.NewProc("RegisterClassExW")
+	// createWindowExW is the CreateWindowExW function.
+	createWindowExW = user32.NewProc("CreateWindowExW")
+	// destroyWindow is the DestroyWindow function.
+	destroyWindow = user32.NewProc("DestroyWindow")
+	// defWindowProcW is the DefWindowProcW function.
+	defWindowProcW = user32.NewProc("DefWindowProcW")
+	// getMessageW is the GetMessageW function.
+	getMessageW = user32.NewProc("GetMessageW")
+	// dispatchMessageW is the DispatchMessageW function.
+	dispatchMessageW = user32.NewProc("DispatchMessageW")
+	// postMessageW is the PostMessageW function.
+	postMessageW = user32.NewProc("PostMessageW")
+	// postQuitMessage is the PostQuitMessage function.
+	postQuitMessage = user32.NewProc("PostQuitMessage")
+)
+
+// windowClass is the Go representation of WNDCLASSEXW.
+type windowClass struct {
+	size       uint32
+	style      uint32
+	procedure  uintptr
+	classExtra int32
+	windowData int32
+	instance   windows.Handle
+	icon       windows.Handle
+	cursor     windows.Handle
+	background windows.Handle
+	menuName   *uint16
+	className  *uint16
+	smallIcon  windows.Handle
+}
+
+// windowMessage is the Go representation of MSG.
+type windowMessage struct {
+	window   uintptr
+	message  uint32
+	wParam   uintptr
+	lParam   uintptr
+	time     uint32
+	pointX   int32
+	pointY   int32
+	reserved uint32
+}
+
+var (
+	// powerWindowsLock serializes access to powerWindows.
+	powerWindowsLock sync.Mutex
+	// powerWindows maps power broadcast window handles to the notification
+	// channels for their monitors.
+	powerWindows = make(map[uintptr]chan<- notification)
+
+	// registerPowerWindowClassOnce ensures that the power window class (and its
+	// window procedure callback, which can't be released) are only created once.
+	registerPowerWindowClassOnce sync.Once
+	// powerWindowClassErr records any error that occurred while registering the
+	// power window class.
+	powerWindowClassErr error
+	// powerWindowInstance is the module instance handle used for the power
+	// window class.
+	powerWindowInstance uintptr
+	// powerWindowClass is the power window class name.
+	powerWindowClass *uint16
+)
+
+// powerWindowProcedure is the window procedure for power broadcast windows.
+func powerWindowProcedure(window, message, wParam, lParam uintptr) uintptr {
+	switch message {
+	case wmPowerBroadcast:
+		if wParam == pbtAPMResumeAutomatic || wParam == pbtAPMResumeSuspend {
+			powerWindowsLock.Lock()
+			notifications, ok := powerWindows[window]
+			powerWindowsLock.Unlock()
+			if ok {
+				notify(notifications, notificationWake)
+			}
+		}
+		return 1
+	case wmDestroy:
+		postQuitMessage.Call(0)
+		return 0
+	}
+	result, _, _ := defWindowProcW.Call(window, message, wParam, lParam)
+	return result
+}
+
+// registerPowerWindowClass registers the power window class.
+func registerPowerWindowClass() {
+	// Determine the module instance handle.
+	instance, _, err := getModuleHandleW.Call(0)
+	if instance == 0 {
+		powerWindowClassErr = fmt.Errorf("unable to query module handle: %w", err)
+		return
+	}
+	powerWindowInstance = instance
+
+	// Convert the class name.
+	powerWindowClass, err = windows.UTF16PtrFromString(powerWindowClassName)
+	if err != nil {
+		powerWindowClassErr = fmt.Errorf("unable to convert class name: %w", err)
+		return
+	}
+
+	// Register the class.
+	class := &windowClass{
+		procedure: windows.NewCallback(powerWindowProcedure),
+		instance:  windows.Handle(instance),
+		className: powerWindowClass,
+	}
+	class.size = uint32(unsafe.Sizeof(*class))
+	if atom, _, err := registerClassExW.Call(uintptr(unsafe.Pointer(class))); atom == 0 {
+		powerWindowClassErr = fmt.Errorf("unable to register window class: %w", err)
+	}
+}
+
+// watchPower creates a hidden window to receive power broadcasts and runs its
+// message loop until cancelled. The window is a (hidden) top-level window rather
+// than a message-only window, since message-only windows don't receive
+// broadcast messages. The window is created and serviced on a locked operating
+// system thread, since windows belong to the thread that created them. Any
+// window creation error is reported via the provided channel, which is closed
+// once the window is created.
+func watchPower(ctx context.Context, notifications chan<- notification, created chan<- error) {
+	// Lock the Goroutine to its current thread.
+	runtime.LockOSThread()
+	defer runtime.UnlockOSThread()
+
+	// Ensure that the window class is registered.
+	registerPowerWindowClassOnce.Do(registerPowerWindowClass)
+	if powerWindowClassErr != nil {
+		created <- powerWindowClassErr
+		return
+	}
+
+	// Create the window and register it. Messages are only dispatched by the
+	// message loop below, so no power broadcasts can be missed between window
+	// creation and registration.
+	window, _, err := createWindowExW.Call(
+		0, uintptr(unsafe.Pointer(powerWindowClass)), uintptr(unsafe.Pointer(powerWindowClass)),
+		0, 0, 0, 0, 0, 0, 0, powerWindowInstance, 0,
+	)
+	if window == 0 {
+		created <- fmt.Errorf("unable to create window: %w", err)
+		return
+	}
+	powerWindowsLock.Lock()
+	powerWindows[window] = notifications
+	powerWindowsLock.Unlock()
+	close(created)
+
+	// Defer removal of the window from the registry.
+	defer func() {
+		powerWindowsLock.Lock()
+		delete(powerWindows, window)
+		powerWindowsLock.Unlock()
+	}()
+
+	// Request window closure on cancellation, which will destroy the window
+	// and terminate the message loop.
+	done := make(chan struct{})
+	defer close(done)
+	go func() {
+		select {
+		case <-ctx.Done():
+			postMessageW.Call(window, wmClose, 0, 0)
+		case <-done:
+		}
+	}()
+
+	// Run the message loop.
+	var message windowMessage
+	for {
+		result, _, _ := getMessageW.Call(uintptr(unsafe.Pointer(&message)), 0, 0, 0)
+		if result == 0 {
+			return
+		} else if int32(result) == -1 {
+			destroyWindow.Call(window)
+			return
+		}
+		dispatchMessageW.Call(uintptr(unsafe.Pointer(&message)))
+	}
+}
+
+// watchAddresses waits for address change notifications and forwards them
+// until cancelled.
+func watchAddresses(ctx context.Context, logger *logging.Logger, notifications chan<- notification) {
+	// Create the event used to signal address changes.
+	changed, err := windows.CreateEvent(nil, 0, 0, nil)
+	if err != nil {
+		logger.Warn("Unable to create address change event:", err)
+		return
+	}
+	defer windows.CloseHandle(changed)
+
+	// Create the event used to signal cancellation.
+	cancelled, err := windows.CreateEvent(nil, 1, 0, nil)
+	if err != nil {
+		logger.Warn("Unable to create cancellation event:", err)
+		return
+	}
+	defer windows.CloseHandle(cancelled)
+
+	// Signal cancellation when the context is cancelled.
+	done := make(chan struct{})
+	defer close(done)
+	go func() {
+		select {
+		case <-ctx.Done():
+			windows.SetEvent(cancelled)
+		case <-done:
+		}
+	}()
+
+	// Loop, registering for and waiting on address change notifications.
+	overlapped := &windows.Overlapped{HEvent: changed}
+	for {
+		var handle windows.Handle
+		result, _, _ := notifyAddrChange.Call(
+			uintptr(unsafe.Pointer(&handle)),
+			uintptr(unsafe.Pointer(overlapped)),
+		)
+		if windows.Errno(result) != windows.ERROR_IO_PENDING {
+			logger.Warn("Unable to register for address change notifications:", windows.Errno(result))
+			return
+		}
+		event, err := windows.WaitForMultipleObjects([]windows.Handle{changed, cancelled}, false, windows.INFINITE)
+		if err != nil || event != windows.WAIT_OBJECT_0 {
+			// Cancel the pending request and wait for its completion to be
+			// signaled, since the overlapped structure must remain valid until
+			// the request completes.
+			cancelIPChangeNotify.Call(uintptr(unsafe.Pointer(overlapped)))
+			windows.WaitForSingleObject(changed, addressCancellationTimeout)
+			return
+		}
+		notify(notifications, notificationAddresses)
+	}
+}
+
+// startNotifications starts platform connectivity notifications, which are
+// delivered until the provided context is cancelled. On Windows, network
+// changes are monitored using NotifyAddrChange and system wake is detected
+// using WM_POWERBROADCAST messages.
+func startNotifications(ctx context.Context, logger *logging.Logger, notifications chan<- notification) error {
+	// Ensure that the required functions are available.
+	if err := notifyAddrChange.Find(); err != nil {
+		return fmt.Errorf("address change notifications unavailable: %w", err)
+	} else if err = cancelIPChangeNotify.Find(); err != nil {
+		return fmt.Errorf("address change notifications unavailable: %w", err)
+	}
+
+	// Start power broadcast monitoring. If we can't receive power broadcasts
+	// (e.g. in a non-interactive session), then we can still monitor address
+	// changes, which will typically accompany a system wake anyway.
+	created := make(chan error, 1)
+	go watchPower(ctx, notifications, created)
+	if err := <-created; err != nil {
+		logger.Warn("Unable to monitor power broadcasts:", err)
+	}
+
+	// Start address change monitoring.
+	go watchAddresses(ctx, logger, notifications)
+
+	// Success.
+	return nil
+}
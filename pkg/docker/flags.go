@@ -160,3 +160,53 @@ func (f *DaemonConnectionFlags) ToURLParameters() map[string]string {
 	// Done.
 	return result
 }
+
+// ExecFlags encodes Docker exec command line flags that control how commands
+// are executed inside a container. These flags can be loaded from Mutagen URL
+// parameters. The zero value of this structure is a valid value corresponding to
+// the absence of any of these flags.
+type ExecFlags struct {
+	// User stores the value of the -u/--user flag.
+	User string
+	// Workdir stores the value of the -w/--workdir flag.
+	Workdir string
+	// Privileged indicates the presence of the --privileged flag.
+	Privileged bool
+}
+
+// LoadExecFlagsFromURLParameters loads Docker exec flags from Mutagen URL
+// parameters. It returns the exec flags along with any remaining parameters,
+// which will need to be processed separately. The original parameters are not
+// modified.
+func LoadExecFlagsFromURLParameters(parameters map[string]string) (*ExecFlags, map[string]string, error) {
+	// Create a zero-valued result (corresponding to no flags) and storage for
+	// the remaining parameters.
+	result := &ExecFlags{}
+	remaining := make(map[string]string, len(parameters))
+
+	// Validate and convert parameters.
+	for key, value := range parameters {
+		switch key {
+		case "user":
+			if value == "" {
+				return nil, nil, errors.New("user parameter has empty value")
+			}
+			result.User = value
+		case "workdir":
+			if value == "" {
+				return nil, nil, errors.New("workdir parameter has empty value")
+			}
+			result.Workdir = value
+		case "privileged":
+			if value != "" {
+				return nil, nil, errors.New("privileged parameter has non-empty value")
+			}
+			result.Privileged = true
+		default:
+			remaining[key] = value
+		}
+	}
+
+	// Success.
+	return result, remaining, nil
+}
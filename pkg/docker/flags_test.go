@@ -1,3 +1,48 @@
 package docker
 
-// TODO: Implement.
+import (
+	"testing"
+)
+
+// TestLoadExecFlagsFromURLParameters tests LoadExecFlagsFromURLParameters.
+func TestLoadExecFlagsFromURLParameters(t *testing.T) {
+	// Set up test cases.
+	testCases := []struct {
+		parameters        map[string]string
+		expectError       bool
+		expectedFlags     ExecFlags
+		expectedRemaining int
+	}{
+		{nil, false, ExecFlags{}, 0},
+		{map[string]string{"context": "remote"}, false, ExecFlags{}, 1},
+		{
+			map[string]string{"user": "1000:1000", "workdir": "/srv", "privileged": "", "host": "tcp://remote:2376"},
+			false,
+			ExecFlags{User: "1000:1000", Workdir: "/srv", Privileged: true},
+			1,
+		},
+		{map[string]string{"user": ""}, true, ExecFlags{}, 0},
+		{map[string]string{"workdir": ""}, true, ExecFlags{}, 0},
+		{map[string]string{"privileged": "true"}, true, ExecFlags{}, 0},
+	}
+
+	// Process test cases.
+	for i, testCase := range testCases {
+		flags, remaining, err := LoadExecFlagsFromURLParameters(testCase.parameters)
+		if err != nil {
+			if !testCase.expectError {
+				t.Errorf("test index %d: unexpected error: %v", i, err)
+			}
+			continue
+		} else if testCase.expectError {
+			t.Errorf("test index %d: error expected", i)
+			continue
+		}
+		if *flags != testCase.expectedFlags {
+			t.Errorf("test index %d: flags do not match expected: %v != %v", i, *flags, testCase.expectedFlags)
+		}
+		if len(remaining) != testCase.expectedRemaining {
+			t.Errorf("test index %d: remaining parameter count mismatch: %d != %d", i, len(remaining), testCase.expectedRemaining)
+		}
+	}
+}
@@ -0,0 +1,50 @@
+package behavior
+
+import (
+	"testing"
+	"time"
+)
+
+// TestCaseSensitiveByPathAssume tests that CaseSensitiveByPath returns the
+// platform assumption without using probe files in assume mode.
+func TestCaseSensitiveByPathAssume(t *testing.T) {
+	if sensitive, usedFiles, err := CaseSensitiveByPath(t.TempDir(), ProbeMode_ProbeModeAssume); err != nil {
+		t.Fatal("unable to determine case sensitivity:", err)
+	} else if usedFiles {
+		t.Error("probe files used in assume mode")
+	} else if sensitive != assumeCaseSensitivity {
+		t.Error("case sensitivity does not match assumption")
+	}
+}
+
+// TestCaseSensitiveByPath tests that CaseSensitiveByPath succeeds in probe
+// mode.
+func TestCaseSensitiveByPath(t *testing.T) {
+	if _, usedFiles, err := CaseSensitiveByPath(t.TempDir(), ProbeMode_ProbeModeProbe); err != nil {
+		t.Fatal("unable to probe case sensitivity:", err)
+	} else if !usedFiles {
+		t.Error("probe files not used in probe mode")
+	}
+}
+
+// TestSupportsSymbolicLinksByPath tests that SupportsSymbolicLinksByPath
+// succeeds in probe mode and detects support on POSIX systems.
+func TestSupportsSymbolicLinksByPath(t *testing.T) {
+	if supported, usedFiles, err := SupportsSymbolicLinksByPath(t.TempDir(), ProbeMode_ProbeModeProbe); err != nil {
+		t.Fatal("unable to probe symbolic link support:", err)
+	} else if !usedFiles {
+		t.Error("probe files not used in probe mode")
+	} else if assumeSymbolicLinkSupport && !supported {
+		t.Error("symbolic links unexpectedly unsupported")
+	}
+}
+
+// TestModificationTimeGranularityByPath tests that
+// ModificationTimeGranularityByPath returns a valid granularity in probe mode.
+func TestModificationTimeGranularityByPath(t *testing.T) {
+	if granularity, _, err := ModificationTimeGranularityByPath(t.TempDir(), ProbeMode_ProbeModeProbe); err != nil {
+		t.Fatal("unable to probe modification time granularity:", err)
+	} else if granularity < time.Nanosecond || granularity > 2*time.Second {
+		t.Error("invalid modification time granularity:", granularity)
+	}
+}
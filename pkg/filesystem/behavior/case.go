@@ -0,0 +1,58 @@
+package behavior
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+
+	"github.com/mutagen-io/mutagen/pkg/filesystem"
+)
+
+const (
+	// caseProbeFileNamePrefix is the prefix used for temporary files created
+	// by the case sensitivity test. It must contain lowercase characters.
+	caseProbeFileNamePrefix = filesystem.TemporaryNamePrefix + "case-test"
+
+	// assumeCaseSensitivity indicates whether or not case sensitivity should
+	// be assumed for the platform. The default filesystems on macOS and Windows
+	// are case-insensitive.
+	assumeCaseSensitivity = runtime.GOOS != "darwin" && runtime.GOOS != "windows"
+)
+
+// CaseSensitiveByPath determines whether or not the filesystem on which the
+// directory at the specified path resides distinguishes between names that
+// differ only in case. The second value returned by this function indicates
+// whether or not probe files were used in determining behavior.
+func CaseSensitiveByPath(path string, probeMode ProbeMode) (bool, bool, error) {
+	// Check the filesystem probing mode and see if we can return an assumption.
+	if probeMode == ProbeMode_ProbeModeAssume {
+		return assumeCaseSensitivity, false, nil
+	} else if !probeMode.Supported() {
+		panic("invalid probe mode")
+	}
+
+	// Create a temporary file.
+	file, err := os.CreateTemp(path, caseProbeFileNamePrefix)
+	if err != nil {
+		return false, true, fmt.Errorf("unable to create test file: %w", err)
+	}
+
+	// Ensure that the file is cleaned up and removed when we're done.
+	defer func() {
+		file.Close()
+		os.Remove(file.Name())
+	}()
+
+	// Check whether or not the file is visible under an uppercase variant of
+	// its name.
+	uppercase := filepath.Join(filepath.Dir(file.Name()), strings.ToUpper(filepath.Base(file.Name())))
+	if _, err := os.Lstat(uppercase); err == nil {
+		return false, true, nil
+	} else if os.IsNotExist(err) {
+		return true, true, nil
+	} else {
+		return false, true, fmt.Errorf("unable to query test file using uppercase name: %w", err)
+	}
+}
@@ -0,0 +1,84 @@
+package behavior
+
+import (
+	"fmt"
+	"os"
+	"runtime"
+	"time"
+
+	"github.com/mutagen-io/mutagen/pkg/filesystem"
+)
+
+const (
+	// modificationTimeProbeFileNamePrefix is the prefix used for temporary
+	// files created by the modification time granularity test.
+	modificationTimeProbeFileNamePrefix = filesystem.TemporaryNamePrefix + "mtime-test"
+)
+
+// modificationTimeGranularities are the candidate modification time
+// granularities, in increasing order.
+var modificationTimeGranularities = []time.Duration{
+	time.Nanosecond,
+	100 * time.Nanosecond,
+	time.Microsecond,
+	time.Millisecond,
+	time.Second,
+	2 * time.Second,
+}
+
+// assumeModificationTimeGranularity returns the modification time granularity
+// that should be assumed for the platform. NTFS records modification times in
+// 100 nanosecond intervals, while modern POSIX filesystems generally provide
+// nanosecond precision.
+func assumeModificationTimeGranularity() time.Duration {
+	if runtime.GOOS == "windows" {
+		return 100 * time.Nanosecond
+	}
+	return time.Nanosecond
+}
+
+// ModificationTimeGranularityByPath determines the granularity with which the
+// filesystem on which the directory at the specified path resides records
+// modification times. The second value returned by this function indicates
+// whether or not probe files were used in determining behavior.
+func ModificationTimeGranularityByPath(path string, probeMode ProbeMode) (time.Duration, bool, error) {
+	// Check the filesystem probing mode and see if we can return an assumption.
+	if probeMode == ProbeMode_ProbeModeAssume {
+		return assumeModificationTimeGranularity(), false, nil
+	} else if !probeMode.Supported() {
+		panic("invalid probe mode")
+	}
+
+	// Create a temporary file.
+	file, err := os.CreateTemp(path, modificationTimeProbeFileNamePrefix)
+	if err != nil {
+		return 0, true, fmt.Errorf("unable to create test file: %w", err)
+	}
+	file.Close()
+	defer os.Remove(file.Name())
+
+	// Set a modification time with an odd number of seconds and non-zero
+	// digits at every sub-second position, so that the truncation or rounding
+	// performed by the filesystem reveals its granularity.
+	probe := time.Unix(1600000001, 999999999)
+	if err := os.Chtimes(file.Name(), probe, probe); err != nil {
+		return 0, true, fmt.Errorf("unable to set test file modification time: %w", err)
+	}
+
+	// Read back the modification time.
+	metadata, err := os.Lstat(file.Name())
+	if err != nil {
+		return 0, true, fmt.Errorf("unable to query test file modification time: %w", err)
+	}
+	recorded := metadata.ModTime().UnixNano()
+
+	// Find the coarsest granularity that's consistent with the recorded value.
+	granularity := modificationTimeGranularities[0]
+	for _, candidate := range modificationTimeGranularities[1:] {
+		if recorded%int64(candidate) != 0 {
+			break
+		}
+		granularity = candidate
+	}
+	return granularity, true, nil
+}
@@ -0,0 +1,58 @@
+package behavior
+
+import (
+	"fmt"
+	"os"
+	"runtime"
+
+	"github.com/mutagen-io/mutagen/pkg/filesystem"
+)
+
+const (
+	// symbolicLinkProbeFileNamePrefix is the prefix used for temporary files
+	// created by the symbolic link support test.
+	symbolicLinkProbeFileNamePrefix = filesystem.TemporaryNamePrefix + "symlink-test"
+
+	// assumeSymbolicLinkSupport indicates whether or not symbolic link support
+	// should be assumed for the platform. Symbolic link creation on Windows
+	// requires either elevated privileges or developer mode.
+	assumeSymbolicLinkSupport = runtime.GOOS != "windows"
+)
+
+// SupportsSymbolicLinksByPath determines whether or not symbolic links can be
+// created in the directory at the specified path. The second value returned by
+// this function indicates whether or not probe files were used in determining
+// behavior.
+func SupportsSymbolicLinksByPath(path string, probeMode ProbeMode) (bool, bool, error) {
+	// Check the filesystem probing mode and see if we can return an assumption.
+	if probeMode == ProbeMode_ProbeModeAssume {
+		return assumeSymbolicLinkSupport, false, nil
+	} else if !probeMode.Supported() {
+		panic("invalid probe mode")
+	}
+
+	// Create a temporary file to reserve a unique name and to serve as the
+	// symbolic link target.
+	file, err := os.CreateTemp(path, symbolicLinkProbeFileNamePrefix)
+	if err != nil {
+		return false, true, fmt.Errorf("unable to create test file: %w", err)
+	}
+	file.Close()
+	defer os.Remove(file.Name())
+
+	// Attempt to create a symbolic link. Any failure is treated as a lack of
+	// support, since we've already verified that we can create content in the
+	// directory.
+	link := file.Name() + "-link"
+	if err := os.Symlink(file.Name(), link); err != nil {
+		return false, true, nil
+	}
+	defer os.Remove(link)
+
+	// Verify that the link was created as a symbolic link.
+	if metadata, err := os.Lstat(link); err != nil {
+		return false, true, fmt.Errorf("unable to query test symbolic link: %w", err)
+	} else {
+		return metadata.Mode()&os.ModeSymlink != 0, true, nil
+	}
+}
@@ -171,6 +171,15 @@ func (d *Directory) CreateSymbolicLink(name, target string) error {
 	return symlinkatRetryingOnEINTR(target, d.descriptor, name)
 }
 
+// CreateDirectorySymbolicLink creates a new symbolic link with the specified
+// name and target inside the directory, marking the symbolic link as targeting
+// a directory on platforms that require it. POSIX systems don't distinguish
+// between symbolic links to files and directories, so this method is equivalent
+// to CreateSymbolicLink.
+func (d *Directory) CreateDirectorySymbolicLink(name, target string) error {
+	return d.CreateSymbolicLink(name, target)
+}
+
 // SetPermissions sets the permissions on the content within the directory
 // specified by name. Ownership information is set first, followed by
 // permissions extracted from the mode using ModePermissionsMask. Ownership
@@ -90,8 +90,11 @@ func (d *Directory) CreateDirectory(name string) error {
 		return err
 	}
 
+	// Compute the full path and fix long paths.
+	path := osvendor.FixLongPath(filepath.Join(d.file.Name(), name))
+
 	// Create the directory.
-	return os.Mkdir(filepath.Join(d.file.Name(), name), 0700)
+	return os.Mkdir(path, 0700)
 }
 
 // CreateTemporaryFile creates a new temporary file using the specified name
@@ -104,8 +107,10 @@ func (d *Directory) CreateTemporaryFile(pattern string) (string, io.WriteCloser,
 		return "", nil, err
 	}
 
-	// Create the temporary file using the standard os implementation.
-	file, err := os.CreateTemp(d.file.Name(), pattern)
+	// Create the temporary file using the standard os implementation. We fix
+	// long paths for the parent directory since the generated name may push
+	// the full path beyond the Windows path length limit.
+	file, err := os.CreateTemp(osvendor.FixLongPath(d.file.Name()), pattern)
 	if err != nil {
 		return "", nil, err
 	}
@@ -127,8 +132,52 @@ func (d *Directory) CreateSymbolicLink(name, target string) error {
 		return err
 	}
 
+	// Compute the full path and fix long paths.
+	path := osvendor.FixLongPath(filepath.Join(d.file.Name(), name))
+
 	// Create the symbolic link.
-	return os.Symlink(target, filepath.Join(d.file.Name(), name))
+	return os.Symlink(target, path)
+}
+
+// symbolicLinkFlagAllowUnprivilegedCreate is the Windows
+// SYMBOLIC_LINK_FLAG_ALLOW_UNPRIVILEGED_CREATE flag, which isn't defined in the
+// golang.org/x/sys/windows package.
+const symbolicLinkFlagAllowUnprivilegedCreate = 0x2
+
+// CreateDirectorySymbolicLink creates a new symbolic link with the specified
+// name and target inside the directory, marking the symbolic link as targeting
+// a directory. Unlike CreateSymbolicLink, this method doesn't depend on the
+// target existing at the time of creation in order to create a directory
+// symbolic link.
+func (d *Directory) CreateDirectorySymbolicLink(name, target string) error {
+	// Verify that the name is valid.
+	if err := ensureValidName(name); err != nil {
+		return err
+	}
+
+	// Compute the full path and fix long paths.
+	path := osvendor.FixLongPath(filepath.Join(d.file.Name(), name))
+
+	// Convert the path and target to UTF-16. Windows only supports backslashes
+	// in symbolic link targets, so we perform that conversion as well.
+	path16, err := windows.UTF16PtrFromString(path)
+	if err != nil {
+		return fmt.Errorf("unable to convert path to UTF-16: %w", err)
+	}
+	target16, err := windows.UTF16PtrFromString(filepath.FromSlash(target))
+	if err != nil {
+		return fmt.Errorf("unable to convert target to UTF-16: %w", err)
+	}
+
+	// Create the symbolic link, allowing unprivileged creation. Older versions
+	// of Windows don't support unprivileged creation and will reject the flag,
+	// in which case we retry without it. This mirrors the logic in os.Symlink.
+	flags := uint32(windows.SYMBOLIC_LINK_FLAG_DIRECTORY)
+	err = windows.CreateSymbolicLink(path16, target16, flags|symbolicLinkFlagAllowUnprivilegedCreate)
+	if err == windows.ERROR_INVALID_PARAMETER {
+		err = windows.CreateSymbolicLink(path16, target16, flags)
+	}
+	return err
 }
 
 // SetPermissions sets the permissions on the content within the directory
@@ -330,8 +379,11 @@ func (d *Directory) ReadContentMetadata(name string) (*Metadata, error) {
 		return nil, err
 	}
 
+	// Compute the full path and fix long paths.
+	path := osvendor.FixLongPath(filepath.Join(d.file.Name(), name))
+
 	// Query metadata.
-	metadata, err := os.Lstat(filepath.Join(d.file.Name(), name))
+	metadata, err := os.Lstat(path)
 	if err != nil {
 		return nil, err
 	}
@@ -425,8 +477,11 @@ func (d *Directory) ReadSymbolicLink(name string) (string, error) {
 		return "", err
 	}
 
+	// Compute the full path and fix long paths.
+	path := osvendor.FixLongPath(filepath.Join(d.file.Name(), name))
+
 	// Read the symbolic link.
-	return os.Readlink(filepath.Join(d.file.Name(), name))
+	return os.Readlink(path)
 }
 
 // RemoveDirectory deletes a directory with the specified name inside the
@@ -487,6 +542,9 @@ func (d *Directory) RemoveSymbolicLink(name string) error {
 	// Compute the full path.
 	path := filepath.Join(d.file.Name(), name)
 
+	// Fix long paths.
+	path = osvendor.FixLongPath(path)
+
 	// On Windows, we need the same type-based fallback logic used in os.Remove
 	// (i.e. trying file removal first and then directory removal), so we just
 	// use that. This is necessary because Windows symbolic links are typed and
@@ -524,6 +582,10 @@ func Rename(
 		targetNameOrPath = filepath.Join(targetDirectory.file.Name(), targetNameOrPath)
 	}
 
+	// Fix long paths.
+	sourceNameOrPath = osvendor.FixLongPath(sourceNameOrPath)
+	targetNameOrPath = osvendor.FixLongPath(targetNameOrPath)
+
 	// Convert paths to UTF-16.
 	sourceNameOrPathUTF16, err := windows.UTF16PtrFromString(sourceNameOrPath)
 	if err != nil {
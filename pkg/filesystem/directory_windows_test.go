@@ -82,3 +82,69 @@ func TestDirectoryLongPaths(t *testing.T) {
 		t.Error("unable to remove file with long name:", err)
 	}
 }
+
+// TestDirectoryDeeplyNestedPaths tests Directory operations on content nested
+// deeply enough that its full path exceeds the default Windows path length
+// limit, even though no individual name does.
+func TestDirectoryDeeplyNestedPaths(t *testing.T) {
+	// Create a temporary directory (that will be automatically removed).
+	temporaryDirectoryPath := t.TempDir()
+
+	// Open the temporary directory for access.
+	closer, _, err := Open(temporaryDirectoryPath, false)
+	if err != nil {
+		t.Fatal("unable to open directory:", err)
+	}
+	directory, ok := closer.(*Directory)
+	if !ok {
+		closer.Close()
+		t.Fatal("opened object is not a directory")
+	}
+
+	// Create a chain of nested directories whose combined path length exceeds
+	// the Windows path length limit. We track opened directories so that they
+	// can be closed (in reverse order) when we're done.
+	const componentName = "node_modules"
+	directories := []*Directory{directory}
+	defer func() {
+		for i := len(directories) - 1; i >= 0; i-- {
+			directories[i].Close()
+		}
+	}()
+	for length := len(temporaryDirectoryPath); length <= windowsLongPathTestingLength; length += len(componentName) + 1 {
+		if err := directory.CreateDirectory(componentName); err != nil {
+			t.Fatal("unable to create nested directory:", err)
+		}
+		if directory, err = directory.OpenDirectory(componentName); err != nil {
+			t.Fatal("unable to open nested directory:", err)
+		}
+		directories = append(directories, directory)
+	}
+
+	// Create a temporary file in the innermost directory.
+	name, file, err := directory.CreateTemporaryFile("file")
+	if err != nil {
+		t.Fatal("unable to create temporary file in nested directory:", err)
+	}
+	file.Close()
+
+	// Ensure that metadata for the file can be read.
+	if _, err := directory.ReadContentMetadata(name); err != nil {
+		t.Error("unable to read metadata for nested file:", err)
+	}
+
+	// Ensure that the file can be renamed.
+	if err := Rename(directory, name, directory, "renamed", false); err != nil {
+		t.Fatal("unable to rename nested file:", err)
+	}
+
+	// Ensure that the file can be marked as hidden.
+	if err := MarkHidden(filepath.Join(directory.file.Name(), "renamed")); err != nil {
+		t.Error("unable to mark nested file as hidden:", err)
+	}
+
+	// Ensure that the file can be removed.
+	if err := directory.RemoveFile("renamed"); err != nil {
+		t.Error("unable to remove nested file:", err)
+	}
+}
@@ -100,7 +100,8 @@ func volumeName(path string) (v string) {
 // path limit imposed by Windows. If path is not easily converted to
 // the extended-length form (for example, if path is a relative path
 // or contains .. elements), or is short enough, fixLongPath returns
-// path unmodified.
+// path unmodified. UNC paths are converted to the extended-length UNC
+// form.
 //
 // See https://msdn.microsoft.com/en-us/library/windows/desktop/aa365247(v=vs.85).aspx#maxpath
 func FixLongPath(path string) string {
@@ -128,25 +129,31 @@ func FixLongPath(path string) string {
 	// to \. The conversion here rewrites / to \ and elides
 	// . elements as well as trailing or duplicate separators. For
 	// simplicity it avoids the conversion entirely for relative
-	// paths or paths containing .. elements. For now,
-	// \\server\share paths are not converted to
-	// \\?\UNC\server\share paths because the rules for doing so
-	// are less well-specified.
+	// paths or paths containing .. elements. Unlike the original
+	// implementation, \\server\share paths are converted to
+	// \\?\UNC\server\share paths, since synchronization roots on
+	// network shares are just as likely to contain deeply nested
+	// content.
+	prefix, start := `\\?`, 0
 	if len(path) >= 2 && path[:2] == `\\` {
-		// Don't canonicalize UNC paths.
-		return path
-	}
-	if !isAbs(path) {
+		// Paths that are already in extended-length or device form
+		// are left unmodified.
+		if len(path) >= 4 && (path[2] == '?' || path[2] == '.') && path[3] == '\\' {
+			return path
+		}
+
+		// Convert the UNC path by skipping one of its leading
+		// separators and using the extended-length UNC prefix.
+		prefix, start = `\\?\UNC`, 1
+	} else if !isAbs(path) {
 		// Relative path
 		return path
 	}
 
-	const prefix = `\\?`
-
 	pathbuf := make([]byte, len(prefix)+len(path)+len(`\`))
 	copy(pathbuf, prefix)
 	n := len(path)
-	r, w := 0, len(prefix)
+	r, w := start, len(prefix)
 	for r < n {
 		switch {
 		case isPathSeparator(path[r]):
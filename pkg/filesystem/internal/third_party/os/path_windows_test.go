@@ -64,6 +64,11 @@ func TestFixLongPath(t *testing.T) {
 		{`C:/long/foo.txt`, `\\?\C:\long\foo.txt`},
 		{`C:\long\foo\\bar\.\baz\\`, `\\?\C:\long\foo\bar\baz`},
 		{`\\unc\path`, `\\unc\path`},
+		{`\\unc\long\foo.txt`, `\\?\UNC\unc\long\foo.txt`},
+		{`\\unc\long\foo\\bar\.\baz\\`, `\\?\UNC\unc\long\foo\bar\baz`},
+		{`\\unc\long\..\foo.txt`, `\\unc\long\..\foo.txt`},
+		{`\\.\long\device`, `\\.\long\device`},
+		{`\\?\UNC\unc\long\foo.txt`, `\\?\UNC\unc\long\foo.txt`},
 		{`long.txt`, `long.txt`},
 		{`C:long.txt`, `C:long.txt`},
 		{`c:\long\..\bar\baz`, `c:\long\..\bar\baz`},
@@ -19,6 +19,11 @@ const (
 	// Mutagen.
 	MutagenDataDirectoryDevelopmentName = ".mutagen-dev"
 
+	// MutagenDataDirectoryEnvironmentVariable is the environment variable
+	// that can be used to override the Mutagen data directory path. If set, it
+	// must specify an absolute path.
+	MutagenDataDirectoryEnvironmentVariable = "MUTAGEN_DATA_DIRECTORY"
+
 	// MutagenGlobalConfigurationName is the name of the global Mutagen
 	// configuration file inside the user's home directory.
 	MutagenGlobalConfigurationName = ".mutagen.yml"
@@ -66,7 +71,7 @@ func Mutagen(create bool, pathComponents ...string) (string, error) {
 	// Check if a data directory path has been explicitly specified. If not,
 	// compute it using the standard procedure. Also track whether or not we
 	// need to mark the directory as hidden on creation.
-	mutagenDataDirectoryPath, ok := os.LookupEnv("MUTAGEN_DATA_DIRECTORY")
+	mutagenDataDirectoryPath, ok := os.LookupEnv(MutagenDataDirectoryEnvironmentVariable)
 	var hide bool
 	if ok {
 		// Validate the provided path.
@@ -9,6 +9,8 @@ import (
 	"golang.org/x/sys/windows"
 
 	aclapi "github.com/hectane/go-acl/api"
+
+	osvendor "github.com/mutagen-io/mutagen/pkg/filesystem/internal/third_party/os"
 )
 
 // OwnershipSpecification is an opaque type that encodes specification of file
@@ -120,6 +122,9 @@ func NewOwnershipSpecification(owner, group string) (*OwnershipSpecification, er
 // Permission setting can be skipped by providing a mode value that yields 0
 // after permission bit masking.
 func SetPermissionsByPath(path string, ownership *OwnershipSpecification, mode Mode) error {
+	// Fix long paths.
+	path = osvendor.FixLongPath(path)
+
 	// Set ownership information, if specified.
 	if ownership != nil && (ownership.ownerSID != nil || ownership.groupSID != nil) {
 		// Compute the information that we're going to set.
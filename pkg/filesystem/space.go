@@ -0,0 +1,9 @@
+package filesystem
+
+import (
+	"errors"
+)
+
+// ErrAvailableSpaceUnsupported indicates that available space queries aren't
+// supported on the current platform.
+var ErrAvailableSpaceUnsupported = errors.New("available space queries unsupported")
@@ -0,0 +1,23 @@
+//go:build darwin || linux
+
+package filesystem
+
+import (
+	"golang.org/x/sys/unix"
+)
+
+// AvailableSpace returns the number of bytes available to unprivileged users
+// on the filesystem containing the specified path.
+func AvailableSpace(path string) (uint64, error) {
+	var metadata unix.Statfs_t
+	for {
+		err := unix.Statfs(path, &metadata)
+		if err == unix.EINTR {
+			continue
+		} else if err != nil {
+			return 0, err
+		}
+		break
+	}
+	return uint64(metadata.Bavail) * uint64(metadata.Bsize), nil
+}
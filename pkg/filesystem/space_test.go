@@ -0,0 +1,16 @@
+package filesystem
+
+import (
+	"testing"
+)
+
+// TestAvailableSpace tests AvailableSpace.
+func TestAvailableSpace(t *testing.T) {
+	if available, err := AvailableSpace(t.TempDir()); err == ErrAvailableSpaceUnsupported {
+		t.Skip()
+	} else if err != nil {
+		t.Fatal("unable to query available space:", err)
+	} else if available == 0 {
+		t.Error("no available space reported for temporary directory")
+	}
+}
@@ -0,0 +1,10 @@
+//go:build !windows && !darwin && !linux
+
+package filesystem
+
+// AvailableSpace returns the number of bytes available on the filesystem
+// containing the specified path. It is not supported on this platform and
+// always returns ErrAvailableSpaceUnsupported.
+func AvailableSpace(_ string) (uint64, error) {
+	return 0, ErrAvailableSpaceUnsupported
+}
@@ -0,0 +1,29 @@
+package filesystem
+
+import (
+	"fmt"
+
+	"golang.org/x/sys/windows"
+
+	osvendor "github.com/mutagen-io/mutagen/pkg/filesystem/internal/third_party/os"
+)
+
+// AvailableSpace returns the number of bytes available to the calling user on
+// the volume containing the specified path.
+func AvailableSpace(path string) (uint64, error) {
+	// Fix long paths.
+	path = osvendor.FixLongPath(path)
+
+	// Convert the path to UTF-16 encoding for the system call.
+	path16, err := windows.UTF16PtrFromString(path)
+	if err != nil {
+		return 0, fmt.Errorf("unable to convert path encoding: %w", err)
+	}
+
+	// Query available space.
+	var available uint64
+	if err := windows.GetDiskFreeSpaceEx(path16, &available, nil, nil); err != nil {
+		return 0, err
+	}
+	return available, nil
+}
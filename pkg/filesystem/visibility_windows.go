@@ -3,10 +3,15 @@ package filesystem
 import (
 	"fmt"
 	"syscall"
+
+	osvendor "github.com/mutagen-io/mutagen/pkg/filesystem/internal/third_party/os"
 )
 
 // MarkHidden ensures that a path is hidden.
 func MarkHidden(path string) error {
+	// Fix long paths.
+	path = osvendor.FixLongPath(path)
+
 	// Convert the path to UTF-16 encoding for the system call.
 	path16, err := syscall.UTF16PtrFromString(path)
 	if err != nil {
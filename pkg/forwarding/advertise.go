@@ -0,0 +1,143 @@
+package forwarding
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"net"
+	"os"
+	"strconv"
+	"strings"
+
+	"github.com/mutagen-io/mutagen/pkg/forwarding/mdns"
+	"github.com/mutagen-io/mutagen/pkg/url"
+)
+
+// defaultMDNSServiceType is the DNS-SD service type used for mDNS
+// advertisement if none is specified.
+const defaultMDNSServiceType = "_http._tcp"
+
+// advertisedHostNameSuffixLength is the number of hexadecimal digits of the
+// session identifier hash included in advertised host names.
+const advertisedHostNameSuffixLength = 8
+
+// advertisedHostName derives the host name used for a session's mDNS address
+// records from the machine's host name and the session identifier. The
+// machine's own host name can't be used since its address records are owned by
+// the system's mDNS responder (if any), so a session-specific name is derived.
+func advertisedHostName(hostname, sessionIdentifier string) string {
+	// Compute the session-specific suffix.
+	digest := sha256.Sum256([]byte(sessionIdentifier))
+	suffix := "-mutagen-" + hex.EncodeToString(digest[:])[:advertisedHostNameSuffixLength]
+
+	// Strip any domain components from the host name and truncate it so that
+	// the result fits within a single DNS label.
+	hostname = strings.SplitN(hostname, ".", 2)[0]
+	if maximum := 63 - len(suffix); len(hostname) > maximum {
+		hostname = hostname[:maximum]
+	}
+	return hostname + suffix
+}
+
+// advertisedAddresses computes the addresses to advertise for a listener bound
+// to the specified host. Only IPv4 addresses are advertised, since mDNS
+// advertisement is only performed via IPv4. Listeners bound to unspecified
+// addresses are advertised using the non-loopback IPv4 addresses of all
+// interfaces. Listeners that are only reachable via loopback or IPv6 addresses
+// can't be advertised.
+func advertisedAddresses(host string) ([]net.IP, error) {
+	// Parse the bound address.
+	ip := net.ParseIP(host)
+	if ip == nil {
+		return nil, fmt.Errorf("invalid listener address: %s", host)
+	} else if ip.IsLoopback() {
+		return nil, errors.New("listener is only reachable via loopback")
+	} else if !ip.IsUnspecified() {
+		if ip.To4() == nil {
+			return nil, errors.New("listener is only reachable via IPv6")
+		}
+		return []net.IP{ip}, nil
+	}
+
+	// Enumerate interface addresses.
+	interfaceAddresses, err := net.InterfaceAddrs()
+	if err != nil {
+		return nil, fmt.Errorf("unable to enumerate interface addresses: %w", err)
+	}
+	var result []net.IP
+	for _, address := range interfaceAddresses {
+		network, ok := address.(*net.IPNet)
+		if !ok || network.IP.IsLoopback() || network.IP.To4() == nil {
+			continue
+		}
+		result = append(result, network.IP)
+	}
+	if len(result) == 0 {
+		return nil, errors.New("no non-loopback IPv4 interface addresses available")
+	}
+
+	// Success.
+	return result, nil
+}
+
+// advertise starts advertising the source listener via mDNS using the session
+// configuration. Advertisement failures are logged but not fatal, in which case
+// nil is returned.
+func (c *controller) advertise(source Endpoint) *mdns.Advertiser {
+	// Only local listeners can be advertised, since remote listeners aren't
+	// reachable via this host.
+	if c.session.Source.Protocol != url.Protocol_Local {
+		c.logger.Warn("mDNS advertisement is only supported for local source listeners")
+		return nil
+	}
+
+	// Determine the bound host and port. Non-TCP listeners will fail here.
+	host, portString, err := net.SplitHostPort(boundAddress(source))
+	if err != nil {
+		c.logger.Warnf("Unable to advertise non-TCP source listener via mDNS: %v", err)
+		return nil
+	}
+	port, err := strconv.ParseUint(portString, 10, 16)
+	if err != nil {
+		c.logger.Warnf("Unable to advertise source listener via mDNS: invalid port: %v", err)
+		return nil
+	}
+
+	// Compute the addresses to advertise.
+	addresses, err := advertisedAddresses(host)
+	if err != nil {
+		c.logger.Warnf("Unable to advertise source listener via mDNS: %v", err)
+		return nil
+	}
+
+	// Compute the host name to advertise.
+	hostname, err := os.Hostname()
+	if err != nil {
+		c.logger.Warnf("Unable to advertise source listener via mDNS: unable to determine host name: %v", err)
+		return nil
+	}
+	hostname = advertisedHostName(hostname, c.session.Identifier)
+
+	// Compute the service type.
+	serviceType := c.session.Configuration.MdnsServiceType
+	if serviceType == "" {
+		serviceType = defaultMDNSServiceType
+	}
+
+	// Start advertising.
+	advertiser, err := mdns.Advertise(c.logger.Sublogger("mdns"), &mdns.Service{
+		Instance:  c.session.Configuration.MdnsServiceName,
+		Type:      serviceType,
+		Host:      hostname,
+		Port:      uint16(port),
+		Addresses: addresses,
+	})
+	if err != nil {
+		c.logger.Warnf("Unable to advertise source listener via mDNS: %v", err)
+		return nil
+	}
+
+	// Success.
+	return advertiser
+}
@@ -0,0 +1,65 @@
+package forwarding
+
+import (
+	"strings"
+	"testing"
+)
+
+// TestAdvertisedAddresses tests advertisedAddresses.
+func TestAdvertisedAddresses(t *testing.T) {
+	// Set up test cases.
+	testCases := []struct {
+		host        string
+		expectError bool
+	}{
+		{"192.168.1.10", false},
+		{"fe80::1", true},
+		{"127.0.0.1", true},
+		{"::1", true},
+		{"localhost", true},
+	}
+
+	// Process test cases.
+	for _, testCase := range testCases {
+		addresses, err := advertisedAddresses(testCase.host)
+		if testCase.expectError {
+			if err == nil {
+				t.Errorf("expected error for host %s", testCase.host)
+			}
+		} else if err != nil {
+			t.Errorf("unexpected error for host %s: %v", testCase.host, err)
+		} else if len(addresses) != 1 || addresses[0].String() != testCase.host {
+			t.Errorf("unexpected addresses for host %s: %v", testCase.host, addresses)
+		}
+	}
+
+	// Verify that unspecified addresses don't yield IPv6 addresses.
+	for _, host := range []string{"0.0.0.0", "::"} {
+		if addresses, err := advertisedAddresses(host); err == nil {
+			for _, address := range addresses {
+				if address.To4() == nil || address.IsLoopback() {
+					t.Errorf("unexpected address for unspecified host %s: %v", host, address)
+				}
+			}
+		}
+	}
+}
+
+// TestAdvertisedHostName tests advertisedHostName.
+func TestAdvertisedHostName(t *testing.T) {
+	// Verify that names are session-specific and distinct from the host name.
+	first := advertisedHostName("workstation.example.com", "fwrd_first")
+	second := advertisedHostName("workstation.example.com", "fwrd_second")
+	if first == second {
+		t.Error("advertised host names not session-specific:", first)
+	} else if !strings.HasPrefix(first, "workstation-mutagen-") {
+		t.Error("advertised host name not derived from host name:", first)
+	} else if strings.Contains(first, ".") {
+		t.Error("advertised host name contains domain components:", first)
+	}
+
+	// Verify that long host names are truncated to fit within a DNS label.
+	if name := advertisedHostName(strings.Repeat("a", 100), "fwrd_first"); len(name) > 63 {
+		t.Error("advertised host name exceeds DNS label length:", len(name))
+	}
+}
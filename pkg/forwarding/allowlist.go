@@ -0,0 +1,67 @@
+package forwarding
+
+import (
+	"fmt"
+	"net"
+	"strings"
+)
+
+// SourceAllowlist is a parsed set of client addresses permitted to connect to a
+// TCP listener. A nil allowlist permits all addresses.
+type SourceAllowlist []*net.IPNet
+
+// ParseSourceAllowlist parses a list of IP address and CIDR block
+// specifications into an allowlist. If the list is empty, then a nil allowlist
+// is returned.
+func ParseSourceAllowlist(specifications []string) (SourceAllowlist, error) {
+	// Handle the empty case.
+	if len(specifications) == 0 {
+		return nil, nil
+	}
+
+	// Parse specifications.
+	result := make(SourceAllowlist, len(specifications))
+	for s, specification := range specifications {
+		if strings.ContainsRune(specification, '/') {
+			_, network, err := net.ParseCIDR(specification)
+			if err != nil {
+				return nil, fmt.Errorf("invalid CIDR block (%s)", specification)
+			}
+			result[s] = network
+		} else if ip := net.ParseIP(specification); ip == nil {
+			return nil, fmt.Errorf("invalid IP address (%s)", specification)
+		} else if ip4 := ip.To4(); ip4 != nil {
+			result[s] = &net.IPNet{IP: ip4, Mask: net.CIDRMask(8*net.IPv4len, 8*net.IPv4len)}
+		} else {
+			result[s] = &net.IPNet{IP: ip, Mask: net.CIDRMask(8*net.IPv6len, 8*net.IPv6len)}
+		}
+	}
+
+	// Success.
+	return result, nil
+}
+
+// Allows returns whether or not the allowlist permits connections from the
+// specified client address. Addresses other than TCP addresses (e.g. those of
+// Unix domain socket clients) are always permitted, since the allowlist only
+// applies to TCP listeners.
+func (l SourceAllowlist) Allows(address net.Addr) bool {
+	// A nil allowlist permits all addresses.
+	if l == nil {
+		return true
+	}
+
+	// Only TCP addresses are subject to the allowlist.
+	tcpAddress, ok := address.(*net.TCPAddr)
+	if !ok {
+		return true
+	}
+
+	// Check for a matching entry.
+	for _, network := range l {
+		if network.Contains(tcpAddress.IP) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,68 @@
+package forwarding
+
+import (
+	"net"
+	"testing"
+)
+
+// TestParseSourceAllowlist tests ParseSourceAllowlist.
+func TestParseSourceAllowlist(t *testing.T) {
+	// Set up test cases.
+	testCases := []struct {
+		specifications []string
+		expectFailure  bool
+	}{
+		{nil, false},
+		{[]string{"192.168.1.0/24"}, false},
+		{[]string{"10.0.0.5", "::1", "fd00::/8"}, false},
+		{[]string{"192.168.1.0/33"}, true},
+		{[]string{"localhost"}, true},
+		{[]string{""}, true},
+	}
+
+	// Process test cases.
+	for _, testCase := range testCases {
+		if _, err := ParseSourceAllowlist(testCase.specifications); err != nil && !testCase.expectFailure {
+			t.Errorf("unable to parse allowlist (%v): %v", testCase.specifications, err)
+		} else if err == nil && testCase.expectFailure {
+			t.Errorf("invalid allowlist (%v) parsed successfully", testCase.specifications)
+		}
+	}
+}
+
+// TestSourceAllowlistAllows tests SourceAllowlist.Allows.
+func TestSourceAllowlistAllows(t *testing.T) {
+	// Create an allowlist.
+	allowlist, err := ParseSourceAllowlist([]string{"192.168.1.0/24", "10.0.0.5", "::1"})
+	if err != nil {
+		t.Fatal("unable to parse allowlist:", err)
+	}
+
+	// Set up test cases.
+	testCases := []struct {
+		address  net.Addr
+		expected bool
+	}{
+		{&net.TCPAddr{IP: net.IPv4(192, 168, 1, 20), Port: 50000}, true},
+		{&net.TCPAddr{IP: net.IPv4(192, 168, 2, 20), Port: 50000}, false},
+		{&net.TCPAddr{IP: net.IPv4(10, 0, 0, 5), Port: 50000}, true},
+		{&net.TCPAddr{IP: net.IPv4(10, 0, 0, 6), Port: 50000}, false},
+		{&net.TCPAddr{IP: net.IPv6loopback, Port: 50000}, true},
+		{&net.TCPAddr{IP: net.ParseIP("::ffff:192.168.1.20"), Port: 50000}, true},
+		{&net.UnixAddr{Name: "@", Net: "unix"}, true},
+	}
+
+	// Process test cases.
+	for _, testCase := range testCases {
+		if allowed := allowlist.Allows(testCase.address); allowed != testCase.expected {
+			t.Errorf("allowance for %v (%t) does not match expected (%t)",
+				testCase.address, allowed, testCase.expected,
+			)
+		}
+	}
+
+	// Verify that a nil allowlist permits all addresses.
+	if !SourceAllowlist(nil).Allows(&net.TCPAddr{IP: net.IPv4(203, 0, 113, 1)}) {
+		t.Error("nil allowlist rejected address")
+	}
+}
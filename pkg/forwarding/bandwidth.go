@@ -0,0 +1,100 @@
+package forwarding
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/mutagen-io/mutagen/pkg/stream"
+)
+
+// rateLimiter limits the rate of data transfer using a token bucket with a
+// capacity of one second's worth of transfer. It is safe for concurrent usage,
+// allowing a single limit to be shared by all connections in a session. A nil
+// rate limiter imposes no limit.
+type rateLimiter struct {
+	// rate is the maximum transfer rate (in bytes per second).
+	rate float64
+	// lock guards available and updated.
+	lock sync.Mutex
+	// available is the number of bytes that may be transferred without delay.
+	// It may be negative if transfers have exceeded the limit, in which case
+	// subsequent transfers are delayed until the deficit is recovered.
+	available float64
+	// updated is the time at which available was last updated.
+	updated time.Time
+}
+
+// newRateLimiter creates a new rate limiter with the specified maximum rate (in
+// bytes per second). If rate is 0, then nil is returned.
+func newRateLimiter(rate uint64) *rateLimiter {
+	if rate == 0 {
+		return nil
+	}
+	return &rateLimiter{
+		rate:      float64(rate),
+		available: float64(rate),
+		updated:   time.Now(),
+	}
+}
+
+// reserve records the transfer of the specified number of bytes and returns the
+// amount of time that the caller should wait before transferring more data.
+func (l *rateLimiter) reserve(amount uint64) time.Duration {
+	// Lock the limiter and defer its release.
+	l.lock.Lock()
+	defer l.lock.Unlock()
+
+	// Replenish the available allowance based on the time elapsed since the
+	// last update, but cap it at the bucket capacity.
+	now := time.Now()
+	l.available += now.Sub(l.updated).Seconds() * l.rate
+	if l.available > l.rate {
+		l.available = l.rate
+	}
+	l.updated = now
+
+	// Deduct the transfer and compute any delay needed to recover a deficit.
+	l.available -= float64(amount)
+	if l.available >= 0 {
+		return 0
+	}
+	return time.Duration(-l.available / l.rate * float64(time.Second))
+}
+
+// wait records the transfer of the specified number of bytes and blocks until
+// further transfer is permitted by the limit or the context is cancelled.
+func (l *rateLimiter) wait(ctx context.Context, amount uint64) {
+	// If there's no limit, then there's nothing to wait for.
+	if l == nil {
+		return
+	}
+
+	// Compute the required delay, if any.
+	delay := l.reserve(amount)
+	if delay == 0 {
+		return
+	}
+
+	// Wait for the delay to elapse.
+	timer := time.NewTimer(delay)
+	defer timer.Stop()
+	select {
+	case <-timer.C:
+	case <-ctx.Done():
+	}
+}
+
+// throttleAuditor wraps an auditor so that each audited transfer is subject to
+// the specified rate limit. Since auditors are invoked synchronously with each
+// forwarded write, blocking within the auditor throttles forwarding. If limiter
+// is nil, then auditor is returned unmodified.
+func throttleAuditor(ctx context.Context, auditor stream.Auditor, limiter *rateLimiter) stream.Auditor {
+	if limiter == nil {
+		return auditor
+	}
+	return func(amount uint64) {
+		auditor(amount)
+		limiter.wait(ctx, amount)
+	}
+}
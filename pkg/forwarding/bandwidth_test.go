@@ -0,0 +1,68 @@
+package forwarding
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+// TestRateLimiterNil tests that a nil rate limiter imposes no limit.
+func TestRateLimiterNil(t *testing.T) {
+	limiter := newRateLimiter(0)
+	if limiter != nil {
+		t.Fatal("non-nil limiter created for zero rate")
+	}
+	start := time.Now()
+	limiter.wait(context.Background(), 1<<30)
+	if time.Since(start) > time.Second {
+		t.Error("nil limiter imposed delay")
+	}
+}
+
+// TestRateLimiterReserve tests that a rate limiter permits bursts of up to one
+// second's worth of transfer and delays transfers in excess of that.
+func TestRateLimiterReserve(t *testing.T) {
+	// Create a limiter.
+	limiter := newRateLimiter(1000)
+
+	// Verify that an initial burst is permitted without delay.
+	if delay := limiter.reserve(1000); delay != 0 {
+		t.Error("initial burst delayed:", delay)
+	}
+
+	// Verify that an excess transfer incurs a proportional delay.
+	delay := limiter.reserve(500)
+	if delay < 400*time.Millisecond || delay > 500*time.Millisecond {
+		t.Error("unexpected delay for excess transfer:", delay)
+	}
+}
+
+// TestRateLimiterCancellation tests that waiting on a rate limiter is
+// interrupted by context cancellation.
+func TestRateLimiterCancellation(t *testing.T) {
+	// Create a limiter and a cancelled context.
+	limiter := newRateLimiter(1)
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	// Verify that a transfer requiring a lengthy delay returns promptly.
+	start := time.Now()
+	limiter.wait(ctx, 3600)
+	if time.Since(start) > time.Second {
+		t.Error("wait not interrupted by cancellation")
+	}
+}
+
+// TestThrottleAuditor tests that throttleAuditor invokes the underlying
+// auditor.
+func TestThrottleAuditor(t *testing.T) {
+	var total uint64
+	auditor := throttleAuditor(context.Background(), func(amount uint64) {
+		total += amount
+	}, newRateLimiter(1<<20))
+	auditor(100)
+	auditor(200)
+	if total != 300 {
+		t.Error("audited total mismatch:", total, "!= 300")
+	}
+}
@@ -2,8 +2,14 @@ package forwarding
 
 import (
 	"errors"
+	"fmt"
+	"net"
+	"strings"
+	"time"
 
+	"github.com/mutagen-io/mutagen/pkg/comparison"
 	"github.com/mutagen-io/mutagen/pkg/filesystem"
+	"github.com/mutagen-io/mutagen/pkg/forwarding/mdns"
 )
 
 // EnsureValid ensures that Configuration's invariants are respected. The
@@ -15,6 +21,71 @@ func (c *Configuration) EnsureValid(endpointSpecific bool) error {
 		return errors.New("nil configuration")
 	}
 
+	// Verify that HTTP routes are unset for endpoint-specific configurations
+	// and that any specified routes are valid.
+	if endpointSpecific && len(c.HttpRoutes) > 0 {
+		return errors.New("HTTP routes cannot be specified on an endpoint-specific basis")
+	}
+	for _, route := range c.HttpRoutes {
+		if _, err := parseHTTPRoute(route); err != nil {
+			return fmt.Errorf("invalid HTTP route (%s): %w", route, err)
+		}
+	}
+
+	// Verify that the maximum connection count is unset for endpoint-specific
+	// configurations.
+	if endpointSpecific && c.MaximumConnections != 0 {
+		return errors.New("maximum connections cannot be specified on an endpoint-specific basis")
+	}
+
+	// Verify that health checks are unset for endpoint-specific configurations
+	// and that any specified health check path is valid.
+	if endpointSpecific && (c.HealthCheckInterval != 0 || c.HealthCheckPath != "") {
+		return errors.New("health checks cannot be specified on an endpoint-specific basis")
+	}
+	if c.HealthCheckPath != "" && !strings.HasPrefix(c.HealthCheckPath, "/") {
+		return errors.New("health check path must begin with '/'")
+	}
+
+	// Verify that the bind retry maximum interval isn't less than the bind
+	// retry interval.
+	if c.BindRetryMaximumInterval != 0 {
+		interval := c.BindRetryInterval
+		if interval == 0 {
+			interval = uint32(autoReconnectInterval / time.Second)
+		}
+		if c.BindRetryMaximumInterval < interval {
+			return errors.New("bind retry maximum interval less than bind retry interval")
+		}
+	}
+
+	// Verify that the reconnect hold timeout is unset for endpoint-specific
+	// configurations.
+	if endpointSpecific && c.ReconnectHoldTimeout != 0 {
+		return errors.New("reconnect hold timeout cannot be specified on an endpoint-specific basis")
+	}
+
+	// Verify that rate limits are unset for endpoint-specific configurations.
+	if endpointSpecific && (c.MaximumUploadRate != 0 || c.MaximumDownloadRate != 0) {
+		return errors.New("rate limits cannot be specified on an endpoint-specific basis")
+	}
+
+	// Verify that mDNS advertisement parameters are unset for endpoint-specific
+	// configurations and that any specified parameters are valid.
+	if endpointSpecific && (c.MdnsServiceName != "" || c.MdnsServiceType != "") {
+		return errors.New("mDNS advertisement cannot be specified on an endpoint-specific basis")
+	}
+	if c.MdnsServiceName != "" {
+		if err := mdns.ValidateInstance(c.MdnsServiceName); err != nil {
+			return fmt.Errorf("invalid mDNS service name: %w", err)
+		}
+	}
+	if c.MdnsServiceType != "" {
+		if err := mdns.ValidateType(c.MdnsServiceType); err != nil {
+			return fmt.Errorf("invalid mDNS service type: %w", err)
+		}
+	}
+
 	// Verify that the socket overwrite mode is unspecified or supported for
 	// usage.
 	if !(c.SocketOverwriteMode.IsDefault() || c.SocketOverwriteMode.Supported()) {
@@ -35,6 +106,33 @@ func (c *Configuration) EnsureValid(endpointSpecific bool) error {
 		}
 	}
 
+	// Verify that the TLS mode is unspecified or supported for usage.
+	if !(c.TlsMode.IsDefault() || c.TlsMode.Supported()) {
+		return errors.New("unknown or unsupported TLS mode")
+	}
+
+	// Verify that the PROXY protocol mode is unspecified or supported for usage.
+	if !(c.ProxyProtocolMode.IsDefault() || c.ProxyProtocolMode.Supported()) {
+		return errors.New("unknown or unsupported PROXY protocol mode")
+	}
+
+	// Verify that the TLS certificate and key are specified together.
+	if (c.TlsCertificate == "") != (c.TlsKey == "") {
+		return errors.New("TLS certificate and key must be specified together")
+	}
+
+	// Verify that allowed sources are valid.
+	if _, err := ParseSourceAllowlist(c.AllowedSources); err != nil {
+		return fmt.Errorf("invalid allowed sources: %w", err)
+	}
+
+	// Verify that additional addresses are valid.
+	for _, address := range c.AdditionalAddresses {
+		if _, _, err := net.SplitHostPort(address); err != nil {
+			return fmt.Errorf("invalid additional address (%s): %w", address, err)
+		}
+	}
+
 	// We don't verify the socket permission mode because there's not really any
 	// way to know if it's a sane value.
 
@@ -51,10 +149,29 @@ func (c *Configuration) Equal(other *Configuration) bool {
 	}
 
 	// Perform an equivalence check.
-	return c.SocketOverwriteMode == other.SocketOverwriteMode &&
+	return comparison.StringSlicesEqual(c.HttpRoutes, other.HttpRoutes) &&
+		c.MaximumConnections == other.MaximumConnections &&
+		c.HealthCheckInterval == other.HealthCheckInterval &&
+		c.HealthCheckPath == other.HealthCheckPath &&
+		c.BindRetryInterval == other.BindRetryInterval &&
+		c.BindRetryMaximumInterval == other.BindRetryMaximumInterval &&
+		c.ReconnectHoldTimeout == other.ReconnectHoldTimeout &&
+		c.MaximumUploadRate == other.MaximumUploadRate &&
+		c.MaximumDownloadRate == other.MaximumDownloadRate &&
+		c.MdnsServiceName == other.MdnsServiceName &&
+		c.MdnsServiceType == other.MdnsServiceType &&
+		c.SocketOverwriteMode == other.SocketOverwriteMode &&
 		c.SocketOwner == other.SocketOwner &&
 		c.SocketGroup == other.SocketGroup &&
-		c.SocketPermissionMode == other.SocketPermissionMode
+		c.SocketPermissionMode == other.SocketPermissionMode &&
+		c.TlsMode == other.TlsMode &&
+		c.TlsCertificate == other.TlsCertificate &&
+		c.TlsKey == other.TlsKey &&
+		c.TlsServerName == other.TlsServerName &&
+		c.TlsCertificateAuthority == other.TlsCertificateAuthority &&
+		c.ProxyProtocolMode == other.ProxyProtocolMode &&
+		comparison.StringSlicesEqual(c.AllowedSources, other.AllowedSources) &&
+		comparison.StringSlicesEqual(c.AdditionalAddresses, other.AdditionalAddresses)
 }
 
 // MergeConfigurations merges two configurations of differing priorities. Both
@@ -63,6 +180,81 @@ func MergeConfigurations(lower, higher *Configuration) *Configuration {
 	// Create the resulting configuration.
 	result := &Configuration{}
 
+	// Merge HTTP routes. Because routes of equal specificity are resolved in
+	// favor of later routes, higher-priority routes take precedence.
+	result.HttpRoutes = append(result.HttpRoutes, lower.HttpRoutes...)
+	result.HttpRoutes = append(result.HttpRoutes, higher.HttpRoutes...)
+
+	// Merge maximum connections.
+	if higher.MaximumConnections != 0 {
+		result.MaximumConnections = higher.MaximumConnections
+	} else {
+		result.MaximumConnections = lower.MaximumConnections
+	}
+
+	// Merge health check interval.
+	if higher.HealthCheckInterval != 0 {
+		result.HealthCheckInterval = higher.HealthCheckInterval
+	} else {
+		result.HealthCheckInterval = lower.HealthCheckInterval
+	}
+
+	// Merge health check path.
+	if higher.HealthCheckPath != "" {
+		result.HealthCheckPath = higher.HealthCheckPath
+	} else {
+		result.HealthCheckPath = lower.HealthCheckPath
+	}
+
+	// Merge bind retry interval.
+	if higher.BindRetryInterval != 0 {
+		result.BindRetryInterval = higher.BindRetryInterval
+	} else {
+		result.BindRetryInterval = lower.BindRetryInterval
+	}
+
+	// Merge bind retry maximum interval.
+	if higher.BindRetryMaximumInterval != 0 {
+		result.BindRetryMaximumInterval = higher.BindRetryMaximumInterval
+	} else {
+		result.BindRetryMaximumInterval = lower.BindRetryMaximumInterval
+	}
+
+	// Merge reconnect hold timeout.
+	if higher.ReconnectHoldTimeout != 0 {
+		result.ReconnectHoldTimeout = higher.ReconnectHoldTimeout
+	} else {
+		result.ReconnectHoldTimeout = lower.ReconnectHoldTimeout
+	}
+
+	// Merge maximum upload rate.
+	if higher.MaximumUploadRate != 0 {
+		result.MaximumUploadRate = higher.MaximumUploadRate
+	} else {
+		result.MaximumUploadRate = lower.MaximumUploadRate
+	}
+
+	// Merge maximum download rate.
+	if higher.MaximumDownloadRate != 0 {
+		result.MaximumDownloadRate = higher.MaximumDownloadRate
+	} else {
+		result.MaximumDownloadRate = lower.MaximumDownloadRate
+	}
+
+	// Merge mDNS service name.
+	if higher.MdnsServiceName != "" {
+		result.MdnsServiceName = higher.MdnsServiceName
+	} else {
+		result.MdnsServiceName = lower.MdnsServiceName
+	}
+
+	// Merge mDNS service type.
+	if higher.MdnsServiceType != "" {
+		result.MdnsServiceType = higher.MdnsServiceType
+	} else {
+		result.MdnsServiceType = lower.MdnsServiceType
+	}
+
 	// Merge socket overwrite mode.
 	if !higher.SocketOverwriteMode.IsDefault() {
 		result.SocketOverwriteMode = higher.SocketOverwriteMode
@@ -91,6 +283,59 @@ func MergeConfigurations(lower, higher *Configuration) *Configuration {
 		result.SocketPermissionMode = lower.SocketPermissionMode
 	}
 
+	// Merge TLS mode.
+	if !higher.TlsMode.IsDefault() {
+		result.TlsMode = higher.TlsMode
+	} else {
+		result.TlsMode = lower.TlsMode
+	}
+
+	// Merge TLS certificate and key. These are merged as a pair since they're
+	// only meaningful together.
+	if higher.TlsCertificate != "" {
+		result.TlsCertificate = higher.TlsCertificate
+		result.TlsKey = higher.TlsKey
+	} else {
+		result.TlsCertificate = lower.TlsCertificate
+		result.TlsKey = lower.TlsKey
+	}
+
+	// Merge TLS server name.
+	if higher.TlsServerName != "" {
+		result.TlsServerName = higher.TlsServerName
+	} else {
+		result.TlsServerName = lower.TlsServerName
+	}
+
+	// Merge TLS certificate authority.
+	if higher.TlsCertificateAuthority != "" {
+		result.TlsCertificateAuthority = higher.TlsCertificateAuthority
+	} else {
+		result.TlsCertificateAuthority = lower.TlsCertificateAuthority
+	}
+
+	// Merge PROXY protocol handling.
+	if !higher.ProxyProtocolMode.IsDefault() {
+		result.ProxyProtocolMode = higher.ProxyProtocolMode
+	} else {
+		result.ProxyProtocolMode = lower.ProxyProtocolMode
+	}
+
+	// Merge allowed sources. Unlike HTTP routes, these aren't combined, since
+	// a higher-priority allowlist is expected to be more restrictive.
+	if len(higher.AllowedSources) > 0 {
+		result.AllowedSources = higher.AllowedSources
+	} else {
+		result.AllowedSources = lower.AllowedSources
+	}
+
+	// Merge additional addresses.
+	if len(higher.AdditionalAddresses) > 0 {
+		result.AdditionalAddresses = higher.AdditionalAddresses
+	} else {
+		result.AdditionalAddresses = lower.AdditionalAddresses
+	}
+
 	// Done.
 	return result
 }
@@ -29,6 +29,102 @@ type Configuration struct {
 	sizeCache     protoimpl.SizeCache
 	unknownFields protoimpl.UnknownFields
 
+	// HttpRoutes are the routing rules used by HTTP forwarding sessions to
+	// select the destination address for each request. Each rule takes the
+	// form "<match>=<target>", where match is a host ("host"), a host and
+	// path prefix ("host/prefix"), or a path prefix ("/prefix"), and target is
+	// the address to dial on the destination's network.
+	HttpRoutes []string `protobuf:"bytes,1,rep,name=httpRoutes,proto3" json:"httpRoutes,omitempty"`
+	// MaximumConnections is the maximum number of connections that may be
+	// forwarded simultaneously. Connections accepted in excess of this limit
+	// are queued until an existing connection closes, and are rejected if no
+	// capacity becomes available within a short period. A value of 0 indicates
+	// no limit.
+	MaximumConnections uint64 `protobuf:"varint,2,opt,name=maximumConnections,proto3" json:"maximumConnections,omitempty"`
+	// HealthCheckInterval is the interval (in seconds) at which health checks
+	// are performed against the destination. Health checks are only performed
+	// for sessions with a fixed destination. A value of 0 disables health
+	// checks.
+	HealthCheckInterval uint32 `protobuf:"varint,3,opt,name=healthCheckInterval,proto3" json:"healthCheckInterval,omitempty"`
+	// HealthCheckPath is the request path used for HTTP health checks. If
+	// empty, then health checks only verify that a connection to the
+	// destination can be established.
+	HealthCheckPath string `protobuf:"bytes,4,opt,name=healthCheckPath,proto3" json:"healthCheckPath,omitempty"`
+	// BindRetryInterval is the interval (in seconds) to wait before retrying
+	// after a source listener fails to bind (e.g. because its address is
+	// occupied by another process). If unspecified, then the default automatic
+	// reconnection interval is used. This parameter is only meaningful for
+	// source endpoints.
+	BindRetryInterval uint32 `protobuf:"varint,5,opt,name=bindRetryInterval,proto3" json:"bindRetryInterval,omitempty"`
+	// BindRetryMaximumInterval is the maximum interval (in seconds) to wait
+	// between source listener binding attempts. If specified, then the retry
+	// interval doubles after each consecutive failure, up to this maximum. If
+	// unspecified, then no backoff is performed. This parameter is only
+	// meaningful for source endpoints.
+	BindRetryMaximumInterval uint32 `protobuf:"varint,6,opt,name=bindRetryMaximumInterval,proto3" json:"bindRetryMaximumInterval,omitempty"`
+	// ReconnectHoldTimeout is the maximum amount of time (in seconds) that
+	// incoming connections are held while the destination is reconnecting. If
+	// specified, then the source listener is retained across destination
+	// failures and connections accepted while reconnecting are forwarded once
+	// the destination is re-established, rather than being refused. A value of
+	// 0 disables connection holding. Connection holding isn't supported for
+	// port range sessions.
+	ReconnectHoldTimeout uint32 `protobuf:"varint,7,opt,name=reconnectHoldTimeout,proto3" json:"reconnectHoldTimeout,omitempty"`
+	// MaximumUploadRate is the maximum rate (in bytes per second) at which data
+	// is forwarded from source to destination, shared across all connections
+	// in the session. A value of 0 indicates no limit.
+	MaximumUploadRate uint64 `protobuf:"varint,8,opt,name=maximumUploadRate,proto3" json:"maximumUploadRate,omitempty"`
+	// MaximumDownloadRate is the maximum rate (in bytes per second) at which
+	// data is forwarded from destination to source, shared across all
+	// connections in the session. A value of 0 indicates no limit.
+	MaximumDownloadRate uint64 `protobuf:"varint,9,opt,name=maximumDownloadRate,proto3" json:"maximumDownloadRate,omitempty"`
+	// MDNSServiceName is the service instance name under which the source
+	// listener is advertised via mDNS/DNS-SD, allowing other devices on the
+	// local network to discover it. Advertisement is only supported for local
+	// TCP source listeners that aren't bound exclusively to loopback addresses.
+	// If empty, then no advertisement is performed.
+	MdnsServiceName string `protobuf:"bytes,10,opt,name=mdnsServiceName,proto3" json:"mdnsServiceName,omitempty"`
+	// MDNSServiceType is the DNS-SD service type (e.g. "_http._tcp") under
+	// which the source listener is advertised. If empty, then "_http._tcp" is
+	// used.
+	MdnsServiceType string `protobuf:"bytes,11,opt,name=mdnsServiceType,proto3" json:"mdnsServiceType,omitempty"`
+	// TLSMode specifies whether or not TLS should be terminated (for source
+	// endpoints) or originated (for destination endpoints).
+	TlsMode TLSMode `protobuf:"varint,21,opt,name=tlsMode,proto3,enum=forwarding.TLSMode" json:"tlsMode,omitempty"`
+	// TLSCertificate specifies the path to a PEM-encoded certificate (chain)
+	// to use when terminating TLS. If unspecified, then a self-signed
+	// development certificate is used.
+	TlsCertificate string `protobuf:"bytes,22,opt,name=tlsCertificate,proto3" json:"tlsCertificate,omitempty"`
+	// TLSKey specifies the path to the PEM-encoded private key corresponding to
+	// TLSCertificate.
+	TlsKey string `protobuf:"bytes,23,opt,name=tlsKey,proto3" json:"tlsKey,omitempty"`
+	// TLSServerName specifies the server name to use for SNI and certificate
+	// verification when originating TLS. If unspecified, then the host
+	// component of the dialing address is used.
+	TlsServerName string `protobuf:"bytes,24,opt,name=tlsServerName,proto3" json:"tlsServerName,omitempty"`
+	// TLSCertificateAuthority specifies the path to a PEM-encoded certificate
+	// authority bundle to use for certificate verification when originating
+	// TLS. If unspecified, then the system certificate pool is used.
+	TlsCertificateAuthority string `protobuf:"bytes,25,opt,name=tlsCertificateAuthority,proto3" json:"tlsCertificateAuthority,omitempty"`
+	// ProxyProtocolMode specifies whether or not version 2 PROXY protocol
+	// headers should be consumed from incoming connections (for source
+	// endpoints) or emitted on outgoing connections (for destination
+	// endpoints). Headers are not emitted for HTTP sessions.
+	ProxyProtocolMode ProxyProtocolMode `protobuf:"varint,26,opt,name=proxyProtocolMode,proto3,enum=forwarding.ProxyProtocolMode" json:"proxyProtocolMode,omitempty"`
+	// AllowedSources specifies the client addresses permitted to connect to a
+	// TCP listener. Each entry is an IP address or a CIDR block. Connections
+	// from other addresses are closed immediately after being accepted. If
+	// empty, then all client addresses are permitted. This parameter is only
+	// meaningful for source endpoints.
+	AllowedSources []string `protobuf:"bytes,27,rep,name=allowedSources,proto3" json:"allowedSources,omitempty"`
+	// AdditionalAddresses specifies additional addresses on which a TCP
+	// listener should accept connections (e.g. to listen on both IPv4 and IPv6
+	// loopback addresses within a single session). Each entry is a host and
+	// port pair. The primary listening address must bind successfully, but
+	// failures to bind additional addresses are reported per-listener without
+	// failing the endpoint. This parameter is only meaningful for source
+	// endpoints and isn't supported for port range listeners.
+	AdditionalAddresses []string `protobuf:"bytes,28,rep,name=additionalAddresses,proto3" json:"additionalAddresses,omitempty"`
 	// SocketOverwriteMode specifies whether or not existing Unix domain sockets
 	// should be overwritten when creating new listener sockets.
 	SocketOverwriteMode SocketOverwriteMode `protobuf:"varint,41,opt,name=socketOverwriteMode,proto3,enum=forwarding.SocketOverwriteMode" json:"socketOverwriteMode,omitempty"`
@@ -75,6 +171,139 @@ func (*Configuration) Descriptor() ([]byte, []int) {
 	return file_forwarding_configuration_proto_rawDescGZIP(), []int{0}
 }
 
+func (x *Configuration) GetHttpRoutes() []string {
+	if x != nil {
+		return x.HttpRoutes
+	}
+	return nil
+}
+
+func (x *Configuration) GetMaximumConnections() uint64 {
+	if x != nil {
+		return x.MaximumConnections
+	}
+	return 0
+}
+
+func (x *Configuration) GetHealthCheckInterval() uint32 {
+	if x != nil {
+		return x.HealthCheckInterval
+	}
+	return 0
+}
+
+func (x *Configuration) GetHealthCheckPath() string {
+	if x != nil {
+		return x.HealthCheckPath
+	}
+	return ""
+}
+
+func (x *Configuration) GetBindRetryInterval() uint32 {
+	if x != nil {
+		return x.BindRetryInterval
+	}
+	return 0
+}
+
+func (x *Configuration) GetBindRetryMaximumInterval() uint32 {
+	if x != nil {
+		return x.BindRetryMaximumInterval
+	}
+	return 0
+}
+
+func (x *Configuration) GetReconnectHoldTimeout() uint32 {
+	if x != nil {
+		return x.ReconnectHoldTimeout
+	}
+	return 0
+}
+
+func (x *Configuration) GetMaximumUploadRate() uint64 {
+	if x != nil {
+		return x.MaximumUploadRate
+	}
+	return 0
+}
+
+func (x *Configuration) GetMaximumDownloadRate() uint64 {
+	if x != nil {
+		return x.MaximumDownloadRate
+	}
+	return 0
+}
+
+func (x *Configuration) GetMdnsServiceName() string {
+	if x != nil {
+		return x.MdnsServiceName
+	}
+	return ""
+}
+
+func (x *Configuration) GetMdnsServiceType() string {
+	if x != nil {
+		return x.MdnsServiceType
+	}
+	return ""
+}
+
+func (x *Configuration) GetTlsMode() TLSMode {
+	if x != nil {
+		return x.TlsMode
+	}
+	return TLSMode_TLSModeDefault
+}
+
+func (x *Configuration) GetTlsCertificate() string {
+	if x != nil {
+		return x.TlsCertificate
+	}
+	return ""
+}
+
+func (x *Configuration) GetTlsKey() string {
+	if x != nil {
+		return x.TlsKey
+	}
+	return ""
+}
+
+func (x *Configuration) GetTlsServerName() string {
+	if x != nil {
+		return x.TlsServerName
+	}
+	return ""
+}
+
+func (x *Configuration) GetTlsCertificateAuthority() string {
+	if x != nil {
+		return x.TlsCertificateAuthority
+	}
+	return ""
+}
+
+func (x *Configuration) GetProxyProtocolMode() ProxyProtocolMode {
+	if x != nil {
+		return x.ProxyProtocolMode
+	}
+	return ProxyProtocolMode_ProxyProtocolModeDefault
+}
+
+func (x *Configuration) GetAllowedSources() []string {
+	if x != nil {
+		return x.AllowedSources
+	}
+	return nil
+}
+
+func (x *Configuration) GetAdditionalAddresses() []string {
+	if x != nil {
+		return x.AdditionalAddresses
+	}
+	return nil
+}
+
 func (x *Configuration) GetSocketOverwriteMode() SocketOverwriteMode {
 	if x != nil {
 		return x.SocketOverwriteMode
@@ -108,27 +337,86 @@ var File_forwarding_configuration_proto protoreflect.FileDescriptor
 var file_forwarding_configuration_proto_rawDesc = []byte{
 	0x0a, 0x1e, 0x66, 0x6f, 0x72, 0x77, 0x61, 0x72, 0x64, 0x69, 0x6e, 0x67, 0x2f, 0x63, 0x6f, 0x6e,
 	0x66, 0x69, 0x67, 0x75, 0x72, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f,
-	0x12, 0x0a, 0x66, 0x6f, 0x72, 0x77, 0x61, 0x72, 0x64, 0x69, 0x6e, 0x67, 0x1a, 0x26, 0x66, 0x6f,
-	0x72, 0x77, 0x61, 0x72, 0x64, 0x69, 0x6e, 0x67, 0x2f, 0x73, 0x6f, 0x63, 0x6b, 0x65, 0x74, 0x5f,
-	0x6f, 0x76, 0x65, 0x72, 0x77, 0x72, 0x69, 0x74, 0x65, 0x5f, 0x6d, 0x6f, 0x64, 0x65, 0x2e, 0x70,
-	0x72, 0x6f, 0x74, 0x6f, 0x22, 0xda, 0x01, 0x0a, 0x0d, 0x43, 0x6f, 0x6e, 0x66, 0x69, 0x67, 0x75,
-	0x72, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x12, 0x51, 0x0a, 0x13, 0x73, 0x6f, 0x63, 0x6b, 0x65, 0x74,
-	0x4f, 0x76, 0x65, 0x72, 0x77, 0x72, 0x69, 0x74, 0x65, 0x4d, 0x6f, 0x64, 0x65, 0x18, 0x29, 0x20,
-	0x01, 0x28, 0x0e, 0x32, 0x1f, 0x2e, 0x66, 0x6f, 0x72, 0x77, 0x61, 0x72, 0x64, 0x69, 0x6e, 0x67,
-	0x2e, 0x53, 0x6f, 0x63, 0x6b, 0x65, 0x74, 0x4f, 0x76, 0x65, 0x72, 0x77, 0x72, 0x69, 0x74, 0x65,
-	0x4d, 0x6f, 0x64, 0x65, 0x52, 0x13, 0x73, 0x6f, 0x63, 0x6b, 0x65, 0x74, 0x4f, 0x76, 0x65, 0x72,
-	0x77, 0x72, 0x69, 0x74, 0x65, 0x4d, 0x6f, 0x64, 0x65, 0x12, 0x20, 0x0a, 0x0b, 0x73, 0x6f, 0x63,
-	0x6b, 0x65, 0x74, 0x4f, 0x77, 0x6e, 0x65, 0x72, 0x18, 0x2a, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0b,
-	0x73, 0x6f, 0x63, 0x6b, 0x65, 0x74, 0x4f, 0x77, 0x6e, 0x65, 0x72, 0x12, 0x20, 0x0a, 0x0b, 0x73,
-	0x6f, 0x63, 0x6b, 0x65, 0x74, 0x47, 0x72, 0x6f, 0x75, 0x70, 0x18, 0x2b, 0x20, 0x01, 0x28, 0x09,
-	0x52, 0x0b, 0x73, 0x6f, 0x63, 0x6b, 0x65, 0x74, 0x47, 0x72, 0x6f, 0x75, 0x70, 0x12, 0x32, 0x0a,
-	0x14, 0x73, 0x6f, 0x63, 0x6b, 0x65, 0x74, 0x50, 0x65, 0x72, 0x6d, 0x69, 0x73, 0x73, 0x69, 0x6f,
-	0x6e, 0x4d, 0x6f, 0x64, 0x65, 0x18, 0x2c, 0x20, 0x01, 0x28, 0x0d, 0x52, 0x14, 0x73, 0x6f, 0x63,
-	0x6b, 0x65, 0x74, 0x50, 0x65, 0x72, 0x6d, 0x69, 0x73, 0x73, 0x69, 0x6f, 0x6e, 0x4d, 0x6f, 0x64,
-	0x65, 0x42, 0x2e, 0x5a, 0x2c, 0x67, 0x69, 0x74, 0x68, 0x75, 0x62, 0x2e, 0x63, 0x6f, 0x6d, 0x2f,
-	0x6d, 0x75, 0x74, 0x61, 0x67, 0x65, 0x6e, 0x2d, 0x69, 0x6f, 0x2f, 0x6d, 0x75, 0x74, 0x61, 0x67,
-	0x65, 0x6e, 0x2f, 0x70, 0x6b, 0x67, 0x2f, 0x66, 0x6f, 0x72, 0x77, 0x61, 0x72, 0x64, 0x69, 0x6e,
-	0x67, 0x62, 0x06, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x33,
+	0x12, 0x0a, 0x66, 0x6f, 0x72, 0x77, 0x61, 0x72, 0x64, 0x69, 0x6e, 0x67, 0x1a, 0x24, 0x66, 0x6f,
+	0x72, 0x77, 0x61, 0x72, 0x64, 0x69, 0x6e, 0x67, 0x2f, 0x70, 0x72, 0x6f, 0x78, 0x79, 0x5f, 0x70,
+	0x72, 0x6f, 0x74, 0x6f, 0x63, 0x6f, 0x6c, 0x5f, 0x6d, 0x6f, 0x64, 0x65, 0x2e, 0x70, 0x72, 0x6f,
+	0x74, 0x6f, 0x1a, 0x26, 0x66, 0x6f, 0x72, 0x77, 0x61, 0x72, 0x64, 0x69, 0x6e, 0x67, 0x2f, 0x73,
+	0x6f, 0x63, 0x6b, 0x65, 0x74, 0x5f, 0x6f, 0x76, 0x65, 0x72, 0x77, 0x72, 0x69, 0x74, 0x65, 0x5f,
+	0x6d, 0x6f, 0x64, 0x65, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x1a, 0x19, 0x66, 0x6f, 0x72, 0x77,
+	0x61, 0x72, 0x64, 0x69, 0x6e, 0x67, 0x2f, 0x74, 0x6c, 0x73, 0x5f, 0x6d, 0x6f, 0x64, 0x65, 0x2e,
+	0x70, 0x72, 0x6f, 0x74, 0x6f, 0x22, 0xce, 0x08, 0x0a, 0x0d, 0x43, 0x6f, 0x6e, 0x66, 0x69, 0x67,
+	0x75, 0x72, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x12, 0x1e, 0x0a, 0x0a, 0x68, 0x74, 0x74, 0x70, 0x52,
+	0x6f, 0x75, 0x74, 0x65, 0x73, 0x18, 0x01, 0x20, 0x03, 0x28, 0x09, 0x52, 0x0a, 0x68, 0x74, 0x74,
+	0x70, 0x52, 0x6f, 0x75, 0x74, 0x65, 0x73, 0x12, 0x2e, 0x0a, 0x12, 0x6d, 0x61, 0x78, 0x69, 0x6d,
+	0x75, 0x6d, 0x43, 0x6f, 0x6e, 0x6e, 0x65, 0x63, 0x74, 0x69, 0x6f, 0x6e, 0x73, 0x18, 0x02, 0x20,
+	0x01, 0x28, 0x04, 0x52, 0x12, 0x6d, 0x61, 0x78, 0x69, 0x6d, 0x75, 0x6d, 0x43, 0x6f, 0x6e, 0x6e,
+	0x65, 0x63, 0x74, 0x69, 0x6f, 0x6e, 0x73, 0x12, 0x30, 0x0a, 0x13, 0x68, 0x65, 0x61, 0x6c, 0x74,
+	0x68, 0x43, 0x68, 0x65, 0x63, 0x6b, 0x49, 0x6e, 0x74, 0x65, 0x72, 0x76, 0x61, 0x6c, 0x18, 0x03,
+	0x20, 0x01, 0x28, 0x0d, 0x52, 0x13, 0x68, 0x65, 0x61, 0x6c, 0x74, 0x68, 0x43, 0x68, 0x65, 0x63,
+	0x6b, 0x49, 0x6e, 0x74, 0x65, 0x72, 0x76, 0x61, 0x6c, 0x12, 0x28, 0x0a, 0x0f, 0x68, 0x65, 0x61,
+	0x6c, 0x74, 0x68, 0x43, 0x68, 0x65, 0x63, 0x6b, 0x50, 0x61, 0x74, 0x68, 0x18, 0x04, 0x20, 0x01,
+	0x28, 0x09, 0x52, 0x0f, 0x68, 0x65, 0x61, 0x6c, 0x74, 0x68, 0x43, 0x68, 0x65, 0x63, 0x6b, 0x50,
+	0x61, 0x74, 0x68, 0x12, 0x2c, 0x0a, 0x11, 0x62, 0x69, 0x6e, 0x64, 0x52, 0x65, 0x74, 0x72, 0x79,
+	0x49, 0x6e, 0x74, 0x65, 0x72, 0x76, 0x61, 0x6c, 0x18, 0x05, 0x20, 0x01, 0x28, 0x0d, 0x52, 0x11,
+	0x62, 0x69, 0x6e, 0x64, 0x52, 0x65, 0x74, 0x72, 0x79, 0x49, 0x6e, 0x74, 0x65, 0x72, 0x76, 0x61,
+	0x6c, 0x12, 0x3a, 0x0a, 0x18, 0x62, 0x69, 0x6e, 0x64, 0x52, 0x65, 0x74, 0x72, 0x79, 0x4d, 0x61,
+	0x78, 0x69, 0x6d, 0x75, 0x6d, 0x49, 0x6e, 0x74, 0x65, 0x72, 0x76, 0x61, 0x6c, 0x18, 0x06, 0x20,
+	0x01, 0x28, 0x0d, 0x52, 0x18, 0x62, 0x69, 0x6e, 0x64, 0x52, 0x65, 0x74, 0x72, 0x79, 0x4d, 0x61,
+	0x78, 0x69, 0x6d, 0x75, 0x6d, 0x49, 0x6e, 0x74, 0x65, 0x72, 0x76, 0x61, 0x6c, 0x12, 0x32, 0x0a,
+	0x14, 0x72, 0x65, 0x63, 0x6f, 0x6e, 0x6e, 0x65, 0x63, 0x74, 0x48, 0x6f, 0x6c, 0x64, 0x54, 0x69,
+	0x6d, 0x65, 0x6f, 0x75, 0x74, 0x18, 0x07, 0x20, 0x01, 0x28, 0x0d, 0x52, 0x14, 0x72, 0x65, 0x63,
+	0x6f, 0x6e, 0x6e, 0x65, 0x63, 0x74, 0x48, 0x6f, 0x6c, 0x64, 0x54, 0x69, 0x6d, 0x65, 0x6f, 0x75,
+	0x74, 0x12, 0x2c, 0x0a, 0x11, 0x6d, 0x61, 0x78, 0x69, 0x6d, 0x75, 0x6d, 0x55, 0x70, 0x6c, 0x6f,
+	0x61, 0x64, 0x52, 0x61, 0x74, 0x65, 0x18, 0x08, 0x20, 0x01, 0x28, 0x04, 0x52, 0x11, 0x6d, 0x61,
+	0x78, 0x69, 0x6d, 0x75, 0x6d, 0x55, 0x70, 0x6c, 0x6f, 0x61, 0x64, 0x52, 0x61, 0x74, 0x65, 0x12,
+	0x30, 0x0a, 0x13, 0x6d, 0x61, 0x78, 0x69, 0x6d, 0x75, 0x6d, 0x44, 0x6f, 0x77, 0x6e, 0x6c, 0x6f,
+	0x61, 0x64, 0x52, 0x61, 0x74, 0x65, 0x18, 0x09, 0x20, 0x01, 0x28, 0x04, 0x52, 0x13, 0x6d, 0x61,
+	0x78, 0x69, 0x6d, 0x75, 0x6d, 0x44, 0x6f, 0x77, 0x6e, 0x6c, 0x6f, 0x61, 0x64, 0x52, 0x61, 0x74,
+	0x65, 0x12, 0x28, 0x0a, 0x0f, 0x6d, 0x64, 0x6e, 0x73, 0x53, 0x65, 0x72, 0x76, 0x69, 0x63, 0x65,
+	0x4e, 0x61, 0x6d, 0x65, 0x18, 0x0a, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0f, 0x6d, 0x64, 0x6e, 0x73,
+	0x53, 0x65, 0x72, 0x76, 0x69, 0x63, 0x65, 0x4e, 0x61, 0x6d, 0x65, 0x12, 0x28, 0x0a, 0x0f, 0x6d,
+	0x64, 0x6e, 0x73, 0x53, 0x65, 0x72, 0x76, 0x69, 0x63, 0x65, 0x54, 0x79, 0x70, 0x65, 0x18, 0x0b,
+	0x20, 0x01, 0x28, 0x09, 0x52, 0x0f, 0x6d, 0x64, 0x6e, 0x73, 0x53, 0x65, 0x72, 0x76, 0x69, 0x63,
+	0x65, 0x54, 0x79, 0x70, 0x65, 0x12, 0x2d, 0x0a, 0x07, 0x74, 0x6c, 0x73, 0x4d, 0x6f, 0x64, 0x65,
+	0x18, 0x15, 0x20, 0x01, 0x28, 0x0e, 0x32, 0x13, 0x2e, 0x66, 0x6f, 0x72, 0x77, 0x61, 0x72, 0x64,
+	0x69, 0x6e, 0x67, 0x2e, 0x54, 0x4c, 0x53, 0x4d, 0x6f, 0x64, 0x65, 0x52, 0x07, 0x74, 0x6c, 0x73,
+	0x4d, 0x6f, 0x64, 0x65, 0x12, 0x26, 0x0a, 0x0e, 0x74, 0x6c, 0x73, 0x43, 0x65, 0x72, 0x74, 0x69,
+	0x66, 0x69, 0x63, 0x61, 0x74, 0x65, 0x18, 0x16, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0e, 0x74, 0x6c,
+	0x73, 0x43, 0x65, 0x72, 0x74, 0x69, 0x66, 0x69, 0x63, 0x61, 0x74, 0x65, 0x12, 0x16, 0x0a, 0x06,
+	0x74, 0x6c, 0x73, 0x4b, 0x65, 0x79, 0x18, 0x17, 0x20, 0x01, 0x28, 0x09, 0x52, 0x06, 0x74, 0x6c,
+	0x73, 0x4b, 0x65, 0x79, 0x12, 0x24, 0x0a, 0x0d, 0x74, 0x6c, 0x73, 0x53, 0x65, 0x72, 0x76, 0x65,
+	0x72, 0x4e, 0x61, 0x6d, 0x65, 0x18, 0x18, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0d, 0x74, 0x6c, 0x73,
+	0x53, 0x65, 0x72, 0x76, 0x65, 0x72, 0x4e, 0x61, 0x6d, 0x65, 0x12, 0x38, 0x0a, 0x17, 0x74, 0x6c,
+	0x73, 0x43, 0x65, 0x72, 0x74, 0x69, 0x66, 0x69, 0x63, 0x61, 0x74, 0x65, 0x41, 0x75, 0x74, 0x68,
+	0x6f, 0x72, 0x69, 0x74, 0x79, 0x18, 0x19, 0x20, 0x01, 0x28, 0x09, 0x52, 0x17, 0x74, 0x6c, 0x73,
+	0x43, 0x65, 0x72, 0x74, 0x69, 0x66, 0x69, 0x63, 0x61, 0x74, 0x65, 0x41, 0x75, 0x74, 0x68, 0x6f,
+	0x72, 0x69, 0x74, 0x79, 0x12, 0x4b, 0x0a, 0x11, 0x70, 0x72, 0x6f, 0x78, 0x79, 0x50, 0x72, 0x6f,
+	0x74, 0x6f, 0x63, 0x6f, 0x6c, 0x4d, 0x6f, 0x64, 0x65, 0x18, 0x1a, 0x20, 0x01, 0x28, 0x0e, 0x32,
+	0x1d, 0x2e, 0x66, 0x6f, 0x72, 0x77, 0x61, 0x72, 0x64, 0x69, 0x6e, 0x67, 0x2e, 0x50, 0x72, 0x6f,
+	0x78, 0x79, 0x50, 0x72, 0x6f, 0x74, 0x6f, 0x63, 0x6f, 0x6c, 0x4d, 0x6f, 0x64, 0x65, 0x52, 0x11,
+	0x70, 0x72, 0x6f, 0x78, 0x79, 0x50, 0x72, 0x6f, 0x74, 0x6f, 0x63, 0x6f, 0x6c, 0x4d, 0x6f, 0x64,
+	0x65, 0x12, 0x26, 0x0a, 0x0e, 0x61, 0x6c, 0x6c, 0x6f, 0x77, 0x65, 0x64, 0x53, 0x6f, 0x75, 0x72,
+	0x63, 0x65, 0x73, 0x18, 0x1b, 0x20, 0x03, 0x28, 0x09, 0x52, 0x0e, 0x61, 0x6c, 0x6c, 0x6f, 0x77,
+	0x65, 0x64, 0x53, 0x6f, 0x75, 0x72, 0x63, 0x65, 0x73, 0x12, 0x30, 0x0a, 0x13, 0x61, 0x64, 0x64,
+	0x69, 0x74, 0x69, 0x6f, 0x6e, 0x61, 0x6c, 0x41, 0x64, 0x64, 0x72, 0x65, 0x73, 0x73, 0x65, 0x73,
+	0x18, 0x1c, 0x20, 0x03, 0x28, 0x09, 0x52, 0x13, 0x61, 0x64, 0x64, 0x69, 0x74, 0x69, 0x6f, 0x6e,
+	0x61, 0x6c, 0x41, 0x64, 0x64, 0x72, 0x65, 0x73, 0x73, 0x65, 0x73, 0x12, 0x51, 0x0a, 0x13, 0x73,
+	0x6f, 0x63, 0x6b, 0x65, 0x74, 0x4f, 0x76, 0x65, 0x72, 0x77, 0x72, 0x69, 0x74, 0x65, 0x4d, 0x6f,
+	0x64, 0x65, 0x18, 0x29, 0x20, 0x01, 0x28, 0x0e, 0x32, 0x1f, 0x2e, 0x66, 0x6f, 0x72, 0x77, 0x61,
+	0x72, 0x64, 0x69, 0x6e, 0x67, 0x2e, 0x53, 0x6f, 0x63, 0x6b, 0x65, 0x74, 0x4f, 0x76, 0x65, 0x72,
+	0x77, 0x72, 0x69, 0x74, 0x65, 0x4d, 0x6f, 0x64, 0x65, 0x52, 0x13, 0x73, 0x6f, 0x63, 0x6b, 0x65,
+	0x74, 0x4f, 0x76, 0x65, 0x72, 0x77, 0x72, 0x69, 0x74, 0x65, 0x4d, 0x6f, 0x64, 0x65, 0x12, 0x20,
+	0x0a, 0x0b, 0x73, 0x6f, 0x63, 0x6b, 0x65, 0x74, 0x4f, 0x77, 0x6e, 0x65, 0x72, 0x18, 0x2a, 0x20,
+	0x01, 0x28, 0x09, 0x52, 0x0b, 0x73, 0x6f, 0x63, 0x6b, 0x65, 0x74, 0x4f, 0x77, 0x6e, 0x65, 0x72,
+	0x12, 0x20, 0x0a, 0x0b, 0x73, 0x6f, 0x63, 0x6b, 0x65, 0x74, 0x47, 0x72, 0x6f, 0x75, 0x70, 0x18,
+	0x2b, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0b, 0x73, 0x6f, 0x63, 0x6b, 0x65, 0x74, 0x47, 0x72, 0x6f,
+	0x75, 0x70, 0x12, 0x32, 0x0a, 0x14, 0x73, 0x6f, 0x63, 0x6b, 0x65, 0x74, 0x50, 0x65, 0x72, 0x6d,
+	0x69, 0x73, 0x73, 0x69, 0x6f, 0x6e, 0x4d, 0x6f, 0x64, 0x65, 0x18, 0x2c, 0x20, 0x01, 0x28, 0x0d,
+	0x52, 0x14, 0x73, 0x6f, 0x63, 0x6b, 0x65, 0x74, 0x50, 0x65, 0x72, 0x6d, 0x69, 0x73, 0x73, 0x69,
+	0x6f, 0x6e, 0x4d, 0x6f, 0x64, 0x65, 0x42, 0x2e, 0x5a, 0x2c, 0x67, 0x69, 0x74, 0x68, 0x75, 0x62,
+	0x2e, 0x63, 0x6f, 0x6d, 0x2f, 0x6d, 0x75, 0x74, 0x61, 0x67, 0x65, 0x6e, 0x2d, 0x69, 0x6f, 0x2f,
+	0x6d, 0x75, 0x74, 0x61, 0x67, 0x65, 0x6e, 0x2f, 0x70, 0x6b, 0x67, 0x2f, 0x66, 0x6f, 0x72, 0x77,
+	0x61, 0x72, 0x64, 0x69, 0x6e, 0x67, 0x62, 0x06, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x33,
 }
 
 var (
@@ -146,15 +434,19 @@ func file_forwarding_configuration_proto_rawDescGZIP() []byte {
 var file_forwarding_configuration_proto_msgTypes = make([]protoimpl.MessageInfo, 1)
 var file_forwarding_configuration_proto_goTypes = []interface{}{
 	(*Configuration)(nil),    // 0: forwarding.Configuration
-	(SocketOverwriteMode)(0), // 1: forwarding.SocketOverwriteMode
+	(TLSMode)(0),             // 1: forwarding.TLSMode
+	(ProxyProtocolMode)(0),   // 2: forwarding.ProxyProtocolMode
+	(SocketOverwriteMode)(0), // 3: forwarding.SocketOverwriteMode
 }
 var file_forwarding_configuration_proto_depIdxs = []int32{
-	1, // 0: forwarding.Configuration.socketOverwriteMode:type_name -> forwarding.SocketOverwriteMode
-	1, // [1:1] is the sub-list for method output_type
-	1, // [1:1] is the sub-list for method input_type
-	1, // [1:1] is the sub-list for extension type_name
-	1, // [1:1] is the sub-list for extension extendee
-	0, // [0:1] is the sub-list for field type_name
+	1, // 0: forwarding.Configuration.tlsMode:type_name -> forwarding.TLSMode
+	2, // 1: forwarding.Configuration.proxyProtocolMode:type_name -> forwarding.ProxyProtocolMode
+	3, // 2: forwarding.Configuration.socketOverwriteMode:type_name -> forwarding.SocketOverwriteMode
+	3, // [3:3] is the sub-list for method output_type
+	3, // [3:3] is the sub-list for method input_type
+	3, // [3:3] is the sub-list for extension type_name
+	3, // [3:3] is the sub-list for extension extendee
+	0, // [0:3] is the sub-list for field type_name
 }
 
 func init() { file_forwarding_configuration_proto_init() }
@@ -162,7 +454,9 @@ func file_forwarding_configuration_proto_init() {
 	if File_forwarding_configuration_proto != nil {
 		return
 	}
+	file_forwarding_proxy_protocol_mode_proto_init()
 	file_forwarding_socket_overwrite_mode_proto_init()
+	file_forwarding_tls_mode_proto_init()
 	if !protoimpl.UnsafeEnabled {
 		file_forwarding_configuration_proto_msgTypes[0].Exporter = func(v interface{}, i int) interface{} {
 			switch v := v.(*Configuration); i {
@@ -1,3 +1,34 @@
 package forwarding
 
-// TODO: Implement.
+import (
+	"testing"
+)
+
+// TestMergeConfigurationsProxyProtocolMode tests that PROXY protocol modes are
+// merged with explicit higher-priority values taking precedence.
+func TestMergeConfigurationsProxyProtocolMode(t *testing.T) {
+	// Set up test cases.
+	testCases := []struct {
+		lower    ProxyProtocolMode
+		higher   ProxyProtocolMode
+		expected ProxyProtocolMode
+	}{
+		{ProxyProtocolMode_ProxyProtocolModeDefault, ProxyProtocolMode_ProxyProtocolModeDefault, ProxyProtocolMode_ProxyProtocolModeDefault},
+		{ProxyProtocolMode_ProxyProtocolModeEnabled, ProxyProtocolMode_ProxyProtocolModeDefault, ProxyProtocolMode_ProxyProtocolModeEnabled},
+		{ProxyProtocolMode_ProxyProtocolModeEnabled, ProxyProtocolMode_ProxyProtocolModeDisabled, ProxyProtocolMode_ProxyProtocolModeDisabled},
+		{ProxyProtocolMode_ProxyProtocolModeDisabled, ProxyProtocolMode_ProxyProtocolModeEnabled, ProxyProtocolMode_ProxyProtocolModeEnabled},
+	}
+
+	// Process test cases.
+	for i, testCase := range testCases {
+		result := MergeConfigurations(
+			&Configuration{ProxyProtocolMode: testCase.lower},
+			&Configuration{ProxyProtocolMode: testCase.higher},
+		)
+		if result.ProxyProtocolMode != testCase.expected {
+			t.Errorf("test index %d: merged mode (%s) does not match expected (%s)",
+				i, result.ProxyProtocolMode, testCase.expected,
+			)
+		}
+	}
+}
@@ -0,0 +1,63 @@
+package forwarding
+
+import (
+	"net"
+	"time"
+
+	"google.golang.org/protobuf/types/known/durationpb"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+// openConnection records the opening of an incoming connection in the
+// specified state, updating connection counts and statistics, and returns the
+// state for the individual connection. The connection must be recorded as
+// closed using closeConnection.
+func (c *controller) openConnection(state *State, incoming net.Conn) *ConnectionState {
+	// Lock the state and defer its release.
+	c.stateLock.Lock()
+	defer c.stateLock.Unlock()
+
+	// Update connection counts and statistics.
+	state.recordConnection()
+
+	// Create and record the connection state.
+	c.connectionCounter++
+	connection := &ConnectionState{
+		Identifier: c.connectionCounter,
+		Source:     incoming.RemoteAddr().String(),
+		Opened:     timestamppb.Now(),
+	}
+	state.Connections = append(state.Connections, connection)
+
+	// Done.
+	return connection
+}
+
+// recordDial records the opening of the outgoing connection corresponding to
+// an incoming connection, including the time taken to open it (which serves as
+// a round-trip time estimate).
+func (c *controller) recordDial(connection *ConnectionState, target string, latency time.Duration) {
+	c.stateLock.Lock()
+	connection.Target = target
+	connection.RoundTripTime = durationpb.New(latency)
+	c.stateLock.Unlock()
+}
+
+// closeConnection records the closure of a connection opened with
+// openConnection.
+func (c *controller) closeConnection(state *State, connection *ConnectionState) {
+	// Lock the state and defer its release.
+	c.stateLock.Lock()
+	defer c.stateLock.Unlock()
+
+	// Update the open connection count.
+	state.OpenConnections--
+
+	// Remove the connection state.
+	for i, s := range state.Connections {
+		if s == connection {
+			state.Connections = append(state.Connections[:i], state.Connections[i+1:]...)
+			break
+		}
+	}
+}
@@ -0,0 +1,53 @@
+package forwarding
+
+import (
+	"net"
+	"testing"
+	"time"
+
+	"github.com/mutagen-io/mutagen/pkg/state"
+)
+
+// TestConnectionTracking tests that controllers track the states of individual
+// connections.
+func TestConnectionTracking(t *testing.T) {
+	// Create a controller and a state to track connections.
+	c := &controller{stateLock: state.NewTrackingLock(state.NewTracker())}
+	s := &State{Statistics: &SessionStatistics{}}
+
+	// Create a pair of connections to act as incoming connections.
+	first, firstPeer := net.Pipe()
+	defer first.Close()
+	defer firstPeer.Close()
+	second, secondPeer := net.Pipe()
+	defer second.Close()
+	defer secondPeer.Close()
+
+	// Open connections and verify that they're tracked.
+	firstConnection := c.openConnection(s, first)
+	secondConnection := c.openConnection(s, second)
+	if len(s.Connections) != 2 || s.OpenConnections != 2 {
+		t.Fatal("connections not tracked")
+	} else if firstConnection.Identifier == secondConnection.Identifier {
+		t.Error("connection identifiers are not unique")
+	}
+
+	// Record dialing and verify that it's reflected in the connection state.
+	c.recordDial(firstConnection, "localhost:8080", 5*time.Millisecond)
+	if firstConnection.Target != "localhost:8080" {
+		t.Error("connection target mismatch:", firstConnection.Target)
+	} else if firstConnection.RoundTripTime.AsDuration() != 5*time.Millisecond {
+		t.Error("round-trip time mismatch:", firstConnection.RoundTripTime.AsDuration())
+	}
+	if err := s.Connections[0].ensureValid(); err != nil {
+		t.Error("connection state invalid:", err)
+	}
+
+	// Close a connection and verify that only it is removed.
+	c.closeConnection(s, firstConnection)
+	if len(s.Connections) != 1 || s.Connections[0] != secondConnection {
+		t.Error("incorrect connection removed")
+	} else if s.OpenConnections != 1 || s.TotalConnections != 2 {
+		t.Error("connection counts incorrect after closure")
+	}
+}
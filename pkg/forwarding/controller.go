@@ -4,6 +4,7 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"net"
 	"os"
 	"sync"
 	"time"
@@ -12,19 +13,71 @@ import (
 	"google.golang.org/protobuf/types/known/timestamppb"
 
 	"github.com/mutagen-io/mutagen/pkg/encoding"
+	"github.com/mutagen-io/mutagen/pkg/forwarding/socks5"
 	"github.com/mutagen-io/mutagen/pkg/logging"
 	"github.com/mutagen-io/mutagen/pkg/mutagen"
 	"github.com/mutagen-io/mutagen/pkg/prompting"
 	"github.com/mutagen-io/mutagen/pkg/state"
+	"github.com/mutagen-io/mutagen/pkg/stream"
 	"github.com/mutagen-io/mutagen/pkg/url"
+	forwardingurl "github.com/mutagen-io/mutagen/pkg/url/forwarding"
 )
 
 const (
 	// autoReconnectInterval is the period of time to wait before attempting an
 	// automatic reconnect after disconnection or a failed reconnect.
 	autoReconnectInterval = 15 * time.Second
+	// socks5NegotiationTimeout is the maximum amount of time that SOCKS5
+	// clients are given to complete negotiation.
+	socks5NegotiationTimeout = 10 * time.Second
 )
 
+// bindRetryDelay computes the delay before the next source listener binding
+// attempt based on the source configuration and the number of consecutive
+// failed attempts.
+func bindRetryDelay(configuration *Configuration, failures uint64) time.Duration {
+	// Determine the base interval.
+	interval := autoReconnectInterval
+	if configuration.BindRetryInterval != 0 {
+		interval = time.Duration(configuration.BindRetryInterval) * time.Second
+	}
+
+	// If no maximum interval is specified, then there's no backoff.
+	if configuration.BindRetryMaximumInterval == 0 {
+		return interval
+	}
+	maximum := time.Duration(configuration.BindRetryMaximumInterval) * time.Second
+
+	// Double the interval for each consecutive failure beyond the first, up
+	// to the maximum.
+	for i := uint64(1); i < failures && interval < maximum; i++ {
+		interval *= 2
+	}
+	if interval > maximum {
+		interval = maximum
+	}
+	return interval
+}
+
+// endpointError is an error returned by a forwarding loop that records the
+// endpoint from which it originated.
+type endpointError struct {
+	// source indicates whether or not the error originated from the source.
+	source bool
+	// err is the underlying error.
+	err error
+}
+
+// Error implements error.Error.
+func (e *endpointError) Error() string {
+	return e.err.Error()
+}
+
+// Unwrap implements errors.Unwrap.
+func (e *endpointError) Unwrap() error {
+	return e.err
+}
+
 // controller manages and executes a single session.
 type controller struct {
 	// logger is the controller logger.
@@ -50,6 +103,12 @@ type controller struct {
 	mergedDestinationConfiguration *Configuration
 	// state represents the current forwarding state.
 	state *State
+	// statistics are the cumulative session statistics. They are shared by all
+	// state instances and should be accessed with stateLock held.
+	statistics *SessionStatistics
+	// connectionCounter is the identifier assigned to the most recently opened
+	// connection. It should be accessed with stateLock held.
+	connectionCounter uint64
 	// lifecycleLock guards access to disabled, cancel, and done. Only the
 	// current holder of the lifecycle lock may set any of these fields or
 	// invoke cancel. The forwarding loop may close done without holding the
@@ -69,6 +128,10 @@ type controller struct {
 	cancel context.CancelFunc
 	// done will be closed by the current forwarding loop when it exits.
 	done chan struct{}
+	// connectivityChanges is used to signal host connectivity changes to the
+	// forwarding loop. It is buffered, allowing a single signal to be queued.
+	// It is static and safe for concurrent access.
+	connectivityChanges chan struct{}
 }
 
 // newSession creates a new session and corresponding controller.
@@ -178,6 +241,7 @@ func newSession(
 	}
 
 	// Create the controller.
+	statistics := &SessionStatistics{}
 	controller := &controller{
 		logger:                         logger,
 		sessionPath:                    sessionPath,
@@ -185,11 +249,14 @@ func newSession(
 		session:                        session,
 		mergedSourceConfiguration:      mergedSourceConfiguration,
 		mergedDestinationConfiguration: mergedDestinationConfiguration,
+		statistics:                     statistics,
 		state: &State{
 			Session:          session,
 			SourceState:      &EndpointState{},
 			DestinationState: &EndpointState{},
+			Statistics:       statistics,
 		},
+		connectivityChanges: make(chan struct{}, 1),
 	}
 
 	// If the session isn't being created paused, then start a forwarding loop
@@ -227,6 +294,7 @@ func loadSession(logger *logging.Logger, tracker *state.Tracker, identifier stri
 	}
 
 	// Create the controller.
+	statistics := &SessionStatistics{}
 	controller := &controller{
 		logger:      logger,
 		sessionPath: sessionPath,
@@ -240,11 +308,14 @@ func loadSession(logger *logging.Logger, tracker *state.Tracker, identifier stri
 			session.Configuration,
 			session.ConfigurationDestination,
 		),
+		statistics: statistics,
 		state: &State{
 			Session:          session,
 			SourceState:      &EndpointState{},
 			DestinationState: &EndpointState{},
+			Statistics:       statistics,
 		},
+		connectivityChanges: make(chan struct{}, 1),
 	}
 
 	// If the session isn't marked as paused, start a forwarding loop.
@@ -346,6 +417,8 @@ func (c *controller) resume(ctx context.Context, prompter string) error {
 	)
 	c.stateLock.Lock()
 	c.state.SourceState.Connected = (source != nil)
+	c.state.SourceState.BoundAddress = boundAddress(source)
+	c.state.SourceState.Listeners = listenerStates(source)
 	c.stateLock.Unlock()
 
 	// Attempt to connect to destination.
@@ -477,12 +550,51 @@ func (c *controller) halt(_ context.Context, mode controllerHaltMode, prompter s
 	return nil
 }
 
+// handleConnectivityChange notifies the controller of a host network
+// configuration change or system wake. If any of the session's endpoints are
+// reached via a transport that depends on the network, then the forwarding
+// loop (if running) will drop its existing connections and reconnect
+// immediately rather than waiting for them to time out. Sessions whose
+// endpoints are all reached without the network (e.g. local, Docker, WSL, or
+// exec endpoints) are unaffected.
+func (c *controller) handleConnectivityChange() {
+	// Ignore sessions that don't depend on network connectivity.
+	if !c.session.Source.UsesNetwork() && !c.session.Destination.UsesNetwork() {
+		return
+	}
+
+	// Signal the change without blocking. If a signal is already queued, then
+	// there's no need to queue another.
+	select {
+	case c.connectivityChanges <- struct{}{}:
+	default:
+	}
+}
+
+// holdSource wraps a newly connected source endpoint to hold incoming
+// connections across forwarding cycles, if the session is configured to do so.
+func (c *controller) holdSource(source Endpoint) Endpoint {
+	timeout := c.session.Configuration.ReconnectHoldTimeout
+	if timeout == 0 {
+		return source
+	} else if _, ok := source.(IndexedEndpoint); ok {
+		return source
+	}
+	return newHoldingEndpoint(source, time.Duration(timeout)*time.Second)
+}
+
 // run is the main run loop for the controller, managing connectivity and
 // forwarding.
 func (c *controller) run(ctx context.Context, source, destination Endpoint) {
 	// Log run loop entry.
 	c.logger.Debug("Run loop commencing")
 
+	// If we've been provided with a connected source, then wrap it to hold
+	// connections, if configured.
+	if source != nil {
+		source = c.holdSource(source)
+	}
+
 	// Defer resource and state cleanup.
 	defer func() {
 		// Shutdown any endpoints. These might be non-nil if the run loop was
@@ -501,6 +613,7 @@ func (c *controller) run(ctx context.Context, source, destination Endpoint) {
 			Session:          c.session,
 			SourceState:      &EndpointState{},
 			DestinationState: &EndpointState{},
+			Statistics:       c.statistics,
 		}
 		c.stateLock.Unlock()
 
@@ -514,6 +627,19 @@ func (c *controller) run(ctx context.Context, source, destination Endpoint) {
 	// Track the last time that forwarding failed.
 	var lastForwardingFailureTime time.Time
 
+	// Track the number of consecutive source binding failures. This is tracked
+	// outside of the session state since lazily bound listeners only report
+	// binding failures once forwarding starts, after the state for their
+	// connection attempt has been recorded.
+	var bindFailures uint64
+
+	// Discard any connectivity change signal that was queued before this run
+	// loop started, since any connections that it applied to are long gone.
+	select {
+	case <-c.connectivityChanges:
+	default:
+	}
+
 	// Loop until cancelled.
 	for {
 		// Loop until we're connected to both endpoints. We do a non-blocking
@@ -537,12 +663,21 @@ func (c *controller) run(ctx context.Context, source, destination Endpoint) {
 					c.mergedSourceConfiguration,
 					true,
 				)
+				if source != nil {
+					source = c.holdSource(source)
+				}
 			}
 			c.stateLock.Lock()
 			c.state.SourceState.Connected = (source != nil)
+			c.state.SourceState.BoundAddress = boundAddress(source)
+			c.state.SourceState.Listeners = listenerStates(source)
 			if sourceConnectErr != nil {
 				c.state.LastError = fmt.Errorf("unable to connect to source: %w", sourceConnectErr).Error()
+				bindFailures++
+			} else if source != nil {
+				c.state.SourceState.NextBindAttempt = nil
 			}
+			c.state.SourceState.BindFailures = bindFailures
 			c.stateLock.Unlock()
 
 			// Check for cancellation to avoid a spurious connection to
@@ -586,12 +721,30 @@ func (c *controller) run(ctx context.Context, source, destination Endpoint) {
 				break
 			}
 
+			// Determine how long to wait before retrying. Source endpoints are
+			// always listeners, so source connection failures are treated as
+			// binding failures and retried according to the source's bind
+			// retry policy, unless the destination also needs to be retried,
+			// in which case the standard reconnection interval is used.
+			retryDelay := autoReconnectInterval
+			if source == nil {
+				c.stateLock.Lock()
+				if destination != nil {
+					retryDelay = bindRetryDelay(c.mergedSourceConfiguration, bindFailures)
+				}
+				c.state.SourceState.NextBindAttempt = timestamppb.New(time.Now().Add(retryDelay))
+				c.stateLock.Unlock()
+			}
+
 			// If we failed to connect, wait and then retry. Watch for
-			// cancellation in the mean time.
+			// cancellation in the mean time. If a connectivity change occurs,
+			// then retry immediately, since it may have resolved the failure.
 			select {
 			case <-ctx.Done():
 				return
-			case <-time.After(autoReconnectInterval):
+			case <-time.After(retryDelay):
+			case <-c.connectivityChanges:
+				c.logger.Info("Connectivity change detected, retrying connection")
 			}
 		}
 
@@ -602,12 +755,21 @@ func (c *controller) run(ctx context.Context, source, destination Endpoint) {
 		// Create a cancellable subcontext that we can use to manage shutdown.
 		shutdownCtx, forceShutdown := context.WithCancel(ctx)
 
+		// Determine the source endpoint for this forwarding cycle. If the
+		// source is holding connections, then we use a cycle-specific endpoint
+		// so that terminating the cycle doesn't shut down the source.
+		cycleSource := source
+		held, isHeld := source.(*holdingEndpoint)
+		if isHeld {
+			cycleSource = held.cycle()
+		}
+
 		// Create a Goroutine that will shut down (and unblock) endpoints. This
 		// is the only way to unblock forwarding on cancellation.
 		shutdownComplete := make(chan struct{})
 		go func() {
 			<-shutdownCtx.Done()
-			source.Shutdown()
+			cycleSource.Shutdown()
 			destination.Shutdown()
 			close(shutdownComplete)
 		}()
@@ -616,12 +778,12 @@ func (c *controller) run(ctx context.Context, source, destination Endpoint) {
 		forwardingErrors := make(chan error, 1)
 		go func() {
 			c.logger.Debug("Entering forwarding loop")
-			forwardingErrors <- c.forward(source, destination)
+			forwardingErrors <- c.forward(cycleSource, destination)
 		}()
 
-		// Wait for cancellation, an error from forwarding, or an error from
-		// either transport.
-		var cancelled bool
+		// Wait for cancellation, an error from forwarding, an error from either
+		// transport, or a connectivity change.
+		var cancelled, preempted, sourceFailed bool
 		var sessionErr error
 		var forwardingErrorReceived bool
 		select {
@@ -632,12 +794,21 @@ func (c *controller) run(ctx context.Context, source, destination Endpoint) {
 		case sessionErr = <-forwardingErrors:
 			c.logger.Debug("Forwarding loop terminated with error:", sessionErr)
 			forwardingErrorReceived = true
+			var endpointErr *endpointError
+			if errors.As(sessionErr, &endpointErr) && endpointErr.source {
+				sourceFailed = true
+			}
 		case err := <-sourceTransportErrors:
 			c.logger.Debug("Source transport failure:", err)
 			sessionErr = fmt.Errorf("source transport failure: %w", err)
+			sourceFailed = true
 		case err := <-destinationTransportErrors:
 			c.logger.Debug("Destination transport failure:", err)
 			sessionErr = fmt.Errorf("destination transport failure: %w", err)
+		case <-c.connectivityChanges:
+			c.logger.Info("Connectivity change detected, forcing reconnection")
+			sessionErr = errors.New("connection reset due to connectivity change")
+			preempted = true
 		}
 
 		// Force shutdown, which may have already occurred due to cancellation.
@@ -655,18 +826,49 @@ func (c *controller) run(ctx context.Context, source, destination Endpoint) {
 			c.logger.Debug("Forwarding loop terminated")
 		}
 
+		// Determine whether or not the source failed to bind, which occurs
+		// during forwarding for lazily bound listeners. Any other termination
+		// of the forwarding loop indicates that binding succeeded (or wasn't
+		// attempted), so the failure count is reset.
+		var bindErr *BindError
+		bindFailed := sourceFailed && errors.As(sessionErr, &bindErr)
+		if bindFailed {
+			bindFailures++
+		} else {
+			bindFailures = 0
+		}
+
+		// If the source is holding connections and is still functional, then
+		// retain it so that connections accepted while reconnecting can be
+		// forwarded once the destination is re-established. Otherwise, shut it
+		// down fully.
+		retainSource := isHeld && !cancelled && !sourceFailed && !held.hasFailed()
+		if retainSource {
+			c.logger.Info("Holding incoming connections while reconnecting")
+		} else if isHeld {
+			held.Shutdown()
+		}
+
 		// Nil out endpoints to update our state.
-		source = nil
+		if !retainSource {
+			source = nil
+		}
 		destination = nil
 
 		// Reset the forwarding state, but propagate the error that caused
 		// failure.
 		c.stateLock.Lock()
 		c.state = &State{
-			Session:          c.session,
-			LastError:        sessionErr.Error(),
-			SourceState:      &EndpointState{},
+			Session:   c.session,
+			LastError: sessionErr.Error(),
+			SourceState: &EndpointState{
+				Connected:    source != nil,
+				BoundAddress: boundAddress(source),
+				Listeners:    listenerStates(source),
+				BindFailures: bindFailures,
+			},
 			DestinationState: &EndpointState{},
+			Statistics:       c.statistics,
 		}
 		c.stateLock.Unlock()
 
@@ -675,20 +877,170 @@ func (c *controller) run(ctx context.Context, source, destination Endpoint) {
 			return
 		}
 
-		// If less than one auto-reconnect interval has elapsed since the last
-		// forwarding failure, then wait before attempting reconnection.
+		// If the source failed to bind, then wait according to the source's
+		// bind retry policy before attempting reconnection. Otherwise, if less
+		// than one auto-reconnect interval has elapsed since the last
+		// forwarding failure, then wait before attempting reconnection. We skip
+		// the latter wait if forwarding was terminated due to a connectivity
+		// change, since reconnection is expected to succeed.
 		now := time.Now()
-		if now.Sub(lastForwardingFailureTime) < autoReconnectInterval {
+		if bindFailed {
+			retryDelay := bindRetryDelay(c.mergedSourceConfiguration, bindFailures)
+			c.stateLock.Lock()
+			c.state.SourceState.NextBindAttempt = timestamppb.New(now.Add(retryDelay))
+			c.stateLock.Unlock()
+			select {
+			case <-ctx.Done():
+				return
+			case <-time.After(retryDelay):
+			case <-c.connectivityChanges:
+				c.logger.Info("Connectivity change detected, retrying binding")
+			}
+		} else if !preempted && now.Sub(lastForwardingFailureTime) < autoReconnectInterval {
 			select {
 			case <-ctx.Done():
 				return
 			case <-time.After(autoReconnectInterval):
+			case <-c.connectivityChanges:
+				c.logger.Info("Connectivity change detected, reconnecting")
 			}
 		}
 		lastForwardingFailureTime = now
 	}
 }
 
+// admit reserves connection limiter capacity for an incoming connection,
+// waiting for capacity to become available if necessary. If no capacity becomes
+// available, then the connection is closed and false is returned.
+func (c *controller) admit(ctx context.Context, limiter *connectionLimiter, incoming net.Conn) bool {
+	if err := limiter.acquire(ctx, connectionQueueTimeout); err != nil {
+		c.logger.Warnf("Rejecting connection from %s: %v", incoming.RemoteAddr(), err)
+		incoming.Close()
+		return false
+	}
+	return true
+}
+
+// prepareOutgoing performs any preparation of an outgoing connection required
+// by the destination configuration before forwarding from an incoming
+// connection begins. Currently this consists of emitting a PROXY protocol
+// header, if enabled. On failure, both connections are closed and false is
+// returned.
+func (c *controller) prepareOutgoing(outgoing, incoming net.Conn) bool {
+	if c.mergedDestinationConfiguration.ProxyProtocolMode.Enabled() {
+		if err := emitProxyProtocolHeader(outgoing, incoming); err != nil {
+			c.logger.Debugf("Unable to emit PROXY protocol header: %v", err)
+			incoming.Close()
+			outgoing.Close()
+			return false
+		}
+	}
+	return true
+}
+
+// forwardSOCKS5 performs SOCKS5 negotiation with an incoming connection, dials
+// the requested target using the destination, reports the dialing result to
+// the client, and (if dialing succeeded) forwards traffic between the incoming
+// connection and the target. It enforces that the incoming connection is closed
+// by the time this function returns.
+func (c *controller) forwardSOCKS5(
+	ctx context.Context,
+	incoming net.Conn,
+	destination TargetedEndpoint,
+	connection *ConnectionState,
+	incomingAuditor, outgoingAuditor stream.Auditor,
+) {
+	// Perform negotiation, limiting the time that the client has to complete
+	// it.
+	incoming.SetDeadline(time.Now().Add(socks5NegotiationTimeout))
+	target, err := socks5.Negotiate(incoming)
+	if err != nil {
+		c.logger.Debugf("SOCKS5 negotiation failed: %v", err)
+		incoming.Close()
+		return
+	}
+
+	// Dial the target and report the result to the client.
+	dialStart := time.Now()
+	outgoing, err := destination.OpenTarget(target)
+	if err != nil {
+		c.logger.Debugf("Unable to dial SOCKS5 target (%s): %v", target, err)
+		socks5.Reply(incoming, socks5.ReplyCodeForError(err))
+		incoming.Close()
+		return
+	} else if !c.prepareOutgoing(outgoing, incoming) {
+		return
+	} else if err = socks5.Reply(incoming, socks5.ReplySucceeded); err != nil {
+		incoming.Close()
+		outgoing.Close()
+		return
+	}
+	incoming.SetDeadline(time.Time{})
+
+	// Record the dialing result.
+	c.recordDial(connection, target, time.Since(dialStart))
+
+	// Perform forwarding.
+	ForwardAndClose(ctx, incoming, outgoing, incomingAuditor, outgoingAuditor)
+}
+
+// forwardPortRange is the forwarding loop for port range sessions. Each
+// accepted connection is forwarded to the port at the same offset within the
+// destination range. Unlike fixed-target dialing, dialing failures here are
+// specific to a single port in the range (which may simply have no listener)
+// and thus only close the incoming connection rather than terminating
+// forwarding.
+func (c *controller) forwardPortRange(
+	ctx context.Context,
+	state *State,
+	source IndexedEndpoint,
+	destination TargetedEndpoint,
+	destinationRange *forwardingurl.PortRange,
+	limiter *connectionLimiter,
+	auditors func(*ConnectionState) (stream.Auditor, stream.Auditor),
+) error {
+	for {
+		// Accept a connection from the source.
+		incoming, index, err := source.OpenIndexed()
+		if err != nil {
+			return &endpointError{true, fmt.Errorf("unable to accept connection: %w", err)}
+		} else if index < 0 || index >= destinationRange.Size() {
+			incoming.Close()
+			return fmt.Errorf("accepted connection has out-of-range port offset: %d", index)
+		}
+
+		// Wait for capacity to forward the connection.
+		if !c.admit(ctx, limiter, incoming) {
+			continue
+		}
+
+		// Record the connection.
+		connection := c.openConnection(state, incoming)
+
+		// Perform dialing, forwarding, and state updates in a background
+		// Goroutine.
+		go func() {
+			// Dial the corresponding target and perform forwarding.
+			target := destinationRange.Address(index)
+			dialStart := time.Now()
+			if outgoing, err := destination.OpenTarget(target); err != nil {
+				c.logger.Debugf("Unable to open forwarding connection to %s: %v", target, err)
+				incoming.Close()
+			} else if c.prepareOutgoing(outgoing, incoming) {
+				c.recordDial(connection, target, time.Since(dialStart))
+				incomingAuditor, outgoingAuditor := auditors(connection)
+				ForwardAndClose(ctx, incoming, outgoing, incomingAuditor, outgoingAuditor)
+			}
+
+			// Release connection capacity.
+			limiter.release()
+
+			// Record the connection closure.
+			c.closeConnection(state, connection)
+		}()
+	}
+}
+
 // forward is the main forwarding loop for the controller.
 func (c *controller) forward(source, destination Endpoint) error {
 	// Create a context that we can use to regulate the lifecycle of forwarding
@@ -710,16 +1062,113 @@ func (c *controller) forward(source, destination Endpoint) error {
 	state = c.state
 	c.stateLock.Unlock()
 
-	// Create auditor functions to track data transfer.
-	incomingAuditor := func(amount uint64) {
-		c.stateLock.Lock()
-		state.TotalInboundData += amount
-		c.stateLock.Unlock()
+	// Create the connection limiter.
+	limiter := newConnectionLimiter(c.session.Configuration.MaximumConnections)
+
+	// Create the rate limiters. Data written to incoming connections is
+	// downloaded from the destination, while data written to outgoing
+	// connections is uploaded from the source.
+	downloadLimiter := newRateLimiter(c.session.Configuration.MaximumDownloadRate)
+	uploadLimiter := newRateLimiter(c.session.Configuration.MaximumUploadRate)
+
+	// Create a function to create auditors that track data transfer for the
+	// session and (if non-nil) an individual connection, subject to any rate
+	// limits.
+	auditors := func(connection *ConnectionState) (stream.Auditor, stream.Auditor) {
+		incomingAuditor := func(amount uint64) {
+			c.stateLock.Lock()
+			state.TotalInboundData += amount
+			state.Statistics.TotalInboundData += amount
+			if connection != nil {
+				connection.InboundData += amount
+			}
+			c.stateLock.Unlock()
+		}
+		outgoingAuditor := func(amount uint64) {
+			c.stateLock.Lock()
+			state.TotalOutboundData += amount
+			state.Statistics.TotalOutboundData += amount
+			if connection != nil {
+				connection.OutboundData += amount
+			}
+			c.stateLock.Unlock()
+		}
+		return throttleAuditor(ctx, incomingAuditor, downloadLimiter),
+			throttleAuditor(ctx, outgoingAuditor, uploadLimiter)
 	}
-	outgoingAuditor := func(amount uint64) {
-		c.stateLock.Lock()
-		state.TotalOutboundData += amount
-		c.stateLock.Unlock()
+
+	// Wrap the source to consume PROXY protocol headers, if configured. This
+	// must occur before TLS wrapping since headers precede TLS handshakes.
+	source = wrapSourceWithProxyProtocol(source, c.mergedSourceConfiguration)
+
+	// Wrap the endpoints to terminate and originate TLS, if configured.
+	destinationProtocol, destinationAddress, _ := forwardingurl.Parse(c.session.Destination.Path)
+	source, destination, err := wrapEndpointsWithTLS(
+		source, destination,
+		c.mergedSourceConfiguration, c.mergedDestinationConfiguration,
+		destinationAddress,
+	)
+	if err != nil {
+		return err
+	}
+
+	// Determine whether or not the destination dials targets on a
+	// per-connection basis, in which case this is a proxy or port range
+	// session.
+	targeted, isTargeted := destination.(TargetedEndpoint)
+
+	// Start destination health checks, if configured. These are only performed
+	// for fixed-target sessions, since other sessions have no single target to
+	// check. Checks share the destination with the forwarding loop below, so
+	// the destination is wrapped to serialize connection opening.
+	if interval := c.session.Configuration.HealthCheckInterval; interval > 0 && !isTargeted {
+		serialized := &serializedEndpoint{Endpoint: destination}
+		destination = serialized
+		go c.monitorDestinationHealth(
+			ctx, state, serialized,
+			healthCheckHost(destinationAddress),
+			c.session.Configuration.HealthCheckPath,
+			time.Duration(interval)*time.Second,
+		)
+	}
+
+	// Advertise the source listener via mDNS, if configured.
+	if c.session.Configuration.MdnsServiceName != "" {
+		if advertiser := c.advertise(source); advertiser != nil {
+			defer advertiser.Shutdown()
+		}
+	}
+
+	// If this is an HTTP session, then serve requests until there's an error.
+	if sourceProtocol, _, _ := forwardingurl.Parse(c.session.Source.Path); isTargeted && sourceProtocol == "http" {
+		router, err := newHTTPRouter(c.session.Configuration.HttpRoutes)
+		if err != nil {
+			return fmt.Errorf("unable to create HTTP router: %w", err)
+		}
+		connectionTracker := func(delta int) {
+			c.stateLock.Lock()
+			if delta > 0 {
+				state.recordConnection()
+			} else {
+				state.OpenConnections--
+			}
+			c.stateLock.Unlock()
+		}
+		incomingAuditor, outgoingAuditor := auditors(nil)
+		err = serveHTTP(c.logger, source, targeted, router, limiter, incomingAuditor, outgoingAuditor, connectionTracker)
+		return &endpointError{true, fmt.Errorf("unable to accept connection: %w", err)}
+	}
+
+	// If this is a port range session, then connections are accepted with the
+	// offset of the accepting port and forwarded to the port at the same offset
+	// within the destination range.
+	if indexed, ok := source.(IndexedEndpoint); ok && isTargeted {
+		return c.forwardPortRange(
+			ctx, state, indexed, targeted,
+			portRange(destinationProtocol, destinationAddress),
+			limiter,
+			auditors,
+		)
 	}
 
 	// Accept and forward connections until there's an error.
@@ -727,31 +1176,61 @@ func (c *controller) forward(source, destination Endpoint) error {
 		// Accept a connection from the source.
 		incoming, err := source.Open()
 		if err != nil {
-			return fmt.Errorf("unable to accept connection: %w", err)
+			return &endpointError{true, fmt.Errorf("unable to accept connection: %w", err)}
+		}
+
+		// Wait for capacity to forward the connection.
+		if !c.admit(ctx, limiter, incoming) {
+			continue
+		}
+
+		// If this is a SOCKS5 session (the only other type of targeted
+		// session), then perform negotiation, dialing, and forwarding in a
+		// background Goroutine. Unlike fixed-target dialing,
+		// dialing failures here are specific to the requested target and thus
+		// reported to the client rather than terminating forwarding.
+		if isTargeted {
+			connection := c.openConnection(state, incoming)
+			go func() {
+				incomingAuditor, outgoingAuditor := auditors(connection)
+				c.forwardSOCKS5(ctx, incoming, targeted, connection, incomingAuditor, outgoingAuditor)
+				limiter.release()
+				c.closeConnection(state, connection)
+			}()
+			continue
 		}
 
 		// Open the outgoing connection to which we should forward.
+		dialStart := time.Now()
 		outgoing, err := destination.Open()
+		dialLatency := time.Since(dialStart)
 		if err != nil {
 			incoming.Close()
-			return fmt.Errorf("unable to open forwarding connection: %w", err)
+			limiter.release()
+			return &endpointError{false, fmt.Errorf("unable to open forwarding connection: %w", err)}
 		}
 
-		// Increment the open and total connection counts.
-		c.stateLock.Lock()
-		state.OpenConnections++
-		state.TotalConnections++
-		c.stateLock.Unlock()
+		// Prepare the outgoing connection for forwarding.
+		if !c.prepareOutgoing(outgoing, incoming) {
+			limiter.release()
+			continue
+		}
+
+		// Record the connection.
+		connection := c.openConnection(state, incoming)
+		c.recordDial(connection, destinationAddress, dialLatency)
 
 		// Perform forwarding and update state in a background Goroutine.
 		go func() {
 			// Perform forwarding.
+			incomingAuditor, outgoingAuditor := auditors(connection)
 			ForwardAndClose(ctx, incoming, outgoing, incomingAuditor, outgoingAuditor)
 
-			// Decrement open connection counts.
-			c.stateLock.Lock()
-			state.OpenConnections--
-			c.stateLock.Unlock()
+			// Release connection capacity.
+			limiter.release()
+
+			// Record the connection closure.
+			c.closeConnection(state, connection)
 		}()
 	}
 }
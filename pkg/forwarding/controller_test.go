@@ -1,3 +1,37 @@
 package forwarding
 
-// TODO: Implement.
+import (
+	"testing"
+	"time"
+)
+
+// TestBindRetryDelay tests bindRetryDelay.
+func TestBindRetryDelay(t *testing.T) {
+	// Set up test cases.
+	testCases := []struct {
+		configuration *Configuration
+		failures      uint64
+		expected      time.Duration
+	}{
+		{&Configuration{}, 1, autoReconnectInterval},
+		{&Configuration{}, 10, autoReconnectInterval},
+		{&Configuration{BindRetryInterval: 2}, 5, 2 * time.Second},
+		{&Configuration{BindRetryInterval: 1, BindRetryMaximumInterval: 30}, 1, 1 * time.Second},
+		{&Configuration{BindRetryInterval: 1, BindRetryMaximumInterval: 30}, 2, 2 * time.Second},
+		{&Configuration{BindRetryInterval: 1, BindRetryMaximumInterval: 30}, 5, 16 * time.Second},
+		{&Configuration{BindRetryInterval: 1, BindRetryMaximumInterval: 30}, 6, 30 * time.Second},
+		{&Configuration{BindRetryInterval: 1, BindRetryMaximumInterval: 30}, 1000, 30 * time.Second},
+		{&Configuration{BindRetryMaximumInterval: 60}, 2, 30 * time.Second},
+		{&Configuration{BindRetryMaximumInterval: 60}, 3, 60 * time.Second},
+	}
+
+	// Process test cases.
+	for _, testCase := range testCases {
+		delay := bindRetryDelay(testCase.configuration, testCase.failures)
+		if delay != testCase.expected {
+			t.Errorf("delay for %d failures (%v) does not match expected (%v)",
+				testCase.failures, delay, testCase.expected,
+			)
+		}
+	}
+}
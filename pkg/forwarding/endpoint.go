@@ -32,3 +32,88 @@ type Endpoint interface {
 	// Open call.
 	Shutdown() error
 }
+
+// BindError is returned by source endpoints when they're unable to bind their
+// underlying listener. Endpoints that bind lazily return it from Open, which
+// allows the controller to apply the bind retry policy to these failures.
+type BindError struct {
+	// Err is the underlying error.
+	Err error
+}
+
+// Error implements error.Error.
+func (e *BindError) Error() string {
+	return e.Err.Error()
+}
+
+// Unwrap implements errors.Unwrap.
+func (e *BindError) Unwrap() error {
+	return e.Err
+}
+
+// TargetedEndpoint is an optional interface that can be implemented by
+// destination endpoints that dial targets specified on a per-connection basis
+// (e.g. those requested by clients of SOCKS5 forwarding sessions or those
+// corresponding to the accepting port in port range sessions) rather than a
+// fixed target. Such endpoints need not support Open.
+type TargetedEndpoint interface {
+	Endpoint
+
+	// OpenTarget should dial the specified target address. Unlike Open, this
+	// method must be safe for concurrent invocation, since dialing is performed
+	// independently for each incoming connection.
+	OpenTarget(address string) (net.Conn, error)
+}
+
+// IndexedEndpoint is an optional interface that can be implemented by source
+// endpoints that listen on a range of ports. Such endpoints need not support
+// Open.
+type IndexedEndpoint interface {
+	Endpoint
+
+	// OpenIndexed should block until an incoming connection arrives on any
+	// port in the endpoint's range, returning the connection along with the
+	// offset of the accepting port within the range.
+	OpenIndexed() (net.Conn, int, error)
+}
+
+// BoundEndpoint is an optional interface that can be implemented by source
+// endpoints that are able to report the address on which they're listening.
+type BoundEndpoint interface {
+	Endpoint
+
+	// BoundAddress should return the address on which the endpoint is
+	// listening. For TCP-based listeners, the address should reflect the port
+	// that was actually bound. If the address is not yet known (e.g. due to
+	// lazy initialization), then the configured address may be returned.
+	BoundAddress() string
+}
+
+// boundAddress returns the address on which an endpoint is listening, or an
+// empty string if the endpoint is nil or unable to report its address.
+func boundAddress(endpoint Endpoint) string {
+	if bound, ok := endpoint.(BoundEndpoint); ok {
+		return bound.BoundAddress()
+	}
+	return ""
+}
+
+// MultiListenerEndpoint is an optional interface that can be implemented by
+// source endpoints that are able to listen on multiple addresses.
+type MultiListenerEndpoint interface {
+	Endpoint
+
+	// ListenerStates should return the states of the individual listeners
+	// underlying the endpoint. It may return nil if the endpoint only listens
+	// on a single address.
+	ListenerStates() []*ListenerState
+}
+
+// listenerStates returns the states of the listeners underlying an endpoint, or
+// nil if the endpoint is nil or only listens on a single address.
+func listenerStates(endpoint Endpoint) []*ListenerState {
+	if multi, ok := endpoint.(MultiListenerEndpoint); ok {
+		return multi.ListenerStates()
+	}
+	return nil
+}
@@ -2,10 +2,22 @@ package local
 
 import (
 	"context"
+	"errors"
+	"fmt"
 	"net"
+	"time"
 
 	"github.com/mutagen-io/mutagen/pkg/forwarding"
 	"github.com/mutagen-io/mutagen/pkg/logging"
+	forwardingurl "github.com/mutagen-io/mutagen/pkg/url/forwarding"
+)
+
+const (
+	// targetDialTimeout is the maximum amount of time to wait when dialing an
+	// explicit target for a proxy or port range endpoint. Unlike dialing for
+	// non-targeted endpoints, this dialing occurs on behalf of individual
+	// incoming connections, so it can't rely on forwarding cancellation.
+	targetDialTimeout = 30 * time.Second
 )
 
 // dialerEndpoint implements forwarding.Endpoint for dialer endpoints.
@@ -32,6 +44,16 @@ func NewDialerEndpoint(
 	protocol string,
 	address string,
 ) (forwarding.Endpoint, error) {
+	// If this is a proxy endpoint, then the address specifies the network to
+	// use when dialing targets, so ensure that it's supported.
+	if forwardingurl.IsProxyProtocol(protocol) {
+		switch address {
+		case "tcp", "tcp4", "tcp6":
+		default:
+			return nil, fmt.Errorf("unsupported proxy dialing network: %s", address)
+		}
+	}
+
 	// Create a cancellable context that we can use to regulate connections.
 	dialingCtx, dialingCancel := context.WithCancel(context.Background())
 
@@ -42,14 +64,34 @@ func NewDialerEndpoint(
 	}
 
 	// Create the endpoint.
-	return &dialerEndpoint{
+	endpoint := &dialerEndpoint{
 		logger:        logger,
 		dialingCtx:    dialingCtx,
 		dialingCancel: dialingCancel,
 		dialer:        dialer,
 		protocol:      protocol,
 		address:       address,
-	}, nil
+	}
+
+	// If this is a proxy endpoint, then wrap the endpoint to support targeted
+	// dialing, using the address as the dialing network.
+	if forwardingurl.IsProxyProtocol(protocol) {
+		return &targetedDialerEndpoint{endpoint, address}, nil
+	}
+
+	// If this is a port range endpoint, then wrap the endpoint to support
+	// targeted dialing, since the target port depends on the accepting port.
+	if forwardingurl.IsPortRangeProtocol(protocol) {
+		if portRange, err := forwardingurl.ParsePortRange(address); err != nil {
+			dialingCancel()
+			return nil, fmt.Errorf("invalid port range: %w", err)
+		} else if portRange != nil {
+			return &targetedDialerEndpoint{endpoint, protocol}, nil
+		}
+	}
+
+	// Done.
+	return endpoint, nil
 }
 
 // TransportErrors implements forwarding.Endpoint.TransportErrors.
@@ -78,3 +120,23 @@ func (e *dialerEndpoint) Shutdown() error {
 	// Success.
 	return nil
 }
+
+// targetedDialerEndpoint implements forwarding.TargetedEndpoint for proxy and
+// port range dialer endpoints.
+type targetedDialerEndpoint struct {
+	*dialerEndpoint
+	// network is the network to use for dialing targets.
+	network string
+}
+
+// Open implements forwarding.Endpoint.Open.
+func (e *targetedDialerEndpoint) Open() (net.Conn, error) {
+	return nil, errors.New("targeted endpoint requires an explicit target")
+}
+
+// OpenTarget implements forwarding.TargetedEndpoint.OpenTarget.
+func (e *targetedDialerEndpoint) OpenTarget(address string) (net.Conn, error) {
+	ctx, cancel := context.WithTimeout(e.dialingCtx, targetDialTimeout)
+	defer cancel()
+	return e.dialer.DialContext(ctx, e.network, address)
+}
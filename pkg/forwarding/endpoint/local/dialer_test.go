@@ -1,3 +1,81 @@
 package local
 
-// TODO: Implement.
+import (
+	"net"
+	"testing"
+
+	"github.com/mutagen-io/mutagen/pkg/forwarding"
+)
+
+// TestTargetedDialerEndpoint tests that SOCKS5 dialer endpoints support
+// targeted dialing.
+func TestTargetedDialerEndpoint(t *testing.T) {
+	// Create a listener to serve as the target.
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal("unable to create target listener:", err)
+	}
+	defer listener.Close()
+
+	// Create the endpoint and ensure that it supports targeted dialing.
+	endpoint, err := NewDialerEndpoint(nil, forwarding.Version_Version1, &forwarding.Configuration{}, "socks5", "tcp")
+	if err != nil {
+		t.Fatal("unable to create endpoint:", err)
+	}
+	defer endpoint.Shutdown()
+	targeted, ok := endpoint.(forwarding.TargetedEndpoint)
+	if !ok {
+		t.Fatal("SOCKS5 dialer endpoint does not support targeted dialing")
+	}
+
+	// Ensure that untargeted opening fails.
+	if connection, err := endpoint.Open(); err == nil {
+		connection.Close()
+		t.Error("untargeted open succeeded unexpectedly")
+	}
+
+	// Dial the target.
+	connection, err := targeted.OpenTarget(listener.Addr().String())
+	if err != nil {
+		t.Fatal("unable to dial target:", err)
+	}
+	connection.Close()
+}
+
+// TestTargetedDialerEndpointInvalidNetwork tests that SOCKS5 dialer endpoints
+// reject unsupported dialing networks.
+func TestTargetedDialerEndpointInvalidNetwork(t *testing.T) {
+	if endpoint, err := NewDialerEndpoint(nil, forwarding.Version_Version1, &forwarding.Configuration{}, "socks5", "unix"); err == nil {
+		endpoint.Shutdown()
+		t.Error("endpoint creation succeeded with unsupported network")
+	}
+}
+
+// TestPortRangeDialerEndpoint tests that port range dialer endpoints support
+// targeted dialing.
+func TestPortRangeDialerEndpoint(t *testing.T) {
+	// Create a listener to serve as the target.
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal("unable to create target listener:", err)
+	}
+	defer listener.Close()
+
+	// Create the endpoint and ensure that it supports targeted dialing.
+	endpoint, err := NewDialerEndpoint(nil, forwarding.Version_Version1, &forwarding.Configuration{}, "tcp", "127.0.0.1:9000-9010")
+	if err != nil {
+		t.Fatal("unable to create endpoint:", err)
+	}
+	defer endpoint.Shutdown()
+	targeted, ok := endpoint.(forwarding.TargetedEndpoint)
+	if !ok {
+		t.Fatal("port range dialer endpoint does not support targeted dialing")
+	}
+
+	// Dial the target.
+	connection, err := targeted.OpenTarget(listener.Addr().String())
+	if err != nil {
+		t.Fatal("unable to dial target:", err)
+	}
+	connection.Close()
+}
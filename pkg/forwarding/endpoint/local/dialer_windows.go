@@ -50,17 +50,17 @@ func (c *npipeCloseWriterConn) RemoteAddr() net.Addr {
 
 // SetDeadline implements net.Conn.SetDeadline.
 func (c *npipeCloseWriterConn) SetDeadline(t time.Time) error {
-	return c.SetDeadline(t)
+	return c.connection.SetDeadline(t)
 }
 
 // SetReadDeadline implements net.Conn.SetReadDeadline.
 func (c *npipeCloseWriterConn) SetReadDeadline(t time.Time) error {
-	return c.SetReadDeadline(t)
+	return c.connection.SetReadDeadline(t)
 }
 
 // SetWriteDeadline implements net.Conn.SetWriteDeadline.
 func (c *npipeCloseWriterConn) SetWriteDeadline(t time.Time) error {
-	return c.SetWriteDeadline(t)
+	return c.connection.SetWriteDeadline(t)
 }
 
 // dialWindowsNamedPipe performs a named pipe dialing operation on Windows. The
@@ -6,12 +6,22 @@ import (
 	"net"
 	"os"
 	"sync"
+	"time"
 
 	"github.com/mutagen-io/mutagen/pkg/filesystem"
 	"github.com/mutagen-io/mutagen/pkg/forwarding"
 	"github.com/mutagen-io/mutagen/pkg/logging"
+	forwardingurl "github.com/mutagen-io/mutagen/pkg/url/forwarding"
 )
 
+// staleSocketProbeTimeout is the timeout used when dialing a conflicting Unix
+// domain socket to determine whether or not it's stale.
+const staleSocketProbeTimeout = time.Second
+
+// errEndpointShutdown is the initialization error recorded for lazily
+// initialized endpoints that are shut down before initialization.
+var errEndpointShutdown = errors.New("endpoint shutdown")
+
 // DisableLazyListenerInitialization indicates that lazy listener initialization
 // should be disabled for all endpoints in the current process. It must be set
 // during an init function and must not be changed later. This should only be
@@ -32,6 +42,12 @@ type listenerEndpoint struct {
 	protocol string
 	// address is the listening address.
 	address string
+	// portRange is the listening port range, if any.
+	portRange *forwardingurl.PortRange
+	// allowlist is the allowlist of client addresses.
+	allowlist forwarding.SourceAllowlist
+	// additionalAddresses are the additional listening addresses, if any.
+	additionalAddresses []string
 	// lazy indicates whether or not the endpoint uses lazy initialization.
 	lazy bool
 	// initializeOnce is used to guard calls to initialize.
@@ -40,6 +56,9 @@ type listenerEndpoint struct {
 	listener net.Listener
 	// initializeError is any error that occurred during initialization.
 	initializeError error
+	// listenerStates are the states of the individual listeners. It is set by
+	// initialize if the endpoint has additional listening addresses.
+	listenerStates []*forwarding.ListenerState
 }
 
 // NewListenerEndpoint creates a new forwarding.Endpoint that behaves as a
@@ -81,24 +100,67 @@ func NewListenerEndpoint(
 		lazy = false
 	}
 
+	// If the endpoint listens on an ephemeral TCP port, then disable lazy
+	// initialization so that the bound port can be reported immediately.
+	if forwardingurl.IsEphemeralPortAddress(protocol, address) {
+		lazy = false
+	}
+
+	// If the endpoint listens on a port range, then parse the range.
+	var portRange *forwardingurl.PortRange
+	if forwardingurl.IsPortRangeProtocol(protocol) {
+		if r, err := forwardingurl.ParsePortRange(address); err != nil {
+			return nil, fmt.Errorf("invalid port range: %w", err)
+		} else {
+			portRange = r
+		}
+	}
+
+	// If the endpoint listens on additional addresses, then ensure that it's a
+	// TCP listener and disable lazy initialization so that per-listener status
+	// can be reported immediately.
+	if len(configuration.AdditionalAddresses) > 0 {
+		if portRange != nil {
+			return nil, errors.New("additional addresses not supported for port range listeners")
+		} else if protocol == "unix" || protocol == "npipe" {
+			return nil, errors.New("additional addresses only supported for TCP listeners")
+		}
+		lazy = false
+	}
+
+	// Parse the client address allowlist.
+	allowlist, err := forwarding.ParseSourceAllowlist(configuration.AllowedSources)
+	if err != nil {
+		return nil, fmt.Errorf("invalid allowed sources: %w", err)
+	}
+
 	// Create the endpoint.
 	endpoint := &listenerEndpoint{
-		logger:        logger,
-		version:       version,
-		configuration: configuration,
-		protocol:      protocol,
-		address:       address,
-		lazy:          lazy,
+		logger:              logger,
+		version:             version,
+		configuration:       configuration,
+		protocol:            protocol,
+		address:             address,
+		portRange:           portRange,
+		allowlist:           allowlist,
+		additionalAddresses: configuration.AdditionalAddresses,
+		lazy:                lazy,
 	}
 
 	// Perform initialization if required.
 	if !lazy {
 		endpoint.initializeOnce.Do(func() { endpoint.initialize(false) })
 		if endpoint.initializeError != nil {
-			return nil, endpoint.initializeError
+			return nil, &forwarding.BindError{Err: endpoint.initializeError}
 		}
 	}
 
+	// If the endpoint listens on a port range, then wrap the endpoint to
+	// support indexed accepting.
+	if portRange != nil {
+		return &indexedListenerEndpoint{endpoint}, nil
+	}
+
 	// Done.
 	return endpoint, nil
 }
@@ -109,7 +171,7 @@ func NewListenerEndpoint(
 func (e *listenerEndpoint) initialize(shutdown bool) {
 	// If we're called on shutdown, then we act as a no-op.
 	if shutdown {
-		e.initializeError = errors.New("endpoint shutdown")
+		e.initializeError = errEndpointShutdown
 		return
 	}
 
@@ -120,8 +182,20 @@ func (e *listenerEndpoint) initialize(shutdown bool) {
 		return
 	}
 
-	// Otherwise attempt to create a listener using the generic method.
-	listener, err := net.Listen(e.protocol, e.address)
+	// If we're listening on a port range, then create an aggregate listener.
+	if e.portRange != nil {
+		e.listener, e.initializeError = listenPortRange(e.protocol, e.portRange)
+		return
+	}
+
+	// Otherwise attempt to create a listener using the generic method. Proxy
+	// endpoints listen using TCP, with the proxy protocol being handled by the
+	// forwarding loop.
+	network := e.protocol
+	if forwardingurl.IsProxyProtocol(network) {
+		network = "tcp"
+	}
+	listener, err := net.Listen(network, e.address)
 	if err != nil {
 		// If we're not targeting a Unix domain socket or the error isn't due to
 		// a conflicting socket, then abort.
@@ -142,6 +216,32 @@ func (e *listenerEndpoint) initialize(shutdown bool) {
 			return
 		}
 
+		// Ensure that the conflicting path is actually a socket, since we don't
+		// want to remove arbitrary files.
+		if metadata, statErr := os.Lstat(e.address); statErr != nil {
+			e.initializeError = fmt.Errorf("unable to query conflicting path: %w", statErr)
+			return
+		} else if metadata.Mode()&os.ModeSocket == 0 {
+			e.initializeError = errors.New("conflicting path is not a socket")
+			return
+		}
+
+		// If only stale sockets should be overwritten, then check whether or
+		// not there's a listener on the conflicting socket.
+		if socketOverwriteMode.OverwriteOnlyIfStale() {
+			connection, dialErr := net.DialTimeout("unix", e.address, staleSocketProbeTimeout)
+			if dialErr == nil {
+				connection.Close()
+			}
+			if dialErr == nil {
+				e.initializeError = errors.New("conflicting socket is in use")
+				return
+			} else if !isStaleSocket(dialErr) {
+				e.initializeError = fmt.Errorf("unable to determine whether conflicting socket is stale: %w", dialErr)
+				return
+			}
+		}
+
 		// Attempt to remove the conflicting socket.
 		e.logger.Debug("Encountered conflicting socket, attempting removal")
 		if err := os.Remove(e.address); err != nil {
@@ -197,6 +297,30 @@ func (e *listenerEndpoint) initialize(shutdown bool) {
 		}
 	}
 
+	// If there are additional listening addresses, then attempt to listen on
+	// each of them and aggregate the resulting listeners. Failures to bind
+	// additional addresses aren't fatal, but they are recorded.
+	if len(e.additionalAddresses) > 0 {
+		listeners := []net.Listener{listener}
+		e.listenerStates = []*forwarding.ListenerState{{
+			Address:      e.address,
+			BoundAddress: listener.Addr().String(),
+		}}
+		for _, address := range e.additionalAddresses {
+			state := &forwarding.ListenerState{Address: address}
+			if additional, err := net.Listen(network, address); err != nil {
+				e.logger.Warnf("Unable to listen on additional address (%s): %v", address, err)
+				state.Error = err.Error()
+			} else {
+				listeners = append(listeners, additional)
+				state.BoundAddress = additional.Addr().String()
+			}
+			e.listenerStates = append(e.listenerStates, state)
+		}
+		e.listener = newAggregateListener(listeners)
+		return
+	}
+
 	// Success.
 	e.listener = listener
 }
@@ -209,16 +333,51 @@ func (e *listenerEndpoint) TransportErrors() <-chan error {
 // Open implements forwarding.Endpoint.Open.
 func (e *listenerEndpoint) Open() (net.Conn, error) {
 	// For lazily initialized endpoints, we need to ensure that the listener has
-	// been established.
+	// been established. Failures are reported as binding failures so that the
+	// controller's bind retry policy applies, unless they're due to shutdown.
 	if e.lazy {
 		e.initializeOnce.Do(func() { e.initialize(false) })
-		if e.initializeError != nil {
-			return nil, fmt.Errorf("lazy listen error: %w", e.initializeError)
+		if e.initializeError == errEndpointShutdown {
+			return nil, e.initializeError
+		} else if e.initializeError != nil {
+			return nil, &forwarding.BindError{Err: fmt.Errorf("lazy listen error: %w", e.initializeError)}
 		}
 	}
 
-	// Accept a connection.
-	return e.listener.Accept()
+	// Accept a connection, closing any from addresses that aren't allowed.
+	for {
+		connection, err := e.listener.Accept()
+		if err != nil {
+			return nil, err
+		} else if !e.allowlist.Allows(connection.RemoteAddr()) {
+			e.logger.Warnf("Rejecting connection from disallowed address: %s", connection.RemoteAddr())
+			connection.Close()
+			continue
+		}
+
+		// Connections from listeners on additional addresses are delivered via
+		// an aggregate listener, so unwrap them. Port range listeners handle
+		// unwrapping themselves.
+		if indexed, ok := connection.(*indexedConn); ok && e.portRange == nil {
+			connection = indexed.Conn
+		}
+		return connection, nil
+	}
+}
+
+// BoundAddress implements forwarding.BoundEndpoint.BoundAddress. For lazily
+// initialized endpoints, it always returns the configured address, since the
+// listener may be established concurrently.
+func (e *listenerEndpoint) BoundAddress() string {
+	if e.lazy {
+		return e.address
+	}
+	return e.listener.Addr().String()
+}
+
+// ListenerStates implements forwarding.MultiListenerEndpoint.ListenerStates.
+func (e *listenerEndpoint) ListenerStates() []*forwarding.ListenerState {
+	return e.listenerStates
 }
 
 // Shutdown implements forwarding.Endpoint.Shutdown.
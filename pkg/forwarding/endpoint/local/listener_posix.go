@@ -21,3 +21,9 @@ func isConflictingSocket(err error) bool {
 	// or not a listener is currently bound to it.
 	return errors.Is(err, syscall.EEXIST) || errors.Is(err, syscall.EADDRINUSE)
 }
+
+// isStaleSocket returns whether or not a Unix domain socket dialing error is due
+// to a stale socket (i.e. one with no listener).
+func isStaleSocket(err error) bool {
+	return errors.Is(err, syscall.ECONNREFUSED)
+}
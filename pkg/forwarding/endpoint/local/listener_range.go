@@ -0,0 +1,163 @@
+package local
+
+import (
+	"errors"
+	"fmt"
+	"net"
+	"sync"
+
+	forwardingurl "github.com/mutagen-io/mutagen/pkg/url/forwarding"
+)
+
+// indexedConn is a connection accepted by an aggregateListener, annotated with
+// the index of the accepting listener (e.g. the offset of the accepting port
+// within a port range).
+type indexedConn struct {
+	net.Conn
+	// index is the index of the accepting listener.
+	index int
+}
+
+// acceptResult is the result of an accept operation on one of the listeners
+// underlying an aggregateListener.
+type acceptResult struct {
+	// connection is the accepted connection, if any.
+	connection *indexedConn
+	// err is the accept error, if any.
+	err error
+}
+
+// aggregateListener implements net.Listener by aggregating multiple listeners
+// (e.g. those on each port in a port range). Accepted connections are of type
+// *indexedConn.
+type aggregateListener struct {
+	// listeners are the underlying listeners.
+	listeners []net.Listener
+	// results is the channel to which accept results are delivered.
+	results chan acceptResult
+	// closed is closed when the listener is closed.
+	closed chan struct{}
+	// closeOnce guards closure of the listener.
+	closeOnce sync.Once
+}
+
+// listenPortRange creates a listener on each port in the specified port range
+// and aggregates them into a single listener. If listening fails on any port,
+// then all listeners are closed.
+func listenPortRange(network string, portRange *forwardingurl.PortRange) (net.Listener, error) {
+	// Create the underlying listeners.
+	listeners := make([]net.Listener, 0, portRange.Size())
+	for i := 0; i < portRange.Size(); i++ {
+		listener, err := net.Listen(network, portRange.Address(i))
+		if err != nil {
+			for _, l := range listeners {
+				l.Close()
+			}
+			return nil, fmt.Errorf("unable to listen on %s: %w", portRange.Address(i), err)
+		}
+		listeners = append(listeners, listener)
+	}
+
+	// Success.
+	return newAggregateListener(listeners), nil
+}
+
+// newAggregateListener creates a new aggregate listener from the specified
+// listeners, which must be non-empty, and starts accepting on each of them.
+// Accepted connections are indexed by their listener's position in the slice.
+func newAggregateListener(listeners []net.Listener) *aggregateListener {
+	// Create the aggregate listener.
+	result := &aggregateListener{
+		listeners: listeners,
+		results:   make(chan acceptResult),
+		closed:    make(chan struct{}),
+	}
+
+	// Start accepting on each of the underlying listeners.
+	for i, listener := range listeners {
+		go result.accept(i, listener)
+	}
+
+	// Done.
+	return result
+}
+
+// accept accepts connections from an underlying listener and delivers them to
+// the results channel until accepting fails or the listener is closed.
+func (l *aggregateListener) accept(index int, listener net.Listener) {
+	for {
+		connection, err := listener.Accept()
+		var result acceptResult
+		if err != nil {
+			result.err = err
+		} else {
+			result.connection = &indexedConn{connection, index}
+		}
+		select {
+		case l.results <- result:
+			if err != nil {
+				return
+			}
+		case <-l.closed:
+			if connection != nil {
+				connection.Close()
+			}
+			return
+		}
+	}
+}
+
+// Accept implements net.Listener.Accept.
+func (l *aggregateListener) Accept() (net.Conn, error) {
+	select {
+	case result := <-l.results:
+		if result.err != nil {
+			l.Close()
+			return nil, result.err
+		}
+		return result.connection, nil
+	case <-l.closed:
+		return nil, errors.New("listener closed")
+	}
+}
+
+// Close implements net.Listener.Close.
+func (l *aggregateListener) Close() error {
+	var firstErr error
+	l.closeOnce.Do(func() {
+		close(l.closed)
+		for _, listener := range l.listeners {
+			if err := listener.Close(); err != nil && firstErr == nil {
+				firstErr = err
+			}
+		}
+	})
+	return firstErr
+}
+
+// Addr implements net.Listener.Addr. It returns the address of the first
+// underlying listener.
+func (l *aggregateListener) Addr() net.Addr {
+	return l.listeners[0].Addr()
+}
+
+// indexedListenerEndpoint implements forwarding.IndexedEndpoint for port range
+// listener endpoints.
+type indexedListenerEndpoint struct {
+	*listenerEndpoint
+}
+
+// Open implements forwarding.Endpoint.Open.
+func (e *indexedListenerEndpoint) Open() (net.Conn, error) {
+	return nil, errors.New("indexed endpoint requires indexed accepting")
+}
+
+// OpenIndexed implements forwarding.IndexedEndpoint.OpenIndexed.
+func (e *indexedListenerEndpoint) OpenIndexed() (net.Conn, int, error) {
+	connection, err := e.listenerEndpoint.Open()
+	if err != nil {
+		return nil, 0, err
+	}
+	indexed := connection.(*indexedConn)
+	return indexed.Conn, indexed.index, nil
+}
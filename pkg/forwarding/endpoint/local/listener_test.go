@@ -1,3 +1,285 @@
 package local
 
-// TODO: Implement.
+import (
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"os"
+	"path/filepath"
+	"runtime"
+	"testing"
+	"time"
+
+	"github.com/mutagen-io/mutagen/pkg/forwarding"
+)
+
+// TestPortRangeListenerEndpoint tests that port range listener endpoints
+// accept connections on each port in the range and report the corresponding
+// port offsets.
+func TestPortRangeListenerEndpoint(t *testing.T) {
+	// Find a free port to use as the start of the range. Since the subsequent
+	// ports may be in use, we allow a few attempts at endpoint creation.
+	const rangeSize = 3
+	var endpoint forwarding.Endpoint
+	var firstPort int
+	for attempt := 0; attempt < 10 && endpoint == nil; attempt++ {
+		probe, err := net.Listen("tcp", "127.0.0.1:0")
+		if err != nil {
+			t.Fatal("unable to probe for free port:", err)
+		}
+		firstPort = probe.Addr().(*net.TCPAddr).Port
+		probe.Close()
+		if firstPort+rangeSize-1 > 65535 {
+			continue
+		}
+		address := fmt.Sprintf("127.0.0.1:%d-%d", firstPort, firstPort+rangeSize-1)
+		endpoint, _ = NewListenerEndpoint(nil, forwarding.Version_Version1, &forwarding.Configuration{}, "tcp", address, false)
+	}
+	if endpoint == nil {
+		t.Skip("unable to find free port range")
+	}
+	defer endpoint.Shutdown()
+
+	// Ensure that the endpoint supports indexed accepting.
+	indexed, ok := endpoint.(forwarding.IndexedEndpoint)
+	if !ok {
+		t.Fatal("port range listener endpoint does not support indexed accepting")
+	}
+
+	// Connect to each port in the range and verify the reported offset.
+	for offset := 0; offset < rangeSize; offset++ {
+		client, err := net.Dial("tcp", fmt.Sprintf("127.0.0.1:%d", firstPort+offset))
+		if err != nil {
+			t.Fatal("unable to dial port in range:", err)
+		}
+		connection, index, err := indexed.OpenIndexed()
+		if err != nil {
+			client.Close()
+			t.Fatal("unable to accept connection:", err)
+		}
+		if index != offset {
+			t.Error("accepted port offset does not match expected:", index, "!=", offset)
+		}
+		connection.Close()
+		client.Close()
+	}
+}
+
+// TestEphemeralPortListenerEndpoint tests that listener endpoints using an
+// ephemeral port report the port that was actually bound, even when lazy
+// initialization is requested.
+func TestEphemeralPortListenerEndpoint(t *testing.T) {
+	// Create the endpoint.
+	endpoint, err := NewListenerEndpoint(nil, forwarding.Version_Version1, &forwarding.Configuration{}, "tcp", "127.0.0.1:0", true)
+	if err != nil {
+		t.Fatal("unable to create endpoint:", err)
+	}
+	defer endpoint.Shutdown()
+
+	// Ensure that the bound address is reported with a non-zero port.
+	bound, ok := endpoint.(forwarding.BoundEndpoint)
+	if !ok {
+		t.Fatal("listener endpoint does not report bound address")
+	}
+	_, port, err := net.SplitHostPort(bound.BoundAddress())
+	if err != nil {
+		t.Fatal("unable to parse bound address:", err)
+	} else if port == "0" {
+		t.Error("bound address reports ephemeral port")
+	}
+
+	// Ensure that the bound port accepts connections.
+	client, err := net.Dial("tcp", bound.BoundAddress())
+	if err != nil {
+		t.Fatal("unable to dial bound address:", err)
+	}
+	defer client.Close()
+	connection, err := endpoint.Open()
+	if err != nil {
+		t.Fatal("unable to accept connection:", err)
+	}
+	connection.Close()
+}
+
+// TestLazyListenerEndpointBindError tests that lazily initialized listener
+// endpoints report binding failures from Open as binding errors.
+func TestLazyListenerEndpointBindError(t *testing.T) {
+	// Occupy a port.
+	occupier, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal("unable to create listener:", err)
+	}
+	defer occupier.Close()
+
+	// Create a lazily initialized endpoint targeting the occupied port.
+	endpoint, err := NewListenerEndpoint(nil, forwarding.Version_Version1, &forwarding.Configuration{}, "tcp", occupier.Addr().String(), true)
+	if err != nil {
+		t.Fatal("unable to create endpoint:", err)
+	}
+	defer endpoint.Shutdown()
+
+	// Ensure that opening fails with a binding error.
+	var bindErr *forwarding.BindError
+	if _, err := endpoint.Open(); err == nil {
+		t.Fatal("opening succeeded on occupied port")
+	} else if !errors.As(err, &bindErr) {
+		t.Error("opening failure not reported as binding error:", err)
+	}
+}
+
+// TestUnixListenerEndpointSocketOverwrite tests the handling of conflicting
+// paths for Unix domain socket listener endpoints under each socket overwrite
+// mode.
+func TestUnixListenerEndpointSocketOverwrite(t *testing.T) {
+	// Skip this test on Windows, where Unix domain socket support varies.
+	if runtime.GOOS == "windows" {
+		t.Skip()
+	}
+
+	// Create a temporary directory for sockets.
+	directory := t.TempDir()
+
+	// Create a stale socket.
+	stalePath := filepath.Join(directory, "stale.sock")
+	staleListener, err := net.Listen("unix", stalePath)
+	if err != nil {
+		t.Fatal("unable to create stale socket:", err)
+	}
+	staleListener.(*net.UnixListener).SetUnlinkOnClose(false)
+	staleListener.Close()
+
+	// Create an active socket.
+	activePath := filepath.Join(directory, "active.sock")
+	activeListener, err := net.Listen("unix", activePath)
+	if err != nil {
+		t.Fatal("unable to create active socket:", err)
+	}
+	defer activeListener.Close()
+
+	// Create a regular file.
+	filePath := filepath.Join(directory, "file")
+	if err := os.WriteFile(filePath, nil, 0600); err != nil {
+		t.Fatal("unable to create regular file:", err)
+	}
+
+	// Set up test cases.
+	testCases := []struct {
+		path          string
+		mode          forwarding.SocketOverwriteMode
+		expectFailure bool
+	}{
+		{stalePath, forwarding.SocketOverwriteMode_SocketOverwriteModeLeave, true},
+		{activePath, forwarding.SocketOverwriteMode_SocketOverwriteModeStale, true},
+		{filePath, forwarding.SocketOverwriteMode_SocketOverwriteModeOverwrite, true},
+		{stalePath, forwarding.SocketOverwriteMode_SocketOverwriteModeStale, false},
+	}
+
+	// Process test cases.
+	for _, testCase := range testCases {
+		configuration := &forwarding.Configuration{SocketOverwriteMode: testCase.mode}
+		endpoint, err := NewListenerEndpoint(nil, forwarding.Version_Version1, configuration, "unix", testCase.path, false)
+		if err != nil {
+			if !testCase.expectFailure {
+				t.Errorf("unable to create endpoint at %s with mode %s: %v", testCase.path, testCase.mode, err)
+			}
+			continue
+		}
+		endpoint.Shutdown()
+		if testCase.expectFailure {
+			t.Errorf("endpoint creation at %s with mode %s succeeded unexpectedly", testCase.path, testCase.mode)
+		}
+	}
+}
+
+// TestListenerEndpointAllowedSources tests that listener endpoints reject
+// connections from client addresses that aren't allowed.
+func TestListenerEndpointAllowedSources(t *testing.T) {
+	// Create an endpoint that only allows a documentation address.
+	configuration := &forwarding.Configuration{AllowedSources: []string{"192.0.2.0/24"}}
+	endpoint, err := NewListenerEndpoint(nil, forwarding.Version_Version1, configuration, "tcp", "127.0.0.1:0", false)
+	if err != nil {
+		t.Fatal("unable to create endpoint:", err)
+	}
+	address := endpoint.(forwarding.BoundEndpoint).BoundAddress()
+
+	// Start accepting connections in the background.
+	accepted := make(chan error, 1)
+	go func() {
+		connection, err := endpoint.Open()
+		if err == nil {
+			connection.Close()
+		}
+		accepted <- err
+	}()
+
+	// Connect and verify that the connection is closed by the endpoint.
+	client, err := net.Dial("tcp", address)
+	if err != nil {
+		t.Fatal("unable to connect:", err)
+	}
+	defer client.Close()
+	client.SetReadDeadline(time.Now().Add(5 * time.Second))
+	if _, err := client.Read(make([]byte, 1)); err != io.EOF {
+		t.Error("unexpected result reading from rejected connection:", err)
+	}
+
+	// Verify that the rejected connection wasn't returned.
+	endpoint.Shutdown()
+	if err := <-accepted; err == nil {
+		t.Error("disallowed connection accepted")
+	}
+}
+
+// TestListenerEndpointAdditionalAddresses tests that listener endpoints accept
+// connections on additional addresses and report per-listener status.
+func TestListenerEndpointAdditionalAddresses(t *testing.T) {
+	// Create the endpoint with one bindable and one unbindable additional
+	// address. The latter is in a documentation address block, so it won't be
+	// assigned to any local interface.
+	configuration := &forwarding.Configuration{
+		AdditionalAddresses: []string{"127.0.0.1:0", "192.0.2.1:0"},
+	}
+	endpoint, err := NewListenerEndpoint(nil, forwarding.Version_Version1, configuration, "tcp", "127.0.0.1:0", true)
+	if err != nil {
+		t.Fatal("unable to create endpoint:", err)
+	}
+	defer endpoint.Shutdown()
+
+	// Verify the listener states.
+	multi, ok := endpoint.(forwarding.MultiListenerEndpoint)
+	if !ok {
+		t.Fatal("listener endpoint does not report listener states")
+	}
+	states := multi.ListenerStates()
+	if len(states) != 3 {
+		t.Fatal("unexpected number of listener states:", len(states))
+	}
+	for i, state := range states[:2] {
+		if state.BoundAddress == "" || state.Error != "" {
+			t.Error("listener", i, "not bound:", state.Error)
+		}
+	}
+	if states[2].BoundAddress != "" || states[2].Error == "" {
+		t.Error("unbindable listener reported as bound")
+	}
+
+	// Connect to the additional listener and verify that the connection is
+	// accepted without its aggregation wrapper.
+	client, err := net.Dial("tcp", states[1].BoundAddress)
+	if err != nil {
+		t.Fatal("unable to connect to additional listener:", err)
+	}
+	defer client.Close()
+	connection, err := endpoint.Open()
+	if err != nil {
+		t.Fatal("unable to accept connection:", err)
+	}
+	defer connection.Close()
+	if _, ok := connection.(*indexedConn); ok {
+		t.Error("accepted connection is still wrapped")
+	}
+	if connection.LocalAddr().String() != states[1].BoundAddress {
+		t.Error("connection accepted on unexpected listener:", connection.LocalAddr())
+	}
+}
@@ -63,3 +63,11 @@ const (
 func isConflictingSocket(err error) bool {
 	return errors.Is(err, WSAEADDRINUSE)
 }
+
+// isStaleSocket returns whether or not a Unix domain socket error is due to a
+// stale socket (i.e. one with no listener). On Windows, the error codes
+// returned for stale sockets aren't well-defined, so any dialing failure is
+// treated as an indication of staleness.
+func isStaleSocket(err error) bool {
+	return err != nil
+}
@@ -6,11 +6,21 @@ import (
 	"fmt"
 	"io"
 	"net"
+	"time"
 
 	"github.com/mutagen-io/mutagen/pkg/encoding"
 	"github.com/mutagen-io/mutagen/pkg/forwarding"
 	"github.com/mutagen-io/mutagen/pkg/logging"
 	"github.com/mutagen-io/mutagen/pkg/multiplexing"
+	forwardingurl "github.com/mutagen-io/mutagen/pkg/url/forwarding"
+)
+
+const (
+	// targetOpenTimeout is the maximum amount of time to wait for a remote
+	// endpoint to open a target connection. It's slightly longer than the
+	// timeout that the remote endpoint applies to dialing the target so that
+	// remote dialing failures can be reported.
+	targetOpenTimeout = 35 * time.Second
 )
 
 // client is a client for a remote forwarding.Endpoint and implements
@@ -25,6 +35,12 @@ type client struct {
 	// listener indicates whether or not the remote endpoint is operating as a
 	// listener.
 	listener bool
+	// boundAddress is the address on which the remote endpoint is listening,
+	// if it's a listener.
+	boundAddress string
+	// listeners are the states of the remote endpoint's underlying listeners,
+	// if the remote endpoint is a listener on multiple addresses.
+	listeners []*forwarding.ListenerState
 }
 
 // NewEndpoint creates a new remote forwarding.Endpoint operating over the
@@ -97,13 +113,36 @@ func NewEndpoint(
 		}
 	}()
 
-	// Success.
-	return &client{
+	// Create the client.
+	client := &client{
 		logger:          logger,
 		transportErrors: transportErrors,
 		multiplexer:     multiplexer,
 		listener:        source,
-	}, nil
+		boundAddress:    response.BoundAddress,
+		listeners:       response.Listeners,
+	}
+
+	// If the remote endpoint is a port range endpoint, then wrap the client to
+	// support indexed accepting (for listeners) or targeted dialing (for
+	// dialers).
+	if forwardingurl.IsPortRangeProtocol(protocol) {
+		if portRange, _ := forwardingurl.ParsePortRange(address); portRange != nil {
+			if source {
+				return &indexedClient{client}, nil
+			}
+			return &targetedClient{client}, nil
+		}
+	}
+
+	// If the remote endpoint is a proxy dialer, then wrap the client to
+	// support targeted dialing.
+	if forwardingurl.IsProxyProtocol(protocol) && !source {
+		return &targetedClient{client}, nil
+	}
+
+	// Success.
+	return client, nil
 }
 
 // TransportErrors implements forwarding.Endpoint.TransportErrors.
@@ -121,7 +160,109 @@ func (c *client) Open() (net.Conn, error) {
 	}
 }
 
+// BoundAddress implements forwarding.BoundEndpoint.BoundAddress.
+func (c *client) BoundAddress() string {
+	return c.boundAddress
+}
+
+// ListenerStates implements forwarding.MultiListenerEndpoint.ListenerStates.
+func (c *client) ListenerStates() []*forwarding.ListenerState {
+	return c.listeners
+}
+
 // Shutdown implements forwarding.Endpoint.Shutdown.
 func (c *client) Shutdown() error {
 	return c.multiplexer.Close()
 }
+
+// targetedClient is a client for a remote forwarding.TargetedEndpoint and
+// implements forwarding.TargetedEndpoint itself.
+type targetedClient struct {
+	*client
+}
+
+// Open implements forwarding.Endpoint.Open.
+func (c *targetedClient) Open() (net.Conn, error) {
+	return nil, errors.New("targeted endpoint requires an explicit target")
+}
+
+// OpenTarget implements forwarding.TargetedEndpoint.OpenTarget.
+func (c *targetedClient) OpenTarget(address string) (net.Conn, error) {
+	// Open a stream to the remote endpoint.
+	ctx, cancel := context.WithTimeout(context.Background(), targetOpenTimeout)
+	defer cancel()
+	stream, err := c.multiplexer.OpenStream(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("unable to open stream: %w", err)
+	}
+
+	// Bound the target request exchange by the same deadline.
+	deadline, _ := ctx.Deadline()
+	if err := stream.SetDeadline(deadline); err != nil {
+		stream.Close()
+		return nil, fmt.Errorf("unable to set target request deadline: %w", err)
+	}
+
+	// Send the target request.
+	request := &OpenTargetRequest{Address: address}
+	if err := encoding.EncodeProtobuf(stream, request); err != nil {
+		stream.Close()
+		return nil, fmt.Errorf("unable to send target request: %w", err)
+	}
+
+	// Receive the target response, ensure that it's valid, and check for
+	// dialing errors.
+	response := &OpenTargetResponse{}
+	if err := encoding.DecodeProtobuf(unbufferedReader{stream}, response); err != nil {
+		stream.Close()
+		return nil, fmt.Errorf("unable to receive target response: %w", err)
+	} else if err = response.ensureValid(); err != nil {
+		stream.Close()
+		return nil, fmt.Errorf("invalid target response received: %w", err)
+	} else if response.Error != "" {
+		stream.Close()
+		return nil, fmt.Errorf("remote dialing failure: %w", errors.New(response.Error))
+	}
+
+	// Clear the deadline for forwarding.
+	if err := stream.SetDeadline(time.Time{}); err != nil {
+		stream.Close()
+		return nil, fmt.Errorf("unable to clear target request deadline: %w", err)
+	}
+
+	// Success.
+	return stream, nil
+}
+
+// indexedClient is a client for a remote forwarding.IndexedEndpoint and
+// implements forwarding.IndexedEndpoint itself.
+type indexedClient struct {
+	*client
+}
+
+// Open implements forwarding.Endpoint.Open.
+func (c *indexedClient) Open() (net.Conn, error) {
+	return nil, errors.New("indexed endpoint requires indexed accepting")
+}
+
+// OpenIndexed implements forwarding.IndexedEndpoint.OpenIndexed.
+func (c *indexedClient) OpenIndexed() (net.Conn, int, error) {
+	// Accept a stream from the remote endpoint.
+	stream, err := c.multiplexer.Accept()
+	if err != nil {
+		return nil, 0, err
+	}
+
+	// Receive the stream index and ensure that it's valid.
+	index := &StreamIndex{}
+	if err := encoding.DecodeProtobuf(unbufferedReader{stream}, index); err != nil {
+		stream.Close()
+		return nil, 0, fmt.Errorf("unable to receive stream index: %w", err)
+	} else if err = index.ensureValid(); err != nil {
+		stream.Close()
+		return nil, 0, fmt.Errorf("invalid stream index received: %w", err)
+	}
+
+	// Success.
+	return stream, int(index.Index), nil
+}
@@ -3,6 +3,7 @@ package remote
 import (
 	"errors"
 	"fmt"
+	"io"
 
 	"github.com/mutagen-io/mutagen/pkg/url/forwarding"
 )
@@ -51,6 +52,73 @@ func (r *InitializeForwardingResponse) ensureValid() error {
 
 	// There's no verification to be performed on the error message.
 
+	// Ensure that listener states are non-nil.
+	for _, listener := range r.Listeners {
+		if listener == nil {
+			return errors.New("nil listener state")
+		}
+	}
+
+	// Success.
+	return nil
+}
+
+// ensureValid ensures that OpenTargetRequest's invariants are respected.
+func (r *OpenTargetRequest) ensureValid() error {
+	// A nil request is invalid.
+	if r == nil {
+		return errors.New("nil request")
+	}
+
+	// Enforce that address is non-empty.
+	if r.Address == "" {
+		return errors.New("empty address")
+	}
+
+	// Success.
+	return nil
+}
+
+// ensureValid ensures that OpenTargetResponse's invariants are respected.
+func (r *OpenTargetResponse) ensureValid() error {
+	// A nil response is invalid.
+	if r == nil {
+		return errors.New("nil response")
+	}
+
+	// There's no verification to be performed on the error message.
+
+	// Success.
+	return nil
+}
+
+// unbufferedReader adapts an io.Reader to implement stream.DualModeReader
+// without performing any read-ahead. It's used to decode messages at the start
+// of streams whose remaining contents are forwarded, where read-ahead would
+// consume forwarded data.
+type unbufferedReader struct {
+	io.Reader
+}
+
+// ReadByte implements io.ByteReader.ReadByte.
+func (r unbufferedReader) ReadByte() (byte, error) {
+	var buffer [1]byte
+	if _, err := io.ReadFull(r.Reader, buffer[:]); err != nil {
+		return 0, err
+	}
+	return buffer[0], nil
+}
+
+// ensureValid ensures that StreamIndex's invariants are respected.
+func (i *StreamIndex) ensureValid() error {
+	// A nil index is invalid.
+	if i == nil {
+		return errors.New("nil index")
+	}
+
+	// There's no verification to be performed on the index itself, since its
+	// range is only known to the receiver.
+
 	// Success.
 	return nil
 }
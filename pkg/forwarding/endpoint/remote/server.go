@@ -49,6 +49,62 @@ func initializeEndpoint(logger *logging.Logger, request *InitializeForwardingReq
 	}
 }
 
+// serveTargetedStream reads a target request from an incoming stream, dials the
+// requested target using the specified endpoint, reports the dialing result,
+// and (if dialing succeeded) forwards traffic between the stream and the target.
+// It enforces that the stream is closed by the time this function returns.
+func serveTargetedStream(endpoint forwarding.TargetedEndpoint, stream net.Conn) {
+	// Receive the target request and ensure that it's valid.
+	request := &OpenTargetRequest{}
+	if err := encoding.DecodeProtobuf(unbufferedReader{stream}, request); err != nil {
+		stream.Close()
+		return
+	} else if err = request.ensureValid(); err != nil {
+		stream.Close()
+		return
+	}
+
+	// Dial the target and send the response, indicating any dialing error.
+	outgoing, err := endpoint.OpenTarget(request.Address)
+	response := &OpenTargetResponse{}
+	if err != nil {
+		response.Error = err.Error()
+	}
+	if err := encoding.EncodeProtobuf(stream, response); err != nil || outgoing == nil {
+		if outgoing != nil {
+			outgoing.Close()
+		}
+		stream.Close()
+		return
+	}
+
+	// Perform forwarding.
+	forwarding.ForwardAndClose(context.Background(), stream, outgoing, nil, nil)
+}
+
+// serveIndexedConnection opens a stream for a connection accepted by a port
+// range listener, sends the offset of the accepting port at the start of the
+// stream, and forwards traffic between the connection and the stream. It
+// enforces that the connection is closed by the time this function returns.
+func serveIndexedConnection(multiplexer *multiplexing.Multiplexer, incoming net.Conn, index int) {
+	// Open the outgoing stream.
+	outgoing, err := multiplexer.OpenStream(context.Background())
+	if err != nil {
+		incoming.Close()
+		return
+	}
+
+	// Send the stream index.
+	if err := encoding.EncodeProtobuf(outgoing, &StreamIndex{Index: uint32(index)}); err != nil {
+		incoming.Close()
+		outgoing.Close()
+		return
+	}
+
+	// Perform forwarding.
+	forwarding.ForwardAndClose(context.Background(), incoming, outgoing, nil, nil)
+}
+
 // ServeEndpoint creates and serves a remote endpoint on the specified stream.
 // It enforces that the provided stream is closed by the time this function
 // returns, regardless of failure. The provided stream must unblock read and
@@ -85,6 +141,11 @@ func ServeEndpoint(logger *logging.Logger, stream io.ReadWriteCloser) error {
 	response := &InitializeForwardingResponse{}
 	if initializationError != nil {
 		response.Error = initializationError.Error()
+	} else if bound, ok := underlying.(forwarding.BoundEndpoint); ok && request.Listener {
+		response.BoundAddress = bound.BoundAddress()
+		if multi, ok := underlying.(forwarding.MultiListenerEndpoint); ok {
+			response.Listeners = multi.ListenerStates()
+		}
 	}
 	if err := encoding.EncodeProtobuf(carrier, response); err != nil {
 		return fmt.Errorf("unable to send initialization response: %w", err)
@@ -116,7 +177,14 @@ func ServeEndpoint(logger *logging.Logger, stream io.ReadWriteCloser) error {
 		// multiplexer has failed.
 		var incoming net.Conn
 		var err error
-		if request.Listener {
+		if indexed, ok := underlying.(forwarding.IndexedEndpoint); ok {
+			var index int
+			if incoming, index, err = indexed.OpenIndexed(); err != nil {
+				return fmt.Errorf("listener failure: %w", err)
+			}
+			go serveIndexedConnection(multiplexer, incoming, index)
+			continue
+		} else if request.Listener {
 			if incoming, err = underlying.Open(); err != nil {
 				return fmt.Errorf("listener failure: %w", err)
 			}
@@ -127,6 +195,14 @@ func ServeEndpoint(logger *logging.Logger, stream io.ReadWriteCloser) error {
 			}
 		}
 
+		// If the underlying endpoint dials targets on a per-connection basis,
+		// then the target is specified at the start of the incoming stream, so
+		// perform dialing and forwarding in a background Goroutine.
+		if targeted, ok := underlying.(forwarding.TargetedEndpoint); ok {
+			go serveTargetedStream(targeted, incoming)
+			continue
+		}
+
 		// Open the corresponding outgoing connection. If the multiplexer fails,
 		// then we should terminate serving. If local dialing fails, then we can
 		// just close the incoming connection to indicate dialing failure.
@@ -0,0 +1,177 @@
+package forwarding
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"sync"
+	"time"
+)
+
+const (
+	// healthCheckTimeout is the maximum amount of time allowed for a single
+	// health check to complete.
+	healthCheckTimeout = 5 * time.Second
+)
+
+// serializedEndpoint wraps a destination endpoint to serialize calls to Open.
+// It's used when health checks are enabled so that checks and the forwarding
+// loop never open connections through the underlying endpoint concurrently,
+// since not all endpoint implementations support concurrent opening.
+type serializedEndpoint struct {
+	Endpoint
+	// openLock serializes calls to Open.
+	openLock sync.Mutex
+}
+
+// Open implements Endpoint.Open.
+func (e *serializedEndpoint) Open() (net.Conn, error) {
+	e.openLock.Lock()
+	defer e.openLock.Unlock()
+	return e.Endpoint.Open()
+}
+
+// openWithTimeout opens a connection to the destination, giving up if the
+// connection isn't established within the specified timeout. If the timeout
+// elapses, then any connection that's subsequently established is closed.
+func openWithTimeout(destination Endpoint, timeout time.Duration) (net.Conn, error) {
+	// Create a timer to enforce the timeout and defer its shutdown.
+	timer := time.NewTimer(timeout)
+	defer timer.Stop()
+
+	// Open the connection in a background Goroutine.
+	type result struct {
+		connection net.Conn
+		err        error
+	}
+	results := make(chan result)
+	abandoned := make(chan struct{})
+	go func() {
+		connection, err := destination.Open()
+		select {
+		case results <- result{connection, err}:
+		case <-abandoned:
+			if connection != nil {
+				connection.Close()
+			}
+		}
+	}()
+
+	// Wait for the result or the timeout.
+	select {
+	case r := <-results:
+		return r.connection, r.err
+	case <-timer.C:
+		close(abandoned)
+		return nil, errors.New("connection timed out")
+	}
+}
+
+// healthCheckHost computes the host to use for HTTP health check requests based
+// on the destination address. Addresses without a host component (e.g. Unix
+// domain socket paths) use "localhost".
+func healthCheckHost(address string) string {
+	if _, _, err := net.SplitHostPort(address); err == nil {
+		return address
+	}
+	return "localhost"
+}
+
+// checkDestinationHealth performs a single health check against a destination.
+// If path is empty, then the check only verifies that a connection can be
+// established. Otherwise, an HTTP GET request for the path is sent and the
+// check succeeds only if the response status doesn't indicate an error. The
+// entire check is limited to healthCheckTimeout.
+func checkDestinationHealth(destination Endpoint, host, path string) error {
+	// Open a connection to the destination and defer its closure.
+	deadline := time.Now().Add(healthCheckTimeout)
+	connection, err := openWithTimeout(destination, healthCheckTimeout)
+	if err != nil {
+		return fmt.Errorf("unable to connect: %w", err)
+	}
+	defer connection.Close()
+
+	// If this is a connection-only check, then we're done.
+	if path == "" {
+		return nil
+	}
+
+	// Limit the time allowed to complete the request.
+	connection.SetDeadline(deadline)
+
+	// Send the request.
+	request, err := http.NewRequest(http.MethodGet, "http://"+host+path, nil)
+	if err != nil {
+		return fmt.Errorf("unable to create request: %w", err)
+	}
+	request.Close = true
+	if err := request.Write(connection); err != nil {
+		return fmt.Errorf("unable to send request: %w", err)
+	}
+
+	// Read and validate the response.
+	response, err := http.ReadResponse(bufio.NewReader(connection), request)
+	if err != nil {
+		return fmt.Errorf("unable to read response: %w", err)
+	}
+	response.Body.Close()
+	if response.StatusCode >= http.StatusBadRequest {
+		return fmt.Errorf("unhealthy response status: %s", response.Status)
+	}
+
+	// Success.
+	return nil
+}
+
+// monitorDestinationHealth periodically performs health checks against the
+// destination and records the results in the destination endpoint state. The
+// first check is performed immediately. The destination must be the same
+// serializedEndpoint used by the forwarding loop so that checks don't open
+// connections concurrently with forwarding. This function blocks until the
+// context is cancelled.
+func (c *controller) monitorDestinationHealth(
+	ctx context.Context,
+	state *State,
+	destination *serializedEndpoint,
+	host, path string,
+	interval time.Duration,
+) {
+	// Create a ticker to regulate checks and defer its shutdown.
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	// Perform checks until cancelled.
+	healthy := true
+	for {
+		// Perform a check and record the result.
+		err := checkDestinationHealth(destination, host, path)
+		if ctx.Err() != nil {
+			return
+		}
+		c.stateLock.Lock()
+		if err != nil {
+			state.DestinationState.HealthCheckError = err.Error()
+		} else {
+			state.DestinationState.HealthCheckError = ""
+		}
+		c.stateLock.Unlock()
+
+		// Log health transitions.
+		if err != nil && healthy {
+			c.logger.Warnf("Destination unreachable: %v", err)
+		} else if err == nil && !healthy {
+			c.logger.Info("Destination reachable")
+		}
+		healthy = err == nil
+
+		// Wait for the next check or cancellation.
+		select {
+		case <-ticker.C:
+		case <-ctx.Done():
+			return
+		}
+	}
+}
@@ -0,0 +1,119 @@
+package forwarding
+
+import (
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+// testDialerEndpoint implements Endpoint by dialing a fixed TCP address.
+type testDialerEndpoint struct {
+	address string
+}
+
+func (e *testDialerEndpoint) TransportErrors() <-chan error {
+	return nil
+}
+
+func (e *testDialerEndpoint) Open() (net.Conn, error) {
+	return net.Dial("tcp", e.address)
+}
+
+func (e *testDialerEndpoint) Shutdown() error {
+	return nil
+}
+
+// testBlockingEndpoint implements Endpoint with an Open method that blocks until
+// the endpoint is released.
+type testBlockingEndpoint struct {
+	release chan struct{}
+}
+
+func (e *testBlockingEndpoint) TransportErrors() <-chan error {
+	return nil
+}
+
+func (e *testBlockingEndpoint) Open() (net.Conn, error) {
+	<-e.release
+	client, server := net.Pipe()
+	server.Close()
+	return client, nil
+}
+
+func (e *testBlockingEndpoint) Shutdown() error {
+	return nil
+}
+
+// TestOpenWithTimeout tests that openWithTimeout abandons connections that
+// aren't established within the timeout.
+func TestOpenWithTimeout(t *testing.T) {
+	// Create an endpoint that blocks until released and defer its release.
+	destination := &testBlockingEndpoint{make(chan struct{})}
+	defer close(destination.release)
+
+	// Verify that opening times out.
+	if _, err := openWithTimeout(destination, 10*time.Millisecond); err == nil {
+		t.Error("opening succeeded for blocked destination")
+	}
+}
+
+// TestHealthCheckHost tests healthCheckHost.
+func TestHealthCheckHost(t *testing.T) {
+	// Set up test cases.
+	testCases := []struct {
+		address  string
+		expected string
+	}{
+		{"localhost:8080", "localhost:8080"},
+		{"[::1]:80", "[::1]:80"},
+		{"/var/run/server.sock", "localhost"},
+	}
+
+	// Process test cases.
+	for _, testCase := range testCases {
+		if host := healthCheckHost(testCase.address); host != testCase.expected {
+			t.Errorf("host for %s (%s) does not match expected (%s)",
+				testCase.address, host, testCase.expected,
+			)
+		}
+	}
+}
+
+// TestCheckDestinationHealth tests checkDestinationHealth.
+func TestCheckDestinationHealth(t *testing.T) {
+	// Create a target server.
+	server := httptest.NewServer(http.HandlerFunc(func(writer http.ResponseWriter, request *http.Request) {
+		if request.URL.Path != "/healthz" {
+			http.NotFound(writer, request)
+		}
+	}))
+	defer server.Close()
+	destination := &testDialerEndpoint{strings.TrimPrefix(server.URL, "http://")}
+
+	// Verify connection-only checks.
+	if err := checkDestinationHealth(destination, "localhost", ""); err != nil {
+		t.Error("connection-only health check failed:", err)
+	}
+
+	// Verify HTTP checks.
+	if err := checkDestinationHealth(destination, "localhost", "/healthz"); err != nil {
+		t.Error("HTTP health check failed:", err)
+	}
+	if err := checkDestinationHealth(destination, "localhost", "/missing"); err == nil {
+		t.Error("HTTP health check succeeded for error status")
+	}
+
+	// Verify that checks fail for unreachable destinations.
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal("unable to create listener:", err)
+	}
+	unreachable := &testDialerEndpoint{listener.Addr().String()}
+	listener.Close()
+	if err := checkDestinationHealth(unreachable, "localhost", ""); err == nil {
+		t.Error("health check succeeded for unreachable destination")
+	}
+}
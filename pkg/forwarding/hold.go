@@ -0,0 +1,217 @@
+package forwarding
+
+import (
+	"errors"
+	"net"
+	"sync"
+	"time"
+)
+
+const (
+	// maximumHeldConnections is the maximum number of incoming connections
+	// that a holding endpoint will queue. Connections accepted in excess of
+	// this limit are closed immediately.
+	maximumHeldConnections = 128
+)
+
+// heldConnection is an incoming connection queued by a holding endpoint.
+type heldConnection struct {
+	// connection is the underlying connection.
+	connection net.Conn
+	// expiration is the timer that closes the connection if it isn't claimed
+	// before the hold timeout elapses.
+	expiration *time.Timer
+}
+
+// holdingEndpoint wraps a source endpoint and continuously accepts incoming
+// connections, queuing them until they're claimed by a forwarding loop. This
+// allows a source endpoint to be retained across forwarding cycles (e.g. while
+// the destination is reconnecting) without refusing connections. Connections
+// that aren't claimed within the hold timeout are closed. Forwarding loops
+// should claim connections using an endpoint created by cycle.
+type holdingEndpoint struct {
+	Endpoint
+	// timeout is the maximum amount of time that a connection is held.
+	timeout time.Duration
+	// queueLock guards queue.
+	queueLock sync.Mutex
+	// queue is the queue of held connections.
+	queue []*heldConnection
+	// available is signaled (without blocking) when a connection is queued.
+	available chan struct{}
+	// failed is closed when accepting from the underlying endpoint fails.
+	failed chan struct{}
+	// err is the error that caused accepting to fail. It may only be read
+	// after failed is closed.
+	err error
+}
+
+// newHoldingEndpoint creates a new holding endpoint that wraps the specified
+// source endpoint and begins accepting connections.
+func newHoldingEndpoint(source Endpoint, timeout time.Duration) *holdingEndpoint {
+	// Create the endpoint.
+	endpoint := &holdingEndpoint{
+		Endpoint:  source,
+		timeout:   timeout,
+		available: make(chan struct{}, 1),
+		failed:    make(chan struct{}),
+	}
+
+	// Start accepting connections.
+	go endpoint.accept()
+
+	// Done.
+	return endpoint
+}
+
+// accept accepts connections from the underlying endpoint and queues them
+// until accepting fails.
+func (e *holdingEndpoint) accept() {
+	for {
+		// Accept a connection.
+		connection, err := e.Endpoint.Open()
+		if err != nil {
+			e.err = err
+			close(e.failed)
+			return
+		}
+
+		// Queue the connection, unless the queue is full.
+		e.queueLock.Lock()
+		if len(e.queue) >= maximumHeldConnections {
+			e.queueLock.Unlock()
+			connection.Close()
+			continue
+		}
+		held := &heldConnection{connection: connection}
+		held.expiration = time.AfterFunc(e.timeout, func() {
+			e.expire(held)
+		})
+		e.queue = append(e.queue, held)
+		e.queueLock.Unlock()
+
+		// Signal availability.
+		select {
+		case e.available <- struct{}{}:
+		default:
+		}
+	}
+}
+
+// expire removes a held connection from the queue and closes it if it's still
+// queued.
+func (e *holdingEndpoint) expire(held *heldConnection) {
+	e.queueLock.Lock()
+	for i, h := range e.queue {
+		if h == held {
+			e.queue = append(e.queue[:i], e.queue[i+1:]...)
+			e.queueLock.Unlock()
+			held.connection.Close()
+			return
+		}
+	}
+	e.queueLock.Unlock()
+}
+
+// dequeue removes and returns the oldest held connection, if any.
+func (e *holdingEndpoint) dequeue() net.Conn {
+	e.queueLock.Lock()
+	defer e.queueLock.Unlock()
+	if len(e.queue) == 0 {
+		return nil
+	}
+	held := e.queue[0]
+	e.queue = e.queue[1:]
+	held.expiration.Stop()
+	return held.connection
+}
+
+// hasFailed returns whether or not accepting from the underlying endpoint has
+// failed.
+func (e *holdingEndpoint) hasFailed() bool {
+	select {
+	case <-e.failed:
+		return true
+	default:
+		return false
+	}
+}
+
+// BoundAddress implements BoundEndpoint.BoundAddress.
+func (e *holdingEndpoint) BoundAddress() string {
+	return boundAddress(e.Endpoint)
+}
+
+// ListenerStates implements MultiListenerEndpoint.ListenerStates.
+func (e *holdingEndpoint) ListenerStates() []*ListenerState {
+	return listenerStates(e.Endpoint)
+}
+
+// Open implements Endpoint.Open. Holding endpoints should only be opened via
+// cycle endpoints.
+func (e *holdingEndpoint) Open() (net.Conn, error) {
+	return nil, errors.New("holding endpoint requires cycle-based accepting")
+}
+
+// Shutdown implements Endpoint.Shutdown. It shuts down the underlying endpoint
+// and closes any held connections.
+func (e *holdingEndpoint) Shutdown() error {
+	// Shut down the underlying endpoint.
+	err := e.Endpoint.Shutdown()
+
+	// Close any held connections.
+	e.queueLock.Lock()
+	for _, held := range e.queue {
+		held.expiration.Stop()
+		held.connection.Close()
+	}
+	e.queue = nil
+	e.queueLock.Unlock()
+
+	// Done.
+	return err
+}
+
+// cycle creates an endpoint that claims held connections for a single
+// forwarding cycle. Shutting down the resulting endpoint only unblocks its
+// pending Open calls and doesn't affect the holding endpoint.
+func (e *holdingEndpoint) cycle() Endpoint {
+	return &holdingCycleEndpoint{
+		holdingEndpoint: e,
+		done:            make(chan struct{}),
+	}
+}
+
+// holdingCycleEndpoint claims held connections from a holding endpoint for a
+// single forwarding cycle.
+type holdingCycleEndpoint struct {
+	*holdingEndpoint
+	// done is closed when the cycle is terminated.
+	done chan struct{}
+	// doneOnce guards closure of done.
+	doneOnce sync.Once
+}
+
+// Open implements Endpoint.Open.
+func (e *holdingCycleEndpoint) Open() (net.Conn, error) {
+	for {
+		if connection := e.dequeue(); connection != nil {
+			return connection, nil
+		}
+		select {
+		case <-e.available:
+		case <-e.failed:
+			return nil, e.err
+		case <-e.done:
+			return nil, errors.New("forwarding cycle terminated")
+		}
+	}
+}
+
+// Shutdown implements Endpoint.Shutdown.
+func (e *holdingCycleEndpoint) Shutdown() error {
+	e.doneOnce.Do(func() {
+		close(e.done)
+	})
+	return nil
+}
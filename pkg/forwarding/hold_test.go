@@ -0,0 +1,93 @@
+package forwarding
+
+import (
+	"io"
+	"net"
+	"testing"
+	"time"
+)
+
+// TestHoldingEndpoint tests that holdingEndpoint holds connections across
+// forwarding cycles.
+func TestHoldingEndpoint(t *testing.T) {
+	// Create a holding endpoint and defer its shutdown.
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal("unable to create listener:", err)
+	}
+	held := newHoldingEndpoint(&testListenerEndpoint{listener}, time.Minute)
+	defer held.Shutdown()
+
+	// Start a cycle, verify that it receives a connection, and then verify
+	// that terminating the cycle unblocks pending accepts.
+	cycle := held.cycle()
+	client, err := net.Dial("tcp", listener.Addr().String())
+	if err != nil {
+		t.Fatal("unable to connect:", err)
+	}
+	defer client.Close()
+	if connection, err := cycle.Open(); err != nil {
+		t.Fatal("unable to accept connection:", err)
+	} else {
+		connection.Close()
+	}
+	go cycle.Shutdown()
+	if _, err := cycle.Open(); err == nil {
+		t.Fatal("accept succeeded after cycle termination")
+	}
+
+	// Connect while no cycle is active, then verify that a subsequent cycle
+	// receives the connection.
+	client, err = net.Dial("tcp", listener.Addr().String())
+	if err != nil {
+		t.Fatal("unable to connect:", err)
+	}
+	defer client.Close()
+	if _, err := client.Write([]byte("held")); err != nil {
+		t.Fatal("unable to write to held connection:", err)
+	}
+	time.Sleep(10 * time.Millisecond)
+	cycle = held.cycle()
+	connection, err := cycle.Open()
+	if err != nil {
+		t.Fatal("unable to accept held connection:", err)
+	}
+	defer connection.Close()
+	buffer := make([]byte, 4)
+	if _, err := io.ReadFull(connection, buffer); err != nil {
+		t.Fatal("unable to read from held connection:", err)
+	} else if string(buffer) != "held" {
+		t.Error("held connection data mismatch:", string(buffer))
+	}
+
+	// Verify that shutting down the holding endpoint fails the cycle.
+	held.Shutdown()
+	if _, err := cycle.Open(); err == nil {
+		t.Error("accept succeeded after shutdown")
+	} else if !held.hasFailed() {
+		t.Error("holding endpoint not marked as failed after shutdown")
+	}
+}
+
+// TestHoldingEndpointExpiration tests that holdingEndpoint closes connections
+// that aren't claimed within the hold timeout.
+func TestHoldingEndpointExpiration(t *testing.T) {
+	// Create a holding endpoint with a short timeout and defer its shutdown.
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal("unable to create listener:", err)
+	}
+	held := newHoldingEndpoint(&testListenerEndpoint{listener}, 50*time.Millisecond)
+	defer held.Shutdown()
+
+	// Connect and verify that the connection is closed once it expires.
+	client, err := net.Dial("tcp", listener.Addr().String())
+	if err != nil {
+		t.Fatal("unable to connect:", err)
+	}
+	defer client.Close()
+	client.SetReadDeadline(time.Now().Add(5 * time.Second))
+	if _, err := client.Read(make([]byte, 1)); err != io.EOF {
+		t.Error("unexpected result reading from expired connection:", err)
+	}
+}
@@ -0,0 +1,288 @@
+package forwarding
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"net/http/httputil"
+	"strings"
+	"time"
+
+	"github.com/mutagen-io/mutagen/pkg/logging"
+	"github.com/mutagen-io/mutagen/pkg/stream"
+)
+
+const (
+	// httpReadHeaderTimeout is the maximum amount of time that the HTTP server
+	// will wait for a client to send request headers. It prevents clients that
+	// never complete their requests from holding connections (and connection
+	// limiter slots) indefinitely.
+	httpReadHeaderTimeout = 30 * time.Second
+)
+
+// httpRoute is a parsed HTTP routing rule.
+type httpRoute struct {
+	// host is the lowercase host that the rule matches. If empty, then the rule
+	// matches any host.
+	host string
+	// pathPrefix is the path prefix that the rule matches. If empty, then the
+	// rule matches any path.
+	pathPrefix string
+	// target is the address to which matching requests are forwarded.
+	target string
+}
+
+// parseHTTPRoute parses an HTTP routing rule of the form "<match>=<target>",
+// where match is a host ("host"), a host and path prefix ("host/prefix"), or a
+// path prefix ("/prefix").
+func parseHTTPRoute(rule string) (*httpRoute, error) {
+	// Split the match and target.
+	match, target, ok := strings.Cut(rule, "=")
+	if !ok {
+		return nil, errors.New("missing target")
+	} else if match == "" {
+		return nil, errors.New("empty match")
+	}
+
+	// Ensure that the target is a valid host and port specification.
+	if _, _, err := net.SplitHostPort(target); err != nil {
+		return nil, fmt.Errorf("invalid target: %w", err)
+	}
+
+	// Split the host and path prefix. We strip any trailing slash from the
+	// prefix since matching is performed on path component boundaries.
+	var host, pathPrefix string
+	if index := strings.IndexByte(match, '/'); index >= 0 {
+		host, pathPrefix = match[:index], strings.TrimRight(match[index:], "/")
+	} else {
+		host = match
+	}
+
+	// Success.
+	return &httpRoute{
+		host:       strings.ToLower(host),
+		pathPrefix: pathPrefix,
+		target:     target,
+	}, nil
+}
+
+// matches returns whether or not the route matches the specified host (which
+// should be lowercase and have any port removed) and path.
+func (r *httpRoute) matches(host, path string) bool {
+	if r.host != "" && r.host != host {
+		return false
+	}
+	if r.pathPrefix != "" && path != r.pathPrefix && !strings.HasPrefix(path, r.pathPrefix+"/") {
+		return false
+	}
+	return true
+}
+
+// moreSpecificThan returns whether or not the route is more specific than
+// another route. Host matches take precedence over path prefix matches, with
+// longer path prefixes taking precedence over shorter ones.
+func (r *httpRoute) moreSpecificThan(other *httpRoute) bool {
+	if (r.host != "") != (other.host != "") {
+		return r.host != ""
+	}
+	return len(r.pathPrefix) > len(other.pathPrefix)
+}
+
+// httpRouter selects targets for HTTP requests.
+type httpRouter struct {
+	// routes are the routing rules.
+	routes []*httpRoute
+}
+
+// newHTTPRouter creates a new HTTP router from the specified routing rules.
+func newHTTPRouter(rules []string) (*httpRouter, error) {
+	routes := make([]*httpRoute, len(rules))
+	for r, rule := range rules {
+		if route, err := parseHTTPRoute(rule); err != nil {
+			return nil, fmt.Errorf("invalid HTTP route (%s): %w", rule, err)
+		} else {
+			routes[r] = route
+		}
+	}
+	return &httpRouter{routes}, nil
+}
+
+// route selects the target for the specified request. If multiple rules match
+// with equal specificity, then the last such rule is used, allowing rules to be
+// overridden by those appended during configuration merging.
+func (r *httpRouter) route(request *http.Request) (string, bool) {
+	// Extract and normalize the request host.
+	host := request.Host
+	if h, _, err := net.SplitHostPort(host); err == nil {
+		host = h
+	}
+	host = strings.ToLower(host)
+
+	// Find the most specific matching route.
+	var selected *httpRoute
+	for _, route := range r.routes {
+		if route.matches(host, request.URL.Path) && (selected == nil || !selected.moreSpecificThan(route)) {
+			selected = route
+		}
+	}
+	if selected == nil {
+		return "", false
+	}
+	return selected.target, true
+}
+
+// endpointListener adapts a source endpoint to implement net.Listener and
+// performs connection limiting and auditing on accepted connections. Closing
+// the listener has no effect since the endpoint's lifecycle is managed by the
+// forwarding loop.
+type endpointListener struct {
+	// logger is the logger used to report rejected connections.
+	logger *logging.Logger
+	// endpoint is the underlying endpoint.
+	endpoint Endpoint
+	// limiter is the connection limiter.
+	limiter *connectionLimiter
+	// incomingAuditor is the auditor for data written to accepted connections.
+	incomingAuditor stream.Auditor
+	// outgoingAuditor is the auditor for data read from accepted connections.
+	outgoingAuditor stream.Auditor
+}
+
+// Accept implements net.Listener.Accept.
+func (l *endpointListener) Accept() (net.Conn, error) {
+	for {
+		// Accept a connection from the endpoint.
+		connection, err := l.endpoint.Open()
+		if err != nil {
+			return nil, err
+		}
+
+		// Wait for capacity to serve the connection.
+		if err := l.limiter.acquire(context.Background(), connectionQueueTimeout); err != nil {
+			l.logger.Warnf("Rejecting connection from %s: %v", connection.RemoteAddr(), err)
+			connection.Close()
+			continue
+		}
+
+		// Wrap the connection.
+		return &limitedConn{
+			Conn: &auditedConn{
+				Conn:         connection,
+				readAuditor:  l.outgoingAuditor,
+				writeAuditor: l.incomingAuditor,
+			},
+			limiter: l.limiter,
+		}, nil
+	}
+}
+
+// Close implements net.Listener.Close.
+func (l *endpointListener) Close() error {
+	return nil
+}
+
+// Addr implements net.Listener.Addr.
+func (l *endpointListener) Addr() net.Addr {
+	return &net.TCPAddr{}
+}
+
+// auditedConn wraps a connection and performs auditing of data read from and
+// written to the connection.
+type auditedConn struct {
+	net.Conn
+	// readAuditor is the auditor for data read from the connection.
+	readAuditor stream.Auditor
+	// writeAuditor is the auditor for data written to the connection.
+	writeAuditor stream.Auditor
+}
+
+// Read implements net.Conn.Read.
+func (c *auditedConn) Read(buffer []byte) (int, error) {
+	n, err := c.Conn.Read(buffer)
+	if n > 0 {
+		c.readAuditor(uint64(n))
+	}
+	return n, err
+}
+
+// Write implements net.Conn.Write.
+func (c *auditedConn) Write(buffer []byte) (int, error) {
+	n, err := c.Conn.Write(buffer)
+	if n > 0 {
+		c.writeAuditor(uint64(n))
+	}
+	return n, err
+}
+
+// serveHTTP serves HTTP requests accepted from the source, forwarding each to
+// the target selected by the router using the destination. Accepted connections
+// are subject to the connection limiter. Data written to incoming connections
+// is audited by incomingAuditor, and data read from them (i.e. data forwarded
+// towards targets) is audited by outgoingAuditor. The connection tracker is
+// invoked with 1 when a connection is accepted and -1 when it is closed. This
+// function blocks until accepting from the source fails, at which point all
+// connections are closed.
+func serveHTTP(
+	logger *logging.Logger,
+	source Endpoint,
+	destination TargetedEndpoint,
+	router *httpRouter,
+	limiter *connectionLimiter,
+	incomingAuditor, outgoingAuditor stream.Auditor,
+	connectionTracker func(int),
+) error {
+	// Create the transport used to reach targets.
+	transport := &http.Transport{
+		DialContext: func(_ context.Context, _, address string) (net.Conn, error) {
+			return destination.OpenTarget(address)
+		},
+	}
+	defer transport.CloseIdleConnections()
+
+	// Create the reverse proxy. Routing is performed before the proxy is
+	// invoked, so the director need only set the scheme.
+	proxy := &httputil.ReverseProxy{
+		Director: func(request *http.Request) {
+			request.URL.Scheme = "http"
+		},
+		Transport: transport,
+		ErrorHandler: func(writer http.ResponseWriter, request *http.Request, err error) {
+			logger.Debugf("Unable to proxy HTTP request to %s: %v", request.URL.Host, err)
+			writer.WriteHeader(http.StatusBadGateway)
+		},
+	}
+
+	// Create the server.
+	server := &http.Server{
+		ReadHeaderTimeout: httpReadHeaderTimeout,
+		Handler: http.HandlerFunc(func(writer http.ResponseWriter, request *http.Request) {
+			target, ok := router.route(request)
+			if !ok {
+				http.Error(writer, "no matching HTTP route", http.StatusNotFound)
+				return
+			}
+			request.URL.Host = target
+			proxy.ServeHTTP(writer, request)
+		}),
+		ConnState: func(_ net.Conn, state http.ConnState) {
+			switch state {
+			case http.StateNew:
+				connectionTracker(1)
+			case http.StateClosed, http.StateHijacked:
+				connectionTracker(-1)
+			}
+		},
+	}
+	defer server.Close()
+
+	// Serve requests until accepting fails.
+	return server.Serve(&endpointListener{
+		logger:          logger,
+		endpoint:        source,
+		limiter:         limiter,
+		incomingAuditor: incomingAuditor,
+		outgoingAuditor: outgoingAuditor,
+	})
+}
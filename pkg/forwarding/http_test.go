@@ -0,0 +1,225 @@
+package forwarding
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+)
+
+// TestParseHTTPRoute tests parseHTTPRoute.
+func TestParseHTTPRoute(t *testing.T) {
+	// Define test cases.
+	testCases := []struct {
+		rule          string
+		expected      httpRoute
+		expectFailure bool
+	}{
+		{"", httpRoute{}, true},
+		{"api.localhost", httpRoute{}, true},
+		{"=api:80", httpRoute{}, true},
+		{"api.localhost=api", httpRoute{}, true},
+		{"API.localhost=api:80", httpRoute{host: "api.localhost", target: "api:80"}, false},
+		{"/static/=web:8080", httpRoute{pathPrefix: "/static", target: "web:8080"}, false},
+		{"app.localhost/api=api:3000", httpRoute{host: "app.localhost", pathPrefix: "/api", target: "api:3000"}, false},
+		{"/=[::1]:80", httpRoute{target: "[::1]:80"}, false},
+	}
+
+	// Process test cases.
+	for _, testCase := range testCases {
+		route, err := parseHTTPRoute(testCase.rule)
+		if err != nil {
+			if !testCase.expectFailure {
+				t.Errorf("unable to parse route (%s): %v", testCase.rule, err)
+			}
+			continue
+		} else if testCase.expectFailure {
+			t.Errorf("route (%s) parsed unexpectedly", testCase.rule)
+			continue
+		}
+		if *route != testCase.expected {
+			t.Errorf("route (%s) does not match expected: %+v != %+v", testCase.rule, *route, testCase.expected)
+		}
+	}
+}
+
+// TestHTTPRouter tests httpRouter.
+func TestHTTPRouter(t *testing.T) {
+	// Create the router.
+	router, err := newHTTPRouter([]string{
+		"/=default:80",
+		"/api=api:80",
+		"/api/v2=api-v2:80",
+		"admin.localhost=admin:80",
+		"admin.localhost/api=admin-api:80",
+		"/static=static:80",
+		"/static=static-override:80",
+	})
+	if err != nil {
+		t.Fatal("unable to create router:", err)
+	}
+
+	// Define test cases.
+	testCases := []struct {
+		host     string
+		path     string
+		expected string
+	}{
+		{"localhost:8080", "/", "default:80"},
+		{"localhost:8080", "/apiary", "default:80"},
+		{"localhost:8080", "/api", "api:80"},
+		{"localhost:8080", "/api/users", "api:80"},
+		{"localhost:8080", "/api/v2/users", "api-v2:80"},
+		{"ADMIN.localhost:8080", "/", "admin:80"},
+		{"admin.localhost", "/api/users", "admin-api:80"},
+		{"localhost", "/static/style.css", "static-override:80"},
+	}
+
+	// Process test cases.
+	for _, testCase := range testCases {
+		request := httptest.NewRequest(http.MethodGet, "http://"+testCase.host+testCase.path, nil)
+		if target, ok := router.route(request); !ok {
+			t.Errorf("no route found for %s%s", testCase.host, testCase.path)
+		} else if target != testCase.expected {
+			t.Errorf("route for %s%s does not match expected: %s != %s",
+				testCase.host, testCase.path, target, testCase.expected,
+			)
+		}
+	}
+
+	// Ensure that unmatched requests aren't routed.
+	router, err = newHTTPRouter([]string{"/api=api:80"})
+	if err != nil {
+		t.Fatal("unable to create router:", err)
+	}
+	if _, ok := router.route(httptest.NewRequest(http.MethodGet, "http://localhost/other", nil)); ok {
+		t.Error("unmatched request routed unexpectedly")
+	}
+}
+
+// testListenerEndpoint implements Endpoint for a TCP listener.
+type testListenerEndpoint struct {
+	listener net.Listener
+}
+
+func (e *testListenerEndpoint) TransportErrors() <-chan error {
+	return nil
+}
+
+func (e *testListenerEndpoint) Open() (net.Conn, error) {
+	return e.listener.Accept()
+}
+
+func (e *testListenerEndpoint) Shutdown() error {
+	return e.listener.Close()
+}
+
+// testTargetedEndpoint implements TargetedEndpoint by mapping target addresses
+// to local addresses.
+type testTargetedEndpoint struct {
+	targets map[string]string
+}
+
+func (e *testTargetedEndpoint) TransportErrors() <-chan error {
+	return nil
+}
+
+func (e *testTargetedEndpoint) Open() (net.Conn, error) {
+	return nil, errors.New("targeted endpoint requires an explicit target")
+}
+
+func (e *testTargetedEndpoint) OpenTarget(address string) (net.Conn, error) {
+	if local, ok := e.targets[address]; ok {
+		return net.Dial("tcp", local)
+	}
+	return nil, fmt.Errorf("unknown target: %s", address)
+}
+
+func (e *testTargetedEndpoint) Shutdown() error {
+	return nil
+}
+
+// TestServeHTTP tests serveHTTP.
+func TestServeHTTP(t *testing.T) {
+	// Create target servers.
+	api := httptest.NewServer(http.HandlerFunc(func(writer http.ResponseWriter, request *http.Request) {
+		fmt.Fprint(writer, "api:", request.URL.Path)
+	}))
+	defer api.Close()
+	web := httptest.NewServer(http.HandlerFunc(func(writer http.ResponseWriter, request *http.Request) {
+		fmt.Fprint(writer, "web:", request.URL.Path)
+	}))
+	defer web.Close()
+
+	// Create the source and destination.
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal("unable to create listener:", err)
+	}
+	source := &testListenerEndpoint{listener}
+	destination := &testTargetedEndpoint{map[string]string{
+		"api:80": api.Listener.Addr().String(),
+		"web:80": web.Listener.Addr().String(),
+	}}
+
+	// Create the router.
+	router, err := newHTTPRouter([]string{"/=web:80", "/api=api:80", "/missing=missing:80"})
+	if err != nil {
+		t.Fatal("unable to create router:", err)
+	}
+
+	// Start serving.
+	var inbound, outbound, connections int64
+	served := make(chan error, 1)
+	go func() {
+		served <- serveHTTP(nil, source, destination, router, nil,
+			func(amount uint64) { atomic.AddInt64(&inbound, int64(amount)) },
+			func(amount uint64) { atomic.AddInt64(&outbound, int64(amount)) },
+			func(delta int) { atomic.AddInt64(&connections, int64(delta)) },
+		)
+	}()
+
+	// Perform requests.
+	base := "http://" + listener.Addr().String()
+	for path, expected := range map[string]string{
+		"/index.html": "web:/index.html",
+		"/api/users":  "api:/api/users",
+	} {
+		response, err := http.Get(base + path)
+		if err != nil {
+			t.Fatal("unable to perform request:", err)
+		}
+		body, err := io.ReadAll(response.Body)
+		response.Body.Close()
+		if err != nil {
+			t.Fatal("unable to read response body:", err)
+		} else if string(body) != expected {
+			t.Errorf("response for %s does not match expected: %s != %s", path, body, expected)
+		}
+	}
+
+	// Ensure that dialing failures result in bad gateway responses.
+	if response, err := http.Get(base + "/missing"); err != nil {
+		t.Fatal("unable to perform request:", err)
+	} else {
+		response.Body.Close()
+		if response.StatusCode != http.StatusBadGateway {
+			t.Error("unexpected status code for failed target:", response.StatusCode)
+		}
+	}
+
+	// Verify that data transfer was audited.
+	if atomic.LoadInt64(&inbound) == 0 || atomic.LoadInt64(&outbound) == 0 {
+		t.Error("data transfer not audited")
+	}
+
+	// Shut down the source and ensure that serving terminates.
+	source.Shutdown()
+	if err := <-served; err == nil {
+		t.Error("serving terminated without error")
+	}
+}
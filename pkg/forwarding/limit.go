@@ -0,0 +1,91 @@
+package forwarding
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"sync"
+	"time"
+)
+
+const (
+	// connectionQueueTimeout is the maximum amount of time that a connection
+	// accepted in excess of a session's connection limit will wait for capacity
+	// to become available before being rejected.
+	connectionQueueTimeout = 10 * time.Second
+)
+
+// connectionLimiter limits the number of simultaneously forwarded connections.
+// A nil connection limiter imposes no limit.
+type connectionLimiter struct {
+	// maximum is the maximum number of simultaneous connections.
+	maximum uint64
+	// slots is a buffered channel whose occupancy tracks the number of active
+	// connections.
+	slots chan struct{}
+}
+
+// newConnectionLimiter creates a new connection limiter with the specified
+// maximum connection count. If maximum is 0, then nil is returned.
+func newConnectionLimiter(maximum uint64) *connectionLimiter {
+	if maximum == 0 {
+		return nil
+	}
+	return &connectionLimiter{
+		maximum: maximum,
+		slots:   make(chan struct{}, maximum),
+	}
+}
+
+// acquire reserves capacity for a connection, waiting up to the specified
+// timeout for capacity to become available. If capacity is reserved, then it
+// must be released with release once the connection is closed.
+func (l *connectionLimiter) acquire(ctx context.Context, timeout time.Duration) error {
+	// If there's no limit, then there's nothing to reserve.
+	if l == nil {
+		return nil
+	}
+
+	// Attempt to reserve capacity without waiting.
+	select {
+	case l.slots <- struct{}{}:
+		return nil
+	default:
+	}
+
+	// Wait for capacity to become available.
+	timer := time.NewTimer(timeout)
+	defer timer.Stop()
+	select {
+	case l.slots <- struct{}{}:
+		return nil
+	case <-timer.C:
+		return fmt.Errorf("maximum connection count (%d) reached", l.maximum)
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// release releases capacity reserved by a successful call to acquire.
+func (l *connectionLimiter) release() {
+	if l != nil {
+		<-l.slots
+	}
+}
+
+// limitedConn wraps a connection and releases its connection limiter capacity
+// when closed.
+type limitedConn struct {
+	net.Conn
+	// limiter is the connection limiter.
+	limiter *connectionLimiter
+	// releaseOnce guards the release of capacity.
+	releaseOnce sync.Once
+}
+
+// Close implements net.Conn.Close.
+func (c *limitedConn) Close() error {
+	err := c.Conn.Close()
+	c.releaseOnce.Do(c.limiter.release)
+	return err
+}
@@ -0,0 +1,55 @@
+package forwarding
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+// TestConnectionLimiterNil tests that a nil connection limiter imposes no limit.
+func TestConnectionLimiterNil(t *testing.T) {
+	limiter := newConnectionLimiter(0)
+	if limiter != nil {
+		t.Fatal("non-nil limiter created for zero maximum")
+	}
+	for i := 0; i < 10; i++ {
+		if err := limiter.acquire(context.Background(), 0); err != nil {
+			t.Fatal("unable to acquire capacity from nil limiter:", err)
+		}
+	}
+	limiter.release()
+}
+
+// TestConnectionLimiter tests that a connection limiter queues and rejects
+// connections in excess of its limit.
+func TestConnectionLimiter(t *testing.T) {
+	// Create a limiter and exhaust its capacity.
+	limiter := newConnectionLimiter(2)
+	for i := 0; i < 2; i++ {
+		if err := limiter.acquire(context.Background(), 0); err != nil {
+			t.Fatal("unable to acquire capacity:", err)
+		}
+	}
+
+	// Verify that further acquisitions are rejected.
+	if err := limiter.acquire(context.Background(), 10*time.Millisecond); err == nil {
+		t.Error("capacity acquired in excess of limit")
+	}
+
+	// Verify that queued acquisitions succeed once capacity is released.
+	acquired := make(chan error, 1)
+	go func() {
+		acquired <- limiter.acquire(context.Background(), time.Minute)
+	}()
+	limiter.release()
+	if err := <-acquired; err != nil {
+		t.Error("unable to acquire released capacity:", err)
+	}
+
+	// Verify that queued acquisitions are aborted by cancellation.
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if err := limiter.acquire(ctx, time.Minute); err != context.Canceled {
+		t.Error("unexpected error for cancelled acquisition:", err)
+	}
+}
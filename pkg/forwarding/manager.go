@@ -185,6 +185,15 @@ func (m *Manager) Shutdown() {
 	}
 }
 
+// HandleConnectivityChange notifies all sessions of a host network
+// configuration change or system wake, causing sessions with non-local
+// endpoints to reconnect immediately.
+func (m *Manager) HandleConnectivityChange() {
+	for _, controller := range m.allControllers() {
+		controller.handleConnectivityChange()
+	}
+}
+
 // Create tells the manager to create a new session.
 func (m *Manager) Create(
 	ctx context.Context,
@@ -0,0 +1,6 @@
+// Package mdns provides a minimal multicast DNS (mDNS) responder for
+// advertising services via DNS-based service discovery (DNS-SD), as described
+// in RFC 6762 and RFC 6763. It only supports advertising (not browsing) and
+// only operates over IPv4, though it can advertise both IPv4 and IPv6
+// addresses.
+package mdns
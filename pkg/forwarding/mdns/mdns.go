@@ -0,0 +1,533 @@
+package mdns
+
+import (
+	"errors"
+	"fmt"
+	"net"
+	"strings"
+	"time"
+
+	"golang.org/x/net/dns/dnsmessage"
+
+	"github.com/mutagen-io/mutagen/pkg/logging"
+)
+
+const (
+	// recordTTL is the time-to-live (in seconds) for advertised records.
+	recordTTL = 120
+	// announcementCount is the number of unsolicited announcements sent when
+	// advertising starts.
+	announcementCount = 2
+	// announcementInterval is the interval between unsolicited announcements.
+	announcementInterval = time.Second
+	// probeCount is the number of probe queries sent before advertising to
+	// verify that the advertised names aren't already in use.
+	probeCount = 3
+	// probeInterval is the interval between probe queries.
+	probeInterval = 250 * time.Millisecond
+	// maximumMessageSize is the maximum size of a received mDNS message.
+	maximumMessageSize = 9000
+	// classINCacheFlush is the Internet class with the cache-flush bit set,
+	// which is used for records that are unique to this responder.
+	classINCacheFlush = dnsmessage.ClassINET | 0x8000
+	// unicastResponseBit is the bit set in a question's class to indicate
+	// that a unicast response is requested.
+	unicastResponseBit = 0x8000
+	// servicesName is the DNS-SD service type enumeration name.
+	servicesName = "_services._dns-sd._udp.local."
+)
+
+// multicastAddress is the IPv4 mDNS multicast address.
+var multicastAddress = &net.UDPAddr{IP: net.IPv4(224, 0, 0, 251), Port: 5353}
+
+// Service describes a service to be advertised.
+type Service struct {
+	// Instance is the service instance name (e.g. "My Development Server"). It
+	// must be a valid DNS label that doesn't contain periods.
+	Instance string
+	// Type is the service type (e.g. "_http._tcp").
+	Type string
+	// Host is the host name (without the ".local" domain) used for the
+	// service's address records. It should be unique to the service, since
+	// these records are claimed exclusively by the advertiser, and thus it
+	// shouldn't be the machine's own host name.
+	Host string
+	// Port is the port on which the service is listening.
+	Port uint16
+	// Addresses are the IPv4 addresses on which the service is reachable.
+	// Advertisement is only performed via IPv4, so IPv6 addresses aren't
+	// supported.
+	Addresses []net.IP
+}
+
+// EnsureValid ensures that Service's invariants are respected.
+func (s *Service) EnsureValid() error {
+	// A nil service is not valid.
+	if s == nil {
+		return errors.New("nil service")
+	}
+
+	// Validate the components of the service.
+	if err := ValidateInstance(s.Instance); err != nil {
+		return fmt.Errorf("invalid instance name: %w", err)
+	} else if err = ValidateType(s.Type); err != nil {
+		return fmt.Errorf("invalid service type: %w", err)
+	} else if s.Host == "" || strings.Contains(s.Host, ".") {
+		return errors.New("invalid host name")
+	} else if s.Port == 0 {
+		return errors.New("invalid port")
+	} else if len(s.Addresses) == 0 {
+		return errors.New("no addresses")
+	}
+
+	// Validate that addresses are IPv4 addresses.
+	for _, address := range s.Addresses {
+		if address.To4() == nil {
+			return fmt.Errorf("non-IPv4 address: %s", address)
+		}
+	}
+
+	// Success.
+	return nil
+}
+
+// ValidateInstance validates a service instance name.
+func ValidateInstance(instance string) error {
+	if instance == "" {
+		return errors.New("empty name")
+	} else if len(instance) > 63 {
+		return errors.New("name too long")
+	} else if strings.Contains(instance, ".") {
+		return errors.New("name contains period")
+	}
+	return nil
+}
+
+// ValidateType validates a service type, which must be of the form
+// "_<name>._tcp" or "_<name>._udp".
+func ValidateType(serviceType string) error {
+	components := strings.Split(serviceType, ".")
+	if len(components) != 2 {
+		return errors.New("service type must have two components")
+	} else if len(components[0]) < 2 || len(components[0]) > 16 || components[0][0] != '_' {
+		return errors.New("invalid service name component")
+	} else if components[1] != "_tcp" && components[1] != "_udp" {
+		return errors.New("invalid protocol component")
+	}
+	return nil
+}
+
+// names are the fully qualified names associated with a service.
+type names struct {
+	// services is the service type enumeration name.
+	services dnsmessage.Name
+	// service is the service type name.
+	service dnsmessage.Name
+	// instance is the service instance name.
+	instance dnsmessage.Name
+	// host is the host name.
+	host dnsmessage.Name
+}
+
+// names computes the fully qualified names associated with the service.
+func (s *Service) names() (*names, error) {
+	services, err := dnsmessage.NewName(servicesName)
+	if err != nil {
+		return nil, fmt.Errorf("invalid services name: %w", err)
+	}
+	service, err := dnsmessage.NewName(s.Type + ".local.")
+	if err != nil {
+		return nil, fmt.Errorf("invalid service name: %w", err)
+	}
+	instance, err := dnsmessage.NewName(s.Instance + "." + s.Type + ".local.")
+	if err != nil {
+		return nil, fmt.Errorf("invalid instance name: %w", err)
+	}
+	host, err := dnsmessage.NewName(s.Host + ".local.")
+	if err != nil {
+		return nil, fmt.Errorf("invalid host name: %w", err)
+	}
+	return &names{services, service, instance, host}, nil
+}
+
+// namesEqual performs a case-insensitive comparison of two DNS names.
+func namesEqual(first, second dnsmessage.Name) bool {
+	return strings.EqualFold(first.String(), second.String())
+}
+
+// matches returns whether or not a question of the specified type matches any
+// of the specified record types.
+func matches(question dnsmessage.Type, types ...dnsmessage.Type) bool {
+	if question == dnsmessage.TypeALL {
+		return true
+	}
+	for _, t := range types {
+		if question == t {
+			return true
+		}
+	}
+	return false
+}
+
+// recordSet tracks which records should be included in a response.
+type recordSet struct {
+	// servicesPointer indicates whether or not the service type enumeration
+	// pointer should be included.
+	servicesPointer bool
+	// instancePointer indicates whether or not the service instance pointer
+	// should be included.
+	instancePointer bool
+	// instance indicates whether or not the service instance records (SRV and
+	// TXT) should be included.
+	instance bool
+	// addresses indicates whether or not address records should be included.
+	addresses bool
+}
+
+// any returns whether or not the record set is non-empty.
+func (r recordSet) any() bool {
+	return r.servicesPointer || r.instancePointer || r.instance || r.addresses
+}
+
+// respond computes the response to the specified questions. If none of the
+// questions pertain to the service, then nil is returned. It also returns
+// whether or not the response should be sent via unicast, which is the case if
+// every question requested a unicast response.
+func (s *Service) respond(questions []dnsmessage.Question, n *names) ([]byte, bool, error) {
+	// Determine which records to include and whether or not every question
+	// requested a unicast response.
+	var records recordSet
+	unicast := len(questions) > 0
+	for _, q := range questions {
+		if q.Class&unicastResponseBit == 0 {
+			unicast = false
+		}
+		if namesEqual(q.Name, n.services) && matches(q.Type, dnsmessage.TypePTR) {
+			records.servicesPointer = true
+		} else if namesEqual(q.Name, n.service) && matches(q.Type, dnsmessage.TypePTR) {
+			records.instancePointer = true
+			records.instance = true
+			records.addresses = true
+		} else if namesEqual(q.Name, n.instance) && matches(q.Type, dnsmessage.TypeSRV, dnsmessage.TypeTXT) {
+			records.instance = true
+			records.addresses = true
+		} else if namesEqual(q.Name, n.host) && matches(q.Type, dnsmessage.TypeA) {
+			records.addresses = true
+		}
+	}
+	if !records.any() {
+		return nil, false, nil
+	}
+
+	// Build the response.
+	response, err := s.message(records, n, recordTTL)
+	return response, unicast, err
+}
+
+// message builds an mDNS response message containing the specified records.
+func (s *Service) message(records recordSet, n *names, ttl uint32) ([]byte, error) {
+	// Create the message builder.
+	builder := dnsmessage.NewBuilder(nil, dnsmessage.Header{Response: true, Authoritative: true})
+	builder.EnableCompression()
+	if err := builder.StartAnswers(); err != nil {
+		return nil, err
+	}
+
+	// Add the records.
+	if err := s.addRecords(&builder, records, n, ttl); err != nil {
+		return nil, err
+	}
+
+	// Finalize the message.
+	return builder.Finish()
+}
+
+// probe builds an mDNS probe query for the names that the service claims
+// exclusively, with the proposed records included in the authority section.
+func (s *Service) probe(n *names) ([]byte, error) {
+	// Create the message builder.
+	builder := dnsmessage.NewBuilder(nil, dnsmessage.Header{})
+	builder.EnableCompression()
+
+	// Add the questions. Probes request unicast responses.
+	if err := builder.StartQuestions(); err != nil {
+		return nil, err
+	}
+	for _, name := range []dnsmessage.Name{n.instance, n.host} {
+		question := dnsmessage.Question{
+			Name:  name,
+			Type:  dnsmessage.TypeALL,
+			Class: dnsmessage.ClassINET | unicastResponseBit,
+		}
+		if err := builder.Question(question); err != nil {
+			return nil, err
+		}
+	}
+
+	// Add the proposed records.
+	if err := builder.StartAuthorities(); err != nil {
+		return nil, err
+	}
+	if err := s.addRecords(&builder, recordSet{instance: true, addresses: true}, n, recordTTL); err != nil {
+		return nil, err
+	}
+
+	// Finalize the message.
+	return builder.Finish()
+}
+
+// conflicts returns whether or not an mDNS response message contains records
+// for the names that the service claims exclusively.
+func conflicts(message []byte, n *names) bool {
+	// Parse the header, ignoring anything that isn't a response.
+	var parser dnsmessage.Parser
+	if header, err := parser.Start(message); err != nil || !header.Response {
+		return false
+	} else if err = parser.SkipAllQuestions(); err != nil {
+		return false
+	}
+
+	// Check answer records for conflicting names.
+	for {
+		header, err := parser.AnswerHeader()
+		if err != nil {
+			return false
+		} else if namesEqual(header.Name, n.instance) || namesEqual(header.Name, n.host) {
+			return true
+		} else if err = parser.SkipAnswer(); err != nil {
+			return false
+		}
+	}
+}
+
+// addRecords adds the specified records to the current section of a message
+// builder.
+func (s *Service) addRecords(builder *dnsmessage.Builder, records recordSet, n *names, ttl uint32) error {
+	// Create resource header constructors.
+	shared := func(name dnsmessage.Name) dnsmessage.ResourceHeader {
+		return dnsmessage.ResourceHeader{Name: name, Class: dnsmessage.ClassINET, TTL: ttl}
+	}
+	unique := func(name dnsmessage.Name) dnsmessage.ResourceHeader {
+		return dnsmessage.ResourceHeader{Name: name, Class: classINCacheFlush, TTL: ttl}
+	}
+
+	// Add records.
+	if records.servicesPointer {
+		if err := builder.PTRResource(shared(n.services), dnsmessage.PTRResource{PTR: n.service}); err != nil {
+			return err
+		}
+	}
+	if records.instancePointer {
+		if err := builder.PTRResource(shared(n.service), dnsmessage.PTRResource{PTR: n.instance}); err != nil {
+			return err
+		}
+	}
+	if records.instance {
+		if err := builder.SRVResource(unique(n.instance), dnsmessage.SRVResource{Port: s.Port, Target: n.host}); err != nil {
+			return err
+		}
+		if err := builder.TXTResource(unique(n.instance), dnsmessage.TXTResource{TXT: []string{""}}); err != nil {
+			return err
+		}
+	}
+	if records.addresses {
+		for _, address := range s.Addresses {
+			resource := dnsmessage.AResource{}
+			copy(resource.A[:], address.To4())
+			if err := builder.AResource(unique(n.host), resource); err != nil {
+				return err
+			}
+		}
+	}
+
+	// Success.
+	return nil
+}
+
+// Advertiser advertises a service via mDNS.
+type Advertiser struct {
+	// logger is the underlying logger.
+	logger *logging.Logger
+	// service is the advertised service.
+	service *Service
+	// names are the names associated with the service.
+	names *names
+	// connection is the multicast connection.
+	connection *net.UDPConn
+	// conflicts is signaled (without blocking) when a conflicting response is
+	// received while probing.
+	conflicts chan struct{}
+	// probed is closed when probing completes without conflict, after which
+	// the advertiser answers queries.
+	probed chan struct{}
+	// done is closed when the advertiser is shut down.
+	done chan struct{}
+}
+
+// Advertise starts advertising the specified service via mDNS. Before records
+// are announced, the service's instance and host names are probed to ensure
+// that they aren't already in use on the network. If a conflict is detected,
+// then a warning is logged and the service isn't advertised. The advertiser
+// must be shut down to stop advertising.
+func Advertise(logger *logging.Logger, service *Service) (*Advertiser, error) {
+	// Validate the service and compute its names.
+	if err := service.EnsureValid(); err != nil {
+		return nil, fmt.Errorf("invalid service: %w", err)
+	}
+	n, err := service.names()
+	if err != nil {
+		return nil, err
+	}
+
+	// Join the mDNS multicast group.
+	connection, err := net.ListenMulticastUDP("udp4", nil, multicastAddress)
+	if err != nil {
+		return nil, fmt.Errorf("unable to join multicast group: %w", err)
+	}
+
+	// Create the advertiser.
+	advertiser := &Advertiser{
+		logger:     logger,
+		service:    service,
+		names:      n,
+		connection: connection,
+		conflicts:  make(chan struct{}, 1),
+		probed:     make(chan struct{}),
+		done:       make(chan struct{}),
+	}
+
+	// Start probing (followed by announcing) and responding.
+	go advertiser.announce()
+	go advertiser.respond()
+
+	// Success.
+	return advertiser, nil
+}
+
+// all is the record set containing all records.
+var all = recordSet{servicesPointer: true, instancePointer: true, instance: true, addresses: true}
+
+// isProbed returns whether or not probing has completed without conflict.
+func (a *Advertiser) isProbed() bool {
+	select {
+	case <-a.probed:
+		return true
+	default:
+		return false
+	}
+}
+
+// announce probes for conflicts with the service's names and then sends
+// unsolicited announcements of the service.
+func (a *Advertiser) announce() {
+	// Build the probe.
+	probe, err := a.service.probe(a.names)
+	if err != nil {
+		a.logger.Warnf("Unable to build mDNS probe: %v", err)
+		return
+	}
+
+	// Send probes, watching for conflicting responses.
+	for i := 0; i < probeCount; i++ {
+		if _, err := a.connection.WriteToUDP(probe, multicastAddress); err != nil {
+			a.logger.Debugf("Unable to send mDNS probe: %v", err)
+		}
+		select {
+		case <-time.After(probeInterval):
+		case <-a.conflicts:
+			a.logger.Warnf("mDNS name conflict detected for %s or %s, not advertising",
+				a.names.instance, a.names.host,
+			)
+			return
+		case <-a.done:
+			return
+		}
+	}
+	close(a.probed)
+
+	// Build the announcement.
+	announcement, err := a.service.message(all, a.names, recordTTL)
+	if err != nil {
+		a.logger.Warnf("Unable to build mDNS announcement: %v", err)
+		return
+	}
+
+	// Send announcements.
+	for i := 0; i < announcementCount; i++ {
+		if _, err := a.connection.WriteToUDP(announcement, multicastAddress); err != nil {
+			a.logger.Debugf("Unable to send mDNS announcement: %v", err)
+		}
+		select {
+		case <-time.After(announcementInterval):
+		case <-a.done:
+			return
+		}
+	}
+}
+
+// respond responds to mDNS queries until the connection is closed.
+func (a *Advertiser) respond() {
+	buffer := make([]byte, maximumMessageSize)
+	for {
+		// Read a message.
+		length, sender, err := a.connection.ReadFromUDP(buffer)
+		if err != nil {
+			return
+		}
+
+		// If probing is still underway, then check for conflicting responses.
+		// We don't answer queries until probing completes, so any responses
+		// received during this time originate from other responders.
+		if !a.isProbed() {
+			if conflicts(buffer[:length], a.names) {
+				select {
+				case a.conflicts <- struct{}{}:
+				default:
+				}
+			}
+			continue
+		}
+
+		// Parse the message header and questions, ignoring responses.
+		var parser dnsmessage.Parser
+		if header, err := parser.Start(buffer[:length]); err != nil || header.Response {
+			continue
+		}
+		questions, err := parser.AllQuestions()
+		if err != nil {
+			continue
+		}
+
+		// Compute and send any response, sending it directly to the querier if
+		// a unicast response was requested.
+		response, unicast, err := a.service.respond(questions, a.names)
+		if err != nil {
+			a.logger.Debugf("Unable to build mDNS response: %v", err)
+		} else if response != nil {
+			destination := multicastAddress
+			if unicast {
+				destination = sender
+			}
+			if _, err := a.connection.WriteToUDP(response, destination); err != nil {
+				a.logger.Debugf("Unable to send mDNS response: %v", err)
+			}
+		}
+	}
+}
+
+// Shutdown stops advertising the service, sending a goodbye announcement (if
+// the service was advertised) so that cached records are expired.
+func (a *Advertiser) Shutdown() error {
+	// Signal shutdown.
+	close(a.done)
+
+	// Send a goodbye announcement if probing succeeded.
+	if a.isProbed() {
+		if goodbye, err := a.service.message(all, a.names, 0); err == nil {
+			a.connection.WriteToUDP(goodbye, multicastAddress)
+		}
+	}
+
+	// Close the connection.
+	return a.connection.Close()
+}
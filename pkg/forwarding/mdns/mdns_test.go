@@ -0,0 +1,224 @@
+package mdns
+
+import (
+	"net"
+	"testing"
+
+	"golang.org/x/net/dns/dnsmessage"
+)
+
+// TestValidateType tests ValidateType.
+func TestValidateType(t *testing.T) {
+	// Set up test cases.
+	testCases := []struct {
+		serviceType string
+		expectValid bool
+	}{
+		{"_http._tcp", true},
+		{"_ipp._udp", true},
+		{"", false},
+		{"http._tcp", false},
+		{"_http._sctp", false},
+		{"_http._tcp.local", false},
+		{"_averyveryverylongname._tcp", false},
+	}
+
+	// Process test cases.
+	for _, testCase := range testCases {
+		if err := ValidateType(testCase.serviceType); err == nil && !testCase.expectValid {
+			t.Errorf("invalid service type (%s) treated as valid", testCase.serviceType)
+		} else if err != nil && testCase.expectValid {
+			t.Errorf("valid service type (%s) treated as invalid: %v", testCase.serviceType, err)
+		}
+	}
+}
+
+// TestServiceRespond tests that services respond to queries with the expected
+// records.
+func TestServiceRespond(t *testing.T) {
+	// Create the service.
+	service := &Service{
+		Instance:  "Development Server",
+		Type:      "_http._tcp",
+		Host:      "workstation",
+		Port:      8080,
+		Addresses: []net.IP{net.IPv4(192, 168, 1, 10), net.IPv4(10, 0, 0, 10)},
+	}
+	if err := service.EnsureValid(); err != nil {
+		t.Fatal("service invalid:", err)
+	}
+	n, err := service.names()
+	if err != nil {
+		t.Fatal("unable to compute names:", err)
+	}
+
+	// Set up test cases.
+	testCases := []struct {
+		name          string
+		questionType  dnsmessage.Type
+		expectedTypes []dnsmessage.Type
+	}{
+		{"_services._dns-sd._udp.local.", dnsmessage.TypePTR, []dnsmessage.Type{dnsmessage.TypePTR}},
+		{"_HTTP._tcp.local.", dnsmessage.TypePTR, []dnsmessage.Type{
+			dnsmessage.TypePTR, dnsmessage.TypeSRV, dnsmessage.TypeTXT, dnsmessage.TypeA, dnsmessage.TypeA,
+		}},
+		{"Development Server._http._tcp.local.", dnsmessage.TypeSRV, []dnsmessage.Type{
+			dnsmessage.TypeSRV, dnsmessage.TypeTXT, dnsmessage.TypeA, dnsmessage.TypeA,
+		}},
+		{"workstation.local.", dnsmessage.TypeA, []dnsmessage.Type{dnsmessage.TypeA, dnsmessage.TypeA}},
+		{"workstation.local.", dnsmessage.TypeAAAA, nil},
+		{"_ipp._tcp.local.", dnsmessage.TypePTR, nil},
+		{"workstation.local.", dnsmessage.TypeMX, nil},
+	}
+
+	// Process test cases.
+	for _, testCase := range testCases {
+		// Compute the response.
+		question := dnsmessage.Question{
+			Name:  dnsmessage.MustNewName(testCase.name),
+			Type:  testCase.questionType,
+			Class: dnsmessage.ClassINET,
+		}
+		response, unicast, err := service.respond([]dnsmessage.Question{question}, n)
+		if err != nil {
+			t.Errorf("unable to respond to query for %s: %v", testCase.name, err)
+			continue
+		} else if testCase.expectedTypes == nil {
+			if response != nil {
+				t.Errorf("unexpected response to query for %s", testCase.name)
+			}
+			continue
+		} else if unicast {
+			t.Errorf("unicast response to multicast query for %s", testCase.name)
+		}
+
+		// Parse the response and verify the record types.
+		var message dnsmessage.Message
+		if err := message.Unpack(response); err != nil {
+			t.Errorf("unable to parse response to query for %s: %v", testCase.name, err)
+			continue
+		} else if !message.Header.Response {
+			t.Errorf("response to query for %s not marked as response", testCase.name)
+		}
+		if len(message.Answers) != len(testCase.expectedTypes) {
+			t.Errorf("response to query for %s has unexpected number of answers: %d != %d",
+				testCase.name, len(message.Answers), len(testCase.expectedTypes),
+			)
+			continue
+		}
+		for a, answer := range message.Answers {
+			if answer.Header.Type != testCase.expectedTypes[a] {
+				t.Errorf("response to query for %s has unexpected answer type: %v != %v",
+					testCase.name, answer.Header.Type, testCase.expectedTypes[a],
+				)
+			}
+		}
+	}
+}
+
+// TestServiceRespondUnicast tests that services request unicast responses only
+// if every question requests one.
+func TestServiceRespondUnicast(t *testing.T) {
+	// Create the service.
+	service := &Service{
+		Instance:  "Development Server",
+		Type:      "_http._tcp",
+		Host:      "workstation",
+		Port:      8080,
+		Addresses: []net.IP{net.IPv4(192, 168, 1, 10)},
+	}
+	n, err := service.names()
+	if err != nil {
+		t.Fatal("unable to compute names:", err)
+	}
+
+	// Create questions.
+	unicastQuestion := dnsmessage.Question{
+		Name:  dnsmessage.MustNewName("workstation.local."),
+		Type:  dnsmessage.TypeA,
+		Class: dnsmessage.ClassINET | unicastResponseBit,
+	}
+	multicastQuestion := dnsmessage.Question{
+		Name:  dnsmessage.MustNewName("_http._tcp.local."),
+		Type:  dnsmessage.TypePTR,
+		Class: dnsmessage.ClassINET,
+	}
+
+	// Verify unicast response selection.
+	if _, unicast, err := service.respond([]dnsmessage.Question{unicastQuestion}, n); err != nil {
+		t.Fatal("unable to respond:", err)
+	} else if !unicast {
+		t.Error("multicast response to unicast query")
+	}
+	if _, unicast, err := service.respond([]dnsmessage.Question{unicastQuestion, multicastQuestion}, n); err != nil {
+		t.Fatal("unable to respond:", err)
+	} else if unicast {
+		t.Error("unicast response to mixed query")
+	}
+}
+
+// TestServiceEnsureValidIPv6 tests that services with IPv6 addresses are
+// rejected.
+func TestServiceEnsureValidIPv6(t *testing.T) {
+	service := &Service{
+		Instance:  "Development Server",
+		Type:      "_http._tcp",
+		Host:      "workstation",
+		Port:      8080,
+		Addresses: []net.IP{net.ParseIP("fe80::1")},
+	}
+	if err := service.EnsureValid(); err == nil {
+		t.Error("service with IPv6 address treated as valid")
+	}
+}
+
+// TestConflicts tests that probe conflicts are detected based on responses.
+func TestConflicts(t *testing.T) {
+	// Create the service and a service that claims the same names.
+	service := &Service{
+		Instance:  "Development Server",
+		Type:      "_http._tcp",
+		Host:      "workstation",
+		Port:      8080,
+		Addresses: []net.IP{net.IPv4(192, 168, 1, 10)},
+	}
+	n, err := service.names()
+	if err != nil {
+		t.Fatal("unable to compute names:", err)
+	}
+	other := &Service{
+		Instance:  "Other Server",
+		Type:      "_http._tcp",
+		Host:      "workstation",
+		Port:      9090,
+		Addresses: []net.IP{net.IPv4(192, 168, 1, 20)},
+	}
+	otherNames, err := other.names()
+	if err != nil {
+		t.Fatal("unable to compute names:", err)
+	}
+
+	// Verify that probes (which are queries) don't conflict.
+	probe, err := service.probe(n)
+	if err != nil {
+		t.Fatal("unable to build probe:", err)
+	} else if conflicts(probe, n) {
+		t.Error("probe treated as conflicting response")
+	}
+
+	// Verify that responses claiming the host name conflict.
+	response, err := other.message(recordSet{addresses: true}, otherNames, recordTTL)
+	if err != nil {
+		t.Fatal("unable to build response:", err)
+	} else if !conflicts(response, n) {
+		t.Error("conflicting response not detected")
+	}
+
+	// Verify that responses for other names don't conflict.
+	response, err = other.message(recordSet{instance: true}, otherNames, recordTTL)
+	if err != nil {
+		t.Fatal("unable to build response:", err)
+	} else if conflicts(response, n) {
+		t.Error("non-conflicting response treated as conflicting")
+	}
+}
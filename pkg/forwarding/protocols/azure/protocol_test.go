@@ -0,0 +1,164 @@
+package azure
+
+import (
+	"context"
+	"io"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"testing"
+
+	"github.com/mutagen-io/mutagen/pkg/forwarding"
+	"github.com/mutagen-io/mutagen/pkg/logging"
+	urlpkg "github.com/mutagen-io/mutagen/pkg/url"
+)
+
+// fakeAzureCLI is an Azure CLI stand-in that logs its resource group and
+// arguments to the file specified by the FAKE_AZURE_LOG environment variable.
+// Agent invocations fail with the exit code and error output specified by the
+// FAKE_AZURE_EXIT and FAKE_AZURE_ERROR environment variables. All other remote
+// commands succeed without output.
+const fakeAzureCLI = `#!/bin/sh
+echo "$AZURE_DEFAULTS_GROUP: $*" >> "$FAKE_AZURE_LOG"
+case "$*" in
+*mutagen-agent*)
+	echo "$FAKE_AZURE_ERROR" >&2
+	exit "$FAKE_AZURE_EXIT"
+	;;
+esac
+exit 0
+`
+
+// TestConnect tests the Azure CLI invocations performed by Connect for Azure
+// URLs and the handling of agent invocation failures.
+func TestConnect(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip()
+	}
+
+	// Install the fake Azure CLI.
+	directory := t.TempDir()
+	if err := os.WriteFile(filepath.Join(directory, "az"), []byte(fakeAzureCLI), 0700); err != nil {
+		t.Fatal("unable to create fake Azure CLI:", err)
+	}
+	t.Setenv("MUTAGEN_AZURE_PATH", directory)
+
+	// Set up test cases.
+	testCases := []struct {
+		// raw is the raw URL.
+		raw string
+		// group is the resource group to lock in to the URL.
+		group string
+		// exitCode is the exit code for agent invocations.
+		exitCode string
+		// errorOutput is the error output for agent invocations.
+		errorOutput string
+		// expectedPrefix is the expected prefix for logged invocations.
+		expectedPrefix string
+		// expectInstall indicates whether or not an agent installation should
+		// be attempted.
+		expectInstall bool
+		// expectedError is a substring of the expected error.
+		expectedError string
+	}{
+		{
+			raw:            "azure://vm-a:tcp:localhost:8080",
+			exitCode:       "255",
+			errorOutput:    "ssh: connect to host 10.0.0.4 port 22: Connection refused",
+			expectedPrefix: ": ssh vm --name vm-a -- ",
+			expectedError:  "unable to handshake with agent process",
+		},
+		{
+			raw:            "azure://azureuser@vm-b:tcp:localhost:8080",
+			group:          "resources",
+			exitCode:       "255",
+			errorOutput:    "ssh: connect to host 10.0.0.5 port 22: Connection refused",
+			expectedPrefix: "resources: ssh vm --name vm-b --local-user azureuser -- ",
+			expectedError:  "unable to handshake with agent process",
+		},
+		{
+			raw:            "azure://azureuser@vm-c:tcp:localhost:8080",
+			group:          "resources",
+			exitCode:       "127",
+			errorOutput:    "sh: mutagen-agent: command not found",
+			expectedPrefix: "resources: ssh vm --name vm-c --local-user azureuser -- ",
+			expectInstall:  true,
+			expectedError:  "unable to install agent",
+		},
+	}
+
+	// Process test cases.
+	logger := logging.NewLogger(logging.LevelDisabled, io.Discard)
+	handler := &protocolHandler{}
+	for i, testCase := range testCases {
+		// Set up the fake Azure CLI behavior.
+		log := filepath.Join(t.TempDir(), "log")
+		t.Setenv("FAKE_AZURE_LOG", log)
+		t.Setenv("FAKE_AZURE_EXIT", testCase.exitCode)
+		t.Setenv("FAKE_AZURE_ERROR", testCase.errorOutput)
+
+		// Parse the URL with the resource group set in the environment, and
+		// then clear the resource group so that it can only be supplied by the
+		// URL.
+		t.Setenv("AZURE_DEFAULTS_GROUP", testCase.group)
+		if testCase.group == "" {
+			os.Unsetenv("AZURE_DEFAULTS_GROUP")
+		}
+		url, err := urlpkg.Parse(testCase.raw, urlpkg.Kind_Forwarding, true)
+		if err != nil {
+			t.Fatalf("test index %d: unable to parse URL: %v", i, err)
+		}
+		os.Unsetenv("AZURE_DEFAULTS_GROUP")
+
+		// Attempt to connect.
+		endpoint, err := handler.Connect(
+			context.Background(), logger, url, "", "session",
+			forwarding.Version_Version1, &forwarding.Configuration{}, false,
+		)
+		if err == nil {
+			endpoint.Shutdown()
+			t.Fatalf("test index %d: connect succeeded unexpectedly", i)
+		} else if !strings.Contains(err.Error(), testCase.expectedError) {
+			t.Errorf("test index %d: error does not match expected: %v", i, err)
+		}
+
+		// Verify the Azure CLI invocations.
+		contents, err := os.ReadFile(log)
+		if err != nil {
+			t.Fatalf("test index %d: unable to read invocation log: %v", i, err)
+		}
+		invocations := strings.Split(strings.TrimSpace(string(contents)), "\n")
+		if !strings.HasPrefix(invocations[0], testCase.expectedPrefix) || !strings.Contains(invocations[0], " multiplexer ") {
+			t.Errorf("test index %d: unexpected agent invocation: %s", i, invocations[0])
+		}
+		if testCase.expectInstall {
+			if len(invocations) < 2 || !strings.HasPrefix(invocations[1], testCase.expectedPrefix) || !strings.HasSuffix(invocations[1], " uname -s -m") {
+				t.Errorf("test index %d: platform probe not performed: %v", i, invocations)
+			}
+		} else if len(invocations) != 1 {
+			t.Errorf("test index %d: unexpected additional invocations: %v", i, invocations[1:])
+		}
+	}
+}
+
+// TestConnectWrongProtocolPanics tests that Connect panics if dispatched a URL
+// with the wrong protocol.
+func TestConnectWrongProtocolPanics(t *testing.T) {
+	defer func() {
+		if recover() == nil {
+			t.Error("connect did not panic")
+		}
+	}()
+	url := &urlpkg.URL{
+		Kind:     urlpkg.Kind_Forwarding,
+		Protocol: urlpkg.Protocol_Docker,
+		Host:     "host",
+		Path:     "tcp:localhost:8080",
+	}
+	handler := &protocolHandler{}
+	handler.Connect(
+		context.Background(), logging.NewLogger(logging.LevelDisabled, io.Discard), url, "", "session",
+		forwarding.Version_Version1, &forwarding.Configuration{}, false,
+	)
+}
@@ -42,8 +42,8 @@ func (p *protocolHandler) Connect(
 	// Verify that the URL is of the correct kind and protocol.
 	if url.Kind != urlpkg.Kind_Forwarding {
 		panic("non-forwarding URL dispatched to forwarding protocol handler")
-	} else if url.Protocol != urlpkg.Protocol_Docker {
-		panic("non-Docker URL dispatched to Docker protocol handler")
+	} else if url.Protocol != urlpkg.Protocol_Docker && url.Protocol != urlpkg.Protocol_Nerdctl {
+		panic("non-container URL dispatched to Docker protocol handler")
 	}
 
 	// Parse the target specification from the URL's Path component.
@@ -52,8 +52,17 @@ func (p *protocolHandler) Connect(
 		return nil, fmt.Errorf("unable to parse target specification: %w", err)
 	}
 
-	// Create a Docker agent transport.
-	transport, err := docker.NewTransport(url.Host, url.User, url.Environment, url.Parameters, prompter)
+	// Extract the agent directory, which is handled by the agent dialer rather
+	// than the Docker CLI.
+	agentDirectory, parameters := urlpkg.SplitAgentDirectory(url.Parameters)
+
+	// Create an agent transport using the appropriate command line interface.
+	var transport agent.Transport
+	if url.Protocol == urlpkg.Protocol_Nerdctl {
+		transport, err = docker.NewNerdctlTransport(url.Host, url.User, url.Environment, prompter)
+	} else {
+		transport, err = docker.NewTransport(url.Host, url.User, url.Environment, parameters, prompter)
+	}
 	if err != nil {
 		return nil, fmt.Errorf("unable to create Docker transport: %w", err)
 	}
@@ -65,7 +74,7 @@ func (p *protocolHandler) Connect(
 	// cancellation.
 	go func() {
 		// Perform the dialing operation.
-		stream, err := agent.Dial(logger, transport, agent.CommandForwarder, prompter)
+		stream, err := agent.DialShared(logger, url, transport, agent.CommandForwarder, 0, prompter, agentDirectory)
 
 		// Transmit the result or, if cancelled, close the stream.
 		select {
@@ -96,4 +105,8 @@ func (p *protocolHandler) Connect(
 func init() {
 	// Register the Docker protocol handler with the forwarding package.
 	forwarding.ProtocolHandlers[urlpkg.Protocol_Docker] = &protocolHandler{}
+
+	// Register the same handler for nerdctl, which uses a Docker-compatible
+	// command line interface.
+	forwarding.ProtocolHandlers[urlpkg.Protocol_Nerdctl] = &protocolHandler{}
 }
@@ -0,0 +1,3 @@
+// Package exec provides the exec forwarding session protocol implementation,
+// which connects to remote endpoints using a user-specified command.
+package exec
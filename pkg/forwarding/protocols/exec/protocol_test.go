@@ -0,0 +1,190 @@
+package exec
+
+import (
+	"context"
+	"io"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"testing"
+
+	"github.com/mutagen-io/mutagen/pkg/forwarding"
+	"github.com/mutagen-io/mutagen/pkg/logging"
+	urlpkg "github.com/mutagen-io/mutagen/pkg/url"
+)
+
+// fakeWrapper is a remote execution wrapper stand-in that logs its arguments to
+// the file specified by the FAKE_WRAPPER_LOG environment variable. Agent
+// invocations fail with the exit code specified by the FAKE_WRAPPER_EXIT
+// environment variable. All other commands succeed without output.
+const fakeWrapper = `#!/bin/sh
+echo "$*" >> "$FAKE_WRAPPER_LOG"
+case "$*" in
+*mutagen-agent*)
+	exit "$FAKE_WRAPPER_EXIT"
+	;;
+esac
+exit 0
+`
+
+// TestConnect tests the commands executed by Connect for exec URLs, including
+// template expansion, and the handling of agent invocation exit codes.
+func TestConnect(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip()
+	}
+
+	// Install the fake wrapper.
+	directory := t.TempDir()
+	if err := os.WriteFile(filepath.Join(directory, "mutagen-test-wrapper"), []byte(fakeWrapper), 0700); err != nil {
+		t.Fatal("unable to create fake wrapper:", err)
+	}
+	t.Setenv("PATH", directory+string(os.PathListSeparator)+os.Getenv("PATH"))
+
+	// Set up test cases.
+	testCases := []struct {
+		// raw is the raw URL.
+		raw string
+		// exitCode is the exit code for agent invocations.
+		exitCode string
+		// expectedPrefix is the expected prefix for logged invocations.
+		expectedPrefix string
+		// expectedMode is the expected agent mode.
+		expectedMode string
+		// expectInstall indicates whether or not an agent installation should
+		// be attempted.
+		expectInstall bool
+		// expectedError is a substring of the expected error.
+		expectedError string
+	}{
+		{
+			raw:            "exec:'mutagen-test-wrapper --target=a --':tcp:localhost:8080",
+			exitCode:       "1",
+			expectedPrefix: "--target=a -- ",
+			expectedMode:   "multiplexer",
+			expectedError:  "unable to handshake with agent process",
+		},
+		{
+			raw:            "exec:'mutagen-test-wrapper --mode={{.Mode}} --path={{.Path}} -- {{.AgentCommand}}':tcp:localhost:8081",
+			exitCode:       "1",
+			expectedPrefix: "--mode=forwarder --path=tcp:localhost:8081 -- ",
+			expectedMode:   "forwarder",
+			expectedError:  "unable to handshake with agent process",
+		},
+		{
+			raw:            "exec:'mutagen-test-wrapper --mode={{.Mode}} --path={{.Path}} -- {{.AgentCommand}}':tcp:localhost:8082",
+			exitCode:       "127",
+			expectedPrefix: "--mode=forwarder --path=tcp:localhost:8082 -- ",
+			expectedMode:   "forwarder",
+			expectInstall:  true,
+			expectedError:  "unable to install agent",
+		},
+	}
+
+	// Process test cases.
+	logger := logging.NewLogger(logging.LevelDisabled, io.Discard)
+	handler := &protocolHandler{}
+	for i, testCase := range testCases {
+		// Set up the fake wrapper behavior.
+		log := filepath.Join(t.TempDir(), "log")
+		t.Setenv("FAKE_WRAPPER_LOG", log)
+		t.Setenv("FAKE_WRAPPER_EXIT", testCase.exitCode)
+
+		// Parse the URL and attempt to connect.
+		url, err := urlpkg.Parse(testCase.raw, urlpkg.Kind_Forwarding, true)
+		if err != nil {
+			t.Fatalf("test index %d: unable to parse URL: %v", i, err)
+		}
+		endpoint, err := handler.Connect(
+			context.Background(), logger, url, "", "session",
+			forwarding.Version_Version1, &forwarding.Configuration{}, false,
+		)
+		if err == nil {
+			endpoint.Shutdown()
+			t.Fatalf("test index %d: connect succeeded unexpectedly", i)
+		} else if !strings.Contains(err.Error(), testCase.expectedError) {
+			t.Errorf("test index %d: error does not match expected: %v", i, err)
+		}
+
+		// Verify the wrapper invocations.
+		contents, err := os.ReadFile(log)
+		if err != nil {
+			t.Fatalf("test index %d: unable to read invocation log: %v", i, err)
+		}
+		invocations := strings.Split(strings.TrimSpace(string(contents)), "\n")
+		if !strings.HasPrefix(invocations[0], testCase.expectedPrefix) ||
+			!strings.Contains(invocations[0], " "+testCase.expectedMode+" ") {
+			t.Errorf("test index %d: unexpected agent invocation: %s", i, invocations[0])
+		}
+		if testCase.expectInstall {
+			if len(invocations) < 2 || invocations[1] != testCase.expectedPrefix+"uname -s -m" {
+				t.Errorf("test index %d: platform probe not performed: %v", i, invocations)
+			}
+		} else if len(invocations) != 1 {
+			t.Errorf("test index %d: unexpected additional invocations: %v", i, invocations[1:])
+		}
+	}
+}
+
+// TestConnectInvalidCommands tests that Connect rejects invalid commands and
+// copy command templates without executing anything.
+func TestConnectInvalidCommands(t *testing.T) {
+	// Set up test cases.
+	testCases := []struct {
+		command     string
+		copyCommand string
+	}{
+		{"", ""},
+		{"ssh {{.Path}}", ""},
+		{"ssh {{.Host}} {{.AgentCommand}}", ""},
+		{"ssh {{.Mode {{.AgentCommand}}", ""},
+		{"ssh host --", "cp {local}"},
+	}
+
+	// Process test cases.
+	logger := logging.NewLogger(logging.LevelDisabled, io.Discard)
+	handler := &protocolHandler{}
+	for i, testCase := range testCases {
+		url := &urlpkg.URL{
+			Kind:     urlpkg.Kind_Forwarding,
+			Protocol: urlpkg.Protocol_Exec,
+			Host:     testCase.command,
+			Path:     "tcp:localhost:8080",
+		}
+		if testCase.copyCommand != "" {
+			url.Parameters = map[string]string{urlpkg.ExecCopyParameter: testCase.copyCommand}
+		}
+		endpoint, err := handler.Connect(
+			context.Background(), logger, url, "", "session",
+			forwarding.Version_Version1, &forwarding.Configuration{}, false,
+		)
+		if err == nil {
+			endpoint.Shutdown()
+			t.Errorf("test index %d: connect succeeded unexpectedly", i)
+		} else if !strings.Contains(err.Error(), "unable to create exec transport") {
+			t.Errorf("test index %d: error does not describe transport creation failure: %v", i, err)
+		}
+	}
+}
+
+// TestConnectWrongProtocolPanics tests that Connect panics if dispatched a URL
+// with the wrong protocol.
+func TestConnectWrongProtocolPanics(t *testing.T) {
+	defer func() {
+		if recover() == nil {
+			t.Error("connect did not panic")
+		}
+	}()
+	url := &urlpkg.URL{
+		Kind:     urlpkg.Kind_Forwarding,
+		Protocol: urlpkg.Protocol_Docker,
+		Host:     "host",
+		Path:     "tcp:localhost:8080",
+	}
+	handler := &protocolHandler{}
+	handler.Connect(
+		context.Background(), logging.NewLogger(logging.LevelDisabled, io.Discard), url, "", "session",
+		forwarding.Version_Version1, &forwarding.Configuration{}, false,
+	)
+}
@@ -0,0 +1,3 @@
+// Package kubernetes provides the Kubernetes forwarding session protocol
+// implementation.
+package kubernetes
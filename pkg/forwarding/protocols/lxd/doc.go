@@ -0,0 +1,2 @@
+// Package lxd provides the LXD forwarding session protocol implementation.
+package lxd
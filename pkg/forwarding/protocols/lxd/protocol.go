@@ -0,0 +1,99 @@
+package lxd
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+
+	"github.com/mutagen-io/mutagen/pkg/agent"
+	"github.com/mutagen-io/mutagen/pkg/agent/transport/lxd"
+	"github.com/mutagen-io/mutagen/pkg/forwarding"
+	"github.com/mutagen-io/mutagen/pkg/forwarding/endpoint/remote"
+	"github.com/mutagen-io/mutagen/pkg/logging"
+	urlpkg "github.com/mutagen-io/mutagen/pkg/url"
+	forwardingurlpkg "github.com/mutagen-io/mutagen/pkg/url/forwarding"
+)
+
+// protocolHandler implements the forwarding.ProtocolHandler interface for
+// connecting to remote forwarding endpoints inside LXD instances. It uses the
+// agent infrastructure over an LXD transport.
+type protocolHandler struct{}
+
+// dialResult provides asynchronous agent dialing results.
+type dialResult struct {
+	// stream is the stream returned by agent dialing.
+	stream io.ReadWriteCloser
+	// error is the error returned by agent dialing.
+	error error
+}
+
+// Connect connects to an LXD endpoint.
+func (p *protocolHandler) Connect(
+	ctx context.Context,
+	logger *logging.Logger,
+	url *urlpkg.URL,
+	prompter string,
+	session string,
+	version forwarding.Version,
+	configuration *forwarding.Configuration,
+	source bool,
+) (forwarding.Endpoint, error) {
+	// Verify that the URL is of the correct kind and protocol.
+	if url.Kind != urlpkg.Kind_Forwarding {
+		panic("non-forwarding URL dispatched to forwarding protocol handler")
+	} else if url.Protocol != urlpkg.Protocol_LXD {
+		panic("non-LXD URL dispatched to LXD protocol handler")
+	}
+
+	// Parse the target specification from the URL's Path component.
+	protocol, address, err := forwardingurlpkg.Parse(url.Path)
+	if err != nil {
+		return nil, fmt.Errorf("unable to parse target specification: %w", err)
+	}
+
+	// Create an LXD agent transport.
+	transport, err := lxd.NewTransport(url.Host, url.User, url.Environment)
+	if err != nil {
+		return nil, fmt.Errorf("unable to create LXD transport: %w", err)
+	}
+
+	// Create a channel to deliver the dialing result.
+	results := make(chan dialResult)
+
+	// Perform dialing in a background Goroutine so that we can monitor for
+	// cancellation.
+	go func() {
+		// Perform the dialing operation.
+		stream, err := agent.DialShared(logger, url, transport, agent.CommandForwarder, 0, prompter, url.Parameters[urlpkg.AgentDirectoryParameter])
+
+		// Transmit the result or, if cancelled, close the stream.
+		select {
+		case results <- dialResult{stream, err}:
+		case <-ctx.Done():
+			if stream != nil {
+				stream.Close()
+			}
+		}
+	}()
+
+	// Wait for dialing results or cancellation.
+	var stream io.ReadWriteCloser
+	select {
+	case result := <-results:
+		if result.error != nil {
+			return nil, fmt.Errorf("unable to dial agent endpoint: %w", result.error)
+		}
+		stream = result.stream
+	case <-ctx.Done():
+		return nil, errors.New("connect operation cancelled")
+	}
+
+	// Create the endpoint.
+	return remote.NewEndpoint(logger, stream, version, configuration, protocol, address, source)
+}
+
+func init() {
+	// Register the LXD protocol handler with the forwarding package.
+	forwarding.ProtocolHandlers[urlpkg.Protocol_LXD] = &protocolHandler{}
+}
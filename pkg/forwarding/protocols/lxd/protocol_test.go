@@ -0,0 +1,161 @@
+package lxd
+
+import (
+	"context"
+	"io"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"testing"
+
+	"github.com/mutagen-io/mutagen/pkg/forwarding"
+	"github.com/mutagen-io/mutagen/pkg/logging"
+	urlpkg "github.com/mutagen-io/mutagen/pkg/url"
+)
+
+// fakeLXC is an LXD client stand-in that logs its arguments to the file
+// specified by the FAKE_LXC_LOG environment variable. It reports passwd entries
+// for the root and alice users, and agent invocations fail with the exit code
+// specified by the FAKE_LXC_EXIT environment variable. All other commands
+// succeed without output.
+const fakeLXC = `#!/bin/sh
+echo "$*" >> "$FAKE_LXC_LOG"
+case "$*" in
+*"-- getent passwd root")
+	echo "root:x:0:0:root:/root:/bin/bash"
+	;;
+*"-- getent passwd alice")
+	echo "alice:x:1000:1000:Alice:/home/alice:/bin/bash"
+	;;
+*mutagen-agent*)
+	exit "$FAKE_LXC_EXIT"
+	;;
+esac
+exit 0
+`
+
+// TestConnect tests the LXD client invocations performed by Connect for LXD
+// URLs and the handling of agent invocation failures.
+func TestConnect(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip()
+	}
+
+	// Install the fake LXD client.
+	directory := t.TempDir()
+	if err := os.WriteFile(filepath.Join(directory, "lxc"), []byte(fakeLXC), 0700); err != nil {
+		t.Fatal("unable to create fake LXD client:", err)
+	}
+	t.Setenv("MUTAGEN_LXD_PATH", directory)
+
+	// Set up test cases.
+	testCases := []struct {
+		// raw is the raw URL.
+		raw string
+		// exitCode is the exit code for agent invocations.
+		exitCode string
+		// expectedProbe is the expected user probing invocation.
+		expectedProbe string
+		// expectedPrefix is the expected prefix for agent and platform probing
+		// invocations.
+		expectedPrefix string
+		// expectInstall indicates whether or not an agent installation should
+		// be attempted.
+		expectInstall bool
+		// expectedError is a substring of the expected error.
+		expectedError string
+	}{
+		{
+			raw:            "lxd://instance-a:tcp:localhost:8080",
+			exitCode:       "1",
+			expectedProbe:  "exec instance-a --mode=non-interactive -- getent passwd root",
+			expectedPrefix: "exec instance-a --mode=non-interactive --cwd /root -- ",
+			expectedError:  "unable to handshake with agent process",
+		},
+		{
+			raw:            "lxd://alice@instance-b:tcp:localhost:8080",
+			exitCode:       "1",
+			expectedProbe:  "exec instance-b --mode=non-interactive -- getent passwd alice",
+			expectedPrefix: "exec instance-b --mode=non-interactive --user 1000 --group 1000 --env HOME=/home/alice --env USER=alice --cwd /home/alice -- ",
+			expectedError:  "unable to handshake with agent process",
+		},
+		{
+			raw:            "lxd://alice@instance-c:tcp:localhost:8080",
+			exitCode:       "127",
+			expectedProbe:  "exec instance-c --mode=non-interactive -- getent passwd alice",
+			expectedPrefix: "exec instance-c --mode=non-interactive --user 1000 --group 1000 --env HOME=/home/alice --env USER=alice --cwd /home/alice -- ",
+			expectInstall:  true,
+			expectedError:  "unable to install agent",
+		},
+	}
+
+	// Process test cases.
+	logger := logging.NewLogger(logging.LevelDisabled, io.Discard)
+	handler := &protocolHandler{}
+	for i, testCase := range testCases {
+		// Set up the fake LXD client behavior.
+		log := filepath.Join(t.TempDir(), "log")
+		t.Setenv("FAKE_LXC_LOG", log)
+		t.Setenv("FAKE_LXC_EXIT", testCase.exitCode)
+
+		// Parse the URL and attempt to connect.
+		url, err := urlpkg.Parse(testCase.raw, urlpkg.Kind_Forwarding, true)
+		if err != nil {
+			t.Fatalf("test index %d: unable to parse URL: %v", i, err)
+		}
+		endpoint, err := handler.Connect(
+			context.Background(), logger, url, "", "session",
+			forwarding.Version_Version1, &forwarding.Configuration{}, false,
+		)
+		if err == nil {
+			endpoint.Shutdown()
+			t.Fatalf("test index %d: connect succeeded unexpectedly", i)
+		} else if !strings.Contains(err.Error(), testCase.expectedError) {
+			t.Errorf("test index %d: error does not match expected: %v", i, err)
+		}
+
+		// Verify the LXD client invocations.
+		contents, err := os.ReadFile(log)
+		if err != nil {
+			t.Fatalf("test index %d: unable to read invocation log: %v", i, err)
+		}
+		invocations := strings.Split(strings.TrimSpace(string(contents)), "\n")
+		if len(invocations) < 2 {
+			t.Fatalf("test index %d: too few invocations: %v", i, invocations)
+		} else if invocations[0] != testCase.expectedProbe {
+			t.Errorf("test index %d: unexpected user probe: %s", i, invocations[0])
+		}
+		if !strings.HasPrefix(invocations[1], testCase.expectedPrefix) || !strings.Contains(invocations[1], " multiplexer ") {
+			t.Errorf("test index %d: unexpected agent invocation: %s", i, invocations[1])
+		}
+		if testCase.expectInstall {
+			if len(invocations) < 3 || invocations[2] != testCase.expectedPrefix+"uname -s -m" {
+				t.Errorf("test index %d: platform probe not performed: %v", i, invocations)
+			}
+		} else if len(invocations) != 2 {
+			t.Errorf("test index %d: unexpected additional invocations: %v", i, invocations[2:])
+		}
+	}
+}
+
+// TestConnectWrongProtocolPanics tests that Connect panics if dispatched a URL
+// with the wrong protocol.
+func TestConnectWrongProtocolPanics(t *testing.T) {
+	defer func() {
+		if recover() == nil {
+			t.Error("connect did not panic")
+		}
+	}()
+	url := &urlpkg.URL{
+		Kind:     urlpkg.Kind_Forwarding,
+		Protocol: urlpkg.Protocol_Docker,
+		Host:     "host",
+		Path:     "tcp:localhost:8080",
+	}
+	handler := &protocolHandler{}
+	handler.Connect(
+		context.Background(), logging.NewLogger(logging.LevelDisabled, io.Discard), url, "", "session",
+		forwarding.Version_Version1, &forwarding.Configuration{}, false,
+	)
+}
@@ -0,0 +1,4 @@
+// Package plugin provides the plugin forwarding session protocol
+// implementation, which connects to remote endpoints using external protocol
+// handler plugins.
+package plugin
@@ -0,0 +1,3 @@
+// Package rendezvous provides the rendezvous forwarding session protocol
+// implementation, which connects to agents that dial out to the daemon.
+package rendezvous
@@ -0,0 +1,92 @@
+package rendezvous
+
+import (
+	"context"
+	"io"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/mutagen-io/mutagen/pkg/forwarding"
+	"github.com/mutagen-io/mutagen/pkg/logging"
+	urlpkg "github.com/mutagen-io/mutagen/pkg/url"
+)
+
+// TestProtocolHandlerRegistration tests that the protocol handler is
+// registered for rendezvous URLs.
+func TestProtocolHandlerRegistration(t *testing.T) {
+	if _, ok := forwarding.ProtocolHandlers[urlpkg.Protocol_Rendezvous].(*protocolHandler); !ok {
+		t.Error("protocol handler not registered")
+	}
+}
+
+// TestConnectErrors tests that Connect fails with an invalid target, an invalid
+// pre-shared key, and if no agent connection arrives before cancellation.
+func TestConnectErrors(t *testing.T) {
+	// Create key files.
+	directory := t.TempDir()
+	keyFile := filepath.Join(directory, "key")
+	if err := os.WriteFile(keyFile, []byte("0123456789abcdef"), 0600); err != nil {
+		t.Fatal("unable to create key file:", err)
+	}
+	shortKeyFile := filepath.Join(directory, "short")
+	if err := os.WriteFile(shortKeyFile, []byte("short"), 0600); err != nil {
+		t.Fatal("unable to create key file:", err)
+	}
+
+	// Set up test cases.
+	testCases := []struct {
+		target  string
+		keyFile string
+	}{
+		{"invalid", keyFile},
+		{"tcp:localhost:8080", filepath.Join(directory, "missing")},
+		{"tcp:localhost:8080", shortKeyFile},
+		{"tcp:localhost:8080", keyFile},
+	}
+
+	// Process test cases.
+	logger := logging.NewLogger(logging.LevelDisabled, io.Discard)
+	handler := &protocolHandler{}
+	for i, testCase := range testCases {
+		url := &urlpkg.URL{
+			Kind:       urlpkg.Kind_Forwarding,
+			Protocol:   urlpkg.Protocol_Rendezvous,
+			Host:       "protocol-test-agent",
+			Path:       testCase.target,
+			Parameters: map[string]string{urlpkg.TCPKeyFileParameter: testCase.keyFile},
+		}
+		ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+		endpoint, err := handler.Connect(
+			ctx, logger, url, "", "session",
+			forwarding.Version_Version1, &forwarding.Configuration{}, false,
+		)
+		cancel()
+		if err == nil {
+			endpoint.Shutdown()
+			t.Errorf("test index %d: connect succeeded unexpectedly", i)
+		}
+	}
+}
+
+// TestConnectWrongProtocolPanics tests that Connect panics if dispatched a URL
+// with the wrong protocol.
+func TestConnectWrongProtocolPanics(t *testing.T) {
+	defer func() {
+		if recover() == nil {
+			t.Error("connect did not panic")
+		}
+	}()
+	url := &urlpkg.URL{
+		Kind:     urlpkg.Kind_Forwarding,
+		Protocol: urlpkg.Protocol_TCP,
+		Host:     "host",
+		Path:     "tcp:localhost:8080",
+	}
+	handler := &protocolHandler{}
+	handler.Connect(
+		context.Background(), logging.NewLogger(logging.LevelDisabled, io.Discard), url, "", "session",
+		forwarding.Version_Version1, &forwarding.Configuration{}, false,
+	)
+}
@@ -0,0 +1,3 @@
+// Package tcp provides the TCP forwarding session protocol implementation,
+// which connects to agents listening on a TCP port.
+package tcp
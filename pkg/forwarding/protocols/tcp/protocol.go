@@ -0,0 +1,66 @@
+package tcp
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"strconv"
+
+	"github.com/mutagen-io/mutagen/pkg/agent"
+	"github.com/mutagen-io/mutagen/pkg/forwarding"
+	"github.com/mutagen-io/mutagen/pkg/forwarding/endpoint/remote"
+	"github.com/mutagen-io/mutagen/pkg/logging"
+	urlpkg "github.com/mutagen-io/mutagen/pkg/url"
+	forwardingurlpkg "github.com/mutagen-io/mutagen/pkg/url/forwarding"
+)
+
+// protocolHandler implements the forwarding.ProtocolHandler interface for
+// connecting to remote forwarding endpoints served by agents listening on a TCP
+// port.
+type protocolHandler struct{}
+
+// Connect connects to a TCP endpoint.
+func (p *protocolHandler) Connect(
+	ctx context.Context,
+	logger *logging.Logger,
+	url *urlpkg.URL,
+	prompter string,
+	session string,
+	version forwarding.Version,
+	configuration *forwarding.Configuration,
+	source bool,
+) (forwarding.Endpoint, error) {
+	// Verify that the URL is of the correct kind and protocol.
+	if url.Kind != urlpkg.Kind_Forwarding {
+		panic("non-forwarding URL dispatched to forwarding protocol handler")
+	} else if url.Protocol != urlpkg.Protocol_TCP {
+		panic("non-TCP URL dispatched to TCP protocol handler")
+	}
+
+	// Parse the target specification from the URL's Path component.
+	protocol, address, err := forwardingurlpkg.Parse(url.Path)
+	if err != nil {
+		return nil, fmt.Errorf("unable to parse target specification: %w", err)
+	}
+
+	// Load the pre-shared key.
+	key, err := agent.LoadTCPKey(url.Parameters[urlpkg.TCPKeyFileParameter])
+	if err != nil {
+		return nil, fmt.Errorf("unable to load pre-shared key: %w", err)
+	}
+
+	// Dial the agent.
+	agentAddress := net.JoinHostPort(url.Host, strconv.FormatUint(uint64(url.Port), 10))
+	stream, err := agent.DialTCP(ctx, agentAddress, key, agent.CommandForwarder)
+	if err != nil {
+		return nil, fmt.Errorf("unable to dial agent endpoint: %w", err)
+	}
+
+	// Create the endpoint.
+	return remote.NewEndpoint(logger, stream, version, configuration, protocol, address, source)
+}
+
+func init() {
+	// Register the TCP protocol handler with the forwarding package.
+	forwarding.ProtocolHandlers[urlpkg.Protocol_TCP] = &protocolHandler{}
+}
@@ -0,0 +1,121 @@
+package tcp
+
+import (
+	"context"
+	"io"
+	"net"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/mutagen-io/mutagen/pkg/agent"
+	"github.com/mutagen-io/mutagen/pkg/forwarding"
+	"github.com/mutagen-io/mutagen/pkg/logging"
+	urlpkg "github.com/mutagen-io/mutagen/pkg/url"
+)
+
+// TestProtocolHandlerRegistration tests that the protocol handler is
+// registered for TCP URLs.
+func TestProtocolHandlerRegistration(t *testing.T) {
+	if _, ok := forwarding.ProtocolHandlers[urlpkg.Protocol_TCP].(*protocolHandler); !ok {
+		t.Error("protocol handler not registered")
+	}
+}
+
+// TestConnectErrors tests that Connect fails with an invalid target, an invalid
+// pre-shared key, an unreachable agent, and an agent using a different
+// pre-shared key.
+func TestConnectErrors(t *testing.T) {
+	// Create key files.
+	directory := t.TempDir()
+	keyFile := filepath.Join(directory, "key")
+	if err := os.WriteFile(keyFile, []byte("0123456789abcdef"), 0600); err != nil {
+		t.Fatal("unable to create key file:", err)
+	}
+	shortKeyFile := filepath.Join(directory, "short")
+	if err := os.WriteFile(shortKeyFile, []byte("short"), 0600); err != nil {
+		t.Fatal("unable to create key file:", err)
+	}
+
+	// Create an agent listener that uses a different key.
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal("unable to create listener:", err)
+	}
+	defer listener.Close()
+	go func() {
+		for {
+			connection, err := listener.Accept()
+			if err != nil {
+				return
+			}
+			agent.TCPServerAuthenticate(connection, []byte("fedcba9876543210"))
+			connection.Close()
+		}
+	}()
+	port := uint32(listener.Addr().(*net.TCPAddr).Port)
+
+	// Find a port with no listener.
+	unused, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal("unable to create listener:", err)
+	}
+	unusedPort := uint32(unused.Addr().(*net.TCPAddr).Port)
+	unused.Close()
+
+	// Set up test cases.
+	testCases := []struct {
+		port    uint32
+		target  string
+		keyFile string
+	}{
+		{port, "invalid", keyFile},
+		{port, "tcp:localhost:8080", filepath.Join(directory, "missing")},
+		{port, "tcp:localhost:8080", shortKeyFile},
+		{unusedPort, "tcp:localhost:8080", keyFile},
+		{port, "tcp:localhost:8080", keyFile},
+	}
+
+	// Process test cases.
+	logger := logging.NewLogger(logging.LevelDisabled, io.Discard)
+	handler := &protocolHandler{}
+	for i, testCase := range testCases {
+		url := &urlpkg.URL{
+			Kind:       urlpkg.Kind_Forwarding,
+			Protocol:   urlpkg.Protocol_TCP,
+			Host:       "127.0.0.1",
+			Port:       testCase.port,
+			Path:       testCase.target,
+			Parameters: map[string]string{urlpkg.TCPKeyFileParameter: testCase.keyFile},
+		}
+		endpoint, err := handler.Connect(
+			context.Background(), logger, url, "", "session",
+			forwarding.Version_Version1, &forwarding.Configuration{}, false,
+		)
+		if err == nil {
+			endpoint.Shutdown()
+			t.Errorf("test index %d: connect succeeded unexpectedly", i)
+		}
+	}
+}
+
+// TestConnectWrongProtocolPanics tests that Connect panics if dispatched a URL
+// with the wrong protocol.
+func TestConnectWrongProtocolPanics(t *testing.T) {
+	defer func() {
+		if recover() == nil {
+			t.Error("connect did not panic")
+		}
+	}()
+	url := &urlpkg.URL{
+		Kind:     urlpkg.Kind_Forwarding,
+		Protocol: urlpkg.Protocol_SSH,
+		Host:     "host",
+		Path:     "tcp:localhost:8080",
+	}
+	handler := &protocolHandler{}
+	handler.Connect(
+		context.Background(), logging.NewLogger(logging.LevelDisabled, io.Discard), url, "", "session",
+		forwarding.Version_Version1, &forwarding.Configuration{}, false,
+	)
+}
@@ -0,0 +1,3 @@
+// Package teleport provides the Teleport node forwarding session protocol
+// implementation.
+package teleport
@@ -0,0 +1,188 @@
+package forwarding
+
+import (
+	"crypto/tls"
+	"net"
+	"sync"
+	"time"
+
+	"github.com/mutagen-io/mutagen/pkg/forwarding/proxyprotocol"
+)
+
+const (
+	// proxyProtocolHeaderTimeout is the maximum amount of time that clients
+	// are given to send a PROXY protocol header.
+	proxyProtocolHeaderTimeout = 5 * time.Second
+)
+
+// proxyProtocolConn wraps a connection and reports the addresses conveyed by
+// its PROXY protocol header.
+type proxyProtocolConn struct {
+	net.Conn
+	// remoteAddress is the original client address.
+	remoteAddress net.Addr
+	// localAddress is the original server address.
+	localAddress net.Addr
+}
+
+// RemoteAddr implements net.Conn.RemoteAddr.
+func (c *proxyProtocolConn) RemoteAddr() net.Addr {
+	return c.remoteAddress
+}
+
+// LocalAddr implements net.Conn.LocalAddr.
+func (c *proxyProtocolConn) LocalAddr() net.Addr {
+	return c.localAddress
+}
+
+// consumeProxyProtocolHeader reads a PROXY protocol header from a connection.
+// If the header conveys addresses, then the returned connection reports them
+// as its remote and local addresses. On failure, the connection is closed.
+func consumeProxyProtocolHeader(connection net.Conn) (net.Conn, error) {
+	// Read the header within the allowed time.
+	connection.SetReadDeadline(time.Now().Add(proxyProtocolHeaderTimeout))
+	remote, local, err := proxyprotocol.ReadHeader(connection)
+	if err != nil {
+		connection.Close()
+		return nil, err
+	}
+	connection.SetReadDeadline(time.Time{})
+
+	// If the header doesn't convey addresses, then use the connection as-is.
+	if remote == nil || local == nil {
+		return connection, nil
+	}
+
+	// Wrap the connection.
+	return &proxyProtocolConn{connection, remote, local}, nil
+}
+
+// proxyProtocolConnection is a connection whose PROXY protocol header has been
+// consumed, along with the index of the listener that accepted it.
+type proxyProtocolConnection struct {
+	// connection is the connection.
+	connection net.Conn
+	// index is the index of the accepting listener.
+	index int
+}
+
+// proxyProtocolAcceptor accepts connections from an underlying endpoint and
+// consumes their PROXY protocol headers. Each header is read in a Goroutine
+// dedicated to its connection, so a client that's slow to send its header
+// doesn't block the acceptance of other connections.
+type proxyProtocolAcceptor struct {
+	// open opens the next connection from the underlying endpoint.
+	open func() (net.Conn, int, error)
+	// startOnce ensures that the accept loop is only started once.
+	startOnce sync.Once
+	// connections delivers connections whose headers have been consumed.
+	connections chan proxyProtocolConnection
+	// done is closed when the accept loop terminates.
+	done chan struct{}
+	// err is the error that terminated the accept loop. It may only be read
+	// after done is closed.
+	err error
+}
+
+// newProxyProtocolAcceptor creates a new PROXY protocol acceptor that opens
+// connections using the specified function.
+func newProxyProtocolAcceptor(open func() (net.Conn, int, error)) *proxyProtocolAcceptor {
+	return &proxyProtocolAcceptor{
+		open:        open,
+		connections: make(chan proxyProtocolConnection),
+		done:        make(chan struct{}),
+	}
+}
+
+// run is the accept loop for the acceptor.
+func (a *proxyProtocolAcceptor) run() {
+	for {
+		// Accept the next connection.
+		connection, index, err := a.open()
+		if err != nil {
+			a.err = err
+			close(a.done)
+			return
+		}
+
+		// Consume the header and deliver the connection in the background.
+		// Connections with invalid headers are closed and skipped.
+		go func() {
+			connection, err := consumeProxyProtocolHeader(connection)
+			if err != nil {
+				return
+			}
+			select {
+			case a.connections <- proxyProtocolConnection{connection, index}:
+			case <-a.done:
+				connection.Close()
+			}
+		}()
+	}
+}
+
+// accept returns the next connection whose header has been consumed.
+func (a *proxyProtocolAcceptor) accept() (net.Conn, int, error) {
+	a.startOnce.Do(func() { go a.run() })
+	select {
+	case c := <-a.connections:
+		return c.connection, c.index, nil
+	case <-a.done:
+		return nil, 0, a.err
+	}
+}
+
+// proxyProtocolServerEndpoint wraps a source endpoint to consume PROXY protocol
+// headers from incoming connections. Connections with invalid headers are
+// closed and skipped.
+type proxyProtocolServerEndpoint struct {
+	Endpoint
+	// acceptor is the underlying acceptor.
+	acceptor *proxyProtocolAcceptor
+}
+
+// Open implements Endpoint.Open.
+func (e *proxyProtocolServerEndpoint) Open() (net.Conn, error) {
+	connection, _, err := e.acceptor.accept()
+	return connection, err
+}
+
+// proxyProtocolIndexedServerEndpoint wraps an indexed source endpoint to
+// consume PROXY protocol headers from incoming connections. Connections with
+// invalid headers are closed and skipped.
+type proxyProtocolIndexedServerEndpoint struct {
+	IndexedEndpoint
+	// acceptor is the underlying acceptor.
+	acceptor *proxyProtocolAcceptor
+}
+
+// OpenIndexed implements IndexedEndpoint.OpenIndexed.
+func (e *proxyProtocolIndexedServerEndpoint) OpenIndexed() (net.Conn, int, error) {
+	return e.acceptor.accept()
+}
+
+// wrapSourceWithProxyProtocol wraps a source endpoint to consume PROXY protocol
+// headers if its configuration requires it. It must be applied before any TLS
+// wrapping since headers precede the TLS handshake.
+func wrapSourceWithProxyProtocol(source Endpoint, configuration *Configuration) Endpoint {
+	if !configuration.ProxyProtocolMode.Enabled() {
+		return source
+	} else if indexed, ok := source.(IndexedEndpoint); ok {
+		return &proxyProtocolIndexedServerEndpoint{indexed, newProxyProtocolAcceptor(indexed.OpenIndexed)}
+	}
+	return &proxyProtocolServerEndpoint{source, newProxyProtocolAcceptor(func() (net.Conn, int, error) {
+		connection, err := source.Open()
+		return connection, 0, err
+	})}
+}
+
+// emitProxyProtocolHeader writes a PROXY protocol header conveying the
+// addresses of an incoming connection to an outgoing connection. If the
+// outgoing connection originates TLS, then the header is written to the
+// underlying connection, ahead of the TLS handshake.
+func emitProxyProtocolHeader(outgoing, incoming net.Conn) error {
+	if connection, ok := outgoing.(*tls.Conn); ok {
+		outgoing = connection.NetConn()
+	}
+	return proxyprotocol.WriteHeader(outgoing, incoming.RemoteAddr(), incoming.LocalAddr())
+}
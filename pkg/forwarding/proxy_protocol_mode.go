@@ -0,0 +1,78 @@
+package forwarding
+
+import (
+	"fmt"
+)
+
+// IsDefault indicates whether or not the PROXY protocol mode is
+// ProxyProtocolMode_ProxyProtocolModeDefault.
+func (m ProxyProtocolMode) IsDefault() bool {
+	return m == ProxyProtocolMode_ProxyProtocolModeDefault
+}
+
+// Enabled indicates whether or not the PROXY protocol mode is
+// ProxyProtocolMode_ProxyProtocolModeEnabled.
+func (m ProxyProtocolMode) Enabled() bool {
+	return m == ProxyProtocolMode_ProxyProtocolModeEnabled
+}
+
+// MarshalText implements encoding.TextMarshaler.MarshalText.
+func (m ProxyProtocolMode) MarshalText() ([]byte, error) {
+	var result string
+	switch m {
+	case ProxyProtocolMode_ProxyProtocolModeDefault:
+	case ProxyProtocolMode_ProxyProtocolModeDisabled:
+		result = "disabled"
+	case ProxyProtocolMode_ProxyProtocolModeEnabled:
+		result = "enabled"
+	default:
+		result = "unknown"
+	}
+	return []byte(result), nil
+}
+
+// UnmarshalText implements encoding.TextUnmarshaler.UnmarshalText.
+func (m *ProxyProtocolMode) UnmarshalText(textBytes []byte) error {
+	// Convert the bytes to a string.
+	text := string(textBytes)
+
+	// Convert to a PROXY protocol mode.
+	switch text {
+	case "disabled":
+		*m = ProxyProtocolMode_ProxyProtocolModeDisabled
+	case "enabled":
+		*m = ProxyProtocolMode_ProxyProtocolModeEnabled
+	default:
+		return fmt.Errorf("unknown PROXY protocol mode specification: %s", text)
+	}
+
+	// Success.
+	return nil
+}
+
+// Supported indicates whether or not a particular PROXY protocol mode is a valid,
+// non-default value.
+func (m ProxyProtocolMode) Supported() bool {
+	switch m {
+	case ProxyProtocolMode_ProxyProtocolModeDisabled:
+		return true
+	case ProxyProtocolMode_ProxyProtocolModeEnabled:
+		return true
+	default:
+		return false
+	}
+}
+
+// Description returns a human-readable description of a PROXY protocol mode.
+func (m ProxyProtocolMode) Description() string {
+	switch m {
+	case ProxyProtocolMode_ProxyProtocolModeDefault:
+		return "Default"
+	case ProxyProtocolMode_ProxyProtocolModeDisabled:
+		return "Disabled"
+	case ProxyProtocolMode_ProxyProtocolModeEnabled:
+		return "Enabled"
+	default:
+		return "Unknown"
+	}
+}
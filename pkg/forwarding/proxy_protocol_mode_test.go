@@ -0,0 +1,91 @@
+package forwarding
+
+import (
+	"testing"
+)
+
+// TestProxyProtocolModeUnmarshal tests that unmarshaling from a string
+// specification succeeds for ProxyProtocolMode.
+func TestProxyProtocolModeUnmarshal(t *testing.T) {
+	// Set up test cases.
+	testCases := []struct {
+		text          string
+		expectedMode  ProxyProtocolMode
+		expectFailure bool
+	}{
+		{"", ProxyProtocolMode_ProxyProtocolModeDefault, true},
+		{"asdf", ProxyProtocolMode_ProxyProtocolModeDefault, true},
+		{"disabled", ProxyProtocolMode_ProxyProtocolModeDisabled, false},
+		{"enabled", ProxyProtocolMode_ProxyProtocolModeEnabled, false},
+	}
+
+	// Process test cases.
+	for _, testCase := range testCases {
+		var mode ProxyProtocolMode
+		if err := mode.UnmarshalText([]byte(testCase.text)); err != nil {
+			if !testCase.expectFailure {
+				t.Errorf("unable to unmarshal text (%s): %s", testCase.text, err)
+			}
+		} else if testCase.expectFailure {
+			t.Error("unmarshaling succeeded unexpectedly for text:", testCase.text)
+		} else if mode != testCase.expectedMode {
+			t.Errorf(
+				"unmarshaled mode (%s) does not match expected (%s)",
+				mode,
+				testCase.expectedMode,
+			)
+		}
+	}
+}
+
+// TestProxyProtocolModeSupported tests that ProxyProtocolMode support
+// detection works as expected.
+func TestProxyProtocolModeSupported(t *testing.T) {
+	// Set up test cases.
+	testCases := []struct {
+		mode            ProxyProtocolMode
+		expectSupported bool
+	}{
+		{ProxyProtocolMode_ProxyProtocolModeDefault, false},
+		{ProxyProtocolMode_ProxyProtocolModeDisabled, true},
+		{ProxyProtocolMode_ProxyProtocolModeEnabled, true},
+		{(ProxyProtocolMode_ProxyProtocolModeEnabled + 1), false},
+	}
+
+	// Process test cases.
+	for _, testCase := range testCases {
+		if supported := testCase.mode.Supported(); supported != testCase.expectSupported {
+			t.Errorf(
+				"mode support status (%t) does not match expected (%t)",
+				supported,
+				testCase.expectSupported,
+			)
+		}
+	}
+}
+
+// TestProxyProtocolModeDescription tests that ProxyProtocolMode description
+// generation works as expected.
+func TestProxyProtocolModeDescription(t *testing.T) {
+	// Set up test cases.
+	testCases := []struct {
+		mode                ProxyProtocolMode
+		expectedDescription string
+	}{
+		{ProxyProtocolMode_ProxyProtocolModeDefault, "Default"},
+		{ProxyProtocolMode_ProxyProtocolModeDisabled, "Disabled"},
+		{ProxyProtocolMode_ProxyProtocolModeEnabled, "Enabled"},
+		{(ProxyProtocolMode_ProxyProtocolModeEnabled + 1), "Unknown"},
+	}
+
+	// Process test cases.
+	for _, testCase := range testCases {
+		if description := testCase.mode.Description(); description != testCase.expectedDescription {
+			t.Errorf(
+				"mode description (%s) does not match expected (%s)",
+				description,
+				testCase.expectedDescription,
+			)
+		}
+	}
+}
@@ -0,0 +1,101 @@
+package forwarding
+
+import (
+	"net"
+	"testing"
+	"time"
+
+	"github.com/mutagen-io/mutagen/pkg/forwarding/proxyprotocol"
+)
+
+// TestProxyProtocolServerEndpoint tests that proxyProtocolServerEndpoint
+// consumes PROXY protocol headers, skips connections with invalid headers, and
+// isn't blocked by clients that are slow to send headers.
+func TestProxyProtocolServerEndpoint(t *testing.T) {
+	// Create a listener and wrap it to consume PROXY protocol headers.
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal("unable to create listener:", err)
+	}
+	source := wrapSourceWithProxyProtocol(
+		&testListenerEndpoint{listener},
+		&Configuration{ProxyProtocolMode: ProxyProtocolMode_ProxyProtocolModeEnabled},
+	)
+	defer source.Shutdown()
+
+	// Connect a client that doesn't send a header, followed by a client that
+	// sends an invalid header, followed by a client that sends a valid header.
+	client := &net.TCPAddr{IP: net.IPv4(192, 0, 2, 1), Port: 51234}
+	server := &net.TCPAddr{IP: net.IPv4(192, 0, 2, 2), Port: 80}
+	stalled, err := net.Dial("tcp", listener.Addr().String())
+	if err != nil {
+		t.Fatal("unable to connect stalled client:", err)
+	}
+	defer stalled.Close()
+	go func() {
+		if invalid, err := net.Dial("tcp", listener.Addr().String()); err == nil {
+			invalid.Write([]byte("GET / HTTP/1.1\r\nHost: localhost\r\n\r\n"))
+			defer invalid.Close()
+		}
+		if valid, err := net.Dial("tcp", listener.Addr().String()); err == nil {
+			proxyprotocol.WriteHeader(valid, client, server)
+			valid.Write([]byte("hello"))
+			defer valid.Close()
+		}
+	}()
+
+	// Accept a connection and verify that it's the valid client, which must
+	// arrive well before the stalled client's header timeout.
+	start := time.Now()
+	connection, err := source.Open()
+	if err != nil {
+		t.Fatal("unable to accept connection:", err)
+	}
+	defer connection.Close()
+	if time.Since(start) >= proxyProtocolHeaderTimeout {
+		t.Error("acceptance blocked by stalled client")
+	}
+	if connection.RemoteAddr().String() != client.String() {
+		t.Error("remote address mismatch:", connection.RemoteAddr(), "!=", client)
+	}
+	if connection.LocalAddr().String() != server.String() {
+		t.Error("local address mismatch:", connection.LocalAddr(), "!=", server)
+	}
+	buffer := make([]byte, 5)
+	if _, err := connection.Read(buffer); err != nil {
+		t.Fatal("unable to read payload:", err)
+	} else if string(buffer) != "hello" {
+		t.Error("payload mismatch:", string(buffer))
+	}
+}
+
+// TestEmitProxyProtocolHeader tests emitProxyProtocolHeader.
+func TestEmitProxyProtocolHeader(t *testing.T) {
+	// Create a connection pair to act as the outgoing connection.
+	outgoing, target := net.Pipe()
+	defer outgoing.Close()
+	defer target.Close()
+
+	// Create an incoming connection with known addresses.
+	client := &net.TCPAddr{IP: net.ParseIP("2001:db8::1"), Port: 51234}
+	server := &net.TCPAddr{IP: net.ParseIP("2001:db8::2"), Port: 443}
+	incoming := &proxyProtocolConn{nil, client, server}
+
+	// Emit the header and verify that the target receives it.
+	emitted := make(chan error, 1)
+	go func() {
+		emitted <- emitProxyProtocolHeader(outgoing, incoming)
+	}()
+	remote, local, err := proxyprotocol.ReadHeader(target)
+	if err != nil {
+		t.Fatal("unable to read header:", err)
+	} else if err := <-emitted; err != nil {
+		t.Fatal("unable to emit header:", err)
+	}
+	if remote.String() != client.String() {
+		t.Error("source address mismatch:", remote, "!=", client)
+	}
+	if local.String() != server.String() {
+		t.Error("destination address mismatch:", local, "!=", server)
+	}
+}
@@ -0,0 +1,5 @@
+// Package proxyprotocol provides encoding and decoding of version 2 PROXY
+// protocol headers, which convey the original client and server addresses of a
+// proxied TCP connection to the receiving service. Only the address portion of
+// the header is supported, and any type-length-value extensions are ignored.
+package proxyprotocol
@@ -0,0 +1,131 @@
+package proxyprotocol
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+)
+
+const (
+	// versionCommandLocal is the version and command byte for LOCAL headers,
+	// which indicate that the connection wasn't proxied on behalf of a client.
+	versionCommandLocal = 0x20
+	// versionCommandProxy is the version and command byte for PROXY headers.
+	versionCommandProxy = 0x21
+
+	// familyUnspecified is the unspecified address family and protocol.
+	familyUnspecified = 0x00
+	// familyTCPv4 is the TCP over IPv4 address family and protocol.
+	familyTCPv4 = 0x11
+	// familyTCPv6 is the TCP over IPv6 address family and protocol.
+	familyTCPv6 = 0x21
+
+	// addressLengthIPv4 is the length of the IPv4 address block.
+	addressLengthIPv4 = 12
+	// addressLengthIPv6 is the length of the IPv6 address block.
+	addressLengthIPv6 = 36
+)
+
+// signature is the signature that begins every version 2 header.
+var signature = []byte{0x0D, 0x0A, 0x0D, 0x0A, 0x00, 0x0D, 0x0A, 0x51, 0x55, 0x49, 0x54, 0x0A}
+
+// WriteHeader writes a version 2 PROXY protocol header to the specified writer.
+// If both addresses are TCP addresses of the same IP version, then a PROXY
+// header conveying them is written, otherwise a LOCAL header is written.
+func WriteHeader(writer io.Writer, source, destination net.Addr) error {
+	// Compute the header contents.
+	versionCommand := byte(versionCommandLocal)
+	family := byte(familyUnspecified)
+	var addresses []byte
+	sourceTCP, sourceOK := source.(*net.TCPAddr)
+	destinationTCP, destinationOK := destination.(*net.TCPAddr)
+	if sourceOK && destinationOK {
+		if source4, destination4 := sourceTCP.IP.To4(), destinationTCP.IP.To4(); source4 != nil && destination4 != nil {
+			versionCommand, family = versionCommandProxy, familyTCPv4
+			addresses = append(addresses, source4...)
+			addresses = append(addresses, destination4...)
+		} else if source4 == nil && destination4 == nil && sourceTCP.IP.To16() != nil && destinationTCP.IP.To16() != nil {
+			versionCommand, family = versionCommandProxy, familyTCPv6
+			addresses = append(addresses, sourceTCP.IP.To16()...)
+			addresses = append(addresses, destinationTCP.IP.To16()...)
+		}
+		if family != familyUnspecified {
+			addresses = append(addresses, byte(sourceTCP.Port>>8), byte(sourceTCP.Port))
+			addresses = append(addresses, byte(destinationTCP.Port>>8), byte(destinationTCP.Port))
+		}
+	}
+
+	// Encode the header.
+	header := make([]byte, 0, len(signature)+4+len(addresses))
+	header = append(header, signature...)
+	header = append(header, versionCommand, family)
+	header = append(header, byte(len(addresses)>>8), byte(len(addresses)))
+	header = append(header, addresses...)
+
+	// Write the header.
+	_, err := writer.Write(header)
+	return err
+}
+
+// ReadHeader reads a version 2 PROXY protocol header from the specified reader.
+// It returns the source and destination addresses conveyed by the header. If
+// the header is a LOCAL header or conveys addresses of an unsupported family,
+// then nil addresses are returned. The reader should be unbuffered, since this
+// function reads exactly the number of bytes in the header.
+func ReadHeader(reader io.Reader) (net.Addr, net.Addr, error) {
+	// Read and validate the fixed portion of the header.
+	var fixed [16]byte
+	if _, err := io.ReadFull(reader, fixed[:]); err != nil {
+		return nil, nil, fmt.Errorf("unable to read header: %w", err)
+	} else if !bytes.Equal(fixed[:12], signature) {
+		return nil, nil, errors.New("invalid header signature")
+	} else if fixed[12]>>4 != 2 {
+		return nil, nil, fmt.Errorf("unsupported header version: %d", fixed[12]>>4)
+	}
+	command, family := fixed[12]&0x0F, fixed[13]
+
+	// Read the variable portion of the header.
+	variable := make([]byte, binary.BigEndian.Uint16(fixed[14:]))
+	if _, err := io.ReadFull(reader, variable); err != nil {
+		return nil, nil, fmt.Errorf("unable to read header addresses: %w", err)
+	}
+
+	// Handle LOCAL headers.
+	if command == versionCommandLocal&0x0F {
+		return nil, nil, nil
+	} else if command != versionCommandProxy&0x0F {
+		return nil, nil, fmt.Errorf("unsupported header command: %d", command)
+	}
+
+	// Decode addresses.
+	var ipLength int
+	switch family {
+	case familyTCPv4:
+		if len(variable) < addressLengthIPv4 {
+			return nil, nil, errors.New("truncated IPv4 addresses")
+		}
+		ipLength = net.IPv4len
+	case familyTCPv6:
+		if len(variable) < addressLengthIPv6 {
+			return nil, nil, errors.New("truncated IPv6 addresses")
+		}
+		ipLength = net.IPv6len
+	default:
+		return nil, nil, nil
+	}
+	ports := variable[2*ipLength:]
+	source := &net.TCPAddr{
+		IP:   net.IP(append([]byte(nil), variable[:ipLength]...)),
+		Port: int(binary.BigEndian.Uint16(ports[0:2])),
+	}
+	destination := &net.TCPAddr{
+		IP:   net.IP(append([]byte(nil), variable[ipLength:2*ipLength]...)),
+		Port: int(binary.BigEndian.Uint16(ports[2:4])),
+	}
+
+	// Success.
+	return source, destination, nil
+}
@@ -0,0 +1,94 @@
+package proxyprotocol
+
+import (
+	"bytes"
+	"net"
+	"testing"
+)
+
+// TestHeaderRoundTrip tests that headers written by WriteHeader are correctly
+// decoded by ReadHeader.
+func TestHeaderRoundTrip(t *testing.T) {
+	// Set up test cases.
+	testCases := []struct {
+		source      net.Addr
+		destination net.Addr
+		expectLocal bool
+	}{
+		{
+			&net.TCPAddr{IP: net.IPv4(192, 168, 1, 10), Port: 51234},
+			&net.TCPAddr{IP: net.IPv4(127, 0, 0, 1), Port: 8080},
+			false,
+		},
+		{
+			&net.TCPAddr{IP: net.ParseIP("2001:db8::1"), Port: 443},
+			&net.TCPAddr{IP: net.IPv6loopback, Port: 8443},
+			false,
+		},
+		{
+			&net.TCPAddr{IP: net.IPv4(192, 168, 1, 10), Port: 51234},
+			&net.TCPAddr{IP: net.IPv6loopback, Port: 8443},
+			true,
+		},
+		{
+			&net.UnixAddr{Name: "/tmp/client.sock", Net: "unix"},
+			&net.UnixAddr{Name: "/tmp/server.sock", Net: "unix"},
+			true,
+		},
+	}
+
+	// Process test cases.
+	for _, testCase := range testCases {
+		// Write the header, followed by some payload data.
+		buffer := &bytes.Buffer{}
+		if err := WriteHeader(buffer, testCase.source, testCase.destination); err != nil {
+			t.Fatal("unable to write header:", err)
+		}
+		buffer.WriteString("payload")
+
+		// Read the header.
+		source, destination, err := ReadHeader(buffer)
+		if err != nil {
+			t.Errorf("unable to read header for %v -> %v: %v", testCase.source, testCase.destination, err)
+			continue
+		}
+
+		// Verify the addresses.
+		if testCase.expectLocal {
+			if source != nil || destination != nil {
+				t.Errorf("addresses decoded from LOCAL header: %v -> %v", source, destination)
+			}
+		} else {
+			if source == nil || source.String() != testCase.source.String() {
+				t.Errorf("source address mismatch: %v != %v", source, testCase.source)
+			}
+			if destination == nil || destination.String() != testCase.destination.String() {
+				t.Errorf("destination address mismatch: %v != %v", destination, testCase.destination)
+			}
+		}
+
+		// Verify that the payload is left unconsumed.
+		if buffer.String() != "payload" {
+			t.Error("payload consumed by header reading:", buffer.String())
+		}
+	}
+}
+
+// TestReadHeaderInvalid tests that ReadHeader rejects invalid headers.
+func TestReadHeaderInvalid(t *testing.T) {
+	// Set up test cases.
+	testCases := [][]byte{
+		nil,
+		[]byte("PROXY TCP4 192.168.1.10 127.0.0.1 51234 8080\r\n"),
+		append(append([]byte(nil), signature...), 0x11, 0x11, 0x00, 0x00),
+		append(append([]byte(nil), signature...), 0x21, 0x11, 0x00, 0x04, 0x01, 0x02, 0x03, 0x04),
+		append(append([]byte(nil), signature...), 0x21, 0x11, 0x00, 0x0C, 0x01),
+	}
+
+	// Process test cases.
+	for _, testCase := range testCases {
+		if _, _, err := ReadHeader(bytes.NewReader(testCase)); err == nil {
+			t.Errorf("invalid header (%x) read successfully", testCase)
+		}
+	}
+}
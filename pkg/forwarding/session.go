@@ -7,8 +7,73 @@ import (
 	"github.com/mutagen-io/mutagen/pkg/identifier"
 	"github.com/mutagen-io/mutagen/pkg/selection"
 	"github.com/mutagen-io/mutagen/pkg/url"
+	forwardingurl "github.com/mutagen-io/mutagen/pkg/url/forwarding"
 )
 
+// portRange returns the port range specified by a forwarding endpoint's
+// protocol and address, or nil if the endpoint doesn't specify a port range.
+// The protocol and address must have been validated by forwardingurl.Parse.
+func portRange(protocol, address string) *forwardingurl.PortRange {
+	if !forwardingurl.IsPortRangeProtocol(protocol) {
+		return nil
+	}
+	result, _ := forwardingurl.ParsePortRange(address)
+	return result
+}
+
+// EnsureEndpointsCompatible ensures that the specified source and destination
+// forwarding URLs can be used together with the specified session-level
+// configuration. Proxy sources (SOCKS5 and HTTP) must be paired with
+// destinations using the same proxy protocol (which dial the targets requested
+// by clients or selected by routing) and vice versa. HTTP sessions must specify
+// at least one HTTP route, and other sessions must not specify any. Port range
+// endpoints must be paired with port range endpoints of the same size, and
+// only sources may use ephemeral ports. Both URLs must be valid forwarding URLs
+// and the configuration must be valid.
+func EnsureEndpointsCompatible(source, destination *url.URL, configuration *Configuration) error {
+	// Parse the endpoint protocols.
+	sourceProtocol, sourceAddress, err := forwardingurl.Parse(source.Path)
+	if err != nil {
+		return fmt.Errorf("unable to parse source endpoint: %w", err)
+	}
+	destinationProtocol, destinationAddress, err := forwardingurl.Parse(destination.Path)
+	if err != nil {
+		return fmt.Errorf("unable to parse destination endpoint: %w", err)
+	}
+
+	// Ensure that proxy endpoints are correctly paired.
+	if forwardingurl.IsProxyProtocol(sourceProtocol) && destinationProtocol != sourceProtocol {
+		return fmt.Errorf("%s source requires %s destination", sourceProtocol, sourceProtocol)
+	} else if forwardingurl.IsProxyProtocol(destinationProtocol) && sourceProtocol != destinationProtocol {
+		return fmt.Errorf("%s destination requires %s source", destinationProtocol, destinationProtocol)
+	}
+
+	// Ensure that HTTP routes are specified if and only if required.
+	if sourceProtocol == "http" && len(configuration.HttpRoutes) == 0 {
+		return errors.New("HTTP sessions require at least one HTTP route")
+	} else if sourceProtocol != "http" && len(configuration.HttpRoutes) > 0 {
+		return errors.New("HTTP routes can only be specified for HTTP sessions")
+	}
+
+	// Ensure that the destination doesn't specify an ephemeral port, which is
+	// only meaningful for listeners.
+	if forwardingurl.IsEphemeralPortAddress(destinationProtocol, destinationAddress) {
+		return errors.New("destination cannot use an ephemeral port")
+	}
+
+	// Ensure that port ranges are correctly paired.
+	sourceRange := portRange(sourceProtocol, sourceAddress)
+	destinationRange := portRange(destinationProtocol, destinationAddress)
+	if (sourceRange == nil) != (destinationRange == nil) {
+		return errors.New("port ranges must be specified on both endpoints")
+	} else if sourceRange != nil && sourceRange.Size() != destinationRange.Size() {
+		return fmt.Errorf("port range sizes differ (%d != %d)", sourceRange.Size(), destinationRange.Size())
+	}
+
+	// Success.
+	return nil
+}
+
 // EnsureValid ensures that Session's invariants are respected.
 func (s *Session) EnsureValid() error {
 	// A nil session is not valid.
@@ -60,6 +125,11 @@ func (s *Session) EnsureValid() error {
 		return fmt.Errorf("invalid destination-specific configuration: %w", err)
 	}
 
+	// Ensure that the source and destination are compatible.
+	if err := EnsureEndpointsCompatible(s.Source, s.Destination, s.Configuration); err != nil {
+		return fmt.Errorf("incompatible endpoints: %w", err)
+	}
+
 	// Validate the session name.
 	if err := selection.EnsureNameValid(s.Name); err != nil {
 		return fmt.Errorf("invalid session name: %w", err)
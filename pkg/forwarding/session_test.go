@@ -1,3 +1,53 @@
 package forwarding
 
-// TODO: Implement.
+import (
+	"testing"
+
+	"github.com/mutagen-io/mutagen/pkg/url"
+)
+
+// TestEnsureEndpointsCompatible tests EnsureEndpointsCompatible.
+func TestEnsureEndpointsCompatible(t *testing.T) {
+	// Define test cases.
+	testCases := []struct {
+		source        string
+		destination   string
+		httpRoutes    []string
+		expectFailure bool
+	}{
+		{"tcp:localhost:8080", "tcp:localhost:80", nil, false},
+		{"unix:/tmp/socket.sock", "tcp:localhost:80", nil, false},
+		{"socks5:localhost:1080", "socks5:tcp", nil, false},
+		{"socks5:localhost:1080", "tcp:localhost:80", nil, true},
+		{"tcp:localhost:8080", "socks5:tcp", nil, true},
+		{"http:localhost:8080", "http:tcp", []string{"/api=api:80"}, false},
+		{"http:localhost:8080", "http:tcp", nil, true},
+		{"http:localhost:8080", "socks5:tcp", []string{"/api=api:80"}, true},
+		{"tcp:localhost:8080", "tcp:localhost:80", []string{"/api=api:80"}, true},
+		{"tcp:localhost:9000-9010", "tcp:localhost:9000-9010", nil, false},
+		{"tcp:localhost:9000-9010", "tcp6:[::1]:8000-8010", nil, false},
+		{"tcp:localhost:9000-9010", "tcp:localhost:9000-9009", nil, true},
+		{"tcp:localhost:9000-9010", "tcp:localhost:9000", nil, true},
+		{"tcp:localhost:9000", "tcp:localhost:9000-9010", nil, true},
+		{"unix:/tmp/socket.sock", "tcp:localhost:9000-9010", nil, true},
+		{"tcp:localhost:0", "tcp:localhost:80", nil, false},
+		{"tcp:localhost:8080", "tcp:localhost:0", nil, true},
+	}
+
+	// Process test cases.
+	for _, testCase := range testCases {
+		source := &url.URL{Kind: url.Kind_Forwarding, Path: testCase.source}
+		destination := &url.URL{Kind: url.Kind_Forwarding, Path: testCase.destination}
+		configuration := &Configuration{HttpRoutes: testCase.httpRoutes}
+		err := EnsureEndpointsCompatible(source, destination, configuration)
+		if err != nil && !testCase.expectFailure {
+			t.Errorf("endpoints (%s, %s) unexpectedly incompatible: %v",
+				testCase.source, testCase.destination, err,
+			)
+		} else if err == nil && testCase.expectFailure {
+			t.Errorf("endpoints (%s, %s) unexpectedly compatible",
+				testCase.source, testCase.destination,
+			)
+		}
+	}
+}
@@ -11,9 +11,17 @@ func (m SocketOverwriteMode) IsDefault() bool {
 }
 
 // AttemptOverwrite indicates whether or not the socket overwrite mode is
-// SocketOverwriteMode_SocketOverwriteModeOverwrite.
+// SocketOverwriteMode_SocketOverwriteModeOverwrite or
+// SocketOverwriteMode_SocketOverwriteModeStale.
 func (m SocketOverwriteMode) AttemptOverwrite() bool {
-	return m == SocketOverwriteMode_SocketOverwriteModeOverwrite
+	return m == SocketOverwriteMode_SocketOverwriteModeOverwrite ||
+		m == SocketOverwriteMode_SocketOverwriteModeStale
+}
+
+// OverwriteOnlyIfStale indicates whether or not the socket overwrite mode is
+// SocketOverwriteMode_SocketOverwriteModeStale.
+func (m SocketOverwriteMode) OverwriteOnlyIfStale() bool {
+	return m == SocketOverwriteMode_SocketOverwriteModeStale
 }
 
 // MarshalText implements encoding.TextMarshaler.MarshalText.
@@ -25,6 +33,8 @@ func (m SocketOverwriteMode) MarshalText() ([]byte, error) {
 		result = "leave"
 	case SocketOverwriteMode_SocketOverwriteModeOverwrite:
 		result = "overwrite"
+	case SocketOverwriteMode_SocketOverwriteModeStale:
+		result = "stale"
 	default:
 		result = "unknown"
 	}
@@ -42,6 +52,8 @@ func (m *SocketOverwriteMode) UnmarshalText(textBytes []byte) error {
 		*m = SocketOverwriteMode_SocketOverwriteModeLeave
 	case "overwrite":
 		*m = SocketOverwriteMode_SocketOverwriteModeOverwrite
+	case "stale":
+		*m = SocketOverwriteMode_SocketOverwriteModeStale
 	default:
 		return fmt.Errorf("unknown socket overwrite mode specification: %s", text)
 	}
@@ -58,6 +70,8 @@ func (m SocketOverwriteMode) Supported() bool {
 		return true
 	case SocketOverwriteMode_SocketOverwriteModeOverwrite:
 		return true
+	case SocketOverwriteMode_SocketOverwriteModeStale:
+		return true
 	default:
 		return false
 	}
@@ -72,6 +86,8 @@ func (m SocketOverwriteMode) Description() string {
 		return "Leave"
 	case SocketOverwriteMode_SocketOverwriteModeOverwrite:
 		return "Overwrite"
+	case SocketOverwriteMode_SocketOverwriteModeStale:
+		return "Overwrite if stale"
 	default:
 		return "Unknown"
 	}
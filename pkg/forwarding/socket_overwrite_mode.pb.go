@@ -37,6 +37,10 @@ const (
 	// sockets should be overwritten when creating a Unix domain socket
 	// listener.
 	SocketOverwriteMode_SocketOverwriteModeOverwrite SocketOverwriteMode = 2
+	// SocketOverwriteMode_SocketOverwriteModeStale specifies that existing
+	// sockets should be overwritten when creating a Unix domain socket listener
+	// only if they're stale (i.e. no process is listening on them).
+	SocketOverwriteMode_SocketOverwriteModeStale SocketOverwriteMode = 3
 )
 
 // Enum value maps for SocketOverwriteMode.
@@ -45,11 +49,13 @@ var (
 		0: "SocketOverwriteModeDefault",
 		1: "SocketOverwriteModeLeave",
 		2: "SocketOverwriteModeOverwrite",
+		3: "SocketOverwriteModeStale",
 	}
 	SocketOverwriteMode_value = map[string]int32{
 		"SocketOverwriteModeDefault":   0,
 		"SocketOverwriteModeLeave":     1,
 		"SocketOverwriteModeOverwrite": 2,
+		"SocketOverwriteModeStale":     3,
 	}
 )
 
@@ -86,18 +92,20 @@ var file_forwarding_socket_overwrite_mode_proto_rawDesc = []byte{
 	0x0a, 0x26, 0x66, 0x6f, 0x72, 0x77, 0x61, 0x72, 0x64, 0x69, 0x6e, 0x67, 0x2f, 0x73, 0x6f, 0x63,
 	0x6b, 0x65, 0x74, 0x5f, 0x6f, 0x76, 0x65, 0x72, 0x77, 0x72, 0x69, 0x74, 0x65, 0x5f, 0x6d, 0x6f,
 	0x64, 0x65, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x12, 0x0a, 0x66, 0x6f, 0x72, 0x77, 0x61, 0x72,
-	0x64, 0x69, 0x6e, 0x67, 0x2a, 0x75, 0x0a, 0x13, 0x53, 0x6f, 0x63, 0x6b, 0x65, 0x74, 0x4f, 0x76,
-	0x65, 0x72, 0x77, 0x72, 0x69, 0x74, 0x65, 0x4d, 0x6f, 0x64, 0x65, 0x12, 0x1e, 0x0a, 0x1a, 0x53,
-	0x6f, 0x63, 0x6b, 0x65, 0x74, 0x4f, 0x76, 0x65, 0x72, 0x77, 0x72, 0x69, 0x74, 0x65, 0x4d, 0x6f,
-	0x64, 0x65, 0x44, 0x65, 0x66, 0x61, 0x75, 0x6c, 0x74, 0x10, 0x00, 0x12, 0x1c, 0x0a, 0x18, 0x53,
-	0x6f, 0x63, 0x6b, 0x65, 0x74, 0x4f, 0x76, 0x65, 0x72, 0x77, 0x72, 0x69, 0x74, 0x65, 0x4d, 0x6f,
-	0x64, 0x65, 0x4c, 0x65, 0x61, 0x76, 0x65, 0x10, 0x01, 0x12, 0x20, 0x0a, 0x1c, 0x53, 0x6f, 0x63,
-	0x6b, 0x65, 0x74, 0x4f, 0x76, 0x65, 0x72, 0x77, 0x72, 0x69, 0x74, 0x65, 0x4d, 0x6f, 0x64, 0x65,
-	0x4f, 0x76, 0x65, 0x72, 0x77, 0x72, 0x69, 0x74, 0x65, 0x10, 0x02, 0x42, 0x2e, 0x5a, 0x2c, 0x67,
-	0x69, 0x74, 0x68, 0x75, 0x62, 0x2e, 0x63, 0x6f, 0x6d, 0x2f, 0x6d, 0x75, 0x74, 0x61, 0x67, 0x65,
-	0x6e, 0x2d, 0x69, 0x6f, 0x2f, 0x6d, 0x75, 0x74, 0x61, 0x67, 0x65, 0x6e, 0x2f, 0x70, 0x6b, 0x67,
-	0x2f, 0x66, 0x6f, 0x72, 0x77, 0x61, 0x72, 0x64, 0x69, 0x6e, 0x67, 0x62, 0x06, 0x70, 0x72, 0x6f,
-	0x74, 0x6f, 0x33,
+	0x64, 0x69, 0x6e, 0x67, 0x2a, 0x93, 0x01, 0x0a, 0x13, 0x53, 0x6f, 0x63, 0x6b, 0x65, 0x74, 0x4f,
+	0x76, 0x65, 0x72, 0x77, 0x72, 0x69, 0x74, 0x65, 0x4d, 0x6f, 0x64, 0x65, 0x12, 0x1e, 0x0a, 0x1a,
+	0x53, 0x6f, 0x63, 0x6b, 0x65, 0x74, 0x4f, 0x76, 0x65, 0x72, 0x77, 0x72, 0x69, 0x74, 0x65, 0x4d,
+	0x6f, 0x64, 0x65, 0x44, 0x65, 0x66, 0x61, 0x75, 0x6c, 0x74, 0x10, 0x00, 0x12, 0x1c, 0x0a, 0x18,
+	0x53, 0x6f, 0x63, 0x6b, 0x65, 0x74, 0x4f, 0x76, 0x65, 0x72, 0x77, 0x72, 0x69, 0x74, 0x65, 0x4d,
+	0x6f, 0x64, 0x65, 0x4c, 0x65, 0x61, 0x76, 0x65, 0x10, 0x01, 0x12, 0x20, 0x0a, 0x1c, 0x53, 0x6f,
+	0x63, 0x6b, 0x65, 0x74, 0x4f, 0x76, 0x65, 0x72, 0x77, 0x72, 0x69, 0x74, 0x65, 0x4d, 0x6f, 0x64,
+	0x65, 0x4f, 0x76, 0x65, 0x72, 0x77, 0x72, 0x69, 0x74, 0x65, 0x10, 0x02, 0x12, 0x1c, 0x0a, 0x18,
+	0x53, 0x6f, 0x63, 0x6b, 0x65, 0x74, 0x4f, 0x76, 0x65, 0x72, 0x77, 0x72, 0x69, 0x74, 0x65, 0x4d,
+	0x6f, 0x64, 0x65, 0x53, 0x74, 0x61, 0x6c, 0x65, 0x10, 0x03, 0x42, 0x2e, 0x5a, 0x2c, 0x67, 0x69,
+	0x74, 0x68, 0x75, 0x62, 0x2e, 0x63, 0x6f, 0x6d, 0x2f, 0x6d, 0x75, 0x74, 0x61, 0x67, 0x65, 0x6e,
+	0x2d, 0x69, 0x6f, 0x2f, 0x6d, 0x75, 0x74, 0x61, 0x67, 0x65, 0x6e, 0x2f, 0x70, 0x6b, 0x67, 0x2f,
+	0x66, 0x6f, 0x72, 0x77, 0x61, 0x72, 0x64, 0x69, 0x6e, 0x67, 0x62, 0x06, 0x70, 0x72, 0x6f, 0x74,
+	0x6f, 0x33,
 }
 
 var (
@@ -17,6 +17,7 @@ func TestSocketOverwriteModeUnmarshal(t *testing.T) {
 		{"asdf", SocketOverwriteMode_SocketOverwriteModeDefault, true},
 		{"leave", SocketOverwriteMode_SocketOverwriteModeLeave, false},
 		{"overwrite", SocketOverwriteMode_SocketOverwriteModeOverwrite, false},
+		{"stale", SocketOverwriteMode_SocketOverwriteModeStale, false},
 	}
 
 	// Process test cases.
@@ -49,7 +50,8 @@ func TestSocketOverwriteModeSupported(t *testing.T) {
 		{SocketOverwriteMode_SocketOverwriteModeDefault, false},
 		{SocketOverwriteMode_SocketOverwriteModeLeave, true},
 		{SocketOverwriteMode_SocketOverwriteModeOverwrite, true},
-		{(SocketOverwriteMode_SocketOverwriteModeOverwrite + 1), false},
+		{SocketOverwriteMode_SocketOverwriteModeStale, true},
+		{(SocketOverwriteMode_SocketOverwriteModeStale + 1), false},
 	}
 
 	// Process test cases.
@@ -75,7 +77,8 @@ func TestSocketOverwriteModeDescription(t *testing.T) {
 		{SocketOverwriteMode_SocketOverwriteModeDefault, "Default"},
 		{SocketOverwriteMode_SocketOverwriteModeLeave, "Leave"},
 		{SocketOverwriteMode_SocketOverwriteModeOverwrite, "Overwrite"},
-		{(SocketOverwriteMode_SocketOverwriteModeOverwrite + 1), "Unknown"},
+		{SocketOverwriteMode_SocketOverwriteModeStale, "Overwrite if stale"},
+		{(SocketOverwriteMode_SocketOverwriteModeStale + 1), "Unknown"},
 	}
 
 	// Process test cases.
@@ -0,0 +1,4 @@
+// Package socks5 provides the server side of the SOCKS5 handshake (RFC 1928)
+// for use by SOCKS5 forwarding sessions. Only the CONNECT command and the "no
+// authentication required" method are supported.
+package socks5
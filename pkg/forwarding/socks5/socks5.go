@@ -0,0 +1,183 @@
+package socks5
+
+import (
+	"context"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"os"
+	"strconv"
+	"syscall"
+)
+
+const (
+	// version is the SOCKS protocol version.
+	version = 0x05
+
+	// methodNoAuthentication is the "no authentication required" method.
+	methodNoAuthentication = 0x00
+	// methodNoAcceptable is the method used to indicate that none of the
+	// client's offered methods are acceptable.
+	methodNoAcceptable = 0xFF
+
+	// commandConnect is the CONNECT command.
+	commandConnect = 0x01
+
+	// addressTypeIPv4 is the IPv4 address type.
+	addressTypeIPv4 = 0x01
+	// addressTypeDomainName is the fully-qualified domain name address type.
+	addressTypeDomainName = 0x03
+	// addressTypeIPv6 is the IPv6 address type.
+	addressTypeIPv6 = 0x04
+)
+
+// ReplyCode is a SOCKS5 reply code.
+type ReplyCode byte
+
+const (
+	// ReplySucceeded indicates success.
+	ReplySucceeded ReplyCode = 0x00
+	// ReplyGeneralFailure indicates a general SOCKS server failure.
+	ReplyGeneralFailure ReplyCode = 0x01
+	// ReplyNetworkUnreachable indicates that the network was unreachable.
+	ReplyNetworkUnreachable ReplyCode = 0x03
+	// ReplyHostUnreachable indicates that the host was unreachable.
+	ReplyHostUnreachable ReplyCode = 0x04
+	// ReplyConnectionRefused indicates that the connection was refused.
+	ReplyConnectionRefused ReplyCode = 0x05
+	// ReplyTTLExpired indicates that the connection attempt timed out.
+	ReplyTTLExpired ReplyCode = 0x06
+	// ReplyCommandNotSupported indicates that the command isn't supported.
+	ReplyCommandNotSupported ReplyCode = 0x07
+	// ReplyAddressTypeNotSupported indicates that the address type isn't
+	// supported.
+	ReplyAddressTypeNotSupported ReplyCode = 0x08
+)
+
+// ReplyCodeForError determines the most appropriate reply code for a dialing
+// error. It returns ReplySucceeded if err is nil. Errors that have lost their
+// type information (e.g. those transmitted from remote endpoints) are reported
+// as general failures.
+func ReplyCodeForError(err error) ReplyCode {
+	var dnsError *net.DNSError
+	switch {
+	case err == nil:
+		return ReplySucceeded
+	case errors.Is(err, syscall.ECONNREFUSED):
+		return ReplyConnectionRefused
+	case errors.Is(err, syscall.ENETUNREACH):
+		return ReplyNetworkUnreachable
+	case errors.Is(err, syscall.EHOSTUNREACH), errors.As(err, &dnsError):
+		return ReplyHostUnreachable
+	case errors.Is(err, os.ErrDeadlineExceeded), errors.Is(err, context.DeadlineExceeded):
+		return ReplyTTLExpired
+	default:
+		return ReplyGeneralFailure
+	}
+}
+
+// Negotiate performs method negotiation and reads the connection request from
+// a SOCKS5 client. It returns the requested target address in a form suitable
+// for dialing. If the request can't be serviced, then a failure reply is sent
+// before returning an error. On success, the caller is responsible for sending
+// a reply using Reply once the target has been dialed.
+func Negotiate(connection io.ReadWriter) (string, error) {
+	// Read the method selection header.
+	var header [2]byte
+	if _, err := io.ReadFull(connection, header[:]); err != nil {
+		return "", fmt.Errorf("unable to read method selection header: %w", err)
+	} else if header[0] != version {
+		return "", fmt.Errorf("unsupported SOCKS version: %d", header[0])
+	}
+
+	// Read the offered methods and ensure that unauthenticated access is among
+	// them.
+	methods := make([]byte, header[1])
+	if _, err := io.ReadFull(connection, methods); err != nil {
+		return "", fmt.Errorf("unable to read authentication methods: %w", err)
+	}
+	var acceptable bool
+	for _, method := range methods {
+		if method == methodNoAuthentication {
+			acceptable = true
+			break
+		}
+	}
+	if !acceptable {
+		connection.Write([]byte{version, methodNoAcceptable})
+		return "", errors.New("no acceptable authentication methods offered")
+	}
+
+	// Select unauthenticated access.
+	if _, err := connection.Write([]byte{version, methodNoAuthentication}); err != nil {
+		return "", fmt.Errorf("unable to send method selection: %w", err)
+	}
+
+	// Read the request header.
+	var request [4]byte
+	if _, err := io.ReadFull(connection, request[:]); err != nil {
+		return "", fmt.Errorf("unable to read request header: %w", err)
+	} else if request[0] != version {
+		return "", fmt.Errorf("unsupported SOCKS version in request: %d", request[0])
+	}
+
+	// Read the destination host.
+	var host string
+	switch request[3] {
+	case addressTypeIPv4:
+		address := make([]byte, net.IPv4len)
+		if _, err := io.ReadFull(connection, address); err != nil {
+			return "", fmt.Errorf("unable to read IPv4 address: %w", err)
+		}
+		host = net.IP(address).String()
+	case addressTypeIPv6:
+		address := make([]byte, net.IPv6len)
+		if _, err := io.ReadFull(connection, address); err != nil {
+			return "", fmt.Errorf("unable to read IPv6 address: %w", err)
+		}
+		host = net.IP(address).String()
+	case addressTypeDomainName:
+		var length [1]byte
+		if _, err := io.ReadFull(connection, length[:]); err != nil {
+			return "", fmt.Errorf("unable to read domain name length: %w", err)
+		}
+		name := make([]byte, length[0])
+		if _, err := io.ReadFull(connection, name); err != nil {
+			return "", fmt.Errorf("unable to read domain name: %w", err)
+		}
+		host = string(name)
+	default:
+		Reply(connection, ReplyAddressTypeNotSupported)
+		return "", fmt.Errorf("unsupported address type: %d", request[3])
+	}
+
+	// Read the destination port.
+	var port [2]byte
+	if _, err := io.ReadFull(connection, port[:]); err != nil {
+		return "", fmt.Errorf("unable to read port: %w", err)
+	}
+
+	// Ensure that the command is supported. We wait until the full request has
+	// been read to perform this check so that the failure reply isn't
+	// interleaved with unread request data.
+	if request[1] != commandConnect {
+		Reply(connection, ReplyCommandNotSupported)
+		return "", fmt.Errorf("unsupported command: %d", request[1])
+	}
+
+	// Success.
+	return net.JoinHostPort(host, strconv.Itoa(int(binary.BigEndian.Uint16(port[:])))), nil
+}
+
+// Reply sends a reply with the specified code to a SOCKS5 client. Because the
+// bound address of the outgoing connection isn't meaningful to clients of a
+// forwarded connection, an unspecified IPv4 address is always reported.
+func Reply(connection io.Writer, code ReplyCode) error {
+	reply := []byte{version, byte(code), 0x00, addressTypeIPv4, 0, 0, 0, 0, 0, 0}
+	if _, err := connection.Write(reply); err != nil {
+		return fmt.Errorf("unable to send reply: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,142 @@
+package socks5
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"net"
+	"syscall"
+	"testing"
+)
+
+// testConnection is an io.ReadWriter that reads from a fixed input and records
+// output.
+type testConnection struct {
+	// input is the client input.
+	input *bytes.Reader
+	// output is the server output.
+	output bytes.Buffer
+}
+
+// Read implements io.Reader.Read.
+func (c *testConnection) Read(buffer []byte) (int, error) {
+	return c.input.Read(buffer)
+}
+
+// Write implements io.Writer.Write.
+func (c *testConnection) Write(data []byte) (int, error) {
+	return c.output.Write(data)
+}
+
+// TestNegotiate tests Negotiate.
+func TestNegotiate(t *testing.T) {
+	// Define test cases.
+	testCases := []struct {
+		description     string
+		input           []byte
+		expectedAddress string
+		expectedOutput  []byte
+		expectFailure   bool
+	}{
+		{
+			"IPv4 address",
+			[]byte{5, 1, 0, 5, 1, 0, 1, 127, 0, 0, 1, 0x1F, 0x90},
+			"127.0.0.1:8080",
+			[]byte{5, 0},
+			false,
+		},
+		{
+			"IPv6 address",
+			[]byte{5, 2, 2, 0, 5, 1, 0, 4, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 1, 0, 80},
+			"[::1]:80",
+			[]byte{5, 0},
+			false,
+		},
+		{
+			"domain name",
+			[]byte{5, 1, 0, 5, 1, 0, 3, 8, 'd', 'a', 't', 'a', 'b', 'a', 's', 'e', 0x15, 0x38},
+			"database:5432",
+			[]byte{5, 0},
+			false,
+		},
+		{
+			"unsupported version",
+			[]byte{4, 1, 0},
+			"",
+			nil,
+			true,
+		},
+		{
+			"no acceptable methods",
+			[]byte{5, 1, 2},
+			"",
+			[]byte{5, 0xFF},
+			true,
+		},
+		{
+			"unsupported command",
+			[]byte{5, 1, 0, 5, 2, 0, 1, 127, 0, 0, 1, 0, 80},
+			"",
+			[]byte{5, 0, 5, 7, 0, 1, 0, 0, 0, 0, 0, 0},
+			true,
+		},
+		{
+			"unsupported address type",
+			[]byte{5, 1, 0, 5, 1, 0, 9},
+			"",
+			[]byte{5, 0, 5, 8, 0, 1, 0, 0, 0, 0, 0, 0},
+			true,
+		},
+		{
+			"truncated request",
+			[]byte{5, 1, 0, 5, 1, 0, 1, 127, 0},
+			"",
+			[]byte{5, 0},
+			true,
+		},
+	}
+
+	// Process test cases.
+	for _, testCase := range testCases {
+		connection := &testConnection{input: bytes.NewReader(testCase.input)}
+		address, err := Negotiate(connection)
+		if err != nil && !testCase.expectFailure {
+			t.Errorf("%s: negotiation failed unexpectedly: %v", testCase.description, err)
+		} else if err == nil && testCase.expectFailure {
+			t.Errorf("%s: negotiation succeeded unexpectedly", testCase.description)
+		} else if address != testCase.expectedAddress {
+			t.Errorf("%s: address does not match expected: %s != %s",
+				testCase.description, address, testCase.expectedAddress,
+			)
+		}
+		if !bytes.Equal(connection.output.Bytes(), testCase.expectedOutput) {
+			t.Errorf("%s: output does not match expected: %v != %v",
+				testCase.description, connection.output.Bytes(), testCase.expectedOutput,
+			)
+		}
+	}
+}
+
+// TestReplyCodeForError tests ReplyCodeForError.
+func TestReplyCodeForError(t *testing.T) {
+	// Define test cases.
+	testCases := []struct {
+		err      error
+		expected ReplyCode
+	}{
+		{nil, ReplySucceeded},
+		{errors.New("remote failure"), ReplyGeneralFailure},
+		{fmt.Errorf("dial failed: %w", syscall.ECONNREFUSED), ReplyConnectionRefused},
+		{&net.OpError{Op: "dial", Err: syscall.ENETUNREACH}, ReplyNetworkUnreachable},
+		{&net.DNSError{Err: "no such host", Name: "missing"}, ReplyHostUnreachable},
+	}
+
+	// Process test cases.
+	for i, testCase := range testCases {
+		if code := ReplyCodeForError(testCase.err); code != testCase.expected {
+			t.Errorf("test index %d: reply code does not match expected: %d != %d",
+				i, code, testCase.expected,
+			)
+		}
+	}
+}
@@ -3,6 +3,8 @@ package forwarding
 import (
 	"errors"
 	"fmt"
+
+	"google.golang.org/protobuf/types/known/timestamppb"
 )
 
 // Description returns a human-readable description of the session status.
@@ -46,6 +48,20 @@ func (s *EndpointState) ensureValid() error {
 		return errors.New("nil state")
 	}
 
+	// Ensure that the next bind attempt time is valid, if present.
+	if s.NextBindAttempt != nil {
+		if err := s.NextBindAttempt.CheckValid(); err != nil {
+			return fmt.Errorf("invalid next bind attempt time: %w", err)
+		}
+	}
+
+	// Ensure that listener states are valid.
+	for _, listener := range s.Listeners {
+		if listener == nil {
+			return errors.New("nil listener state")
+		}
+	}
+
 	// We could perform additional validation based on the session status and
 	// the endpoint connectivity, but it would be prohibitively complex, and all
 	// we're really concerned about here is memory safety and other structural
@@ -55,6 +71,47 @@ func (s *EndpointState) ensureValid() error {
 	return nil
 }
 
+// ensureValid ensures that ConnectionState's invariants are respected.
+func (s *ConnectionState) ensureValid() error {
+	// A nil connection state is not valid.
+	if s == nil {
+		return errors.New("nil connection state")
+	}
+
+	// Ensure that the opening time is valid.
+	if err := s.Opened.CheckValid(); err != nil {
+		return fmt.Errorf("invalid opening time: %w", err)
+	}
+
+	// Ensure that the round-trip time is valid, if present.
+	if s.RoundTripTime != nil {
+		if err := s.RoundTripTime.CheckValid(); err != nil {
+			return fmt.Errorf("invalid round-trip time: %w", err)
+		}
+	}
+
+	// Success.
+	return nil
+}
+
+// ensureValid ensures that SessionStatistics' invariants are respected.
+func (s *SessionStatistics) ensureValid() error {
+	// A nil statistics object is not valid.
+	if s == nil {
+		return errors.New("nil statistics")
+	}
+
+	// Ensure that the last connection time is valid, if present.
+	if s.LastConnectionTime != nil {
+		if err := s.LastConnectionTime.CheckValid(); err != nil {
+			return fmt.Errorf("invalid last connection time: %w", err)
+		}
+	}
+
+	// Success.
+	return nil
+}
+
 // EnsureValid ensures that State's invariants are respected.
 func (s *State) EnsureValid() error {
 	// A nil state is not valid.
@@ -83,6 +140,27 @@ func (s *State) EnsureValid() error {
 		return fmt.Errorf("invalid destination endpoint state: %w", err)
 	}
 
+	// Ensure that statistics are valid.
+	if err := s.Statistics.ensureValid(); err != nil {
+		return fmt.Errorf("invalid statistics: %w", err)
+	}
+
+	// Ensure that connection states are valid.
+	for _, connection := range s.Connections {
+		if err := connection.ensureValid(); err != nil {
+			return fmt.Errorf("invalid connection state: %w", err)
+		}
+	}
+
 	// Success.
 	return nil
 }
+
+// recordConnection updates connection counts and statistics to reflect the
+// opening of a new connection.
+func (s *State) recordConnection() {
+	s.OpenConnections++
+	s.TotalConnections++
+	s.Statistics.TotalConnections++
+	s.Statistics.LastConnectionTime = timestamppb.Now()
+}
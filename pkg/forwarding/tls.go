@@ -0,0 +1,376 @@
+package forwarding
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"math/big"
+	"net"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"github.com/mutagen-io/mutagen/pkg/filesystem"
+)
+
+const (
+	// tlsDirectoryName is the name of the TLS storage directory within the
+	// forwarding data directory.
+	tlsDirectoryName = "tls"
+	// developmentCertificateName is the name of the file (within the TLS
+	// storage directory) containing the PEM-encoded development certificate and
+	// private key.
+	developmentCertificateName = "development.pem"
+	// developmentCertificateLifetime is the validity period for generated
+	// development certificates.
+	developmentCertificateLifetime = 365 * 24 * time.Hour
+	// developmentCertificateRenewalWindow is the period before expiration at
+	// which development certificates are regenerated.
+	developmentCertificateRenewalWindow = 7 * 24 * time.Hour
+)
+
+// developmentCertificateNames are the subject alternative names for which
+// development certificates are issued. Development certificates are only used
+// to terminate TLS on local listeners, so they're limited to localhost and its
+// loopback addresses.
+var developmentCertificateNames = []string{"localhost", "127.0.0.1", "::1"}
+
+// developmentCertificateLock serializes the loading and generation of the
+// development certificate.
+var developmentCertificateLock sync.Mutex
+
+// generateDevelopmentCertificate generates a self-signed leaf certificate (and
+// corresponding private key) valid only for developmentCertificateNames. The
+// certificate is not a certificate authority and thus can't be used to sign
+// other certificates if a client is configured to trust it. The result is
+// returned in PEM-encoded form.
+func generateDevelopmentCertificate() ([]byte, error) {
+	// Generate the private key.
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		return nil, fmt.Errorf("unable to generate private key: %w", err)
+	}
+
+	// Generate a serial number.
+	serialNumber, err := rand.Int(rand.Reader, new(big.Int).Lsh(big.NewInt(1), 128))
+	if err != nil {
+		return nil, fmt.Errorf("unable to generate serial number: %w", err)
+	}
+
+	// Split the subject alternative names by type.
+	var dnsNames []string
+	var ipAddresses []net.IP
+	for _, name := range developmentCertificateNames {
+		if ip := net.ParseIP(name); ip != nil {
+			ipAddresses = append(ipAddresses, ip)
+		} else {
+			dnsNames = append(dnsNames, name)
+		}
+	}
+
+	// Create the certificate.
+	now := time.Now()
+	template := &x509.Certificate{
+		SerialNumber:          serialNumber,
+		Subject:               pkix.Name{Organization: []string{"Mutagen Development"}},
+		NotBefore:             now.Add(-time.Hour),
+		NotAfter:              now.Add(developmentCertificateLifetime),
+		KeyUsage:              x509.KeyUsageDigitalSignature | x509.KeyUsageKeyEncipherment,
+		ExtKeyUsage:           []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth},
+		BasicConstraintsValid: true,
+		IsCA:                  false,
+		DNSNames:              dnsNames,
+		IPAddresses:           ipAddresses,
+	}
+	certificate, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	if err != nil {
+		return nil, fmt.Errorf("unable to create certificate: %w", err)
+	}
+
+	// Encode the private key.
+	encodedKey, err := x509.MarshalPKCS8PrivateKey(key)
+	if err != nil {
+		return nil, fmt.Errorf("unable to encode private key: %w", err)
+	}
+
+	// Encode the result.
+	result := pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: certificate})
+	result = append(result, pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: encodedKey})...)
+
+	// Success.
+	return result, nil
+}
+
+// parseDevelopmentCertificate parses a PEM-encoded development certificate and
+// private key, verifying that the certificate is a leaf certificate that covers
+// developmentCertificateNames and that it isn't near expiration. Certificates
+// that fail these checks (e.g. those persisted by older versions) are rejected
+// so that they'll be regenerated.
+func parseDevelopmentCertificate(data []byte) (tls.Certificate, error) {
+	// Parse the certificate and key.
+	certificate, err := tls.X509KeyPair(data, data)
+	if err != nil {
+		return tls.Certificate{}, err
+	}
+
+	// Verify that the certificate isn't expired or about to expire.
+	leaf, err := x509.ParseCertificate(certificate.Certificate[0])
+	if err != nil {
+		return tls.Certificate{}, fmt.Errorf("unable to parse certificate: %w", err)
+	} else if time.Now().Add(developmentCertificateRenewalWindow).After(leaf.NotAfter) {
+		return tls.Certificate{}, errors.New("certificate expired or near expiration")
+	}
+
+	// Verify that the certificate is a leaf certificate.
+	if leaf.IsCA || leaf.KeyUsage&x509.KeyUsageCertSign != 0 {
+		return tls.Certificate{}, errors.New("certificate is a certificate authority")
+	}
+
+	// Verify that the certificate covers exactly the expected names.
+	if len(leaf.DNSNames)+len(leaf.IPAddresses) != len(developmentCertificateNames) {
+		return tls.Certificate{}, errors.New("certificate has unexpected subject alternative names")
+	}
+	for _, name := range developmentCertificateNames {
+		if err := leaf.VerifyHostname(name); err != nil {
+			return tls.Certificate{}, fmt.Errorf("certificate not valid for %s: %w", name, err)
+		}
+	}
+
+	// Success.
+	return certificate, nil
+}
+
+// loadDevelopmentCertificate loads the development certificate, generating and
+// persisting a new one if none exists or the existing one is invalid or near
+// expiration. Persisting the certificate allows clients to trust it across
+// forwarding sessions and daemon restarts.
+func loadDevelopmentCertificate() (tls.Certificate, error) {
+	// Lock the development certificate and defer its release.
+	developmentCertificateLock.Lock()
+	defer developmentCertificateLock.Unlock()
+
+	// Compute/create the TLS storage directory.
+	tlsDirectoryPath, err := filesystem.Mutagen(
+		true,
+		filesystem.MutagenForwardingDirectoryName,
+		tlsDirectoryName,
+	)
+	if err != nil {
+		return tls.Certificate{}, fmt.Errorf("unable to compute/create TLS storage directory: %w", err)
+	}
+	path := filepath.Join(tlsDirectoryPath, developmentCertificateName)
+
+	// Attempt to load an existing certificate.
+	if data, err := os.ReadFile(path); err == nil {
+		if certificate, err := parseDevelopmentCertificate(data); err == nil {
+			return certificate, nil
+		}
+	} else if !os.IsNotExist(err) {
+		return tls.Certificate{}, fmt.Errorf("unable to read development certificate: %w", err)
+	}
+
+	// Generate a new certificate and persist it.
+	data, err := generateDevelopmentCertificate()
+	if err != nil {
+		return tls.Certificate{}, fmt.Errorf("unable to generate development certificate: %w", err)
+	} else if err = filesystem.WriteFileAtomic(path, data, 0600); err != nil {
+		return tls.Certificate{}, fmt.Errorf("unable to write development certificate: %w", err)
+	}
+
+	// Parse the certificate.
+	return parseDevelopmentCertificate(data)
+}
+
+// newTLSServerConfiguration creates a TLS configuration for terminating TLS
+// based on the specified endpoint configuration.
+func newTLSServerConfiguration(configuration *Configuration) (*tls.Config, error) {
+	// Load the certificate, falling back to the development certificate if
+	// none is specified.
+	var certificate tls.Certificate
+	if configuration.TlsCertificate != "" {
+		certificatePath, err := filesystem.Normalize(configuration.TlsCertificate)
+		if err != nil {
+			return nil, fmt.Errorf("unable to normalize certificate path: %w", err)
+		}
+		keyPath, err := filesystem.Normalize(configuration.TlsKey)
+		if err != nil {
+			return nil, fmt.Errorf("unable to normalize key path: %w", err)
+		}
+		if certificate, err = tls.LoadX509KeyPair(certificatePath, keyPath); err != nil {
+			return nil, fmt.Errorf("unable to load certificate: %w", err)
+		}
+	} else {
+		var err error
+		if certificate, err = loadDevelopmentCertificate(); err != nil {
+			return nil, err
+		}
+	}
+
+	// Success.
+	return &tls.Config{
+		Certificates: []tls.Certificate{certificate},
+		MinVersion:   tls.VersionTLS12,
+	}, nil
+}
+
+// newTLSClientConfiguration creates a TLS configuration for originating TLS
+// based on the specified endpoint configuration. If no server name is
+// specified in the configuration, then the server name must be set on the
+// resulting configuration before use.
+func newTLSClientConfiguration(configuration *Configuration) (*tls.Config, error) {
+	// Create the configuration.
+	result := &tls.Config{
+		ServerName: configuration.TlsServerName,
+		MinVersion: tls.VersionTLS12,
+	}
+
+	// Load the certificate authority bundle, if specified.
+	if configuration.TlsCertificateAuthority != "" {
+		path, err := filesystem.Normalize(configuration.TlsCertificateAuthority)
+		if err != nil {
+			return nil, fmt.Errorf("unable to normalize certificate authority path: %w", err)
+		}
+		bundle, err := os.ReadFile(path)
+		if err != nil {
+			return nil, fmt.Errorf("unable to read certificate authority bundle: %w", err)
+		}
+		result.RootCAs = x509.NewCertPool()
+		if !result.RootCAs.AppendCertsFromPEM(bundle) {
+			return nil, errors.New("certificate authority bundle contained no certificates")
+		}
+	}
+
+	// Success.
+	return result, nil
+}
+
+// withServerName returns a copy of a client TLS configuration with the server
+// name set to the host component of the specified address if no server name is
+// already set. Addresses without a host component are left unmodified.
+func withServerName(configuration *tls.Config, address string) *tls.Config {
+	if configuration.ServerName != "" {
+		return configuration
+	}
+	host, _, err := net.SplitHostPort(address)
+	if err != nil {
+		return configuration
+	}
+	result := configuration.Clone()
+	result.ServerName = host
+	return result
+}
+
+// tlsServerEndpoint wraps a source endpoint to terminate TLS on incoming
+// connections.
+type tlsServerEndpoint struct {
+	Endpoint
+	// configuration is the TLS configuration.
+	configuration *tls.Config
+}
+
+// Open implements Endpoint.Open.
+func (e *tlsServerEndpoint) Open() (net.Conn, error) {
+	connection, err := e.Endpoint.Open()
+	if err != nil {
+		return nil, err
+	}
+	return tls.Server(connection, e.configuration), nil
+}
+
+// tlsIndexedServerEndpoint wraps an indexed source endpoint to terminate TLS on
+// incoming connections.
+type tlsIndexedServerEndpoint struct {
+	IndexedEndpoint
+	// configuration is the TLS configuration.
+	configuration *tls.Config
+}
+
+// OpenIndexed implements IndexedEndpoint.OpenIndexed.
+func (e *tlsIndexedServerEndpoint) OpenIndexed() (net.Conn, int, error) {
+	connection, index, err := e.IndexedEndpoint.OpenIndexed()
+	if err != nil {
+		return nil, 0, err
+	}
+	return tls.Server(connection, e.configuration), index, nil
+}
+
+// tlsClientEndpoint wraps a destination endpoint to originate TLS on outgoing
+// connections.
+type tlsClientEndpoint struct {
+	Endpoint
+	// configuration is the TLS configuration.
+	configuration *tls.Config
+}
+
+// Open implements Endpoint.Open.
+func (e *tlsClientEndpoint) Open() (net.Conn, error) {
+	connection, err := e.Endpoint.Open()
+	if err != nil {
+		return nil, err
+	}
+	return tls.Client(connection, e.configuration), nil
+}
+
+// tlsTargetedClientEndpoint wraps a targeted destination endpoint to originate
+// TLS on outgoing connections. If no server name is configured, then the host
+// component of each target address is used.
+type tlsTargetedClientEndpoint struct {
+	TargetedEndpoint
+	// configuration is the TLS configuration.
+	configuration *tls.Config
+}
+
+// OpenTarget implements TargetedEndpoint.OpenTarget.
+func (e *tlsTargetedClientEndpoint) OpenTarget(address string) (net.Conn, error) {
+	connection, err := e.TargetedEndpoint.OpenTarget(address)
+	if err != nil {
+		return nil, err
+	}
+	return tls.Client(connection, withServerName(e.configuration, address)), nil
+}
+
+// wrapEndpointsWithTLS wraps source and destination endpoints to terminate and
+// originate TLS (respectively) based on their configurations. The destination
+// address is used to determine the default server name for fixed-target
+// destinations.
+func wrapEndpointsWithTLS(
+	source, destination Endpoint,
+	sourceConfiguration, destinationConfiguration *Configuration,
+	destinationAddress string,
+) (Endpoint, Endpoint, error) {
+	// Handle TLS termination on the source.
+	if sourceConfiguration.TlsMode.Enabled() {
+		configuration, err := newTLSServerConfiguration(sourceConfiguration)
+		if err != nil {
+			return nil, nil, fmt.Errorf("unable to configure source TLS: %w", err)
+		}
+		if indexed, ok := source.(IndexedEndpoint); ok {
+			source = &tlsIndexedServerEndpoint{indexed, configuration}
+		} else {
+			source = &tlsServerEndpoint{source, configuration}
+		}
+	}
+
+	// Handle TLS origination on the destination.
+	if destinationConfiguration.TlsMode.Enabled() {
+		configuration, err := newTLSClientConfiguration(destinationConfiguration)
+		if err != nil {
+			return nil, nil, fmt.Errorf("unable to configure destination TLS: %w", err)
+		}
+		if targeted, ok := destination.(TargetedEndpoint); ok {
+			destination = &tlsTargetedClientEndpoint{targeted, configuration}
+		} else {
+			destination = &tlsClientEndpoint{destination, withServerName(configuration, destinationAddress)}
+		}
+	}
+
+	// Success.
+	return source, destination, nil
+}
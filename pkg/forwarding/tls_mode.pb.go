@@ -0,0 +1,143 @@
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// versions:
+// 	protoc-gen-go v1.28.0
+// 	protoc        v3.19.4
+// source: forwarding/tls_mode.proto
+
+package forwarding
+
+import (
+	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
+	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	reflect "reflect"
+	sync "sync"
+)
+
+const (
+	// Verify that this generated code is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(20 - protoimpl.MinVersion)
+	// Verify that runtime/protoimpl is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
+)
+
+// TLSMode specifies whether or not TLS should be used on an endpoint. For
+// source endpoints, enabling TLS terminates TLS on incoming connections. For
+// destination endpoints, enabling TLS originates TLS on outgoing connections.
+type TLSMode int32
+
+const (
+	// TLSMode_TLSModeDefault represents an unspecified TLS mode. It should be
+	// converted to one of the following values based on the desired default
+	// behavior.
+	TLSMode_TLSModeDefault TLSMode = 0
+	// TLSMode_TLSModeDisabled specifies that TLS should not be used.
+	TLSMode_TLSModeDisabled TLSMode = 1
+	// TLSMode_TLSModeEnabled specifies that TLS should be used.
+	TLSMode_TLSModeEnabled TLSMode = 2
+)
+
+// Enum value maps for TLSMode.
+var (
+	TLSMode_name = map[int32]string{
+		0: "TLSModeDefault",
+		1: "TLSModeDisabled",
+		2: "TLSModeEnabled",
+	}
+	TLSMode_value = map[string]int32{
+		"TLSModeDefault":  0,
+		"TLSModeDisabled": 1,
+		"TLSModeEnabled":  2,
+	}
+)
+
+func (x TLSMode) Enum() *TLSMode {
+	p := new(TLSMode)
+	*p = x
+	return p
+}
+
+func (x TLSMode) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (TLSMode) Descriptor() protoreflect.EnumDescriptor {
+	return file_forwarding_tls_mode_proto_enumTypes[0].Descriptor()
+}
+
+func (TLSMode) Type() protoreflect.EnumType {
+	return &file_forwarding_tls_mode_proto_enumTypes[0]
+}
+
+func (x TLSMode) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use TLSMode.Descriptor instead.
+func (TLSMode) EnumDescriptor() ([]byte, []int) {
+	return file_forwarding_tls_mode_proto_rawDescGZIP(), []int{0}
+}
+
+var File_forwarding_tls_mode_proto protoreflect.FileDescriptor
+
+var file_forwarding_tls_mode_proto_rawDesc = []byte{
+	0x0a, 0x19, 0x66, 0x6f, 0x72, 0x77, 0x61, 0x72, 0x64, 0x69, 0x6e, 0x67, 0x2f, 0x74, 0x6c, 0x73,
+	0x5f, 0x6d, 0x6f, 0x64, 0x65, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x12, 0x0a, 0x66, 0x6f, 0x72,
+	0x77, 0x61, 0x72, 0x64, 0x69, 0x6e, 0x67, 0x2a, 0x46, 0x0a, 0x07, 0x54, 0x4c, 0x53, 0x4d, 0x6f,
+	0x64, 0x65, 0x12, 0x12, 0x0a, 0x0e, 0x54, 0x4c, 0x53, 0x4d, 0x6f, 0x64, 0x65, 0x44, 0x65, 0x66,
+	0x61, 0x75, 0x6c, 0x74, 0x10, 0x00, 0x12, 0x13, 0x0a, 0x0f, 0x54, 0x4c, 0x53, 0x4d, 0x6f, 0x64,
+	0x65, 0x44, 0x69, 0x73, 0x61, 0x62, 0x6c, 0x65, 0x64, 0x10, 0x01, 0x12, 0x12, 0x0a, 0x0e, 0x54,
+	0x4c, 0x53, 0x4d, 0x6f, 0x64, 0x65, 0x45, 0x6e, 0x61, 0x62, 0x6c, 0x65, 0x64, 0x10, 0x02, 0x42,
+	0x2e, 0x5a, 0x2c, 0x67, 0x69, 0x74, 0x68, 0x75, 0x62, 0x2e, 0x63, 0x6f, 0x6d, 0x2f, 0x6d, 0x75,
+	0x74, 0x61, 0x67, 0x65, 0x6e, 0x2d, 0x69, 0x6f, 0x2f, 0x6d, 0x75, 0x74, 0x61, 0x67, 0x65, 0x6e,
+	0x2f, 0x70, 0x6b, 0x67, 0x2f, 0x66, 0x6f, 0x72, 0x77, 0x61, 0x72, 0x64, 0x69, 0x6e, 0x67, 0x62,
+	0x06, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x33,
+}
+
+var (
+	file_forwarding_tls_mode_proto_rawDescOnce sync.Once
+	file_forwarding_tls_mode_proto_rawDescData = file_forwarding_tls_mode_proto_rawDesc
+)
+
+func file_forwarding_tls_mode_proto_rawDescGZIP() []byte {
+	file_forwarding_tls_mode_proto_rawDescOnce.Do(func() {
+		file_forwarding_tls_mode_proto_rawDescData = protoimpl.X.CompressGZIP(file_forwarding_tls_mode_proto_rawDescData)
+	})
+	return file_forwarding_tls_mode_proto_rawDescData
+}
+
+var file_forwarding_tls_mode_proto_enumTypes = make([]protoimpl.EnumInfo, 1)
+var file_forwarding_tls_mode_proto_goTypes = []interface{}{
+	(TLSMode)(0), // 0: forwarding.TLSMode
+}
+var file_forwarding_tls_mode_proto_depIdxs = []int32{
+	0, // [0:0] is the sub-list for method output_type
+	0, // [0:0] is the sub-list for method input_type
+	0, // [0:0] is the sub-list for extension type_name
+	0, // [0:0] is the sub-list for extension extendee
+	0, // [0:0] is the sub-list for field type_name
+}
+
+func init() { file_forwarding_tls_mode_proto_init() }
+func file_forwarding_tls_mode_proto_init() {
+	if File_forwarding_tls_mode_proto != nil {
+		return
+	}
+	type x struct{}
+	out := protoimpl.TypeBuilder{
+		File: protoimpl.DescBuilder{
+			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
+			RawDescriptor: file_forwarding_tls_mode_proto_rawDesc,
+			NumEnums:      1,
+			NumMessages:   0,
+			NumExtensions: 0,
+			NumServices:   0,
+		},
+		GoTypes:           file_forwarding_tls_mode_proto_goTypes,
+		DependencyIndexes: file_forwarding_tls_mode_proto_depIdxs,
+		EnumInfos:         file_forwarding_tls_mode_proto_enumTypes,
+	}.Build()
+	File_forwarding_tls_mode_proto = out.File
+	file_forwarding_tls_mode_proto_rawDesc = nil
+	file_forwarding_tls_mode_proto_goTypes = nil
+	file_forwarding_tls_mode_proto_depIdxs = nil
+}
@@ -0,0 +1,163 @@
+package forwarding
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"io"
+	"net"
+	"testing"
+)
+
+// TestDevelopmentCertificate tests development certificate generation and
+// parsing.
+func TestDevelopmentCertificate(t *testing.T) {
+	// Generate a certificate.
+	data, err := generateDevelopmentCertificate()
+	if err != nil {
+		t.Fatal("unable to generate development certificate:", err)
+	}
+
+	// Parse the certificate.
+	certificate, err := parseDevelopmentCertificate(data)
+	if err != nil {
+		t.Fatal("unable to parse development certificate:", err)
+	}
+
+	// Verify that the certificate is a server leaf certificate.
+	leaf, err := x509.ParseCertificate(certificate.Certificate[0])
+	if err != nil {
+		t.Fatal("unable to parse leaf certificate:", err)
+	}
+	if leaf.IsCA {
+		t.Error("development certificate is a certificate authority")
+	}
+	if leaf.KeyUsage != x509.KeyUsageDigitalSignature|x509.KeyUsageKeyEncipherment {
+		t.Error("unexpected key usage:", leaf.KeyUsage)
+	}
+	if len(leaf.ExtKeyUsage) != 1 || leaf.ExtKeyUsage[0] != x509.ExtKeyUsageServerAuth {
+		t.Error("unexpected extended key usage:", leaf.ExtKeyUsage)
+	}
+
+	// Verify that the certificate is valid only for localhost and its loopback
+	// addresses.
+	if len(leaf.DNSNames) != 1 || len(leaf.IPAddresses) != 2 {
+		t.Error("unexpected subject alternative names:", leaf.DNSNames, leaf.IPAddresses)
+	}
+	roots := x509.NewCertPool()
+	roots.AddCert(leaf)
+	for _, name := range []string{"localhost", "127.0.0.1", "::1"} {
+		if _, err := leaf.Verify(x509.VerifyOptions{DNSName: name, Roots: roots}); err != nil {
+			t.Errorf("certificate not valid for %s: %v", name, err)
+		}
+	}
+	if _, err := leaf.Verify(x509.VerifyOptions{DNSName: "example.com", Roots: roots}); err == nil {
+		t.Error("certificate valid for unexpected name")
+	}
+
+	// Verify that invalid data is rejected.
+	if _, err := parseDevelopmentCertificate([]byte("invalid")); err == nil {
+		t.Error("invalid development certificate parsed successfully")
+	}
+}
+
+// TestTLSEndpoints tests TLS termination and origination by endpoint wrappers.
+func TestTLSEndpoints(t *testing.T) {
+	// Generate a certificate and create a pool that trusts it.
+	data, err := generateDevelopmentCertificate()
+	if err != nil {
+		t.Fatal("unable to generate development certificate:", err)
+	}
+	certificate, err := parseDevelopmentCertificate(data)
+	if err != nil {
+		t.Fatal("unable to parse development certificate:", err)
+	}
+	roots := x509.NewCertPool()
+	if !roots.AppendCertsFromPEM(data) {
+		t.Fatal("unable to add development certificate to pool")
+	}
+
+	// Create a TLS-terminating source endpoint.
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal("unable to create listener:", err)
+	}
+	source := &tlsServerEndpoint{
+		&testListenerEndpoint{listener},
+		&tls.Config{Certificates: []tls.Certificate{certificate}},
+	}
+	defer source.Shutdown()
+
+	// Create a TLS-originating destination endpoint that doesn't specify a
+	// server name, forcing it to be derived from the target address.
+	destination := &tlsTargetedClientEndpoint{
+		&testTargetedEndpoint{map[string]string{"localhost:443": listener.Addr().String()}},
+		&tls.Config{RootCAs: roots},
+	}
+
+	// Accept a connection and echo a message.
+	accepted := make(chan error, 1)
+	go func() {
+		connection, err := source.Open()
+		if err != nil {
+			accepted <- err
+			return
+		}
+		defer connection.Close()
+		buffer := make([]byte, 5)
+		if _, err := io.ReadFull(connection, buffer); err != nil {
+			accepted <- err
+			return
+		}
+		_, err = connection.Write(buffer)
+		accepted <- err
+	}()
+
+	// Dial the target and verify the round trip.
+	connection, err := destination.OpenTarget("localhost:443")
+	if err != nil {
+		t.Fatal("unable to open target:", err)
+	}
+	defer connection.Close()
+	if _, err := connection.Write([]byte("hello")); err != nil {
+		t.Fatal("unable to write message:", err)
+	}
+	buffer := make([]byte, 5)
+	if _, err := io.ReadFull(connection, buffer); err != nil {
+		t.Fatal("unable to read echoed message:", err)
+	} else if string(buffer) != "hello" {
+		t.Error("echoed message does not match original:", string(buffer))
+	}
+	if err := <-accepted; err != nil {
+		t.Error("server-side failure:", err)
+	}
+
+	// Verify that the negotiated server name was derived from the target.
+	if state := connection.(*tls.Conn).ConnectionState(); state.ServerName != "localhost" {
+		t.Error("unexpected server name:", state.ServerName)
+	}
+}
+
+// TestWithServerName tests withServerName.
+func TestWithServerName(t *testing.T) {
+	// Set up test cases.
+	testCases := []struct {
+		serverName string
+		address    string
+		expected   string
+	}{
+		{"", "example.com:443", "example.com"},
+		{"", "[::1]:443", "::1"},
+		{"override.test", "example.com:443", "override.test"},
+		{"", "/var/run/server.sock", ""},
+	}
+
+	// Process test cases.
+	for _, testCase := range testCases {
+		configuration := withServerName(&tls.Config{ServerName: testCase.serverName}, testCase.address)
+		if configuration.ServerName != testCase.expected {
+			t.Errorf("server name for %s (%s) does not match expected (%s)",
+				testCase.address, configuration.ServerName, testCase.expected,
+			)
+		}
+	}
+}
@@ -57,3 +57,13 @@ func (v Version) DefaultSocketGroupSpecification() string {
 		panic("unknown or unsupported session version")
 	}
 }
+
+// DefaultTLSMode returns the default TLS mode for the session version.
+func (v Version) DefaultTLSMode() TLSMode {
+	switch v {
+	case Version_Version1:
+		return TLSMode_TLSModeDisabled
+	default:
+		panic("unknown or unsupported session version")
+	}
+}
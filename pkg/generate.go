@@ -3,15 +3,15 @@
 //go:generate go build google.golang.org/protobuf/cmd/protoc-gen-go
 //go:generate go build google.golang.org/grpc/cmd/protoc-gen-go-grpc
 //go:generate protoc --plugin=./protoc-gen-go -I. --go_out=. --go_opt=paths=source_relative filesystem/behavior/probe_mode.proto
-//go:generate protoc --plugin=./protoc-gen-go -I. --go_out=. --go_opt=paths=source_relative forwarding/configuration.proto forwarding/session.proto forwarding/socket_overwrite_mode.proto forwarding/state.proto forwarding/version.proto
+//go:generate protoc --plugin=./protoc-gen-go -I. --go_out=. --go_opt=paths=source_relative forwarding/configuration.proto forwarding/proxy_protocol_mode.proto forwarding/session.proto forwarding/socket_overwrite_mode.proto forwarding/state.proto forwarding/tls_mode.proto forwarding/version.proto
 //go:generate protoc --plugin=./protoc-gen-go -I. --go_out=. --go_opt=paths=source_relative forwarding/endpoint/remote/protocol.proto
 //go:generate protoc --plugin=./protoc-gen-go -I. --go_out=. --go_opt=paths=source_relative selection/selection.proto
 //go:generate protoc --plugin=./protoc-gen-go --plugin=./protoc-gen-go-grpc -I. --go_out=. --go_opt=paths=source_relative --go-grpc_out=. --go-grpc_opt=paths=source_relative service/daemon/daemon.proto
 //go:generate protoc --plugin=./protoc-gen-go --plugin=./protoc-gen-go-grpc -I. --go_out=. --go_opt=paths=source_relative --go-grpc_out=. --go-grpc_opt=paths=source_relative service/forwarding/forwarding.proto
 //go:generate protoc --plugin=./protoc-gen-go --plugin=./protoc-gen-go-grpc -I. --go_out=. --go_opt=paths=source_relative --go-grpc_out=. --go-grpc_opt=paths=source_relative service/prompting/prompting.proto
 //go:generate protoc --plugin=./protoc-gen-go --plugin=./protoc-gen-go-grpc -I. --go_out=. --go_opt=paths=source_relative --go-grpc_out=. --go-grpc_opt=paths=source_relative service/synchronization/synchronization.proto
-//go:generate protoc --plugin=./protoc-gen-go -I. --go_out=. --go_opt=paths=source_relative synchronization/configuration.proto synchronization/scan_mode.proto synchronization/session.proto synchronization/stage_mode.proto synchronization/state.proto synchronization/version.proto synchronization/watch_mode.proto
-//go:generate protoc --plugin=./protoc-gen-go -I. --go_out=. --go_opt=paths=source_relative synchronization/core/archive.proto synchronization/core/cache.proto synchronization/core/change.proto synchronization/core/conflict.proto synchronization/core/entry.proto synchronization/core/ignore_vcs_mode.proto synchronization/core/mode.proto synchronization/core/problem.proto synchronization/core/snapshot.proto synchronization/core/symbolic_link_mode.proto
+//go:generate protoc --plugin=./protoc-gen-go -I. --go_out=. --go_opt=paths=source_relative synchronization/capabilities.proto synchronization/configuration.proto synchronization/event.proto synchronization/scan_mode.proto synchronization/mirror_enforcement_mode.proto synchronization/path_status.proto synchronization/repair.proto synchronization/session.proto synchronization/stage_mode.proto synchronization/state.proto synchronization/statistics.proto synchronization/verification.proto synchronization/version.proto synchronization/versioning.proto synchronization/watch_mode.proto
+//go:generate protoc --plugin=./protoc-gen-go -I. --go_out=. --go_opt=paths=source_relative synchronization/core/archive.proto synchronization/core/cache.proto synchronization/core/change.proto synchronization/core/conflict.proto synchronization/core/entry.proto synchronization/core/ignore_vcs_mode.proto synchronization/core/mode.proto synchronization/core/path_change.proto synchronization/core/problem.proto synchronization/core/replacement_mode.proto synchronization/core/snapshot.proto synchronization/core/symbolic_link_mode.proto synchronization/core/unicode_normalization_mode.proto
 //go:generate protoc --plugin=./protoc-gen-go -I. --go_out=. --go_opt=paths=source_relative synchronization/endpoint/remote/protocol.proto
 //go:generate protoc --plugin=./protoc-gen-go -I. --go_out=. --go_opt=paths=source_relative synchronization/rsync/engine.proto synchronization/rsync/receive.proto synchronization/rsync/transmission.proto
 //go:generate protoc --plugin=./protoc-gen-go -I. --go_out=. --go_opt=paths=source_relative url/url.proto
@@ -20,13 +20,31 @@ import (
 	"github.com/mutagen-io/mutagen/pkg/synchronization"
 
 	// Explicitly import packages that need to register protocol handlers.
+	_ "github.com/mutagen-io/mutagen/pkg/forwarding/protocols/azure"
 	_ "github.com/mutagen-io/mutagen/pkg/forwarding/protocols/docker"
+	_ "github.com/mutagen-io/mutagen/pkg/forwarding/protocols/exec"
 	_ "github.com/mutagen-io/mutagen/pkg/forwarding/protocols/local"
+	_ "github.com/mutagen-io/mutagen/pkg/forwarding/protocols/lxd"
+	_ "github.com/mutagen-io/mutagen/pkg/forwarding/protocols/plugin"
+	_ "github.com/mutagen-io/mutagen/pkg/forwarding/protocols/rendezvous"
 	_ "github.com/mutagen-io/mutagen/pkg/forwarding/protocols/ssh"
+	_ "github.com/mutagen-io/mutagen/pkg/forwarding/protocols/tcp"
+	_ "github.com/mutagen-io/mutagen/pkg/forwarding/protocols/teleport"
+	_ "github.com/mutagen-io/mutagen/pkg/forwarding/protocols/wsl"
 	_ "github.com/mutagen-io/mutagen/pkg/integration/protocols/netpipe"
+	_ "github.com/mutagen-io/mutagen/pkg/synchronization/protocols/azure"
 	_ "github.com/mutagen-io/mutagen/pkg/synchronization/protocols/docker"
+	_ "github.com/mutagen-io/mutagen/pkg/synchronization/protocols/exec"
 	_ "github.com/mutagen-io/mutagen/pkg/synchronization/protocols/local"
+	_ "github.com/mutagen-io/mutagen/pkg/synchronization/protocols/lxd"
+	_ "github.com/mutagen-io/mutagen/pkg/synchronization/protocols/plugin"
+	_ "github.com/mutagen-io/mutagen/pkg/synchronization/protocols/rendezvous"
+	_ "github.com/mutagen-io/mutagen/pkg/synchronization/protocols/s3"
+	_ "github.com/mutagen-io/mutagen/pkg/synchronization/protocols/sftp"
 	_ "github.com/mutagen-io/mutagen/pkg/synchronization/protocols/ssh"
+	_ "github.com/mutagen-io/mutagen/pkg/synchronization/protocols/tcp"
+	_ "github.com/mutagen-io/mutagen/pkg/synchronization/protocols/teleport"
+	_ "github.com/mutagen-io/mutagen/pkg/synchronization/protocols/wsl"
 )
 
 // forwardingManager is the forwarding session manager for the integration
@@ -34,7 +34,7 @@ func waitForSuccessfulSynchronizationCycle(ctx context.Context, sessionID string
 	var states []*synchronization.State
 	var err error
 	for {
-		previousStateIndex, states, err = synchronizationManager.List(ctx, selection, previousStateIndex)
+		previousStateIndex, states, err = synchronizationManager.List(ctx, selection, previousStateIndex, 0, 0)
 		if err != nil {
 			return fmt.Errorf("unable to list session states: %w", err)
 		} else if len(states) != 1 {
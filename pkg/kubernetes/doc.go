@@ -0,0 +1,5 @@
+// Package kubernetes provides an in-process Kubernetes client, built on the
+// official Go client library (client-go), for executing commands inside
+// containers. Commands are executed using the pod exec API over SPDY, falling
+// back to WebSocket streams when SPDY upgrades aren't supported.
+package kubernetes
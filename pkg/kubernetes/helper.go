@@ -0,0 +1,31 @@
+package kubernetes
+
+const (
+	// HelperCommandName is the name of the hidden Mutagen command that hosts
+	// the Kubernetes client in a subprocess. Running the client in a separate
+	// process allows it to be used anywhere that an os/exec.Cmd is expected.
+	// The helper accepts the command to run inside the container as its
+	// arguments.
+	HelperCommandName = "kubernetes-exec"
+
+	// HelperFlagNamespace is the helper flag specifying the pod namespace.
+	HelperFlagNamespace = "namespace"
+	// HelperFlagPod is the helper flag specifying the pod name.
+	HelperFlagPod = "pod"
+	// HelperFlagDeployment is the helper flag specifying a deployment whose
+	// pods should be targeted.
+	HelperFlagDeployment = "deployment"
+	// HelperFlagSelector is the helper flag specifying a label selector
+	// identifying the pods to target.
+	HelperFlagSelector = "selector"
+	// HelperFlagContainer is the helper flag specifying the container name.
+	HelperFlagContainer = "container"
+	// HelperFlagContext is the helper flag specifying the kubeconfig context.
+	HelperFlagContext = "context"
+
+	// HelperFailureExitCode is the exit code used by the helper if it fails to
+	// load the Kubernetes configuration, fails to reach the target container,
+	// or can't determine the remote command's exit status. It matches the code
+	// used by the native SSH helper in these cases.
+	HelperFailureExitCode = 255
+)
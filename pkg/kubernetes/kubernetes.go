@@ -0,0 +1,322 @@
+package kubernetes
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"sync/atomic"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	clientset "k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/kubernetes/scheme"
+	"k8s.io/client-go/rest"
+	"k8s.io/client-go/tools/clientcmd"
+	"k8s.io/client-go/tools/remotecommand"
+	utilexec "k8s.io/client-go/util/exec"
+)
+
+const (
+	// apiRequestTimeout is the timeout for individual (non-streaming) API
+	// requests.
+	apiRequestTimeout = 30 * time.Second
+)
+
+// Target specifies a container within a pod. The pod may be specified by name,
+// by deployment, or by label selector, with exactly one of Pod, Deployment, and
+// Selector set.
+type Target struct {
+	// Namespace is the namespace containing the pod.
+	Namespace string
+	// Pod is the pod name.
+	Pod string
+	// Deployment is the name of a deployment whose pods should be targeted.
+	Deployment string
+	// Selector is a label selector identifying the pods to target.
+	Selector string
+	// Container is the container name within the pod.
+	Container string
+}
+
+// Client is a Kubernetes API client capable of executing commands inside
+// containers.
+type Client struct {
+	// configuration is the REST client configuration.
+	configuration *rest.Config
+	// clientset is the API client set.
+	clientset clientset.Interface
+}
+
+// NewClient creates a new client using the standard kubeconfig loading rules
+// (i.e. the KUBECONFIG environment variable or ~/.kube/config, falling back to
+// the in-cluster configuration). If context is non-empty, then it overrides the
+// kubeconfig's current context.
+func NewClient(context string) (*Client, error) {
+	// Load the configuration.
+	loader := clientcmd.NewNonInteractiveDeferredLoadingClientConfig(
+		clientcmd.NewDefaultClientConfigLoadingRules(),
+		&clientcmd.ConfigOverrides{CurrentContext: context},
+	)
+	configuration, err := loader.ClientConfig()
+	if err != nil {
+		if clientcmd.IsEmptyConfig(err) {
+			return nil, errors.New("no Kubernetes configuration found")
+		}
+		return nil, fmt.Errorf("invalid Kubernetes configuration: %w", err)
+	}
+
+	// Create the client set.
+	clients, err := clientset.NewForConfig(configuration)
+	if err != nil {
+		return nil, fmt.Errorf("unable to create Kubernetes client: %w", err)
+	}
+
+	// Success.
+	return &Client{
+		configuration: configuration,
+		clientset:     clients,
+	}, nil
+}
+
+// describeAPIError converts an API error encountered while performing the
+// specified operation into a more descriptive error.
+func describeAPIError(operation string, err error) error {
+	switch {
+	case apierrors.IsUnauthorized(err):
+		return fmt.Errorf("unauthorized (check cluster credentials): %w", err)
+	case apierrors.IsForbidden(err):
+		return fmt.Errorf("access forbidden: %w", err)
+	default:
+		return fmt.Errorf("unable to %s: %w", operation, err)
+	}
+}
+
+// containerRunning returns whether or not the specified container is running
+// within a pod.
+func containerRunning(pod *corev1.Pod, container string) bool {
+	for _, status := range pod.Status.ContainerStatuses {
+		if status.Name == container {
+			return status.State.Running != nil
+		}
+	}
+	return false
+}
+
+// podReady returns whether or not a pod is ready.
+func podReady(pod *corev1.Pod) bool {
+	for _, condition := range pod.Status.Conditions {
+		if condition.Type == corev1.PodReady {
+			return condition.Status == corev1.ConditionTrue
+		}
+	}
+	return false
+}
+
+// selectPod selects the best candidate pod from a list of pods. Only running
+// pods that aren't being deleted and whose target container is running are
+// considered. Ready pods are preferred, followed by newer pods (which, during a
+// rollout, are the ones that will survive), with ties broken by name. It
+// returns nil if there are no candidate pods.
+func selectPod(pods []corev1.Pod, container string) *corev1.Pod {
+	var selected *corev1.Pod
+	for i := range pods {
+		pod := &pods[i]
+		if pod.Status.Phase != corev1.PodRunning || pod.DeletionTimestamp != nil ||
+			!containerRunning(pod, container) {
+			continue
+		}
+		if selected == nil {
+			selected = pod
+		} else if ready, selectedReady := podReady(pod), podReady(selected); ready != selectedReady {
+			if ready {
+				selected = pod
+			}
+		} else if !pod.CreationTimestamp.Equal(&selected.CreationTimestamp) {
+			if selected.CreationTimestamp.Before(&pod.CreationTimestamp) {
+				selected = pod
+			}
+		} else if pod.Name < selected.Name {
+			selected = pod
+		}
+	}
+	return selected
+}
+
+// resolvePod resolves the target pod name, verifying that the target container
+// exists and is running. Pods specified by deployment or label selector are
+// resolved on each call, so a new pod will be targeted if the previous pod has
+// been replaced (e.g. due to a rollout).
+func (c *Client) resolvePod(ctx context.Context, target *Target) (string, error) {
+	// Set up a timeout for API requests.
+	ctx, cancel := context.WithTimeout(ctx, apiRequestTimeout)
+	defer cancel()
+
+	// Handle pods specified by name.
+	if target.Pod != "" {
+		pod, err := c.clientset.CoreV1().Pods(target.Namespace).Get(ctx, target.Pod, metav1.GetOptions{})
+		if err != nil {
+			if apierrors.IsNotFound(err) {
+				return "", fmt.Errorf("pod %s not found in namespace %s", target.Pod, target.Namespace)
+			}
+			return "", describeAPIError("query pod", err)
+		}
+		if pod.Status.Phase != corev1.PodRunning {
+			return "", fmt.Errorf("pod %s is not running (phase: %s)", target.Pod, pod.Status.Phase)
+		}
+		for _, status := range pod.Status.ContainerStatuses {
+			if status.Name == target.Container {
+				if status.State.Running == nil {
+					return "", fmt.Errorf("container %s is not running", target.Container)
+				}
+				return pod.Name, nil
+			}
+		}
+		return "", fmt.Errorf("container %s not found in pod %s", target.Container, target.Pod)
+	}
+
+	// Compute the label selector and a description of its source.
+	var selector labels.Selector
+	var description string
+	if target.Deployment != "" {
+		deployment, err := c.clientset.AppsV1().Deployments(target.Namespace).Get(ctx, target.Deployment, metav1.GetOptions{})
+		if err != nil {
+			if apierrors.IsNotFound(err) {
+				return "", fmt.Errorf("deployment %s not found in namespace %s", target.Deployment, target.Namespace)
+			}
+			return "", describeAPIError("query deployment", err)
+		}
+		if selector, err = metav1.LabelSelectorAsSelector(deployment.Spec.Selector); err != nil {
+			return "", fmt.Errorf("invalid deployment selector: %w", err)
+		}
+		description = "deployment " + target.Deployment
+	} else if target.Selector != "" {
+		var err error
+		if selector, err = labels.Parse(target.Selector); err != nil {
+			return "", fmt.Errorf("invalid label selector: %w", err)
+		}
+		description = "selector " + target.Selector
+	} else {
+		return "", errors.New("no pod specified")
+	}
+
+	// List matching pods and select the best candidate.
+	pods, err := c.clientset.CoreV1().Pods(target.Namespace).List(ctx, metav1.ListOptions{
+		LabelSelector: selector.String(),
+	})
+	if err != nil {
+		return "", describeAPIError("list pods", err)
+	}
+	if pod := selectPod(pods.Items, target.Container); pod != nil {
+		return pod.Name, nil
+	}
+	return "", fmt.Errorf("no running pods with a running %s container match %s in namespace %s",
+		target.Container, description, target.Namespace,
+	)
+}
+
+// trackingReader is an io.Reader that records whether or not it's been read.
+type trackingReader struct {
+	// reader is the underlying reader.
+	reader io.Reader
+	// read is set to a non-zero value once a read has been attempted.
+	read uint32
+}
+
+// Read implements io.Reader.Read.
+func (r *trackingReader) Read(buffer []byte) (int, error) {
+	atomic.StoreUint32(&r.read, 1)
+	return r.reader.Read(buffer)
+}
+
+// trackingWriter is an io.Writer that records whether or not it's been written.
+type trackingWriter struct {
+	// writer is the underlying writer.
+	writer io.Writer
+	// written is set to a non-zero value once a write has been attempted.
+	written uint32
+}
+
+// Write implements io.Writer.Write.
+func (w *trackingWriter) Write(buffer []byte) (int, error) {
+	atomic.StoreUint32(&w.written, 1)
+	return w.writer.Write(buffer)
+}
+
+// Run resolves the target pod and executes a command inside the target
+// container, streaming the provided input and output. The command is executed directly (i.e. not via a shell). If
+// stdin is nil, then no input is provided. It returns the exit status of the
+// command if it could be determined, otherwise an error.
+func (c *Client) Run(ctx context.Context, target *Target, command []string, stdin io.Reader, stdout, stderr io.Writer) (int, error) {
+	// Resolve the target pod.
+	pod, err := c.resolvePod(ctx, target)
+	if err != nil {
+		return 0, err
+	}
+
+	// Compute the exec endpoint.
+	endpoint := c.clientset.CoreV1().RESTClient().Post().
+		Namespace(target.Namespace).
+		Resource("pods").
+		Name(pod).
+		SubResource("exec").
+		VersionedParams(&corev1.PodExecOptions{
+			Container: target.Container,
+			Command:   command,
+			Stdin:     stdin != nil,
+			Stdout:    true,
+			Stderr:    true,
+		}, scheme.ParameterCodec).
+		URL()
+
+	// Wrap the streams so that we can determine whether or not any I/O took
+	// place before a failure.
+	var trackedStdin *trackingReader
+	options := remotecommand.StreamOptions{}
+	if stdin != nil {
+		trackedStdin = &trackingReader{reader: stdin}
+		options.Stdin = trackedStdin
+	}
+	trackedStdout := &trackingWriter{writer: stdout}
+	trackedStderr := &trackingWriter{writer: stderr}
+	options.Stdout = trackedStdout
+	options.Stderr = trackedStderr
+
+	// Attempt to execute the command over SPDY.
+	executor, err := remotecommand.NewSPDYExecutor(c.configuration, "POST", endpoint)
+	if err != nil {
+		return 0, fmt.Errorf("unable to create SPDY executor: %w", err)
+	}
+	err = executor.Stream(options)
+
+	// If SPDY failed (for a reason other than the command exiting) before any
+	// I/O took place, then the connection couldn't be upgraded (e.g. due to an
+	// intermediate proxy or a cluster that no longer supports SPDY), so fall
+	// back to WebSocket streams.
+	var exitErr utilexec.ExitError
+	if err != nil && !errors.As(err, &exitErr) &&
+		atomic.LoadUint32(&trackedStdout.written) == 0 &&
+		atomic.LoadUint32(&trackedStderr.written) == 0 &&
+		(trackedStdin == nil || atomic.LoadUint32(&trackedStdin.read) == 0) {
+		var upgradeErr *webSocketUpgradeError
+		if webSocketErr := streamWebSocket(ctx, c.configuration, endpoint, stdin, stdout, stderr); webSocketErr == nil {
+			err = nil
+		} else if errors.As(webSocketErr, &upgradeErr) {
+			err = fmt.Errorf("%w (WebSocket fallback failed: %v)", err, webSocketErr)
+		} else {
+			err = webSocketErr
+		}
+	}
+
+	// Extract the exit status.
+	if err != nil {
+		if errors.As(err, &exitErr) {
+			return exitErr.ExitStatus(), nil
+		}
+		return 0, err
+	}
+	return 0, nil
+}
@@ -0,0 +1,504 @@
+package kubernetes
+
+import (
+	"bytes"
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"encoding/pem"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gorilla/websocket"
+
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	remotecommandconsts "k8s.io/apimachinery/pkg/util/remotecommand"
+)
+
+// testToken is the bearer token expected by the fake API server.
+const testToken = "test-token"
+
+// testKubeconfig is the kubeconfig template used for tests. It contains a
+// (default) context for the fake API server and a context with invalid
+// credentials.
+const testKubeconfig = `apiVersion: v1
+kind: Config
+clusters:
+- name: test
+  cluster:
+    server: %[1]s
+    certificate-authority-data: %[2]s
+users:
+- name: valid
+  user:
+    token: %[3]s
+- name: invalid
+  user:
+    token: invalid
+contexts:
+- name: test
+  context:
+    cluster: test
+    user: valid
+- name: unauthorized
+  context:
+    cluster: test
+    user: invalid
+current-context: test
+`
+
+// writeStatus writes an API status object as an HTTP response.
+func writeStatus(writer http.ResponseWriter, code int, reason metav1.StatusReason, message string) {
+	writer.Header().Set("Content-Type", "application/json")
+	writer.WriteHeader(code)
+	json.NewEncoder(writer).Encode(&metav1.Status{
+		TypeMeta: metav1.TypeMeta{Kind: "Status", APIVersion: "v1"},
+		Status:   metav1.StatusFailure,
+		Message:  message,
+		Reason:   reason,
+		Code:     int32(code),
+	})
+}
+
+// fakeAPIServer is a minimal Kubernetes API server implementation that serves
+// pods and executes a small set of commands over WebSocket streams. It rejects
+// SPDY upgrades in order to exercise the WebSocket fallback.
+type fakeAPIServer struct {
+	// pods maps pod names in the default namespace to pods.
+	pods map[string]*corev1.Pod
+	// deployments maps deployment names in the default namespace to
+	// deployments.
+	deployments map[string]*appsv1.Deployment
+}
+
+// writeObject writes an API object as an HTTP response.
+func writeObject(writer http.ResponseWriter, object interface{}) {
+	writer.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(writer).Encode(object)
+}
+
+// ServeHTTP implements http.Handler.ServeHTTP.
+func (s *fakeAPIServer) ServeHTTP(writer http.ResponseWriter, request *http.Request) {
+	// Verify credentials.
+	if request.Header.Get("Authorization") != "Bearer "+testToken {
+		writeStatus(writer, http.StatusUnauthorized, metav1.StatusReasonUnauthorized, "Unauthorized")
+		return
+	}
+
+	// Handle deployment queries.
+	if name := strings.TrimPrefix(request.URL.Path, "/apis/apps/v1/namespaces/default/deployments/"); name != request.URL.Path {
+		if deployment, ok := s.deployments[name]; ok {
+			writeObject(writer, deployment)
+		} else {
+			writeStatus(writer, http.StatusNotFound, metav1.StatusReasonNotFound, fmt.Sprintf("deployments %q not found", name))
+		}
+		return
+	}
+
+	// Handle pod listings.
+	if request.URL.Path == "/api/v1/namespaces/default/pods" {
+		selector, err := labels.Parse(request.URL.Query().Get("labelSelector"))
+		if err != nil {
+			writeStatus(writer, http.StatusBadRequest, metav1.StatusReasonBadRequest, err.Error())
+			return
+		}
+		list := &corev1.PodList{TypeMeta: metav1.TypeMeta{Kind: "PodList", APIVersion: "v1"}}
+		for _, pod := range s.pods {
+			if selector.Matches(labels.Set(pod.Labels)) {
+				list.Items = append(list.Items, *pod)
+			}
+		}
+		writeObject(writer, list)
+		return
+	}
+
+	// Parse the path.
+	path := strings.TrimPrefix(request.URL.Path, "/api/v1/namespaces/default/pods/")
+	if path == request.URL.Path {
+		writeStatus(writer, http.StatusForbidden, metav1.StatusReasonForbidden, "namespace access denied")
+		return
+	}
+	name, subresource := path, ""
+	if index := strings.IndexByte(path, '/'); index >= 0 {
+		name, subresource = path[:index], path[index+1:]
+	}
+	pod, ok := s.pods[name]
+	if !ok {
+		writeStatus(writer, http.StatusNotFound, metav1.StatusReasonNotFound, fmt.Sprintf("pods %q not found", name))
+		return
+	}
+
+	// Handle pod queries.
+	if subresource == "" {
+		writeObject(writer, pod)
+		return
+	} else if subresource != "exec" {
+		writeStatus(writer, http.StatusNotFound, metav1.StatusReasonNotFound, "unknown subresource")
+		return
+	}
+
+	// Reject SPDY upgrades (and, for pods labeled accordingly, WebSocket
+	// upgrades).
+	if !websocket.IsWebSocketUpgrade(request) || pod.Labels["websocket"] == "unsupported" {
+		writeStatus(writer, http.StatusBadRequest, metav1.StatusReasonBadRequest, "upgrade not supported")
+		return
+	}
+
+	// Perform the WebSocket upgrade and defer closure of the connection.
+	upgrader := &websocket.Upgrader{Subprotocols: []string{webSocketProtocol}}
+	connection, err := upgrader.Upgrade(writer, request, nil)
+	if err != nil {
+		return
+	}
+	defer connection.Close()
+
+	// Execute the command.
+	var stdout, stderr []byte
+	status := &metav1.Status{Status: metav1.StatusSuccess}
+	command := request.URL.Query()["command"]
+	switch command[0] {
+	case "echo":
+		stdout = []byte(strings.Join(command[1:], " "))
+	case "hostname":
+		stdout = []byte(name)
+	case "cat":
+		for {
+			_, message, err := connection.ReadMessage()
+			if err != nil {
+				return
+			} else if len(message) == 0 {
+				continue
+			} else if message[0] == webSocketChannelStdin {
+				stdout = append(stdout, message[1:]...)
+			} else if bytes.Equal(message, []byte{webSocketChannelClose, webSocketChannelStdin}) {
+				break
+			}
+		}
+	case "fail":
+		stderr = []byte("failure")
+		status = &metav1.Status{
+			Status:  metav1.StatusFailure,
+			Message: "command terminated with exit code 3",
+			Reason:  remotecommandconsts.NonZeroExitCodeReason,
+			Details: &metav1.StatusDetails{
+				Causes: []metav1.StatusCause{{Type: remotecommandconsts.ExitCodeCauseType, Message: "3"}},
+			},
+		}
+	default:
+		status = &metav1.Status{
+			Status:  metav1.StatusFailure,
+			Message: fmt.Sprintf("exec: %q: executable file not found in $PATH", command[0]),
+		}
+	}
+
+	// Transmit the results and close the connection.
+	if len(stdout) > 0 {
+		connection.WriteMessage(websocket.BinaryMessage, append([]byte{webSocketChannelStdout}, stdout...))
+	}
+	if len(stderr) > 0 {
+		connection.WriteMessage(websocket.BinaryMessage, append([]byte{webSocketChannelStderr}, stderr...))
+	}
+	encodedStatus, _ := json.Marshal(status)
+	connection.WriteMessage(websocket.BinaryMessage, append([]byte{webSocketChannelError}, encodedStatus...))
+	connection.WriteMessage(websocket.CloseMessage, websocket.FormatCloseMessage(websocket.CloseNormalClosure, ""))
+}
+
+// newTestPod creates a pod with the specified name, phase, labels, and
+// container states.
+func newTestPod(name string, phase corev1.PodPhase, labels map[string]string, containers map[string]bool) *corev1.Pod {
+	pod := &corev1.Pod{
+		TypeMeta:   metav1.TypeMeta{Kind: "Pod", APIVersion: "v1"},
+		ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: "default", Labels: labels},
+		Status:     corev1.PodStatus{Phase: phase},
+	}
+	for container, running := range containers {
+		status := corev1.ContainerStatus{Name: container}
+		if running {
+			status.State.Running = &corev1.ContainerStateRunning{}
+		} else {
+			status.State.Waiting = &corev1.ContainerStateWaiting{Reason: "CrashLoopBackOff"}
+		}
+		pod.Status.ContainerStatuses = append(pod.Status.ContainerStatuses, status)
+	}
+	return pod
+}
+
+// newRolloutPod creates a running pod belonging to the "api" deployment with
+// the specified name, readiness, creation time (in hours since an arbitrary
+// epoch), and deletion state.
+func newRolloutPod(name string, ready bool, created int, deleted bool) *corev1.Pod {
+	pod := newTestPod(name, corev1.PodRunning, map[string]string{"app": "api"}, map[string]bool{"app": true})
+	pod.CreationTimestamp = metav1.NewTime(time.Date(2020, 1, 1, created, 0, 0, 0, time.UTC))
+	if ready {
+		pod.Status.Conditions = []corev1.PodCondition{{Type: corev1.PodReady, Status: corev1.ConditionTrue}}
+	}
+	if deleted {
+		pod.DeletionTimestamp = &pod.CreationTimestamp
+	}
+	return pod
+}
+
+// setupFakeAPIServer starts a fake API server and creates a kubeconfig file
+// that targets it, setting KUBECONFIG accordingly. The server hosts an "api"
+// deployment whose pods are mid-rollout, with "api-new" being the pod that
+// should be targeted.
+func setupFakeAPIServer(t *testing.T) {
+	t.Helper()
+
+	// Start the server. Credentials are only used with TLS, so we need a TLS
+	// server.
+	server := httptest.NewTLSServer(&fakeAPIServer{
+		pods: map[string]*corev1.Pod{
+			"web-0": newTestPod("web-0", corev1.PodRunning, nil, map[string]bool{
+				"app": true, "sidecar": false,
+			}),
+			"pending": newTestPod("pending", corev1.PodPending, nil, nil),
+			"legacy": newTestPod("legacy", corev1.PodRunning, map[string]string{
+				"websocket": "unsupported",
+			}, map[string]bool{"app": true}),
+			"api-old":      newRolloutPod("api-old", true, 1, true),
+			"api-new":      newRolloutPod("api-new", true, 2, false),
+			"api-starting": newRolloutPod("api-starting", false, 3, false),
+		},
+		deployments: map[string]*appsv1.Deployment{
+			"api": {
+				TypeMeta:   metav1.TypeMeta{Kind: "Deployment", APIVersion: "apps/v1"},
+				ObjectMeta: metav1.ObjectMeta{Name: "api", Namespace: "default"},
+				Spec: appsv1.DeploymentSpec{
+					Selector: &metav1.LabelSelector{MatchLabels: map[string]string{"app": "api"}},
+				},
+			},
+			"idle": {
+				TypeMeta:   metav1.TypeMeta{Kind: "Deployment", APIVersion: "apps/v1"},
+				ObjectMeta: metav1.ObjectMeta{Name: "idle", Namespace: "default"},
+				Spec: appsv1.DeploymentSpec{
+					Selector: &metav1.LabelSelector{MatchLabels: map[string]string{"app": "idle"}},
+				},
+			},
+		},
+	})
+	t.Cleanup(server.Close)
+
+	// Write the kubeconfig.
+	kubeconfig := filepath.Join(t.TempDir(), "config")
+	authority := pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: server.Certificate().Raw})
+	contents := fmt.Sprintf(testKubeconfig,
+		server.URL, base64.StdEncoding.EncodeToString(authority), testToken,
+	)
+	if err := os.WriteFile(kubeconfig, []byte(contents), 0600); err != nil {
+		t.Fatal("unable to write kubeconfig:", err)
+	}
+	t.Setenv("KUBECONFIG", kubeconfig)
+}
+
+// TestNewClient tests kubeconfig loading and context selection.
+func TestNewClient(t *testing.T) {
+	// Ensure that in-cluster configuration isn't used.
+	t.Setenv("KUBERNETES_SERVICE_HOST", "")
+	t.Setenv("HOME", t.TempDir())
+
+	// Verify that a missing configuration is reported.
+	t.Setenv("KUBECONFIG", filepath.Join(t.TempDir(), "nonexistent"))
+	if _, err := NewClient(""); err == nil {
+		t.Error("client creation succeeded without configuration")
+	} else if !strings.Contains(err.Error(), "no Kubernetes configuration found") {
+		t.Error("missing configuration error has unexpected message:", err)
+	}
+
+	// Verify context handling.
+	setupFakeAPIServer(t)
+	for _, context := range []string{"", "test", "unauthorized"} {
+		if _, err := NewClient(context); err != nil {
+			t.Errorf("unable to create client with context %q: %v", context, err)
+		}
+	}
+	if _, err := NewClient("nonexistent"); err == nil {
+		t.Error("client creation succeeded with nonexistent context")
+	} else if !strings.Contains(err.Error(), "invalid Kubernetes configuration") {
+		t.Error("nonexistent context error has unexpected message:", err)
+	}
+}
+
+// TestRun tests command execution and error classification.
+func TestRun(t *testing.T) {
+	setupFakeAPIServer(t)
+
+	// Set up test cases.
+	testCases := []struct {
+		// context is the kubeconfig context to use.
+		context string
+		// pod is the target pod.
+		pod string
+		// container is the target container.
+		container string
+		// command is the command to run.
+		command []string
+		// stdin is the standard input to provide, if any.
+		stdin string
+		// expectedStatus is the expected exit status.
+		expectedStatus int
+		// expectedStdout is the expected standard output.
+		expectedStdout string
+		// expectedStderr is the expected standard error.
+		expectedStderr string
+		// expectedErrors are fragments expected to appear in the error message.
+		// If empty, then no error is expected.
+		expectedErrors []string
+	}{
+		{"", "web-0", "app", []string{"echo", "hello", "world"}, "", 0, "hello world", "", nil},
+		{"", "web-0", "app", []string{"cat"}, "input", 0, "input", "", nil},
+		{"", "web-0", "app", []string{"fail"}, "", 3, "", "failure", nil},
+		{"", "web-0", "app", []string{"sh"}, "", 0, "", "", []string{"executable file not found"}},
+		{"", "missing", "app", []string{"echo"}, "", 0, "", "", []string{"pod missing not found in namespace default"}},
+		{"", "web-0", "missing", []string{"echo"}, "", 0, "", "", []string{"container missing not found in pod web-0"}},
+		{"", "web-0", "sidecar", []string{"echo"}, "", 0, "", "", []string{"container sidecar is not running"}},
+		{"", "pending", "app", []string{"echo"}, "", 0, "", "", []string{"pod pending is not running (phase: Pending)"}},
+		{"", "legacy", "app", []string{"echo"}, "", 0, "", "", []string{"upgrade not supported", "WebSocket fallback failed"}},
+		{"unauthorized", "web-0", "app", []string{"echo"}, "", 0, "", "", []string{"unauthorized"}},
+	}
+
+	// Process test cases.
+	for i, testCase := range testCases {
+		// Create the client.
+		client, err := NewClient(testCase.context)
+		if err != nil {
+			t.Fatalf("test index %d: unable to create client: %v", i, err)
+		}
+
+		// Run the command.
+		target := &Target{Namespace: "default", Pod: testCase.pod, Container: testCase.container}
+		var stdin io.Reader
+		if testCase.stdin != "" {
+			stdin = strings.NewReader(testCase.stdin)
+		}
+		stdout, stderr := &bytes.Buffer{}, &bytes.Buffer{}
+		status, err := client.Run(context.Background(), target, testCase.command, stdin, stdout, stderr)
+
+		// Check the results.
+		if len(testCase.expectedErrors) > 0 {
+			if err == nil {
+				t.Errorf("test index %d: command succeeded unexpectedly", i)
+				continue
+			}
+			for _, fragment := range testCase.expectedErrors {
+				if !strings.Contains(err.Error(), fragment) {
+					t.Errorf("test index %d: error does not contain %q: %v", i, fragment, err)
+				}
+			}
+			continue
+		} else if err != nil {
+			t.Errorf("test index %d: unable to run command: %v", i, err)
+			continue
+		}
+		if status != testCase.expectedStatus {
+			t.Errorf("test index %d: exit status does not match expected: %d != %d", i, status, testCase.expectedStatus)
+		}
+		if stdout.String() != testCase.expectedStdout {
+			t.Errorf("test index %d: standard output does not match expected: %q != %q", i, stdout.String(), testCase.expectedStdout)
+		}
+		if stderr.String() != testCase.expectedStderr {
+			t.Errorf("test index %d: standard error does not match expected: %q != %q", i, stderr.String(), testCase.expectedStderr)
+		}
+	}
+}
+
+// TestRunPodResolution tests that pods specified by deployment or label
+// selector are resolved to the appropriate pod.
+func TestRunPodResolution(t *testing.T) {
+	setupFakeAPIServer(t)
+
+	// Create the client.
+	client, err := NewClient("")
+	if err != nil {
+		t.Fatal("unable to create client:", err)
+	}
+
+	// Set up test cases.
+	testCases := []struct {
+		// target is the target specification.
+		target *Target
+		// expectedPod is the expected resolved pod name.
+		expectedPod string
+		// expectedError is a fragment expected to appear in the error message.
+		// If empty, then no error is expected.
+		expectedError string
+	}{
+		{&Target{Pod: "web-0", Container: "app"}, "web-0", ""},
+		{&Target{Deployment: "api", Container: "app"}, "api-new", ""},
+		{&Target{Selector: "app=api", Container: "app"}, "api-new", ""},
+		{&Target{Selector: "app in (api, web)", Container: "app"}, "api-new", ""},
+		{&Target{Deployment: "missing", Container: "app"}, "", "deployment missing not found in namespace default"},
+		{&Target{Deployment: "idle", Container: "app"}, "", "no running pods with a running app container match deployment idle"},
+		{&Target{Selector: "app=api", Container: "sidecar"}, "", "no running pods with a running sidecar container match selector app=api"},
+		{&Target{Selector: "app in (", Container: "app"}, "", "invalid label selector"},
+		{&Target{Container: "app"}, "", "no pod specified"},
+	}
+
+	// Process test cases.
+	for i, testCase := range testCases {
+		testCase.target.Namespace = "default"
+		stdout := &bytes.Buffer{}
+		_, err := client.Run(context.Background(), testCase.target, []string{"hostname"}, nil, stdout, io.Discard)
+		if testCase.expectedError != "" {
+			if err == nil {
+				t.Errorf("test index %d: pod resolution succeeded unexpectedly", i)
+			} else if !strings.Contains(err.Error(), testCase.expectedError) {
+				t.Errorf("test index %d: error does not contain %q: %v", i, testCase.expectedError, err)
+			}
+		} else if err != nil {
+			t.Errorf("test index %d: unable to run command: %v", i, err)
+		} else if pod := stdout.String(); pod != testCase.expectedPod {
+			t.Errorf("test index %d: resolved pod does not match expected: %s != %s", i, pod, testCase.expectedPod)
+		}
+	}
+}
+
+// TestSelectPod tests pod selection preferences.
+func TestSelectPod(t *testing.T) {
+	// Set up test cases.
+	pending := newTestPod("pending", corev1.PodPending, nil, map[string]bool{"app": true})
+	crashing := newTestPod("crashing", corev1.PodRunning, nil, map[string]bool{"app": false})
+	testCases := []struct {
+		// pods are the candidate pods.
+		pods []*corev1.Pod
+		// expected is the expected selected pod name. If empty, then no pod is
+		// expected to be selected.
+		expected string
+	}{
+		{nil, ""},
+		{[]*corev1.Pod{pending, crashing}, ""},
+		{[]*corev1.Pod{newRolloutPod("old", true, 1, true)}, ""},
+		{[]*corev1.Pod{newRolloutPod("a", false, 1, false), newRolloutPod("b", true, 1, false)}, "b"},
+		{[]*corev1.Pod{newRolloutPod("a", true, 1, false), newRolloutPod("b", false, 2, false)}, "a"},
+		{[]*corev1.Pod{newRolloutPod("a", true, 1, false), newRolloutPod("b", true, 2, false)}, "b"},
+		{[]*corev1.Pod{newRolloutPod("b", true, 1, false), newRolloutPod("a", true, 1, false)}, "a"},
+	}
+
+	// Process test cases.
+	for i, testCase := range testCases {
+		var pods []corev1.Pod
+		for _, pod := range testCase.pods {
+			pods = append(pods, *pod)
+		}
+		selected := selectPod(pods, "app")
+		if testCase.expected == "" {
+			if selected != nil {
+				t.Errorf("test index %d: pod selected unexpectedly: %s", i, selected.Name)
+			}
+		} else if selected == nil {
+			t.Errorf("test index %d: no pod selected", i)
+		} else if selected.Name != testCase.expected {
+			t.Errorf("test index %d: selected pod does not match expected: %s != %s", i, selected.Name, testCase.expected)
+		}
+	}
+}
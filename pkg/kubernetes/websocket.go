@@ -0,0 +1,256 @@
+package kubernetes
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+
+	"github.com/gorilla/websocket"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	remotecommandconsts "k8s.io/apimachinery/pkg/util/remotecommand"
+	"k8s.io/client-go/rest"
+	utilexec "k8s.io/client-go/util/exec"
+)
+
+const (
+	// webSocketProtocol is the WebSocket subprotocol used for exec streams. We
+	// require version 5 of the channel protocol because it's the first version
+	// that supports closing standard input (which our copy operations rely on).
+	// Any cluster that supports only earlier WebSocket protocol versions also
+	// supports SPDY.
+	webSocketProtocol = "v5.channel.k8s.io"
+
+	// webSocketChannelStdin is the channel identifier for standard input.
+	webSocketChannelStdin = 0
+	// webSocketChannelStdout is the channel identifier for standard output.
+	webSocketChannelStdout = 1
+	// webSocketChannelStderr is the channel identifier for standard error.
+	webSocketChannelStderr = 2
+	// webSocketChannelError is the channel identifier for the status channel.
+	webSocketChannelError = 3
+	// webSocketChannelClose is the channel identifier for stream closure
+	// signals, which carry the identifier of the channel to close.
+	webSocketChannelClose = 255
+
+	// webSocketStdinBufferSize is the buffer size used for reading standard
+	// input.
+	webSocketStdinBufferSize = 32 * 1024
+)
+
+// webSocketUpgradeError indicates that a WebSocket connection couldn't be
+// established.
+type webSocketUpgradeError struct {
+	// message is the error message.
+	message string
+}
+
+// Error implements error.Error.
+func (e *webSocketUpgradeError) Error() string {
+	return "unable to upgrade connection: " + e.message
+}
+
+// webSocketRoundTripper is an http.RoundTripper that establishes a WebSocket
+// connection for a request. It allows the standard client-go authentication
+// wrappers to be applied to the WebSocket handshake.
+type webSocketRoundTripper struct {
+	// dialer is the WebSocket dialer.
+	dialer *websocket.Dialer
+	// connection is the established connection.
+	connection *websocket.Conn
+}
+
+// RoundTrip implements http.RoundTripper.RoundTrip.
+func (t *webSocketRoundTripper) RoundTrip(request *http.Request) (*http.Response, error) {
+	// Convert the URL to a WebSocket URL.
+	target := *request.URL
+	switch target.Scheme {
+	case "https":
+		target.Scheme = "wss"
+	case "http":
+		target.Scheme = "ws"
+	}
+
+	// Perform the handshake. On handshake failure, the server's response body
+	// will usually contain an API status object describing the problem.
+	connection, response, err := t.dialer.DialContext(request.Context(), target.String(), request.Header)
+	if err != nil {
+		if errors.Is(err, websocket.ErrBadHandshake) && response != nil {
+			message := response.Status
+			if body, readErr := io.ReadAll(response.Body); readErr == nil {
+				var status metav1.Status
+				if json.Unmarshal(body, &status) == nil && status.Message != "" {
+					message = status.Message
+				} else if trimmed := strings.TrimSpace(string(body)); trimmed != "" {
+					message = trimmed
+				}
+			}
+			return nil, &webSocketUpgradeError{message}
+		}
+		return nil, &webSocketUpgradeError{err.Error()}
+	}
+	t.connection = connection
+
+	// Success.
+	return response, nil
+}
+
+// dialWebSocket establishes an exec WebSocket connection to the specified
+// endpoint using the specified configuration.
+func dialWebSocket(ctx context.Context, configuration *rest.Config, endpoint *url.URL) (*websocket.Conn, error) {
+	// Set up the dialer.
+	tlsConfig, err := rest.TLSConfigFor(configuration)
+	if err != nil {
+		return nil, fmt.Errorf("unable to create TLS configuration: %w", err)
+	}
+	proxy := configuration.Proxy
+	if proxy == nil {
+		proxy = http.ProxyFromEnvironment
+	}
+	transport := &webSocketRoundTripper{
+		dialer: &websocket.Dialer{
+			Proxy:            proxy,
+			TLSClientConfig:  tlsConfig,
+			Subprotocols:     []string{webSocketProtocol},
+			HandshakeTimeout: apiRequestTimeout,
+		},
+	}
+
+	// Wrap the transport with authentication handling.
+	wrapped, err := rest.HTTPWrappersForConfig(configuration, transport)
+	if err != nil {
+		return nil, fmt.Errorf("unable to configure authentication: %w", err)
+	}
+
+	// Perform the handshake.
+	request, err := http.NewRequestWithContext(ctx, http.MethodGet, endpoint.String(), nil)
+	if err != nil {
+		return nil, fmt.Errorf("unable to create request: %w", err)
+	}
+	if _, err := wrapped.RoundTrip(request); err != nil {
+		return nil, err
+	} else if transport.connection == nil {
+		return nil, &webSocketUpgradeError{"connection not established"}
+	}
+
+	// Verify the negotiated protocol.
+	if protocol := transport.connection.Subprotocol(); protocol != webSocketProtocol {
+		transport.connection.Close()
+		return nil, &webSocketUpgradeError{fmt.Sprintf("unsupported stream protocol: %q", protocol)}
+	}
+
+	// Success.
+	return transport.connection, nil
+}
+
+// decodeWebSocketStatus decodes the status object sent on the error channel.
+func decodeWebSocketStatus(data []byte) error {
+	// An absent status indicates success.
+	if len(data) == 0 {
+		return nil
+	}
+
+	// Decode the status.
+	var status metav1.Status
+	if err := json.Unmarshal(data, &status); err != nil {
+		return fmt.Errorf("unable to decode exec status: %w", err)
+	}
+
+	// Handle success.
+	if status.Status == metav1.StatusSuccess {
+		return nil
+	}
+
+	// Extract non-zero exit codes.
+	if status.Reason == remotecommandconsts.NonZeroExitCodeReason && status.Details != nil {
+		for _, cause := range status.Details.Causes {
+			if cause.Type != remotecommandconsts.ExitCodeCauseType {
+				continue
+			}
+			code, err := strconv.Atoi(cause.Message)
+			if err != nil {
+				return fmt.Errorf("invalid exit code: %s", cause.Message)
+			}
+			return utilexec.CodeExitError{
+				Err:  fmt.Errorf("command terminated with non-zero exit code: %s", status.Message),
+				Code: code,
+			}
+		}
+	}
+
+	// Handle other failures.
+	return fmt.Errorf("error executing command in container: %s", status.Message)
+}
+
+// streamWebSocket executes a command using a WebSocket connection to the
+// specified exec endpoint.
+func streamWebSocket(ctx context.Context, configuration *rest.Config, endpoint *url.URL, stdin io.Reader, stdout, stderr io.Writer) error {
+	// Establish the connection and defer its closure.
+	connection, err := dialWebSocket(ctx, configuration, endpoint)
+	if err != nil {
+		return err
+	}
+	defer connection.Close()
+
+	// Forward standard input. The connection is only written to by this
+	// Goroutine, so no additional synchronization is required. Once standard
+	// input is exhausted, we signal its closure to the remote.
+	if stdin != nil {
+		go func() {
+			buffer := make([]byte, webSocketStdinBufferSize+1)
+			buffer[0] = webSocketChannelStdin
+			for {
+				n, err := stdin.Read(buffer[1:])
+				if n > 0 {
+					if connection.WriteMessage(websocket.BinaryMessage, buffer[:n+1]) != nil {
+						return
+					}
+				}
+				if err != nil {
+					if err == io.EOF {
+						connection.WriteMessage(websocket.BinaryMessage, []byte{
+							webSocketChannelClose, webSocketChannelStdin,
+						})
+					}
+					return
+				}
+			}
+		}()
+	}
+
+	// Demultiplex output until the remote closes the connection.
+	var status []byte
+	for {
+		_, message, err := connection.ReadMessage()
+		if err != nil {
+			if websocket.IsCloseError(err, websocket.CloseNormalClosure) || errors.Is(err, io.EOF) {
+				break
+			}
+			return fmt.Errorf("unable to read from stream: %w", err)
+		}
+		if len(message) == 0 {
+			continue
+		}
+		switch message[0] {
+		case webSocketChannelStdout:
+			if _, err := stdout.Write(message[1:]); err != nil {
+				return fmt.Errorf("unable to write standard output: %w", err)
+			}
+		case webSocketChannelStderr:
+			if _, err := stderr.Write(message[1:]); err != nil {
+				return fmt.Errorf("unable to write standard error: %w", err)
+			}
+		case webSocketChannelError:
+			status = append(status, message[1:]...)
+		}
+	}
+
+	// Decode the final status.
+	return decodeWebSocketStatus(status)
+}
@@ -0,0 +1,3 @@
+// Package lxd provides utility functions for interfacing with the LXD and Incus
+// command line clients.
+package lxd
@@ -0,0 +1,56 @@
+package lxd
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+
+	"github.com/mutagen-io/mutagen/pkg/process"
+)
+
+// commandNames are the names of the supported command line clients, in order of
+// preference. The Incus client is a fork of the LXD client and supports the
+// same commands.
+var commandNames = []string{"lxc", "incus"}
+
+// CommandPath returns the absolute path specification to use for invoking the
+// LXD command line client. It will use the MUTAGEN_LXD_PATH environment
+// variable if provided, otherwise falling back to a search of the user's path.
+// In either case, the LXD client (lxc) is preferred over the Incus client
+// (incus) if both are present.
+func CommandPath() (string, error) {
+	// Determine the search path, if any.
+	searchPath := os.Getenv("MUTAGEN_LXD_PATH")
+
+	// Search for each command.
+	for _, name := range commandNames {
+		var path string
+		var err error
+		if searchPath != "" {
+			path, err = process.FindCommand(name, []string{searchPath})
+		} else {
+			path, err = exec.LookPath(name)
+		}
+		if err == nil {
+			return path, nil
+		}
+	}
+
+	// Failure.
+	return "", errors.New("no LXD client found")
+}
+
+// Command prepares (but does not start) an LXD client command with the
+// specified arguments and scoped to lifetime of the provided context.
+func Command(ctx context.Context, args ...string) (*exec.Cmd, error) {
+	// Identify the command path.
+	commandPath, err := CommandPath()
+	if err != nil {
+		return nil, fmt.Errorf("unable to identify 'lxc' or 'incus' command: %w", err)
+	}
+
+	// Create the command.
+	return exec.CommandContext(ctx, commandPath, args...), nil
+}
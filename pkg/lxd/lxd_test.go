@@ -0,0 +1,88 @@
+package lxd
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"runtime"
+	"testing"
+
+	"github.com/mutagen-io/mutagen/pkg/process"
+)
+
+// createExecutables creates empty executables with the specified names in a
+// temporary directory and returns the directory path.
+func createExecutables(t *testing.T, names ...string) string {
+	t.Helper()
+	directory := t.TempDir()
+	for _, name := range names {
+		executable := filepath.Join(directory, process.ExecutableName(name, runtime.GOOS))
+		if err := os.WriteFile(executable, nil, 0700); err != nil {
+			t.Fatal("unable to create executable:", err)
+		}
+	}
+	return directory
+}
+
+// TestCommandPathOverride tests that CommandPath respects the MUTAGEN_LXD_PATH
+// environment variable and its client preference order.
+func TestCommandPathOverride(t *testing.T) {
+	// Set up test cases.
+	testCases := []struct {
+		// present are the clients present in the search path.
+		present []string
+		// expected is the expected client, or an empty string if no client
+		// should be found.
+		expected string
+	}{
+		{nil, ""},
+		{[]string{"lxc"}, "lxc"},
+		{[]string{"incus"}, "incus"},
+		{[]string{"lxc", "incus"}, "lxc"},
+	}
+
+	// Process test cases.
+	for i, testCase := range testCases {
+		directory := createExecutables(t, testCase.present...)
+		t.Setenv("MUTAGEN_LXD_PATH", directory)
+		path, err := CommandPath()
+		if testCase.expected == "" {
+			if err == nil {
+				t.Errorf("test index %d: command path found unexpectedly: %s", i, path)
+			}
+			continue
+		} else if err != nil {
+			t.Errorf("test index %d: unable to find command: %v", i, err)
+			continue
+		}
+		expected := filepath.Join(directory, process.ExecutableName(testCase.expected, runtime.GOOS))
+		if path != expected {
+			t.Errorf("test index %d: command path does not match expected: %s != %s", i, path, expected)
+		}
+	}
+}
+
+// TestCommand tests that Command uses the resolved client and passes through
+// arguments.
+func TestCommand(t *testing.T) {
+	// Create a search path containing only the Incus client.
+	directory := createExecutables(t, "incus")
+	t.Setenv("MUTAGEN_LXD_PATH", directory)
+	executable := filepath.Join(directory, process.ExecutableName("incus", runtime.GOOS))
+
+	// Verify command construction.
+	command, err := Command(context.Background(), "exec", "instance")
+	if err != nil {
+		t.Fatal("unable to create command:", err)
+	} else if command.Path != executable {
+		t.Error("command path does not match expected:", command.Path, "!=", executable)
+	} else if len(command.Args) != 3 || command.Args[1] != "exec" || command.Args[2] != "instance" {
+		t.Error("command arguments do not match expected:", command.Args)
+	}
+
+	// Verify that command construction fails without a client.
+	t.Setenv("MUTAGEN_LXD_PATH", t.TempDir())
+	if _, err := Command(context.Background()); err == nil {
+		t.Error("command created unexpectedly")
+	}
+}
@@ -0,0 +1,3 @@
+// Package nerdctl provides utility functions for interfacing with nerdctl, the
+// Docker-compatible command line interface for containerd.
+package nerdctl
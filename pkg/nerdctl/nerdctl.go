@@ -0,0 +1,36 @@
+package nerdctl
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/exec"
+
+	"github.com/mutagen-io/mutagen/pkg/process"
+)
+
+// CommandPath returns the absolute path specification to use for invoking
+// nerdctl. It will use the MUTAGEN_NERDCTL_PATH environment variable if
+// provided, otherwise falling back to a platform-specific implementation.
+func CommandPath() (string, error) {
+	// If MUTAGEN_NERDCTL_PATH is specified, then use it to perform the lookup.
+	if searchPath := os.Getenv("MUTAGEN_NERDCTL_PATH"); searchPath != "" {
+		return process.FindCommand("nerdctl", []string{searchPath})
+	}
+
+	// Otherwise fall back to the platform-specific implementation.
+	return commandPathForPlatform()
+}
+
+// Command prepares (but does not start) a nerdctl command with the specified
+// arguments and scoped to lifetime of the provided context.
+func Command(ctx context.Context, args ...string) (*exec.Cmd, error) {
+	// Identify the command path.
+	commandPath, err := CommandPath()
+	if err != nil {
+		return nil, fmt.Errorf("unable to identify 'nerdctl' command: %w", err)
+	}
+
+	// Create the command.
+	return exec.CommandContext(ctx, commandPath, args...), nil
+}
@@ -0,0 +1,37 @@
+package nerdctl
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+
+	"github.com/mutagen-io/mutagen/pkg/process"
+)
+
+// commandSearchPaths specifies locations on macOS where we might find the
+// nerdctl binary.
+var commandSearchPaths = []string{
+	"/usr/local/bin",
+	"/opt/homebrew/bin",
+}
+
+// commandPathForPlatform will search for a suitable nerdctl command
+// implementation on macOS.
+func commandPathForPlatform() (string, error) {
+	// First, attempt to find the nerdctl executable using the PATH environment
+	// variable. If that works, use that result.
+	if path, err := exec.LookPath("nerdctl"); err == nil {
+		return path, nil
+	}
+
+	// If the PATH-based lookup fails, attempt to search a set of common
+	// locations where nerdctl installations reside on macOS. As with Docker,
+	// this is necessary because launchd strips most entries from the PATH
+	// environment variable. Rancher Desktop installs nerdctl into a directory
+	// inside the user's home directory, so we search that as well.
+	searchPaths := commandSearchPaths
+	if home, err := os.UserHomeDir(); err == nil {
+		searchPaths = append([]string{filepath.Join(home, ".rd", "bin")}, searchPaths...)
+	}
+	return process.FindCommand("nerdctl", searchPaths)
+}
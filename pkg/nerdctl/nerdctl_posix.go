@@ -0,0 +1,12 @@
+//go:build !windows && !darwin
+
+package nerdctl
+
+import (
+	"os/exec"
+)
+
+// commandPathForPlatform searches for the nerdctl command in the user's path.
+func commandPathForPlatform() (string, error) {
+	return exec.LookPath("nerdctl")
+}
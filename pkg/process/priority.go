@@ -0,0 +1,7 @@
+package process
+
+const (
+	// MaximumNiceness is the maximum niceness value supported by
+	// SetNiceness.
+	MaximumNiceness = 19
+)
@@ -0,0 +1,42 @@
+package process
+
+import (
+	"fmt"
+	"os"
+	"strconv"
+
+	"golang.org/x/sys/unix"
+)
+
+// SetNiceness sets the scheduling niceness of the current process. On Linux,
+// niceness is a per-thread attribute, so the niceness is applied to every
+// existing thread in the process (threads created later will inherit it from
+// their creating thread). Because the default I/O priority for a thread is
+// derived from its niceness, this also lowers I/O priority.
+func SetNiceness(niceness int) error {
+	// Validate the niceness.
+	if niceness < 0 || niceness > MaximumNiceness {
+		return fmt.Errorf("niceness (%d) outside supported range", niceness)
+	}
+
+	// Enumerate the threads in the current process.
+	tasks, err := os.ReadDir("/proc/self/task")
+	if err != nil {
+		return fmt.Errorf("unable to enumerate threads: %w", err)
+	}
+
+	// Set the niceness for each thread. Threads may exit while we're doing
+	// this, so we ignore failures due to missing threads.
+	for _, task := range tasks {
+		tid, err := strconv.Atoi(task.Name())
+		if err != nil {
+			continue
+		}
+		if err := unix.Setpriority(unix.PRIO_PROCESS, tid, niceness); err != nil && err != unix.ESRCH {
+			return fmt.Errorf("unable to set thread niceness: %w", err)
+		}
+	}
+
+	// Success.
+	return nil
+}
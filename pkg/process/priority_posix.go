@@ -0,0 +1,25 @@
+//go:build !windows && !linux
+
+package process
+
+import (
+	"fmt"
+
+	"golang.org/x/sys/unix"
+)
+
+// SetNiceness sets the scheduling niceness of the current process.
+func SetNiceness(niceness int) error {
+	// Validate the niceness.
+	if niceness < 0 || niceness > MaximumNiceness {
+		return fmt.Errorf("niceness (%d) outside supported range", niceness)
+	}
+
+	// Set the niceness.
+	if err := unix.Setpriority(unix.PRIO_PROCESS, 0, niceness); err != nil {
+		return fmt.Errorf("unable to set process niceness: %w", err)
+	}
+
+	// Success.
+	return nil
+}
@@ -0,0 +1,15 @@
+package process
+
+import (
+	"testing"
+)
+
+// TestSetNicenessInvalid tests that SetNiceness rejects out-of-range values.
+func TestSetNicenessInvalid(t *testing.T) {
+	if SetNiceness(-1) == nil {
+		t.Error("negative niceness accepted")
+	}
+	if SetNiceness(MaximumNiceness+1) == nil {
+		t.Error("excessive niceness accepted")
+	}
+}
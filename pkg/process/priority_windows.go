@@ -0,0 +1,37 @@
+package process
+
+import (
+	"fmt"
+
+	"golang.org/x/sys/windows"
+)
+
+// SetNiceness sets the scheduling niceness of the current process. Windows
+// doesn't support niceness values, so the niceness is mapped to a priority
+// class: a niceness of 0 maps to the normal priority class, values below 15
+// map to the below normal priority class, and values of 15 or above map to the
+// idle priority class.
+func SetNiceness(niceness int) error {
+	// Validate the niceness.
+	if niceness < 0 || niceness > MaximumNiceness {
+		return fmt.Errorf("niceness (%d) outside supported range", niceness)
+	}
+
+	// Determine the priority class.
+	var class uint32
+	if niceness == 0 {
+		class = windows.NORMAL_PRIORITY_CLASS
+	} else if niceness < 15 {
+		class = windows.BELOW_NORMAL_PRIORITY_CLASS
+	} else {
+		class = windows.IDLE_PRIORITY_CLASS
+	}
+
+	// Set the priority class.
+	if err := windows.SetPriorityClass(windows.CurrentProcess(), class); err != nil {
+		return fmt.Errorf("unable to set process priority class: %w", err)
+	}
+
+	// Success.
+	return nil
+}
@@ -1,6 +1,9 @@
 package project
 
 import (
+	"fmt"
+	"os"
+
 	"github.com/mutagen-io/mutagen/pkg/api/models/forwarding"
 	"github.com/mutagen-io/mutagen/pkg/api/models/synchronization"
 	"github.com/mutagen-io/mutagen/pkg/encoding"
@@ -93,8 +96,18 @@ type SynchronizationConfiguration struct {
 	ConfigurationBeta synchronization.Configuration `yaml:"configurationBeta"`
 }
 
-// Configuration is the orchestration configuration object type.
+// Configuration is the orchestration configuration object type. Session URLs
+// and certain path and identity fields within session configurations may
+// reference variables using the ${NAME} syntax, which are resolved (in order of
+// precedence) from command line overrides, the variables block, and the
+// environment. A literal "$" can be specified in these fields using "$$".
+// Shell commands (such as hooks and project commands) are never subject to
+// interpolation.
 type Configuration struct {
+	// Variables are variable definitions that can be referenced from other
+	// configuration values. Their values may themselves reference environment
+	// variables.
+	Variables map[string]string `yaml:"variables"`
 	// BeforeCreate are setup commands to be run before session creation.
 	BeforeCreate []string `yaml:"beforeCreate"`
 	// AfterCreate are setup commands to be run after session creation.
@@ -127,8 +140,9 @@ type Configuration struct {
 }
 
 // LoadConfiguration attempts to load a YAML-based Mutagen orchestration
-// configuration file from the specified path.
-func LoadConfiguration(path string) (*Configuration, error) {
+// configuration file from the specified path. Variable references are resolved
+// using the specified variable overrides, which may be nil.
+func LoadConfiguration(path string, overrides map[string]string) (*Configuration, error) {
 	// Create the target configuration object.
 	result := &Configuration{}
 
@@ -137,6 +151,23 @@ func LoadConfiguration(path string) (*Configuration, error) {
 		return nil, err
 	}
 
+	// Resolve variables.
+	variables, err := resolveVariables(result.Variables, overrides)
+	if err != nil {
+		return nil, err
+	}
+	result.Variables = variables
+
+	// Perform interpolation.
+	if err := result.interpolate(func(name string) (string, bool) {
+		if value, ok := variables[name]; ok {
+			return value, true
+		}
+		return os.LookupEnv(name)
+	}); err != nil {
+		return nil, fmt.Errorf("unable to interpolate variables: %w", err)
+	}
+
 	// Success.
 	return result, nil
 }
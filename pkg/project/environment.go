@@ -0,0 +1,190 @@
+package project
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/mutagen-io/mutagen/pkg/url"
+)
+
+const (
+	// EnvironmentVariableIdentifier is the environment variable that exposes
+	// the project identifier to project commands.
+	EnvironmentVariableIdentifier = "MUTAGEN_PROJECT_IDENTIFIER"
+	// EnvironmentVariableForwardingSessions is the environment variable that
+	// exposes a space-separated list of forwarding session names to project
+	// commands.
+	EnvironmentVariableForwardingSessions = "MUTAGEN_PROJECT_FORWARDING_SESSIONS"
+	// EnvironmentVariableSynchronizationSessions is the environment variable
+	// that exposes a space-separated list of synchronization session names to
+	// project commands.
+	EnvironmentVariableSynchronizationSessions = "MUTAGEN_PROJECT_SYNCHRONIZATION_SESSIONS"
+
+	// environmentVariableForwardingPrefix is the prefix for environment
+	// variables that expose forwarding session URLs.
+	environmentVariableForwardingPrefix = "MUTAGEN_PROJECT_FORWARDING_"
+	// environmentVariableSynchronizationPrefix is the prefix for environment
+	// variables that expose synchronization session URLs.
+	environmentVariableSynchronizationPrefix = "MUTAGEN_PROJECT_SYNCHRONIZATION_"
+)
+
+// environmentVariableSessionComponent converts a session name to the form used
+// in session-specific environment variable names, i.e. the name converted to
+// uppercase with dashes replaced by underscores. Since this conversion isn't
+// reversible for all names (e.g. names differing only in case), callers must
+// check for collisions. An error is returned if the name contains characters
+// that can't be represented portably in an environment variable name.
+func environmentVariableSessionComponent(name string) (string, error) {
+	component := strings.ToUpper(strings.ReplaceAll(name, "-", "_"))
+	for _, r := range component {
+		if !(('A' <= r && r <= 'Z') || ('0' <= r && r <= '9') || r == '_') {
+			return "", fmt.Errorf("session name (%s) can't be represented in an environment variable name", name)
+		}
+	}
+	return component, nil
+}
+
+// environmentVariableSessionComponents computes the environment variable name
+// components for the specified session names, ensuring that they're unique.
+func environmentVariableSessionComponents(names []string) (map[string]string, error) {
+	result := make(map[string]string, len(names))
+	owners := make(map[string]string, len(names))
+	for _, name := range names {
+		component, err := environmentVariableSessionComponent(name)
+		if err != nil {
+			return nil, err
+		} else if owner, ok := owners[component]; ok {
+			return nil, fmt.Errorf("session names (%s and %s) map to the same environment variable names", owner, name)
+		}
+		owners[component] = name
+		result[name] = component
+	}
+	return result, nil
+}
+
+// resolveURL parses and reformats a session URL, thereby resolving relative
+// local paths and normalizing its representation.
+func resolveURL(raw string, kind url.Kind, first bool) (string, error) {
+	parsed, err := url.Parse(raw, kind, first)
+	if err != nil {
+		return "", fmt.Errorf("unable to parse URL (%s): %w", raw, err)
+	}
+	return parsed.Format(""), nil
+}
+
+// Environment computes environment variables that describe the project context
+// for a project with the specified identifier. In addition to the identifier
+// and lists of session names, it includes the resolved URLs for each session
+// (with defaults applied) as MUTAGEN_PROJECT_FORWARDING_<NAME>_SOURCE,
+// MUTAGEN_PROJECT_FORWARDING_<NAME>_DESTINATION,
+// MUTAGEN_PROJECT_SYNCHRONIZATION_<NAME>_ALPHA, and
+// MUTAGEN_PROJECT_SYNCHRONIZATION_<NAME>_BETA, where <NAME> is the session name
+// converted to uppercase with dashes replaced by underscores. An error is
+// returned if session names can't be mapped to unique, portable environment
+// variable names or if session URLs can't be resolved. Relative local paths
+// are resolved against the current working directory. The result is in the
+// "key=value" format used by os/exec.
+func (c *Configuration) Environment(identifier string) ([]string, error) {
+	// Create the result and add the project identifier.
+	result := []string{EnvironmentVariableIdentifier + "=" + identifier}
+
+	// Extract forwarding defaults.
+	var defaultSource, defaultDestination string
+	if defaults, ok := c.Forwarding["defaults"]; ok {
+		defaultSource = defaults.Source
+		defaultDestination = defaults.Destination
+	}
+
+	// Compute forwarding session names in a deterministic order, along with
+	// their environment variable name components.
+	var forwardingNames []string
+	for name := range c.Forwarding {
+		if name != "defaults" {
+			forwardingNames = append(forwardingNames, name)
+		}
+	}
+	sort.Strings(forwardingNames)
+	forwardingComponents, err := environmentVariableSessionComponents(forwardingNames)
+	if err != nil {
+		return nil, fmt.Errorf("invalid forwarding sessions: %w", err)
+	}
+
+	// Add forwarding session information.
+	result = append(result, EnvironmentVariableForwardingSessions+"="+strings.Join(forwardingNames, " "))
+	for _, name := range forwardingNames {
+		session := c.Forwarding[name]
+		source := session.Source
+		if source == "" {
+			source = defaultSource
+		}
+		destination := session.Destination
+		if destination == "" {
+			destination = defaultDestination
+		}
+		source, err := resolveURL(source, url.Kind_Forwarding, true)
+		if err != nil {
+			return nil, fmt.Errorf("invalid forwarding session source (%s): %w", name, err)
+		}
+		destination, err = resolveURL(destination, url.Kind_Forwarding, false)
+		if err != nil {
+			return nil, fmt.Errorf("invalid forwarding session destination (%s): %w", name, err)
+		}
+		prefix := environmentVariableForwardingPrefix + forwardingComponents[name]
+		result = append(result,
+			prefix+"_SOURCE="+source,
+			prefix+"_DESTINATION="+destination,
+		)
+	}
+
+	// Extract synchronization defaults.
+	var defaultAlpha, defaultBeta string
+	if defaults, ok := c.Synchronization["defaults"]; ok {
+		defaultAlpha = defaults.Alpha
+		defaultBeta = defaults.Beta
+	}
+
+	// Compute synchronization session names in a deterministic order, along
+	// with their environment variable name components.
+	var synchronizationNames []string
+	for name := range c.Synchronization {
+		if name != "defaults" {
+			synchronizationNames = append(synchronizationNames, name)
+		}
+	}
+	sort.Strings(synchronizationNames)
+	synchronizationComponents, err := environmentVariableSessionComponents(synchronizationNames)
+	if err != nil {
+		return nil, fmt.Errorf("invalid synchronization sessions: %w", err)
+	}
+
+	// Add synchronization session information.
+	result = append(result, EnvironmentVariableSynchronizationSessions+"="+strings.Join(synchronizationNames, " "))
+	for _, name := range synchronizationNames {
+		session := c.Synchronization[name]
+		alpha := session.Alpha
+		if alpha == "" {
+			alpha = defaultAlpha
+		}
+		beta := session.Beta
+		if beta == "" {
+			beta = defaultBeta
+		}
+		alpha, err := resolveURL(alpha, url.Kind_Synchronization, true)
+		if err != nil {
+			return nil, fmt.Errorf("invalid synchronization session alpha (%s): %w", name, err)
+		}
+		beta, err = resolveURL(beta, url.Kind_Synchronization, false)
+		if err != nil {
+			return nil, fmt.Errorf("invalid synchronization session beta (%s): %w", name, err)
+		}
+		prefix := environmentVariableSynchronizationPrefix + synchronizationComponents[name]
+		result = append(result,
+			prefix+"_ALPHA="+alpha,
+			prefix+"_BETA="+beta,
+		)
+	}
+
+	// Success.
+	return result, nil
+}
@@ -0,0 +1,80 @@
+package project
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+// TestConfigurationEnvironment tests Configuration.Environment.
+func TestConfigurationEnvironment(t *testing.T) {
+	// Compute the expected resolution of relative local paths.
+	workingDirectory, err := os.Getwd()
+	if err != nil {
+		t.Fatal("unable to compute working directory:", err)
+	}
+
+	// Create a configuration.
+	configuration := &Configuration{
+		Forwarding: map[string]ForwardingConfiguration{
+			"defaults": {Source: "tcp:localhost:8080"},
+			"web-app":  {Destination: "docker://web:tcp:localhost:80"},
+		},
+		Synchronization: map[string]SynchronizationConfiguration{
+			"defaults": {Alpha: "."},
+			"code":     {Beta: "docker://web/code"},
+			"assets":   {Alpha: "./assets", Beta: "docker://web/assets"},
+		},
+	}
+
+	// Set up the expected result.
+	expected := []string{
+		"MUTAGEN_PROJECT_IDENTIFIER=proj_test",
+		"MUTAGEN_PROJECT_FORWARDING_SESSIONS=web-app",
+		"MUTAGEN_PROJECT_FORWARDING_WEB_APP_SOURCE=tcp:localhost:8080",
+		"MUTAGEN_PROJECT_FORWARDING_WEB_APP_DESTINATION=docker://web:tcp:localhost:80",
+		"MUTAGEN_PROJECT_SYNCHRONIZATION_SESSIONS=assets code",
+		"MUTAGEN_PROJECT_SYNCHRONIZATION_ASSETS_ALPHA=" + filepath.Join(workingDirectory, "assets"),
+		"MUTAGEN_PROJECT_SYNCHRONIZATION_ASSETS_BETA=docker://web/assets",
+		"MUTAGEN_PROJECT_SYNCHRONIZATION_CODE_ALPHA=" + workingDirectory,
+		"MUTAGEN_PROJECT_SYNCHRONIZATION_CODE_BETA=docker://web/code",
+	}
+
+	// Compute the environment and verify the result.
+	environment, err := configuration.Environment("proj_test")
+	if err != nil {
+		t.Fatal("unable to compute environment:", err)
+	}
+	if len(environment) != len(expected) {
+		t.Fatalf("environment length does not match expected: %d != %d", len(environment), len(expected))
+	}
+	for i, entry := range environment {
+		if entry != expected[i] {
+			t.Errorf("environment entry %d does not match expected: %s != %s", i, entry, expected[i])
+		}
+	}
+}
+
+// TestConfigurationEnvironmentInvalidNames tests that Configuration.Environment
+// rejects session names that can't be mapped to unique, portable environment
+// variable names.
+func TestConfigurationEnvironmentInvalidNames(t *testing.T) {
+	// Set up test cases.
+	testCases := []map[string]SynchronizationConfiguration{
+		{
+			"api": {Alpha: "/a", Beta: "/b"},
+			"API": {Alpha: "/c", Beta: "/d"},
+		},
+		{
+			"código": {Alpha: "/a", Beta: "/b"},
+		},
+	}
+
+	// Process test cases.
+	for i, testCase := range testCases {
+		configuration := &Configuration{Synchronization: testCase}
+		if _, err := configuration.Environment("proj_test"); err == nil {
+			t.Errorf("test index %d: environment computed for invalid session names", i)
+		}
+	}
+}
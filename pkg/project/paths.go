@@ -1,10 +1,45 @@
 package project
 
+import (
+	"fmt"
+)
+
 const (
 	// DefaultConfigurationFileName is the name of the Mutagen project
 	// configuration file.
 	DefaultConfigurationFileName = "mutagen.yml"
+	// namedConfigurationFileNameFormat is the format string used to compute
+	// the name of the configuration file for a named project.
+	namedConfigurationFileNameFormat = "mutagen.%s.yml"
+	// nameMaximumLength is the maximum allowed length for project names.
+	nameMaximumLength = 64
 	// LockFileExtension is the extension added to a configuration file path in
 	// order to compute the corresponding lock file.
 	LockFileExtension = ".lock"
 )
+
+// IsValidName returns whether or not the specified project name is valid.
+// Names must be non-empty, no longer than 64 bytes, start with a lowercase
+// ASCII letter or digit, and otherwise consist of lowercase ASCII letters,
+// digits, '-', and '_'.
+func IsValidName(name string) bool {
+	if name == "" || len(name) > nameMaximumLength {
+		return false
+	}
+	for i, r := range name {
+		if !(('a' <= r && r <= 'z') || ('0' <= r && r <= '9') || (i > 0 && (r == '-' || r == '_'))) {
+			return false
+		}
+	}
+	return true
+}
+
+// NamedConfigurationFileName computes the name of the configuration file for
+// the named project with the specified name, e.g. "mutagen.dev.yml" for a
+// project named "dev". Named projects allow multiple projects to be run
+// independently from the same directory, since each configuration file has its
+// own lock file and project identifier. The name should be validated using
+// IsValidName before calling this function.
+func NamedConfigurationFileName(name string) string {
+	return fmt.Sprintf(namedConfigurationFileNameFormat, name)
+}
@@ -0,0 +1,41 @@
+package project
+
+import (
+	"testing"
+)
+
+// TestIsValidName tests IsValidName.
+func TestIsValidName(t *testing.T) {
+	// Set up test cases.
+	testCases := []struct {
+		name     string
+		expected bool
+	}{
+		{"", false},
+		{"dev", true},
+		{"test-2", true},
+		{"staging_eu", true},
+		{"2fast", true},
+		{"-dev", false},
+		{"_dev", false},
+		{"Dev", false},
+		{"dev.local", false},
+		{"dev/test", false},
+		{"0123456789012345678901234567890123456789012345678901234567890123", true},
+		{"01234567890123456789012345678901234567890123456789012345678901234", false},
+	}
+
+	// Process test cases.
+	for i, testCase := range testCases {
+		if valid := IsValidName(testCase.name); valid != testCase.expected {
+			t.Errorf("test index %d: validity does not match expected: %t != %t", i, valid, testCase.expected)
+		}
+	}
+}
+
+// TestNamedConfigurationFileName tests NamedConfigurationFileName.
+func TestNamedConfigurationFileName(t *testing.T) {
+	if name := NamedConfigurationFileName("dev"); name != "mutagen.dev.yml" {
+		t.Error("configuration file name does not match expected:", name)
+	}
+}
@@ -0,0 +1,229 @@
+package project
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/mutagen-io/mutagen/pkg/api/models/forwarding"
+	"github.com/mutagen-io/mutagen/pkg/api/models/synchronization"
+)
+
+// IsValidVariableName returns whether or not the specified variable name is
+// valid. Names must be non-empty, must start with an ASCII letter or '_', and
+// must otherwise consist of ASCII letters, digits, and '_'.
+func IsValidVariableName(name string) bool {
+	if name == "" {
+		return false
+	}
+	for i, r := range name {
+		if !(('a' <= r && r <= 'z') || ('A' <= r && r <= 'Z') || r == '_' || (i > 0 && '0' <= r && r <= '9')) {
+			return false
+		}
+	}
+	return true
+}
+
+// ParseVariableOverrides parses a list of variable override specifications of
+// the form name=value (as provided on the command line) into a map.
+func ParseVariableOverrides(specifications []string) (map[string]string, error) {
+	// If there are no specifications, then there's nothing to parse.
+	if len(specifications) == 0 {
+		return nil, nil
+	}
+
+	// Parse and validate specifications.
+	result := make(map[string]string, len(specifications))
+	for _, specification := range specifications {
+		name, value, ok := strings.Cut(specification, "=")
+		if !ok {
+			return nil, fmt.Errorf("invalid variable specification: %s", specification)
+		} else if !IsValidVariableName(name) {
+			return nil, fmt.Errorf("invalid variable name: %s", name)
+		}
+		result[name] = value
+	}
+
+	// Success.
+	return result, nil
+}
+
+// interpolate performs variable substitution on the specified value. Variable
+// references take the form ${NAME} and are resolved using the specified lookup
+// function. A literal "$" can be specified using "$$", and any "$" that isn't
+// followed by "$" or "{" is left unmodified. References to undefined variables
+// are treated as errors.
+func interpolate(value string, lookup func(string) (string, bool)) (string, error) {
+	// If there are no potential references, then avoid allocation.
+	if !strings.Contains(value, "$") {
+		return value, nil
+	}
+
+	// Perform substitution.
+	var result strings.Builder
+	for {
+		// Find the next potential reference.
+		index := strings.IndexByte(value, '$')
+		if index == -1 || index == len(value)-1 {
+			result.WriteString(value)
+			break
+		}
+		result.WriteString(value[:index])
+		value = value[index+1:]
+
+		// Handle escapes and non-references.
+		if value[0] == '$' {
+			result.WriteByte('$')
+			value = value[1:]
+			continue
+		} else if value[0] != '{' {
+			result.WriteByte('$')
+			continue
+		}
+
+		// Parse the variable name.
+		end := strings.IndexByte(value, '}')
+		if end == -1 {
+			return "", errors.New("unterminated variable reference")
+		}
+		name := value[1:end]
+		if !IsValidVariableName(name) {
+			return "", fmt.Errorf("invalid variable name: %s", name)
+		}
+		value = value[end+1:]
+
+		// Resolve the variable.
+		resolved, ok := lookup(name)
+		if !ok {
+			return "", fmt.Errorf("undefined variable: %s", name)
+		}
+		result.WriteString(resolved)
+	}
+
+	// Success.
+	return result.String(), nil
+}
+
+// resolveVariables computes the effective set of variables for a project. It
+// layers the specified overrides on top of the specified variable definitions,
+// interpolating the values of any definitions that aren't overridden using the
+// environment.
+func resolveVariables(definitions, overrides map[string]string) (map[string]string, error) {
+	// Create the result.
+	result := make(map[string]string, len(definitions)+len(overrides))
+
+	// Resolve definitions that aren't overridden.
+	for name, value := range definitions {
+		if !IsValidVariableName(name) {
+			return nil, fmt.Errorf("invalid variable name: %s", name)
+		} else if _, ok := overrides[name]; ok {
+			continue
+		}
+		resolved, err := interpolate(value, os.LookupEnv)
+		if err != nil {
+			return nil, fmt.Errorf("unable to resolve variable \"%s\": %w", name, err)
+		}
+		result[name] = resolved
+	}
+
+	// Apply overrides.
+	for name, value := range overrides {
+		result[name] = value
+	}
+
+	// Success.
+	return result, nil
+}
+
+// interpolateFields performs variable substitution on each of the specified
+// fields in-place.
+func interpolateFields(lookup func(string) (string, bool), fields ...*string) error {
+	for _, field := range fields {
+		interpolated, err := interpolate(*field, lookup)
+		if err != nil {
+			return err
+		}
+		*field = interpolated
+	}
+	return nil
+}
+
+// interpolateSlice performs variable substitution on each element of the
+// specified slice in-place.
+func interpolateSlice(lookup func(string) (string, bool), values []string) error {
+	for i := range values {
+		if err := interpolateFields(lookup, &values[i]); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// interpolateForwardingConfiguration performs variable substitution on the path
+// and identity fields of a forwarding configuration.
+func interpolateForwardingConfiguration(lookup func(string) (string, bool), configuration *forwarding.Configuration) error {
+	if err := interpolateFields(lookup,
+		&configuration.Socket.Owner,
+		&configuration.Socket.Group,
+		&configuration.TLS.Certificate,
+		&configuration.TLS.Key,
+		&configuration.TLS.ServerName,
+		&configuration.TLS.CertificateAuthority,
+	); err != nil {
+		return err
+	}
+	return interpolateSlice(lookup, configuration.AdditionalAddresses)
+}
+
+// interpolateSynchronizationConfiguration performs variable substitution on
+// the path and identity fields of a synchronization configuration.
+func interpolateSynchronizationConfiguration(lookup func(string) (string, bool), configuration *synchronization.Configuration) error {
+	return interpolateFields(lookup,
+		&configuration.Permissions.DefaultOwner,
+		&configuration.Permissions.DefaultGroup,
+		&configuration.Safety.TrashDirectory,
+	)
+}
+
+// interpolate performs variable substitution on the configuration's session
+// URLs and session configuration path and identity fields. Shell commands are
+// left unmodified.
+func (c *Configuration) interpolate(lookup func(string) (string, bool)) error {
+	// Handle forwarding sessions.
+	for name, session := range c.Forwarding {
+		if err := interpolateFields(lookup, &session.Source, &session.Destination); err != nil {
+			return fmt.Errorf("forwarding session %s: %w", name, err)
+		}
+		for _, configuration := range []*forwarding.Configuration{
+			&session.Configuration,
+			&session.ConfigurationSource,
+			&session.ConfigurationDestination,
+		} {
+			if err := interpolateForwardingConfiguration(lookup, configuration); err != nil {
+				return fmt.Errorf("forwarding session %s: %w", name, err)
+			}
+		}
+		c.Forwarding[name] = session
+	}
+
+	// Handle synchronization sessions.
+	for name, session := range c.Synchronization {
+		if err := interpolateFields(lookup, &session.Alpha, &session.Beta); err != nil {
+			return fmt.Errorf("synchronization session %s: %w", name, err)
+		}
+		for _, configuration := range []*synchronization.Configuration{
+			&session.Configuration,
+			&session.ConfigurationAlpha,
+			&session.ConfigurationBeta,
+		} {
+			if err := interpolateSynchronizationConfiguration(lookup, configuration); err != nil {
+				return fmt.Errorf("synchronization session %s: %w", name, err)
+			}
+		}
+		c.Synchronization[name] = session
+	}
+
+	// Success.
+	return nil
+}
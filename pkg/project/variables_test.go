@@ -0,0 +1,187 @@
+package project
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+// TestIsValidVariableName tests IsValidVariableName.
+func TestIsValidVariableName(t *testing.T) {
+	// Set up test cases.
+	testCases := []struct {
+		name     string
+		expected bool
+	}{
+		{"", false},
+		{"host", true},
+		{"HOST_2", true},
+		{"_private", true},
+		{"2host", false},
+		{"host-name", false},
+		{"host name", false},
+	}
+
+	// Process test cases.
+	for i, testCase := range testCases {
+		if valid := IsValidVariableName(testCase.name); valid != testCase.expected {
+			t.Errorf("test index %d: validity does not match expected: %t != %t", i, valid, testCase.expected)
+		}
+	}
+}
+
+// TestParseVariableOverrides tests ParseVariableOverrides.
+func TestParseVariableOverrides(t *testing.T) {
+	// Set up test cases.
+	testCases := []struct {
+		specifications []string
+		expected       map[string]string
+		expectFailure  bool
+	}{
+		{nil, nil, false},
+		{[]string{"host=dev2"}, map[string]string{"host": "dev2"}, false},
+		{[]string{"host=dev2", "path=/a=b"}, map[string]string{"host": "dev2", "path": "/a=b"}, false},
+		{[]string{"host="}, map[string]string{"host": ""}, false},
+		{[]string{"host"}, nil, true},
+		{[]string{"=dev2"}, nil, true},
+		{[]string{"host-name=dev2"}, nil, true},
+	}
+
+	// Process test cases.
+	for i, testCase := range testCases {
+		overrides, err := ParseVariableOverrides(testCase.specifications)
+		if testCase.expectFailure {
+			if err == nil {
+				t.Errorf("test index %d: parsing succeeded unexpectedly", i)
+			}
+			continue
+		} else if err != nil {
+			t.Errorf("test index %d: parsing failed unexpectedly: %v", i, err)
+			continue
+		}
+		if len(overrides) != len(testCase.expected) {
+			t.Errorf("test index %d: override count does not match expected: %d != %d", i, len(overrides), len(testCase.expected))
+			continue
+		}
+		for name, value := range testCase.expected {
+			if overrides[name] != value {
+				t.Errorf("test index %d: override value for %s does not match expected: %s != %s", i, name, overrides[name], value)
+			}
+		}
+	}
+}
+
+// TestInterpolate tests interpolate.
+func TestInterpolate(t *testing.T) {
+	// Set up the lookup function.
+	variables := map[string]string{"host": "dev2", "path": "/code"}
+	lookup := func(name string) (string, bool) {
+		value, ok := variables[name]
+		return value, ok
+	}
+
+	// Set up test cases.
+	testCases := []struct {
+		value         string
+		expected      string
+		expectFailure bool
+	}{
+		{"", "", false},
+		{"plain", "plain", false},
+		{"${host}", "dev2", false},
+		{"${host}:${path}", "dev2:/code", false},
+		{"docker://${host}${path}/src", "docker://dev2/code/src", false},
+		{"echo $HOME", "echo $HOME", false},
+		{"cost: 5$", "cost: 5$", false},
+		{"$${host}", "${host}", false},
+		{"$$$${host}", "$${host}", false},
+		{"$$${host}", "$dev2", false},
+		{"${missing}", "", true},
+		{"${host", "", true},
+		{"${}", "", true},
+		{"${host-name}", "", true},
+	}
+
+	// Process test cases.
+	for i, testCase := range testCases {
+		result, err := interpolate(testCase.value, lookup)
+		if testCase.expectFailure {
+			if err == nil {
+				t.Errorf("test index %d: interpolation succeeded unexpectedly", i)
+			}
+		} else if err != nil {
+			t.Errorf("test index %d: interpolation failed unexpectedly: %v", i, err)
+		} else if result != testCase.expected {
+			t.Errorf("test index %d: result does not match expected: %s != %s", i, result, testCase.expected)
+		}
+	}
+}
+
+// TestLoadConfigurationVariables tests variable resolution in
+// LoadConfiguration.
+func TestLoadConfigurationVariables(t *testing.T) {
+	// Set up an environment variable.
+	t.Setenv("MUTAGEN_TEST_PROJECT_USER", "developer")
+
+	// Write a configuration file.
+	path := filepath.Join(t.TempDir(), "mutagen.yml")
+	contents := `variables:
+  host: dev1
+  root: /home/${MUTAGEN_TEST_PROJECT_USER}
+  remote: ${MUTAGEN_TEST_PROJECT_UNDEFINED}
+beforeCreate:
+  - echo ${LOCAL_SHELL_VAR:-x} $$
+commands:
+  shell: ssh ${host}
+sync:
+  code:
+    alpha: "."
+    beta: "docker://${host}${root}/code"
+    permissions:
+      defaultOwner: "${host}"
+`
+	if err := os.WriteFile(path, []byte(contents), 0600); err != nil {
+		t.Fatal("unable to write configuration file:", err)
+	}
+
+	// Verify that loading fails if a variable references an undefined
+	// environment variable and isn't overridden.
+	if _, err := LoadConfiguration(path, nil); err == nil {
+		t.Fatal("configuration loaded with unresolvable variable")
+	}
+
+	// Load the configuration with an override for the unresolvable variable
+	// and verify the result.
+	configuration, err := LoadConfiguration(path, map[string]string{"remote": "r"})
+	if err != nil {
+		t.Fatal("unable to load configuration:", err)
+	}
+	if beta := configuration.Synchronization["code"].Beta; beta != "docker://dev1/home/developer/code" {
+		t.Error("beta URL does not match expected:", beta)
+	}
+	if owner := configuration.Synchronization["code"].Configuration.Permissions.DefaultOwner; owner != "dev1" {
+		t.Error("default owner does not match expected:", owner)
+	}
+	if len(configuration.BeforeCreate) != 1 || configuration.BeforeCreate[0] != "echo ${LOCAL_SHELL_VAR:-x} $$" {
+		t.Error("pre-create commands do not match expected:", configuration.BeforeCreate)
+	}
+	if command := configuration.Commands["shell"]; command != "ssh ${host}" {
+		t.Error("project command does not match expected:", command)
+	}
+	if configuration.Variables["root"] != "/home/developer" {
+		t.Error("resolved variable does not match expected:", configuration.Variables["root"])
+	}
+
+	// Load the configuration with overrides containing YAML syntax and verify
+	// that they're substituted verbatim.
+	configuration, err = LoadConfiguration(path, map[string]string{"remote": "r", "host": "dev2 # x: 'y'\nz"})
+	if err != nil {
+		t.Fatal("unable to load configuration:", err)
+	}
+	if beta := configuration.Synchronization["code"].Beta; beta != "docker://dev2 # x: 'y'\nz/home/developer/code" {
+		t.Error("beta URL does not match expected:", beta)
+	}
+	if len(configuration.Synchronization) != 1 {
+		t.Error("unexpected synchronization session count:", len(configuration.Synchronization))
+	}
+}
@@ -0,0 +1,272 @@
+package s3
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"net"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+)
+
+const (
+	// maximumErrorBodySize is the maximum number of bytes of an error response
+	// body that will be read.
+	maximumErrorBodySize = 64 * 1024
+	// dialTimeout is the timeout for establishing connections to the service.
+	dialTimeout = 30 * time.Second
+	// tlsHandshakeTimeout is the timeout for TLS handshakes with the service.
+	tlsHandshakeTimeout = 10 * time.Second
+	// responseHeaderTimeout is the timeout for receiving response headers
+	// after a request has been fully written. We don't bound the entire
+	// request because object transfers may legitimately take a long time.
+	responseHeaderTimeout = time.Minute
+	// idleConnectionTimeout is the time after which idle connections are
+	// closed.
+	idleConnectionTimeout = 90 * time.Second
+)
+
+// ResponseError is the error type returned when the service responds with an
+// error.
+type ResponseError struct {
+	// StatusCode is the HTTP status code of the response.
+	StatusCode int
+	// Code is the S3 error code. It may be empty if the response didn't include
+	// an error body.
+	Code string
+	// Message is the S3 error message. It may be empty.
+	Message string
+}
+
+// Error implements error.Error.
+func (e *ResponseError) Error() string {
+	if e.Code == "" {
+		return fmt.Sprintf("request failed with status %d", e.StatusCode)
+	} else if e.Message == "" {
+		return fmt.Sprintf("request failed with status %d: %s", e.StatusCode, e.Code)
+	}
+	return fmt.Sprintf("request failed with status %d: %s: %s", e.StatusCode, e.Code, e.Message)
+}
+
+// Is allows ResponseError to be compared with fs.ErrNotExist and
+// fs.ErrPermission using errors.Is.
+func (e *ResponseError) Is(target error) bool {
+	switch target {
+	case fs.ErrNotExist:
+		return e.StatusCode == http.StatusNotFound
+	case fs.ErrPermission:
+		return e.StatusCode == http.StatusForbidden
+	default:
+		return false
+	}
+}
+
+// errorResponse is the XML structure of an error response body.
+type errorResponse struct {
+	// Code is the error code.
+	Code string `xml:"Code"`
+	// Message is the error message.
+	Message string `xml:"Message"`
+}
+
+// Client is a client for a single bucket in S3-compatible object storage. It
+// is safe for concurrent usage.
+type Client struct {
+	// base is the base URL for object requests. Object keys are appended to
+	// its path.
+	base *url.URL
+	// region is the bucket region.
+	region string
+	// credentials are the credentials used to sign requests.
+	credentials *Credentials
+	// httpClient is the underlying HTTP client.
+	httpClient *http.Client
+}
+
+// NewClient creates a new client for the specified bucket.
+func NewClient(bucket string, configuration *Configuration) (*Client, error) {
+	// Validate the configuration.
+	if bucket == "" {
+		return nil, errors.New("empty bucket name")
+	} else if configuration.Credentials == nil {
+		return nil, errors.New("no credentials specified")
+	}
+
+	// Compute the base URL. For custom endpoints and for bucket names that
+	// can't be used in hostnames under TLS, we use path-style addressing.
+	var base *url.URL
+	if configuration.Endpoint != "" {
+		endpoint, err := url.Parse(configuration.Endpoint)
+		if err != nil {
+			return nil, fmt.Errorf("invalid endpoint URL: %w", err)
+		} else if endpoint.Scheme != "http" && endpoint.Scheme != "https" {
+			return nil, errors.New("endpoint URL must use HTTP or HTTPS")
+		}
+		base = &url.URL{
+			Scheme: endpoint.Scheme,
+			Host:   endpoint.Host,
+			Path:   strings.TrimSuffix(endpoint.Path, "/") + "/" + bucket + "/",
+		}
+	} else if strings.IndexByte(bucket, '.') != -1 {
+		base = &url.URL{
+			Scheme: "https",
+			Host:   fmt.Sprintf("s3.%s.amazonaws.com", configuration.Region),
+			Path:   "/" + bucket + "/",
+		}
+	} else {
+		base = &url.URL{
+			Scheme: "https",
+			Host:   fmt.Sprintf("%s.s3.%s.amazonaws.com", bucket, configuration.Region),
+			Path:   "/",
+		}
+	}
+
+	// Success.
+	return &Client{
+		base:        base,
+		region:      configuration.Region,
+		credentials: configuration.Credentials,
+		httpClient: &http.Client{
+			Transport: &http.Transport{
+				Proxy:                 http.ProxyFromEnvironment,
+				DialContext:           (&net.Dialer{Timeout: dialTimeout}).DialContext,
+				TLSHandshakeTimeout:   tlsHandshakeTimeout,
+				ResponseHeaderTimeout: responseHeaderTimeout,
+				IdleConnTimeout:       idleConnectionTimeout,
+			},
+		},
+	}, nil
+}
+
+// newRequest creates a new signed request for the specified object key.
+func (c *Client) newRequest(method, key string, body io.Reader, payloadHash string) (*http.Request, error) {
+	// Compute the request URL, ensuring that the path is encoded in the same
+	// manner used for signing.
+	target := *c.base
+	target.Path += key
+	target.RawPath = uriEncode(target.Path, true)
+
+	// Create and sign the request.
+	request, err := http.NewRequest(method, target.String(), body)
+	if err != nil {
+		return nil, err
+	}
+	sign(request, c.credentials, c.region, payloadHash, time.Now())
+
+	// Success.
+	return request, nil
+}
+
+// do performs a request and converts error responses to errors. On success,
+// the caller is responsible for closing the response body.
+func (c *Client) do(request *http.Request) (*http.Response, error) {
+	// Perform the request.
+	response, err := c.httpClient.Do(request)
+	if err != nil {
+		return nil, err
+	}
+
+	// Handle success.
+	if response.StatusCode >= 200 && response.StatusCode < 300 {
+		return response, nil
+	}
+
+	// Extract error information.
+	defer response.Body.Close()
+	result := &ResponseError{StatusCode: response.StatusCode}
+	var body errorResponse
+	if xml.NewDecoder(io.LimitReader(response.Body, maximumErrorBodySize)).Decode(&body) == nil {
+		result.Code = body.Code
+		result.Message = body.Message
+	}
+	return nil, result
+}
+
+// Get retrieves the object with the specified key. It returns the object
+// content and its size. The caller is responsible for closing the content.
+func (c *Client) Get(key string) (io.ReadCloser, uint64, error) {
+	// Create the request.
+	request, err := c.newRequest(http.MethodGet, key, nil, emptyPayloadHash)
+	if err != nil {
+		return nil, 0, fmt.Errorf("unable to create request: %w", err)
+	}
+
+	// Perform the request.
+	response, err := c.do(request)
+	if err != nil {
+		return nil, 0, err
+	} else if response.ContentLength < 0 {
+		response.Body.Close()
+		return nil, 0, errors.New("response did not specify content length")
+	}
+
+	// Success.
+	return response.Body, uint64(response.ContentLength), nil
+}
+
+// Put stores an object with the specified key, replacing any existing object.
+// The content is read twice: once to compute its digest for signing and once
+// to transmit it.
+func (c *Client) Put(key string, content io.ReadSeeker) error {
+	// Compute the payload hash and size.
+	hasher := sha256.New()
+	size, err := io.Copy(hasher, content)
+	if err != nil {
+		return fmt.Errorf("unable to compute content digest: %w", err)
+	} else if _, err := content.Seek(0, io.SeekStart); err != nil {
+		return fmt.Errorf("unable to rewind content: %w", err)
+	}
+	payloadHash := hex.EncodeToString(hasher.Sum(nil))
+
+	// Create the request. We wrap the content in a reader that doesn't expose
+	// io.Seeker so that the HTTP client doesn't attempt to infer its length.
+	request, err := c.newRequest(http.MethodPut, key, io.LimitReader(content, size), payloadHash)
+	if err != nil {
+		return fmt.Errorf("unable to create request: %w", err)
+	}
+	request.ContentLength = size
+
+	// Perform the request.
+	response, err := c.do(request)
+	if err != nil {
+		return err
+	}
+	response.Body.Close()
+
+	// Success.
+	return nil
+}
+
+// Delete removes the object with the specified key. Removing a non-existent
+// object is not an error.
+func (c *Client) Delete(key string) error {
+	// Create the request.
+	request, err := c.newRequest(http.MethodDelete, key, nil, emptyPayloadHash)
+	if err != nil {
+		return fmt.Errorf("unable to create request: %w", err)
+	}
+
+	// Perform the request.
+	response, err := c.do(request)
+	if err != nil {
+		if errors.Is(err, fs.ErrNotExist) {
+			return nil
+		}
+		return err
+	}
+	response.Body.Close()
+
+	// Success.
+	return nil
+}
+
+// Close closes any idle connections held by the client.
+func (c *Client) Close() {
+	c.httpClient.CloseIdleConnections()
+}
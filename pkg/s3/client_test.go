@@ -0,0 +1,101 @@
+package s3
+
+import (
+	"errors"
+	"io"
+	"io/fs"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+)
+
+// testServer is an in-memory S3-compatible object store for testing.
+type testServer struct {
+	// lock serializes access to objects.
+	lock sync.Mutex
+	// objects maps request paths to object content.
+	objects map[string][]byte
+}
+
+// ServeHTTP implements http.Handler.ServeHTTP.
+func (s *testServer) ServeHTTP(writer http.ResponseWriter, request *http.Request) {
+	// Ensure that the request is signed.
+	if !strings.HasPrefix(request.Header.Get("Authorization"), signingAlgorithm+" ") {
+		writer.WriteHeader(http.StatusForbidden)
+		return
+	}
+
+	// Lock the object store.
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	// Handle the request.
+	switch request.Method {
+	case http.MethodGet:
+		if content, ok := s.objects[request.URL.Path]; ok {
+			writer.Write(content)
+		} else {
+			writer.WriteHeader(http.StatusNotFound)
+			io.WriteString(writer, "<Error><Code>NoSuchKey</Code><Message>missing</Message></Error>")
+		}
+	case http.MethodPut:
+		content, _ := io.ReadAll(request.Body)
+		s.objects[request.URL.Path] = content
+	case http.MethodDelete:
+		delete(s.objects, request.URL.Path)
+		writer.WriteHeader(http.StatusNoContent)
+	default:
+		writer.WriteHeader(http.StatusMethodNotAllowed)
+	}
+}
+
+// TestClient tests basic object operations against an in-memory server.
+func TestClient(t *testing.T) {
+	// Start the server.
+	store := &testServer{objects: make(map[string][]byte)}
+	server := httptest.NewServer(store)
+	defer server.Close()
+
+	// Create the client.
+	client, err := NewClient("bucket", &Configuration{
+		Region:      defaultRegion,
+		Endpoint:    server.URL,
+		Credentials: &Credentials{AccessKeyID: "id", SecretAccessKey: "secret"},
+	})
+	if err != nil {
+		t.Fatal("unable to create client:", err)
+	}
+	defer client.Close()
+
+	// Store an object with a key that requires escaping.
+	const key = "prefix/the file+ü"
+	if err := client.Put(key, strings.NewReader("content")); err != nil {
+		t.Fatal("unable to store object:", err)
+	}
+	if _, ok := store.objects["/bucket/"+key]; !ok {
+		t.Error("object not stored at expected path")
+	}
+
+	// Retrieve the object.
+	content, size, err := client.Get(key)
+	if err != nil {
+		t.Fatal("unable to retrieve object:", err)
+	}
+	data, err := io.ReadAll(content)
+	content.Close()
+	if err != nil {
+		t.Fatal("unable to read object content:", err)
+	} else if string(data) != "content" || size != uint64(len(data)) {
+		t.Error("object content does not match expected:", string(data), size)
+	}
+
+	// Remove the object and verify that it no longer exists.
+	if err := client.Delete(key); err != nil {
+		t.Fatal("unable to remove object:", err)
+	}
+	if _, _, err := client.Get(key); !errors.Is(err, fs.ErrNotExist) {
+		t.Error("unexpected error retrieving removed object:", err)
+	}
+}
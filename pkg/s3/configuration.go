@@ -0,0 +1,154 @@
+package s3
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+const (
+	// defaultRegion is the region used if none is configured.
+	defaultRegion = "us-east-1"
+	// defaultProfile is the shared credentials profile used if none is
+	// configured.
+	defaultProfile = "default"
+)
+
+// Credentials are the credentials used to sign requests.
+type Credentials struct {
+	// AccessKeyID is the access key identifier.
+	AccessKeyID string
+	// SecretAccessKey is the secret access key.
+	SecretAccessKey string
+	// SessionToken is the session token for temporary credentials. It may be
+	// empty.
+	SessionToken string
+}
+
+// Configuration is the client configuration.
+type Configuration struct {
+	// Region is the region in which the bucket resides.
+	Region string
+	// Endpoint is the base URL of an S3-compatible service. If empty, then the
+	// AWS endpoint for the region is used. If non-empty, then path-style
+	// addressing is used.
+	Endpoint string
+	// Credentials are the credentials used to sign requests.
+	Credentials *Credentials
+}
+
+// LoadConfiguration loads client configuration using the standard AWS
+// environment variables, which are queried using the specified lookup
+// function. If credentials aren't specified in the environment, then they're
+// loaded from the shared credentials file.
+func LoadConfiguration(lookup func(string) (string, bool)) (*Configuration, error) {
+	// Create a helper to grab the first non-empty variable value.
+	get := func(names ...string) string {
+		for _, name := range names {
+			if value, ok := lookup(name); ok && value != "" {
+				return value
+			}
+		}
+		return ""
+	}
+
+	// Determine the region and endpoint.
+	result := &Configuration{
+		Region:   get("AWS_REGION", "AWS_DEFAULT_REGION"),
+		Endpoint: get("AWS_ENDPOINT_URL_S3", "AWS_ENDPOINT_URL"),
+	}
+	if result.Region == "" {
+		result.Region = defaultRegion
+	}
+
+	// Load credentials from the environment, if available.
+	if accessKeyID := get("AWS_ACCESS_KEY_ID"); accessKeyID != "" {
+		result.Credentials = &Credentials{
+			AccessKeyID:     accessKeyID,
+			SecretAccessKey: get("AWS_SECRET_ACCESS_KEY"),
+			SessionToken:    get("AWS_SESSION_TOKEN"),
+		}
+		if result.Credentials.SecretAccessKey == "" {
+			return nil, errors.New("access key identifier specified without secret access key")
+		}
+		return result, nil
+	}
+
+	// Otherwise load credentials from the shared credentials file.
+	path := get("AWS_SHARED_CREDENTIALS_FILE")
+	if path == "" {
+		home, err := os.UserHomeDir()
+		if err != nil {
+			return nil, fmt.Errorf("unable to compute path to home directory: %w", err)
+		}
+		path = filepath.Join(home, ".aws", "credentials")
+	}
+	profile := get("AWS_PROFILE")
+	if profile == "" {
+		profile = defaultProfile
+	}
+	credentials, err := loadSharedCredentials(path, profile)
+	if err != nil {
+		return nil, fmt.Errorf("unable to load shared credentials: %w", err)
+	}
+	result.Credentials = credentials
+
+	// Success.
+	return result, nil
+}
+
+// loadSharedCredentials loads credentials for the specified profile from a
+// shared credentials file.
+func loadSharedCredentials(path, profile string) (*Credentials, error) {
+	// Open the file.
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	// Scan the file for the profile's section and extract its values.
+	var inProfile, found bool
+	credentials := &Credentials{}
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || line[0] == '#' || line[0] == ';' {
+			continue
+		} else if line[0] == '[' && line[len(line)-1] == ']' {
+			inProfile = strings.TrimSpace(line[1:len(line)-1]) == profile
+			found = found || inProfile
+			continue
+		} else if !inProfile {
+			continue
+		}
+		key, value, ok := strings.Cut(line, "=")
+		if !ok {
+			continue
+		}
+		switch strings.TrimSpace(key) {
+		case "aws_access_key_id":
+			credentials.AccessKeyID = strings.TrimSpace(value)
+		case "aws_secret_access_key":
+			credentials.SecretAccessKey = strings.TrimSpace(value)
+		case "aws_session_token":
+			credentials.SessionToken = strings.TrimSpace(value)
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("unable to read file: %w", err)
+	}
+
+	// Validate the result.
+	if !found {
+		return nil, fmt.Errorf("profile not found: %s", profile)
+	} else if credentials.AccessKeyID == "" || credentials.SecretAccessKey == "" {
+		return nil, fmt.Errorf("incomplete credentials for profile: %s", profile)
+	}
+
+	// Success.
+	return credentials, nil
+}
@@ -0,0 +1,4 @@
+// Package s3 provides a minimal client for S3-compatible object storage. It
+// supports only the object operations required by Mutagen's S3 endpoint and
+// authenticates requests using AWS Signature Version 4.
+package s3
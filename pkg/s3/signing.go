@@ -0,0 +1,137 @@
+package s3
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"net/http"
+	"sort"
+	"strings"
+	"time"
+)
+
+const (
+	// signingAlgorithm is the signing algorithm identifier.
+	signingAlgorithm = "AWS4-HMAC-SHA256"
+	// signingService is the service name used in credential scopes.
+	signingService = "s3"
+	// signingTimeFormat is the format used for request timestamps.
+	signingTimeFormat = "20060102T150405Z"
+	// signingDateFormat is the format used for credential scope dates.
+	signingDateFormat = "20060102"
+	// emptyPayloadHash is the SHA-256 digest of an empty payload.
+	emptyPayloadHash = "e3b0c44298fc1c149afbf4c8996fb92427ae41e4649b934ca495991b7852b855"
+)
+
+// hmacSHA256 computes the HMAC-SHA256 of data using the specified key.
+func hmacSHA256(key []byte, data string) []byte {
+	h := hmac.New(sha256.New, key)
+	h.Write([]byte(data))
+	return h.Sum(nil)
+}
+
+// uriEncode encodes a string as required by Signature Version 4, escaping all
+// characters other than unreserved characters. If preserveSlashes is true,
+// then forward slashes are not escaped.
+func uriEncode(value string, preserveSlashes bool) string {
+	var builder strings.Builder
+	for i := 0; i < len(value); i++ {
+		c := value[i]
+		if ('A' <= c && c <= 'Z') || ('a' <= c && c <= 'z') || ('0' <= c && c <= '9') ||
+			c == '-' || c == '_' || c == '.' || c == '~' || (c == '/' && preserveSlashes) {
+			builder.WriteByte(c)
+		} else {
+			fmt.Fprintf(&builder, "%%%02X", c)
+		}
+	}
+	return builder.String()
+}
+
+// canonicalQuery computes the canonical query string for a request.
+func canonicalQuery(request *http.Request) string {
+	query := request.URL.Query()
+	parameters := make([]string, 0, len(query))
+	for key, values := range query {
+		for _, value := range values {
+			parameters = append(parameters, uriEncode(key, false)+"="+uriEncode(value, false))
+		}
+	}
+	sort.Strings(parameters)
+	return strings.Join(parameters, "&")
+}
+
+// sign signs a request using Signature Version 4. All headers present on the
+// request (along with the host header) are included in the signature. The
+// payload hash must be the hex-encoded SHA-256 digest of the request body.
+func sign(request *http.Request, credentials *Credentials, region, payloadHash string, now time.Time) {
+	// Set the headers required for signing.
+	now = now.UTC()
+	timestamp := now.Format(signingTimeFormat)
+	request.Header.Set("X-Amz-Date", timestamp)
+	request.Header.Set("X-Amz-Content-Sha256", payloadHash)
+	if credentials.SessionToken != "" {
+		request.Header.Set("X-Amz-Security-Token", credentials.SessionToken)
+	}
+
+	// Compute the canonical headers.
+	host := request.Host
+	if host == "" {
+		host = request.URL.Host
+	}
+	headers := map[string]string{"host": host}
+	for name, values := range request.Header {
+		trimmed := make([]string, len(values))
+		for v, value := range values {
+			trimmed[v] = strings.Join(strings.Fields(value), " ")
+		}
+		headers[strings.ToLower(name)] = strings.Join(trimmed, ",")
+	}
+	names := make([]string, 0, len(headers))
+	for name := range headers {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	var canonicalHeaders strings.Builder
+	for _, name := range names {
+		canonicalHeaders.WriteString(name)
+		canonicalHeaders.WriteByte(':')
+		canonicalHeaders.WriteString(headers[name])
+		canonicalHeaders.WriteByte('\n')
+	}
+	signedHeaders := strings.Join(names, ";")
+
+	// Compute the canonical request.
+	canonicalRequest := strings.Join([]string{
+		request.Method,
+		request.URL.EscapedPath(),
+		canonicalQuery(request),
+		canonicalHeaders.String(),
+		signedHeaders,
+		payloadHash,
+	}, "\n")
+	canonicalRequestHash := sha256.Sum256([]byte(canonicalRequest))
+
+	// Compute the string to sign.
+	date := now.Format(signingDateFormat)
+	scope := strings.Join([]string{date, region, signingService, "aws4_request"}, "/")
+	stringToSign := strings.Join([]string{
+		signingAlgorithm,
+		timestamp,
+		scope,
+		hex.EncodeToString(canonicalRequestHash[:]),
+	}, "\n")
+
+	// Derive the signing key and compute the signature.
+	key := hmacSHA256([]byte("AWS4"+credentials.SecretAccessKey), date)
+	key = hmacSHA256(key, region)
+	key = hmacSHA256(key, signingService)
+	key = hmacSHA256(key, "aws4_request")
+	signature := hex.EncodeToString(hmacSHA256(key, stringToSign))
+
+	// Set the authorization header.
+	request.Header.Set("Authorization", fmt.Sprintf(
+		"%s Credential=%s/%s, SignedHeaders=%s, Signature=%s",
+		signingAlgorithm, credentials.AccessKeyID, scope, signedHeaders, signature,
+	))
+}
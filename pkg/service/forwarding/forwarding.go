@@ -4,6 +4,7 @@ import (
 	"errors"
 	"fmt"
 
+	"github.com/mutagen-io/mutagen/pkg/forwarding"
 	"github.com/mutagen-io/mutagen/pkg/selection"
 	"github.com/mutagen-io/mutagen/pkg/url"
 )
@@ -44,6 +45,11 @@ func (s *CreationSpecification) ensureValid() error {
 		return fmt.Errorf("invalid destination-specific configuration: %w", err)
 	}
 
+	// Verify that the source and destination are compatible.
+	if err := forwarding.EnsureEndpointsCompatible(s.Source, s.Destination, s.Configuration); err != nil {
+		return fmt.Errorf("incompatible endpoints: %w", err)
+	}
+
 	// Verify that the name is valid.
 	if err := selection.EnsureNameValid(s.Name); err != nil {
 		return fmt.Errorf("invalid name: %w", err)
@@ -85,8 +85,13 @@ func (s *Server) Prompt(ctx context.Context, request *PromptRequest) (*PromptRes
 	// TODO: Should we build cancellation into the Prompter interface itself?
 	asyncResponse := make(chan asyncPromptResponse, 1)
 	go func() {
-		response, err := prompting.Prompt(request.Prompter, request.Prompt)
-		asyncResponse <- asyncPromptResponse{response, err}
+		if request.Message {
+			err := prompting.Message(request.Prompter, request.Prompt)
+			asyncResponse <- asyncPromptResponse{"", err}
+		} else {
+			response, err := prompting.Prompt(request.Prompter, request.Prompt)
+			asyncResponse <- asyncPromptResponse{response, err}
+		}
 	}()
 
 	// Wait for a response or cancellation.
@@ -7,6 +7,12 @@ import (
 	"github.com/mutagen-io/mutagen/pkg/synchronization"
 )
 
+const (
+	// eventsBufferSize is the number of change events that will be buffered
+	// for each events stream before further events are dropped.
+	eventsBufferSize = 64
+)
+
 // Server provides an implementation of the Synchronization service.
 type Server struct {
 	// UnimplementedSynchronizationServer is the required base implementation.
@@ -58,7 +64,7 @@ func (s *Server) List(ctx context.Context, request *ListRequest) (*ListResponse,
 	}
 
 	// Perform listing.
-	stateIndex, states, err := s.manager.List(ctx, request.Selection, request.PreviousStateIndex)
+	stateIndex, states, err := s.manager.List(ctx, request.Selection, request.PreviousStateIndex, request.ListOffset, request.ListLimit)
 	if err != nil {
 		return nil, err
 	}
@@ -86,6 +92,120 @@ func (s *Server) Flush(ctx context.Context, request *FlushRequest) (*FlushRespon
 	return &FlushResponse{}, nil
 }
 
+// Verify verifies sessions.
+func (s *Server) Verify(ctx context.Context, request *VerifyRequest) (*VerifyResponse, error) {
+	// Validate the request.
+	if err := request.ensureValid(); err != nil {
+		return nil, fmt.Errorf("invalid verify request: %w", err)
+	}
+
+	// Perform verification.
+	results, err := s.manager.Verify(ctx, request.Selection, request.Prompter)
+	if err != nil {
+		return nil, err
+	}
+
+	// Success.
+	return &VerifyResponse{Results: results}, nil
+}
+
+// Repair restores diverged content in sessions.
+func (s *Server) Repair(ctx context.Context, request *RepairRequest) (*RepairResponse, error) {
+	// Validate the request.
+	if err := request.ensureValid(); err != nil {
+		return nil, fmt.Errorf("invalid repair request: %w", err)
+	}
+
+	// Perform repair.
+	results, err := s.manager.Repair(ctx, request.Selection, request.Paths, request.Prompter)
+	if err != nil {
+		return nil, err
+	}
+
+	// Success.
+	return &RepairResponse{Results: results}, nil
+}
+
+// Versions lists and restores retained file versions in sessions.
+func (s *Server) Versions(ctx context.Context, request *VersionsRequest) (*VersionsResponse, error) {
+	// Validate the request.
+	if err := request.ensureValid(); err != nil {
+		return nil, fmt.Errorf("invalid versions request: %w", err)
+	}
+
+	// Perform the versions operation.
+	results, err := s.manager.Versions(ctx, request.Selection, request.Path, request.Restore, request.Alpha, request.Prompter)
+	if err != nil {
+		return nil, err
+	}
+
+	// Success.
+	return &VersionsResponse{Results: results}, nil
+}
+
+// PathStatus reports the synchronization status of a path.
+func (s *Server) PathStatus(_ context.Context, request *PathStatusRequest) (*PathStatusResponse, error) {
+	// Validate the request.
+	if err := request.ensureValid(); err != nil {
+		return nil, fmt.Errorf("invalid path status request: %w", err)
+	}
+
+	// Perform the path status operation.
+	results, err := s.manager.PathStatus(request.Selection, request.Path)
+	if err != nil {
+		return nil, err
+	}
+
+	// Success.
+	return &PathStatusResponse{Results: results}, nil
+}
+
+// Events streams session change events.
+func (s *Server) Events(request *EventsRequest, stream Synchronization_EventsServer) error {
+	// Validate the request.
+	if err := request.ensureValid(); err != nil {
+		return fmt.Errorf("invalid events request: %w", err)
+	}
+
+	// Create a context to regulate the subscription and defer its cancellation.
+	ctx, cancel := context.WithCancel(stream.Context())
+	defer cancel()
+
+	// Start the subscription in a separate Goroutine. It will terminate with an
+	// error if session selection fails or if we fall behind in forwarding
+	// events, or without an error once the context is cancelled.
+	events := make(chan *synchronization.ChangeEvent, eventsBufferSize)
+	subscriptionErrors := make(chan error, 1)
+	go func() {
+		subscriptionErrors <- s.manager.Events(ctx, request.Selection, events)
+	}()
+
+	// Forward events until the subscription terminates.
+	for {
+		select {
+		case event := <-events:
+			if err := stream.Send(&EventsResponse{Event: event}); err != nil {
+				return fmt.Errorf("unable to send event: %w", err)
+			}
+		case err := <-subscriptionErrors:
+			// Forward any events that were buffered before the subscription
+			// terminated so that the client sees everything prior to the
+			// failure.
+			for {
+				select {
+				case event := <-events:
+					if sendErr := stream.Send(&EventsResponse{Event: event}); sendErr != nil {
+						return fmt.Errorf("unable to send event: %w", sendErr)
+					}
+					continue
+				default:
+				}
+				return err
+			}
+		}
+	}
+}
+
 // Pause pauses sessions.
 func (s *Server) Pause(ctx context.Context, request *PauseRequest) (*PauseResponse, error) {
 	// Validate the request.
@@ -118,6 +238,22 @@ func (s *Server) Resume(ctx context.Context, request *ResumeRequest) (*ResumeRes
 	return &ResumeResponse{}, nil
 }
 
+// AcceptRootChange accepts pending root changes for sessions.
+func (s *Server) AcceptRootChange(ctx context.Context, request *AcceptRootChangeRequest) (*AcceptRootChangeResponse, error) {
+	// Validate the request.
+	if err := request.ensureValid(); err != nil {
+		return nil, fmt.Errorf("invalid accept root change request: %w", err)
+	}
+
+	// Perform acceptance.
+	if err := s.manager.AcceptRootChange(ctx, request.Selection, request.Prompter); err != nil {
+		return nil, err
+	}
+
+	// Success.
+	return &AcceptRootChangeResponse{}, nil
+}
+
 // Reset resets sessions.
 func (s *Server) Reset(ctx context.Context, request *ResetRequest) (*ResetResponse, error) {
 	// Validate the request.
@@ -5,6 +5,7 @@ import (
 	"fmt"
 
 	"github.com/mutagen-io/mutagen/pkg/selection"
+	"github.com/mutagen-io/mutagen/pkg/synchronization/core"
 	"github.com/mutagen-io/mutagen/pkg/url"
 )
 
@@ -171,6 +172,215 @@ func (r *FlushResponse) EnsureValid() error {
 	return nil
 }
 
+// ensureValid verifies that a VerifyRequest is valid.
+func (r *VerifyRequest) ensureValid() error {
+	// A nil verify request is not valid.
+	if r == nil {
+		return errors.New("nil verify request")
+	}
+
+	// Ensure that a prompter has been specified.
+	if r.Prompter == "" {
+		return errors.New("no prompter specified")
+	}
+
+	// Ensure that the session selection is valid.
+	if err := r.Selection.EnsureValid(); err != nil {
+		return fmt.Errorf("invalid selection specification: %w", err)
+	}
+
+	// Success.
+	return nil
+}
+
+// EnsureValid verifies that a VerifyResponse is valid.
+func (r *VerifyResponse) EnsureValid() error {
+	// A nil verify response is not valid.
+	if r == nil {
+		return errors.New("nil verify response")
+	}
+
+	// Ensure that all results are valid.
+	for _, result := range r.Results {
+		if err := result.EnsureValid(); err != nil {
+			return fmt.Errorf("invalid verification result: %w", err)
+		}
+	}
+
+	// Success.
+	return nil
+}
+
+// ensureValid verifies that a RepairRequest is valid.
+func (r *RepairRequest) ensureValid() error {
+	// A nil repair request is not valid.
+	if r == nil {
+		return errors.New("nil repair request")
+	}
+
+	// Ensure that a prompter has been specified.
+	if r.Prompter == "" {
+		return errors.New("no prompter specified")
+	}
+
+	// Ensure that the session selection is valid.
+	if err := r.Selection.EnsureValid(); err != nil {
+		return fmt.Errorf("invalid selection specification: %w", err)
+	}
+
+	// Ensure that at least one path has been specified and that all paths are
+	// valid.
+	if len(r.Paths) == 0 {
+		return errors.New("no paths specified")
+	}
+	for _, path := range r.Paths {
+		if err := core.ValidatePath(path); err != nil {
+			return fmt.Errorf("invalid path (%s): %w", path, err)
+		}
+	}
+
+	// Success.
+	return nil
+}
+
+// EnsureValid verifies that a RepairResponse is valid.
+func (r *RepairResponse) EnsureValid() error {
+	// A nil repair response is not valid.
+	if r == nil {
+		return errors.New("nil repair response")
+	}
+
+	// Ensure that all results are valid.
+	for _, result := range r.Results {
+		if err := result.EnsureValid(); err != nil {
+			return fmt.Errorf("invalid repair result: %w", err)
+		}
+	}
+
+	// Success.
+	return nil
+}
+
+// ensureValid verifies that a VersionsRequest is valid.
+func (r *VersionsRequest) ensureValid() error {
+	// A nil versions request is not valid.
+	if r == nil {
+		return errors.New("nil versions request")
+	}
+
+	// Ensure that a prompter has been specified.
+	if r.Prompter == "" {
+		return errors.New("no prompter specified")
+	}
+
+	// Ensure that the session selection is valid.
+	if err := r.Selection.EnsureValid(); err != nil {
+		return fmt.Errorf("invalid selection specification: %w", err)
+	}
+
+	// Ensure that a non-root path has been specified and that it's valid.
+	if r.Path == "" {
+		return errors.New("no path specified")
+	} else if err := core.ValidatePath(r.Path); err != nil {
+		return fmt.Errorf("invalid path: %w", err)
+	}
+
+	// Success.
+	return nil
+}
+
+// EnsureValid verifies that a VersionsResponse is valid.
+func (r *VersionsResponse) EnsureValid() error {
+	// A nil versions response is not valid.
+	if r == nil {
+		return errors.New("nil versions response")
+	}
+
+	// Ensure that all results are valid.
+	for _, result := range r.Results {
+		if err := result.EnsureValid(); err != nil {
+			return fmt.Errorf("invalid versions result: %w", err)
+		}
+	}
+
+	// Success.
+	return nil
+}
+
+// ensureValid verifies that a PathStatusRequest is valid.
+func (r *PathStatusRequest) ensureValid() error {
+	// A nil path status request is not valid.
+	if r == nil {
+		return errors.New("nil path status request")
+	}
+
+	// Ensure that the session selection is valid.
+	if err := r.Selection.EnsureValid(); err != nil {
+		return fmt.Errorf("invalid selection specification: %w", err)
+	}
+
+	// Ensure that the path is valid. An empty path indicates the
+	// synchronization root.
+	if r.Path != "" {
+		if err := core.ValidatePath(r.Path); err != nil {
+			return fmt.Errorf("invalid path: %w", err)
+		}
+	}
+
+	// Success.
+	return nil
+}
+
+// EnsureValid verifies that a PathStatusResponse is valid.
+func (r *PathStatusResponse) EnsureValid() error {
+	// A nil path status response is not valid.
+	if r == nil {
+		return errors.New("nil path status response")
+	}
+
+	// Ensure that all results are valid.
+	for _, result := range r.Results {
+		if err := result.EnsureValid(); err != nil {
+			return fmt.Errorf("invalid path status result: %w", err)
+		}
+	}
+
+	// Success.
+	return nil
+}
+
+// ensureValid verifies that an EventsRequest is valid.
+func (r *EventsRequest) ensureValid() error {
+	// A nil events request is not valid.
+	if r == nil {
+		return errors.New("nil events request")
+	}
+
+	// Ensure that the session selection is valid.
+	if err := r.Selection.EnsureValid(); err != nil {
+		return fmt.Errorf("invalid selection specification: %w", err)
+	}
+
+	// Success.
+	return nil
+}
+
+// EnsureValid verifies that an EventsResponse is valid.
+func (r *EventsResponse) EnsureValid() error {
+	// A nil events response is not valid.
+	if r == nil {
+		return errors.New("nil events response")
+	}
+
+	// Ensure that the event is valid.
+	if err := r.Event.EnsureValid(); err != nil {
+		return fmt.Errorf("invalid change event: %w", err)
+	}
+
+	// Success.
+	return nil
+}
+
 // ensureValid verifies that a PauseRequest is valid.
 func (r *PauseRequest) ensureValid() error {
 	// A nil pause request is not valid.
@@ -235,6 +445,38 @@ func (r *ResumeResponse) EnsureValid() error {
 	return nil
 }
 
+// ensureValid verifies that an AcceptRootChangeRequest is valid.
+func (r *AcceptRootChangeRequest) ensureValid() error {
+	// A nil accept root change request is not valid.
+	if r == nil {
+		return errors.New("nil accept root change request")
+	}
+
+	// Ensure that a prompter has been specified.
+	if r.Prompter == "" {
+		return errors.New("no prompter specified")
+	}
+
+	// Ensure that the session selection is valid.
+	if err := r.Selection.EnsureValid(); err != nil {
+		return fmt.Errorf("invalid selection specification: %w", err)
+	}
+
+	// Success.
+	return nil
+}
+
+// EnsureValid verifies that an AcceptRootChangeResponse is valid.
+func (r *AcceptRootChangeResponse) EnsureValid() error {
+	// A nil accept root change response is not valid.
+	if r == nil {
+		return errors.New("nil accept root change response")
+	}
+
+	// Success.
+	return nil
+}
+
 // ensureValid verifies that a ResetRequest is valid.
 func (r *ResetRequest) ensureValid() error {
 	// A nil reset request is not valid.
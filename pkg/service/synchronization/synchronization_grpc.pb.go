@@ -28,10 +28,23 @@ type SynchronizationClient interface {
 	List(ctx context.Context, in *ListRequest, opts ...grpc.CallOption) (*ListResponse, error)
 	// Flush flushes sessions.
 	Flush(ctx context.Context, in *FlushRequest, opts ...grpc.CallOption) (*FlushResponse, error)
+	// Verify verifies sessions' endpoint contents.
+	Verify(ctx context.Context, in *VerifyRequest, opts ...grpc.CallOption) (*VerifyResponse, error)
+	// Repair restores corrupted content in sessions.
+	Repair(ctx context.Context, in *RepairRequest, opts ...grpc.CallOption) (*RepairResponse, error)
+	// Versions lists (and optionally restores) retained versions of a file.
+	Versions(ctx context.Context, in *VersionsRequest, opts ...grpc.CallOption) (*VersionsResponse, error)
+	// PathStatus reports the synchronization status of a path.
+	PathStatus(ctx context.Context, in *PathStatusRequest, opts ...grpc.CallOption) (*PathStatusResponse, error)
+	// Events streams change events for sessions.
+	Events(ctx context.Context, in *EventsRequest, opts ...grpc.CallOption) (Synchronization_EventsClient, error)
 	// Pause pauses sessions.
 	Pause(ctx context.Context, in *PauseRequest, opts ...grpc.CallOption) (*PauseResponse, error)
 	// Resume resumes paused or disconnected sessions.
 	Resume(ctx context.Context, in *ResumeRequest, opts ...grpc.CallOption) (*ResumeResponse, error)
+	// AcceptRootChange approves pending root changes for halted sessions and
+	// resumes them.
+	AcceptRootChange(ctx context.Context, in *AcceptRootChangeRequest, opts ...grpc.CallOption) (*AcceptRootChangeResponse, error)
 	// Reset resets sessions' histories.
 	Reset(ctx context.Context, in *ResetRequest, opts ...grpc.CallOption) (*ResetResponse, error)
 	// Terminate terminates sessions.
@@ -73,6 +86,74 @@ func (c *synchronizationClient) Flush(ctx context.Context, in *FlushRequest, opt
 	return out, nil
 }
 
+func (c *synchronizationClient) Verify(ctx context.Context, in *VerifyRequest, opts ...grpc.CallOption) (*VerifyResponse, error) {
+	out := new(VerifyResponse)
+	err := c.cc.Invoke(ctx, "/synchronization.Synchronization/Verify", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *synchronizationClient) Repair(ctx context.Context, in *RepairRequest, opts ...grpc.CallOption) (*RepairResponse, error) {
+	out := new(RepairResponse)
+	err := c.cc.Invoke(ctx, "/synchronization.Synchronization/Repair", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *synchronizationClient) Versions(ctx context.Context, in *VersionsRequest, opts ...grpc.CallOption) (*VersionsResponse, error) {
+	out := new(VersionsResponse)
+	err := c.cc.Invoke(ctx, "/synchronization.Synchronization/Versions", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *synchronizationClient) PathStatus(ctx context.Context, in *PathStatusRequest, opts ...grpc.CallOption) (*PathStatusResponse, error) {
+	out := new(PathStatusResponse)
+	err := c.cc.Invoke(ctx, "/synchronization.Synchronization/PathStatus", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *synchronizationClient) Events(ctx context.Context, in *EventsRequest, opts ...grpc.CallOption) (Synchronization_EventsClient, error) {
+	stream, err := c.cc.NewStream(ctx, &Synchronization_ServiceDesc.Streams[0], "/synchronization.Synchronization/Events", opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &synchronizationEventsClient{stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+type Synchronization_EventsClient interface {
+	Recv() (*EventsResponse, error)
+	grpc.ClientStream
+}
+
+type synchronizationEventsClient struct {
+	grpc.ClientStream
+}
+
+func (x *synchronizationEventsClient) Recv() (*EventsResponse, error) {
+	m := new(EventsResponse)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
 func (c *synchronizationClient) Pause(ctx context.Context, in *PauseRequest, opts ...grpc.CallOption) (*PauseResponse, error) {
 	out := new(PauseResponse)
 	err := c.cc.Invoke(ctx, "/synchronization.Synchronization/Pause", in, out, opts...)
@@ -91,6 +172,15 @@ func (c *synchronizationClient) Resume(ctx context.Context, in *ResumeRequest, o
 	return out, nil
 }
 
+func (c *synchronizationClient) AcceptRootChange(ctx context.Context, in *AcceptRootChangeRequest, opts ...grpc.CallOption) (*AcceptRootChangeResponse, error) {
+	out := new(AcceptRootChangeResponse)
+	err := c.cc.Invoke(ctx, "/synchronization.Synchronization/AcceptRootChange", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 func (c *synchronizationClient) Reset(ctx context.Context, in *ResetRequest, opts ...grpc.CallOption) (*ResetResponse, error) {
 	out := new(ResetResponse)
 	err := c.cc.Invoke(ctx, "/synchronization.Synchronization/Reset", in, out, opts...)
@@ -119,10 +209,23 @@ type SynchronizationServer interface {
 	List(context.Context, *ListRequest) (*ListResponse, error)
 	// Flush flushes sessions.
 	Flush(context.Context, *FlushRequest) (*FlushResponse, error)
+	// Verify verifies sessions' endpoint contents.
+	Verify(context.Context, *VerifyRequest) (*VerifyResponse, error)
+	// Repair restores corrupted content in sessions.
+	Repair(context.Context, *RepairRequest) (*RepairResponse, error)
+	// Versions lists (and optionally restores) retained versions of a file.
+	Versions(context.Context, *VersionsRequest) (*VersionsResponse, error)
+	// PathStatus reports the synchronization status of a path.
+	PathStatus(context.Context, *PathStatusRequest) (*PathStatusResponse, error)
+	// Events streams change events for sessions.
+	Events(*EventsRequest, Synchronization_EventsServer) error
 	// Pause pauses sessions.
 	Pause(context.Context, *PauseRequest) (*PauseResponse, error)
 	// Resume resumes paused or disconnected sessions.
 	Resume(context.Context, *ResumeRequest) (*ResumeResponse, error)
+	// AcceptRootChange approves pending root changes for halted sessions and
+	// resumes them.
+	AcceptRootChange(context.Context, *AcceptRootChangeRequest) (*AcceptRootChangeResponse, error)
 	// Reset resets sessions' histories.
 	Reset(context.Context, *ResetRequest) (*ResetResponse, error)
 	// Terminate terminates sessions.
@@ -143,12 +246,30 @@ func (UnimplementedSynchronizationServer) List(context.Context, *ListRequest) (*
 func (UnimplementedSynchronizationServer) Flush(context.Context, *FlushRequest) (*FlushResponse, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method Flush not implemented")
 }
+func (UnimplementedSynchronizationServer) Verify(context.Context, *VerifyRequest) (*VerifyResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Verify not implemented")
+}
+func (UnimplementedSynchronizationServer) Repair(context.Context, *RepairRequest) (*RepairResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Repair not implemented")
+}
+func (UnimplementedSynchronizationServer) Versions(context.Context, *VersionsRequest) (*VersionsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Versions not implemented")
+}
+func (UnimplementedSynchronizationServer) PathStatus(context.Context, *PathStatusRequest) (*PathStatusResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method PathStatus not implemented")
+}
+func (UnimplementedSynchronizationServer) Events(*EventsRequest, Synchronization_EventsServer) error {
+	return status.Errorf(codes.Unimplemented, "method Events not implemented")
+}
 func (UnimplementedSynchronizationServer) Pause(context.Context, *PauseRequest) (*PauseResponse, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method Pause not implemented")
 }
 func (UnimplementedSynchronizationServer) Resume(context.Context, *ResumeRequest) (*ResumeResponse, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method Resume not implemented")
 }
+func (UnimplementedSynchronizationServer) AcceptRootChange(context.Context, *AcceptRootChangeRequest) (*AcceptRootChangeResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method AcceptRootChange not implemented")
+}
 func (UnimplementedSynchronizationServer) Reset(context.Context, *ResetRequest) (*ResetResponse, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method Reset not implemented")
 }
@@ -222,6 +343,99 @@ func _Synchronization_Flush_Handler(srv interface{}, ctx context.Context, dec fu
 	return interceptor(ctx, in, info, handler)
 }
 
+func _Synchronization_Verify_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(VerifyRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(SynchronizationServer).Verify(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/synchronization.Synchronization/Verify",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(SynchronizationServer).Verify(ctx, req.(*VerifyRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Synchronization_Repair_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(RepairRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(SynchronizationServer).Repair(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/synchronization.Synchronization/Repair",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(SynchronizationServer).Repair(ctx, req.(*RepairRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Synchronization_Versions_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(VersionsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(SynchronizationServer).Versions(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/synchronization.Synchronization/Versions",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(SynchronizationServer).Versions(ctx, req.(*VersionsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Synchronization_PathStatus_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(PathStatusRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(SynchronizationServer).PathStatus(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/synchronization.Synchronization/PathStatus",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(SynchronizationServer).PathStatus(ctx, req.(*PathStatusRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Synchronization_Events_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(EventsRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(SynchronizationServer).Events(m, &synchronizationEventsServer{stream})
+}
+
+type Synchronization_EventsServer interface {
+	Send(*EventsResponse) error
+	grpc.ServerStream
+}
+
+type synchronizationEventsServer struct {
+	grpc.ServerStream
+}
+
+func (x *synchronizationEventsServer) Send(m *EventsResponse) error {
+	return x.ServerStream.SendMsg(m)
+}
+
 func _Synchronization_Pause_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
 	in := new(PauseRequest)
 	if err := dec(in); err != nil {
@@ -258,6 +472,24 @@ func _Synchronization_Resume_Handler(srv interface{}, ctx context.Context, dec f
 	return interceptor(ctx, in, info, handler)
 }
 
+func _Synchronization_AcceptRootChange_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(AcceptRootChangeRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(SynchronizationServer).AcceptRootChange(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/synchronization.Synchronization/AcceptRootChange",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(SynchronizationServer).AcceptRootChange(ctx, req.(*AcceptRootChangeRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 func _Synchronization_Reset_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
 	in := new(ResetRequest)
 	if err := dec(in); err != nil {
@@ -313,6 +545,22 @@ var Synchronization_ServiceDesc = grpc.ServiceDesc{
 			MethodName: "Flush",
 			Handler:    _Synchronization_Flush_Handler,
 		},
+		{
+			MethodName: "Verify",
+			Handler:    _Synchronization_Verify_Handler,
+		},
+		{
+			MethodName: "Repair",
+			Handler:    _Synchronization_Repair_Handler,
+		},
+		{
+			MethodName: "Versions",
+			Handler:    _Synchronization_Versions_Handler,
+		},
+		{
+			MethodName: "PathStatus",
+			Handler:    _Synchronization_PathStatus_Handler,
+		},
 		{
 			MethodName: "Pause",
 			Handler:    _Synchronization_Pause_Handler,
@@ -321,6 +569,10 @@ var Synchronization_ServiceDesc = grpc.ServiceDesc{
 			MethodName: "Resume",
 			Handler:    _Synchronization_Resume_Handler,
 		},
+		{
+			MethodName: "AcceptRootChange",
+			Handler:    _Synchronization_AcceptRootChange_Handler,
+		},
 		{
 			MethodName: "Reset",
 			Handler:    _Synchronization_Reset_Handler,
@@ -330,6 +582,12 @@ var Synchronization_ServiceDesc = grpc.ServiceDesc{
 			Handler:    _Synchronization_Terminate_Handler,
 		},
 	},
-	Streams:  []grpc.StreamDesc{},
+	Streams: []grpc.StreamDesc{
+		{
+			StreamName:    "Events",
+			Handler:       _Synchronization_Events_Handler,
+			ServerStreams: true,
+		},
+	},
 	Metadata: "service/synchronization/synchronization.proto",
 }
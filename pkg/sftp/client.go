@@ -0,0 +1,530 @@
+package sftp
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"sync"
+
+	"golang.org/x/crypto/ssh"
+)
+
+const (
+	// protocolVersion is the SFTP protocol version spoken by the client.
+	protocolVersion = 3
+	// maximumPacketSize is the maximum packet size that the client will accept
+	// from the server.
+	maximumPacketSize = 1024 * 1024
+	// maximumDataSize is the maximum number of bytes transferred in a single
+	// read or write request. It's the largest size that all common servers are
+	// guaranteed to support.
+	maximumDataSize = 32 * 1024
+	// posixRenameExtension is the name of the OpenSSH extension that provides
+	// rename operations with POSIX semantics.
+	posixRenameExtension = "posix-rename@openssh.com"
+)
+
+// Packet types.
+const (
+	packetTypeInit     = 1
+	packetTypeVersion  = 2
+	packetTypeOpen     = 3
+	packetTypeClose    = 4
+	packetTypeRead     = 5
+	packetTypeWrite    = 6
+	packetTypeLstat    = 7
+	packetTypeSetstat  = 9
+	packetTypeOpendir  = 11
+	packetTypeReaddir  = 12
+	packetTypeRemove   = 13
+	packetTypeMkdir    = 14
+	packetTypeRmdir    = 15
+	packetTypeRealpath = 16
+	packetTypeRename   = 18
+	packetTypeReadlink = 19
+	packetTypeSymlink  = 20
+	packetTypeStatus   = 101
+	packetTypeHandle   = 102
+	packetTypeData     = 103
+	packetTypeName     = 104
+	packetTypeAttrs    = 105
+	packetTypeExtended = 200
+)
+
+// Status codes.
+const (
+	statusOK               = 0
+	statusEOF              = 1
+	statusNoSuchFile       = 2
+	statusPermissionDenied = 3
+)
+
+// Attribute flags.
+const (
+	attributeFlagSize                   = 0x1
+	attributeFlagUIDGID                 = 0x2
+	attributeFlagPermissions            = 0x4
+	attributeFlagAccessModificationTime = 0x8
+	attributeFlagExtended               = 0x80000000
+)
+
+// File open flags.
+const (
+	openFlagRead     = 0x1
+	openFlagWrite    = 0x2
+	openFlagCreate   = 0x8
+	openFlagTruncate = 0x10
+)
+
+// StatusError represents a failure status returned by an SFTP server.
+type StatusError struct {
+	// Code is the status code.
+	Code uint32
+	// Message is the (human-readable) error message provided by the server.
+	Message string
+}
+
+// Error implements error.Error.
+func (e *StatusError) Error() string {
+	if e.Message != "" {
+		return fmt.Sprintf("sftp: %s (status %d)", e.Message, e.Code)
+	}
+	return fmt.Sprintf("sftp: status %d", e.Code)
+}
+
+// Is allows status errors to be compared against standard filesystem errors
+// using errors.Is.
+func (e *StatusError) Is(target error) bool {
+	switch target {
+	case fs.ErrNotExist:
+		return e.Code == statusNoSuchFile
+	case fs.ErrPermission:
+		return e.Code == statusPermissionDenied
+	default:
+		return false
+	}
+}
+
+// Client is an SFTP client. It is safe for concurrent usage, though requests
+// are serialized.
+type Client struct {
+	// lock serializes requests.
+	lock sync.Mutex
+	// reader is the stream from the server.
+	reader io.Reader
+	// writer is the stream to the server.
+	writer io.Writer
+	// closer closes the underlying transport.
+	closer io.Closer
+	// nextID is the next request identifier.
+	nextID uint32
+	// posixRename indicates whether or not the server supports the POSIX
+	// rename extension.
+	posixRename bool
+}
+
+// Dial starts an SFTP session using the specified SSH client. Closing the
+// resulting SFTP client closes the underlying SSH session (but not the SSH
+// client).
+func Dial(client *ssh.Client) (*Client, error) {
+	// Create a session.
+	session, err := client.NewSession()
+	if err != nil {
+		return nil, fmt.Errorf("unable to create session: %w", err)
+	}
+
+	// Grab the session's input and output streams.
+	writer, err := session.StdinPipe()
+	if err != nil {
+		session.Close()
+		return nil, fmt.Errorf("unable to create session input pipe: %w", err)
+	}
+	reader, err := session.StdoutPipe()
+	if err != nil {
+		session.Close()
+		return nil, fmt.Errorf("unable to create session output pipe: %w", err)
+	}
+
+	// Request the SFTP subsystem.
+	if err := session.RequestSubsystem("sftp"); err != nil {
+		session.Close()
+		return nil, fmt.Errorf("unable to start SFTP subsystem: %w", err)
+	}
+
+	// Create the client.
+	result, err := NewClient(reader, writer, session)
+	if err != nil {
+		session.Close()
+		return nil, err
+	}
+
+	// Success.
+	return result, nil
+}
+
+// NewClient creates a new SFTP client using the specified streams, performing
+// the protocol version handshake. The specified closer is used to terminate the
+// underlying transport when the client is closed.
+func NewClient(reader io.Reader, writer io.Writer, closer io.Closer) (*Client, error) {
+	// Create the client.
+	client := &Client{
+		reader: reader,
+		writer: writer,
+		closer: closer,
+	}
+
+	// Send the initialization packet. Unlike other packets, it doesn't contain
+	// a request identifier.
+	initialization := &encoder{}
+	initialization.byte(packetTypeInit)
+	initialization.uint32(protocolVersion)
+	if err := client.writePacket(initialization.buffer); err != nil {
+		return nil, fmt.Errorf("unable to send initialization packet: %w", err)
+	}
+
+	// Receive the version packet.
+	response, err := client.readPacket()
+	if err != nil {
+		return nil, fmt.Errorf("unable to receive version packet: %w", err)
+	} else if response[0] != packetTypeVersion {
+		return nil, fmt.Errorf("unexpected response packet type: %d", response[0])
+	}
+	version := &decoder{data: response[1:]}
+	if v := version.uint32(); version.err != nil {
+		return nil, fmt.Errorf("unable to decode version: %w", version.err)
+	} else if v != protocolVersion {
+		return nil, fmt.Errorf("unsupported protocol version: %d", v)
+	}
+
+	// Record supported extensions.
+	for len(version.data) > 0 && version.err == nil {
+		if name := version.string(); name == posixRenameExtension {
+			client.posixRename = true
+		}
+		version.string()
+	}
+
+	// Success.
+	return client, nil
+}
+
+// writePacket writes a packet (which must be non-empty) to the server.
+func (c *Client) writePacket(packet []byte) error {
+	var length [4]byte
+	binary.BigEndian.PutUint32(length[:], uint32(len(packet)))
+	if _, err := c.writer.Write(append(length[:], packet...)); err != nil {
+		return err
+	}
+	return nil
+}
+
+// readPacket reads a packet from the server. The result is guaranteed to be
+// non-empty.
+func (c *Client) readPacket() ([]byte, error) {
+	var length [4]byte
+	if _, err := io.ReadFull(c.reader, length[:]); err != nil {
+		return nil, err
+	}
+	size := binary.BigEndian.Uint32(length[:])
+	if size == 0 {
+		return nil, errors.New("empty packet")
+	} else if size > maximumPacketSize {
+		return nil, errors.New("packet too large")
+	}
+	packet := make([]byte, size)
+	if _, err := io.ReadFull(c.reader, packet); err != nil {
+		return nil, err
+	}
+	return packet, nil
+}
+
+// request performs a request with the specified packet type, using the
+// specified function to encode the request fields. It returns the response
+// packet type and a decoder for the response fields.
+func (c *Client) request(packetType byte, encode func(*encoder)) (byte, *decoder, error) {
+	// Serialize requests.
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	// Allocate a request identifier.
+	id := c.nextID
+	c.nextID++
+
+	// Encode and send the request.
+	request := &encoder{}
+	request.byte(packetType)
+	request.uint32(id)
+	encode(request)
+	if err := c.writePacket(request.buffer); err != nil {
+		return 0, nil, fmt.Errorf("unable to send request: %w", err)
+	}
+
+	// Receive and validate the response.
+	response, err := c.readPacket()
+	if err != nil {
+		return 0, nil, fmt.Errorf("unable to receive response: %w", err)
+	}
+	fields := &decoder{data: response[1:]}
+	if responseID := fields.uint32(); fields.err != nil {
+		return 0, nil, fmt.Errorf("unable to decode response identifier: %w", fields.err)
+	} else if responseID != id {
+		return 0, nil, errors.New("response identifier mismatch")
+	}
+
+	// Success.
+	return response[0], fields, nil
+}
+
+// status converts a status response to an error. It returns nil for successful
+// statuses and io.EOF for end-of-file statuses. If the response isn't a status
+// response, then an error is returned.
+func status(packetType byte, fields *decoder) error {
+	if packetType != packetTypeStatus {
+		return fmt.Errorf("unexpected response packet type: %d", packetType)
+	}
+	code := fields.uint32()
+	message := fields.string()
+	if fields.err != nil {
+		return fmt.Errorf("unable to decode status: %w", fields.err)
+	} else if code == statusOK {
+		return nil
+	} else if code == statusEOF {
+		return io.EOF
+	}
+	return &StatusError{Code: code, Message: message}
+}
+
+// failure converts a response that wasn't of the expected type to an error.
+func failure(packetType byte, fields *decoder) error {
+	if err := status(packetType, fields); err != nil {
+		return err
+	}
+	return errors.New("unexpected success status")
+}
+
+// simpleRequest performs a request that expects a status response.
+func (c *Client) simpleRequest(packetType byte, encode func(*encoder)) error {
+	responseType, fields, err := c.request(packetType, encode)
+	if err != nil {
+		return err
+	}
+	return status(responseType, fields)
+}
+
+// handleRequest performs a request that expects a handle response.
+func (c *Client) handleRequest(packetType byte, encode func(*encoder)) (string, error) {
+	responseType, fields, err := c.request(packetType, encode)
+	if err != nil {
+		return "", err
+	} else if responseType != packetTypeHandle {
+		return "", failure(responseType, fields)
+	}
+	handle := fields.string()
+	if fields.err != nil {
+		return "", fmt.Errorf("unable to decode handle: %w", fields.err)
+	}
+	return handle, nil
+}
+
+// nameRequest performs a request that expects a name response containing a
+// single name.
+func (c *Client) nameRequest(packetType byte, path string) (string, error) {
+	responseType, fields, err := c.request(packetType, func(e *encoder) {
+		e.string(path)
+	})
+	if err != nil {
+		return "", err
+	} else if responseType != packetTypeName {
+		return "", failure(responseType, fields)
+	}
+	if count := fields.uint32(); fields.err == nil && count != 1 {
+		return "", fmt.Errorf("unexpected name count: %d", count)
+	}
+	name := fields.string()
+	if fields.err != nil {
+		return "", fmt.Errorf("unable to decode name: %w", fields.err)
+	}
+	return name, nil
+}
+
+// closeHandle closes a file or directory handle.
+func (c *Client) closeHandle(handle string) error {
+	return c.simpleRequest(packetTypeClose, func(e *encoder) {
+		e.string(handle)
+	})
+}
+
+// Lstat returns information about the specified path without following
+// symbolic links. The name in the result is empty.
+func (c *Client) Lstat(path string) (*FileInfo, error) {
+	responseType, fields, err := c.request(packetTypeLstat, func(e *encoder) {
+		e.string(path)
+	})
+	if err != nil {
+		return nil, err
+	} else if responseType != packetTypeAttrs {
+		return nil, failure(responseType, fields)
+	}
+	info := fields.attributes()
+	if fields.err != nil {
+		return nil, fmt.Errorf("unable to decode attributes: %w", fields.err)
+	}
+	return info, nil
+}
+
+// ReadDirectory returns information about the contents of the specified
+// directory, excluding the "." and ".." entries. The result is unordered and
+// symbolic links are not followed.
+func (c *Client) ReadDirectory(path string) ([]*FileInfo, error) {
+	// Open the directory and defer closure of its handle.
+	handle, err := c.handleRequest(packetTypeOpendir, func(e *encoder) {
+		e.string(path)
+	})
+	if err != nil {
+		return nil, err
+	}
+	defer c.closeHandle(handle)
+
+	// Read contents until the server indicates that there are no more.
+	var results []*FileInfo
+	for {
+		responseType, fields, err := c.request(packetTypeReaddir, func(e *encoder) {
+			e.string(handle)
+		})
+		if err != nil {
+			return nil, err
+		} else if responseType != packetTypeName {
+			if err := status(responseType, fields); err == io.EOF {
+				return results, nil
+			} else if err != nil {
+				return nil, err
+			}
+			return nil, errors.New("unexpected success status")
+		}
+		count := fields.uint32()
+		for i := uint32(0); i < count && fields.err == nil; i++ {
+			name := fields.string()
+			fields.string()
+			info := fields.attributes()
+			if name != "." && name != ".." {
+				info.Name = name
+				results = append(results, info)
+			}
+		}
+		if fields.err != nil {
+			return nil, fmt.Errorf("unable to decode names: %w", fields.err)
+		}
+	}
+}
+
+// ReadLink returns the target of the specified symbolic link.
+func (c *Client) ReadLink(path string) (string, error) {
+	return c.nameRequest(packetTypeReadlink, path)
+}
+
+// RealPath canonicalizes the specified path on the server. Relative paths are
+// resolved relative to the server's default directory (typically the user's
+// home directory).
+func (c *Client) RealPath(path string) (string, error) {
+	return c.nameRequest(packetTypeRealpath, path)
+}
+
+// Symlink creates a symbolic link at the specified path with the specified
+// target.
+func (c *Client) Symlink(target, path string) error {
+	// OpenSSH's server (which is by far the most common) inverted the order of
+	// the arguments relative to the specification, and other servers have
+	// adopted its ordering for compatibility, so we use that ordering.
+	return c.simpleRequest(packetTypeSymlink, func(e *encoder) {
+		e.string(target)
+		e.string(path)
+	})
+}
+
+// Mkdir creates a directory with the specified permissions.
+func (c *Client) Mkdir(path string, permissions uint32) error {
+	return c.simpleRequest(packetTypeMkdir, func(e *encoder) {
+		e.string(path)
+		e.permissions(permissions)
+	})
+}
+
+// Remove removes the specified file or symbolic link.
+func (c *Client) Remove(path string) error {
+	return c.simpleRequest(packetTypeRemove, func(e *encoder) {
+		e.string(path)
+	})
+}
+
+// RemoveDirectory removes the specified (empty) directory.
+func (c *Client) RemoveDirectory(path string) error {
+	return c.simpleRequest(packetTypeRmdir, func(e *encoder) {
+		e.string(path)
+	})
+}
+
+// Rename renames the specified path, replacing any existing file at the new
+// path. If the server doesn't support POSIX rename semantics, then replacement
+// isn't atomic.
+func (c *Client) Rename(oldPath, newPath string) error {
+	// If the server supports POSIX renames, then use them.
+	if c.posixRename {
+		return c.simpleRequest(packetTypeExtended, func(e *encoder) {
+			e.string(posixRenameExtension)
+			e.string(oldPath)
+			e.string(newPath)
+		})
+	}
+
+	// Otherwise, the standard rename operation will fail if the destination
+	// exists, so remove it first.
+	if err := c.Remove(newPath); err != nil && !errors.Is(err, fs.ErrNotExist) {
+		return fmt.Errorf("unable to remove existing file: %w", err)
+	}
+	return c.simpleRequest(packetTypeRename, func(e *encoder) {
+		e.string(oldPath)
+		e.string(newPath)
+	})
+}
+
+// Chmod sets the permissions of the specified path.
+func (c *Client) Chmod(path string, permissions uint32) error {
+	return c.simpleRequest(packetTypeSetstat, func(e *encoder) {
+		e.string(path)
+		e.permissions(permissions)
+	})
+}
+
+// Open opens the specified file for reading.
+func (c *Client) Open(path string) (*File, error) {
+	handle, err := c.handleRequest(packetTypeOpen, func(e *encoder) {
+		e.string(path)
+		e.uint32(openFlagRead)
+		e.uint32(0)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &File{client: c, handle: handle}, nil
+}
+
+// Create opens the specified file for writing, creating it with the specified
+// permissions if it doesn't exist and truncating it if it does.
+func (c *Client) Create(path string, permissions uint32) (*File, error) {
+	handle, err := c.handleRequest(packetTypeOpen, func(e *encoder) {
+		e.string(path)
+		e.uint32(openFlagWrite | openFlagCreate | openFlagTruncate)
+		e.permissions(permissions)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &File{client: c, handle: handle}, nil
+}
+
+// Close terminates the client and its underlying transport.
+func (c *Client) Close() error {
+	return c.closer.Close()
+}
@@ -0,0 +1,427 @@
+package sftp
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"io"
+	"io/fs"
+	pathpkg "path"
+	"sort"
+	"strconv"
+	"strings"
+	"testing"
+)
+
+// testHomeDirectory is the home directory reported by testServer.
+const testHomeDirectory = "/home/user"
+
+// testNode is a filesystem node in testServer.
+type testNode struct {
+	// mode is the node's POSIX mode, including file type bits.
+	mode uint32
+	// data is the node's content (for files).
+	data []byte
+	// target is the node's target (for symbolic links).
+	target string
+}
+
+// testServer is a minimal in-memory SFTP server for testing.
+type testServer struct {
+	// nodes are the filesystem nodes, keyed by absolute path.
+	nodes map[string]*testNode
+	// handles maps open handles to their paths.
+	handles map[string]string
+	// listed tracks which directory handles have already been listed.
+	listed map[string]bool
+	// nextHandle is the next handle index.
+	nextHandle int
+}
+
+// newTestServer creates a new test server with an empty home directory.
+func newTestServer() *testServer {
+	return &testServer{
+		nodes: map[string]*testNode{
+			"/":               {mode: modeTypeDirectory | 0755},
+			"/home":           {mode: modeTypeDirectory | 0755},
+			testHomeDirectory: {mode: modeTypeDirectory | 0755},
+		},
+		handles: make(map[string]string),
+		listed:  make(map[string]bool),
+	}
+}
+
+// resolve converts a request path to an absolute path.
+func (s *testServer) resolve(path string) string {
+	if !strings.HasPrefix(path, "/") {
+		path = testHomeDirectory + "/" + path
+	}
+	return pathpkg.Clean(path)
+}
+
+// attributes encodes the attributes for a node.
+func (s *testServer) attributes(e *encoder, node *testNode) {
+	e.uint32(attributeFlagSize | attributeFlagPermissions | attributeFlagAccessModificationTime)
+	e.uint64(uint64(len(node.data)))
+	e.uint32(node.mode)
+	e.uint32(0)
+	e.uint32(1234567890)
+}
+
+// handle processes a single request and returns the response packet.
+func (s *testServer) handle(packetType byte, id uint32, d *decoder) []byte {
+	response := &encoder{}
+	status := func(code uint32) []byte {
+		response.byte(packetTypeStatus)
+		response.uint32(id)
+		response.uint32(code)
+		response.string("")
+		response.string("")
+		return response.buffer
+	}
+	name := func(value string) []byte {
+		response.byte(packetTypeName)
+		response.uint32(id)
+		response.uint32(1)
+		response.string(value)
+		response.string(value)
+		response.uint32(0)
+		return response.buffer
+	}
+	newHandle := func(path string) []byte {
+		handle := strconv.Itoa(s.nextHandle)
+		s.nextHandle++
+		s.handles[handle] = path
+		response.byte(packetTypeHandle)
+		response.uint32(id)
+		response.string(handle)
+		return response.buffer
+	}
+
+	switch packetType {
+	case packetTypeLstat:
+		node, ok := s.nodes[s.resolve(d.string())]
+		if !ok {
+			return status(statusNoSuchFile)
+		}
+		response.byte(packetTypeAttrs)
+		response.uint32(id)
+		s.attributes(response, node)
+		return response.buffer
+	case packetTypeOpendir:
+		path := s.resolve(d.string())
+		if node, ok := s.nodes[path]; !ok {
+			return status(statusNoSuchFile)
+		} else if node.mode&modeTypeMask != modeTypeDirectory {
+			return status(4)
+		}
+		return newHandle(path)
+	case packetTypeReaddir:
+		handle := d.string()
+		if s.listed[handle] {
+			return status(statusEOF)
+		}
+		s.listed[handle] = true
+		directory := s.handles[handle]
+		var names []string
+		for path := range s.nodes {
+			if path != directory && pathpkg.Dir(path) == directory {
+				names = append(names, pathpkg.Base(path))
+			}
+		}
+		sort.Strings(names)
+		names = append(names, ".", "..")
+		response.byte(packetTypeName)
+		response.uint32(id)
+		response.uint32(uint32(len(names)))
+		for _, name := range names {
+			response.string(name)
+			response.string(name)
+			if node, ok := s.nodes[pathpkg.Join(directory, name)]; ok && name != "." && name != ".." {
+				s.attributes(response, node)
+			} else {
+				response.uint32(0)
+			}
+		}
+		return response.buffer
+	case packetTypeClose:
+		handle := d.string()
+		if _, ok := s.handles[handle]; !ok {
+			return status(4)
+		}
+		delete(s.handles, handle)
+		return status(statusOK)
+	case packetTypeOpen:
+		path := s.resolve(d.string())
+		flags := d.uint32()
+		attributes := d.attributes()
+		node, ok := s.nodes[path]
+		if !ok {
+			if flags&openFlagCreate == 0 {
+				return status(statusNoSuchFile)
+			}
+			node = &testNode{mode: modeTypeRegular | attributes.Permissions()}
+			s.nodes[path] = node
+		}
+		if flags&openFlagTruncate != 0 {
+			node.data = nil
+		}
+		return newHandle(path)
+	case packetTypeRead:
+		node := s.nodes[s.handles[d.string()]]
+		offset := d.uint64()
+		length := d.uint32()
+		if offset >= uint64(len(node.data)) {
+			return status(statusEOF)
+		}
+		end := offset + uint64(length)
+		if end > uint64(len(node.data)) {
+			end = uint64(len(node.data))
+		}
+		response.byte(packetTypeData)
+		response.uint32(id)
+		response.bytes(node.data[offset:end])
+		return response.buffer
+	case packetTypeWrite:
+		node := s.nodes[s.handles[d.string()]]
+		offset := d.uint64()
+		data := d.bytes()
+		if end := offset + uint64(len(data)); end > uint64(len(node.data)) {
+			node.data = append(node.data, make([]byte, end-uint64(len(node.data)))...)
+		}
+		copy(node.data[offset:], data)
+		return status(statusOK)
+	case packetTypeMkdir:
+		path := s.resolve(d.string())
+		if _, ok := s.nodes[path]; ok {
+			return status(4)
+		}
+		s.nodes[path] = &testNode{mode: modeTypeDirectory | d.attributes().Permissions()}
+		return status(statusOK)
+	case packetTypeRemove, packetTypeRmdir:
+		path := s.resolve(d.string())
+		if _, ok := s.nodes[path]; !ok {
+			return status(statusNoSuchFile)
+		}
+		delete(s.nodes, path)
+		return status(statusOK)
+	case packetTypeRealpath:
+		return name(s.resolve(d.string()))
+	case packetTypeReadlink:
+		node, ok := s.nodes[s.resolve(d.string())]
+		if !ok {
+			return status(statusNoSuchFile)
+		}
+		return name(node.target)
+	case packetTypeSymlink:
+		target := d.string()
+		s.nodes[s.resolve(d.string())] = &testNode{mode: modeTypeSymbolicLink | 0777, target: target}
+		return status(statusOK)
+	case packetTypeExtended:
+		if d.string() != posixRenameExtension {
+			return status(8)
+		}
+		oldPath, newPath := s.resolve(d.string()), s.resolve(d.string())
+		node, ok := s.nodes[oldPath]
+		if !ok {
+			return status(statusNoSuchFile)
+		}
+		delete(s.nodes, oldPath)
+		s.nodes[newPath] = node
+		return status(statusOK)
+	case packetTypeSetstat:
+		node, ok := s.nodes[s.resolve(d.string())]
+		if !ok {
+			return status(statusNoSuchFile)
+		}
+		node.mode = node.mode&modeTypeMask | d.attributes().Permissions()
+		return status(statusOK)
+	default:
+		return status(8)
+	}
+}
+
+// serve serves requests until the input stream is closed.
+func (s *testServer) serve(reader io.Reader, writer io.Writer) error {
+	for {
+		// Read the next packet.
+		var length [4]byte
+		if _, err := io.ReadFull(reader, length[:]); err != nil {
+			if err == io.EOF {
+				return nil
+			}
+			return err
+		}
+		packet := make([]byte, binary.BigEndian.Uint32(length[:]))
+		if _, err := io.ReadFull(reader, packet); err != nil {
+			return err
+		}
+
+		// Compute the response.
+		var response []byte
+		d := &decoder{data: packet[1:]}
+		if packet[0] == packetTypeInit {
+			version := &encoder{}
+			version.byte(packetTypeVersion)
+			version.uint32(protocolVersion)
+			version.string(posixRenameExtension)
+			version.string("1")
+			response = version.buffer
+		} else {
+			response = s.handle(packet[0], d.uint32(), d)
+		}
+		if d.err != nil {
+			return d.err
+		}
+
+		// Write the response.
+		binary.BigEndian.PutUint32(length[:], uint32(len(response)))
+		if _, err := writer.Write(append(length[:], response...)); err != nil {
+			return err
+		}
+	}
+}
+
+// newTestClient creates a client connected to the specified test server.
+func newTestClient(t *testing.T, server *testServer) *Client {
+	t.Helper()
+	serverReader, clientWriter := io.Pipe()
+	clientReader, serverWriter := io.Pipe()
+	go func() {
+		serverWriter.CloseWithError(server.serve(serverReader, serverWriter))
+	}()
+	client, err := NewClient(clientReader, clientWriter, clientWriter)
+	if err != nil {
+		t.Fatal("unable to create client:", err)
+	}
+	t.Cleanup(func() {
+		client.Close()
+	})
+	return client
+}
+
+// TestClientFileOperations tests file creation, reading, and metadata queries.
+func TestClientFileOperations(t *testing.T) {
+	// Create a client.
+	client := newTestClient(t, newTestServer())
+
+	// Verify the home directory.
+	if home, err := client.RealPath("."); err != nil {
+		t.Fatal("unable to compute real path:", err)
+	} else if home != testHomeDirectory {
+		t.Error("unexpected home directory:", home)
+	}
+
+	// Create a file with content larger than a single transfer.
+	content := bytes.Repeat([]byte("mutagen"), maximumDataSize/3)
+	if file, err := client.Create("file", 0644); err != nil {
+		t.Fatal("unable to create file:", err)
+	} else if _, err := file.Write(content); err != nil {
+		t.Fatal("unable to write file:", err)
+	} else if err := file.Close(); err != nil {
+		t.Fatal("unable to close file:", err)
+	}
+
+	// Verify the file's metadata.
+	if info, err := client.Lstat("file"); err != nil {
+		t.Fatal("unable to query file metadata:", err)
+	} else if !info.IsRegular() {
+		t.Error("file not identified as regular")
+	} else if info.Size != uint64(len(content)) {
+		t.Error("file size incorrect:", info.Size, "!=", len(content))
+	} else if info.Permissions() != 0644 {
+		t.Errorf("file permissions incorrect: %o", info.Permissions())
+	} else if info.ModificationTime.Unix() != 1234567890 {
+		t.Error("file modification time incorrect:", info.ModificationTime)
+	}
+
+	// Read the file back.
+	file, err := client.Open("file")
+	if err != nil {
+		t.Fatal("unable to open file:", err)
+	}
+	read, err := io.ReadAll(file)
+	file.Close()
+	if err != nil {
+		t.Fatal("unable to read file:", err)
+	} else if !bytes.Equal(read, content) {
+		t.Error("file content does not match")
+	}
+
+	// Update permissions and rename the file.
+	if err := client.Chmod("file", 0755); err != nil {
+		t.Error("unable to change permissions:", err)
+	}
+	if err := client.Rename("file", "renamed"); err != nil {
+		t.Fatal("unable to rename file:", err)
+	} else if info, err := client.Lstat("renamed"); err != nil {
+		t.Error("unable to query renamed file metadata:", err)
+	} else if info.Permissions() != 0755 {
+		t.Errorf("renamed file permissions incorrect: %o", info.Permissions())
+	}
+
+	// Verify that missing files are identified as such.
+	if _, err := client.Lstat("file"); !errors.Is(err, fs.ErrNotExist) {
+		t.Error("missing file not identified as non-existent:", err)
+	}
+	if _, err := client.Open("file"); !errors.Is(err, fs.ErrNotExist) {
+		t.Error("missing file opened or not identified as non-existent:", err)
+	}
+}
+
+// TestClientDirectoryOperations tests directory and symbolic link operations.
+func TestClientDirectoryOperations(t *testing.T) {
+	// Create a client.
+	client := newTestClient(t, newTestServer())
+
+	// Create a directory with a file and a symbolic link.
+	if err := client.Mkdir("directory", 0700); err != nil {
+		t.Fatal("unable to create directory:", err)
+	}
+	if file, err := client.Create("directory/file", 0600); err != nil {
+		t.Fatal("unable to create file:", err)
+	} else if err := file.Close(); err != nil {
+		t.Fatal("unable to close file:", err)
+	}
+	if err := client.Symlink("file", "directory/link"); err != nil {
+		t.Fatal("unable to create symbolic link:", err)
+	}
+
+	// Verify the directory contents.
+	contents, err := client.ReadDirectory("directory")
+	if err != nil {
+		t.Fatal("unable to read directory:", err)
+	} else if len(contents) != 2 {
+		t.Fatal("unexpected directory content count:", len(contents))
+	}
+	sort.Slice(contents, func(i, j int) bool {
+		return contents[i].Name < contents[j].Name
+	})
+	if contents[0].Name != "file" || !contents[0].IsRegular() {
+		t.Error("file not listed correctly")
+	}
+	if contents[1].Name != "link" || !contents[1].IsSymbolicLink() {
+		t.Error("symbolic link not listed correctly")
+	}
+
+	// Verify the symbolic link target.
+	if target, err := client.ReadLink("directory/link"); err != nil {
+		t.Error("unable to read symbolic link:", err)
+	} else if target != "file" {
+		t.Error("symbolic link target incorrect:", target)
+	}
+
+	// Remove the directory contents and the directory itself.
+	if err := client.Remove("directory/link"); err != nil {
+		t.Error("unable to remove symbolic link:", err)
+	}
+	if err := client.Remove("directory/file"); err != nil {
+		t.Error("unable to remove file:", err)
+	}
+	if err := client.RemoveDirectory("directory"); err != nil {
+		t.Error("unable to remove directory:", err)
+	}
+	if _, err := client.ReadDirectory("directory"); !errors.Is(err, fs.ErrNotExist) {
+		t.Error("removed directory not identified as non-existent:", err)
+	}
+}
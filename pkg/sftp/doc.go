@@ -0,0 +1,4 @@
+// Package sftp provides a minimal client implementation of version 3 of the SSH
+// File Transfer Protocol (SFTP), supporting the subset of operations needed to
+// synchronize files on hosts where Mutagen's agent can't be executed.
+package sftp
@@ -0,0 +1,142 @@
+package sftp
+
+import (
+	"encoding/binary"
+	"errors"
+	"time"
+)
+
+// errTruncatedPacket indicates that a packet ended before all of its expected
+// fields could be decoded.
+var errTruncatedPacket = errors.New("truncated packet")
+
+// encoder encodes SFTP packet fields.
+type encoder struct {
+	// buffer is the encoded data.
+	buffer []byte
+}
+
+// byte encodes a single byte.
+func (e *encoder) byte(value byte) {
+	e.buffer = append(e.buffer, value)
+}
+
+// uint32 encodes a 32-bit unsigned integer.
+func (e *encoder) uint32(value uint32) {
+	var encoded [4]byte
+	binary.BigEndian.PutUint32(encoded[:], value)
+	e.buffer = append(e.buffer, encoded[:]...)
+}
+
+// uint64 encodes a 64-bit unsigned integer.
+func (e *encoder) uint64(value uint64) {
+	var encoded [8]byte
+	binary.BigEndian.PutUint64(encoded[:], value)
+	e.buffer = append(e.buffer, encoded[:]...)
+}
+
+// string encodes a length-prefixed string.
+func (e *encoder) string(value string) {
+	e.uint32(uint32(len(value)))
+	e.buffer = append(e.buffer, value...)
+}
+
+// bytes encodes a length-prefixed byte sequence.
+func (e *encoder) bytes(value []byte) {
+	e.uint32(uint32(len(value)))
+	e.buffer = append(e.buffer, value...)
+}
+
+// permissions encodes an attributes structure containing only permissions.
+func (e *encoder) permissions(permissions uint32) {
+	e.uint32(attributeFlagPermissions)
+	e.uint32(permissions)
+}
+
+// decoder decodes SFTP packet fields. Once a decoding error occurs, all
+// subsequent decoding operations return zero values and the error is recorded.
+type decoder struct {
+	// data is the remaining data to decode.
+	data []byte
+	// err is the first decoding error that occurred, if any.
+	err error
+}
+
+// uint32 decodes a 32-bit unsigned integer.
+func (d *decoder) uint32() uint32 {
+	if d.err != nil {
+		return 0
+	} else if len(d.data) < 4 {
+		d.err = errTruncatedPacket
+		return 0
+	}
+	value := binary.BigEndian.Uint32(d.data)
+	d.data = d.data[4:]
+	return value
+}
+
+// uint64 decodes a 64-bit unsigned integer.
+func (d *decoder) uint64() uint64 {
+	if d.err != nil {
+		return 0
+	} else if len(d.data) < 8 {
+		d.err = errTruncatedPacket
+		return 0
+	}
+	value := binary.BigEndian.Uint64(d.data)
+	d.data = d.data[8:]
+	return value
+}
+
+// bytes decodes a length-prefixed byte sequence. The result aliases the
+// underlying packet data.
+func (d *decoder) bytes() []byte {
+	length := d.uint32()
+	if d.err != nil {
+		return nil
+	} else if uint64(len(d.data)) < uint64(length) {
+		d.err = errTruncatedPacket
+		return nil
+	}
+	value := d.data[:length]
+	d.data = d.data[length:]
+	return value
+}
+
+// string decodes a length-prefixed string.
+func (d *decoder) string() string {
+	return string(d.bytes())
+}
+
+// attributes decodes an attributes structure.
+func (d *decoder) attributes() *FileInfo {
+	// Decode the flags indicating which attributes are present.
+	flags := d.uint32()
+
+	// Decode the attributes that we track and skip the others.
+	result := &FileInfo{}
+	if flags&attributeFlagSize != 0 {
+		result.Size = d.uint64()
+	}
+	if flags&attributeFlagUIDGID != 0 {
+		d.uint32()
+		d.uint32()
+	}
+	if flags&attributeFlagPermissions != 0 {
+		result.Mode = d.uint32()
+	}
+	if flags&attributeFlagAccessModificationTime != 0 {
+		d.uint32()
+		result.ModificationTime = time.Unix(int64(d.uint32()), 0)
+	}
+	if flags&attributeFlagExtended != 0 {
+		count := d.uint32()
+		for i := uint32(0); i < count && d.err == nil; i++ {
+			d.string()
+			d.string()
+		}
+	}
+
+	// Done.
+	return result
+}
@@ -0,0 +1,148 @@
+package sftp
+
+import (
+	"errors"
+	"fmt"
+	"time"
+)
+
+const (
+	// modeTypeMask is the mask for the file type bits of a POSIX mode.
+	modeTypeMask = 0170000
+	// modeTypeDirectory is the file type for directories.
+	modeTypeDirectory = 0040000
+	// modeTypeRegular is the file type for regular files.
+	modeTypeRegular = 0100000
+	// modeTypeSymbolicLink is the file type for symbolic links.
+	modeTypeSymbolicLink = 0120000
+	// modePermissionsMask is the mask for the permission bits of a POSIX mode.
+	modePermissionsMask = 07777
+)
+
+// FileInfo provides information about a file.
+type FileInfo struct {
+	// Name is the base name of the file. It is only populated for directory
+	// listings.
+	Name string
+	// Size is the size of the file in bytes.
+	Size uint64
+	// Mode is the POSIX mode of the file, including file type bits.
+	Mode uint32
+	// ModificationTime is the modification time of the file. SFTP only
+	// provides modification times with a resolution of one second.
+	ModificationTime time.Time
+}
+
+// IsDirectory returns whether or not the file is a directory.
+func (i *FileInfo) IsDirectory() bool {
+	return i.Mode&modeTypeMask == modeTypeDirectory
+}
+
+// IsRegular returns whether or not the file is a regular file.
+func (i *FileInfo) IsRegular() bool {
+	return i.Mode&modeTypeMask == modeTypeRegular
+}
+
+// IsSymbolicLink returns whether or not the file is a symbolic link.
+func (i *FileInfo) IsSymbolicLink() bool {
+	return i.Mode&modeTypeMask == modeTypeSymbolicLink
+}
+
+// Permissions returns the permission bits of the file's mode.
+func (i *FileInfo) Permissions() uint32 {
+	return i.Mode & modePermissionsMask
+}
+
+// File is a handle to an open remote file. It implements io.ReadWriteCloser,
+// though only the operations corresponding to the mode in which the file was
+// opened will succeed. It is not safe for concurrent usage.
+type File struct {
+	// client is the associated client.
+	client *Client
+	// handle is the file handle.
+	handle string
+	// offset is the current file offset.
+	offset uint64
+	// closed indicates whether or not the file has been closed.
+	closed bool
+}
+
+// Read implements io.Reader.Read.
+func (f *File) Read(buffer []byte) (int, error) {
+	// Check that the file isn't closed.
+	if f.closed {
+		return 0, errors.New("file closed")
+	}
+
+	// Handle empty reads.
+	if len(buffer) == 0 {
+		return 0, nil
+	}
+
+	// Limit the read size.
+	if len(buffer) > maximumDataSize {
+		buffer = buffer[:maximumDataSize]
+	}
+
+	// Perform the read.
+	responseType, fields, err := f.client.request(packetTypeRead, func(e *encoder) {
+		e.string(f.handle)
+		e.uint64(f.offset)
+		e.uint32(uint32(len(buffer)))
+	})
+	if err != nil {
+		return 0, err
+	} else if responseType != packetTypeData {
+		return 0, failure(responseType, fields)
+	}
+	data := fields.bytes()
+	if fields.err != nil {
+		return 0, fmt.Errorf("unable to decode data: %w", fields.err)
+	} else if len(data) > len(buffer) {
+		return 0, errors.New("server returned excess data")
+	}
+
+	// Copy out the data and update the offset.
+	n := copy(buffer, data)
+	f.offset += uint64(n)
+	return n, nil
+}
+
+// Write implements io.Writer.Write.
+func (f *File) Write(data []byte) (int, error) {
+	// Check that the file isn't closed.
+	if f.closed {
+		return 0, errors.New("file closed")
+	}
+
+	// Write the data in chunks.
+	var written int
+	for len(data) > 0 {
+		chunk := data
+		if len(chunk) > maximumDataSize {
+			chunk = chunk[:maximumDataSize]
+		}
+		if err := f.client.simpleRequest(packetTypeWrite, func(e *encoder) {
+			e.string(f.handle)
+			e.uint64(f.offset)
+			e.bytes(chunk)
+		}); err != nil {
+			return written, err
+		}
+		f.offset += uint64(len(chunk))
+		written += len(chunk)
+		data = data[len(chunk):]
+	}
+
+	// Success.
+	return written, nil
+}
+
+// Close implements io.Closer.Close.
+func (f *File) Close() error {
+	if f.closed {
+		return errors.New("file already closed")
+	}
+	f.closed = true
+	return f.client.closeHandle(f.handle)
+}
@@ -0,0 +1,19 @@
+//go:build !windows
+
+package native
+
+import (
+	"errors"
+	"net"
+	"os"
+)
+
+// dialAgent connects to the SSH agent identified by the SSH_AUTH_SOCK
+// environment variable.
+func dialAgent() (net.Conn, error) {
+	socket := os.Getenv("SSH_AUTH_SOCK")
+	if socket == "" {
+		return nil, errors.New("no SSH agent socket specified")
+	}
+	return net.Dial("unix", socket)
+}
@@ -0,0 +1,29 @@
+package native
+
+import (
+	"net"
+	"os"
+	"time"
+
+	"github.com/Microsoft/go-winio"
+)
+
+const (
+	// defaultAgentPipe is the named pipe used by the Windows OpenSSH agent
+	// service.
+	defaultAgentPipe = `\\.\pipe\openssh-ssh-agent`
+	// agentDialTimeout is the maximum amount of time to wait when connecting
+	// to the SSH agent pipe.
+	agentDialTimeout = time.Second
+)
+
+// dialAgent connects to the SSH agent identified by the SSH_AUTH_SOCK
+// environment variable, falling back to the Windows OpenSSH agent pipe.
+func dialAgent() (net.Conn, error) {
+	pipe := os.Getenv("SSH_AUTH_SOCK")
+	if pipe == "" {
+		pipe = defaultAgentPipe
+	}
+	timeout := agentDialTimeout
+	return winio.DialPipe(pipe, &timeout)
+}
@@ -0,0 +1,254 @@
+package native
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+
+	"golang.org/x/crypto/ssh"
+	"golang.org/x/crypto/ssh/agent"
+)
+
+// defaultIdentityNames are the names of the default identity files (within the
+// user's SSH configuration directory) that are tried for public key
+// authentication, in order of preference, if no identity files are configured.
+// They match the defaults used by OpenSSH.
+var defaultIdentityNames = []string{
+	"id_ed25519",
+	"id_ecdsa",
+	"id_rsa",
+}
+
+// loadIdentity loads the private key stored at the specified path, prompting
+// for a passphrase if the key is encrypted and a prompter is available.
+func loadIdentity(path string, prompter Prompter) (ssh.Signer, error) {
+	// Read the key.
+	key, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	// Attempt to parse the key without a passphrase.
+	signer, err := ssh.ParsePrivateKey(key)
+	if err == nil {
+		return signer, nil
+	}
+
+	// If the key requires a passphrase, then prompt for one (if possible).
+	var missingErr *ssh.PassphraseMissingError
+	if !errors.As(err, &missingErr) {
+		return nil, err
+	} else if prompter == nil {
+		return nil, errors.New("passphrase required but no prompter available")
+	}
+	passphrase, err := prompter(fmt.Sprintf("Enter passphrase for key '%s': ", path))
+	if err != nil {
+		return nil, fmt.Errorf("unable to prompt for passphrase: %w", err)
+	}
+	return ssh.ParsePrivateKeyWithPassphrase(key, []byte(passphrase))
+}
+
+// certificateSuffix is the suffix that OpenSSH appends to an identity file path
+// to locate the corresponding certificate.
+const certificateSuffix = "-cert.pub"
+
+// loadCertificate loads the OpenSSH user certificate stored at the specified
+// path.
+func loadCertificate(path string) (*ssh.Certificate, error) {
+	// Read the certificate.
+	contents, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	// Parse the certificate.
+	key, _, _, _, err := ssh.ParseAuthorizedKey(contents)
+	if err != nil {
+		return nil, err
+	}
+	certificate, ok := key.(*ssh.Certificate)
+	if !ok {
+		return nil, errors.New("key is not a certificate")
+	} else if certificate.CertType != ssh.UserCert {
+		return nil, errors.New("certificate is not a user certificate")
+	}
+
+	// Success.
+	return certificate, nil
+}
+
+// isHardwareBackedIdentity returns whether or not the identity file at the
+// specified path is backed by a FIDO2 hardware authenticator. Such keys can't
+// be used directly by the native client, but they can be used if they've been
+// loaded into an SSH agent. The determination is made using the identity's
+// public key file, since the private key file format for these keys isn't
+// supported.
+func isHardwareBackedIdentity(path string) bool {
+	contents, err := os.ReadFile(path + ".pub")
+	if err != nil {
+		return false
+	}
+	key, _, _, _, err := ssh.ParseAuthorizedKey(contents)
+	if err != nil {
+		return false
+	}
+	switch key.Type() {
+	case ssh.KeyAlgoSKED25519, ssh.KeyAlgoSKECDSA256:
+		return true
+	default:
+		return false
+	}
+}
+
+// withCertificates augments a list of signers with certificate-based signers
+// for any certificates that correspond to the signers' keys. Certificate-based
+// signers are placed immediately before the corresponding plain signer, since
+// servers that accept certificates generally don't have the plain key
+// authorized. Signers that are already certificate-based are left unmodified.
+func withCertificates(signers []ssh.Signer, certificates []*ssh.Certificate) []ssh.Signer {
+	// If there are no certificates, then there's nothing to augment.
+	if len(certificates) == 0 {
+		return signers
+	}
+
+	// Pair certificates with signers.
+	result := make([]ssh.Signer, 0, len(signers))
+	for _, signer := range signers {
+		if _, ok := signer.PublicKey().(*ssh.Certificate); !ok {
+			key := signer.PublicKey().Marshal()
+			for _, certificate := range certificates {
+				if !bytes.Equal(certificate.Key.Marshal(), key) {
+					continue
+				}
+				if certificateSigner, err := ssh.NewCertSigner(certificate, signer); err == nil {
+					result = append(result, certificateSigner)
+				}
+			}
+		}
+		result = append(result, signer)
+	}
+
+	// Done.
+	return result
+}
+
+// authenticationHint annotates an authentication failure with guidance for
+// hardware-backed credentials that the native client can only use via an SSH
+// agent. If no such credentials are involved, then the error is returned
+// unmodified.
+func authenticationHint(err error, hardwareIdentities []string, pkcs11Provider string) error {
+	// Only annotate authentication failures.
+	if err == nil || !strings.Contains(err.Error(), "unable to authenticate") {
+		return err
+	}
+
+	// Add hints for hardware-backed credentials.
+	if len(hardwareIdentities) > 0 {
+		return fmt.Errorf(
+			"%w (hardware-backed keys must be loaded into an SSH agent for use with the native client, e.g. using 'ssh-add %s')",
+			err, strings.Join(hardwareIdentities, " "),
+		)
+	} else if pkcs11Provider != "" {
+		return fmt.Errorf(
+			"%w (PKCS#11 keys must be loaded into an SSH agent for use with the native client, e.g. using 'ssh-add -s %s')",
+			err, pkcs11Provider,
+		)
+	}
+	return err
+}
+
+// authenticationMethods computes the authentication methods to use when
+// connecting. Methods are attempted in the same order as OpenSSH: SSH agent
+// keys, then the specified identity files, then keyboard-interactive and
+// password authentication (if a prompter is available). Certificates found
+// alongside identity files (using the OpenSSH "-cert.pub" naming convention) or
+// specified explicitly are paired with matching keys from both sources. In
+// addition to the methods, it returns the identity files that are backed by
+// hardware authenticators (and thus can't be used directly) and a function that
+// releases any SSH agent connection that was opened. This function must be
+// called once authentication has completed.
+func authenticationMethods(identityFiles, certificateFiles []string, prompter Prompter) ([]ssh.AuthMethod, []string, func()) {
+	// Set up the result and the cleanup function.
+	var methods []ssh.AuthMethod
+	cleanup := func() {}
+
+	// Load any available certificates and identify hardware-backed identities.
+	// Missing or invalid certificates are ignored, as with OpenSSH.
+	var certificates []*ssh.Certificate
+	var hardwareIdentities []string
+	for _, path := range identityFiles {
+		if certificate, err := loadCertificate(path + certificateSuffix); err == nil {
+			certificates = append(certificates, certificate)
+		}
+		if isHardwareBackedIdentity(path) {
+			hardwareIdentities = append(hardwareIdentities, path)
+		}
+	}
+	for _, path := range certificateFiles {
+		if certificate, err := loadCertificate(path); err == nil {
+			certificates = append(certificates, certificate)
+		}
+	}
+
+	// If an SSH agent is available, then use it. Agents may hold certificates
+	// and hardware-backed keys directly, in which case the signers they provide
+	// are used as-is.
+	if connection, err := dialAgent(); err == nil {
+		client := agent.NewClient(connection)
+		methods = append(methods, ssh.PublicKeysCallback(func() ([]ssh.Signer, error) {
+			signers, err := client.Signers()
+			if err != nil {
+				return nil, err
+			}
+			return withCertificates(signers, certificates), nil
+		}))
+		cleanup = func() {
+			connection.Close()
+		}
+	}
+
+	// Add public key authentication using the identity files. We load these
+	// lazily so that passphrase prompts only occur if agent-based
+	// authentication fails. Hardware-backed identities are skipped since their
+	// private key files can't be used without the authenticator.
+	methods = append(methods, ssh.PublicKeysCallback(func() ([]ssh.Signer, error) {
+		var signers []ssh.Signer
+		for _, path := range identityFiles {
+			if isHardwareBackedIdentity(path) {
+				continue
+			} else if signer, err := loadIdentity(path, prompter); err == nil {
+				signers = append(signers, signer)
+			}
+		}
+		return withCertificates(signers, certificates), nil
+	}))
+
+	// If prompting is possible, then add interactive methods.
+	if prompter != nil {
+		methods = append(methods, ssh.KeyboardInteractive(
+			func(_, instruction string, questions []string, _ []bool) ([]string, error) {
+				answers := make([]string, len(questions))
+				for q, question := range questions {
+					prompt := question
+					if instruction != "" {
+						prompt = instruction + "\n" + question
+					}
+					answer, err := prompter(prompt)
+					if err != nil {
+						return nil, err
+					}
+					answers[q] = answer
+				}
+				return answers, nil
+			},
+		))
+		methods = append(methods, ssh.RetryableAuthMethod(ssh.PasswordCallback(func() (string, error) {
+			return prompter("Password: ")
+		}), 3))
+	}
+
+	// Done.
+	return methods, hardwareIdentities, cleanup
+}
@@ -0,0 +1,149 @@
+package native
+
+import (
+	"crypto/ed25519"
+	"crypto/rand"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"golang.org/x/crypto/ssh"
+)
+
+// generateSigner generates a random signer for testing.
+func generateSigner(t *testing.T) ssh.Signer {
+	t.Helper()
+	_, private, err := ed25519.GenerateKey(rand.Reader)
+	if err != nil {
+		t.Fatal("unable to generate key:", err)
+	}
+	signer, err := ssh.NewSignerFromKey(private)
+	if err != nil {
+		t.Fatal("unable to create signer:", err)
+	}
+	return signer
+}
+
+// generateCertificate generates a user certificate for the specified key that's
+// signed by the specified authority.
+func generateCertificate(t *testing.T, key ssh.PublicKey, authority ssh.Signer) *ssh.Certificate {
+	t.Helper()
+	certificate := &ssh.Certificate{
+		Key:             key,
+		CertType:        ssh.UserCert,
+		ValidPrincipals: []string{testUser},
+		ValidBefore:     ssh.CertTimeInfinity,
+	}
+	if err := certificate.SignCert(rand.Reader, authority); err != nil {
+		t.Fatal("unable to sign certificate:", err)
+	}
+	return certificate
+}
+
+// TestLoadCertificate tests loading of certificates from disk.
+func TestLoadCertificate(t *testing.T) {
+	// Generate a key and a certificate for it.
+	signer := generateSigner(t)
+	certificate := generateCertificate(t, signer.PublicKey(), generateSigner(t))
+
+	// Write the certificate and the plain public key to disk.
+	directory := t.TempDir()
+	certificatePath := filepath.Join(directory, "id_ed25519"+certificateSuffix)
+	if err := os.WriteFile(certificatePath, ssh.MarshalAuthorizedKey(certificate), 0600); err != nil {
+		t.Fatal("unable to write certificate:", err)
+	}
+	publicPath := filepath.Join(directory, "id_ed25519.pub")
+	if err := os.WriteFile(publicPath, ssh.MarshalAuthorizedKey(signer.PublicKey()), 0600); err != nil {
+		t.Fatal("unable to write public key:", err)
+	}
+
+	// Verify that the certificate loads correctly.
+	if loaded, err := loadCertificate(certificatePath); err != nil {
+		t.Error("unable to load certificate:", err)
+	} else if string(loaded.Marshal()) != string(certificate.Marshal()) {
+		t.Error("loaded certificate does not match original")
+	}
+
+	// Verify that plain public keys and missing files are rejected.
+	if _, err := loadCertificate(publicPath); err == nil {
+		t.Error("plain public key loaded as certificate")
+	}
+	if _, err := loadCertificate(filepath.Join(directory, "missing")); err == nil {
+		t.Error("missing certificate loaded successfully")
+	}
+
+	// Verify that a software-backed identity isn't treated as hardware-backed.
+	if isHardwareBackedIdentity(filepath.Join(directory, "id_ed25519")) {
+		t.Error("software key identified as hardware-backed")
+	}
+}
+
+// TestWithCertificates tests pairing of certificates with signers.
+func TestWithCertificates(t *testing.T) {
+	// Generate keys and a certificate for one of them.
+	authority := generateSigner(t)
+	certified := generateSigner(t)
+	uncertified := generateSigner(t)
+	certificate := generateCertificate(t, certified.PublicKey(), authority)
+
+	// Compute the augmented signers.
+	signers := withCertificates([]ssh.Signer{uncertified, certified}, []*ssh.Certificate{certificate})
+
+	// Verify the result ordering and types.
+	if len(signers) != 3 {
+		t.Fatal("unexpected signer count:", len(signers))
+	}
+	if signers[0] != uncertified {
+		t.Error("uncertified signer not preserved")
+	}
+	if key, ok := signers[1].PublicKey().(*ssh.Certificate); !ok {
+		t.Error("certificate signer not placed before plain signer")
+	} else if string(key.Marshal()) != string(certificate.Marshal()) {
+		t.Error("certificate signer uses incorrect certificate")
+	}
+	if signers[2] != certified {
+		t.Error("certified signer not preserved")
+	}
+
+	// Verify that certificate-based signers aren't augmented further.
+	if again := withCertificates(signers[1:2], []*ssh.Certificate{certificate}); len(again) != 1 {
+		t.Error("certificate-based signer augmented unexpectedly")
+	}
+}
+
+// TestAuthenticationHint tests annotation of authentication failures.
+func TestAuthenticationHint(t *testing.T) {
+	// Set up test cases.
+	failure := errors.New("ssh: handshake failed: ssh: unable to authenticate")
+	other := errors.New("connection refused")
+	testCases := []struct {
+		err                error
+		hardwareIdentities []string
+		pkcs11Provider     string
+		expected           string
+	}{
+		{nil, []string{"id_ed25519_sk"}, "", ""},
+		{other, []string{"id_ed25519_sk"}, "", other.Error()},
+		{failure, nil, "", failure.Error()},
+		{failure, []string{"id_ed25519_sk"}, "", "ssh-add id_ed25519_sk"},
+		{failure, nil, "/usr/lib/opensc-pkcs11.so", "ssh-add -s /usr/lib/opensc-pkcs11.so"},
+	}
+
+	// Process test cases.
+	for i, testCase := range testCases {
+		err := authenticationHint(testCase.err, testCase.hardwareIdentities, testCase.pkcs11Provider)
+		if testCase.err == nil {
+			if err != nil {
+				t.Errorf("test index %d: unexpected error: %v", i, err)
+			}
+			continue
+		}
+		if !errors.Is(err, testCase.err) {
+			t.Errorf("test index %d: original error not wrapped", i)
+		} else if !strings.Contains(err.Error(), testCase.expected) {
+			t.Errorf("test index %d: error does not contain expected text: %v", i, err)
+		}
+	}
+}
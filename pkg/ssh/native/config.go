@@ -0,0 +1,614 @@
+package native
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"os/user"
+	"path/filepath"
+	"strconv"
+	"strings"
+)
+
+// maximumIncludeDepth is the maximum nesting depth for Include directives. It
+// matches the limit used by OpenSSH.
+const maximumIncludeDepth = 16
+
+// configurationOption is an option specified in a configuration section.
+type configurationOption struct {
+	// keyword is the lowercase option keyword.
+	keyword string
+	// value is the option value.
+	value string
+	// included is the configuration loaded by an Include directive. If
+	// non-nil, then keyword and value are unused.
+	included configuration
+}
+
+// matchCriterion is a single criterion on a Match line.
+type matchCriterion struct {
+	// negated indicates whether or not the criterion is negated.
+	negated bool
+	// keyword is the lowercase criterion keyword.
+	keyword string
+	// patterns are the patterns for the criterion. Patterns prefixed with '!'
+	// are negated. It is empty for the "all" criterion.
+	patterns []string
+}
+
+// configurationSection is a Host or Match section of an OpenSSH client
+// configuration file.
+type configurationSection struct {
+	// patterns are the host patterns for a Host section. Patterns prefixed
+	// with '!' are negated.
+	patterns []string
+	// criteria are the criteria for a Match section. If non-empty, then
+	// patterns is unused.
+	criteria []matchCriterion
+	// options are the options specified in the section, in order.
+	options []configurationOption
+}
+
+// matchPatternList returns whether or not the value matches a pattern list,
+// which requires that it match at least one pattern and no negated patterns.
+func matchPatternList(patterns []string, value string) bool {
+	var matched bool
+	for _, pattern := range patterns {
+		if strings.HasPrefix(pattern, "!") {
+			if matchHostPattern(pattern[1:], value) {
+				return false
+			}
+		} else if matchHostPattern(pattern, value) {
+			matched = true
+		}
+	}
+	return matched
+}
+
+// matches returns whether or not the section applies given the current lookup
+// state.
+func (s *configurationSection) matches(state *lookupState) (bool, error) {
+	// Handle Host sections.
+	if len(s.criteria) == 0 {
+		return matchPatternList(s.patterns, state.host), nil
+	}
+
+	// Handle Match sections, which require that all criteria be satisfied.
+	for _, criterion := range s.criteria {
+		var matched bool
+		switch criterion.keyword {
+		case "all":
+			matched = true
+		case "host":
+			matched = matchPatternList(criterion.patterns, state.hostName())
+		case "originalhost":
+			matched = matchPatternList(criterion.patterns, state.host)
+		case "user":
+			remoteUser, err := state.remoteUser()
+			if err != nil {
+				return false, err
+			}
+			matched = matchPatternList(criterion.patterns, remoteUser)
+		case "localuser":
+			current, err := user.Current()
+			if err != nil {
+				return false, fmt.Errorf("unable to determine current user: %w", err)
+			}
+			matched = matchPatternList(criterion.patterns, current.Username)
+		}
+		if matched == criterion.negated {
+			return false, nil
+		}
+	}
+	return true, nil
+}
+
+// matchHostPattern performs OpenSSH-style pattern matching, where '*' matches
+// zero or more characters and '?' matches exactly one character. Matching is
+// case-insensitive.
+func matchHostPattern(pattern, host string) bool {
+	pattern, host = strings.ToLower(pattern), strings.ToLower(host)
+	for len(pattern) > 0 {
+		switch pattern[0] {
+		case '*':
+			for i := len(host); i >= 0; i-- {
+				if matchHostPattern(pattern[1:], host[i:]) {
+					return true
+				}
+			}
+			return false
+		case '?':
+			if len(host) == 0 {
+				return false
+			}
+		default:
+			if len(host) == 0 || host[0] != pattern[0] {
+				return false
+			}
+		}
+		pattern, host = pattern[1:], host[1:]
+	}
+	return len(host) == 0
+}
+
+// configuration is a parsed OpenSSH client configuration.
+type configuration []*configurationSection
+
+// splitConfigurationLine splits a configuration line into a lowercase keyword
+// and its arguments. Arguments may be enclosed in double quotes to include
+// whitespace. The keyword may be separated from its arguments by whitespace or
+// a single '='.
+func splitConfigurationLine(line string) (string, []string, error) {
+	// Extract the keyword.
+	line = strings.TrimSpace(line)
+	end := strings.IndexAny(line, " \t=")
+	if end == -1 {
+		return strings.ToLower(line), nil, nil
+	}
+	keyword := strings.ToLower(line[:end])
+	line = strings.TrimLeft(line[end:], " \t")
+	if strings.HasPrefix(line, "=") {
+		line = strings.TrimLeft(line[1:], " \t")
+	}
+
+	// Split the arguments.
+	var arguments []string
+	for line != "" {
+		if line[0] == '"' {
+			closing := strings.IndexByte(line[1:], '"')
+			if closing == -1 {
+				return "", nil, errors.New("unterminated quoted argument")
+			}
+			arguments = append(arguments, line[1:closing+1])
+			line = line[closing+2:]
+		} else if end := strings.IndexAny(line, " \t"); end == -1 {
+			arguments = append(arguments, line)
+			line = ""
+		} else {
+			arguments = append(arguments, line[:end])
+			line = line[end:]
+		}
+		line = strings.TrimLeft(line, " \t")
+	}
+
+	// Done.
+	return keyword, arguments, nil
+}
+
+// parseMatchCriteria parses the arguments of a Match line. Only criteria that
+// can be evaluated without canonicalization or command execution (all, host,
+// originalhost, user, and localuser) are supported.
+func parseMatchCriteria(arguments []string) ([]matchCriterion, error) {
+	var result []matchCriterion
+	for i := 0; i < len(arguments); i++ {
+		criterion := matchCriterion{keyword: strings.ToLower(arguments[i])}
+		if strings.HasPrefix(criterion.keyword, "!") {
+			criterion.negated = true
+			criterion.keyword = criterion.keyword[1:]
+		}
+		switch criterion.keyword {
+		case "all":
+			if len(arguments) != 1 {
+				return nil, errors.New("all criterion must appear alone")
+			}
+		case "host", "originalhost", "user", "localuser":
+			if i+1 == len(arguments) {
+				return nil, fmt.Errorf("missing argument for %s criterion", criterion.keyword)
+			}
+			i++
+			criterion.patterns = strings.Split(arguments[i], ",")
+		default:
+			return nil, fmt.Errorf("unsupported Match criterion: %s", arguments[i])
+		}
+		result = append(result, criterion)
+	}
+	if len(result) == 0 {
+		return nil, errors.New("missing criteria")
+	}
+	return result, nil
+}
+
+// includePaths computes the paths for the arguments of an Include directive.
+// Relative paths are resolved relative to the specified directory and glob
+// patterns are expanded.
+func includePaths(arguments []string, directory string) ([]string, error) {
+	var result []string
+	for _, argument := range arguments {
+		if argument == "~" || strings.HasPrefix(argument, "~/") {
+			homeDirectory, err := os.UserHomeDir()
+			if err != nil {
+				return nil, fmt.Errorf("unable to compute path to home directory: %w", err)
+			}
+			argument = filepath.Join(homeDirectory, argument[1:])
+		} else if !filepath.IsAbs(argument) {
+			argument = filepath.Join(directory, argument)
+		}
+		matches, err := filepath.Glob(argument)
+		if err != nil {
+			return nil, fmt.Errorf("invalid include pattern (%s): %w", argument, err)
+		}
+		result = append(result, matches...)
+	}
+	return result, nil
+}
+
+// parseConfiguration parses an OpenSSH client configuration. Host and Match
+// sections are supported, though Match sections are limited to the criteria
+// supported by parseMatchCriteria. Relative paths in Include directives are
+// resolved relative to the specified directory.
+func parseConfiguration(reader io.Reader, directory string) (configuration, error) {
+	return parseConfigurationDepth(reader, directory, 0)
+}
+
+// parseConfigurationDepth implements parseConfiguration, tracking the Include
+// depth.
+func parseConfigurationDepth(reader io.Reader, directory string, depth int) (configuration, error) {
+	// Options specified before the first Host section apply to all hosts, so
+	// start with an implicit section that matches everything.
+	current := &configurationSection{patterns: []string{"*"}}
+	result := configuration{current}
+
+	// Process lines.
+	scanner := bufio.NewScanner(reader)
+	for l := 1; scanner.Scan(); l++ {
+		// Skip empty lines and comments.
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || line[0] == '#' {
+			continue
+		}
+
+		// Split the line.
+		keyword, arguments, err := splitConfigurationLine(line)
+		if err != nil {
+			return nil, fmt.Errorf("invalid configuration on line %d: %w", l, err)
+		}
+
+		// Handle section boundaries.
+		if keyword == "host" {
+			if len(arguments) == 0 {
+				return nil, fmt.Errorf("invalid configuration on line %d: Host without patterns", l)
+			}
+			current = &configurationSection{patterns: arguments}
+			result = append(result, current)
+			continue
+		} else if keyword == "match" {
+			criteria, err := parseMatchCriteria(arguments)
+			if err != nil {
+				return nil, fmt.Errorf("invalid configuration on line %d: %w", l, err)
+			}
+			current = &configurationSection{criteria: criteria}
+			result = append(result, current)
+			continue
+		}
+
+		// Ensure that an argument is present.
+		if len(arguments) == 0 {
+			return nil, fmt.Errorf("invalid configuration on line %d: missing argument for %s", l, keyword)
+		}
+
+		// Handle Include directives, which apply the included configuration
+		// within the current section.
+		if keyword == "include" {
+			if depth == maximumIncludeDepth {
+				return nil, fmt.Errorf("invalid configuration on line %d: includes nested too deeply", l)
+			}
+			paths, err := includePaths(arguments, directory)
+			if err != nil {
+				return nil, fmt.Errorf("invalid configuration on line %d: %w", l, err)
+			}
+			for _, path := range paths {
+				included, err := loadConfigurationDepth(path, directory, depth+1)
+				if err != nil {
+					return nil, fmt.Errorf("unable to include %s: %w", path, err)
+				}
+				current.options = append(current.options, configurationOption{included: included})
+			}
+			continue
+		}
+
+		// Record the option.
+		current.options = append(current.options, configurationOption{
+			keyword: keyword,
+			value:   strings.Join(arguments, " "),
+		})
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("unable to read configuration: %w", err)
+	}
+
+	// Success.
+	return result, nil
+}
+
+// loadConfiguration loads the OpenSSH client configuration at the specified
+// path, resolving relative Include paths relative to the file's directory. If
+// the file doesn't exist, then an empty configuration is returned.
+func loadConfiguration(path string) (configuration, error) {
+	return loadConfigurationDepth(path, filepath.Dir(path), 0)
+}
+
+// loadConfigurationDepth implements loadConfiguration, tracking the Include
+// depth and the directory against which relative Include paths are resolved.
+func loadConfigurationDepth(path, directory string, depth int) (configuration, error) {
+	// Open the file and defer its closure.
+	file, err := os.Open(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("unable to open SSH configuration: %w", err)
+	}
+	defer file.Close()
+
+	// Parse the configuration.
+	return parseConfigurationDepth(file, directory, depth)
+}
+
+// hostConfiguration is the effective configuration for a host.
+type hostConfiguration struct {
+	// hostName is the real hostname to which connections should be made.
+	hostName string
+	// user is the remote user. It may be empty if not specified.
+	user string
+	// port is the remote port. It may be 0 if not specified.
+	port uint16
+	// identityFiles are the identity file paths to use for authentication.
+	// Tokens and home directory prefixes have not yet been expanded.
+	identityFiles []string
+	// proxyJump is the jump host specification. It may be empty if not
+	// specified.
+	proxyJump string
+	// certificateFiles are the certificate file paths to use for
+	// authentication. Tokens and home directory prefixes have not yet been
+	// expanded.
+	certificateFiles []string
+	// pkcs11Provider is the PKCS#11 provider library specified for the host.
+	// It may be empty if not specified.
+	pkcs11Provider string
+	// userKnownHostsFiles are the user known hosts file paths. It is nil if
+	// not specified and empty (but non-nil) if specified as "none". Tokens and
+	// home directory prefixes have not yet been expanded.
+	userKnownHostsFiles []string
+	// globalKnownHostsFiles are the global known hosts file paths. It is nil
+	// if not specified and empty (but non-nil) if specified as "none". Tokens
+	// and home directory prefixes have not yet been expanded.
+	globalKnownHostsFiles []string
+}
+
+// parseKnownHostsFiles parses a UserKnownHostsFile or GlobalKnownHostsFile
+// value, which is a whitespace-separated list of paths or "none".
+func parseKnownHostsFiles(value string) []string {
+	if strings.EqualFold(value, "none") {
+		return []string{}
+	}
+	return strings.Fields(value)
+}
+
+// lookupState tracks the state of a configuration lookup.
+type lookupState struct {
+	// host is the original host.
+	host string
+	// user is the remote user specified for the target. It may be empty.
+	user string
+	// result is the effective configuration computed so far.
+	result *hostConfiguration
+	// hostNameSet, userSet, portSet, proxyJumpSet, pkcs11ProviderSet,
+	// userKnownHostsFilesSet, and globalKnownHostsFilesSet track which options
+	// have been set.
+	hostNameSet, userSet, portSet, proxyJumpSet, pkcs11ProviderSet bool
+	userKnownHostsFilesSet, globalKnownHostsFilesSet               bool
+}
+
+// hostName returns the hostname computed so far, which is used to evaluate
+// Match host criteria.
+func (s *lookupState) hostName() string {
+	if s.hostNameSet {
+		return s.result.hostName
+	}
+	return s.host
+}
+
+// remoteUser returns the remote user computed so far, which is used to
+// evaluate Match user criteria.
+func (s *lookupState) remoteUser() (string, error) {
+	if s.user != "" {
+		return s.user, nil
+	} else if s.userSet {
+		return s.result.user, nil
+	}
+	current, err := user.Current()
+	if err != nil {
+		return "", fmt.Errorf("unable to determine current user: %w", err)
+	}
+	return current.Username, nil
+}
+
+// process applies the sections of a configuration that match the current
+// state.
+func (s *lookupState) process(c configuration) error {
+	host, result := s.host, s.result
+	for _, section := range c {
+		if matched, err := section.matches(s); err != nil {
+			return err
+		} else if !matched {
+			continue
+		}
+		for _, option := range section.options {
+			if option.included != nil {
+				if err := s.process(option.included); err != nil {
+					return err
+				}
+				continue
+			}
+			keyword, value := option.keyword, option.value
+			switch keyword {
+			case "hostname":
+				if !s.hostNameSet {
+					result.hostName = strings.ReplaceAll(value, "%h", host)
+					s.hostNameSet = true
+				}
+			case "user":
+				if !s.userSet {
+					result.user = value
+					s.userSet = true
+				}
+			case "port":
+				if !s.portSet {
+					port, err := strconv.ParseUint(value, 10, 16)
+					if err != nil || port == 0 {
+						return fmt.Errorf("invalid port for host %s: %s", host, value)
+					}
+					result.port = uint16(port)
+					s.portSet = true
+				}
+			case "identityfile":
+				result.identityFiles = append(result.identityFiles, value)
+			case "certificatefile":
+				result.certificateFiles = append(result.certificateFiles, value)
+			case "proxyjump":
+				if !s.proxyJumpSet {
+					if !strings.EqualFold(value, "none") {
+						result.proxyJump = value
+					}
+					s.proxyJumpSet = true
+				}
+			case "pkcs11provider":
+				if !s.pkcs11ProviderSet {
+					if !strings.EqualFold(value, "none") {
+						result.pkcs11Provider = value
+					}
+					s.pkcs11ProviderSet = true
+				}
+			case "userknownhostsfile":
+				if !s.userKnownHostsFilesSet {
+					result.userKnownHostsFiles = parseKnownHostsFiles(value)
+					s.userKnownHostsFilesSet = true
+				}
+			case "globalknownhostsfile":
+				if !s.globalKnownHostsFilesSet {
+					result.globalKnownHostsFiles = parseKnownHostsFiles(value)
+					s.globalKnownHostsFilesSet = true
+				}
+			}
+		}
+	}
+
+	// Success.
+	return nil
+}
+
+// lookup computes the effective configuration for the specified host and
+// remote user (which may be empty). As with OpenSSH, the first value obtained
+// for each option takes precedence (except for IdentityFile and
+// CertificateFile, which accumulate).
+func (c configuration) lookup(host, remoteUser string) (*hostConfiguration, error) {
+	// Process matching sections.
+	state := &lookupState{host: host, user: remoteUser, result: &hostConfiguration{}}
+	if err := state.process(c); err != nil {
+		return nil, err
+	}
+	result := state.result
+
+	// Default the hostname to the provided host.
+	if result.hostName == "" {
+		result.hostName = host
+	}
+
+	// Success.
+	return result, nil
+}
+
+// expandIdentityPath expands the home directory prefix and supported tokens
+// (%%, %d, %h, %r, and %u) in an identity, certificate, or known hosts file
+// path.
+func expandIdentityPath(path, homeDirectory, hostName, remoteUser string) (string, error) {
+	// Expand the home directory prefix.
+	if path == "~" {
+		path = homeDirectory
+	} else if strings.HasPrefix(path, "~/") {
+		path = filepath.Join(homeDirectory, path[2:])
+	}
+
+	// Expand tokens.
+	var result strings.Builder
+	for i := 0; i < len(path); i++ {
+		if path[i] != '%' {
+			result.WriteByte(path[i])
+			continue
+		} else if i+1 == len(path) {
+			return "", errors.New("incomplete token")
+		}
+		i++
+		switch path[i] {
+		case '%':
+			result.WriteByte('%')
+		case 'd':
+			result.WriteString(homeDirectory)
+		case 'h':
+			result.WriteString(hostName)
+		case 'r':
+			result.WriteString(remoteUser)
+		case 'u':
+			current, err := user.Current()
+			if err != nil {
+				return "", fmt.Errorf("unable to determine current user: %w", err)
+			}
+			result.WriteString(current.Username)
+		default:
+			return "", fmt.Errorf("unsupported token: %%%c", path[i])
+		}
+	}
+
+	// Done.
+	return result.String(), nil
+}
+
+// parseJumpHost parses a single ProxyJump host specification of the form
+// [user@]host[:port].
+func parseJumpHost(specification string) (*Target, error) {
+	// Strip any ssh:// prefix.
+	specification = strings.TrimPrefix(specification, "ssh://")
+
+	// Parse the user.
+	target := &Target{}
+	if at := strings.LastIndexByte(specification, '@'); at != -1 {
+		target.User = specification[:at]
+		specification = specification[at+1:]
+	}
+
+	// Parse the host and port. We support bracketed IPv6 addresses.
+	if strings.HasPrefix(specification, "[") {
+		closing := strings.IndexByte(specification, ']')
+		if closing == -1 {
+			return nil, errors.New("unterminated IPv6 address")
+		}
+		target.Host = specification[1:closing]
+		specification = specification[closing+1:]
+		if specification != "" && specification[0] != ':' {
+			return nil, errors.New("invalid port specification")
+		}
+	} else if colon := strings.IndexByte(specification, ':'); colon != -1 {
+		target.Host = specification[:colon]
+		specification = specification[colon:]
+	} else {
+		target.Host = specification
+		specification = ""
+	}
+	if specification != "" {
+		port, err := strconv.ParseUint(specification[1:], 10, 16)
+		if err != nil || port == 0 {
+			return nil, errors.New("invalid port")
+		}
+		target.Port = uint16(port)
+	}
+
+	// Validate the host.
+	if target.Host == "" {
+		return nil, errors.New("empty hostname")
+	}
+
+	// Success.
+	return target, nil
+}
@@ -0,0 +1,407 @@
+package native
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+// TestMatchHostPattern tests matchHostPattern.
+func TestMatchHostPattern(t *testing.T) {
+	// Set up test cases.
+	testCases := []struct {
+		pattern  string
+		host     string
+		expected bool
+	}{
+		{"*", "", true},
+		{"*", "example.org", true},
+		{"example.org", "example.org", true},
+		{"example.org", "EXAMPLE.org", true},
+		{"example.org", "example.net", false},
+		{"*.org", "example.org", true},
+		{"*.org", "example.org.net", false},
+		{"host?", "host1", true},
+		{"host?", "host", false},
+		{"host?", "host12", false},
+		{"a*b*c", "aXXbYYc", true},
+		{"a*b*c", "aXXbYY", false},
+	}
+
+	// Process test cases.
+	for i, testCase := range testCases {
+		if result := matchHostPattern(testCase.pattern, testCase.host); result != testCase.expected {
+			t.Errorf("test index %d: result does not match expected: %t != %t", i, result, testCase.expected)
+		}
+	}
+}
+
+// TestSplitConfigurationLine tests splitConfigurationLine.
+func TestSplitConfigurationLine(t *testing.T) {
+	// Set up test cases.
+	testCases := []struct {
+		line              string
+		expectedKeyword   string
+		expectedArguments []string
+		expectFailure     bool
+	}{
+		{"Host", "host", nil, false},
+		{"HostName example.org", "hostname", []string{"example.org"}, false},
+		{"Port=2222", "port", []string{"2222"}, false},
+		{"Port = 2222", "port", []string{"2222"}, false},
+		{"  Host a b\tc  ", "host", []string{"a", "b", "c"}, false},
+		{`IdentityFile "~/my keys/id_rsa"`, "identityfile", []string{"~/my keys/id_rsa"}, false},
+		{`IdentityFile "~/my keys/id_rsa`, "", nil, true},
+	}
+
+	// Process test cases.
+	for i, testCase := range testCases {
+		keyword, arguments, err := splitConfigurationLine(testCase.line)
+		if testCase.expectFailure {
+			if err == nil {
+				t.Errorf("test index %d: split succeeded unexpectedly", i)
+			}
+			continue
+		} else if err != nil {
+			t.Errorf("test index %d: split failed: %v", i, err)
+			continue
+		}
+		if keyword != testCase.expectedKeyword {
+			t.Errorf("test index %d: keyword does not match expected: %s != %s", i, keyword, testCase.expectedKeyword)
+		}
+		if !reflect.DeepEqual(arguments, testCase.expectedArguments) {
+			t.Errorf("test index %d: arguments do not match expected: %v != %v", i, arguments, testCase.expectedArguments)
+		}
+	}
+}
+
+// testConfiguration is an OpenSSH client configuration used for testing.
+const testConfiguration = `
+# Global options.
+User global
+
+Host dev !dev.internal
+    HostName dev.example.org
+    Port 2222
+    IdentityFile ~/.ssh/dev_key
+    CertificateFile ~/.ssh/dev_key-cert.pub
+    PKCS11Provider /usr/lib/opensc-pkcs11.so
+
+Host *.internal
+    ProxyJump bastion
+    User internal
+
+Host bastion
+    HostName %h.example.org
+    ProxyJump none
+    PKCS11Provider none
+
+Match host something
+    Port 2200
+
+Host *
+    Port 22
+    IdentityFile ~/.ssh/default_key
+    PKCS11Provider /usr/lib/other-pkcs11.so
+`
+
+// TestConfigurationLookup tests configuration parsing and lookup.
+func TestConfigurationLookup(t *testing.T) {
+	// Parse the configuration.
+	configuration, err := parseConfiguration(strings.NewReader(testConfiguration), "")
+	if err != nil {
+		t.Fatal("unable to parse configuration:", err)
+	}
+
+	// Set up test cases.
+	testCases := []struct {
+		host     string
+		expected *hostConfiguration
+	}{
+		{"dev", &hostConfiguration{
+			hostName:         "dev.example.org",
+			user:             "global",
+			port:             2222,
+			identityFiles:    []string{"~/.ssh/dev_key", "~/.ssh/default_key"},
+			certificateFiles: []string{"~/.ssh/dev_key-cert.pub"},
+			pkcs11Provider:   "/usr/lib/opensc-pkcs11.so",
+		}},
+		{"dev.internal", &hostConfiguration{
+			hostName:       "dev.internal",
+			user:           "global",
+			port:           22,
+			identityFiles:  []string{"~/.ssh/default_key"},
+			proxyJump:      "bastion",
+			pkcs11Provider: "/usr/lib/other-pkcs11.so",
+		}},
+		{"bastion", &hostConfiguration{
+			hostName:      "bastion.example.org",
+			user:          "global",
+			port:          22,
+			identityFiles: []string{"~/.ssh/default_key"},
+		}},
+		{"something", &hostConfiguration{
+			hostName:       "something",
+			user:           "global",
+			port:           2200,
+			identityFiles:  []string{"~/.ssh/default_key"},
+			pkcs11Provider: "/usr/lib/other-pkcs11.so",
+		}},
+	}
+
+	// Process test cases.
+	for i, testCase := range testCases {
+		if result, err := configuration.lookup(testCase.host, ""); err != nil {
+			t.Errorf("test index %d: lookup failed: %v", i, err)
+		} else if !reflect.DeepEqual(result, testCase.expected) {
+			t.Errorf("test index %d: result does not match expected: %+v != %+v", i, result, testCase.expected)
+		}
+	}
+}
+
+// TestConfigurationLookupKnownHostsFiles tests known hosts file options.
+func TestConfigurationLookupKnownHostsFiles(t *testing.T) {
+	// Parse the configuration.
+	configuration, err := parseConfiguration(strings.NewReader(
+		"Host custom\n  UserKnownHostsFile ~/.ssh/first ~/.ssh/second\n  GlobalKnownHostsFile none\n"+
+			"Host *\n  UserKnownHostsFile ~/.ssh/ignored\n",
+	), "")
+	if err != nil {
+		t.Fatal("unable to parse configuration:", err)
+	}
+
+	// Verify the custom host.
+	if result, err := configuration.lookup("custom", ""); err != nil {
+		t.Fatal("lookup failed:", err)
+	} else if !reflect.DeepEqual(result.userKnownHostsFiles, []string{"~/.ssh/first", "~/.ssh/second"}) {
+		t.Error("unexpected user known hosts files:", result.userKnownHostsFiles)
+	} else if result.globalKnownHostsFiles == nil || len(result.globalKnownHostsFiles) != 0 {
+		t.Error("unexpected global known hosts files:", result.globalKnownHostsFiles)
+	}
+
+	// Verify another host.
+	if result, err := configuration.lookup("other", ""); err != nil {
+		t.Fatal("lookup failed:", err)
+	} else if !reflect.DeepEqual(result.userKnownHostsFiles, []string{"~/.ssh/ignored"}) {
+		t.Error("unexpected user known hosts files:", result.userKnownHostsFiles)
+	} else if result.globalKnownHostsFiles != nil {
+		t.Error("unexpected global known hosts files:", result.globalKnownHostsFiles)
+	}
+}
+
+// TestConfigurationLookupInvalidPort tests that lookup fails for invalid ports.
+func TestConfigurationLookupInvalidPort(t *testing.T) {
+	configuration, err := parseConfiguration(strings.NewReader("Host *\n  Port http\n"), "")
+	if err != nil {
+		t.Fatal("unable to parse configuration:", err)
+	}
+	if _, err := configuration.lookup("example.org", ""); err == nil {
+		t.Error("lookup succeeded with invalid port")
+	}
+}
+
+// TestParseConfigurationInvalid tests that parseConfiguration rejects invalid
+// configurations.
+func TestParseConfigurationInvalid(t *testing.T) {
+	// Set up test cases.
+	testCases := []string{
+		"Host\n",
+		"Host a\n  Port\n",
+		"User \"unterminated\n",
+		"Match\n",
+		"Match host\n",
+		"Match all host a\n",
+		"Match exec true\n",
+		"Match canonical\n",
+		"Include\n",
+	}
+
+	// Process test cases.
+	for i, invalid := range testCases {
+		if _, err := parseConfiguration(strings.NewReader(invalid), ""); err == nil {
+			t.Errorf("test index %d: parsing succeeded unexpectedly", i)
+		}
+	}
+}
+
+// TestConfigurationLookupMatch tests Match section evaluation.
+func TestConfigurationLookupMatch(t *testing.T) {
+	// Parse the configuration.
+	configuration, err := parseConfiguration(strings.NewReader(`
+Host alias
+    HostName real.example.org
+
+Match host real.example.org user deploy
+    Port 2201
+
+Match originalhost alias !user deploy
+    Port 2202
+
+Match host *.example.org,!other.example.org
+    User matched
+
+Match all
+    Port 22
+`), "")
+	if err != nil {
+		t.Fatal("unable to parse configuration:", err)
+	}
+
+	// Set up test cases.
+	testCases := []struct {
+		host         string
+		user         string
+		expectedUser string
+		expectedPort uint16
+	}{
+		{"alias", "deploy", "matched", 2201},
+		{"alias", "admin", "matched", 2202},
+		{"real.example.org", "deploy", "matched", 2201},
+		{"other.example.org", "deploy", "", 22},
+	}
+
+	// Process test cases.
+	for i, testCase := range testCases {
+		if result, err := configuration.lookup(testCase.host, testCase.user); err != nil {
+			t.Errorf("test index %d: lookup failed: %v", i, err)
+		} else if result.user != testCase.expectedUser {
+			t.Errorf("test index %d: user does not match expected: %s != %s", i, result.user, testCase.expectedUser)
+		} else if result.port != testCase.expectedPort {
+			t.Errorf("test index %d: port does not match expected: %d != %d", i, result.port, testCase.expectedPort)
+		}
+	}
+}
+
+// TestLoadConfigurationInclude tests Include directive handling.
+func TestLoadConfigurationInclude(t *testing.T) {
+	// Create a configuration with includes. The include within the Host
+	// section should only apply to that host, while the top-level include uses
+	// a glob pattern relative to the configuration directory.
+	directory := t.TempDir()
+	if err := os.Mkdir(filepath.Join(directory, "config.d"), 0700); err != nil {
+		t.Fatal("unable to create include directory:", err)
+	}
+	files := map[string]string{
+		"config":           "Include config.d/*.conf\nHost dev\n    Include dev\n    User dev\nHost *\n    User default\n",
+		"config.d/a.conf":  "Host a\n    Port 2201\n",
+		"config.d/b.conf":  "Host b\n    Port 2202\n",
+		"config.d/ignored": "Host *\n    Port 2203\n",
+		"dev":              "HostName dev.example.org\nUser included\n",
+	}
+	for name, contents := range files {
+		path := filepath.Join(directory, name)
+		if err := os.MkdirAll(filepath.Dir(path), 0700); err != nil {
+			t.Fatal("unable to create directory:", err)
+		} else if err := os.WriteFile(path, []byte(contents), 0600); err != nil {
+			t.Fatal("unable to write configuration:", err)
+		}
+	}
+
+	// Load the configuration.
+	configuration, err := loadConfiguration(filepath.Join(directory, "config"))
+	if err != nil {
+		t.Fatal("unable to load configuration:", err)
+	}
+
+	// Set up test cases.
+	testCases := []struct {
+		host     string
+		expected *hostConfiguration
+	}{
+		{"a", &hostConfiguration{hostName: "a", user: "default", port: 2201}},
+		{"b", &hostConfiguration{hostName: "b", user: "default", port: 2202}},
+		{"dev", &hostConfiguration{hostName: "dev.example.org", user: "included"}},
+		{"other", &hostConfiguration{hostName: "other", user: "default"}},
+	}
+
+	// Process test cases.
+	for i, testCase := range testCases {
+		if result, err := configuration.lookup(testCase.host, ""); err != nil {
+			t.Errorf("test index %d: lookup failed: %v", i, err)
+		} else if !reflect.DeepEqual(result, testCase.expected) {
+			t.Errorf("test index %d: result does not match expected: %+v != %+v", i, result, testCase.expected)
+		}
+	}
+}
+
+// TestLoadConfigurationIncludeRecursive tests that recursive includes fail.
+func TestLoadConfigurationIncludeRecursive(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "config")
+	if err := os.WriteFile(path, []byte("Include config\n"), 0600); err != nil {
+		t.Fatal("unable to write configuration:", err)
+	}
+	if _, err := loadConfiguration(path); err == nil {
+		t.Error("recursive include succeeded unexpectedly")
+	}
+}
+
+// TestExpandIdentityPath tests expandIdentityPath.
+func TestExpandIdentityPath(t *testing.T) {
+	// Set up test cases.
+	testCases := []struct {
+		path          string
+		expected      string
+		expectFailure bool
+	}{
+		{"/keys/id_rsa", "/keys/id_rsa", false},
+		{"~", "/home/george", false},
+		{"~/.ssh/id_rsa", filepath.Join("/home/george", ".ssh/id_rsa"), false},
+		{"%d/.ssh/%r@%h", "/home/george/.ssh/admin@example.org", false},
+		{"/keys/100%%", "/keys/100%", false},
+		{"/keys/%", "", true},
+		{"/keys/%z", "", true},
+	}
+
+	// Process test cases.
+	for i, testCase := range testCases {
+		result, err := expandIdentityPath(testCase.path, "/home/george", "example.org", "admin")
+		if testCase.expectFailure {
+			if err == nil {
+				t.Errorf("test index %d: expansion succeeded unexpectedly", i)
+			}
+		} else if err != nil {
+			t.Errorf("test index %d: expansion failed: %v", i, err)
+		} else if result != testCase.expected {
+			t.Errorf("test index %d: result does not match expected: %s != %s", i, result, testCase.expected)
+		}
+	}
+}
+
+// TestParseJumpHost tests parseJumpHost.
+func TestParseJumpHost(t *testing.T) {
+	// Set up test cases.
+	testCases := []struct {
+		specification string
+		expected      *Target
+		expectFailure bool
+	}{
+		{"bastion", &Target{Host: "bastion"}, false},
+		{"admin@bastion", &Target{User: "admin", Host: "bastion"}, false},
+		{"admin@bastion:2222", &Target{User: "admin", Host: "bastion", Port: 2222}, false},
+		{"ssh://bastion:2222", &Target{Host: "bastion", Port: 2222}, false},
+		{"[::1]:2222", &Target{Host: "::1", Port: 2222}, false},
+		{"[::1]", &Target{Host: "::1"}, false},
+		{"", nil, true},
+		{"admin@", nil, true},
+		{"bastion:port", nil, true},
+		{"bastion:0", nil, true},
+		{"[::1", nil, true},
+		{"[::1]2222", nil, true},
+	}
+
+	// Process test cases.
+	for i, testCase := range testCases {
+		result, err := parseJumpHost(testCase.specification)
+		if testCase.expectFailure {
+			if err == nil {
+				t.Errorf("test index %d: parsing succeeded unexpectedly", i)
+			}
+		} else if err != nil {
+			t.Errorf("test index %d: parsing failed: %v", i, err)
+		} else if *result != *testCase.expected {
+			t.Errorf("test index %d: result does not match expected: %+v != %+v", i, result, testCase.expected)
+		}
+	}
+}
@@ -0,0 +1,133 @@
+package native
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"golang.org/x/crypto/ssh"
+)
+
+// readSCPAcknowledgement reads an acknowledgement from an SCP sink.
+func readSCPAcknowledgement(reader *bufio.Reader) error {
+	// Read the response code.
+	code, err := reader.ReadByte()
+	if err != nil {
+		return fmt.Errorf("unable to read response: %w", err)
+	}
+
+	// Handle success.
+	if code == 0 {
+		return nil
+	}
+
+	// Any other code indicates an error (1) or fatal error (2), which is
+	// followed by a message. We also treat unknown codes as errors.
+	message, err := reader.ReadString('\n')
+	if err != nil {
+		return fmt.Errorf("unable to read error message (code %d): %w", code, err)
+	}
+	return fmt.Errorf("remote error: %s", strings.TrimSuffix(message, "\n"))
+}
+
+// sendSCPFile transmits a file to an SCP sink (i.e. a remote "scp -t" process)
+// using the specified streams.
+func sendSCPFile(writer io.Writer, reader *bufio.Reader, name string, mode os.FileMode, size int64, content io.Reader) error {
+	// Validate the name.
+	if name == "" || strings.ContainsAny(name, "/\n") {
+		return errors.New("invalid file name")
+	}
+
+	// Wait for the sink to indicate that it's ready.
+	if err := readSCPAcknowledgement(reader); err != nil {
+		return err
+	}
+
+	// Send the file header.
+	if _, err := fmt.Fprintf(writer, "C%04o %d %s\n", mode.Perm(), size, name); err != nil {
+		return fmt.Errorf("unable to send file header: %w", err)
+	} else if err := readSCPAcknowledgement(reader); err != nil {
+		return err
+	}
+
+	// Send the file content and terminator.
+	if copied, err := io.CopyN(writer, content, size); err != nil {
+		return fmt.Errorf("unable to send file content (%d of %d bytes sent): %w", copied, size, err)
+	} else if _, err := writer.Write([]byte{0}); err != nil {
+		return fmt.Errorf("unable to send content terminator: %w", err)
+	} else if err := readSCPAcknowledgement(reader); err != nil {
+		return err
+	}
+
+	// Success.
+	return nil
+}
+
+// Copy copies the specified local file to the remote using the SCP protocol.
+// The remote name is interpreted relative to the remote user's home directory.
+func Copy(client *ssh.Client, localPath, remoteName string) error {
+	// Open the file and defer its closure.
+	file, err := os.Open(localPath)
+	if err != nil {
+		return fmt.Errorf("unable to open file: %w", err)
+	}
+	defer file.Close()
+
+	// Grab file metadata.
+	metadata, err := file.Stat()
+	if err != nil {
+		return fmt.Errorf("unable to query file metadata: %w", err)
+	} else if !metadata.Mode().IsRegular() {
+		return errors.New("not a regular file")
+	}
+
+	// Create a session and defer its closure.
+	session, err := client.NewSession()
+	if err != nil {
+		return fmt.Errorf("unable to create session: %w", err)
+	}
+	defer session.Close()
+
+	// Set up the session's streams.
+	writer, err := session.StdinPipe()
+	if err != nil {
+		return fmt.Errorf("unable to create input pipe: %w", err)
+	}
+	output, err := session.StdoutPipe()
+	if err != nil {
+		return fmt.Errorf("unable to create output pipe: %w", err)
+	}
+	var errorOutput strings.Builder
+	session.Stderr = &errorOutput
+
+	// Start the sink. We intentionally don't specify a directory so that the
+	// file lands relative to the default working directory (i.e. the user's
+	// home directory), which mirrors the OpenSSH-based transport.
+	if err := session.Start("scp -t " + remoteName); err != nil {
+		return fmt.Errorf("unable to start remote scp process: %w", err)
+	}
+
+	// Transmit the file and signal completion.
+	sendErr := sendSCPFile(writer, bufio.NewReader(output), filepath.Base(remoteName), metadata.Mode(), metadata.Size(), file)
+	writer.Close()
+
+	// Wait for the sink to exit.
+	waitErr := session.Wait()
+
+	// Handle errors.
+	if sendErr != nil {
+		if message := strings.TrimSpace(errorOutput.String()); message != "" {
+			return fmt.Errorf("%w (%s)", sendErr, message)
+		}
+		return sendErr
+	} else if waitErr != nil {
+		return fmt.Errorf("remote scp process failed: %w", waitErr)
+	}
+
+	// Success.
+	return nil
+}
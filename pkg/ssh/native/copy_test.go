@@ -0,0 +1,53 @@
+package native
+
+import (
+	"bufio"
+	"bytes"
+	"strings"
+	"testing"
+)
+
+// TestSendSCPFile tests sendSCPFile against a scripted sink.
+func TestSendSCPFile(t *testing.T) {
+	// Set up test cases.
+	testCases := []struct {
+		name          string
+		responses     string
+		content       string
+		expected      string
+		expectFailure bool
+		expectedError string
+	}{
+		{"", "\x00\x00\x00", "data", "", true, "invalid file name"},
+		{"agent", "\x00\x00\x00", "data", "C0644 4 agent\ndata\x00", false, ""},
+		{"agent", "\x00\x00\x00", "", "C0644 0 agent\n\x00", false, ""},
+		{"agent", "\x01scp: permission denied\n", "data", "", true, "remote error: scp: permission denied"},
+		{"agent", "\x00\x02scp: disk full\n", "data", "C0644 4 agent\n", true, "remote error: scp: disk full"},
+		{"agent", "\x00\x00", "data", "C0644 4 agent\ndata\x00", true, "unable to read response: EOF"},
+	}
+
+	// Process test cases.
+	for i, testCase := range testCases {
+		var output bytes.Buffer
+		err := sendSCPFile(
+			&output,
+			bufio.NewReader(strings.NewReader(testCase.responses)),
+			testCase.name,
+			0644,
+			int64(len(testCase.content)),
+			strings.NewReader(testCase.content),
+		)
+		if testCase.expectFailure {
+			if err == nil {
+				t.Errorf("test index %d: send succeeded unexpectedly", i)
+			} else if err.Error() != testCase.expectedError {
+				t.Errorf("test index %d: error does not match expected: %v != %s", i, err, testCase.expectedError)
+			}
+		} else if err != nil {
+			t.Errorf("test index %d: send failed: %v", i, err)
+		}
+		if output.String() != testCase.expected {
+			t.Errorf("test index %d: output does not match expected: %q != %q", i, output.String(), testCase.expected)
+		}
+	}
+}
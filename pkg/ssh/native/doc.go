@@ -0,0 +1,4 @@
+// Package native provides an SSH client implementation built on the Go SSH
+// library, allowing commands to be run and files to be copied on remote hosts
+// without depending on an installed OpenSSH client.
+package native
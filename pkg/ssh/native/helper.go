@@ -0,0 +1,40 @@
+package native
+
+const (
+	// HelperCommandName is the name of the hidden Mutagen command that hosts
+	// the native SSH client in a subprocess. Running the client in a separate
+	// process allows it to be used anywhere that the OpenSSH client would be
+	// used (i.e. by anything that expects an os/exec.Cmd).
+	HelperCommandName = "ssh-native"
+	// HelperRunCommandName is the name of the helper subcommand that runs a
+	// command on the remote. It accepts the command as its only argument.
+	HelperRunCommandName = "run"
+	// HelperCopyCommandName is the name of the helper subcommand that copies a
+	// file to the remote. It accepts the local path and remote name as its
+	// arguments.
+	HelperCopyCommandName = "copy"
+
+	// HelperFlagUser is the helper flag specifying the remote user.
+	HelperFlagUser = "user"
+	// HelperFlagHost is the helper flag specifying the remote host.
+	HelperFlagHost = "host"
+	// HelperFlagPort is the helper flag specifying the remote port.
+	HelperFlagPort = "port"
+	// HelperFlagPrompter is the helper flag specifying the prompter identifier
+	// to use for prompting.
+	HelperFlagPrompter = "prompter"
+	// HelperFlagConnectTimeout is the helper flag specifying the connection
+	// timeout in seconds.
+	HelperFlagConnectTimeout = "connect-timeout"
+	// HelperFlagKeepaliveInterval is the helper flag specifying the keepalive
+	// interval in seconds.
+	HelperFlagKeepaliveInterval = "keepalive-interval"
+	// HelperFlagForwardAgent is the helper flag enabling SSH agent forwarding
+	// for commands.
+	HelperFlagForwardAgent = "forward-agent"
+
+	// HelperConnectionFailureExitCode is the exit code used by the helper if
+	// it fails to connect or if the remote command's exit status can't be
+	// determined. It matches the code used by OpenSSH in these cases.
+	HelperConnectionFailureExitCode = 255
+)
@@ -0,0 +1,199 @@
+package native
+
+import (
+	"errors"
+	"fmt"
+	"net"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"golang.org/x/crypto/ssh"
+	"golang.org/x/crypto/ssh/knownhosts"
+)
+
+// defaultUserKnownHostsFiles are the user known hosts files used if none are
+// configured. They match OpenSSH's defaults.
+var defaultUserKnownHostsFiles = []string{"~/.ssh/known_hosts", "~/.ssh/known_hosts2"}
+
+// defaultGlobalKnownHostsFiles are the global known hosts files used if none
+// are configured. They match OpenSSH's defaults.
+var defaultGlobalKnownHostsFiles = []string{"/etc/ssh/ssh_known_hosts", "/etc/ssh/ssh_known_hosts2"}
+
+// hostKeyAlgorithmPreferences are the host key algorithms that may be
+// requested based on known host keys, in order of preference, indexed by the
+// key type to which they apply.
+var hostKeyAlgorithmPreferences = []struct {
+	keyType    string
+	algorithms []string
+}{
+	{ssh.KeyAlgoED25519, []string{ssh.KeyAlgoED25519}},
+	{ssh.KeyAlgoECDSA256, []string{ssh.KeyAlgoECDSA256}},
+	{ssh.KeyAlgoECDSA384, []string{ssh.KeyAlgoECDSA384}},
+	{ssh.KeyAlgoECDSA521, []string{ssh.KeyAlgoECDSA521}},
+	{ssh.KeyAlgoRSA, []string{ssh.KeyAlgoRSASHA512, ssh.KeyAlgoRSASHA256, ssh.KeyAlgoRSA}},
+	{ssh.KeyAlgoDSA, []string{ssh.KeyAlgoDSA}},
+}
+
+// loadKnownHosts creates a host key callback that checks host keys against
+// those known hosts files in the specified list that exist. If none of them
+// exist, then nil is returned.
+func loadKnownHosts(paths []string) (ssh.HostKeyCallback, error) {
+	// Filter out files that don't exist.
+	var existing []string
+	for _, path := range paths {
+		if _, err := os.Stat(path); err == nil {
+			existing = append(existing, path)
+		} else if !os.IsNotExist(err) {
+			return nil, err
+		}
+	}
+	if len(existing) == 0 {
+		return nil, nil
+	}
+
+	// Load the files.
+	return knownhosts.New(existing...)
+}
+
+// unknownPublicKey is a public key that doesn't match any known key. It's used
+// to query the keys known for a host.
+type unknownPublicKey struct{}
+
+// Type implements ssh.PublicKey.Type.
+func (unknownPublicKey) Type() string {
+	return "unknown"
+}
+
+// Marshal implements ssh.PublicKey.Marshal.
+func (unknownPublicKey) Marshal() []byte {
+	return nil
+}
+
+// Verify implements ssh.PublicKey.Verify.
+func (unknownPublicKey) Verify([]byte, *ssh.Signature) error {
+	return errors.New("unknown public key")
+}
+
+// hostKeyAlgorithms computes the host key algorithms to request when
+// connecting to the specified address (in host:port form), based on the keys
+// recorded for that address in the specified known hosts files. This mirrors
+// OpenSSH's behavior and is necessary because a server offering multiple host
+// keys may otherwise negotiate a key type that isn't known, which would be
+// treated as a changed host key. If no keys are known for the address (or the
+// known hosts files can't be loaded), then nil is returned, in which case the
+// default algorithms should be used.
+func hostKeyAlgorithms(paths []string, address string) []string {
+	// Load the known hosts files.
+	check, err := loadKnownHosts(paths)
+	if err != nil || check == nil {
+		return nil
+	}
+
+	// Query the keys known for the address by checking a key that can't
+	// match. The remote address is only used if the address is empty.
+	var keyErr *knownhosts.KeyError
+	if err := check(address, &net.TCPAddr{}, unknownPublicKey{}); !errors.As(err, &keyErr) {
+		return nil
+	}
+	knownTypes := make(map[string]bool, len(keyErr.Want))
+	for _, known := range keyErr.Want {
+		knownTypes[known.Key.Type()] = true
+	}
+
+	// Compute the algorithms in order of preference.
+	var result []string
+	for _, preference := range hostKeyAlgorithmPreferences {
+		if knownTypes[preference.keyType] {
+			result = append(result, preference.algorithms...)
+		}
+	}
+
+	// Done.
+	return result
+}
+
+// hostKeyCallback creates a host key callback that verifies host keys against
+// the specified known hosts files. If a host is unknown and a prompter is
+// available, then the user is asked to confirm the host key (in the same
+// manner as OpenSSH), in which case it's recorded in the known hosts file at
+// recordPath (if non-empty). Keys that conflict with known keys are always
+// rejected.
+func hostKeyCallback(paths []string, recordPath string, prompter Prompter) ssh.HostKeyCallback {
+	return func(hostname string, remote net.Addr, key ssh.PublicKey) error {
+		// Check the key against the known hosts files, if any exist.
+		if check, err := loadKnownHosts(paths); err != nil {
+			return fmt.Errorf("unable to load known hosts: %w", err)
+		} else if check != nil {
+			err = check(hostname, remote, key)
+			var keyErr *knownhosts.KeyError
+			if err == nil {
+				return nil
+			} else if !errors.As(err, &keyErr) {
+				return fmt.Errorf("unable to verify host key: %w", err)
+			} else if len(keyErr.Want) > 0 {
+				return fmt.Errorf("host key for %s has changed (possible man-in-the-middle attack): %w", hostname, err)
+			}
+		}
+
+		// The host is unknown, so ask the user to confirm the key.
+		if prompter == nil {
+			return fmt.Errorf("host key verification failed: unknown host %s", hostname)
+		}
+		prompt := fmt.Sprintf(
+			"The authenticity of host '%s (%s)' can't be established.\n"+
+				"%s key fingerprint is %s.\n"+
+				"Are you sure you want to continue connecting (yes/no)? ",
+			hostname, remote, key.Type(), ssh.FingerprintSHA256(key),
+		)
+		response, err := prompter(prompt)
+		if err != nil {
+			return fmt.Errorf("unable to prompt for host key confirmation: %w", err)
+		} else if strings.ToLower(strings.TrimSpace(response)) != "yes" {
+			return errors.New("host key verification failed")
+		}
+
+		// Record the key, if possible.
+		if recordPath != "" {
+			if err := addKnownHost(recordPath, hostname, remote, key); err != nil {
+				return fmt.Errorf("unable to record host key: %w", err)
+			}
+		}
+
+		// Success.
+		return nil
+	}
+}
+
+// addKnownHost appends an entry for the specified host to the known hosts file
+// at the specified path, creating the file (and its parent directory) if
+// necessary.
+func addKnownHost(path, hostname string, remote net.Addr, key ssh.PublicKey) error {
+	// Compute the addresses to record.
+	addresses := []string{knownhosts.Normalize(hostname)}
+	if remote != nil {
+		if address := knownhosts.Normalize(remote.String()); address != addresses[0] {
+			addresses = append(addresses, address)
+		}
+	}
+
+	// Ensure that the parent directory exists.
+	if err := os.MkdirAll(filepath.Dir(path), 0700); err != nil {
+		return fmt.Errorf("unable to create directory: %w", err)
+	}
+
+	// Open the file and defer its closure.
+	file, err := os.OpenFile(path, os.O_WRONLY|os.O_APPEND|os.O_CREATE, 0600)
+	if err != nil {
+		return fmt.Errorf("unable to open known hosts file: %w", err)
+	}
+	defer file.Close()
+
+	// Write the entry.
+	if _, err := fmt.Fprintln(file, knownhosts.Line(addresses, key)); err != nil {
+		return fmt.Errorf("unable to write known hosts entry: %w", err)
+	}
+
+	// Success.
+	return nil
+}
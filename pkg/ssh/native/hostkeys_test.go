@@ -0,0 +1,136 @@
+package native
+
+import (
+	"crypto/ed25519"
+	"crypto/rand"
+	"crypto/rsa"
+	"errors"
+	"net"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"golang.org/x/crypto/ssh"
+	"golang.org/x/crypto/ssh/knownhosts"
+)
+
+// generateHostKey generates a random host public key for testing.
+func generateHostKey(t *testing.T) ssh.PublicKey {
+	t.Helper()
+	public, _, err := ed25519.GenerateKey(rand.Reader)
+	if err != nil {
+		t.Fatal("unable to generate key:", err)
+	}
+	key, err := ssh.NewPublicKey(public)
+	if err != nil {
+		t.Fatal("unable to convert key:", err)
+	}
+	return key
+}
+
+// TestHostKeyCallback tests host key verification and recording.
+func TestHostKeyCallback(t *testing.T) {
+	// Set up the known hosts path, host keys, and remote address.
+	path := filepath.Join(t.TempDir(), ".ssh", "known_hosts")
+	key := generateHostKey(t)
+	otherKey := generateHostKey(t)
+	remote := &net.TCPAddr{IP: net.IPv4(192, 0, 2, 1), Port: 22}
+
+	// Set up a prompter that tracks prompts and returns a configurable answer.
+	var prompts int
+	var answer string
+	prompter := func(string) (string, error) {
+		prompts++
+		return answer, nil
+	}
+
+	// Verify that unknown hosts are rejected without a prompter.
+	if err := hostKeyCallback([]string{path}, path, nil)("example.org:22", remote, key); err == nil {
+		t.Error("unknown host accepted without prompter")
+	}
+
+	// Verify that unknown hosts are rejected if the user declines.
+	answer = "no"
+	if err := hostKeyCallback([]string{path}, path, prompter)("example.org:22", remote, key); err == nil {
+		t.Error("unknown host accepted despite being declined")
+	} else if prompts != 1 {
+		t.Error("unexpected prompt count:", prompts)
+	}
+
+	// Verify that unknown hosts are accepted if the user confirms.
+	answer = "yes\n"
+	if err := hostKeyCallback([]string{path}, path, prompter)("example.org:22", remote, key); err != nil {
+		t.Error("confirmed host rejected:", err)
+	} else if prompts != 2 {
+		t.Error("unexpected prompt count:", prompts)
+	}
+
+	// Verify that the recorded key is accepted without prompting.
+	if err := hostKeyCallback([]string{path}, path, prompter)("example.org:22", remote, key); err != nil {
+		t.Error("known host rejected:", err)
+	} else if prompts != 2 {
+		t.Error("unexpected prompt count:", prompts)
+	}
+
+	// Verify that a conflicting key is rejected without prompting.
+	if err := hostKeyCallback([]string{path}, path, prompter)("example.org:22", remote, otherKey); err == nil {
+		t.Error("conflicting host key accepted")
+	} else if prompts != 2 {
+		t.Error("unexpected prompt count:", prompts)
+	}
+
+	// Verify that prompting errors are propagated.
+	failingPrompter := func(string) (string, error) {
+		return "", errors.New("prompting failed")
+	}
+	otherRemote := &net.TCPAddr{IP: net.IPv4(192, 0, 2, 2), Port: 22}
+	if err := hostKeyCallback([]string{path}, path, failingPrompter)("example.net:22", otherRemote, otherKey); err == nil {
+		t.Error("host accepted despite prompting failure")
+	}
+}
+
+// TestHostKeyAlgorithms tests that host key algorithms are computed from the
+// keys known for a host across known hosts files.
+func TestHostKeyAlgorithms(t *testing.T) {
+	// Create an RSA host key.
+	private, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatal("unable to generate key:", err)
+	}
+	rsaKey, err := ssh.NewPublicKey(&private.PublicKey)
+	if err != nil {
+		t.Fatal("unable to convert key:", err)
+	}
+
+	// Record an RSA key for the host in one file and an Ed25519 key in
+	// another. A nonexistent file is included to verify that it's ignored.
+	directory := t.TempDir()
+	userPath := filepath.Join(directory, "known_hosts")
+	globalPath := filepath.Join(directory, "ssh_known_hosts")
+	missingPath := filepath.Join(directory, "missing")
+	userEntry := knownhosts.Line([]string{knownhosts.Normalize("example.org:2222")}, rsaKey)
+	globalEntry := knownhosts.Line([]string{knownhosts.Normalize("example.org:2222")}, generateHostKey(t))
+	if err := os.WriteFile(userPath, []byte(userEntry+"\n"), 0600); err != nil {
+		t.Fatal("unable to write known hosts file:", err)
+	} else if err := os.WriteFile(globalPath, []byte(globalEntry+"\n"), 0600); err != nil {
+		t.Fatal("unable to write known hosts file:", err)
+	}
+	paths := []string{userPath, missingPath, globalPath}
+
+	// Verify the algorithms for the known host, which should be in order of
+	// preference.
+	expected := []string{ssh.KeyAlgoED25519, ssh.KeyAlgoRSASHA512, ssh.KeyAlgoRSASHA256, ssh.KeyAlgoRSA}
+	if algorithms := hostKeyAlgorithms(paths, "example.org:2222"); !reflect.DeepEqual(algorithms, expected) {
+		t.Error("unexpected algorithms for known host:", algorithms)
+	}
+
+	// Verify that no algorithms are specified for unknown hosts (including the
+	// same host on a different port) or if no known hosts files exist.
+	if algorithms := hostKeyAlgorithms(paths, "example.org:22"); algorithms != nil {
+		t.Error("unexpected algorithms for unknown host:", algorithms)
+	}
+	if algorithms := hostKeyAlgorithms([]string{missingPath}, "example.org:2222"); algorithms != nil {
+		t.Error("unexpected algorithms without known hosts files:", algorithms)
+	}
+}
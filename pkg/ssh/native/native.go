@@ -0,0 +1,374 @@
+package native
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"os"
+	"os/user"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"golang.org/x/crypto/ssh"
+	"golang.org/x/crypto/ssh/agent"
+)
+
+const (
+	// defaultPort is the default SSH port.
+	defaultPort = 22
+	// keepaliveRequestName is the name of the global request used to check
+	// server liveness. It matches the request name used by OpenSSH.
+	keepaliveRequestName = "keepalive@openssh.com"
+	// maximumJumpDepth is the maximum nesting depth allowed for jump hosts. It
+	// prevents cycles in jump host configuration from causing infinite
+	// recursion.
+	maximumJumpDepth = 8
+)
+
+// Prompter is the interface used to prompt the user for information (e.g.
+// passwords or host key confirmation) during connection establishment.
+type Prompter func(prompt string) (string, error)
+
+// Target specifies the remote host to which a connection should be made. The
+// host may be an alias defined in the user's OpenSSH client configuration, in
+// which case the configured hostname, user, port, identity files, and jump
+// hosts are used.
+type Target struct {
+	// User is the user as whom to authenticate. If empty, the configured user
+	// (or the current user's username) is used.
+	User string
+	// Host is the remote hostname or alias.
+	Host string
+	// Port is the remote port. If 0, the configured port (or the default SSH
+	// port) is used.
+	Port uint16
+}
+
+// Options specifies connection behavior.
+type Options struct {
+	// ConnectTimeout is the maximum amount of time allowed for establishing the
+	// TCP connection and performing the SSH handshake. If 0, no timeout is
+	// applied.
+	ConnectTimeout time.Duration
+	// KeepaliveInterval is the interval at which liveness checks are sent to
+	// the server. If 0, no liveness checks are performed.
+	KeepaliveInterval time.Duration
+	// Prompter is the prompter to use for password, passphrase, and host key
+	// prompts. If nil, then interactive authentication methods are disabled
+	// and unknown host keys are rejected.
+	Prompter Prompter
+}
+
+// dialer encapsulates the state needed to establish connections.
+type dialer struct {
+	// options are the connection options.
+	options *Options
+	// homeDirectory is the path to the user's home directory.
+	homeDirectory string
+	// directory is the path to the user's SSH configuration directory.
+	directory string
+	// configuration is the user's OpenSSH client configuration.
+	configuration configuration
+}
+
+// Dial connects and authenticates to the specified target.
+func Dial(target *Target, options *Options) (*ssh.Client, error) {
+	// Validate the target.
+	if target.Host == "" {
+		return nil, errors.New("empty hostname")
+	}
+
+	// Compute the path to the user's home directory and SSH configuration
+	// directory.
+	homeDirectory, err := os.UserHomeDir()
+	if err != nil {
+		return nil, fmt.Errorf("unable to compute path to home directory: %w", err)
+	}
+	directory := filepath.Join(homeDirectory, ".ssh")
+
+	// Load the user's OpenSSH client configuration.
+	configuration, err := loadConfiguration(filepath.Join(directory, "config"))
+	if err != nil {
+		return nil, err
+	}
+
+	// Connect.
+	d := &dialer{
+		options:       options,
+		homeDirectory: homeDirectory,
+		directory:     directory,
+		configuration: configuration,
+	}
+	client, err := d.dial(target, nil, 0)
+	if err != nil {
+		return nil, err
+	}
+
+	// Start liveness checks, if requested.
+	if options.KeepaliveInterval > 0 {
+		go keepalive(client, options.KeepaliveInterval)
+	}
+
+	// Success.
+	return client, nil
+}
+
+// dial connects and authenticates to the specified target. If via is non-nil,
+// then the connection is tunneled through that client (and any jump hosts
+// configured for the target are ignored). Ownership of via passes to dial,
+// which will close it when the resulting connection terminates (or if
+// connecting fails). The depth parameter tracks jump host recursion.
+func (d *dialer) dial(target *Target, via *ssh.Client, depth int) (*ssh.Client, error) {
+	// Compute the effective configuration for the target.
+	host, err := d.configuration.lookup(target.Host, target.User)
+	if err != nil {
+		if via != nil {
+			via.Close()
+		}
+		return nil, err
+	}
+
+	// Determine the username.
+	username := target.User
+	if username == "" {
+		username = host.user
+	}
+	if username == "" {
+		current, err := user.Current()
+		if err != nil {
+			if via != nil {
+				via.Close()
+			}
+			return nil, fmt.Errorf("unable to determine current user: %w", err)
+		}
+		username = current.Username
+	}
+
+	// Determine the address.
+	port := target.Port
+	if port == 0 {
+		port = host.port
+	}
+	if port == 0 {
+		port = defaultPort
+	}
+	address := net.JoinHostPort(host.hostName, strconv.Itoa(int(port)))
+
+	// Determine the identity files.
+	var identityFiles []string
+	if len(host.identityFiles) > 0 {
+		for _, path := range host.identityFiles {
+			expanded, err := expandIdentityPath(path, d.homeDirectory, host.hostName, username)
+			if err != nil {
+				if via != nil {
+					via.Close()
+				}
+				return nil, fmt.Errorf("invalid identity file path (%s): %w", path, err)
+			}
+			identityFiles = append(identityFiles, expanded)
+		}
+	} else {
+		for _, name := range defaultIdentityNames {
+			identityFiles = append(identityFiles, filepath.Join(d.directory, name))
+		}
+	}
+
+	// Determine the certificate files.
+	var certificateFiles []string
+	for _, path := range host.certificateFiles {
+		expanded, err := expandIdentityPath(path, d.homeDirectory, host.hostName, username)
+		if err != nil {
+			if via != nil {
+				via.Close()
+			}
+			return nil, fmt.Errorf("invalid certificate file path (%s): %w", path, err)
+		}
+		certificateFiles = append(certificateFiles, expanded)
+	}
+
+	// If we're not already tunneling and jump hosts are configured, then
+	// connect through them in order.
+	if via == nil && host.proxyJump != "" {
+		if depth >= maximumJumpDepth {
+			return nil, errors.New("too many jump hosts")
+		}
+		for _, specification := range strings.Split(host.proxyJump, ",") {
+			jump, err := parseJumpHost(strings.TrimSpace(specification))
+			if err != nil {
+				if via != nil {
+					via.Close()
+				}
+				return nil, fmt.Errorf("invalid jump host (%s): %w", specification, err)
+			}
+			if via, err = d.dial(jump, via, depth+1); err != nil {
+				return nil, fmt.Errorf("unable to connect to jump host (%s): %w", jump.Host, err)
+			}
+		}
+	}
+
+	// Determine the known hosts files. Newly accepted host keys are recorded
+	// in the first user known hosts file.
+	userKnownHostsFiles := host.userKnownHostsFiles
+	if userKnownHostsFiles == nil {
+		userKnownHostsFiles = defaultUserKnownHostsFiles
+	}
+	globalKnownHostsFiles := host.globalKnownHostsFiles
+	if globalKnownHostsFiles == nil {
+		globalKnownHostsFiles = defaultGlobalKnownHostsFiles
+	}
+	var knownHostsFiles []string
+	for _, path := range append(append([]string{}, userKnownHostsFiles...), globalKnownHostsFiles...) {
+		expanded, err := expandIdentityPath(path, d.homeDirectory, host.hostName, username)
+		if err != nil {
+			if via != nil {
+				via.Close()
+			}
+			return nil, fmt.Errorf("invalid known hosts file path (%s): %w", path, err)
+		}
+		knownHostsFiles = append(knownHostsFiles, expanded)
+	}
+	var recordPath string
+	if len(userKnownHostsFiles) > 0 {
+		recordPath = knownHostsFiles[0]
+	}
+
+	// Set up authentication methods.
+	authMethods, hardwareIdentities, closeAgent := authenticationMethods(identityFiles, certificateFiles, d.options.Prompter)
+	defer closeAgent()
+
+	// Create the client configuration. If keys are already known for the
+	// host, then only their algorithms are requested.
+	configuration := &ssh.ClientConfig{
+		User:              username,
+		Auth:              authMethods,
+		HostKeyCallback:   hostKeyCallback(knownHostsFiles, recordPath, d.options.Prompter),
+		HostKeyAlgorithms: hostKeyAlgorithms(knownHostsFiles, address),
+		Timeout:           d.options.ConnectTimeout,
+	}
+
+	// If we're not tunneling, then connect directly.
+	if via == nil {
+		client, err := ssh.Dial("tcp", address, configuration)
+		return client, authenticationHint(err, hardwareIdentities, host.pkcs11Provider)
+	}
+
+	// Otherwise, connect through the tunnel.
+	connection, err := via.Dial("tcp", address)
+	if err != nil {
+		via.Close()
+		return nil, err
+	}
+	clientConnection, channels, requests, err := ssh.NewClientConn(connection, address, configuration)
+	if err != nil {
+		connection.Close()
+		via.Close()
+		return nil, authenticationHint(err, hardwareIdentities, host.pkcs11Provider)
+	}
+	client := ssh.NewClient(clientConnection, channels, requests)
+
+	// Close the tunnel once the connection terminates.
+	go func() {
+		client.Wait()
+		via.Close()
+	}()
+
+	// Success.
+	return client, nil
+}
+
+// keepalive periodically checks that the server is responsive, closing the
+// client if a response isn't received within the specified interval. It
+// terminates once the client is closed.
+func keepalive(client *ssh.Client, interval time.Duration) {
+	// Monitor for client closure.
+	closed := make(chan struct{})
+	go func() {
+		client.Wait()
+		close(closed)
+	}()
+
+	// Perform liveness checks.
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-closed:
+			return
+		case <-ticker.C:
+		}
+		responses := make(chan error, 1)
+		go func() {
+			_, _, err := client.SendRequest(keepaliveRequestName, true, nil)
+			responses <- err
+		}()
+		timeout := time.NewTimer(interval)
+		select {
+		case err := <-responses:
+			timeout.Stop()
+			if err != nil {
+				client.Close()
+				return
+			}
+		case <-timeout.C:
+			client.Close()
+			return
+		case <-closed:
+			timeout.Stop()
+			return
+		}
+	}
+}
+
+// Run runs the specified command on the remote using the provided client,
+// connecting the provided streams to the command's standard input, output, and
+// error streams. If forwardAgent is true, then the local SSH agent is made
+// available to the command. Run returns the command's exit status. If the
+// command's exit status can't be determined (e.g. due to a connection failure),
+// then an error is returned.
+func Run(client *ssh.Client, command string, forwardAgent bool, stdin io.Reader, stdout, stderr io.Writer) (int, error) {
+	// If requested, set up forwarding to the local SSH agent.
+	if forwardAgent {
+		connection, err := dialAgent()
+		if err != nil {
+			return 0, fmt.Errorf("unable to connect to SSH agent: %w", err)
+		}
+		defer connection.Close()
+		if err := agent.ForwardToAgent(client, agent.NewClient(connection)); err != nil {
+			return 0, fmt.Errorf("unable to set up agent forwarding: %w", err)
+		}
+	}
+
+	// Create a session and defer its closure.
+	session, err := client.NewSession()
+	if err != nil {
+		return 0, fmt.Errorf("unable to create session: %w", err)
+	}
+	defer session.Close()
+
+	// If requested, enable agent forwarding for the session.
+	if forwardAgent {
+		if err := agent.RequestAgentForwarding(session); err != nil {
+			return 0, fmt.Errorf("unable to request agent forwarding: %w", err)
+		}
+	}
+
+	// Connect the session's streams.
+	session.Stdin = stdin
+	session.Stdout = stdout
+	session.Stderr = stderr
+
+	// Run the command and extract its exit status.
+	if err := session.Run(command); err != nil {
+		var exitErr *ssh.ExitError
+		if errors.As(err, &exitErr) {
+			return exitErr.ExitStatus(), nil
+		}
+		return 0, err
+	}
+
+	// Success.
+	return 0, nil
+}
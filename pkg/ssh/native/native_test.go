@@ -0,0 +1,372 @@
+package native
+
+import (
+	"bytes"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/rsa"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"testing"
+
+	"golang.org/x/crypto/ssh"
+	"golang.org/x/crypto/ssh/knownhosts"
+)
+
+const (
+	// testUser is the user accepted by the test server.
+	testUser = "george"
+	// testPassword is the password accepted by the test server.
+	testPassword = "washington"
+)
+
+// forwardTestChannel handles a direct-tcpip channel for the test server by
+// connecting to the requested address and forwarding data.
+func forwardTestChannel(newChannel ssh.NewChannel) {
+	// Parse the request.
+	var request struct {
+		Host       string
+		Port       uint32
+		OriginHost string
+		OriginPort uint32
+	}
+	if err := ssh.Unmarshal(newChannel.ExtraData(), &request); err != nil {
+		newChannel.Reject(ssh.ConnectionFailed, "invalid request")
+		return
+	}
+
+	// Connect to the target.
+	target, err := net.Dial("tcp", net.JoinHostPort(request.Host, strconv.Itoa(int(request.Port))))
+	if err != nil {
+		newChannel.Reject(ssh.ConnectionFailed, err.Error())
+		return
+	}
+
+	// Accept the channel and forward data.
+	channel, requests, err := newChannel.Accept()
+	if err != nil {
+		target.Close()
+		return
+	}
+	go ssh.DiscardRequests(requests)
+	go func() {
+		io.Copy(channel, target)
+		channel.Close()
+	}()
+	io.Copy(target, channel)
+	target.Close()
+}
+
+// serveTestConnection serves a single connection for the test server. It
+// responds to exec requests by echoing the command to standard output and
+// exiting with a status equal to the command length. It also supports
+// direct-tcpip channels so that it can act as a jump host.
+func serveTestConnection(connection net.Conn, configuration *ssh.ServerConfig) {
+	// Perform the handshake.
+	_, channels, requests, err := ssh.NewServerConn(connection, configuration)
+	if err != nil {
+		return
+	}
+	go ssh.DiscardRequests(requests)
+
+	// Serve sessions.
+	for newChannel := range channels {
+		if newChannel.ChannelType() == "direct-tcpip" {
+			go forwardTestChannel(newChannel)
+			continue
+		} else if newChannel.ChannelType() != "session" {
+			newChannel.Reject(ssh.UnknownChannelType, "unsupported channel type")
+			continue
+		}
+		channel, channelRequests, err := newChannel.Accept()
+		if err != nil {
+			continue
+		}
+		go func() {
+			defer channel.Close()
+			for request := range channelRequests {
+				if request.Type != "exec" || len(request.Payload) < 4 {
+					request.Reply(false, nil)
+					continue
+				}
+				command := string(request.Payload[4:])
+				request.Reply(true, nil)
+				channel.Write([]byte(command))
+				status := make([]byte, 4)
+				binary.BigEndian.PutUint32(status, uint32(len(command)))
+				channel.SendRequest("exit-status", false, status)
+				return
+			}
+		}()
+	}
+}
+
+// generateHostKeySigner generates a random host key signer of the specified
+// type ("ed25519", "ecdsa", or "rsa") for testing.
+func generateHostKeySigner(t *testing.T, keyType string) ssh.Signer {
+	t.Helper()
+	var private interface{}
+	var err error
+	switch keyType {
+	case "ed25519":
+		_, private, err = ed25519.GenerateKey(rand.Reader)
+	case "ecdsa":
+		private, err = ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	case "rsa":
+		private, err = rsa.GenerateKey(rand.Reader, 2048)
+	default:
+		t.Fatal("unknown key type:", keyType)
+	}
+	if err != nil {
+		t.Fatal("unable to generate host key:", err)
+	}
+	signer, err := ssh.NewSignerFromKey(private)
+	if err != nil {
+		t.Fatal("unable to create host key signer:", err)
+	}
+	return signer
+}
+
+// startTestServer starts an SSH server that accepts password authentication
+// for the test user using a single Ed25519 host key. It returns the server's
+// address.
+func startTestServer(t *testing.T) *net.TCPAddr {
+	t.Helper()
+	return startTestServerWithHostKeys(t, generateHostKeySigner(t, "ed25519"))
+}
+
+// startTestServerWithHostKeys starts an SSH server that accepts password
+// authentication for the test user using the specified host keys. It returns
+// the server's address.
+func startTestServerWithHostKeys(t *testing.T, signers ...ssh.Signer) *net.TCPAddr {
+	t.Helper()
+
+	// Create the server configuration.
+	configuration := &ssh.ServerConfig{
+		PasswordCallback: func(metadata ssh.ConnMetadata, password []byte) (*ssh.Permissions, error) {
+			if metadata.User() == testUser && string(password) == testPassword {
+				return nil, nil
+			}
+			return nil, ssh.ErrNoAuth
+		},
+	}
+	for _, signer := range signers {
+		configuration.AddHostKey(signer)
+	}
+
+	// Start listening and serving.
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal("unable to create listener:", err)
+	}
+	t.Cleanup(func() { listener.Close() })
+	go func() {
+		for {
+			connection, err := listener.Accept()
+			if err != nil {
+				return
+			}
+			go serveTestConnection(connection, configuration)
+		}
+	}()
+
+	// Done.
+	return listener.Addr().(*net.TCPAddr)
+}
+
+// TestDialAndRun tests connecting to a server with password authentication and
+// host key confirmation and then running a command.
+func TestDialAndRun(t *testing.T) {
+	// Isolate the test from the user's SSH configuration.
+	t.Setenv("HOME", t.TempDir())
+	t.Setenv("USERPROFILE", t.TempDir())
+	t.Setenv("SSH_AUTH_SOCK", "")
+
+	// Start the server.
+	address := startTestServer(t)
+
+	// Set up a prompter that confirms the host key and provides the password.
+	var prompts []string
+	prompter := func(prompt string) (string, error) {
+		prompts = append(prompts, prompt)
+		if strings.Contains(prompt, "continue connecting") {
+			return "yes", nil
+		}
+		return testPassword, nil
+	}
+
+	// Connect and defer closure of the connection.
+	target := &Target{User: testUser, Host: address.IP.String(), Port: uint16(address.Port)}
+	client, err := Dial(target, &Options{Prompter: prompter})
+	if err != nil {
+		t.Fatal("unable to connect:", err)
+	}
+	defer client.Close()
+
+	// Verify that the host key and password were both prompted for.
+	if len(prompts) != 2 {
+		t.Fatal("unexpected prompt count:", len(prompts))
+	}
+
+	// Run a command and verify its output and exit status.
+	var output bytes.Buffer
+	if status, err := Run(client, "hello", false, nil, &output, nil); err != nil {
+		t.Fatal("unable to run command:", err)
+	} else if status != len("hello") {
+		t.Error("unexpected exit status:", status)
+	} else if output.String() != "hello" {
+		t.Error("unexpected output:", output.String())
+	}
+
+	// Verify that a subsequent connection uses the recorded host key and thus
+	// only prompts for the password.
+	prompts = nil
+	client2, err := Dial(target, &Options{Prompter: prompter})
+	if err != nil {
+		t.Fatal("unable to reconnect:", err)
+	}
+	client2.Close()
+	if len(prompts) != 1 {
+		t.Error("unexpected prompt count on reconnection:", len(prompts))
+	}
+}
+
+// TestDialAliasThroughJumpHost tests connecting to a host alias defined in the
+// user's OpenSSH client configuration that requires a jump host.
+func TestDialAliasThroughJumpHost(t *testing.T) {
+	// Isolate the test from the user's SSH configuration.
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+	t.Setenv("USERPROFILE", home)
+	t.Setenv("SSH_AUTH_SOCK", "")
+
+	// Start the server, which we'll use as both the jump host and the target.
+	address := startTestServer(t)
+
+	// Write an OpenSSH client configuration with an alias for the server.
+	configuration := fmt.Sprintf(
+		"Host target\n  HostName %s\n  Port %d\n  User %s\n  ProxyJump %s@%s\n",
+		address.IP, address.Port, testUser, testUser, address,
+	)
+	if err := os.Mkdir(filepath.Join(home, ".ssh"), 0700); err != nil {
+		t.Fatal("unable to create SSH configuration directory:", err)
+	} else if err := os.WriteFile(filepath.Join(home, ".ssh", "config"), []byte(configuration), 0600); err != nil {
+		t.Fatal("unable to write SSH configuration:", err)
+	}
+
+	// Set up a prompter that confirms host keys and provides passwords.
+	var prompts int
+	prompter := func(prompt string) (string, error) {
+		prompts++
+		if strings.Contains(prompt, "continue connecting") {
+			return "yes", nil
+		}
+		return testPassword, nil
+	}
+
+	// Connect and defer closure of the connection.
+	client, err := Dial(&Target{Host: "target"}, &Options{Prompter: prompter})
+	if err != nil {
+		t.Fatal("unable to connect:", err)
+	}
+	defer client.Close()
+
+	// Verify that the host key was only confirmed once (since both hops use
+	// the same address) and that passwords were requested for both hops.
+	if prompts != 3 {
+		t.Error("unexpected prompt count:", prompts)
+	}
+
+	// Run a command and verify its exit status.
+	if status, err := Run(client, "hi", false, nil, nil, nil); err != nil {
+		t.Fatal("unable to run command:", err)
+	} else if status != len("hi") {
+		t.Error("unexpected exit status:", status)
+	}
+}
+
+// TestDialWithoutPrompterUnknownHostFails tests that connecting to an unknown
+// host fails if prompting isn't available.
+func TestDialWithoutPrompterUnknownHostFails(t *testing.T) {
+	// Isolate the test from the user's SSH configuration.
+	t.Setenv("HOME", t.TempDir())
+	t.Setenv("USERPROFILE", t.TempDir())
+	t.Setenv("SSH_AUTH_SOCK", "")
+
+	// Start the server.
+	address := startTestServer(t)
+
+	// Attempt to connect.
+	target := &Target{User: testUser, Host: address.IP.String(), Port: uint16(address.Port)}
+	if client, err := Dial(target, &Options{}); err == nil {
+		client.Close()
+		t.Error("connection to unknown host succeeded without prompter")
+	}
+}
+
+// TestDialMultipleHostKeyTypes tests connecting to a server that offers several
+// host key types when only one of them is known, with the known key recorded in
+// a known hosts file specified by the user's OpenSSH client configuration.
+func TestDialMultipleHostKeyTypes(t *testing.T) {
+	// Isolate the test from the user's SSH configuration.
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+	t.Setenv("USERPROFILE", home)
+	t.Setenv("SSH_AUTH_SOCK", "")
+
+	// Start a server that offers multiple host key types. The Ed25519 key is
+	// the least preferred by default, so it's the one that we record.
+	known := generateHostKeySigner(t, "ed25519")
+	address := startTestServerWithHostKeys(t,
+		generateHostKeySigner(t, "ecdsa"),
+		generateHostKeySigner(t, "rsa"),
+		known,
+	)
+
+	// Write an OpenSSH client configuration that specifies a custom user known
+	// hosts file and disables global known hosts files.
+	if err := os.Mkdir(filepath.Join(home, ".ssh"), 0700); err != nil {
+		t.Fatal("unable to create SSH configuration directory:", err)
+	}
+	configuration := "Host *\n  UserKnownHostsFile ~/custom_known_hosts\n  GlobalKnownHostsFile none\n"
+	if err := os.WriteFile(filepath.Join(home, ".ssh", "config"), []byte(configuration), 0600); err != nil {
+		t.Fatal("unable to write SSH configuration:", err)
+	}
+
+	// Record the known host key in the custom known hosts file.
+	entry := knownhosts.Line([]string{knownhosts.Normalize(address.String())}, known.PublicKey())
+	if err := os.WriteFile(filepath.Join(home, "custom_known_hosts"), []byte(entry+"\n"), 0600); err != nil {
+		t.Fatal("unable to write known hosts file:", err)
+	}
+
+	// Set up a prompter that only provides passwords, failing host key
+	// confirmations.
+	prompter := func(prompt string) (string, error) {
+		if strings.Contains(prompt, "continue connecting") {
+			return "", errors.New("unexpected host key confirmation")
+		}
+		return testPassword, nil
+	}
+
+	// Connect and verify that the known key was used.
+	target := &Target{User: testUser, Host: address.IP.String(), Port: uint16(address.Port)}
+	client, err := Dial(target, &Options{Prompter: prompter})
+	if err != nil {
+		t.Fatal("unable to connect:", err)
+	}
+	client.Close()
+
+	// Verify that the default known hosts file wasn't used.
+	if _, err := os.Stat(filepath.Join(home, ".ssh", "known_hosts")); !os.IsNotExist(err) {
+		t.Error("default known hosts file created or inaccessible:", err)
+	}
+}
@@ -30,6 +30,12 @@ func ConnectTimeoutFlag(timeout uint64) string {
 	return fmt.Sprintf("-oConnectTimeout=%d", timeout)
 }
 
+// ForwardAgentFlag returns a flag that can be passed to ssh to enable SSH agent
+// forwarding.
+func ForwardAgentFlag() string {
+	return "-oForwardAgent=yes"
+}
+
 // ServerAliveFlags returns a set of flags that can be passed to scp or ssh to
 // enable use of server alive messages. The provided interval is in seconds.
 // Both the interval and count must be greater than 0, otherwise this function
@@ -0,0 +1,61 @@
+package synchronization
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/mutagen-io/mutagen/pkg/filesystem/behavior"
+)
+
+// probeDirectory returns the nearest existing directory at or above the
+// specified path, which may not yet exist (e.g. if the synchronization root
+// hasn't been created yet) or may be a file.
+func probeDirectory(path string) (string, error) {
+	for {
+		if metadata, err := os.Stat(path); err == nil {
+			if metadata.IsDir() {
+				return path, nil
+			}
+		} else if !os.IsNotExist(err) {
+			return "", fmt.Errorf("unable to query path: %w", err)
+		}
+		parent := filepath.Dir(path)
+		if parent == path {
+			return "", fmt.Errorf("no existing parent directory found")
+		}
+		path = parent
+	}
+}
+
+// ProbeFilesystemCapabilities probes the capabilities of the filesystem on
+// which the specified synchronization root resides using the specified probe
+// mode. If the root doesn't exist, then its nearest existing parent directory
+// is probed instead.
+func ProbeFilesystemCapabilities(root string, probeMode behavior.ProbeMode) (*FilesystemCapabilities, error) {
+	// Find a directory to probe.
+	directory, err := probeDirectory(root)
+	if err != nil {
+		return nil, fmt.Errorf("unable to locate probe directory: %w", err)
+	}
+
+	// Perform individual probes.
+	result := &FilesystemCapabilities{}
+	if result.CaseSensitive, _, err = behavior.CaseSensitiveByPath(directory, probeMode); err != nil {
+		return nil, fmt.Errorf("unable to probe case sensitivity: %w", err)
+	}
+	if result.SymbolicLinks, _, err = behavior.SupportsSymbolicLinksByPath(directory, probeMode); err != nil {
+		return nil, fmt.Errorf("unable to probe symbolic link support: %w", err)
+	}
+	if result.PreservesExecutability, _, err = behavior.PreservesExecutabilityByPath(directory, probeMode); err != nil {
+		return nil, fmt.Errorf("unable to probe executability preservation: %w", err)
+	}
+	if granularity, _, err := behavior.ModificationTimeGranularityByPath(directory, probeMode); err != nil {
+		return nil, fmt.Errorf("unable to probe modification time granularity: %w", err)
+	} else {
+		result.ModificationTimeGranularity = uint64(granularity)
+	}
+
+	// Success.
+	return result, nil
+}
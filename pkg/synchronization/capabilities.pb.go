@@ -0,0 +1,193 @@
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// versions:
+// 	protoc-gen-go v1.28.0
+// 	protoc        v3.19.4
+// source: synchronization/capabilities.proto
+
+package synchronization
+
+import (
+	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
+	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	reflect "reflect"
+	sync "sync"
+)
+
+const (
+	// Verify that this generated code is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(20 - protoimpl.MinVersion)
+	// Verify that runtime/protoimpl is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
+)
+
+// FilesystemCapabilities encodes the capabilities of the filesystem on which an
+// endpoint's synchronization root resides, as probed when connecting to the
+// endpoint.
+type FilesystemCapabilities struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	// CaseSensitive indicates whether or not the filesystem distinguishes
+	// between names that differ only in case.
+	CaseSensitive bool `protobuf:"varint,1,opt,name=caseSensitive,proto3" json:"caseSensitive,omitempty"`
+	// SymbolicLinks indicates whether or not the filesystem supports the
+	// creation of symbolic links.
+	SymbolicLinks bool `protobuf:"varint,2,opt,name=symbolicLinks,proto3" json:"symbolicLinks,omitempty"`
+	// PreservesExecutability indicates whether or not the filesystem preserves
+	// POSIX executability bits.
+	PreservesExecutability bool `protobuf:"varint,3,opt,name=preservesExecutability,proto3" json:"preservesExecutability,omitempty"`
+	// ModificationTimeGranularity is the granularity (in nanoseconds) with
+	// which the filesystem records modification times.
+	ModificationTimeGranularity uint64 `protobuf:"varint,4,opt,name=modificationTimeGranularity,proto3" json:"modificationTimeGranularity,omitempty"`
+}
+
+func (x *FilesystemCapabilities) Reset() {
+	*x = FilesystemCapabilities{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_synchronization_capabilities_proto_msgTypes[0]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *FilesystemCapabilities) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*FilesystemCapabilities) ProtoMessage() {}
+
+func (x *FilesystemCapabilities) ProtoReflect() protoreflect.Message {
+	mi := &file_synchronization_capabilities_proto_msgTypes[0]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use FilesystemCapabilities.ProtoReflect.Descriptor instead.
+func (*FilesystemCapabilities) Descriptor() ([]byte, []int) {
+	return file_synchronization_capabilities_proto_rawDescGZIP(), []int{0}
+}
+
+func (x *FilesystemCapabilities) GetCaseSensitive() bool {
+	if x != nil {
+		return x.CaseSensitive
+	}
+	return false
+}
+
+func (x *FilesystemCapabilities) GetSymbolicLinks() bool {
+	if x != nil {
+		return x.SymbolicLinks
+	}
+	return false
+}
+
+func (x *FilesystemCapabilities) GetPreservesExecutability() bool {
+	if x != nil {
+		return x.PreservesExecutability
+	}
+	return false
+}
+
+func (x *FilesystemCapabilities) GetModificationTimeGranularity() uint64 {
+	if x != nil {
+		return x.ModificationTimeGranularity
+	}
+	return 0
+}
+
+var File_synchronization_capabilities_proto protoreflect.FileDescriptor
+
+var file_synchronization_capabilities_proto_rawDesc = []byte{
+	0x0a, 0x22, 0x73, 0x79, 0x6e, 0x63, 0x68, 0x72, 0x6f, 0x6e, 0x69, 0x7a, 0x61, 0x74, 0x69, 0x6f,
+	0x6e, 0x2f, 0x63, 0x61, 0x70, 0x61, 0x62, 0x69, 0x6c, 0x69, 0x74, 0x69, 0x65, 0x73, 0x2e, 0x70,
+	0x72, 0x6f, 0x74, 0x6f, 0x12, 0x0f, 0x73, 0x79, 0x6e, 0x63, 0x68, 0x72, 0x6f, 0x6e, 0x69, 0x7a,
+	0x61, 0x74, 0x69, 0x6f, 0x6e, 0x22, 0xde, 0x01, 0x0a, 0x16, 0x46, 0x69, 0x6c, 0x65, 0x73, 0x79,
+	0x73, 0x74, 0x65, 0x6d, 0x43, 0x61, 0x70, 0x61, 0x62, 0x69, 0x6c, 0x69, 0x74, 0x69, 0x65, 0x73,
+	0x12, 0x24, 0x0a, 0x0d, 0x63, 0x61, 0x73, 0x65, 0x53, 0x65, 0x6e, 0x73, 0x69, 0x74, 0x69, 0x76,
+	0x65, 0x18, 0x01, 0x20, 0x01, 0x28, 0x08, 0x52, 0x0d, 0x63, 0x61, 0x73, 0x65, 0x53, 0x65, 0x6e,
+	0x73, 0x69, 0x74, 0x69, 0x76, 0x65, 0x12, 0x24, 0x0a, 0x0d, 0x73, 0x79, 0x6d, 0x62, 0x6f, 0x6c,
+	0x69, 0x63, 0x4c, 0x69, 0x6e, 0x6b, 0x73, 0x18, 0x02, 0x20, 0x01, 0x28, 0x08, 0x52, 0x0d, 0x73,
+	0x79, 0x6d, 0x62, 0x6f, 0x6c, 0x69, 0x63, 0x4c, 0x69, 0x6e, 0x6b, 0x73, 0x12, 0x36, 0x0a, 0x16,
+	0x70, 0x72, 0x65, 0x73, 0x65, 0x72, 0x76, 0x65, 0x73, 0x45, 0x78, 0x65, 0x63, 0x75, 0x74, 0x61,
+	0x62, 0x69, 0x6c, 0x69, 0x74, 0x79, 0x18, 0x03, 0x20, 0x01, 0x28, 0x08, 0x52, 0x16, 0x70, 0x72,
+	0x65, 0x73, 0x65, 0x72, 0x76, 0x65, 0x73, 0x45, 0x78, 0x65, 0x63, 0x75, 0x74, 0x61, 0x62, 0x69,
+	0x6c, 0x69, 0x74, 0x79, 0x12, 0x40, 0x0a, 0x1b, 0x6d, 0x6f, 0x64, 0x69, 0x66, 0x69, 0x63, 0x61,
+	0x74, 0x69, 0x6f, 0x6e, 0x54, 0x69, 0x6d, 0x65, 0x47, 0x72, 0x61, 0x6e, 0x75, 0x6c, 0x61, 0x72,
+	0x69, 0x74, 0x79, 0x18, 0x04, 0x20, 0x01, 0x28, 0x04, 0x52, 0x1b, 0x6d, 0x6f, 0x64, 0x69, 0x66,
+	0x69, 0x63, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x54, 0x69, 0x6d, 0x65, 0x47, 0x72, 0x61, 0x6e, 0x75,
+	0x6c, 0x61, 0x72, 0x69, 0x74, 0x79, 0x42, 0x33, 0x5a, 0x31, 0x67, 0x69, 0x74, 0x68, 0x75, 0x62,
+	0x2e, 0x63, 0x6f, 0x6d, 0x2f, 0x6d, 0x75, 0x74, 0x61, 0x67, 0x65, 0x6e, 0x2d, 0x69, 0x6f, 0x2f,
+	0x6d, 0x75, 0x74, 0x61, 0x67, 0x65, 0x6e, 0x2f, 0x70, 0x6b, 0x67, 0x2f, 0x73, 0x79, 0x6e, 0x63,
+	0x68, 0x72, 0x6f, 0x6e, 0x69, 0x7a, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x62, 0x06, 0x70, 0x72, 0x6f,
+	0x74, 0x6f, 0x33,
+}
+
+var (
+	file_synchronization_capabilities_proto_rawDescOnce sync.Once
+	file_synchronization_capabilities_proto_rawDescData = file_synchronization_capabilities_proto_rawDesc
+)
+
+func file_synchronization_capabilities_proto_rawDescGZIP() []byte {
+	file_synchronization_capabilities_proto_rawDescOnce.Do(func() {
+		file_synchronization_capabilities_proto_rawDescData = protoimpl.X.CompressGZIP(file_synchronization_capabilities_proto_rawDescData)
+	})
+	return file_synchronization_capabilities_proto_rawDescData
+}
+
+var file_synchronization_capabilities_proto_msgTypes = make([]protoimpl.MessageInfo, 1)
+var file_synchronization_capabilities_proto_goTypes = []interface{}{
+	(*FilesystemCapabilities)(nil), // 0: synchronization.FilesystemCapabilities
+}
+var file_synchronization_capabilities_proto_depIdxs = []int32{
+	0, // [0:0] is the sub-list for method output_type
+	0, // [0:0] is the sub-list for method input_type
+	0, // [0:0] is the sub-list for extension type_name
+	0, // [0:0] is the sub-list for extension extendee
+	0, // [0:0] is the sub-list for field type_name
+}
+
+func init() { file_synchronization_capabilities_proto_init() }
+func file_synchronization_capabilities_proto_init() {
+	if File_synchronization_capabilities_proto != nil {
+		return
+	}
+	if !protoimpl.UnsafeEnabled {
+		file_synchronization_capabilities_proto_msgTypes[0].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*FilesystemCapabilities); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+	}
+	type x struct{}
+	out := protoimpl.TypeBuilder{
+		File: protoimpl.DescBuilder{
+			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
+			RawDescriptor: file_synchronization_capabilities_proto_rawDesc,
+			NumEnums:      0,
+			NumMessages:   1,
+			NumExtensions: 0,
+			NumServices:   0,
+		},
+		GoTypes:           file_synchronization_capabilities_proto_goTypes,
+		DependencyIndexes: file_synchronization_capabilities_proto_depIdxs,
+		MessageInfos:      file_synchronization_capabilities_proto_msgTypes,
+	}.Build()
+	File_synchronization_capabilities_proto = out.File
+	file_synchronization_capabilities_proto_rawDesc = nil
+	file_synchronization_capabilities_proto_goTypes = nil
+	file_synchronization_capabilities_proto_depIdxs = nil
+}
@@ -0,0 +1,40 @@
+package synchronization
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/mutagen-io/mutagen/pkg/filesystem/behavior"
+)
+
+// TestProbeFilesystemCapabilities tests ProbeFilesystemCapabilities.
+func TestProbeFilesystemCapabilities(t *testing.T) {
+	// Create a temporary directory and file.
+	directory := t.TempDir()
+	file := filepath.Join(directory, "file")
+	if err := os.WriteFile(file, nil, 0600); err != nil {
+		t.Fatal("unable to create test file:", err)
+	}
+
+	// Probe an existing directory, a file, and a non-existent path.
+	paths := []string{
+		directory,
+		file,
+		filepath.Join(directory, "missing", "root"),
+	}
+	for _, path := range paths {
+		if capabilities, err := ProbeFilesystemCapabilities(path, behavior.ProbeMode_ProbeModeProbe); err != nil {
+			t.Errorf("unable to probe capabilities for %s: %v", path, err)
+		} else if capabilities.ModificationTimeGranularity == 0 {
+			t.Errorf("zero modification time granularity for %s", path)
+		}
+	}
+
+	// Verify that no probe files were left behind.
+	if contents, err := os.ReadDir(directory); err != nil {
+		t.Fatal("unable to read test directory:", err)
+	} else if len(contents) != 1 {
+		t.Error("unexpected test directory contents after probing:", len(contents))
+	}
+}
@@ -6,6 +6,7 @@ import (
 
 	"github.com/mutagen-io/mutagen/pkg/comparison"
 	"github.com/mutagen-io/mutagen/pkg/filesystem"
+	"github.com/mutagen-io/mutagen/pkg/process"
 	"github.com/mutagen-io/mutagen/pkg/synchronization/core"
 )
 
@@ -35,6 +36,9 @@ func (c *Configuration) EnsureValid(endpointSpecific bool) error {
 	// The maximum staging file size doesn't need to be validated - any of its
 	// values are technically valid regardless of the source.
 
+	// The maximum total staging size doesn't need to be validated - any of its
+	// values are technically valid regardless of the source.
+
 	// Verify that the probe mode is unspecified or supported for usage.
 	if !(c.ProbeMode.IsDefault() || c.ProbeMode.Supported()) {
 		return errors.New("unknown or unsupported probe mode")
@@ -50,6 +54,35 @@ func (c *Configuration) EnsureValid(endpointSpecific bool) error {
 		return errors.New("unknown or unsupported staging mode")
 	}
 
+	// Verify that the Unicode normalization mode is unspecified or supported
+	// for usage.
+	if endpointSpecific {
+		if !c.UnicodeNormalizationMode.IsDefault() {
+			return errors.New("Unicode normalization mode cannot be specified on an endpoint-specific basis")
+		}
+	} else {
+		if !(c.UnicodeNormalizationMode.IsDefault() || c.UnicodeNormalizationMode.Supported()) {
+			return errors.New("unknown or unsupported Unicode normalization mode")
+		}
+	}
+
+	// Verify that the replacement mode is unspecified or supported for usage.
+	if !(c.ReplacementMode.IsDefault() || c.ReplacementMode.Supported()) {
+		return errors.New("unknown or unsupported replacement mode")
+	}
+
+	// Verify that the mirror enforcement mode is unspecified or supported for
+	// usage.
+	if endpointSpecific {
+		if !c.MirrorEnforcementMode.IsDefault() {
+			return errors.New("mirror enforcement mode cannot be specified on an endpoint-specific basis")
+		}
+	} else {
+		if !(c.MirrorEnforcementMode.IsDefault() || c.MirrorEnforcementMode.Supported()) {
+			return errors.New("unknown or unsupported mirror enforcement mode")
+		}
+	}
+
 	// Verify that the symbolic link mode is unspecified or supported for usage.
 	if endpointSpecific {
 		if !c.SymbolicLinkMode.IsDefault() {
@@ -69,6 +102,12 @@ func (c *Configuration) EnsureValid(endpointSpecific bool) error {
 	// The watch polling interval doesn't need to be validated - any of its
 	// values are technically valid regardless of the source.
 
+	// Verify that the minimum cycle interval isn't specified on an
+	// endpoint-specific basis.
+	if endpointSpecific && c.MinimumCycleInterval != 0 {
+		return errors.New("minimum cycle interval cannot be specified on an endpoint-specific basis")
+	}
+
 	// Verify that default ignores are unset for endpoint-specific
 	// configurations and that any specified ignores are valid. This field is
 	// deprecated, but existing sessions may have it set, in which case we'll
@@ -105,6 +144,12 @@ func (c *Configuration) EnsureValid(endpointSpecific bool) error {
 		}
 	}
 
+	// Verify that VCS ignore file importing isn't specified on an
+	// endpoint-specific basis.
+	if endpointSpecific && c.IgnoreVCSIgnores {
+		return errors.New("VCS ignore file importing cannot be specified on an endpoint-specific basis")
+	}
+
 	// Verify the default file mode.
 	if c.DefaultFileMode != 0 {
 		if err := core.EnsureDefaultFileModeValid(filesystem.Mode(c.DefaultFileMode)); err != nil {
@@ -133,6 +178,47 @@ func (c *Configuration) EnsureValid(endpointSpecific bool) error {
 		}
 	}
 
+	// Verify that the deletion threshold is unspecified or a valid percentage.
+	if endpointSpecific {
+		if c.DeletionThreshold != 0 {
+			return errors.New("deletion threshold cannot be specified on an endpoint-specific basis")
+		}
+	} else if c.DeletionThreshold > 100 {
+		return errors.New("deletion threshold exceeds 100 percent")
+	}
+
+	// The trash directory, trash retention period, and maximum trash size
+	// don't need to be validated - any of their values are technically valid
+	// regardless of the source.
+
+	// The minimum free space doesn't need to be validated - any of its values
+	// are technically valid regardless of the source.
+
+	// The scan concurrency doesn't need to be validated - any of its values
+	// are technically valid regardless of the source.
+
+	// The memory budget doesn't need to be validated - any of its values are
+	// technically valid regardless of the source.
+
+	// Verify that the agent niceness is within the supported range.
+	if c.AgentNiceness > process.MaximumNiceness {
+		return fmt.Errorf("agent niceness exceeds %d", process.MaximumNiceness)
+	}
+
+	// Verify that any after-sync hook commands are non-empty.
+	for _, command := range c.AfterSync {
+		if command == "" {
+			return errors.New("empty after-sync hook command")
+		}
+	}
+
+	// Verify that any before-apply hook commands are non-empty.
+	for _, command := range c.BeforeApply {
+		if command == "" {
+			return errors.New("empty before-apply hook command")
+		}
+	}
+
 	// Success.
 	return nil
 }
@@ -149,19 +235,63 @@ func (c *Configuration) Equal(other *Configuration) bool {
 	return c.SynchronizationMode == other.SynchronizationMode &&
 		c.MaximumEntryCount == other.MaximumEntryCount &&
 		c.MaximumStagingFileSize == other.MaximumStagingFileSize &&
+		c.MaximumStagingTotalSize == other.MaximumStagingTotalSize &&
 		c.ProbeMode == other.ProbeMode &&
 		c.ScanMode == other.ScanMode &&
 		c.StageMode == other.StageMode &&
+		c.UnicodeNormalizationMode == other.UnicodeNormalizationMode &&
+		c.ReplacementMode == other.ReplacementMode &&
+		c.MirrorEnforcementMode == other.MirrorEnforcementMode &&
 		c.SymbolicLinkMode == other.SymbolicLinkMode &&
 		c.WatchMode == other.WatchMode &&
 		c.WatchPollingInterval == other.WatchPollingInterval &&
+		c.MinimumCycleInterval == other.MinimumCycleInterval &&
 		comparison.StringSlicesEqual(c.DefaultIgnores, other.DefaultIgnores) &&
 		comparison.StringSlicesEqual(c.Ignores, other.Ignores) &&
 		c.IgnoreVCSMode == other.IgnoreVCSMode &&
+		c.IgnoreVCSIgnores == other.IgnoreVCSIgnores &&
 		c.DefaultFileMode == other.DefaultFileMode &&
 		c.DefaultDirectoryMode == other.DefaultDirectoryMode &&
 		c.DefaultOwner == other.DefaultOwner &&
-		c.DefaultGroup == other.DefaultGroup
+		c.DefaultGroup == other.DefaultGroup &&
+		comparison.StringSlicesEqual(c.AfterSync, other.AfterSync) &&
+		comparison.StringSlicesEqual(c.BeforeApply, other.BeforeApply) &&
+		c.DeletionThreshold == other.DeletionThreshold &&
+		c.TrashDirectory == other.TrashDirectory &&
+		c.TrashRetentionPeriod == other.TrashRetentionPeriod &&
+		c.MaximumTrashSize == other.MaximumTrashSize &&
+		c.VersionCount == other.VersionCount &&
+		c.MinimumFreeSpace == other.MinimumFreeSpace &&
+		c.ScanConcurrency == other.ScanConcurrency &&
+		c.MemoryBudget == other.MemoryBudget &&
+		c.AgentNiceness == other.AgentNiceness &&
+		c.ModificationTimeTolerance == other.ModificationTimeTolerance
+}
+
+// mergeHooks merges two lists of hook commands of differing priorities. Hooks
+// from the lower-priority list run first. Commands that appear in both lists
+// (e.g. because a global configuration and a project configuration specify the
+// same hook) are only run once, at the position of their first occurrence.
+func mergeHooks(lower, higher []string) []string {
+	// If neither list has any hooks, then there's nothing to merge.
+	if len(lower) == 0 && len(higher) == 0 {
+		return nil
+	}
+
+	// Merge the lists, skipping duplicates.
+	result := make([]string, 0, len(lower)+len(higher))
+	seen := make(map[string]bool, len(lower)+len(higher))
+	for _, list := range [][]string{lower, higher} {
+		for _, command := range list {
+			if !seen[command] {
+				seen[command] = true
+				result = append(result, command)
+			}
+		}
+	}
+
+	// Done.
+	return result
 }
 
 // MergeConfigurations merges two configurations of differing priorities. Both
@@ -191,6 +321,13 @@ func MergeConfigurations(lower, higher *Configuration) *Configuration {
 		result.MaximumStagingFileSize = lower.MaximumStagingFileSize
 	}
 
+	// Merge maximum total staging size.
+	if higher.MaximumStagingTotalSize != 0 {
+		result.MaximumStagingTotalSize = higher.MaximumStagingTotalSize
+	} else {
+		result.MaximumStagingTotalSize = lower.MaximumStagingTotalSize
+	}
+
 	// Merge probing mode.
 	if !higher.ProbeMode.IsDefault() {
 		result.ProbeMode = higher.ProbeMode
@@ -212,6 +349,27 @@ func MergeConfigurations(lower, higher *Configuration) *Configuration {
 		result.StageMode = lower.StageMode
 	}
 
+	// Merge Unicode normalization mode.
+	if !higher.UnicodeNormalizationMode.IsDefault() {
+		result.UnicodeNormalizationMode = higher.UnicodeNormalizationMode
+	} else {
+		result.UnicodeNormalizationMode = lower.UnicodeNormalizationMode
+	}
+
+	// Merge replacement mode.
+	if !higher.ReplacementMode.IsDefault() {
+		result.ReplacementMode = higher.ReplacementMode
+	} else {
+		result.ReplacementMode = lower.ReplacementMode
+	}
+
+	// Merge mirror enforcement mode.
+	if !higher.MirrorEnforcementMode.IsDefault() {
+		result.MirrorEnforcementMode = higher.MirrorEnforcementMode
+	} else {
+		result.MirrorEnforcementMode = lower.MirrorEnforcementMode
+	}
+
 	// Merge symbolic link mode.
 	if !higher.SymbolicLinkMode.IsDefault() {
 		result.SymbolicLinkMode = higher.SymbolicLinkMode
@@ -233,6 +391,13 @@ func MergeConfigurations(lower, higher *Configuration) *Configuration {
 		result.WatchPollingInterval = lower.WatchPollingInterval
 	}
 
+	// Merge minimum cycle interval.
+	if higher.MinimumCycleInterval != 0 {
+		result.MinimumCycleInterval = higher.MinimumCycleInterval
+	} else {
+		result.MinimumCycleInterval = lower.MinimumCycleInterval
+	}
+
 	// Merge default ignores. In theory, at most one of these should be
 	// non-empty, but we'll still implement it as if they both might have
 	// content.
@@ -250,6 +415,9 @@ func MergeConfigurations(lower, higher *Configuration) *Configuration {
 		result.IgnoreVCSMode = lower.IgnoreVCSMode
 	}
 
+	// Merge VCS ignore file importing.
+	result.IgnoreVCSIgnores = higher.IgnoreVCSIgnores || lower.IgnoreVCSIgnores
+
 	// Merge default file mode.
 	if higher.DefaultFileMode != 0 {
 		result.DefaultFileMode = higher.DefaultFileMode
@@ -278,6 +446,82 @@ func MergeConfigurations(lower, higher *Configuration) *Configuration {
 		result.DefaultGroup = lower.DefaultGroup
 	}
 
+	// Merge after-sync hooks.
+	result.AfterSync = mergeHooks(lower.AfterSync, higher.AfterSync)
+
+	// Merge before-apply hooks.
+	result.BeforeApply = mergeHooks(lower.BeforeApply, higher.BeforeApply)
+
+	// Merge deletion threshold.
+	if higher.DeletionThreshold != 0 {
+		result.DeletionThreshold = higher.DeletionThreshold
+	} else {
+		result.DeletionThreshold = lower.DeletionThreshold
+	}
+
+	// Merge trash directory.
+	if higher.TrashDirectory != "" {
+		result.TrashDirectory = higher.TrashDirectory
+	} else {
+		result.TrashDirectory = lower.TrashDirectory
+	}
+
+	// Merge trash retention period.
+	if higher.TrashRetentionPeriod != 0 {
+		result.TrashRetentionPeriod = higher.TrashRetentionPeriod
+	} else {
+		result.TrashRetentionPeriod = lower.TrashRetentionPeriod
+	}
+
+	// Merge maximum trash size.
+	if higher.MaximumTrashSize != 0 {
+		result.MaximumTrashSize = higher.MaximumTrashSize
+	} else {
+		result.MaximumTrashSize = lower.MaximumTrashSize
+	}
+
+	// Merge version count.
+	if higher.VersionCount != 0 {
+		result.VersionCount = higher.VersionCount
+	} else {
+		result.VersionCount = lower.VersionCount
+	}
+
+	// Merge minimum free space.
+	if higher.MinimumFreeSpace != 0 {
+		result.MinimumFreeSpace = higher.MinimumFreeSpace
+	} else {
+		result.MinimumFreeSpace = lower.MinimumFreeSpace
+	}
+
+	// Merge scan concurrency.
+	if higher.ScanConcurrency != 0 {
+		result.ScanConcurrency = higher.ScanConcurrency
+	} else {
+		result.ScanConcurrency = lower.ScanConcurrency
+	}
+
+	// Merge memory budget.
+	if higher.MemoryBudget != 0 {
+		result.MemoryBudget = higher.MemoryBudget
+	} else {
+		result.MemoryBudget = lower.MemoryBudget
+	}
+
+	// Merge agent niceness.
+	if higher.AgentNiceness != 0 {
+		result.AgentNiceness = higher.AgentNiceness
+	} else {
+		result.AgentNiceness = lower.AgentNiceness
+	}
+
+	// Merge modification time tolerance.
+	if higher.ModificationTimeTolerance != 0 {
+		result.ModificationTimeTolerance = higher.ModificationTimeTolerance
+	} else {
+		result.ModificationTimeTolerance = lower.ModificationTimeTolerance
+	}
+
 	// Done.
 	return result
 }
@@ -46,6 +46,18 @@ type Configuration struct {
 	ScanMode ScanMode `protobuf:"varint,15,opt,name=scanMode,proto3,enum=synchronization.ScanMode" json:"scanMode,omitempty"`
 	// StageMode specifies the file staging mode.
 	StageMode StageMode `protobuf:"varint,16,opt,name=stageMode,proto3,enum=synchronization.StageMode" json:"stageMode,omitempty"`
+	// UnicodeNormalizationMode specifies the Unicode normalization mode to use
+	// for content names.
+	UnicodeNormalizationMode core.UnicodeNormalizationMode `protobuf:"varint,17,opt,name=unicodeNormalizationMode,proto3,enum=core.UnicodeNormalizationMode" json:"unicodeNormalizationMode,omitempty"`
+	// ReplacementMode specifies the mode used to replace existing files.
+	ReplacementMode core.ReplacementMode `protobuf:"varint,18,opt,name=replacementMode,proto3,enum=core.ReplacementMode" json:"replacementMode,omitempty"`
+	// MirrorEnforcementMode specifies the mode used to enforce replica contents
+	// in the one-way-replica synchronization mode.
+	MirrorEnforcementMode MirrorEnforcementMode `protobuf:"varint,19,opt,name=mirrorEnforcementMode,proto3,enum=synchronization.MirrorEnforcementMode" json:"mirrorEnforcementMode,omitempty"`
+	// MaximumStagingTotalSize is the maximum total size of files that endpoints
+	// will stage in a single synchronization cycle. A zero value indicates no
+	// limit.
+	MaximumStagingTotalSize uint64 `protobuf:"varint,20,opt,name=maximumStagingTotalSize,proto3" json:"maximumStagingTotalSize,omitempty"`
 	// SymbolicLinkMode specifies the symbolic link mode.
 	SymbolicLinkMode core.SymbolicLinkMode `protobuf:"varint,1,opt,name=symbolicLinkMode,proto3,enum=core.SymbolicLinkMode" json:"symbolicLinkMode,omitempty"`
 	// WatchMode specifies the filesystem watching mode.
@@ -54,6 +66,12 @@ type Configuration struct {
 	// file monitoring. A value of 0 specifies that the default interval should
 	// be used.
 	WatchPollingInterval uint32 `protobuf:"varint,22,opt,name=watchPollingInterval,proto3" json:"watchPollingInterval,omitempty"`
+	// MinimumCycleInterval specifies the minimum interval (in seconds) between
+	// the starts of consecutive synchronization cycles triggered by filesystem
+	// events. Events arriving within this interval are batched into the next
+	// cycle. Explicitly requested cycles (e.g. flushes) are not delayed. A
+	// value of 0 specifies that no minimum interval should be enforced.
+	MinimumCycleInterval uint32 `protobuf:"varint,23,opt,name=minimumCycleInterval,proto3" json:"minimumCycleInterval,omitempty"`
 	// DefaultIgnores specifies the ignore patterns brought in from the global
 	// configuration.
 	// DEPRECATED: This field is no longer used when loading from global
@@ -66,6 +84,10 @@ type Configuration struct {
 	// IgnoreVCSMode specifies the VCS ignore mode that should be used in
 	// synchronization.
 	IgnoreVCSMode core.IgnoreVCSMode `protobuf:"varint,33,opt,name=ignoreVCSMode,proto3,enum=core.IgnoreVCSMode" json:"ignoreVCSMode,omitempty"`
+	// IgnoreVCSIgnores specifies whether or not Git ignore files found within
+	// the alpha synchronization root should be read and their patterns applied
+	// as session ignores. It can't be specified on an endpoint-specific basis.
+	IgnoreVCSIgnores bool `protobuf:"varint,34,opt,name=ignoreVCSIgnores,proto3" json:"ignoreVCSIgnores,omitempty"`
 	// DefaultFileMode specifies the default permission mode to use for new
 	// files in "portable" permission propagation mode.
 	DefaultFileMode uint32 `protobuf:"varint,63,opt,name=defaultFileMode,proto3" json:"defaultFileMode,omitempty"`
@@ -80,6 +102,59 @@ type Configuration struct {
 	// ownership of new files and directories in "portable" permission
 	// propagation mode.
 	DefaultGroup string `protobuf:"bytes,66,opt,name=defaultGroup,proto3" json:"defaultGroup,omitempty"`
+	// AfterSync specifies commands to run (using the system shell) on an
+	// endpoint after a synchronization cycle propagates changes to it.
+	AfterSync []string `protobuf:"bytes,81,rep,name=afterSync,proto3" json:"afterSync,omitempty"`
+	// BeforeApply specifies commands to run (using the system shell) on an
+	// endpoint before changes are applied to it. If any of these commands fail
+	// (i.e. exit with a non-zero exit code), then changes are not applied.
+	BeforeApply []string `protobuf:"bytes,82,rep,name=beforeApply,proto3" json:"beforeApply,omitempty"`
+	// DeletionThreshold specifies the maximum percentage of previously
+	// synchronized entries that a single synchronization cycle may delete from
+	// either endpoint before the session is halted. A value of 100 disables
+	// the check.
+	DeletionThreshold uint32 `protobuf:"varint,91,opt,name=deletionThreshold,proto3" json:"deletionThreshold,omitempty"`
+	// TrashDirectory specifies a directory on the endpoint in which files that
+	// are removed or overwritten by synchronization should be retained. If
+	// empty, then files are not retained.
+	TrashDirectory string `protobuf:"bytes,92,opt,name=trashDirectory,proto3" json:"trashDirectory,omitempty"`
+	// TrashRetentionPeriod specifies the period (in seconds) for which files
+	// should be retained in the trash directory.
+	TrashRetentionPeriod uint32 `protobuf:"varint,93,opt,name=trashRetentionPeriod,proto3" json:"trashRetentionPeriod,omitempty"`
+	// MaximumTrashSize specifies the maximum total size of files that should
+	// be retained in the trash directory. If this size is exceeded, then the
+	// oldest retained files are removed first.
+	MaximumTrashSize uint64 `protobuf:"varint,94,opt,name=maximumTrashSize,proto3" json:"maximumTrashSize,omitempty"`
+	// VersionCount specifies the number of previous versions of each removed
+	// or overwritten file to retain in the endpoint's versions directory. If
+	// zero, then versions are not retained.
+	VersionCount uint32 `protobuf:"varint,95,opt,name=versionCount,proto3" json:"versionCount,omitempty"`
+	// MinimumFreeSpace specifies the amount of disk space that must remain
+	// available on an endpoint's filesystem after the files staged for a
+	// transition are moved into place. If a transition would violate this
+	// requirement, then it isn't applied and a problem is reported instead. A
+	// value of 0 specifies that the default should be used.
+	MinimumFreeSpace uint64 `protobuf:"varint,96,opt,name=minimumFreeSpace,proto3" json:"minimumFreeSpace,omitempty"`
+	// ScanConcurrency specifies the maximum number of Goroutines that an
+	// endpoint will use to walk and hash content during scans. A value of 0
+	// specifies that the default concurrency should be used.
+	ScanConcurrency uint32 `protobuf:"varint,101,opt,name=scanConcurrency,proto3" json:"scanConcurrency,omitempty"`
+	// MemoryBudget specifies the approximate maximum amount of memory that an
+	// endpoint's scan caches should occupy before they're spilled to disk. A
+	// value of 0 specifies that the default budget should be used.
+	MemoryBudget uint64 `protobuf:"varint,102,opt,name=memoryBudget,proto3" json:"memoryBudget,omitempty"`
+	// AgentNiceness specifies the scheduling niceness (1-19) that agent
+	// processes should adopt in order to avoid starving other processes of
+	// CPU and I/O resources. On Windows, this is mapped to a process priority
+	// class. It has no effect on local endpoints hosted by the daemon. A value
+	// of 0 specifies that the agent priority should not be adjusted.
+	AgentNiceness uint32 `protobuf:"varint,103,opt,name=agentNiceness,proto3" json:"agentNiceness,omitempty"`
+	// ModificationTimeTolerance specifies the tolerance (in milliseconds) used
+	// when comparing file modification times to determine whether or not
+	// cached file metadata is still valid. Endpoints will widen this tolerance
+	// to the detected modification time granularity of their filesystem. A
+	// value of 0 specifies that the default tolerance should be used.
+	ModificationTimeTolerance uint32 `protobuf:"varint,104,opt,name=modificationTimeTolerance,proto3" json:"modificationTimeTolerance,omitempty"`
 }
 
 func (x *Configuration) Reset() {
@@ -156,6 +231,34 @@ func (x *Configuration) GetStageMode() StageMode {
 	return StageMode_StageModeDefault
 }
 
+func (x *Configuration) GetUnicodeNormalizationMode() core.UnicodeNormalizationMode {
+	if x != nil {
+		return x.UnicodeNormalizationMode
+	}
+	return core.UnicodeNormalizationMode(0)
+}
+
+func (x *Configuration) GetReplacementMode() core.ReplacementMode {
+	if x != nil {
+		return x.ReplacementMode
+	}
+	return core.ReplacementMode(0)
+}
+
+func (x *Configuration) GetMirrorEnforcementMode() MirrorEnforcementMode {
+	if x != nil {
+		return x.MirrorEnforcementMode
+	}
+	return MirrorEnforcementMode_MirrorEnforcementModeDefault
+}
+
+func (x *Configuration) GetMaximumStagingTotalSize() uint64 {
+	if x != nil {
+		return x.MaximumStagingTotalSize
+	}
+	return 0
+}
+
 func (x *Configuration) GetSymbolicLinkMode() core.SymbolicLinkMode {
 	if x != nil {
 		return x.SymbolicLinkMode
@@ -177,6 +280,13 @@ func (x *Configuration) GetWatchPollingInterval() uint32 {
 	return 0
 }
 
+func (x *Configuration) GetMinimumCycleInterval() uint32 {
+	if x != nil {
+		return x.MinimumCycleInterval
+	}
+	return 0
+}
+
 func (x *Configuration) GetDefaultIgnores() []string {
 	if x != nil {
 		return x.DefaultIgnores
@@ -198,6 +308,13 @@ func (x *Configuration) GetIgnoreVCSMode() core.IgnoreVCSMode {
 	return core.IgnoreVCSMode(0)
 }
 
+func (x *Configuration) GetIgnoreVCSIgnores() bool {
+	if x != nil {
+		return x.IgnoreVCSIgnores
+	}
+	return false
+}
+
 func (x *Configuration) GetDefaultFileMode() uint32 {
 	if x != nil {
 		return x.DefaultFileMode
@@ -226,6 +343,90 @@ func (x *Configuration) GetDefaultGroup() string {
 	return ""
 }
 
+func (x *Configuration) GetAfterSync() []string {
+	if x != nil {
+		return x.AfterSync
+	}
+	return nil
+}
+
+func (x *Configuration) GetBeforeApply() []string {
+	if x != nil {
+		return x.BeforeApply
+	}
+	return nil
+}
+
+func (x *Configuration) GetDeletionThreshold() uint32 {
+	if x != nil {
+		return x.DeletionThreshold
+	}
+	return 0
+}
+
+func (x *Configuration) GetTrashDirectory() string {
+	if x != nil {
+		return x.TrashDirectory
+	}
+	return ""
+}
+
+func (x *Configuration) GetTrashRetentionPeriod() uint32 {
+	if x != nil {
+		return x.TrashRetentionPeriod
+	}
+	return 0
+}
+
+func (x *Configuration) GetMaximumTrashSize() uint64 {
+	if x != nil {
+		return x.MaximumTrashSize
+	}
+	return 0
+}
+
+func (x *Configuration) GetVersionCount() uint32 {
+	if x != nil {
+		return x.VersionCount
+	}
+	return 0
+}
+
+func (x *Configuration) GetMinimumFreeSpace() uint64 {
+	if x != nil {
+		return x.MinimumFreeSpace
+	}
+	return 0
+}
+
+func (x *Configuration) GetScanConcurrency() uint32 {
+	if x != nil {
+		return x.ScanConcurrency
+	}
+	return 0
+}
+
+func (x *Configuration) GetMemoryBudget() uint64 {
+	if x != nil {
+		return x.MemoryBudget
+	}
+	return 0
+}
+
+func (x *Configuration) GetAgentNiceness() uint32 {
+	if x != nil {
+		return x.AgentNiceness
+	}
+	return 0
+}
+
+func (x *Configuration) GetModificationTimeTolerance() uint32 {
+	if x != nil {
+		return x.ModificationTimeTolerance
+	}
+	return 0
+}
+
 var File_synchronization_configuration_proto protoreflect.FileDescriptor
 
 var file_synchronization_configuration_proto_rawDesc = []byte{
@@ -234,73 +435,139 @@ var file_synchronization_configuration_proto_rawDesc = []byte{
 	0x70, 0x72, 0x6f, 0x74, 0x6f, 0x12, 0x0f, 0x73, 0x79, 0x6e, 0x63, 0x68, 0x72, 0x6f, 0x6e, 0x69,
 	0x7a, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x1a, 0x24, 0x66, 0x69, 0x6c, 0x65, 0x73, 0x79, 0x73, 0x74,
 	0x65, 0x6d, 0x2f, 0x62, 0x65, 0x68, 0x61, 0x76, 0x69, 0x6f, 0x72, 0x2f, 0x70, 0x72, 0x6f, 0x62,
-	0x65, 0x5f, 0x6d, 0x6f, 0x64, 0x65, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x1a, 0x1f, 0x73, 0x79,
-	0x6e, 0x63, 0x68, 0x72, 0x6f, 0x6e, 0x69, 0x7a, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x2f, 0x73, 0x63,
-	0x61, 0x6e, 0x5f, 0x6d, 0x6f, 0x64, 0x65, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x1a, 0x20, 0x73,
-	0x79, 0x6e, 0x63, 0x68, 0x72, 0x6f, 0x6e, 0x69, 0x7a, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x2f, 0x73,
-	0x74, 0x61, 0x67, 0x65, 0x5f, 0x6d, 0x6f, 0x64, 0x65, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x1a,
-	0x20, 0x73, 0x79, 0x6e, 0x63, 0x68, 0x72, 0x6f, 0x6e, 0x69, 0x7a, 0x61, 0x74, 0x69, 0x6f, 0x6e,
-	0x2f, 0x77, 0x61, 0x74, 0x63, 0x68, 0x5f, 0x6d, 0x6f, 0x64, 0x65, 0x2e, 0x70, 0x72, 0x6f, 0x74,
-	0x6f, 0x1a, 0x2a, 0x73, 0x79, 0x6e, 0x63, 0x68, 0x72, 0x6f, 0x6e, 0x69, 0x7a, 0x61, 0x74, 0x69,
-	0x6f, 0x6e, 0x2f, 0x63, 0x6f, 0x72, 0x65, 0x2f, 0x69, 0x67, 0x6e, 0x6f, 0x72, 0x65, 0x5f, 0x76,
-	0x63, 0x73, 0x5f, 0x6d, 0x6f, 0x64, 0x65, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x1a, 0x1f, 0x73,
-	0x79, 0x6e, 0x63, 0x68, 0x72, 0x6f, 0x6e, 0x69, 0x7a, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x2f, 0x63,
-	0x6f, 0x72, 0x65, 0x2f, 0x6d, 0x6f, 0x64, 0x65, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x1a, 0x2d,
+	0x65, 0x5f, 0x6d, 0x6f, 0x64, 0x65, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x1a, 0x2d, 0x73, 0x79,
+	0x6e, 0x63, 0x68, 0x72, 0x6f, 0x6e, 0x69, 0x7a, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x2f, 0x6d, 0x69,
+	0x72, 0x72, 0x6f, 0x72, 0x5f, 0x65, 0x6e, 0x66, 0x6f, 0x72, 0x63, 0x65, 0x6d, 0x65, 0x6e, 0x74,
+	0x5f, 0x6d, 0x6f, 0x64, 0x65, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x1a, 0x1f, 0x73, 0x79, 0x6e,
+	0x63, 0x68, 0x72, 0x6f, 0x6e, 0x69, 0x7a, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x2f, 0x73, 0x63, 0x61,
+	0x6e, 0x5f, 0x6d, 0x6f, 0x64, 0x65, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x1a, 0x20, 0x73, 0x79,
+	0x6e, 0x63, 0x68, 0x72, 0x6f, 0x6e, 0x69, 0x7a, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x2f, 0x73, 0x74,
+	0x61, 0x67, 0x65, 0x5f, 0x6d, 0x6f, 0x64, 0x65, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x1a, 0x20,
 	0x73, 0x79, 0x6e, 0x63, 0x68, 0x72, 0x6f, 0x6e, 0x69, 0x7a, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x2f,
-	0x63, 0x6f, 0x72, 0x65, 0x2f, 0x73, 0x79, 0x6d, 0x62, 0x6f, 0x6c, 0x69, 0x63, 0x5f, 0x6c, 0x69,
-	0x6e, 0x6b, 0x5f, 0x6d, 0x6f, 0x64, 0x65, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x22, 0xbb, 0x06,
-	0x0a, 0x0d, 0x43, 0x6f, 0x6e, 0x66, 0x69, 0x67, 0x75, 0x72, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x12,
-	0x4b, 0x0a, 0x13, 0x73, 0x79, 0x6e, 0x63, 0x68, 0x72, 0x6f, 0x6e, 0x69, 0x7a, 0x61, 0x74, 0x69,
-	0x6f, 0x6e, 0x4d, 0x6f, 0x64, 0x65, 0x18, 0x0b, 0x20, 0x01, 0x28, 0x0e, 0x32, 0x19, 0x2e, 0x63,
-	0x6f, 0x72, 0x65, 0x2e, 0x53, 0x79, 0x6e, 0x63, 0x68, 0x72, 0x6f, 0x6e, 0x69, 0x7a, 0x61, 0x74,
-	0x69, 0x6f, 0x6e, 0x4d, 0x6f, 0x64, 0x65, 0x52, 0x13, 0x73, 0x79, 0x6e, 0x63, 0x68, 0x72, 0x6f,
-	0x6e, 0x69, 0x7a, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x4d, 0x6f, 0x64, 0x65, 0x12, 0x2c, 0x0a, 0x11,
-	0x6d, 0x61, 0x78, 0x69, 0x6d, 0x75, 0x6d, 0x45, 0x6e, 0x74, 0x72, 0x79, 0x43, 0x6f, 0x75, 0x6e,
-	0x74, 0x18, 0x0c, 0x20, 0x01, 0x28, 0x04, 0x52, 0x11, 0x6d, 0x61, 0x78, 0x69, 0x6d, 0x75, 0x6d,
-	0x45, 0x6e, 0x74, 0x72, 0x79, 0x43, 0x6f, 0x75, 0x6e, 0x74, 0x12, 0x36, 0x0a, 0x16, 0x6d, 0x61,
-	0x78, 0x69, 0x6d, 0x75, 0x6d, 0x53, 0x74, 0x61, 0x67, 0x69, 0x6e, 0x67, 0x46, 0x69, 0x6c, 0x65,
-	0x53, 0x69, 0x7a, 0x65, 0x18, 0x0d, 0x20, 0x01, 0x28, 0x04, 0x52, 0x16, 0x6d, 0x61, 0x78, 0x69,
-	0x6d, 0x75, 0x6d, 0x53, 0x74, 0x61, 0x67, 0x69, 0x6e, 0x67, 0x46, 0x69, 0x6c, 0x65, 0x53, 0x69,
-	0x7a, 0x65, 0x12, 0x31, 0x0a, 0x09, 0x70, 0x72, 0x6f, 0x62, 0x65, 0x4d, 0x6f, 0x64, 0x65, 0x18,
-	0x0e, 0x20, 0x01, 0x28, 0x0e, 0x32, 0x13, 0x2e, 0x62, 0x65, 0x68, 0x61, 0x76, 0x69, 0x6f, 0x72,
-	0x2e, 0x50, 0x72, 0x6f, 0x62, 0x65, 0x4d, 0x6f, 0x64, 0x65, 0x52, 0x09, 0x70, 0x72, 0x6f, 0x62,
-	0x65, 0x4d, 0x6f, 0x64, 0x65, 0x12, 0x35, 0x0a, 0x08, 0x73, 0x63, 0x61, 0x6e, 0x4d, 0x6f, 0x64,
-	0x65, 0x18, 0x0f, 0x20, 0x01, 0x28, 0x0e, 0x32, 0x19, 0x2e, 0x73, 0x79, 0x6e, 0x63, 0x68, 0x72,
-	0x6f, 0x6e, 0x69, 0x7a, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x2e, 0x53, 0x63, 0x61, 0x6e, 0x4d, 0x6f,
-	0x64, 0x65, 0x52, 0x08, 0x73, 0x63, 0x61, 0x6e, 0x4d, 0x6f, 0x64, 0x65, 0x12, 0x38, 0x0a, 0x09,
-	0x73, 0x74, 0x61, 0x67, 0x65, 0x4d, 0x6f, 0x64, 0x65, 0x18, 0x10, 0x20, 0x01, 0x28, 0x0e, 0x32,
-	0x1a, 0x2e, 0x73, 0x79, 0x6e, 0x63, 0x68, 0x72, 0x6f, 0x6e, 0x69, 0x7a, 0x61, 0x74, 0x69, 0x6f,
-	0x6e, 0x2e, 0x53, 0x74, 0x61, 0x67, 0x65, 0x4d, 0x6f, 0x64, 0x65, 0x52, 0x09, 0x73, 0x74, 0x61,
-	0x67, 0x65, 0x4d, 0x6f, 0x64, 0x65, 0x12, 0x42, 0x0a, 0x10, 0x73, 0x79, 0x6d, 0x62, 0x6f, 0x6c,
-	0x69, 0x63, 0x4c, 0x69, 0x6e, 0x6b, 0x4d, 0x6f, 0x64, 0x65, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0e,
-	0x32, 0x16, 0x2e, 0x63, 0x6f, 0x72, 0x65, 0x2e, 0x53, 0x79, 0x6d, 0x62, 0x6f, 0x6c, 0x69, 0x63,
-	0x4c, 0x69, 0x6e, 0x6b, 0x4d, 0x6f, 0x64, 0x65, 0x52, 0x10, 0x73, 0x79, 0x6d, 0x62, 0x6f, 0x6c,
-	0x69, 0x63, 0x4c, 0x69, 0x6e, 0x6b, 0x4d, 0x6f, 0x64, 0x65, 0x12, 0x38, 0x0a, 0x09, 0x77, 0x61,
-	0x74, 0x63, 0x68, 0x4d, 0x6f, 0x64, 0x65, 0x18, 0x15, 0x20, 0x01, 0x28, 0x0e, 0x32, 0x1a, 0x2e,
-	0x73, 0x79, 0x6e, 0x63, 0x68, 0x72, 0x6f, 0x6e, 0x69, 0x7a, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x2e,
-	0x57, 0x61, 0x74, 0x63, 0x68, 0x4d, 0x6f, 0x64, 0x65, 0x52, 0x09, 0x77, 0x61, 0x74, 0x63, 0x68,
-	0x4d, 0x6f, 0x64, 0x65, 0x12, 0x32, 0x0a, 0x14, 0x77, 0x61, 0x74, 0x63, 0x68, 0x50, 0x6f, 0x6c,
-	0x6c, 0x69, 0x6e, 0x67, 0x49, 0x6e, 0x74, 0x65, 0x72, 0x76, 0x61, 0x6c, 0x18, 0x16, 0x20, 0x01,
-	0x28, 0x0d, 0x52, 0x14, 0x77, 0x61, 0x74, 0x63, 0x68, 0x50, 0x6f, 0x6c, 0x6c, 0x69, 0x6e, 0x67,
-	0x49, 0x6e, 0x74, 0x65, 0x72, 0x76, 0x61, 0x6c, 0x12, 0x26, 0x0a, 0x0e, 0x64, 0x65, 0x66, 0x61,
-	0x75, 0x6c, 0x74, 0x49, 0x67, 0x6e, 0x6f, 0x72, 0x65, 0x73, 0x18, 0x1f, 0x20, 0x03, 0x28, 0x09,
-	0x52, 0x0e, 0x64, 0x65, 0x66, 0x61, 0x75, 0x6c, 0x74, 0x49, 0x67, 0x6e, 0x6f, 0x72, 0x65, 0x73,
-	0x12, 0x18, 0x0a, 0x07, 0x69, 0x67, 0x6e, 0x6f, 0x72, 0x65, 0x73, 0x18, 0x20, 0x20, 0x03, 0x28,
-	0x09, 0x52, 0x07, 0x69, 0x67, 0x6e, 0x6f, 0x72, 0x65, 0x73, 0x12, 0x39, 0x0a, 0x0d, 0x69, 0x67,
-	0x6e, 0x6f, 0x72, 0x65, 0x56, 0x43, 0x53, 0x4d, 0x6f, 0x64, 0x65, 0x18, 0x21, 0x20, 0x01, 0x28,
-	0x0e, 0x32, 0x13, 0x2e, 0x63, 0x6f, 0x72, 0x65, 0x2e, 0x49, 0x67, 0x6e, 0x6f, 0x72, 0x65, 0x56,
-	0x43, 0x53, 0x4d, 0x6f, 0x64, 0x65, 0x52, 0x0d, 0x69, 0x67, 0x6e, 0x6f, 0x72, 0x65, 0x56, 0x43,
-	0x53, 0x4d, 0x6f, 0x64, 0x65, 0x12, 0x28, 0x0a, 0x0f, 0x64, 0x65, 0x66, 0x61, 0x75, 0x6c, 0x74,
-	0x46, 0x69, 0x6c, 0x65, 0x4d, 0x6f, 0x64, 0x65, 0x18, 0x3f, 0x20, 0x01, 0x28, 0x0d, 0x52, 0x0f,
-	0x64, 0x65, 0x66, 0x61, 0x75, 0x6c, 0x74, 0x46, 0x69, 0x6c, 0x65, 0x4d, 0x6f, 0x64, 0x65, 0x12,
-	0x32, 0x0a, 0x14, 0x64, 0x65, 0x66, 0x61, 0x75, 0x6c, 0x74, 0x44, 0x69, 0x72, 0x65, 0x63, 0x74,
-	0x6f, 0x72, 0x79, 0x4d, 0x6f, 0x64, 0x65, 0x18, 0x40, 0x20, 0x01, 0x28, 0x0d, 0x52, 0x14, 0x64,
-	0x65, 0x66, 0x61, 0x75, 0x6c, 0x74, 0x44, 0x69, 0x72, 0x65, 0x63, 0x74, 0x6f, 0x72, 0x79, 0x4d,
-	0x6f, 0x64, 0x65, 0x12, 0x22, 0x0a, 0x0c, 0x64, 0x65, 0x66, 0x61, 0x75, 0x6c, 0x74, 0x4f, 0x77,
-	0x6e, 0x65, 0x72, 0x18, 0x41, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0c, 0x64, 0x65, 0x66, 0x61, 0x75,
-	0x6c, 0x74, 0x4f, 0x77, 0x6e, 0x65, 0x72, 0x12, 0x22, 0x0a, 0x0c, 0x64, 0x65, 0x66, 0x61, 0x75,
-	0x6c, 0x74, 0x47, 0x72, 0x6f, 0x75, 0x70, 0x18, 0x42, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0c, 0x64,
-	0x65, 0x66, 0x61, 0x75, 0x6c, 0x74, 0x47, 0x72, 0x6f, 0x75, 0x70, 0x42, 0x33, 0x5a, 0x31, 0x67,
+	0x77, 0x61, 0x74, 0x63, 0x68, 0x5f, 0x6d, 0x6f, 0x64, 0x65, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f,
+	0x1a, 0x2a, 0x73, 0x79, 0x6e, 0x63, 0x68, 0x72, 0x6f, 0x6e, 0x69, 0x7a, 0x61, 0x74, 0x69, 0x6f,
+	0x6e, 0x2f, 0x63, 0x6f, 0x72, 0x65, 0x2f, 0x69, 0x67, 0x6e, 0x6f, 0x72, 0x65, 0x5f, 0x76, 0x63,
+	0x73, 0x5f, 0x6d, 0x6f, 0x64, 0x65, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x1a, 0x1f, 0x73, 0x79,
+	0x6e, 0x63, 0x68, 0x72, 0x6f, 0x6e, 0x69, 0x7a, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x2f, 0x63, 0x6f,
+	0x72, 0x65, 0x2f, 0x6d, 0x6f, 0x64, 0x65, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x1a, 0x2b, 0x73,
+	0x79, 0x6e, 0x63, 0x68, 0x72, 0x6f, 0x6e, 0x69, 0x7a, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x2f, 0x63,
+	0x6f, 0x72, 0x65, 0x2f, 0x72, 0x65, 0x70, 0x6c, 0x61, 0x63, 0x65, 0x6d, 0x65, 0x6e, 0x74, 0x5f,
+	0x6d, 0x6f, 0x64, 0x65, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x1a, 0x2d, 0x73, 0x79, 0x6e, 0x63,
+	0x68, 0x72, 0x6f, 0x6e, 0x69, 0x7a, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x2f, 0x63, 0x6f, 0x72, 0x65,
+	0x2f, 0x73, 0x79, 0x6d, 0x62, 0x6f, 0x6c, 0x69, 0x63, 0x5f, 0x6c, 0x69, 0x6e, 0x6b, 0x5f, 0x6d,
+	0x6f, 0x64, 0x65, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x1a, 0x35, 0x73, 0x79, 0x6e, 0x63, 0x68,
+	0x72, 0x6f, 0x6e, 0x69, 0x7a, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x2f, 0x63, 0x6f, 0x72, 0x65, 0x2f,
+	0x75, 0x6e, 0x69, 0x63, 0x6f, 0x64, 0x65, 0x5f, 0x6e, 0x6f, 0x72, 0x6d, 0x61, 0x6c, 0x69, 0x7a,
+	0x61, 0x74, 0x69, 0x6f, 0x6e, 0x5f, 0x6d, 0x6f, 0x64, 0x65, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f,
+	0x22, 0xc8, 0x0d, 0x0a, 0x0d, 0x43, 0x6f, 0x6e, 0x66, 0x69, 0x67, 0x75, 0x72, 0x61, 0x74, 0x69,
+	0x6f, 0x6e, 0x12, 0x4b, 0x0a, 0x13, 0x73, 0x79, 0x6e, 0x63, 0x68, 0x72, 0x6f, 0x6e, 0x69, 0x7a,
+	0x61, 0x74, 0x69, 0x6f, 0x6e, 0x4d, 0x6f, 0x64, 0x65, 0x18, 0x0b, 0x20, 0x01, 0x28, 0x0e, 0x32,
+	0x19, 0x2e, 0x63, 0x6f, 0x72, 0x65, 0x2e, 0x53, 0x79, 0x6e, 0x63, 0x68, 0x72, 0x6f, 0x6e, 0x69,
+	0x7a, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x4d, 0x6f, 0x64, 0x65, 0x52, 0x13, 0x73, 0x79, 0x6e, 0x63,
+	0x68, 0x72, 0x6f, 0x6e, 0x69, 0x7a, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x4d, 0x6f, 0x64, 0x65, 0x12,
+	0x2c, 0x0a, 0x11, 0x6d, 0x61, 0x78, 0x69, 0x6d, 0x75, 0x6d, 0x45, 0x6e, 0x74, 0x72, 0x79, 0x43,
+	0x6f, 0x75, 0x6e, 0x74, 0x18, 0x0c, 0x20, 0x01, 0x28, 0x04, 0x52, 0x11, 0x6d, 0x61, 0x78, 0x69,
+	0x6d, 0x75, 0x6d, 0x45, 0x6e, 0x74, 0x72, 0x79, 0x43, 0x6f, 0x75, 0x6e, 0x74, 0x12, 0x36, 0x0a,
+	0x16, 0x6d, 0x61, 0x78, 0x69, 0x6d, 0x75, 0x6d, 0x53, 0x74, 0x61, 0x67, 0x69, 0x6e, 0x67, 0x46,
+	0x69, 0x6c, 0x65, 0x53, 0x69, 0x7a, 0x65, 0x18, 0x0d, 0x20, 0x01, 0x28, 0x04, 0x52, 0x16, 0x6d,
+	0x61, 0x78, 0x69, 0x6d, 0x75, 0x6d, 0x53, 0x74, 0x61, 0x67, 0x69, 0x6e, 0x67, 0x46, 0x69, 0x6c,
+	0x65, 0x53, 0x69, 0x7a, 0x65, 0x12, 0x31, 0x0a, 0x09, 0x70, 0x72, 0x6f, 0x62, 0x65, 0x4d, 0x6f,
+	0x64, 0x65, 0x18, 0x0e, 0x20, 0x01, 0x28, 0x0e, 0x32, 0x13, 0x2e, 0x62, 0x65, 0x68, 0x61, 0x76,
+	0x69, 0x6f, 0x72, 0x2e, 0x50, 0x72, 0x6f, 0x62, 0x65, 0x4d, 0x6f, 0x64, 0x65, 0x52, 0x09, 0x70,
+	0x72, 0x6f, 0x62, 0x65, 0x4d, 0x6f, 0x64, 0x65, 0x12, 0x35, 0x0a, 0x08, 0x73, 0x63, 0x61, 0x6e,
+	0x4d, 0x6f, 0x64, 0x65, 0x18, 0x0f, 0x20, 0x01, 0x28, 0x0e, 0x32, 0x19, 0x2e, 0x73, 0x79, 0x6e,
+	0x63, 0x68, 0x72, 0x6f, 0x6e, 0x69, 0x7a, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x2e, 0x53, 0x63, 0x61,
+	0x6e, 0x4d, 0x6f, 0x64, 0x65, 0x52, 0x08, 0x73, 0x63, 0x61, 0x6e, 0x4d, 0x6f, 0x64, 0x65, 0x12,
+	0x38, 0x0a, 0x09, 0x73, 0x74, 0x61, 0x67, 0x65, 0x4d, 0x6f, 0x64, 0x65, 0x18, 0x10, 0x20, 0x01,
+	0x28, 0x0e, 0x32, 0x1a, 0x2e, 0x73, 0x79, 0x6e, 0x63, 0x68, 0x72, 0x6f, 0x6e, 0x69, 0x7a, 0x61,
+	0x74, 0x69, 0x6f, 0x6e, 0x2e, 0x53, 0x74, 0x61, 0x67, 0x65, 0x4d, 0x6f, 0x64, 0x65, 0x52, 0x09,
+	0x73, 0x74, 0x61, 0x67, 0x65, 0x4d, 0x6f, 0x64, 0x65, 0x12, 0x5a, 0x0a, 0x18, 0x75, 0x6e, 0x69,
+	0x63, 0x6f, 0x64, 0x65, 0x4e, 0x6f, 0x72, 0x6d, 0x61, 0x6c, 0x69, 0x7a, 0x61, 0x74, 0x69, 0x6f,
+	0x6e, 0x4d, 0x6f, 0x64, 0x65, 0x18, 0x11, 0x20, 0x01, 0x28, 0x0e, 0x32, 0x1e, 0x2e, 0x63, 0x6f,
+	0x72, 0x65, 0x2e, 0x55, 0x6e, 0x69, 0x63, 0x6f, 0x64, 0x65, 0x4e, 0x6f, 0x72, 0x6d, 0x61, 0x6c,
+	0x69, 0x7a, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x4d, 0x6f, 0x64, 0x65, 0x52, 0x18, 0x75, 0x6e, 0x69,
+	0x63, 0x6f, 0x64, 0x65, 0x4e, 0x6f, 0x72, 0x6d, 0x61, 0x6c, 0x69, 0x7a, 0x61, 0x74, 0x69, 0x6f,
+	0x6e, 0x4d, 0x6f, 0x64, 0x65, 0x12, 0x3f, 0x0a, 0x0f, 0x72, 0x65, 0x70, 0x6c, 0x61, 0x63, 0x65,
+	0x6d, 0x65, 0x6e, 0x74, 0x4d, 0x6f, 0x64, 0x65, 0x18, 0x12, 0x20, 0x01, 0x28, 0x0e, 0x32, 0x15,
+	0x2e, 0x63, 0x6f, 0x72, 0x65, 0x2e, 0x52, 0x65, 0x70, 0x6c, 0x61, 0x63, 0x65, 0x6d, 0x65, 0x6e,
+	0x74, 0x4d, 0x6f, 0x64, 0x65, 0x52, 0x0f, 0x72, 0x65, 0x70, 0x6c, 0x61, 0x63, 0x65, 0x6d, 0x65,
+	0x6e, 0x74, 0x4d, 0x6f, 0x64, 0x65, 0x12, 0x5c, 0x0a, 0x15, 0x6d, 0x69, 0x72, 0x72, 0x6f, 0x72,
+	0x45, 0x6e, 0x66, 0x6f, 0x72, 0x63, 0x65, 0x6d, 0x65, 0x6e, 0x74, 0x4d, 0x6f, 0x64, 0x65, 0x18,
+	0x13, 0x20, 0x01, 0x28, 0x0e, 0x32, 0x26, 0x2e, 0x73, 0x79, 0x6e, 0x63, 0x68, 0x72, 0x6f, 0x6e,
+	0x69, 0x7a, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x2e, 0x4d, 0x69, 0x72, 0x72, 0x6f, 0x72, 0x45, 0x6e,
+	0x66, 0x6f, 0x72, 0x63, 0x65, 0x6d, 0x65, 0x6e, 0x74, 0x4d, 0x6f, 0x64, 0x65, 0x52, 0x15, 0x6d,
+	0x69, 0x72, 0x72, 0x6f, 0x72, 0x45, 0x6e, 0x66, 0x6f, 0x72, 0x63, 0x65, 0x6d, 0x65, 0x6e, 0x74,
+	0x4d, 0x6f, 0x64, 0x65, 0x12, 0x38, 0x0a, 0x17, 0x6d, 0x61, 0x78, 0x69, 0x6d, 0x75, 0x6d, 0x53,
+	0x74, 0x61, 0x67, 0x69, 0x6e, 0x67, 0x54, 0x6f, 0x74, 0x61, 0x6c, 0x53, 0x69, 0x7a, 0x65, 0x18,
+	0x14, 0x20, 0x01, 0x28, 0x04, 0x52, 0x17, 0x6d, 0x61, 0x78, 0x69, 0x6d, 0x75, 0x6d, 0x53, 0x74,
+	0x61, 0x67, 0x69, 0x6e, 0x67, 0x54, 0x6f, 0x74, 0x61, 0x6c, 0x53, 0x69, 0x7a, 0x65, 0x12, 0x42,
+	0x0a, 0x10, 0x73, 0x79, 0x6d, 0x62, 0x6f, 0x6c, 0x69, 0x63, 0x4c, 0x69, 0x6e, 0x6b, 0x4d, 0x6f,
+	0x64, 0x65, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0e, 0x32, 0x16, 0x2e, 0x63, 0x6f, 0x72, 0x65, 0x2e,
+	0x53, 0x79, 0x6d, 0x62, 0x6f, 0x6c, 0x69, 0x63, 0x4c, 0x69, 0x6e, 0x6b, 0x4d, 0x6f, 0x64, 0x65,
+	0x52, 0x10, 0x73, 0x79, 0x6d, 0x62, 0x6f, 0x6c, 0x69, 0x63, 0x4c, 0x69, 0x6e, 0x6b, 0x4d, 0x6f,
+	0x64, 0x65, 0x12, 0x38, 0x0a, 0x09, 0x77, 0x61, 0x74, 0x63, 0x68, 0x4d, 0x6f, 0x64, 0x65, 0x18,
+	0x15, 0x20, 0x01, 0x28, 0x0e, 0x32, 0x1a, 0x2e, 0x73, 0x79, 0x6e, 0x63, 0x68, 0x72, 0x6f, 0x6e,
+	0x69, 0x7a, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x2e, 0x57, 0x61, 0x74, 0x63, 0x68, 0x4d, 0x6f, 0x64,
+	0x65, 0x52, 0x09, 0x77, 0x61, 0x74, 0x63, 0x68, 0x4d, 0x6f, 0x64, 0x65, 0x12, 0x32, 0x0a, 0x14,
+	0x77, 0x61, 0x74, 0x63, 0x68, 0x50, 0x6f, 0x6c, 0x6c, 0x69, 0x6e, 0x67, 0x49, 0x6e, 0x74, 0x65,
+	0x72, 0x76, 0x61, 0x6c, 0x18, 0x16, 0x20, 0x01, 0x28, 0x0d, 0x52, 0x14, 0x77, 0x61, 0x74, 0x63,
+	0x68, 0x50, 0x6f, 0x6c, 0x6c, 0x69, 0x6e, 0x67, 0x49, 0x6e, 0x74, 0x65, 0x72, 0x76, 0x61, 0x6c,
+	0x12, 0x32, 0x0a, 0x14, 0x6d, 0x69, 0x6e, 0x69, 0x6d, 0x75, 0x6d, 0x43, 0x79, 0x63, 0x6c, 0x65,
+	0x49, 0x6e, 0x74, 0x65, 0x72, 0x76, 0x61, 0x6c, 0x18, 0x17, 0x20, 0x01, 0x28, 0x0d, 0x52, 0x14,
+	0x6d, 0x69, 0x6e, 0x69, 0x6d, 0x75, 0x6d, 0x43, 0x79, 0x63, 0x6c, 0x65, 0x49, 0x6e, 0x74, 0x65,
+	0x72, 0x76, 0x61, 0x6c, 0x12, 0x26, 0x0a, 0x0e, 0x64, 0x65, 0x66, 0x61, 0x75, 0x6c, 0x74, 0x49,
+	0x67, 0x6e, 0x6f, 0x72, 0x65, 0x73, 0x18, 0x1f, 0x20, 0x03, 0x28, 0x09, 0x52, 0x0e, 0x64, 0x65,
+	0x66, 0x61, 0x75, 0x6c, 0x74, 0x49, 0x67, 0x6e, 0x6f, 0x72, 0x65, 0x73, 0x12, 0x18, 0x0a, 0x07,
+	0x69, 0x67, 0x6e, 0x6f, 0x72, 0x65, 0x73, 0x18, 0x20, 0x20, 0x03, 0x28, 0x09, 0x52, 0x07, 0x69,
+	0x67, 0x6e, 0x6f, 0x72, 0x65, 0x73, 0x12, 0x39, 0x0a, 0x0d, 0x69, 0x67, 0x6e, 0x6f, 0x72, 0x65,
+	0x56, 0x43, 0x53, 0x4d, 0x6f, 0x64, 0x65, 0x18, 0x21, 0x20, 0x01, 0x28, 0x0e, 0x32, 0x13, 0x2e,
+	0x63, 0x6f, 0x72, 0x65, 0x2e, 0x49, 0x67, 0x6e, 0x6f, 0x72, 0x65, 0x56, 0x43, 0x53, 0x4d, 0x6f,
+	0x64, 0x65, 0x52, 0x0d, 0x69, 0x67, 0x6e, 0x6f, 0x72, 0x65, 0x56, 0x43, 0x53, 0x4d, 0x6f, 0x64,
+	0x65, 0x12, 0x2a, 0x0a, 0x10, 0x69, 0x67, 0x6e, 0x6f, 0x72, 0x65, 0x56, 0x43, 0x53, 0x49, 0x67,
+	0x6e, 0x6f, 0x72, 0x65, 0x73, 0x18, 0x22, 0x20, 0x01, 0x28, 0x08, 0x52, 0x10, 0x69, 0x67, 0x6e,
+	0x6f, 0x72, 0x65, 0x56, 0x43, 0x53, 0x49, 0x67, 0x6e, 0x6f, 0x72, 0x65, 0x73, 0x12, 0x28, 0x0a,
+	0x0f, 0x64, 0x65, 0x66, 0x61, 0x75, 0x6c, 0x74, 0x46, 0x69, 0x6c, 0x65, 0x4d, 0x6f, 0x64, 0x65,
+	0x18, 0x3f, 0x20, 0x01, 0x28, 0x0d, 0x52, 0x0f, 0x64, 0x65, 0x66, 0x61, 0x75, 0x6c, 0x74, 0x46,
+	0x69, 0x6c, 0x65, 0x4d, 0x6f, 0x64, 0x65, 0x12, 0x32, 0x0a, 0x14, 0x64, 0x65, 0x66, 0x61, 0x75,
+	0x6c, 0x74, 0x44, 0x69, 0x72, 0x65, 0x63, 0x74, 0x6f, 0x72, 0x79, 0x4d, 0x6f, 0x64, 0x65, 0x18,
+	0x40, 0x20, 0x01, 0x28, 0x0d, 0x52, 0x14, 0x64, 0x65, 0x66, 0x61, 0x75, 0x6c, 0x74, 0x44, 0x69,
+	0x72, 0x65, 0x63, 0x74, 0x6f, 0x72, 0x79, 0x4d, 0x6f, 0x64, 0x65, 0x12, 0x22, 0x0a, 0x0c, 0x64,
+	0x65, 0x66, 0x61, 0x75, 0x6c, 0x74, 0x4f, 0x77, 0x6e, 0x65, 0x72, 0x18, 0x41, 0x20, 0x01, 0x28,
+	0x09, 0x52, 0x0c, 0x64, 0x65, 0x66, 0x61, 0x75, 0x6c, 0x74, 0x4f, 0x77, 0x6e, 0x65, 0x72, 0x12,
+	0x22, 0x0a, 0x0c, 0x64, 0x65, 0x66, 0x61, 0x75, 0x6c, 0x74, 0x47, 0x72, 0x6f, 0x75, 0x70, 0x18,
+	0x42, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0c, 0x64, 0x65, 0x66, 0x61, 0x75, 0x6c, 0x74, 0x47, 0x72,
+	0x6f, 0x75, 0x70, 0x12, 0x1c, 0x0a, 0x09, 0x61, 0x66, 0x74, 0x65, 0x72, 0x53, 0x79, 0x6e, 0x63,
+	0x18, 0x51, 0x20, 0x03, 0x28, 0x09, 0x52, 0x09, 0x61, 0x66, 0x74, 0x65, 0x72, 0x53, 0x79, 0x6e,
+	0x63, 0x12, 0x20, 0x0a, 0x0b, 0x62, 0x65, 0x66, 0x6f, 0x72, 0x65, 0x41, 0x70, 0x70, 0x6c, 0x79,
+	0x18, 0x52, 0x20, 0x03, 0x28, 0x09, 0x52, 0x0b, 0x62, 0x65, 0x66, 0x6f, 0x72, 0x65, 0x41, 0x70,
+	0x70, 0x6c, 0x79, 0x12, 0x2c, 0x0a, 0x11, 0x64, 0x65, 0x6c, 0x65, 0x74, 0x69, 0x6f, 0x6e, 0x54,
+	0x68, 0x72, 0x65, 0x73, 0x68, 0x6f, 0x6c, 0x64, 0x18, 0x5b, 0x20, 0x01, 0x28, 0x0d, 0x52, 0x11,
+	0x64, 0x65, 0x6c, 0x65, 0x74, 0x69, 0x6f, 0x6e, 0x54, 0x68, 0x72, 0x65, 0x73, 0x68, 0x6f, 0x6c,
+	0x64, 0x12, 0x26, 0x0a, 0x0e, 0x74, 0x72, 0x61, 0x73, 0x68, 0x44, 0x69, 0x72, 0x65, 0x63, 0x74,
+	0x6f, 0x72, 0x79, 0x18, 0x5c, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0e, 0x74, 0x72, 0x61, 0x73, 0x68,
+	0x44, 0x69, 0x72, 0x65, 0x63, 0x74, 0x6f, 0x72, 0x79, 0x12, 0x32, 0x0a, 0x14, 0x74, 0x72, 0x61,
+	0x73, 0x68, 0x52, 0x65, 0x74, 0x65, 0x6e, 0x74, 0x69, 0x6f, 0x6e, 0x50, 0x65, 0x72, 0x69, 0x6f,
+	0x64, 0x18, 0x5d, 0x20, 0x01, 0x28, 0x0d, 0x52, 0x14, 0x74, 0x72, 0x61, 0x73, 0x68, 0x52, 0x65,
+	0x74, 0x65, 0x6e, 0x74, 0x69, 0x6f, 0x6e, 0x50, 0x65, 0x72, 0x69, 0x6f, 0x64, 0x12, 0x2a, 0x0a,
+	0x10, 0x6d, 0x61, 0x78, 0x69, 0x6d, 0x75, 0x6d, 0x54, 0x72, 0x61, 0x73, 0x68, 0x53, 0x69, 0x7a,
+	0x65, 0x18, 0x5e, 0x20, 0x01, 0x28, 0x04, 0x52, 0x10, 0x6d, 0x61, 0x78, 0x69, 0x6d, 0x75, 0x6d,
+	0x54, 0x72, 0x61, 0x73, 0x68, 0x53, 0x69, 0x7a, 0x65, 0x12, 0x22, 0x0a, 0x0c, 0x76, 0x65, 0x72,
+	0x73, 0x69, 0x6f, 0x6e, 0x43, 0x6f, 0x75, 0x6e, 0x74, 0x18, 0x5f, 0x20, 0x01, 0x28, 0x0d, 0x52,
+	0x0c, 0x76, 0x65, 0x72, 0x73, 0x69, 0x6f, 0x6e, 0x43, 0x6f, 0x75, 0x6e, 0x74, 0x12, 0x2a, 0x0a,
+	0x10, 0x6d, 0x69, 0x6e, 0x69, 0x6d, 0x75, 0x6d, 0x46, 0x72, 0x65, 0x65, 0x53, 0x70, 0x61, 0x63,
+	0x65, 0x18, 0x60, 0x20, 0x01, 0x28, 0x04, 0x52, 0x10, 0x6d, 0x69, 0x6e, 0x69, 0x6d, 0x75, 0x6d,
+	0x46, 0x72, 0x65, 0x65, 0x53, 0x70, 0x61, 0x63, 0x65, 0x12, 0x28, 0x0a, 0x0f, 0x73, 0x63, 0x61,
+	0x6e, 0x43, 0x6f, 0x6e, 0x63, 0x75, 0x72, 0x72, 0x65, 0x6e, 0x63, 0x79, 0x18, 0x65, 0x20, 0x01,
+	0x28, 0x0d, 0x52, 0x0f, 0x73, 0x63, 0x61, 0x6e, 0x43, 0x6f, 0x6e, 0x63, 0x75, 0x72, 0x72, 0x65,
+	0x6e, 0x63, 0x79, 0x12, 0x22, 0x0a, 0x0c, 0x6d, 0x65, 0x6d, 0x6f, 0x72, 0x79, 0x42, 0x75, 0x64,
+	0x67, 0x65, 0x74, 0x18, 0x66, 0x20, 0x01, 0x28, 0x04, 0x52, 0x0c, 0x6d, 0x65, 0x6d, 0x6f, 0x72,
+	0x79, 0x42, 0x75, 0x64, 0x67, 0x65, 0x74, 0x12, 0x24, 0x0a, 0x0d, 0x61, 0x67, 0x65, 0x6e, 0x74,
+	0x4e, 0x69, 0x63, 0x65, 0x6e, 0x65, 0x73, 0x73, 0x18, 0x67, 0x20, 0x01, 0x28, 0x0d, 0x52, 0x0d,
+	0x61, 0x67, 0x65, 0x6e, 0x74, 0x4e, 0x69, 0x63, 0x65, 0x6e, 0x65, 0x73, 0x73, 0x12, 0x3c, 0x0a,
+	0x19, 0x6d, 0x6f, 0x64, 0x69, 0x66, 0x69, 0x63, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x54, 0x69, 0x6d,
+	0x65, 0x54, 0x6f, 0x6c, 0x65, 0x72, 0x61, 0x6e, 0x63, 0x65, 0x18, 0x68, 0x20, 0x01, 0x28, 0x0d,
+	0x52, 0x19, 0x6d, 0x6f, 0x64, 0x69, 0x66, 0x69, 0x63, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x54, 0x69,
+	0x6d, 0x65, 0x54, 0x6f, 0x6c, 0x65, 0x72, 0x61, 0x6e, 0x63, 0x65, 0x42, 0x33, 0x5a, 0x31, 0x67,
 	0x69, 0x74, 0x68, 0x75, 0x62, 0x2e, 0x63, 0x6f, 0x6d, 0x2f, 0x6d, 0x75, 0x74, 0x61, 0x67, 0x65,
 	0x6e, 0x2d, 0x69, 0x6f, 0x2f, 0x6d, 0x75, 0x74, 0x61, 0x67, 0x65, 0x6e, 0x2f, 0x70, 0x6b, 0x67,
 	0x2f, 0x73, 0x79, 0x6e, 0x63, 0x68, 0x72, 0x6f, 0x6e, 0x69, 0x7a, 0x61, 0x74, 0x69, 0x6f, 0x6e,
@@ -321,28 +588,34 @@ func file_synchronization_configuration_proto_rawDescGZIP() []byte {
 
 var file_synchronization_configuration_proto_msgTypes = make([]protoimpl.MessageInfo, 1)
 var file_synchronization_configuration_proto_goTypes = []interface{}{
-	(*Configuration)(nil),         // 0: synchronization.Configuration
-	(core.SynchronizationMode)(0), // 1: core.SynchronizationMode
-	(behavior.ProbeMode)(0),       // 2: behavior.ProbeMode
-	(ScanMode)(0),                 // 3: synchronization.ScanMode
-	(StageMode)(0),                // 4: synchronization.StageMode
-	(core.SymbolicLinkMode)(0),    // 5: core.SymbolicLinkMode
-	(WatchMode)(0),                // 6: synchronization.WatchMode
-	(core.IgnoreVCSMode)(0),       // 7: core.IgnoreVCSMode
+	(*Configuration)(nil),              // 0: synchronization.Configuration
+	(core.SynchronizationMode)(0),      // 1: core.SynchronizationMode
+	(behavior.ProbeMode)(0),            // 2: behavior.ProbeMode
+	(ScanMode)(0),                      // 3: synchronization.ScanMode
+	(StageMode)(0),                     // 4: synchronization.StageMode
+	(core.UnicodeNormalizationMode)(0), // 5: core.UnicodeNormalizationMode
+	(core.ReplacementMode)(0),          // 6: core.ReplacementMode
+	(MirrorEnforcementMode)(0),         // 7: synchronization.MirrorEnforcementMode
+	(core.SymbolicLinkMode)(0),         // 8: core.SymbolicLinkMode
+	(WatchMode)(0),                     // 9: synchronization.WatchMode
+	(core.IgnoreVCSMode)(0),            // 10: core.IgnoreVCSMode
 }
 var file_synchronization_configuration_proto_depIdxs = []int32{
-	1, // 0: synchronization.Configuration.synchronizationMode:type_name -> core.SynchronizationMode
-	2, // 1: synchronization.Configuration.probeMode:type_name -> behavior.ProbeMode
-	3, // 2: synchronization.Configuration.scanMode:type_name -> synchronization.ScanMode
-	4, // 3: synchronization.Configuration.stageMode:type_name -> synchronization.StageMode
-	5, // 4: synchronization.Configuration.symbolicLinkMode:type_name -> core.SymbolicLinkMode
-	6, // 5: synchronization.Configuration.watchMode:type_name -> synchronization.WatchMode
-	7, // 6: synchronization.Configuration.ignoreVCSMode:type_name -> core.IgnoreVCSMode
-	7, // [7:7] is the sub-list for method output_type
-	7, // [7:7] is the sub-list for method input_type
-	7, // [7:7] is the sub-list for extension type_name
-	7, // [7:7] is the sub-list for extension extendee
-	0, // [0:7] is the sub-list for field type_name
+	1,  // 0: synchronization.Configuration.synchronizationMode:type_name -> core.SynchronizationMode
+	2,  // 1: synchronization.Configuration.probeMode:type_name -> behavior.ProbeMode
+	3,  // 2: synchronization.Configuration.scanMode:type_name -> synchronization.ScanMode
+	4,  // 3: synchronization.Configuration.stageMode:type_name -> synchronization.StageMode
+	5,  // 4: synchronization.Configuration.unicodeNormalizationMode:type_name -> core.UnicodeNormalizationMode
+	6,  // 5: synchronization.Configuration.replacementMode:type_name -> core.ReplacementMode
+	7,  // 6: synchronization.Configuration.mirrorEnforcementMode:type_name -> synchronization.MirrorEnforcementMode
+	8,  // 7: synchronization.Configuration.symbolicLinkMode:type_name -> core.SymbolicLinkMode
+	9,  // 8: synchronization.Configuration.watchMode:type_name -> synchronization.WatchMode
+	10, // 9: synchronization.Configuration.ignoreVCSMode:type_name -> core.IgnoreVCSMode
+	10, // [10:10] is the sub-list for method output_type
+	10, // [10:10] is the sub-list for method input_type
+	10, // [10:10] is the sub-list for extension type_name
+	10, // [10:10] is the sub-list for extension extendee
+	0,  // [0:10] is the sub-list for field type_name
 }
 
 func init() { file_synchronization_configuration_proto_init() }
@@ -350,6 +623,7 @@ func file_synchronization_configuration_proto_init() {
 	if File_synchronization_configuration_proto != nil {
 		return
 	}
+	file_synchronization_mirror_enforcement_mode_proto_init()
 	file_synchronization_scan_mode_proto_init()
 	file_synchronization_stage_mode_proto_init()
 	file_synchronization_watch_mode_proto_init()
@@ -30,6 +30,28 @@ const (
 	rescanWaitDuration = 5 * time.Second
 )
 
+// repairRequest encodes a request for a repair cycle.
+type repairRequest struct {
+	// paths are the root-relative paths to repair.
+	paths []string
+	// result is used to return the repair result. It must be buffered and
+	// contain room for one result.
+	result chan *RepairResult
+}
+
+// versionsRequest encodes a request to list or restore file versions.
+type versionsRequest struct {
+	// path is the root-relative path whose versions should be listed.
+	path string
+	// restore is the identifier of the version to restore, if any.
+	restore string
+	// alpha indicates whether the request targets alpha (as opposed to beta).
+	alpha bool
+	// result is used to return the versions result. It must be buffered and
+	// contain room for one result.
+	result chan *VersionsResult
+}
+
 // controller manages and executes a single session.
 type controller struct {
 	// logger is the controller logger.
@@ -38,9 +60,10 @@ type controller struct {
 	sessionPath string
 	// archivePath is the path to the serialized archive.
 	archivePath string
-	// stateLock guards and tracks changes to session's Paused field, state, and
-	// synchronizing. Previous holders may continue to poll on synchronizing if
-	// they store it in a separate variable before releasing the lock.
+	// stateLock guards and tracks changes to session's Paused field, state,
+	// pendingPaths, synchronizing, rootChangeAccepted, and statistics. Previous
+	// holders may continue to poll on synchronizing if they store it in a
+	// separate variable before releasing the lock.
 	stateLock *state.TrackingLock
 	// session encodes the associated session metadata. It is considered static
 	// and safe for concurrent access except for its Paused field, for which
@@ -59,21 +82,36 @@ type controller struct {
 	mergedBetaConfiguration *Configuration
 	// state represents the current synchronization state.
 	state *State
+	// pendingPaths are the root paths of the transitions computed by the
+	// current synchronization cycle that have yet to be applied.
+	pendingPaths []string
 	// synchronizing is used to track whether or not the synchronization loop is
 	// currently in a state where it is capable of performing synchronization.
 	// It is non-nil if and only if the synchronization loop is connected and in
 	// a state where it can perform synchronization. It is closed when
 	// synchronization fails due to an error.
 	synchronizing chan struct{}
-	// lifecycleLock guards access to disabled, cancel, flushRequests, and done.
-	// Only the current holder of the lifecycle lock may set any of these fields
-	// or invoke cancel. The synchronization loop may close close done or
-	// receive from flushRequests without holding the lifecycle lock. Moreover,
-	// previous lifecycle lock holders may continue to send to flushRequests and
-	// poll on done after storing them in separate variables and releasing the
-	// lifecycle lock. Any code wishing to set these fields must first acquire
-	// the lock, then cancel the synchronization loop and wait for it to
-	// complete before making any changes.
+	// rootChangeAccepted indicates that the user has approved the changes that
+	// caused the synchronization loop to halt for safety. It causes the safety
+	// checks to be bypassed for the next synchronization cycle that reaches
+	// them, at which point it is reset.
+	rootChangeAccepted bool
+	// statistics are the synchronization statistics accumulated by the
+	// controller. Unlike state, they aren't reset when the synchronization loop
+	// reconnects.
+	statistics *SessionStatistics
+	// lifecycleLock guards access to disabled, cancel, flushRequests,
+	// verifyRequests, repairRequests, versionsRequests, and done. Only the
+	// current holder of the lifecycle lock may set any of these fields or
+	// invoke cancel. The synchronization loop may close close done or receive
+	// from flushRequests, verifyRequests, repairRequests, and versionsRequests
+	// without holding the lifecycle lock. Moreover, previous lifecycle lock
+	// holders may continue to send to flushRequests, verifyRequests,
+	// repairRequests, and versionsRequests and poll on done after storing them
+	// in separate variables and releasing the lifecycle lock. Any code wishing
+	// to set these fields must first acquire the lock, then cancel the
+	// synchronization loop and wait for it to complete before making any
+	// changes.
 	lifecycleLock sync.Mutex
 	// disabled indicates that no more changes to the synchronization loop
 	// lifecycle are allowed (i.e. no more synchronization loops can be started
@@ -88,8 +126,37 @@ type controller struct {
 	// is buffered, allowing a single request to be queued. All requests passed
 	// via this channel must be buffered and contain room for one error.
 	flushRequests chan chan error
+	// verifyRequests is used to pass verification requests to the
+	// synchronization loop. It is buffered, allowing a single request to be
+	// queued. All requests passed via this channel must be buffered and contain
+	// room for one result.
+	verifyRequests chan chan *VerificationResult
+	// repairRequests is used to pass repair requests to the synchronization
+	// loop. It is buffered, allowing a single request to be queued.
+	repairRequests chan *repairRequest
+	// versionsRequests is used to pass versions requests to the
+	// synchronization loop. It is buffered, allowing a single request to be
+	// queued.
+	versionsRequests chan *versionsRequest
 	// done will be closed by the current synchronization loop when it exits.
 	done chan struct{}
+	// connectivityChanges is used to signal host connectivity changes to the
+	// synchronization loop. It is buffered, allowing a single signal to be
+	// queued. It is static and safe for concurrent access.
+	connectivityChanges chan struct{}
+	// preemptionLock is held by the synchronization loop while transitions are
+	// being applied. It must be acquired before endpoints are shut down in
+	// response to a connectivity change, ensuring that such a shutdown can't
+	// preempt a partially applied transition.
+	preemptionLock sync.Mutex
+	// eventSubscribersLock guards eventSubscribers.
+	eventSubscribersLock sync.Mutex
+	// eventSubscribers maps the channels to which change events are delivered
+	// to channels that are closed if the corresponding subscriber falls behind.
+	// Delivery is non-blocking, so any subscriber whose channel is full when
+	// an event is published is signaled and removed rather than being silently
+	// deprived of the event.
+	eventSubscribers map[chan<- *ChangeEvent]chan struct{}
 }
 
 // newSession creates a new session and corresponding controller.
@@ -221,6 +288,8 @@ func newSession(
 			AlphaState: &EndpointState{},
 			BetaState:  &EndpointState{},
 		},
+		statistics:          &SessionStatistics{},
+		connectivityChanges: make(chan struct{}, 1),
 	}
 
 	// If the session isn't being created paused, then start a synchronization
@@ -230,6 +299,9 @@ func newSession(
 		ctx, cancel := context.WithCancel(context.Background())
 		controller.cancel = cancel
 		controller.flushRequests = make(chan chan error, 1)
+		controller.verifyRequests = make(chan chan *VerificationResult, 1)
+		controller.repairRequests = make(chan *repairRequest, 1)
+		controller.versionsRequests = make(chan *versionsRequest, 1)
 		controller.done = make(chan struct{})
 		go controller.run(ctx, alphaEndpoint, betaEndpoint)
 		alphaEndpoint = nil
@@ -291,6 +363,8 @@ func loadSession(logger *logging.Logger, tracker *state.Tracker, identifier stri
 			AlphaState: &EndpointState{},
 			BetaState:  &EndpointState{},
 		},
+		statistics:          &SessionStatistics{},
+		connectivityChanges: make(chan struct{}, 1),
 	}
 
 	// If the session isn't marked as paused, start a synchronization loop.
@@ -298,6 +372,9 @@ func loadSession(logger *logging.Logger, tracker *state.Tracker, identifier stri
 		ctx, cancel := context.WithCancel(context.Background())
 		controller.cancel = cancel
 		controller.flushRequests = make(chan chan error, 1)
+		controller.verifyRequests = make(chan chan *VerificationResult, 1)
+		controller.repairRequests = make(chan *repairRequest, 1)
+		controller.versionsRequests = make(chan *versionsRequest, 1)
 		controller.done = make(chan struct{})
 		go controller.run(ctx, nil, nil)
 	}
@@ -315,8 +392,70 @@ func (c *controller) currentState() *State {
 	c.stateLock.Lock()
 	defer c.stateLock.UnlockWithoutNotify()
 
-	// Create a static copy of the state.
-	return proto.Clone(c.state).(*State)
+	// Create a static copy of the state and attach the accumulated statistics.
+	// Statistics are tracked separately because the state is reset on
+	// reconnection.
+	result := proto.Clone(c.state).(*State)
+	result.Statistics = proto.Clone(c.statistics).(*SessionStatistics)
+	return result
+}
+
+// pathStatus computes the synchronization status of the specified path.
+func (c *controller) pathStatus(path string) *PathStatusResult {
+	// Lock the session state and defer its release. As with currentState, we
+	// unlock without a notification since no changes are made.
+	c.stateLock.Lock()
+	defer c.stateLock.UnlockWithoutNotify()
+
+	// Compute the path status.
+	return computePathStatus(c.state, c.pendingPaths, path)
+}
+
+// subscribe registers a channel to receive change events for the session. It
+// returns a channel that will be closed if the subscriber falls behind (i.e.
+// if its channel is full when an event is published), at which point the
+// subscriber is removed and will receive no further events.
+func (c *controller) subscribe(events chan<- *ChangeEvent) <-chan struct{} {
+	c.eventSubscribersLock.Lock()
+	defer c.eventSubscribersLock.Unlock()
+	if c.eventSubscribers == nil {
+		c.eventSubscribers = make(map[chan<- *ChangeEvent]chan struct{})
+	}
+	lagged := make(chan struct{})
+	c.eventSubscribers[events] = lagged
+	return lagged
+}
+
+// unsubscribe deregisters a channel previously registered with subscribe.
+func (c *controller) unsubscribe(events chan<- *ChangeEvent) {
+	c.eventSubscribersLock.Lock()
+	defer c.eventSubscribersLock.Unlock()
+	delete(c.eventSubscribers, events)
+}
+
+// hasSubscribers returns whether or not there are any change event
+// subscribers, allowing callers to avoid computing events that won't be
+// delivered.
+func (c *controller) hasSubscribers() bool {
+	c.eventSubscribersLock.Lock()
+	defer c.eventSubscribersLock.Unlock()
+	return len(c.eventSubscribers) > 0
+}
+
+// publish delivers a change event to all subscribers without blocking. Any
+// subscriber that can't accept the event is signaled and removed.
+func (c *controller) publish(event *ChangeEvent) {
+	c.eventSubscribersLock.Lock()
+	defer c.eventSubscribersLock.Unlock()
+	for events, lagged := range c.eventSubscribers {
+		select {
+		case events <- event:
+		default:
+			c.logger.Debug("Removing change event subscriber that fell behind")
+			close(lagged)
+			delete(c.eventSubscribers, events)
+		}
+	}
 }
 
 // flush attempts to force a synchronization cycle for the session. If wait is
@@ -410,6 +549,238 @@ func (c *controller) flush(ctx context.Context, prompter string, skipWait bool)
 	}
 }
 
+// verify forces both endpoints to perform a full cold scan (re-hashing all
+// content) and returns a comparison of the resulting contents against the
+// ancestor and against each other. Verification is read-only: no
+// reconciliation or transitions are performed as part of it. The provided
+// context (which must be non-nil) can terminate the wait for a result.
+func (c *controller) verify(ctx context.Context, prompter string) (*VerificationResult, error) {
+	// Update status.
+	prompting.Message(prompter, fmt.Sprintf("Verifying session %s...", c.session.Identifier))
+
+	// Lock the controller's lifecycle.
+	c.lifecycleLock.Lock()
+
+	// Don't allow any operations if the controller is disabled.
+	if c.disabled {
+		c.lifecycleLock.Unlock()
+		return nil, errors.New("controller disabled")
+	}
+
+	// Check if the session is paused.
+	if c.cancel == nil {
+		c.lifecycleLock.Unlock()
+		return nil, errors.New("session is paused")
+	}
+
+	// Perform logging.
+	c.logger.Infof("Forcing verification cycle")
+
+	// Check if the session is currently synchronizing and store the channel
+	// that we'll use to track synchronizability.
+	c.stateLock.Lock()
+	synchronizing := c.synchronizing
+	c.stateLock.UnlockWithoutNotify()
+	if synchronizing == nil {
+		c.lifecycleLock.Unlock()
+		return nil, errors.New("session is not currently able to synchronize")
+	}
+
+	// Store the channels that we'll need to submit verification requests and
+	// track synchronization termination.
+	verifyRequests := c.verifyRequests
+	done := c.done
+
+	// Release the lifecycle lock.
+	c.lifecycleLock.Unlock()
+
+	// Create a verification request.
+	request := make(chan *VerificationResult, 1)
+
+	// Send the request in a blocking manner, watching for cancellation,
+	// failure, or termination.
+	select {
+	case verifyRequests <- request:
+	case <-ctx.Done():
+		return nil, errors.New("verification cancelled before request could be sent")
+	case <-synchronizing:
+		return nil, errors.New("synchronization failed before verification request could be sent")
+	case <-done:
+		return nil, errors.New("synchronization terminated before verification request could be sent")
+	}
+
+	// Wait for a response to the request, again watching for cancellation,
+	// failure, or termination.
+	select {
+	case result := <-request:
+		return result, nil
+	case <-ctx.Done():
+		return nil, errors.New("verification cancelled while waiting for result")
+	case <-synchronizing:
+		return nil, errors.New("synchronization failed while waiting for verification result")
+	case <-done:
+		return nil, errors.New("synchronization terminated while waiting for verification result")
+	}
+}
+
+// repair forces a synchronization cycle for the session in which content at the
+// specified paths is re-hashed on both endpoints and any content found to have
+// been corrupted (i.e. modified without a corresponding change in metadata) on
+// only one endpoint is restored to its last synchronized state, even if it
+// would otherwise be propagated to the other endpoint. Other pending changes
+// are synchronized as usual. The provided context (which must be non-nil) can
+// terminate the wait for a result.
+func (c *controller) repair(ctx context.Context, paths []string, prompter string) (*RepairResult, error) {
+	// Update status.
+	prompting.Message(prompter, fmt.Sprintf("Repairing session %s...", c.session.Identifier))
+
+	// Lock the controller's lifecycle.
+	c.lifecycleLock.Lock()
+
+	// Don't allow any operations if the controller is disabled.
+	if c.disabled {
+		c.lifecycleLock.Unlock()
+		return nil, errors.New("controller disabled")
+	}
+
+	// Check if the session is paused.
+	if c.cancel == nil {
+		c.lifecycleLock.Unlock()
+		return nil, errors.New("session is paused")
+	}
+
+	// Perform logging.
+	c.logger.Infof("Forcing repair cycle for %d path(s)", len(paths))
+
+	// Check if the session is currently synchronizing and store the channel
+	// that we'll use to track synchronizability.
+	c.stateLock.Lock()
+	synchronizing := c.synchronizing
+	c.stateLock.UnlockWithoutNotify()
+	if synchronizing == nil {
+		c.lifecycleLock.Unlock()
+		return nil, errors.New("session is not currently able to synchronize")
+	}
+
+	// Store the channels that we'll need to submit repair requests and track
+	// synchronization termination.
+	repairRequests := c.repairRequests
+	done := c.done
+
+	// Release the lifecycle lock.
+	c.lifecycleLock.Unlock()
+
+	// Create a repair request.
+	request := &repairRequest{
+		paths:  paths,
+		result: make(chan *RepairResult, 1),
+	}
+
+	// Send the request in a blocking manner, watching for cancellation,
+	// failure, or termination.
+	select {
+	case repairRequests <- request:
+	case <-ctx.Done():
+		return nil, errors.New("repair cancelled before request could be sent")
+	case <-synchronizing:
+		return nil, errors.New("synchronization failed before repair request could be sent")
+	case <-done:
+		return nil, errors.New("synchronization terminated before repair request could be sent")
+	}
+
+	// Wait for a response to the request, again watching for cancellation,
+	// failure, or termination.
+	select {
+	case result := <-request.result:
+		return result, nil
+	case <-ctx.Done():
+		return nil, errors.New("repair cancelled while waiting for result")
+	case <-synchronizing:
+		return nil, errors.New("synchronization failed while waiting for repair result")
+	case <-done:
+		return nil, errors.New("synchronization terminated while waiting for repair result")
+	}
+}
+
+// versions lists the available versions of a path on one endpoint of the
+// session and optionally restores one of them. A restored version will be
+// propagated by a subsequent synchronization cycle. The provided context (which
+// must be non-nil) can terminate the wait for a result.
+func (c *controller) versions(ctx context.Context, path, restore string, alpha bool, prompter string) (*VersionsResult, error) {
+	// Update status.
+	if restore != "" {
+		prompting.Message(prompter, fmt.Sprintf("Restoring version for session %s...", c.session.Identifier))
+	} else {
+		prompting.Message(prompter, fmt.Sprintf("Listing versions for session %s...", c.session.Identifier))
+	}
+
+	// Lock the controller's lifecycle.
+	c.lifecycleLock.Lock()
+
+	// Don't allow any operations if the controller is disabled.
+	if c.disabled {
+		c.lifecycleLock.Unlock()
+		return nil, errors.New("controller disabled")
+	}
+
+	// Check if the session is paused.
+	if c.cancel == nil {
+		c.lifecycleLock.Unlock()
+		return nil, errors.New("session is paused")
+	}
+
+	// Check if the session is currently synchronizing and store the channel
+	// that we'll use to track synchronizability.
+	c.stateLock.Lock()
+	synchronizing := c.synchronizing
+	c.stateLock.UnlockWithoutNotify()
+	if synchronizing == nil {
+		c.lifecycleLock.Unlock()
+		return nil, errors.New("session is not currently able to synchronize")
+	}
+
+	// Store the channels that we'll need to submit versions requests and track
+	// synchronization termination.
+	versionsRequests := c.versionsRequests
+	done := c.done
+
+	// Release the lifecycle lock.
+	c.lifecycleLock.Unlock()
+
+	// Create a versions request.
+	request := &versionsRequest{
+		path:    path,
+		restore: restore,
+		alpha:   alpha,
+		result:  make(chan *VersionsResult, 1),
+	}
+
+	// Send the request in a blocking manner, watching for cancellation,
+	// failure, or termination.
+	select {
+	case versionsRequests <- request:
+	case <-ctx.Done():
+		return nil, errors.New("versions operation cancelled before request could be sent")
+	case <-synchronizing:
+		return nil, errors.New("synchronization failed before versions request could be sent")
+	case <-done:
+		return nil, errors.New("synchronization terminated before versions request could be sent")
+	}
+
+	// Wait for a response to the request, again watching for cancellation,
+	// failure, or termination.
+	select {
+	case result := <-request.result:
+		return result, nil
+	case <-ctx.Done():
+		return nil, errors.New("versions operation cancelled while waiting for result")
+	case <-synchronizing:
+		return nil, errors.New("synchronization failed while waiting for versions result")
+	case <-done:
+		return nil, errors.New("synchronization terminated while waiting for versions result")
+	}
+}
+
 // resume attempts to reconnect and resume the session if it isn't currently
 // connected and synchronizing. If lifecycleLockHeld is true, then halt will
 // assume that the lifecycle lock is held by the caller and will not attempt to
@@ -438,7 +809,7 @@ func (c *controller) resume(ctx context.Context, prompter string, lifecycleLockH
 		// If there is an existing synchronization loop, check if it's already
 		// in a state that's considered "connected".
 		c.stateLock.Lock()
-		connected := c.state.Status >= Status_Watching
+		connected := c.state.Status >= Status_Watching && !c.state.Status.Halted()
 		c.stateLock.UnlockWithoutNotify()
 
 		// If we're already connected, then there's nothing we need to do. We
@@ -464,6 +835,9 @@ func (c *controller) resume(ctx context.Context, prompter string, lifecycleLockH
 		// Nil out any lifecycle state.
 		c.cancel = nil
 		c.flushRequests = nil
+		c.verifyRequests = nil
+		c.repairRequests = nil
+		c.versionsRequests = nil
 		c.done = nil
 	}
 
@@ -489,6 +863,9 @@ func (c *controller) resume(ctx context.Context, prompter string, lifecycleLockH
 	)
 	c.stateLock.Lock()
 	c.state.AlphaState.Connected = (alpha != nil)
+	if alpha != nil {
+		c.state.AlphaState.Capabilities = alpha.Capabilities()
+	}
 	c.stateLock.Unlock()
 
 	// Attempt to connect to beta.
@@ -507,6 +884,9 @@ func (c *controller) resume(ctx context.Context, prompter string, lifecycleLockH
 	)
 	c.stateLock.Lock()
 	c.state.BetaState.Connected = (beta != nil)
+	if beta != nil {
+		c.state.BetaState.Capabilities = beta.Capabilities()
+	}
 	c.stateLock.Unlock()
 
 	// Start the synchronization loop with what we have. Alpha or beta may have
@@ -515,6 +895,9 @@ func (c *controller) resume(ctx context.Context, prompter string, lifecycleLockH
 	ctx, cancel := context.WithCancel(context.Background())
 	c.cancel = cancel
 	c.flushRequests = make(chan chan error, 1)
+	c.verifyRequests = make(chan chan *VerificationResult, 1)
+	c.repairRequests = make(chan *repairRequest, 1)
+	c.versionsRequests = make(chan *versionsRequest, 1)
 	c.done = make(chan struct{})
 	go c.run(ctx, alpha, beta)
 
@@ -561,6 +944,38 @@ func (m controllerHaltMode) description() string {
 	}
 }
 
+// acceptRootChange approves the changes that caused the session to halt for
+// safety and resumes the session. The safety checks are bypassed for the next
+// synchronization cycle that reaches them.
+func (c *controller) acceptRootChange(ctx context.Context, prompter string) error {
+	// Update status.
+	prompting.Message(prompter, fmt.Sprintf("Accepting root change for session %s...", c.session.Identifier))
+
+	// Lock the controller's lifecycle and defer its release.
+	c.lifecycleLock.Lock()
+	defer c.lifecycleLock.Unlock()
+
+	// Don't allow any operations if the controller is disabled.
+	if c.disabled {
+		return errors.New("controller disabled")
+	}
+
+	// Verify that the session is halted for safety and record the approval.
+	c.stateLock.Lock()
+	if !c.state.Status.Halted() {
+		c.stateLock.UnlockWithoutNotify()
+		return errors.New("session is not halted on a root change")
+	}
+	c.rootChangeAccepted = true
+	c.stateLock.UnlockWithoutNotify()
+
+	// Perform logging.
+	c.logger.Info("Accepting pending root change")
+
+	// Resume the session.
+	return c.resume(ctx, prompter, true)
+}
+
 // halt halts the session with the specified behavior. If lifecycleLockHeld is
 // true, then halt will assume that the lifecycle lock is held by the caller and
 // will not attempt to acquire it.
@@ -593,6 +1008,9 @@ func (c *controller) halt(_ context.Context, mode controllerHaltMode, prompter s
 		// Nil out any lifecycle state.
 		c.cancel = nil
 		c.flushRequests = nil
+		c.verifyRequests = nil
+		c.repairRequests = nil
+		c.versionsRequests = nil
 		c.done = nil
 	}
 
@@ -671,6 +1089,27 @@ var (
 	errHaltedForSafety = errors.New("synchronization halted")
 )
 
+// handleConnectivityChange notifies the controller of a host network
+// configuration change or system wake. If any of the session's endpoints are
+// reached via a transport that depends on the network, then the synchronization
+// loop (if running) will drop its existing connections and reconnect
+// immediately rather than waiting for them to time out. Sessions whose
+// endpoints are all reached without the network (e.g. local, Docker, WSL, or
+// exec endpoints) are unaffected.
+func (c *controller) handleConnectivityChange() {
+	// Ignore sessions that don't depend on network connectivity.
+	if !c.session.Alpha.UsesNetwork() && !c.session.Beta.UsesNetwork() {
+		return
+	}
+
+	// Signal the change without blocking. If a signal is already queued, then
+	// there's no need to queue another.
+	select {
+	case c.connectivityChanges <- struct{}{}:
+	default:
+	}
+}
+
 // run is the main run loop for the controller, managing connectivity and
 // synchronization.
 func (c *controller) run(ctx context.Context, alpha, beta Endpoint) {
@@ -695,6 +1134,7 @@ func (c *controller) run(ctx context.Context, alpha, beta Endpoint) {
 			AlphaState: &EndpointState{},
 			BetaState:  &EndpointState{},
 		}
+		c.pendingPaths = nil
 		c.stateLock.Unlock()
 
 		// Log run loop termination.
@@ -704,6 +1144,13 @@ func (c *controller) run(ctx context.Context, alpha, beta Endpoint) {
 		close(c.done)
 	}()
 
+	// Discard any connectivity change signal that was queued before this run
+	// loop started, since any connections that it applied to are long gone.
+	select {
+	case <-c.connectivityChanges:
+	default:
+	}
+
 	// Track the last time that synchronization failed.
 	var lastSynchronizationFailureTime time.Time
 
@@ -732,6 +1179,9 @@ func (c *controller) run(ctx context.Context, alpha, beta Endpoint) {
 			}
 			c.stateLock.Lock()
 			c.state.AlphaState.Connected = (alpha != nil)
+			if alpha != nil {
+				c.state.AlphaState.Capabilities = alpha.Capabilities()
+			}
 			c.stateLock.Unlock()
 
 			// Check for cancellation to avoid a spurious connection to beta in
@@ -760,6 +1210,9 @@ func (c *controller) run(ctx context.Context, alpha, beta Endpoint) {
 			}
 			c.stateLock.Lock()
 			c.state.BetaState.Connected = (beta != nil)
+			if beta != nil {
+				c.state.BetaState.Capabilities = beta.Capabilities()
+			}
 			c.stateLock.Unlock()
 
 			// If both endpoints are connected, we're done. We perform this
@@ -771,11 +1224,14 @@ func (c *controller) run(ctx context.Context, alpha, beta Endpoint) {
 			}
 
 			// If we failed to connect, wait and then retry. Watch for
-			// cancellation in the mean time.
+			// cancellation in the mean time. If a connectivity change occurs,
+			// then retry immediately, since it may have resolved the failure.
 			select {
 			case <-ctx.Done():
 				return
 			case <-time.After(autoReconnectInterval):
+			case <-c.connectivityChanges:
+				c.logger.Info("Connectivity change detected, retrying connection")
 			}
 		}
 
@@ -786,11 +1242,42 @@ func (c *controller) run(ctx context.Context, alpha, beta Endpoint) {
 		c.synchronizing = make(chan struct{})
 		c.stateLock.UnlockWithoutNotify()
 
+		// Monitor for connectivity changes while synchronizing. If one occurs,
+		// then we cancel synchronization and shut down the endpoints, which
+		// preempts any operations that would otherwise block until the
+		// underlying transport times out. We coordinate the shutdown with the
+		// synchronization loop via the preemption lock so that it doesn't
+		// interrupt transitions.
+		synchronizeCtx, synchronizeCancel := context.WithCancel(ctx)
+		preemptions := make(chan bool, 1)
+		go func() {
+			select {
+			case <-c.connectivityChanges:
+				c.logger.Info("Connectivity change detected, forcing reconnection")
+				synchronizeCancel()
+				c.preemptionLock.Lock()
+				alpha.Shutdown()
+				beta.Shutdown()
+				c.preemptionLock.Unlock()
+				preemptions <- true
+			case <-synchronizeCtx.Done():
+				preemptions <- false
+			}
+		}()
+
 		// Perform synchronization.
 		c.logger.Debug("Entering synchronization loop")
-		err := c.synchronize(ctx, alpha, beta)
+		err := c.synchronize(synchronizeCtx, alpha, beta)
 		c.logger.Debug("Synchronization loop terminated with error:", err)
 
+		// Stop connectivity monitoring and determine whether or not it caused
+		// synchronization to terminate.
+		synchronizeCancel()
+		preempted := <-preemptions
+		if preempted && err != errHaltedForSafety {
+			err = errors.New("connection reset due to connectivity change")
+		}
+
 		// Indicate that the synchronization loop is no longer synchronizing.
 		// Again, no notification is required here since this is not a
 		// user-visible state change.
@@ -799,10 +1286,13 @@ func (c *controller) run(ctx context.Context, alpha, beta Endpoint) {
 		c.synchronizing = nil
 		c.stateLock.UnlockWithoutNotify()
 
-		// Shutdown the endpoints.
-		alpha.Shutdown()
+		// Shutdown the endpoints, unless that has already been done due to a
+		// connectivity change.
+		if !preempted {
+			alpha.Shutdown()
+			beta.Shutdown()
+		}
 		alpha = nil
-		beta.Shutdown()
 		beta = nil
 
 		// If synchronization failed due a halting error, then wait for the
@@ -821,6 +1311,7 @@ func (c *controller) run(ctx context.Context, alpha, beta Endpoint) {
 			AlphaState: &EndpointState{},
 			BetaState:  &EndpointState{},
 		}
+		c.pendingPaths = nil
 		c.stateLock.Unlock()
 
 		// If we were cancelled, then return immediately.
@@ -832,18 +1323,61 @@ func (c *controller) run(ctx context.Context, alpha, beta Endpoint) {
 
 		// If less than one auto-reconnect interval has elapsed since the last
 		// synchronization failure, then wait before attempting reconnection.
+		// We skip this wait if synchronization was terminated due to a
+		// connectivity change, since reconnection is expected to succeed.
 		now := time.Now()
-		if now.Sub(lastSynchronizationFailureTime) < autoReconnectInterval {
+		if !preempted && now.Sub(lastSynchronizationFailureTime) < autoReconnectInterval {
 			select {
 			case <-ctx.Done():
 				return
 			case <-time.After(autoReconnectInterval):
+			case <-c.connectivityChanges:
+				c.logger.Info("Connectivity change detected, reconnecting")
 			}
 		}
 		lastSynchronizationFailureTime = now
 	}
 }
 
+// prepareContents extracts the contents from a pair of endpoint snapshots and
+// applies the cross-endpoint adjustments that are required before
+// reconciliation, namely the application of alpha's VCS ignores to beta and the
+// propagation of executability to endpoints that don't preserve it.
+func (c *controller) prepareContents(ancestor *core.Entry, αSnapshot, βSnapshot *core.Snapshot) (*core.Entry, *core.Entry, error) {
+	// Extract contents.
+	αContent := αSnapshot.Content
+	βContent := βSnapshot.Content
+
+	// If alpha has imported patterns from VCS ignore files, then apply them to
+	// beta's content so that they act as session ignores. Alpha will have
+	// already applied them during scanning.
+	if len(αSnapshot.VcsIgnores) > 0 && βContent != nil {
+		if content, err := core.ApplyIgnores(βContent, αSnapshot.VcsIgnores); err != nil {
+			return nil, nil, fmt.Errorf("unable to apply alpha VCS ignores to beta: %w", err)
+		} else {
+			βContent = content
+		}
+	}
+
+	// If one side preserves executability and the other does not, then
+	// propagate executability from the preserving side to the non-preserving
+	// side. We only do this if the corresponding target content is non-nil,
+	// because (a) PropagateExecutability is a no-op if it is nil and (b)
+	// PreservesExecutability will have defaulted to false if there's no
+	// content and (even though this will be a no-op) we don't want the
+	// spurious logs.
+	if αSnapshot.PreservesExecutability && βContent != nil && !βSnapshot.PreservesExecutability {
+		c.logger.Debug("Propagating alpha executability to beta")
+		βContent = core.PropagateExecutability(ancestor, αContent, βContent)
+	} else if βSnapshot.PreservesExecutability && αContent != nil && !αSnapshot.PreservesExecutability {
+		c.logger.Debug("Propagating beta executability to alpha")
+		αContent = core.PropagateExecutability(ancestor, βContent, αContent)
+	}
+
+	// Done.
+	return αContent, βContent, nil
+}
+
 // synchronize is the main synchronization loop for the controller.
 func (c *controller) synchronize(ctx context.Context, alpha, beta Endpoint) error {
 	// Clear any error state upon restart of this function. If there was a
@@ -861,6 +1395,20 @@ func (c *controller) synchronize(ctx context.Context, alpha, beta Endpoint) erro
 	// Track whether or not a flush request triggered the synchronization loop.
 	var flushRequest chan error
 
+	// Track whether or not a verification request triggered the
+	// synchronization loop.
+	var verifyRequest chan *VerificationResult
+
+	// Track whether or not a repair request triggered the synchronization
+	// loop, as well as the result of the repair once it's been computed.
+	var repairRequest *repairRequest
+	var repairResult *RepairResult
+
+	// Track whether or not a versions request triggered the synchronization
+	// loop, as well as whether or not it restored a version.
+	var versionsRequest *versionsRequest
+	var versionRestored bool
+
 	// Load the archive and extract the ancestor. We enforce that the archive
 	// contains only synchronizable content.
 	archive := &core.Archive{}
@@ -877,6 +1425,23 @@ func (c *controller) synchronize(ctx context.Context, alpha, beta Endpoint) erro
 		synchronizationMode = c.session.Version.DefaultSynchronizationMode()
 	}
 
+	// Compute whether or not mirror violations should be tracked. This only
+	// applies in the one-way-replica synchronization mode, where modifications
+	// made directly to beta are always overwritten, but only reported in the
+	// strict mirror enforcement mode.
+	mirrorEnforcementMode := c.session.Configuration.MirrorEnforcementMode
+	if mirrorEnforcementMode.IsDefault() {
+		mirrorEnforcementMode = c.session.Version.DefaultMirrorEnforcementMode()
+	}
+	trackMirrorViolations := synchronizationMode == core.SynchronizationMode_SynchronizationModeOneWayReplica &&
+		mirrorEnforcementMode == MirrorEnforcementMode_MirrorEnforcementModeStrict
+
+	// Compute the effective deletion threshold.
+	deletionThreshold := c.session.Configuration.DeletionThreshold
+	if deletionThreshold == 0 {
+		deletionThreshold = c.session.Version.DefaultDeletionThreshold()
+	}
+
 	// Compute, on a per-endpoint basis, whether or not polling should be
 	// disabled.
 	αWatchMode := c.mergedAlphaConfiguration.WatchMode
@@ -901,6 +1466,11 @@ func (c *controller) synchronize(ctx context.Context, alpha, beta Endpoint) erro
 	// Create variables to track our reasons for skipping polling.
 	var skippingPollingDueToScanError, skippingPollingDueToMissingFiles bool
 
+	// Compute the minimum interval between event-triggered synchronization
+	// cycles and track the start time of the most recent cycle.
+	minimumCycleInterval := time.Duration(c.session.Configuration.MinimumCycleInterval) * time.Second
+	var lastCycleStart time.Time
+
 	// Loop until there is a synchronization error.
 	for {
 		// Unless we've been requested to skip polling, wait for a dirty state
@@ -966,14 +1536,16 @@ func (c *controller) synchronize(ctx context.Context, alpha, beta Endpoint) erro
 			// request, or for cancellation. In any of these cases, cancel
 			// polling and ensure that both polling operations have completed.
 			var αPollErr, βPollErr error
-			cancelled := false
+			cancelled, triggeredByEndpoint := false, false
 			select {
 			case αPollErr = <-αPollResults:
 				c.logger.Debug("Triggered by alpha endpoint")
+				triggeredByEndpoint = true
 				pollCancel()
 				βPollErr = <-βPollResults
 			case βPollErr = <-βPollResults:
 				c.logger.Debug("Triggered by beta endpoint")
+				triggeredByEndpoint = true
 				pollCancel()
 				αPollErr = <-αPollResults
 			case flushRequest = <-c.flushRequests:
@@ -984,6 +1556,30 @@ func (c *controller) synchronize(ctx context.Context, alpha, beta Endpoint) erro
 				pollCancel()
 				αPollErr = <-αPollResults
 				βPollErr = <-βPollResults
+			case verifyRequest = <-c.verifyRequests:
+				if cap(verifyRequest) < 1 {
+					panic("unbuffered verification request")
+				}
+				c.logger.Debug("Triggered by verification request")
+				pollCancel()
+				αPollErr = <-αPollResults
+				βPollErr = <-βPollResults
+			case repairRequest = <-c.repairRequests:
+				if cap(repairRequest.result) < 1 {
+					panic("unbuffered repair request")
+				}
+				c.logger.Debug("Triggered by repair request")
+				pollCancel()
+				αPollErr = <-αPollResults
+				βPollErr = <-βPollResults
+			case versionsRequest = <-c.versionsRequests:
+				if cap(versionsRequest.result) < 1 {
+					panic("unbuffered versions request")
+				}
+				c.logger.Debug("Triggered by versions request")
+				pollCancel()
+				αPollErr = <-αPollResults
+				βPollErr = <-βPollResults
 			case <-ctx.Done():
 				cancelled = true
 				pollCancel()
@@ -999,6 +1595,69 @@ func (c *controller) synchronize(ctx context.Context, alpha, beta Endpoint) erro
 			} else if βPollErr != nil {
 				return fmt.Errorf("beta polling error: %w", βPollErr)
 			}
+
+			// If the cycle was triggered by filesystem events and a minimum
+			// cycle interval is being enforced, then wait until that interval
+			// has elapsed since the start of the previous cycle. Any events
+			// that arrive in the meantime will be batched into the upcoming
+			// cycle. We still allow flush, verification, and repair requests
+			// to cut the wait short, since those are explicit user requests.
+			if triggeredByEndpoint && minimumCycleInterval > 0 {
+				if remaining := minimumCycleInterval - time.Since(lastCycleStart); remaining > 0 {
+					c.logger.Debugf("Delaying synchronization cycle by %v to enforce minimum cycle interval", remaining)
+					timer := time.NewTimer(remaining)
+					select {
+					case <-timer.C:
+					case flushRequest = <-c.flushRequests:
+						if cap(flushRequest) < 1 {
+							panic("unbuffered flush request")
+						}
+						c.logger.Debug("Minimum cycle interval wait interrupted by flush request")
+						timer.Stop()
+					case verifyRequest = <-c.verifyRequests:
+						if cap(verifyRequest) < 1 {
+							panic("unbuffered verification request")
+						}
+						c.logger.Debug("Minimum cycle interval wait interrupted by verification request")
+						timer.Stop()
+					case repairRequest = <-c.repairRequests:
+						if cap(repairRequest.result) < 1 {
+							panic("unbuffered repair request")
+						}
+						c.logger.Debug("Minimum cycle interval wait interrupted by repair request")
+						timer.Stop()
+					case <-ctx.Done():
+						timer.Stop()
+						return errors.New("cancelled during minimum cycle interval wait")
+					}
+				}
+			}
+
+			// If a versions request is present, then service it. If no version
+			// was restored, then there's nothing new to synchronize, so we can
+			// return to polling. Otherwise, we proceed with a synchronization
+			// cycle to propagate the restored content.
+			if versionsRequest != nil {
+				endpoint := beta
+				if versionsRequest.alpha {
+					endpoint = alpha
+				}
+				versions, problem, err := endpoint.Versions(versionsRequest.path, versionsRequest.restore)
+				if err != nil {
+					return fmt.Errorf("unable to perform versions operation: %w", err)
+				}
+				versionRestored = versionsRequest.restore != "" && problem == ""
+				versionsRequest.result <- &VersionsResult{
+					Session:  c.session.Identifier,
+					Versions: versions,
+					Restored: versionRestored,
+					Problem:  problem,
+				}
+				versionsRequest = nil
+				if !versionRestored {
+					continue
+				}
+			}
 		} else {
 			c.logger.Debug("Skipping polling")
 			skipPolling = false
@@ -1006,26 +1665,54 @@ func (c *controller) synchronize(ctx context.Context, alpha, beta Endpoint) erro
 
 		// Scan both endpoints in parallel and check for errors. If a flush
 		// request is present, then force both endpoints to perform a full
-		// (warm) re-scan rather than using acceleration.
+		// (warm) re-scan rather than using acceleration. If a verification
+		// request is present, then force both endpoints to perform a full cold
+		// re-scan that re-hashes all content, since the cache can't be trusted
+		// to detect out-of-band modifications. If a repair request is present,
+		// then perform a full warm scan followed by a scan that re-hashes only
+		// the content at the requested paths, which allows corruption to be
+		// distinguished from ordinary modifications. If a version was
+		// restored, then force a full re-scan to ensure that the restored
+		// content is picked up.
 		c.logger.Debug("Scanning endpoints")
+		lastCycleStart = time.Now()
+		cycleStatistics := &CycleStatistics{}
 		c.stateLock.Lock()
 		c.state.Status = Status_Scanning
 		c.stateLock.Unlock()
-		forceFullScan := flushRequest != nil
+		forceFullScan := flushRequest != nil || verifyRequest != nil || repairRequest != nil || versionRestored
+		versionRestored = false
+		var rehashPaths []string
+		if verifyRequest != nil {
+			rehashPaths = []string{""}
+		}
 		var αSnapshot, βSnapshot *core.Snapshot
 		var αScanErr, βScanErr error
 		var αTryAgain, βTryAgain bool
-		scanDone := &sync.WaitGroup{}
-		scanDone.Add(2)
-		go func() {
-			αSnapshot, αScanErr, αTryAgain = alpha.Scan(ctx, ancestor, forceFullScan)
-			scanDone.Done()
-		}()
-		go func() {
-			βSnapshot, βScanErr, βTryAgain = beta.Scan(ctx, ancestor, forceFullScan)
-			scanDone.Done()
-		}()
-		scanDone.Wait()
+		scan := func(rehashPaths []string) {
+			scanDone := &sync.WaitGroup{}
+			scanDone.Add(2)
+			go func() {
+				αSnapshot, αScanErr, αTryAgain = alpha.Scan(ctx, ancestor, forceFullScan, rehashPaths)
+				scanDone.Done()
+			}()
+			go func() {
+				βSnapshot, βScanErr, βTryAgain = beta.Scan(ctx, ancestor, forceFullScan, rehashPaths)
+				scanDone.Done()
+			}()
+			scanDone.Wait()
+		}
+		scan(rehashPaths)
+		var αCachedSnapshot, βCachedSnapshot *core.Snapshot
+		// RACE: Content modified between the two repair scans will appear to
+		// have been corrupted, because its metadata will have changed by the
+		// time that it's rehashed. This window is small, and repairs are only
+		// performed at explicitly requested paths.
+		if repairRequest != nil && αScanErr == nil && βScanErr == nil {
+			c.logger.Debug("Re-scanning endpoints with rehashing of repair paths")
+			αCachedSnapshot, βCachedSnapshot = αSnapshot, βSnapshot
+			scan(repairRequest.paths)
+		}
 
 		// Check if cancellation occurred during scanning.
 		select {
@@ -1090,9 +1777,11 @@ func (c *controller) synchronize(ctx context.Context, alpha, beta Endpoint) erro
 		}
 		skippingPollingDueToScanError = false
 
-		// Extract contents.
-		αContent := αSnapshot.Content
-		βContent := βSnapshot.Content
+		// Extract contents and prepare them for reconciliation.
+		αContent, βContent, err := c.prepareContents(ancestor, αSnapshot, βSnapshot)
+		if err != nil {
+			return err
+		}
 		if c.logger.Level() >= logging.LevelTrace {
 			c.logger.Tracef("Ancestor contains %d entries, alpha contains %d entries, beta contains %d entries",
 				ancestor.Count(), αContent.Count(), βContent.Count(),
@@ -1124,29 +1813,61 @@ func (c *controller) synchronize(ctx context.Context, alpha, beta Endpoint) erro
 		c.state.Status = Status_Reconciling
 		c.stateLock.Unlock()
 
-		// If one side preserves executability and the other does not, then
-		// propagate executability from the preserving side to the
-		// non-preserving side. We only do this if the corresponding target
-		// content is non-nil, because (a) PropagateExecutability is a no-op if
-		// it is nil and (b) PreservesExecutability will have defaulted to false
-		// if there's no content and (even though this will be a no-op) we don't
-		// want the spurious logs.
-		if αSnapshot.PreservesExecutability && βContent != nil && !βSnapshot.PreservesExecutability {
-			c.logger.Debug("Propagating alpha executability to beta")
-			βContent = core.PropagateExecutability(ancestor, αContent, βContent)
-		} else if βSnapshot.PreservesExecutability && αContent != nil && !αSnapshot.PreservesExecutability {
-			c.logger.Debug("Propagating beta executability to alpha")
-			αContent = core.PropagateExecutability(ancestor, βContent, αContent)
+		// If a verification request triggered this scan, then compare the
+		// freshly re-hashed contents against the ancestor and against each
+		// other, respond with the result, and remove the request from our
+		// tracking. Verification is read-only, so we then return to polling
+		// without performing reconciliation or transitions. Any changes
+		// observed by the scan that predate the request will already have
+		// triggered a synchronization cycle, so nothing is missed by doing so.
+		if verifyRequest != nil {
+			verifyRequest <- &VerificationResult{
+				Session:             c.session.Identifier,
+				AlphaDivergences:    core.DivergentPaths(ancestor, αContent),
+				BetaDivergences:     core.DivergentPaths(ancestor, βContent),
+				EndpointDivergences: core.DivergentPaths(αContent, βContent),
+			}
+			verifyRequest = nil
+			continue
 		}
 
+		// If we're tracking mirror violations, then identify any locations where
+		// beta's contents have diverged from the ancestor. Since alpha's changes
+		// are only ever propagated to beta (and recorded in the ancestor) as a
+		// unit, any such divergence represents an out-of-band modification to
+		// beta, which reconciliation will revert. We skip this check until the
+		// ancestor has been populated by an initial synchronization cycle,
+		// since beta's pre-existing contents aren't considered violations. The
+		// reported violations are always replaced so that they only reflect
+		// the current cycle.
+		var mirrorViolations []string
+		if trackMirrorViolations && ancestor != nil {
+			mirrorViolations = core.DivergentPaths(ancestor, βContent)
+			if len(mirrorViolations) > 0 {
+				c.logger.Warnf("Reverting %d mirror violation(s) on beta", len(mirrorViolations))
+			}
+		}
+		c.stateLock.Lock()
+		c.state.MirrorViolations = mirrorViolations
+		c.state.TotalMirrorViolations += uint64(len(mirrorViolations))
+		c.stateLock.Unlock()
+
+		// Check whether or not the user has approved the changes that caused
+		// a previous safety halt, in which case the safety checks are bypassed
+		// for this cycle.
+		c.stateLock.Lock()
+		rootChangeAccepted := c.rootChangeAccepted
+		c.stateLock.UnlockWithoutNotify()
+
 		// Check if the root is a directory that's been emptied (by deleting a
 		// non-trivial amount of content) on one endpoint (but not both). This
 		// can be intentional, but usually indicates that a non-persistent
 		// filesystem (such as a container filesystem) is being used as the
 		// synchronization root. In any case, we switch to a halted state and
 		// wait for the user to either manually propagate the deletion and
-		// resume the session, recreate the session, or reset the session.
-		if oneEndpointEmptiedRoot(ancestor, αContent, βContent) {
+		// resume the session, recreate the session, reset the session, or
+		// accept the change.
+		if !rootChangeAccepted && oneEndpointEmptiedRoot(ancestor, αContent, βContent) {
 			c.stateLock.Lock()
 			c.state.Status = Status_HaltedOnRootEmptied
 			c.stateLock.Unlock()
@@ -1189,18 +1910,53 @@ func (c *controller) synchronize(ctx context.Context, alpha, beta Endpoint) erro
 			}
 		}
 
-		// Store conflicts that arose during reconciliation.
+		// If a repair request triggered this synchronization cycle, then
+		// replace the reconciliation output at the requested paths with the
+		// transitions necessary to restore diverged content, and record the
+		// result so that we can respond once the cycle completes.
+		if repairRequest != nil {
+			αCachedContent, βCachedContent, err := c.prepareContents(ancestor, αCachedSnapshot, βCachedSnapshot)
+			if err != nil {
+				return err
+			}
+			var repaired, unrepairable []string
+			ancestorChanges, αTransitions, βTransitions, repaired, unrepairable = core.Repair(
+				repairRequest.paths,
+				ancestor, αCachedContent, αContent, βCachedContent, βContent,
+				synchronizationMode,
+				ancestorChanges, αTransitions, βTransitions,
+			)
+			c.logger.Infof("Repairing %d path(s), unable to repair %d path(s)",
+				len(repaired), len(unrepairable),
+			)
+			repairResult = &RepairResult{
+				Session:      c.session.Identifier,
+				Repaired:     repaired,
+				Unrepairable: unrepairable,
+			}
+		}
+
+		// Store conflicts that arose during reconciliation and record the
+		// paths at which changes are pending.
+		var pendingPaths []string
+		for _, transition := range αTransitions {
+			pendingPaths = append(pendingPaths, transition.Path)
+		}
+		for _, transition := range βTransitions {
+			pendingPaths = append(pendingPaths, transition.Path)
+		}
 		c.stateLock.Lock()
 		c.state.Conflicts = conflicts
+		c.pendingPaths = pendingPaths
 		c.stateLock.Unlock()
 
 		// Check if a root deletion operation is being propagated. This can be
 		// intentional, accidental, or an indication of a non-persistent
 		// filesystem (such as a container filesystem). In any case, we switch
 		// to a halted state and wait for the user to either manually propagate
-		// the deletion and resume the session, recreate the session, or reset
-		// the session.
-		if containsRootDeletion(αTransitions) || containsRootDeletion(βTransitions) {
+		// the deletion and resume the session, recreate the session, reset the
+		// session, or accept the change.
+		if !rootChangeAccepted && (containsRootDeletion(αTransitions) || containsRootDeletion(βTransitions)) {
 			c.stateLock.Lock()
 			c.state.Status = Status_HaltedOnRootDeletion
 			c.stateLock.Unlock()
@@ -1210,14 +1966,38 @@ func (c *controller) synchronize(ctx context.Context, alpha, beta Endpoint) erro
 		// Check if a root type change is being propagated. This can be
 		// intentional or accidental. In any case, we switch to a halted state
 		// and wait for the user to manually delete the content that will be
-		// overwritten by the type change and resume the session.
-		if containsRootTypeChange(αTransitions) || containsRootTypeChange(βTransitions) {
+		// overwritten by the type change and resume the session, or accept the
+		// change.
+		if !rootChangeAccepted && (containsRootTypeChange(αTransitions) || containsRootTypeChange(βTransitions)) {
 			c.stateLock.Lock()
 			c.state.Status = Status_HaltedOnRootTypeChange
 			c.stateLock.Unlock()
 			return errHaltedForSafety
 		}
 
+		// Check if either endpoint would have more than the configured
+		// percentage of previously synchronized content deleted. Mass deletions
+		// are sometimes intentional, but can also indicate a misconfigured or
+		// non-persistent filesystem. In any case, we switch to a halted state
+		// and wait for the user to accept the change, reset the session, or
+		// recreate the session.
+		if !rootChangeAccepted &&
+			(exceedsDeletionThreshold(ancestor, αTransitions, deletionThreshold) ||
+				exceedsDeletionThreshold(ancestor, βTransitions, deletionThreshold)) {
+			c.stateLock.Lock()
+			c.state.Status = Status_HaltedOnDeletionThreshold
+			c.stateLock.Unlock()
+			return errHaltedForSafety
+		}
+
+		// If the user approved a previous safety halt, then that approval has
+		// now been consumed.
+		if rootChangeAccepted {
+			c.stateLock.Lock()
+			c.rootChangeAccepted = false
+			c.stateLock.UnlockWithoutNotify()
+		}
+
 		// Stage files on alpha.
 		c.stateLock.Lock()
 		c.state.Status = Status_StagingAlpha
@@ -1235,6 +2015,7 @@ func (c *controller) synchronize(ctx context.Context, alpha, beta Endpoint) erro
 				c.logger.Debugf("Alpha pre-staged %d/%d files", len(paths)-len(filteredPaths), len(paths))
 			}
 			if len(filteredPaths) > 0 {
+				var stagedSize, transferredSize uint64
 				monitor := func(state *rsync.ReceiverState) error {
 					c.stateLock.Lock()
 					if state == nil {
@@ -1244,6 +2025,7 @@ func (c *controller) synchronize(ctx context.Context, alpha, beta Endpoint) erro
 							c.state.AlphaState.StagingProgress = &rsync.ReceiverState{}
 						}
 						proto.Merge(c.state.AlphaState.StagingProgress, state)
+						stagedSize, transferredSize = state.TotalReceivedSize, state.TotalDataSize
 					}
 					c.stateLock.Unlock()
 					return nil
@@ -1253,6 +2035,9 @@ func (c *controller) synchronize(ctx context.Context, alpha, beta Endpoint) erro
 				if err = beta.Supply(filteredPaths, signatures, receiver); err != nil {
 					return fmt.Errorf("unable to stage files on alpha: %w", err)
 				}
+				cycleStatistics.StagedFiles += uint64(len(filteredPaths))
+				cycleStatistics.StagedSize += stagedSize
+				cycleStatistics.TransferredSize += transferredSize
 			}
 		}
 
@@ -1273,6 +2058,7 @@ func (c *controller) synchronize(ctx context.Context, alpha, beta Endpoint) erro
 				c.logger.Debugf("Beta pre-staged %d/%d files", len(paths)-len(filteredPaths), len(paths))
 			}
 			if len(filteredPaths) > 0 {
+				var stagedSize, transferredSize uint64
 				monitor := func(state *rsync.ReceiverState) error {
 					c.stateLock.Lock()
 					if state == nil {
@@ -1282,6 +2068,7 @@ func (c *controller) synchronize(ctx context.Context, alpha, beta Endpoint) erro
 							c.state.BetaState.StagingProgress = &rsync.ReceiverState{}
 						}
 						proto.Merge(c.state.BetaState.StagingProgress, state)
+						stagedSize, transferredSize = state.TotalReceivedSize, state.TotalDataSize
 					}
 					c.stateLock.Unlock()
 					return nil
@@ -1291,6 +2078,9 @@ func (c *controller) synchronize(ctx context.Context, alpha, beta Endpoint) erro
 				if err = alpha.Supply(filteredPaths, signatures, receiver); err != nil {
 					return fmt.Errorf("unable to stage files on beta: %w", err)
 				}
+				cycleStatistics.StagedFiles += uint64(len(filteredPaths))
+				cycleStatistics.StagedSize += stagedSize
+				cycleStatistics.TransferredSize += transferredSize
 			}
 		}
 
@@ -1306,6 +2096,9 @@ func (c *controller) synchronize(ctx context.Context, alpha, beta Endpoint) erro
 		var αMissingFiles, βMissingFiles bool
 		var αTransitionErr, βTransitionErr error
 		var αChanges, βChanges []*core.Change
+		// Hold the preemption lock while transitioning so that any
+		// connectivity-triggered endpoint shutdown waits for completion.
+		c.preemptionLock.Lock()
 		transitionDone := &sync.WaitGroup{}
 		if len(αTransitions) > 0 {
 			transitionDone.Add(1)
@@ -1338,12 +2131,14 @@ func (c *controller) synchronize(ctx context.Context, alpha, beta Endpoint) erro
 			}()
 		}
 		transitionDone.Wait()
+		c.preemptionLock.Unlock()
 
 		// Record transition problems.
 		c.stateLock.Lock()
 		c.state.Status = Status_Saving
 		c.state.AlphaState.TransitionProblems = αProblems
 		c.state.BetaState.TransitionProblems = βProblems
+		c.pendingPaths = nil
 		c.stateLock.Unlock()
 
 		// Fold applied changes into the ancestor's change list and update the
@@ -1400,16 +2195,53 @@ func (c *controller) synchronize(ctx context.Context, alpha, beta Endpoint) erro
 			skippingPollingDueToMissingFiles = false
 		}
 
-		// Increment the synchronization cycle count.
+		// Increment the synchronization cycle count and record statistics for
+		// the cycle.
+		cycleStatistics.Duration = uint64(time.Since(lastCycleStart))
 		c.stateLock.Lock()
 		c.state.SuccessfulCycles++
+		c.statistics.record(cycleStatistics)
+		successfulCycles := c.state.SuccessfulCycles
 		c.stateLock.Unlock()
 
+		// If any modifications were applied to either endpoint, then publish a
+		// change event describing them. Since expanding changes into per-path
+		// modifications can be expensive for large hierarchies, we only do so
+		// if there's someone to receive the event.
+		if c.hasSubscribers() {
+			var αPathChanges, βPathChanges []*core.PathChange
+			for t, transition := range αTransitions {
+				change := &core.Change{Path: transition.Path, Old: transition.Old, New: αResults[t]}
+				αPathChanges = append(αPathChanges, change.PathChanges()...)
+			}
+			for t, transition := range βTransitions {
+				change := &core.Change{Path: transition.Path, Old: transition.Old, New: βResults[t]}
+				βPathChanges = append(βPathChanges, change.PathChanges()...)
+			}
+			if len(αPathChanges) > 0 || len(βPathChanges) > 0 {
+				c.publish(&ChangeEvent{
+					Session:      c.session.Identifier,
+					Cycle:        successfulCycles,
+					Time:         timestamppb.Now(),
+					AlphaChanges: αPathChanges,
+					BetaChanges:  βPathChanges,
+				})
+			}
+		}
+
 		// If a flush request triggered this synchronization cycle, then tell it
 		// that the cycle has completed and remove it from our tracking.
 		if flushRequest != nil {
 			flushRequest <- nil
 			flushRequest = nil
 		}
+
+		// If a repair request triggered this synchronization cycle, then
+		// respond with the result and remove it from our tracking.
+		if repairRequest != nil {
+			repairRequest.result <- repairResult
+			repairRequest = nil
+			repairResult = nil
+		}
 	}
 }
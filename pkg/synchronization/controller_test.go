@@ -1,3 +1,51 @@
 package synchronization
 
-// TODO: Implement.
+import (
+	"testing"
+)
+
+// TestControllerEventSubscriptions tests controller change event subscription
+// and publication, including the handling of subscribers that fall behind.
+func TestControllerEventSubscriptions(t *testing.T) {
+	// Create a controller.
+	c := &controller{}
+
+	// Verify that there are no subscribers initially.
+	if c.hasSubscribers() {
+		t.Fatal("controller has subscribers before subscription")
+	}
+
+	// Subscribe with a single-event buffer.
+	events := make(chan *ChangeEvent, 1)
+	lagged := c.subscribe(events)
+	if !c.hasSubscribers() {
+		t.Fatal("controller has no subscribers after subscription")
+	}
+
+	// Publish an event and verify that it's delivered.
+	first := &ChangeEvent{Session: "session", Cycle: 1}
+	c.publish(first)
+	select {
+	case <-lagged:
+		t.Fatal("subscriber marked as lagging after successful delivery")
+	default:
+	}
+
+	// Publish another event without draining the first and verify that the
+	// subscriber is signaled and removed.
+	c.publish(&ChangeEvent{Session: "session", Cycle: 2})
+	select {
+	case <-lagged:
+	default:
+		t.Fatal("subscriber not marked as lagging after failed delivery")
+	}
+	if c.hasSubscribers() {
+		t.Error("lagging subscriber not removed")
+	}
+	if event := <-events; event != first {
+		t.Error("delivered event does not match published event")
+	}
+
+	// Verify that unsubscribing a removed subscriber is harmless.
+	c.unsubscribe(events)
+}
@@ -4,6 +4,9 @@ import (
 	"bytes"
 	"errors"
 	"fmt"
+	"time"
+
+	"google.golang.org/protobuf/types/known/timestamppb"
 )
 
 // EnsureValid ensures that Cache's invariants are respected.
@@ -33,6 +36,28 @@ func (c *Cache) EnsureValid() error {
 	return nil
 }
 
+// Invalidate returns a copy of the cache without any entries at or beneath the
+// specified paths, forcing the corresponding content to be re-hashed if the
+// result is used for a scan. The cache entries themselves are shared with the
+// original cache. If no paths are specified, then the cache is returned as-is.
+func (c *Cache) Invalidate(paths []string) *Cache {
+	// Handle the trivial case.
+	if len(paths) == 0 {
+		return c
+	}
+
+	// Copy entries that aren't being invalidated.
+	result := &Cache{Entries: make(map[string]*CacheEntry, len(c.GetEntries()))}
+	for path, entry := range c.GetEntries() {
+		if !PathWithinAny(path, paths) {
+			result.Entries[path] = entry
+		}
+	}
+
+	// Done.
+	return result
+}
+
 // Equal determines whether or not another cache is equal to this one. It is
 // designed specifically for tests, though it is exported so that it can be used
 // by scan_bench.
@@ -84,6 +109,19 @@ func (c *Cache) Equal(other *Cache) bool {
 	return true
 }
 
+// modificationTimesMatch determines whether or not an observed modification
+// time matches a cached modification time to within the specified tolerance. A
+// tolerance of 0 requires exact equality. Non-zero tolerances accommodate
+// filesystems with coarse or inconsistently reported timestamps, at the cost of
+// missing same-size content modifications made within the tolerance window.
+func modificationTimesMatch(observed time.Time, cached *timestamppb.Timestamp, tolerance time.Duration) bool {
+	difference := observed.Sub(cached.AsTime())
+	if difference < 0 {
+		difference = -difference
+	}
+	return difference <= tolerance
+}
+
 // ReverseLookupMap provides facilities for doing reverse lookups to avoid
 // expensive staging operations in the case of renames and copies.
 type ReverseLookupMap struct {
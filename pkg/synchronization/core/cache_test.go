@@ -3,6 +3,7 @@ package core
 import (
 	"math"
 	"testing"
+	"time"
 
 	"google.golang.org/protobuf/types/known/timestamppb"
 )
@@ -67,3 +68,76 @@ func TestCacheEnsureValid(t *testing.T) {
 // but it's worth testing for completeness.
 
 // TODO: Implement TestReverseLookupMap.
+
+// TestModificationTimesMatch tests modificationTimesMatch.
+func TestModificationTimesMatch(t *testing.T) {
+	// Create a reference time and its cached representation.
+	reference := time.Unix(1600000000, 500000000)
+	cached := timestamppb.New(reference)
+
+	// Define test cases.
+	tests := []struct {
+		observed  time.Time
+		tolerance time.Duration
+		expected  bool
+	}{
+		{reference, 0, true},
+		{reference.Add(time.Nanosecond), 0, false},
+		{reference.Add(-time.Nanosecond), 0, false},
+		{reference.Add(time.Second), 2 * time.Second, true},
+		{reference.Add(-2 * time.Second), 2 * time.Second, true},
+		{reference.Add(2*time.Second + time.Nanosecond), 2 * time.Second, false},
+		{reference.Add(-3 * time.Second), 2 * time.Second, false},
+	}
+
+	// Process test cases.
+	for i, test := range tests {
+		if result := modificationTimesMatch(test.observed, cached, test.tolerance); result != test.expected {
+			t.Errorf("test index %d: result does not match expected: %t != %t", i, result, test.expected)
+		}
+	}
+}
+
+// TestCacheInvalidate tests Cache.Invalidate.
+func TestCacheInvalidate(t *testing.T) {
+	// Create a cache.
+	cache := &Cache{Entries: map[string]*CacheEntry{
+		"a":     {Size: 1},
+		"a/b":   {Size: 2},
+		"ab":    {Size: 3},
+		"c/d/e": {Size: 4},
+	}}
+
+	// Set up test cases.
+	testCases := []struct {
+		paths    []string
+		expected []string
+	}{
+		{nil, []string{"a", "a/b", "ab", "c/d/e"}},
+		{[]string{""}, nil},
+		{[]string{"a"}, []string{"ab", "c/d/e"}},
+		{[]string{"a/b", "c"}, []string{"a", "ab"}},
+		{[]string{"missing"}, []string{"a", "a/b", "ab", "c/d/e"}},
+	}
+
+	// Process test cases.
+	for i, testCase := range testCases {
+		result := cache.Invalidate(testCase.paths)
+		if len(result.Entries) != len(testCase.expected) {
+			t.Errorf("test index %d: entry count does not match expected: %d != %d",
+				i, len(result.Entries), len(testCase.expected),
+			)
+			continue
+		}
+		for _, path := range testCase.expected {
+			if result.Entries[path] != cache.Entries[path] {
+				t.Errorf("test index %d: entry for %s not retained", i, path)
+			}
+		}
+	}
+
+	// Ensure that the original cache wasn't modified.
+	if len(cache.Entries) != 4 {
+		t.Error("original cache modified by invalidation")
+	}
+}
@@ -1,5 +1,9 @@
 package core
 
+import (
+	"sort"
+)
+
 // differ provides recursive diffing infrastructure.
 type differ struct {
 	// changes is the list of changes being tracked by the diff.
@@ -53,3 +57,21 @@ func diff(path string, base, target *Entry) []*Change {
 func Diff(base, target *Entry) []*Change {
 	return diff("", base, target)
 }
+
+// DivergentPaths performs a diff operation between the synchronizable portions
+// of a base and target entry and returns the sorted list of paths at which they
+// differ.
+func DivergentPaths(base, target *Entry) []string {
+	// Compute changes.
+	changes := diff("", base.synchronizable(), target.synchronizable())
+
+	// Extract and sort paths.
+	paths := make([]string, len(changes))
+	for c, change := range changes {
+		paths[c] = change.Path
+	}
+	sort.Strings(paths)
+
+	// Done.
+	return paths
+}
@@ -29,3 +29,42 @@ func TestDiff(t *testing.T) {
 		}
 	}
 }
+
+// TestDivergentPaths tests DivergentPaths.
+func TestDivergentPaths(t *testing.T) {
+	// Define test cases.
+	tests := []struct {
+		base     *Entry
+		target   *Entry
+		expected []string
+	}{
+		{tN, tN, nil},
+		{tD1, tD1, nil},
+		{tN, tF1, []string{""}},
+		{tD1, tD2, []string{"file"}},
+		{tD0, tDU, nil},
+		{tD0, tDP1, nil},
+		{tD1, tDM, []string{
+			"executable file",
+			"file link",
+			"populated subdir",
+			"second_file.txt",
+			"subdir",
+			"unicode-composed-\xc3\xa9ntry",
+		}},
+	}
+
+	// Process test cases.
+	for i, test := range tests {
+		paths := DivergentPaths(test.base, test.target)
+		if len(paths) != len(test.expected) {
+			t.Errorf("test index %d: path count (%d) does not match expected (%d)", i, len(paths), len(test.expected))
+			continue
+		}
+		for p, path := range paths {
+			if path != test.expected[p] {
+				t.Errorf("test index %d: path (%q) does not match expected (%q)", i, path, test.expected[p])
+			}
+		}
+	}
+}
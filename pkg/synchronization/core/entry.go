@@ -185,6 +185,28 @@ func (e *Entry) walk(path string, visitor entryVisitor, reverse bool) {
 	}
 }
 
+// lookup returns the entry at the specified root-relative path within the entry
+// hierarchy, treating the entry itself as the synchronization root. It returns
+// nil if no entry exists at the specified path.
+func (e *Entry) lookup(path string) *Entry {
+	// Handle the case of the synchronization root.
+	if path == "" {
+		return e
+	}
+
+	// Crawl down the hierarchy. Indexing into a nil content map is fine, so we
+	// don't need to worry about intermediate entries that aren't directories.
+	for _, component := range strings.Split(path, "/") {
+		if e == nil {
+			return nil
+		}
+		e = e.Contents[component]
+	}
+
+	// Done.
+	return e
+}
+
 // Count returns the total number of entries within the entry hierarchy rooted
 // at the entry, excluding nil and unsynchronizable entries.
 func (e *Entry) Count() uint64 {
@@ -159,6 +159,28 @@ func (i *ignorer) ignored(path string, directory bool) bool {
 	return ignored
 }
 
+// Ignorer provides ignore evaluation for endpoints that perform their own
+// scanning rather than using Scan.
+type Ignorer struct {
+	// ignorer is the underlying ignorer.
+	ignorer *ignorer
+}
+
+// NewIgnorer creates a new ignorer given a list of user-provided ignore
+// patterns.
+func NewIgnorer(patterns []string) (*Ignorer, error) {
+	ignorer, err := newIgnorer(patterns)
+	if err != nil {
+		return nil, err
+	}
+	return &Ignorer{ignorer}, nil
+}
+
+// Ignored determines whether or not the specified path should be ignored.
+func (i *Ignorer) Ignored(path string, directory bool) bool {
+	return i.ignorer.ignored(path, directory)
+}
+
 // IgnoreCacheKey represents a key in an ignore cache.
 type IgnoreCacheKey struct {
 	// path is the path used for testing ignore status.
@@ -0,0 +1,159 @@
+package core
+
+import (
+	"bytes"
+	"sort"
+	"strings"
+)
+
+const (
+	// GitIgnoreName is the name of Git ignore files.
+	GitIgnoreName = ".gitignore"
+)
+
+// ParseGitIgnore converts the contents of a Git ignore file into equivalent
+// ignore patterns that are anchored relative to the synchronization root. The
+// path argument specifies the root-relative path of the ignore file itself,
+// which must be non-empty. Blank lines, comments, and patterns that aren't
+// representable as ignore patterns are skipped.
+func ParseGitIgnore(path string, contents []byte) []string {
+	// Compute the prefix to add to patterns in order to anchor them to the
+	// directory containing the ignore file.
+	prefix := pathJoinable(pathDir(path))
+
+	// Process lines.
+	var result []string
+	for _, line := range bytes.Split(contents, []byte{'\n'}) {
+		// Convert the line and strip any carriage return.
+		pattern := strings.TrimSuffix(string(line), "\r")
+
+		// Remove trailing spaces unless they're escaped.
+		for strings.HasSuffix(pattern, " ") && !strings.HasSuffix(pattern, "\\ ") {
+			pattern = pattern[:len(pattern)-1]
+		}
+		pattern = strings.ReplaceAll(pattern, "\\ ", " ")
+
+		// Skip blank lines and comments.
+		if pattern == "" || pattern[0] == '#' {
+			continue
+		}
+
+		// Check for negation. Git allows a leading backslash to escape literal
+		// exclamation points and hashes, so we remove that escape if present.
+		negated := false
+		if pattern[0] == '!' {
+			negated = true
+			pattern = pattern[1:]
+		} else if strings.HasPrefix(pattern, "\\!") || strings.HasPrefix(pattern, "\\#") {
+			pattern = pattern[1:]
+		}
+
+		// Determine whether or not the pattern is anchored to the ignore file
+		// directory. In Git's semantics, a pattern is anchored if it contains a
+		// slash anywhere other than at its end.
+		anchored := strings.IndexByte(strings.TrimSuffix(pattern, "/"), '/') >= 0
+		pattern = strings.TrimPrefix(pattern, "/")
+		if pattern == "" || pattern == "/" {
+			continue
+		}
+
+		// Convert the pattern to be relative to the synchronization root. At
+		// the root, Git's semantics match our own. In subdirectories, we have
+		// to anchor patterns explicitly and allow unanchored patterns to match
+		// at any depth beneath the ignore file directory.
+		if prefix != "" {
+			if anchored {
+				pattern = prefix + pattern
+			} else {
+				pattern = prefix + "**/" + pattern
+			}
+		} else if anchored {
+			pattern = "/" + pattern
+		}
+
+		// Restore negation.
+		if negated {
+			pattern = "!" + pattern
+		}
+
+		// Verify that the pattern is valid.
+		if !ValidIgnorePattern(pattern) {
+			continue
+		}
+
+		// Record the pattern.
+		result = append(result, pattern)
+	}
+
+	// Done.
+	return result
+}
+
+// GitIgnorePaths returns the root-relative paths of all synchronizable Git
+// ignore files within the specified entry hierarchy. Paths are sorted such that
+// ignore files in parent directories precede those in their subdirectories,
+// meaning that patterns derived from the files (in order) will give deeper
+// ignore files precedence, matching Git's behavior.
+func GitIgnorePaths(root *Entry) []string {
+	// Collect paths.
+	var result []string
+	root.walk("", func(path string, entry *Entry) {
+		if entry != nil && entry.Kind == EntryKind_File && PathBase(path) == GitIgnoreName {
+			result = append(result, path)
+		}
+	}, false)
+
+	// Sort paths by depth and then lexicographically.
+	sort.Slice(result, func(i, j int) bool {
+		iDepth, jDepth := strings.Count(result[i], "/"), strings.Count(result[j], "/")
+		if iDepth != jDepth {
+			return iDepth < jDepth
+		}
+		return result[i] < result[j]
+	})
+
+	// Done.
+	return result
+}
+
+// ApplyIgnores returns a copy of the specified entry hierarchy in which any
+// content matching the specified ignore patterns has been replaced with
+// untracked entries. It's used to apply ignores derived on one endpoint to the
+// content of another. The root of the hierarchy is never ignored.
+func ApplyIgnores(root *Entry, ignores []string) (*Entry, error) {
+	// Create an ignorer.
+	ignorer, err := newIgnorer(ignores)
+	if err != nil {
+		return nil, err
+	}
+
+	// Create a copy of the hierarchy that we can mutate.
+	result := root.Copy(true)
+
+	// Replace ignored content with untracked entries.
+	applyIgnoresRecursive("", result, ignorer)
+
+	// Done.
+	return result, nil
+}
+
+// applyIgnoresRecursive is the recursive implementation of ApplyIgnores.
+func applyIgnoresRecursive(path string, entry *Entry, ignorer *ignorer) {
+	// Only directories have contents that need processing.
+	if entry == nil || entry.Kind != EntryKind_Directory {
+		return
+	}
+
+	// Process contents.
+	prefix := pathJoinable(path)
+	for name, child := range entry.Contents {
+		childPath := prefix + name
+		if child.Kind == EntryKind_Untracked || child.Kind == EntryKind_Problematic {
+			continue
+		} else if ignorer.ignored(childPath, child.Kind == EntryKind_Directory) {
+			entry.Contents[name] = &Entry{Kind: EntryKind_Untracked}
+		} else {
+			applyIgnoresRecursive(childPath, child, ignorer)
+		}
+	}
+}
@@ -0,0 +1,118 @@
+package core
+
+import (
+	"testing"
+
+	"github.com/mutagen-io/mutagen/pkg/comparison"
+)
+
+// TestParseGitIgnore tests ParseGitIgnore.
+func TestParseGitIgnore(t *testing.T) {
+	// Define test cases.
+	tests := []struct {
+		path     string
+		contents string
+		expected []string
+	}{
+		{".gitignore", "", nil},
+		{".gitignore", "# comment\n\n   \n", nil},
+		{".gitignore", "node_modules/\n*.o\r\n", []string{"node_modules/", "*.o"}},
+		{".gitignore", "/build\ndocs/out\n!keep.o\n", []string{"/build", "/docs/out", "!keep.o"}},
+		{".gitignore", "\\#literal\n\\!bang\ntrailing   \nescaped\\ \n", []string{"#literal", "!bang", "trailing", "escaped "}},
+		{"sub/.gitignore", "*.log\n/tmp/\na/b\n!x\n", []string{"sub/**/*.log", "sub/tmp/", "sub/a/b", "!sub/**/x"}},
+		{".gitignore", "/\n!\n[\n", nil},
+	}
+
+	// Process test cases.
+	for i, test := range tests {
+		if result := ParseGitIgnore(test.path, []byte(test.contents)); !comparison.StringSlicesEqual(result, test.expected) {
+			t.Errorf("test index %d: patterns do not match expected: %v != %v", i, result, test.expected)
+		}
+	}
+}
+
+// TestGitIgnorePaths tests GitIgnorePaths.
+func TestGitIgnorePaths(t *testing.T) {
+	// Create a hierarchy with ignore files at multiple depths.
+	file := &Entry{Kind: EntryKind_File, Digest: []byte{0}}
+	root := &Entry{
+		Kind: EntryKind_Directory,
+		Contents: map[string]*Entry{
+			".gitignore": file,
+			"-a": {
+				Kind: EntryKind_Directory,
+				Contents: map[string]*Entry{
+					".gitignore": file,
+				},
+			},
+			"b": {
+				Kind: EntryKind_Directory,
+				Contents: map[string]*Entry{
+					".gitignore": {Kind: EntryKind_Directory},
+					"c": {
+						Kind: EntryKind_Directory,
+						Contents: map[string]*Entry{
+							".gitignore": file,
+						},
+					},
+				},
+			},
+		},
+	}
+
+	// Verify the result.
+	expected := []string{".gitignore", "-a/.gitignore", "b/c/.gitignore"}
+	if result := GitIgnorePaths(root); !comparison.StringSlicesEqual(result, expected) {
+		t.Error("ignore paths do not match expected:", result, "!=", expected)
+	}
+}
+
+// TestApplyIgnores tests ApplyIgnores.
+func TestApplyIgnores(t *testing.T) {
+	// Create a hierarchy.
+	file := &Entry{Kind: EntryKind_File, Digest: []byte{0}}
+	root := &Entry{
+		Kind: EntryKind_Directory,
+		Contents: map[string]*Entry{
+			"keep":   file,
+			"drop.o": file,
+			"sub": {
+				Kind: EntryKind_Directory,
+				Contents: map[string]*Entry{
+					"build": {Kind: EntryKind_Directory},
+					"other": file,
+				},
+			},
+		},
+	}
+
+	// Apply ignores.
+	result, err := ApplyIgnores(root, []string{"*.o", "sub/**/build/"})
+	if err != nil {
+		t.Fatal("unable to apply ignores:", err)
+	}
+
+	// Verify that the original hierarchy wasn't modified.
+	if root.Contents["drop.o"].Kind != EntryKind_File {
+		t.Error("original hierarchy modified")
+	}
+
+	// Verify the result.
+	if result.Contents["keep"].Kind != EntryKind_File {
+		t.Error("unignored file modified")
+	}
+	if result.Contents["drop.o"].Kind != EntryKind_Untracked {
+		t.Error("ignored file not untracked")
+	}
+	if result.Contents["sub"].Contents["build"].Kind != EntryKind_Untracked {
+		t.Error("ignored directory not untracked")
+	}
+	if result.Contents["sub"].Contents["other"].Kind != EntryKind_File {
+		t.Error("unignored nested file modified")
+	}
+
+	// Verify that invalid ignores are rejected.
+	if _, err := ApplyIgnores(root, []string{""}); err == nil {
+		t.Error("invalid ignores accepted")
+	}
+}
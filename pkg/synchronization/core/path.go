@@ -1,6 +1,7 @@
 package core
 
 import (
+	"errors"
 	"strings"
 )
 
@@ -135,3 +136,42 @@ func pathLess(first, second string) bool {
 		}
 	}
 }
+
+// ValidatePath ensures that a path is a valid root-relative synchronization
+// path, i.e. that it's either empty (representing the synchronization root) or
+// a slash-separated sequence of non-empty components that doesn't contain any
+// "." or ".." components.
+func ValidatePath(path string) error {
+	// The synchronization root is always valid.
+	if path == "" {
+		return nil
+	}
+
+	// Validate each component.
+	for _, component := range strings.Split(path, "/") {
+		if component == "" {
+			return errors.New("path contains empty component")
+		} else if component == "." || component == ".." {
+			return errors.New("path contains relative component")
+		}
+	}
+
+	// Success.
+	return nil
+}
+
+// pathWithin returns true if path is equal to or contained beneath root.
+func pathWithin(path, root string) bool {
+	return root == "" || path == root || strings.HasPrefix(path, root+"/")
+}
+
+// PathWithinAny returns true if path is equal to or contained beneath any of
+// the specified roots.
+func PathWithinAny(path string, roots []string) bool {
+	for _, root := range roots {
+		if pathWithin(path, root) {
+			return true
+		}
+	}
+	return false
+}
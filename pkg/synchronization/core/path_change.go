@@ -0,0 +1,78 @@
+package core
+
+import (
+	"errors"
+)
+
+// Description returns a human-readable description of a path change kind.
+func (k PathChangeKind) Description() string {
+	switch k {
+	case PathChangeKind_PathChangeKindCreated:
+		return "Created"
+	case PathChangeKind_PathChangeKindUpdated:
+		return "Updated"
+	case PathChangeKind_PathChangeKindDeleted:
+		return "Deleted"
+	default:
+		return "Unknown"
+	}
+}
+
+// EnsureValid ensures that PathChange's invariants are respected.
+func (c *PathChange) EnsureValid() error {
+	// A nil path change is not valid.
+	if c == nil {
+		return errors.New("nil path change")
+	}
+
+	// Ensure that the kind is valid.
+	switch c.Kind {
+	case PathChangeKind_PathChangeKindCreated:
+	case PathChangeKind_PathChangeKindUpdated:
+	case PathChangeKind_PathChangeKindDeleted:
+	default:
+		return errors.New("unknown path change kind")
+	}
+
+	// Success.
+	return nil
+}
+
+// PathChanges expands a change into a list of per-path modifications. Content
+// that is created or deleted as part of a larger hierarchy is reported
+// individually for each path in that hierarchy. Deletions are reported before
+// other modifications at or beneath the same path, with deleted contents
+// reported before their parents. The ordering is otherwise non-deterministic.
+func (c *Change) PathChanges() []*PathChange {
+	// Create the result.
+	var result []*PathChange
+
+	// Compute the minimal set of changes between the old and new entries and
+	// expand each one.
+	for _, change := range diff(c.Path, c.Old, c.New) {
+		// Record deletions of old content. If the old entry is being replaced,
+		// then the root of the replacement will be recorded as an update below.
+		change.Old.walk(change.Path, func(path string, entry *Entry) {
+			if entry == nil || (path == change.Path && change.New != nil) {
+				return
+			}
+			result = append(result, &PathChange{Path: path, Kind: PathChangeKind_PathChangeKindDeleted})
+		}, true)
+
+		// Record creations of new content. If the new entry is replacing an old
+		// entry, then record the root of the replacement as an update.
+		change.New.walk(change.Path, func(path string, entry *Entry) {
+			if entry == nil {
+				return
+			}
+			kind := PathChangeKind_PathChangeKindCreated
+			if path == change.Path && change.Old != nil {
+				kind = PathChangeKind_PathChangeKindUpdated
+			}
+			result = append(result, &PathChange{Path: path, Kind: kind})
+		}, false)
+	}
+
+	// Done.
+	return result
+}
@@ -0,0 +1,234 @@
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// versions:
+// 	protoc-gen-go v1.28.0
+// 	protoc        v3.19.4
+// source: synchronization/core/path_change.proto
+
+package core
+
+import (
+	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
+	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	reflect "reflect"
+	sync "sync"
+)
+
+const (
+	// Verify that this generated code is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(20 - protoimpl.MinVersion)
+	// Verify that runtime/protoimpl is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
+)
+
+// PathChangeKind encodes the kind of modification applied at a path.
+type PathChangeKind int32
+
+const (
+	// PathChangeKind_PathChangeKindUnknown indicates an unknown or unspecified
+	// kind of modification. It is never used for valid path changes.
+	PathChangeKind_PathChangeKindUnknown PathChangeKind = 0
+	// PathChangeKind_PathChangeKindCreated indicates that content was created
+	// at a path where no content previously existed.
+	PathChangeKind_PathChangeKindCreated PathChangeKind = 1
+	// PathChangeKind_PathChangeKindUpdated indicates that existing content at
+	// a path was modified or replaced.
+	PathChangeKind_PathChangeKindUpdated PathChangeKind = 2
+	// PathChangeKind_PathChangeKindDeleted indicates that content was removed
+	// from a path.
+	PathChangeKind_PathChangeKindDeleted PathChangeKind = 3
+)
+
+// Enum value maps for PathChangeKind.
+var (
+	PathChangeKind_name = map[int32]string{
+		0: "PathChangeKindUnknown",
+		1: "PathChangeKindCreated",
+		2: "PathChangeKindUpdated",
+		3: "PathChangeKindDeleted",
+	}
+	PathChangeKind_value = map[string]int32{
+		"PathChangeKindUnknown": 0,
+		"PathChangeKindCreated": 1,
+		"PathChangeKindUpdated": 2,
+		"PathChangeKindDeleted": 3,
+	}
+)
+
+func (x PathChangeKind) Enum() *PathChangeKind {
+	p := new(PathChangeKind)
+	*p = x
+	return p
+}
+
+func (x PathChangeKind) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (PathChangeKind) Descriptor() protoreflect.EnumDescriptor {
+	return file_synchronization_core_path_change_proto_enumTypes[0].Descriptor()
+}
+
+func (PathChangeKind) Type() protoreflect.EnumType {
+	return &file_synchronization_core_path_change_proto_enumTypes[0]
+}
+
+func (x PathChangeKind) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use PathChangeKind.Descriptor instead.
+func (PathChangeKind) EnumDescriptor() ([]byte, []int) {
+	return file_synchronization_core_path_change_proto_rawDescGZIP(), []int{0}
+}
+
+// PathChange describes a modification applied at a single path. PathChange
+// objects should be considered immutable and must not be modified.
+type PathChange struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	// Path is the path at which the modification occurred (relative to the
+	// synchronization root).
+	Path string `protobuf:"bytes,1,opt,name=path,proto3" json:"path,omitempty"`
+	// Kind is the kind of modification.
+	Kind PathChangeKind `protobuf:"varint,2,opt,name=kind,proto3,enum=core.PathChangeKind" json:"kind,omitempty"`
+}
+
+func (x *PathChange) Reset() {
+	*x = PathChange{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_synchronization_core_path_change_proto_msgTypes[0]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *PathChange) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*PathChange) ProtoMessage() {}
+
+func (x *PathChange) ProtoReflect() protoreflect.Message {
+	mi := &file_synchronization_core_path_change_proto_msgTypes[0]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use PathChange.ProtoReflect.Descriptor instead.
+func (*PathChange) Descriptor() ([]byte, []int) {
+	return file_synchronization_core_path_change_proto_rawDescGZIP(), []int{0}
+}
+
+func (x *PathChange) GetPath() string {
+	if x != nil {
+		return x.Path
+	}
+	return ""
+}
+
+func (x *PathChange) GetKind() PathChangeKind {
+	if x != nil {
+		return x.Kind
+	}
+	return PathChangeKind_PathChangeKindUnknown
+}
+
+var File_synchronization_core_path_change_proto protoreflect.FileDescriptor
+
+var file_synchronization_core_path_change_proto_rawDesc = []byte{
+	0x0a, 0x26, 0x73, 0x79, 0x6e, 0x63, 0x68, 0x72, 0x6f, 0x6e, 0x69, 0x7a, 0x61, 0x74, 0x69, 0x6f,
+	0x6e, 0x2f, 0x63, 0x6f, 0x72, 0x65, 0x2f, 0x70, 0x61, 0x74, 0x68, 0x5f, 0x63, 0x68, 0x61, 0x6e,
+	0x67, 0x65, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x12, 0x04, 0x63, 0x6f, 0x72, 0x65, 0x22, 0x4a,
+	0x0a, 0x0a, 0x50, 0x61, 0x74, 0x68, 0x43, 0x68, 0x61, 0x6e, 0x67, 0x65, 0x12, 0x12, 0x0a, 0x04,
+	0x70, 0x61, 0x74, 0x68, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x04, 0x70, 0x61, 0x74, 0x68,
+	0x12, 0x28, 0x0a, 0x04, 0x6b, 0x69, 0x6e, 0x64, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0e, 0x32, 0x14,
+	0x2e, 0x63, 0x6f, 0x72, 0x65, 0x2e, 0x50, 0x61, 0x74, 0x68, 0x43, 0x68, 0x61, 0x6e, 0x67, 0x65,
+	0x4b, 0x69, 0x6e, 0x64, 0x52, 0x04, 0x6b, 0x69, 0x6e, 0x64, 0x2a, 0x7c, 0x0a, 0x0e, 0x50, 0x61,
+	0x74, 0x68, 0x43, 0x68, 0x61, 0x6e, 0x67, 0x65, 0x4b, 0x69, 0x6e, 0x64, 0x12, 0x19, 0x0a, 0x15,
+	0x50, 0x61, 0x74, 0x68, 0x43, 0x68, 0x61, 0x6e, 0x67, 0x65, 0x4b, 0x69, 0x6e, 0x64, 0x55, 0x6e,
+	0x6b, 0x6e, 0x6f, 0x77, 0x6e, 0x10, 0x00, 0x12, 0x19, 0x0a, 0x15, 0x50, 0x61, 0x74, 0x68, 0x43,
+	0x68, 0x61, 0x6e, 0x67, 0x65, 0x4b, 0x69, 0x6e, 0x64, 0x43, 0x72, 0x65, 0x61, 0x74, 0x65, 0x64,
+	0x10, 0x01, 0x12, 0x19, 0x0a, 0x15, 0x50, 0x61, 0x74, 0x68, 0x43, 0x68, 0x61, 0x6e, 0x67, 0x65,
+	0x4b, 0x69, 0x6e, 0x64, 0x55, 0x70, 0x64, 0x61, 0x74, 0x65, 0x64, 0x10, 0x02, 0x12, 0x19, 0x0a,
+	0x15, 0x50, 0x61, 0x74, 0x68, 0x43, 0x68, 0x61, 0x6e, 0x67, 0x65, 0x4b, 0x69, 0x6e, 0x64, 0x44,
+	0x65, 0x6c, 0x65, 0x74, 0x65, 0x64, 0x10, 0x03, 0x42, 0x38, 0x5a, 0x36, 0x67, 0x69, 0x74, 0x68,
+	0x75, 0x62, 0x2e, 0x63, 0x6f, 0x6d, 0x2f, 0x6d, 0x75, 0x74, 0x61, 0x67, 0x65, 0x6e, 0x2d, 0x69,
+	0x6f, 0x2f, 0x6d, 0x75, 0x74, 0x61, 0x67, 0x65, 0x6e, 0x2f, 0x70, 0x6b, 0x67, 0x2f, 0x73, 0x79,
+	0x6e, 0x63, 0x68, 0x72, 0x6f, 0x6e, 0x69, 0x7a, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x2f, 0x63, 0x6f,
+	0x72, 0x65, 0x62, 0x06, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x33,
+}
+
+var (
+	file_synchronization_core_path_change_proto_rawDescOnce sync.Once
+	file_synchronization_core_path_change_proto_rawDescData = file_synchronization_core_path_change_proto_rawDesc
+)
+
+func file_synchronization_core_path_change_proto_rawDescGZIP() []byte {
+	file_synchronization_core_path_change_proto_rawDescOnce.Do(func() {
+		file_synchronization_core_path_change_proto_rawDescData = protoimpl.X.CompressGZIP(file_synchronization_core_path_change_proto_rawDescData)
+	})
+	return file_synchronization_core_path_change_proto_rawDescData
+}
+
+var file_synchronization_core_path_change_proto_enumTypes = make([]protoimpl.EnumInfo, 1)
+var file_synchronization_core_path_change_proto_msgTypes = make([]protoimpl.MessageInfo, 1)
+var file_synchronization_core_path_change_proto_goTypes = []interface{}{
+	(PathChangeKind)(0), // 0: core.PathChangeKind
+	(*PathChange)(nil),  // 1: core.PathChange
+}
+var file_synchronization_core_path_change_proto_depIdxs = []int32{
+	0, // 0: core.PathChange.kind:type_name -> core.PathChangeKind
+	1, // [1:1] is the sub-list for method output_type
+	1, // [1:1] is the sub-list for method input_type
+	1, // [1:1] is the sub-list for extension type_name
+	1, // [1:1] is the sub-list for extension extendee
+	0, // [0:1] is the sub-list for field type_name
+}
+
+func init() { file_synchronization_core_path_change_proto_init() }
+func file_synchronization_core_path_change_proto_init() {
+	if File_synchronization_core_path_change_proto != nil {
+		return
+	}
+	if !protoimpl.UnsafeEnabled {
+		file_synchronization_core_path_change_proto_msgTypes[0].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*PathChange); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+	}
+	type x struct{}
+	out := protoimpl.TypeBuilder{
+		File: protoimpl.DescBuilder{
+			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
+			RawDescriptor: file_synchronization_core_path_change_proto_rawDesc,
+			NumEnums:      1,
+			NumMessages:   1,
+			NumExtensions: 0,
+			NumServices:   0,
+		},
+		GoTypes:           file_synchronization_core_path_change_proto_goTypes,
+		DependencyIndexes: file_synchronization_core_path_change_proto_depIdxs,
+		EnumInfos:         file_synchronization_core_path_change_proto_enumTypes,
+		MessageInfos:      file_synchronization_core_path_change_proto_msgTypes,
+	}.Build()
+	File_synchronization_core_path_change_proto = out.File
+	file_synchronization_core_path_change_proto_rawDesc = nil
+	file_synchronization_core_path_change_proto_goTypes = nil
+	file_synchronization_core_path_change_proto_depIdxs = nil
+}
@@ -0,0 +1,101 @@
+package core
+
+import (
+	"sort"
+	"testing"
+)
+
+// TestPathChangeKindDescription tests PathChangeKind.Description.
+func TestPathChangeKindDescription(t *testing.T) {
+	// Set up test cases.
+	testCases := []struct {
+		kind                PathChangeKind
+		expectedDescription string
+	}{
+		{PathChangeKind_PathChangeKindCreated, "Created"},
+		{PathChangeKind_PathChangeKindUpdated, "Updated"},
+		{PathChangeKind_PathChangeKindDeleted, "Deleted"},
+		{(PathChangeKind_PathChangeKindDeleted + 1), "Unknown"},
+	}
+
+	// Process test cases.
+	for _, testCase := range testCases {
+		if description := testCase.kind.Description(); description != testCase.expectedDescription {
+			t.Errorf(
+				"path change kind description (%s) does not match expected (%s)",
+				description,
+				testCase.expectedDescription,
+			)
+		}
+	}
+}
+
+// TestChangePathChanges tests Change.PathChanges.
+func TestChangePathChanges(t *testing.T) {
+	// Set up test cases.
+	testCases := []struct {
+		description string
+		change      *Change
+		expected    map[string]PathChangeKind
+	}{
+		{"no-op", &Change{Path: "a", Old: tF1, New: tF1}, map[string]PathChangeKind{}},
+		{"file creation", &Change{Path: "a", New: tF1}, map[string]PathChangeKind{
+			"a": PathChangeKind_PathChangeKindCreated,
+		}},
+		{"file modification", &Change{Path: "a", Old: tF1, New: tF2}, map[string]PathChangeKind{
+			"a": PathChangeKind_PathChangeKindUpdated,
+		}},
+		{"file deletion", &Change{Path: "a", Old: tF1}, map[string]PathChangeKind{
+			"a": PathChangeKind_PathChangeKindDeleted,
+		}},
+		{"directory creation", &Change{Path: "a", New: tD1}, map[string]PathChangeKind{
+			"a":      PathChangeKind_PathChangeKindCreated,
+			"a/file": PathChangeKind_PathChangeKindCreated,
+		}},
+		{"directory content modification", &Change{Path: "a", Old: tD1, New: tD2}, map[string]PathChangeKind{
+			"a/file": PathChangeKind_PathChangeKindUpdated,
+		}},
+		{"directory deletion at root", &Change{Old: tD1}, map[string]PathChangeKind{
+			"":     PathChangeKind_PathChangeKindDeleted,
+			"file": PathChangeKind_PathChangeKindDeleted,
+		}},
+		{"directory replaced with file", &Change{Path: "a", Old: tD1, New: tF1}, map[string]PathChangeKind{
+			"a":      PathChangeKind_PathChangeKindUpdated,
+			"a/file": PathChangeKind_PathChangeKindDeleted,
+		}},
+	}
+
+	// Process test cases.
+	for _, testCase := range testCases {
+		// Compute path changes.
+		pathChanges := testCase.change.PathChanges()
+
+		// Verify that the changes are valid and match what's expected.
+		if len(pathChanges) != len(testCase.expected) {
+			t.Errorf("%s: path change count (%d) does not match expected (%d)",
+				testCase.description, len(pathChanges), len(testCase.expected),
+			)
+			continue
+		}
+		for _, pathChange := range pathChanges {
+			if err := pathChange.EnsureValid(); err != nil {
+				t.Errorf("%s: invalid path change: %v", testCase.description, err)
+			} else if kind, ok := testCase.expected[pathChange.Path]; !ok {
+				t.Errorf("%s: unexpected path change at path: %q", testCase.description, pathChange.Path)
+			} else if kind != pathChange.Kind {
+				t.Errorf("%s: path change kind at path %q (%s) does not match expected (%s)",
+					testCase.description, pathChange.Path, pathChange.Kind.Description(), kind.Description(),
+				)
+			}
+		}
+
+		// Verify that deletions precede the modifications that they enable.
+		deletionsFinished := sort.SliceIsSorted(pathChanges, func(i, j int) bool {
+			return pathChanges[i].Kind == PathChangeKind_PathChangeKindDeleted &&
+				pathChanges[j].Kind != PathChangeKind_PathChangeKindDeleted
+		})
+		if !deletionsFinished {
+			t.Errorf("%s: deletions do not precede other modifications", testCase.description)
+		}
+	}
+}
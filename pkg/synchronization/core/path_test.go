@@ -131,3 +131,61 @@ func TestPathLess(t *testing.T) {
 		}
 	}
 }
+
+// TestPathWithinAny tests PathWithinAny.
+func TestPathWithinAny(t *testing.T) {
+	// Set up test cases.
+	testCases := []struct {
+		path     string
+		roots    []string
+		expected bool
+	}{
+		{"", nil, false},
+		{"a", nil, false},
+		{"a", []string{""}, true},
+		{"", []string{""}, true},
+		{"a", []string{"a"}, true},
+		{"a/b", []string{"a"}, true},
+		{"ab", []string{"a"}, false},
+		{"a", []string{"a/b"}, false},
+		{"c/d", []string{"a", "c"}, true},
+		{"e", []string{"a", "c"}, false},
+	}
+
+	// Process test cases.
+	for i, testCase := range testCases {
+		if result := PathWithinAny(testCase.path, testCase.roots); result != testCase.expected {
+			t.Errorf("test index %d: result does not match expected: %t != %t", i, result, testCase.expected)
+		}
+	}
+}
+
+// TestValidatePath verifies that ValidatePath behaves correctly.
+func TestValidatePath(t *testing.T) {
+	// Set up test cases.
+	testCases := []struct {
+		path        string
+		expectValid bool
+	}{
+		{"", true},
+		{"a", true},
+		{"a/b", true},
+		{"a/b.txt", true},
+		{"/a", false},
+		{"a/", false},
+		{"a//b", false},
+		{".", false},
+		{"..", false},
+		{"a/./b", false},
+		{"a/../b", false},
+	}
+
+	// Process test cases.
+	for _, testCase := range testCases {
+		if err := ValidatePath(testCase.path); err != nil && testCase.expectValid {
+			t.Errorf("ValidatePath unexpectedly failed for \"%s\": %v", testCase.path, err)
+		} else if err == nil && !testCase.expectValid {
+			t.Errorf("ValidatePath unexpectedly succeeded for \"%s\"", testCase.path)
+		}
+	}
+}
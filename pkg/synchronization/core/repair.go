@@ -0,0 +1,181 @@
+package core
+
+import (
+	"sort"
+)
+
+// changesAbove returns true if any of the specified changes is rooted strictly
+// above the specified path.
+func changesAbove(path string, changes []*Change) bool {
+	for _, change := range changes {
+		if change.Path != path && pathWithin(path, change.Path) {
+			return true
+		}
+	}
+	return false
+}
+
+// changesOverlap returns true if any of the specified changes is rooted at,
+// above, or beneath the specified path.
+func changesOverlap(path string, changes []*Change) bool {
+	for _, change := range changes {
+		if pathWithin(path, change.Path) || pathWithin(change.Path, path) {
+			return true
+		}
+	}
+	return false
+}
+
+// changesOutside returns the subset of the specified changes that aren't
+// rooted at or beneath the specified path.
+func changesOutside(path string, changes []*Change) []*Change {
+	var result []*Change
+	for _, change := range changes {
+		if !pathWithin(change.Path, path) {
+			result = append(result, change)
+		}
+	}
+	return result
+}
+
+// repairCandidate is a path at which rehashing has revealed that an endpoint's
+// content changed without a corresponding change to its cached metadata.
+type repairCandidate struct {
+	// path is the path at which the corruption was detected.
+	path string
+	// alpha indicates whether or not the corruption is on alpha.
+	alpha bool
+}
+
+// Repair adjusts the output of Reconcile so that content that has been
+// corrupted out-of-band on an endpoint (i.e. modified in a way that didn't
+// change the metadata used to validate cached digests) is restored to its last
+// synchronized (i.e. ancestor) state, rather than having reconciliation
+// propagate the corruption to the other endpoint (or preserve it, depending on
+// the synchronization mode).
+//
+// Corruption is detected by comparing the content of each endpoint from a scan
+// that used cached digests (alphaCached and betaCached) against content from a
+// subsequent scan that re-hashed content at the specified paths (alpha and
+// beta, which must also be the content passed to Reconcile). Any differences
+// at or beneath the specified paths are treated as corruption. Content that
+// differs from the ancestor in both scans (e.g. pending edits) is left for
+// reconciliation to handle as usual.
+//
+// A corrupted path can only be repaired if the cached content at that path
+// matches the ancestor (i.e. no edit is pending there), if the other endpoint
+// hasn't diverged from the ancestor at that path, if the corrupted endpoint has
+// no unsynchronizable content at that path, and if reconciliation hasn't
+// generated any changes rooted above that path. In the unidirectional
+// synchronization modes, only beta can be repaired, since changes on alpha are
+// authoritative. For each repaired path, any ancestor changes and transitions
+// generated by reconciliation at or beneath that path are replaced with the
+// transitions necessary to restore the corrupted endpoint.
+//
+// The specified paths must be valid root-relative paths. This function returns
+// the adjusted ancestor changes, alpha transitions, and beta transitions, as
+// well as the corrupted paths that were repaired and the corrupted paths that
+// couldn't be repaired (both in depth-first order).
+func Repair(
+	paths []string,
+	ancestor, alphaCached, alpha, betaCached, beta *Entry,
+	mode SynchronizationMode,
+	ancestorChanges, alphaTransitions, betaTransitions []*Change,
+) ([]*Change, []*Change, []*Change, []string, []string) {
+	// Sort the paths in depth-first order and exclude any paths that are
+	// duplicates of (or contained within) previous paths, since those will be
+	// handled as part of their containing path.
+	sorted := make([]string, len(paths))
+	copy(sorted, paths)
+	sort.Slice(sorted, func(i, j int) bool {
+		return pathLess(sorted[i], sorted[j])
+	})
+	var roots []string
+	for _, path := range sorted {
+		if len(roots) > 0 && pathWithin(path, roots[len(roots)-1]) {
+			continue
+		}
+		roots = append(roots, path)
+	}
+
+	// Identify corrupted paths on each endpoint by comparing cached and
+	// rehashed content. A path that's corrupted on both endpoints can't be
+	// repaired, since there's no intact copy from which to restore it.
+	var candidates []repairCandidate
+	var unrepairable []string
+	for _, root := range roots {
+		alphaCorrupted := diff(root, alphaCached.lookup(root), alpha.lookup(root))
+		betaCorrupted := diff(root, betaCached.lookup(root), beta.lookup(root))
+		for _, change := range alphaCorrupted {
+			if changesOverlap(change.Path, betaCorrupted) {
+				unrepairable = append(unrepairable, change.Path)
+				continue
+			}
+			candidates = append(candidates, repairCandidate{change.Path, true})
+		}
+		for _, change := range betaCorrupted {
+			if !changesOverlap(change.Path, alphaCorrupted) {
+				candidates = append(candidates, repairCandidate{change.Path, false})
+			}
+		}
+	}
+
+	// Determine whether or not alpha can be repaired.
+	alphaRepairable := mode != SynchronizationMode_SynchronizationModeOneWaySafe &&
+		mode != SynchronizationMode_SynchronizationModeOneWayReplica
+
+	// Process candidates.
+	var repaired []string
+	for _, candidate := range candidates {
+		// Look up the relevant entries.
+		path := candidate.path
+		ancestorEntry := ancestor.lookup(path)
+		var cached, target, other *Entry
+		if candidate.alpha {
+			cached, target, other = alphaCached.lookup(path), alpha.lookup(path), beta.lookup(path)
+		} else {
+			cached, target, other = betaCached.lookup(path), beta.lookup(path), alpha.lookup(path)
+		}
+
+		// Ensure that the corrupted endpoint can be repaired, that there's no
+		// pending edit at this path on either endpoint, that the target
+		// doesn't contain unsynchronizable content (which we wouldn't be able
+		// to remove), and that reconciliation hasn't generated any changes
+		// that would encompass this path.
+		if (candidate.alpha && !alphaRepairable) ||
+			!cached.synchronizable().Equal(ancestorEntry, true) ||
+			!other.synchronizable().Equal(ancestorEntry, true) ||
+			len(diff(path, target.synchronizable(), target)) > 0 ||
+			changesAbove(path, ancestorChanges) ||
+			changesAbove(path, alphaTransitions) ||
+			changesAbove(path, betaTransitions) {
+			unrepairable = append(unrepairable, path)
+			continue
+		}
+
+		// Replace any reconciliation-generated changes at or beneath this path
+		// with the transitions necessary to restore the target.
+		ancestorChanges = changesOutside(path, ancestorChanges)
+		alphaTransitions = changesOutside(path, alphaTransitions)
+		betaTransitions = changesOutside(path, betaTransitions)
+		if candidate.alpha {
+			alphaTransitions = append(alphaTransitions, diff(path, target, ancestorEntry)...)
+		} else {
+			betaTransitions = append(betaTransitions, diff(path, target, ancestorEntry)...)
+		}
+
+		// Record the repair.
+		repaired = append(repaired, path)
+	}
+
+	// Sort the results in depth-first order.
+	sort.Slice(repaired, func(i, j int) bool {
+		return pathLess(repaired[i], repaired[j])
+	})
+	sort.Slice(unrepairable, func(i, j int) bool {
+		return pathLess(unrepairable[i], unrepairable[j])
+	})
+
+	// Done.
+	return ancestorChanges, alphaTransitions, betaTransitions, repaired, unrepairable
+}
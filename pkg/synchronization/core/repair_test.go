@@ -0,0 +1,192 @@
+package core
+
+import (
+	"testing"
+)
+
+// TestRepair tests Repair.
+func TestRepair(t *testing.T) {
+	// Create entries with content at multiple paths.
+	twoFiles := func(file, other *Entry) *Entry {
+		return &Entry{Contents: map[string]*Entry{"file": file, "other": other}}
+	}
+
+	// Define test cases.
+	tests := []struct {
+		description          string
+		paths                []string
+		ancestor             *Entry
+		alphaCached          *Entry
+		alpha                *Entry
+		betaCached           *Entry
+		beta                 *Entry
+		mode                 SynchronizationMode
+		expectedAlphaChanges []*Change
+		expectedBetaChanges  []*Change
+		expectedRepaired     []string
+		expectedUnrepairable []string
+	}{
+		{
+			description: "intact content",
+			paths:       []string{""},
+			ancestor:    tD1,
+			alphaCached: tD1,
+			alpha:       tD1,
+			betaCached:  tD1,
+			beta:        tD1,
+			mode:        SynchronizationMode_SynchronizationModeTwoWaySafe,
+		},
+		{
+			description:         "corrupted beta file",
+			paths:               []string{"file"},
+			ancestor:            tD1,
+			alphaCached:         tD1,
+			alpha:               tD1,
+			betaCached:          tD1,
+			beta:                tD2,
+			mode:                SynchronizationMode_SynchronizationModeTwoWaySafe,
+			expectedBetaChanges: []*Change{{Path: "file", Old: tF2, New: tF1}},
+			expectedRepaired:    []string{"file"},
+		},
+		{
+			description: "corrupted beta file repaired from root with pending alpha edit",
+			paths:       []string{"file", "", "file"},
+			ancestor:    twoFiles(tF1, tF1),
+			alphaCached: twoFiles(tF1, tF2),
+			alpha:       twoFiles(tF1, tF2),
+			betaCached:  twoFiles(tF1, tF1),
+			beta:        twoFiles(tF2, tF1),
+			mode:        SynchronizationMode_SynchronizationModeOneWaySafe,
+			expectedBetaChanges: []*Change{
+				{Path: "file", Old: tF2, New: tF1},
+				{Path: "other", Old: tF1, New: tF2},
+			},
+			expectedRepaired: []string{"file"},
+		},
+		{
+			description:          "pending beta edit at root",
+			paths:                []string{""},
+			ancestor:             tD1,
+			alphaCached:          tD1,
+			alpha:                tD1,
+			betaCached:           tD2,
+			beta:                 tD2,
+			mode:                 SynchronizationMode_SynchronizationModeTwoWaySafe,
+			expectedAlphaChanges: []*Change{{Path: "file", Old: tF1, New: tF2}},
+		},
+		{
+			description:          "corrupted alpha file",
+			paths:                []string{"file"},
+			ancestor:             tD1,
+			alphaCached:          tD1,
+			alpha:                tD2,
+			betaCached:           tD1,
+			beta:                 tD1,
+			mode:                 SynchronizationMode_SynchronizationModeTwoWayResolved,
+			expectedAlphaChanges: []*Change{{Path: "file", Old: tF2, New: tF1}},
+			expectedRepaired:     []string{"file"},
+		},
+		{
+			description:          "corrupted alpha file in unidirectional mode",
+			paths:                []string{"file"},
+			ancestor:             tD1,
+			alphaCached:          tD1,
+			alpha:                tD2,
+			betaCached:           tD1,
+			beta:                 tD1,
+			mode:                 SynchronizationMode_SynchronizationModeOneWayReplica,
+			expectedBetaChanges:  []*Change{{Path: "file", Old: tF1, New: tF2}},
+			expectedUnrepairable: []string{"file"},
+		},
+		{
+			description:          "both endpoints corrupted",
+			paths:                []string{"file"},
+			ancestor:             tD1,
+			alphaCached:          tD1,
+			alpha:                tD2,
+			betaCached:           tD1,
+			beta:                 tD3,
+			mode:                 SynchronizationMode_SynchronizationModeTwoWayResolved,
+			expectedBetaChanges:  []*Change{{Path: "file", Old: tF3, New: tF2}},
+			expectedUnrepairable: []string{"file"},
+		},
+		{
+			description:          "corrupted beta file with pending beta edit",
+			paths:                []string{""},
+			ancestor:             tD1,
+			alphaCached:          tD1,
+			alpha:                tD1,
+			betaCached:           tD2,
+			beta:                 tD3,
+			mode:                 SynchronizationMode_SynchronizationModeTwoWaySafe,
+			expectedAlphaChanges: []*Change{{Path: "file", Old: tF1, New: tF3}},
+			expectedUnrepairable: []string{"file"},
+		},
+		{
+			description:          "corrupted beta file with pending alpha edit",
+			paths:                []string{"file"},
+			ancestor:             tD1,
+			alphaCached:          tD2,
+			alpha:                tD2,
+			betaCached:           tD1,
+			beta:                 tD3,
+			mode:                 SynchronizationMode_SynchronizationModeTwoWaySafe,
+			expectedUnrepairable: []string{"file"},
+		},
+		{
+			description:          "corruption outside repair path",
+			paths:                []string{"other"},
+			ancestor:             twoFiles(tF1, tF1),
+			alphaCached:          twoFiles(tF1, tF1),
+			alpha:                twoFiles(tF1, tF1),
+			betaCached:           twoFiles(tF1, tF1),
+			beta:                 twoFiles(tF2, tF1),
+			mode:                 SynchronizationMode_SynchronizationModeTwoWaySafe,
+			expectedAlphaChanges: []*Change{{Path: "file", Old: tF1, New: tF2}},
+		},
+	}
+
+	// Process test cases.
+	for _, test := range tests {
+		// Perform reconciliation and repair.
+		ancestorChanges, alphaChanges, betaChanges, _ := Reconcile(test.ancestor, test.alpha, test.beta, test.mode)
+		_, alphaChanges, betaChanges, repaired, unrepairable := Repair(
+			test.paths,
+			test.ancestor, test.alphaCached, test.alpha, test.betaCached, test.beta,
+			test.mode,
+			ancestorChanges, alphaChanges, betaChanges,
+		)
+
+		// Verify results.
+		if !testingChangeListsEqual(alphaChanges, test.expectedAlphaChanges) {
+			t.Errorf("%s: alpha changes do not match expected", test.description)
+		}
+		if !testingChangeListsEqual(betaChanges, test.expectedBetaChanges) {
+			t.Errorf("%s: beta changes do not match expected", test.description)
+		}
+		if !testingStringListsEqual(repaired, test.expectedRepaired) {
+			t.Errorf("%s: repaired paths (%v) do not match expected (%v)",
+				test.description, repaired, test.expectedRepaired,
+			)
+		}
+		if !testingStringListsEqual(unrepairable, test.expectedUnrepairable) {
+			t.Errorf("%s: unrepairable paths (%v) do not match expected (%v)",
+				test.description, unrepairable, test.expectedUnrepairable,
+			)
+		}
+	}
+}
+
+// testingStringListsEqual determines whether or not two string lists are
+// equal, treating nil and empty lists as equivalent.
+func testingStringListsEqual(actual, expected []string) bool {
+	if len(actual) != len(expected) {
+		return false
+	}
+	for i, value := range actual {
+		if value != expected[i] {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,72 @@
+package core
+
+import (
+	"fmt"
+)
+
+// IsDefault indicates whether or not the replacement mode is
+// ReplacementMode_ReplacementModeDefault.
+func (m ReplacementMode) IsDefault() bool {
+	return m == ReplacementMode_ReplacementModeDefault
+}
+
+// MarshalText implements encoding.TextMarshaler.MarshalText.
+func (m ReplacementMode) MarshalText() ([]byte, error) {
+	var result string
+	switch m {
+	case ReplacementMode_ReplacementModeDefault:
+	case ReplacementMode_ReplacementModeRename:
+		result = "rename"
+	case ReplacementMode_ReplacementModeAtomic:
+		result = "atomic"
+	default:
+		result = "unknown"
+	}
+	return []byte(result), nil
+}
+
+// UnmarshalText implements encoding.TextUnmarshaler.UnmarshalText.
+func (m *ReplacementMode) UnmarshalText(textBytes []byte) error {
+	// Convert the bytes to a string.
+	text := string(textBytes)
+
+	// Convert to a replacement mode.
+	switch text {
+	case "rename":
+		*m = ReplacementMode_ReplacementModeRename
+	case "atomic":
+		*m = ReplacementMode_ReplacementModeAtomic
+	default:
+		return fmt.Errorf("unknown replacement mode specification: %s", text)
+	}
+
+	// Success.
+	return nil
+}
+
+// Supported indicates whether or not a particular replacement mode is a valid,
+// non-default value.
+func (m ReplacementMode) Supported() bool {
+	switch m {
+	case ReplacementMode_ReplacementModeRename:
+		return true
+	case ReplacementMode_ReplacementModeAtomic:
+		return true
+	default:
+		return false
+	}
+}
+
+// Description returns a human-readable description of a replacement mode.
+func (m ReplacementMode) Description() string {
+	switch m {
+	case ReplacementMode_ReplacementModeDefault:
+		return "Default"
+	case ReplacementMode_ReplacementModeRename:
+		return "Rename"
+	case ReplacementMode_ReplacementModeAtomic:
+		return "Atomic"
+	default:
+		return "Unknown"
+	}
+}
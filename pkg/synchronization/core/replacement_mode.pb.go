@@ -0,0 +1,156 @@
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// versions:
+// 	protoc-gen-go v1.28.0
+// 	protoc        v3.19.4
+// source: synchronization/core/replacement_mode.proto
+
+package core
+
+import (
+	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
+	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	reflect "reflect"
+	sync "sync"
+)
+
+const (
+	// Verify that this generated code is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(20 - protoimpl.MinVersion)
+	// Verify that runtime/protoimpl is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
+)
+
+// ReplacementMode specifies the mode for moving new file contents into place
+// during transitions.
+type ReplacementMode int32
+
+const (
+	// ReplacementMode_ReplacementModeDefault represents an unspecified
+	// replacement mode. It is not valid for use with Transition. It should be
+	// converted to one of the following values based on the desired default
+	// behavior.
+	ReplacementMode_ReplacementModeDefault ReplacementMode = 0
+	// ReplacementMode_ReplacementModeRename specifies that staged files should
+	// be renamed directly into place, falling back to an intermediate
+	// temporary file in the target directory only if a direct rename isn't
+	// possible (e.g. due to the staging directory residing on a different
+	// device).
+	ReplacementMode_ReplacementModeRename ReplacementMode = 1
+	// ReplacementMode_ReplacementModeAtomic specifies that staged files should
+	// be flushed to disk and then renamed directly into place. If a direct
+	// rename isn't possible (e.g. due to the staging directory residing on a
+	// different device), then contents are written to a temporary file in the
+	// target directory, flushed to disk, and renamed over the target. This
+	// ensures that processes watching the target directory only ever observe
+	// a single rename of a fully written file, even across system crashes.
+	ReplacementMode_ReplacementModeAtomic ReplacementMode = 2
+)
+
+// Enum value maps for ReplacementMode.
+var (
+	ReplacementMode_name = map[int32]string{
+		0: "ReplacementModeDefault",
+		1: "ReplacementModeRename",
+		2: "ReplacementModeAtomic",
+	}
+	ReplacementMode_value = map[string]int32{
+		"ReplacementModeDefault": 0,
+		"ReplacementModeRename":  1,
+		"ReplacementModeAtomic":  2,
+	}
+)
+
+func (x ReplacementMode) Enum() *ReplacementMode {
+	p := new(ReplacementMode)
+	*p = x
+	return p
+}
+
+func (x ReplacementMode) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (ReplacementMode) Descriptor() protoreflect.EnumDescriptor {
+	return file_synchronization_core_replacement_mode_proto_enumTypes[0].Descriptor()
+}
+
+func (ReplacementMode) Type() protoreflect.EnumType {
+	return &file_synchronization_core_replacement_mode_proto_enumTypes[0]
+}
+
+func (x ReplacementMode) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use ReplacementMode.Descriptor instead.
+func (ReplacementMode) EnumDescriptor() ([]byte, []int) {
+	return file_synchronization_core_replacement_mode_proto_rawDescGZIP(), []int{0}
+}
+
+var File_synchronization_core_replacement_mode_proto protoreflect.FileDescriptor
+
+var file_synchronization_core_replacement_mode_proto_rawDesc = []byte{
+	0x0a, 0x2b, 0x73, 0x79, 0x6e, 0x63, 0x68, 0x72, 0x6f, 0x6e, 0x69, 0x7a, 0x61, 0x74, 0x69, 0x6f,
+	0x6e, 0x2f, 0x63, 0x6f, 0x72, 0x65, 0x2f, 0x72, 0x65, 0x70, 0x6c, 0x61, 0x63, 0x65, 0x6d, 0x65,
+	0x6e, 0x74, 0x5f, 0x6d, 0x6f, 0x64, 0x65, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x12, 0x04, 0x63,
+	0x6f, 0x72, 0x65, 0x2a, 0x63, 0x0a, 0x0f, 0x52, 0x65, 0x70, 0x6c, 0x61, 0x63, 0x65, 0x6d, 0x65,
+	0x6e, 0x74, 0x4d, 0x6f, 0x64, 0x65, 0x12, 0x1a, 0x0a, 0x16, 0x52, 0x65, 0x70, 0x6c, 0x61, 0x63,
+	0x65, 0x6d, 0x65, 0x6e, 0x74, 0x4d, 0x6f, 0x64, 0x65, 0x44, 0x65, 0x66, 0x61, 0x75, 0x6c, 0x74,
+	0x10, 0x00, 0x12, 0x19, 0x0a, 0x15, 0x52, 0x65, 0x70, 0x6c, 0x61, 0x63, 0x65, 0x6d, 0x65, 0x6e,
+	0x74, 0x4d, 0x6f, 0x64, 0x65, 0x52, 0x65, 0x6e, 0x61, 0x6d, 0x65, 0x10, 0x01, 0x12, 0x19, 0x0a,
+	0x15, 0x52, 0x65, 0x70, 0x6c, 0x61, 0x63, 0x65, 0x6d, 0x65, 0x6e, 0x74, 0x4d, 0x6f, 0x64, 0x65,
+	0x41, 0x74, 0x6f, 0x6d, 0x69, 0x63, 0x10, 0x02, 0x42, 0x38, 0x5a, 0x36, 0x67, 0x69, 0x74, 0x68,
+	0x75, 0x62, 0x2e, 0x63, 0x6f, 0x6d, 0x2f, 0x6d, 0x75, 0x74, 0x61, 0x67, 0x65, 0x6e, 0x2d, 0x69,
+	0x6f, 0x2f, 0x6d, 0x75, 0x74, 0x61, 0x67, 0x65, 0x6e, 0x2f, 0x70, 0x6b, 0x67, 0x2f, 0x73, 0x79,
+	0x6e, 0x63, 0x68, 0x72, 0x6f, 0x6e, 0x69, 0x7a, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x2f, 0x63, 0x6f,
+	0x72, 0x65, 0x62, 0x06, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x33,
+}
+
+var (
+	file_synchronization_core_replacement_mode_proto_rawDescOnce sync.Once
+	file_synchronization_core_replacement_mode_proto_rawDescData = file_synchronization_core_replacement_mode_proto_rawDesc
+)
+
+func file_synchronization_core_replacement_mode_proto_rawDescGZIP() []byte {
+	file_synchronization_core_replacement_mode_proto_rawDescOnce.Do(func() {
+		file_synchronization_core_replacement_mode_proto_rawDescData = protoimpl.X.CompressGZIP(file_synchronization_core_replacement_mode_proto_rawDescData)
+	})
+	return file_synchronization_core_replacement_mode_proto_rawDescData
+}
+
+var file_synchronization_core_replacement_mode_proto_enumTypes = make([]protoimpl.EnumInfo, 1)
+var file_synchronization_core_replacement_mode_proto_goTypes = []interface{}{
+	(ReplacementMode)(0), // 0: core.ReplacementMode
+}
+var file_synchronization_core_replacement_mode_proto_depIdxs = []int32{
+	0, // [0:0] is the sub-list for method output_type
+	0, // [0:0] is the sub-list for method input_type
+	0, // [0:0] is the sub-list for extension type_name
+	0, // [0:0] is the sub-list for extension extendee
+	0, // [0:0] is the sub-list for field type_name
+}
+
+func init() { file_synchronization_core_replacement_mode_proto_init() }
+func file_synchronization_core_replacement_mode_proto_init() {
+	if File_synchronization_core_replacement_mode_proto != nil {
+		return
+	}
+	type x struct{}
+	out := protoimpl.TypeBuilder{
+		File: protoimpl.DescBuilder{
+			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
+			RawDescriptor: file_synchronization_core_replacement_mode_proto_rawDesc,
+			NumEnums:      1,
+			NumMessages:   0,
+			NumExtensions: 0,
+			NumServices:   0,
+		},
+		GoTypes:           file_synchronization_core_replacement_mode_proto_goTypes,
+		DependencyIndexes: file_synchronization_core_replacement_mode_proto_depIdxs,
+		EnumInfos:         file_synchronization_core_replacement_mode_proto_enumTypes,
+	}.Build()
+	File_synchronization_core_replacement_mode_proto = out.File
+	file_synchronization_core_replacement_mode_proto_rawDesc = nil
+	file_synchronization_core_replacement_mode_proto_goTypes = nil
+	file_synchronization_core_replacement_mode_proto_depIdxs = nil
+}
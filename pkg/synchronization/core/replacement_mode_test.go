@@ -0,0 +1,112 @@
+package core
+
+import (
+	"testing"
+)
+
+// TestReplacementModeIsDefault tests ReplacementMode.IsDefault.
+func TestReplacementModeIsDefault(t *testing.T) {
+	// Define test cases.
+	tests := []struct {
+		value    ReplacementMode
+		expected bool
+	}{
+		{ReplacementMode_ReplacementModeDefault - 1, false},
+		{ReplacementMode_ReplacementModeDefault, true},
+		{ReplacementMode_ReplacementModeRename, false},
+		{ReplacementMode_ReplacementModeAtomic, false},
+		{ReplacementMode_ReplacementModeAtomic + 1, false},
+	}
+
+	// Process test cases.
+	for i, test := range tests {
+		if result := test.value.IsDefault(); result && !test.expected {
+			t.Errorf("test index %d: value was unexpectedly classified as default", i)
+		} else if !result && test.expected {
+			t.Errorf("test index %d: value was unexpectedly classified as non-default", i)
+		}
+	}
+}
+
+// TestReplacementModeUnmarshalText tests ReplacementMode.UnmarshalText.
+func TestReplacementModeUnmarshalText(t *testing.T) {
+	// Define test cases.
+	tests := []struct {
+		text          string
+		expectedMode  ReplacementMode
+		expectFailure bool
+	}{
+		{"", ReplacementMode_ReplacementModeDefault, true},
+		{"asdf", ReplacementMode_ReplacementModeDefault, true},
+		{"rename", ReplacementMode_ReplacementModeRename, false},
+		{"atomic", ReplacementMode_ReplacementModeAtomic, false},
+	}
+
+	// Process test cases.
+	for _, test := range tests {
+		var mode ReplacementMode
+		if err := mode.UnmarshalText([]byte(test.text)); err != nil {
+			if !test.expectFailure {
+				t.Errorf("unable to unmarshal text (%s): %s", test.text, err)
+			}
+		} else if test.expectFailure {
+			t.Error("unmarshaling succeeded unexpectedly for text:", test.text)
+		} else if mode != test.expectedMode {
+			t.Errorf(
+				"unmarshaled mode (%s) does not match expected (%s)",
+				mode,
+				test.expectedMode,
+			)
+		}
+	}
+}
+
+// TestReplacementModeSupported tests ReplacementMode.Supported.
+func TestReplacementModeSupported(t *testing.T) {
+	// Set up test cases.
+	testCases := []struct {
+		mode            ReplacementMode
+		expectSupported bool
+	}{
+		{ReplacementMode_ReplacementModeDefault, false},
+		{ReplacementMode_ReplacementModeRename, true},
+		{ReplacementMode_ReplacementModeAtomic, true},
+		{(ReplacementMode_ReplacementModeAtomic + 1), false},
+	}
+
+	// Process test cases.
+	for _, testCase := range testCases {
+		if supported := testCase.mode.Supported(); supported != testCase.expectSupported {
+			t.Errorf(
+				"mode support status (%t) does not match expected (%t)",
+				supported,
+				testCase.expectSupported,
+			)
+		}
+	}
+}
+
+// TestReplacementModeDescription tests ReplacementMode.Description.
+func TestReplacementModeDescription(t *testing.T) {
+	// Set up test cases.
+	testCases := []struct {
+		mode                ReplacementMode
+		expectedDescription string
+	}{
+		{ReplacementMode_ReplacementModeDefault, "Default"},
+		{ReplacementMode_ReplacementModeRename, "Rename"},
+		{ReplacementMode_ReplacementModeAtomic, "Atomic"},
+		{(ReplacementMode_ReplacementModeAtomic + 1), "Unknown"},
+	}
+
+	// Process test cases.
+	for _, testCase := range testCases {
+		if description := testCase.mode.Description(); description != testCase.expectedDescription {
+			t.Errorf(
+				"mode description (%s) does not match expected (%s)",
+				description,
+				testCase.expectedDescription,
+			)
+		}
+	}
+}
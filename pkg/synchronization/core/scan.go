@@ -11,6 +11,7 @@ import (
 	"runtime"
 	"strings"
 	"sync"
+	"time"
 
 	"golang.org/x/text/unicode/norm"
 
@@ -89,7 +90,37 @@ func init() {
 	behaviorCache.decomposesUnicode = make(map[uint64]bool)
 }
 
-// scanner provides the recursive implementation of scanning.
+// scanWorker encapsulates the per-Goroutine state required to compute file
+// digests during a scan.
+type scanWorker struct {
+	// hasher is the hashing function to use for computing file digests.
+	hasher hash.Hash
+	// copyBuffer is the copy buffer used for computing file digests.
+	copyBuffer []byte
+}
+
+// newScanWorker creates a new scan worker using the specified hasher.
+func newScanWorker(hasher hash.Hash) *scanWorker {
+	return &scanWorker{
+		hasher:     hasher,
+		copyBuffer: make([]byte, scannerCopyBufferSize),
+	}
+}
+
+// scanTask tracks a directory content entry that's being processed
+// asynchronously by an auxiliary scan worker.
+type scanTask struct {
+	// name is the content name.
+	name string
+	// entry is the resulting entry.
+	entry *Entry
+	// err is the resulting error.
+	err error
+}
+
+// scanner provides the recursive implementation of scanning. Its recursive
+// methods may be invoked concurrently by auxiliary scan workers, so any state
+// that's modified during scanning must be accessed while holding lock.
 type scanner struct {
 	// cancelled is the cancellation channel from the scan context.
 	cancelled <-chan struct{}
@@ -98,8 +129,11 @@ type scanner struct {
 	// dirtyPaths is the set of tainted paths for which a baseline snapshot
 	// can't be trusted.
 	dirtyPaths map[string]bool
-	// hasher is the hashing function to use for computing file digests.
-	hasher hash.Hash
+	// workers is the pool of idle auxiliary scan workers. Content is only
+	// dispatched to an auxiliary worker if one is immediately available,
+	// otherwise it's processed by the Goroutine that encountered it, which
+	// ensures that scanning can't deadlock.
+	workers chan *scanWorker
 	// cache is the existing cache to use for fast digest lookups.
 	cache *Cache
 	// ignorer is the ignorer identifying ignored paths.
@@ -108,21 +142,33 @@ type scanner struct {
 	ignoreCache IgnoreCache
 	// symbolicLinkMode is the symbolic link mode being used.
 	symbolicLinkMode SymbolicLinkMode
+	// realRoot is the synchronization root path with all symbolic links
+	// resolved. It is only set when following symbolic links.
+	realRoot string
+	// lock guards newCache, newIgnoreCache, followed, directories, files,
+	// symbolicLinks, and totalFileSize.
+	lock sync.Mutex
+	// followed maps the paths of followed directory symbolic links to their
+	// resolved targets. It is only used when following symbolic links.
+	followed map[string]string
 	// newCache is the new file digest cache to populate.
 	newCache *Cache
 	// newIgnoreCache is the new ignored path behavior cache to populate.
 	newIgnoreCache IgnoreCache
-	// copyBuffer is the copy buffer used for computing file digests.
-	copyBuffer []byte
 	// deviceID is the device ID of the synchronization root filesystem.
 	deviceID uint64
 	// recomposeUnicode indicates whether or not filenames need to be recomposed
 	// due to Unicode decomposition behavior on the synchronization root
 	// filesystem.
 	recomposeUnicode bool
+	// unicodeNormalizationMode is the Unicode normalization mode being used.
+	unicodeNormalizationMode UnicodeNormalizationMode
 	// preservesExecutability indicates whether or not the synchronization root
 	// filesystem preserves POSIX executability bits.
 	preservesExecutability bool
+	// modificationTimeTolerance is the tolerance used when comparing file
+	// modification times against those in the cache.
+	modificationTimeTolerance time.Duration
 	// directories is the number of synchronizable directories encountered.
 	directories uint64
 	// files is the number of synchronizable files encountered.
@@ -141,6 +187,7 @@ type scanner struct {
 // is provided and this function is responsible for opening and closing the file
 // as necessary.
 func (s *scanner) file(
+	worker *scanWorker,
 	path string,
 	parent *filesystem.Directory,
 	metadata *filesystem.Metadata,
@@ -162,7 +209,7 @@ func (s *scanner) file(
 	// detected during transition operations (where the cache is also used).
 	cacheContentMatch := cacheHit &&
 		(metadata.Mode&filesystem.ModeTypeMask) == (filesystem.Mode(cached.Mode)&filesystem.ModeTypeMask) &&
-		metadata.ModificationTime.Equal(cached.ModificationTime.AsTime()) &&
+		modificationTimesMatch(metadata.ModificationTime, cached.ModificationTime, s.modificationTimeTolerance) &&
 		metadata.Size == cached.Size &&
 		metadata.FileID == cached.FileID
 	cacheEntryReusable := cacheContentMatch &&
@@ -193,13 +240,13 @@ func (s *scanner) file(
 		}
 
 		// Reset the hash state.
-		s.hasher.Reset()
+		worker.hasher.Reset()
 
 		// Copy data into the hash and verify that we copied the amount
 		// expected. We use a preemptable wrapper around the hasher to enable
 		// timely cancellation.
-		preemptableHasher := stream.NewPreemptableWriter(s.hasher, s.cancelled, scannerCopyPreemptionInterval)
-		if copied, err := io.CopyBuffer(preemptableHasher, file, s.copyBuffer); err != nil {
+		preemptableHasher := stream.NewPreemptableWriter(worker.hasher, s.cancelled, scannerCopyPreemptionInterval)
+		if copied, err := io.CopyBuffer(preemptableHasher, file, worker.copyBuffer); err != nil {
 			if err == stream.ErrWritePreempted {
 				return nil, ErrScanCancelled
 			}
@@ -215,13 +262,12 @@ func (s *scanner) file(
 		}
 
 		// Compute the digest.
-		digest = s.hasher.Sum(nil)
+		digest = worker.hasher.Sum(nil)
 	}
 
-	// Add an entry to the new cache.
-	if cacheEntryReusable {
-		s.newCache.Entries[path] = cached
-	} else {
+	// Compute the new cache entry.
+	newCacheEntry := cached
+	if !cacheEntryReusable {
 		// Convert the new modification time to Protocol Buffers format.
 		modificationTime := timestamppb.New(metadata.ModificationTime)
 		if err := modificationTime.CheckValid(); err != nil {
@@ -232,7 +278,7 @@ func (s *scanner) file(
 		}
 
 		// Create the new cache entry.
-		s.newCache.Entries[path] = &CacheEntry{
+		newCacheEntry = &CacheEntry{
 			Mode:             uint32(metadata.Mode),
 			ModificationTime: modificationTime,
 			Size:             metadata.Size,
@@ -241,9 +287,13 @@ func (s *scanner) file(
 		}
 	}
 
-	// Increment the total file count and size.
+	// Add the entry to the new cache and increment the total file count and
+	// size.
+	s.lock.Lock()
+	s.newCache.Entries[path] = newCacheEntry
 	s.files++
 	s.totalFileSize += metadata.Size
+	s.lock.Unlock()
 
 	// Success.
 	return &Entry{
@@ -272,6 +322,12 @@ func (s *scanner) symbolicLink(
 		}, nil
 	}
 
+	// If we're rewriting absolute symbolic links, then convert absolute targets
+	// inside the synchronization root to relative targets.
+	if s.symbolicLinkMode.rewritesAbsolute() {
+		target = rewriteAbsoluteSymbolicLink(s.root, path, target)
+	}
+
 	// If requested, enforce that the link is portable, otherwise just ensure
 	// that it's non-empty (this is required even in POSIX raw mode).
 	if enforcePortable {
@@ -290,7 +346,9 @@ func (s *scanner) symbolicLink(
 	}
 
 	// Increment the total symbolic link count.
+	s.lock.Lock()
 	s.symbolicLinks++
+	s.lock.Unlock()
 
 	// Success.
 	return &Entry{
@@ -299,6 +357,71 @@ func (s *scanner) symbolicLink(
 	}, nil
 }
 
+// realPath computes the on-disk location of the directory at the specified
+// path, taking followed directory symbolic links into account. It must be
+// called with the scanner lock held.
+func (s *scanner) realPath(path string) string {
+	for ancestor := path; ; ancestor = pathDir(ancestor) {
+		if target, ok := s.followed[ancestor]; ok {
+			return filepath.Join(target, filepath.FromSlash(strings.TrimPrefix(path[len(ancestor):], "/")))
+		} else if ancestor == "" {
+			return filepath.Join(s.realRoot, filepath.FromSlash(path))
+		}
+	}
+}
+
+// followSymbolicLink resolves and opens the target of a symbolic link entry
+// when following symbolic links. It returns the opened target (either a
+// *filesystem.Directory or an io.ReadSeekCloser) and its metadata. If the link
+// can't be followed, then a problematic entry is returned instead. The caller
+// is responsible for closing the target.
+func (s *scanner) followSymbolicLink(path string) (io.Closer, *filesystem.Metadata, *Entry) {
+	// Resolve the link target.
+	target, err := filepath.EvalSymlinks(filepath.Join(s.root, filepath.FromSlash(path)))
+	if err != nil {
+		return nil, nil, &Entry{
+			Kind:    EntryKind_Problematic,
+			Problem: fmt.Errorf("unable to resolve symbolic link: %w", err).Error(),
+		}
+	}
+
+	// Open the target.
+	object, metadata, err := filesystem.Open(target, false)
+	if err != nil {
+		return nil, nil, &Entry{
+			Kind:    EntryKind_Problematic,
+			Problem: fmt.Errorf("unable to open symbolic link target: %w", err).Error(),
+		}
+	}
+
+	// If the target is a directory, then ensure that following it wouldn't
+	// create a loop, i.e. that the target isn't the same as or a parent of any
+	// directory through which we reached the link, and record it as followed.
+	if (metadata.Mode & filesystem.ModeTypeMask) == filesystem.ModeTypeDirectory {
+		targetPrefix := target
+		if !strings.HasSuffix(targetPrefix, string(filepath.Separator)) {
+			targetPrefix += string(filepath.Separator)
+		}
+		s.lock.Lock()
+		for ancestor := path; ancestor != ""; {
+			ancestor = pathDir(ancestor)
+			if real := s.realPath(ancestor); real == target || strings.HasPrefix(real, targetPrefix) {
+				s.lock.Unlock()
+				object.Close()
+				return nil, nil, &Entry{
+					Kind:    EntryKind_Problematic,
+					Problem: "symbolic link loop detected",
+				}
+			}
+		}
+		s.followed[path] = target
+		s.lock.Unlock()
+	}
+
+	// Success.
+	return object, metadata, nil
+}
+
 // directory performs processing of a directory entry. Exactly one of parent or
 // directory will be non-nil, depending on whether or not the path represents
 // the synchronization root. If the path represents the synchronization root,
@@ -307,6 +430,7 @@ func (s *scanner) symbolicLink(
 // the path is provided and this function is responsible for opening and closing
 // the directory as necessary.
 func (s *scanner) directory(
+	worker *scanWorker,
 	path string,
 	parent *filesystem.Directory,
 	metadata *filesystem.Metadata,
@@ -344,6 +468,13 @@ func (s *scanner) directory(
 		}
 	}
 
+	// Track content being processed by auxiliary workers. We defer a wait for
+	// their completion (which will run before the directory is closed) to
+	// ensure that they're done using the directory on all return paths.
+	var tasks []*scanTask
+	tasksDone := &sync.WaitGroup{}
+	defer tasksDone.Wait()
+
 	// Read directory contents.
 	directoryContents, err := directory.ReadContents()
 	if err != nil {
@@ -370,9 +501,31 @@ func (s *scanner) directory(
 		contentPathPrefix = pathJoinable(path)
 	}
 
+	// Compute the names under which content will be recorded, recomposing and
+	// normalizing Unicode as necessary, and count the number of on-disk names
+	// that map to each of them. We do this before processing any content so
+	// that collision detection doesn't depend on the order in which contents
+	// are listed. Intermediate temporary files are assigned an empty name,
+	// because we avoid recording them (even as untracked entries) since we know
+	// that they're ephemeral.
+	contentNames := make([]string, len(directoryContents))
+	contentNameCounts := make(map[string]int, len(directoryContents))
+	for i, contentMetadata := range directoryContents {
+		contentName := contentMetadata.Name
+		if strings.HasPrefix(contentName, filesystem.TemporaryNamePrefix) {
+			continue
+		}
+		if s.recomposeUnicode {
+			contentName = norm.NFC.String(contentName)
+		}
+		contentName = s.unicodeNormalizationMode.normalize(contentName)
+		contentNames[i] = contentName
+		contentNameCounts[contentName]++
+	}
+
 	// Compute entries.
 	contents := make(map[string]*Entry, len(directoryContents))
-	for _, contentMetadata := range directoryContents {
+	for i, contentMetadata := range directoryContents {
 		// Check for cancellation.
 		select {
 		case <-s.cancelled:
@@ -380,24 +533,40 @@ func (s *scanner) directory(
 		default:
 		}
 
-		// Extract the content name.
-		contentName := contentMetadata.Name
-
-		// If this is an intermediate temporary file, then ignore it. We avoid
-		// recording these files, even as untracked entries, because we know
-		// that they're ephemeral.
-		if strings.HasPrefix(contentName, filesystem.TemporaryNamePrefix) {
+		// Extract the content name, skipping intermediate temporary files.
+		contentName := contentNames[i]
+		if contentName == "" {
 			continue
 		}
 
-		// Recompose Unicode in the content name if necessary.
-		if s.recomposeUnicode {
-			contentName = norm.NFC.String(contentName)
+		// If multiple names in the directory normalize to this name, then we
+		// can't represent them on disk with a single name, so we mark the
+		// content as problematic without processing any of them.
+		if contentNameCounts[contentName] > 1 {
+			contents[contentName] = &Entry{
+				Kind:    EntryKind_Problematic,
+				Problem: "multiple names with equivalent Unicode normalization",
+			}
+			continue
 		}
 
 		// Compute the content path.
 		contentPath := contentPathPrefix + contentName
 
+		// If this is a symbolic link and we're following symbolic links, then
+		// resolve and open its target and process the content as the target.
+		// The target content is processed synchronously.
+		var followedTarget io.Closer
+		if s.symbolicLinkMode == SymbolicLinkMode_SymbolicLinkModeFollow &&
+			(contentMetadata.Mode&filesystem.ModeTypeMask) == filesystem.ModeTypeSymbolicLink {
+			target, targetMetadata, problematic := s.followSymbolicLink(contentPath)
+			if problematic != nil {
+				contents[contentName] = problematic
+				continue
+			}
+			followedTarget, contentMetadata = target, targetMetadata
+		}
+
 		// Compute the kind for this content, recording an untracked entry if
 		// the content type isn't supported.
 		var contentKind EntryKind
@@ -421,12 +590,41 @@ func (s *scanner) directory(
 		if !ok {
 			ignored = s.ignorer.ignored(contentPath, contentIsDirectory)
 		}
+		s.lock.Lock()
 		s.newIgnoreCache[ignoreCacheKey] = ignored
+		s.lock.Unlock()
 		if ignored {
+			if followedTarget != nil {
+				followedTarget.Close()
+			}
 			contents[contentName] = &Entry{Kind: EntryKind_Untracked}
 			continue
 		}
 
+		// If we're processing the target of a followed symbolic link, then do
+		// so directly using the opened target. We don't use a baseline for
+		// followed directories since their contents aren't monitored.
+		if followedTarget != nil {
+			var entry *Entry
+			var err error
+			if contentKind == EntryKind_File {
+				entry, err = s.file(worker, contentPath, nil, contentMetadata, followedTarget.(io.ReadSeekCloser))
+			} else if contentKind == EntryKind_Directory {
+				entry, err = s.directory(worker, contentPath, nil, contentMetadata, followedTarget.(*filesystem.Directory), nil)
+			} else {
+				panic("unhandled followed entry kind")
+			}
+			followedTarget.Close()
+			if err != nil {
+				if os.IsNotExist(err) {
+					continue
+				}
+				return nil, err
+			}
+			contents[contentName] = entry
+			continue
+		}
+
 		// If this is a directory, and we have a baseline, then check if that
 		// baseline has content with the same name that is also a directory. If
 		// so, then we can use that as a baseline for this content. While we
@@ -456,6 +654,7 @@ func (s *scanner) directory(
 			if _, contentDirty := s.dirtyPaths[contentPath]; !contentDirty {
 				contents[contentName] = contentBaseline
 				var missingCacheEntries bool
+				s.lock.Lock()
 				contentBaseline.walk(contentPath, func(path string, entry *Entry) {
 					// Update total entry counts.
 					if entry.Kind == EntryKind_Directory {
@@ -489,6 +688,7 @@ func (s *scanner) directory(
 						}
 					}
 				}, false)
+				s.lock.Unlock()
 				if missingCacheEntries {
 					return nil, errors.New("old cache entries don't correspond to baseline")
 				}
@@ -500,22 +700,45 @@ func (s *scanner) directory(
 		// dirty, then we need to handle it manually. Note that we're still
 		// passing the directory baseline down at this point, because its child
 		// entries may not be marked as dirty and may be reusable.
+		//
+		// Files and directories are the only content types whose processing is
+		// potentially expensive, so if an auxiliary worker is idle, then we
+		// dispatch their processing to it.
+		if contentKind == EntryKind_File || contentKind == EntryKind_Directory {
+			select {
+			case auxiliary := <-s.workers:
+				task := &scanTask{name: contentName}
+				tasks = append(tasks, task)
+				tasksDone.Add(1)
+				go func(kind EntryKind, path string, metadata *filesystem.Metadata, baseline *Entry) {
+					if kind == EntryKind_File {
+						task.entry, task.err = s.file(auxiliary, path, directory, metadata, nil)
+					} else {
+						task.entry, task.err = s.directory(auxiliary, path, directory, metadata, nil, baseline)
+					}
+					s.workers <- auxiliary
+					tasksDone.Done()
+				}(contentKind, contentPath, contentMetadata, contentBaseline)
+				continue
+			default:
+			}
+		}
 		var entry *Entry
 		var err error
 		if contentKind == EntryKind_File {
-			entry, err = s.file(contentPath, directory, contentMetadata, nil)
+			entry, err = s.file(worker, contentPath, directory, contentMetadata, nil)
 		} else if contentKind == EntryKind_SymbolicLink {
-			if s.symbolicLinkMode == SymbolicLinkMode_SymbolicLinkModePortable {
-				entry, err = s.symbolicLink(contentPath, directory, contentName, true)
+			if s.symbolicLinkMode.portable() {
+				entry, err = s.symbolicLink(contentPath, directory, contentMetadata.Name, true)
 			} else if s.symbolicLinkMode == SymbolicLinkMode_SymbolicLinkModeIgnore {
 				entry = &Entry{Kind: EntryKind_Untracked}
 			} else if s.symbolicLinkMode == SymbolicLinkMode_SymbolicLinkModePOSIXRaw {
-				entry, err = s.symbolicLink(contentPath, directory, contentName, false)
+				entry, err = s.symbolicLink(contentPath, directory, contentMetadata.Name, false)
 			} else {
 				panic("unsupported symbolic link mode")
 			}
 		} else if contentKind == EntryKind_Directory {
-			entry, err = s.directory(contentPath, directory, contentMetadata, nil, contentBaseline)
+			entry, err = s.directory(worker, contentPath, directory, contentMetadata, nil, contentBaseline)
 		} else {
 			panic("unhandled entry kind")
 		}
@@ -534,8 +757,23 @@ func (s *scanner) directory(
 		contents[contentName] = entry
 	}
 
+	// Wait for content being processed by auxiliary workers and record the
+	// results.
+	tasksDone.Wait()
+	for _, task := range tasks {
+		if task.err != nil {
+			if os.IsNotExist(task.err) {
+				continue
+			}
+			return nil, task.err
+		}
+		contents[task.name] = task.entry
+	}
+
 	// Increment the total directory count.
+	s.lock.Lock()
 	s.directories++
+	s.lock.Unlock()
 
 	// Success.
 	return &Entry{
@@ -545,17 +783,25 @@ func (s *scanner) directory(
 }
 
 // Scan creates a new filesystem snapshot at the specified root. The only
-// required arguments are ctx, root, hasher, ignores, probeMode, and
-// symbolicLinkMode. The baseline, recheckPaths, cache, and ignoreCache fields
-// merely provide acceleration options.
+// required arguments are ctx, root, hasherFactory, ignores, probeMode,
+// symbolicLinkMode, and unicodeNormalizationMode. The baseline, recheckPaths,
+// cache, and ignoreCache fields merely provide acceleration options. The
+// modificationTimeTolerance argument specifies the tolerance used when
+// comparing file modification times against those in the cache. The concurrency
+// argument specifies the maximum number of Goroutines that will be used to walk
+// and hash content, with a value of 0 being treated as 1. The hasher factory
+// will be invoked once for each such Goroutine.
 func Scan(
 	ctx context.Context,
 	root string,
 	baseline *Snapshot, recheckPaths map[string]bool,
-	hasher hash.Hash, cache *Cache,
+	hasherFactory func() hash.Hash, cache *Cache,
 	ignores []string, ignoreCache IgnoreCache,
 	probeMode behavior.ProbeMode,
 	symbolicLinkMode SymbolicLinkMode,
+	unicodeNormalizationMode UnicodeNormalizationMode,
+	modificationTimeTolerance time.Duration,
+	concurrency uint32,
 ) (*Snapshot, *Cache, IgnoreCache, error) {
 	// Verify that the symbolic link mode is valid for this platform.
 	if symbolicLinkMode == SymbolicLinkMode_SymbolicLinkModePOSIXRaw && runtime.GOOS == "windows" {
@@ -688,6 +934,21 @@ func Scan(
 		behaviorCache.Unlock()
 	}
 
+	// If we're following symbolic links, then resolve the root path (which may
+	// contain intermediate symbolic links) so that loops can be detected. We
+	// also ignore any baseline, because followed content may reside outside of
+	// the synchronization root, where modifications won't be detected by
+	// filesystem watching.
+	var realRoot string
+	var followed map[string]string
+	if symbolicLinkMode == SymbolicLinkMode_SymbolicLinkModeFollow {
+		if realRoot, err = filepath.EvalSymlinks(root); err != nil {
+			return nil, nil, nil, fmt.Errorf("unable to resolve synchronization root: %w", err)
+		}
+		followed = make(map[string]string)
+		baseline = nil
+	}
+
 	// If a baseline has been provided but differs in terms of root kind or
 	// filesystem behavior, then we can just ignore it.
 	if baseline != nil {
@@ -758,24 +1019,40 @@ func Scan(
 	}
 	newIgnoreCache := make(IgnoreCache, initialIgnoreCacheCapacity)
 
+	// Create the pool of auxiliary scan workers. The scanning Goroutine itself
+	// accounts for one unit of concurrency.
+	var workers chan *scanWorker
+	if concurrency > 1 {
+		workers = make(chan *scanWorker, concurrency-1)
+		for w := uint32(1); w < concurrency; w++ {
+			workers <- newScanWorker(hasherFactory())
+		}
+	}
+
 	// Create a scanner.
 	s := &scanner{
-		cancelled:              ctx.Done(),
-		root:                   root,
-		dirtyPaths:             dirtyPaths,
-		hasher:                 hasher,
-		cache:                  cache,
-		ignorer:                ignorer,
-		ignoreCache:            ignoreCache,
-		symbolicLinkMode:       symbolicLinkMode,
-		newCache:               newCache,
-		newIgnoreCache:         newIgnoreCache,
-		copyBuffer:             make([]byte, scannerCopyBufferSize),
-		deviceID:               metadata.DeviceID,
-		recomposeUnicode:       decomposesUnicode,
-		preservesExecutability: preservesExecutability,
+		cancelled:                 ctx.Done(),
+		root:                      root,
+		dirtyPaths:                dirtyPaths,
+		workers:                   workers,
+		cache:                     cache,
+		ignorer:                   ignorer,
+		ignoreCache:               ignoreCache,
+		symbolicLinkMode:          symbolicLinkMode,
+		realRoot:                  realRoot,
+		followed:                  followed,
+		newCache:                  newCache,
+		newIgnoreCache:            newIgnoreCache,
+		deviceID:                  metadata.DeviceID,
+		recomposeUnicode:          decomposesUnicode,
+		unicodeNormalizationMode:  unicodeNormalizationMode,
+		preservesExecutability:    preservesExecutability,
+		modificationTimeTolerance: modificationTimeTolerance,
 	}
 
+	// Create the scan worker for this Goroutine.
+	worker := newScanWorker(hasherFactory())
+
 	// Handle the scan based on the root type.
 	var content *Entry
 	if rootKind == EntryKind_Directory {
@@ -783,9 +1060,9 @@ func Scan(
 		if baseline != nil {
 			directoryBaseline = baseline.Content
 		}
-		content, err = s.directory("", nil, metadata, directoryRoot, directoryBaseline)
+		content, err = s.directory(worker, "", nil, metadata, directoryRoot, directoryBaseline)
 	} else if rootKind == EntryKind_File {
-		content, err = s.file("", nil, metadata, fileRoot)
+		content, err = s.file(worker, "", nil, metadata, fileRoot)
 	} else {
 		panic("unhandled root kind")
 	}
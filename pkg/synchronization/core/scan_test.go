@@ -4,6 +4,7 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"hash"
 	"net"
 	"os"
 	"path/filepath"
@@ -472,10 +473,13 @@ func TestScan(t *testing.T) {
 				test.ctx,
 				root,
 				nil, nil,
-				hasher, nil,
+				newTestingHasher, nil,
 				test.ignores, nil,
 				behavior.ProbeMode_ProbeModeProbe,
 				test.symbolicLinkMode,
+				UnicodeNormalizationMode_UnicodeNormalizationModeNone,
+				0,
+				1,
 			)
 			if test.expectFailure {
 				if err == nil {
@@ -553,10 +557,13 @@ func TestScan(t *testing.T) {
 				test.ctx,
 				root,
 				nil, nil,
-				rescanHasher, cache,
+				func() hash.Hash { return rescanHasher }, cache,
 				test.ignores, ignoreCache,
 				behavior.ProbeMode_ProbeModeProbe,
 				test.symbolicLinkMode,
+				UnicodeNormalizationMode_UnicodeNormalizationModeNone,
+				0,
+				1,
 			)
 
 			// Handle scan failure (which isn't expected at this point).
@@ -624,10 +631,13 @@ func TestScan(t *testing.T) {
 				test.ctx,
 				root,
 				snapshot, nil,
-				hasher, cache,
+				newTestingHasher, cache,
 				test.ignores, ignoreCache,
 				behavior.ProbeMode_ProbeModeProbe,
 				test.symbolicLinkMode,
+				UnicodeNormalizationMode_UnicodeNormalizationModeNone,
+				0,
+				1,
 			)
 
 			// Handle scan failure (which isn't expected at this point).
@@ -722,10 +732,13 @@ func TestScan(t *testing.T) {
 				test.ctx,
 				root,
 				snapshot, recheckPaths,
-				hasher, cache,
+				newTestingHasher, cache,
 				test.ignores, ignoreCache,
 				behavior.ProbeMode_ProbeModeProbe,
 				test.symbolicLinkMode,
+				UnicodeNormalizationMode_UnicodeNormalizationModeNone,
+				0,
+				1,
 			)
 
 			// Handle scan failure (which isn't expected at this point).
@@ -834,10 +847,13 @@ func TestScanCrossFilesystemBoundary(t *testing.T) {
 		context.Background(),
 		parent,
 		nil, nil,
-		newTestingHasher(), nil,
+		newTestingHasher, nil,
 		[]string{"*", "!" + name}, nil,
 		behavior.ProbeMode_ProbeModeProbe,
 		SymbolicLinkMode_SymbolicLinkModePortable,
+		UnicodeNormalizationMode_UnicodeNormalizationModeNone,
+		0,
+		1,
 	)
 	if err != nil {
 		t.Fatalf("unable to perform scan: %v", err)
@@ -853,3 +869,240 @@ func TestScanCrossFilesystemBoundary(t *testing.T) {
 		t.Errorf("result does not match expected: %v != %v", snapshot.Content.Contents[name], expected)
 	}
 }
+
+// TestScanUnicodeNormalization tests the behavior of Scan when performing
+// Unicode normalization of content names. It is separate from TestScan because
+// the test content in TestScan is specified by synchronization name, which
+// can't represent on-disk names that aren't normalized.
+func TestScanUnicodeNormalization(t *testing.T) {
+	// Create a temporary directory containing a file with a decomposed name.
+	root := t.TempDir()
+	decomposed := "cafe\u0301"
+	composed := "caf\u00e9"
+	if err := os.WriteFile(filepath.Join(root, decomposed), nil, 0600); err != nil {
+		t.Fatal("unable to create test file:", err)
+	}
+
+	// Perform a scan with NFC normalization.
+	snapshot, _, _, err := Scan(
+		context.Background(),
+		root,
+		nil, nil,
+		newTestingHasher, nil,
+		nil, nil,
+		behavior.ProbeMode_ProbeModeProbe,
+		SymbolicLinkMode_SymbolicLinkModePortable,
+		UnicodeNormalizationMode_UnicodeNormalizationModeNFC,
+		0,
+		1,
+	)
+	if err != nil {
+		t.Fatalf("unable to perform scan: %v", err)
+	} else if snapshot == nil {
+		t.Fatalf("scan returned nil result")
+	}
+
+	// Ensure that the file was recorded using its composed name.
+	if entry, ok := snapshot.Content.Contents[composed]; !ok {
+		t.Error("composed name not found in snapshot")
+	} else if entry.Kind != EntryKind_File {
+		t.Error("composed name does not correspond to file")
+	}
+	if _, ok := snapshot.Content.Contents[decomposed]; ok {
+		t.Error("decomposed name unexpectedly found in snapshot")
+	}
+
+	// Create a file with the composed name. On filesystems that don't treat
+	// equivalent normalizations as the same name, this will create a second
+	// file, in which case the two names collide and should be reported as
+	// problematic content.
+	if err := os.WriteFile(filepath.Join(root, composed), nil, 0600); err != nil {
+		t.Fatal("unable to create test file:", err)
+	}
+	if names, err := os.ReadDir(root); err != nil {
+		t.Fatal("unable to read test directory:", err)
+	} else if len(names) != 2 {
+		t.Skip("filesystem does not distinguish Unicode normalization forms")
+	}
+
+	// Rescan with NFC normalization.
+	snapshot, cache, _, err := Scan(
+		context.Background(),
+		root,
+		nil, nil,
+		newTestingHasher, nil,
+		nil, nil,
+		behavior.ProbeMode_ProbeModeProbe,
+		SymbolicLinkMode_SymbolicLinkModePortable,
+		UnicodeNormalizationMode_UnicodeNormalizationModeNFC,
+		0,
+		1,
+	)
+	if err != nil {
+		t.Fatalf("unable to perform scan: %v", err)
+	} else if snapshot == nil {
+		t.Fatalf("scan returned nil result")
+	}
+
+	// Ensure that the collision was recorded as problematic.
+	if entry, ok := snapshot.Content.Contents[composed]; !ok {
+		t.Error("composed name not found in snapshot")
+	} else if entry.Kind != EntryKind_Problematic {
+		t.Error("colliding names not recorded as problematic")
+	}
+
+	// Ensure that neither of the colliding files was counted or cached,
+	// regardless of the order in which they were listed.
+	if snapshot.Files != 0 {
+		t.Error("colliding files counted as synchronizable:", snapshot.Files)
+	}
+	if len(cache.Entries) != 0 {
+		t.Error("colliding files recorded in cache:", len(cache.Entries))
+	}
+}
+
+// TestScanConcurrency tests that scans performed with multiple Goroutines
+// produce the same results as single-Goroutine scans.
+func TestScanConcurrency(t *testing.T) {
+	// Create a temporary directory with a reasonably wide and deep hierarchy
+	// of content.
+	root := t.TempDir()
+	for d := 0; d < 8; d++ {
+		directory := filepath.Join(root, fmt.Sprintf("directory%d", d), "child")
+		if err := os.MkdirAll(directory, 0700); err != nil {
+			t.Fatal("unable to create test directory:", err)
+		}
+		for f := 0; f < 16; f++ {
+			content := []byte(fmt.Sprintf("content %d %d", d, f))
+			name := fmt.Sprintf("file%d", f)
+			if err := os.WriteFile(filepath.Join(directory, name), content, 0600); err != nil {
+				t.Fatal("unable to create test file:", err)
+			}
+			if err := os.WriteFile(filepath.Join(root, fmt.Sprintf("directory%d", d), name), content, 0600); err != nil {
+				t.Fatal("unable to create test file:", err)
+			}
+		}
+	}
+
+	// Perform a single-Goroutine scan.
+	expected, expectedCache, expectedIgnoreCache, err := Scan(
+		context.Background(),
+		root,
+		nil, nil,
+		newTestingHasher, nil,
+		[]string{"file1*"}, nil,
+		behavior.ProbeMode_ProbeModeProbe,
+		SymbolicLinkMode_SymbolicLinkModePortable,
+		UnicodeNormalizationMode_UnicodeNormalizationModeNone,
+		0,
+		1,
+	)
+	if err != nil {
+		t.Fatal("unable to perform single-Goroutine scan:", err)
+	}
+
+	// Perform a concurrent scan and ensure that its results match.
+	snapshot, cache, ignoreCache, err := Scan(
+		context.Background(),
+		root,
+		nil, nil,
+		newTestingHasher, nil,
+		[]string{"file1*"}, nil,
+		behavior.ProbeMode_ProbeModeProbe,
+		SymbolicLinkMode_SymbolicLinkModePortable,
+		UnicodeNormalizationMode_UnicodeNormalizationModeNone,
+		0,
+		8,
+	)
+	if err != nil {
+		t.Fatal("unable to perform concurrent scan:", err)
+	}
+	if !snapshot.Equal(expected) {
+		t.Error("concurrent scan snapshot does not match single-Goroutine scan snapshot")
+	}
+	if !cache.Equal(expectedCache) {
+		t.Error("concurrent scan cache does not match single-Goroutine scan cache")
+	}
+	if !testingIgnoreCachesEqual(ignoreCache, expectedIgnoreCache) {
+		t.Error("concurrent scan ignore cache does not match single-Goroutine scan ignore cache")
+	}
+}
+
+// TestScanFollowSymbolicLinks tests that scans performed in follow mode
+// synchronize the targets of symbolic links as regular content and detect
+// symbolic link loops.
+func TestScanFollowSymbolicLinks(t *testing.T) {
+	// Symbolic link creation requires special privileges on Windows.
+	if runtime.GOOS == "windows" {
+		t.Skip()
+	}
+
+	// Create a vendored package outside of the synchronization root.
+	vendored := t.TempDir()
+	if err := os.WriteFile(filepath.Join(vendored, "file"), []byte("vendored"), 0600); err != nil {
+		t.Fatal("unable to create vendored file:", err)
+	}
+
+	// Create a synchronization root with links to the vendored package, to a
+	// file within the vendored package, to a parent directory, and to a
+	// non-existent target.
+	root := t.TempDir()
+	if err := os.Mkdir(filepath.Join(root, "directory"), 0700); err != nil {
+		t.Fatal("unable to create test directory:", err)
+	}
+	links := map[string]string{
+		"package":           vendored,
+		"file":              filepath.Join(vendored, "file"),
+		"directory/loop":    root,
+		"directory/missing": filepath.Join(root, "missing"),
+	}
+	for link, target := range links {
+		if err := os.Symlink(target, filepath.Join(root, filepath.FromSlash(link))); err != nil {
+			t.Fatal("unable to create test symbolic link:", err)
+		}
+	}
+
+	// Perform a scan.
+	snapshot, _, _, err := Scan(
+		context.Background(),
+		root,
+		nil, nil,
+		newTestingHasher, nil,
+		nil, nil,
+		behavior.ProbeMode_ProbeModeProbe,
+		SymbolicLinkMode_SymbolicLinkModeFollow,
+		UnicodeNormalizationMode_UnicodeNormalizationModeNone,
+		0,
+		1,
+	)
+	if err != nil {
+		t.Fatal("unable to perform scan:", err)
+	} else if snapshot == nil {
+		t.Fatal("scan returned nil result")
+	}
+
+	// Verify that link targets were recorded as regular content.
+	if entry := snapshot.Content.Contents["package"]; entry == nil || entry.Kind != EntryKind_Directory {
+		t.Error("followed directory link not recorded as directory")
+	} else if child := entry.Contents["file"]; child == nil || child.Kind != EntryKind_File {
+		t.Error("followed directory contents not recorded")
+	}
+	if entry := snapshot.Content.Contents["file"]; entry == nil || entry.Kind != EntryKind_File {
+		t.Error("followed file link not recorded as file")
+	}
+	if snapshot.SymbolicLinks != 0 {
+		t.Error("symbolic links unexpectedly recorded:", snapshot.SymbolicLinks)
+	}
+
+	// Verify that the loop and dangling links were recorded as problematic.
+	directory := snapshot.Content.Contents["directory"]
+	if directory == nil || directory.Kind != EntryKind_Directory {
+		t.Fatal("directory not recorded as directory")
+	}
+	if entry := directory.Contents["loop"]; entry == nil || entry.Kind != EntryKind_Problematic {
+		t.Error("symbolic link loop not recorded as problematic")
+	}
+	if entry := directory.Contents["missing"]; entry == nil || entry.Kind != EntryKind_Problematic {
+		t.Error("dangling symbolic link not recorded as problematic")
+	}
+}
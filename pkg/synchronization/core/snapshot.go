@@ -3,6 +3,8 @@ package core
 import (
 	"errors"
 	"fmt"
+
+	"github.com/mutagen-io/mutagen/pkg/comparison"
 )
 
 // EnsureValid ensures that Snapshot's invariants are respected.
@@ -20,6 +22,13 @@ func (s *Snapshot) EnsureValid() error {
 
 	// All values of behavioral metadata fields are valid.
 
+	// Ensure that any VCS ignores are valid.
+	for _, ignore := range s.VcsIgnores {
+		if !ValidIgnorePattern(ignore) {
+			return fmt.Errorf("invalid VCS ignore pattern: %s", ignore)
+		}
+	}
+
 	// While there are some validations that we could perform on the statistical
 	// fields of the snapshot, they don't exhaustively determine validity.
 	// Moreover, some of these might be expensive, such as comparing the static
@@ -36,5 +45,6 @@ func (s *Snapshot) EnsureValid() error {
 func (s *Snapshot) Equal(other *Snapshot) bool {
 	return s.Content.Equal(other.Content, true) &&
 		s.PreservesExecutability == other.PreservesExecutability &&
-		s.DecomposesUnicode == other.DecomposesUnicode
+		s.DecomposesUnicode == other.DecomposesUnicode &&
+		comparison.StringSlicesEqual(s.VcsIgnores, other.VcsIgnores)
 }
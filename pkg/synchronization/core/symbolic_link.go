@@ -2,6 +2,7 @@ package core
 
 import (
 	"errors"
+	"path/filepath"
 	"runtime"
 	"strings"
 )
@@ -103,3 +104,46 @@ func normalizeSymbolicLinkAndEnsurePortable(path, target string) (string, error)
 	// Success.
 	return target, nil
 }
+
+// NormalizeSymbolicLinkAndEnsurePortable is the exported version of
+// normalizeSymbolicLinkAndEnsurePortable, provided for endpoints that perform
+// their own scanning rather than using Scan.
+func NormalizeSymbolicLinkAndEnsurePortable(path, target string) (string, error) {
+	return normalizeSymbolicLinkAndEnsurePortable(path, target)
+}
+
+// rewriteAbsoluteSymbolicLink converts an absolute symbolic link target that
+// references a location inside the synchronization root to an equivalent
+// relative target. The root must be absolute and normalized, and path must be
+// the path of the symbolic link within the synchronization root. Targets that
+// are relative or that reference locations outside of the synchronization root
+// are returned unmodified. The rewriting is performed lexically, so it won't
+// detect absolute targets that only reference the synchronization root by way
+// of an intermediate symbolic link.
+func rewriteAbsoluteSymbolicLink(root, path, target string) string {
+	// If the target isn't absolute, then there's nothing to rewrite.
+	if !filepath.IsAbs(target) {
+		return target
+	}
+
+	// Ensure that the target references a location inside the synchronization
+	// root.
+	target = filepath.Clean(target)
+	if relative, err := filepath.Rel(root, target); err != nil {
+		return target
+	} else if relative == ".." || strings.HasPrefix(relative, ".."+string(filepath.Separator)) {
+		return target
+	}
+
+	// Compute the target relative to the directory containing the symbolic
+	// link.
+	parent := filepath.Dir(filepath.Join(root, filepath.FromSlash(path)))
+	relative, err := filepath.Rel(parent, target)
+	if err != nil {
+		return target
+	}
+
+	// Convert the result to use forward slashes. This conversion will be
+	// reverted on Windows when the symbolic link is created.
+	return filepath.ToSlash(relative)
+}
@@ -21,6 +21,12 @@ func (m SymbolicLinkMode) MarshalText() ([]byte, error) {
 		result = "portable"
 	case SymbolicLinkMode_SymbolicLinkModePOSIXRaw:
 		result = "posix-raw"
+	case SymbolicLinkMode_SymbolicLinkModeRewriteAbsolute:
+		result = "rewrite-absolute"
+	case SymbolicLinkMode_SymbolicLinkModePortableWindows:
+		result = "portable-windows"
+	case SymbolicLinkMode_SymbolicLinkModeFollow:
+		result = "follow"
 	default:
 		result = "unknown"
 	}
@@ -40,6 +46,12 @@ func (m *SymbolicLinkMode) UnmarshalText(textBytes []byte) error {
 		*m = SymbolicLinkMode_SymbolicLinkModePortable
 	case "posix-raw":
 		*m = SymbolicLinkMode_SymbolicLinkModePOSIXRaw
+	case "rewrite-absolute":
+		*m = SymbolicLinkMode_SymbolicLinkModeRewriteAbsolute
+	case "portable-windows":
+		*m = SymbolicLinkMode_SymbolicLinkModePortableWindows
+	case "follow":
+		*m = SymbolicLinkMode_SymbolicLinkModeFollow
 	default:
 		return fmt.Errorf("unknown symbolic link mode specification: %s", text)
 	}
@@ -58,6 +70,41 @@ func (m SymbolicLinkMode) Supported() bool {
 		return true
 	case SymbolicLinkMode_SymbolicLinkModePOSIXRaw:
 		return true
+	case SymbolicLinkMode_SymbolicLinkModeRewriteAbsolute:
+		return true
+	case SymbolicLinkMode_SymbolicLinkModePortableWindows:
+		return true
+	case SymbolicLinkMode_SymbolicLinkModeFollow:
+		return true
+	default:
+		return false
+	}
+}
+
+// portable indicates whether or not the symbolic link mode requires that
+// synchronized symbolic links be portable.
+func (m SymbolicLinkMode) portable() bool {
+	switch m {
+	case SymbolicLinkMode_SymbolicLinkModePortable:
+		return true
+	case SymbolicLinkMode_SymbolicLinkModeRewriteAbsolute:
+		return true
+	case SymbolicLinkMode_SymbolicLinkModePortableWindows:
+		return true
+	default:
+		return false
+	}
+}
+
+// rewritesAbsolute indicates whether or not the symbolic link mode rewrites
+// absolute symbolic link targets inside the synchronization root to relative
+// targets.
+func (m SymbolicLinkMode) rewritesAbsolute() bool {
+	switch m {
+	case SymbolicLinkMode_SymbolicLinkModeRewriteAbsolute:
+		return true
+	case SymbolicLinkMode_SymbolicLinkModePortableWindows:
+		return true
 	default:
 		return false
 	}
@@ -74,6 +121,12 @@ func (m SymbolicLinkMode) Description() string {
 		return "Portable"
 	case SymbolicLinkMode_SymbolicLinkModePOSIXRaw:
 		return "POSIX Raw"
+	case SymbolicLinkMode_SymbolicLinkModeRewriteAbsolute:
+		return "Rewrite Absolute"
+	case SymbolicLinkMode_SymbolicLinkModePortableWindows:
+		return "Portable (Windows)"
+	case SymbolicLinkMode_SymbolicLinkModeFollow:
+		return "Follow"
 	default:
 		return "Unknown"
 	}
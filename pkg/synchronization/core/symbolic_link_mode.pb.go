@@ -41,6 +41,24 @@ const (
 	// should be propagated in their raw form. It is only valid on POSIX systems
 	// and only makes sense in the context of POSIX-to-POSIX synchronization.
 	SymbolicLinkMode_SymbolicLinkModePOSIXRaw SymbolicLinkMode = 3
+	// SymbolicLinkMode_SymbolicLinkModeRewriteAbsolute specifies that symbolic
+	// links should be handled as in portable mode, except that absolute
+	// symbolic links with targets inside the synchronization root will be
+	// rewritten to equivalent relative targets before propagation.
+	SymbolicLinkMode_SymbolicLinkModeRewriteAbsolute SymbolicLinkMode = 4
+	// SymbolicLinkMode_SymbolicLinkModePortableWindows specifies that symbolic
+	// links should be handled as in rewrite-absolute mode, with additional
+	// handling for Windows directory junctions and directory symbolic links.
+	// Junctions (which always have absolute targets) are synchronized as
+	// portable symbolic links if their targets are inside the synchronization
+	// root, and symbolic links that target directories are created as
+	// directory symbolic links on Windows.
+	SymbolicLinkMode_SymbolicLinkModePortableWindows SymbolicLinkMode = 5
+	// SymbolicLinkMode_SymbolicLinkModeFollow specifies that symbolic links
+	// should be resolved and their targets synchronized as regular files and
+	// directories. Symbolic links that can't be resolved or that would form a
+	// directory loop are treated as problematic content.
+	SymbolicLinkMode_SymbolicLinkModeFollow SymbolicLinkMode = 6
 )
 
 // Enum value maps for SymbolicLinkMode.
@@ -50,12 +68,18 @@ var (
 		1: "SymbolicLinkModeIgnore",
 		2: "SymbolicLinkModePortable",
 		3: "SymbolicLinkModePOSIXRaw",
+		4: "SymbolicLinkModeRewriteAbsolute",
+		5: "SymbolicLinkModePortableWindows",
+		6: "SymbolicLinkModeFollow",
 	}
 	SymbolicLinkMode_value = map[string]int32{
-		"SymbolicLinkModeDefault":  0,
-		"SymbolicLinkModeIgnore":   1,
-		"SymbolicLinkModePortable": 2,
-		"SymbolicLinkModePOSIXRaw": 3,
+		"SymbolicLinkModeDefault":         0,
+		"SymbolicLinkModeIgnore":          1,
+		"SymbolicLinkModePortable":        2,
+		"SymbolicLinkModePOSIXRaw":        3,
+		"SymbolicLinkModeRewriteAbsolute": 4,
+		"SymbolicLinkModePortableWindows": 5,
+		"SymbolicLinkModeFollow":          6,
 	}
 )
 
@@ -92,7 +116,7 @@ var file_synchronization_core_symbolic_link_mode_proto_rawDesc = []byte{
 	0x0a, 0x2d, 0x73, 0x79, 0x6e, 0x63, 0x68, 0x72, 0x6f, 0x6e, 0x69, 0x7a, 0x61, 0x74, 0x69, 0x6f,
 	0x6e, 0x2f, 0x63, 0x6f, 0x72, 0x65, 0x2f, 0x73, 0x79, 0x6d, 0x62, 0x6f, 0x6c, 0x69, 0x63, 0x5f,
 	0x6c, 0x69, 0x6e, 0x6b, 0x5f, 0x6d, 0x6f, 0x64, 0x65, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x12,
-	0x04, 0x63, 0x6f, 0x72, 0x65, 0x2a, 0x87, 0x01, 0x0a, 0x10, 0x53, 0x79, 0x6d, 0x62, 0x6f, 0x6c,
+	0x04, 0x63, 0x6f, 0x72, 0x65, 0x2a, 0xed, 0x01, 0x0a, 0x10, 0x53, 0x79, 0x6d, 0x62, 0x6f, 0x6c,
 	0x69, 0x63, 0x4c, 0x69, 0x6e, 0x6b, 0x4d, 0x6f, 0x64, 0x65, 0x12, 0x1b, 0x0a, 0x17, 0x53, 0x79,
 	0x6d, 0x62, 0x6f, 0x6c, 0x69, 0x63, 0x4c, 0x69, 0x6e, 0x6b, 0x4d, 0x6f, 0x64, 0x65, 0x44, 0x65,
 	0x66, 0x61, 0x75, 0x6c, 0x74, 0x10, 0x00, 0x12, 0x1a, 0x0a, 0x16, 0x53, 0x79, 0x6d, 0x62, 0x6f,
@@ -100,12 +124,18 @@ var file_synchronization_core_symbolic_link_mode_proto_rawDesc = []byte{
 	0x65, 0x10, 0x01, 0x12, 0x1c, 0x0a, 0x18, 0x53, 0x79, 0x6d, 0x62, 0x6f, 0x6c, 0x69, 0x63, 0x4c,
 	0x69, 0x6e, 0x6b, 0x4d, 0x6f, 0x64, 0x65, 0x50, 0x6f, 0x72, 0x74, 0x61, 0x62, 0x6c, 0x65, 0x10,
 	0x02, 0x12, 0x1c, 0x0a, 0x18, 0x53, 0x79, 0x6d, 0x62, 0x6f, 0x6c, 0x69, 0x63, 0x4c, 0x69, 0x6e,
-	0x6b, 0x4d, 0x6f, 0x64, 0x65, 0x50, 0x4f, 0x53, 0x49, 0x58, 0x52, 0x61, 0x77, 0x10, 0x03, 0x42,
-	0x38, 0x5a, 0x36, 0x67, 0x69, 0x74, 0x68, 0x75, 0x62, 0x2e, 0x63, 0x6f, 0x6d, 0x2f, 0x6d, 0x75,
-	0x74, 0x61, 0x67, 0x65, 0x6e, 0x2d, 0x69, 0x6f, 0x2f, 0x6d, 0x75, 0x74, 0x61, 0x67, 0x65, 0x6e,
-	0x2f, 0x70, 0x6b, 0x67, 0x2f, 0x73, 0x79, 0x6e, 0x63, 0x68, 0x72, 0x6f, 0x6e, 0x69, 0x7a, 0x61,
-	0x74, 0x69, 0x6f, 0x6e, 0x2f, 0x63, 0x6f, 0x72, 0x65, 0x62, 0x06, 0x70, 0x72, 0x6f, 0x74, 0x6f,
-	0x33,
+	0x6b, 0x4d, 0x6f, 0x64, 0x65, 0x50, 0x4f, 0x53, 0x49, 0x58, 0x52, 0x61, 0x77, 0x10, 0x03, 0x12,
+	0x23, 0x0a, 0x1f, 0x53, 0x79, 0x6d, 0x62, 0x6f, 0x6c, 0x69, 0x63, 0x4c, 0x69, 0x6e, 0x6b, 0x4d,
+	0x6f, 0x64, 0x65, 0x52, 0x65, 0x77, 0x72, 0x69, 0x74, 0x65, 0x41, 0x62, 0x73, 0x6f, 0x6c, 0x75,
+	0x74, 0x65, 0x10, 0x04, 0x12, 0x23, 0x0a, 0x1f, 0x53, 0x79, 0x6d, 0x62, 0x6f, 0x6c, 0x69, 0x63,
+	0x4c, 0x69, 0x6e, 0x6b, 0x4d, 0x6f, 0x64, 0x65, 0x50, 0x6f, 0x72, 0x74, 0x61, 0x62, 0x6c, 0x65,
+	0x57, 0x69, 0x6e, 0x64, 0x6f, 0x77, 0x73, 0x10, 0x05, 0x12, 0x1a, 0x0a, 0x16, 0x53, 0x79, 0x6d,
+	0x62, 0x6f, 0x6c, 0x69, 0x63, 0x4c, 0x69, 0x6e, 0x6b, 0x4d, 0x6f, 0x64, 0x65, 0x46, 0x6f, 0x6c,
+	0x6c, 0x6f, 0x77, 0x10, 0x06, 0x42, 0x38, 0x5a, 0x36, 0x67, 0x69, 0x74, 0x68, 0x75, 0x62, 0x2e,
+	0x63, 0x6f, 0x6d, 0x2f, 0x6d, 0x75, 0x74, 0x61, 0x67, 0x65, 0x6e, 0x2d, 0x69, 0x6f, 0x2f, 0x6d,
+	0x75, 0x74, 0x61, 0x67, 0x65, 0x6e, 0x2f, 0x70, 0x6b, 0x67, 0x2f, 0x73, 0x79, 0x6e, 0x63, 0x68,
+	0x72, 0x6f, 0x6e, 0x69, 0x7a, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x2f, 0x63, 0x6f, 0x72, 0x65, 0x62,
+	0x06, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x33,
 }
 
 var (
@@ -16,7 +16,10 @@ func TestSymbolicLinkModeIsDefault(t *testing.T) {
 		{SymbolicLinkMode_SymbolicLinkModeIgnore, false},
 		{SymbolicLinkMode_SymbolicLinkModePortable, false},
 		{SymbolicLinkMode_SymbolicLinkModePOSIXRaw, false},
-		{SymbolicLinkMode_SymbolicLinkModePOSIXRaw + 1, false},
+		{SymbolicLinkMode_SymbolicLinkModeRewriteAbsolute, false},
+		{SymbolicLinkMode_SymbolicLinkModePortableWindows, false},
+		{SymbolicLinkMode_SymbolicLinkModeFollow, false},
+		{SymbolicLinkMode_SymbolicLinkModeFollow + 1, false},
 	}
 
 	// Process test cases.
@@ -42,6 +45,9 @@ func TestSymbolicLinkModeUnmarshalText(t *testing.T) {
 		{"ignore", SymbolicLinkMode_SymbolicLinkModeIgnore, false},
 		{"portable", SymbolicLinkMode_SymbolicLinkModePortable, false},
 		{"posix-raw", SymbolicLinkMode_SymbolicLinkModePOSIXRaw, false},
+		{"rewrite-absolute", SymbolicLinkMode_SymbolicLinkModeRewriteAbsolute, false},
+		{"portable-windows", SymbolicLinkMode_SymbolicLinkModePortableWindows, false},
+		{"follow", SymbolicLinkMode_SymbolicLinkModeFollow, false},
 	}
 
 	// Process test cases.
@@ -74,7 +80,10 @@ func TestSymbolicLinkModeSupported(t *testing.T) {
 		{SymbolicLinkMode_SymbolicLinkModeIgnore, true},
 		{SymbolicLinkMode_SymbolicLinkModePortable, true},
 		{SymbolicLinkMode_SymbolicLinkModePOSIXRaw, true},
-		{(SymbolicLinkMode_SymbolicLinkModePOSIXRaw + 1), false},
+		{SymbolicLinkMode_SymbolicLinkModeRewriteAbsolute, true},
+		{SymbolicLinkMode_SymbolicLinkModePortableWindows, true},
+		{SymbolicLinkMode_SymbolicLinkModeFollow, true},
+		{(SymbolicLinkMode_SymbolicLinkModeFollow + 1), false},
 	}
 
 	// Process test cases.
@@ -100,7 +109,10 @@ func TestSymbolicLinkModeDescription(t *testing.T) {
 		{SymbolicLinkMode_SymbolicLinkModeIgnore, "Ignore"},
 		{SymbolicLinkMode_SymbolicLinkModePortable, "Portable"},
 		{SymbolicLinkMode_SymbolicLinkModePOSIXRaw, "POSIX Raw"},
-		{(SymbolicLinkMode_SymbolicLinkModePOSIXRaw + 1), "Unknown"},
+		{SymbolicLinkMode_SymbolicLinkModeRewriteAbsolute, "Rewrite Absolute"},
+		{SymbolicLinkMode_SymbolicLinkModePortableWindows, "Portable (Windows)"},
+		{SymbolicLinkMode_SymbolicLinkModeFollow, "Follow"},
+		{(SymbolicLinkMode_SymbolicLinkModeFollow + 1), "Unknown"},
 	}
 
 	// Process test cases.
@@ -11,3 +11,27 @@ func TestSymbolicLinkPOSIXBackslashInvalid(t *testing.T) {
 		t.Fatal("symbolic link with backslash in target treated as sane")
 	}
 }
+
+func TestSymbolicLinkPOSIXRewriteAbsoluteInsideRoot(t *testing.T) {
+	if target := rewriteAbsoluteSymbolicLink("/root", "directory/link", "/root/other/file"); target != "../other/file" {
+		t.Error("rewritten symbolic link target incorrect:", target, "!=", "../other/file")
+	}
+}
+
+func TestSymbolicLinkPOSIXRewriteAbsoluteRoot(t *testing.T) {
+	if target := rewriteAbsoluteSymbolicLink("/root", "directory/link", "/root"); target != ".." {
+		t.Error("rewritten symbolic link target incorrect:", target, "!=", "..")
+	}
+}
+
+func TestSymbolicLinkPOSIXRewriteAbsoluteOutsideRoot(t *testing.T) {
+	if target := rewriteAbsoluteSymbolicLink("/root", "link", "/rootless/file"); target != "/rootless/file" {
+		t.Error("symbolic link target outside root rewritten:", target)
+	}
+}
+
+func TestSymbolicLinkPOSIXRewriteAbsoluteRelativeUnmodified(t *testing.T) {
+	if target := rewriteAbsoluteSymbolicLink("/root", "link", "../file"); target != "../file" {
+		t.Error("relative symbolic link target rewritten:", target)
+	}
+}
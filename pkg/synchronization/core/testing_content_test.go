@@ -97,7 +97,11 @@ func (g *testingContentManager) generate() (string, error) {
 		0700,
 		nil,
 		false,
+		UnicodeNormalizationMode_UnicodeNormalizationModeNone,
+		ReplacementMode_ReplacementModeRename,
+		0,
 		provider,
+		nil,
 	)
 	if missingFiles {
 		return "", errors.New("content map missing file definitions")
@@ -7,9 +7,11 @@ import (
 	"fmt"
 	"io"
 	"os"
+	pathpkg "path"
 	"path/filepath"
 	"runtime"
 	"strings"
+	"time"
 
 	"golang.org/x/text/unicode/norm"
 
@@ -21,6 +23,9 @@ const (
 	// crossDeviceRenameTemporaryNamePrefix is the file name prefix to use for
 	// intermediate temporary files used in cross-device renames.
 	crossDeviceRenameTemporaryNamePrefix = filesystem.TemporaryNamePrefix + "cross-device-rename"
+	// atomicReplacementTemporaryNamePrefix is the file name prefix to use for
+	// intermediate temporary files used in atomic replacement mode.
+	atomicReplacementTemporaryNamePrefix = filesystem.TemporaryNamePrefix + "atomic-replacement"
 
 	// transitionCopyBufferSize specifies the size of the internal buffer that a
 	// transitioner uses to copy file data (e.g. when performing cross-device
@@ -54,6 +59,16 @@ type Provider interface {
 	Provide(path string, digest []byte) (string, error)
 }
 
+// Trash defines the interface that higher-level logic can use to retain files
+// that are removed or overwritten by transition algorithms.
+type Trash interface {
+	// Allocate returns an absolute filesystem path at which to retain the file
+	// at the path given as the argument before it's removed or overwritten.
+	// The parent directory of the returned path must exist and the path itself
+	// must not. If the returned path is empty, then the file isn't retained.
+	Allocate(path string) (string, error)
+}
+
 // transitioner provides the recursive implementation of transitioning.
 type transitioner struct {
 	// cancelled is the cancellation channel from the transition context.
@@ -79,8 +94,24 @@ type transitioner struct {
 	// due to Unicode decomposition behavior on the synchronization root
 	// filesystem.
 	recomposeUnicode bool
+	// unicodeNormalizationMode is the Unicode normalization mode being used.
+	unicodeNormalizationMode UnicodeNormalizationMode
+	// replacementMode is the file replacement mode being used.
+	replacementMode ReplacementMode
+	// modificationTimeTolerance is the tolerance used when comparing file
+	// modification times against those in the cache.
+	modificationTimeTolerance time.Duration
 	// provider is the staged file provider.
 	provider Provider
+	// trash is the trash used to retain removed and overwritten files. It may
+	// be nil, in which case files aren't retained.
+	trash Trash
+	// creationPath is the path of the creation operation currently being
+	// performed.
+	creationPath string
+	// creationTarget is the target of the creation operation currently being
+	// performed.
+	creationTarget *Entry
 	// problems are the problems encountered during transition operations.
 	problems []*Problem
 	// providerMissingFiles indicates that the staged file provider returned an
@@ -93,32 +124,41 @@ func (t *transitioner) recordProblem(path string, err error) {
 	t.problems = append(t.problems, &Problem{Path: path, Error: err.Error()})
 }
 
-// nameExistsInDirectoryWithProperCase is a utility method that checks if a name
-// exists within the specified directory, recomposing the names of the
-// directory's contents if necessary.
-func (t *transitioner) nameExistsInDirectoryWithProperCase(
+// normalizeContentName computes the synchronization name corresponding to an
+// on-disk content name, recomposing and normalizing Unicode if necessary.
+func (t *transitioner) normalizeContentName(name string) string {
+	if t.recomposeUnicode {
+		name = norm.NFC.String(name)
+	}
+	return t.unicodeNormalizationMode.normalize(name)
+}
+
+// findNameInDirectoryWithProperCase is a utility method that checks if a name
+// exists within the specified directory, recomposing and normalizing the names
+// of the directory's contents if necessary. If a match is found, then the
+// on-disk name of the matching content is returned.
+func (t *transitioner) findNameInDirectoryWithProperCase(
 	name string,
 	directory *filesystem.Directory,
-) (bool, error) {
+) (string, bool, error) {
 	// Grab the content names in the directory.
 	names, err := directory.ReadContentNames()
 	if err != nil {
-		return false, fmt.Errorf("unable to read directory contents: %w", err)
+		return "", false, fmt.Errorf("unable to read directory contents: %w", err)
 	}
 
 	// Check if this path component exists in the contents. It's important
 	// to note that the contents are not guaranteed to be ordered, and we
-	// may need to recompose Unicode, so we can't do a binary search here.
+	// may need to recompose or normalize Unicode, so we can't do a binary
+	// search here.
 	for _, n := range names {
-		if !t.recomposeUnicode && n == name {
-			return true, nil
-		} else if t.recomposeUnicode && norm.NFC.String(n) == name {
-			return true, nil
+		if t.normalizeContentName(n) == name {
+			return n, true, nil
 		}
 	}
 
 	// No match was found.
-	return false, nil
+	return "", false, nil
 }
 
 // walkToParentAndComputeLeafName walks down to the parent directory of the
@@ -138,7 +178,9 @@ func (t *transitioner) nameExistsInDirectoryWithProperCase(
 // This method's implementation also has the side effect of enforcing case
 // correctness. If verifyLeafCasing is true, then this method will additionally
 // verify that the casing of the leaf name of the path matches what's on the
-// filesystem.
+// filesystem. In that case, the returned leaf name will be the on-disk name of
+// the leaf, which may differ from the leaf name in the path if Unicode
+// normalization is being performed.
 func (t *transitioner) walkToParentAndComputeLeafName(
 	path string,
 	validateLeafCasing bool,
@@ -190,8 +232,10 @@ func (t *transitioner) walkToParentAndComputeLeafName(
 	// Traverse through parent components, validating casing as we go and moving
 	// down the directory hierarchy.
 	for _, component := range parentComponents {
-		// Verify that the next component exists with the proper casing.
-		if found, err := t.nameExistsInDirectoryWithProperCase(component, parent); err != nil {
+		// Verify that the next component exists with the proper casing and
+		// determine its on-disk name.
+		onDiskComponent, found, err := t.findNameInDirectoryWithProperCase(component, parent)
+		if err != nil {
 			parent.Close()
 			return nil, "", fmt.Errorf("unable to verify parent path casing: %w", err)
 		} else if !found {
@@ -212,7 +256,7 @@ func (t *transitioner) walkToParentAndComputeLeafName(
 		// correct casing on the next synchronization cycle.
 
 		// Open the next directory.
-		if p, err := parent.OpenDirectory(component); err != nil {
+		if p, err := parent.OpenDirectory(onDiskComponent); err != nil {
 			parent.Close()
 			return nil, "", fmt.Errorf("unable to open parent component: %w", err)
 		} else {
@@ -224,12 +268,14 @@ func (t *transitioner) walkToParentAndComputeLeafName(
 	// Once we've extracted the parent, validate the leaf name casing if
 	// requested.
 	if validateLeafCasing {
-		if found, err := t.nameExistsInDirectoryWithProperCase(leafName, parent); err != nil {
+		if onDiskLeafName, found, err := t.findNameInDirectoryWithProperCase(leafName, parent); err != nil {
 			parent.Close()
 			return nil, "", fmt.Errorf("unable to verify path leaf name casing: %w", err)
 		} else if !found {
 			parent.Close()
 			return nil, "", errors.New("leaf name does not exist or has incorrect casing")
+		} else {
+			leafName = onDiskLeafName
 		}
 	}
 
@@ -276,7 +322,7 @@ func (t *transitioner) ensureExpectedFile(parent *filesystem.Directory, name, pa
 	// hence the Executability property value would be unchanged as well if we
 	// were able to compute and compare it directly.
 	match := metadata.Mode == filesystem.Mode(cached.Mode) &&
-		metadata.ModificationTime.Equal(cached.ModificationTime.AsTime()) &&
+		modificationTimesMatch(metadata.ModificationTime, cached.ModificationTime, t.modificationTimeTolerance) &&
 		metadata.Size == cached.Size &&
 		metadata.FileID == cached.FileID &&
 		bytes.Equal(cached.Digest, expected.Digest)
@@ -297,10 +343,16 @@ func (t *transitioner) ensureExpectedSymbolicLink(parent *filesystem.Directory,
 		return fmt.Errorf("unable to read symbolic link target: %w", err)
 	}
 
-	// If we're in portable symbolic link mode, then we need to normalize the
+	// If we're rewriting absolute symbolic links, then we need to perform the
+	// same rewriting that would have been performed by the scan.
+	if t.symbolicLinkMode.rewritesAbsolute() {
+		target = rewriteAbsoluteSymbolicLink(t.root, path, target)
+	}
+
+	// If we're in a portable symbolic link mode, then we need to normalize the
 	// target coming from disk, because some systems (e.g. Windows) won't
 	// round-trip the target correctly.
-	if t.symbolicLinkMode == SymbolicLinkMode_SymbolicLinkModePortable {
+	if t.symbolicLinkMode.portable() {
 		target, err = normalizeSymbolicLinkAndEnsurePortable(path, target)
 		if err != nil {
 			return fmt.Errorf("unable to normalize target in portable mode: %w", err)
@@ -316,6 +368,66 @@ func (t *transitioner) ensureExpectedSymbolicLink(parent *filesystem.Directory,
 	return nil
 }
 
+// retainFile retains the file specified by name within the specified directory
+// in the trash. If move is true, then the file will be moved into the trash if
+// possible, in which case the returned boolean will be true. Otherwise, a copy
+// of the file's contents will be retained.
+func (t *transitioner) retainFile(parent *filesystem.Directory, name, path string, move bool) (bool, error) {
+	// Allocate a location in the trash.
+	destination, err := t.trash.Allocate(path)
+	if err != nil {
+		return false, fmt.Errorf("unable to allocate trash location: %w", err)
+	} else if destination == "" {
+		return false, nil
+	}
+
+	// If requested, attempt to move the file into the trash. If that fails for
+	// any reason other than a cross-device rename, then there's nothing else we
+	// can do.
+	if move {
+		renameErr := filesystem.Rename(parent, name, nil, destination, false)
+		if renameErr == nil {
+			return true, nil
+		} else if !filesystem.IsCrossDeviceError(renameErr) {
+			return false, fmt.Errorf("unable to move file to trash: %w", renameErr)
+		}
+	}
+
+	// Open the file.
+	source, _, err := parent.OpenFile(name)
+	if err != nil {
+		return false, fmt.Errorf("unable to open file: %w", err)
+	}
+	defer source.Close()
+
+	// Create the trash file. We can't defer its closure because we'll want to
+	// remove it on failure, which we can't do (on some platforms, notably
+	// Windows) if the file handle is open.
+	retained, err := os.OpenFile(destination, os.O_WRONLY|os.O_CREATE|os.O_EXCL, 0600)
+	if err != nil {
+		return false, fmt.Errorf("unable to create trash file: %w", err)
+	}
+
+	// Copy the file contents, monitoring for preemption.
+	preemptableRetained := stream.NewPreemptableWriter(
+		retained,
+		t.cancelled,
+		transitionCopyPreemptionInterval,
+	)
+	_, copyErr := io.CopyBuffer(preemptableRetained, source, t.copyBuffer)
+	retained.Close()
+	if copyErr != nil {
+		os.Remove(destination)
+		if copyErr == stream.ErrWritePreempted {
+			return false, errTransitionCancelled
+		}
+		return false, fmt.Errorf("unable to copy file contents to trash: %w", copyErr)
+	}
+
+	// Success.
+	return false, nil
+}
+
 // removeFile removes the file specified by name within the specified directory,
 // enforcing that it matches the specified entry.
 func (t *transitioner) removeFile(parent *filesystem.Directory, name, path string, expected *Entry) error {
@@ -330,6 +442,16 @@ func (t *transitioner) removeFile(parent *filesystem.Directory, name, path strin
 	// The worst case fallout is removal of contents that are modified during
 	// this window.
 
+	// If we're using a trash, then retain the file. If the file was moved into
+	// the trash, then there's nothing left to remove.
+	if t.trash != nil {
+		if moved, err := t.retainFile(parent, name, path, true); err != nil {
+			return err
+		} else if moved {
+			return nil
+		}
+	}
+
 	// Attempt to remove the file.
 	return parent.RemoveFile(name)
 }
@@ -340,6 +462,8 @@ func (t *transitioner) removeSymbolicLink(parent *filesystem.Directory, name, pa
 	// Ensure that this request is valid for the current symbolic link mode.
 	if t.symbolicLinkMode == SymbolicLinkMode_SymbolicLinkModeIgnore {
 		return errors.New("symbolic link removal requested with symbolic links ignored")
+	} else if t.symbolicLinkMode == SymbolicLinkMode_SymbolicLinkModeFollow {
+		return errors.New("symbolic link removal requested with symbolic links followed")
 	}
 
 	// Ensure that the existing symbolic link hasn't been modified from what
@@ -409,10 +533,7 @@ ContentLoop:
 		}
 
 		// Compute the content name, renormalizing Unicode if necessary.
-		contentName := c.Name
-		if t.recomposeUnicode {
-			contentName = norm.NFC.String(contentName)
-		}
+		contentName := t.normalizeContentName(c.Name)
 
 		// Compute the content path.
 		contentPath := contentPathPrefix + contentName
@@ -428,18 +549,18 @@ ContentLoop:
 
 		// Handle content removal based on type.
 		if entry.Kind == EntryKind_Directory {
-			if !t.removeDirectory(directory, contentName, contentPath, entry) {
+			if !t.removeDirectory(directory, c.Name, contentPath, entry) {
 				contentRemovalFailed = true
 				continue
 			}
 		} else if entry.Kind == EntryKind_File {
-			if err = t.removeFile(directory, contentName, contentPath, entry); err != nil {
+			if err = t.removeFile(directory, c.Name, contentPath, entry); err != nil {
 				contentRemovalFailed = true
 				t.recordProblem(contentPath, fmt.Errorf("unable to remove file: %w", err))
 				continue
 			}
 		} else if entry.Kind == EntryKind_SymbolicLink {
-			if err = t.removeSymbolicLink(directory, contentName, contentPath, entry); err != nil {
+			if err = t.removeSymbolicLink(directory, c.Name, contentPath, entry); err != nil {
 				contentRemovalFailed = true
 				t.recordProblem(contentPath, fmt.Errorf("unable to remove symbolic link: %w", err))
 				continue
@@ -547,11 +668,29 @@ func (t *transitioner) remove(path string, entry *Entry) *Entry {
 	return nil
 }
 
+// syncFile flushes the contents of the file at the specified path to disk. The
+// file is opened for writing because some platforms (notably Windows) require
+// write access to flush file contents.
+func syncFile(path string) error {
+	file, err := os.OpenFile(path, os.O_WRONLY, 0)
+	if err != nil {
+		return err
+	}
+	if err := file.Sync(); err != nil {
+		file.Close()
+		return err
+	}
+	return file.Close()
+}
+
 // findAndMoveStagedFileIntoPlace locates a staged file for the specified
 // combination of path and entry, sets its permissions appropriately, and moves
 // it to the location specified by the combination of parent directory and
-// content name. If this requires a cross-device rename, this function will
-// approximate atomicity using an intermediate temporary file.
+// content name. If atomic replacement mode is being used, then the staged file
+// contents will be flushed to disk before the move. If this requires a
+// cross-device rename, this function will copy the staged file to an
+// intermediate temporary file in the parent directory and rename it into
+// place.
 func (t *transitioner) findAndMoveStagedFileIntoPlace(
 	path string,
 	target *Entry,
@@ -578,6 +717,17 @@ func (t *transitioner) findAndMoveStagedFileIntoPlace(
 		return fmt.Errorf("unable to locate staged file: %w", err)
 	}
 
+	// In atomic replacement mode, flush the staged file contents to disk
+	// before the rename so that the target never references partially written
+	// contents, even in the event of a system crash. We do this before setting
+	// permissions because some platforms require write access to flush a file.
+	atomicReplacement := t.replacementMode == ReplacementMode_ReplacementModeAtomic
+	if atomicReplacement {
+		if err := syncFile(stagedPath); err != nil {
+			return fmt.Errorf("unable to flush staged file to disk: %w", err)
+		}
+	}
+
 	// Set permissions for the staged file.
 	if err := filesystem.SetPermissionsByPath(stagedPath, t.defaultOwnership, mode); err != nil {
 		return fmt.Errorf("unable to set staged file permissions: %w", err)
@@ -598,7 +748,11 @@ func (t *transitioner) findAndMoveStagedFileIntoPlace(
 	// At this point, we know we're dealing with a cross-device rename, for
 	// which we'll have to simulate atomicity with an intermediate temporary
 	// file. We'll also need to monitor for preemption at this point because
-	// cross-device copies can take a significant amount of time.
+	// copies can take a significant amount of time.
+	temporaryNamePrefix := crossDeviceRenameTemporaryNamePrefix
+	if atomicReplacement {
+		temporaryNamePrefix = atomicReplacementTemporaryNamePrefix
+	}
 
 	// Open the staged file. We can't defer its closure because we need to be
 	// able to remove it after a successful rename, which we can't do (on some
@@ -612,10 +766,10 @@ func (t *transitioner) findAndMoveStagedFileIntoPlace(
 	// closure because we'll want to be rename it or remove it on rename
 	// failure, which we can't do (on some platforms, notably Windows) if the
 	// file handle is open.
-	temporaryName, temporary, err := parent.CreateTemporaryFile(crossDeviceRenameTemporaryNamePrefix)
+	temporaryName, temporary, err := parent.CreateTemporaryFile(temporaryNamePrefix)
 	if err != nil {
 		stagedFile.Close()
-		return fmt.Errorf("unable to create temporary file for cross-device rename: %w", err)
+		return fmt.Errorf("unable to create intermediate temporary file: %w", err)
 	}
 
 	// Wrap the temporary file in a preemptable writer to enable cancellation.
@@ -628,6 +782,17 @@ func (t *transitioner) findAndMoveStagedFileIntoPlace(
 	// Copy the file contents. We'll handle errors below.
 	_, copyErr := io.CopyBuffer(preemptableTemporary, stagedFile, t.copyBuffer)
 
+	// In atomic replacement mode, flush the file contents to disk before the
+	// rename so that the target never references partially written contents,
+	// even in the event of a system crash.
+	if copyErr == nil && atomicReplacement {
+		if syncer, ok := temporary.(interface{ Sync() error }); ok {
+			if err := syncer.Sync(); err != nil {
+				copyErr = fmt.Errorf("unable to flush contents to disk: %w", err)
+			}
+		}
+	}
+
 	// Close out files.
 	stagedFile.Close()
 	temporary.Close()
@@ -709,7 +874,15 @@ func (t *transitioner) swapFile(path string, oldEntry, newEntry *Entry) error {
 		return nil
 	}
 
-	// Otherwise, we will have a staged file, so find it and move it into place.
+	// Otherwise, the existing file will be overwritten, so if we're using a
+	// trash, then retain a copy of it first.
+	if t.trash != nil {
+		if _, err := t.retainFile(parent, name, path, false); err != nil {
+			return err
+		}
+	}
+
+	// We will have a staged file, so find it and move it into place.
 	return t.findAndMoveStagedFileIntoPlace(path, newEntry, parent, name, true)
 }
 
@@ -723,14 +896,24 @@ func (t *transitioner) createSymbolicLink(parent *filesystem.Directory, name, pa
 	// Verify that the symbolic link agrees with our symbolic link mode.
 	if t.symbolicLinkMode == SymbolicLinkMode_SymbolicLinkModeIgnore {
 		return errors.New("symbolic link creation requested with symbolic links ignored")
-	} else if t.symbolicLinkMode == SymbolicLinkMode_SymbolicLinkModePortable {
+	} else if t.symbolicLinkMode == SymbolicLinkMode_SymbolicLinkModeFollow {
+		return errors.New("symbolic link creation requested with symbolic links followed")
+	} else if t.symbolicLinkMode.portable() {
 		if normalized, err := normalizeSymbolicLinkAndEnsurePortable(path, target.Target); err != nil || normalized != target.Target {
 			return errors.New("symbolic link was not in normalized form or was not portable")
 		}
 	}
 
-	// Create the symbolic link.
-	if err := parent.CreateSymbolicLink(name, target.Target); err != nil {
+	// Create the symbolic link. In portable Windows mode, we explicitly create
+	// directory symbolic links for targets that are directories, because the
+	// target might not exist yet (in which case Windows would default to
+	// creating a file symbolic link).
+	if t.symbolicLinkMode == SymbolicLinkMode_SymbolicLinkModePortableWindows &&
+		t.symbolicLinkTargetIsDirectory(path, target.Target) {
+		if err := parent.CreateDirectorySymbolicLink(name, target.Target); err != nil {
+			return err
+		}
+	} else if err := parent.CreateSymbolicLink(name, target.Target); err != nil {
 		return err
 	}
 
@@ -764,6 +947,60 @@ func (t *transitioner) createSymbolicLink(parent *filesystem.Directory, name, pa
 	return nil
 }
 
+// symbolicLinkTargetIsDirectory determines whether or not the portable symbolic
+// link target specified for the symbolic link at the specified path refers to a
+// directory. It first consults the content of the creation operation currently
+// being performed (since the target may not have been created yet) and then
+// falls back to checking the filesystem.
+func (t *transitioner) symbolicLinkTargetIsDirectory(path, target string) bool {
+	// Resolve the target to a synchronization path. Portable targets are
+	// guaranteed not to reference locations outside the synchronization root.
+	var parentPath string
+	if path != "" {
+		parentPath = pathDir(path)
+	}
+	resolved := pathpkg.Join(parentPath, target)
+	if resolved == "." {
+		resolved = ""
+	}
+
+	// Compute the path of the resolved target relative to the content being
+	// created, if it falls within that content.
+	var relative string
+	var withinCreation bool
+	if t.creationTarget != nil {
+		if t.creationPath == "" {
+			relative, withinCreation = resolved, true
+		} else if resolved == t.creationPath {
+			relative, withinCreation = "", true
+		} else if strings.HasPrefix(resolved, t.creationPath+"/") {
+			relative, withinCreation = resolved[len(t.creationPath)+1:], true
+		}
+	}
+
+	// If the resolved target falls within the content being created, then
+	// attempt to locate it.
+	if withinCreation {
+		entry := t.creationTarget
+		if relative != "" {
+			for _, component := range strings.Split(relative, "/") {
+				if entry == nil || entry.Kind != EntryKind_Directory {
+					entry = nil
+					break
+				}
+				entry = entry.Contents[component]
+			}
+		}
+		if entry != nil {
+			return entry.Kind == EntryKind_Directory
+		}
+	}
+
+	// Otherwise fall back to checking the filesystem.
+	metadata, err := os.Stat(filepath.Join(t.root, filepath.FromSlash(resolved)))
+	return err == nil && metadata.IsDir()
+}
+
 // createDirectory creates the target directory at the specified path. If only a
 // portion of the directory can be created, an entry representing that portion
 // will be returned.
@@ -868,6 +1105,12 @@ func (t *transitioner) create(path string, target *Entry) *Entry {
 		return nil
 	}
 
+	// Record the creation operation being performed.
+	t.creationPath, t.creationTarget = path, target
+	defer func() {
+		t.creationPath, t.creationTarget = "", nil
+	}()
+
 	// Walk down to the parent of the target and compute the target's leaf name.
 	// If we are successful, defer closure of the parent.
 	parent, name, err := t.walkToParentAndComputeLeafName(path, false)
@@ -905,7 +1148,9 @@ func (t *transitioner) create(path string, target *Entry) *Entry {
 // reconciliation. The path to the provided synchronization root must be
 // absolute and normalized (using filepath.Clean). The function returns a slice
 // of the resulting entries, problems, and a boolean indicating whether or not
-// the provider was missing files.
+// the provider was missing files. If a trash is provided, then files that are
+// removed or overwritten will be retained in the trash. File modification times
+// are compared against the cache using the specified tolerance.
 func Transition(
 	ctx context.Context,
 	root string,
@@ -916,7 +1161,11 @@ func Transition(
 	defaultDirectoryPermissionMode filesystem.Mode,
 	defaultOwnership *filesystem.OwnershipSpecification,
 	recomposeUnicode bool,
+	unicodeNormalizationMode UnicodeNormalizationMode,
+	replacementMode ReplacementMode,
+	modificationTimeTolerance time.Duration,
 	provider Provider,
+	trash Trash,
 ) ([]*Entry, []*Problem, bool) {
 	// Extract the cancellation channel.
 	cancelled := ctx.Done()
@@ -932,7 +1181,11 @@ func Transition(
 		defaultOwnership:               defaultOwnership,
 		copyBuffer:                     make([]byte, transitionCopyBufferSize),
 		recomposeUnicode:               recomposeUnicode,
+		unicodeNormalizationMode:       unicodeNormalizationMode,
+		replacementMode:                replacementMode,
+		modificationTimeTolerance:      modificationTimeTolerance,
 		provider:                       provider,
+		trash:                          trash,
 	}
 
 	// Set up results.
@@ -678,9 +678,6 @@ func TestTransition(t *testing.T) {
 		},
 	}
 
-	// Create a hasher that we can use for testing.
-	hasher := newTestingHasher()
-
 	// Create a temporary directory that transition content providers can use
 	// for staging. We'll put this on the OS temporary directory so that we test
 	// same-device staging for the OS filesystem and cross-device staging for
@@ -727,10 +724,13 @@ func TestTransition(t *testing.T) {
 				backgroundCtx,
 				root,
 				nil, nil,
-				hasher, nil,
+				newTestingHasher, nil,
 				nil, nil,
 				behavior.ProbeMode_ProbeModeProbe,
 				test.symbolicLinkMode,
+				UnicodeNormalizationMode_UnicodeNormalizationModeNone,
+				0,
+				1,
 			)
 			if err != nil {
 				t.Errorf("%s: unable to perform scan of baseline on %s filesystem: %v",
@@ -765,7 +765,11 @@ func TestTransition(t *testing.T) {
 				0700,
 				nil,
 				snapshot.DecomposesUnicode,
+				UnicodeNormalizationMode_UnicodeNormalizationModeNone,
+				ReplacementMode_ReplacementModeRename,
+				0,
 				provider,
+				nil,
 			)
 
 			// Check results.
@@ -800,3 +804,254 @@ func TestTransition(t *testing.T) {
 		}
 	}
 }
+
+// TestTransitionSymbolicLinkTargetIsDirectory tests
+// transitioner.symbolicLinkTargetIsDirectory.
+func TestTransitionSymbolicLinkTargetIsDirectory(t *testing.T) {
+	// Create a synchronization root with an existing directory and file.
+	root := t.TempDir()
+	if err := os.Mkdir(filepath.Join(root, "existing directory"), 0700); err != nil {
+		t.Fatal("unable to create test directory:", err)
+	} else if err = os.WriteFile(filepath.Join(root, "existing file"), nil, 0600); err != nil {
+		t.Fatal("unable to create test file:", err)
+	}
+
+	// Create a transitioner that's in the process of creating content.
+	transitioner := &transitioner{
+		root:         root,
+		creationPath: "created",
+		creationTarget: &Entry{
+			Kind: EntryKind_Directory,
+			Contents: map[string]*Entry{
+				"directory": {Kind: EntryKind_Directory},
+				"file":      {Kind: EntryKind_File},
+				"link":      {Kind: EntryKind_SymbolicLink, Target: "directory"},
+			},
+		},
+	}
+
+	// Define test cases.
+	tests := []struct {
+		path     string
+		target   string
+		expected bool
+	}{
+		{"created/link", "directory", true},
+		{"created/link", "file", false},
+		{"created/link", "..", true},
+		{"created/link", "../existing directory", true},
+		{"created/link", "../existing file", false},
+		{"created/link", "../nonexistent", false},
+		{"link", "created", true},
+		{"link", "created/directory", true},
+		{"link", "created/file", false},
+		{"link", ".", true},
+	}
+
+	// Process test cases.
+	for _, test := range tests {
+		if result := transitioner.symbolicLinkTargetIsDirectory(test.path, test.target); result != test.expected {
+			t.Errorf("directory classification for %s -> %s (%t) does not match expected (%t)",
+				test.path, test.target, result, test.expected,
+			)
+		}
+	}
+}
+
+// TestTransitionAtomicReplacement tests Transition in atomic replacement mode.
+func TestTransitionAtomicReplacement(t *testing.T) {
+	// Create an empty synchronization root and staging storage.
+	root := t.TempDir()
+	storage := t.TempDir()
+
+	// Compute the target entry.
+	content := []byte("atomic content")
+	hasher := newTestingHasher()
+	hasher.Write(content)
+	target := &Entry{Kind: EntryKind_File, Digest: hasher.Sum(nil)}
+
+	// Perform a file creation in atomic replacement mode.
+	provider := &statingProvider{
+		testingProvider: &testingProvider{
+			storage:    storage,
+			contentMap: testingContentMap{"file": content},
+			hasher:     newTestingHasher(),
+		},
+	}
+	results, problems, missingFiles := Transition(
+		context.Background(),
+		root,
+		[]*Change{{Path: "file", New: target}},
+		nil,
+		SymbolicLinkMode_SymbolicLinkModePortable,
+		0600,
+		0700,
+		nil,
+		false,
+		UnicodeNormalizationMode_UnicodeNormalizationModeNone,
+		ReplacementMode_ReplacementModeAtomic,
+		0,
+		provider,
+		nil,
+	)
+
+	// Check the transition results.
+	if len(problems) > 0 {
+		t.Fatal("transition problems encountered:", problems[0].Error)
+	} else if missingFiles {
+		t.Fatal("transition reported missing files")
+	} else if len(results) != 1 || !results[0].Equal(target, true) {
+		t.Fatal("transition result does not match expected")
+	}
+
+	// Verify the on-disk contents and ensure that no intermediate files remain
+	// in either the synchronization root or staging storage.
+	if contents, err := os.ReadDir(root); err != nil {
+		t.Fatal("unable to read synchronization root:", err)
+	} else if len(contents) != 1 || contents[0].Name() != "file" {
+		t.Error("synchronization root contents do not match expected")
+	}
+	if data, err := os.ReadFile(filepath.Join(root, "file")); err != nil {
+		t.Fatal("unable to read transitioned file:", err)
+	} else if string(data) != string(content) {
+		t.Error("transitioned file contents do not match expected")
+	}
+	if contents, err := os.ReadDir(storage); err != nil {
+		t.Fatal("unable to read staging storage:", err)
+	} else if len(contents) != 0 {
+		t.Error("staged file not removed after atomic replacement")
+	}
+
+	// Since staging storage and the synchronization root reside on the same
+	// device, verify that the staged file was renamed into place rather than
+	// copied.
+	if info, err := os.Stat(filepath.Join(root, "file")); err != nil {
+		t.Fatal("unable to query transitioned file:", err)
+	} else if provider.staged == nil {
+		t.Fatal("staged file not provided")
+	} else if !os.SameFile(info, provider.staged) {
+		t.Error("staged file was not renamed into place")
+	}
+}
+
+// statingProvider is a Provider implementation for testing that wraps a
+// testingProvider and records metadata for the last provided file.
+type statingProvider struct {
+	*testingProvider
+	// staged is the metadata for the last provided file.
+	staged os.FileInfo
+}
+
+// Provide implements Provider.Provide.
+func (p *statingProvider) Provide(path string, digest []byte) (string, error) {
+	stagedPath, err := p.testingProvider.Provide(path, digest)
+	if err != nil {
+		return "", err
+	}
+	p.staged, err = os.Stat(stagedPath)
+	return stagedPath, err
+}
+
+// testingTrash is a Trash implementation for testing that retains files in a
+// flat directory.
+type testingTrash struct {
+	// storage is the directory in which files should be retained.
+	storage string
+}
+
+// Allocate implements Trash.Allocate.
+func (t *testingTrash) Allocate(path string) (string, error) {
+	return filepath.Join(t.storage, path), nil
+}
+
+// TestTransitionTrash tests that Transition retains removed and overwritten
+// files when a trash is provided.
+func TestTransitionTrash(t *testing.T) {
+	// Create a synchronization root with content to be removed and overwritten,
+	// as well as staging and trash storage.
+	root := t.TempDir()
+	storage := t.TempDir()
+	trash := &testingTrash{t.TempDir()}
+	if err := os.WriteFile(filepath.Join(root, "removed"), []byte("removed content"), 0600); err != nil {
+		t.Fatal("unable to create file to be removed:", err)
+	}
+	if err := os.WriteFile(filepath.Join(root, "overwritten"), []byte("original content"), 0600); err != nil {
+		t.Fatal("unable to create file to be overwritten:", err)
+	}
+
+	// Scan the synchronization root.
+	snapshot, cache, _, err := Scan(
+		context.Background(),
+		root,
+		nil, nil,
+		newTestingHasher, nil,
+		nil, nil,
+		behavior.ProbeMode_ProbeModeProbe,
+		SymbolicLinkMode_SymbolicLinkModePortable,
+		UnicodeNormalizationMode_UnicodeNormalizationModeNone,
+		0,
+		1,
+	)
+	if err != nil {
+		t.Fatal("unable to scan synchronization root:", err)
+	}
+
+	// Compute the replacement entry.
+	content := []byte("replacement content")
+	hasher := newTestingHasher()
+	hasher.Write(content)
+	replacement := &Entry{Kind: EntryKind_File, Digest: hasher.Sum(nil)}
+
+	// Perform the transition.
+	provider := &testingProvider{
+		storage:    storage,
+		contentMap: testingContentMap{"overwritten": content},
+		hasher:     newTestingHasher(),
+	}
+	_, problems, missingFiles := Transition(
+		context.Background(),
+		root,
+		[]*Change{
+			{Path: "removed", Old: snapshot.Content.Contents["removed"]},
+			{Path: "overwritten", Old: snapshot.Content.Contents["overwritten"], New: replacement},
+		},
+		cache,
+		SymbolicLinkMode_SymbolicLinkModePortable,
+		0600,
+		0700,
+		nil,
+		snapshot.DecomposesUnicode,
+		UnicodeNormalizationMode_UnicodeNormalizationModeNone,
+		ReplacementMode_ReplacementModeRename,
+		0,
+		provider,
+		trash,
+	)
+	if len(problems) > 0 {
+		t.Fatal("transition problems encountered:", problems[0].Error)
+	} else if missingFiles {
+		t.Fatal("transition reported missing files")
+	}
+
+	// Verify that the synchronization root contents were updated.
+	if _, err := os.Lstat(filepath.Join(root, "removed")); !os.IsNotExist(err) {
+		t.Error("removed file still exists in synchronization root")
+	}
+	if data, err := os.ReadFile(filepath.Join(root, "overwritten")); err != nil {
+		t.Fatal("unable to read overwritten file:", err)
+	} else if string(data) != string(content) {
+		t.Error("overwritten file contents do not match expected")
+	}
+
+	// Verify that the original contents were retained in the trash.
+	if data, err := os.ReadFile(filepath.Join(trash.storage, "removed")); err != nil {
+		t.Error("removed file not retained in trash:", err)
+	} else if string(data) != "removed content" {
+		t.Error("retained removed file contents do not match expected")
+	}
+	if data, err := os.ReadFile(filepath.Join(trash.storage, "overwritten")); err != nil {
+		t.Error("overwritten file not retained in trash:", err)
+	} else if string(data) != "original content" {
+		t.Error("retained overwritten file contents do not match expected")
+	}
+}
@@ -0,0 +1,97 @@
+package core
+
+import (
+	"fmt"
+
+	"golang.org/x/text/unicode/norm"
+)
+
+// IsDefault indicates whether or not the Unicode normalization mode is
+// UnicodeNormalizationMode_UnicodeNormalizationModeDefault.
+func (m UnicodeNormalizationMode) IsDefault() bool {
+	return m == UnicodeNormalizationMode_UnicodeNormalizationModeDefault
+}
+
+// MarshalText implements encoding.TextMarshaler.MarshalText.
+func (m UnicodeNormalizationMode) MarshalText() ([]byte, error) {
+	var result string
+	switch m {
+	case UnicodeNormalizationMode_UnicodeNormalizationModeDefault:
+	case UnicodeNormalizationMode_UnicodeNormalizationModeNone:
+		result = "none"
+	case UnicodeNormalizationMode_UnicodeNormalizationModeNFC:
+		result = "nfc"
+	case UnicodeNormalizationMode_UnicodeNormalizationModeNFD:
+		result = "nfd"
+	default:
+		result = "unknown"
+	}
+	return []byte(result), nil
+}
+
+// UnmarshalText implements encoding.TextUnmarshaler.UnmarshalText.
+func (m *UnicodeNormalizationMode) UnmarshalText(textBytes []byte) error {
+	// Convert the bytes to a string.
+	text := string(textBytes)
+
+	// Convert to a Unicode normalization mode.
+	switch text {
+	case "none":
+		*m = UnicodeNormalizationMode_UnicodeNormalizationModeNone
+	case "nfc":
+		*m = UnicodeNormalizationMode_UnicodeNormalizationModeNFC
+	case "nfd":
+		*m = UnicodeNormalizationMode_UnicodeNormalizationModeNFD
+	default:
+		return fmt.Errorf("unknown Unicode normalization mode specification: %s", text)
+	}
+
+	// Success.
+	return nil
+}
+
+// Supported indicates whether or not a particular Unicode normalization mode
+// is a valid, non-default value.
+func (m UnicodeNormalizationMode) Supported() bool {
+	switch m {
+	case UnicodeNormalizationMode_UnicodeNormalizationModeNone:
+		return true
+	case UnicodeNormalizationMode_UnicodeNormalizationModeNFC:
+		return true
+	case UnicodeNormalizationMode_UnicodeNormalizationModeNFD:
+		return true
+	default:
+		return false
+	}
+}
+
+// Description returns a human-readable description of a Unicode normalization
+// mode.
+func (m UnicodeNormalizationMode) Description() string {
+	switch m {
+	case UnicodeNormalizationMode_UnicodeNormalizationModeDefault:
+		return "Default"
+	case UnicodeNormalizationMode_UnicodeNormalizationModeNone:
+		return "None"
+	case UnicodeNormalizationMode_UnicodeNormalizationModeNFC:
+		return "NFC"
+	case UnicodeNormalizationMode_UnicodeNormalizationModeNFD:
+		return "NFD"
+	default:
+		return "Unknown"
+	}
+}
+
+// normalize normalizes a content name according to the Unicode normalization
+// mode. Content names are returned unmodified in
+// UnicodeNormalizationMode_UnicodeNormalizationModeNone.
+func (m UnicodeNormalizationMode) normalize(name string) string {
+	switch m {
+	case UnicodeNormalizationMode_UnicodeNormalizationModeNFC:
+		return norm.NFC.String(name)
+	case UnicodeNormalizationMode_UnicodeNormalizationModeNFD:
+		return norm.NFD.String(name)
+	default:
+		return name
+	}
+}
@@ -0,0 +1,161 @@
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// versions:
+// 	protoc-gen-go v1.28.0
+// 	protoc        v3.19.4
+// source: synchronization/core/unicode_normalization_mode.proto
+
+package core
+
+import (
+	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
+	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	reflect "reflect"
+	sync "sync"
+)
+
+const (
+	// Verify that this generated code is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(20 - protoimpl.MinVersion)
+	// Verify that runtime/protoimpl is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
+)
+
+// UnicodeNormalizationMode specifies the mode for normalizing Unicode content
+// names.
+type UnicodeNormalizationMode int32
+
+const (
+	// UnicodeNormalizationMode_UnicodeNormalizationModeDefault represents an
+	// unspecified Unicode normalization mode. It is not valid for use with Scan
+	// or Transition. It should be converted to one of the following values
+	// based on the desired default behavior.
+	UnicodeNormalizationMode_UnicodeNormalizationModeDefault UnicodeNormalizationMode = 0
+	// UnicodeNormalizationMode_UnicodeNormalizationModeNone specifies that
+	// content names should be used as they're stored on disk (with the
+	// exception of recomposition on filesystems that decompose names).
+	UnicodeNormalizationMode_UnicodeNormalizationModeNone UnicodeNormalizationMode = 1
+	// UnicodeNormalizationMode_UnicodeNormalizationModeNFC specifies that
+	// content names should be normalized to Unicode Normalization Form C
+	// (canonical composition).
+	UnicodeNormalizationMode_UnicodeNormalizationModeNFC UnicodeNormalizationMode = 2
+	// UnicodeNormalizationMode_UnicodeNormalizationModeNFD specifies that
+	// content names should be normalized to Unicode Normalization Form D
+	// (canonical decomposition).
+	UnicodeNormalizationMode_UnicodeNormalizationModeNFD UnicodeNormalizationMode = 3
+)
+
+// Enum value maps for UnicodeNormalizationMode.
+var (
+	UnicodeNormalizationMode_name = map[int32]string{
+		0: "UnicodeNormalizationModeDefault",
+		1: "UnicodeNormalizationModeNone",
+		2: "UnicodeNormalizationModeNFC",
+		3: "UnicodeNormalizationModeNFD",
+	}
+	UnicodeNormalizationMode_value = map[string]int32{
+		"UnicodeNormalizationModeDefault": 0,
+		"UnicodeNormalizationModeNone":    1,
+		"UnicodeNormalizationModeNFC":     2,
+		"UnicodeNormalizationModeNFD":     3,
+	}
+)
+
+func (x UnicodeNormalizationMode) Enum() *UnicodeNormalizationMode {
+	p := new(UnicodeNormalizationMode)
+	*p = x
+	return p
+}
+
+func (x UnicodeNormalizationMode) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (UnicodeNormalizationMode) Descriptor() protoreflect.EnumDescriptor {
+	return file_synchronization_core_unicode_normalization_mode_proto_enumTypes[0].Descriptor()
+}
+
+func (UnicodeNormalizationMode) Type() protoreflect.EnumType {
+	return &file_synchronization_core_unicode_normalization_mode_proto_enumTypes[0]
+}
+
+func (x UnicodeNormalizationMode) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use UnicodeNormalizationMode.Descriptor instead.
+func (UnicodeNormalizationMode) EnumDescriptor() ([]byte, []int) {
+	return file_synchronization_core_unicode_normalization_mode_proto_rawDescGZIP(), []int{0}
+}
+
+var File_synchronization_core_unicode_normalization_mode_proto protoreflect.FileDescriptor
+
+var file_synchronization_core_unicode_normalization_mode_proto_rawDesc = []byte{
+	0x0a, 0x35, 0x73, 0x79, 0x6e, 0x63, 0x68, 0x72, 0x6f, 0x6e, 0x69, 0x7a, 0x61, 0x74, 0x69, 0x6f,
+	0x6e, 0x2f, 0x63, 0x6f, 0x72, 0x65, 0x2f, 0x75, 0x6e, 0x69, 0x63, 0x6f, 0x64, 0x65, 0x5f, 0x6e,
+	0x6f, 0x72, 0x6d, 0x61, 0x6c, 0x69, 0x7a, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x5f, 0x6d, 0x6f, 0x64,
+	0x65, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x12, 0x04, 0x63, 0x6f, 0x72, 0x65, 0x2a, 0xa3, 0x01,
+	0x0a, 0x18, 0x55, 0x6e, 0x69, 0x63, 0x6f, 0x64, 0x65, 0x4e, 0x6f, 0x72, 0x6d, 0x61, 0x6c, 0x69,
+	0x7a, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x4d, 0x6f, 0x64, 0x65, 0x12, 0x23, 0x0a, 0x1f, 0x55, 0x6e,
+	0x69, 0x63, 0x6f, 0x64, 0x65, 0x4e, 0x6f, 0x72, 0x6d, 0x61, 0x6c, 0x69, 0x7a, 0x61, 0x74, 0x69,
+	0x6f, 0x6e, 0x4d, 0x6f, 0x64, 0x65, 0x44, 0x65, 0x66, 0x61, 0x75, 0x6c, 0x74, 0x10, 0x00, 0x12,
+	0x20, 0x0a, 0x1c, 0x55, 0x6e, 0x69, 0x63, 0x6f, 0x64, 0x65, 0x4e, 0x6f, 0x72, 0x6d, 0x61, 0x6c,
+	0x69, 0x7a, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x4d, 0x6f, 0x64, 0x65, 0x4e, 0x6f, 0x6e, 0x65, 0x10,
+	0x01, 0x12, 0x1f, 0x0a, 0x1b, 0x55, 0x6e, 0x69, 0x63, 0x6f, 0x64, 0x65, 0x4e, 0x6f, 0x72, 0x6d,
+	0x61, 0x6c, 0x69, 0x7a, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x4d, 0x6f, 0x64, 0x65, 0x4e, 0x46, 0x43,
+	0x10, 0x02, 0x12, 0x1f, 0x0a, 0x1b, 0x55, 0x6e, 0x69, 0x63, 0x6f, 0x64, 0x65, 0x4e, 0x6f, 0x72,
+	0x6d, 0x61, 0x6c, 0x69, 0x7a, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x4d, 0x6f, 0x64, 0x65, 0x4e, 0x46,
+	0x44, 0x10, 0x03, 0x42, 0x38, 0x5a, 0x36, 0x67, 0x69, 0x74, 0x68, 0x75, 0x62, 0x2e, 0x63, 0x6f,
+	0x6d, 0x2f, 0x6d, 0x75, 0x74, 0x61, 0x67, 0x65, 0x6e, 0x2d, 0x69, 0x6f, 0x2f, 0x6d, 0x75, 0x74,
+	0x61, 0x67, 0x65, 0x6e, 0x2f, 0x70, 0x6b, 0x67, 0x2f, 0x73, 0x79, 0x6e, 0x63, 0x68, 0x72, 0x6f,
+	0x6e, 0x69, 0x7a, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x2f, 0x63, 0x6f, 0x72, 0x65, 0x62, 0x06, 0x70,
+	0x72, 0x6f, 0x74, 0x6f, 0x33,
+}
+
+var (
+	file_synchronization_core_unicode_normalization_mode_proto_rawDescOnce sync.Once
+	file_synchronization_core_unicode_normalization_mode_proto_rawDescData = file_synchronization_core_unicode_normalization_mode_proto_rawDesc
+)
+
+func file_synchronization_core_unicode_normalization_mode_proto_rawDescGZIP() []byte {
+	file_synchronization_core_unicode_normalization_mode_proto_rawDescOnce.Do(func() {
+		file_synchronization_core_unicode_normalization_mode_proto_rawDescData = protoimpl.X.CompressGZIP(file_synchronization_core_unicode_normalization_mode_proto_rawDescData)
+	})
+	return file_synchronization_core_unicode_normalization_mode_proto_rawDescData
+}
+
+var file_synchronization_core_unicode_normalization_mode_proto_enumTypes = make([]protoimpl.EnumInfo, 1)
+var file_synchronization_core_unicode_normalization_mode_proto_goTypes = []interface{}{
+	(UnicodeNormalizationMode)(0), // 0: core.UnicodeNormalizationMode
+}
+var file_synchronization_core_unicode_normalization_mode_proto_depIdxs = []int32{
+	0, // [0:0] is the sub-list for method output_type
+	0, // [0:0] is the sub-list for method input_type
+	0, // [0:0] is the sub-list for extension type_name
+	0, // [0:0] is the sub-list for extension extendee
+	0, // [0:0] is the sub-list for field type_name
+}
+
+func init() { file_synchronization_core_unicode_normalization_mode_proto_init() }
+func file_synchronization_core_unicode_normalization_mode_proto_init() {
+	if File_synchronization_core_unicode_normalization_mode_proto != nil {
+		return
+	}
+	type x struct{}
+	out := protoimpl.TypeBuilder{
+		File: protoimpl.DescBuilder{
+			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
+			RawDescriptor: file_synchronization_core_unicode_normalization_mode_proto_rawDesc,
+			NumEnums:      1,
+			NumMessages:   0,
+			NumExtensions: 0,
+			NumServices:   0,
+		},
+		GoTypes:           file_synchronization_core_unicode_normalization_mode_proto_goTypes,
+		DependencyIndexes: file_synchronization_core_unicode_normalization_mode_proto_depIdxs,
+		EnumInfos:         file_synchronization_core_unicode_normalization_mode_proto_enumTypes,
+	}.Build()
+	File_synchronization_core_unicode_normalization_mode_proto = out.File
+	file_synchronization_core_unicode_normalization_mode_proto_rawDesc = nil
+	file_synchronization_core_unicode_normalization_mode_proto_goTypes = nil
+	file_synchronization_core_unicode_normalization_mode_proto_depIdxs = nil
+}
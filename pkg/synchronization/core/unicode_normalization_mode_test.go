@@ -0,0 +1,150 @@
+package core
+
+import (
+	"testing"
+)
+
+// TestUnicodeNormalizationModeIsDefault tests
+// UnicodeNormalizationMode.IsDefault.
+func TestUnicodeNormalizationModeIsDefault(t *testing.T) {
+	// Define test cases.
+	tests := []struct {
+		value    UnicodeNormalizationMode
+		expected bool
+	}{
+		{UnicodeNormalizationMode_UnicodeNormalizationModeDefault - 1, false},
+		{UnicodeNormalizationMode_UnicodeNormalizationModeDefault, true},
+		{UnicodeNormalizationMode_UnicodeNormalizationModeNone, false},
+		{UnicodeNormalizationMode_UnicodeNormalizationModeNFC, false},
+		{UnicodeNormalizationMode_UnicodeNormalizationModeNFD, false},
+		{UnicodeNormalizationMode_UnicodeNormalizationModeNFD + 1, false},
+	}
+
+	// Process test cases.
+	for i, test := range tests {
+		if result := test.value.IsDefault(); result && !test.expected {
+			t.Errorf("test index %d: value was unexpectedly classified as default", i)
+		} else if !result && test.expected {
+			t.Errorf("test index %d: value was unexpectedly classified as non-default", i)
+		}
+	}
+}
+
+// TestUnicodeNormalizationModeUnmarshalText tests
+// UnicodeNormalizationMode.UnmarshalText.
+func TestUnicodeNormalizationModeUnmarshalText(t *testing.T) {
+	// Define test cases.
+	tests := []struct {
+		text          string
+		expectedMode  UnicodeNormalizationMode
+		expectFailure bool
+	}{
+		{"", UnicodeNormalizationMode_UnicodeNormalizationModeDefault, true},
+		{"asdf", UnicodeNormalizationMode_UnicodeNormalizationModeDefault, true},
+		{"none", UnicodeNormalizationMode_UnicodeNormalizationModeNone, false},
+		{"nfc", UnicodeNormalizationMode_UnicodeNormalizationModeNFC, false},
+		{"nfd", UnicodeNormalizationMode_UnicodeNormalizationModeNFD, false},
+	}
+
+	// Process test cases.
+	for _, test := range tests {
+		var mode UnicodeNormalizationMode
+		if err := mode.UnmarshalText([]byte(test.text)); err != nil {
+			if !test.expectFailure {
+				t.Errorf("unable to unmarshal text (%s): %s", test.text, err)
+			}
+		} else if test.expectFailure {
+			t.Error("unmarshaling succeeded unexpectedly for text:", test.text)
+		} else if mode != test.expectedMode {
+			t.Errorf(
+				"unmarshaled mode (%s) does not match expected (%s)",
+				mode,
+				test.expectedMode,
+			)
+		}
+	}
+}
+
+// TestUnicodeNormalizationModeSupported tests
+// UnicodeNormalizationMode.Supported.
+func TestUnicodeNormalizationModeSupported(t *testing.T) {
+	// Set up test cases.
+	testCases := []struct {
+		mode            UnicodeNormalizationMode
+		expectSupported bool
+	}{
+		{UnicodeNormalizationMode_UnicodeNormalizationModeDefault, false},
+		{UnicodeNormalizationMode_UnicodeNormalizationModeNone, true},
+		{UnicodeNormalizationMode_UnicodeNormalizationModeNFC, true},
+		{UnicodeNormalizationMode_UnicodeNormalizationModeNFD, true},
+		{(UnicodeNormalizationMode_UnicodeNormalizationModeNFD + 1), false},
+	}
+
+	// Process test cases.
+	for _, testCase := range testCases {
+		if supported := testCase.mode.Supported(); supported != testCase.expectSupported {
+			t.Errorf(
+				"mode support status (%t) does not match expected (%t)",
+				supported,
+				testCase.expectSupported,
+			)
+		}
+	}
+}
+
+// TestUnicodeNormalizationModeDescription tests
+// UnicodeNormalizationMode.Description.
+func TestUnicodeNormalizationModeDescription(t *testing.T) {
+	// Set up test cases.
+	testCases := []struct {
+		mode                UnicodeNormalizationMode
+		expectedDescription string
+	}{
+		{UnicodeNormalizationMode_UnicodeNormalizationModeDefault, "Default"},
+		{UnicodeNormalizationMode_UnicodeNormalizationModeNone, "None"},
+		{UnicodeNormalizationMode_UnicodeNormalizationModeNFC, "NFC"},
+		{UnicodeNormalizationMode_UnicodeNormalizationModeNFD, "NFD"},
+		{(UnicodeNormalizationMode_UnicodeNormalizationModeNFD + 1), "Unknown"},
+	}
+
+	// Process test cases.
+	for _, testCase := range testCases {
+		if description := testCase.mode.Description(); description != testCase.expectedDescription {
+			t.Errorf(
+				"mode description (%s) does not match expected (%s)",
+				description,
+				testCase.expectedDescription,
+			)
+		}
+	}
+}
+
+// TestUnicodeNormalizationModeNormalize tests
+// UnicodeNormalizationMode.normalize.
+func TestUnicodeNormalizationModeNormalize(t *testing.T) {
+	// Set up test cases.
+	testCases := []struct {
+		mode     UnicodeNormalizationMode
+		name     string
+		expected string
+	}{
+		{UnicodeNormalizationMode_UnicodeNormalizationModeNone, "caf\u00e9", "caf\u00e9"},
+		{UnicodeNormalizationMode_UnicodeNormalizationModeNone, "cafe\u0301", "cafe\u0301"},
+		{UnicodeNormalizationMode_UnicodeNormalizationModeNFC, "caf\u00e9", "caf\u00e9"},
+		{UnicodeNormalizationMode_UnicodeNormalizationModeNFC, "cafe\u0301", "caf\u00e9"},
+		{UnicodeNormalizationMode_UnicodeNormalizationModeNFD, "caf\u00e9", "cafe\u0301"},
+		{UnicodeNormalizationMode_UnicodeNormalizationModeNFD, "cafe\u0301", "cafe\u0301"},
+	}
+
+	// Process test cases.
+	for _, testCase := range testCases {
+		if normalized := testCase.mode.normalize(testCase.name); normalized != testCase.expected {
+			t.Errorf(
+				"normalized name (%q) does not match expected (%q) in mode %s",
+				normalized,
+				testCase.expected,
+				testCase.mode.Description(),
+			)
+		}
+	}
+}
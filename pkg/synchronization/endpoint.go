@@ -26,11 +26,15 @@ type Endpoint interface {
 	// which case the transfer of the initial snapshot may be less than optimal.
 	// The full parameter forces the function to perform a full (but still warm)
 	// scan, avoiding any acceleration that might be available on the endpoint.
+	// The rehashPaths parameter specifies root-relative paths at and beneath
+	// which any cached digests should be ignored, forcing the corresponding
+	// file content to be re-hashed (which also implies a full scan). Passing
+	// the root path ("") forces a full, cold scan that re-hashes all content.
 	// The function returns the scan result, any error that occurred while
 	// trying to perform the scan, and a boolean indicating whether or not to
 	// re-try the scan if an error occurred. Any non-fatal problems encountered
 	// during the scan can be extracted from the resulting content.
-	Scan(ctx context.Context, ancestor *core.Entry, full bool) (*core.Snapshot, error, bool)
+	Scan(ctx context.Context, ancestor *core.Entry, full bool, rehashPaths []string) (*core.Snapshot, error, bool)
 
 	// Stage performs file staging on the endpoint. It accepts a list of file
 	// paths and a separate list of desired digests corresponding to those
@@ -69,6 +73,19 @@ type Endpoint interface {
 	// cancellation until they're all done anyway.
 	Transition(ctx context.Context, transitions []*core.Change) ([]*core.Entry, []*core.Problem, bool, error)
 
+	// Versions lists the retained versions of the file at the specified path,
+	// ordered from newest to oldest. If restore is non-empty, then the version
+	// with that identifier is first restored to the specified path. Any
+	// non-fatal problem that prevents listing or restoration (e.g. versioning
+	// being disabled or the requested version not existing) is returned as a
+	// problem description rather than an error.
+	Versions(path, restore string) ([]*FileVersion, string, error)
+
+	// Capabilities returns the capabilities of the filesystem on which the
+	// endpoint's synchronization root resides, as probed when the endpoint was
+	// created. It may return nil if probing failed.
+	Capabilities() *FilesystemCapabilities
+
 	// Shutdown terminates any resources associated with the endpoint. For local
 	// endpoints, Shutdown will not preempt calls, but for remote endpoints it
 	// will because it closes the underlying connection to the endpoint
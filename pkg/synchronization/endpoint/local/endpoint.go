@@ -6,11 +6,14 @@ import (
 	"fmt"
 	"hash"
 	"io"
+	"os"
 	"path/filepath"
+	"runtime/debug"
 	"strings"
 	"sync"
 	"time"
 
+	"github.com/mutagen-io/mutagen/pkg/comparison"
 	"github.com/mutagen-io/mutagen/pkg/encoding"
 	"github.com/mutagen-io/mutagen/pkg/filesystem"
 	"github.com/mutagen-io/mutagen/pkg/filesystem/behavior"
@@ -34,6 +37,18 @@ const (
 	// triggering of scan operations by the non-recursive watch in watchPoll
 	// will be coalesced.
 	watchPollScanSignalCoalescingWindow = 10 * time.Millisecond
+	// maximumVCSIgnoreRescans is the maximum number of re-scans that will be
+	// performed in a single scan operation due to changes in the patterns
+	// imported from VCS ignore files.
+	maximumVCSIgnoreRescans = 3
+	// minimumBeforeApplyBackoff is the delay imposed before re-running
+	// before-apply hooks after they first veto a transition operation. The
+	// delay doubles with each consecutive veto, up to
+	// maximumBeforeApplyBackoff.
+	minimumBeforeApplyBackoff = 5 * time.Second
+	// maximumBeforeApplyBackoff is the maximum delay imposed before re-running
+	// before-apply hooks after they veto a transition operation.
+	maximumBeforeApplyBackoff = 2 * time.Minute
 )
 
 // reifiedWatchMode describes a fully reified watch mode based on the watch mode
@@ -55,6 +70,12 @@ type endpoint struct {
 	// logger is the underlying logger. This field is static and thus safe for
 	// concurrent usage.
 	logger *logging.Logger
+	// sessionIdentifier is the identifier of the session to which the endpoint
+	// belongs. This field is static and thus safe for concurrent reads.
+	sessionIdentifier string
+	// alpha indicates whether or not the endpoint is the alpha endpoint. This
+	// field is static and thus safe for concurrent reads.
+	alpha bool
 	// root is the synchronization root. This field is static and thus safe for
 	// concurrent reads.
 	root string
@@ -71,15 +92,36 @@ type endpoint struct {
 	// accelerationAllowed indicates whether or not scan acceleration is
 	// allowed. This field is static and thus safe for concurrent reads.
 	accelerationAllowed bool
+	// scanConcurrency is the maximum number of Goroutines to use for scanning.
+	// This field is static and thus safe for concurrent reads.
+	scanConcurrency uint32
+	// memoryBudget is the approximate cache memory usage above which the
+	// endpoint will spill its caches to disk after scanning. This field is
+	// static and thus safe for concurrent reads.
+	memoryBudget uint64
+	// cachePath is the path to the on-disk cache. This field is static and
+	// thus safe for concurrent reads.
+	cachePath string
 	// probeMode is the probe mode. This field is static and thus safe for
 	// concurrent reads.
 	probeMode behavior.ProbeMode
 	// symbolicLinkMode is the symbolic link mode. This field is static and thus
 	// safe for concurrent reads.
 	symbolicLinkMode core.SymbolicLinkMode
+	// unicodeNormalizationMode is the Unicode normalization mode. This field is
+	// static and thus safe for concurrent reads.
+	unicodeNormalizationMode core.UnicodeNormalizationMode
+	// replacementMode is the file replacement mode. This field is static and
+	// thus safe for concurrent reads.
+	replacementMode core.ReplacementMode
 	// ignores are the path ignore specifications. This field is static and thus
 	// safe for concurrent reads.
 	ignores []string
+	// importVCSIgnores indicates whether or not patterns from Git ignore files
+	// within the synchronization root should be imported as ignores. This is
+	// only enabled on alpha endpoints. This field is static and thus safe for
+	// concurrent reads.
+	importVCSIgnores bool
 	// defaultFileMode is the default file permission mode to use in "portable"
 	// permission propagation. This field is static and thus safe for concurrent
 	// reads.
@@ -92,6 +134,27 @@ type endpoint struct {
 	// "portable" permission propagation. This field is static and thus safe for
 	// concurrent reads.
 	defaultOwnership *filesystem.OwnershipSpecification
+	// afterSync are the commands to run after a transition operation that
+	// modifies the synchronization root. This field is static and thus safe for
+	// concurrent reads.
+	afterSync []string
+	// beforeApply are the commands to run before a transition operation. If any
+	// of these commands fail, then the transition operation is aborted. This
+	// field is static and thus safe for concurrent reads.
+	beforeApply []string
+	// minimumFreeSpace is the amount of disk space that must remain available
+	// on the filesystem containing the synchronization root after staged files
+	// are moved into place by a transition operation. This field is static and
+	// thus safe for concurrent reads.
+	minimumFreeSpace uint64
+	// modificationTimeTolerance is the tolerance used when comparing file
+	// modification times against those in the cache. This field is static and
+	// thus safe for concurrent reads.
+	modificationTimeTolerance time.Duration
+	// capabilities are the probed capabilities of the filesystem on which the
+	// synchronization root resides. They may be nil if probing failed. This
+	// field is static and thus safe for concurrent reads.
+	capabilities *synchronization.FilesystemCapabilities
 	// workerCancel cancels any background worker Goroutines for the endpoint.
 	// This field is static and thus safe for concurrent invocation.
 	workerCancel context.CancelFunc
@@ -122,12 +185,13 @@ type endpoint struct {
 	// timer-based signal)). This field is static and never closed, and is thus
 	// safe for concurrent send operations.
 	recursiveWatchRetryEstablish chan struct{}
-	// scanLock serializes access to accelerate, recheckPaths, snapshot, hasher,
-	// cache, ignoreCache, cacheWriteError, and lastScanEntryCount. This lock is
-	// not necessitated by the Endpoint interface (which doesn't permit
-	// concurrent usage), but rather the endpoint's background worker Goroutines
-	// for cache saving and filesystem watching. This lock also notably excludes
-	// coverage of scannedSinceLastStageCall, scannedSinceLastTransitionCall,
+	// scanLock serializes access to accelerate, recheckPaths, snapshot,
+	// hasherFactory, cache, cacheSpilled, ignoreCache, vcsIgnores,
+	// cacheWriteError, and lastScanEntryCount. This lock is not necessitated
+	// by the Endpoint interface (which doesn't permit concurrent usage), but
+	// rather the endpoint's background worker Goroutines for cache saving and
+	// filesystem watching. This lock also notably excludes coverage of
+	// scannedSinceLastStageCall, scannedSinceLastTransitionCall,
 	// lastReturnedScanCache, lastReturnedScanSnapshotDecomposesUnicode, which
 	// are only updated by Scan and read by Stage and Transition, thus making
 	// them safe under Endpoint's (non-concurrent) interface.
@@ -141,13 +205,21 @@ type endpoint struct {
 	recheckPaths map[string]bool
 	// snapshot is the snapshot from the last scan.
 	snapshot *core.Snapshot
-	// hasher is the hasher used for scans.
-	hasher hash.Hash
-	// cache is the cache from the last successful scan on the endpoint.
+	// hasherFactory creates the hashers used for scans.
+	hasherFactory func() hash.Hash
+	// cache is the cache from the last successful scan on the endpoint. It is
+	// nil if the cache has been spilled to disk.
 	cache *core.Cache
+	// cacheSpilled indicates that the cache from the last successful scan has
+	// been spilled to disk (at cachePath) and released from memory in order
+	// to stay within the memory budget.
+	cacheSpilled bool
 	// ignoreCache is the ignore cache from the last successful scan on the
 	// endpoint.
 	ignoreCache core.IgnoreCache
+	// vcsIgnores are the ignore patterns imported from Git ignore files during
+	// the last successful scan on the endpoint.
+	vcsIgnores []string
 	// cacheWriteError is the last error encountered when trying to write the
 	// cache to disk, if any.
 	cacheWriteError error
@@ -163,6 +235,8 @@ type endpoint struct {
 	// returned by Scan. This may be different than cache and is tracked
 	// separately because Transition (in order to function correctly) requires
 	// the cache corresponding to the snapshot that resulted in its operations.
+	// It is nil if the cache was spilled to disk after the scan, in which case
+	// it is loaded from disk when required.
 	lastReturnedScanCache *core.Cache
 	// lastReturnedScanSnapshotDecomposesUnicode is the value of
 	// DecomposesUnicode from the last snapshot returned by Scan. Despite very
@@ -174,6 +248,21 @@ type endpoint struct {
 	// stager will only be used in at most one of Stage or Transition methods at
 	// any given time.
 	stager *stager
+	// beforeApplyBackoff is the delay that must elapse after beforeApplyVeto
+	// before before-apply hooks are run again, or 0 if they didn't veto the
+	// last transition operation. Like stager, it is only used by Transition.
+	beforeApplyBackoff time.Duration
+	// beforeApplyVeto is the time at which before-apply hooks last vetoed a
+	// transition operation. Like stager, it is only used by Transition.
+	beforeApplyVeto time.Time
+	// trash is the trash used to retain removed and overwritten files. It is
+	// nil if files aren't being retained. Like stager, it is not safe for
+	// concurrent usage, but will only be used by the Transition method.
+	trash *trash
+	// versioner is the versioner used to retain previous versions of removed
+	// and overwritten files. It is nil if versioning is disabled. It has the
+	// same concurrency characteristics as trash.
+	versioner *versioner
 }
 
 // NewEndpoint creates a new local endpoint instance using the specified session
@@ -207,6 +296,12 @@ func NewEndpoint(
 		maximumStagingFileSize = version.DefaultMaximumStagingFileSize()
 	}
 
+	// Determine the maximum total staging size.
+	maximumStagingTotalSize := configuration.MaximumStagingTotalSize
+	if maximumStagingTotalSize == 0 {
+		maximumStagingTotalSize = version.DefaultMaximumStagingTotalSize()
+	}
+
 	// Compute the effective watch mode.
 	watchMode := configuration.WatchMode
 	if watchMode.IsDefault() {
@@ -239,17 +334,47 @@ func NewEndpoint(
 	}
 	accelerationAllowed := scanMode == synchronization.ScanMode_ScanModeAccelerated
 
+	// Compute the effective scan concurrency.
+	scanConcurrency := configuration.ScanConcurrency
+	if scanConcurrency == 0 {
+		scanConcurrency = version.DefaultScanConcurrency()
+	}
+
+	// Compute the effective memory budget.
+	memoryBudget := configuration.MemoryBudget
+	if memoryBudget == 0 {
+		memoryBudget = version.DefaultMemoryBudget()
+	}
+
 	// Compute the effective probe mode.
 	probeMode := configuration.ProbeMode
 	if probeMode.IsDefault() {
 		probeMode = version.DefaultProbeMode()
 	}
 
-	// Compute the effective symbolic link mode.
+	// Compute the effective symbolic link mode. If symbolic links are being
+	// followed, then scan acceleration isn't allowed, because modifications to
+	// followed content outside of the synchronization root won't be detected
+	// by filesystem watching.
 	symbolicLinkMode := configuration.SymbolicLinkMode
 	if symbolicLinkMode.IsDefault() {
 		symbolicLinkMode = version.DefaultSymbolicLinkMode()
 	}
+	if symbolicLinkMode == core.SymbolicLinkMode_SymbolicLinkModeFollow {
+		accelerationAllowed = false
+	}
+
+	// Compute the effective Unicode normalization mode.
+	unicodeNormalizationMode := configuration.UnicodeNormalizationMode
+	if unicodeNormalizationMode.IsDefault() {
+		unicodeNormalizationMode = version.DefaultUnicodeNormalizationMode()
+	}
+
+	// Compute the effective replacement mode.
+	replacementMode := configuration.ReplacementMode
+	if replacementMode.IsDefault() {
+		replacementMode = version.DefaultReplacementMode()
+	}
 
 	// Compute the effective VCS ignore mode.
 	ignoreVCSMode := configuration.IgnoreVCSMode
@@ -374,6 +499,43 @@ func NewEndpoint(
 		return nil, fmt.Errorf("unable to compute staging root: %w", err)
 	}
 
+	// If a trash directory has been specified, then create the trash.
+	var endpointTrash *trash
+	if configuration.TrashDirectory != "" {
+		trashDirectory, err := filesystem.Normalize(configuration.TrashDirectory)
+		if err != nil {
+			return nil, fmt.Errorf("unable to normalize trash directory: %w", err)
+		}
+		trashRetentionPeriod := configuration.TrashRetentionPeriod
+		if trashRetentionPeriod == 0 {
+			trashRetentionPeriod = version.DefaultTrashRetentionPeriod()
+		}
+		maximumTrashSize := configuration.MaximumTrashSize
+		if maximumTrashSize == 0 {
+			maximumTrashSize = version.DefaultMaximumTrashSize()
+		}
+		endpointTrash = newTrash(
+			pathForTrashRoot(trashDirectory, sessionIdentifier, alpha),
+			filepath.Base(root),
+			time.Duration(trashRetentionPeriod)*time.Second,
+			maximumTrashSize,
+		)
+	}
+
+	// If file versioning has been enabled, then create the versioner. Since
+	// both the trash and the versioner intercept removed and overwritten
+	// files, they can't be used simultaneously.
+	var endpointVersioner *versioner
+	if configuration.VersionCount > 0 {
+		if endpointTrash != nil {
+			return nil, errors.New("trash directory and file versioning cannot be used simultaneously")
+		}
+		endpointVersioner = newVersioner(
+			pathForVersionsRoot(root, sessionIdentifier, alpha),
+			configuration.VersionCount,
+		)
+	}
+
 	// HACK: If non-default ownership or permissions have been set and the
 	// synchronization root is a volume mount point in a Mutagen sidecar
 	// container with no pre-existing content, then set the ownership and
@@ -392,6 +554,34 @@ func NewEndpoint(
 		}
 	}
 
+	// Compute the effective minimum free space.
+	minimumFreeSpace := configuration.MinimumFreeSpace
+	if minimumFreeSpace == 0 {
+		minimumFreeSpace = version.DefaultMinimumFreeSpace()
+	}
+
+	// Probe the capabilities of the filesystem on which the synchronization
+	// root resides. Failure here isn't fatal, since these capabilities are
+	// only informational.
+	capabilities, err := synchronization.ProbeFilesystemCapabilities(root, probeMode)
+	if err != nil {
+		logger.Warn("Unable to probe filesystem capabilities:", err)
+	}
+
+	// Compute the effective modification time tolerance. If the filesystem
+	// records modification times more coarsely than the configured tolerance,
+	// then we widen the tolerance to match that granularity.
+	modificationTimeToleranceMilliseconds := configuration.ModificationTimeTolerance
+	if modificationTimeToleranceMilliseconds == 0 {
+		modificationTimeToleranceMilliseconds = version.DefaultModificationTimeTolerance()
+	}
+	modificationTimeTolerance := time.Duration(modificationTimeToleranceMilliseconds) * time.Millisecond
+	if capabilities != nil {
+		if granularity := time.Duration(capabilities.ModificationTimeGranularity); granularity > modificationTimeTolerance {
+			modificationTimeTolerance = granularity
+		}
+	}
+
 	// Create a cancellable context in which the endpoint's background worker
 	// Goroutines will operate.
 	workerCtx, workerCancel := context.WithCancel(context.Background())
@@ -406,31 +596,47 @@ func NewEndpoint(
 	// Create the endpoint.
 	endpoint := &endpoint{
 		logger:                       logger,
+		sessionIdentifier:            sessionIdentifier,
+		alpha:                        alpha,
 		root:                         root,
 		readOnly:                     readOnly,
 		maximumEntryCount:            maximumEntryCount,
 		watchMode:                    actualWatchMode,
 		accelerationAllowed:          accelerationAllowed,
+		scanConcurrency:              scanConcurrency,
+		memoryBudget:                 memoryBudget,
+		cachePath:                    cachePath,
 		probeMode:                    probeMode,
 		symbolicLinkMode:             symbolicLinkMode,
+		unicodeNormalizationMode:     unicodeNormalizationMode,
+		replacementMode:              replacementMode,
 		ignores:                      ignores,
+		importVCSIgnores:             configuration.IgnoreVCSIgnores && alpha,
 		defaultFileMode:              defaultFileMode,
 		defaultDirectoryMode:         defaultDirectoryMode,
 		defaultOwnership:             defaultOwnership,
+		afterSync:                    configuration.AfterSync,
+		beforeApply:                  configuration.BeforeApply,
+		minimumFreeSpace:             minimumFreeSpace,
+		modificationTimeTolerance:    modificationTimeTolerance,
+		capabilities:                 capabilities,
 		workerCancel:                 workerCancel,
 		saveCacheSignal:              saveCacheSignal,
 		saveCacheDone:                saveCacheDone,
 		watchDone:                    watchDone,
 		pollSignal:                   state.NewCoalescer(pollSignalCoalescingWindow),
 		recursiveWatchRetryEstablish: make(chan struct{}),
-		hasher:                       version.Hasher(),
+		hasherFactory:                version.Hasher,
 		cache:                        cache,
 		stager: newStager(
 			stagingRoot,
 			hideStagingRoot,
 			version.Hasher(),
 			maximumStagingFileSize,
+			maximumStagingTotalSize,
 		),
+		trash:     endpointTrash,
+		versioner: endpointVersioner,
 	}
 
 	// Start the cache saving Goroutine.
@@ -489,9 +695,16 @@ func (e *endpoint) saveCache(ctx context.Context, cachePath string, signal <-cha
 			// Grab the scan lock.
 			e.scanLock.Lock()
 
-			// If the cache hasn't changed since the last write, then skip this
-			// save request.
-			if e.cache == lastSavedCache {
+			// If the cache has been spilled to disk (in which case it's already
+			// been saved), then release our reference to the last saved cache
+			// (so that it doesn't count against the memory budget) and skip
+			// this save request. Likewise, if the cache hasn't changed since
+			// the last write, then skip this save request.
+			if e.cacheSpilled {
+				lastSavedCache = nil
+				e.scanLock.Unlock()
+				continue
+			} else if e.cache == lastSavedCache {
 				e.scanLock.Unlock()
 				continue
 			}
@@ -931,33 +1144,189 @@ func (e *endpoint) Poll(ctx context.Context) error {
 	return nil
 }
 
+// loadSpilledCache loads the cache that was last spilled to disk.
+func (e *endpoint) loadSpilledCache() (*core.Cache, error) {
+	cache := &core.Cache{}
+	if err := encoding.LoadAndUnmarshalProtobuf(e.cachePath, cache); err != nil {
+		return nil, fmt.Errorf("unable to load spilled cache: %w", err)
+	} else if err = cache.EnsureValid(); err != nil {
+		return nil, fmt.Errorf("invalid spilled cache: %w", err)
+	}
+	return cache, nil
+}
+
+// spillCache saves the cache to disk and releases it (along with the ignore
+// cache, which only serves to accelerate scans) from memory. The caller must
+// hold the scan lock.
+func (e *endpoint) spillCache() error {
+	// Save the cache to disk.
+	if err := encoding.MarshalAndSaveProtobuf(e.cachePath, e.cache); err != nil {
+		return fmt.Errorf("unable to save cache: %w", err)
+	}
+
+	// Release the caches from memory. If the cache corresponding to the last
+	// returned scan is different (i.e. this is a background scan), then we
+	// have to retain it, because the on-disk copy will no longer match it.
+	if e.lastReturnedScanCache == e.cache {
+		e.lastReturnedScanCache = nil
+	}
+	e.cache = nil
+	e.cacheSpilled = true
+	e.ignoreCache = nil
+
+	// Return the freed memory to the operating system.
+	debug.FreeOSMemory()
+
+	// Success.
+	return nil
+}
+
+// restoreCache reloads the cache if it has been spilled to disk. If the cache
+// corresponding to the last returned scan was spilled, then it's also restored
+// so that Transition doesn't pick up a cache from a subsequent (background)
+// scan. The caller must hold the scan lock.
+func (e *endpoint) restoreCache() error {
+	// If the cache hasn't been spilled, then there's nothing to do.
+	if !e.cacheSpilled {
+		return nil
+	}
+
+	// Load the cache from disk.
+	cache, err := e.loadSpilledCache()
+	if err != nil {
+		return err
+	}
+
+	// Update our state.
+	e.cache = cache
+	e.cacheSpilled = false
+	if e.lastReturnedScanCache == nil {
+		e.lastReturnedScanCache = cache
+	}
+
+	// Success.
+	return nil
+}
+
+// generateReverseLookupMap generates a reverse lookup map from the cache. If
+// the cache has been spilled to disk, then it's loaded temporarily (without
+// being restored) so that it can be released once staging completes. The
+// caller must hold the scan lock.
+func (e *endpoint) generateReverseLookupMap() (*core.ReverseLookupMap, error) {
+	cache := e.cache
+	if e.cacheSpilled {
+		var err error
+		if cache, err = e.loadSpilledCache(); err != nil {
+			return nil, err
+		}
+	}
+	return cache.GenerateReverseLookupMap()
+}
+
+// loadVCSIgnores loads and converts the patterns from the Git ignore files
+// present in the specified content.
+func (e *endpoint) loadVCSIgnores(content *core.Entry) ([]string, error) {
+	var result []string
+	for _, path := range core.GitIgnorePaths(content) {
+		contents, err := os.ReadFile(filepath.Join(e.root, path))
+		if err != nil {
+			return nil, fmt.Errorf("unable to read VCS ignore file (%s): %w", path, err)
+		}
+		result = append(result, core.ParseGitIgnore(path, contents)...)
+	}
+	return result, nil
+}
+
 // scan is the internal function which performs a scan operation on the root and
 // updates the endpoint scan parameters. The caller must hold the scan lock.
 func (e *endpoint) scan(ctx context.Context, baseline *core.Snapshot, recheckPaths map[string]bool) error {
-	// Perform a full (warm) scan, watching for errors.
-	snapshot, newCache, newIgnoreCache, err := core.Scan(
-		ctx,
-		e.root,
-		baseline, recheckPaths,
-		e.hasher, e.cache,
-		e.ignores, e.ignoreCache,
-		e.probeMode,
-		e.symbolicLinkMode,
-	)
-	if err != nil {
+	// If the cache was spilled to disk, then reload it.
+	if err := e.restoreCache(); err != nil {
 		return err
 	}
 
+	// Perform a scan, watching for errors. If we're importing VCS ignore files,
+	// then the scan may change the set of imported patterns, in which case we
+	// need to re-scan (without acceleration, since the baseline was computed
+	// using stale ignores) to apply them.
+	var snapshot *core.Snapshot
+	for rescans := 0; ; rescans++ {
+		// Compute the effective ignores.
+		ignores := e.ignores
+		if len(e.vcsIgnores) > 0 {
+			ignores = make([]string, 0, len(e.ignores)+len(e.vcsIgnores))
+			ignores = append(ignores, e.ignores...)
+			ignores = append(ignores, e.vcsIgnores...)
+		}
+
+		// Perform the scan.
+		s, newCache, newIgnoreCache, err := core.Scan(
+			ctx,
+			e.root,
+			baseline, recheckPaths,
+			e.hasherFactory, e.cache,
+			ignores, e.ignoreCache,
+			e.probeMode,
+			e.symbolicLinkMode,
+			e.unicodeNormalizationMode,
+			e.modificationTimeTolerance,
+			e.scanConcurrency,
+		)
+		if err != nil {
+			return err
+		}
+		snapshot = s
+
+		// Update caches.
+		e.cache = newCache
+		e.ignoreCache = newIgnoreCache
+
+		// If we're not importing VCS ignore files, then we're done.
+		if !e.importVCSIgnores {
+			break
+		}
+
+		// Load VCS ignores based on the new snapshot.
+		vcsIgnores, err := e.loadVCSIgnores(snapshot.Content)
+		if err != nil {
+			return err
+		}
+		snapshot.VcsIgnores = vcsIgnores
+
+		// If the imported ignores haven't changed, then we're done. We also
+		// bound the number of re-scans, because patterns imported from one
+		// ignore file can exclude other ignore files and (in pathological
+		// cases) cause the imported patterns to oscillate. Any residual
+		// changes will be picked up by subsequent scans.
+		if comparison.StringSlicesEqual(vcsIgnores, e.vcsIgnores) {
+			break
+		}
+		e.vcsIgnores = vcsIgnores
+		e.ignoreCache = nil
+		if rescans == maximumVCSIgnoreRescans {
+			break
+		}
+		e.logger.Debug("VCS ignores changed, re-scanning")
+		baseline, recheckPaths = nil, nil
+	}
+
 	// Update the snapshot.
 	e.snapshot = snapshot
 
-	// Update caches.
-	e.cache = newCache
-	e.ignoreCache = newIgnoreCache
-
 	// Update the last scan entry count.
 	e.lastScanEntryCount = snapshot.Content.Count()
 
+	// If the resulting caches exceed the memory budget, then spill them to
+	// disk. We do this as part of every scan (rather than only in Scan) so
+	// that background scans are also subject to the budget. This isn't fatal
+	// if it fails since the caches can simply remain in memory.
+	if usage := cacheMemoryUsage(e.cache, e.ignoreCache); usage > e.memoryBudget {
+		e.logger.Debugf("Cache memory usage (%d bytes) exceeds budget, spilling cache to disk", usage)
+		if err := e.spillCache(); err != nil {
+			e.logger.Warn("Unable to spill cache to disk:", err)
+		}
+	}
+
 	// Trigger an asynchronous cache save operation.
 	select {
 	case e.saveCacheSignal <- struct{}{}:
@@ -969,7 +1338,7 @@ func (e *endpoint) scan(ctx context.Context, baseline *core.Snapshot, recheckPat
 }
 
 // Scan implements the Scan method for local endpoints.
-func (e *endpoint) Scan(ctx context.Context, _ *core.Entry, full bool) (*core.Snapshot, error, bool) {
+func (e *endpoint) Scan(ctx context.Context, _ *core.Entry, full bool, rehashPaths []string) (*core.Snapshot, error, bool) {
 	// Grab the scan lock and defer its release.
 	e.scanLock.Lock()
 	defer e.scanLock.Unlock()
@@ -1000,7 +1369,22 @@ func (e *endpoint) Scan(ctx context.Context, _ *core.Entry, full bool) (*core.Sn
 	// accelerated scanning with recursive watching, there's no need to disable
 	// acceleration on failure so long as the watch is still established (and if
 	// it's not, that will handled elsewhere).
-	if e.accelerate && !full {
+	//
+	// If rehashing has been requested, then we discard the corresponding
+	// entries from the existing cache before performing a full scan, forcing
+	// the associated file content to be re-hashed. The resulting cache will be
+	// fully accurate, so there's no need to treat this operation specially
+	// beyond that.
+	if len(rehashPaths) > 0 {
+		e.logger.Debug("Performing full scan with rehashing of", len(rehashPaths), "path(s)")
+		if err := e.restoreCache(); err != nil {
+			return nil, err, false
+		}
+		e.cache = e.cache.Invalidate(rehashPaths)
+		if err := e.scan(ctx, nil, nil); err != nil {
+			return nil, err, true
+		}
+	} else if e.accelerate && !full {
 		if e.watchMode == reifiedWatchModeRecursive {
 			e.logger.Debug("Performing accelerated scan with", len(e.recheckPaths), "recheck paths")
 			if err := e.scan(ctx, e.snapshot, e.recheckPaths); err != nil {
@@ -1033,7 +1417,8 @@ func (e *endpoint) Scan(ctx context.Context, _ *core.Entry, full bool) (*core.Sn
 	e.scannedSinceLastStageCall = true
 	e.scannedSinceLastTransitionCall = true
 
-	// Store the values corresponding to the snapshot that we'll return.
+	// Store the values corresponding to the snapshot that we'll return. If
+	// the cache has been spilled to disk, then Transition will load it.
 	e.lastReturnedScanCache = e.cache
 	e.lastReturnedScanSnapshotDecomposesUnicode = e.snapshot.DecomposesUnicode
 
@@ -1116,7 +1501,7 @@ func (e *endpoint) Stage(paths []string, digests [][]byte) ([]string, []*rsync.S
 
 	// Generate a reverse lookup map from the cache, which we'll use shortly to
 	// detect renames and copies.
-	reverseLookupMap, err := e.cache.GenerateReverseLookupMap()
+	reverseLookupMap, err := e.generateReverseLookupMap()
 	if err != nil {
 		e.scanLock.Unlock()
 		return nil, nil, nil, fmt.Errorf("unable to generate reverse lookup map: %w", err)
@@ -1125,6 +1510,12 @@ func (e *endpoint) Stage(paths []string, digests [][]byte) ([]string, []*rsync.S
 	// Release the scan lock.
 	e.scanLock.Unlock()
 
+	// Reset the stager's total size tracking, since the maximum total staging
+	// size applies on a per-operation basis. We also bound staging by the disk
+	// space that's currently available for it, so that staging itself can't
+	// exhaust the disk before the space check in Transition takes place.
+	e.stager.resetTotalSize(e.stagingSpaceLimit())
+
 	// Create an opener that we can use file opening and defer its closure. We
 	// can't cache this across synchronization cycles since its path references
 	// may become invalidated or may prevent modifications.
@@ -1146,7 +1537,7 @@ func (e *endpoint) Stage(paths []string, digests [][]byte) ([]string, []*rsync.S
 		digest := digests[p]
 		if _, err := e.stager.Provide(path, digest); err == nil {
 			continue
-		} else if e.stageFromRoot(path, digest, reverseLookupMap, opener) {
+		} else if reverseLookupMap != nil && e.stageFromRoot(path, digest, reverseLookupMap, opener) {
 			continue
 		} else {
 			filteredPaths = append(filteredPaths, path)
@@ -1240,6 +1631,94 @@ func (e *endpoint) Transition(ctx context.Context, transitions []*core.Change) (
 		}
 	}
 
+	// Verify that the filesystem containing the synchronization root has
+	// enough space available to accommodate the staged files that will be
+	// moved into place while still leaving the minimum free space. If it
+	// doesn't, then abort the transitioning operation, but return the failure
+	// as a problem for each affected path, not an error, since nobody is
+	// malfunctioning here.
+	if problem := e.checkAvailableSpace(transitions); problem != "" {
+		results := make([]*core.Entry, len(transitions))
+		problems := make([]*core.Problem, len(transitions))
+		for t, transition := range transitions {
+			results[t] = transition.Old
+			problems[t] = &core.Problem{Path: transition.Path, Error: problem}
+		}
+		return results, problems, false, nil
+	}
+
+	// Run before-apply hooks, if any. If any hook fails, then abort the
+	// transitioning operation, but return the failure as a problem for each
+	// vetoed path, not an error, since nobody is malfunctioning here. In that
+	// case, we also wipe the staging directory (since the staged files won't be
+	// used by a subsequent transition operation if the hooks' veto causes the
+	// underlying changes to be reverted) and impose an exponentially increasing
+	// delay before running the hooks again, which limits restaging and hook
+	// churn while changes are being blocked. We release the scan lock while
+	// waiting and running hooks for the same reasons that we release it around
+	// the transition operation itself (see below).
+	if len(e.beforeApply) > 0 && len(transitions) > 0 {
+		paths := make([]string, len(transitions))
+		for t, transition := range transitions {
+			paths[t] = transition.Path
+		}
+		e.scanLock.Unlock()
+		if delay := time.Until(e.beforeApplyVeto.Add(e.beforeApplyBackoff)); delay > 0 {
+			e.logger.Debugf("Delaying before-apply hooks by %v after previous veto", delay)
+			timer := time.NewTimer(delay)
+			select {
+			case <-timer.C:
+			case <-ctx.Done():
+				timer.Stop()
+				e.scanLock.Lock()
+				return nil, nil, false, errors.New("cancelled while waiting to run before-apply hooks")
+			}
+		}
+		err := e.runHooks(ctx, e.beforeApply, paths)
+		e.scanLock.Lock()
+		if err != nil {
+			e.stager.wipe()
+			e.beforeApplyVeto = time.Now()
+			if e.beforeApplyBackoff == 0 {
+				e.beforeApplyBackoff = minimumBeforeApplyBackoff
+			} else if e.beforeApplyBackoff *= 2; e.beforeApplyBackoff > maximumBeforeApplyBackoff {
+				e.beforeApplyBackoff = maximumBeforeApplyBackoff
+			}
+			results := make([]*core.Entry, len(transitions))
+			problems := make([]*core.Problem, len(transitions))
+			for t, transition := range transitions {
+				results[t] = transition.Old
+				problems[t] = &core.Problem{
+					Path:  transition.Path,
+					Error: fmt.Sprintf("change vetoed by before-apply %v", err),
+				}
+			}
+			return results, problems, false, nil
+		}
+		e.beforeApplyBackoff = 0
+	}
+
+	// Determine the cache corresponding to the last returned scan, loading it
+	// from disk if it was spilled.
+	cache := e.lastReturnedScanCache
+	if cache == nil {
+		var err error
+		if cache, err = e.loadSpilledCache(); err != nil {
+			return nil, nil, false, err
+		}
+	}
+
+	// If we're retaining removed and overwritten files, then start a new trash
+	// batch or use the versioner. We have to be careful not to pass a nil
+	// *trash or *versioner as a non-nil core.Trash interface.
+	var transitionTrash core.Trash
+	if e.trash != nil {
+		e.trash.startBatch()
+		transitionTrash = e.trash
+	} else if e.versioner != nil {
+		transitionTrash = e.versioner
+	}
+
 	// Perform the transition. We release the scan lock around this operation
 	// because we want watching Goroutines to be able to pick up events, or at
 	// least be able to handle them. If we held scan lock, there's a good chance
@@ -1253,22 +1732,28 @@ func (e *endpoint) Transition(ctx context.Context, transitions []*core.Change) (
 		ctx,
 		e.root,
 		transitions,
-		e.lastReturnedScanCache,
+		cache,
 		e.symbolicLinkMode,
 		e.defaultFileMode,
 		e.defaultDirectoryMode,
 		e.defaultOwnership,
 		e.lastReturnedScanSnapshotDecomposesUnicode,
+		e.unicodeNormalizationMode,
+		e.replacementMode,
+		e.modificationTimeTolerance,
 		e.stager,
+		transitionTrash,
 	)
 	e.scanLock.Lock()
 
-	// Determine whether or not the transition made any changes on disk.
+	// Determine whether or not the transition made any changes on disk and
+	// track the paths at which changes were made.
 	var transitionMadeChanges bool
+	var changedPaths []string
 	for r, result := range results {
 		if !result.Equal(transitions[r].Old, true) {
 			transitionMadeChanges = true
-			break
+			changedPaths = append(changedPaths, transitions[r].Path)
 		}
 	}
 
@@ -1350,10 +1835,69 @@ func (e *endpoint) Transition(ctx context.Context, transitions []*core.Change) (
 	// files.
 	e.stager.wipe()
 
+	// If we're retaining removed and overwritten files, then prune the trash.
+	// As with the staging directory, we don't treat failure as fatal.
+	if e.trash != nil {
+		if err := e.trash.prune(time.Now()); err != nil {
+			e.logger.Warnf("Unable to prune trash: %v", err)
+		}
+	} else if e.versioner != nil {
+		if err := e.versioner.prune(); err != nil {
+			e.logger.Warnf("Unable to prune versions: %v", err)
+		}
+	}
+
+	// If the transition made any changes on disk, then run after-sync hooks. We
+	// release the scan lock while doing so for the same reasons that we release
+	// it around the transition operation itself.
+	if transitionMadeChanges && len(e.afterSync) > 0 {
+		e.scanLock.Unlock()
+		if err := e.runHooks(ctx, e.afterSync, changedPaths); err != nil {
+			e.logger.Warnf("After-sync %v", err)
+		}
+		e.scanLock.Lock()
+	}
+
 	// Done.
 	return results, problems, stagerMissingFiles, nil
 }
 
+// Versions implements the Versions method for local endpoints.
+func (e *endpoint) Versions(path, restore string) ([]*synchronization.FileVersion, string, error) {
+	// Ensure that versioning is enabled and that the path is versionable.
+	if e.versioner == nil {
+		return nil, "versioning not enabled on endpoint", nil
+	} else if path == "" {
+		return nil, "versioning not supported for synchronization root", nil
+	}
+
+	// If a version has been specified, then restore it. We don't modify the
+	// synchronization root if we're in a read-only mode.
+	if restore != "" {
+		if e.readOnly {
+			return nil, "endpoint is in read-only mode", nil
+		}
+		destination := filepath.Join(e.root, filepath.FromSlash(path))
+		if err := e.versioner.restore(path, restore, destination); err != nil {
+			return nil, err.Error(), nil
+		}
+	}
+
+	// List the available versions.
+	versions, err := e.versioner.list(path)
+	if err != nil {
+		return nil, err.Error(), nil
+	}
+
+	// Success.
+	return versions, "", nil
+}
+
+// Capabilities implements the Capabilities method for local endpoints.
+func (e *endpoint) Capabilities() *synchronization.FilesystemCapabilities {
+	return e.capabilities
+}
+
 // Shutdown implements the Shutdown method for local endpoints.
 func (e *endpoint) Shutdown() error {
 	// Signal background worker Goroutines to terminate.
@@ -1,3 +1,121 @@
 package local
 
-// TODO: Implement.
+import (
+	"context"
+	"crypto/sha1"
+	"fmt"
+	"math"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/mutagen-io/mutagen/pkg/filesystem/behavior"
+	"github.com/mutagen-io/mutagen/pkg/synchronization/core"
+)
+
+const (
+	// testingMemoryBudgetDirectoryCount is the number of directories to
+	// create in the memory budget test tree.
+	testingMemoryBudgetDirectoryCount = 20
+	// testingMemoryBudgetFileCount is the number of files to create in each
+	// directory of the memory budget test tree.
+	testingMemoryBudgetFileCount = 100
+)
+
+// TestScanMemoryBudget tests that scans of a large tree spill the endpoint's
+// caches to disk when they exceed the memory budget and that the spilled
+// caches continue to support rescanning and rename detection.
+func TestScanMemoryBudget(t *testing.T) {
+	// Create a large tree with unique file contents.
+	root := t.TempDir()
+	for d := 0; d < testingMemoryBudgetDirectoryCount; d++ {
+		directory := filepath.Join(root, fmt.Sprintf("directory%d", d))
+		if err := os.Mkdir(directory, 0700); err != nil {
+			t.Fatal("unable to create directory:", err)
+		}
+		for f := 0; f < testingMemoryBudgetFileCount; f++ {
+			path := filepath.Join(directory, fmt.Sprintf("file%d", f))
+			if err := os.WriteFile(path, []byte(path), 0600); err != nil {
+				t.Fatal("unable to create file:", err)
+			}
+		}
+	}
+	expectedFiles := uint64(testingMemoryBudgetDirectoryCount * testingMemoryBudgetFileCount)
+
+	// Create an endpoint with a memory budget that's far smaller than the
+	// cache for the tree.
+	e := &endpoint{
+		root:                     root,
+		maximumEntryCount:        math.MaxUint64,
+		scanConcurrency:          4,
+		memoryBudget:             16 * 1024,
+		cachePath:                filepath.Join(t.TempDir(), "cache"),
+		probeMode:                behavior.ProbeMode_ProbeModeProbe,
+		symbolicLinkMode:         core.SymbolicLinkMode_SymbolicLinkModePortable,
+		unicodeNormalizationMode: core.UnicodeNormalizationMode_UnicodeNormalizationModeNone,
+		saveCacheSignal:          make(chan struct{}, 1),
+		hasherFactory:            sha1.New,
+	}
+
+	// Perform a scan and verify that the cache was spilled.
+	snapshot, err, _ := e.Scan(context.Background(), nil, false, nil)
+	if err != nil {
+		t.Fatal("unable to perform scan:", err)
+	} else if snapshot.Content.Count() == 0 {
+		t.Fatal("scan returned empty snapshot")
+	}
+	if !e.cacheSpilled || e.cache != nil || e.ignoreCache != nil {
+		t.Error("cache not spilled after scan exceeding memory budget")
+	}
+	if e.lastReturnedScanCache != nil {
+		t.Error("cache for returned scan retained in memory after spilling")
+	}
+
+	// Verify that the spilled cache is complete.
+	spilled, err := e.loadSpilledCache()
+	if err != nil {
+		t.Fatal("unable to load spilled cache:", err)
+	} else if uint64(len(spilled.Entries)) != expectedFiles {
+		t.Errorf("spilled cache entry count (%d) does not match expected (%d)",
+			len(spilled.Entries), expectedFiles,
+		)
+	}
+
+	// Verify that rename detection works with the spilled cache.
+	reverseLookupMap, err := e.generateReverseLookupMap()
+	if err != nil {
+		t.Fatal("unable to generate reverse lookup map:", err)
+	}
+	file := snapshot.Content.Contents["directory0"].Contents["file0"]
+	if path, ok := reverseLookupMap.Lookup(file.Digest); !ok {
+		t.Error("unable to look up file digest with spilled cache")
+	} else if path != "directory0/file0" {
+		t.Error("reverse lookup path does not match expected:", path)
+	}
+
+	// Perform a rescan and verify that it sees the same content and re-spills
+	// the cache.
+	rescanned, err, _ := e.Scan(context.Background(), nil, true, nil)
+	if err != nil {
+		t.Fatal("unable to perform rescan:", err)
+	} else if !rescanned.Content.Equal(snapshot.Content, true) {
+		t.Error("rescan content does not match original scan")
+	}
+	if !e.cacheSpilled || e.cache != nil {
+		t.Error("cache not spilled after rescan exceeding memory budget")
+	}
+
+	// Lift the memory budget, perform a rescan, and verify that the cache is
+	// restored and retained in memory.
+	e.memoryBudget = math.MaxUint64
+	if _, err, _ = e.Scan(context.Background(), nil, true, nil); err != nil {
+		t.Fatal("unable to perform rescan:", err)
+	}
+	if e.cacheSpilled || e.cache == nil {
+		t.Error("cache spilled despite unlimited memory budget")
+	} else if uint64(len(e.cache.Entries)) != expectedFiles {
+		t.Error("restored cache entry count does not match expected")
+	} else if e.lastReturnedScanCache != e.cache {
+		t.Error("cache for returned scan not retained in memory")
+	}
+}
@@ -0,0 +1,166 @@
+package local
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	// hookEnvironmentVariableSession is the environment variable used to
+	// provide the session identifier to hooks.
+	hookEnvironmentVariableSession = "MUTAGEN_SYNC_SESSION"
+	// hookEnvironmentVariableEndpoint is the environment variable used to
+	// provide the endpoint name ("alpha" or "beta") to hooks.
+	hookEnvironmentVariableEndpoint = "MUTAGEN_SYNC_ENDPOINT"
+	// hookEnvironmentVariableRoot is the environment variable used to provide
+	// the synchronization root to hooks.
+	hookEnvironmentVariableRoot = "MUTAGEN_SYNC_ROOT"
+	// hookEnvironmentVariableChangedPathsFile is the environment variable used
+	// to provide hooks with the path to a file containing the newline-separated
+	// list of changed (or to-be-changed) paths. A file is used (rather than an
+	// environment variable containing the list itself) because the list may
+	// exceed platform limits on environment size.
+	hookEnvironmentVariableChangedPathsFile = "MUTAGEN_SYNC_CHANGED_PATHS_FILE"
+	// hookEnvironmentVariableChangedPathCount is the environment variable used
+	// to provide the number of changed (or to-be-changed) paths to hooks.
+	hookEnvironmentVariableChangedPathCount = "MUTAGEN_SYNC_CHANGED_PATH_COUNT"
+
+	// hookTemporaryFilePattern is the pattern used for temporary files created
+	// to provide changed paths to hooks and to capture their output.
+	hookTemporaryFilePattern = "mutagen-hook-*"
+	// hookTimeout is the maximum amount of time that an individual hook command
+	// is allowed to run before it's terminated and treated as failed.
+	hookTimeout = 5 * time.Minute
+)
+
+// formatChangedPaths formats changed paths for consumption by hooks. Paths are
+// relative to the synchronization root (using forward slashes as separators),
+// with the root itself represented by ".", and each path is terminated by a
+// newline.
+func formatChangedPaths(changedPaths []string) string {
+	var builder strings.Builder
+	for _, path := range changedPaths {
+		if path == "" {
+			path = "."
+		}
+		builder.WriteString(path)
+		builder.WriteByte('\n')
+	}
+	return builder.String()
+}
+
+// hookEnvironment computes the environment for hook commands.
+func hookEnvironment(session string, alpha bool, root, changedPathsFile string, changedPathCount int) []string {
+	// Compute the endpoint name.
+	endpoint := "beta"
+	if alpha {
+		endpoint = "alpha"
+	}
+
+	// Compute the environment.
+	return append(os.Environ(),
+		hookEnvironmentVariableSession+"="+session,
+		hookEnvironmentVariableEndpoint+"="+endpoint,
+		hookEnvironmentVariableRoot+"="+root,
+		hookEnvironmentVariableChangedPathsFile+"="+changedPathsFile,
+		hookEnvironmentVariableChangedPathCount+"="+strconv.Itoa(changedPathCount),
+	)
+}
+
+// runHooks runs hook commands in order using the system shell, with the
+// synchronization root as the working directory. Execution stops at the first
+// failing command, in which case an error is returned. Hook output is captured
+// and logged rather than forwarded, since the endpoint may be running inside an
+// agent process whose standard output is used for transport.
+func (e *endpoint) runHooks(ctx context.Context, commands, changedPaths []string) error {
+	// Write the changed paths to a temporary file and defer its removal.
+	changedPathsFile, err := os.CreateTemp("", hookTemporaryFilePattern)
+	if err != nil {
+		return fmt.Errorf("unable to create changed paths file: %w", err)
+	}
+	defer os.Remove(changedPathsFile.Name())
+	_, err = changedPathsFile.WriteString(formatChangedPaths(changedPaths))
+	if closeErr := changedPathsFile.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		return fmt.Errorf("unable to write changed paths file: %w", err)
+	}
+
+	// Create a temporary file to capture hook output and defer its closure and
+	// removal. We capture output to a file rather than a pipe so that waiting
+	// for a terminated hook doesn't block on any background processes that it
+	// spawned (which may have inherited its output handles).
+	output, err := os.CreateTemp("", hookTemporaryFilePattern)
+	if err != nil {
+		return fmt.Errorf("unable to create hook output file: %w", err)
+	}
+	defer func() {
+		output.Close()
+		os.Remove(output.Name())
+	}()
+
+	// Compute the hook environment.
+	environment := hookEnvironment(
+		e.sessionIdentifier, e.alpha, e.root,
+		changedPathsFile.Name(), len(changedPaths),
+	)
+
+	// Run commands.
+	for _, command := range commands {
+		if err := e.runHook(ctx, command, environment, output); err != nil {
+			return err
+		}
+	}
+
+	// Success.
+	return nil
+}
+
+// runHook runs a single hook command with the specified environment, enforcing
+// hookTimeout and capturing its output to the specified file.
+func (e *endpoint) runHook(ctx context.Context, command string, environment []string, output *os.File) error {
+	// Reset the output file.
+	if err := output.Truncate(0); err != nil {
+		return fmt.Errorf("unable to reset hook output file: %w", err)
+	} else if _, err := output.Seek(0, io.SeekStart); err != nil {
+		return fmt.Errorf("unable to reset hook output file: %w", err)
+	}
+
+	// Create a subcontext to enforce the hook timeout.
+	hookCtx, cancel := context.WithTimeout(ctx, hookTimeout)
+	defer cancel()
+
+	// Run the command.
+	e.logger.Debug("Running hook:", command)
+	process := shellCommand(hookCtx, command)
+	process.Dir = e.root
+	process.Env = environment
+	process.Stdout = output
+	process.Stderr = output
+	err := process.Run()
+
+	// Log any output.
+	if _, seekErr := output.Seek(0, io.SeekStart); seekErr == nil {
+		if contents, readErr := io.ReadAll(output); readErr == nil && len(contents) > 0 {
+			e.logger.Debug("Hook output:", string(contents))
+		}
+	}
+
+	// Handle errors.
+	if err != nil {
+		if errors.Is(hookCtx.Err(), context.DeadlineExceeded) && ctx.Err() == nil {
+			return fmt.Errorf("hook (%s) timed out after %v", command, hookTimeout)
+		}
+		return fmt.Errorf("hook (%s) failed: %w", command, err)
+	}
+
+	// Success.
+	return nil
+}
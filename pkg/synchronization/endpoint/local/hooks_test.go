@@ -0,0 +1,110 @@
+package local
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"runtime"
+	"testing"
+	"time"
+)
+
+// TestFormatChangedPaths tests formatChangedPaths.
+func TestFormatChangedPaths(t *testing.T) {
+	// Set up test cases.
+	testCases := []struct {
+		changedPaths []string
+		expected     string
+	}{
+		{nil, ""},
+		{[]string{""}, ".\n"},
+		{[]string{"", "a/b", "c"}, ".\na/b\nc\n"},
+	}
+
+	// Process test cases.
+	for i, testCase := range testCases {
+		if formatted := formatChangedPaths(testCase.changedPaths); formatted != testCase.expected {
+			t.Errorf("test index %d: formatted paths do not match expected: %q != %q", i, formatted, testCase.expected)
+		}
+	}
+}
+
+// TestHookEnvironment tests hookEnvironment.
+func TestHookEnvironment(t *testing.T) {
+	// Compute an environment.
+	environment := hookEnvironment("session", false, "/root", "/tmp/paths", 3)
+
+	// Extract the hook-specific variables, which are appended to the end.
+	if len(environment) < 5 {
+		t.Fatal("environment has too few variables")
+	}
+	environment = environment[len(environment)-5:]
+
+	// Verify the hook-specific variables.
+	expected := []string{
+		"MUTAGEN_SYNC_SESSION=session",
+		"MUTAGEN_SYNC_ENDPOINT=beta",
+		"MUTAGEN_SYNC_ROOT=/root",
+		"MUTAGEN_SYNC_CHANGED_PATHS_FILE=/tmp/paths",
+		"MUTAGEN_SYNC_CHANGED_PATH_COUNT=3",
+	}
+	for i, variable := range environment {
+		if variable != expected[i] {
+			t.Errorf("environment variable (%q) does not match expected (%q)", variable, expected[i])
+		}
+	}
+}
+
+// TestRunHooks tests endpoint.runHooks.
+func TestRunHooks(t *testing.T) {
+	// The hook commands used in this test require a POSIX shell.
+	if runtime.GOOS == "windows" {
+		t.Skip("hook commands require a POSIX shell")
+	}
+
+	// Create an endpoint with a temporary synchronization root.
+	root := t.TempDir()
+	e := &endpoint{root: root, alpha: true, sessionIdentifier: "session"}
+
+	// Run hooks that record their environment and the changed paths file into
+	// the synchronization root (which should be their working directory).
+	commands := []string{
+		`echo "$MUTAGEN_SYNC_ENDPOINT $MUTAGEN_SYNC_CHANGED_PATH_COUNT" > environment`,
+		`cat "$MUTAGEN_SYNC_CHANGED_PATHS_FILE" > paths`,
+	}
+	if err := e.runHooks(context.Background(), commands, []string{"", "a/b"}); err != nil {
+		t.Fatal("unable to run hooks:", err)
+	}
+
+	// Verify the recorded information.
+	if contents, err := os.ReadFile(filepath.Join(root, "environment")); err != nil {
+		t.Error("unable to read recorded environment:", err)
+	} else if string(contents) != "alpha 2\n" {
+		t.Errorf("recorded environment does not match expected: %q", contents)
+	}
+	if contents, err := os.ReadFile(filepath.Join(root, "paths")); err != nil {
+		t.Error("unable to read recorded paths:", err)
+	} else if string(contents) != ".\na/b\n" {
+		t.Errorf("recorded paths do not match expected: %q", contents)
+	}
+
+	// Verify that execution stops at the first failing hook.
+	commands = []string{"exit 1", "touch unexpected"}
+	if err := e.runHooks(context.Background(), commands, nil); err == nil {
+		t.Error("failing hook did not result in error")
+	}
+	if _, err := os.Lstat(filepath.Join(root, "unexpected")); !os.IsNotExist(err) {
+		t.Error("hook ran after failing hook")
+	}
+
+	// Verify that hooks are terminated if they run too long, even if they've
+	// spawned a background process that holds their output open.
+	ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+	defer cancel()
+	start := time.Now()
+	if err := e.runHooks(ctx, []string{"sleep 10 & sleep 10"}, nil); err == nil {
+		t.Error("long-running hook did not result in error")
+	} else if time.Since(start) > 5*time.Second {
+		t.Error("long-running hook was not terminated promptly")
+	}
+}
@@ -0,0 +1,29 @@
+package local
+
+import (
+	"github.com/mutagen-io/mutagen/pkg/synchronization/core"
+)
+
+const (
+	// cacheEntryOverhead is the approximate number of bytes of memory occupied
+	// by a cache entry, excluding its path and digest. It accounts for the
+	// entry structure, its modification time, and its share of the cache map.
+	cacheEntryOverhead = 128
+	// ignoreCacheEntryOverhead is the approximate number of bytes of memory
+	// occupied by an ignore cache entry, including an average-length path.
+	ignoreCacheEntryOverhead = 128
+)
+
+// cacheMemoryUsage estimates the number of bytes of memory occupied by an
+// endpoint's caches. It only considers the caches themselves (and not the
+// process heap as a whole) so that the estimate reflects a single endpoint,
+// even when multiple endpoints share a process.
+func cacheMemoryUsage(cache *core.Cache, ignoreCache core.IgnoreCache) uint64 {
+	var usage uint64
+	if cache != nil {
+		for path, entry := range cache.Entries {
+			usage += cacheEntryOverhead + uint64(len(path)) + uint64(len(entry.Digest))
+		}
+	}
+	return usage + uint64(len(ignoreCache))*ignoreCacheEntryOverhead
+}
@@ -0,0 +1,29 @@
+package local
+
+import (
+	"testing"
+
+	"github.com/mutagen-io/mutagen/pkg/synchronization/core"
+)
+
+// TestCacheMemoryUsage tests cacheMemoryUsage.
+func TestCacheMemoryUsage(t *testing.T) {
+	// Verify that empty caches don't register any usage.
+	if usage := cacheMemoryUsage(nil, nil); usage != 0 {
+		t.Error("non-zero usage reported for nil caches:", usage)
+	}
+
+	// Verify that usage scales with cache contents.
+	cache := &core.Cache{Entries: map[string]*core.CacheEntry{
+		"file":      {Digest: make([]byte, 20)},
+		"directory": {Digest: make([]byte, 20)},
+	}}
+	expected := uint64(2*cacheEntryOverhead + len("file") + len("directory") + 2*20)
+	if usage := cacheMemoryUsage(cache, nil); usage != expected {
+		t.Errorf("cache usage (%d) does not match expected (%d)", usage, expected)
+	}
+	ignoreCache := core.IgnoreCache{core.IgnoreCacheKey{}: true}
+	if usage := cacheMemoryUsage(cache, ignoreCache); usage != expected+ignoreCacheEntryOverhead {
+		t.Error("ignore cache usage not included")
+	}
+}
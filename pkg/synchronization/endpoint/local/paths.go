@@ -115,6 +115,42 @@ func pathForInternalStagingRoot(root, session string, alpha bool) (string, error
 	return filepath.Join(root, stagingRootName), nil
 }
 
+// pathForTrashRoot computes the path to the trash root within the specified
+// trash directory for the given session identifier and endpoint. It does not
+// create the directory or any parent directories.
+func pathForTrashRoot(trashDirectory, session string, alpha bool) string {
+	// Compute the endpoint name.
+	endpointName := alphaName
+	if !alpha {
+		endpointName = betaName
+	}
+
+	// Compute the path to the trash root.
+	return filepath.Join(trashDirectory, fmt.Sprintf("%s-%s", session, endpointName))
+}
+
+// pathForVersionsRoot computes the path to the versions root which is internal
+// to the synchronization root for the given root, session identifier, and
+// endpoint. It does not create the directory or any parent directories.
+func pathForVersionsRoot(root, session string, alpha bool) string {
+	// Compute the endpoint name.
+	endpointName := alphaName
+	if !alpha {
+		endpointName = betaName
+	}
+
+	// Compute the name of the versions directory.
+	versionsRootName := fmt.Sprintf(
+		"%sversions-%s-%s",
+		filesystem.TemporaryNamePrefix,
+		session,
+		endpointName,
+	)
+
+	// Compute the path to the versions root.
+	return filepath.Join(root, versionsRootName)
+}
+
 // pathForStaging computes the staging path for the specified path/digest
 // relative to the staging root. It also returns the prefix directory byte value
 // and name, though it does not create the prefix directory.
@@ -0,0 +1,14 @@
+//go:build !windows
+
+package local
+
+import (
+	"context"
+	"os/exec"
+)
+
+// shellCommand creates a command that will run the specified command string
+// using the system shell.
+func shellCommand(ctx context.Context, command string) *exec.Cmd {
+	return exec.CommandContext(ctx, "/bin/sh", "-c", command)
+}
@@ -0,0 +1,21 @@
+package local
+
+import (
+	"context"
+	"os"
+	"os/exec"
+)
+
+// shellCommand creates a command that will run the specified command string
+// using the system shell. On Windows systems, this is %COMSPEC% (with a
+// fallback to cmd.exe if unspecified).
+func shellCommand(ctx context.Context, command string) *exec.Cmd {
+	// Determine the shell to use.
+	shell := os.Getenv("COMSPEC")
+	if shell == "" {
+		shell = "cmd.exe"
+	}
+
+	// Create the command.
+	return exec.CommandContext(ctx, shell, "/c", command)
+}
@@ -0,0 +1,132 @@
+package local
+
+import (
+	"fmt"
+	"math"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+
+	"github.com/dustin/go-humanize"
+
+	"github.com/mutagen-io/mutagen/pkg/filesystem"
+	"github.com/mutagen-io/mutagen/pkg/synchronization/core"
+)
+
+// availableSpace returns the number of bytes available on the filesystem that
+// contains (or will contain) the specified path. If the path doesn't exist,
+// then its nearest existing parent is queried.
+func availableSpace(path string) (uint64, error) {
+	for {
+		available, err := filesystem.AvailableSpace(path)
+		if err == nil || !os.IsNotExist(err) {
+			return available, err
+		}
+		parent := filepath.Dir(path)
+		if parent == path {
+			return 0, err
+		}
+		path = parent
+	}
+}
+
+// deviceID returns the device ID of the filesystem that contains (or will
+// contain) the specified path. If the path doesn't exist, then its nearest
+// existing parent is queried. Device IDs are only available on POSIX systems.
+func deviceID(path string) (uint64, error) {
+	for {
+		object, metadata, err := filesystem.Open(path, false)
+		if err == nil {
+			object.Close()
+			return metadata.DeviceID, nil
+		} else if !os.IsNotExist(err) {
+			return 0, err
+		}
+		parent := filepath.Dir(path)
+		if parent == path {
+			return 0, err
+		}
+		path = parent
+	}
+}
+
+// sameFilesystem determines whether or not two paths reside (or will reside)
+// on the same filesystem, in which case files can be moved between them by
+// renaming (without consuming additional space). If this can't be determined,
+// then false is returned. On Windows, paths are compared by volume name.
+func sameFilesystem(first, second string) bool {
+	if runtime.GOOS == "windows" {
+		return strings.EqualFold(filepath.VolumeName(first), filepath.VolumeName(second))
+	}
+	firstID, err := deviceID(first)
+	if err != nil {
+		return false
+	}
+	secondID, err := deviceID(second)
+	if err != nil {
+		return false
+	}
+	return firstID == secondID
+}
+
+// stagingSpaceLimit computes the number of bytes that can be staged without
+// reducing the space available on the staging filesystem below the minimum
+// free space. If available space can't be determined, then no limit is
+// imposed.
+func (e *endpoint) stagingSpaceLimit() uint64 {
+	available, err := availableSpace(e.stager.root)
+	if err != nil {
+		e.logger.Debug("Unable to query available staging disk space:", err)
+		return math.MaxUint64
+	} else if available < e.minimumFreeSpace {
+		return 0
+	}
+	return available - e.minimumFreeSpace
+}
+
+// checkAvailableSpace determines whether or not the filesystem containing the
+// synchronization root can accommodate the files staged for the specified
+// transitions while leaving the minimum free space available. It returns a
+// problem description if it can't or an empty string if it can. Staged files
+// only count against available space if the staging root is on a different
+// filesystem, since they'll otherwise be moved into place by renaming. The
+// required space is otherwise computed conservatively, i.e. it doesn't account
+// for space freed by removed or replaced content. If available space can't be
+// determined, then the check is skipped.
+func (e *endpoint) checkAvailableSpace(transitions []*core.Change) string {
+	// Compute the total size of staged files required by the transitions.
+	var required uint64
+	if !sameFilesystem(e.stager.root, e.root) {
+		paths, digests := core.TransitionDependencies(transitions)
+		for p, path := range paths {
+			if size, ok := e.stager.stagedSize(path, digests[p]); ok {
+				required += size
+			}
+		}
+	}
+
+	// If there's nothing to move into place and no reserve to maintain, then
+	// there's no need to query available space.
+	if required == 0 && e.minimumFreeSpace == 0 {
+		return ""
+	}
+
+	// Query available space.
+	available, err := availableSpace(e.root)
+	if err != nil {
+		e.logger.Debug("Unable to query available disk space:", err)
+		return ""
+	}
+
+	// Check whether or not the transitions can be accommodated.
+	if available < required || available-required < e.minimumFreeSpace {
+		return fmt.Sprintf(
+			"insufficient disk space: %s required (with %s reserved), %s available",
+			humanize.Bytes(required),
+			humanize.Bytes(e.minimumFreeSpace),
+			humanize.Bytes(available),
+		)
+	}
+	return ""
+}
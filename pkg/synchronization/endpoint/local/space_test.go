@@ -0,0 +1,86 @@
+package local
+
+import (
+	"crypto/sha1"
+	"math"
+	"path/filepath"
+	"testing"
+
+	"github.com/mutagen-io/mutagen/pkg/filesystem"
+	"github.com/mutagen-io/mutagen/pkg/synchronization/core"
+)
+
+// TestCheckAvailableSpace tests that endpoint.checkAvailableSpace reports a
+// problem if and only if the minimum free space can't be maintained.
+func TestCheckAvailableSpace(t *testing.T) {
+	// Skip the test if available space queries aren't supported.
+	root := t.TempDir()
+	if _, err := filesystem.AvailableSpace(root); err == filesystem.ErrAvailableSpaceUnsupported {
+		t.Skip()
+	} else if err != nil {
+		t.Fatal("unable to query available space:", err)
+	}
+
+	// Create an endpoint with only the fields needed for space checks. We use
+	// a synchronization root that doesn't exist yet to ensure that its parent
+	// is queried instead.
+	e := &endpoint{
+		root:   filepath.Join(root, "root"),
+		stager: newStager(filepath.Join(root, "staging"), false, sha1.New(), 0, 0),
+	}
+
+	// Create a transition that requires a (non-staged) file.
+	transitions := []*core.Change{{
+		Path: "file",
+		New:  &core.Entry{Kind: core.EntryKind_File, Digest: []byte{0}},
+	}}
+
+	// Verify that no problem is reported without a reserve.
+	if problem := e.checkAvailableSpace(transitions); problem != "" {
+		t.Error("unexpected problem reported without reserve:", problem)
+	}
+
+	// Verify that an unsatisfiable reserve results in a problem.
+	e.minimumFreeSpace = math.MaxUint64
+	if problem := e.checkAvailableSpace(transitions); problem == "" {
+		t.Error("no problem reported with unsatisfiable reserve")
+	}
+}
+
+// TestSameFilesystem tests sameFilesystem.
+func TestSameFilesystem(t *testing.T) {
+	// Verify that a directory and a non-existent path within it are reported
+	// as residing on the same filesystem.
+	root := t.TempDir()
+	if !sameFilesystem(root, filepath.Join(root, "nonexistent", "path")) {
+		t.Error("paths within the same directory not reported as same filesystem")
+	}
+}
+
+// TestStagingSpaceLimit tests that endpoint.stagingSpaceLimit accounts for the
+// minimum free space.
+func TestStagingSpaceLimit(t *testing.T) {
+	// Skip the test if available space queries aren't supported.
+	root := t.TempDir()
+	if _, err := filesystem.AvailableSpace(root); err == filesystem.ErrAvailableSpaceUnsupported {
+		t.Skip()
+	} else if err != nil {
+		t.Fatal("unable to query available space:", err)
+	}
+
+	// Create an endpoint with only the fields needed for space checks.
+	e := &endpoint{
+		stager: newStager(filepath.Join(root, "staging"), false, sha1.New(), 0, 0),
+	}
+
+	// Verify that a limit is imposed without a reserve.
+	if limit := e.stagingSpaceLimit(); limit == 0 || limit == math.MaxUint64 {
+		t.Error("unexpected staging space limit without reserve:", limit)
+	}
+
+	// Verify that an unsatisfiable reserve prevents staging.
+	e.minimumFreeSpace = math.MaxUint64
+	if limit := e.stagingSpaceLimit(); limit != 0 {
+		t.Error("non-zero staging space limit with unsatisfiable reserve:", limit)
+	}
+}
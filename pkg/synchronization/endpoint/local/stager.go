@@ -5,6 +5,7 @@ import (
 	"fmt"
 	"hash"
 	"io"
+	"math"
 	"os"
 	"path/filepath"
 
@@ -54,6 +55,10 @@ func (s *stagingSink) Write(data []byte) (int, error) {
 	// Watch for size violations.
 	if (s.maximumSize - s.currentSize) < uint64(len(data)) {
 		return 0, errors.New("maximum file size reached")
+	} else if (s.stager.maximumTotalSize - s.stager.totalSize) < uint64(len(data)) {
+		return 0, errors.New("maximum total staging size reached")
+	} else if (s.stager.spaceLimit - s.stager.totalSize) < uint64(len(data)) {
+		return 0, errors.New("insufficient disk space for staging")
 	}
 
 	// Write to the underlying storage.
@@ -63,10 +68,11 @@ func (s *stagingSink) Write(data []byte) (int, error) {
 	// can't fail.
 	s.digester.Write(data[:n])
 
-	// Update the current size. We needn't worry about this overflowing, because
-	// the check above is sufficient to ensure that this amount of data won't
-	// overflow the maximum uint64 value.
+	// Update the current size and the stager's total size. We needn't worry
+	// about these overflowing, because the checks above are sufficient to
+	// ensure that this amount of data won't overflow the maximum uint64 value.
 	s.currentSize += uint64(n)
+	s.stager.totalSize += uint64(n)
 
 	// Done.
 	return n, err
@@ -120,6 +126,16 @@ type stager struct {
 	digester hash.Hash
 	// maximumFileSize is the maximum allowed size for a single staged file.
 	maximumFileSize uint64
+	// maximumTotalSize is the maximum allowed total size of files staged
+	// between calls to resetTotalSize.
+	maximumTotalSize uint64
+	// totalSize is the total number of bytes written to staging sinks since the
+	// last call to resetTotalSize.
+	totalSize uint64
+	// spaceLimit is the maximum number of bytes that can be written to staging
+	// sinks (between calls to resetTotalSize) without exhausting the disk
+	// space available for staging.
+	spaceLimit uint64
 	// rootExists indicates whether or not the staging root currently exists.
 	rootExists bool
 	// prefixExists tracks whether or not individual prefix directories exist.
@@ -127,16 +143,26 @@ type stager struct {
 }
 
 // newStager creates a new stager.
-func newStager(root string, hideRoot bool, digester hash.Hash, maximumFileSize uint64) *stager {
+func newStager(root string, hideRoot bool, digester hash.Hash, maximumFileSize, maximumTotalSize uint64) *stager {
 	return &stager{
-		root:            root,
-		hideRoot:        hideRoot,
-		digester:        digester,
-		maximumFileSize: maximumFileSize,
-		rootExists:      existsAndIsDirectory(root),
+		root:             root,
+		hideRoot:         hideRoot,
+		digester:         digester,
+		maximumFileSize:  maximumFileSize,
+		maximumTotalSize: maximumTotalSize,
+		spaceLimit:       math.MaxUint64,
+		rootExists:       existsAndIsDirectory(root),
 	}
 }
 
+// resetTotalSize resets the tracking of the total size of staged files and
+// sets the space limit for subsequent staging. It should be invoked at the
+// start of each staging operation.
+func (s *stager) resetTotalSize(spaceLimit uint64) {
+	s.totalSize = 0
+	s.spaceLimit = spaceLimit
+}
+
 // ensurePrefixExists ensures that the specified prefix directory exists within
 // the staging root, using a cache to avoid inefficient recreation.
 func (s *stager) ensurePrefixExists(prefixByte byte, prefix string) error {
@@ -212,6 +238,29 @@ func (s *stager) Sink(path string) (io.WriteCloser, error) {
 	}, nil
 }
 
+// stagedSize returns the size of the staged file corresponding to the
+// specified path and digest. If no such file has been staged, then it returns
+// false.
+func (s *stager) stagedSize(path string, digest []byte) (uint64, bool) {
+	// If the root doesn't exist, then there's no way the file exists.
+	if !s.rootExists {
+		return 0, false
+	}
+
+	// Compute the expected location of the file.
+	expectedLocation, _, _, err := pathForStaging(s.root, path, digest)
+	if err != nil {
+		return 0, false
+	}
+
+	// Query the file size.
+	metadata, err := os.Lstat(expectedLocation)
+	if err != nil {
+		return 0, false
+	}
+	return uint64(metadata.Size()), true
+}
+
 // Provide implements the Provide method of sync.Provider.
 func (s *stager) Provide(path string, digest []byte) (string, error) {
 	// If the root doesn't exist, then there's no way the file exists, and we
@@ -1,3 +1,73 @@
 package local
 
-// TODO: Implement.
+import (
+	"crypto/sha1"
+	"math"
+	"path/filepath"
+	"testing"
+)
+
+// TestStagerMaximumTotalSize tests that the stager enforces its maximum total
+// size across sinks and that the limit is reset by resetTotalSize.
+func TestStagerMaximumTotalSize(t *testing.T) {
+	// Create a stager with a generous per-file limit but a small total limit.
+	s := newStager(filepath.Join(t.TempDir(), "staging"), false, sha1.New(), 1024, 8)
+
+	// Stage a file that fits within the total limit.
+	sink, err := s.Sink("first")
+	if err != nil {
+		t.Fatal("unable to create first sink:", err)
+	}
+	if _, err := sink.Write([]byte("12345")); err != nil {
+		t.Error("unable to write within total size limit:", err)
+	}
+	if err := sink.Close(); err != nil {
+		t.Fatal("unable to close first sink:", err)
+	}
+
+	// Attempt to stage a file that would exceed the total limit.
+	sink, err = s.Sink("second")
+	if err != nil {
+		t.Fatal("unable to create second sink:", err)
+	}
+	if _, err := sink.Write([]byte("12345")); err == nil {
+		t.Error("write exceeding total size limit succeeded unexpectedly")
+	}
+	if err := sink.Close(); err != nil {
+		t.Fatal("unable to close second sink:", err)
+	}
+
+	// Reset the total size and ensure that staging can proceed.
+	s.resetTotalSize(math.MaxUint64)
+	sink, err = s.Sink("third")
+	if err != nil {
+		t.Fatal("unable to create third sink:", err)
+	}
+	if _, err := sink.Write([]byte("12345")); err != nil {
+		t.Error("unable to write after total size reset:", err)
+	}
+	if err := sink.Close(); err != nil {
+		t.Fatal("unable to close third sink:", err)
+	}
+}
+
+// TestStagerSpaceLimit tests that the stager enforces the space limit set by
+// resetTotalSize.
+func TestStagerSpaceLimit(t *testing.T) {
+	// Create a stager without meaningful size limits and impose a small space
+	// limit.
+	s := newStager(filepath.Join(t.TempDir(), "staging"), false, sha1.New(), math.MaxUint64, math.MaxUint64)
+	s.resetTotalSize(4)
+
+	// Attempt to stage a file that would exceed the space limit.
+	sink, err := s.Sink("file")
+	if err != nil {
+		t.Fatal("unable to create sink:", err)
+	}
+	if _, err := sink.Write([]byte("12345")); err == nil {
+		t.Error("write exceeding space limit succeeded unexpectedly")
+	}
+	if err := sink.Close(); err != nil {
+		t.Fatal("unable to close sink:", err)
+	}
+}
@@ -0,0 +1,155 @@
+package local
+
+import (
+	"fmt"
+	"io/fs"
+	"math"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+const (
+	// trashBatchNameFormat is the time format used to name trash batch
+	// directories. It sorts lexicographically in chronological order.
+	trashBatchNameFormat = "20060102T150405.000000000Z"
+)
+
+// trash retains files that are removed or overwritten by transition operations.
+// Files retained during a single transition operation are grouped into a batch
+// directory named by the time at which the batch was started. It implements
+// the core.Trash interface. It is not safe for concurrent usage.
+type trash struct {
+	// root is the path to the trash root for the endpoint.
+	root string
+	// rootName is the name to use when retaining the synchronization root
+	// itself (i.e. when it's a file).
+	rootName string
+	// retentionPeriod is the period for which retained files are kept.
+	retentionPeriod time.Duration
+	// maximumSize is the maximum total size of retained files.
+	maximumSize uint64
+	// batch is the path to the current batch directory. It is empty if no
+	// files have been retained in the current batch.
+	batch string
+}
+
+// newTrash creates a new trash.
+func newTrash(root, rootName string, retentionPeriod time.Duration, maximumSize uint64) *trash {
+	return &trash{
+		root:            root,
+		rootName:        rootName,
+		retentionPeriod: retentionPeriod,
+		maximumSize:     maximumSize,
+	}
+}
+
+// startBatch indicates that subsequently retained files should be grouped into
+// a new batch.
+func (t *trash) startBatch() {
+	t.batch = ""
+}
+
+// Allocate implements core.Trash.Allocate.
+func (t *trash) Allocate(path string) (string, error) {
+	// If a batch directory hasn't been computed yet, then compute one.
+	if t.batch == "" {
+		t.batch = filepath.Join(t.root, time.Now().UTC().Format(trashBatchNameFormat))
+	}
+
+	// Compute the destination path.
+	var destination string
+	if path == "" {
+		destination = filepath.Join(t.batch, t.rootName)
+	} else {
+		destination = filepath.Join(t.batch, filepath.FromSlash(path))
+	}
+
+	// Ensure that the parent directory exists.
+	if err := os.MkdirAll(filepath.Dir(destination), 0700); err != nil {
+		return "", fmt.Errorf("unable to create trash directory: %w", err)
+	}
+
+	// Success.
+	return destination, nil
+}
+
+// batchSize computes the total size of the files in a batch directory.
+func batchSize(path string) (uint64, error) {
+	var result uint64
+	err := filepath.WalkDir(path, func(_ string, entry fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		} else if entry.Type().IsRegular() {
+			info, err := entry.Info()
+			if err != nil {
+				return err
+			}
+			result += uint64(info.Size())
+		}
+		return nil
+	})
+	return result, err
+}
+
+// prune removes batches that have exceeded the retention period, as well as
+// the oldest remaining batches if the maximum trash size is exceeded.
+func (t *trash) prune(now time.Time) error {
+	// Read the trash root contents. If the root doesn't exist, then nothing has
+	// been retained.
+	contents, err := os.ReadDir(t.root)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil
+		}
+		return fmt.Errorf("unable to read trash contents: %w", err)
+	}
+
+	// Remove expired batches and collect the remaining batches. We rely on the
+	// fact that os.ReadDir sorts by name and thus chronologically. Any content
+	// that isn't a batch directory is left untouched.
+	cutoff := now.Add(-t.retentionPeriod)
+	var remaining []string
+	for _, content := range contents {
+		created, err := time.Parse(trashBatchNameFormat, content.Name())
+		if err != nil || !content.IsDir() {
+			continue
+		}
+		path := filepath.Join(t.root, content.Name())
+		if created.Before(cutoff) {
+			if err := os.RemoveAll(path); err != nil {
+				return fmt.Errorf("unable to remove expired trash batch: %w", err)
+			}
+			continue
+		}
+		remaining = append(remaining, path)
+	}
+
+	// If there's no size limit, then we're done.
+	if t.maximumSize == math.MaxUint64 {
+		return nil
+	}
+
+	// Compute the size of each remaining batch.
+	sizes := make([]uint64, len(remaining))
+	var total uint64
+	for b, path := range remaining {
+		size, err := batchSize(path)
+		if err != nil {
+			return fmt.Errorf("unable to compute trash batch size: %w", err)
+		}
+		sizes[b] = size
+		total += size
+	}
+
+	// Remove the oldest batches until we're within the size limit.
+	for b := 0; b < len(remaining) && total > t.maximumSize; b++ {
+		if err := os.RemoveAll(remaining[b]); err != nil {
+			return fmt.Errorf("unable to remove trash batch: %w", err)
+		}
+		total -= sizes[b]
+	}
+
+	// Success.
+	return nil
+}
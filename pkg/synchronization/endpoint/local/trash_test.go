@@ -0,0 +1,94 @@
+package local
+
+import (
+	"math"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+// TestTrashAllocate tests that trash allocation groups files into a batch and
+// that a new batch is started by startBatch.
+func TestTrashAllocate(t *testing.T) {
+	// Create a trash.
+	trash := newTrash(filepath.Join(t.TempDir(), "trash"), "root", time.Hour, math.MaxUint64)
+
+	// Allocate locations within the first batch.
+	first, err := trash.Allocate("a/b")
+	if err != nil {
+		t.Fatal("unable to allocate first location:", err)
+	}
+	root, err := trash.Allocate("")
+	if err != nil {
+		t.Fatal("unable to allocate root location:", err)
+	}
+	if filepath.Dir(filepath.Dir(first)) != filepath.Dir(root) {
+		t.Error("allocations within a batch do not share a batch directory")
+	} else if filepath.Base(root) != "root" {
+		t.Error("root allocation does not use root name")
+	}
+	if _, err := os.Stat(filepath.Dir(first)); err != nil {
+		t.Error("parent directory not created for allocation:", err)
+	}
+
+	// Start a new batch and ensure that it uses a different directory.
+	trash.startBatch()
+	time.Sleep(time.Millisecond)
+	second, err := trash.Allocate("a/b")
+	if err != nil {
+		t.Fatal("unable to allocate second location:", err)
+	} else if second == first {
+		t.Error("new batch reused previous batch location")
+	}
+}
+
+// TestTrashPrune tests that pruning removes expired batches and enforces the
+// maximum trash size by removing the oldest batches first.
+func TestTrashPrune(t *testing.T) {
+	// Create a trash root with three batches of differing ages, as well as some
+	// unrelated content that should be left untouched.
+	root := t.TempDir()
+	now := time.Now().UTC()
+	ages := []time.Duration{3 * time.Hour, 2 * time.Minute, time.Minute}
+	batches := make([]string, len(ages))
+	for a, age := range ages {
+		batches[a] = filepath.Join(root, now.Add(-age).Format(trashBatchNameFormat))
+		if err := os.Mkdir(batches[a], 0700); err != nil {
+			t.Fatal("unable to create batch:", err)
+		}
+		if err := os.WriteFile(filepath.Join(batches[a], "file"), []byte("0123456789"), 0600); err != nil {
+			t.Fatal("unable to create batch content:", err)
+		}
+	}
+	unrelated := filepath.Join(root, "unrelated")
+	if err := os.Mkdir(unrelated, 0700); err != nil {
+		t.Fatal("unable to create unrelated content:", err)
+	}
+
+	// Prune with a retention period that expires only the oldest batch and a
+	// size limit that allows only a single remaining batch.
+	trash := newTrash(root, "root", time.Hour, 15)
+	if err := trash.prune(now); err != nil {
+		t.Fatal("unable to prune trash:", err)
+	}
+
+	// Verify the results.
+	expected := []bool{false, false, true}
+	for b, batch := range batches {
+		if _, err := os.Stat(batch); (err == nil) != expected[b] {
+			t.Errorf("batch %d existence does not match expected: %t", b, expected[b])
+		}
+	}
+	if _, err := os.Stat(unrelated); err != nil {
+		t.Error("unrelated content removed by pruning")
+	}
+}
+
+// TestTrashPruneNonExistent tests that pruning a non-existent trash succeeds.
+func TestTrashPruneNonExistent(t *testing.T) {
+	trash := newTrash(filepath.Join(t.TempDir(), "trash"), "root", time.Hour, 0)
+	if err := trash.prune(time.Now()); err != nil {
+		t.Error("unable to prune non-existent trash:", err)
+	}
+}
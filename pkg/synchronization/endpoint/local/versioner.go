@@ -0,0 +1,214 @@
+package local
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"google.golang.org/protobuf/types/known/timestamppb"
+
+	"github.com/mutagen-io/mutagen/pkg/filesystem"
+	"github.com/mutagen-io/mutagen/pkg/synchronization"
+)
+
+const (
+	// versionSeparator separates a file name from its version identifier in
+	// the versions root.
+	versionSeparator = "~"
+	// versionIdentifierFormat is the time format used for version identifiers.
+	// It sorts lexicographically in chronological order.
+	versionIdentifierFormat = "20060102T150405.000000000Z"
+)
+
+// errVersionNotFound indicates that a requested version does not exist.
+var errVersionNotFound = errors.New("version not found")
+
+// versioner retains previous versions of files that are removed or overwritten
+// by transition operations, keeping a fixed number of versions per path. It
+// implements the core.Trash interface. It is not safe for concurrent usage.
+type versioner struct {
+	// root is the path to the versions root for the endpoint.
+	root string
+	// count is the maximum number of versions to keep for each path.
+	count uint32
+	// retained is the set of paths for which versions have been retained since
+	// the last pruning operation.
+	retained map[string]bool
+}
+
+// newVersioner creates a new versioner.
+func newVersioner(root string, count uint32) *versioner {
+	return &versioner{
+		root:     root,
+		count:    count,
+		retained: make(map[string]bool),
+	}
+}
+
+// Allocate implements core.Trash.Allocate. The synchronization root itself is
+// never versioned.
+func (v *versioner) Allocate(path string) (string, error) {
+	// Don't version the synchronization root.
+	if path == "" {
+		return "", nil
+	}
+
+	// Compute the destination path.
+	identifier := time.Now().UTC().Format(versionIdentifierFormat)
+	destination := filepath.Join(v.root, filepath.FromSlash(path)) + versionSeparator + identifier
+
+	// Ensure that the parent directory exists.
+	if err := os.MkdirAll(filepath.Dir(destination), 0700); err != nil {
+		return "", fmt.Errorf("unable to create versions directory: %w", err)
+	}
+
+	// Record the path for pruning.
+	v.retained[path] = true
+
+	// Success.
+	return destination, nil
+}
+
+// path computes the location of the specified version of a path, validating
+// the version identifier in the process.
+func (v *versioner) path(path, identifier string) (string, error) {
+	if _, err := time.Parse(versionIdentifierFormat, identifier); err != nil {
+		return "", errors.New("invalid version identifier")
+	}
+	return filepath.Join(v.root, filepath.FromSlash(path)) + versionSeparator + identifier, nil
+}
+
+// list returns the versions available for a path, ordered from newest to
+// oldest.
+func (v *versioner) list(path string) ([]*synchronization.FileVersion, error) {
+	// Compute the directory containing versions and the name prefix that they
+	// share.
+	target := filepath.Join(v.root, filepath.FromSlash(path))
+	directory := filepath.Dir(target)
+	prefix := filepath.Base(target) + versionSeparator
+
+	// Read the directory contents. If the directory doesn't exist, then there
+	// aren't any versions.
+	contents, err := os.ReadDir(directory)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("unable to read versions directory: %w", err)
+	}
+
+	// Extract versions.
+	var versions []*synchronization.FileVersion
+	for _, content := range contents {
+		name := content.Name()
+		if !content.Type().IsRegular() || !strings.HasPrefix(name, prefix) {
+			continue
+		}
+		identifier := name[len(prefix):]
+		creationTime, err := time.Parse(versionIdentifierFormat, identifier)
+		if err != nil {
+			continue
+		}
+		info, err := content.Info()
+		if err != nil {
+			if os.IsNotExist(err) {
+				continue
+			}
+			return nil, fmt.Errorf("unable to query version information: %w", err)
+		}
+		versions = append(versions, &synchronization.FileVersion{
+			Identifier:   identifier,
+			CreationTime: timestamppb.New(creationTime),
+			Size:         uint64(info.Size()),
+		})
+	}
+
+	// Sort versions from newest to oldest.
+	sort.Slice(versions, func(i, j int) bool {
+		return versions[i].Identifier > versions[j].Identifier
+	})
+
+	// Success.
+	return versions, nil
+}
+
+// prune removes excess versions for paths retained since the last pruning
+// operation. It attempts to prune all paths, returning the first error
+// encountered.
+func (v *versioner) prune() error {
+	var result error
+	for path := range v.retained {
+		versions, err := v.list(path)
+		if err != nil {
+			if result == nil {
+				result = err
+			}
+			continue
+		}
+		for i := int(v.count); i < len(versions); i++ {
+			target, _ := v.path(path, versions[i].Identifier)
+			if err := os.Remove(target); err != nil && !os.IsNotExist(err) && result == nil {
+				result = fmt.Errorf("unable to remove version: %w", err)
+			}
+		}
+	}
+	v.retained = make(map[string]bool)
+	return result
+}
+
+// restore atomically replaces the file at the specified destination with the
+// specified version of a path, preserving the version's permissions.
+func (v *versioner) restore(path, identifier, destination string) error {
+	// Compute the version path.
+	source, err := v.path(path, identifier)
+	if err != nil {
+		return err
+	}
+
+	// Open the version.
+	file, err := os.Open(source)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return errVersionNotFound
+		}
+		return fmt.Errorf("unable to open version: %w", err)
+	}
+	defer file.Close()
+	info, err := file.Stat()
+	if err != nil {
+		return fmt.Errorf("unable to query version information: %w", err)
+	} else if !info.Mode().IsRegular() {
+		return errVersionNotFound
+	}
+
+	// Copy the version to a temporary file alongside the destination.
+	temporary, err := os.CreateTemp(filepath.Dir(destination), filesystem.TemporaryNamePrefix+"restore")
+	if err != nil {
+		return fmt.Errorf("unable to create temporary file: %w", err)
+	}
+	if _, err := io.Copy(temporary, file); err != nil {
+		temporary.Close()
+		os.Remove(temporary.Name())
+		return fmt.Errorf("unable to copy version contents: %w", err)
+	} else if err = temporary.Close(); err != nil {
+		os.Remove(temporary.Name())
+		return fmt.Errorf("unable to close temporary file: %w", err)
+	} else if err = os.Chmod(temporary.Name(), info.Mode().Perm()); err != nil {
+		os.Remove(temporary.Name())
+		return fmt.Errorf("unable to set file permissions: %w", err)
+	}
+
+	// Move the temporary file into place.
+	if err := filesystem.Rename(nil, temporary.Name(), nil, destination, true); err != nil {
+		os.Remove(temporary.Name())
+		return fmt.Errorf("unable to move version into place: %w", err)
+	}
+
+	// Success.
+	return nil
+}
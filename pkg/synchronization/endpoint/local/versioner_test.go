@@ -0,0 +1,83 @@
+package local
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+// TestVersionerAllocateRoot tests that the synchronization root isn't
+// versioned.
+func TestVersionerAllocateRoot(t *testing.T) {
+	versioner := newVersioner(filepath.Join(t.TempDir(), "versions"), 3)
+	if destination, err := versioner.Allocate(""); err != nil {
+		t.Fatal("unable to allocate root location:", err)
+	} else if destination != "" {
+		t.Error("root allocation returned non-empty location")
+	}
+}
+
+// TestVersionerPruneAndRestore tests that versions are listed newest first,
+// that pruning keeps only the configured number of versions, and that versions
+// can be restored.
+func TestVersionerPruneAndRestore(t *testing.T) {
+	// Create a versioner.
+	versioner := newVersioner(filepath.Join(t.TempDir(), "versions"), 2)
+
+	// Retain three versions of a file.
+	for _, content := range []string{"first", "second", "third"} {
+		destination, err := versioner.Allocate("a/b")
+		if err != nil {
+			t.Fatal("unable to allocate version location:", err)
+		}
+		if err := os.WriteFile(destination, []byte(content), 0640); err != nil {
+			t.Fatal("unable to write version:", err)
+		}
+		time.Sleep(time.Millisecond)
+	}
+
+	// Prune and verify that only the newest two versions remain.
+	if err := versioner.prune(); err != nil {
+		t.Fatal("unable to prune versions:", err)
+	}
+	versions, err := versioner.list("a/b")
+	if err != nil {
+		t.Fatal("unable to list versions:", err)
+	} else if len(versions) != 2 {
+		t.Fatal("unexpected version count after pruning:", len(versions))
+	} else if versions[0].Identifier <= versions[1].Identifier {
+		t.Error("versions not ordered from newest to oldest")
+	} else if versions[0].Size != uint64(len("third")) {
+		t.Error("unexpected size for newest version:", versions[0].Size)
+	}
+
+	// Restore the older remaining version and verify its contents.
+	destination := filepath.Join(t.TempDir(), "b")
+	if err := os.WriteFile(destination, []byte("current"), 0600); err != nil {
+		t.Fatal("unable to write current file:", err)
+	}
+	if err := versioner.restore("a/b", versions[1].Identifier, destination); err != nil {
+		t.Fatal("unable to restore version:", err)
+	}
+	if contents, err := os.ReadFile(destination); err != nil {
+		t.Fatal("unable to read restored file:", err)
+	} else if string(contents) != "second" {
+		t.Error("restored file has unexpected contents:", string(contents))
+	}
+
+	// Verify that restoring an unknown or invalid version fails.
+	if err := versioner.restore("a/b", time.Now().UTC().Format(versionIdentifierFormat), destination); err != errVersionNotFound {
+		t.Error("restoring non-existent version did not report missing version:", err)
+	}
+	if err := versioner.restore("a/b", "../../escape", destination); err == nil {
+		t.Error("restoring invalid version identifier succeeded")
+	}
+
+	// Verify that listing versions for an unknown path succeeds.
+	if versions, err := versioner.list("c"); err != nil {
+		t.Error("unable to list versions for unknown path:", err)
+	} else if len(versions) != 0 {
+		t.Error("unexpected versions for unknown path")
+	}
+}
@@ -2,6 +2,7 @@ package remote
 
 import (
 	"bufio"
+	"bytes"
 	"compress/flate"
 	"context"
 	"errors"
@@ -34,6 +35,9 @@ type endpointClient struct {
 	// lastSnapshotBytes is the serialized form of the last snapshot received
 	// from the remote endpoint.
 	lastSnapshotBytes []byte
+	// capabilities are the filesystem capabilities reported by the remote
+	// endpoint during initialization.
+	capabilities *synchronization.FilesystemCapabilities
 }
 
 // NewEndpoint creates a new remote synchronization.Endpoint operating over the
@@ -102,11 +106,12 @@ func NewEndpoint(
 	// Success.
 	successful = true
 	return &endpointClient{
-		logger:  logger,
-		closer:  closer,
-		flusher: flusher,
-		encoder: encoder,
-		decoder: decoder,
+		logger:       logger,
+		closer:       closer,
+		flusher:      flusher,
+		encoder:      encoder,
+		decoder:      decoder,
+		capabilities: response.Capabilities,
 	}, nil
 }
 
@@ -193,7 +198,7 @@ func (c *endpointClient) Poll(ctx context.Context) error {
 }
 
 // Scan implements the Scan method for remote endpoints.
-func (c *endpointClient) Scan(ctx context.Context, ancestor *core.Entry, full bool) (*core.Snapshot, error, bool) {
+func (c *endpointClient) Scan(ctx context.Context, ancestor *core.Entry, full bool, rehashPaths []string) (*core.Snapshot, error, bool) {
 	// Create an rsync engine.
 	engine := rsync.NewEngine()
 
@@ -226,6 +231,7 @@ func (c *endpointClient) Scan(ctx context.Context, ancestor *core.Entry, full bo
 		Scan: &ScanRequest{
 			BaselineSnapshotSignature: baselineSignature,
 			Full:                      full,
+			RehashPaths:               rehashPaths,
 		},
 	}
 	if err := c.encodeAndFlush(request); err != nil {
@@ -248,16 +254,40 @@ func (c *endpointClient) Scan(ctx context.Context, ancestor *core.Entry, full bo
 		}
 	}()
 
-	// Create a Goroutine that will receive a scan response.
+	// Create a Goroutine that will receive the stream of scan responses and
+	// apply their deltas to the baseline as they arrive. The final response
+	// (which is the only one that can carry an error) is retained.
 	response := &ScanResponse{}
+	baselineReader := bytes.NewReader(baselineBytes)
+	snapshotBuffer := &bytes.Buffer{}
+	var dataOperations, totalDataSize, blockOperations int
 	responseReceiveErrors := make(chan error, 1)
 	go func() {
-		if err := c.decoder.Decode(response); err != nil {
-			responseReceiveErrors <- fmt.Errorf("unable to receive scan response: %w", err)
-		} else if err = response.ensureValid(); err != nil {
-			responseReceiveErrors <- fmt.Errorf("invalid scan response: %w", err)
-		} else {
-			responseReceiveErrors <- nil
+		for {
+			response.Reset()
+			if err := c.decoder.Decode(response); err != nil {
+				responseReceiveErrors <- fmt.Errorf("unable to receive scan response: %w", err)
+				return
+			} else if err = response.ensureValid(); err != nil {
+				responseReceiveErrors <- fmt.Errorf("invalid scan response: %w", err)
+				return
+			}
+			for _, operation := range response.SnapshotDelta {
+				if err := engine.Patch(snapshotBuffer, baselineReader, baselineSignature, operation); err != nil {
+					responseReceiveErrors <- fmt.Errorf("unable to patch base snapshot: %w", err)
+					return
+				}
+				if dataSize := len(operation.Data); dataSize > 0 {
+					dataOperations++
+					totalDataSize += dataSize
+				} else {
+					blockOperations++
+				}
+			}
+			if response.Done {
+				responseReceiveErrors <- nil
+				return
+			}
 		}
 	}()
 
@@ -291,27 +321,13 @@ func (c *endpointClient) Scan(ctx context.Context, ancestor *core.Entry, full bo
 		return nil, fmt.Errorf("remote error: %s", response.Error), response.TryAgain
 	}
 
-	// Apply the remote's deltas to the expected snapshot.
-	snapshotBytes, err := engine.PatchBytes(baselineBytes, baselineSignature, response.SnapshotDelta)
-	if err != nil {
-		return nil, fmt.Errorf("unable to patch base snapshot: %w", err), false
-	}
+	// Extract the snapshot bytes reconstructed from the remote's deltas.
+	snapshotBytes := snapshotBuffer.Bytes()
 
-	// If logging is enabled, then compute snapshot transmission statistics.
-	if c.logger.Level() >= logging.LevelDebug {
-		var dataOperations, totalDataSize, blockOperations int
-		for _, operation := range response.SnapshotDelta {
-			if dataSize := len(operation.Data); dataSize > 0 {
-				dataOperations++
-				totalDataSize += dataSize
-			} else {
-				blockOperations++
-			}
-		}
-		c.logger.Debugf("Snapshot delta yielded %d bytes using %d block operation(s) and %d data operation(s) totaling %d byte(s)",
-			len(snapshotBytes), blockOperations, dataOperations, totalDataSize,
-		)
-	}
+	// Log snapshot transmission statistics.
+	c.logger.Debugf("Snapshot delta yielded %d bytes using %d block operation(s) and %d data operation(s) totaling %d byte(s)",
+		len(snapshotBytes), blockOperations, dataOperations, totalDataSize,
+	)
 
 	// Unmarshal the snapshot.
 	snapshot := &core.Snapshot{}
@@ -324,7 +340,7 @@ func (c *endpointClient) Scan(ctx context.Context, ancestor *core.Entry, full bo
 	// ScanResponse, but because this method requires rsync-based patching and
 	// Protocol Buffers decoding before it actually has the underlying response,
 	// we can't perform this validation in ScanResponse.ensureValid.
-	if err = snapshot.EnsureValid(); err != nil {
+	if err := snapshot.EnsureValid(); err != nil {
 		return nil, fmt.Errorf("invalid snapshot received: %w", err), false
 	}
 
@@ -514,6 +530,38 @@ func (c *endpointClient) Transition(ctx context.Context, transitions []*core.Cha
 	return results, response.Problems, response.StagerMissingFiles, nil
 }
 
+// Versions implements the Versions method for remote endpoints.
+func (c *endpointClient) Versions(path, restore string) ([]*synchronization.FileVersion, string, error) {
+	// Create and send the versions request.
+	request := &EndpointRequest{
+		Versions: &VersionsRequest{
+			Path:    path,
+			Restore: restore,
+		},
+	}
+	if err := c.encodeAndFlush(request); err != nil {
+		return nil, "", fmt.Errorf("unable to send versions request: %w", err)
+	}
+
+	// Receive the response and check for remote errors.
+	response := &VersionsResponse{}
+	if err := c.decoder.Decode(response); err != nil {
+		return nil, "", fmt.Errorf("unable to receive versions response: %w", err)
+	} else if err = response.ensureValid(); err != nil {
+		return nil, "", fmt.Errorf("invalid versions response: %w", err)
+	} else if response.Error != "" {
+		return nil, "", fmt.Errorf("remote error: %s", response.Error)
+	}
+
+	// Success.
+	return response.Versions, response.Problem, nil
+}
+
+// Capabilities implements the Capabilities method for remote endpoints.
+func (c *endpointClient) Capabilities() *synchronization.FilesystemCapabilities {
+	return c.capabilities
+}
+
 // Shutdown implements the Shutdown method for remote endpoints.
 func (c *endpointClient) Shutdown() error {
 	// Close the compression resources and the control stream. This will cause
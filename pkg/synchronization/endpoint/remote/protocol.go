@@ -3,6 +3,8 @@ package remote
 import (
 	"errors"
 	"fmt"
+
+	"github.com/mutagen-io/mutagen/pkg/synchronization/core"
 )
 
 const (
@@ -11,6 +13,17 @@ const (
 	// for whatever stream is being used as a transport, which in our case is
 	// typically an OS pipe.
 	controlStreamBufferSize = 64 * 1024
+
+	// scanResponseMaximumDeltaSize is the approximate maximum amount of
+	// operation data that will be included in a single scan response. Snapshot
+	// deltas that exceed this size are split across multiple responses, which
+	// avoids buffering a complete (and potentially very large) delta in a
+	// single message on either end of the connection.
+	scanResponseMaximumDeltaSize = 512 * 1024
+
+	// scanResponseMaximumDeltaOperations is the maximum number of operations
+	// that will be included in a single scan response.
+	scanResponseMaximumDeltaOperations = 4096
 )
 
 // ensureValid ensures that the InitializeSynchronizationRequest's invariants
@@ -107,6 +120,13 @@ func (r *ScanRequest) ensureValid() error {
 
 	// Full is correct regardless of value, so no validation is required.
 
+	// Ensure that all rehash paths are valid.
+	for _, path := range r.RehashPaths {
+		if err := core.ValidatePath(path); err != nil {
+			return fmt.Errorf("invalid rehash path (%s): %w", path, err)
+		}
+	}
+
 	// Success.
 	return nil
 }
@@ -137,10 +157,13 @@ func (r *ScanResponse) ensureValid() error {
 		}
 	}
 
-	// If an error is set, then make sure that no snapshot delta was provided.
+	// If an error is set, then make sure that no snapshot delta was provided
+	// and that the response is marked as final.
 	if r.Error != "" {
 		if len(r.SnapshotDelta) > 0 {
 			return errors.New("non-empty snapshot delta present on error")
+		} else if !r.Done {
+			return errors.New("non-final scan response present on error")
 		}
 	}
 
@@ -314,6 +337,40 @@ func (r *TransitionResponse) ensureValid(expectedCount int) error {
 	return nil
 }
 
+// ensureValid ensures that VersionsRequest's invariants are respected.
+func (r *VersionsRequest) ensureValid() error {
+	// A nil versions request is not valid.
+	if r == nil {
+		return errors.New("nil versions request")
+	}
+
+	// Ensure that the path is valid.
+	if err := core.ValidatePath(r.Path); err != nil {
+		return fmt.Errorf("invalid path: %w", err)
+	}
+
+	// Success.
+	return nil
+}
+
+// ensureValid ensures that VersionsResponse's invariants are respected.
+func (r *VersionsResponse) ensureValid() error {
+	// A nil versions response is not valid.
+	if r == nil {
+		return errors.New("nil versions response")
+	}
+
+	// Ensure that all versions are valid.
+	for _, version := range r.Versions {
+		if err := version.EnsureValid(); err != nil {
+			return fmt.Errorf("invalid file version returned: %w", err)
+		}
+	}
+
+	// Success.
+	return nil
+}
+
 // ensureValid ensures that EndpointRequest's invariants are respected.
 func (r *EndpointRequest) ensureValid() error {
 	// A nil endpoint request is not valid.
@@ -338,6 +395,9 @@ func (r *EndpointRequest) ensureValid() error {
 	if r.Transition != nil {
 		set++
 	}
+	if r.Versions != nil {
+		set++
+	}
 	if set != 1 {
 		return errors.New("invalid number of fields set")
 	}
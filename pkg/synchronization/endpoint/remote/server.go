@@ -2,6 +2,7 @@ package remote
 
 import (
 	"bufio"
+	"bytes"
 	"compress/flate"
 	"context"
 	"errors"
@@ -13,6 +14,7 @@ import (
 	"github.com/mutagen-io/mutagen/pkg/encoding"
 	"github.com/mutagen-io/mutagen/pkg/filesystem"
 	"github.com/mutagen-io/mutagen/pkg/logging"
+	"github.com/mutagen-io/mutagen/pkg/process"
 	streampkg "github.com/mutagen-io/mutagen/pkg/stream"
 	"github.com/mutagen-io/mutagen/pkg/synchronization"
 	"github.com/mutagen-io/mutagen/pkg/synchronization/core"
@@ -82,6 +84,16 @@ func ServeEndpoint(logger *logging.Logger, stream io.ReadWriteCloser) error {
 		request.Root = r
 	}
 
+	// If requested, lower our scheduling priority so that we don't starve
+	// other processes on the host. This isn't fatal if it fails.
+	if niceness := request.Configuration.AgentNiceness; niceness != 0 {
+		if err := process.SetNiceness(int(niceness)); err != nil {
+			logger.Warn("Unable to set agent niceness:", err)
+		} else {
+			logger.Debug("Set agent niceness to", niceness)
+		}
+	}
+
 	// Create the underlying endpoint. If it fails to create, then send a
 	// failure response and abort. If it succeeds, then defer its closure.
 	endpoint, err := local.NewEndpoint(
@@ -101,7 +113,8 @@ func ServeEndpoint(logger *logging.Logger, stream io.ReadWriteCloser) error {
 	defer endpoint.Shutdown()
 
 	// Send a successful initialize response.
-	if err = encoder.Encode(&InitializeSynchronizationResponse{}); err != nil {
+	response := &InitializeSynchronizationResponse{Capabilities: endpoint.Capabilities()}
+	if err = encoder.Encode(response); err != nil {
 		return fmt.Errorf("unable to encode initialize response: %w", err)
 	} else if err = flusher.Flush(); err != nil {
 		return fmt.Errorf("unable to transmit initialize response: %w", err)
@@ -166,6 +179,10 @@ func (s *endpointServer) serve() error {
 			if err := s.serveTransition(request.Transition); err != nil {
 				return fmt.Errorf("unable to serve transition request: %w", err)
 			}
+		} else if request.Versions != nil {
+			if err := s.serveVersions(request.Versions); err != nil {
+				return fmt.Errorf("unable to serve versions request: %w", err)
+			}
 		} else {
 			// TODO: Should we panic here? The request validation already
 			// ensures that one and only one message component is set, so we
@@ -279,29 +296,60 @@ func (s *endpointServer) serveScan(request *ScanRequest) error {
 		// Create an rsync engine.
 		engine := rsync.NewEngine()
 
-		// Perform a scan and set up the response.
-		var response *ScanResponse
-		snapshot, err, tryAgain := s.endpoint.Scan(ctx, nil, request.Full)
+		// Perform a scan and marshal the resulting snapshot. If either of
+		// these operations fails, then send a final response with the error.
+		snapshot, err, tryAgain := s.endpoint.Scan(ctx, nil, request.Full, request.RehashPaths)
+		var snapshotBytes []byte
+		if err == nil {
+			if snapshotBytes, err = marshaling.Marshal(snapshot); err != nil {
+				err = fmt.Errorf("unable to marshal snapshot: %w", err)
+				tryAgain = false
+			}
+		}
 		if err != nil {
-			response = &ScanResponse{
+			if err := s.encodeAndFlush(&ScanResponse{
 				Error:    err.Error(),
 				TryAgain: tryAgain,
+				Done:     true,
+			}); err != nil {
+				responseSendErrors <- fmt.Errorf("unable to transmit response: %w", err)
+			} else {
+				responseSendErrors <- nil
 			}
-		} else if snapshotBytes, err := marshaling.Marshal(snapshot); err != nil {
-			response = &ScanResponse{
-				Error: fmt.Errorf("unable to marshal snapshot: %w", err).Error(),
+			return
+		}
+
+		// Stream the snapshot delta, splitting it across multiple responses
+		// if necessary. Operations are cloned when batched because the engine
+		// reuses its operation object between transmissions.
+		response := &ScanResponse{}
+		var responseDeltaSize int
+		transmit := func(operation *rsync.Operation) error {
+			response.SnapshotDelta = append(response.SnapshotDelta, proto.Clone(operation).(*rsync.Operation))
+			responseDeltaSize += len(operation.Data)
+			if responseDeltaSize < scanResponseMaximumDeltaSize &&
+				len(response.SnapshotDelta) < scanResponseMaximumDeltaOperations {
+				return nil
 			}
-		} else {
-			response = &ScanResponse{
-				SnapshotDelta: engine.DeltifyBytes(
-					snapshotBytes,
-					request.BaselineSnapshotSignature,
-					0,
-				),
+			if err := s.encoder.Encode(response); err != nil {
+				return err
 			}
+			response.SnapshotDelta = response.SnapshotDelta[:0]
+			responseDeltaSize = 0
+			return nil
+		}
+		if err := engine.Deltify(
+			bytes.NewReader(snapshotBytes),
+			request.BaselineSnapshotSignature,
+			0,
+			transmit,
+		); err != nil {
+			responseSendErrors <- fmt.Errorf("unable to transmit response: %w", err)
+			return
 		}
 
-		// Send the response.
+		// Send the final response.
+		response.Done = true
 		if err := s.encodeAndFlush(response); err != nil {
 			responseSendErrors <- fmt.Errorf("unable to transmit response: %w", err)
 		} else {
@@ -488,3 +536,30 @@ func (s *endpointServer) serveTransition(request *TransitionRequest) error {
 	// Success.
 	return nil
 }
+
+// serveVersions serves a versions request.
+func (s *endpointServer) serveVersions(request *VersionsRequest) error {
+	// Ensure the request is valid.
+	if err := request.ensureValid(); err != nil {
+		return fmt.Errorf("invalid versions request: %w", err)
+	}
+
+	// Perform the versions operation.
+	versions, problem, err := s.endpoint.Versions(request.Path, request.Restore)
+	if err != nil {
+		s.encodeAndFlush(&VersionsResponse{Error: err.Error()})
+		return fmt.Errorf("unable to perform versions operation: %w", err)
+	}
+
+	// Send the response.
+	response := &VersionsResponse{
+		Versions: versions,
+		Problem:  problem,
+	}
+	if err := s.encodeAndFlush(response); err != nil {
+		return fmt.Errorf("unable to send versions response: %w", err)
+	}
+
+	// Success.
+	return nil
+}
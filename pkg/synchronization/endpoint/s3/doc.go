@@ -0,0 +1,8 @@
+// Package s3 provides an agentless synchronization endpoint implementation
+// that stores content in S3-compatible object storage. Files are stored as
+// objects beneath a key prefix, while the full entry hierarchy (including
+// directories, executability, and symbolic links, none of which can be
+// represented by objects alone) is stored in an index object. The endpoint is
+// primarily intended as the destination of one-way synchronization sessions,
+// e.g. for publishing build artifacts or assets.
+package s3
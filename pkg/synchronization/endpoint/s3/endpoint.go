@@ -0,0 +1,383 @@
+package s3
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	pathpkg "path"
+	"strings"
+
+	"github.com/mutagen-io/mutagen/pkg/logging"
+	"github.com/mutagen-io/mutagen/pkg/s3"
+	"github.com/mutagen-io/mutagen/pkg/synchronization"
+	"github.com/mutagen-io/mutagen/pkg/synchronization/core"
+	"github.com/mutagen-io/mutagen/pkg/synchronization/endpoint/staging"
+	"github.com/mutagen-io/mutagen/pkg/synchronization/rsync"
+)
+
+var (
+	// errTransitionCancelled is the error recorded for paths that aren't
+	// transitioned due to cancellation.
+	errTransitionCancelled = errors.New("transition cancelled")
+)
+
+// endpoint provides a synchronization.Endpoint implementation that stores
+// content in S3-compatible object storage.
+type endpoint struct {
+	// logger is the underlying logger.
+	logger *logging.Logger
+	// store is the object store.
+	store *store
+	// readOnly indicates whether or not the endpoint should be operating in a
+	// read-only mode (i.e. it is the source of unidirectional synchronization).
+	readOnly bool
+	// maximumEntryCount is the maximum number of entries that the endpoint will
+	// synchronize.
+	maximumEntryCount uint64
+	// snapshot is the snapshot loaded from the index by the last scan.
+	snapshot *core.Snapshot
+	// lastScanEntryCount is the entry count from the last scan.
+	lastScanEntryCount uint64
+	// stager is the staging coordinator.
+	stager *staging.Stager
+}
+
+// NewEndpoint creates a new S3 endpoint that stores content beneath the
+// specified key prefix using the specified client. The client will be closed
+// when the endpoint is shut down.
+func NewEndpoint(
+	logger *logging.Logger,
+	client *s3.Client,
+	prefix string,
+	sessionIdentifier string,
+	version synchronization.Version,
+	configuration *synchronization.Configuration,
+	alpha bool,
+) (synchronization.Endpoint, error) {
+	// Reject configuration options that require filesystem access or command
+	// execution.
+	if configuration.TrashDirectory != "" {
+		return nil, errors.New("trash directories are not supported by S3 endpoints")
+	} else if configuration.VersionCount > 0 {
+		return nil, errors.New("file versioning is not supported by S3 endpoints")
+	} else if len(configuration.AfterSync) > 0 || len(configuration.BeforeApply) > 0 {
+		return nil, errors.New("hooks are not supported by S3 endpoints")
+	} else if configuration.DefaultOwner != "" || configuration.DefaultGroup != "" {
+		return nil, errors.New("ownership specifications are not supported by S3 endpoints")
+	} else if configuration.IgnoreVCSIgnores {
+		return nil, errors.New("VCS ignore files are not supported by S3 endpoints")
+	}
+
+	// Determine if the endpoint is running in a read-only mode.
+	synchronizationMode := configuration.SynchronizationMode
+	if synchronizationMode.IsDefault() {
+		synchronizationMode = version.DefaultSynchronizationMode()
+	}
+	unidirectional := synchronizationMode == core.SynchronizationMode_SynchronizationModeOneWaySafe ||
+		synchronizationMode == core.SynchronizationMode_SynchronizationModeOneWayReplica
+	readOnly := alpha && unidirectional
+
+	// Determine the maximum entry count.
+	maximumEntryCount := configuration.MaximumEntryCount
+	if maximumEntryCount == 0 {
+		maximumEntryCount = version.DefaultMaximumEntryCount()
+	}
+
+	// Determine the maximum staging file size.
+	maximumStagingFileSize := configuration.MaximumStagingFileSize
+	if maximumStagingFileSize == 0 {
+		maximumStagingFileSize = version.DefaultMaximumStagingFileSize()
+	}
+
+	// Determine the maximum total staging size.
+	maximumStagingTotalSize := configuration.MaximumStagingTotalSize
+	if maximumStagingTotalSize == 0 {
+		maximumStagingTotalSize = version.DefaultMaximumStagingTotalSize()
+	}
+
+	// Compute the staging root.
+	stagingRoot, err := staging.PathForMutagenStagingRoot(sessionIdentifier, alpha)
+	if err != nil {
+		return nil, err
+	}
+
+	// Success.
+	return &endpoint{
+		logger: logger,
+		store: &store{
+			client: client,
+			prefix: strings.Trim(prefix, "/"),
+		},
+		readOnly:          readOnly,
+		maximumEntryCount: maximumEntryCount,
+		stager: staging.NewStager(
+			stagingRoot,
+			version.Hasher,
+			maximumStagingFileSize,
+			maximumStagingTotalSize,
+		),
+	}, nil
+}
+
+// Poll implements the Poll method for S3 endpoints. Since content is only
+// expected to be modified by Mutagen, there are no modifications to detect,
+// so it simply waits for cancellation.
+func (e *endpoint) Poll(ctx context.Context) error {
+	<-ctx.Done()
+	return nil
+}
+
+// Scan implements the Scan method for S3 endpoints. Rather than listing and
+// reading objects, it loads the snapshot recorded in the index object.
+func (e *endpoint) Scan(_ context.Context, _ *core.Entry, _ bool, _ []string) (*core.Snapshot, error, bool) {
+	// Load the index. Retrieval failures may be transient, so we suggest a
+	// retry in that case.
+	snapshot, err := e.store.loadIndex()
+	if err != nil {
+		return nil, err, true
+	}
+
+	// Verify that we haven't exceeded the maximum entry count.
+	e.lastScanEntryCount = snapshot.Content.Count()
+	if e.lastScanEntryCount > e.maximumEntryCount {
+		e.logger.Debugf("Scan count (%d) exceeded maximum allowed entry count (%d)",
+			e.lastScanEntryCount, e.maximumEntryCount,
+		)
+		return nil, errors.New("exceeded allowed entry count"), true
+	}
+
+	// Store the snapshot for use in transitions.
+	e.snapshot = snapshot
+
+	// Success.
+	return snapshot, nil, false
+}
+
+// Stage implements the Stage method for S3 endpoints.
+func (e *endpoint) Stage(paths []string, digests [][]byte) ([]string, []*rsync.Signature, rsync.Receiver, error) {
+	// If we're in a read-only mode, we shouldn't be staging files.
+	if e.readOnly {
+		return nil, nil, nil, errors.New("endpoint is in read-only mode")
+	}
+
+	// Validate argument lengths and bail if there's nothing to stage.
+	if len(paths) != len(digests) {
+		return nil, nil, nil, errors.New("path count does not match digest count")
+	} else if len(paths) == 0 {
+		return nil, nil, nil, nil
+	}
+
+	// Verify that the number of paths provided isn't going to put us over the
+	// maximum number of allowed entries.
+	if e.maximumEntryCount != 0 && (e.maximumEntryCount-e.lastScanEntryCount) < uint64(len(paths)) {
+		return nil, nil, nil, errors.New("staging would exceeded allowed entry count")
+	}
+
+	// Reset the stager's total size tracking, since the maximum total staging
+	// size applies on a per-operation basis.
+	e.stager.ResetTotalSize()
+
+	// Filter out paths that have already been staged by a previous
+	// (interrupted) staging operation.
+	filteredPaths := paths[:0]
+	for p, path := range paths {
+		if !e.stager.Staged(path, digests[p]) {
+			filteredPaths = append(filteredPaths, path)
+		}
+	}
+	if len(filteredPaths) == 0 {
+		return nil, nil, nil, nil
+	}
+
+	// Use empty signatures for all paths, since computing signatures would
+	// require downloading the existing objects.
+	signatures := make([]*rsync.Signature, len(filteredPaths))
+	for p := range signatures {
+		signatures[p] = &rsync.Signature{}
+	}
+
+	// Create a receiver.
+	receiver, err := rsync.NewReceiver(e.stager.Root(), filteredPaths, signatures, e.stager)
+	if err != nil {
+		return nil, nil, nil, fmt.Errorf("unable to create rsync receiver: %w", err)
+	}
+
+	// Done.
+	return filteredPaths, signatures, receiver, nil
+}
+
+// Supply implements the Supply method for S3 endpoints.
+func (e *endpoint) Supply(paths []string, signatures []*rsync.Signature, receiver rsync.Receiver) error {
+	return rsync.TransmitFromSource(e.store, paths, signatures, receiver)
+}
+
+// upload uploads the staged file for the specified path and entry. It returns
+// whether or not the failure was due to the stager missing the file.
+func (e *endpoint) upload(path string, entry *core.Entry) (bool, error) {
+	// Compute the object key.
+	key, err := e.store.key(path)
+	if err != nil {
+		return false, err
+	}
+
+	// Locate and open the staged file.
+	stagedPath, err := e.stager.Provide(path, entry.Digest)
+	if err != nil {
+		return os.IsNotExist(err), fmt.Errorf("unable to locate staged file: %w", err)
+	}
+	staged, err := os.Open(stagedPath)
+	if err != nil {
+		return false, fmt.Errorf("unable to open staged file: %w", err)
+	}
+	defer staged.Close()
+
+	// Upload the file.
+	if err := e.store.client.Put(key, staged); err != nil {
+		return false, fmt.Errorf("unable to upload file: %w", err)
+	}
+
+	// Success.
+	return false, nil
+}
+
+// Transition implements the Transition method for S3 endpoints.
+func (e *endpoint) Transition(ctx context.Context, transitions []*core.Change) ([]*core.Entry, []*core.Problem, bool, error) {
+	// If we're in a read-only mode, we shouldn't be performing transitions.
+	if e.readOnly {
+		return nil, nil, false, errors.New("endpoint is in read-only mode")
+	} else if e.snapshot == nil {
+		return nil, nil, false, errors.New("transition performed without scan")
+	}
+
+	// Create a mutable copy of the indexed content.
+	content := e.snapshot.Content.Copy(true)
+
+	// Perform transitions.
+	var results []*core.Entry
+	var problems []*core.Problem
+	var stagerMissingFiles bool
+	var removals []string
+	recordProblem := func(path string, err error) {
+		problems = append(problems, &core.Problem{Path: path, Error: err.Error()})
+	}
+	for _, t := range transitions {
+		// Check for cancellation.
+		select {
+		case <-ctx.Done():
+			results = append(results, t.Old)
+			recordProblem(t.Path, errTransitionCancelled)
+			continue
+		default:
+		}
+
+		// Ensure that the indexed content matches what's expected and that the
+		// parent directory exists.
+		current := lookup(content, t.Path)
+		if !current.Equal(t.Old, true) {
+			results = append(results, current)
+			recordProblem(t.Path, errors.New("modification detected"))
+			continue
+		} else if t.Path != "" {
+			parentPath := pathpkg.Dir(t.Path)
+			if parentPath == "." {
+				parentPath = ""
+			}
+			if parent := lookup(content, parentPath); parent == nil || parent.Kind != core.EntryKind_Directory {
+				results = append(results, current)
+				recordProblem(t.Path, errors.New("parent directory does not exist"))
+				continue
+			}
+		}
+
+		// Compute the files being removed and created.
+		oldFiles := files(t.Path, t.Old)
+		newFiles := files(t.Path, t.New)
+
+		// Record objects for files that no longer exist. These are removed
+		// only after the updated index has been saved, so that the index never
+		// references objects that don't exist.
+		for path := range oldFiles {
+			if _, ok := newFiles[path]; !ok {
+				removals = append(removals, path)
+			}
+		}
+
+		// Upload new and modified files. If an upload fails, then any existing
+		// object at that path is unchanged, so we reflect the previous entry
+		// (if any) in the result.
+		result := t.New.Copy(true)
+		for path, entry := range newFiles {
+			if old, ok := oldFiles[path]; ok && bytes.Equal(old.Digest, entry.Digest) {
+				continue
+			}
+			if missing, err := e.upload(path, entry); err != nil {
+				stagerMissingFiles = stagerMissingFiles || missing
+				recordProblem(path, fmt.Errorf("unable to create file: %w", err))
+				relative := strings.TrimPrefix(strings.TrimPrefix(path, t.Path), "/")
+				result = replace(result, relative, oldFiles[path])
+			}
+		}
+
+		// Record the result.
+		content = replace(content, t.Path, result)
+		results = append(results, result)
+	}
+
+	// Wipe the staging directory. We don't monitor for errors here, because we
+	// need to return the results and problems no matter what.
+	if err := e.stager.Wipe(); err != nil {
+		e.logger.Warn("Unable to wipe stager:", err)
+	}
+
+	// Save the updated index. If this fails, then the index no longer reflects
+	// the stored content, so the endpoint has to be considered failed.
+	directoryCount, fileCount, symbolicLinkCount := statistics(content)
+	snapshot := &core.Snapshot{
+		Content:                content,
+		PreservesExecutability: true,
+		Directories:            directoryCount,
+		Files:                  fileCount,
+		SymbolicLinks:          symbolicLinkCount,
+	}
+	if err := e.store.saveIndex(snapshot); err != nil {
+		return nil, nil, false, fmt.Errorf("unable to save index: %w", err)
+	}
+	e.snapshot = snapshot
+
+	// Remove objects for files that no longer exist, skipping any that were
+	// recreated by a later transition. Failure to remove an object only leaves
+	// an orphaned object behind, so it's recorded as a problem without
+	// affecting the result.
+	for _, path := range removals {
+		if entry := lookup(content, path); entry != nil && entry.Kind == core.EntryKind_File {
+			continue
+		}
+		if key, err := e.store.key(path); err != nil {
+			recordProblem(path, fmt.Errorf("unable to remove file: %w", err))
+		} else if err = e.store.client.Delete(key); err != nil {
+			recordProblem(path, fmt.Errorf("unable to remove file: %w", err))
+		}
+	}
+
+	// Done.
+	return results, problems, stagerMissingFiles, nil
+}
+
+// Versions implements the Versions method for S3 endpoints.
+func (e *endpoint) Versions(_, _ string) ([]*synchronization.FileVersion, string, error) {
+	return nil, "versioning not supported by S3 endpoints", nil
+}
+
+// Capabilities implements the Capabilities method for S3 endpoints. Object
+// storage has no filesystem to probe, so it always returns nil.
+func (e *endpoint) Capabilities() *synchronization.FilesystemCapabilities {
+	return nil
+}
+
+// Shutdown implements the Shutdown method for S3 endpoints.
+func (e *endpoint) Shutdown() error {
+	e.store.client.Close()
+	return nil
+}
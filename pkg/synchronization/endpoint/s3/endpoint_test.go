@@ -0,0 +1,132 @@
+package s3
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/mutagen-io/mutagen/pkg/logging"
+	"github.com/mutagen-io/mutagen/pkg/s3"
+	"github.com/mutagen-io/mutagen/pkg/synchronization"
+	"github.com/mutagen-io/mutagen/pkg/synchronization/core"
+	"github.com/mutagen-io/mutagen/pkg/synchronization/endpoint/staging"
+)
+
+// recordingServer is a minimal S3-compatible server that records the requests
+// that it receives and optionally fails index writes.
+type recordingServer struct {
+	// lock serializes access to requests.
+	lock sync.Mutex
+	// requests are the method and path of each request received.
+	requests []string
+	// failIndexWrites indicates that index writes should fail.
+	failIndexWrites bool
+}
+
+// ServeHTTP implements http.Handler.ServeHTTP.
+func (s *recordingServer) ServeHTTP(writer http.ResponseWriter, request *http.Request) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	io.Copy(io.Discard, request.Body)
+	s.requests = append(s.requests, request.Method+" "+request.URL.Path)
+	if s.failIndexWrites && request.Method == http.MethodPut && strings.HasSuffix(request.URL.Path, indexName) {
+		writer.WriteHeader(http.StatusInternalServerError)
+	} else if request.Method == http.MethodDelete {
+		writer.WriteHeader(http.StatusNoContent)
+	}
+}
+
+// newTestEndpoint creates an endpoint backed by the specified server with a
+// snapshot containing a single file at the root of the prefix.
+func newTestEndpoint(t *testing.T, server *httptest.Server) *endpoint {
+	t.Helper()
+	client, err := s3.NewClient("bucket", &s3.Configuration{
+		Region:      "us-east-1",
+		Endpoint:    server.URL,
+		Credentials: &s3.Credentials{AccessKeyID: "id", SecretAccessKey: "secret"},
+	})
+	if err != nil {
+		t.Fatal("unable to create client:", err)
+	}
+	t.Cleanup(client.Close)
+	return &endpoint{
+		logger: logging.NewLogger(logging.LevelDisabled, io.Discard),
+		store:  &store{client: client, prefix: "prefix"},
+		snapshot: &core.Snapshot{
+			Content: &core.Entry{
+				Kind: core.EntryKind_Directory,
+				Contents: map[string]*core.Entry{
+					"file": {Kind: core.EntryKind_File, Digest: []byte{1}},
+				},
+			},
+			PreservesExecutability: true,
+		},
+		stager: staging.NewStager(t.TempDir(), synchronization.Version_Version1.Hasher, 0, 0),
+	}
+}
+
+// TestTransitionRemovesObjectsAfterSavingIndex tests that objects for removed
+// files are only deleted once the updated index has been saved.
+func TestTransitionRemovesObjectsAfterSavingIndex(t *testing.T) {
+	// Start the server.
+	recorder := &recordingServer{}
+	server := httptest.NewServer(recorder)
+	defer server.Close()
+
+	// Remove the file.
+	e := newTestEndpoint(t, server)
+	old := e.snapshot.Content.Contents["file"]
+	results, problems, _, err := e.Transition(context.Background(), []*core.Change{
+		{Path: "file", Old: old},
+	})
+	if err != nil {
+		t.Fatal("transition failed:", err)
+	} else if len(problems) > 0 {
+		t.Error("transition reported problems:", problems)
+	} else if len(results) != 1 || results[0] != nil {
+		t.Error("unexpected transition results:", results)
+	}
+
+	// Verify the request ordering.
+	expected := []string{
+		"PUT /bucket/prefix/" + indexName,
+		"DELETE /bucket/prefix/file",
+	}
+	if len(recorder.requests) != len(expected) {
+		t.Fatal("unexpected requests:", recorder.requests)
+	}
+	for i, request := range recorder.requests {
+		if request != expected[i] {
+			t.Errorf("request %d mismatch: %s != %s", i, request, expected[i])
+		}
+	}
+}
+
+// TestTransitionIndexFailurePreservesObjects tests that objects for removed
+// files aren't deleted if the updated index can't be saved.
+func TestTransitionIndexFailurePreservesObjects(t *testing.T) {
+	// Start the server.
+	recorder := &recordingServer{failIndexWrites: true}
+	server := httptest.NewServer(recorder)
+	defer server.Close()
+
+	// Attempt to remove the file.
+	e := newTestEndpoint(t, server)
+	old := e.snapshot.Content.Contents["file"]
+	if _, _, _, err := e.Transition(context.Background(), []*core.Change{
+		{Path: "file", Old: old},
+	}); err == nil {
+		t.Fatal("transition succeeded despite index write failure")
+	}
+
+	// Verify that no objects were deleted.
+	for _, request := range recorder.requests {
+		if strings.HasPrefix(request, http.MethodDelete) {
+			t.Error("object deleted despite index write failure:", request)
+		}
+	}
+}
@@ -0,0 +1,188 @@
+package s3
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"strings"
+
+	"google.golang.org/protobuf/proto"
+
+	"github.com/mutagen-io/mutagen/pkg/s3"
+	"github.com/mutagen-io/mutagen/pkg/synchronization/core"
+)
+
+const (
+	// indexName is the name of the index object within the key prefix.
+	indexName = ".mutagen-s3-index"
+	// maximumIndexSize is the maximum allowed size for the index object.
+	maximumIndexSize = 1024 * 1024 * 1024
+)
+
+// store maps synchronization root paths onto object keys within a bucket.
+type store struct {
+	// client is the S3 client.
+	client *s3.Client
+	// prefix is the key prefix under which content is stored, without leading
+	// or trailing slashes. It may be empty.
+	prefix string
+}
+
+// key computes the object key for the specified path.
+func (s *store) key(path string) (string, error) {
+	if path == "" {
+		if s.prefix == "" {
+			return "", errors.New("files can't be stored at the root of a bucket")
+		}
+		return s.prefix, nil
+	} else if path == indexName {
+		return "", errors.New("path conflicts with index object")
+	} else if s.prefix == "" {
+		return path, nil
+	}
+	return s.prefix + "/" + path, nil
+}
+
+// indexKey returns the key of the index object.
+func (s *store) indexKey() string {
+	if s.prefix == "" {
+		return indexName
+	}
+	return s.prefix + "/" + indexName
+}
+
+// loadIndex loads the snapshot stored in the index object. If no index object
+// exists, then an empty snapshot is returned.
+func (s *store) loadIndex() (*core.Snapshot, error) {
+	// Retrieve the index content.
+	content, size, err := s.client.Get(s.indexKey())
+	if err != nil {
+		if errors.Is(err, fs.ErrNotExist) {
+			return &core.Snapshot{PreservesExecutability: true}, nil
+		}
+		return nil, fmt.Errorf("unable to retrieve index: %w", err)
+	}
+	defer content.Close()
+	if size > maximumIndexSize {
+		return nil, errors.New("index too large")
+	}
+	data, err := io.ReadAll(content)
+	if err != nil {
+		return nil, fmt.Errorf("unable to read index: %w", err)
+	}
+
+	// Decode and validate the snapshot.
+	snapshot := &core.Snapshot{}
+	if err := proto.Unmarshal(data, snapshot); err != nil {
+		return nil, fmt.Errorf("unable to decode index: %w", err)
+	} else if err = snapshot.EnsureValid(); err != nil {
+		return nil, fmt.Errorf("invalid index: %w", err)
+	}
+
+	// Success.
+	return snapshot, nil
+}
+
+// saveIndex saves the specified snapshot to the index object.
+func (s *store) saveIndex(snapshot *core.Snapshot) error {
+	data, err := proto.Marshal(snapshot)
+	if err != nil {
+		return fmt.Errorf("unable to encode index: %w", err)
+	}
+	return s.client.Put(s.indexKey(), bytes.NewReader(data))
+}
+
+// Open implements rsync.Source.Open.
+func (s *store) Open(path string) (io.ReadCloser, uint64, error) {
+	key, err := s.key(path)
+	if err != nil {
+		return nil, 0, err
+	}
+	return s.client.Get(key)
+}
+
+// lookup returns the entry at the specified path within the hierarchy rooted
+// at root, or nil if no entry exists at that path.
+func lookup(root *core.Entry, path string) *core.Entry {
+	if path == "" {
+		return root
+	}
+	for _, component := range strings.Split(path, "/") {
+		if root == nil {
+			return nil
+		}
+		root = root.Contents[component]
+	}
+	return root
+}
+
+// replace replaces the entry at the specified path within the hierarchy rooted
+// at root, returning the new root. The parent of the path must exist and be a
+// directory unless the path is the root path.
+func replace(root *core.Entry, path string, entry *core.Entry) *core.Entry {
+	if path == "" {
+		return entry
+	}
+	parent, name := root, path
+	if slash := strings.LastIndexByte(path, '/'); slash != -1 {
+		parent, name = lookup(root, path[:slash]), path[slash+1:]
+	}
+	if entry == nil {
+		delete(parent.Contents, name)
+	} else {
+		if parent.Contents == nil {
+			parent.Contents = make(map[string]*core.Entry)
+		}
+		parent.Contents[name] = entry
+	}
+	return root
+}
+
+// files returns a map of paths to file entries for all files within the
+// hierarchy rooted at the specified entry and path.
+func files(path string, entry *core.Entry) map[string]*core.Entry {
+	result := make(map[string]*core.Entry)
+	var walk func(string, *core.Entry)
+	walk = func(path string, entry *core.Entry) {
+		if entry == nil {
+			return
+		} else if entry.Kind == core.EntryKind_File {
+			result[path] = entry
+		}
+		for name, child := range entry.Contents {
+			if path == "" {
+				walk(name, child)
+			} else {
+				walk(path+"/"+name, child)
+			}
+		}
+	}
+	walk(path, entry)
+	return result
+}
+
+// statistics computes the directory, file, and symbolic link counts for the
+// hierarchy rooted at the specified entry.
+func statistics(entry *core.Entry) (directories, regularFiles, symbolicLinks uint64) {
+	var walk func(*core.Entry)
+	walk = func(entry *core.Entry) {
+		if entry == nil {
+			return
+		}
+		switch entry.Kind {
+		case core.EntryKind_Directory:
+			directories++
+		case core.EntryKind_File:
+			regularFiles++
+		case core.EntryKind_SymbolicLink:
+			symbolicLinks++
+		}
+		for _, child := range entry.Contents {
+			walk(child)
+		}
+	}
+	walk(entry)
+	return
+}
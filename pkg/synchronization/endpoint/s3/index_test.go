@@ -0,0 +1,83 @@
+package s3
+
+import (
+	"testing"
+
+	"github.com/mutagen-io/mutagen/pkg/synchronization/core"
+)
+
+// TestStoreKey tests store.key.
+func TestStoreKey(t *testing.T) {
+	// Set up test cases.
+	testCases := []struct {
+		prefix      string
+		path        string
+		expected    string
+		expectError bool
+	}{
+		{"", "", "", true},
+		{"", "file", "file", false},
+		{"", "directory/file", "directory/file", false},
+		{"", indexName, "", true},
+		{"prefix", "", "prefix", false},
+		{"prefix", "file", "prefix/file", false},
+		{"prefix/nested", "directory/file", "prefix/nested/directory/file", false},
+		{"prefix", indexName, "", true},
+	}
+
+	// Process test cases.
+	for i, testCase := range testCases {
+		s := &store{prefix: testCase.prefix}
+		key, err := s.key(testCase.path)
+		if err != nil && !testCase.expectError {
+			t.Errorf("test index %d: unexpected error: %v", i, err)
+		} else if err == nil && testCase.expectError {
+			t.Errorf("test index %d: error expected", i)
+		} else if key != testCase.expected {
+			t.Errorf("test index %d: key does not match expected: %s != %s", i, key, testCase.expected)
+		}
+	}
+}
+
+// TestReplaceAndFiles tests replace, lookup, files, and statistics.
+func TestReplaceAndFiles(t *testing.T) {
+	// Create a hierarchy piece by piece.
+	var root *core.Entry
+	root = replace(root, "", &core.Entry{Kind: core.EntryKind_Directory})
+	root = replace(root, "directory", &core.Entry{Kind: core.EntryKind_Directory})
+	root = replace(root, "directory/file", &core.Entry{Kind: core.EntryKind_File, Digest: []byte{1}})
+	root = replace(root, "link", &core.Entry{Kind: core.EntryKind_SymbolicLink, Target: "directory"})
+	root = replace(root, "file", &core.Entry{Kind: core.EntryKind_File, Digest: []byte{2}})
+
+	// Verify lookup.
+	if entry := lookup(root, "directory/file"); entry == nil || entry.Kind != core.EntryKind_File {
+		t.Error("unable to look up nested file")
+	} else if lookup(root, "missing/file") != nil {
+		t.Error("lookup of missing path returned entry")
+	}
+
+	// Verify file enumeration.
+	if all := files("", root); len(all) != 2 {
+		t.Error("unexpected number of files:", len(all))
+	} else if _, ok := all["directory/file"]; !ok {
+		t.Error("nested file not enumerated")
+	}
+	if nested := files("directory", lookup(root, "directory")); len(nested) != 1 {
+		t.Error("unexpected number of nested files:", len(nested))
+	} else if _, ok := nested["directory/file"]; !ok {
+		t.Error("nested file path incorrect")
+	}
+
+	// Verify statistics.
+	if directories, regularFiles, symbolicLinks := statistics(root); directories != 2 || regularFiles != 2 || symbolicLinks != 1 {
+		t.Error("statistics do not match expected:", directories, regularFiles, symbolicLinks)
+	}
+
+	// Remove the directory and verify the result.
+	root = replace(root, "directory", nil)
+	if lookup(root, "directory") != nil {
+		t.Error("directory not removed")
+	} else if all := files("", root); len(all) != 1 {
+		t.Error("unexpected number of files after removal:", len(all))
+	}
+}
@@ -0,0 +1,4 @@
+// Package sftp provides an agentless synchronization endpoint implementation
+// that operates over SFTP. It's intended for hosts where Mutagen's agent can't
+// be executed and trades performance (and some features) for compatibility.
+package sftp
@@ -0,0 +1,465 @@
+package sftp
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	pathpkg "path"
+	"strings"
+	"time"
+
+	"github.com/mutagen-io/mutagen/pkg/filesystem"
+	"github.com/mutagen-io/mutagen/pkg/logging"
+	"github.com/mutagen-io/mutagen/pkg/sftp"
+	"github.com/mutagen-io/mutagen/pkg/synchronization"
+	"github.com/mutagen-io/mutagen/pkg/synchronization/core"
+	"github.com/mutagen-io/mutagen/pkg/synchronization/endpoint/staging"
+	"github.com/mutagen-io/mutagen/pkg/synchronization/rsync"
+)
+
+// remoteSource is an rsync.Source implementation that reads files over SFTP.
+type remoteSource struct {
+	// client is the SFTP client.
+	client *sftp.Client
+	// root is the absolute path to the synchronization root on the server.
+	root string
+}
+
+// Open implements rsync.Source.Open.
+func (s *remoteSource) Open(path string) (io.ReadCloser, uint64, error) {
+	// Compute the full path.
+	path = pathpkg.Join(s.root, path)
+
+	// Verify that the path refers to a regular file and grab its size.
+	info, err := s.client.Lstat(path)
+	if err != nil {
+		return nil, 0, err
+	} else if !info.IsRegular() {
+		return nil, 0, errors.New("not a regular file")
+	}
+
+	// Open the file.
+	file, err := s.client.Open(path)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	// Success.
+	return file, info.Size, nil
+}
+
+// endpoint provides a synchronization.Endpoint implementation that operates
+// over SFTP without requiring an agent on the remote host.
+type endpoint struct {
+	// logger is the underlying logger.
+	logger *logging.Logger
+	// client is the SFTP client.
+	client *sftp.Client
+	// closer closes the underlying transport.
+	closer io.Closer
+	// root is the absolute path to the synchronization root on the server.
+	root string
+	// readOnly indicates whether or not the endpoint should be operating in a
+	// read-only mode (i.e. it is the source of unidirectional synchronization).
+	readOnly bool
+	// maximumEntryCount is the maximum number of entries that the endpoint will
+	// synchronize.
+	maximumEntryCount uint64
+	// watchPollingInterval is the interval at which Poll returns to trigger a
+	// re-scan. It is zero if watching is disabled.
+	watchPollingInterval time.Duration
+	// symbolicLinkMode is the symbolic link mode.
+	symbolicLinkMode core.SymbolicLinkMode
+	// ignorer is the ignorer identifying ignored paths.
+	ignorer *core.Ignorer
+	// defaultFileMode is the default mode for newly created files.
+	defaultFileMode filesystem.Mode
+	// defaultDirectoryMode is the default mode for newly created directories.
+	defaultDirectoryMode filesystem.Mode
+	// version is the session version.
+	version synchronization.Version
+	// cache is the cache from the last scan.
+	cache map[string]*cacheEntry
+	// lastScanEntryCount is the entry count from the last scan.
+	lastScanEntryCount uint64
+	// stager is the staging coordinator.
+	stager *staging.Stager
+}
+
+// NewEndpoint creates a new SFTP endpoint using the specified client, which
+// will be closed (along with the specified closer) when the endpoint is shut
+// down. Both will also be closed if endpoint creation fails. The root may be
+// absolute or home-relative (i.e. begin with "~").
+func NewEndpoint(
+	logger *logging.Logger,
+	client *sftp.Client,
+	closer io.Closer,
+	root string,
+	sessionIdentifier string,
+	version synchronization.Version,
+	configuration *synchronization.Configuration,
+	alpha bool,
+) (synchronization.Endpoint, error) {
+	// Create the endpoint.
+	endpoint, err := newEndpoint(logger, client, closer, root, sessionIdentifier, version, configuration, alpha)
+	if err != nil {
+		client.Close()
+		if closer != nil {
+			closer.Close()
+		}
+		return nil, err
+	}
+
+	// Success.
+	return endpoint, nil
+}
+
+// newEndpoint implements the bulk of NewEndpoint, but doesn't close resources
+// on failure.
+func newEndpoint(
+	logger *logging.Logger,
+	client *sftp.Client,
+	closer io.Closer,
+	root string,
+	sessionIdentifier string,
+	version synchronization.Version,
+	configuration *synchronization.Configuration,
+	alpha bool,
+) (*endpoint, error) {
+	// Reject configuration options that require local filesystem access or
+	// command execution on the remote host.
+	if configuration.TrashDirectory != "" {
+		return nil, errors.New("trash directories are not supported by SFTP endpoints")
+	} else if configuration.VersionCount > 0 {
+		return nil, errors.New("file versioning is not supported by SFTP endpoints")
+	} else if len(configuration.AfterSync) > 0 || len(configuration.BeforeApply) > 0 {
+		return nil, errors.New("hooks are not supported by SFTP endpoints")
+	} else if configuration.DefaultOwner != "" || configuration.DefaultGroup != "" {
+		return nil, errors.New("ownership specifications are not supported by SFTP endpoints")
+	} else if configuration.IgnoreVCSIgnores {
+		return nil, errors.New("VCS ignore files are not supported by SFTP endpoints")
+	}
+
+	// Resolve home-relative roots.
+	if root == "~" || strings.HasPrefix(root, "~/") {
+		home, err := client.RealPath(".")
+		if err != nil {
+			return nil, fmt.Errorf("unable to determine home directory: %w", err)
+		}
+		root = pathpkg.Join(home, root[1:])
+	} else if !pathpkg.IsAbs(root) {
+		return nil, errors.New("synchronization root must be absolute or home-relative")
+	}
+	root = pathpkg.Clean(root)
+
+	// Determine if the endpoint is running in a read-only mode.
+	synchronizationMode := configuration.SynchronizationMode
+	if synchronizationMode.IsDefault() {
+		synchronizationMode = version.DefaultSynchronizationMode()
+	}
+	unidirectional := synchronizationMode == core.SynchronizationMode_SynchronizationModeOneWaySafe ||
+		synchronizationMode == core.SynchronizationMode_SynchronizationModeOneWayReplica
+	readOnly := alpha && unidirectional
+
+	// Determine the maximum entry count.
+	maximumEntryCount := configuration.MaximumEntryCount
+	if maximumEntryCount == 0 {
+		maximumEntryCount = version.DefaultMaximumEntryCount()
+	}
+
+	// Determine the maximum staging file size.
+	maximumStagingFileSize := configuration.MaximumStagingFileSize
+	if maximumStagingFileSize == 0 {
+		maximumStagingFileSize = version.DefaultMaximumStagingFileSize()
+	}
+
+	// Determine the maximum total staging size.
+	maximumStagingTotalSize := configuration.MaximumStagingTotalSize
+	if maximumStagingTotalSize == 0 {
+		maximumStagingTotalSize = version.DefaultMaximumStagingTotalSize()
+	}
+
+	// Compute the effective watch mode and polling interval. Since there's no
+	// way to watch for modifications over SFTP, all watching is poll-based.
+	watchMode := configuration.WatchMode
+	if watchMode.IsDefault() {
+		watchMode = version.DefaultWatchMode()
+	}
+	var watchPollingInterval time.Duration
+	if watchMode != synchronization.WatchMode_WatchModeNoWatch {
+		interval := configuration.WatchPollingInterval
+		if interval == 0 {
+			interval = version.DefaultWatchPollingInterval()
+		}
+		watchPollingInterval = time.Duration(interval) * time.Second
+	}
+
+	// Compute the effective symbolic link mode and ensure that it's supported.
+	symbolicLinkMode := configuration.SymbolicLinkMode
+	if symbolicLinkMode.IsDefault() {
+		symbolicLinkMode = version.DefaultSymbolicLinkMode()
+	}
+	if symbolicLinkMode != core.SymbolicLinkMode_SymbolicLinkModeIgnore &&
+		symbolicLinkMode != core.SymbolicLinkMode_SymbolicLinkModePortable &&
+		symbolicLinkMode != core.SymbolicLinkMode_SymbolicLinkModePOSIXRaw {
+		return nil, fmt.Errorf("symbolic link mode not supported by SFTP endpoints: %s", symbolicLinkMode.Description())
+	}
+
+	// Compute the effective VCS ignore mode.
+	ignoreVCSMode := configuration.IgnoreVCSMode
+	if ignoreVCSMode.IsDefault() {
+		ignoreVCSMode = version.DefaultIgnoreVCSMode()
+	}
+
+	// Compute a combined ignore list and create an ignorer.
+	var ignores []string
+	if ignoreVCSMode == core.IgnoreVCSMode_IgnoreVCSModeIgnore {
+		ignores = append(ignores, core.DefaultVCSIgnores...)
+	}
+	ignores = append(ignores, configuration.DefaultIgnores...)
+	ignores = append(ignores, configuration.Ignores...)
+	ignorer, err := core.NewIgnorer(ignores)
+	if err != nil {
+		return nil, fmt.Errorf("unable to create ignorer: %w", err)
+	}
+
+	// Compute the effective default file mode.
+	defaultFileMode := filesystem.Mode(configuration.DefaultFileMode)
+	if defaultFileMode == 0 {
+		defaultFileMode = version.DefaultFileMode()
+	}
+
+	// Compute the effective default directory mode.
+	defaultDirectoryMode := filesystem.Mode(configuration.DefaultDirectoryMode)
+	if defaultDirectoryMode == 0 {
+		defaultDirectoryMode = version.DefaultDirectoryMode()
+	}
+
+	// Compute the staging root. Since the synchronization root isn't local, we
+	// always stage files in the Mutagen data directory.
+	stagingRoot, err := staging.PathForMutagenStagingRoot(sessionIdentifier, alpha)
+	if err != nil {
+		return nil, err
+	}
+
+	// Success.
+	return &endpoint{
+		logger:               logger,
+		client:               client,
+		closer:               closer,
+		root:                 root,
+		readOnly:             readOnly,
+		maximumEntryCount:    maximumEntryCount,
+		watchPollingInterval: watchPollingInterval,
+		symbolicLinkMode:     symbolicLinkMode,
+		ignorer:              ignorer,
+		defaultFileMode:      defaultFileMode,
+		defaultDirectoryMode: defaultDirectoryMode,
+		version:              version,
+		stager: staging.NewStager(
+			stagingRoot,
+			version.Hasher,
+			maximumStagingFileSize,
+			maximumStagingTotalSize,
+		),
+	}, nil
+}
+
+// Poll implements the Poll method for SFTP endpoints. Since SFTP doesn't
+// provide any change notification mechanism, it simply waits for the polling
+// interval to elapse.
+func (e *endpoint) Poll(ctx context.Context) error {
+	// If watching is disabled, then just wait for cancellation.
+	if e.watchPollingInterval == 0 {
+		<-ctx.Done()
+		return nil
+	}
+
+	// Wait for either cancellation or the polling interval to elapse.
+	timer := time.NewTimer(e.watchPollingInterval)
+	defer timer.Stop()
+	select {
+	case <-ctx.Done():
+	case <-timer.C:
+	}
+
+	// Done.
+	return nil
+}
+
+// Scan implements the Scan method for SFTP endpoints.
+func (e *endpoint) Scan(ctx context.Context, _ *core.Entry, _ bool, rehashPaths []string) (*core.Snapshot, error, bool) {
+	// Set up the scanner. If rehashing has been requested, then exclude the
+	// corresponding entries from the existing cache when using it as a
+	// baseline.
+	s := &scanner{
+		ctx:              ctx,
+		client:           e.client,
+		root:             e.root,
+		ignorer:          e.ignorer,
+		symbolicLinkMode: e.symbolicLinkMode,
+		hasher:           e.version.Hasher(),
+		buffer:           make([]byte, scanCopyBufferSize),
+		cache:            make(map[string]*cacheEntry),
+	}
+	if len(rehashPaths) == 0 {
+		s.baseline = e.cache
+	} else {
+		s.baseline = make(map[string]*cacheEntry, len(e.cache))
+		for path, entry := range e.cache {
+			if !core.PathWithinAny(path, rehashPaths) {
+				s.baseline[path] = entry
+			}
+		}
+	}
+
+	// Perform the scan. Any error is likely due to concurrent modifications
+	// or a transient server failure, so suggest a retry.
+	e.logger.Debug("Performing full scan")
+	content, err := s.scan()
+	if err != nil {
+		return nil, err, true
+	}
+
+	// Update the cache and entry count.
+	e.cache = s.cache
+	e.lastScanEntryCount = s.directories + s.files + s.symbolicLinks
+
+	// Verify that we haven't exceeded the maximum entry count.
+	if e.lastScanEntryCount > e.maximumEntryCount {
+		e.logger.Debugf("Scan count (%d) exceeded maximum allowed entry count (%d)",
+			e.lastScanEntryCount, e.maximumEntryCount,
+		)
+		return nil, errors.New("exceeded allowed entry count"), true
+	}
+
+	// Success.
+	return &core.Snapshot{
+		Content:                content,
+		PreservesExecutability: true,
+		Directories:            s.directories,
+		Files:                  s.files,
+		SymbolicLinks:          s.symbolicLinks,
+		TotalFileSize:          s.totalFileSize,
+	}, nil, false
+}
+
+// Stage implements the Stage method for SFTP endpoints.
+func (e *endpoint) Stage(paths []string, digests [][]byte) ([]string, []*rsync.Signature, rsync.Receiver, error) {
+	// If we're in a read-only mode, we shouldn't be staging files.
+	if e.readOnly {
+		return nil, nil, nil, errors.New("endpoint is in read-only mode")
+	}
+
+	// Validate argument lengths and bail if there's nothing to stage.
+	if len(paths) != len(digests) {
+		return nil, nil, nil, errors.New("path count does not match digest count")
+	} else if len(paths) == 0 {
+		return nil, nil, nil, nil
+	}
+
+	// Verify that the number of paths provided isn't going to put us over the
+	// maximum number of allowed entries.
+	if e.maximumEntryCount != 0 && (e.maximumEntryCount-e.lastScanEntryCount) < uint64(len(paths)) {
+		return nil, nil, nil, errors.New("staging would exceeded allowed entry count")
+	}
+
+	// Reset the stager's total size tracking, since the maximum total staging
+	// size applies on a per-operation basis.
+	e.stager.ResetTotalSize()
+
+	// Filter out paths that have already been staged by a previous
+	// (interrupted) staging operation.
+	filteredPaths := paths[:0]
+	for p, path := range paths {
+		if !e.stager.Staged(path, digests[p]) {
+			filteredPaths = append(filteredPaths, path)
+		}
+	}
+	if len(filteredPaths) == 0 {
+		return nil, nil, nil, nil
+	}
+
+	// Use empty signatures for all paths. Computing signatures would require
+	// reading the existing remote files in their entirety, which would cost
+	// roughly as much as transferring their new content.
+	signatures := make([]*rsync.Signature, len(filteredPaths))
+	for p := range signatures {
+		signatures[p] = &rsync.Signature{}
+	}
+
+	// Create a receiver.
+	receiver, err := rsync.NewReceiver(e.stager.Root(), filteredPaths, signatures, e.stager)
+	if err != nil {
+		return nil, nil, nil, fmt.Errorf("unable to create rsync receiver: %w", err)
+	}
+
+	// Done.
+	return filteredPaths, signatures, receiver, nil
+}
+
+// Supply implements the Supply method for SFTP endpoints.
+func (e *endpoint) Supply(paths []string, signatures []*rsync.Signature, receiver rsync.Receiver) error {
+	return rsync.TransmitFromSource(&remoteSource{e.client, e.root}, paths, signatures, receiver)
+}
+
+// Transition implements the Transition method for SFTP endpoints.
+func (e *endpoint) Transition(ctx context.Context, transitions []*core.Change) ([]*core.Entry, []*core.Problem, bool, error) {
+	// If we're in a read-only mode, we shouldn't be performing transitions.
+	if e.readOnly {
+		return nil, nil, false, errors.New("endpoint is in read-only mode")
+	}
+
+	// Perform the transitions.
+	t := &transitioner{
+		client:                         e.client,
+		root:                           e.root,
+		cache:                          e.cache,
+		hasher:                         e.version.Hasher(),
+		symbolicLinkMode:               e.symbolicLinkMode,
+		defaultFilePermissionMode:      e.defaultFileMode,
+		defaultDirectoryPermissionMode: e.defaultDirectoryMode,
+		copyBuffer:                     make([]byte, scanCopyBufferSize),
+		provider:                       e.stager,
+	}
+	results, problems, stagerMissingFiles := t.transition(ctx, transitions)
+
+	// Wipe the staging directory. We don't monitor for errors here, because we
+	// need to return the results and problems no matter what, but if there's
+	// something weird going on with the filesystem, we'll see it the next time
+	// we scan or stage.
+	if err := e.stager.Wipe(); err != nil {
+		e.logger.Warn("Unable to wipe stager:", err)
+	}
+
+	// Done.
+	return results, problems, stagerMissingFiles, nil
+}
+
+// Versions implements the Versions method for SFTP endpoints.
+func (e *endpoint) Versions(_, _ string) ([]*synchronization.FileVersion, string, error) {
+	return nil, "versioning not supported by SFTP endpoints", nil
+}
+
+// Capabilities implements the Capabilities method for SFTP endpoints. Probing
+// isn't possible over SFTP, so it always returns nil.
+func (e *endpoint) Capabilities() *synchronization.FilesystemCapabilities {
+	return nil
+}
+
+// Shutdown implements the Shutdown method for SFTP endpoints.
+func (e *endpoint) Shutdown() error {
+	// Close the SFTP client.
+	err := e.client.Close()
+
+	// Close the underlying transport.
+	if e.closer != nil {
+		if closeErr := e.closer.Close(); closeErr != nil && err == nil {
+			err = closeErr
+		}
+	}
+
+	// Done.
+	return err
+}
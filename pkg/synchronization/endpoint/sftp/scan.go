@@ -0,0 +1,303 @@
+package sftp
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"hash"
+	"io"
+	"io/fs"
+	pathpkg "path"
+	"strings"
+	"time"
+
+	"github.com/mutagen-io/mutagen/pkg/filesystem"
+	"github.com/mutagen-io/mutagen/pkg/sftp"
+	"github.com/mutagen-io/mutagen/pkg/synchronization/core"
+)
+
+// scanCopyBufferSize is the size of the buffer used to read file content when
+// computing digests.
+const scanCopyBufferSize = 32 * 1024
+
+// racyModificationWindow is the window after a file's modification time during
+// which cached metadata can't be trusted to identify its content. SFTP (version
+// 3) only reports modification times with one-second granularity, so a file
+// modified shortly after being hashed may retain the same size and reported
+// modification time. This window also absorbs modest clock skew between the
+// client and the server.
+const racyModificationWindow = 2 * time.Second
+
+// cacheEntry stores the metadata and digest for a file encountered during a
+// scan, allowing subsequent scans to avoid re-reading unmodified files and
+// allowing transitions to detect files modified since the scan.
+type cacheEntry struct {
+	// mode is the file mode, including type bits.
+	mode uint32
+	// size is the file size.
+	size uint64
+	// modificationTime is the file modification time.
+	modificationTime time.Time
+	// digest is the file digest.
+	digest []byte
+	// cached is the (local) time at which the file was hashed, or, for entries
+	// carried over from a previous scan, the time at which it was originally
+	// hashed.
+	cached time.Time
+}
+
+// matches returns whether or not the cache entry matches the specified file
+// information.
+func (c *cacheEntry) matches(info *sftp.FileInfo) bool {
+	return c.mode == info.Mode &&
+		c.size == info.Size &&
+		c.modificationTime.Equal(info.ModificationTime)
+}
+
+// racy returns whether or not the file was modified so close to the time at
+// which it was hashed that a subsequent modification might not be reflected in
+// its metadata. The digests for such entries must be recomputed rather than
+// trusted.
+func (c *cacheEntry) racy() bool {
+	return c.cached.Sub(c.modificationTime) < racyModificationWindow
+}
+
+// isStatusError returns whether or not an error is a status error returned by
+// the SFTP server, as opposed to a transport failure. Status errors are
+// recorded as problems rather than causing the operation to fail.
+func isStatusError(err error) bool {
+	var statusError *sftp.StatusError
+	return errors.As(err, &statusError)
+}
+
+// scanner provides recursive scanning of a synchronization root over SFTP.
+type scanner struct {
+	// ctx is the context regulating the scan.
+	ctx context.Context
+	// client is the SFTP client.
+	client *sftp.Client
+	// root is the absolute path to the synchronization root on the server.
+	root string
+	// ignorer is the ignorer identifying ignored paths.
+	ignorer *core.Ignorer
+	// symbolicLinkMode is the symbolic link mode.
+	symbolicLinkMode core.SymbolicLinkMode
+	// hasher is the hasher used to compute file digests.
+	hasher hash.Hash
+	// buffer is the buffer used to read file content.
+	buffer []byte
+	// baseline is the cache from the previous scan. It may be nil.
+	baseline map[string]*cacheEntry
+	// cache is the cache being populated by the scan.
+	cache map[string]*cacheEntry
+	// directories is the number of synchronizable directories encountered.
+	directories uint64
+	// files is the number of synchronizable files encountered.
+	files uint64
+	// symbolicLinks is the number of synchronizable symbolic links
+	// encountered.
+	symbolicLinks uint64
+	// totalFileSize is the total size of synchronizable files encountered.
+	totalFileSize uint64
+}
+
+// file scans a file.
+func (s *scanner) file(path string, info *sftp.FileInfo) (*core.Entry, error) {
+	// Check if we can use a cached digest. If not, then read the file and
+	// compute its digest, recording the time before reading so that
+	// modifications made during the read are treated as racy.
+	var digest []byte
+	var cachedAt time.Time
+	if cached, ok := s.baseline[path]; ok && cached.matches(info) && !cached.racy() {
+		digest = cached.digest
+		cachedAt = cached.cached
+	} else {
+		cachedAt = time.Now()
+		file, err := s.client.Open(pathpkg.Join(s.root, path))
+		if err != nil {
+			if isStatusError(err) {
+				return &core.Entry{
+					Kind:    core.EntryKind_Problematic,
+					Problem: fmt.Errorf("unable to open file: %w", err).Error(),
+				}, nil
+			}
+			return nil, fmt.Errorf("unable to open file (%s): %w", path, err)
+		}
+		s.hasher.Reset()
+		copied, err := io.CopyBuffer(s.hasher, file, s.buffer)
+		file.Close()
+		if err != nil {
+			if isStatusError(err) {
+				return &core.Entry{
+					Kind:    core.EntryKind_Problematic,
+					Problem: fmt.Errorf("unable to read file: %w", err).Error(),
+				}, nil
+			}
+			return nil, fmt.Errorf("unable to read file (%s): %w", path, err)
+		} else if uint64(copied) != info.Size {
+			return &core.Entry{
+				Kind:    core.EntryKind_Problematic,
+				Problem: "file modified during scan",
+			}, nil
+		}
+		digest = s.hasher.Sum(nil)
+	}
+
+	// Record the cache entry.
+	s.cache[path] = &cacheEntry{
+		mode:             info.Mode,
+		size:             info.Size,
+		modificationTime: info.ModificationTime,
+		digest:           digest,
+		cached:           cachedAt,
+	}
+
+	// Update statistics.
+	s.files++
+	s.totalFileSize += info.Size
+
+	// Success.
+	return &core.Entry{
+		Kind:       core.EntryKind_File,
+		Digest:     digest,
+		Executable: info.Permissions()&0111 != 0,
+	}, nil
+}
+
+// symbolicLink scans a symbolic link.
+func (s *scanner) symbolicLink(path string) (*core.Entry, error) {
+	// If symbolic links are being ignored, then treat this as untracked.
+	if s.symbolicLinkMode == core.SymbolicLinkMode_SymbolicLinkModeIgnore {
+		return &core.Entry{Kind: core.EntryKind_Untracked}, nil
+	}
+
+	// Read the target.
+	target, err := s.client.ReadLink(pathpkg.Join(s.root, path))
+	if err != nil {
+		if isStatusError(err) {
+			return &core.Entry{
+				Kind:    core.EntryKind_Problematic,
+				Problem: fmt.Errorf("unable to read symbolic link target: %w", err).Error(),
+			}, nil
+		}
+		return nil, fmt.Errorf("unable to read symbolic link target (%s): %w", path, err)
+	}
+
+	// Validate the target based on the symbolic link mode.
+	if s.symbolicLinkMode == core.SymbolicLinkMode_SymbolicLinkModePortable {
+		if target, err = core.NormalizeSymbolicLinkAndEnsurePortable(path, target); err != nil {
+			return &core.Entry{
+				Kind:    core.EntryKind_Problematic,
+				Problem: fmt.Errorf("invalid symbolic link: %w", err).Error(),
+			}, nil
+		}
+	} else if target == "" {
+		return &core.Entry{
+			Kind:    core.EntryKind_Problematic,
+			Problem: "symbolic link target is empty",
+		}, nil
+	}
+
+	// Update statistics.
+	s.symbolicLinks++
+
+	// Success.
+	return &core.Entry{
+		Kind:   core.EntryKind_SymbolicLink,
+		Target: target,
+	}, nil
+}
+
+// directory scans a directory.
+func (s *scanner) directory(path string) (*core.Entry, error) {
+	// Check for cancellation.
+	select {
+	case <-s.ctx.Done():
+		return nil, errors.New("scan cancelled")
+	default:
+	}
+
+	// Read the directory contents.
+	contents, err := s.client.ReadDirectory(pathpkg.Join(s.root, path))
+	if err != nil {
+		if isStatusError(err) {
+			return &core.Entry{
+				Kind:    core.EntryKind_Problematic,
+				Problem: fmt.Errorf("unable to read directory contents: %w", err).Error(),
+			}, nil
+		}
+		return nil, fmt.Errorf("unable to read directory contents (%s): %w", path, err)
+	}
+
+	// Process contents.
+	result := &core.Entry{Kind: core.EntryKind_Directory}
+	if len(contents) > 0 {
+		result.Contents = make(map[string]*core.Entry, len(contents))
+	}
+	for _, content := range contents {
+		// Skip temporary files created by Mutagen.
+		if strings.HasPrefix(content.Name, filesystem.TemporaryNamePrefix) {
+			continue
+		}
+
+		// Compute the content path.
+		contentPath := content.Name
+		if path != "" {
+			contentPath = path + "/" + content.Name
+		}
+
+		// Check if the content is ignored.
+		if s.ignorer.Ignored(contentPath, content.IsDirectory()) {
+			result.Contents[content.Name] = &core.Entry{Kind: core.EntryKind_Untracked}
+			continue
+		}
+
+		// Scan the content based on its type.
+		var entry *core.Entry
+		if content.IsDirectory() {
+			entry, err = s.directory(contentPath)
+		} else if content.IsRegular() {
+			entry, err = s.file(contentPath, content)
+		} else if content.IsSymbolicLink() {
+			entry, err = s.symbolicLink(contentPath)
+		} else {
+			entry = &core.Entry{Kind: core.EntryKind_Untracked}
+		}
+		if err != nil {
+			return nil, err
+		}
+		result.Contents[content.Name] = entry
+	}
+
+	// Update statistics.
+	s.directories++
+
+	// Success.
+	return result, nil
+}
+
+// scan performs a scan of the synchronization root.
+func (s *scanner) scan() (*core.Entry, error) {
+	// Query the synchronization root. If it doesn't exist, then there's no
+	// content.
+	info, err := s.client.Lstat(s.root)
+	if err != nil {
+		if isStatusError(err) && errors.Is(err, fs.ErrNotExist) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("unable to query synchronization root: %w", err)
+	}
+
+	// Scan based on the root type.
+	if info.IsDirectory() {
+		return s.directory("")
+	} else if info.IsRegular() {
+		return s.file("", info)
+	} else if info.IsSymbolicLink() {
+		return &core.Entry{
+			Kind:    core.EntryKind_Problematic,
+			Problem: "synchronization root is a symbolic link",
+		}, nil
+	}
+	return &core.Entry{Kind: core.EntryKind_Untracked}, nil
+}
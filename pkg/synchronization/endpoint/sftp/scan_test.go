@@ -0,0 +1,58 @@
+package sftp
+
+import (
+	"testing"
+	"time"
+
+	"github.com/mutagen-io/mutagen/pkg/sftp"
+)
+
+// TestCacheEntryRacy tests that cache entries for files modified close to the
+// time at which they were hashed are treated as racy.
+func TestCacheEntryRacy(t *testing.T) {
+	// Set up test cases.
+	now := time.Unix(1700000000, 0)
+	testCases := []struct {
+		modificationTime time.Time
+		expected         bool
+	}{
+		{now.Add(-time.Hour), false},
+		{now.Add(-racyModificationWindow), false},
+		{now.Add(-time.Second), true},
+		{now, true},
+		{now.Add(time.Second), true},
+	}
+
+	// Process test cases.
+	for i, testCase := range testCases {
+		entry := &cacheEntry{modificationTime: testCase.modificationTime, cached: now}
+		if racy := entry.racy(); racy != testCase.expected {
+			t.Errorf("test index %d: racy mismatch: %t != %t", i, racy, testCase.expected)
+		}
+	}
+}
+
+// TestCacheEntryMatches tests cache entry metadata matching.
+func TestCacheEntryMatches(t *testing.T) {
+	// Create a cache entry.
+	modificationTime := time.Unix(1700000000, 0)
+	entry := &cacheEntry{mode: 0100644, size: 10, modificationTime: modificationTime}
+
+	// Set up test cases.
+	testCases := []struct {
+		info     *sftp.FileInfo
+		expected bool
+	}{
+		{&sftp.FileInfo{Mode: 0100644, Size: 10, ModificationTime: modificationTime}, true},
+		{&sftp.FileInfo{Mode: 0100755, Size: 10, ModificationTime: modificationTime}, false},
+		{&sftp.FileInfo{Mode: 0100644, Size: 11, ModificationTime: modificationTime}, false},
+		{&sftp.FileInfo{Mode: 0100644, Size: 10, ModificationTime: modificationTime.Add(time.Second)}, false},
+	}
+
+	// Process test cases.
+	for i, testCase := range testCases {
+		if matches := entry.matches(testCase.info); matches != testCase.expected {
+			t.Errorf("test index %d: match mismatch: %t != %t", i, matches, testCase.expected)
+		}
+	}
+}
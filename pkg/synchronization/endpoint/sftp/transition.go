@@ -0,0 +1,563 @@
+package sftp
+
+import (
+	"bytes"
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"hash"
+	"io"
+	"io/fs"
+	"os"
+	pathpkg "path"
+
+	"github.com/mutagen-io/mutagen/pkg/filesystem"
+	"github.com/mutagen-io/mutagen/pkg/sftp"
+	"github.com/mutagen-io/mutagen/pkg/synchronization/core"
+)
+
+var (
+	// errTransitionCancelled is the error recorded for paths that aren't
+	// transitioned due to cancellation.
+	errTransitionCancelled = errors.New("transition cancelled")
+)
+
+// markExecutableForReaders sets the executable bit for the user, group, and
+// others, but only for those classes with read access.
+func markExecutableForReaders(mode filesystem.Mode) filesystem.Mode {
+	if (mode & filesystem.ModePermissionUserRead) != 0 {
+		mode |= filesystem.ModePermissionUserExecute
+	}
+	if (mode & filesystem.ModePermissionGroupRead) != 0 {
+		mode |= filesystem.ModePermissionGroupExecute
+	}
+	if (mode & filesystem.ModePermissionOthersRead) != 0 {
+		mode |= filesystem.ModePermissionOthersExecute
+	}
+	return mode
+}
+
+// transitioner performs transitions over SFTP. It mirrors the semantics of
+// core.Transition, but operates on a remote synchronization root.
+type transitioner struct {
+	// cancelled is the cancellation channel for the transition operation.
+	cancelled <-chan struct{}
+	// client is the SFTP client.
+	client *sftp.Client
+	// root is the absolute path to the synchronization root on the server.
+	root string
+	// cache is the cache from the last scan.
+	cache map[string]*cacheEntry
+	// hasher is the hasher used to verify the content of files whose cache
+	// entries are racy.
+	hasher hash.Hash
+	// symbolicLinkMode is the symbolic link mode.
+	symbolicLinkMode core.SymbolicLinkMode
+	// defaultFilePermissionMode is the default file permission mode.
+	defaultFilePermissionMode filesystem.Mode
+	// defaultDirectoryPermissionMode is the default directory permission mode.
+	defaultDirectoryPermissionMode filesystem.Mode
+	// copyBuffer is the buffer used to upload files.
+	copyBuffer []byte
+	// provider is the provider for staged files.
+	provider core.Provider
+	// problems are the problems encountered during the transition.
+	problems []*core.Problem
+	// providerMissingFiles indicates that the provider was missing files.
+	providerMissingFiles bool
+}
+
+// recordProblem records a problem for the specified path.
+func (t *transitioner) recordProblem(path string, err error) {
+	t.problems = append(t.problems, &core.Problem{Path: path, Error: err.Error()})
+}
+
+// fullPath computes the server path for a path relative to the
+// synchronization root.
+func (t *transitioner) fullPath(path string) string {
+	return pathpkg.Join(t.root, path)
+}
+
+// ensureExpectedFile ensures that the file at the specified path matches the
+// specified entry, using the cache from the last scan to avoid re-reading file
+// content.
+func (t *transitioner) ensureExpectedFile(path string, expected *core.Entry) error {
+	// Grab cache information for this path.
+	cached, ok := t.cache[path]
+	if !ok {
+		return errors.New("unable to find cache information for path")
+	}
+
+	// Grab metadata for this path and compare it with the cache.
+	info, err := t.client.Lstat(t.fullPath(path))
+	if err != nil {
+		return fmt.Errorf("unable to grab file statistics: %w", err)
+	} else if !cached.matches(info) || !bytes.Equal(cached.digest, expected.Digest) {
+		return errors.New("modification detected")
+	}
+
+	// If the cache entry is racy, then the metadata isn't sufficient to
+	// identify the file content, so verify the content directly.
+	if cached.racy() {
+		if digest, err := t.digest(path); err != nil {
+			return fmt.Errorf("unable to compute file digest: %w", err)
+		} else if !bytes.Equal(digest, expected.Digest) {
+			return errors.New("modification detected")
+		}
+	}
+
+	// Success.
+	return nil
+}
+
+// digest computes the digest of the file at the specified path.
+func (t *transitioner) digest(path string) ([]byte, error) {
+	// Open the file.
+	file, err := t.client.Open(t.fullPath(path))
+	if err != nil {
+		return nil, fmt.Errorf("unable to open file: %w", err)
+	}
+	defer file.Close()
+
+	// Hash the file content.
+	t.hasher.Reset()
+	if _, err := io.CopyBuffer(t.hasher, file, t.copyBuffer); err != nil {
+		return nil, fmt.Errorf("unable to read file: %w", err)
+	}
+
+	// Success.
+	return t.hasher.Sum(nil), nil
+}
+
+// ensureExpectedSymbolicLink ensures that the symbolic link at the specified
+// path matches the specified entry.
+func (t *transitioner) ensureExpectedSymbolicLink(path string, expected *core.Entry) error {
+	// Read the symbolic link target.
+	target, err := t.client.ReadLink(t.fullPath(path))
+	if err != nil {
+		return fmt.Errorf("unable to read symbolic link target: %w", err)
+	}
+
+	// Normalize the target if necessary.
+	if t.symbolicLinkMode == core.SymbolicLinkMode_SymbolicLinkModePortable {
+		if target, err = core.NormalizeSymbolicLinkAndEnsurePortable(path, target); err != nil {
+			return fmt.Errorf("unable to normalize target: %w", err)
+		}
+	}
+
+	// Compare the targets.
+	if target != expected.Target {
+		return errors.New("symbolic link target does not match expected")
+	}
+
+	// Success.
+	return nil
+}
+
+// removeDirectory removes the directory at the specified path, enforcing that
+// its contents match the specified entry. Any content that is successfully
+// removed is deleted from the entry's content map. It returns whether or not
+// the directory was fully removed.
+func (t *transitioner) removeDirectory(path string, expected *core.Entry) bool {
+	// List the directory contents.
+	contents, err := t.client.ReadDirectory(t.fullPath(path))
+	if err != nil {
+		t.recordProblem(path, fmt.Errorf("unable to read directory contents: %w", err))
+		return false
+	}
+
+	// Remove the contents.
+	var cancelled, unknownContentEncountered, contentRemovalFailed bool
+ContentLoop:
+	for _, c := range contents {
+		// Check for cancellation.
+		select {
+		case <-t.cancelled:
+			cancelled = true
+			t.recordProblem(path, errTransitionCancelled)
+			break ContentLoop
+		default:
+		}
+
+		// Compute the content path.
+		contentPath := c.Name
+		if path != "" {
+			contentPath = path + "/" + c.Name
+		}
+
+		// Grab the corresponding entry. If we don't know anything about this
+		// entry, then mark that as a problem and ignore for now.
+		entry, ok := expected.Contents[c.Name]
+		if !ok {
+			unknownContentEncountered = true
+			t.recordProblem(contentPath, errors.New("unknown content encountered on disk"))
+			continue
+		}
+
+		// Handle content removal based on type.
+		if entry.Kind == core.EntryKind_Directory {
+			if !t.removeDirectory(contentPath, entry) {
+				contentRemovalFailed = true
+				continue
+			}
+		} else if entry.Kind == core.EntryKind_File {
+			if err := t.removeFile(contentPath, entry); err != nil {
+				contentRemovalFailed = true
+				t.recordProblem(contentPath, fmt.Errorf("unable to remove file: %w", err))
+				continue
+			}
+		} else if entry.Kind == core.EntryKind_SymbolicLink {
+			if err := t.removeSymbolicLink(contentPath, entry); err != nil {
+				contentRemovalFailed = true
+				t.recordProblem(contentPath, fmt.Errorf("unable to remove symbolic link: %w", err))
+				continue
+			}
+		} else {
+			contentRemovalFailed = true
+			t.recordProblem(contentPath, errors.New("unknown entry type found in removal target"))
+			continue
+		}
+
+		// At this point the removal must have succeeded, so remove the entry
+		// from the target.
+		delete(expected.Contents, c.Name)
+	}
+
+	// If all on-disk content was removed, then any remaining entries weren't
+	// seen on disk and can be treated as removed.
+	if !cancelled && !contentRemovalFailed {
+		expected.Contents = nil
+	}
+
+	// Attempt to remove the directory itself if no problems were encountered.
+	if !cancelled && !unknownContentEncountered && !contentRemovalFailed {
+		if err := t.client.RemoveDirectory(t.fullPath(path)); err != nil {
+			t.recordProblem(path, fmt.Errorf("unable to remove directory: %w", err))
+		} else {
+			return true
+		}
+	}
+
+	// Any problems will already have been recorded.
+	return false
+}
+
+// removeFile removes the file at the specified path, enforcing that it matches
+// the specified entry.
+func (t *transitioner) removeFile(path string, expected *core.Entry) error {
+	if err := t.ensureExpectedFile(path, expected); err != nil {
+		return fmt.Errorf("unable to validate existing file: %w", err)
+	}
+	return t.client.Remove(t.fullPath(path))
+}
+
+// removeSymbolicLink removes the symbolic link at the specified path, enforcing
+// that it matches the specified entry.
+func (t *transitioner) removeSymbolicLink(path string, expected *core.Entry) error {
+	if err := t.ensureExpectedSymbolicLink(path, expected); err != nil {
+		return fmt.Errorf("unable to validate existing symbolic link: %w", err)
+	}
+	return t.client.Remove(t.fullPath(path))
+}
+
+// remove removes the content at the specified path, enforcing that it matches
+// the specified entry. If only a portion of the content can be removed, then
+// what remains will be represented by the return value.
+func (t *transitioner) remove(path string, entry *core.Entry) *core.Entry {
+	// If the entry is nil, we're done.
+	if entry == nil {
+		return nil
+	}
+
+	// Handle removal based on type.
+	if entry.Kind == core.EntryKind_Directory {
+		entryCopy := entry.Copy(true)
+		if !t.removeDirectory(path, entryCopy) {
+			return entryCopy
+		}
+	} else if entry.Kind == core.EntryKind_File {
+		if err := t.removeFile(path, entry); err != nil {
+			t.recordProblem(path, fmt.Errorf("unable to remove file: %w", err))
+			return entry
+		}
+	} else if entry.Kind == core.EntryKind_SymbolicLink {
+		if err := t.removeSymbolicLink(path, entry); err != nil {
+			t.recordProblem(path, fmt.Errorf("unable to remove symbolic link: %w", err))
+			return entry
+		}
+	} else {
+		t.recordProblem(path, errors.New("removal requested for unknown entry type"))
+		return entry
+	}
+
+	// Success.
+	return nil
+}
+
+// upload locates the staged file for the specified path and entry, uploads it
+// to a temporary file alongside the target, sets its permissions, and renames
+// it into place.
+func (t *transitioner) upload(path string, target *core.Entry) error {
+	// Locate the staged file.
+	stagedPath, err := t.provider.Provide(path, target.Digest)
+	if err != nil {
+		if os.IsNotExist(err) {
+			t.providerMissingFiles = true
+		}
+		return fmt.Errorf("unable to locate staged file: %w", err)
+	}
+	staged, err := os.Open(stagedPath)
+	if err != nil {
+		return fmt.Errorf("unable to open staged file: %w", err)
+	}
+	defer staged.Close()
+
+	// Compute the permissions for the file.
+	mode := t.defaultFilePermissionMode
+	if target.Executable {
+		mode = markExecutableForReaders(mode)
+	}
+
+	// Compute a temporary name in the target's parent directory.
+	var randomness [8]byte
+	if _, err := rand.Read(randomness[:]); err != nil {
+		return fmt.Errorf("unable to generate temporary name: %w", err)
+	}
+	temporary := t.fullPath(pathpkg.Join(
+		pathpkg.Dir(path),
+		filesystem.TemporaryNamePrefix+"sftp-"+hex.EncodeToString(randomness[:]),
+	))
+
+	// Upload the content.
+	remote, err := t.client.Create(temporary, uint32(mode))
+	if err != nil {
+		return fmt.Errorf("unable to create temporary file: %w", err)
+	}
+	if _, err := io.CopyBuffer(remote, staged, t.copyBuffer); err != nil {
+		remote.Close()
+		t.client.Remove(temporary)
+		return fmt.Errorf("unable to upload file: %w", err)
+	} else if err = remote.Close(); err != nil {
+		t.client.Remove(temporary)
+		return fmt.Errorf("unable to close temporary file: %w", err)
+	}
+
+	// Set permissions explicitly, since the requested permissions are only
+	// applied at creation time (and are subject to the server's umask).
+	if err := t.client.Chmod(temporary, uint32(mode)); err != nil {
+		t.client.Remove(temporary)
+		return fmt.Errorf("unable to set file permissions: %w", err)
+	}
+
+	// Move the file into place.
+	if err := t.client.Rename(temporary, t.fullPath(path)); err != nil {
+		t.client.Remove(temporary)
+		return fmt.Errorf("unable to relocate file: %w", err)
+	}
+
+	// Success.
+	return nil
+}
+
+// swapFile swaps files at the specified path, enforcing that the existing file
+// matches what's expected.
+func (t *transitioner) swapFile(path string, oldEntry, newEntry *core.Entry) error {
+	// Ensure that the existing entry hasn't been modified from what we're
+	// expecting.
+	if err := t.ensureExpectedFile(path, oldEntry); err != nil {
+		return fmt.Errorf("unable to validate existing file: %w", err)
+	}
+
+	// If both files have the same contents (differing only in executability),
+	// then we won't have staged the file, so we just change the permissions on
+	// the existing file.
+	if bytes.Equal(oldEntry.Digest, newEntry.Digest) {
+		mode := t.defaultFilePermissionMode
+		if newEntry.Executable {
+			mode = markExecutableForReaders(mode)
+		}
+		if err := t.client.Chmod(t.fullPath(path), uint32(mode)); err != nil {
+			return fmt.Errorf("unable to change file permissions: %w", err)
+		}
+		return nil
+	}
+
+	// Otherwise upload the staged file in place of the existing file.
+	return t.upload(path, newEntry)
+}
+
+// createSymbolicLink creates the target symbolic link at the specified path.
+func (t *transitioner) createSymbolicLink(path string, target *core.Entry) error {
+	// Verify that the symbolic link agrees with our symbolic link mode.
+	if t.symbolicLinkMode == core.SymbolicLinkMode_SymbolicLinkModeIgnore {
+		return errors.New("symbolic link creation requested with symbolic links ignored")
+	} else if t.symbolicLinkMode == core.SymbolicLinkMode_SymbolicLinkModePortable {
+		if normalized, err := core.NormalizeSymbolicLinkAndEnsurePortable(path, target.Target); err != nil || normalized != target.Target {
+			return errors.New("symbolic link was not in normalized form or was not portable")
+		}
+	}
+
+	// Create the symbolic link.
+	return t.client.Symlink(target.Target, t.fullPath(path))
+}
+
+// createDirectory creates the target directory at the specified path. If only
+// a portion of the content can be created, an entry representing that portion
+// will be returned.
+func (t *transitioner) createDirectory(path string, target *core.Entry) *core.Entry {
+	// Attempt to create the directory.
+	if err := t.client.Mkdir(t.fullPath(path), uint32(t.defaultDirectoryPermissionMode)); err != nil {
+		t.recordProblem(path, fmt.Errorf("unable to create directory: %w", err))
+		return nil
+	}
+
+	// Create a shallow copy of the target that we'll populate as we create its
+	// contents.
+	created := target.Copy(false)
+
+	// Set directory permissions, since the requested permissions are subject
+	// to the server's umask.
+	if err := t.client.Chmod(t.fullPath(path), uint32(t.defaultDirectoryPermissionMode)); err != nil {
+		t.recordProblem(path, fmt.Errorf("unable to set directory permissions: %w", err))
+		return created
+	}
+
+	// Attempt to create the target contents.
+	if len(target.Contents) > 0 {
+		created.Contents = make(map[string]*core.Entry, len(target.Contents))
+	}
+ContentLoop:
+	for name, entry := range target.Contents {
+		// Check for cancellation.
+		select {
+		case <-t.cancelled:
+			t.recordProblem(path, errTransitionCancelled)
+			break ContentLoop
+		default:
+		}
+
+		// Compute the content path.
+		contentPath := name
+		if path != "" {
+			contentPath = path + "/" + name
+		}
+
+		// Handle content creation based on type.
+		if entry.Kind == core.EntryKind_Directory {
+			if c := t.createDirectory(contentPath, entry); c != nil {
+				created.Contents[name] = c
+			}
+		} else if entry.Kind == core.EntryKind_File {
+			if err := t.upload(contentPath, entry); err != nil {
+				t.recordProblem(contentPath, fmt.Errorf("unable to create file: %w", err))
+			} else {
+				created.Contents[name] = entry
+			}
+		} else if entry.Kind == core.EntryKind_SymbolicLink {
+			if err := t.createSymbolicLink(contentPath, entry); err != nil {
+				t.recordProblem(contentPath, fmt.Errorf("unable to create symbolic link: %w", err))
+			} else {
+				created.Contents[name] = entry
+			}
+		} else {
+			t.recordProblem(contentPath, errors.New("creation requested for unknown entry type"))
+		}
+	}
+
+	// Return the portion of the target that was created.
+	return created
+}
+
+// create creates the target content at the specified path. If only a portion of
+// the content can be created, an entry representing that portion will be
+// returned.
+func (t *transitioner) create(path string, target *core.Entry) *core.Entry {
+	// If the target is nil, we're done.
+	if target == nil {
+		return nil
+	}
+
+	// Ensure that the parent of the target exists. We only need to check this
+	// for the synchronization root, since the reconciliation algorithm ensures
+	// that parents exist for all other paths.
+	if path == "" {
+		if _, err := t.client.Lstat(pathpkg.Dir(t.root)); err != nil {
+			if errors.Is(err, fs.ErrNotExist) {
+				err = errors.New("parent directory does not exist")
+			}
+			t.recordProblem(path, fmt.Errorf("unable to access synchronization root parent: %w", err))
+			return nil
+		}
+	}
+
+	// Handle creation based on type.
+	if target.Kind == core.EntryKind_Directory {
+		return t.createDirectory(path, target)
+	} else if target.Kind == core.EntryKind_File {
+		if err := t.upload(path, target); err != nil {
+			t.recordProblem(path, fmt.Errorf("unable to create file: %w", err))
+			return nil
+		}
+		return target
+	} else if target.Kind == core.EntryKind_SymbolicLink {
+		if err := t.createSymbolicLink(path, target); err != nil {
+			t.recordProblem(path, fmt.Errorf("unable to create symbolic link: %w", err))
+			return nil
+		}
+		return target
+	} else {
+		t.recordProblem(path, errors.New("creation requested for unknown entry type"))
+		return nil
+	}
+}
+
+// transition performs the specified transitions. It returns the resulting
+// entries, any problems encountered, and whether or not the provider was
+// missing files.
+func (t *transitioner) transition(ctx context.Context, transitions []*core.Change) ([]*core.Entry, []*core.Problem, bool) {
+	// Extract the cancellation channel.
+	t.cancelled = ctx.Done()
+
+	// Iterate through transitions.
+	var results []*core.Entry
+	for _, c := range transitions {
+		// Check for cancellation. Even if cancelled, we still need to yield a
+		// result.
+		select {
+		case <-t.cancelled:
+			results = append(results, c.Old)
+			t.recordProblem(c.Path, errTransitionCancelled)
+			continue
+		default:
+		}
+
+		// Handle file-to-file transitions with a swap.
+		fileToFile := c.Old != nil && c.New != nil &&
+			c.Old.Kind == core.EntryKind_File &&
+			c.New.Kind == core.EntryKind_File
+		if fileToFile {
+			if err := t.swapFile(c.Path, c.Old, c.New); err != nil {
+				results = append(results, c.Old)
+				t.recordProblem(c.Path, fmt.Errorf("unable to swap file: %w", err))
+			} else {
+				results = append(results, c.New)
+			}
+			continue
+		}
+
+		// Reduce the existing content to nil. If this fails, then record the
+		// reduced entry and continue to the next transition.
+		if r := t.remove(c.Path, c.Old); r != nil {
+			results = append(results, r)
+			continue
+		}
+
+		// Create the new content.
+		results = append(results, t.create(c.Path, c.New))
+	}
+
+	// Done.
+	return results, t.problems, t.providerMissingFiles
+}
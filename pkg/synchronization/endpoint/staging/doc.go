@@ -0,0 +1,4 @@
+// Package staging provides local file staging facilities for agentless
+// synchronization endpoints, which receive files from the opposite endpoint
+// before uploading them to a remote store.
+package staging
@@ -0,0 +1,29 @@
+package staging
+
+import (
+	"fmt"
+	"path/filepath"
+
+	"github.com/mutagen-io/mutagen/pkg/filesystem"
+)
+
+// PathForMutagenStagingRoot computes the path to the staging root in the
+// Mutagen data directory for the given session identifier and endpoint. It
+// ensures that the staging subdirectory of the Mutagen data directory exists,
+// but it does not create the staging root itself.
+func PathForMutagenStagingRoot(session string, alpha bool) (string, error) {
+	// Compute the path to the staging root parent and ensure that it exists.
+	stagingDataPath, err := filesystem.Mutagen(true, filesystem.MutagenSynchronizationStagingDirectoryName)
+	if err != nil {
+		return "", fmt.Errorf("unable to create staging data directory: %w", err)
+	}
+
+	// Compute the endpoint name.
+	endpointName := "alpha"
+	if !alpha {
+		endpointName = "beta"
+	}
+
+	// Compute the combined path.
+	return filepath.Join(stagingDataPath, fmt.Sprintf("%s-%s", session, endpointName)), nil
+}
@@ -0,0 +1,168 @@
+package staging
+
+import (
+	"crypto/sha1"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"hash"
+	"io"
+	"os"
+	"path/filepath"
+)
+
+// pathForStaging computes the name of the staged file for the specified path
+// and digest within the staging root. Staged files are identified by both
+// their path and digest, meaning that files whose content doesn't match the
+// expected digest will simply be treated as missing.
+func pathForStaging(root, path string, digest []byte) string {
+	pathDigest := sha1.Sum([]byte(path))
+	return filepath.Join(root, hex.EncodeToString(pathDigest[:])+"_"+hex.EncodeToString(digest))
+}
+
+// stagingSink is an io.WriteCloser designed to be returned by Stager.
+type stagingSink struct {
+	// stager is the parent stager.
+	stager *Stager
+	// path is the path that is being staged.
+	path string
+	// storage is the temporary storage for the data.
+	storage *os.File
+	// digester is the hash of the data already written.
+	digester hash.Hash
+	// currentSize is the number of bytes that have been written to the file.
+	currentSize uint64
+}
+
+// Write writes data to the sink.
+func (s *stagingSink) Write(data []byte) (int, error) {
+	// Watch for size violations.
+	if (s.stager.maximumFileSize - s.currentSize) < uint64(len(data)) {
+		return 0, errors.New("maximum file size reached")
+	} else if (s.stager.maximumTotalSize - s.stager.totalSize) < uint64(len(data)) {
+		return 0, errors.New("maximum total staging size reached")
+	}
+
+	// Write to the underlying storage and the digester.
+	n, err := s.storage.Write(data)
+	s.digester.Write(data[:n])
+
+	// Update sizes.
+	s.currentSize += uint64(n)
+	s.stager.totalSize += uint64(n)
+
+	// Done.
+	return n, err
+}
+
+// Close closes the sink and moves the file into place.
+func (s *stagingSink) Close() error {
+	// Close the underlying storage.
+	if err := s.storage.Close(); err != nil {
+		os.Remove(s.storage.Name())
+		return fmt.Errorf("unable to close underlying storage: %w", err)
+	}
+
+	// Relocate the file to its final location.
+	destination := pathForStaging(s.stager.root, s.path, s.digester.Sum(nil))
+	if err := os.Rename(s.storage.Name(), destination); err != nil {
+		os.Remove(s.storage.Name())
+		return fmt.Errorf("unable to relocate file: %w", err)
+	}
+
+	// Success.
+	return nil
+}
+
+// Stager is an ephemeral local store for files received from the opposite
+// endpoint before they're uploaded to a remote store. It implements both
+// rsync.Sinker and core.Provider. It is not safe for concurrent access, and
+// each sink that it produces should be closed before any other method is
+// invoked.
+type Stager struct {
+	// root is the staging root path.
+	root string
+	// hasherFactory creates the hash function to use when processing files.
+	hasherFactory func() hash.Hash
+	// maximumFileSize is the maximum allowed size for a single staged file.
+	maximumFileSize uint64
+	// maximumTotalSize is the maximum allowed total size of files staged
+	// between calls to ResetTotalSize.
+	maximumTotalSize uint64
+	// totalSize is the total number of bytes written to staging sinks since the
+	// last call to ResetTotalSize.
+	totalSize uint64
+}
+
+// NewStager creates a new stager. The staging root is created on demand.
+func NewStager(root string, hasherFactory func() hash.Hash, maximumFileSize, maximumTotalSize uint64) *Stager {
+	return &Stager{
+		root:             root,
+		hasherFactory:    hasherFactory,
+		maximumFileSize:  maximumFileSize,
+		maximumTotalSize: maximumTotalSize,
+	}
+}
+
+// ResetTotalSize resets the tracking of the total size of staged files. It
+// should be invoked at the start of each staging operation.
+func (s *Stager) ResetTotalSize() {
+	s.totalSize = 0
+}
+
+// Root returns the staging root.
+func (s *Stager) Root() string {
+	return s.root
+}
+
+// Staged returns whether or not a file with the specified path and digest has
+// already been staged.
+func (s *Stager) Staged(path string, digest []byte) bool {
+	_, err := os.Lstat(pathForStaging(s.root, path, digest))
+	return err == nil
+}
+
+// Wipe removes the staging root.
+func (s *Stager) Wipe() error {
+	if err := os.RemoveAll(s.root); err != nil {
+		return fmt.Errorf("unable to remove staging directory: %w", err)
+	}
+	return nil
+}
+
+// Sink implements the Sink method of rsync.Sinker.
+func (s *Stager) Sink(path string) (io.WriteCloser, error) {
+	// Ensure that the staging root exists.
+	if err := os.MkdirAll(s.root, 0700); err != nil {
+		return nil, fmt.Errorf("unable to create staging root: %w", err)
+	}
+
+	// Create temporary storage.
+	storage, err := os.CreateTemp(s.root, "staging")
+	if err != nil {
+		return nil, fmt.Errorf("unable to create temporary storage: %w", err)
+	}
+
+	// Success.
+	return &stagingSink{
+		stager:   s,
+		path:     path,
+		storage:  storage,
+		digester: s.hasherFactory(),
+	}, nil
+}
+
+// Provide implements the Provide method of core.Provider.
+func (s *Stager) Provide(path string, digest []byte) (string, error) {
+	// Compute the expected location of the file.
+	expectedLocation := pathForStaging(s.root, path, digest)
+
+	// Ensure that the file exists. Any error here (including non-existence) is
+	// returned directly.
+	if _, err := os.Lstat(expectedLocation); err != nil {
+		return "", err
+	}
+
+	// Success.
+	return expectedLocation, nil
+}
@@ -0,0 +1,70 @@
+package staging
+
+import (
+	"crypto/sha1"
+	"path/filepath"
+	"testing"
+)
+
+// TestStagerStageAndProvide tests that files staged by the stager can be
+// located by path and digest and that wiping removes them.
+func TestStagerStageAndProvide(t *testing.T) {
+	// Create a stager.
+	s := NewStager(filepath.Join(t.TempDir(), "staging"), sha1.New, 1024, 1024)
+
+	// Stage a file.
+	sink, err := s.Sink("file")
+	if err != nil {
+		t.Fatal("unable to create sink:", err)
+	}
+	if _, err := sink.Write([]byte("content")); err != nil {
+		t.Fatal("unable to write to sink:", err)
+	}
+	if err := sink.Close(); err != nil {
+		t.Fatal("unable to close sink:", err)
+	}
+
+	// Verify that the file can be located using its digest.
+	digest := sha1.Sum([]byte("content"))
+	if !s.Staged("file", digest[:]) {
+		t.Error("staged file not found")
+	} else if _, err := s.Provide("file", digest[:]); err != nil {
+		t.Error("unable to provide staged file:", err)
+	}
+
+	// Verify that the file can't be located with a different path or digest.
+	if s.Staged("other", digest[:]) {
+		t.Error("staged file found under incorrect path")
+	}
+	otherDigest := sha1.Sum([]byte("other"))
+	if _, err := s.Provide("file", otherDigest[:]); err == nil {
+		t.Error("staged file provided with incorrect digest")
+	}
+
+	// Wipe the stager and verify that the file is gone.
+	if err := s.Wipe(); err != nil {
+		t.Fatal("unable to wipe stager:", err)
+	}
+	if s.Staged("file", digest[:]) {
+		t.Error("staged file found after wipe")
+	}
+}
+
+// TestStagerMaximumFileSize tests that the stager enforces its maximum file
+// size.
+func TestStagerMaximumFileSize(t *testing.T) {
+	// Create a stager with a small per-file limit.
+	s := NewStager(filepath.Join(t.TempDir(), "staging"), sha1.New, 4, 1024)
+
+	// Attempt to stage a file that exceeds the limit.
+	sink, err := s.Sink("file")
+	if err != nil {
+		t.Fatal("unable to create sink:", err)
+	}
+	if _, err := sink.Write([]byte("12345")); err == nil {
+		t.Error("write exceeding file size limit succeeded unexpectedly")
+	}
+	if err := sink.Close(); err != nil {
+		t.Fatal("unable to close sink:", err)
+	}
+}
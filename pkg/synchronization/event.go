@@ -0,0 +1,39 @@
+package synchronization
+
+import (
+	"errors"
+	"fmt"
+)
+
+// EnsureValid ensures that ChangeEvent's invariants are respected.
+func (e *ChangeEvent) EnsureValid() error {
+	// A nil change event is not valid.
+	if e == nil {
+		return errors.New("nil change event")
+	}
+
+	// Ensure that a session identifier is present.
+	if e.Session == "" {
+		return errors.New("empty session identifier")
+	}
+
+	// Ensure that the time is valid.
+	if err := e.Time.CheckValid(); err != nil {
+		return fmt.Errorf("invalid event time: %w", err)
+	}
+
+	// Ensure that all path changes are valid.
+	for _, c := range e.AlphaChanges {
+		if err := c.EnsureValid(); err != nil {
+			return fmt.Errorf("invalid alpha path change: %w", err)
+		}
+	}
+	for _, c := range e.BetaChanges {
+		if err := c.EnsureValid(); err != nil {
+			return fmt.Errorf("invalid beta path change: %w", err)
+		}
+	}
+
+	// Success.
+	return nil
+}
@@ -0,0 +1,48 @@
+package synchronization
+
+import (
+	"testing"
+
+	"google.golang.org/protobuf/types/known/timestamppb"
+
+	"github.com/mutagen-io/mutagen/pkg/synchronization/core"
+)
+
+// TestChangeEventEnsureValid tests ChangeEvent.EnsureValid.
+func TestChangeEventEnsureValid(t *testing.T) {
+	// Set up test cases.
+	testCases := []struct {
+		event    *ChangeEvent
+		expected bool
+	}{
+		{nil, false},
+		{&ChangeEvent{Time: timestamppb.Now()}, false},
+		{&ChangeEvent{Session: "session"}, false},
+		{&ChangeEvent{Session: "session", Time: timestamppb.Now()}, true},
+		{&ChangeEvent{
+			Session:      "session",
+			Time:         timestamppb.Now(),
+			AlphaChanges: []*core.PathChange{{Path: "file", Kind: core.PathChangeKind_PathChangeKindCreated}},
+			BetaChanges:  []*core.PathChange{{Path: "file", Kind: core.PathChangeKind_PathChangeKindDeleted}},
+		}, true},
+		{&ChangeEvent{
+			Session:      "session",
+			Time:         timestamppb.Now(),
+			AlphaChanges: []*core.PathChange{{Path: "file"}},
+		}, false},
+		{&ChangeEvent{
+			Session:     "session",
+			Time:        timestamppb.Now(),
+			BetaChanges: []*core.PathChange{nil},
+		}, false},
+	}
+
+	// Process test cases.
+	for i, testCase := range testCases {
+		if err := testCase.event.EnsureValid(); err == nil && !testCase.expected {
+			t.Errorf("test index %d: invalid event classified as valid", i)
+		} else if err != nil && testCase.expected {
+			t.Errorf("test index %d: valid event classified as invalid: %v", i, err)
+		}
+	}
+}
@@ -28,8 +28,44 @@ const (
 	// problems that will be reported by Manager.List for a single endpoint in a
 	// session before transition problem list truncation for that endpoint.
 	maximumListTransitionProblems = 10
+	// maximumListMirrorViolations is the maximum number of mirror violations
+	// that will be reported by Manager.List for a single session before mirror
+	// violation list truncation for that session.
+	maximumListMirrorViolations = 10
+	// maximumListPageSize is the maximum number of entries that will be
+	// reported by Manager.List for any single conflict, problem, or mirror
+	// violation list when an explicit list limit is specified.
+	maximumListPageSize = 1000
 )
 
+// listWindow computes the window of a sorted list (of the specified length)
+// that should be reported by Manager.List for the specified offset and limit.
+// If limit is 0, then defaultLimit is used. It returns the start and end
+// indices of the window, as well as the number of entries excluded from the
+// window (both before and after it).
+func listWindow(length int, offset, limit, defaultLimit uint64) (int, int, uint64) {
+	// Compute the effective limit.
+	if limit == 0 {
+		limit = defaultLimit
+	} else if limit > maximumListPageSize {
+		limit = maximumListPageSize
+	}
+
+	// Compute the window.
+	total := uint64(length)
+	start := offset
+	if start > total {
+		start = total
+	}
+	end := start + limit
+	if end > total {
+		end = total
+	}
+
+	// Done.
+	return int(start), int(end), total - (end - start)
+}
+
 // Manager provides synchronization session management facilities. Its methods
 // are safe for concurrent usage, so it can be easily exported via an RPC
 // interface.
@@ -202,6 +238,15 @@ func (m *Manager) Shutdown() {
 	}
 }
 
+// HandleConnectivityChange notifies all sessions of a host network
+// configuration change or system wake, causing sessions with non-local
+// endpoints to reconnect immediately.
+func (m *Manager) HandleConnectivityChange() {
+	for _, controller := range m.allControllers() {
+		controller.handleConnectivityChange()
+	}
+}
+
 // Create tells the manager to create a new session.
 func (m *Manager) Create(
 	ctx context.Context,
@@ -246,9 +291,10 @@ func (m *Manager) Create(
 
 // List requests a state snapshot for the specified sessions. Session states
 // will be ordered by creation time, from oldest to newest. Problem and conflict
-// lists will sorted by path and truncated to reasonable lengths, and conflicts
-// will be converted to their slim variants.
-func (m *Manager) List(_ context.Context, selection *selection.Selection, previousStateIndex uint64) (uint64, []*State, error) {
+// lists will sorted by path and windowed using the specified offset and limit
+// (with a limit of 0 indicating that default truncation lengths should be
+// used), and conflicts will be converted to their slim variants.
+func (m *Manager) List(_ context.Context, selection *selection.Selection, previousStateIndex, listOffset, listLimit uint64) (uint64, []*State, error) {
 	// Wait for a state change from the previous index.
 	// TODO: Figure out if we can use the provided context to preempt this wait.
 	// Unfortunately this will be tricky to implement since state tracking is
@@ -267,7 +313,7 @@ func (m *Manager) List(_ context.Context, selection *selection.Selection, previo
 
 	// Create a static snapshot of the state from each controller, then perform
 	// additional deep copying of problem and conflict lists, sort these lists
-	// based on path, truncate them if they're too long, and convert conflicts
+	// based on path, window them based on the offset and limit, and convert conflicts
 	// to their slim representation.
 	//
 	// HACK: We're relying a lot on understanding the internals of currentState
@@ -292,45 +338,40 @@ func (m *Manager) List(_ context.Context, selection *selection.Selection, previo
 		// slim representations.
 		state.Conflicts = core.CopyConflicts(state.Conflicts)
 		core.SortConflicts(state.Conflicts)
-		if len(state.Conflicts) > maximumListConflicts {
-			state.ExcludedConflicts = uint64(len(state.Conflicts) - maximumListConflicts)
-			state.Conflicts = state.Conflicts[:maximumListConflicts]
-		}
+		start, end, excluded := listWindow(len(state.Conflicts), listOffset, listLimit, maximumListConflicts)
+		state.Conflicts, state.ExcludedConflicts = state.Conflicts[start:end], excluded
 		for c, conflict := range state.Conflicts {
 			state.Conflicts[c] = conflict.Slim()
 		}
 
+		// Truncate mirror violations, if necessary. These are already stored
+		// in sorted order, and truncation doesn't modify the underlying list.
+		start, end, excluded = listWindow(len(state.MirrorViolations), listOffset, listLimit, maximumListMirrorViolations)
+		state.MirrorViolations, state.ExcludedMirrorViolations = state.MirrorViolations[start:end], excluded
+
 		// Sort and (potentially) truncate alpha scan problems.
 		state.AlphaState.ScanProblems = core.CopyProblems(state.AlphaState.ScanProblems)
 		core.SortProblems(state.AlphaState.ScanProblems)
-		if len(state.AlphaState.ScanProblems) > maximumListScanProblems {
-			state.AlphaState.ExcludedScanProblems = uint64(len(state.AlphaState.ScanProblems) - maximumListScanProblems)
-			state.AlphaState.ScanProblems = state.AlphaState.ScanProblems[:maximumListScanProblems]
-		}
+		start, end, excluded = listWindow(len(state.AlphaState.ScanProblems), listOffset, listLimit, maximumListScanProblems)
+		state.AlphaState.ScanProblems, state.AlphaState.ExcludedScanProblems = state.AlphaState.ScanProblems[start:end], excluded
 
 		// Sort and (potentially) truncate alpha transition problems.
 		state.AlphaState.TransitionProblems = core.CopyProblems(state.AlphaState.TransitionProblems)
 		core.SortProblems(state.AlphaState.TransitionProblems)
-		if len(state.AlphaState.TransitionProblems) > maximumListTransitionProblems {
-			state.AlphaState.ExcludedTransitionProblems = uint64(len(state.AlphaState.TransitionProblems) - maximumListTransitionProblems)
-			state.AlphaState.TransitionProblems = state.AlphaState.TransitionProblems[:maximumListTransitionProblems]
-		}
+		start, end, excluded = listWindow(len(state.AlphaState.TransitionProblems), listOffset, listLimit, maximumListTransitionProblems)
+		state.AlphaState.TransitionProblems, state.AlphaState.ExcludedTransitionProblems = state.AlphaState.TransitionProblems[start:end], excluded
 
-		// Sort and (potentially) truncate alpha scan problems.
+		// Sort and (potentially) truncate beta scan problems.
 		state.BetaState.ScanProblems = core.CopyProblems(state.BetaState.ScanProblems)
 		core.SortProblems(state.BetaState.ScanProblems)
-		if len(state.BetaState.ScanProblems) > maximumListScanProblems {
-			state.BetaState.ExcludedScanProblems = uint64(len(state.BetaState.ScanProblems) - maximumListScanProblems)
-			state.BetaState.ScanProblems = state.BetaState.ScanProblems[:maximumListScanProblems]
-		}
+		start, end, excluded = listWindow(len(state.BetaState.ScanProblems), listOffset, listLimit, maximumListScanProblems)
+		state.BetaState.ScanProblems, state.BetaState.ExcludedScanProblems = state.BetaState.ScanProblems[start:end], excluded
 
-		// Sort and (potentially) truncate alpha transition problems.
+		// Sort and (potentially) truncate beta transition problems.
 		state.BetaState.TransitionProblems = core.CopyProblems(state.BetaState.TransitionProblems)
 		core.SortProblems(state.BetaState.TransitionProblems)
-		if len(state.BetaState.TransitionProblems) > maximumListTransitionProblems {
-			state.BetaState.ExcludedTransitionProblems = uint64(len(state.BetaState.TransitionProblems) - maximumListTransitionProblems)
-			state.BetaState.TransitionProblems = state.BetaState.TransitionProblems[:maximumListTransitionProblems]
-		}
+		start, end, excluded = listWindow(len(state.BetaState.TransitionProblems), listOffset, listLimit, maximumListTransitionProblems)
+		state.BetaState.TransitionProblems, state.BetaState.ExcludedTransitionProblems = state.BetaState.TransitionProblems[start:end], excluded
 
 		// Store the state snapshot.
 		states[i] = state
@@ -367,6 +408,133 @@ func (m *Manager) Flush(ctx context.Context, selection *selection.Selection, pro
 	return nil
 }
 
+// Verify tells the manager to verify sessions matching the given
+// specifications, returning the verification result for each session.
+func (m *Manager) Verify(ctx context.Context, selection *selection.Selection, prompter string) ([]*VerificationResult, error) {
+	// Extract the controllers for the sessions of interest.
+	controllers, err := m.selectControllers(selection)
+	if err != nil {
+		return nil, fmt.Errorf("unable to locate requested sessions: %w", err)
+	}
+
+	// Attempt to verify the sessions.
+	results := make([]*VerificationResult, 0, len(controllers))
+	for _, controller := range controllers {
+		result, err := controller.verify(ctx, prompter)
+		if err != nil {
+			return nil, fmt.Errorf("unable to verify session: %w", err)
+		}
+		results = append(results, result)
+	}
+
+	// Success.
+	return results, nil
+}
+
+// Repair tells the manager to restore corrupted content at the specified paths
+// for sessions matching the given specifications.
+func (m *Manager) Repair(ctx context.Context, selection *selection.Selection, paths []string, prompter string) ([]*RepairResult, error) {
+	// Extract the controllers for the sessions of interest.
+	controllers, err := m.selectControllers(selection)
+	if err != nil {
+		return nil, fmt.Errorf("unable to locate requested sessions: %w", err)
+	}
+
+	// Attempt to repair the sessions.
+	results := make([]*RepairResult, 0, len(controllers))
+	for _, controller := range controllers {
+		result, err := controller.repair(ctx, paths, prompter)
+		if err != nil {
+			return nil, fmt.Errorf("unable to repair session: %w", err)
+		}
+		results = append(results, result)
+	}
+
+	// Success.
+	return results, nil
+}
+
+// Versions lists the available versions of a path on one endpoint of the
+// selected sessions and optionally restores one of them.
+func (m *Manager) Versions(ctx context.Context, selection *selection.Selection, path, restore string, alpha bool, prompter string) ([]*VersionsResult, error) {
+	// Extract the controllers for the sessions of interest.
+	controllers, err := m.selectControllers(selection)
+	if err != nil {
+		return nil, fmt.Errorf("unable to locate requested sessions: %w", err)
+	}
+
+	// Attempt to perform the versions operation for the sessions.
+	results := make([]*VersionsResult, 0, len(controllers))
+	for _, controller := range controllers {
+		result, err := controller.versions(ctx, path, restore, alpha, prompter)
+		if err != nil {
+			return nil, fmt.Errorf("unable to perform versions operation: %w", err)
+		}
+		results = append(results, result)
+	}
+
+	// Success.
+	return results, nil
+}
+
+// PathStatus reports the synchronization status of a path for sessions.
+func (m *Manager) PathStatus(selection *selection.Selection, path string) ([]*PathStatusResult, error) {
+	// Extract the controllers for the sessions of interest.
+	controllers, err := m.selectControllers(selection)
+	if err != nil {
+		return nil, fmt.Errorf("unable to locate requested sessions: %w", err)
+	}
+
+	// Compute the path status for each session.
+	results := make([]*PathStatusResult, 0, len(controllers))
+	for _, controller := range controllers {
+		results = append(results, controller.pathStatus(path))
+	}
+
+	// Success.
+	return results, nil
+}
+
+// Events delivers change events for sessions matching the given specifications
+// to the provided channel until the context is cancelled. Delivery is
+// non-blocking, so if the channel is full when an event is published, then
+// the subscription is terminated with an error rather than silently dropping
+// the event. Sessions created after the call are not included.
+func (m *Manager) Events(ctx context.Context, selection *selection.Selection, events chan<- *ChangeEvent) error {
+	// Extract the controllers for the sessions of interest.
+	controllers, err := m.selectControllers(selection)
+	if err != nil {
+		return fmt.Errorf("unable to locate requested sessions: %w", err)
+	}
+
+	// Subscribe to events from each session and defer unsubscription. We
+	// monitor each subscription in a separate Goroutine and funnel any lag
+	// notifications into a single channel.
+	lagged := make(chan struct{}, 1)
+	for _, controller := range controllers {
+		controllerLagged := controller.subscribe(events)
+		defer controller.unsubscribe(events)
+		go func() {
+			select {
+			case <-controllerLagged:
+				select {
+				case lagged <- struct{}{}:
+				default:
+				}
+			case <-ctx.Done():
+			}
+		}()
+	}
+
+	// Wait for cancellation or for the subscriber to fall behind.
+	select {
+	case <-ctx.Done():
+		return nil
+	case <-lagged:
+		return errors.New("event subscriber fell behind and events were dropped")
+	}
+}
+
 // Pause tells the manager to pause sessions matching the given specifications.
 func (m *Manager) Pause(ctx context.Context, selection *selection.Selection, prompter string) error {
 	// Extract the controllers for the sessions of interest.
@@ -406,6 +574,26 @@ func (m *Manager) Resume(ctx context.Context, selection *selection.Selection, pr
 	return nil
 }
 
+// AcceptRootChange tells the manager to approve pending root changes for
+// sessions matching the given specifications and resume them.
+func (m *Manager) AcceptRootChange(ctx context.Context, selection *selection.Selection, prompter string) error {
+	// Extract the controllers for the sessions of interest.
+	controllers, err := m.selectControllers(selection)
+	if err != nil {
+		return fmt.Errorf("unable to locate requested sessions: %w", err)
+	}
+
+	// Attempt to accept root changes.
+	for _, controller := range controllers {
+		if err := controller.acceptRootChange(ctx, prompter); err != nil {
+			return fmt.Errorf("unable to accept root change for session: %w", err)
+		}
+	}
+
+	// Success.
+	return nil
+}
+
 // Reset tells the manager to reset session histories for sessions matching the
 // given specifications.
 func (m *Manager) Reset(ctx context.Context, selection *selection.Selection, prompter string) error {
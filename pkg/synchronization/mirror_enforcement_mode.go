@@ -0,0 +1,73 @@
+package synchronization
+
+import (
+	"fmt"
+)
+
+// IsDefault indicates whether or not the mirror enforcement mode is
+// MirrorEnforcementMode_MirrorEnforcementModeDefault.
+func (m MirrorEnforcementMode) IsDefault() bool {
+	return m == MirrorEnforcementMode_MirrorEnforcementModeDefault
+}
+
+// MarshalText implements encoding.TextMarshaler.MarshalText.
+func (m MirrorEnforcementMode) MarshalText() ([]byte, error) {
+	var result string
+	switch m {
+	case MirrorEnforcementMode_MirrorEnforcementModeDefault:
+	case MirrorEnforcementMode_MirrorEnforcementModeStandard:
+		result = "standard"
+	case MirrorEnforcementMode_MirrorEnforcementModeStrict:
+		result = "strict"
+	default:
+		result = "unknown"
+	}
+	return []byte(result), nil
+}
+
+// UnmarshalText implements encoding.TextUnmarshaler.UnmarshalText.
+func (m *MirrorEnforcementMode) UnmarshalText(textBytes []byte) error {
+	// Convert the bytes to a string.
+	text := string(textBytes)
+
+	// Convert to a mirror enforcement mode.
+	switch text {
+	case "standard":
+		*m = MirrorEnforcementMode_MirrorEnforcementModeStandard
+	case "strict":
+		*m = MirrorEnforcementMode_MirrorEnforcementModeStrict
+	default:
+		return fmt.Errorf("unknown mirror enforcement mode specification: %s", text)
+	}
+
+	// Success.
+	return nil
+}
+
+// Supported indicates whether or not a particular mirror enforcement mode is a
+// valid, non-default value.
+func (m MirrorEnforcementMode) Supported() bool {
+	switch m {
+	case MirrorEnforcementMode_MirrorEnforcementModeStandard:
+		return true
+	case MirrorEnforcementMode_MirrorEnforcementModeStrict:
+		return true
+	default:
+		return false
+	}
+}
+
+// Description returns a human-readable description of a mirror enforcement
+// mode.
+func (m MirrorEnforcementMode) Description() string {
+	switch m {
+	case MirrorEnforcementMode_MirrorEnforcementModeDefault:
+		return "Default"
+	case MirrorEnforcementMode_MirrorEnforcementModeStandard:
+		return "Standard"
+	case MirrorEnforcementMode_MirrorEnforcementModeStrict:
+		return "Strict"
+	default:
+		return "Unknown"
+	}
+}
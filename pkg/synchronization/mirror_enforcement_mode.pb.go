@@ -0,0 +1,152 @@
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// versions:
+// 	protoc-gen-go v1.28.0
+// 	protoc        v3.19.4
+// source: synchronization/mirror_enforcement_mode.proto
+
+package synchronization
+
+import (
+	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
+	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	reflect "reflect"
+	sync "sync"
+)
+
+const (
+	// Verify that this generated code is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(20 - protoimpl.MinVersion)
+	// Verify that runtime/protoimpl is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
+)
+
+// MirrorEnforcementMode specifies the mode for enforcing replica contents in
+// the one-way-replica synchronization mode. It has no effect in other
+// synchronization modes.
+type MirrorEnforcementMode int32
+
+const (
+	// MirrorEnforcementMode_MirrorEnforcementModeDefault represents an
+	// unspecified mirror enforcement mode. It should be converted to one of the
+	// following values based on the desired default behavior.
+	MirrorEnforcementMode_MirrorEnforcementModeDefault MirrorEnforcementMode = 0
+	// MirrorEnforcementMode_MirrorEnforcementModeStandard specifies that
+	// modifications made directly to beta should be overwritten with the
+	// contents of alpha without being reported.
+	MirrorEnforcementMode_MirrorEnforcementModeStandard MirrorEnforcementMode = 1
+	// MirrorEnforcementMode_MirrorEnforcementModeStrict specifies that
+	// modifications made directly to beta should be overwritten with the
+	// contents of alpha and reported as mirror violations.
+	MirrorEnforcementMode_MirrorEnforcementModeStrict MirrorEnforcementMode = 2
+)
+
+// Enum value maps for MirrorEnforcementMode.
+var (
+	MirrorEnforcementMode_name = map[int32]string{
+		0: "MirrorEnforcementModeDefault",
+		1: "MirrorEnforcementModeStandard",
+		2: "MirrorEnforcementModeStrict",
+	}
+	MirrorEnforcementMode_value = map[string]int32{
+		"MirrorEnforcementModeDefault":  0,
+		"MirrorEnforcementModeStandard": 1,
+		"MirrorEnforcementModeStrict":   2,
+	}
+)
+
+func (x MirrorEnforcementMode) Enum() *MirrorEnforcementMode {
+	p := new(MirrorEnforcementMode)
+	*p = x
+	return p
+}
+
+func (x MirrorEnforcementMode) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (MirrorEnforcementMode) Descriptor() protoreflect.EnumDescriptor {
+	return file_synchronization_mirror_enforcement_mode_proto_enumTypes[0].Descriptor()
+}
+
+func (MirrorEnforcementMode) Type() protoreflect.EnumType {
+	return &file_synchronization_mirror_enforcement_mode_proto_enumTypes[0]
+}
+
+func (x MirrorEnforcementMode) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use MirrorEnforcementMode.Descriptor instead.
+func (MirrorEnforcementMode) EnumDescriptor() ([]byte, []int) {
+	return file_synchronization_mirror_enforcement_mode_proto_rawDescGZIP(), []int{0}
+}
+
+var File_synchronization_mirror_enforcement_mode_proto protoreflect.FileDescriptor
+
+var file_synchronization_mirror_enforcement_mode_proto_rawDesc = []byte{
+	0x0a, 0x2d, 0x73, 0x79, 0x6e, 0x63, 0x68, 0x72, 0x6f, 0x6e, 0x69, 0x7a, 0x61, 0x74, 0x69, 0x6f,
+	0x6e, 0x2f, 0x6d, 0x69, 0x72, 0x72, 0x6f, 0x72, 0x5f, 0x65, 0x6e, 0x66, 0x6f, 0x72, 0x63, 0x65,
+	0x6d, 0x65, 0x6e, 0x74, 0x5f, 0x6d, 0x6f, 0x64, 0x65, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x12,
+	0x0f, 0x73, 0x79, 0x6e, 0x63, 0x68, 0x72, 0x6f, 0x6e, 0x69, 0x7a, 0x61, 0x74, 0x69, 0x6f, 0x6e,
+	0x2a, 0x7d, 0x0a, 0x15, 0x4d, 0x69, 0x72, 0x72, 0x6f, 0x72, 0x45, 0x6e, 0x66, 0x6f, 0x72, 0x63,
+	0x65, 0x6d, 0x65, 0x6e, 0x74, 0x4d, 0x6f, 0x64, 0x65, 0x12, 0x20, 0x0a, 0x1c, 0x4d, 0x69, 0x72,
+	0x72, 0x6f, 0x72, 0x45, 0x6e, 0x66, 0x6f, 0x72, 0x63, 0x65, 0x6d, 0x65, 0x6e, 0x74, 0x4d, 0x6f,
+	0x64, 0x65, 0x44, 0x65, 0x66, 0x61, 0x75, 0x6c, 0x74, 0x10, 0x00, 0x12, 0x21, 0x0a, 0x1d, 0x4d,
+	0x69, 0x72, 0x72, 0x6f, 0x72, 0x45, 0x6e, 0x66, 0x6f, 0x72, 0x63, 0x65, 0x6d, 0x65, 0x6e, 0x74,
+	0x4d, 0x6f, 0x64, 0x65, 0x53, 0x74, 0x61, 0x6e, 0x64, 0x61, 0x72, 0x64, 0x10, 0x01, 0x12, 0x1f,
+	0x0a, 0x1b, 0x4d, 0x69, 0x72, 0x72, 0x6f, 0x72, 0x45, 0x6e, 0x66, 0x6f, 0x72, 0x63, 0x65, 0x6d,
+	0x65, 0x6e, 0x74, 0x4d, 0x6f, 0x64, 0x65, 0x53, 0x74, 0x72, 0x69, 0x63, 0x74, 0x10, 0x02, 0x42,
+	0x33, 0x5a, 0x31, 0x67, 0x69, 0x74, 0x68, 0x75, 0x62, 0x2e, 0x63, 0x6f, 0x6d, 0x2f, 0x6d, 0x75,
+	0x74, 0x61, 0x67, 0x65, 0x6e, 0x2d, 0x69, 0x6f, 0x2f, 0x6d, 0x75, 0x74, 0x61, 0x67, 0x65, 0x6e,
+	0x2f, 0x70, 0x6b, 0x67, 0x2f, 0x73, 0x79, 0x6e, 0x63, 0x68, 0x72, 0x6f, 0x6e, 0x69, 0x7a, 0x61,
+	0x74, 0x69, 0x6f, 0x6e, 0x62, 0x06, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x33,
+}
+
+var (
+	file_synchronization_mirror_enforcement_mode_proto_rawDescOnce sync.Once
+	file_synchronization_mirror_enforcement_mode_proto_rawDescData = file_synchronization_mirror_enforcement_mode_proto_rawDesc
+)
+
+func file_synchronization_mirror_enforcement_mode_proto_rawDescGZIP() []byte {
+	file_synchronization_mirror_enforcement_mode_proto_rawDescOnce.Do(func() {
+		file_synchronization_mirror_enforcement_mode_proto_rawDescData = protoimpl.X.CompressGZIP(file_synchronization_mirror_enforcement_mode_proto_rawDescData)
+	})
+	return file_synchronization_mirror_enforcement_mode_proto_rawDescData
+}
+
+var file_synchronization_mirror_enforcement_mode_proto_enumTypes = make([]protoimpl.EnumInfo, 1)
+var file_synchronization_mirror_enforcement_mode_proto_goTypes = []interface{}{
+	(MirrorEnforcementMode)(0), // 0: synchronization.MirrorEnforcementMode
+}
+var file_synchronization_mirror_enforcement_mode_proto_depIdxs = []int32{
+	0, // [0:0] is the sub-list for method output_type
+	0, // [0:0] is the sub-list for method input_type
+	0, // [0:0] is the sub-list for extension type_name
+	0, // [0:0] is the sub-list for extension extendee
+	0, // [0:0] is the sub-list for field type_name
+}
+
+func init() { file_synchronization_mirror_enforcement_mode_proto_init() }
+func file_synchronization_mirror_enforcement_mode_proto_init() {
+	if File_synchronization_mirror_enforcement_mode_proto != nil {
+		return
+	}
+	type x struct{}
+	out := protoimpl.TypeBuilder{
+		File: protoimpl.DescBuilder{
+			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
+			RawDescriptor: file_synchronization_mirror_enforcement_mode_proto_rawDesc,
+			NumEnums:      1,
+			NumMessages:   0,
+			NumExtensions: 0,
+			NumServices:   0,
+		},
+		GoTypes:           file_synchronization_mirror_enforcement_mode_proto_goTypes,
+		DependencyIndexes: file_synchronization_mirror_enforcement_mode_proto_depIdxs,
+		EnumInfos:         file_synchronization_mirror_enforcement_mode_proto_enumTypes,
+	}.Build()
+	File_synchronization_mirror_enforcement_mode_proto = out.File
+	file_synchronization_mirror_enforcement_mode_proto_rawDesc = nil
+	file_synchronization_mirror_enforcement_mode_proto_goTypes = nil
+	file_synchronization_mirror_enforcement_mode_proto_depIdxs = nil
+}
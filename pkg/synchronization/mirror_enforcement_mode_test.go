@@ -0,0 +1,91 @@
+package synchronization
+
+import (
+	"testing"
+)
+
+// TestMirrorEnforcementModeUnmarshal tests that unmarshaling from a string
+// specification succeeds for MirrorEnforcementMode.
+func TestMirrorEnforcementModeUnmarshal(t *testing.T) {
+	// Set up test cases.
+	testCases := []struct {
+		text          string
+		expectedMode  MirrorEnforcementMode
+		expectFailure bool
+	}{
+		{"", MirrorEnforcementMode_MirrorEnforcementModeDefault, true},
+		{"asdf", MirrorEnforcementMode_MirrorEnforcementModeDefault, true},
+		{"standard", MirrorEnforcementMode_MirrorEnforcementModeStandard, false},
+		{"strict", MirrorEnforcementMode_MirrorEnforcementModeStrict, false},
+	}
+
+	// Process test cases.
+	for _, testCase := range testCases {
+		var mode MirrorEnforcementMode
+		if err := mode.UnmarshalText([]byte(testCase.text)); err != nil {
+			if !testCase.expectFailure {
+				t.Errorf("unable to unmarshal text (%s): %s", testCase.text, err)
+			}
+		} else if testCase.expectFailure {
+			t.Error("unmarshaling succeeded unexpectedly for text:", testCase.text)
+		} else if mode != testCase.expectedMode {
+			t.Errorf(
+				"unmarshaled mode (%s) does not match expected (%s)",
+				mode,
+				testCase.expectedMode,
+			)
+		}
+	}
+}
+
+// TestMirrorEnforcementModeSupported tests that MirrorEnforcementMode support
+// detection works as expected.
+func TestMirrorEnforcementModeSupported(t *testing.T) {
+	// Set up test cases.
+	testCases := []struct {
+		mode            MirrorEnforcementMode
+		expectSupported bool
+	}{
+		{MirrorEnforcementMode_MirrorEnforcementModeDefault, false},
+		{MirrorEnforcementMode_MirrorEnforcementModeStandard, true},
+		{MirrorEnforcementMode_MirrorEnforcementModeStrict, true},
+		{(MirrorEnforcementMode_MirrorEnforcementModeStrict + 1), false},
+	}
+
+	// Process test cases.
+	for _, testCase := range testCases {
+		if supported := testCase.mode.Supported(); supported != testCase.expectSupported {
+			t.Errorf(
+				"mode support status (%t) does not match expected (%t)",
+				supported,
+				testCase.expectSupported,
+			)
+		}
+	}
+}
+
+// TestMirrorEnforcementModeDescription tests that MirrorEnforcementMode
+// description generation works as expected.
+func TestMirrorEnforcementModeDescription(t *testing.T) {
+	// Set up test cases.
+	testCases := []struct {
+		mode                MirrorEnforcementMode
+		expectedDescription string
+	}{
+		{MirrorEnforcementMode_MirrorEnforcementModeDefault, "Default"},
+		{MirrorEnforcementMode_MirrorEnforcementModeStandard, "Standard"},
+		{MirrorEnforcementMode_MirrorEnforcementModeStrict, "Strict"},
+		{(MirrorEnforcementMode_MirrorEnforcementModeStrict + 1), "Unknown"},
+	}
+
+	// Process test cases.
+	for _, testCase := range testCases {
+		if description := testCase.mode.Description(); description != testCase.expectedDescription {
+			t.Errorf(
+				"mode description (%s) does not match expected (%s)",
+				description,
+				testCase.expectedDescription,
+			)
+		}
+	}
+}
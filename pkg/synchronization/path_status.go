@@ -0,0 +1,139 @@
+package synchronization
+
+import (
+	"errors"
+	"strings"
+
+	"google.golang.org/protobuf/proto"
+
+	"github.com/mutagen-io/mutagen/pkg/synchronization/core"
+	"github.com/mutagen-io/mutagen/pkg/synchronization/rsync"
+)
+
+// Description returns a human-readable description of a path status.
+func (s PathStatus) Description() string {
+	switch s {
+	case PathStatus_PathStatusUnknown:
+		return "Unknown"
+	case PathStatus_PathStatusInSync:
+		return "In sync"
+	case PathStatus_PathStatusPending:
+		return "Pending"
+	case PathStatus_PathStatusStaging:
+		return "Staging"
+	case PathStatus_PathStatusConflicted:
+		return "Conflicted"
+	case PathStatus_PathStatusProblematic:
+		return "Problematic"
+	default:
+		return "Unknown"
+	}
+}
+
+// EnsureValid ensures that PathStatusResult's invariants are respected.
+func (r *PathStatusResult) EnsureValid() error {
+	// A nil path status result is not valid.
+	if r == nil {
+		return errors.New("nil path status result")
+	}
+
+	// Ensure that a session identifier is present.
+	if r.Session == "" {
+		return errors.New("empty session identifier")
+	}
+
+	// Ensure that staging progress is only present when staging.
+	if r.StagingProgress != nil && r.Status != PathStatus_PathStatusStaging {
+		return errors.New("staging progress present for non-staging path")
+	}
+
+	// Success.
+	return nil
+}
+
+// pathsOverlap returns true if either path is equal to or contained beneath the
+// other. This allows status queries on directories to reflect activity on
+// their contents, and queries on files to reflect activity on their parents.
+func pathsOverlap(first, second string) bool {
+	if first == "" || second == "" || first == second {
+		return true
+	}
+	return strings.HasPrefix(first, second+"/") || strings.HasPrefix(second, first+"/")
+}
+
+// problemAffectingPath returns the first problem in the specified list that
+// overlaps with the specified path, if any.
+func problemAffectingPath(problems []*core.Problem, path string) *core.Problem {
+	for _, problem := range problems {
+		if pathsOverlap(problem.Path, path) {
+			return problem
+		}
+	}
+	return nil
+}
+
+// computePathStatus computes the synchronization status of a path based on the
+// session state and the root paths of any pending transitions. Statuses are
+// prioritized such that conflicts take precedence over problems, which take
+// precedence over staging, which takes precedence over pending changes.
+func computePathStatus(state *State, pendingPaths []string, path string) *PathStatusResult {
+	// Create the result.
+	result := &PathStatusResult{Session: state.Session.Identifier, Path: path}
+
+	// If the session hasn't completed a scan on both endpoints or isn't
+	// currently connected, then we can't determine the path status.
+	switch state.Status {
+	case Status_Disconnected, Status_ConnectingAlpha, Status_ConnectingBeta:
+		return result
+	}
+	if !state.AlphaState.Scanned || !state.BetaState.Scanned {
+		return result
+	}
+
+	// Check for conflicts.
+	for _, conflict := range state.Conflicts {
+		if pathsOverlap(conflict.Root, path) {
+			result.Status = PathStatus_PathStatusConflicted
+			return result
+		}
+	}
+
+	// Check for problems.
+	for _, problems := range [][]*core.Problem{
+		state.AlphaState.ScanProblems,
+		state.BetaState.ScanProblems,
+		state.AlphaState.TransitionProblems,
+		state.BetaState.TransitionProblems,
+	} {
+		if problem := problemAffectingPath(problems, path); problem != nil {
+			result.Status = PathStatus_PathStatusProblematic
+			result.Problem = problem.Error
+			return result
+		}
+	}
+
+	// Check for staging.
+	var stagingProgress *rsync.ReceiverState
+	if state.Status == Status_StagingAlpha {
+		stagingProgress = state.AlphaState.StagingProgress
+	} else if state.Status == Status_StagingBeta {
+		stagingProgress = state.BetaState.StagingProgress
+	}
+	if stagingProgress != nil && stagingProgress.Path != "" && pathsOverlap(stagingProgress.Path, path) {
+		result.Status = PathStatus_PathStatusStaging
+		result.StagingProgress = proto.Clone(stagingProgress).(*rsync.ReceiverState)
+		return result
+	}
+
+	// Check for pending changes.
+	for _, pending := range pendingPaths {
+		if pathsOverlap(pending, path) {
+			result.Status = PathStatus_PathStatusPending
+			return result
+		}
+	}
+
+	// Otherwise the path is in sync.
+	result.Status = PathStatus_PathStatusInSync
+	return result
+}
@@ -0,0 +1,289 @@
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// versions:
+// 	protoc-gen-go v1.28.0
+// 	protoc        v3.19.4
+// source: synchronization/path_status.proto
+
+package synchronization
+
+import (
+	rsync "github.com/mutagen-io/mutagen/pkg/synchronization/rsync"
+	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
+	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	reflect "reflect"
+	sync "sync"
+)
+
+const (
+	// Verify that this generated code is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(20 - protoimpl.MinVersion)
+	// Verify that runtime/protoimpl is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
+)
+
+// PathStatus specifies the synchronization status of a path.
+type PathStatus int32
+
+const (
+	// PathStatus_PathStatusUnknown indicates that the status of the path
+	// can't currently be determined, e.g. because the session is disconnected
+	// or hasn't yet completed a scan.
+	PathStatus_PathStatusUnknown PathStatus = 0
+	// PathStatus_PathStatusInSync indicates that no synchronization activity is
+	// pending for the path as of the most recent synchronization cycle.
+	PathStatus_PathStatusInSync PathStatus = 1
+	// PathStatus_PathStatusPending indicates that changes at or beneath the
+	// path are waiting to be propagated.
+	PathStatus_PathStatusPending PathStatus = 2
+	// PathStatus_PathStatusStaging indicates that the content of the path is
+	// currently being staged.
+	PathStatus_PathStatusStaging PathStatus = 3
+	// PathStatus_PathStatusConflicted indicates that the path is involved in a
+	// synchronization conflict.
+	PathStatus_PathStatusConflicted PathStatus = 4
+	// PathStatus_PathStatusProblematic indicates that a scan or transition
+	// problem was encountered at or beneath the path.
+	PathStatus_PathStatusProblematic PathStatus = 5
+)
+
+// Enum value maps for PathStatus.
+var (
+	PathStatus_name = map[int32]string{
+		0: "PathStatusUnknown",
+		1: "PathStatusInSync",
+		2: "PathStatusPending",
+		3: "PathStatusStaging",
+		4: "PathStatusConflicted",
+		5: "PathStatusProblematic",
+	}
+	PathStatus_value = map[string]int32{
+		"PathStatusUnknown":     0,
+		"PathStatusInSync":      1,
+		"PathStatusPending":     2,
+		"PathStatusStaging":     3,
+		"PathStatusConflicted":  4,
+		"PathStatusProblematic": 5,
+	}
+)
+
+func (x PathStatus) Enum() *PathStatus {
+	p := new(PathStatus)
+	*p = x
+	return p
+}
+
+func (x PathStatus) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (PathStatus) Descriptor() protoreflect.EnumDescriptor {
+	return file_synchronization_path_status_proto_enumTypes[0].Descriptor()
+}
+
+func (PathStatus) Type() protoreflect.EnumType {
+	return &file_synchronization_path_status_proto_enumTypes[0]
+}
+
+func (x PathStatus) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use PathStatus.Descriptor instead.
+func (PathStatus) EnumDescriptor() ([]byte, []int) {
+	return file_synchronization_path_status_proto_rawDescGZIP(), []int{0}
+}
+
+// PathStatusResult encodes the result of a path status query.
+type PathStatusResult struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	// Session is the identifier of the session that was queried.
+	Session string `protobuf:"bytes,1,opt,name=session,proto3" json:"session,omitempty"`
+	// Path is the path that was queried (relative to the synchronization
+	// root).
+	Path string `protobuf:"bytes,2,opt,name=path,proto3" json:"path,omitempty"`
+	// Status is the synchronization status of the path.
+	Status PathStatus `protobuf:"varint,3,opt,name=status,proto3,enum=synchronization.PathStatus" json:"status,omitempty"`
+	// StagingProgress is the staging progress for the path. It is only set if
+	// Status is PathStatusStaging.
+	StagingProgress *rsync.ReceiverState `protobuf:"bytes,4,opt,name=stagingProgress,proto3" json:"stagingProgress,omitempty"`
+	// Problem is a description of the problem affecting the path. It is only
+	// set if Status is PathStatusProblematic.
+	Problem string `protobuf:"bytes,5,opt,name=problem,proto3" json:"problem,omitempty"`
+}
+
+func (x *PathStatusResult) Reset() {
+	*x = PathStatusResult{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_synchronization_path_status_proto_msgTypes[0]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *PathStatusResult) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*PathStatusResult) ProtoMessage() {}
+
+func (x *PathStatusResult) ProtoReflect() protoreflect.Message {
+	mi := &file_synchronization_path_status_proto_msgTypes[0]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use PathStatusResult.ProtoReflect.Descriptor instead.
+func (*PathStatusResult) Descriptor() ([]byte, []int) {
+	return file_synchronization_path_status_proto_rawDescGZIP(), []int{0}
+}
+
+func (x *PathStatusResult) GetSession() string {
+	if x != nil {
+		return x.Session
+	}
+	return ""
+}
+
+func (x *PathStatusResult) GetPath() string {
+	if x != nil {
+		return x.Path
+	}
+	return ""
+}
+
+func (x *PathStatusResult) GetStatus() PathStatus {
+	if x != nil {
+		return x.Status
+	}
+	return PathStatus_PathStatusUnknown
+}
+
+func (x *PathStatusResult) GetStagingProgress() *rsync.ReceiverState {
+	if x != nil {
+		return x.StagingProgress
+	}
+	return nil
+}
+
+func (x *PathStatusResult) GetProblem() string {
+	if x != nil {
+		return x.Problem
+	}
+	return ""
+}
+
+var File_synchronization_path_status_proto protoreflect.FileDescriptor
+
+var file_synchronization_path_status_proto_rawDesc = []byte{
+	0x0a, 0x21, 0x73, 0x79, 0x6e, 0x63, 0x68, 0x72, 0x6f, 0x6e, 0x69, 0x7a, 0x61, 0x74, 0x69, 0x6f,
+	0x6e, 0x2f, 0x70, 0x61, 0x74, 0x68, 0x5f, 0x73, 0x74, 0x61, 0x74, 0x75, 0x73, 0x2e, 0x70, 0x72,
+	0x6f, 0x74, 0x6f, 0x12, 0x0f, 0x73, 0x79, 0x6e, 0x63, 0x68, 0x72, 0x6f, 0x6e, 0x69, 0x7a, 0x61,
+	0x74, 0x69, 0x6f, 0x6e, 0x1a, 0x23, 0x73, 0x79, 0x6e, 0x63, 0x68, 0x72, 0x6f, 0x6e, 0x69, 0x7a,
+	0x61, 0x74, 0x69, 0x6f, 0x6e, 0x2f, 0x72, 0x73, 0x79, 0x6e, 0x63, 0x2f, 0x72, 0x65, 0x63, 0x65,
+	0x69, 0x76, 0x65, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x22, 0xcf, 0x01, 0x0a, 0x10, 0x50, 0x61,
+	0x74, 0x68, 0x53, 0x74, 0x61, 0x74, 0x75, 0x73, 0x52, 0x65, 0x73, 0x75, 0x6c, 0x74, 0x12, 0x18,
+	0x0a, 0x07, 0x73, 0x65, 0x73, 0x73, 0x69, 0x6f, 0x6e, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52,
+	0x07, 0x73, 0x65, 0x73, 0x73, 0x69, 0x6f, 0x6e, 0x12, 0x12, 0x0a, 0x04, 0x70, 0x61, 0x74, 0x68,
+	0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x04, 0x70, 0x61, 0x74, 0x68, 0x12, 0x33, 0x0a, 0x06,
+	0x73, 0x74, 0x61, 0x74, 0x75, 0x73, 0x18, 0x03, 0x20, 0x01, 0x28, 0x0e, 0x32, 0x1b, 0x2e, 0x73,
+	0x79, 0x6e, 0x63, 0x68, 0x72, 0x6f, 0x6e, 0x69, 0x7a, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x2e, 0x50,
+	0x61, 0x74, 0x68, 0x53, 0x74, 0x61, 0x74, 0x75, 0x73, 0x52, 0x06, 0x73, 0x74, 0x61, 0x74, 0x75,
+	0x73, 0x12, 0x3e, 0x0a, 0x0f, 0x73, 0x74, 0x61, 0x67, 0x69, 0x6e, 0x67, 0x50, 0x72, 0x6f, 0x67,
+	0x72, 0x65, 0x73, 0x73, 0x18, 0x04, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x14, 0x2e, 0x72, 0x73, 0x79,
+	0x6e, 0x63, 0x2e, 0x52, 0x65, 0x63, 0x65, 0x69, 0x76, 0x65, 0x72, 0x53, 0x74, 0x61, 0x74, 0x65,
+	0x52, 0x0f, 0x73, 0x74, 0x61, 0x67, 0x69, 0x6e, 0x67, 0x50, 0x72, 0x6f, 0x67, 0x72, 0x65, 0x73,
+	0x73, 0x12, 0x18, 0x0a, 0x07, 0x70, 0x72, 0x6f, 0x62, 0x6c, 0x65, 0x6d, 0x18, 0x05, 0x20, 0x01,
+	0x28, 0x09, 0x52, 0x07, 0x70, 0x72, 0x6f, 0x62, 0x6c, 0x65, 0x6d, 0x2a, 0x9c, 0x01, 0x0a, 0x0a,
+	0x50, 0x61, 0x74, 0x68, 0x53, 0x74, 0x61, 0x74, 0x75, 0x73, 0x12, 0x15, 0x0a, 0x11, 0x50, 0x61,
+	0x74, 0x68, 0x53, 0x74, 0x61, 0x74, 0x75, 0x73, 0x55, 0x6e, 0x6b, 0x6e, 0x6f, 0x77, 0x6e, 0x10,
+	0x00, 0x12, 0x14, 0x0a, 0x10, 0x50, 0x61, 0x74, 0x68, 0x53, 0x74, 0x61, 0x74, 0x75, 0x73, 0x49,
+	0x6e, 0x53, 0x79, 0x6e, 0x63, 0x10, 0x01, 0x12, 0x15, 0x0a, 0x11, 0x50, 0x61, 0x74, 0x68, 0x53,
+	0x74, 0x61, 0x74, 0x75, 0x73, 0x50, 0x65, 0x6e, 0x64, 0x69, 0x6e, 0x67, 0x10, 0x02, 0x12, 0x15,
+	0x0a, 0x11, 0x50, 0x61, 0x74, 0x68, 0x53, 0x74, 0x61, 0x74, 0x75, 0x73, 0x53, 0x74, 0x61, 0x67,
+	0x69, 0x6e, 0x67, 0x10, 0x03, 0x12, 0x18, 0x0a, 0x14, 0x50, 0x61, 0x74, 0x68, 0x53, 0x74, 0x61,
+	0x74, 0x75, 0x73, 0x43, 0x6f, 0x6e, 0x66, 0x6c, 0x69, 0x63, 0x74, 0x65, 0x64, 0x10, 0x04, 0x12,
+	0x19, 0x0a, 0x15, 0x50, 0x61, 0x74, 0x68, 0x53, 0x74, 0x61, 0x74, 0x75, 0x73, 0x50, 0x72, 0x6f,
+	0x62, 0x6c, 0x65, 0x6d, 0x61, 0x74, 0x69, 0x63, 0x10, 0x05, 0x42, 0x33, 0x5a, 0x31, 0x67, 0x69,
+	0x74, 0x68, 0x75, 0x62, 0x2e, 0x63, 0x6f, 0x6d, 0x2f, 0x6d, 0x75, 0x74, 0x61, 0x67, 0x65, 0x6e,
+	0x2d, 0x69, 0x6f, 0x2f, 0x6d, 0x75, 0x74, 0x61, 0x67, 0x65, 0x6e, 0x2f, 0x70, 0x6b, 0x67, 0x2f,
+	0x73, 0x79, 0x6e, 0x63, 0x68, 0x72, 0x6f, 0x6e, 0x69, 0x7a, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x62,
+	0x06, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x33,
+}
+
+var (
+	file_synchronization_path_status_proto_rawDescOnce sync.Once
+	file_synchronization_path_status_proto_rawDescData = file_synchronization_path_status_proto_rawDesc
+)
+
+func file_synchronization_path_status_proto_rawDescGZIP() []byte {
+	file_synchronization_path_status_proto_rawDescOnce.Do(func() {
+		file_synchronization_path_status_proto_rawDescData = protoimpl.X.CompressGZIP(file_synchronization_path_status_proto_rawDescData)
+	})
+	return file_synchronization_path_status_proto_rawDescData
+}
+
+var file_synchronization_path_status_proto_enumTypes = make([]protoimpl.EnumInfo, 1)
+var file_synchronization_path_status_proto_msgTypes = make([]protoimpl.MessageInfo, 1)
+var file_synchronization_path_status_proto_goTypes = []interface{}{
+	(PathStatus)(0),             // 0: synchronization.PathStatus
+	(*PathStatusResult)(nil),    // 1: synchronization.PathStatusResult
+	(*rsync.ReceiverState)(nil), // 2: rsync.ReceiverState
+}
+var file_synchronization_path_status_proto_depIdxs = []int32{
+	0, // 0: synchronization.PathStatusResult.status:type_name -> synchronization.PathStatus
+	2, // 1: synchronization.PathStatusResult.stagingProgress:type_name -> rsync.ReceiverState
+	2, // [2:2] is the sub-list for method output_type
+	2, // [2:2] is the sub-list for method input_type
+	2, // [2:2] is the sub-list for extension type_name
+	2, // [2:2] is the sub-list for extension extendee
+	0, // [0:2] is the sub-list for field type_name
+}
+
+func init() { file_synchronization_path_status_proto_init() }
+func file_synchronization_path_status_proto_init() {
+	if File_synchronization_path_status_proto != nil {
+		return
+	}
+	if !protoimpl.UnsafeEnabled {
+		file_synchronization_path_status_proto_msgTypes[0].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*PathStatusResult); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+	}
+	type x struct{}
+	out := protoimpl.TypeBuilder{
+		File: protoimpl.DescBuilder{
+			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
+			RawDescriptor: file_synchronization_path_status_proto_rawDesc,
+			NumEnums:      1,
+			NumMessages:   1,
+			NumExtensions: 0,
+			NumServices:   0,
+		},
+		GoTypes:           file_synchronization_path_status_proto_goTypes,
+		DependencyIndexes: file_synchronization_path_status_proto_depIdxs,
+		EnumInfos:         file_synchronization_path_status_proto_enumTypes,
+		MessageInfos:      file_synchronization_path_status_proto_msgTypes,
+	}.Build()
+	File_synchronization_path_status_proto = out.File
+	file_synchronization_path_status_proto_rawDesc = nil
+	file_synchronization_path_status_proto_goTypes = nil
+	file_synchronization_path_status_proto_depIdxs = nil
+}
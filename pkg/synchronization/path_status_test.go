@@ -0,0 +1,62 @@
+package synchronization
+
+import (
+	"testing"
+
+	"github.com/mutagen-io/mutagen/pkg/synchronization/core"
+	"github.com/mutagen-io/mutagen/pkg/synchronization/rsync"
+)
+
+// TestComputePathStatus tests computePathStatus.
+func TestComputePathStatus(t *testing.T) {
+	// Create a state with activity at various paths.
+	state := &State{
+		Session: &Session{Identifier: "session"},
+		Status:  Status_StagingBeta,
+		Conflicts: []*core.Conflict{
+			{Root: "conflicted"},
+		},
+		AlphaState: &EndpointState{
+			Scanned:      true,
+			ScanProblems: []*core.Problem{{Path: "problematic/file", Error: "problem"}},
+		},
+		BetaState: &EndpointState{
+			Scanned:         true,
+			StagingProgress: &rsync.ReceiverState{Path: "pending/staging", ExpectedSize: 10},
+		},
+	}
+	pendingPaths := []string{"pending"}
+
+	// Define test cases.
+	tests := []struct {
+		path     string
+		expected PathStatus
+	}{
+		{"conflicted", PathStatus_PathStatusConflicted},
+		{"conflicted/child", PathStatus_PathStatusConflicted},
+		{"problematic", PathStatus_PathStatusProblematic},
+		{"problematic/file", PathStatus_PathStatusProblematic},
+		{"pending/staging", PathStatus_PathStatusStaging},
+		{"pending/other", PathStatus_PathStatusPending},
+		{"synced", PathStatus_PathStatusInSync},
+		{"conflictedness", PathStatus_PathStatusInSync},
+		{"", PathStatus_PathStatusConflicted},
+	}
+
+	// Process test cases.
+	for _, test := range tests {
+		result := computePathStatus(state, pendingPaths, test.path)
+		if result.Status != test.expected {
+			t.Errorf("status for %q: %s != %s", test.path, result.Status.Description(), test.expected.Description())
+		}
+		if err := result.EnsureValid(); err != nil {
+			t.Errorf("result for %q invalid: %v", test.path, err)
+		}
+	}
+
+	// Verify that status is unknown if the session is disconnected.
+	state.Status = Status_Disconnected
+	if result := computePathStatus(state, pendingPaths, "synced"); result.Status != PathStatus_PathStatusUnknown {
+		t.Error("status known for disconnected session")
+	}
+}
@@ -0,0 +1,3 @@
+// Package azure provides the Azure virtual machine synchronization session
+// protocol implementation.
+package azure
@@ -41,12 +41,22 @@ func (h *protocolHandler) Connect(
 	// Verify that the URL is of the correct kind and protocol.
 	if url.Kind != urlpkg.Kind_Synchronization {
 		panic("non-synchronization URL dispatched to synchronization protocol handler")
-	} else if url.Protocol != urlpkg.Protocol_Docker {
-		panic("non-Docker URL dispatched to Docker protocol handler")
+	} else if url.Protocol != urlpkg.Protocol_Docker && url.Protocol != urlpkg.Protocol_Nerdctl {
+		panic("non-container URL dispatched to Docker protocol handler")
 	}
 
-	// Create a Docker agent transport.
-	transport, err := docker.NewTransport(url.Host, url.User, url.Environment, url.Parameters, prompter)
+	// Extract the agent directory, which is handled by the agent dialer rather
+	// than the Docker CLI.
+	agentDirectory, parameters := urlpkg.SplitAgentDirectory(url.Parameters)
+
+	// Create an agent transport using the appropriate command line interface.
+	var transport agent.Transport
+	var err error
+	if url.Protocol == urlpkg.Protocol_Nerdctl {
+		transport, err = docker.NewNerdctlTransport(url.Host, url.User, url.Environment, prompter)
+	} else {
+		transport, err = docker.NewTransport(url.Host, url.User, url.Environment, parameters, prompter)
+	}
 	if err != nil {
 		return nil, fmt.Errorf("unable to create Docker transport: %w", err)
 	}
@@ -58,7 +68,7 @@ func (h *protocolHandler) Connect(
 	// cancellation.
 	go func() {
 		// Perform the dialing operation.
-		stream, err := agent.Dial(logger, transport, agent.CommandSynchronizer, prompter)
+		stream, err := agent.DialShared(logger, url, transport, agent.CommandSynchronizer, configuration.AgentNiceness, prompter, agentDirectory)
 
 		// Transmit the result or, if cancelled, close the stream.
 		select {
@@ -89,4 +99,8 @@ func (h *protocolHandler) Connect(
 func init() {
 	// Register the Docker protocol handler with the synchronization package.
 	synchronization.ProtocolHandlers[urlpkg.Protocol_Docker] = &protocolHandler{}
+
+	// Register the same handler for nerdctl, which uses a Docker-compatible
+	// command line interface.
+	synchronization.ProtocolHandlers[urlpkg.Protocol_Nerdctl] = &protocolHandler{}
 }
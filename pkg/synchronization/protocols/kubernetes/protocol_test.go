@@ -0,0 +1,150 @@
+package kubernetes
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"testing"
+
+	"github.com/mutagen-io/mutagen/pkg/kubernetes"
+	"github.com/mutagen-io/mutagen/pkg/logging"
+	"github.com/mutagen-io/mutagen/pkg/synchronization"
+	urlpkg "github.com/mutagen-io/mutagen/pkg/url"
+)
+
+// TestMain is the entry point for protocol tests. Since the Kubernetes
+// transport invokes the current executable as its helper, it also acts as a
+// stand-in for the Kubernetes exec helper that always fails to reach the
+// cluster, recording its arguments to the file specified by the
+// FAKE_KUBERNETES_LOG environment variable.
+func TestMain(m *testing.M) {
+	// Handle helper invocations.
+	if len(os.Args) > 1 && os.Args[1] == kubernetes.HelperCommandName {
+		if log, err := os.OpenFile(os.Getenv("FAKE_KUBERNETES_LOG"), os.O_WRONLY|os.O_APPEND|os.O_CREATE, 0600); err == nil {
+			fmt.Fprintln(log, strings.Join(os.Args[1:], " "))
+			log.Close()
+		}
+		fmt.Fprintln(os.Stderr, "Error: pod not found")
+		os.Exit(kubernetes.HelperFailureExitCode)
+	}
+
+	// Run tests.
+	os.Exit(m.Run())
+}
+
+// setupFakeHelper configures the helper stand-in, returning the path to its
+// argument log.
+func setupFakeHelper(t *testing.T) string {
+	t.Helper()
+	if runtime.GOOS == "windows" {
+		t.Skip()
+	}
+	log := filepath.Join(t.TempDir(), "log")
+	t.Setenv("FAKE_KUBERNETES_LOG", log)
+	return log
+}
+
+// TestProtocolHandlerRegistration tests that the protocol handler is
+// registered for Kubernetes URLs.
+func TestProtocolHandlerRegistration(t *testing.T) {
+	if _, ok := synchronization.ProtocolHandlers[urlpkg.Protocol_Kubernetes].(*protocolHandler); !ok {
+		t.Error("protocol handler not registered")
+	}
+}
+
+// TestConnectErrors tests that Connect fails for invalid targets and when
+// the cluster is unreachable.
+func TestConnectErrors(t *testing.T) {
+	log := setupFakeHelper(t)
+
+	// Set up test cases.
+	testCases := []string{
+		"invalid",
+		"default/web-0/app",
+	}
+
+	// Process test cases.
+	logger := logging.NewLogger(logging.LevelDisabled, io.Discard)
+	handler := &protocolHandler{}
+	for i, target := range testCases {
+		url := &urlpkg.URL{
+			Kind:     urlpkg.Kind_Synchronization,
+			Protocol: urlpkg.Protocol_Kubernetes,
+			Host:     target,
+			Path:     "~/project",
+		}
+		endpoint, err := handler.Connect(
+			context.Background(), logger, url, "", "session",
+			synchronization.Version_Version1, &synchronization.Configuration{}, true,
+		)
+		if err == nil {
+			endpoint.Shutdown()
+			t.Errorf("test index %d: connect succeeded unexpectedly", i)
+		}
+	}
+
+	// Verify that the helper was invoked once for the valid target and that
+	// its failure didn't trigger an agent installation attempt.
+	invocations, err := os.ReadFile(log)
+	if err != nil {
+		t.Fatal("unable to read helper invocations:", err)
+	}
+	lines := strings.Split(strings.TrimSpace(string(invocations)), "\n")
+	expectedPrefix := kubernetes.HelperCommandName + " --namespace=default --pod=web-0 --container=app -- sh -c "
+	if len(lines) != 1 {
+		t.Error("unexpected number of helper invocations:", len(lines))
+	} else if !strings.HasPrefix(lines[0], expectedPrefix) {
+		t.Error("helper invocation has unexpected arguments:", lines[0])
+	} else if !strings.Contains(lines[0], " multiplexer ") {
+		t.Error("helper invocation does not run agent multiplexer:", lines[0])
+	}
+}
+
+// TestConnectCancelled tests that Connect respects cancellation.
+func TestConnectCancelled(t *testing.T) {
+	setupFakeHelper(t)
+
+	// Create a cancelled context.
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	// Attempt to connect.
+	url := &urlpkg.URL{
+		Kind:     urlpkg.Kind_Synchronization,
+		Protocol: urlpkg.Protocol_Kubernetes,
+		Host:     "default/web-0/app",
+		Path:     "~/project",
+	}
+	handler := &protocolHandler{}
+	if _, err := handler.Connect(
+		ctx, logging.NewLogger(logging.LevelDisabled, io.Discard), url, "", "session",
+		synchronization.Version_Version1, &synchronization.Configuration{}, true,
+	); err == nil {
+		t.Error("connect succeeded unexpectedly")
+	}
+}
+
+// TestConnectWrongProtocolPanics tests that Connect panics if dispatched a URL
+// with the wrong protocol.
+func TestConnectWrongProtocolPanics(t *testing.T) {
+	defer func() {
+		if recover() == nil {
+			t.Error("connect did not panic")
+		}
+	}()
+	url := &urlpkg.URL{
+		Kind:     urlpkg.Kind_Synchronization,
+		Protocol: urlpkg.Protocol_Docker,
+		Host:     "container",
+		Path:     "/path",
+	}
+	handler := &protocolHandler{}
+	handler.Connect(
+		context.Background(), logging.NewLogger(logging.LevelDisabled, io.Discard), url, "", "session",
+		synchronization.Version_Version1, &synchronization.Configuration{}, true,
+	)
+}
@@ -0,0 +1,2 @@
+// Package lxd provides the LXD synchronization session protocol implementation.
+package lxd
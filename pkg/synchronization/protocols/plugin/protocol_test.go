@@ -0,0 +1,123 @@
+package plugin
+
+import (
+	"context"
+	"io"
+	"os"
+	"path/filepath"
+	"runtime"
+	"testing"
+
+	"github.com/mutagen-io/mutagen/pkg/agent/transport/plugin"
+	"github.com/mutagen-io/mutagen/pkg/logging"
+	"github.com/mutagen-io/mutagen/pkg/synchronization"
+	urlpkg "github.com/mutagen-io/mutagen/pkg/url"
+)
+
+// installFailingPlugin creates a plugin executable with the specified name
+// that fails all operations and sets the PATH environment variable to include
+// only its directory.
+func installFailingPlugin(t *testing.T, name string) {
+	// Plugin discovery on Windows relies on executable extensions, so we only
+	// run these tests on POSIX systems.
+	if runtime.GOOS == "windows" {
+		t.Skip()
+	}
+
+	// Create the executable and set the search path.
+	directory := t.TempDir()
+	executable := filepath.Join(directory, plugin.ExecutablePrefix+name)
+	if err := os.WriteFile(executable, []byte("#!/bin/sh\necho \"unreachable target\" >&2\nexit 1\n"), 0700); err != nil {
+		t.Fatal("unable to create plugin executable:", err)
+	}
+	t.Setenv("PATH", directory)
+}
+
+// TestProtocolHandlerRegistration tests that the protocol handler is
+// registered for plugin URLs.
+func TestProtocolHandlerRegistration(t *testing.T) {
+	if _, ok := synchronization.ProtocolHandlers[urlpkg.Protocol_Plugin].(*protocolHandler); !ok {
+		t.Error("protocol handler not registered")
+	}
+}
+
+// TestConnectErrors tests that Connect fails if the plugin can't be found or
+// if the plugin fails.
+func TestConnectErrors(t *testing.T) {
+	// Install a failing plugin.
+	installFailingPlugin(t, "failing")
+
+	// Set up test cases.
+	testCases := []string{
+		"missing",
+		"failing",
+	}
+
+	// Process test cases.
+	logger := logging.NewLogger(logging.LevelDisabled, io.Discard)
+	handler := &protocolHandler{}
+	for i, name := range testCases {
+		url := &urlpkg.URL{
+			Kind:       urlpkg.Kind_Synchronization,
+			Protocol:   urlpkg.Protocol_Plugin,
+			Host:       "target",
+			Path:       "/project",
+			Parameters: map[string]string{urlpkg.PluginParameter: name},
+		}
+		endpoint, err := handler.Connect(
+			context.Background(), logger, url, "", "session",
+			synchronization.Version_Version1, &synchronization.Configuration{}, true,
+		)
+		if err == nil {
+			endpoint.Shutdown()
+			t.Errorf("test index %d: connect succeeded unexpectedly", i)
+		}
+	}
+}
+
+// TestConnectCancelled tests that Connect respects cancellation.
+func TestConnectCancelled(t *testing.T) {
+	// Install a failing plugin.
+	installFailingPlugin(t, "failing")
+
+	// Create a cancelled context.
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	// Attempt to connect.
+	url := &urlpkg.URL{
+		Kind:       urlpkg.Kind_Synchronization,
+		Protocol:   urlpkg.Protocol_Plugin,
+		Host:       "target",
+		Path:       "/project",
+		Parameters: map[string]string{urlpkg.PluginParameter: "failing"},
+	}
+	handler := &protocolHandler{}
+	if _, err := handler.Connect(
+		ctx, logging.NewLogger(logging.LevelDisabled, io.Discard), url, "", "session",
+		synchronization.Version_Version1, &synchronization.Configuration{}, true,
+	); err == nil {
+		t.Error("connect succeeded unexpectedly")
+	}
+}
+
+// TestConnectWrongProtocolPanics tests that Connect panics if dispatched a URL
+// with the wrong protocol.
+func TestConnectWrongProtocolPanics(t *testing.T) {
+	defer func() {
+		if recover() == nil {
+			t.Error("connect did not panic")
+		}
+	}()
+	url := &urlpkg.URL{
+		Kind:     urlpkg.Kind_Synchronization,
+		Protocol: urlpkg.Protocol_Docker,
+		Host:     "container",
+		Path:     "/path",
+	}
+	handler := &protocolHandler{}
+	handler.Connect(
+		context.Background(), logging.NewLogger(logging.LevelDisabled, io.Discard), url, "", "session",
+		synchronization.Version_Version1, &synchronization.Configuration{}, true,
+	)
+}
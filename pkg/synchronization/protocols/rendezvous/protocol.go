@@ -0,0 +1,56 @@
+package rendezvous
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/mutagen-io/mutagen/pkg/agent"
+	"github.com/mutagen-io/mutagen/pkg/logging"
+	"github.com/mutagen-io/mutagen/pkg/synchronization"
+	"github.com/mutagen-io/mutagen/pkg/synchronization/endpoint/remote"
+	urlpkg "github.com/mutagen-io/mutagen/pkg/url"
+)
+
+// protocolHandler implements the synchronization.ProtocolHandler interface for
+// connecting to remote endpoints served by agents that dial out to the daemon's
+// rendezvous listener.
+type protocolHandler struct{}
+
+// Connect connects to a rendezvous endpoint.
+func (h *protocolHandler) Connect(
+	ctx context.Context,
+	logger *logging.Logger,
+	url *urlpkg.URL,
+	prompter string,
+	session string,
+	version synchronization.Version,
+	configuration *synchronization.Configuration,
+	alpha bool,
+) (synchronization.Endpoint, error) {
+	// Verify that the URL is of the correct kind and protocol.
+	if url.Kind != urlpkg.Kind_Synchronization {
+		panic("non-synchronization URL dispatched to synchronization protocol handler")
+	} else if url.Protocol != urlpkg.Protocol_Rendezvous {
+		panic("non-rendezvous URL dispatched to rendezvous protocol handler")
+	}
+
+	// Load the pre-shared key.
+	key, err := agent.LoadTCPKey(url.Parameters[urlpkg.TCPKeyFileParameter])
+	if err != nil {
+		return nil, fmt.Errorf("unable to load pre-shared key: %w", err)
+	}
+
+	// Claim an agent connection, waiting for one if necessary.
+	stream, err := agent.DefaultRendezvous.Claim(ctx, url.Host, key, agent.CommandSynchronizer)
+	if err != nil {
+		return nil, fmt.Errorf("unable to claim agent connection: %w", err)
+	}
+
+	// Create the endpoint client.
+	return remote.NewEndpoint(logger, stream, url.Path, session, version, configuration, alpha)
+}
+
+func init() {
+	// Register the rendezvous protocol handler with the synchronization package.
+	synchronization.ProtocolHandlers[urlpkg.Protocol_Rendezvous] = &protocolHandler{}
+}
@@ -0,0 +1,3 @@
+// Package s3 provides the agentless S3-compatible object storage
+// synchronization session protocol implementation.
+package s3
@@ -0,0 +1,76 @@
+package s3
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+
+	"github.com/mutagen-io/mutagen/pkg/logging"
+	"github.com/mutagen-io/mutagen/pkg/s3"
+	"github.com/mutagen-io/mutagen/pkg/synchronization"
+	endpoint "github.com/mutagen-io/mutagen/pkg/synchronization/endpoint/s3"
+	urlpkg "github.com/mutagen-io/mutagen/pkg/url"
+)
+
+// protocolHandler implements the synchronization.ProtocolHandler interface for
+// connecting to S3-compatible object storage endpoints.
+type protocolHandler struct{}
+
+// Connect connects to an S3 endpoint.
+func (h *protocolHandler) Connect(
+	_ context.Context,
+	logger *logging.Logger,
+	url *urlpkg.URL,
+	_ string,
+	session string,
+	version synchronization.Version,
+	configuration *synchronization.Configuration,
+	alpha bool,
+) (synchronization.Endpoint, error) {
+	// Verify that the URL is of the correct kind and protocol.
+	if url.Kind != urlpkg.Kind_Synchronization {
+		panic("non-synchronization URL dispatched to synchronization protocol handler")
+	} else if url.Protocol != urlpkg.Protocol_S3 {
+		panic("non-S3 URL dispatched to S3 protocol handler")
+	}
+
+	// Ensure that no parameters are specified.
+	if len(url.Parameters) > 0 {
+		return nil, errors.New("S3 URL contains parameters")
+	}
+
+	// Load the client configuration, preferring environment variables captured
+	// in the URL over those in the daemon's environment.
+	clientConfiguration, err := s3.LoadConfiguration(func(name string) (string, bool) {
+		if value, ok := url.Environment[name]; ok {
+			return value, true
+		}
+		return os.LookupEnv(name)
+	})
+	if err != nil {
+		return nil, fmt.Errorf("unable to load S3 configuration: %w", err)
+	}
+
+	// Create the client.
+	client, err := s3.NewClient(url.Host, clientConfiguration)
+	if err != nil {
+		return nil, fmt.Errorf("unable to create S3 client: %w", err)
+	}
+
+	// Create the endpoint.
+	return endpoint.NewEndpoint(
+		logger,
+		client,
+		url.Path,
+		session,
+		version,
+		configuration,
+		alpha,
+	)
+}
+
+func init() {
+	// Register the S3 protocol handler with the synchronization package.
+	synchronization.ProtocolHandlers[urlpkg.Protocol_S3] = &protocolHandler{}
+}
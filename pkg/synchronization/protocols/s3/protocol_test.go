@@ -0,0 +1,111 @@
+package s3
+
+import (
+	"context"
+	"io"
+	"testing"
+
+	"github.com/mutagen-io/mutagen/pkg/logging"
+	"github.com/mutagen-io/mutagen/pkg/synchronization"
+	urlpkg "github.com/mutagen-io/mutagen/pkg/url"
+)
+
+// TestProtocolHandlerRegistration tests that the protocol handler is
+// registered for S3 URLs.
+func TestProtocolHandlerRegistration(t *testing.T) {
+	if _, ok := synchronization.ProtocolHandlers[urlpkg.Protocol_S3].(*protocolHandler); !ok {
+		t.Error("protocol handler not registered")
+	}
+}
+
+// TestConnect tests Connect with a variety of URLs and configurations.
+func TestConnect(t *testing.T) {
+	// Ensure that the daemon environment doesn't provide configuration.
+	t.Setenv("HOME", t.TempDir())
+	for _, name := range []string{
+		"AWS_ACCESS_KEY_ID", "AWS_SECRET_ACCESS_KEY", "AWS_SESSION_TOKEN",
+		"AWS_SHARED_CREDENTIALS_FILE", "AWS_PROFILE",
+	} {
+		t.Setenv(name, "")
+	}
+
+	// Set up test cases.
+	credentials := map[string]string{
+		"AWS_ACCESS_KEY_ID":     "id",
+		"AWS_SECRET_ACCESS_KEY": "secret",
+		"AWS_REGION":            "us-east-1",
+	}
+	testCases := []struct {
+		url           *urlpkg.URL
+		configuration *synchronization.Configuration
+		expectError   bool
+	}{
+		{
+			&urlpkg.URL{Host: "bucket", Path: "prefix", Environment: credentials},
+			&synchronization.Configuration{},
+			false,
+		},
+		{
+			&urlpkg.URL{Host: "bucket", Path: "prefix"},
+			&synchronization.Configuration{},
+			true,
+		},
+		{
+			&urlpkg.URL{Host: "bucket", Path: "prefix", Environment: map[string]string{"AWS_ACCESS_KEY_ID": "id"}},
+			&synchronization.Configuration{},
+			true,
+		},
+		{
+			&urlpkg.URL{Host: "bucket", Path: "prefix", Environment: credentials, Parameters: map[string]string{"key": "value"}},
+			&synchronization.Configuration{},
+			true,
+		},
+		{
+			&urlpkg.URL{Host: "bucket", Path: "prefix", Environment: credentials},
+			&synchronization.Configuration{VersionCount: 1},
+			true,
+		},
+	}
+
+	// Process test cases.
+	logger := logging.NewLogger(logging.LevelDisabled, io.Discard)
+	handler := &protocolHandler{}
+	for i, testCase := range testCases {
+		testCase.url.Kind = urlpkg.Kind_Synchronization
+		testCase.url.Protocol = urlpkg.Protocol_S3
+		endpoint, err := handler.Connect(
+			context.Background(), logger, testCase.url, "", "session",
+			synchronization.Version_Version1, testCase.configuration, true,
+		)
+		if err != nil {
+			if !testCase.expectError {
+				t.Errorf("test index %d: connect failed unexpectedly: %v", i, err)
+			}
+			continue
+		} else if testCase.expectError {
+			t.Errorf("test index %d: connect succeeded unexpectedly", i)
+		}
+		endpoint.Shutdown()
+	}
+}
+
+// TestConnectWrongProtocolPanics tests that Connect panics if dispatched a URL
+// with the wrong protocol.
+func TestConnectWrongProtocolPanics(t *testing.T) {
+	defer func() {
+		if recover() == nil {
+			t.Error("connect did not panic")
+		}
+	}()
+	url := &urlpkg.URL{
+		Kind:     urlpkg.Kind_Synchronization,
+		Protocol: urlpkg.Protocol_SFTP,
+		Host:     "host",
+		Path:     "/path",
+	}
+	handler := &protocolHandler{}
+	handler.Connect(
+		context.Background(), logging.NewLogger(logging.LevelDisabled, io.Discard), url, "", "session",
+		synchronization.Version_Version1, &synchronization.Configuration{}, true,
+	)
+}
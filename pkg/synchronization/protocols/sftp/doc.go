@@ -0,0 +1,3 @@
+// Package sftp provides the agentless SFTP synchronization session protocol
+// implementation.
+package sftp
@@ -0,0 +1,166 @@
+package sftp
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"strconv"
+	"time"
+
+	"golang.org/x/crypto/ssh"
+
+	"github.com/mutagen-io/mutagen/pkg/logging"
+	"github.com/mutagen-io/mutagen/pkg/prompting"
+	"github.com/mutagen-io/mutagen/pkg/sftp"
+	"github.com/mutagen-io/mutagen/pkg/ssh/native"
+	"github.com/mutagen-io/mutagen/pkg/synchronization"
+	endpoint "github.com/mutagen-io/mutagen/pkg/synchronization/endpoint/sftp"
+	urlpkg "github.com/mutagen-io/mutagen/pkg/url"
+)
+
+const (
+	// keepaliveInterval is the interval at which server liveness checks are
+	// performed.
+	keepaliveInterval = 10 * time.Second
+)
+
+var (
+	// connectTimeout is the timeout for establishing SSH connections. It uses
+	// the same environment variable override as the SSH agent transports.
+	connectTimeout = 5 * time.Second
+)
+
+func init() {
+	// If a valid connection timeout has been specified in the environment, then
+	// override the default connection timeout setting.
+	if t, err := strconv.ParseUint(os.Getenv("MUTAGEN_SSH_CONNECT_TIMEOUT"), 10, 64); err == nil && t > 0 {
+		connectTimeout = time.Duration(t) * time.Second
+	}
+}
+
+// protocolHandler implements the synchronization.ProtocolHandler interface for
+// connecting to agentless SFTP endpoints.
+type protocolHandler struct{}
+
+// dialResult provides asynchronous dialing results.
+type dialResult struct {
+	// sshClient is the SSH client returned by dialing.
+	sshClient *ssh.Client
+	// sftpClient is the SFTP client returned by dialing.
+	sftpClient *sftp.Client
+	// error is the error returned by dialing.
+	error error
+}
+
+// dial connects to the SSH server and starts an SFTP session.
+func dial(url *urlpkg.URL, prompter string) (*ssh.Client, *sftp.Client, error) {
+	// Set up the target.
+	target := &native.Target{
+		User: url.User,
+		Host: url.Host,
+		Port: uint16(url.Port),
+	}
+
+	// Set up connection options.
+	options := &native.Options{
+		ConnectTimeout:    connectTimeout,
+		KeepaliveInterval: keepaliveInterval,
+	}
+	if prompter != "" {
+		options.Prompter = func(message string) (string, error) {
+			return prompting.Prompt(prompter, message)
+		}
+	}
+
+	// Connect.
+	sshClient, err := native.Dial(target, options)
+	if err != nil {
+		return nil, nil, fmt.Errorf("unable to connect to %s: %w", target.Host, err)
+	}
+
+	// Start the SFTP session.
+	sftpClient, err := sftp.Dial(sshClient)
+	if err != nil {
+		sshClient.Close()
+		return nil, nil, fmt.Errorf("unable to start SFTP session: %w", err)
+	}
+
+	// Success.
+	return sshClient, sftpClient, nil
+}
+
+// Connect connects to an SFTP endpoint.
+func (h *protocolHandler) Connect(
+	ctx context.Context,
+	logger *logging.Logger,
+	url *urlpkg.URL,
+	prompter string,
+	session string,
+	version synchronization.Version,
+	configuration *synchronization.Configuration,
+	alpha bool,
+) (synchronization.Endpoint, error) {
+	// Verify that the URL is of the correct kind and protocol.
+	if url.Kind != urlpkg.Kind_Synchronization {
+		panic("non-synchronization URL dispatched to synchronization protocol handler")
+	} else if url.Protocol != urlpkg.Protocol_SFTP {
+		panic("non-SFTP URL dispatched to SFTP protocol handler")
+	}
+
+	// Ensure that no environment variables or parameters are specified. These
+	// are neither expected nor supported for SFTP URLs.
+	if len(url.Environment) > 0 {
+		return nil, errors.New("SFTP URL contains environment variables")
+	} else if len(url.Parameters) > 0 {
+		return nil, errors.New("SFTP URL contains parameters")
+	}
+
+	// Create a channel to deliver the dialing result.
+	results := make(chan dialResult)
+
+	// Perform dialing in a background Goroutine so that we can monitor for
+	// cancellation.
+	go func() {
+		// Perform the dialing operation.
+		sshClient, sftpClient, err := dial(url, prompter)
+
+		// Transmit the result or, if cancelled, close the clients.
+		select {
+		case results <- dialResult{sshClient, sftpClient, err}:
+		case <-ctx.Done():
+			if err == nil {
+				sftpClient.Close()
+				sshClient.Close()
+			}
+		}
+	}()
+
+	// Wait for dialing results or cancellation.
+	var result dialResult
+	select {
+	case result = <-results:
+		if result.error != nil {
+			return nil, fmt.Errorf("unable to dial SFTP endpoint: %w", result.error)
+		}
+	case <-ctx.Done():
+		return nil, errors.New("connect operation cancelled")
+	}
+
+	// Create the endpoint.
+	return endpoint.NewEndpoint(
+		logger,
+		result.sftpClient,
+		result.sshClient,
+		url.Path,
+		session,
+		version,
+		configuration,
+		alpha,
+	)
+}
+
+func init() {
+	// Register the SFTP protocol handler with the synchronization package.
+	synchronization.ProtocolHandlers[urlpkg.Protocol_SFTP] = &protocolHandler{}
+}
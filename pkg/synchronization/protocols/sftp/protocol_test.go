@@ -0,0 +1,142 @@
+package sftp
+
+import (
+	"context"
+	"io"
+	"net"
+	"testing"
+
+	"github.com/mutagen-io/mutagen/pkg/logging"
+	"github.com/mutagen-io/mutagen/pkg/synchronization"
+	urlpkg "github.com/mutagen-io/mutagen/pkg/url"
+)
+
+// TestProtocolHandlerRegistration tests that the protocol handler is
+// registered for SFTP URLs.
+func TestProtocolHandlerRegistration(t *testing.T) {
+	if _, ok := synchronization.ProtocolHandlers[urlpkg.Protocol_SFTP].(*protocolHandler); !ok {
+		t.Error("protocol handler not registered")
+	}
+}
+
+// TestConnectErrors tests that Connect rejects unsupported URL components and
+// fails if the server is unreachable.
+func TestConnectErrors(t *testing.T) {
+	// Avoid picking up any user SSH configuration.
+	t.Setenv("HOME", t.TempDir())
+
+	// Find a port with no listener.
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal("unable to create listener:", err)
+	}
+	port := uint32(listener.Addr().(*net.TCPAddr).Port)
+	listener.Close()
+
+	// Set up test cases.
+	testCases := []*urlpkg.URL{
+		{
+			Kind:        urlpkg.Kind_Synchronization,
+			Protocol:    urlpkg.Protocol_SFTP,
+			Host:        "127.0.0.1",
+			Port:        port,
+			Path:        "/project",
+			Environment: map[string]string{"KEY": "value"},
+		},
+		{
+			Kind:       urlpkg.Kind_Synchronization,
+			Protocol:   urlpkg.Protocol_SFTP,
+			Host:       "127.0.0.1",
+			Port:       port,
+			Path:       "/project",
+			Parameters: map[string]string{"key": "value"},
+		},
+		{
+			Kind:     urlpkg.Kind_Synchronization,
+			Protocol: urlpkg.Protocol_SFTP,
+			Host:     "127.0.0.1",
+			Port:     port,
+			Path:     "/project",
+		},
+	}
+
+	// Process test cases.
+	logger := logging.NewLogger(logging.LevelDisabled, io.Discard)
+	handler := &protocolHandler{}
+	for i, url := range testCases {
+		endpoint, err := handler.Connect(
+			context.Background(), logger, url, "", "session",
+			synchronization.Version_Version1, &synchronization.Configuration{}, true,
+		)
+		if err == nil {
+			endpoint.Shutdown()
+			t.Errorf("test index %d: connect succeeded unexpectedly", i)
+		}
+	}
+}
+
+// TestConnectCancelled tests that Connect respects cancellation.
+func TestConnectCancelled(t *testing.T) {
+	// Create a listener that accepts connections but never responds, ensuring
+	// that dialing blocks until cancellation.
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal("unable to create listener:", err)
+	}
+	defer listener.Close()
+	go func() {
+		var connections []net.Conn
+		for {
+			connection, err := listener.Accept()
+			if err != nil {
+				break
+			}
+			connections = append(connections, connection)
+		}
+		for _, connection := range connections {
+			connection.Close()
+		}
+	}()
+
+	// Create a cancelled context.
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	// Attempt to connect.
+	t.Setenv("HOME", t.TempDir())
+	url := &urlpkg.URL{
+		Kind:     urlpkg.Kind_Synchronization,
+		Protocol: urlpkg.Protocol_SFTP,
+		Host:     "127.0.0.1",
+		Port:     uint32(listener.Addr().(*net.TCPAddr).Port),
+		Path:     "/project",
+	}
+	handler := &protocolHandler{}
+	if _, err := handler.Connect(
+		ctx, logging.NewLogger(logging.LevelDisabled, io.Discard), url, "", "session",
+		synchronization.Version_Version1, &synchronization.Configuration{}, true,
+	); err == nil {
+		t.Error("connect succeeded unexpectedly")
+	}
+}
+
+// TestConnectWrongProtocolPanics tests that Connect panics if dispatched a URL
+// with the wrong protocol.
+func TestConnectWrongProtocolPanics(t *testing.T) {
+	defer func() {
+		if recover() == nil {
+			t.Error("connect did not panic")
+		}
+	}()
+	url := &urlpkg.URL{
+		Kind:     urlpkg.Kind_Synchronization,
+		Protocol: urlpkg.Protocol_SSH,
+		Host:     "host",
+		Path:     "/path",
+	}
+	handler := &protocolHandler{}
+	handler.Connect(
+		context.Background(), logging.NewLogger(logging.LevelDisabled, io.Discard), url, "", "session",
+		synchronization.Version_Version1, &synchronization.Configuration{}, true,
+	)
+}
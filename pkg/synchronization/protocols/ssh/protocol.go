@@ -45,16 +45,23 @@ func (h *protocolHandler) Connect(
 		panic("non-SSH URL dispatched to SSH protocol handler")
 	}
 
-	// Ensure that no environment variables or parameters are specified. These
-	// are neither expected nor supported for SSH URLs.
+	// Ensure that no environment variables are specified. These are neither
+	// expected nor supported for SSH URLs.
 	if len(url.Environment) > 0 {
 		return nil, errors.New("SSH URL contains environment variables")
-	} else if len(url.Parameters) > 0 {
-		return nil, errors.New("SSH URL contains internal parameters")
 	}
 
-	// Create an SSH agent transport.
-	transport, err := ssh.NewTransport(url.User, url.Host, uint16(url.Port), prompter)
+	// Determine whether or not SSH agent forwarding has been requested.
+	forwardAgent := url.Parameters[urlpkg.SSHForwardAgentParameter] == "true"
+
+	// Create an SSH agent transport using the selected SSH client.
+	var transport agent.Transport
+	var err error
+	if url.Parameters[urlpkg.SSHClientParameter] == urlpkg.SSHClientNative {
+		transport, err = ssh.NewNativeTransport(url.User, url.Host, uint16(url.Port), forwardAgent, prompter)
+	} else {
+		transport, err = ssh.NewTransport(url.User, url.Host, uint16(url.Port), forwardAgent, prompter)
+	}
 	if err != nil {
 		return nil, fmt.Errorf("unable to create SSH transport: %w", err)
 	}
@@ -66,7 +73,7 @@ func (h *protocolHandler) Connect(
 	// cancellation.
 	go func() {
 		// Perform the dialing operation.
-		stream, err := agent.Dial(logger, transport, agent.CommandSynchronizer, prompter)
+		stream, err := agent.DialShared(logger, url, transport, agent.CommandSynchronizer, configuration.AgentNiceness, prompter, url.Parameters[urlpkg.AgentDirectoryParameter])
 
 		// Transmit the result or, if cancelled, close the stream.
 		select {
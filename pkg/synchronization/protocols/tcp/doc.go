@@ -0,0 +1,3 @@
+// Package tcp provides the TCP synchronization session protocol implementation,
+// which connects to agents listening on a TCP port.
+package tcp
@@ -0,0 +1,58 @@
+package tcp
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"strconv"
+
+	"github.com/mutagen-io/mutagen/pkg/agent"
+	"github.com/mutagen-io/mutagen/pkg/logging"
+	"github.com/mutagen-io/mutagen/pkg/synchronization"
+	"github.com/mutagen-io/mutagen/pkg/synchronization/endpoint/remote"
+	urlpkg "github.com/mutagen-io/mutagen/pkg/url"
+)
+
+// protocolHandler implements the synchronization.ProtocolHandler interface for
+// connecting to remote endpoints served by agents listening on a TCP port.
+type protocolHandler struct{}
+
+// Connect connects to a TCP endpoint.
+func (h *protocolHandler) Connect(
+	ctx context.Context,
+	logger *logging.Logger,
+	url *urlpkg.URL,
+	prompter string,
+	session string,
+	version synchronization.Version,
+	configuration *synchronization.Configuration,
+	alpha bool,
+) (synchronization.Endpoint, error) {
+	// Verify that the URL is of the correct kind and protocol.
+	if url.Kind != urlpkg.Kind_Synchronization {
+		panic("non-synchronization URL dispatched to synchronization protocol handler")
+	} else if url.Protocol != urlpkg.Protocol_TCP {
+		panic("non-TCP URL dispatched to TCP protocol handler")
+	}
+
+	// Load the pre-shared key.
+	key, err := agent.LoadTCPKey(url.Parameters[urlpkg.TCPKeyFileParameter])
+	if err != nil {
+		return nil, fmt.Errorf("unable to load pre-shared key: %w", err)
+	}
+
+	// Dial the agent.
+	address := net.JoinHostPort(url.Host, strconv.FormatUint(uint64(url.Port), 10))
+	stream, err := agent.DialTCP(ctx, address, key, agent.CommandSynchronizer)
+	if err != nil {
+		return nil, fmt.Errorf("unable to dial agent endpoint: %w", err)
+	}
+
+	// Create the endpoint client.
+	return remote.NewEndpoint(logger, stream, url.Path, session, version, configuration, alpha)
+}
+
+func init() {
+	// Register the TCP protocol handler with the synchronization package.
+	synchronization.ProtocolHandlers[urlpkg.Protocol_TCP] = &protocolHandler{}
+}
@@ -0,0 +1,3 @@
+// Package teleport provides the Teleport node synchronization session
+// protocol implementation.
+package teleport
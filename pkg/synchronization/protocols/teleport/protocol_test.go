@@ -0,0 +1,165 @@
+package teleport
+
+import (
+	"context"
+	"io"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"testing"
+
+	"github.com/mutagen-io/mutagen/pkg/logging"
+	"github.com/mutagen-io/mutagen/pkg/synchronization"
+	urlpkg "github.com/mutagen-io/mutagen/pkg/url"
+)
+
+// fakeTSH is a tsh stand-in that logs its arguments to the file specified by
+// the FAKE_TSH_LOG environment variable. It reports a valid session, and its
+// ssh command fails with the exit code and error output specified by the
+// FAKE_TSH_EXIT and FAKE_TSH_ERROR environment variables when invoking an
+// agent. All other remote commands succeed without output.
+const fakeTSH = `#!/bin/sh
+echo "$*" >> "$FAKE_TSH_LOG"
+case "$1" in
+status)
+	exit 0
+	;;
+ssh)
+	case "$3" in
+	*mutagen-agent*)
+		echo "$FAKE_TSH_ERROR" >&2
+		exit "$FAKE_TSH_EXIT"
+		;;
+	esac
+	exit 0
+	;;
+esac
+exit 1
+`
+
+// TestConnect tests the tsh invocations performed by Connect for Teleport URLs
+// and the handling of agent invocation failures.
+func TestConnect(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip()
+	}
+
+	// Install the fake tsh.
+	directory := t.TempDir()
+	if err := os.WriteFile(filepath.Join(directory, "tsh"), []byte(fakeTSH), 0700); err != nil {
+		t.Fatal("unable to create fake tsh:", err)
+	}
+	t.Setenv("MUTAGEN_TELEPORT_PATH", directory)
+
+	// Set up test cases.
+	testCases := []struct {
+		// raw is the raw URL.
+		raw string
+		// exitCode is the exit code for agent invocations.
+		exitCode string
+		// errorOutput is the error output for agent invocations.
+		errorOutput string
+		// expectedTarget is the expected tsh target specification.
+		expectedTarget string
+		// expectInstall indicates whether or not an agent installation should
+		// be attempted.
+		expectInstall bool
+		// expectedError is a substring of the expected error.
+		expectedError string
+	}{
+		{
+			raw:            "teleport://node-a/~/project",
+			exitCode:       "255",
+			errorOutput:    "ssh: connect to host node-a: Connection refused",
+			expectedTarget: "node-a",
+			expectedError:  "unable to handshake with agent process",
+		},
+		{
+			raw:            "teleport://alice@node-b/~/project",
+			exitCode:       "255",
+			errorOutput:    "ssh: connect to host node-b: Connection refused",
+			expectedTarget: "alice@node-b",
+			expectedError:  "unable to handshake with agent process",
+		},
+		{
+			raw:            "teleport://alice@node-c/~/project",
+			exitCode:       "127",
+			errorOutput:    "sh: mutagen-agent: command not found",
+			expectedTarget: "alice@node-c",
+			expectInstall:  true,
+			expectedError:  "unable to install agent",
+		},
+	}
+
+	// Process test cases.
+	logger := logging.NewLogger(logging.LevelDisabled, io.Discard)
+	handler := &protocolHandler{}
+	for i, testCase := range testCases {
+		// Set up the fake tsh behavior.
+		log := filepath.Join(t.TempDir(), "log")
+		t.Setenv("FAKE_TSH_LOG", log)
+		t.Setenv("FAKE_TSH_EXIT", testCase.exitCode)
+		t.Setenv("FAKE_TSH_ERROR", testCase.errorOutput)
+
+		// Parse the URL and attempt to connect.
+		url, err := urlpkg.Parse(testCase.raw, urlpkg.Kind_Synchronization, true)
+		if err != nil {
+			t.Fatalf("test index %d: unable to parse URL: %v", i, err)
+		}
+		endpoint, err := handler.Connect(
+			context.Background(), logger, url, "", "session",
+			synchronization.Version_Version1, &synchronization.Configuration{}, true,
+		)
+		if err == nil {
+			endpoint.Shutdown()
+			t.Fatalf("test index %d: connect succeeded unexpectedly", i)
+		} else if !strings.Contains(err.Error(), testCase.expectedError) {
+			t.Errorf("test index %d: error does not match expected: %v", i, err)
+		}
+
+		// Verify the tsh invocations.
+		contents, err := os.ReadFile(log)
+		if err != nil {
+			t.Fatalf("test index %d: unable to read invocation log: %v", i, err)
+		}
+		invocations := strings.Split(strings.TrimSpace(string(contents)), "\n")
+		if len(invocations) < 2 {
+			t.Fatalf("test index %d: too few invocations: %v", i, invocations)
+		} else if invocations[0] != "status" {
+			t.Errorf("test index %d: session status not checked first: %s", i, invocations[0])
+		}
+		agentPrefix := "ssh " + testCase.expectedTarget + " "
+		if !strings.HasPrefix(invocations[1], agentPrefix) || !strings.Contains(invocations[1], " multiplexer ") {
+			t.Errorf("test index %d: unexpected agent invocation: %s", i, invocations[1])
+		}
+		if testCase.expectInstall {
+			if len(invocations) < 3 || invocations[2] != agentPrefix+"uname -s -m" {
+				t.Errorf("test index %d: platform probe not performed: %v", i, invocations)
+			}
+		} else if len(invocations) != 2 {
+			t.Errorf("test index %d: unexpected additional invocations: %v", i, invocations[2:])
+		}
+	}
+}
+
+// TestConnectWrongProtocolPanics tests that Connect panics if dispatched a URL
+// with the wrong protocol.
+func TestConnectWrongProtocolPanics(t *testing.T) {
+	defer func() {
+		if recover() == nil {
+			t.Error("connect did not panic")
+		}
+	}()
+	url := &urlpkg.URL{
+		Kind:     urlpkg.Kind_Synchronization,
+		Protocol: urlpkg.Protocol_Docker,
+		Host:     "host",
+		Path:     "/path",
+	}
+	handler := &protocolHandler{}
+	handler.Connect(
+		context.Background(), logging.NewLogger(logging.LevelDisabled, io.Discard), url, "", "session",
+		synchronization.Version_Version1, &synchronization.Configuration{}, true,
+	)
+}
@@ -0,0 +1,66 @@
+package wsl
+
+import (
+	"context"
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/mutagen-io/mutagen/pkg/logging"
+	"github.com/mutagen-io/mutagen/pkg/synchronization"
+	urlpkg "github.com/mutagen-io/mutagen/pkg/url"
+)
+
+// TestConnectMissingWSL tests that Connect reports a missing WSL command without
+// attempting an agent installation.
+func TestConnectMissingWSL(t *testing.T) {
+	// Ensure that the WSL command can't be found. On non-Windows platforms,
+	// this is always the case.
+	t.Setenv("PATH", t.TempDir())
+	t.Setenv("SystemRoot", "")
+
+	// Process test cases.
+	logger := logging.NewLogger(logging.LevelDisabled, io.Discard)
+	handler := &protocolHandler{}
+	for i, raw := range []string{"wsl://Ubuntu/~/project", "wsl://alice@Ubuntu/~/project"} {
+		url, err := urlpkg.Parse(raw, urlpkg.Kind_Synchronization, true)
+		if err != nil {
+			t.Fatalf("test index %d: unable to parse URL: %v", i, err)
+		}
+		endpoint, err := handler.Connect(
+			context.Background(), logger, url, "", "session",
+			synchronization.Version_Version1, &synchronization.Configuration{}, true,
+		)
+		if err == nil {
+			endpoint.Shutdown()
+			t.Errorf("test index %d: connect succeeded unexpectedly", i)
+		} else if !strings.Contains(err.Error(), "unable to create agent command") {
+			t.Errorf("test index %d: error does not describe command creation failure: %v", i, err)
+		} else if !strings.Contains(err.Error(), "'wsl' command") {
+			t.Errorf("test index %d: error does not identify WSL command: %v", i, err)
+		} else if strings.Contains(err.Error(), "install") {
+			t.Errorf("test index %d: agent installation attempted: %v", i, err)
+		}
+	}
+}
+
+// TestConnectWrongProtocolPanics tests that Connect panics if dispatched a URL
+// with the wrong protocol.
+func TestConnectWrongProtocolPanics(t *testing.T) {
+	defer func() {
+		if recover() == nil {
+			t.Error("connect did not panic")
+		}
+	}()
+	url := &urlpkg.URL{
+		Kind:     urlpkg.Kind_Synchronization,
+		Protocol: urlpkg.Protocol_Docker,
+		Host:     "host",
+		Path:     "/path",
+	}
+	handler := &protocolHandler{}
+	handler.Connect(
+		context.Background(), logging.NewLogger(logging.LevelDisabled, io.Discard), url, "", "session",
+		synchronization.Version_Version1, &synchronization.Configuration{}, true,
+	)
+}
@@ -0,0 +1,23 @@
+package synchronization
+
+import (
+	"errors"
+)
+
+// EnsureValid ensures that RepairResult's invariants are respected.
+func (r *RepairResult) EnsureValid() error {
+	// A nil repair result is not valid.
+	if r == nil {
+		return errors.New("nil repair result")
+	}
+
+	// Ensure that a session identifier is present.
+	if r.Session == "" {
+		return errors.New("empty session identifier")
+	}
+
+	// Repair paths don't need to be validated - any value is valid.
+
+	// Success.
+	return nil
+}
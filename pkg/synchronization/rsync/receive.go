@@ -320,10 +320,11 @@ func (r *monitoringReceiver) Receive(transmission *Transmission) error {
 	}
 
 	// Compute the amount of data contained in this transmission.
-	var dataSize uint64
+	var dataSize, literalSize uint64
 	if !transmission.Done {
 		if d := len(transmission.Operation.Data); d > 0 {
 			dataSize = uint64(d)
+			literalSize = dataSize
 		} else {
 			signature := r.signatures[r.state.ReceivedFiles]
 			if transmission.Operation.Start+transmission.Operation.Count == uint64(len(signature.Hashes)) {
@@ -338,6 +339,7 @@ func (r *monitoringReceiver) Receive(transmission *Transmission) error {
 	// Update received data statistics.
 	r.state.ReceivedSize += dataSize
 	r.state.TotalReceivedSize += dataSize
+	r.state.TotalDataSize += literalSize
 
 	// Provide the updated state to the monitor if relevant.
 	if !transmission.Done || r.startOfFile {
@@ -3,27 +3,58 @@ package rsync
 import (
 	"errors"
 	"fmt"
+	"io"
 
 	"github.com/mutagen-io/mutagen/pkg/filesystem"
 )
 
+// Source provides the interface for a transmitter to read outgoing files.
+type Source interface {
+	// Open should return a new io.ReadCloser for the given path, along with
+	// the size of the file. Each result it returns will be closed before Open
+	// is invoked again.
+	Open(path string) (io.ReadCloser, uint64, error)
+}
+
+// filesystemSource is a Source implementation that reads files from disk.
+type filesystemSource struct {
+	// opener is the underlying file opener.
+	opener *filesystem.Opener
+}
+
+// Open implements Source.Open.
+func (s *filesystemSource) Open(path string) (io.ReadCloser, uint64, error) {
+	file, metadata, err := s.opener.OpenFile(path)
+	if err != nil {
+		return nil, 0, err
+	}
+	return file, metadata.Size, nil
+}
+
 // Transmit performs streaming transmission of files (in rsync deltified form)
 // to the specified receiver. It is the responsibility of the caller to ensure
 // that the provided signatures are valid by invoking their EnsureValid method.
 // In order for this function to perform efficiently, paths should be passed in
 // depth-first traversal order.
 func Transmit(root string, paths []string, signatures []*Signature, receiver Receiver) error {
+	// Create a file opener that we can use to safely open files, and defer its
+	// closure.
+	opener := filesystem.NewOpener(root)
+	defer opener.Close()
+
+	// Perform transmission.
+	return TransmitFromSource(&filesystemSource{opener}, paths, signatures, receiver)
+}
+
+// TransmitFromSource is the same as Transmit, but it reads files from the
+// specified source rather than from disk.
+func TransmitFromSource(source Source, paths []string, signatures []*Signature, receiver Receiver) error {
 	// Ensure that the transmission request is sane.
 	if len(paths) != len(signatures) {
 		receiver.finalize()
 		return errors.New("number of paths does not match number of signatures")
 	}
 
-	// Create a file opener that we can use to safely open files, and defer its
-	// closure.
-	opener := filesystem.NewOpener(root)
-	defer opener.Close()
-
 	// Create an rsync engine.
 	engine := NewEngine()
 
@@ -35,7 +66,7 @@ func Transmit(root string, paths []string, signatures []*Signature, receiver Rec
 		// Open the file and extract its size. Failure here is non-terminal, but
 		// we need to inform the receiver. If sending the message fails, that is
 		// a terminal error.
-		file, metadata, err := opener.OpenFile(p)
+		file, fileSize, err := source.Open(p)
 		if err != nil {
 			*transmission = Transmission{
 				Done:  true,
@@ -47,7 +78,6 @@ func Transmit(root string, paths []string, signatures []*Signature, receiver Rec
 			}
 			continue
 		}
-		fileSize := metadata.Size
 
 		// Create an operation transmitter for deltification and track reception
 		// errors. We can safely set transmitError on each call because as soon
@@ -64,6 +64,54 @@ func containsRootTypeChange(changes []*core.Change) bool {
 	return false
 }
 
+// deletedEntryCount computes the number of synchronizable entries within the
+// old hierarchy that aren't present (with the same kind) in the new hierarchy.
+func deletedEntryCount(old, new *core.Entry) uint64 {
+	// If there's no old content, then nothing can have been deleted.
+	if old == nil {
+		return 0
+	}
+
+	// If the new content is absent or of a different kind, then the entire old
+	// hierarchy has been deleted.
+	if new == nil || new.Kind != old.Kind {
+		return old.Count()
+	}
+
+	// Otherwise, count deletions within the contents.
+	var result uint64
+	for name, child := range old.Contents {
+		result += deletedEntryCount(child, new.Contents[name])
+	}
+
+	// Done.
+	return result
+}
+
+// exceedsDeletionThreshold determines whether or not the specified changes
+// would delete more than the specified percentage of the entries in the
+// ancestor. A threshold of 100 or more never triggers.
+func exceedsDeletionThreshold(ancestor *core.Entry, changes []*core.Change, threshold uint32) bool {
+	// If the threshold is disabled or there's no ancestor content, then this
+	// check doesn't apply.
+	if threshold >= 100 {
+		return false
+	}
+	total := ancestor.Count()
+	if total == 0 {
+		return false
+	}
+
+	// Count the number of entries being deleted.
+	var deleted uint64
+	for _, change := range changes {
+		deleted += deletedEntryCount(change.Old, change.New)
+	}
+
+	// Compare the deleted fraction against the threshold.
+	return deleted*100 > uint64(threshold)*total
+}
+
 // filteredPathsAreSubset checks whether or not a slice of filtered paths is a
 // subset of a larger slice of unfiltered paths. The paths in the filtered slice
 // must share the same relative ordering as in the original slice.
@@ -2,6 +2,8 @@ package synchronization
 
 import (
 	"testing"
+
+	"github.com/mutagen-io/mutagen/pkg/synchronization/core"
 )
 
 // TODO: Implement tests for additional functions.
@@ -45,3 +47,53 @@ func TestFilteredPathsAreSubset(t *testing.T) {
 		}
 	}
 }
+
+// TestExceedsDeletionThreshold tests that exceedsDeletionThreshold returns a
+// correct assessment for a variety of test cases.
+func TestExceedsDeletionThreshold(t *testing.T) {
+	// Create test content.
+	file := &core.Entry{Kind: core.EntryKind_File, Digest: []byte{0}}
+	directory := &core.Entry{
+		Kind: core.EntryKind_Directory,
+		Contents: map[string]*core.Entry{
+			"a": file,
+			"b": file,
+			"c": file,
+		},
+	}
+
+	// Set up test cases.
+	testCases := []struct {
+		ancestor  *core.Entry
+		changes   []*core.Change
+		threshold uint32
+		expected  bool
+	}{
+		{nil, nil, 0, false},
+		{directory, nil, 0, false},
+		{directory, []*core.Change{{Path: "a", Old: file}}, 100, false},
+		{directory, []*core.Change{{Path: "a", Old: file}}, 25, false},
+		{directory, []*core.Change{{Path: "a", Old: file}}, 24, true},
+		{directory, []*core.Change{{Path: "a", Old: file}, {Path: "b", Old: file}}, 50, false},
+		{directory, []*core.Change{{Path: "a", Old: file}, {Path: "b", Old: file}}, 49, true},
+		{directory, []*core.Change{{Path: "a", Old: file, New: file}}, 0, false},
+		{directory, []*core.Change{{Old: directory, New: &core.Entry{Kind: core.EntryKind_Directory}}}, 74, true},
+		{directory, []*core.Change{{Old: directory, New: file}}, 99, true},
+	}
+
+	// Run test cases.
+	for c, testCase := range testCases {
+		if result := exceedsDeletionThreshold(
+			testCase.ancestor,
+			testCase.changes,
+			testCase.threshold,
+		); result != testCase.expected {
+			t.Errorf(
+				"result did not match expected for test case %d: %t != %t",
+				c,
+				result,
+				testCase.expected,
+			)
+		}
+	}
+}
@@ -36,11 +36,30 @@ func (s Status) Description() string {
 		return "Applying changes"
 	case Status_Saving:
 		return "Saving archive"
+	case Status_HaltedOnDeletionThreshold:
+		return "Halted due to deletion threshold"
 	default:
 		return "Unknown"
 	}
 }
 
+// Halted returns whether or not the status indicates that the session has been
+// halted by a safety check.
+func (s Status) Halted() bool {
+	switch s {
+	case Status_HaltedOnRootEmptied:
+		return true
+	case Status_HaltedOnRootDeletion:
+		return true
+	case Status_HaltedOnRootTypeChange:
+		return true
+	case Status_HaltedOnDeletionThreshold:
+		return true
+	default:
+		return false
+	}
+}
+
 // MarshalText implements encoding.TextMarshaler.MarshalText.
 func (s Status) MarshalText() ([]byte, error) {
 	var result string
@@ -73,6 +92,8 @@ func (s Status) MarshalText() ([]byte, error) {
 		result = "transitioning"
 	case Status_Saving:
 		result = "saving"
+	case Status_HaltedOnDeletionThreshold:
+		result = "halted-on-deletion-threshold"
 	default:
 		result = "unknown"
 	}
@@ -91,25 +112,22 @@ func (s *EndpointState) ensureValid() error {
 	// we're really concerned about here is memory safety and other structural
 	// invariants.
 
-	// Ensure that all scan problems are valid and truncation is sane.
+	// Ensure that all scan problems are valid. Because listings can be
+	// paginated, excluded scan problems may be reported even if no scan
+	// problems are reported.
 	for _, p := range s.ScanProblems {
 		if err := p.EnsureValid(); err != nil {
 			return fmt.Errorf("invalid scan problem detected: %w", err)
 		}
 	}
-	if s.ExcludedScanProblems > 0 && len(s.ScanProblems) == 0 {
-		return errors.New("excluded scan problems reported with no scan problems reported")
-	}
 
-	// Ensure that all transition problems are valid and truncation is sane.
+	// Ensure that all transition problems are valid. As with scan problems,
+	// exclusions may be reported without any transition problems.
 	for _, p := range s.TransitionProblems {
 		if err := p.EnsureValid(); err != nil {
 			return fmt.Errorf("invalid transition problem detected: %w", err)
 		}
 	}
-	if s.ExcludedTransitionProblems > 0 && len(s.TransitionProblems) == 0 {
-		return errors.New("excluded transition problems reported with no transition problems reported")
-	}
 
 	// Ensure that staging progress is valid.
 	if err := s.StagingProgress.EnsureValid(); err != nil {
@@ -136,15 +154,16 @@ func (s *State) EnsureValid() error {
 		return fmt.Errorf("invalid session: %w", err)
 	}
 
-	// Ensure that all conflicts are valid and truncation is sane.
+	// Ensure that all conflicts are valid. Because listings can be paginated,
+	// excluded conflicts may be reported even if no conflicts are reported.
 	for _, c := range s.Conflicts {
 		if err := c.EnsureValid(); err != nil {
 			return fmt.Errorf("invalid conflict detected: %w", err)
 		}
 	}
-	if s.ExcludedConflicts > 0 && len(s.Conflicts) == 0 {
-		return errors.New("excluded conflicts reported with no conflicts reported")
-	}
+
+	// Mirror violation paths don't need to be validated - any value is valid.
+	// As with conflicts, exclusions may be reported without any violations.
 
 	// Ensure that endpoint states are valid.
 	if err := s.AlphaState.ensureValid(); err != nil {
@@ -153,6 +172,11 @@ func (s *State) EnsureValid() error {
 		return fmt.Errorf("invalid beta endpoint state: %w", err)
 	}
 
+	// Ensure that statistics are valid.
+	if err := s.Statistics.EnsureValid(); err != nil {
+		return fmt.Errorf("invalid statistics: %w", err)
+	}
+
 	// Success.
 	return nil
 }
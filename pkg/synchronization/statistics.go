@@ -0,0 +1,63 @@
+package synchronization
+
+import (
+	"errors"
+)
+
+// accumulate adds the statistics from other to the receiver.
+func (s *CycleStatistics) accumulate(other *CycleStatistics) {
+	s.Duration += other.Duration
+	s.StagedFiles += other.StagedFiles
+	s.StagedSize += other.StagedSize
+	s.TransferredSize += other.TransferredSize
+}
+
+// DeltaCompressionRatio returns the ratio of staged data size to transferred
+// data size, i.e. the factor by which delta transfers reduced the volume of
+// data that needed to be transmitted. If no data was staged, or if all staged
+// data was reconstructed without transferring any literal data, then the ratio
+// is undefined and false is returned.
+func (s *CycleStatistics) DeltaCompressionRatio() (float64, bool) {
+	if s.StagedSize == 0 || s.TransferredSize == 0 {
+		return 0, false
+	}
+	return float64(s.StagedSize) / float64(s.TransferredSize), true
+}
+
+// record incorporates the statistics from a successful synchronization cycle.
+func (s *SessionStatistics) record(cycle *CycleStatistics) {
+	s.Cycles++
+	s.LastCycle = cycle
+	if s.Total == nil {
+		s.Total = &CycleStatistics{}
+	}
+	s.Total.accumulate(cycle)
+}
+
+// EnsureValid ensures that SessionStatistics' invariants are respected.
+func (s *SessionStatistics) EnsureValid() error {
+	// A nil set of statistics is considered valid since statistics are an
+	// optional component of session state.
+	if s == nil {
+		return nil
+	}
+
+	// Ensure that per-cycle statistics are present if and only if cycles have
+	// been recorded.
+	if s.Cycles == 0 {
+		if s.LastCycle != nil {
+			return errors.New("last cycle statistics present without cycles")
+		} else if s.Total != nil {
+			return errors.New("total statistics present without cycles")
+		}
+	} else {
+		if s.LastCycle == nil {
+			return errors.New("missing last cycle statistics")
+		} else if s.Total == nil {
+			return errors.New("missing total statistics")
+		}
+	}
+
+	// Success.
+	return nil
+}
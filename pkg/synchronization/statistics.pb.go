@@ -0,0 +1,284 @@
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// versions:
+// 	protoc-gen-go v1.28.0
+// 	protoc        v3.19.4
+// source: synchronization/statistics.proto
+
+package synchronization
+
+import (
+	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
+	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	reflect "reflect"
+	sync "sync"
+)
+
+const (
+	// Verify that this generated code is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(20 - protoimpl.MinVersion)
+	// Verify that runtime/protoimpl is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
+)
+
+// CycleStatistics encodes statistics for one or more synchronization cycles.
+type CycleStatistics struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	// Duration is the time (in nanoseconds) spent performing the cycle(s),
+	// measured from the start of scanning to the completion of history saving.
+	Duration uint64 `protobuf:"varint,1,opt,name=duration,proto3" json:"duration,omitempty"`
+	// StagedFiles is the number of files staged by the cycle(s) across both
+	// endpoints.
+	StagedFiles uint64 `protobuf:"varint,2,opt,name=stagedFiles,proto3" json:"stagedFiles,omitempty"`
+	// StagedSize is the total size of the files staged by the cycle(s) across
+	// both endpoints.
+	StagedSize uint64 `protobuf:"varint,3,opt,name=stagedSize,proto3" json:"stagedSize,omitempty"`
+	// TransferredSize is the number of bytes of literal file data transferred
+	// to stage files during the cycle(s). The ratio of StagedSize to this value
+	// indicates the savings offered by delta transfers.
+	TransferredSize uint64 `protobuf:"varint,4,opt,name=transferredSize,proto3" json:"transferredSize,omitempty"`
+}
+
+func (x *CycleStatistics) Reset() {
+	*x = CycleStatistics{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_synchronization_statistics_proto_msgTypes[0]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *CycleStatistics) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CycleStatistics) ProtoMessage() {}
+
+func (x *CycleStatistics) ProtoReflect() protoreflect.Message {
+	mi := &file_synchronization_statistics_proto_msgTypes[0]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CycleStatistics.ProtoReflect.Descriptor instead.
+func (*CycleStatistics) Descriptor() ([]byte, []int) {
+	return file_synchronization_statistics_proto_rawDescGZIP(), []int{0}
+}
+
+func (x *CycleStatistics) GetDuration() uint64 {
+	if x != nil {
+		return x.Duration
+	}
+	return 0
+}
+
+func (x *CycleStatistics) GetStagedFiles() uint64 {
+	if x != nil {
+		return x.StagedFiles
+	}
+	return 0
+}
+
+func (x *CycleStatistics) GetStagedSize() uint64 {
+	if x != nil {
+		return x.StagedSize
+	}
+	return 0
+}
+
+func (x *CycleStatistics) GetTransferredSize() uint64 {
+	if x != nil {
+		return x.TransferredSize
+	}
+	return 0
+}
+
+// SessionStatistics encodes synchronization statistics accumulated by a
+// session since it was loaded or created by the daemon.
+type SessionStatistics struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	// Cycles is the number of successful synchronization cycles that have been
+	// performed.
+	Cycles uint64 `protobuf:"varint,1,opt,name=cycles,proto3" json:"cycles,omitempty"`
+	// LastCycle contains statistics for the most recent successful
+	// synchronization cycle. It is nil if no cycles have been performed.
+	LastCycle *CycleStatistics `protobuf:"bytes,2,opt,name=lastCycle,proto3" json:"lastCycle,omitempty"`
+	// Total contains statistics accumulated across all successful
+	// synchronization cycles. It is nil if no cycles have been performed.
+	Total *CycleStatistics `protobuf:"bytes,3,opt,name=total,proto3" json:"total,omitempty"`
+}
+
+func (x *SessionStatistics) Reset() {
+	*x = SessionStatistics{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_synchronization_statistics_proto_msgTypes[1]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *SessionStatistics) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SessionStatistics) ProtoMessage() {}
+
+func (x *SessionStatistics) ProtoReflect() protoreflect.Message {
+	mi := &file_synchronization_statistics_proto_msgTypes[1]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SessionStatistics.ProtoReflect.Descriptor instead.
+func (*SessionStatistics) Descriptor() ([]byte, []int) {
+	return file_synchronization_statistics_proto_rawDescGZIP(), []int{1}
+}
+
+func (x *SessionStatistics) GetCycles() uint64 {
+	if x != nil {
+		return x.Cycles
+	}
+	return 0
+}
+
+func (x *SessionStatistics) GetLastCycle() *CycleStatistics {
+	if x != nil {
+		return x.LastCycle
+	}
+	return nil
+}
+
+func (x *SessionStatistics) GetTotal() *CycleStatistics {
+	if x != nil {
+		return x.Total
+	}
+	return nil
+}
+
+var File_synchronization_statistics_proto protoreflect.FileDescriptor
+
+var file_synchronization_statistics_proto_rawDesc = []byte{
+	0x0a, 0x20, 0x73, 0x79, 0x6e, 0x63, 0x68, 0x72, 0x6f, 0x6e, 0x69, 0x7a, 0x61, 0x74, 0x69, 0x6f,
+	0x6e, 0x2f, 0x73, 0x74, 0x61, 0x74, 0x69, 0x73, 0x74, 0x69, 0x63, 0x73, 0x2e, 0x70, 0x72, 0x6f,
+	0x74, 0x6f, 0x12, 0x0f, 0x73, 0x79, 0x6e, 0x63, 0x68, 0x72, 0x6f, 0x6e, 0x69, 0x7a, 0x61, 0x74,
+	0x69, 0x6f, 0x6e, 0x22, 0x99, 0x01, 0x0a, 0x0f, 0x43, 0x79, 0x63, 0x6c, 0x65, 0x53, 0x74, 0x61,
+	0x74, 0x69, 0x73, 0x74, 0x69, 0x63, 0x73, 0x12, 0x1a, 0x0a, 0x08, 0x64, 0x75, 0x72, 0x61, 0x74,
+	0x69, 0x6f, 0x6e, 0x18, 0x01, 0x20, 0x01, 0x28, 0x04, 0x52, 0x08, 0x64, 0x75, 0x72, 0x61, 0x74,
+	0x69, 0x6f, 0x6e, 0x12, 0x20, 0x0a, 0x0b, 0x73, 0x74, 0x61, 0x67, 0x65, 0x64, 0x46, 0x69, 0x6c,
+	0x65, 0x73, 0x18, 0x02, 0x20, 0x01, 0x28, 0x04, 0x52, 0x0b, 0x73, 0x74, 0x61, 0x67, 0x65, 0x64,
+	0x46, 0x69, 0x6c, 0x65, 0x73, 0x12, 0x1e, 0x0a, 0x0a, 0x73, 0x74, 0x61, 0x67, 0x65, 0x64, 0x53,
+	0x69, 0x7a, 0x65, 0x18, 0x03, 0x20, 0x01, 0x28, 0x04, 0x52, 0x0a, 0x73, 0x74, 0x61, 0x67, 0x65,
+	0x64, 0x53, 0x69, 0x7a, 0x65, 0x12, 0x28, 0x0a, 0x0f, 0x74, 0x72, 0x61, 0x6e, 0x73, 0x66, 0x65,
+	0x72, 0x72, 0x65, 0x64, 0x53, 0x69, 0x7a, 0x65, 0x18, 0x04, 0x20, 0x01, 0x28, 0x04, 0x52, 0x0f,
+	0x74, 0x72, 0x61, 0x6e, 0x73, 0x66, 0x65, 0x72, 0x72, 0x65, 0x64, 0x53, 0x69, 0x7a, 0x65, 0x22,
+	0xa3, 0x01, 0x0a, 0x11, 0x53, 0x65, 0x73, 0x73, 0x69, 0x6f, 0x6e, 0x53, 0x74, 0x61, 0x74, 0x69,
+	0x73, 0x74, 0x69, 0x63, 0x73, 0x12, 0x16, 0x0a, 0x06, 0x63, 0x79, 0x63, 0x6c, 0x65, 0x73, 0x18,
+	0x01, 0x20, 0x01, 0x28, 0x04, 0x52, 0x06, 0x63, 0x79, 0x63, 0x6c, 0x65, 0x73, 0x12, 0x3e, 0x0a,
+	0x09, 0x6c, 0x61, 0x73, 0x74, 0x43, 0x79, 0x63, 0x6c, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0b,
+	0x32, 0x20, 0x2e, 0x73, 0x79, 0x6e, 0x63, 0x68, 0x72, 0x6f, 0x6e, 0x69, 0x7a, 0x61, 0x74, 0x69,
+	0x6f, 0x6e, 0x2e, 0x43, 0x79, 0x63, 0x6c, 0x65, 0x53, 0x74, 0x61, 0x74, 0x69, 0x73, 0x74, 0x69,
+	0x63, 0x73, 0x52, 0x09, 0x6c, 0x61, 0x73, 0x74, 0x43, 0x79, 0x63, 0x6c, 0x65, 0x12, 0x36, 0x0a,
+	0x05, 0x74, 0x6f, 0x74, 0x61, 0x6c, 0x18, 0x03, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x20, 0x2e, 0x73,
+	0x79, 0x6e, 0x63, 0x68, 0x72, 0x6f, 0x6e, 0x69, 0x7a, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x2e, 0x43,
+	0x79, 0x63, 0x6c, 0x65, 0x53, 0x74, 0x61, 0x74, 0x69, 0x73, 0x74, 0x69, 0x63, 0x73, 0x52, 0x05,
+	0x74, 0x6f, 0x74, 0x61, 0x6c, 0x42, 0x33, 0x5a, 0x31, 0x67, 0x69, 0x74, 0x68, 0x75, 0x62, 0x2e,
+	0x63, 0x6f, 0x6d, 0x2f, 0x6d, 0x75, 0x74, 0x61, 0x67, 0x65, 0x6e, 0x2d, 0x69, 0x6f, 0x2f, 0x6d,
+	0x75, 0x74, 0x61, 0x67, 0x65, 0x6e, 0x2f, 0x70, 0x6b, 0x67, 0x2f, 0x73, 0x79, 0x6e, 0x63, 0x68,
+	0x72, 0x6f, 0x6e, 0x69, 0x7a, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x62, 0x06, 0x70, 0x72, 0x6f, 0x74,
+	0x6f, 0x33,
+}
+
+var (
+	file_synchronization_statistics_proto_rawDescOnce sync.Once
+	file_synchronization_statistics_proto_rawDescData = file_synchronization_statistics_proto_rawDesc
+)
+
+func file_synchronization_statistics_proto_rawDescGZIP() []byte {
+	file_synchronization_statistics_proto_rawDescOnce.Do(func() {
+		file_synchronization_statistics_proto_rawDescData = protoimpl.X.CompressGZIP(file_synchronization_statistics_proto_rawDescData)
+	})
+	return file_synchronization_statistics_proto_rawDescData
+}
+
+var file_synchronization_statistics_proto_msgTypes = make([]protoimpl.MessageInfo, 2)
+var file_synchronization_statistics_proto_goTypes = []interface{}{
+	(*CycleStatistics)(nil),   // 0: synchronization.CycleStatistics
+	(*SessionStatistics)(nil), // 1: synchronization.SessionStatistics
+}
+var file_synchronization_statistics_proto_depIdxs = []int32{
+	0, // 0: synchronization.SessionStatistics.lastCycle:type_name -> synchronization.CycleStatistics
+	0, // 1: synchronization.SessionStatistics.total:type_name -> synchronization.CycleStatistics
+	2, // [2:2] is the sub-list for method output_type
+	2, // [2:2] is the sub-list for method input_type
+	2, // [2:2] is the sub-list for extension type_name
+	2, // [2:2] is the sub-list for extension extendee
+	0, // [0:2] is the sub-list for field type_name
+}
+
+func init() { file_synchronization_statistics_proto_init() }
+func file_synchronization_statistics_proto_init() {
+	if File_synchronization_statistics_proto != nil {
+		return
+	}
+	if !protoimpl.UnsafeEnabled {
+		file_synchronization_statistics_proto_msgTypes[0].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*CycleStatistics); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_synchronization_statistics_proto_msgTypes[1].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*SessionStatistics); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+	}
+	type x struct{}
+	out := protoimpl.TypeBuilder{
+		File: protoimpl.DescBuilder{
+			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
+			RawDescriptor: file_synchronization_statistics_proto_rawDesc,
+			NumEnums:      0,
+			NumMessages:   2,
+			NumExtensions: 0,
+			NumServices:   0,
+		},
+		GoTypes:           file_synchronization_statistics_proto_goTypes,
+		DependencyIndexes: file_synchronization_statistics_proto_depIdxs,
+		MessageInfos:      file_synchronization_statistics_proto_msgTypes,
+	}.Build()
+	File_synchronization_statistics_proto = out.File
+	file_synchronization_statistics_proto_rawDesc = nil
+	file_synchronization_statistics_proto_goTypes = nil
+	file_synchronization_statistics_proto_depIdxs = nil
+}
@@ -0,0 +1,95 @@
+package synchronization
+
+import (
+	"testing"
+)
+
+// TestCycleStatisticsDeltaCompressionRatio tests
+// CycleStatistics.DeltaCompressionRatio.
+func TestCycleStatisticsDeltaCompressionRatio(t *testing.T) {
+	// Define test cases.
+	testCases := []struct {
+		statistics    *CycleStatistics
+		expected      float64
+		expectDefined bool
+	}{
+		{&CycleStatistics{}, 0, false},
+		{&CycleStatistics{StagedSize: 1024}, 0, false},
+		{&CycleStatistics{StagedSize: 1024, TransferredSize: 1024}, 1, true},
+		{&CycleStatistics{StagedSize: 1024, TransferredSize: 256}, 4, true},
+	}
+
+	// Process test cases.
+	for i, testCase := range testCases {
+		ratio, defined := testCase.statistics.DeltaCompressionRatio()
+		if defined != testCase.expectDefined {
+			t.Errorf("test index %d: ratio definedness does not match expected: %t != %t",
+				i, defined, testCase.expectDefined,
+			)
+		} else if ratio != testCase.expected {
+			t.Errorf("test index %d: ratio does not match expected: %f != %f",
+				i, ratio, testCase.expected,
+			)
+		}
+	}
+}
+
+// TestSessionStatisticsRecord tests SessionStatistics.record.
+func TestSessionStatisticsRecord(t *testing.T) {
+	// Create empty statistics and verify that they're valid.
+	statistics := &SessionStatistics{}
+	if err := statistics.EnsureValid(); err != nil {
+		t.Fatal("empty statistics considered invalid:", err)
+	}
+
+	// Record two cycles.
+	first := &CycleStatistics{Duration: 100, StagedFiles: 2, StagedSize: 2048, TransferredSize: 512}
+	second := &CycleStatistics{Duration: 50, StagedFiles: 1, StagedSize: 1024, TransferredSize: 1024}
+	statistics.record(first)
+	statistics.record(second)
+
+	// Verify the results.
+	if err := statistics.EnsureValid(); err != nil {
+		t.Fatal("recorded statistics considered invalid:", err)
+	}
+	if statistics.Cycles != 2 {
+		t.Error("cycle count does not match expected:", statistics.Cycles, "!=", 2)
+	}
+	if statistics.LastCycle != second {
+		t.Error("last cycle statistics do not match most recent cycle")
+	}
+	expectedTotal := &CycleStatistics{Duration: 150, StagedFiles: 3, StagedSize: 3072, TransferredSize: 1536}
+	if statistics.Total.Duration != expectedTotal.Duration ||
+		statistics.Total.StagedFiles != expectedTotal.StagedFiles ||
+		statistics.Total.StagedSize != expectedTotal.StagedSize ||
+		statistics.Total.TransferredSize != expectedTotal.TransferredSize {
+		t.Error("total statistics do not match expected")
+	}
+}
+
+// TestSessionStatisticsEnsureValid tests SessionStatistics.EnsureValid.
+func TestSessionStatisticsEnsureValid(t *testing.T) {
+	// Define test cases.
+	testCases := []struct {
+		statistics  *SessionStatistics
+		expectValid bool
+	}{
+		{nil, true},
+		{&SessionStatistics{}, true},
+		{&SessionStatistics{LastCycle: &CycleStatistics{}}, false},
+		{&SessionStatistics{Total: &CycleStatistics{}}, false},
+		{&SessionStatistics{Cycles: 1}, false},
+		{&SessionStatistics{Cycles: 1, LastCycle: &CycleStatistics{}}, false},
+		{&SessionStatistics{Cycles: 1, LastCycle: &CycleStatistics{}, Total: &CycleStatistics{}}, true},
+	}
+
+	// Process test cases.
+	for i, testCase := range testCases {
+		err := testCase.statistics.EnsureValid()
+		if valid := err == nil; valid != testCase.expectValid {
+			t.Errorf("test index %d: validity does not match expected: %t != %t (%v)",
+				i, valid, testCase.expectValid, err,
+			)
+		}
+	}
+}
@@ -0,0 +1,30 @@
+package synchronization
+
+import (
+	"errors"
+)
+
+// EnsureValid ensures that VerificationResult's invariants are respected.
+func (r *VerificationResult) EnsureValid() error {
+	// A nil verification result is not valid.
+	if r == nil {
+		return errors.New("nil verification result")
+	}
+
+	// Ensure that a session identifier is present.
+	if r.Session == "" {
+		return errors.New("empty session identifier")
+	}
+
+	// Divergence paths don't need to be validated - any value is valid.
+
+	// Success.
+	return nil
+}
+
+// Consistent indicates whether or not verification found no divergences.
+func (r *VerificationResult) Consistent() bool {
+	return len(r.AlphaDivergences) == 0 &&
+		len(r.BetaDivergences) == 0 &&
+		len(r.EndpointDivergences) == 0
+}
@@ -63,6 +63,17 @@ func (v Version) DefaultMaximumStagingFileSize() uint64 {
 	}
 }
 
+// DefaultMaximumStagingTotalSize returns the default maximum total staging size
+// for the session version.
+func (v Version) DefaultMaximumStagingTotalSize() uint64 {
+	switch v {
+	case Version_Version1:
+		return math.MaxUint64
+	default:
+		panic("unknown or unsupported session version")
+	}
+}
+
 // DefaultProbeMode returns the default probe mode for the session version.
 func (v Version) DefaultProbeMode() behavior.ProbeMode {
 	switch v {
@@ -83,6 +94,39 @@ func (v Version) DefaultScanMode() ScanMode {
 	}
 }
 
+// DefaultScanConcurrency returns the default scan concurrency for the session
+// version.
+func (v Version) DefaultScanConcurrency() uint32 {
+	switch v {
+	case Version_Version1:
+		return 1
+	default:
+		panic("unknown or unsupported session version")
+	}
+}
+
+// DefaultMemoryBudget returns the default memory budget for the session
+// version.
+func (v Version) DefaultMemoryBudget() uint64 {
+	switch v {
+	case Version_Version1:
+		return math.MaxUint64
+	default:
+		panic("unknown or unsupported session version")
+	}
+}
+
+// DefaultModificationTimeTolerance returns the default modification time
+// tolerance (in milliseconds) for the session version.
+func (v Version) DefaultModificationTimeTolerance() uint32 {
+	switch v {
+	case Version_Version1:
+		return 0
+	default:
+		panic("unknown or unsupported session version")
+	}
+}
+
 // DefaultStageMode returns the default staging mode for the session version.
 func (v Version) DefaultStageMode() StageMode {
 	switch v {
@@ -93,6 +137,39 @@ func (v Version) DefaultStageMode() StageMode {
 	}
 }
 
+// DefaultUnicodeNormalizationMode returns the default Unicode normalization
+// mode for the session version.
+func (v Version) DefaultUnicodeNormalizationMode() core.UnicodeNormalizationMode {
+	switch v {
+	case Version_Version1:
+		return core.UnicodeNormalizationMode_UnicodeNormalizationModeNone
+	default:
+		panic("unknown or unsupported session version")
+	}
+}
+
+// DefaultReplacementMode returns the default replacement mode for the session
+// version.
+func (v Version) DefaultReplacementMode() core.ReplacementMode {
+	switch v {
+	case Version_Version1:
+		return core.ReplacementMode_ReplacementModeRename
+	default:
+		panic("unknown or unsupported session version")
+	}
+}
+
+// DefaultMirrorEnforcementMode returns the default mirror enforcement mode for
+// the session version.
+func (v Version) DefaultMirrorEnforcementMode() MirrorEnforcementMode {
+	switch v {
+	case Version_Version1:
+		return MirrorEnforcementMode_MirrorEnforcementModeStandard
+	default:
+		panic("unknown or unsupported session version")
+	}
+}
+
 // DefaultSymbolicLinkMode returns the default symbolic link mode for the
 // session version.
 func (v Version) DefaultSymbolicLinkMode() core.SymbolicLinkMode {
@@ -182,3 +259,47 @@ func (v Version) DefaultGroupSpecification() string {
 		panic("unknown or unsupported session version")
 	}
 }
+
+// DefaultDeletionThreshold returns the default deletion threshold percentage
+// for the session version.
+func (v Version) DefaultDeletionThreshold() uint32 {
+	switch v {
+	case Version_Version1:
+		return 100
+	default:
+		panic("unknown or unsupported session version")
+	}
+}
+
+// DefaultTrashRetentionPeriod returns the default trash retention period (in
+// seconds) for the session version.
+func (v Version) DefaultTrashRetentionPeriod() uint32 {
+	switch v {
+	case Version_Version1:
+		return 7 * 24 * 60 * 60
+	default:
+		panic("unknown or unsupported session version")
+	}
+}
+
+// DefaultMaximumTrashSize returns the default maximum trash size for the session
+// version.
+func (v Version) DefaultMaximumTrashSize() uint64 {
+	switch v {
+	case Version_Version1:
+		return math.MaxUint64
+	default:
+		panic("unknown or unsupported session version")
+	}
+}
+
+// DefaultMinimumFreeSpace returns the default minimum free space for the
+// session version.
+func (v Version) DefaultMinimumFreeSpace() uint64 {
+	switch v {
+	case Version_Version1:
+		return 0
+	default:
+		panic("unknown or unsupported session version")
+	}
+}
@@ -0,0 +1,50 @@
+package synchronization
+
+import (
+	"errors"
+	"fmt"
+)
+
+// EnsureValid ensures that FileVersion's invariants are respected.
+func (v *FileVersion) EnsureValid() error {
+	// A nil file version is not valid.
+	if v == nil {
+		return errors.New("nil file version")
+	}
+
+	// Ensure that an identifier is present.
+	if v.Identifier == "" {
+		return errors.New("empty version identifier")
+	}
+
+	// Ensure that the creation time is valid.
+	if err := v.CreationTime.CheckValid(); err != nil {
+		return fmt.Errorf("invalid creation time: %w", err)
+	}
+
+	// Success.
+	return nil
+}
+
+// EnsureValid ensures that VersionsResult's invariants are respected.
+func (r *VersionsResult) EnsureValid() error {
+	// A nil versions result is not valid.
+	if r == nil {
+		return errors.New("nil versions result")
+	}
+
+	// Ensure that a session identifier is present.
+	if r.Session == "" {
+		return errors.New("empty session identifier")
+	}
+
+	// Ensure that all versions are valid.
+	for _, version := range r.Versions {
+		if err := version.EnsureValid(); err != nil {
+			return fmt.Errorf("invalid file version: %w", err)
+		}
+	}
+
+	// Success.
+	return nil
+}
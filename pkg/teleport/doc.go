@@ -0,0 +1,3 @@
+// Package teleport provides utility functions for interfacing with the Teleport
+// client (tsh).
+package teleport
@@ -0,0 +1,36 @@
+package teleport
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/exec"
+
+	"github.com/mutagen-io/mutagen/pkg/process"
+)
+
+// CommandPath returns the absolute path specification to use for invoking tsh.
+// It will use the MUTAGEN_TELEPORT_PATH environment variable if provided,
+// otherwise falling back to a search of the user's path.
+func CommandPath() (string, error) {
+	// If MUTAGEN_TELEPORT_PATH is specified, then use it to perform the lookup.
+	if searchPath := os.Getenv("MUTAGEN_TELEPORT_PATH"); searchPath != "" {
+		return process.FindCommand("tsh", []string{searchPath})
+	}
+
+	// Otherwise search the user's path.
+	return exec.LookPath("tsh")
+}
+
+// Command prepares (but does not start) a tsh command with the specified
+// arguments and scoped to lifetime of the provided context.
+func Command(ctx context.Context, args ...string) (*exec.Cmd, error) {
+	// Identify the command path.
+	commandPath, err := CommandPath()
+	if err != nil {
+		return nil, fmt.Errorf("unable to identify 'tsh' command: %w", err)
+	}
+
+	// Create the command.
+	return exec.CommandContext(ctx, commandPath, args...), nil
+}
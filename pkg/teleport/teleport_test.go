@@ -0,0 +1,53 @@
+package teleport
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"runtime"
+	"testing"
+
+	"github.com/mutagen-io/mutagen/pkg/process"
+)
+
+// TestCommandPathOverride tests that CommandPath and Command respect the
+// MUTAGEN_TELEPORT_PATH environment variable.
+func TestCommandPathOverride(t *testing.T) {
+	// Create a search path containing a tsh executable.
+	directory := t.TempDir()
+	executable := filepath.Join(directory, process.ExecutableName("tsh", runtime.GOOS))
+	if err := os.WriteFile(executable, nil, 0700); err != nil {
+		t.Fatal("unable to create executable:", err)
+	}
+	t.Setenv("MUTAGEN_TELEPORT_PATH", directory)
+
+	// Verify that the executable is found.
+	if path, err := CommandPath(); err != nil {
+		t.Fatal("unable to find command:", err)
+	} else if path != executable {
+		t.Error("command path does not match expected:", path, "!=", executable)
+	}
+
+	// Verify command construction.
+	command, err := Command(context.Background(), "ssh", "node")
+	if err != nil {
+		t.Fatal("unable to create command:", err)
+	} else if command.Path != executable {
+		t.Error("command path does not match expected:", command.Path, "!=", executable)
+	} else if len(command.Args) != 3 || command.Args[1] != "ssh" || command.Args[2] != "node" {
+		t.Error("command arguments do not match expected:", command.Args)
+	}
+}
+
+// TestCommandPathOverrideMissing tests that CommandPath and Command fail if
+// the MUTAGEN_TELEPORT_PATH environment variable specifies a directory that
+// doesn't contain tsh.
+func TestCommandPathOverrideMissing(t *testing.T) {
+	t.Setenv("MUTAGEN_TELEPORT_PATH", t.TempDir())
+	if _, err := CommandPath(); err == nil {
+		t.Error("command path found unexpectedly")
+	}
+	if _, err := Command(context.Background()); err == nil {
+		t.Error("command created unexpectedly")
+	}
+}
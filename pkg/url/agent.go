@@ -0,0 +1,83 @@
+package url
+
+import (
+	"fmt"
+	"strings"
+)
+
+const (
+	// AgentDirectoryParameter is the name of the URL parameter that overrides
+	// the directory used as the Mutagen data directory (and thus the agent
+	// installation location) on the remote for agent-based URLs. If unset, the
+	// default data directory inside the remote user's home directory is used.
+	AgentDirectoryParameter = "agent-directory"
+
+	// agentDirectoryEnvironmentVariable is the (Mutagen-specific) environment
+	// variable that's used to specify the remote agent directory at parse time.
+	agentDirectoryEnvironmentVariable = "AGENT_DIRECTORY"
+)
+
+// UsesAgent returns whether or not a protocol relies on a Mutagen agent
+// installed on the remote.
+func (p Protocol) UsesAgent() bool {
+	switch p {
+	case Protocol_SSH,
+		Protocol_Docker,
+		Protocol_Nerdctl,
+		Protocol_LXD,
+		Protocol_WSL,
+		Protocol_Azure,
+		Protocol_Teleport,
+		Protocol_Exec,
+		Protocol_Plugin,
+		Protocol_Kubernetes:
+		return true
+	default:
+		return false
+	}
+}
+
+// IsValidAgentDirectory returns whether or not the specified remote agent
+// directory is valid. The directory must be an absolute POSIX or Windows path.
+// Since the path is embedded in commands that are split on spaces (and may be
+// interpreted by a shell), whitespace, quotes, and shell metacharacters aren't
+// allowed.
+func IsValidAgentDirectory(directory string) bool {
+	if !(strings.HasPrefix(directory, "/") || isWindowsPath(directory)) {
+		return false
+	}
+	return !strings.ContainsAny(directory, " \t\r\n'\"`$;&|<>*?%!()")
+}
+
+// SplitAgentDirectory extracts the agent directory parameter (if any) from a
+// set of URL parameters, returning its value along with the remaining
+// parameters. The original parameters are not modified.
+func SplitAgentDirectory(parameters map[string]string) (string, map[string]string) {
+	directory, ok := parameters[AgentDirectoryParameter]
+	if !ok {
+		return "", parameters
+	}
+	remaining := make(map[string]string, len(parameters)-1)
+	for name, value := range parameters {
+		if name != AgentDirectoryParameter {
+			remaining[name] = value
+		}
+	}
+	return directory, remaining
+}
+
+// lockInAgentDirectory stores any agent directory specified in the environment
+// into the specified parameters, creating the parameter map if necessary.
+func lockInAgentDirectory(parameters map[string]string, kind Kind, first bool) (map[string]string, error) {
+	directory, ok := getMutagenEnvironmentVariable(agentDirectoryEnvironmentVariable, kind, first)
+	if !ok || directory == "" {
+		return parameters, nil
+	} else if !IsValidAgentDirectory(directory) {
+		return nil, fmt.Errorf("invalid agent directory specified in environment: %s", directory)
+	}
+	if parameters == nil {
+		parameters = make(map[string]string, 1)
+	}
+	parameters[AgentDirectoryParameter] = directory
+	return parameters, nil
+}
@@ -5,6 +5,9 @@ import (
 )
 
 const (
+	// mutagenEnvironmentVariablePrefix is the prefix used for general variants
+	// of Mutagen-specific environment variables.
+	mutagenEnvironmentVariablePrefix = "MUTAGEN_"
 	// alphaSpecificEnvironmentVariablePrefix is the prefix to use when checking
 	// for alpha-specific environment variables.
 	alphaSpecificEnvironmentVariablePrefix = "MUTAGEN_ALPHA_"
@@ -33,26 +36,46 @@ func getEnvironmentVariable(name string, kind Kind, first bool) (string, bool) {
 	}
 
 	// Check for an endpoint-specific variant.
-	var endpointSpecificName string
+	if value, ok := lookupEnv(endpointSpecificEnvironmentVariableName(name, kind, first)); ok {
+		return value, true
+	}
+
+	// Check for the general variant.
+	return lookupEnv(name)
+}
+
+// getMutagenEnvironmentVariable is like getEnvironmentVariable, but it's used
+// for Mutagen-specific variables, where the general variant carries a
+// "MUTAGEN_" prefix. For example, a name of SSH_CLIENT will be looked up as
+// MUTAGEN_ALPHA_SSH_CLIENT (for an alpha URL) and then as MUTAGEN_SSH_CLIENT.
+func getMutagenEnvironmentVariable(name string, kind Kind, first bool) (string, bool) {
+	// Validate the variable name.
+	if name == "" {
+		return "", false
+	}
+
+	// Check for an endpoint-specific variant.
+	if value, ok := lookupEnv(endpointSpecificEnvironmentVariableName(name, kind, first)); ok {
+		return value, true
+	}
+
+	// Check for the general variant.
+	return lookupEnv(mutagenEnvironmentVariablePrefix + name)
+}
+
+// endpointSpecificEnvironmentVariableName computes the name of the
+// endpoint-specific variant of an environment variable.
+func endpointSpecificEnvironmentVariableName(name string, kind Kind, first bool) string {
 	if kind == Kind_Synchronization {
 		if first {
-			endpointSpecificName = alphaSpecificEnvironmentVariablePrefix + name
-		} else {
-			endpointSpecificName = betaSpecificEnvironmentVariablePrefix + name
+			return alphaSpecificEnvironmentVariablePrefix + name
 		}
+		return betaSpecificEnvironmentVariablePrefix + name
 	} else if kind == Kind_Forwarding {
 		if first {
-			endpointSpecificName = sourceSpecificEnvironmentVariablePrefix + name
-		} else {
-			endpointSpecificName = destinationSpecificEnvironmentVariablePrefix + name
+			return sourceSpecificEnvironmentVariablePrefix + name
 		}
-	} else {
-		panic("unhandled URL kind")
-	}
-	if value, ok := lookupEnv(endpointSpecificName); ok {
-		return value, true
+		return destinationSpecificEnvironmentVariablePrefix + name
 	}
-
-	// Check for the general variant.
-	return lookupEnv(name)
+	panic("unhandled URL kind")
 }
@@ -23,6 +23,15 @@ const (
 	// destinationSpecificDockerTLSVerify is the destination-specific value for
 	// the DOCKER_TLS_VERIFY environment variable.
 	destinationSpecificDockerTLSVerify = "false"
+	// defaultContainerdNamespace is the non-endpoint-specific value for the
+	// CONTAINERD_NAMESPACE environment variable.
+	defaultContainerdNamespace = "k8s.io"
+	// betaSpecificContainerdAddress is the beta-specific value for the
+	// CONTAINERD_ADDRESS environment variable.
+	betaSpecificContainerdAddress = "/beta/containerd.sock"
+	// sourceSpecificLXDConf is the source-specific value for the LXD_CONF
+	// environment variable.
+	sourceSpecificLXDConf = "/source/lxc"
 )
 
 // mockEnvironment is a mock environment setup for use in testing.
@@ -33,6 +42,9 @@ var mockEnvironment = map[string]string{
 	"MUTAGEN_BETA_DOCKER_TLS_VERIFY":        betaSpecificDockerTLSVerify,
 	"MUTAGEN_SOURCE_DOCKER_CONTEXT":         sourceSpecificDockerContext,
 	"MUTAGEN_DESTINATION_DOCKER_TLS_VERIFY": destinationSpecificDockerTLSVerify,
+	"CONTAINERD_NAMESPACE":                  defaultContainerdNamespace,
+	"MUTAGEN_BETA_CONTAINERD_ADDRESS":       betaSpecificContainerdAddress,
+	"MUTAGEN_SOURCE_LXD_CONF":               sourceSpecificLXDConf,
 }
 
 // mockLookupEnv is a mock implementation of the os.LookupEnv function.
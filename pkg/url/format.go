@@ -2,6 +2,8 @@ package url
 
 import (
 	"fmt"
+	"sort"
+	"strings"
 )
 
 // Format formats a URL into a human-readable (and reparsable) format.
@@ -12,6 +14,30 @@ func (u *URL) Format(environmentPrefix string) string {
 		return u.formatSSH()
 	} else if u.Protocol == Protocol_Docker {
 		return u.formatDocker(environmentPrefix)
+	} else if u.Protocol == Protocol_Nerdctl {
+		return u.formatNerdctl(environmentPrefix)
+	} else if u.Protocol == Protocol_LXD {
+		return u.formatLXD(environmentPrefix)
+	} else if u.Protocol == Protocol_Kubernetes {
+		return u.formatKubernetes(environmentPrefix)
+	} else if u.Protocol == Protocol_WSL {
+		return u.formatWSL()
+	} else if u.Protocol == Protocol_Azure {
+		return u.formatAzure(environmentPrefix)
+	} else if u.Protocol == Protocol_Teleport {
+		return u.formatTeleport(environmentPrefix)
+	} else if u.Protocol == Protocol_SFTP {
+		return u.formatSFTP()
+	} else if u.Protocol == Protocol_S3 {
+		return u.formatS3(environmentPrefix)
+	} else if u.Protocol == Protocol_Exec {
+		return u.formatExec(environmentPrefix)
+	} else if u.Protocol == Protocol_TCP {
+		return u.formatTCP(environmentPrefix)
+	} else if u.Protocol == Protocol_Rendezvous {
+		return u.formatRendezvous(environmentPrefix)
+	} else if u.Protocol == Protocol_Plugin {
+		return u.formatPlugin(environmentPrefix)
 	}
 	panic("unknown URL protocol")
 }
@@ -49,6 +75,283 @@ const invalidDockerURLFormat = "<invalid-docker-url>"
 
 // formatDocker formats a Docker URL.
 func (u *URL) formatDocker(environmentPrefix string) string {
+	return u.formatContainer(
+		dockerURLPrefix, invalidDockerURLFormat,
+		DockerEnvironmentVariables, dockerParameterNames,
+		environmentPrefix,
+	)
+}
+
+// invalidNerdctlURLFormat is the value returned by formatNerdctl when a URL is
+// provided that breaks invariants.
+const invalidNerdctlURLFormat = "<invalid-nerdctl-url>"
+
+// formatNerdctl formats a nerdctl URL.
+func (u *URL) formatNerdctl(environmentPrefix string) string {
+	return u.formatContainer(
+		nerdctlURLPrefix, invalidNerdctlURLFormat,
+		NerdctlEnvironmentVariables, nil,
+		environmentPrefix,
+	)
+}
+
+// invalidLXDURLFormat is the value returned by formatLXD when a URL is provided
+// that breaks invariants.
+const invalidLXDURLFormat = "<invalid-lxd-url>"
+
+// formatLXD formats an LXD URL.
+func (u *URL) formatLXD(environmentPrefix string) string {
+	return u.formatContainer(
+		lxdURLPrefix, invalidLXDURLFormat,
+		LXDEnvironmentVariables, nil,
+		environmentPrefix,
+	)
+}
+
+// invalidKubernetesURLFormat is the value returned by formatKubernetes when a
+// URL is provided that breaks invariants.
+const invalidKubernetesURLFormat = "<invalid-kubernetes-url>"
+
+// formatKubernetes formats a Kubernetes URL.
+func (u *URL) formatKubernetes(environmentPrefix string) string {
+	return u.formatContainer(
+		kubernetesURLPrefix, invalidKubernetesURLFormat,
+		KubernetesEnvironmentVariables, kubernetesParameterNames,
+		environmentPrefix,
+	)
+}
+
+// invalidWSLURLFormat is the value returned by formatWSL when a URL is provided
+// that breaks invariants.
+const invalidWSLURLFormat = "<invalid-wsl-url>"
+
+// formatWSL formats a WSL URL.
+func (u *URL) formatWSL() string {
+	return u.formatContainer(wslURLPrefix, invalidWSLURLFormat, nil, nil, "")
+}
+
+// invalidAzureURLFormat is the value returned by formatAzure when a URL is
+// provided that breaks invariants.
+const invalidAzureURLFormat = "<invalid-azure-url>"
+
+// formatAzure formats an Azure URL.
+func (u *URL) formatAzure(environmentPrefix string) string {
+	return u.formatContainer(
+		azureURLPrefix, invalidAzureURLFormat,
+		AzureEnvironmentVariables, nil,
+		environmentPrefix,
+	)
+}
+
+// invalidTeleportURLFormat is the value returned by formatTeleport when a URL is
+// provided that breaks invariants.
+const invalidTeleportURLFormat = "<invalid-teleport-url>"
+
+// formatTeleport formats a Teleport URL.
+func (u *URL) formatTeleport(environmentPrefix string) string {
+	return u.formatContainer(
+		teleportURLPrefix, invalidTeleportURLFormat,
+		TeleportEnvironmentVariables, nil,
+		environmentPrefix,
+	)
+}
+
+// invalidSFTPURLFormat is the value returned by formatSFTP when a URL is
+// provided that breaks invariants.
+const invalidSFTPURLFormat = "<invalid-sftp-url>"
+
+// formatSFTP formats an SFTP URL.
+func (u *URL) formatSFTP() string {
+	// Start with the hostname, wrapping it in brackets if it's an IPv6
+	// address.
+	result := u.Host
+	if strings.IndexByte(result, ':') != -1 {
+		result = fmt.Sprintf("[%s]", result)
+	}
+
+	// Add username if present.
+	if u.User != "" {
+		result = fmt.Sprintf("%s@%s", u.User, result)
+	}
+
+	// Add port if present.
+	if u.Port != 0 {
+		result = fmt.Sprintf("%s:%d", result, u.Port)
+	}
+
+	// Append the path, prepending a slash to home-directory-relative paths.
+	if u.Path == "" {
+		return invalidSFTPURLFormat
+	} else if u.Path[0] == '/' {
+		result += u.Path
+	} else if u.Path[0] == '~' {
+		result += fmt.Sprintf("/%s", u.Path)
+	} else {
+		return invalidSFTPURLFormat
+	}
+
+	// Add the scheme.
+	return sftpURLPrefix + result
+}
+
+// invalidS3URLFormat is the value returned by formatS3 when a URL is provided
+// that breaks invariants.
+const invalidS3URLFormat = "<invalid-s3-url>"
+
+// formatS3 formats an S3 URL.
+func (u *URL) formatS3(environmentPrefix string) string {
+	// Ensure that the path is valid.
+	if u.Path == "" || u.Path[0] != '/' {
+		return invalidS3URLFormat
+	}
+
+	// Combine the scheme, bucket, and prefix.
+	result := s3URLPrefix + u.Host + u.Path
+
+	// Add environment variable information if requested.
+	if environmentPrefix != "" {
+		for _, variable := range S3EnvironmentVariables {
+			if value, present := u.Environment[variable]; present {
+				result += fmt.Sprintf("%s%s=%s", environmentPrefix, variable, value)
+			}
+		}
+	}
+
+	// Done.
+	return result
+}
+
+// invalidTCPURLFormat is the value returned by formatTCP when a URL is provided
+// that breaks invariants.
+const invalidTCPURLFormat = "<invalid-tcp-url>"
+
+// formatTCP formats a TCP URL.
+func (u *URL) formatTCP(environmentPrefix string) string {
+	// Start with the hostname, wrapping it in brackets if it's an IPv6
+	// address, and add the port.
+	result := u.Host
+	if strings.IndexByte(result, ':') != -1 {
+		result = fmt.Sprintf("[%s]", result)
+	}
+	result = fmt.Sprintf("%s%s:%d", tcpURLPrefix, result, u.Port)
+
+	// Append the path and parameters.
+	return u.formatTCPSuffix(result, invalidTCPURLFormat, environmentPrefix)
+}
+
+// invalidRendezvousURLFormat is the value returned by formatRendezvous when a
+// URL is provided that breaks invariants.
+const invalidRendezvousURLFormat = "<invalid-rendezvous-url>"
+
+// formatRendezvous formats a rendezvous URL.
+func (u *URL) formatRendezvous(environmentPrefix string) string {
+	return u.formatTCPSuffix(rendezvousURLPrefix+u.Host, invalidRendezvousURLFormat, environmentPrefix)
+}
+
+// invalidPluginURLFormat is the value returned by formatPlugin when a URL is
+// provided that breaks invariants.
+const invalidPluginURLFormat = "<invalid-plugin-url>"
+
+// formatPlugin formats a plugin URL.
+func (u *URL) formatPlugin(environmentPrefix string) string {
+	// Combine the scheme, plugin name, and target.
+	result := fmt.Sprintf("%s%s://%s", pluginURLPrefix, u.Parameters[PluginParameter], u.Host)
+
+	// Append the path in a manner that depends on the URL kind.
+	if u.Kind == Kind_Synchronization {
+		if u.Path == "" {
+			return invalidPluginURLFormat
+		} else if u.Path[0] == '/' {
+			result += u.Path
+		} else if u.Path[0] == '~' || isWindowsPath(u.Path) {
+			result += fmt.Sprintf("/%s", u.Path)
+		} else {
+			return invalidPluginURLFormat
+		}
+	} else if u.Kind == Kind_Forwarding {
+		result += fmt.Sprintf(":%s", u.Path)
+	} else {
+		panic("unhandled URL kind")
+	}
+
+	// Add parameter information, if requested.
+	if environmentPrefix != "" {
+		if value, present := u.Parameters[AgentDirectoryParameter]; present {
+			result += fmt.Sprintf("%s%s=%s", environmentPrefix, AgentDirectoryParameter, value)
+		}
+	}
+
+	// Done.
+	return result
+}
+
+// formatTCPSuffix appends the path and (if requested) parameter information for
+// TCP-based URLs (i.e. TCP and rendezvous URLs) to the specified base.
+func (u *URL) formatTCPSuffix(result, invalid, environmentPrefix string) string {
+	// Append the path in a manner that depends on the URL kind.
+	if u.Kind == Kind_Synchronization {
+		if u.Path == "" {
+			return invalid
+		} else if u.Path[0] == '/' {
+			result += u.Path
+		} else if u.Path[0] == '~' || isWindowsPath(u.Path) {
+			result += fmt.Sprintf("/%s", u.Path)
+		} else {
+			return invalid
+		}
+	} else if u.Kind == Kind_Forwarding {
+		result += fmt.Sprintf(":%s", u.Path)
+	} else {
+		panic("unhandled URL kind")
+	}
+
+	// Add parameter information, if requested.
+	if environmentPrefix != "" {
+		if value, present := u.Parameters[TCPKeyFileParameter]; present {
+			result += fmt.Sprintf("%s%s=%s", environmentPrefix, TCPKeyFileParameter, value)
+		}
+	}
+
+	// Done.
+	return result
+}
+
+// formatExec formats an exec URL.
+func (u *URL) formatExec(environmentPrefix string) string {
+	// Combine the scheme, quoted command, and path or forwarding endpoint.
+	result := fmt.Sprintf("%s'%s':%s", execURLPrefix, u.Host, u.Path)
+
+	// Add environment variable information, if requested. Since there's no
+	// fixed set of variables, we sort them to ensure a stable format.
+	if environmentPrefix != "" {
+		names := make([]string, 0, len(u.Environment))
+		for name := range u.Environment {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+		for _, name := range names {
+			result += fmt.Sprintf("%s%s=%s", environmentPrefix, name, u.Environment[name])
+		}
+	}
+
+	// Add parameter information, if requested.
+	if environmentPrefix != "" {
+		for _, name := range []string{ExecCopyParameter, AgentDirectoryParameter} {
+			if value, present := u.Parameters[name]; present {
+				result += fmt.Sprintf("%s%s=%s", environmentPrefix, name, value)
+			}
+		}
+	}
+
+	// Done.
+	return result
+}
+
+// formatContainer formats a container-style URL (e.g. a Docker, LXD, or Azure
+// URL) using the specified prefix, invalid URL representation, and the names
+// of the environment variables and parameters that should be included if
+// requested.
+func (u *URL) formatContainer(prefix, invalid string, environmentVariables, parameterNames []string, environmentPrefix string) string {
 	// Start with the container name.
 	result := u.Host
 
@@ -62,13 +365,13 @@ func (u *URL) formatDocker(environmentPrefix string) string {
 		// If this is a home-directory-relative path or a Windows path, then we
 		// need to prepend a slash.
 		if u.Path == "" {
-			return invalidDockerURLFormat
+			return invalid
 		} else if u.Path[0] == '/' {
 			result += u.Path
 		} else if u.Path[0] == '~' || isWindowsPath(u.Path) {
 			result += fmt.Sprintf("/%s", u.Path)
 		} else {
-			return invalidDockerURLFormat
+			return invalid
 		}
 	} else if u.Kind == Kind_Forwarding {
 		result += fmt.Sprintf(":%s", u.Path)
@@ -77,20 +380,21 @@ func (u *URL) formatDocker(environmentPrefix string) string {
 	}
 
 	// Add the scheme.
-	result = dockerURLPrefix + result
+	result = prefix + result
 
 	// Add environment variable information if requested.
 	if environmentPrefix != "" {
-		for _, variable := range DockerEnvironmentVariables {
+		for _, variable := range environmentVariables {
 			if value, present := u.Environment[variable]; present {
 				result += fmt.Sprintf("%s%s=%s", environmentPrefix, variable, value)
 			}
 		}
 	}
 
-	// Add parameter information, if requested.
+	// Add parameter information, if requested. The agent directory parameter
+	// is supported by all container-style URLs.
 	if environmentPrefix != "" {
-		for _, name := range dockerParameterNames {
+		for _, name := range parameterNames {
 			if value, present := u.Parameters[name]; present {
 				if value == "" {
 					result += fmt.Sprintf("%s%s=true", environmentPrefix, name)
@@ -99,6 +403,9 @@ func (u *URL) formatDocker(environmentPrefix string) string {
 				}
 			}
 		}
+		if value, present := u.Parameters[AgentDirectoryParameter]; present {
+			result += fmt.Sprintf("%s%s=%s", environmentPrefix, AgentDirectoryParameter, value)
+		}
 	}
 
 	// Done.
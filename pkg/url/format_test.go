@@ -177,6 +177,315 @@ func TestFormatForwardingDocker(t *testing.T) {
 	test.run(t)
 }
 
+func TestFormatNerdctlInvalidEmptyPath(t *testing.T) {
+	test := &formatTestCase{
+		url: &URL{
+			Protocol: Protocol_Nerdctl,
+			Host:     "container",
+			Path:     "",
+		},
+		expected: invalidNerdctlURLFormat,
+	}
+	test.run(t)
+}
+
+func TestFormatNerdctl(t *testing.T) {
+	test := &formatTestCase{
+		url: &URL{
+			Protocol: Protocol_Nerdctl,
+			User:     "user",
+			Host:     "container",
+			Path:     "~/test/path",
+			Environment: map[string]string{
+				"CONTAINERD_NAMESPACE": "k8s.io",
+			},
+		},
+		environmentPrefix: "|",
+		expected:          "nerdctl://user@container/~/test/path|CONTAINERD_NAMESPACE=k8s.io",
+	}
+	test.run(t)
+}
+
+func TestFormatForwardingNerdctl(t *testing.T) {
+	test := &formatTestCase{
+		url: &URL{
+			Kind:     Kind_Forwarding,
+			Protocol: Protocol_Nerdctl,
+			Host:     "container",
+			Path:     "tcp4:localhost:8080",
+		},
+		expected: "nerdctl://container:tcp4:localhost:8080",
+	}
+	test.run(t)
+}
+
+func TestFormatLXD(t *testing.T) {
+	test := &formatTestCase{
+		url: &URL{
+			Protocol: Protocol_LXD,
+			User:     "user",
+			Host:     "instance",
+			Path:     "/test/path",
+			Environment: map[string]string{
+				"LXD_CONF": "/path/to/lxc",
+			},
+		},
+		environmentPrefix: "|",
+		expected:          "lxd://user@instance/test/path|LXD_CONF=/path/to/lxc",
+	}
+	test.run(t)
+}
+
+func TestFormatLXDWithAgentDirectory(t *testing.T) {
+	test := &formatTestCase{
+		url: &URL{
+			Protocol: Protocol_LXD,
+			Host:     "instance",
+			Path:     "/test/path",
+			Parameters: map[string]string{
+				AgentDirectoryParameter: "/tmp/.mutagen",
+			},
+		},
+		environmentPrefix: "|",
+		expected:          "lxd://instance/test/path|agent-directory=/tmp/.mutagen",
+	}
+	test.run(t)
+}
+
+func TestFormatKubernetes(t *testing.T) {
+	test := &formatTestCase{
+		url: &URL{
+			Protocol: Protocol_Kubernetes,
+			Host:     "default/web-0/app",
+			Path:     "~/project",
+			Environment: map[string]string{
+				"KUBECONFIG": "/path/to/kubeconfig",
+			},
+			Parameters: map[string]string{
+				KubernetesContextParameter: "staging",
+			},
+		},
+		environmentPrefix: "|",
+		expected:          "kubernetes://default/web-0/app/~/project|KUBECONFIG=/path/to/kubeconfig|context=staging",
+	}
+	test.run(t)
+}
+
+func TestFormatForwardingKubernetes(t *testing.T) {
+	test := &formatTestCase{
+		url: &URL{
+			Kind:     Kind_Forwarding,
+			Protocol: Protocol_Kubernetes,
+			Host:     "default/web-0/app",
+			Path:     "tcp:localhost:8080",
+		},
+		expected: "kubernetes://default/web-0/app:tcp:localhost:8080",
+	}
+	test.run(t)
+}
+
+func TestFormatWSL(t *testing.T) {
+	test := &formatTestCase{
+		url: &URL{
+			Protocol: Protocol_WSL,
+			Host:     "Ubuntu",
+			Path:     "~/test/path",
+		},
+		environmentPrefix: "|",
+		expected:          "wsl://Ubuntu/~/test/path",
+	}
+	test.run(t)
+}
+
+func TestFormatAzure(t *testing.T) {
+	test := &formatTestCase{
+		url: &URL{
+			Protocol: Protocol_Azure,
+			User:     "azureuser",
+			Host:     "vm-1",
+			Path:     "~/test/path",
+			Environment: map[string]string{
+				"AZURE_DEFAULTS_GROUP": "resources",
+			},
+		},
+		environmentPrefix: "|",
+		expected:          "azure://azureuser@vm-1/~/test/path|AZURE_DEFAULTS_GROUP=resources",
+	}
+	test.run(t)
+}
+
+func TestFormatTeleport(t *testing.T) {
+	test := &formatTestCase{
+		url: &URL{
+			Protocol: Protocol_Teleport,
+			User:     "root",
+			Host:     "node-1",
+			Path:     "/test/path",
+			Environment: map[string]string{
+				"TELEPORT_PROXY": "teleport.example.com:443",
+			},
+		},
+		environmentPrefix: "|",
+		expected:          "teleport://root@node-1/test/path|TELEPORT_PROXY=teleport.example.com:443",
+	}
+	test.run(t)
+}
+
+func TestFormatSFTP(t *testing.T) {
+	test := &formatTestCase{
+		url: &URL{
+			Protocol: Protocol_SFTP,
+			User:     "user",
+			Host:     "example.com",
+			Port:     2222,
+			Path:     "~/test/path",
+		},
+		expected: "sftp://user@example.com:2222/~/test/path",
+	}
+	test.run(t)
+}
+
+func TestFormatSFTPIPv6(t *testing.T) {
+	test := &formatTestCase{
+		url: &URL{
+			Protocol: Protocol_SFTP,
+			Host:     "fe80::1",
+			Path:     "/test/path",
+		},
+		expected: "sftp://[fe80::1]/test/path",
+	}
+	test.run(t)
+}
+
+func TestFormatS3WithEnvironment(t *testing.T) {
+	test := &formatTestCase{
+		url: &URL{
+			Protocol: Protocol_S3,
+			Host:     "bucket",
+			Path:     "/test/prefix",
+			Environment: map[string]string{
+				"AWS_ENDPOINT_URL": "http://localhost:9000",
+			},
+		},
+		environmentPrefix: "|",
+		expected:          "s3://bucket/test/prefix|AWS_ENDPOINT_URL=http://localhost:9000",
+	}
+	test.run(t)
+}
+
+func TestFormatTCP(t *testing.T) {
+	test := &formatTestCase{
+		url: &URL{
+			Protocol: Protocol_TCP,
+			Host:     "fe80::1",
+			Port:     7820,
+			Path:     "~/path",
+			Parameters: map[string]string{
+				TCPKeyFileParameter: "/path/to/key",
+			},
+		},
+		environmentPrefix: "|",
+		expected:          "tcp://[fe80::1]:7820/~/path|key-file=/path/to/key",
+	}
+	test.run(t)
+}
+
+func TestFormatForwardingTCP(t *testing.T) {
+	test := &formatTestCase{
+		url: &URL{
+			Kind:     Kind_Forwarding,
+			Protocol: Protocol_TCP,
+			Host:     "host",
+			Port:     7820,
+			Path:     "tcp:localhost:8080",
+		},
+		expected: "tcp://host:7820:tcp:localhost:8080",
+	}
+	test.run(t)
+}
+
+func TestFormatRendezvous(t *testing.T) {
+	test := &formatTestCase{
+		url: &URL{
+			Protocol: Protocol_Rendezvous,
+			Host:     "office-nas",
+			Path:     "~/path",
+			Parameters: map[string]string{
+				TCPKeyFileParameter: "/path/to/key",
+			},
+		},
+		environmentPrefix: "|",
+		expected:          "rendezvous://office-nas/~/path|key-file=/path/to/key",
+	}
+	test.run(t)
+}
+
+func TestFormatPlugin(t *testing.T) {
+	test := &formatTestCase{
+		url: &URL{
+			Protocol: Protocol_Plugin,
+			Host:     "pod",
+			Path:     "~/path",
+			Parameters: map[string]string{
+				PluginParameter:         "k8s",
+				AgentDirectoryParameter: "/opt/mutagen",
+			},
+		},
+		environmentPrefix: "|",
+		expected:          "plugin+k8s://pod/~/path|agent-directory=/opt/mutagen",
+	}
+	test.run(t)
+}
+
+func TestFormatForwardingPlugin(t *testing.T) {
+	test := &formatTestCase{
+		url: &URL{
+			Kind:     Kind_Forwarding,
+			Protocol: Protocol_Plugin,
+			Host:     "pod",
+			Path:     "tcp:localhost:8080",
+			Parameters: map[string]string{
+				PluginParameter: "k8s",
+			},
+		},
+		expected: "plugin+k8s://pod:tcp:localhost:8080",
+	}
+	test.run(t)
+}
+
+func TestFormatForwardingRendezvous(t *testing.T) {
+	test := &formatTestCase{
+		url: &URL{
+			Kind:     Kind_Forwarding,
+			Protocol: Protocol_Rendezvous,
+			Host:     "office-nas",
+			Path:     "tcp:localhost:8080",
+		},
+		expected: "rendezvous://office-nas:tcp:localhost:8080",
+	}
+	test.run(t)
+}
+
+func TestFormatExecWithEnvironmentAndParameters(t *testing.T) {
+	test := &formatTestCase{
+		url: &URL{
+			Protocol: Protocol_Exec,
+			Host:     "kubectl exec -i pod --",
+			Path:     "~/project",
+			Environment: map[string]string{
+				"KUBECONFIG":     "/path/to/kubeconfig",
+				"DOCKER_CONTEXT": "remote",
+			},
+			Parameters: map[string]string{
+				ExecCopyParameter: "kubectl cp {local} pod:{remote}",
+			},
+		},
+		environmentPrefix: "|",
+		expected:          "exec:'kubectl exec -i pod --':~/project|DOCKER_CONTEXT=remote|KUBECONFIG=/path/to/kubeconfig|copy=kubectl cp {local} pod:{remote}",
+	}
+	test.run(t)
+}
+
 func TestFormatDockerWithUsernameAndHomeRelativePath(t *testing.T) {
 	test := &formatTestCase{
 		url: &URL{
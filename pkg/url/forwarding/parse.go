@@ -3,6 +3,7 @@ package forwarding
 import (
 	"errors"
 	"fmt"
+	"net"
 	"strings"
 )
 
@@ -31,6 +32,49 @@ func Parse(url string) (string, string, error) {
 		return "", "", errors.New("empty address")
 	}
 
+	// If this is a Windows named pipe, then ensure that the address is a valid
+	// named pipe path.
+	if components[0] == "npipe" && !isNamedPipePath(components[1]) {
+		return "", "", fmt.Errorf("invalid named pipe path: %s", components[1])
+	}
+
+	// If the protocol supports port ranges, then ensure that any port range
+	// specified in the address is valid.
+	if IsPortRangeProtocol(components[0]) {
+		if _, err := ParsePortRange(components[1]); err != nil {
+			return "", "", fmt.Errorf("invalid port range: %w", err)
+		}
+	}
+
 	// Success.
 	return components[0], components[1], nil
 }
+
+// IsEphemeralPortAddress returns whether or not the specified protocol and
+// address specify a TCP-based listening address with an ephemeral port (i.e.
+// port 0), in which case the port is chosen by the system when listening.
+func IsEphemeralPortAddress(protocol, address string) bool {
+	if !IsPortRangeProtocol(protocol) && !IsProxyProtocol(protocol) {
+		return false
+	}
+	_, port, err := net.SplitHostPort(address)
+	return err == nil && port == "0"
+}
+
+// isNamedPipePath returns whether or not the specified path is a valid Windows
+// named pipe path of the form \\<server>\pipe\<name>. This check is performed
+// lexically (and case-insensitively) so that paths can be validated on any
+// platform.
+func isNamedPipePath(path string) bool {
+	// Ensure that the path has a server prefix.
+	if !strings.HasPrefix(path, `\\`) {
+		return false
+	}
+
+	// Split the server, pipe namespace, and pipe name.
+	components := strings.SplitN(path[2:], `\`, 3)
+	if len(components) != 3 {
+		return false
+	}
+	return components[0] != "" && strings.EqualFold(components[1], "pipe") && components[2] != ""
+}
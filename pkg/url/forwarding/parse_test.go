@@ -23,6 +23,20 @@ func TestParse(t *testing.T) {
 		{"tcp6:[::1]:3992", "tcp6", "[::1]:3992", false},
 		{"unix:/some/socket.sock", "unix", "/some/socket.sock", false},
 		{`npipe:\\.\pipe\pipe_name`, "npipe", `\\.\pipe\pipe_name`, false},
+		{`npipe:\\server\PIPE\MSSQL$SQLEXPRESS\sql\query`, "npipe", `\\server\PIPE\MSSQL$SQLEXPRESS\sql\query`, false},
+		{`npipe:\\.\pipe\`, "", "", true},
+		{`npipe:\\.\other\pipe_name`, "", "", true},
+		{`npipe:pipe_name`, "", "", true},
+		{`npipe:/tmp/pipe`, "", "", true},
+		{"socks5:localhost:1080", "socks5", "localhost:1080", false},
+		{"socks5:tcp", "socks5", "tcp", false},
+		{"http:localhost:8080", "http", "localhost:8080", false},
+		{"tcp:localhost:9000-9010", "tcp", "localhost:9000-9010", false},
+		{"tcp6:[::1]:9000-9000", "tcp6", "[::1]:9000-9000", false},
+		{"tcp:localhost:9010-9000", "", "", true},
+		{"tcp:localhost:0-10", "", "", true},
+		{"tcp:localhost:9000-70000", "", "", true},
+		{"tcp:localhost:1-2000", "", "", true},
 	}
 
 	// Process test cases.
@@ -50,3 +64,32 @@ func TestParse(t *testing.T) {
 		}
 	}
 }
+
+// TestIsEphemeralPortAddress tests that the IsEphemeralPortAddress function
+// behaves as expected for a variety of test cases.
+func TestIsEphemeralPortAddress(t *testing.T) {
+	// Set up test cases.
+	testCases := []struct {
+		protocol string
+		address  string
+		expected bool
+	}{
+		{"tcp", "localhost:0", true},
+		{"tcp6", "[::1]:0", true},
+		{"tcp", ":0", true},
+		{"socks5", "localhost:0", true},
+		{"tcp", "localhost:8080", false},
+		{"tcp", "localhost:9000-9010", false},
+		{"unix", "/tmp/socket:0", false},
+		{"socks5", "tcp", false},
+	}
+
+	// Process test cases.
+	for _, testCase := range testCases {
+		if ephemeral := IsEphemeralPortAddress(testCase.protocol, testCase.address); ephemeral != testCase.expected {
+			t.Errorf("ephemeral port status for %s:%s does not match expected: %t != %t",
+				testCase.protocol, testCase.address, ephemeral, testCase.expected,
+			)
+		}
+	}
+}
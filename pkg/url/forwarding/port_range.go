@@ -0,0 +1,93 @@
+package forwarding
+
+import (
+	"errors"
+	"fmt"
+	"net"
+	"strconv"
+	"strings"
+)
+
+const (
+	// MaximumPortRangeSize is the maximum number of ports that a port range
+	// may span.
+	MaximumPortRangeSize = 1024
+)
+
+// PortRange represents a contiguous range of TCP ports on a host.
+type PortRange struct {
+	// Host is the host component of the range.
+	Host string
+	// First is the first port in the range.
+	First uint16
+	// Last is the last port in the range (inclusive).
+	Last uint16
+}
+
+// IsPortRangeProtocol returns whether or not the specified protocol supports
+// port range addresses.
+func IsPortRangeProtocol(protocol string) bool {
+	return protocol == "tcp" || protocol == "tcp4" || protocol == "tcp6"
+}
+
+// ParsePortRange parses an address of the form "<host>:<first>-<last>" into a
+// port range. If the address doesn't specify a port range, then this function
+// returns nil and no error.
+func ParsePortRange(address string) (*PortRange, error) {
+	// Split the host and port components. If this fails, then the address
+	// isn't a port range, though it may still be a valid address.
+	host, ports, err := net.SplitHostPort(address)
+	if err != nil {
+		return nil, nil
+	}
+
+	// Split the range bounds, if present.
+	first, last, ok := strings.Cut(ports, "-")
+	if !ok {
+		return nil, nil
+	}
+
+	// Parse the range bounds.
+	firstPort, err := strconv.ParseUint(first, 10, 16)
+	if err != nil || firstPort == 0 {
+		return nil, fmt.Errorf("invalid first port in range: %s", first)
+	}
+	lastPort, err := strconv.ParseUint(last, 10, 16)
+	if err != nil || lastPort == 0 {
+		return nil, fmt.Errorf("invalid last port in range: %s", last)
+	}
+
+	// Validate the range extent.
+	if lastPort < firstPort {
+		return nil, errors.New("port range is inverted")
+	} else if lastPort-firstPort+1 > MaximumPortRangeSize {
+		return nil, fmt.Errorf("port range exceeds maximum size (%d ports)", MaximumPortRangeSize)
+	}
+
+	// Success.
+	return &PortRange{
+		Host:  host,
+		First: uint16(firstPort),
+		Last:  uint16(lastPort),
+	}, nil
+}
+
+// Size returns the number of ports in the range.
+func (r *PortRange) Size() int {
+	return int(r.Last) - int(r.First) + 1
+}
+
+// Address returns the address for the port at the specified offset within the
+// range. The offset must be less than the size of the range.
+func (r *PortRange) Address(offset int) string {
+	return net.JoinHostPort(r.Host, strconv.Itoa(int(r.First)+offset))
+}
+
+// Offset returns the offset of the specified port within the range and whether
+// or not the port falls within the range.
+func (r *PortRange) Offset(port int) (int, bool) {
+	if port < int(r.First) || port > int(r.Last) {
+		return 0, false
+	}
+	return port - int(r.First), true
+}
@@ -0,0 +1,66 @@
+package forwarding
+
+import (
+	"testing"
+)
+
+// TestParsePortRange tests that the ParsePortRange function behaves as
+// expected for a variety of test cases.
+func TestParsePortRange(t *testing.T) {
+	// Set up test cases.
+	testCases := []struct {
+		address       string
+		expected      *PortRange
+		expectFailure bool
+	}{
+		{"localhost:9000", nil, false},
+		{"/some/socket.sock", nil, false},
+		{"localhost:9000-9010", &PortRange{"localhost", 9000, 9010}, false},
+		{":9000-9010", &PortRange{"", 9000, 9010}, false},
+		{"[::1]:1-1024", &PortRange{"::1", 1, 1024}, false},
+		{"localhost:9000-", nil, true},
+		{"localhost:-9000", nil, true},
+		{"localhost:a-b", nil, true},
+		{"localhost:9010-9000", nil, true},
+		{"localhost:1-1025", nil, true},
+	}
+
+	// Process test cases.
+	for _, testCase := range testCases {
+		portRange, err := ParsePortRange(testCase.address)
+		if err != nil {
+			if !testCase.expectFailure {
+				t.Errorf("parse failed for address (%s): %v", testCase.address, err)
+			}
+			continue
+		} else if testCase.expectFailure {
+			t.Error("parse succeeded unexpectedly for address:", testCase.address)
+			continue
+		}
+		if (portRange == nil) != (testCase.expected == nil) {
+			t.Errorf("port range presence for address (%s) does not match expected", testCase.address)
+		} else if portRange != nil && *portRange != *testCase.expected {
+			t.Errorf("port range for address (%s) does not match expected: %v != %v",
+				testCase.address, *portRange, *testCase.expected,
+			)
+		}
+	}
+}
+
+// TestPortRangeAddressing tests port range sizing, addressing, and offset
+// computation.
+func TestPortRangeAddressing(t *testing.T) {
+	portRange := &PortRange{"::1", 9000, 9010}
+	if size := portRange.Size(); size != 11 {
+		t.Error("port range size does not match expected:", size, "!=", 11)
+	}
+	if address := portRange.Address(5); address != "[::1]:9005" {
+		t.Error("port range address does not match expected:", address, "!=", "[::1]:9005")
+	}
+	if offset, ok := portRange.Offset(9010); !ok || offset != 10 {
+		t.Error("port range offset does not match expected:", offset, ok)
+	}
+	if _, ok := portRange.Offset(9011); ok {
+		t.Error("port outside range reported as within range")
+	}
+}
@@ -14,7 +14,21 @@ func IsValidProtocol(protocol string) bool {
 		return true
 	case "npipe":
 		return true
+	case "socks5":
+		return true
+	case "http":
+		return true
 	default:
 		return false
 	}
 }
+
+// IsProxyProtocol returns whether or not the specified protocol is a proxy
+// protocol. Proxy protocols are special: as a source, their address is a TCP
+// listening address at which a proxy is served, and as a destination, their
+// address is the network (e.g. "tcp") used to dial the targets requested by
+// proxy clients (in the case of "socks5") or selected by routing rules (in the
+// case of "http").
+func IsProxyProtocol(protocol string) bool {
+	return protocol == "socks5" || protocol == "http"
+}
@@ -20,6 +20,8 @@ func TestIsValidProtocol(t *testing.T) {
 		{"tcp6", true},
 		{"unix", true},
 		{"npipe", true},
+		{"socks5", true},
+		{"http", true},
 	}
 
 	// Process test cases.
@@ -29,3 +31,26 @@ func TestIsValidProtocol(t *testing.T) {
 		}
 	}
 }
+
+// TestIsProxyProtocol tests that the IsProxyProtocol function behaves as
+// expected for a variety of test cases.
+func TestIsProxyProtocol(t *testing.T) {
+	// Set up test cases.
+	testCases := []struct {
+		protocol string
+		expected bool
+	}{
+		{"", false},
+		{"tcp", false},
+		{"unix", false},
+		{"socks5", true},
+		{"http", true},
+	}
+
+	// Process test cases.
+	for _, testCase := range testCases {
+		if proxy := IsProxyProtocol(testCase.protocol); proxy != testCase.expected {
+			t.Error("protocol proxy status does not match expected:", proxy, "!=", testCase.expected)
+		}
+	}
+}
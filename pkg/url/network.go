@@ -0,0 +1,39 @@
+package url
+
+import (
+	"strings"
+)
+
+// dockerHostUsesNetwork returns whether or not a Docker daemon host
+// specification (as used by DOCKER_HOST or the --host flag) refers to a daemon
+// that's reached over the network (as opposed to a local socket or pipe).
+func dockerHostUsesNetwork(host string) bool {
+	return host != "" &&
+		!strings.HasPrefix(host, "unix://") &&
+		!strings.HasPrefix(host, "npipe://") &&
+		!strings.HasPrefix(host, "fd://")
+}
+
+// UsesNetwork returns whether or not the transport used to reach the resource
+// depends on host network connectivity, i.e. whether or not its connections
+// are likely to be invalidated by a host network configuration change or a
+// system suspension. Resources reached via local processes (such as local
+// container runtimes, WSL, or user-specified commands) are treated as not
+// depending on the network. For protocols where this can't be determined (i.e.
+// plugins), network usage is assumed.
+func (u *URL) UsesNetwork() bool {
+	switch u.Protocol {
+	case Protocol_Local, Protocol_Nerdctl, Protocol_WSL, Protocol_Exec:
+		return false
+	case Protocol_Docker:
+		if host, ok := u.Parameters["host"]; ok {
+			return dockerHostUsesNetwork(host)
+		}
+		return dockerHostUsesNetwork(u.Environment["DOCKER_HOST"])
+	case Protocol_LXD:
+		remote, _, ok := strings.Cut(u.Host, ":")
+		return ok && remote != "local"
+	default:
+		return true
+	}
+}
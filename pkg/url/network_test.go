@@ -0,0 +1,52 @@
+package url
+
+import (
+	"testing"
+)
+
+// TestURLUsesNetwork tests URL.UsesNetwork.
+func TestURLUsesNetwork(t *testing.T) {
+	// Set up test cases.
+	testCases := []struct {
+		// url is the URL to classify.
+		url *URL
+		// expected is the expected classification.
+		expected bool
+	}{
+		{&URL{Protocol: Protocol_Local}, false},
+		{&URL{Protocol: Protocol_WSL, Host: "Ubuntu"}, false},
+		{&URL{Protocol: Protocol_Exec, Host: "sh -c"}, false},
+		{&URL{Protocol: Protocol_Nerdctl, Host: "container"}, false},
+		{&URL{Protocol: Protocol_Docker, Host: "container"}, false},
+		{&URL{Protocol: Protocol_Docker, Host: "container", Environment: map[string]string{"DOCKER_HOST": "unix:///var/run/docker.sock"}}, false},
+		{&URL{Protocol: Protocol_Docker, Host: "container", Environment: map[string]string{"DOCKER_HOST": "npipe:////./pipe/docker_engine"}}, false},
+		{&URL{Protocol: Protocol_Docker, Host: "container", Environment: map[string]string{"DOCKER_HOST": "tcp://build:2376"}}, true},
+		{&URL{Protocol: Protocol_Docker, Host: "container", Environment: map[string]string{"DOCKER_HOST": "ssh://user@build"}}, true},
+		{&URL{Protocol: Protocol_Docker, Host: "container", Parameters: map[string]string{"host": "tcp://build:2376"}}, true},
+		{
+			&URL{
+				Protocol:    Protocol_Docker,
+				Host:        "container",
+				Environment: map[string]string{"DOCKER_HOST": "tcp://build:2376"},
+				Parameters:  map[string]string{"host": "unix:///var/run/docker.sock"},
+			},
+			false,
+		},
+		{&URL{Protocol: Protocol_LXD, Host: "instance"}, false},
+		{&URL{Protocol: Protocol_LXD, Host: "local:instance"}, false},
+		{&URL{Protocol: Protocol_LXD, Host: "cluster:instance"}, true},
+		{&URL{Protocol: Protocol_SSH, Host: "host"}, true},
+		{&URL{Protocol: Protocol_Kubernetes, Host: "default/web-0/app"}, true},
+		{&URL{Protocol: Protocol_Plugin, Host: "plugin"}, true},
+		{&URL{Protocol: Protocol_TCP, Host: "host"}, true},
+	}
+
+	// Process test cases.
+	for i, testCase := range testCases {
+		if usesNetwork := testCase.url.UsesNetwork(); usesNetwork != testCase.expected {
+			t.Errorf("test index %d: network usage (%t) does not match expected (%t)",
+				i, usesNetwork, testCase.expected,
+			)
+		}
+	}
+}
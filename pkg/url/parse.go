@@ -21,13 +21,37 @@ func Parse(raw string, kind Kind, first bool) (*URL, error) {
 
 	// Dispatch URL parsing based on type. We have to be careful about the
 	// ordering here because URLs may be classified as multiple types (e.g. a
-	// Docker URL would also be classified as an SCP-style SSH URL), but we only
+	// container URL would also be classified as an SCP-style SSH URL), but we only
 	// want them to be parsed according to the better and more specific match.
 	// If we don't match anything, we assume the URL is a local path.
 	if isDockerURL(raw) {
 		return parseDocker(raw, kind, first)
+	} else if isNerdctlURL(raw) {
+		return parseNerdctl(raw, kind, first)
+	} else if isKubernetesURL(raw) {
+		return parseKubernetes(raw, kind, first)
+	} else if isLXDURL(raw) {
+		return parseLXD(raw, kind, first)
+	} else if isWSLURL(raw) {
+		return parseWSL(raw, kind, first)
+	} else if isAzureURL(raw) {
+		return parseAzure(raw, kind, first)
+	} else if isTeleportURL(raw) {
+		return parseTeleport(raw, kind, first)
+	} else if isSFTPURL(raw) {
+		return parseSFTP(raw, kind)
+	} else if isS3URL(raw) {
+		return parseS3(raw, kind, first)
+	} else if isPluginURL(raw) {
+		return parsePlugin(raw, kind, first)
+	} else if isRendezvousURL(raw) {
+		return parseRendezvous(raw, kind, first)
+	} else if isTCPURL(raw) {
+		return parseTCP(raw, kind, first)
+	} else if isExecURL(raw) {
+		return parseExec(raw, kind, first)
 	} else if isSCPSSHURL(raw, kind) {
-		return parseSCPSSH(raw, kind)
+		return parseSCPSSH(raw, kind, first)
 	} else {
 		return parseLocal(raw, kind)
 	}
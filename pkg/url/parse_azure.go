@@ -0,0 +1,30 @@
+package url
+
+import (
+	"strings"
+)
+
+// azureURLPrefix is the lowercase version of the Azure URL prefix.
+const azureURLPrefix = "azure://"
+
+// AzureEnvironmentVariables is a list of Azure CLI environment variables that
+// should be locked in to Azure URLs at parse time. In particular, the
+// AZURE_DEFAULTS_GROUP variable determines the resource group in which the
+// virtual machine is located.
+var AzureEnvironmentVariables = []string{
+	"AZURE_CONFIG_DIR",
+	"AZURE_DEFAULTS_GROUP",
+}
+
+// isAzureURL checks whether or not a URL is an Azure URL. It requires the
+// presence of an Azure protocol prefix.
+func isAzureURL(raw string) bool {
+	return strings.HasPrefix(strings.ToLower(raw), azureURLPrefix)
+}
+
+// parseAzure parses an Azure URL. Azure URLs use the same format as Docker URLs,
+// with the container name specifying a virtual machine name and the username
+// specifying the local user on the virtual machine.
+func parseAzure(raw string, kind Kind, first bool) (*URL, error) {
+	return parseContainer(raw[len(azureURLPrefix):], kind, first, Protocol_Azure, AzureEnvironmentVariables)
+}
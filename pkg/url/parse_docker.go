@@ -3,6 +3,8 @@ package url
 import (
 	"errors"
 	"fmt"
+	neturl "net/url"
+	"strconv"
 	"strings"
 
 	"github.com/mutagen-io/mutagen/pkg/url/forwarding"
@@ -24,6 +26,8 @@ var DockerEnvironmentVariables = []string{
 }
 
 // dockerParameterNames is a list of supported Docker command line parameters.
+// The first set are top-level daemon connection flags, while the second set are
+// flags for the exec command used to invoke agents.
 var dockerParameterNames = []string{
 	"config",
 	"context",
@@ -33,6 +37,9 @@ var dockerParameterNames = []string{
 	"tlscert",
 	"tlskey",
 	"tlsverify",
+	"user",
+	"workdir",
+	"privileged",
 }
 
 // isDockerURL checks whether or not a URL is a Docker URL. It requires the
@@ -41,11 +48,146 @@ func isDockerURL(raw string) bool {
 	return strings.HasPrefix(strings.ToLower(raw), dockerURLPrefix)
 }
 
-// parseDocker parses a Docker URL.
+// dockerExecEnvironmentVariables maps Docker exec parameter names to the
+// (Mutagen-specific) environment variables that can be used to specify them at
+// parse time.
+var dockerExecEnvironmentVariables = map[string]string{
+	"user":       "DOCKER_USER",
+	"workdir":    "DOCKER_WORKDIR",
+	"privileged": "DOCKER_PRIVILEGED",
+}
+
+// isDockerParameterName returns whether or not the specified name is a
+// supported Docker command line parameter.
+func isDockerParameterName(name string) bool {
+	for _, n := range dockerParameterNames {
+		if n == name {
+			return true
+		}
+	}
+	return false
+}
+
+// isDockerFlagParameterName returns whether or not the specified Docker
+// command line parameter is a flag (i.e. a parameter without a value).
+func isDockerFlagParameterName(name string) bool {
+	return name == "tls" || name == "tlsverify" || name == "privileged"
+}
+
+// splitDockerQuery splits a query string suffix containing Docker command line
+// parameters (e.g. "?context=remote") from a raw Docker URL.
+func splitDockerQuery(raw string) (string, map[string]string, error) {
+	return splitParameterQuery(raw, isDockerParameterName, isDockerFlagParameterName)
+}
+
+// splitParameterQuery splits a query string suffix containing protocol-specific
+// parameters from a raw URL. A suffix is only treated as a query if it parses
+// correctly and specifies only supported parameters (each at most once), since
+// paths may legitimately contain question marks. Flag parameters may be
+// specified without a value or with a value of "true". If no query is present,
+// then the raw URL is returned unmodified with nil parameters.
+func splitParameterQuery(raw string, isParameterName, isFlagParameterName func(string) bool) (string, map[string]string, error) {
+	// Find the last question mark, if any.
+	index := strings.LastIndexByte(raw, '?')
+	if index == -1 {
+		return raw, nil, nil
+	}
+
+	// Parse the query and ensure that it only specifies supported parameters.
+	values, err := neturl.ParseQuery(raw[index+1:])
+	if err != nil || len(values) == 0 {
+		return raw, nil, nil
+	}
+	for name := range values {
+		if !isParameterName(name) {
+			return raw, nil, nil
+		}
+	}
+
+	// Convert the parameters.
+	parameters := make(map[string]string, len(values))
+	for name, value := range values {
+		if len(value) != 1 {
+			return "", nil, fmt.Errorf("parameter specified multiple times: %s", name)
+		} else if isFlagParameterName(name) {
+			if !(value[0] == "" || value[0] == "true") {
+				return "", nil, fmt.Errorf("invalid flag parameter value: %s=%s", name, value[0])
+			}
+			parameters[name] = ""
+		} else if value[0] == "" {
+			return "", nil, fmt.Errorf("empty parameter value: %s", name)
+		} else {
+			parameters[name] = value[0]
+		}
+	}
+
+	// Success.
+	return raw[:index], parameters, nil
+}
+
+// parseDocker parses a Docker URL. In addition to the standard container-style
+// URL format, Docker URLs may specify Docker command line parameters (e.g. the
+// Docker context to use or the user under which to run the agent) as a query
+// string suffix, e.g. docker://container/path?context=remote. Docker exec
+// parameters may also be specified in the environment.
 func parseDocker(raw string, kind Kind, first bool) (*URL, error) {
-	// Strip off the prefix.
-	raw = raw[len(dockerURLPrefix):]
+	// Split off any Docker parameters.
+	raw, parameters, err := splitDockerQuery(raw[len(dockerURLPrefix):])
+	if err != nil {
+		return nil, fmt.Errorf("invalid Docker parameters: %w", err)
+	}
+
+	// Parse the container-style URL.
+	url, err := parseContainer(raw, kind, first, Protocol_Docker, DockerEnvironmentVariables)
+	if err != nil {
+		return nil, err
+	}
+
+	// Lock in any Docker exec settings that have been specified in the
+	// environment, unless they've been specified explicitly in the URL (which
+	// includes the user being specified as part of the container name).
+	for name, variable := range dockerExecEnvironmentVariables {
+		if _, ok := parameters[name]; ok {
+			continue
+		} else if name == "user" && url.User != "" {
+			continue
+		}
+		value, ok := getMutagenEnvironmentVariable(variable, kind, first)
+		if !ok || value == "" {
+			continue
+		}
+		if isDockerFlagParameterName(name) {
+			if enabled, err := strconv.ParseBool(value); err != nil {
+				return nil, fmt.Errorf("invalid %s specification in environment: %s", name, value)
+			} else if !enabled {
+				continue
+			}
+			value = ""
+		}
+		if parameters == nil {
+			parameters = make(map[string]string)
+		}
+		parameters[name] = value
+	}
+
+	// Merge in the Docker parameters.
+	if len(parameters) > 0 {
+		if url.Parameters == nil {
+			url.Parameters = make(map[string]string, len(parameters))
+		}
+		for name, value := range parameters {
+			url.Parameters[name] = value
+		}
+	}
 
+	// Success.
+	return url, nil
+}
+
+// parseContainer parses the prefix-stripped portion of a container-style URL
+// (e.g. a Docker, LXD, or Azure URL), which share a common format. The specified
+// environment variables are locked in to the resulting URL.
+func parseContainer(raw string, kind Kind, first bool, protocol Protocol, environmentVariables []string) (*URL, error) {
 	// Determine the character that splits the container name from the path or
 	// forwarding endpoint component.
 	var splitCharacter rune
@@ -134,23 +276,30 @@ func parseDocker(raw string, kind Kind, first bool) (*URL, error) {
 		panic("unhandled URL kind")
 	}
 
-	// Store any Docker environment variables that we need to preserve. We only
-	// store variables that are actually present, because Docker behavior will
-	// vary depending on whether a variable is unset vs. set but empty.
+	// Store any environment variables that we need to preserve. We only store
+	// variables that are actually present, because container tool behavior
+	// will vary depending on whether a variable is unset vs. set but empty.
 	environment := make(map[string]string)
-	for _, variable := range DockerEnvironmentVariables {
+	for _, variable := range environmentVariables {
 		if value, present := getEnvironmentVariable(variable, kind, first); present {
 			environment[variable] = value
 		}
 	}
 
+	// Lock in any agent directory that's been specified in the environment.
+	parameters, err := lockInAgentDirectory(nil, kind, first)
+	if err != nil {
+		return nil, err
+	}
+
 	// Success.
 	return &URL{
 		Kind:        kind,
-		Protocol:    Protocol_Docker,
+		Protocol:    protocol,
 		User:        username,
 		Host:        container,
 		Path:        path,
 		Environment: environment,
+		Parameters:  parameters,
 	}, nil
 }
@@ -0,0 +1,191 @@
+package url
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/mutagen-io/mutagen/pkg/url/forwarding"
+)
+
+const (
+	// ExecCopyParameter is the name of the URL parameter that specifies the
+	// command template used to copy files (i.e. the agent binary) to the remote
+	// for exec URLs. The template must contain the {local} and {remote}
+	// placeholders, which are replaced by the local source path and remote
+	// destination path, respectively. If unset, the agent must already be
+	// installed on the remote.
+	ExecCopyParameter = "copy"
+	// ExecCopyLocalPlaceholder is the placeholder for the local source path in
+	// exec copy command templates.
+	ExecCopyLocalPlaceholder = "{local}"
+	// ExecCopyRemotePlaceholder is the placeholder for the remote destination
+	// path in exec copy command templates.
+	ExecCopyRemotePlaceholder = "{remote}"
+
+	// execCopyEnvironmentVariable is the (Mutagen-specific) environment
+	// variable that's used to specify the copy command template at parse time.
+	execCopyEnvironmentVariable = "EXEC_COPY"
+	// execEnvironmentEnvironmentVariable is the (Mutagen-specific) environment
+	// variable that's used to specify the environment variables to set for
+	// exec transport commands at parse time. Its value is a comma-separated
+	// list of entries, each of which is either of the form NAME=value (in which
+	// case the value is used directly) or NAME (in which case the value is
+	// locked in from the current environment, if present).
+	execEnvironmentEnvironmentVariable = "EXEC_ENVIRONMENT"
+)
+
+// execURLPrefix is the lowercase version of the exec URL prefix.
+const execURLPrefix = "exec:"
+
+// IsValidExecCopyCommand returns whether or not the specified exec copy command
+// template is valid. The template must contain both placeholders.
+func IsValidExecCopyCommand(template string) bool {
+	return strings.TrimSpace(template) != "" &&
+		strings.Contains(template, ExecCopyLocalPlaceholder) &&
+		strings.Contains(template, ExecCopyRemotePlaceholder)
+}
+
+// ensureExecParameterValid ensures that an exec URL parameter is valid.
+func ensureExecParameterValid(name, value string) error {
+	switch name {
+	case ExecCopyParameter:
+		if !IsValidExecCopyCommand(value) {
+			return fmt.Errorf("invalid copy command: %s", value)
+		}
+	case AgentDirectoryParameter:
+		if !IsValidAgentDirectory(value) {
+			return fmt.Errorf("invalid agent directory: %s", value)
+		}
+	default:
+		return fmt.Errorf("unknown parameter: %s", name)
+	}
+	return nil
+}
+
+// isValidExecEnvironmentVariableName returns whether or not the specified
+// environment variable name is valid for use in exec URLs.
+func isValidExecEnvironmentVariableName(name string) bool {
+	return name != "" && !strings.ContainsAny(name, "=, \t\r\n")
+}
+
+// parseExecEnvironment parses an exec environment specification (as described
+// for execEnvironmentEnvironmentVariable), resolving unassigned entries using
+// the current environment.
+func parseExecEnvironment(specification string, kind Kind, first bool) (map[string]string, error) {
+	environment := make(map[string]string)
+	for _, entry := range strings.Split(specification, ",") {
+		if entry = strings.TrimSpace(entry); entry == "" {
+			continue
+		}
+		name, value, assigned := strings.Cut(entry, "=")
+		if !isValidExecEnvironmentVariableName(name) {
+			return nil, fmt.Errorf("invalid environment variable name: %s", name)
+		}
+		if assigned {
+			environment[name] = value
+		} else if value, present := getEnvironmentVariable(name, kind, first); present {
+			environment[name] = value
+		}
+	}
+	return environment, nil
+}
+
+// isExecURL checks whether or not a URL is an exec URL. It requires the
+// presence of an exec protocol prefix followed by a quoted command.
+func isExecURL(raw string) bool {
+	if !strings.HasPrefix(strings.ToLower(raw), execURLPrefix) {
+		return false
+	}
+	raw = raw[len(execURLPrefix):]
+	return strings.HasPrefix(raw, "'") || strings.HasPrefix(raw, "\"")
+}
+
+// parseExec parses an exec URL. Exec URLs take the form exec:'command':path
+// (or exec:'command':endpoint for forwarding URLs), where command is a command
+// prefix that can be used to execute commands on the remote (e.g. "kubectl
+// exec -i pod --") or a command template that positions the agent command
+// using placeholders (e.g. "kubectl exec -i pod -- {{.AgentCommand}}"). The
+// command may be enclosed in either single or double quotes, and it is lexed by
+// splitting on whitespace. Paths may be absolute POSIX or Windows paths or
+// home-directory-relative paths.
+func parseExec(raw string, kind Kind, first bool) (*URL, error) {
+	// Strip off the prefix.
+	raw = raw[len(execURLPrefix):]
+
+	// Extract the quoted command.
+	quote := raw[0]
+	closing := strings.IndexByte(raw[1:], quote)
+	if closing == -1 {
+		return nil, errors.New("unterminated command")
+	}
+	command, raw := raw[1:closing+1], raw[closing+2:]
+	if strings.TrimSpace(command) == "" {
+		return nil, errors.New("empty command")
+	} else if strings.ContainsAny(command, "'\"") {
+		return nil, errors.New("quotes within command")
+	}
+
+	// Extract the path or forwarding endpoint.
+	if raw == "" || raw[0] != ':' {
+		if kind == Kind_Synchronization {
+			return nil, errors.New("missing path")
+		} else if kind == Kind_Forwarding {
+			return nil, errors.New("missing forwarding endpoint")
+		} else {
+			panic("unhandled URL kind")
+		}
+	}
+	path := raw[1:]
+
+	// Perform path processing based on URL kind.
+	if kind == Kind_Synchronization {
+		if path == "" {
+			return nil, errors.New("empty path")
+		} else if !(path[0] == '/' || path[0] == '~' || isWindowsPath(path)) {
+			return nil, errors.New("invalid path")
+		}
+	} else if kind == Kind_Forwarding {
+		if _, _, err := forwarding.Parse(path); err != nil {
+			return nil, fmt.Errorf("invalid forwarding endpoint URL: %w", err)
+		}
+	} else {
+		panic("unhandled URL kind")
+	}
+
+	// Lock in any copy command that's been specified in the environment.
+	var parameters map[string]string
+	if template, ok := getMutagenEnvironmentVariable(execCopyEnvironmentVariable, kind, first); ok && template != "" {
+		if !IsValidExecCopyCommand(template) {
+			return nil, fmt.Errorf("invalid copy command specified in environment: %s", template)
+		}
+		parameters = map[string]string{ExecCopyParameter: template}
+	}
+
+	// Lock in any agent directory that's been specified in the environment.
+	parameters, err := lockInAgentDirectory(parameters, kind, first)
+	if err != nil {
+		return nil, err
+	}
+
+	// Lock in any environment variables that should be set for transport
+	// commands. We only store variables that are actually present, since the
+	// behavior of the transport command may vary depending on whether a
+	// variable is unset vs. set but empty.
+	var environment map[string]string
+	if specification, ok := getMutagenEnvironmentVariable(execEnvironmentEnvironmentVariable, kind, first); ok {
+		if environment, err = parseExecEnvironment(specification, kind, first); err != nil {
+			return nil, fmt.Errorf("invalid environment specification: %w", err)
+		}
+	}
+
+	// Success.
+	return &URL{
+		Kind:        kind,
+		Protocol:    Protocol_Exec,
+		Host:        command,
+		Path:        path,
+		Environment: environment,
+		Parameters:  parameters,
+	}, nil
+}
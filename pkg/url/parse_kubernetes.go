@@ -0,0 +1,188 @@
+package url
+
+import (
+	"errors"
+	"fmt"
+	neturl "net/url"
+	"strings"
+)
+
+const (
+	// KubernetesContextParameter is the name of the URL parameter that
+	// specifies the kubeconfig context to use for Kubernetes URLs. If unset,
+	// the current context is used.
+	KubernetesContextParameter = "context"
+
+	// kubernetesContextEnvironmentVariable is the (Mutagen-specific)
+	// environment variable that's used to specify the kubeconfig context at
+	// parse time.
+	kubernetesContextEnvironmentVariable = "KUBERNETES_CONTEXT"
+)
+
+const (
+	// kubernetesDeploymentPrefix is the prefix that identifies a deployment
+	// name in the pod component of a Kubernetes URL target.
+	kubernetesDeploymentPrefix = "deployment:"
+	// kubernetesSelectorPrefix is the prefix that identifies a label selector
+	// in the pod component of a Kubernetes URL target.
+	kubernetesSelectorPrefix = "selector:"
+)
+
+// kubernetesURLPrefix is the lowercase version of the Kubernetes URL prefix.
+const kubernetesURLPrefix = "kubernetes://"
+
+// KubernetesEnvironmentVariables is a list of Kubernetes client environment
+// variables that should be locked in to Kubernetes URLs at parse time.
+var KubernetesEnvironmentVariables = []string{
+	"KUBECONFIG",
+}
+
+// kubernetesParameterNames is a list of supported Kubernetes URL parameters
+// (excluding the agent directory parameter).
+var kubernetesParameterNames = []string{
+	KubernetesContextParameter,
+}
+
+// isKubernetesURL checks whether or not a URL is a Kubernetes URL. It requires
+// the presence of a Kubernetes protocol prefix.
+func isKubernetesURL(raw string) bool {
+	return strings.HasPrefix(strings.ToLower(raw), kubernetesURLPrefix)
+}
+
+// isValidKubernetesName returns whether or not the specified name is a valid
+// Kubernetes namespace, pod, or container name, all of which are restricted to
+// (at most) DNS subdomain names.
+func isValidKubernetesName(name string) bool {
+	if name == "" || len(name) > 253 {
+		return false
+	}
+	for _, r := range name {
+		if !(('a' <= r && r <= 'z') || ('0' <= r && r <= '9') || r == '-' || r == '.') {
+			return false
+		}
+	}
+	return true
+}
+
+// KubernetesTarget is a parsed Kubernetes URL target. Exactly one of Pod,
+// Deployment, and Selector is set.
+type KubernetesTarget struct {
+	// Namespace is the namespace containing the target pod.
+	Namespace string
+	// Pod is the target pod name.
+	Pod string
+	// Deployment is the name of the deployment whose pods should be targeted.
+	Deployment string
+	// Selector is a label selector identifying the pods to target.
+	Selector string
+	// Container is the target container name within the pod.
+	Container string
+}
+
+// ParseKubernetesTarget parses a Kubernetes URL target (as stored in the Host
+// field) of the form namespace/pod/container. The pod component may be a pod
+// name, a deployment name prefixed with "deployment:", or a label selector
+// prefixed with "selector:". Since slashes separate target components, label
+// selectors are percent-decoded, allowing prefixed label keys to be specified
+// (e.g. selector:app.kubernetes.io%2Fname=web).
+func ParseKubernetesTarget(target string) (*KubernetesTarget, error) {
+	// Split the target.
+	components := strings.Split(target, "/")
+	if len(components) != 3 {
+		return nil, errors.New("target must be of the form namespace/pod/container")
+	}
+	result := &KubernetesTarget{
+		Namespace: components[0],
+		Container: components[2],
+	}
+	if !isValidKubernetesName(result.Namespace) {
+		return nil, fmt.Errorf("invalid name: %s", result.Namespace)
+	} else if !isValidKubernetesName(result.Container) {
+		return nil, fmt.Errorf("invalid name: %s", result.Container)
+	}
+
+	// Parse the pod specification.
+	pod := components[1]
+	if strings.HasPrefix(pod, kubernetesDeploymentPrefix) {
+		result.Deployment = pod[len(kubernetesDeploymentPrefix):]
+		if !isValidKubernetesName(result.Deployment) {
+			return nil, fmt.Errorf("invalid deployment name: %s", result.Deployment)
+		}
+	} else if strings.HasPrefix(pod, kubernetesSelectorPrefix) {
+		selector, err := neturl.PathUnescape(pod[len(kubernetesSelectorPrefix):])
+		if err != nil {
+			return nil, fmt.Errorf("invalid label selector encoding: %w", err)
+		} else if strings.TrimSpace(selector) == "" {
+			return nil, errors.New("empty label selector")
+		}
+		result.Selector = selector
+	} else if !isValidKubernetesName(pod) {
+		return nil, fmt.Errorf("invalid name: %s", pod)
+	} else {
+		result.Pod = pod
+	}
+
+	// Success.
+	return result, nil
+}
+
+// parseKubernetes parses a Kubernetes URL. Kubernetes URLs take the form
+// kubernetes://namespace/pod/container/path (or
+// kubernetes://namespace/pod/container:endpoint for forwarding URLs), where the
+// namespace, pod, and container components are stored (joined by slashes) in
+// the Host field. The pod may also be identified by deployment or label
+// selector (see ParseKubernetesTarget). The kubeconfig context may be specified as a query string
+// suffix (e.g. ?context=staging) or in the environment.
+func parseKubernetes(raw string, kind Kind, first bool) (*URL, error) {
+	// Split off any Kubernetes parameters.
+	raw, parameters, err := splitParameterQuery(
+		raw[len(kubernetesURLPrefix):],
+		func(name string) bool { return name == KubernetesContextParameter },
+		func(string) bool { return false },
+	)
+	if err != nil {
+		return nil, fmt.Errorf("invalid Kubernetes parameters: %w", err)
+	}
+
+	// Split off the namespace and pod names. The container name, path, and
+	// forwarding endpoint are handled as for other container-style URLs.
+	namespace, raw, ok := strings.Cut(raw, "/")
+	if !ok || namespace == "" {
+		return nil, errors.New("missing namespace")
+	}
+	pod, raw, ok := strings.Cut(raw, "/")
+	if !ok || pod == "" {
+		return nil, errors.New("missing pod name")
+	}
+
+	// Parse the container-style URL.
+	url, err := parseContainer(raw, kind, first, Protocol_Kubernetes, KubernetesEnvironmentVariables)
+	if err != nil {
+		return nil, err
+	}
+	url.Host = namespace + "/" + pod + "/" + url.Host
+
+	// Lock in the kubeconfig context if it's been specified in the environment
+	// but not in the URL.
+	if _, ok := parameters[KubernetesContextParameter]; !ok {
+		if value, ok := getMutagenEnvironmentVariable(kubernetesContextEnvironmentVariable, kind, first); ok && value != "" {
+			if parameters == nil {
+				parameters = make(map[string]string, 1)
+			}
+			parameters[KubernetesContextParameter] = value
+		}
+	}
+
+	// Merge in the Kubernetes parameters.
+	if len(parameters) > 0 {
+		if url.Parameters == nil {
+			url.Parameters = make(map[string]string, len(parameters))
+		}
+		for name, value := range parameters {
+			url.Parameters[name] = value
+		}
+	}
+
+	// Success.
+	return url, nil
+}
@@ -0,0 +1,29 @@
+package url
+
+import (
+	"strings"
+)
+
+// lxdURLPrefix is the lowercase version of the LXD URL prefix.
+const lxdURLPrefix = "lxd://"
+
+// LXDEnvironmentVariables is a list of LXD (and Incus) client environment
+// variables that should be locked in to LXD URLs at parse time.
+var LXDEnvironmentVariables = []string{
+	"LXD_CONF",
+	"LXD_DIR",
+	"INCUS_CONF",
+	"INCUS_DIR",
+}
+
+// isLXDURL checks whether or not a URL is an LXD URL. It requires the presence
+// of an LXD protocol prefix.
+func isLXDURL(raw string) bool {
+	return strings.HasPrefix(strings.ToLower(raw), lxdURLPrefix)
+}
+
+// parseLXD parses an LXD URL. LXD URLs use the same format as Docker URLs, with
+// the container name specifying an LXD instance.
+func parseLXD(raw string, kind Kind, first bool) (*URL, error) {
+	return parseContainer(raw[len(lxdURLPrefix):], kind, first, Protocol_LXD, LXDEnvironmentVariables)
+}
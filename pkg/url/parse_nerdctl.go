@@ -0,0 +1,29 @@
+package url
+
+import (
+	"strings"
+)
+
+// nerdctlURLPrefix is the lowercase version of the nerdctl URL prefix.
+const nerdctlURLPrefix = "nerdctl://"
+
+// NerdctlEnvironmentVariables is a list of nerdctl (and containerd) environment
+// variables that should be locked in to nerdctl URLs at parse time.
+var NerdctlEnvironmentVariables = []string{
+	"CONTAINERD_ADDRESS",
+	"CONTAINERD_NAMESPACE",
+	"CONTAINERD_SNAPSHOTTER",
+	"NERDCTL_TOML",
+}
+
+// isNerdctlURL checks whether or not a URL is a nerdctl URL. It requires the
+// presence of a nerdctl protocol prefix.
+func isNerdctlURL(raw string) bool {
+	return strings.HasPrefix(strings.ToLower(raw), nerdctlURLPrefix)
+}
+
+// parseNerdctl parses a nerdctl URL. nerdctl URLs use the same format as Docker
+// URLs.
+func parseNerdctl(raw string, kind Kind, first bool) (*URL, error) {
+	return parseContainer(raw[len(nerdctlURLPrefix):], kind, first, Protocol_Nerdctl, NerdctlEnvironmentVariables)
+}
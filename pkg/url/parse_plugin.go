@@ -0,0 +1,106 @@
+package url
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+const (
+	// PluginParameter is the name of the URL parameter that specifies the name
+	// of the external protocol handler plugin for plugin URLs. The plugin
+	// executable is named mutagen-transport-<name>.
+	PluginParameter = "plugin"
+
+	// pluginNameMaximumLength is the maximum allowed length for plugin names.
+	pluginNameMaximumLength = 32
+)
+
+// pluginURLPrefix is the lowercase version of the plugin URL prefix. The plugin
+// name follows the prefix and is terminated by "://".
+const pluginURLPrefix = "plugin+"
+
+// IsValidPluginName returns whether or not the specified plugin name is valid.
+// Names must be non-empty, no longer than 32 bytes, and consist of lowercase
+// ASCII letters, digits, '-', and '_'.
+func IsValidPluginName(name string) bool {
+	if name == "" || len(name) > pluginNameMaximumLength {
+		return false
+	}
+	for _, r := range name {
+		if !(('a' <= r && r <= 'z') || ('0' <= r && r <= '9') || r == '-' || r == '_') {
+			return false
+		}
+	}
+	return true
+}
+
+// isValidPluginTarget returns whether or not the specified plugin target is
+// valid. Targets are opaque to Mutagen, but they must be non-empty, can't begin
+// with '-' (since they're passed to plugins as command line arguments and
+// could otherwise be interpreted as flags), and can't contain characters that
+// would prevent the URL from being reparsed. In particular, since ':'
+// separates the target from forwarding endpoints, targets can't take the form
+// host:port, and plugins that need to identify ports must use an alternative
+// syntax (e.g. host_port).
+func isValidPluginTarget(target string) bool {
+	return target != "" &&
+		target[0] != '-' &&
+		!strings.ContainsAny(target, "/: \t\r\n")
+}
+
+// isPluginURL checks whether or not a URL is a plugin URL. It requires the
+// presence of a plugin protocol prefix followed by a scheme terminator.
+func isPluginURL(raw string) bool {
+	return strings.HasPrefix(strings.ToLower(raw), pluginURLPrefix) &&
+		strings.Contains(raw, "://")
+}
+
+// parsePlugin parses a plugin URL. Plugin URLs take the form
+// plugin+name://target/path for synchronization URLs and
+// plugin+name://target:endpoint for forwarding URLs, where name identifies the
+// external protocol handler plugin and target is an opaque, plugin-defined
+// remote specification. Path handling is identical to that of container-style
+// URLs.
+func parsePlugin(raw string, kind Kind, first bool) (*URL, error) {
+	// Strip off the prefix and parse off the plugin name.
+	raw = raw[len(pluginURLPrefix):]
+	name, raw, _ := strings.Cut(raw, "://")
+	if !IsValidPluginName(name) {
+		return nil, fmt.Errorf("invalid plugin name: %s", name)
+	}
+
+	// Parse off the target.
+	end := strings.IndexAny(raw, "/:")
+	if end == -1 {
+		end = len(raw)
+	}
+	target := raw[:end]
+	if target == "" {
+		return nil, errors.New("empty target")
+	} else if !isValidPluginTarget(target) {
+		return nil, fmt.Errorf("invalid target: %s", target)
+	}
+	raw = raw[end:]
+
+	// Perform path processing based on URL kind.
+	path, err := parseTCPPath(raw, kind)
+	if err != nil {
+		return nil, err
+	}
+
+	// Lock in any agent directory that's been specified in the environment.
+	parameters, err := lockInAgentDirectory(map[string]string{PluginParameter: name}, kind, first)
+	if err != nil {
+		return nil, err
+	}
+
+	// Success.
+	return &URL{
+		Kind:       kind,
+		Protocol:   Protocol_Plugin,
+		Host:       target,
+		Path:       path,
+		Parameters: parameters,
+	}, nil
+}
@@ -0,0 +1,85 @@
+package url
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+const (
+	// rendezvousNameMaximumLength is the maximum allowed length for rendezvous
+	// names.
+	rendezvousNameMaximumLength = 64
+)
+
+// rendezvousURLPrefix is the lowercase version of the rendezvous URL prefix.
+const rendezvousURLPrefix = "rendezvous://"
+
+// IsValidRendezvousName returns whether or not the specified name is valid for
+// identifying agents that dial back to the daemon. Names must be non-empty, no
+// longer than 64 bytes, and consist of ASCII letters, digits, '.', '_', and '-'.
+func IsValidRendezvousName(name string) bool {
+	if name == "" || len(name) > rendezvousNameMaximumLength {
+		return false
+	}
+	for _, r := range name {
+		if !(('a' <= r && r <= 'z') || ('A' <= r && r <= 'Z') || ('0' <= r && r <= '9') ||
+			r == '.' || r == '_' || r == '-') {
+			return false
+		}
+	}
+	return true
+}
+
+// isRendezvousURL checks whether or not a URL is a rendezvous URL. It requires
+// the presence of a rendezvous protocol prefix.
+func isRendezvousURL(raw string) bool {
+	return strings.HasPrefix(strings.ToLower(raw), rendezvousURLPrefix)
+}
+
+// parseRendezvous parses a rendezvous URL. Rendezvous URLs take the form
+// rendezvous://name/path for synchronization URLs and rendezvous://name:endpoint
+// for forwarding URLs, where name identifies an agent that dials back to the
+// daemon's rendezvous listener. Path handling is identical to that of TCP URLs,
+// and the path to the pre-shared key file must be specified in the environment
+// in the same manner.
+func parseRendezvous(raw string, kind Kind, first bool) (*URL, error) {
+	// Strip off the prefix.
+	raw = raw[len(rendezvousURLPrefix):]
+
+	// Parse off the name.
+	end := strings.IndexAny(raw, "/:")
+	if end == -1 {
+		end = len(raw)
+	}
+	name := raw[:end]
+	if name == "" {
+		return nil, errors.New("empty name")
+	} else if !IsValidRendezvousName(name) {
+		return nil, fmt.Errorf("invalid name: %s", name)
+	}
+	raw = raw[end:]
+
+	// Perform path processing based on URL kind.
+	path, err := parseTCPPath(raw, kind)
+	if err != nil {
+		return nil, err
+	}
+
+	// Lock in the pre-shared key file, which is required.
+	keyFile, err := lockInTCPKeyFile(kind, first)
+	if err != nil {
+		return nil, err
+	}
+
+	// Success.
+	return &URL{
+		Kind:     kind,
+		Protocol: Protocol_Rendezvous,
+		Host:     name,
+		Path:     path,
+		Parameters: map[string]string{
+			TCPKeyFileParameter: keyFile,
+		},
+	}, nil
+}
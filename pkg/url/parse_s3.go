@@ -0,0 +1,67 @@
+package url
+
+import (
+	"errors"
+	"strings"
+)
+
+// s3URLPrefix is the lowercase version of the S3 URL prefix.
+const s3URLPrefix = "s3://"
+
+// S3EnvironmentVariables is a list of S3 client environment variables that
+// should be locked in to S3 URLs at parse time. Credentials are intentionally
+// excluded so that they aren't persisted with session metadata.
+var S3EnvironmentVariables = []string{
+	"AWS_REGION",
+	"AWS_DEFAULT_REGION",
+	"AWS_ENDPOINT_URL",
+	"AWS_ENDPOINT_URL_S3",
+	"AWS_PROFILE",
+	"AWS_SHARED_CREDENTIALS_FILE",
+}
+
+// isS3URL checks whether or not a URL is an S3 URL. It requires the presence
+// of an S3 protocol prefix.
+func isS3URL(raw string) bool {
+	return strings.HasPrefix(strings.ToLower(raw), s3URLPrefix)
+}
+
+// parseS3 parses an S3 URL. S3 URLs take the form s3://bucket[/prefix], where
+// the optional prefix specifies the key prefix under which content is stored.
+// The prefix is always stored with a leading slash. S3 URLs are only supported
+// for synchronization.
+func parseS3(raw string, kind Kind, first bool) (*URL, error) {
+	// Ensure that this is a synchronization URL.
+	if kind != Kind_Synchronization {
+		return nil, errors.New("S3 URLs are only supported for synchronization")
+	}
+
+	// Strip off the prefix.
+	raw = raw[len(s3URLPrefix):]
+
+	// Split the bucket from the key prefix.
+	bucket, path := raw, "/"
+	if slash := strings.IndexByte(raw, '/'); slash != -1 {
+		bucket, path = raw[:slash], raw[slash:]
+	}
+	if bucket == "" {
+		return nil, errors.New("empty bucket name")
+	}
+
+	// Store any environment variables that we need to preserve.
+	environment := make(map[string]string)
+	for _, variable := range S3EnvironmentVariables {
+		if value, present := getEnvironmentVariable(variable, kind, first); present {
+			environment[variable] = value
+		}
+	}
+
+	// Create the URL.
+	return &URL{
+		Kind:        kind,
+		Protocol:    Protocol_S3,
+		Host:        bucket,
+		Path:        path,
+		Environment: environment,
+	}, nil
+}
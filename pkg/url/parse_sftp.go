@@ -0,0 +1,98 @@
+package url
+
+import (
+	"errors"
+	"strconv"
+	"strings"
+)
+
+// sftpURLPrefix is the lowercase version of the SFTP URL prefix.
+const sftpURLPrefix = "sftp://"
+
+// isSFTPURL checks whether or not a URL is an SFTP URL. It requires the
+// presence of an SFTP protocol prefix.
+func isSFTPURL(raw string) bool {
+	return strings.HasPrefix(strings.ToLower(raw), sftpURLPrefix)
+}
+
+// parseSFTP parses an SFTP URL. SFTP URLs take the standard form of
+// sftp://[user@]host[:port]/path, with IPv6 hosts enclosed in brackets. Paths
+// beginning with /~ are treated as relative to the user's home directory. SFTP
+// URLs are only supported for synchronization.
+func parseSFTP(raw string, kind Kind) (*URL, error) {
+	// Ensure that this is a synchronization URL.
+	if kind != Kind_Synchronization {
+		return nil, errors.New("SFTP URLs are only supported for synchronization")
+	}
+
+	// Strip off the prefix.
+	raw = raw[len(sftpURLPrefix):]
+
+	// Split the authority from the path. We require that a path be present.
+	slash := strings.IndexByte(raw, '/')
+	if slash == -1 {
+		return nil, errors.New("missing path")
+	}
+	authority, path := raw[:slash], raw[slash:]
+
+	// If this is a home-directory-relative path, then strip off the leading
+	// slash.
+	if len(path) > 1 && path[1] == '~' {
+		path = path[1:]
+	}
+
+	// Parse off the username. We enforce that if a username is specified, that
+	// it is non-empty.
+	var username string
+	if at := strings.LastIndexByte(authority, '@'); at != -1 {
+		if at == 0 {
+			return nil, errors.New("empty username specified")
+		}
+		username = authority[:at]
+		authority = authority[at+1:]
+	}
+
+	// Parse off the hostname, handling bracketed IPv6 addresses, and extract
+	// any port specification that follows it.
+	hostname := authority
+	var portSpecification string
+	if strings.HasPrefix(authority, "[") {
+		closing := strings.IndexByte(authority, ']')
+		if closing == -1 {
+			return nil, errors.New("unterminated IPv6 address")
+		}
+		hostname = authority[1:closing]
+		if remaining := authority[closing+1:]; remaining != "" {
+			if remaining[0] != ':' {
+				return nil, errors.New("invalid content after IPv6 address")
+			}
+			portSpecification = remaining[1:]
+		}
+	} else if colon := strings.IndexByte(authority, ':'); colon != -1 {
+		hostname = authority[:colon]
+		portSpecification = authority[colon+1:]
+	}
+	if hostname == "" {
+		return nil, errors.New("empty hostname")
+	}
+
+	// Parse the port, if specified.
+	var port uint32
+	if portSpecification != "" {
+		if port64, err := strconv.ParseUint(portSpecification, 10, 16); err != nil {
+			return nil, errors.New("invalid port value specified")
+		} else {
+			port = uint32(port64)
+		}
+	}
+
+	// Create the URL.
+	return &URL{
+		Kind:     kind,
+		Protocol: Protocol_SFTP,
+		User:     username,
+		Host:     hostname,
+		Port:     port,
+		Path:     path,
+	}, nil
+}
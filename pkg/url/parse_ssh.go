@@ -10,6 +10,56 @@ import (
 	"github.com/mutagen-io/mutagen/pkg/url/forwarding"
 )
 
+const (
+	// SSHClientParameter is the name of the URL parameter that selects the SSH
+	// client implementation used for SSH URLs. If unset, the OpenSSH client is
+	// used.
+	SSHClientParameter = "client"
+	// SSHClientOpenSSH selects the (external) OpenSSH client.
+	SSHClientOpenSSH = "openssh"
+	// SSHClientNative selects the native Go SSH client, which doesn't require
+	// an installed OpenSSH client.
+	SSHClientNative = "native"
+	// SSHForwardAgentParameter is the name of the URL parameter that enables
+	// SSH agent forwarding for SSH URLs. If set, its value must be "true".
+	SSHForwardAgentParameter = "forward-agent"
+
+	// sshClientEnvironmentVariable is the (Mutagen-specific) environment
+	// variable that's used to select the SSH client implementation at parse
+	// time.
+	sshClientEnvironmentVariable = "SSH_CLIENT"
+	// sshForwardAgentEnvironmentVariable is the (Mutagen-specific) environment
+	// variable that's used to enable SSH agent forwarding at parse time.
+	sshForwardAgentEnvironmentVariable = "SSH_FORWARD_AGENT"
+)
+
+// IsValidSSHClient returns whether or not the specified SSH client
+// implementation name is valid.
+func IsValidSSHClient(client string) bool {
+	return client == SSHClientOpenSSH || client == SSHClientNative
+}
+
+// ensureSSHParameterValid ensures that an SSH URL parameter is valid.
+func ensureSSHParameterValid(name, value string) error {
+	switch name {
+	case SSHClientParameter:
+		if !IsValidSSHClient(value) {
+			return fmt.Errorf("invalid SSH client: %s", value)
+		}
+	case SSHForwardAgentParameter:
+		if value != "true" {
+			return fmt.Errorf("invalid agent forwarding specification: %s", value)
+		}
+	case AgentDirectoryParameter:
+		if !IsValidAgentDirectory(value) {
+			return fmt.Errorf("invalid agent directory: %s", value)
+		}
+	default:
+		return fmt.Errorf("unknown parameter: %s", name)
+	}
+	return nil
+}
+
 // isSCPSSHURL determines whether or not a raw URL is an SCP-style SSH URL.
 //
 // For synchronization URLs, a URL is classified as such if it contains a colon
@@ -75,7 +125,7 @@ func isSCPSSHURL(raw string, kind Kind) bool {
 }
 
 // parseSCPSSH parses an SCP-style SSH URL.
-func parseSCPSSH(raw string, kind Kind) (*URL, error) {
+func parseSCPSSH(raw string, kind Kind, first bool) (*URL, error) {
 	// Parse off the username. If we hit a ':', then we've reached the end of
 	// the hostname specification and there was no username. Similarly, if we
 	// hit the end of the string without seeing an '@', then there's also no
@@ -172,13 +222,37 @@ func parseSCPSSH(raw string, kind Kind) (*URL, error) {
 		panic("unhandled URL kind")
 	}
 
+	// Lock in any SSH client settings that have been specified in the
+	// environment. We only store parameters that are actually set.
+	parameters := make(map[string]string)
+	if client, ok := getMutagenEnvironmentVariable(sshClientEnvironmentVariable, kind, first); ok && client != "" {
+		if !IsValidSSHClient(client) {
+			return nil, fmt.Errorf("invalid SSH client specified in environment: %s", client)
+		}
+		parameters[SSHClientParameter] = client
+	}
+	if value, ok := getMutagenEnvironmentVariable(sshForwardAgentEnvironmentVariable, kind, first); ok && value != "" {
+		if forwardAgent, err := strconv.ParseBool(value); err != nil {
+			return nil, fmt.Errorf("invalid agent forwarding specification in environment: %s", value)
+		} else if forwardAgent {
+			parameters[SSHForwardAgentParameter] = "true"
+		}
+	}
+	if _, err := lockInAgentDirectory(parameters, kind, first); err != nil {
+		return nil, err
+	}
+	if len(parameters) == 0 {
+		parameters = nil
+	}
+
 	// Create the URL, using what remains as the path.
 	return &URL{
-		Kind:     kind,
-		Protocol: Protocol_SSH,
-		User:     username,
-		Host:     hostname,
-		Port:     port,
-		Path:     path,
+		Kind:       kind,
+		Protocol:   Protocol_SSH,
+		User:       username,
+		Host:       hostname,
+		Port:       port,
+		Path:       path,
+		Parameters: parameters,
 	}, nil
 }
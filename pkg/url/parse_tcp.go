@@ -0,0 +1,142 @@
+package url
+
+import (
+	"errors"
+	"fmt"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/mutagen-io/mutagen/pkg/url/forwarding"
+)
+
+const (
+	// TCPKeyFileParameter is the name of the URL parameter that specifies the
+	// (absolute) path to the local file containing the pre-shared key used to
+	// authenticate with agents for TCP URLs. The key itself is intentionally
+	// not stored in the URL.
+	TCPKeyFileParameter = "key-file"
+
+	// tcpKeyFileEnvironmentVariable is the (Mutagen-specific) environment
+	// variable that's used to specify the pre-shared key file at parse time.
+	tcpKeyFileEnvironmentVariable = "TCP_KEY_FILE"
+)
+
+// tcpURLPrefix is the lowercase version of the TCP URL prefix.
+const tcpURLPrefix = "tcp://"
+
+// isTCPURL checks whether or not a URL is a TCP URL. It requires the presence
+// of a TCP protocol prefix.
+func isTCPURL(raw string) bool {
+	return strings.HasPrefix(strings.ToLower(raw), tcpURLPrefix)
+}
+
+// parseTCPPath performs path processing for TCP-based URLs (i.e. TCP and
+// rendezvous URLs) based on URL kind. The raw value should be the portion of the
+// URL following the host specification.
+func parseTCPPath(raw string, kind Kind) (string, error) {
+	if kind == Kind_Synchronization {
+		if raw == "" || raw[0] != '/' {
+			return "", errors.New("missing path")
+		}
+		path := raw
+		if len(path) > 1 && path[1] == '~' {
+			path = path[1:]
+		} else if isWindowsPath(path[1:]) {
+			path = path[1:]
+		}
+		return path, nil
+	} else if kind == Kind_Forwarding {
+		if raw == "" || raw[0] != ':' {
+			return "", errors.New("missing forwarding endpoint")
+		}
+		path := raw[1:]
+		if _, _, err := forwarding.Parse(path); err != nil {
+			return "", fmt.Errorf("invalid forwarding endpoint URL: %w", err)
+		}
+		return path, nil
+	} else {
+		panic("unhandled URL kind")
+	}
+}
+
+// lockInTCPKeyFile determines the absolute path to the pre-shared key file for
+// TCP-based URLs from the environment. A key file is required.
+func lockInTCPKeyFile(kind Kind, first bool) (string, error) {
+	keyFile, ok := getMutagenEnvironmentVariable(tcpKeyFileEnvironmentVariable, kind, first)
+	if !ok || keyFile == "" {
+		return "", fmt.Errorf("no pre-shared key file specified (set MUTAGEN_%s)", tcpKeyFileEnvironmentVariable)
+	}
+	keyFile, err := filepath.Abs(keyFile)
+	if err != nil {
+		return "", fmt.Errorf("unable to resolve pre-shared key file path: %w", err)
+	}
+	return keyFile, nil
+}
+
+// parseTCP parses a TCP URL. TCP URLs take the form tcp://host:port/path for
+// synchronization URLs and tcp://host:port:endpoint for forwarding URLs, with
+// IPv6 hosts enclosed in brackets. A port is required. Paths beginning with /~
+// are treated as relative to the home directory of the user running the agent,
+// and paths of the form "/" + Windows path are treated as Windows paths. The
+// path to the pre-shared key file must be specified in the environment.
+func parseTCP(raw string, kind Kind, first bool) (*URL, error) {
+	// Strip off the prefix.
+	raw = raw[len(tcpURLPrefix):]
+
+	// Parse off the hostname, handling bracketed IPv6 addresses.
+	var hostname string
+	if strings.HasPrefix(raw, "[") {
+		closing := strings.IndexByte(raw, ']')
+		if closing == -1 {
+			return nil, errors.New("unterminated IPv6 address")
+		}
+		hostname, raw = raw[1:closing], raw[closing+1:]
+	} else if colon := strings.IndexByte(raw, ':'); colon != -1 {
+		hostname, raw = raw[:colon], raw[colon:]
+	} else {
+		return nil, errors.New("missing port")
+	}
+	if hostname == "" {
+		return nil, errors.New("empty hostname")
+	}
+
+	// Parse off the port.
+	if raw == "" || raw[0] != ':' {
+		return nil, errors.New("missing port")
+	}
+	raw = raw[1:]
+	end := strings.IndexAny(raw, "/:")
+	if end == -1 {
+		end = len(raw)
+	}
+	port, err := strconv.ParseUint(raw[:end], 10, 16)
+	if err != nil || port == 0 {
+		return nil, errors.New("invalid port value specified")
+	}
+	raw = raw[end:]
+
+	// Perform path processing based on URL kind.
+	path, err := parseTCPPath(raw, kind)
+	if err != nil {
+		return nil, err
+	}
+
+	// Lock in the pre-shared key file, which is required.
+	keyFile, err := lockInTCPKeyFile(kind, first)
+	if err != nil {
+		return nil, err
+	}
+
+	// Success.
+	return &URL{
+		Kind:     kind,
+		Protocol: Protocol_TCP,
+		Host:     hostname,
+		Port:     uint32(port),
+		Path:     path,
+		Parameters: map[string]string{
+			TCPKeyFileParameter: keyFile,
+		},
+	}, nil
+}
@@ -0,0 +1,31 @@
+package url
+
+import (
+	"strings"
+)
+
+// teleportURLPrefix is the lowercase version of the Teleport URL prefix.
+const teleportURLPrefix = "teleport://"
+
+// TeleportEnvironmentVariables is a list of Teleport client (tsh) environment
+// variables that should be locked in to Teleport URLs at parse time.
+var TeleportEnvironmentVariables = []string{
+	"TELEPORT_PROXY",
+	"TELEPORT_CLUSTER",
+	"TELEPORT_USER",
+	"TELEPORT_LOGIN",
+	"TELEPORT_HOME",
+}
+
+// isTeleportURL checks whether or not a URL is a Teleport URL. It requires the
+// presence of a Teleport protocol prefix.
+func isTeleportURL(raw string) bool {
+	return strings.HasPrefix(strings.ToLower(raw), teleportURLPrefix)
+}
+
+// parseTeleport parses a Teleport URL. Teleport URLs use the same format as
+// Docker URLs, with the container name specifying a Teleport node and the
+// username specifying the login on that node.
+func parseTeleport(raw string, kind Kind, first bool) (*URL, error) {
+	return parseContainer(raw[len(teleportURLPrefix):], kind, first, Protocol_Teleport, TeleportEnvironmentVariables)
+}
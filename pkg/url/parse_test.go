@@ -1,6 +1,7 @@
 package url
 
 import (
+	"path/filepath"
 	"runtime"
 	"testing"
 
@@ -72,6 +73,19 @@ func (c *parseTestCase) run(t *testing.T) {
 			}
 		}
 	}
+
+	// Verify parameters.
+	if len(url.Parameters) != len(c.expected.Parameters) {
+		t.Error("parameters length mismatch:", len(url.Parameters), "!=", len(c.expected.Parameters))
+	} else {
+		for ek, ev := range c.expected.Parameters {
+			if v, ok := url.Parameters[ek]; !ok {
+				t.Error("expected parameter", ek, "not in URL parameters")
+			} else if v != ev {
+				t.Error("parameter", ek, "value does not match expected:", v, "!=", ev)
+			}
+		}
+	}
 }
 
 func TestParseEmptyInvalid(t *testing.T) {
@@ -318,6 +332,102 @@ func TestParseSCPSSHHostnamePath(t *testing.T) {
 	test.run(t)
 }
 
+func TestParseSCPSSHClientFromEnvironment(t *testing.T) {
+	mockEnvironment["MUTAGEN_SSH_CLIENT"] = SSHClientNative
+	defer delete(mockEnvironment, "MUTAGEN_SSH_CLIENT")
+	test := parseTestCase{
+		raw: "host:path",
+		expected: &URL{
+			Protocol: Protocol_SSH,
+			Host:     "host",
+			Path:     "path",
+			Parameters: map[string]string{
+				SSHClientParameter: SSHClientNative,
+			},
+		},
+	}
+	test.run(t)
+}
+
+func TestParseSCPSSHInvalidClientFromEnvironmentInvalid(t *testing.T) {
+	mockEnvironment["MUTAGEN_SSH_CLIENT"] = "putty"
+	defer delete(mockEnvironment, "MUTAGEN_SSH_CLIENT")
+	test := parseTestCase{
+		raw:  "host:path",
+		fail: true,
+	}
+	test.run(t)
+}
+
+func TestParseSCPSSHForwardAgentFromEnvironment(t *testing.T) {
+	mockEnvironment["MUTAGEN_ALPHA_SSH_FORWARD_AGENT"] = "1"
+	defer delete(mockEnvironment, "MUTAGEN_ALPHA_SSH_FORWARD_AGENT")
+	test := parseTestCase{
+		raw:   "host:path",
+		first: true,
+		expected: &URL{
+			Protocol: Protocol_SSH,
+			Host:     "host",
+			Path:     "path",
+			Parameters: map[string]string{
+				SSHForwardAgentParameter: "true",
+			},
+		},
+	}
+	test.run(t)
+}
+
+func TestParseSCPSSHForwardAgentDisabledFromEnvironment(t *testing.T) {
+	mockEnvironment["MUTAGEN_SSH_FORWARD_AGENT"] = "false"
+	defer delete(mockEnvironment, "MUTAGEN_SSH_FORWARD_AGENT")
+	test := parseTestCase{
+		raw: "host:path",
+		expected: &URL{
+			Protocol: Protocol_SSH,
+			Host:     "host",
+			Path:     "path",
+		},
+	}
+	test.run(t)
+}
+
+func TestParseSCPSSHInvalidForwardAgentFromEnvironmentInvalid(t *testing.T) {
+	mockEnvironment["MUTAGEN_SSH_FORWARD_AGENT"] = "sometimes"
+	defer delete(mockEnvironment, "MUTAGEN_SSH_FORWARD_AGENT")
+	test := parseTestCase{
+		raw:  "host:path",
+		fail: true,
+	}
+	test.run(t)
+}
+
+func TestParseSCPSSHAgentDirectoryFromEnvironment(t *testing.T) {
+	mockEnvironment["MUTAGEN_AGENT_DIRECTORY"] = "/tmp/.mutagen"
+	defer delete(mockEnvironment, "MUTAGEN_AGENT_DIRECTORY")
+	test := parseTestCase{
+		raw: "host:path",
+		expected: &URL{
+			Protocol: Protocol_SSH,
+			Host:     "host",
+			Path:     "path",
+			Parameters: map[string]string{
+				AgentDirectoryParameter: "/tmp/.mutagen",
+			},
+		},
+	}
+	test.run(t)
+}
+
+func TestParseSCPSSHInvalidAgentDirectoryFromEnvironmentInvalid(t *testing.T) {
+	mockEnvironment["MUTAGEN_AGENT_DIRECTORY"] = "relative/path"
+	defer delete(mockEnvironment, "MUTAGEN_AGENT_DIRECTORY")
+	test := parseTestCase{
+		raw:  "host:path",
+		fail: true,
+	}
+	test.run(t)
+}
+
 func TestParseForwardingSCPSSHHostnameTCPEndpoint(t *testing.T) {
 	test := parseTestCase{
 		raw:  "host:tcp4:localhost:5050",
@@ -583,6 +693,81 @@ func TestParseDockerWithBetaSpecificVariables(t *testing.T) {
 	test.run(t)
 }
 
+func TestParseDockerWithContextParameter(t *testing.T) {
+	test := parseTestCase{
+		raw: "docker://cøntainer/пат/to/the file?context=remote-box&tlsverify",
+		expected: &URL{
+			Protocol: Protocol_Docker,
+			Host:     "cøntainer",
+			Path:     "/пат/to/the file",
+			Environment: map[string]string{
+				"DOCKER_HOST":       defaultDockerHost,
+				"DOCKER_TLS_VERIFY": betaSpecificDockerTLSVerify,
+			},
+			Parameters: map[string]string{
+				"context":   "remote-box",
+				"tlsverify": "",
+			},
+		},
+	}
+	test.run(t)
+}
+
+func TestParseDockerWithQuestionMarkInPath(t *testing.T) {
+	test := parseTestCase{
+		raw: "docker://cøntainer/пат/to/what?file=name",
+		expected: &URL{
+			Protocol: Protocol_Docker,
+			Host:     "cøntainer",
+			Path:     "/пат/to/what?file=name",
+			Environment: map[string]string{
+				"DOCKER_HOST":       defaultDockerHost,
+				"DOCKER_TLS_VERIFY": betaSpecificDockerTLSVerify,
+			},
+		},
+	}
+	test.run(t)
+}
+
+func TestParseDockerWithEmptyContextParameterInvalid(t *testing.T) {
+	test := parseTestCase{
+		raw:  "docker://cøntainer/path?context=",
+		fail: true,
+	}
+	test.run(t)
+}
+
+func TestParseDockerWithInvalidFlagParameterInvalid(t *testing.T) {
+	test := parseTestCase{
+		raw:  "docker://cøntainer/path?tls=false",
+		fail: true,
+	}
+	test.run(t)
+}
+
+func TestParseForwardingDockerWithContextParameter(t *testing.T) {
+	test := parseTestCase{
+		raw:   "docker://cøntainer:unix:/some/socket.sock?context=remote-box",
+		kind:  Kind_Forwarding,
+		first: true,
+		expected: &URL{
+			Kind:     Kind_Forwarding,
+			Protocol: Protocol_Docker,
+			Host:     "cøntainer",
+			Path:     "unix:/some/socket.sock",
+			Environment: map[string]string{
+				"DOCKER_HOST":       defaultDockerHost,
+				"DOCKER_TLS_VERIFY": defaultDockerTLSVerify,
+				"DOCKER_CONTEXT":    sourceSpecificDockerContext,
+			},
+			Parameters: map[string]string{
+				"context": "remote-box",
+			},
+		},
+	}
+	test.run(t)
+}
+
 func TestParseDockerWithWindowsPathAndAlphaSpecificVariables(t *testing.T) {
 	test := parseTestCase{
 		raw:   `docker://cøntainer/C:\пат/to\the file`,
@@ -638,3 +823,832 @@ func TestParseDockerWithUsernameUserRelativePathAndAlphaSpecificVariables(t *tes
 	}
 	test.run(t)
 }
+
+func TestParseNerdctlWithBetaSpecificVariables(t *testing.T) {
+	test := parseTestCase{
+		raw: "nerdctl://üsér@cøntainer/~/пат/to/the file",
+		expected: &URL{
+			Protocol: Protocol_Nerdctl,
+			User:     "üsér",
+			Host:     "cøntainer",
+			Path:     "~/пат/to/the file",
+			Environment: map[string]string{
+				"CONTAINERD_ADDRESS":   betaSpecificContainerdAddress,
+				"CONTAINERD_NAMESPACE": defaultContainerdNamespace,
+			},
+		},
+	}
+	test.run(t)
+}
+
+func TestParseNerdctlWithAlphaVariables(t *testing.T) {
+	test := parseTestCase{
+		raw:   "NERDCTL://cøntainer/пат/to/the file",
+		first: true,
+		expected: &URL{
+			Protocol: Protocol_Nerdctl,
+			Host:     "cøntainer",
+			Path:     "/пат/to/the file",
+			Environment: map[string]string{
+				"CONTAINERD_NAMESPACE": defaultContainerdNamespace,
+			},
+		},
+	}
+	test.run(t)
+}
+
+func TestParseNerdctlMissingPathInvalid(t *testing.T) {
+	test := parseTestCase{
+		raw:  "nerdctl://cøntainer",
+		fail: true,
+	}
+	test.run(t)
+}
+
+func TestParseForwardingNerdctl(t *testing.T) {
+	test := parseTestCase{
+		raw:   "nerdctl://cøntainer:tcp:localhost:5432",
+		kind:  Kind_Forwarding,
+		first: true,
+		expected: &URL{
+			Kind:     Kind_Forwarding,
+			Protocol: Protocol_Nerdctl,
+			Host:     "cøntainer",
+			Path:     "tcp:localhost:5432",
+			Environment: map[string]string{
+				"CONTAINERD_NAMESPACE": defaultContainerdNamespace,
+			},
+		},
+	}
+	test.run(t)
+}
+
+func TestParseLXDWithUsernameHomeRelativePath(t *testing.T) {
+	test := parseTestCase{
+		raw: "lxd://üsér@instance/~/пат/to/the file",
+		expected: &URL{
+			Protocol: Protocol_LXD,
+			User:     "üsér",
+			Host:     "instance",
+			Path:     "~/пат/to/the file",
+		},
+	}
+	test.run(t)
+}
+
+func TestParseLXDWithBetaSpecificAgentDirectory(t *testing.T) {
+	mockEnvironment["MUTAGEN_BETA_AGENT_DIRECTORY"] = "/srv/project/.mutagen"
+	defer delete(mockEnvironment, "MUTAGEN_BETA_AGENT_DIRECTORY")
+	test := parseTestCase{
+		raw: "lxd://instance/path",
+		expected: &URL{
+			Protocol: Protocol_LXD,
+			Host:     "instance",
+			Path:     "/path",
+			Parameters: map[string]string{
+				AgentDirectoryParameter: "/srv/project/.mutagen",
+			},
+		},
+	}
+	test.run(t)
+}
+
+func TestParseForwardingLXDWithSourceSpecificVariables(t *testing.T) {
+	test := parseTestCase{
+		raw:   "lxd://instance:tcp:localhost:8080",
+		kind:  Kind_Forwarding,
+		first: true,
+		expected: &URL{
+			Kind:     Kind_Forwarding,
+			Protocol: Protocol_LXD,
+			Host:     "instance",
+			Path:     "tcp:localhost:8080",
+			Environment: map[string]string{
+				"LXD_CONF": sourceSpecificLXDConf,
+			},
+		},
+	}
+	test.run(t)
+}
+
+func TestParseKubernetesHomeRelativePath(t *testing.T) {
+	test := parseTestCase{
+		raw: "kubernetes://default/web-0/app/~/project",
+		expected: &URL{
+			Protocol: Protocol_Kubernetes,
+			Host:     "default/web-0/app",
+			Path:     "~/project",
+		},
+	}
+	test.run(t)
+}
+
+func TestParseKubernetesWithContextParameterAndKubeconfig(t *testing.T) {
+	mockEnvironment["KUBECONFIG"] = "/home/user/.kube/staging"
+	defer delete(mockEnvironment, "KUBECONFIG")
+	test := parseTestCase{
+		raw: "kubernetes://default/web-0/app/srv/project?context=staging",
+		expected: &URL{
+			Protocol: Protocol_Kubernetes,
+			Host:     "default/web-0/app",
+			Path:     "/srv/project",
+			Environment: map[string]string{
+				"KUBECONFIG": "/home/user/.kube/staging",
+			},
+			Parameters: map[string]string{
+				KubernetesContextParameter: "staging",
+			},
+		},
+	}
+	test.run(t)
+}
+
+func TestParseKubernetesWithBetaSpecificContext(t *testing.T) {
+	mockEnvironment["MUTAGEN_BETA_KUBERNETES_CONTEXT"] = "production"
+	defer delete(mockEnvironment, "MUTAGEN_BETA_KUBERNETES_CONTEXT")
+	test := parseTestCase{
+		raw: "kubernetes://default/web-0/app/srv/project",
+		expected: &URL{
+			Protocol: Protocol_Kubernetes,
+			Host:     "default/web-0/app",
+			Path:     "/srv/project",
+			Parameters: map[string]string{
+				KubernetesContextParameter: "production",
+			},
+		},
+	}
+	test.run(t)
+}
+
+func TestParseKubernetesDeployment(t *testing.T) {
+	test := parseTestCase{
+		raw: "kubernetes://default/deployment:web/app/~/project",
+		expected: &URL{
+			Protocol: Protocol_Kubernetes,
+			Host:     "default/deployment:web/app",
+			Path:     "~/project",
+		},
+	}
+	test.run(t)
+}
+
+func TestParseKubernetesSelectorWithContextParameter(t *testing.T) {
+	test := parseTestCase{
+		raw: "kubernetes://default/selector:app.kubernetes.io%2Fname=web,tier!=db/app/srv/project?context=staging",
+		expected: &URL{
+			Protocol: Protocol_Kubernetes,
+			Host:     "default/selector:app.kubernetes.io%2Fname=web,tier!=db/app",
+			Path:     "/srv/project",
+			Parameters: map[string]string{
+				KubernetesContextParameter: "staging",
+			},
+		},
+	}
+	test.run(t)
+}
+
+func TestParseKubernetesMissingContainerInvalid(t *testing.T) {
+	test := parseTestCase{
+		raw:  "kubernetes://default/web-0",
+		fail: true,
+	}
+	test.run(t)
+}
+
+func TestParseKubernetesMissingPathInvalid(t *testing.T) {
+	test := parseTestCase{
+		raw:  "kubernetes://default/web-0/app",
+		fail: true,
+	}
+	test.run(t)
+}
+
+func TestParseForwardingKubernetes(t *testing.T) {
+	test := parseTestCase{
+		raw:   "kubernetes://default/web-0/app:tcp:localhost:8080",
+		kind:  Kind_Forwarding,
+		first: true,
+		expected: &URL{
+			Kind:     Kind_Forwarding,
+			Protocol: Protocol_Kubernetes,
+			Host:     "default/web-0/app",
+			Path:     "tcp:localhost:8080",
+		},
+	}
+	test.run(t)
+}
+
+func TestParseForwardingKubernetesDeployment(t *testing.T) {
+	test := parseTestCase{
+		raw:   "kubernetes://default/deployment:web/app:tcp:localhost:8080",
+		kind:  Kind_Forwarding,
+		first: true,
+		expected: &URL{
+			Kind:     Kind_Forwarding,
+			Protocol: Protocol_Kubernetes,
+			Host:     "default/deployment:web/app",
+			Path:     "tcp:localhost:8080",
+		},
+	}
+	test.run(t)
+}
+
+func TestParseWSLWithUsernameAndPath(t *testing.T) {
+	test := parseTestCase{
+		raw: "wsl://üsér@Ubuntu-22.04/home/üsér/пат",
+		expected: &URL{
+			Protocol: Protocol_WSL,
+			User:     "üsér",
+			Host:     "Ubuntu-22.04",
+			Path:     "/home/üsér/пат",
+		},
+	}
+	test.run(t)
+}
+
+func TestParseWSLMissingPathInvalid(t *testing.T) {
+	test := parseTestCase{
+		raw:  "wsl://Ubuntu",
+		fail: true,
+	}
+	test.run(t)
+}
+
+func TestParseForwardingWSL(t *testing.T) {
+	test := parseTestCase{
+		raw:  "wsl://Ubuntu:tcp:localhost:3000",
+		kind: Kind_Forwarding,
+		expected: &URL{
+			Kind:     Kind_Forwarding,
+			Protocol: Protocol_WSL,
+			Host:     "Ubuntu",
+			Path:     "tcp:localhost:3000",
+		},
+	}
+	test.run(t)
+}
+
+func TestParseAzureWithUsernameAndWindowsPath(t *testing.T) {
+	test := parseTestCase{
+		raw:   `azure://azureuser@vm-1/C:\пат`,
+		first: true,
+		expected: &URL{
+			Protocol: Protocol_Azure,
+			User:     "azureuser",
+			Host:     "vm-1",
+			Path:     `C:\пат`,
+		},
+	}
+	test.run(t)
+}
+
+func TestParseForwardingAzure(t *testing.T) {
+	test := parseTestCase{
+		raw:  "azure://vm-1:tcp:localhost:8080",
+		kind: Kind_Forwarding,
+		expected: &URL{
+			Kind:     Kind_Forwarding,
+			Protocol: Protocol_Azure,
+			Host:     "vm-1",
+			Path:     "tcp:localhost:8080",
+		},
+	}
+	test.run(t)
+}
+
+func TestParseTeleportWithLogin(t *testing.T) {
+	test := parseTestCase{
+		raw: "teleport://root@node-1/~/пат",
+		expected: &URL{
+			Protocol: Protocol_Teleport,
+			User:     "root",
+			Host:     "node-1",
+			Path:     "~/пат",
+		},
+	}
+	test.run(t)
+}
+
+func TestParseForwardingTeleport(t *testing.T) {
+	test := parseTestCase{
+		raw:  "teleport://node-1:tcp:localhost:5432",
+		kind: Kind_Forwarding,
+		expected: &URL{
+			Kind:     Kind_Forwarding,
+			Protocol: Protocol_Teleport,
+			Host:     "node-1",
+			Path:     "tcp:localhost:5432",
+		},
+	}
+	test.run(t)
+}
+
+func TestParseSFTPWithUserPortAndHomeRelativePath(t *testing.T) {
+	test := parseTestCase{
+		raw: "sftp://user@example.com:2222/~/пат",
+		expected: &URL{
+			Protocol: Protocol_SFTP,
+			User:     "user",
+			Host:     "example.com",
+			Port:     2222,
+			Path:     "~/пат",
+		},
+	}
+	test.run(t)
+}
+
+func TestParseSFTPWithIPv6HostAndAbsolutePath(t *testing.T) {
+	test := parseTestCase{
+		raw: "sftp://[fe80::1]:22/var/www",
+		expected: &URL{
+			Protocol: Protocol_SFTP,
+			Host:     "fe80::1",
+			Port:     22,
+			Path:     "/var/www",
+		},
+	}
+	test.run(t)
+}
+
+func TestParseSFTPMissingPathInvalid(t *testing.T) {
+	test := parseTestCase{
+		raw:  "sftp://example.com",
+		fail: true,
+	}
+	test.run(t)
+}
+
+func TestParseSFTPInvalidPortInvalid(t *testing.T) {
+	test := parseTestCase{
+		raw:  "sftp://example.com:65536/path",
+		fail: true,
+	}
+	test.run(t)
+}
+
+func TestParseForwardingSFTPInvalid(t *testing.T) {
+	test := parseTestCase{
+		raw:  "sftp://example.com:tcp:localhost:8080",
+		kind: Kind_Forwarding,
+		fail: true,
+	}
+	test.run(t)
+}
+
+func TestParseS3WithPrefix(t *testing.T) {
+	mockEnvironment["AWS_REGION"] = "eu-west-1"
+	defer delete(mockEnvironment, "AWS_REGION")
+	test := parseTestCase{
+		raw: "s3://bucket/path/to/assets",
+		expected: &URL{
+			Protocol: Protocol_S3,
+			Host:     "bucket",
+			Path:     "/path/to/assets",
+			Environment: map[string]string{
+				"AWS_REGION": "eu-west-1",
+			},
+		},
+	}
+	test.run(t)
+}
+
+func TestParseS3WithoutPrefix(t *testing.T) {
+	test := parseTestCase{
+		raw: "s3://bucket",
+		expected: &URL{
+			Protocol: Protocol_S3,
+			Host:     "bucket",
+			Path:     "/",
+		},
+	}
+	test.run(t)
+}
+
+func TestParseS3EmptyBucketInvalid(t *testing.T) {
+	test := parseTestCase{
+		raw:  "s3:///path",
+		fail: true,
+	}
+	test.run(t)
+}
+
+func TestParseForwardingS3Invalid(t *testing.T) {
+	test := parseTestCase{
+		raw:  "s3://bucket:tcp:localhost:8080",
+		kind: Kind_Forwarding,
+		fail: true,
+	}
+	test.run(t)
+}
+
+func TestParseExec(t *testing.T) {
+	test := parseTestCase{
+		raw: "exec:'kubectl exec -i pod --':/var/www",
+		expected: &URL{
+			Protocol: Protocol_Exec,
+			Host:     "kubectl exec -i pod --",
+			Path:     "/var/www",
+		},
+	}
+	test.run(t)
+}
+
+func TestParseExecDoubleQuotedHomeRelative(t *testing.T) {
+	test := parseTestCase{
+		raw: `exec:"kubectl exec -i pod --":~/project`,
+		expected: &URL{
+			Protocol: Protocol_Exec,
+			Host:     "kubectl exec -i pod --",
+			Path:     "~/project",
+		},
+	}
+	test.run(t)
+}
+
+func TestParseExecWithCopyFromEnvironment(t *testing.T) {
+	mockEnvironment["MUTAGEN_ALPHA_EXEC_COPY"] = "kubectl cp {local} pod:{remote}"
+	defer delete(mockEnvironment, "MUTAGEN_ALPHA_EXEC_COPY")
+	test := parseTestCase{
+		raw:   "exec:'kubectl exec -i pod --':/var/www",
+		first: true,
+		expected: &URL{
+			Protocol: Protocol_Exec,
+			Host:     "kubectl exec -i pod --",
+			Path:     "/var/www",
+			Parameters: map[string]string{
+				ExecCopyParameter: "kubectl cp {local} pod:{remote}",
+			},
+		},
+	}
+	test.run(t)
+}
+
+func TestParseExecInvalidCopyFromEnvironmentInvalid(t *testing.T) {
+	mockEnvironment["MUTAGEN_EXEC_COPY"] = "kubectl cp {local} pod:/tmp"
+	defer delete(mockEnvironment, "MUTAGEN_EXEC_COPY")
+	test := parseTestCase{
+		raw:  "exec:'kubectl exec -i pod --':/var/www",
+		fail: true,
+	}
+	test.run(t)
+}
+
+func TestParseExecUnterminatedCommandInvalid(t *testing.T) {
+	test := parseTestCase{
+		raw:  "exec:'kubectl exec -i pod --:/var/www",
+		fail: true,
+	}
+	test.run(t)
+}
+
+func TestParseExecEmptyCommandInvalid(t *testing.T) {
+	test := parseTestCase{
+		raw:  "exec:' ':/var/www",
+		fail: true,
+	}
+	test.run(t)
+}
+
+func TestParseExecMissingPathInvalid(t *testing.T) {
+	test := parseTestCase{
+		raw:  "exec:'kubectl exec -i pod --'",
+		fail: true,
+	}
+	test.run(t)
+}
+
+func TestParseForwardingExec(t *testing.T) {
+	test := parseTestCase{
+		raw:  "exec:'kubectl exec -i pod --':tcp:localhost:8080",
+		kind: Kind_Forwarding,
+		expected: &URL{
+			Kind:     Kind_Forwarding,
+			Protocol: Protocol_Exec,
+			Host:     "kubectl exec -i pod --",
+			Path:     "tcp:localhost:8080",
+		},
+	}
+	test.run(t)
+}
+
+func TestParseExecWithEnvironment(t *testing.T) {
+	mockEnvironment["KUBECONFIG"] = "/path/to/kubeconfig"
+	mockEnvironment["MUTAGEN_BETA_EXEC_ENVIRONMENT"] = "KUBECONFIG, DOCKER_CONTEXT=remote,UNSET"
+	defer func() {
+		delete(mockEnvironment, "KUBECONFIG")
+		delete(mockEnvironment, "MUTAGEN_BETA_EXEC_ENVIRONMENT")
+	}()
+	test := parseTestCase{
+		raw: "exec:'kubectl exec -i pod --':/var/www",
+		expected: &URL{
+			Protocol: Protocol_Exec,
+			Host:     "kubectl exec -i pod --",
+			Path:     "/var/www",
+			Environment: map[string]string{
+				"KUBECONFIG":     "/path/to/kubeconfig",
+				"DOCKER_CONTEXT": "remote",
+			},
+		},
+	}
+	test.run(t)
+}
+
+func TestParseExecInvalidEnvironmentInvalid(t *testing.T) {
+	mockEnvironment["MUTAGEN_EXEC_ENVIRONMENT"] = "=value"
+	defer delete(mockEnvironment, "MUTAGEN_EXEC_ENVIRONMENT")
+	test := parseTestCase{
+		raw:  "exec:'kubectl exec -i pod --':/var/www",
+		fail: true,
+	}
+	test.run(t)
+}
+
+func TestParseDockerWithExecParameters(t *testing.T) {
+	mockEnvironment["MUTAGEN_BETA_DOCKER_WORKDIR"] = "/srv"
+	mockEnvironment["MUTAGEN_DOCKER_PRIVILEGED"] = "true"
+	mockEnvironment["MUTAGEN_DOCKER_USER"] = "root"
+	defer func() {
+		delete(mockEnvironment, "MUTAGEN_BETA_DOCKER_WORKDIR")
+		delete(mockEnvironment, "MUTAGEN_DOCKER_PRIVILEGED")
+		delete(mockEnvironment, "MUTAGEN_DOCKER_USER")
+	}()
+	test := parseTestCase{
+		raw: "docker://cøntainer/path?user=1000:1000",
+		expected: &URL{
+			Protocol: Protocol_Docker,
+			Host:     "cøntainer",
+			Path:     "/path",
+			Environment: map[string]string{
+				"DOCKER_HOST":       defaultDockerHost,
+				"DOCKER_TLS_VERIFY": betaSpecificDockerTLSVerify,
+			},
+			Parameters: map[string]string{
+				"user":       "1000:1000",
+				"workdir":    "/srv",
+				"privileged": "",
+			},
+		},
+	}
+	test.run(t)
+}
+
+func TestParseDockerWithInvalidPrivilegedFromEnvironmentInvalid(t *testing.T) {
+	mockEnvironment["MUTAGEN_DOCKER_PRIVILEGED"] = "sometimes"
+	defer delete(mockEnvironment, "MUTAGEN_DOCKER_PRIVILEGED")
+	test := parseTestCase{
+		raw:  "docker://cøntainer/path",
+		fail: true,
+	}
+	test.run(t)
+}
+
+func TestParseTCP(t *testing.T) {
+	mockEnvironment["MUTAGEN_TCP_KEY_FILE"] = "/path/to/key"
+	defer delete(mockEnvironment, "MUTAGEN_TCP_KEY_FILE")
+	keyFile, err := filepath.Abs("/path/to/key")
+	if err != nil {
+		t.Fatal("unable to compute absolute key file path:", err)
+	}
+	test := parseTestCase{
+		raw: "tcp://host:7820/~/path",
+		expected: &URL{
+			Protocol: Protocol_TCP,
+			Host:     "host",
+			Port:     7820,
+			Path:     "~/path",
+			Parameters: map[string]string{
+				TCPKeyFileParameter: keyFile,
+			},
+		},
+	}
+	test.run(t)
+}
+
+func TestParseTCPIPv6WithAlphaSpecificKeyFile(t *testing.T) {
+	mockEnvironment["MUTAGEN_ALPHA_TCP_KEY_FILE"] = "/path/to/alpha/key"
+	defer delete(mockEnvironment, "MUTAGEN_ALPHA_TCP_KEY_FILE")
+	keyFile, err := filepath.Abs("/path/to/alpha/key")
+	if err != nil {
+		t.Fatal("unable to compute absolute key file path:", err)
+	}
+	test := parseTestCase{
+		raw:   "tcp://[fe80::1]:7820/var/www",
+		first: true,
+		expected: &URL{
+			Protocol: Protocol_TCP,
+			Host:     "fe80::1",
+			Port:     7820,
+			Path:     "/var/www",
+			Parameters: map[string]string{
+				TCPKeyFileParameter: keyFile,
+			},
+		},
+	}
+	test.run(t)
+}
+
+func TestParseTCPMissingKeyFileInvalid(t *testing.T) {
+	test := parseTestCase{
+		raw:  "tcp://host:7820/path",
+		fail: true,
+	}
+	test.run(t)
+}
+
+func TestParseTCPMissingPortInvalid(t *testing.T) {
+	mockEnvironment["MUTAGEN_TCP_KEY_FILE"] = "/path/to/key"
+	defer delete(mockEnvironment, "MUTAGEN_TCP_KEY_FILE")
+	test := parseTestCase{
+		raw:  "tcp://host/path",
+		fail: true,
+	}
+	test.run(t)
+}
+
+func TestParseTCPInvalidPortInvalid(t *testing.T) {
+	mockEnvironment["MUTAGEN_TCP_KEY_FILE"] = "/path/to/key"
+	defer delete(mockEnvironment, "MUTAGEN_TCP_KEY_FILE")
+	test := parseTestCase{
+		raw:  "tcp://host:65536/path",
+		fail: true,
+	}
+	test.run(t)
+}
+
+func TestParseForwardingTCP(t *testing.T) {
+	mockEnvironment["MUTAGEN_TCP_KEY_FILE"] = "/path/to/key"
+	defer delete(mockEnvironment, "MUTAGEN_TCP_KEY_FILE")
+	keyFile, err := filepath.Abs("/path/to/key")
+	if err != nil {
+		t.Fatal("unable to compute absolute key file path:", err)
+	}
+	test := parseTestCase{
+		raw:  "tcp://host:7820:tcp:localhost:8080",
+		kind: Kind_Forwarding,
+		expected: &URL{
+			Kind:     Kind_Forwarding,
+			Protocol: Protocol_TCP,
+			Host:     "host",
+			Port:     7820,
+			Path:     "tcp:localhost:8080",
+			Parameters: map[string]string{
+				TCPKeyFileParameter: keyFile,
+			},
+		},
+	}
+	test.run(t)
+}
+
+func TestParseRendezvous(t *testing.T) {
+	mockEnvironment["MUTAGEN_TCP_KEY_FILE"] = "/path/to/key"
+	defer delete(mockEnvironment, "MUTAGEN_TCP_KEY_FILE")
+	keyFile, err := filepath.Abs("/path/to/key")
+	if err != nil {
+		t.Fatal("unable to compute absolute key file path:", err)
+	}
+	test := parseTestCase{
+		raw: "rendezvous://office-nas/~/path",
+		expected: &URL{
+			Protocol: Protocol_Rendezvous,
+			Host:     "office-nas",
+			Path:     "~/path",
+			Parameters: map[string]string{
+				TCPKeyFileParameter: keyFile,
+			},
+		},
+	}
+	test.run(t)
+}
+
+func TestParseRendezvousMissingKeyFileInvalid(t *testing.T) {
+	test := parseTestCase{
+		raw:  "rendezvous://office-nas/path",
+		fail: true,
+	}
+	test.run(t)
+}
+
+func TestParseRendezvousInvalidNameInvalid(t *testing.T) {
+	mockEnvironment["MUTAGEN_TCP_KEY_FILE"] = "/path/to/key"
+	defer delete(mockEnvironment, "MUTAGEN_TCP_KEY_FILE")
+	test := parseTestCase{
+		raw:  "rendezvous://office@nas/path",
+		fail: true,
+	}
+	test.run(t)
+}
+
+func TestParseRendezvousEmptyNameInvalid(t *testing.T) {
+	mockEnvironment["MUTAGEN_TCP_KEY_FILE"] = "/path/to/key"
+	defer delete(mockEnvironment, "MUTAGEN_TCP_KEY_FILE")
+	test := parseTestCase{
+		raw:  "rendezvous:///path",
+		fail: true,
+	}
+	test.run(t)
+}
+
+func TestParsePlugin(t *testing.T) {
+	test := parseTestCase{
+		raw: "plugin+k8s://ns.pod.container/~/path",
+		expected: &URL{
+			Protocol: Protocol_Plugin,
+			Host:     "ns.pod.container",
+			Path:     "~/path",
+			Parameters: map[string]string{
+				PluginParameter: "k8s",
+			},
+		},
+	}
+	test.run(t)
+}
+
+func TestParsePluginWithAgentDirectory(t *testing.T) {
+	mockEnvironment["MUTAGEN_AGENT_DIRECTORY"] = "/opt/mutagen"
+	defer delete(mockEnvironment, "MUTAGEN_AGENT_DIRECTORY")
+	test := parseTestCase{
+		raw: "plugin+k8s://pod/var/www",
+		expected: &URL{
+			Protocol: Protocol_Plugin,
+			Host:     "pod",
+			Path:     "/var/www",
+			Parameters: map[string]string{
+				PluginParameter:         "k8s",
+				AgentDirectoryParameter: "/opt/mutagen",
+			},
+		},
+	}
+	test.run(t)
+}
+
+func TestParsePluginInvalidNameInvalid(t *testing.T) {
+	test := parseTestCase{
+		raw:  "plugin+K8S://pod/path",
+		fail: true,
+	}
+	test.run(t)
+}
+
+func TestParsePluginEmptyTargetInvalid(t *testing.T) {
+	test := parseTestCase{
+		raw:  "plugin+k8s:///path",
+		fail: true,
+	}
+	test.run(t)
+}
+
+func TestParsePluginFlagTargetInvalid(t *testing.T) {
+	test := parseTestCase{
+		raw:  "plugin+k8s://--help/path",
+		fail: true,
+	}
+	test.run(t)
+}
+
+func TestParsePluginHostPortTargetInvalid(t *testing.T) {
+	test := parseTestCase{
+		raw:  "plugin+k8s://host:2222/path",
+		fail: true,
+	}
+	test.run(t)
+}
+
+func TestParseForwardingPlugin(t *testing.T) {
+	test := parseTestCase{
+		raw:  "plugin+k8s://pod:tcp:localhost:8080",
+		kind: Kind_Forwarding,
+		expected: &URL{
+			Kind:     Kind_Forwarding,
+			Protocol: Protocol_Plugin,
+			Host:     "pod",
+			Path:     "tcp:localhost:8080",
+			Parameters: map[string]string{
+				PluginParameter: "k8s",
+			},
+		},
+	}
+	test.run(t)
+}
+
+func TestParseForwardingRendezvous(t *testing.T) {
+	mockEnvironment["MUTAGEN_TCP_KEY_FILE"] = "/path/to/key"
+	defer delete(mockEnvironment, "MUTAGEN_TCP_KEY_FILE")
+	keyFile, err := filepath.Abs("/path/to/key")
+	if err != nil {
+		t.Fatal("unable to compute absolute key file path:", err)
+	}
+	test := parseTestCase{
+		raw:  "rendezvous://office-nas:tcp:localhost:8080",
+		kind: Kind_Forwarding,
+		expected: &URL{
+			Kind:     Kind_Forwarding,
+			Protocol: Protocol_Rendezvous,
+			Host:     "office-nas",
+			Path:     "tcp:localhost:8080",
+			Parameters: map[string]string{
+				TCPKeyFileParameter: keyFile,
+			},
+		},
+	}
+	test.run(t)
+}
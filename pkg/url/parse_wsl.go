@@ -0,0 +1,21 @@
+package url
+
+import (
+	"strings"
+)
+
+// wslURLPrefix is the lowercase version of the WSL URL prefix.
+const wslURLPrefix = "wsl://"
+
+// isWSLURL checks whether or not a URL is a WSL URL. It requires the presence of
+// a WSL protocol prefix.
+func isWSLURL(raw string) bool {
+	return strings.HasPrefix(strings.ToLower(raw), wslURLPrefix)
+}
+
+// parseWSL parses a WSL URL. WSL URLs use the same format as Docker URLs, with
+// the container name specifying a WSL distribution. No environment variables
+// are locked in to WSL URLs.
+func parseWSL(raw string, kind Kind, first bool) (*URL, error) {
+	return parseContainer(raw[len(wslURLPrefix):], kind, first, Protocol_WSL, nil)
+}
@@ -5,6 +5,7 @@ import (
 	"fmt"
 	"math"
 	"path/filepath"
+	"strings"
 
 	"github.com/mutagen-io/mutagen/pkg/comparison"
 	"github.com/mutagen-io/mutagen/pkg/url/forwarding"
@@ -32,6 +33,30 @@ func (p Protocol) MarshalText() ([]byte, error) {
 		result = "ssh"
 	case Protocol_Docker:
 		result = "docker"
+	case Protocol_Nerdctl:
+		result = "nerdctl"
+	case Protocol_LXD:
+		result = "lxd"
+	case Protocol_WSL:
+		result = "wsl"
+	case Protocol_Azure:
+		result = "azure"
+	case Protocol_Teleport:
+		result = "teleport"
+	case Protocol_SFTP:
+		result = "sftp"
+	case Protocol_S3:
+		result = "s3"
+	case Protocol_Exec:
+		result = "exec"
+	case Protocol_TCP:
+		result = "tcp"
+	case Protocol_Rendezvous:
+		result = "rendezvous"
+	case Protocol_Plugin:
+		result = "plugin"
+	case Protocol_Kubernetes:
+		result = "kubernetes"
 	default:
 		result = "unknown"
 	}
@@ -51,6 +76,30 @@ func (p *Protocol) UnmarshalText(textBytes []byte) error {
 		*p = Protocol_SSH
 	case "docker":
 		*p = Protocol_Docker
+	case "nerdctl":
+		*p = Protocol_Nerdctl
+	case "lxd":
+		*p = Protocol_LXD
+	case "wsl":
+		*p = Protocol_WSL
+	case "azure":
+		*p = Protocol_Azure
+	case "teleport":
+		*p = Protocol_Teleport
+	case "sftp":
+		*p = Protocol_SFTP
+	case "s3":
+		*p = Protocol_S3
+	case "exec":
+		*p = Protocol_Exec
+	case "tcp":
+		*p = Protocol_TCP
+	case "rendezvous":
+		*p = Protocol_Rendezvous
+	case "plugin":
+		*p = Protocol_Plugin
+	case "kubernetes":
+		*p = Protocol_Kubernetes
 	default:
 		return fmt.Errorf("unknown protocol specification: %s", text)
 	}
@@ -93,6 +142,11 @@ func (u *URL) EnsureValid() error {
 		} else if len(u.Environment) != 0 {
 			return errors.New("SSH URL with environment variables")
 		}
+		for name, value := range u.Parameters {
+			if err := ensureSSHParameterValid(name, value); err != nil {
+				return fmt.Errorf("SSH URL with invalid parameter: %w", err)
+			}
+		}
 	} else if u.Protocol == Protocol_Docker {
 		// In the case of Docker, we intentionally avoid validating environment
 		// variables since the values used could change over time. Since we
@@ -104,10 +158,211 @@ func (u *URL) EnsureValid() error {
 		} else if u.Port != 0 {
 			return errors.New("Docker URL with non-zero port")
 		}
+		for name, value := range u.Parameters {
+			if name == AgentDirectoryParameter {
+				continue
+			} else if !isDockerParameterName(name) {
+				return fmt.Errorf("Docker URL with unknown parameter: %s", name)
+			} else if isDockerFlagParameterName(name) && value != "" {
+				return fmt.Errorf("Docker URL with non-empty flag parameter: %s", name)
+			} else if !isDockerFlagParameterName(name) && value == "" {
+				return fmt.Errorf("Docker URL with empty parameter: %s", name)
+			}
+		}
+		if _, ok := u.Parameters["context"]; ok {
+			if _, ok := u.Parameters["host"]; ok {
+				return errors.New("Docker URL with both context and host parameters")
+			}
+		}
+		if _, ok := u.Parameters["user"]; ok && u.User != "" {
+			return errors.New("Docker URL with both username and user parameter")
+		}
+		if workdir, ok := u.Parameters["workdir"]; ok && !(workdir[0] == '/' || isWindowsPath(workdir)) {
+			return errors.New("Docker URL with relative working directory")
+		}
+	} else if u.Protocol == Protocol_Nerdctl {
+		// As with Docker, we avoid validating environment variables. Unlike
+		// Docker, nerdctl URLs don't support any parameters other than the
+		// agent directory.
+		if u.Host == "" {
+			return errors.New("nerdctl URL with empty container identifier")
+		} else if u.Port != 0 {
+			return errors.New("nerdctl URL with non-zero port")
+		} else if _, remaining := SplitAgentDirectory(u.Parameters); len(remaining) != 0 {
+			return errors.New("nerdctl URL with parameters")
+		}
+	} else if u.Protocol == Protocol_LXD {
+		// As with Docker, we avoid validating environment variables.
+		if u.Host == "" {
+			return errors.New("LXD URL with empty instance name")
+		} else if u.Port != 0 {
+			return errors.New("LXD URL with non-zero port")
+		} else if _, remaining := SplitAgentDirectory(u.Parameters); len(remaining) != 0 {
+			return errors.New("LXD URL with parameters")
+		}
+	} else if u.Protocol == Protocol_Kubernetes {
+		// As with Docker, we avoid validating environment variables. The exec
+		// API doesn't support specifying a user, so none is allowed.
+		if _, err := ParseKubernetesTarget(u.Host); err != nil {
+			return fmt.Errorf("Kubernetes URL with invalid target: %w", err)
+		} else if u.User != "" {
+			return errors.New("Kubernetes URL with non-empty username")
+		} else if u.Port != 0 {
+			return errors.New("Kubernetes URL with non-zero port")
+		}
+		for name, value := range u.Parameters {
+			if name == AgentDirectoryParameter {
+				continue
+			} else if name != KubernetesContextParameter {
+				return fmt.Errorf("Kubernetes URL with unknown parameter: %s", name)
+			} else if value == "" {
+				return errors.New("Kubernetes URL with empty context")
+			}
+		}
+	} else if u.Protocol == Protocol_WSL {
+		if u.Host == "" {
+			return errors.New("WSL URL with empty distribution name")
+		} else if u.Port != 0 {
+			return errors.New("WSL URL with non-zero port")
+		} else if len(u.Environment) != 0 {
+			return errors.New("WSL URL with environment variables")
+		} else if _, remaining := SplitAgentDirectory(u.Parameters); len(remaining) != 0 {
+			return errors.New("WSL URL with parameters")
+		}
+	} else if u.Protocol == Protocol_Azure {
+		// As with Docker, we avoid validating environment variables.
+		if u.Host == "" {
+			return errors.New("Azure URL with empty virtual machine name")
+		} else if u.Port != 0 {
+			return errors.New("Azure URL with non-zero port")
+		} else if _, remaining := SplitAgentDirectory(u.Parameters); len(remaining) != 0 {
+			return errors.New("Azure URL with parameters")
+		}
+	} else if u.Protocol == Protocol_Teleport {
+		// As with Docker, we avoid validating environment variables.
+		if u.Host == "" {
+			return errors.New("Teleport URL with empty node name")
+		} else if u.Port != 0 {
+			return errors.New("Teleport URL with non-zero port")
+		} else if _, remaining := SplitAgentDirectory(u.Parameters); len(remaining) != 0 {
+			return errors.New("Teleport URL with parameters")
+		}
+	} else if u.Protocol == Protocol_SFTP {
+		// SFTP endpoints are only able to host synchronization sessions.
+		if u.Kind != Kind_Synchronization {
+			return errors.New("SFTP URL with non-synchronization kind")
+		} else if u.Host == "" {
+			return errors.New("SFTP URL with empty hostname")
+		} else if u.Port > math.MaxUint16 {
+			return errors.New("SFTP URL with invalid port")
+		} else if len(u.Environment) != 0 {
+			return errors.New("SFTP URL with environment variables")
+		} else if len(u.Parameters) != 0 {
+			return errors.New("SFTP URL with parameters")
+		}
+	} else if u.Protocol == Protocol_S3 {
+		// S3 endpoints are only able to host synchronization sessions. As with
+		// Docker, we avoid validating environment variables.
+		if u.Kind != Kind_Synchronization {
+			return errors.New("S3 URL with non-synchronization kind")
+		} else if u.User != "" {
+			return errors.New("S3 URL with non-empty username")
+		} else if u.Host == "" {
+			return errors.New("S3 URL with empty bucket name")
+		} else if u.Port != 0 {
+			return errors.New("S3 URL with non-zero port")
+		} else if len(u.Parameters) != 0 {
+			return errors.New("S3 URL with parameters")
+		}
+	} else if u.Protocol == Protocol_TCP {
+		if u.User != "" {
+			return errors.New("TCP URL with non-empty username")
+		} else if u.Host == "" {
+			return errors.New("TCP URL with empty hostname")
+		} else if u.Port == 0 || u.Port > math.MaxUint16 {
+			return errors.New("TCP URL with invalid port")
+		} else if len(u.Environment) != 0 {
+			return errors.New("TCP URL with environment variables")
+		}
+		for name, value := range u.Parameters {
+			if name != TCPKeyFileParameter {
+				return fmt.Errorf("TCP URL with unknown parameter: %s", name)
+			} else if !filepath.IsAbs(value) {
+				return errors.New("TCP URL with relative pre-shared key file path")
+			}
+		}
+		if _, ok := u.Parameters[TCPKeyFileParameter]; !ok {
+			return errors.New("TCP URL without pre-shared key file")
+		}
+	} else if u.Protocol == Protocol_Rendezvous {
+		if u.User != "" {
+			return errors.New("rendezvous URL with non-empty username")
+		} else if !IsValidRendezvousName(u.Host) {
+			return errors.New("rendezvous URL with invalid name")
+		} else if u.Port != 0 {
+			return errors.New("rendezvous URL with non-zero port")
+		} else if len(u.Environment) != 0 {
+			return errors.New("rendezvous URL with environment variables")
+		}
+		for name, value := range u.Parameters {
+			if name != TCPKeyFileParameter {
+				return fmt.Errorf("rendezvous URL with unknown parameter: %s", name)
+			} else if !filepath.IsAbs(value) {
+				return errors.New("rendezvous URL with relative pre-shared key file path")
+			}
+		}
+		if _, ok := u.Parameters[TCPKeyFileParameter]; !ok {
+			return errors.New("rendezvous URL without pre-shared key file")
+		}
+	} else if u.Protocol == Protocol_Plugin {
+		if u.User != "" {
+			return errors.New("plugin URL with non-empty username")
+		} else if !isValidPluginTarget(u.Host) {
+			return errors.New("plugin URL with invalid target")
+		} else if u.Port != 0 {
+			return errors.New("plugin URL with non-zero port")
+		} else if len(u.Environment) != 0 {
+			return errors.New("plugin URL with environment variables")
+		} else if !IsValidPluginName(u.Parameters[PluginParameter]) {
+			return errors.New("plugin URL with missing or invalid plugin name")
+		}
+		for name := range u.Parameters {
+			if name != PluginParameter && name != AgentDirectoryParameter {
+				return fmt.Errorf("plugin URL with unknown parameter: %s", name)
+			}
+		}
+	} else if u.Protocol == Protocol_Exec {
+		// We disallow quotes in the command since they would prevent the URL
+		// from being formatted in a reparsable manner.
+		if u.User != "" {
+			return errors.New("exec URL with non-empty username")
+		} else if strings.TrimSpace(u.Host) == "" {
+			return errors.New("exec URL with empty command")
+		} else if strings.ContainsAny(u.Host, "'\"") {
+			return errors.New("exec URL with quotes in command")
+		} else if u.Port != 0 {
+			return errors.New("exec URL with non-zero port")
+		}
+		for name := range u.Environment {
+			if !isValidExecEnvironmentVariableName(name) {
+				return fmt.Errorf("exec URL with invalid environment variable name: %s", name)
+			}
+		}
+		for name, value := range u.Parameters {
+			if err := ensureExecParameterValid(name, value); err != nil {
+				return fmt.Errorf("exec URL with invalid parameter: %w", err)
+			}
+		}
 	} else {
 		return errors.New("unknown or unsupported protocol")
 	}
 
+	// Validate the agent directory, if specified. Protocols that don't rely on
+	// an agent will have already rejected it above.
+	if directory, ok := u.Parameters[AgentDirectoryParameter]; ok && !IsValidAgentDirectory(directory) {
+		return fmt.Errorf("invalid agent directory: %s", directory)
+	}
+
 	// Validate the path component depending on the URL kind.
 	if u.Kind == Kind_Synchronization {
 		// Ensure the path is non-empty.
@@ -120,12 +375,26 @@ func (u *URL) EnsureValid() error {
 			return errors.New("local URL with relative path")
 		}
 
-		// If this is a Docker URL, we can actually do a bit of additional
-		// validation.
-		if u.Protocol == Protocol_Docker {
+		// If this is a container-style URL, we can actually do a bit of
+		// additional validation.
+		if u.Protocol == Protocol_Docker || u.Protocol == Protocol_Nerdctl || u.Protocol == Protocol_Azure || u.Protocol == Protocol_Exec || u.Protocol == Protocol_TCP || u.Protocol == Protocol_Rendezvous || u.Protocol == Protocol_Plugin {
 			if !(u.Path[0] == '/' || u.Path[0] == '~' || isWindowsPath(u.Path)) {
 				return errors.New("incorrect first path character")
 			}
+		} else if u.Protocol == Protocol_LXD || u.Protocol == Protocol_WSL || u.Protocol == Protocol_Teleport || u.Protocol == Protocol_SFTP || u.Protocol == Protocol_Kubernetes {
+			// LXD containers, WSL distributions, and Teleport SSH nodes are
+			// always POSIX systems, so Windows paths aren't valid. SFTP paths
+			// are always POSIX-style, even on Windows servers. The Kubernetes
+			// transport only supports POSIX containers.
+			if !(u.Path[0] == '/' || u.Path[0] == '~') {
+				return errors.New("incorrect first path character")
+			}
+		} else if u.Protocol == Protocol_S3 {
+			// S3 paths are key prefixes that are always stored with a leading
+			// slash.
+			if u.Path[0] != '/' {
+				return errors.New("incorrect first path character")
+			}
 		}
 	} else if u.Kind == Kind_Forwarding {
 		// Parse the forwarding endpoint URL to ensure that it's valid.
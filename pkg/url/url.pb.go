@@ -79,6 +79,42 @@ const (
 	Protocol_SSH Protocol = 1
 	// Docker indicates that the resource is inside a Docker container.
 	Protocol_Docker Protocol = 11
+	// Nerdctl indicates that the resource is inside a containerd container that
+	// is accessible via nerdctl.
+	Protocol_Nerdctl Protocol = 12
+	// LXD indicates that the resource is inside an LXD (or Incus) container.
+	Protocol_LXD Protocol = 13
+	// WSL indicates that the resource is inside a Windows Subsystem for Linux
+	// distribution.
+	Protocol_WSL Protocol = 14
+	// Azure indicates that the resource is on an Azure virtual machine that is
+	// accessible via the Azure CLI's SSH extension.
+	Protocol_Azure Protocol = 15
+	// Teleport indicates that the resource is on a host that is accessible via
+	// Teleport.
+	Protocol_Teleport Protocol = 16
+	// SFTP indicates that the resource is on a host that is accessible via
+	// SFTP (without the use of an agent).
+	Protocol_SFTP Protocol = 17
+	// S3 indicates that the resource is in S3-compatible object storage.
+	Protocol_S3 Protocol = 18
+	// Exec indicates that the resource is on a system that is accessible via
+	// an arbitrary user-specified command capable of executing commands on that
+	// system (e.g. "kubectl exec").
+	Protocol_Exec Protocol = 19
+	// TCP indicates that the resource is on a host that is accessible via an
+	// agent listening on a TCP port with pre-shared key authentication.
+	Protocol_TCP Protocol = 20
+	// Rendezvous indicates that the resource is on a host whose agent dials
+	// out to the daemon's rendezvous listener (e.g. from behind NAT), using
+	// pre-shared key authentication.
+	Protocol_Rendezvous Protocol = 21
+	// Plugin indicates that the resource is on a system that is accessible via
+	// an external protocol handler plugin executable.
+	Protocol_Plugin Protocol = 22
+	// Kubernetes indicates that the resource is inside a container in a
+	// Kubernetes pod that is accessible via the Kubernetes API.
+	Protocol_Kubernetes Protocol = 23
 )
 
 // Enum value maps for Protocol.
@@ -87,11 +123,35 @@ var (
 		0:  "Local",
 		1:  "SSH",
 		11: "Docker",
+		12: "Nerdctl",
+		13: "LXD",
+		14: "WSL",
+		15: "Azure",
+		16: "Teleport",
+		17: "SFTP",
+		18: "S3",
+		19: "Exec",
+		20: "TCP",
+		21: "Rendezvous",
+		22: "Plugin",
+		23: "Kubernetes",
 	}
 	Protocol_value = map[string]int32{
-		"Local":  0,
-		"SSH":    1,
-		"Docker": 11,
+		"Local":      0,
+		"SSH":        1,
+		"Docker":     11,
+		"Nerdctl":    12,
+		"LXD":        13,
+		"WSL":        14,
+		"Azure":      15,
+		"Teleport":   16,
+		"SFTP":       17,
+		"S3":         18,
+		"Exec":       19,
+		"TCP":        20,
+		"Rendezvous": 21,
+		"Plugin":     22,
+		"Kubernetes": 23,
 	}
 )
 
@@ -273,13 +333,21 @@ var file_url_url_proto_rawDesc = []byte{
 	0x09, 0x52, 0x05, 0x76, 0x61, 0x6c, 0x75, 0x65, 0x3a, 0x02, 0x38, 0x01, 0x2a, 0x2b, 0x0a, 0x04,
 	0x4b, 0x69, 0x6e, 0x64, 0x12, 0x13, 0x0a, 0x0f, 0x53, 0x79, 0x6e, 0x63, 0x68, 0x72, 0x6f, 0x6e,
 	0x69, 0x7a, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x10, 0x00, 0x12, 0x0e, 0x0a, 0x0a, 0x46, 0x6f, 0x72,
-	0x77, 0x61, 0x72, 0x64, 0x69, 0x6e, 0x67, 0x10, 0x01, 0x2a, 0x2a, 0x0a, 0x08, 0x50, 0x72, 0x6f,
-	0x74, 0x6f, 0x63, 0x6f, 0x6c, 0x12, 0x09, 0x0a, 0x05, 0x4c, 0x6f, 0x63, 0x61, 0x6c, 0x10, 0x00,
-	0x12, 0x07, 0x0a, 0x03, 0x53, 0x53, 0x48, 0x10, 0x01, 0x12, 0x0a, 0x0a, 0x06, 0x44, 0x6f, 0x63,
-	0x6b, 0x65, 0x72, 0x10, 0x0b, 0x42, 0x27, 0x5a, 0x25, 0x67, 0x69, 0x74, 0x68, 0x75, 0x62, 0x2e,
-	0x63, 0x6f, 0x6d, 0x2f, 0x6d, 0x75, 0x74, 0x61, 0x67, 0x65, 0x6e, 0x2d, 0x69, 0x6f, 0x2f, 0x6d,
-	0x75, 0x74, 0x61, 0x67, 0x65, 0x6e, 0x2f, 0x70, 0x6b, 0x67, 0x2f, 0x75, 0x72, 0x6c, 0x62, 0x06,
-	0x70, 0x72, 0x6f, 0x74, 0x6f, 0x33,
+	0x77, 0x61, 0x72, 0x64, 0x69, 0x6e, 0x67, 0x10, 0x01, 0x2a, 0xb3, 0x01, 0x0a, 0x08, 0x50, 0x72,
+	0x6f, 0x74, 0x6f, 0x63, 0x6f, 0x6c, 0x12, 0x09, 0x0a, 0x05, 0x4c, 0x6f, 0x63, 0x61, 0x6c, 0x10,
+	0x00, 0x12, 0x07, 0x0a, 0x03, 0x53, 0x53, 0x48, 0x10, 0x01, 0x12, 0x0a, 0x0a, 0x06, 0x44, 0x6f,
+	0x63, 0x6b, 0x65, 0x72, 0x10, 0x0b, 0x12, 0x0b, 0x0a, 0x07, 0x4e, 0x65, 0x72, 0x64, 0x63, 0x74,
+	0x6c, 0x10, 0x0c, 0x12, 0x07, 0x0a, 0x03, 0x4c, 0x58, 0x44, 0x10, 0x0d, 0x12, 0x07, 0x0a, 0x03,
+	0x57, 0x53, 0x4c, 0x10, 0x0e, 0x12, 0x09, 0x0a, 0x05, 0x41, 0x7a, 0x75, 0x72, 0x65, 0x10, 0x0f,
+	0x12, 0x0c, 0x0a, 0x08, 0x54, 0x65, 0x6c, 0x65, 0x70, 0x6f, 0x72, 0x74, 0x10, 0x10, 0x12, 0x08,
+	0x0a, 0x04, 0x53, 0x46, 0x54, 0x50, 0x10, 0x11, 0x12, 0x06, 0x0a, 0x02, 0x53, 0x33, 0x10, 0x12,
+	0x12, 0x08, 0x0a, 0x04, 0x45, 0x78, 0x65, 0x63, 0x10, 0x13, 0x12, 0x07, 0x0a, 0x03, 0x54, 0x43,
+	0x50, 0x10, 0x14, 0x12, 0x0e, 0x0a, 0x0a, 0x52, 0x65, 0x6e, 0x64, 0x65, 0x7a, 0x76, 0x6f, 0x75,
+	0x73, 0x10, 0x15, 0x12, 0x0a, 0x0a, 0x06, 0x50, 0x6c, 0x75, 0x67, 0x69, 0x6e, 0x10, 0x16, 0x12,
+	0x0e, 0x0a, 0x0a, 0x4b, 0x75, 0x62, 0x65, 0x72, 0x6e, 0x65, 0x74, 0x65, 0x73, 0x10, 0x17, 0x42,
+	0x27, 0x5a, 0x25, 0x67, 0x69, 0x74, 0x68, 0x75, 0x62, 0x2e, 0x63, 0x6f, 0x6d, 0x2f, 0x6d, 0x75,
+	0x74, 0x61, 0x67, 0x65, 0x6e, 0x2d, 0x69, 0x6f, 0x2f, 0x6d, 0x75, 0x74, 0x61, 0x67, 0x65, 0x6e,
+	0x2f, 0x70, 0x6b, 0x67, 0x2f, 0x75, 0x72, 0x6c, 0x62, 0x06, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x33,
 }
 
 var (
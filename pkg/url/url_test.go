@@ -1,6 +1,7 @@
 package url
 
 import (
+	"runtime"
 	"testing"
 
 	"github.com/mutagen-io/mutagen/pkg/filesystem"
@@ -216,6 +217,132 @@ func TestURLEnsureValidSSH(t *testing.T) {
 	}
 }
 
+func TestURLEnsureValidSSHUnknownParameterInvalid(t *testing.T) {
+	invalid := &URL{
+		Protocol: Protocol_SSH,
+		Host:     "washington",
+		Path:     "~/path",
+		Parameters: map[string]string{
+			"key": "value",
+		},
+	}
+	if invalid.EnsureValid() == nil {
+		t.Error("invalid URL classified as valid")
+	}
+}
+
+func TestURLEnsureValidSSHInvalidClientInvalid(t *testing.T) {
+	invalid := &URL{
+		Protocol: Protocol_SSH,
+		Host:     "washington",
+		Path:     "~/path",
+		Parameters: map[string]string{
+			SSHClientParameter: "putty",
+		},
+	}
+	if invalid.EnsureValid() == nil {
+		t.Error("invalid URL classified as valid")
+	}
+}
+
+func TestURLEnsureValidSSHInvalidForwardAgentInvalid(t *testing.T) {
+	invalid := &URL{
+		Protocol: Protocol_SSH,
+		Host:     "washington",
+		Path:     "~/path",
+		Parameters: map[string]string{
+			SSHForwardAgentParameter: "false",
+		},
+	}
+	if invalid.EnsureValid() == nil {
+		t.Error("invalid URL classified as valid")
+	}
+}
+
+func TestURLEnsureValidSSHForwardAgent(t *testing.T) {
+	valid := &URL{
+		Protocol: Protocol_SSH,
+		Host:     "washington",
+		Path:     "~/path",
+		Parameters: map[string]string{
+			SSHForwardAgentParameter: "true",
+		},
+	}
+	if err := valid.EnsureValid(); err != nil {
+		t.Error("valid URL classified as invalid")
+	}
+}
+
+func TestURLEnsureValidSSHNativeClient(t *testing.T) {
+	valid := &URL{
+		Protocol: Protocol_SSH,
+		Host:     "washington",
+		Path:     "~/path",
+		Parameters: map[string]string{
+			SSHClientParameter: SSHClientNative,
+		},
+	}
+	if err := valid.EnsureValid(); err != nil {
+		t.Error("valid URL classified as invalid")
+	}
+}
+
+func TestURLEnsureValidSSHAgentDirectory(t *testing.T) {
+	valid := &URL{
+		Protocol: Protocol_SSH,
+		Host:     "washington",
+		Path:     "~/path",
+		Parameters: map[string]string{
+			AgentDirectoryParameter: "/tmp/.mutagen",
+		},
+	}
+	if err := valid.EnsureValid(); err != nil {
+		t.Error("valid URL classified as invalid")
+	}
+}
+
+func TestURLEnsureValidDockerInvalidAgentDirectoryInvalid(t *testing.T) {
+	invalid := &URL{
+		Protocol: Protocol_Docker,
+		Host:     "washington",
+		Path:     "~/path",
+		Parameters: map[string]string{
+			AgentDirectoryParameter: "/tmp/my directory",
+		},
+	}
+	if invalid.EnsureValid() == nil {
+		t.Error("invalid URL classified as valid")
+	}
+}
+
+func TestURLEnsureValidLXDAgentDirectory(t *testing.T) {
+	valid := &URL{
+		Protocol: Protocol_LXD,
+		Host:     "washington",
+		Path:     "~/path",
+		Parameters: map[string]string{
+			AgentDirectoryParameter: "/tmp/.mutagen",
+		},
+	}
+	if err := valid.EnsureValid(); err != nil {
+		t.Error("valid URL classified as invalid")
+	}
+}
+
+func TestURLEnsureValidSFTPAgentDirectoryInvalid(t *testing.T) {
+	invalid := &URL{
+		Protocol: Protocol_SFTP,
+		Host:     "washington",
+		Path:     "/path",
+		Parameters: map[string]string{
+			AgentDirectoryParameter: "/tmp/.mutagen",
+		},
+	}
+	if invalid.EnsureValid() == nil {
+		t.Error("invalid URL classified as valid")
+	}
+}
+
 func TestURLEnsureValidDockerPortInvalid(t *testing.T) {
 	invalid := &URL{
 		Protocol: Protocol_Docker,
@@ -300,3 +427,598 @@ func TestURLEnsureValidDocker(t *testing.T) {
 		t.Error("valid URL classified as invalid")
 	}
 }
+
+func TestURLEnsureValidNerdctlParametersInvalid(t *testing.T) {
+	invalid := &URL{
+		Protocol: Protocol_Nerdctl,
+		Host:     "washington",
+		Path:     "/path",
+		Parameters: map[string]string{
+			"host": "unix:///run/containerd/containerd.sock",
+		},
+	}
+	if invalid.EnsureValid() == nil {
+		t.Error("invalid URL classified as valid")
+	}
+}
+
+func TestURLEnsureValidNerdctlBadPathInvalid(t *testing.T) {
+	invalid := &URL{
+		Protocol: Protocol_Nerdctl,
+		Host:     "washington",
+		Path:     "$path",
+	}
+	if invalid.EnsureValid() == nil {
+		t.Error("invalid URL classified as valid")
+	}
+}
+
+func TestURLEnsureValidNerdctl(t *testing.T) {
+	valid := &URL{
+		Protocol: Protocol_Nerdctl,
+		User:     "george",
+		Host:     "washington",
+		Path:     "~/path",
+		Environment: map[string]string{
+			"CONTAINERD_NAMESPACE": "k8s.io",
+		},
+	}
+	if err := valid.EnsureValid(); err != nil {
+		t.Error("valid URL classified as invalid")
+	}
+}
+
+func TestURLEnsureValidLXDWindowsPathInvalid(t *testing.T) {
+	invalid := &URL{
+		Protocol: Protocol_LXD,
+		Host:     "instance",
+		Path:     `C:\path`,
+	}
+	if invalid.EnsureValid() == nil {
+		t.Error("invalid URL classified as valid")
+	}
+}
+
+func TestURLEnsureValidLXD(t *testing.T) {
+	valid := &URL{
+		Protocol: Protocol_LXD,
+		User:     "george",
+		Host:     "instance",
+		Path:     "~/path",
+	}
+	if err := valid.EnsureValid(); err != nil {
+		t.Error("valid URL classified as invalid")
+	}
+}
+
+func TestURLEnsureValidKubernetesInvalidTargetInvalid(t *testing.T) {
+	hosts := []string{
+		"", "web-0", "default/web-0", "default/web-0/app/extra", "default//app", "Default/web-0/app",
+		"default/deployment:/app", "default/deployment:Web/app", "default/selector:/app", "default/selector:%zz/app",
+	}
+	for _, host := range hosts {
+		invalid := &URL{
+			Protocol: Protocol_Kubernetes,
+			Host:     host,
+			Path:     "/path",
+		}
+		if invalid.EnsureValid() == nil {
+			t.Error("invalid URL classified as valid:", host)
+		}
+	}
+}
+
+func TestParseKubernetesTarget(t *testing.T) {
+	// Set up test cases.
+	testCases := []struct {
+		// target is the target to parse.
+		target string
+		// expected is the expected parsed target.
+		expected *KubernetesTarget
+	}{
+		{"default/web-0/app", &KubernetesTarget{Namespace: "default", Pod: "web-0", Container: "app"}},
+		{"default/deployment:web/app", &KubernetesTarget{Namespace: "default", Deployment: "web", Container: "app"}},
+		{"default/selector:app=web/app", &KubernetesTarget{Namespace: "default", Selector: "app=web", Container: "app"}},
+		{
+			"default/selector:app.kubernetes.io%2Fname=web,tier%20in%20(a,b)/app",
+			&KubernetesTarget{Namespace: "default", Selector: "app.kubernetes.io/name=web,tier in (a,b)", Container: "app"},
+		},
+	}
+
+	// Process test cases.
+	for i, testCase := range testCases {
+		if target, err := ParseKubernetesTarget(testCase.target); err != nil {
+			t.Errorf("test index %d: unable to parse target: %v", i, err)
+		} else if *target != *testCase.expected {
+			t.Errorf("test index %d: parsed target does not match expected: %+v != %+v", i, *target, *testCase.expected)
+		}
+	}
+}
+
+func TestURLEnsureValidKubernetesUsernameInvalid(t *testing.T) {
+	invalid := &URL{
+		Protocol: Protocol_Kubernetes,
+		User:     "george",
+		Host:     "default/web-0/app",
+		Path:     "/path",
+	}
+	if invalid.EnsureValid() == nil {
+		t.Error("invalid URL classified as valid")
+	}
+}
+
+func TestURLEnsureValidKubernetesWindowsPathInvalid(t *testing.T) {
+	invalid := &URL{
+		Protocol: Protocol_Kubernetes,
+		Host:     "default/web-0/app",
+		Path:     `C:\path`,
+	}
+	if invalid.EnsureValid() == nil {
+		t.Error("invalid URL classified as valid")
+	}
+}
+
+func TestURLEnsureValidKubernetesUnknownParameterInvalid(t *testing.T) {
+	invalid := &URL{
+		Protocol: Protocol_Kubernetes,
+		Host:     "default/web-0/app",
+		Path:     "/path",
+		Parameters: map[string]string{
+			"namespace": "other",
+		},
+	}
+	if invalid.EnsureValid() == nil {
+		t.Error("invalid URL classified as valid")
+	}
+}
+
+func TestURLEnsureValidKubernetes(t *testing.T) {
+	valid := &URL{
+		Protocol: Protocol_Kubernetes,
+		Host:     "default/web-0/app",
+		Path:     "~/path",
+		Parameters: map[string]string{
+			KubernetesContextParameter: "staging",
+			AgentDirectoryParameter:    "/tmp/.mutagen",
+		},
+	}
+	if err := valid.EnsureValid(); err != nil {
+		t.Error("valid URL classified as invalid:", err)
+	}
+}
+
+func TestURLEnsureValidWSLEnvironmentInvalid(t *testing.T) {
+	invalid := &URL{
+		Protocol: Protocol_WSL,
+		Host:     "Ubuntu",
+		Path:     "~/path",
+		Environment: map[string]string{
+			"WSLENV": "GOPATH/l",
+		},
+	}
+	if invalid.EnsureValid() == nil {
+		t.Error("invalid URL classified as valid")
+	}
+}
+
+func TestURLEnsureValidWSL(t *testing.T) {
+	valid := &URL{
+		Protocol: Protocol_WSL,
+		User:     "george",
+		Host:     "Ubuntu",
+		Path:     "~/path",
+	}
+	if err := valid.EnsureValid(); err != nil {
+		t.Error("valid URL classified as invalid")
+	}
+}
+
+func TestURLEnsureValidAzurePortInvalid(t *testing.T) {
+	invalid := &URL{
+		Protocol: Protocol_Azure,
+		Host:     "vm-1",
+		Port:     22,
+		Path:     "~/path",
+	}
+	if invalid.EnsureValid() == nil {
+		t.Error("invalid URL classified as valid")
+	}
+}
+
+func TestURLEnsureValidAzure(t *testing.T) {
+	valid := &URL{
+		Protocol: Protocol_Azure,
+		User:     "azureuser",
+		Host:     "vm-1",
+		Path:     "~/path",
+		Environment: map[string]string{
+			"AZURE_DEFAULTS_GROUP": "resources",
+		},
+	}
+	if err := valid.EnsureValid(); err != nil {
+		t.Error("valid URL classified as invalid")
+	}
+}
+
+func TestURLEnsureValidTeleportWindowsPathInvalid(t *testing.T) {
+	invalid := &URL{
+		Protocol: Protocol_Teleport,
+		Host:     "node-1",
+		Path:     `C:\path`,
+	}
+	if invalid.EnsureValid() == nil {
+		t.Error("invalid URL classified as valid")
+	}
+}
+
+func TestURLEnsureValidTeleport(t *testing.T) {
+	valid := &URL{
+		Protocol: Protocol_Teleport,
+		User:     "root",
+		Host:     "node-1",
+		Path:     "/path",
+		Environment: map[string]string{
+			"TELEPORT_PROXY": "teleport.example.com:443",
+		},
+	}
+	if err := valid.EnsureValid(); err != nil {
+		t.Error("valid URL classified as invalid")
+	}
+}
+
+func TestURLEnsureValidSFTPForwardingInvalid(t *testing.T) {
+	invalid := &URL{
+		Kind:     Kind_Forwarding,
+		Protocol: Protocol_SFTP,
+		Host:     "example.com",
+		Path:     "tcp:localhost:8080",
+	}
+	if invalid.EnsureValid() == nil {
+		t.Error("invalid URL classified as valid")
+	}
+}
+
+func TestURLEnsureValidSFTPEnvironmentInvalid(t *testing.T) {
+	invalid := &URL{
+		Protocol:    Protocol_SFTP,
+		Host:        "example.com",
+		Path:        "/path",
+		Environment: map[string]string{"key": "value"},
+	}
+	if invalid.EnsureValid() == nil {
+		t.Error("invalid URL classified as valid")
+	}
+}
+
+func TestURLEnsureValidS3ForwardingInvalid(t *testing.T) {
+	invalid := &URL{
+		Kind:     Kind_Forwarding,
+		Protocol: Protocol_S3,
+		Host:     "bucket",
+		Path:     "tcp:localhost:8080",
+	}
+	if invalid.EnsureValid() == nil {
+		t.Error("invalid URL classified as valid")
+	}
+}
+
+func TestURLEnsureValidS3RelativePathInvalid(t *testing.T) {
+	invalid := &URL{
+		Protocol: Protocol_S3,
+		Host:     "bucket",
+		Path:     "prefix",
+	}
+	if invalid.EnsureValid() == nil {
+		t.Error("invalid URL classified as valid")
+	}
+}
+
+func TestURLEnsureValidS3(t *testing.T) {
+	valid := &URL{
+		Protocol: Protocol_S3,
+		Host:     "bucket",
+		Path:     "/prefix",
+	}
+	if err := valid.EnsureValid(); err != nil {
+		t.Error("valid URL classified as invalid")
+	}
+}
+
+func TestURLEnsureValidSFTP(t *testing.T) {
+	valid := &URL{
+		Protocol: Protocol_SFTP,
+		User:     "user",
+		Host:     "example.com",
+		Port:     2222,
+		Path:     "~/path",
+	}
+	if err := valid.EnsureValid(); err != nil {
+		t.Error("valid URL classified as invalid")
+	}
+}
+
+func TestURLEnsureValidExec(t *testing.T) {
+	valid := &URL{
+		Protocol: Protocol_Exec,
+		Host:     "kubectl exec -i pod --",
+		Path:     "/var/www",
+		Parameters: map[string]string{
+			ExecCopyParameter:       "kubectl cp {local} pod:{remote}",
+			AgentDirectoryParameter: "/tmp/.mutagen",
+		},
+	}
+	if err := valid.EnsureValid(); err != nil {
+		t.Error("valid URL classified as invalid")
+	}
+}
+
+func TestURLEnsureValidExecInvalidCopyInvalid(t *testing.T) {
+	invalid := &URL{
+		Protocol: Protocol_Exec,
+		Host:     "kubectl exec -i pod --",
+		Path:     "/var/www",
+		Parameters: map[string]string{
+			ExecCopyParameter: "kubectl cp {local} pod:/tmp",
+		},
+	}
+	if invalid.EnsureValid() == nil {
+		t.Error("invalid URL classified as valid")
+	}
+}
+
+func TestURLEnsureValidExecQuotedCommandInvalid(t *testing.T) {
+	invalid := &URL{
+		Protocol: Protocol_Exec,
+		Host:     "sh -c 'true'",
+		Path:     "/var/www",
+	}
+	if invalid.EnsureValid() == nil {
+		t.Error("invalid URL classified as valid")
+	}
+}
+
+func TestURLEnsureValidExecInvalidEnvironmentVariableNameInvalid(t *testing.T) {
+	invalid := &URL{
+		Protocol: Protocol_Exec,
+		Host:     "kubectl exec -i pod --",
+		Path:     "/var/www",
+		Environment: map[string]string{
+			"KUBE=CONFIG": "/path/to/kubeconfig",
+		},
+	}
+	if invalid.EnsureValid() == nil {
+		t.Error("invalid URL classified as valid")
+	}
+}
+
+func TestURLEnsureValidDockerWithParameters(t *testing.T) {
+	valid := &URL{
+		Protocol: Protocol_Docker,
+		Host:     "container",
+		Path:     "/path",
+		Parameters: map[string]string{
+			"context":               "remote-box",
+			"tls":                   "",
+			AgentDirectoryParameter: "/tmp/.mutagen",
+		},
+	}
+	if err := valid.EnsureValid(); err != nil {
+		t.Error("valid URL classified as invalid:", err)
+	}
+}
+
+func TestURLEnsureValidDockerUnknownParameterInvalid(t *testing.T) {
+	invalid := &URL{
+		Protocol: Protocol_Docker,
+		Host:     "container",
+		Path:     "/path",
+		Parameters: map[string]string{
+			"unknown": "value",
+		},
+	}
+	if invalid.EnsureValid() == nil {
+		t.Error("invalid URL classified as valid")
+	}
+}
+
+func TestURLEnsureValidDockerEmptyContextInvalid(t *testing.T) {
+	invalid := &URL{
+		Protocol: Protocol_Docker,
+		Host:     "container",
+		Path:     "/path",
+		Parameters: map[string]string{
+			"context": "",
+		},
+	}
+	if invalid.EnsureValid() == nil {
+		t.Error("invalid URL classified as valid")
+	}
+}
+
+func TestURLEnsureValidDockerContextAndHostInvalid(t *testing.T) {
+	invalid := &URL{
+		Protocol: Protocol_Docker,
+		Host:     "container",
+		Path:     "/path",
+		Parameters: map[string]string{
+			"context": "remote-box",
+			"host":    "tcp://remote-box:2376",
+		},
+	}
+	if invalid.EnsureValid() == nil {
+		t.Error("invalid URL classified as valid")
+	}
+}
+
+func TestURLEnsureValidDockerUsernameAndUserParameterInvalid(t *testing.T) {
+	invalid := &URL{
+		Protocol: Protocol_Docker,
+		User:     "user",
+		Host:     "container",
+		Path:     "/path",
+		Parameters: map[string]string{
+			"user": "1000",
+		},
+	}
+	if invalid.EnsureValid() == nil {
+		t.Error("invalid URL classified as valid")
+	}
+}
+
+func TestURLEnsureValidDockerRelativeWorkdirInvalid(t *testing.T) {
+	invalid := &URL{
+		Protocol: Protocol_Docker,
+		Host:     "container",
+		Path:     "/path",
+		Parameters: map[string]string{
+			"workdir": "srv",
+		},
+	}
+	if invalid.EnsureValid() == nil {
+		t.Error("invalid URL classified as valid")
+	}
+}
+
+func TestURLEnsureValidTCP(t *testing.T) {
+	valid := &URL{
+		Protocol: Protocol_TCP,
+		Host:     "host",
+		Port:     7820,
+		Path:     "/var/www",
+		Parameters: map[string]string{
+			TCPKeyFileParameter: "/path/to/key",
+		},
+	}
+	if runtime.GOOS == "windows" {
+		valid.Parameters[TCPKeyFileParameter] = `C:\path\to\key`
+	}
+	if err := valid.EnsureValid(); err != nil {
+		t.Error("valid URL classified as invalid:", err)
+	}
+}
+
+func TestURLEnsureValidTCPWithoutKeyFileInvalid(t *testing.T) {
+	invalid := &URL{
+		Protocol: Protocol_TCP,
+		Host:     "host",
+		Port:     7820,
+		Path:     "/var/www",
+	}
+	if invalid.EnsureValid() == nil {
+		t.Error("invalid URL classified as valid")
+	}
+}
+
+func TestURLEnsureValidTCPWithoutPortInvalid(t *testing.T) {
+	invalid := &URL{
+		Protocol: Protocol_TCP,
+		Host:     "host",
+		Path:     "/var/www",
+		Parameters: map[string]string{
+			TCPKeyFileParameter: "/path/to/key",
+		},
+	}
+	if invalid.EnsureValid() == nil {
+		t.Error("invalid URL classified as valid")
+	}
+}
+
+func TestURLEnsureValidRendezvous(t *testing.T) {
+	valid := &URL{
+		Protocol: Protocol_Rendezvous,
+		Host:     "office-nas",
+		Path:     "/var/www",
+		Parameters: map[string]string{
+			TCPKeyFileParameter: "/path/to/key",
+		},
+	}
+	if runtime.GOOS == "windows" {
+		valid.Parameters[TCPKeyFileParameter] = `C:\path\to\key`
+	}
+	if err := valid.EnsureValid(); err != nil {
+		t.Error("valid URL classified as invalid:", err)
+	}
+}
+
+func TestURLEnsureValidPlugin(t *testing.T) {
+	valid := &URL{
+		Protocol: Protocol_Plugin,
+		Host:     "pod",
+		Path:     "/var/www",
+		Parameters: map[string]string{
+			PluginParameter: "k8s",
+		},
+	}
+	if err := valid.EnsureValid(); err != nil {
+		t.Error("valid URL classified as invalid:", err)
+	}
+}
+
+func TestURLEnsureValidPluginFlagTargetInvalid(t *testing.T) {
+	invalid := &URL{
+		Protocol: Protocol_Plugin,
+		Host:     "-oProxyCommand=true",
+		Path:     "/var/www",
+		Parameters: map[string]string{
+			PluginParameter: "k8s",
+		},
+	}
+	if invalid.EnsureValid() == nil {
+		t.Error("invalid URL classified as valid")
+	}
+}
+
+func TestURLEnsureValidPluginWithoutNameInvalid(t *testing.T) {
+	invalid := &URL{
+		Protocol: Protocol_Plugin,
+		Host:     "pod",
+		Path:     "/var/www",
+	}
+	if invalid.EnsureValid() == nil {
+		t.Error("invalid URL classified as valid")
+	}
+}
+
+func TestURLEnsureValidPluginUnknownParameterInvalid(t *testing.T) {
+	invalid := &URL{
+		Protocol: Protocol_Plugin,
+		Host:     "pod",
+		Path:     "/var/www",
+		Parameters: map[string]string{
+			PluginParameter: "k8s",
+			"unknown":       "value",
+		},
+	}
+	if invalid.EnsureValid() == nil {
+		t.Error("invalid URL classified as valid")
+	}
+}
+
+func TestURLEnsureValidRendezvousWithPortInvalid(t *testing.T) {
+	invalid := &URL{
+		Protocol: Protocol_Rendezvous,
+		Host:     "office-nas",
+		Port:     7820,
+		Path:     "/var/www",
+		Parameters: map[string]string{
+			TCPKeyFileParameter: "/path/to/key",
+		},
+	}
+	if invalid.EnsureValid() == nil {
+		t.Error("invalid URL classified as valid")
+	}
+}
+
+func TestURLEnsureValidRendezvousInvalidNameInvalid(t *testing.T) {
+	invalid := &URL{
+		Protocol: Protocol_Rendezvous,
+		Host:     "office nas",
+		Path:     "/var/www",
+		Parameters: map[string]string{
+			TCPKeyFileParameter: "/path/to/key",
+		},
+	}
+	if invalid.EnsureValid() == nil {
+		t.Error("invalid URL classified as valid")
+	}
+}
@@ -0,0 +1,20 @@
+package wsl
+
+import (
+	"context"
+	"fmt"
+	"os/exec"
+)
+
+// Command prepares (but does not start) a WSL command with the specified
+// arguments and scoped to lifetime of the provided context.
+func Command(ctx context.Context, args ...string) (*exec.Cmd, error) {
+	// Identify the command path.
+	commandPath, err := commandPathForPlatform()
+	if err != nil {
+		return nil, fmt.Errorf("unable to identify 'wsl' command: %w", err)
+	}
+
+	// Create the command.
+	return exec.CommandContext(ctx, commandPath, args...), nil
+}
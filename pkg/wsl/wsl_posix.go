@@ -0,0 +1,13 @@
+//go:build !windows
+
+package wsl
+
+import (
+	"errors"
+)
+
+// commandPathForPlatform returns an error on non-Windows platforms, since WSL is
+// only available on Windows.
+func commandPathForPlatform() (string, error) {
+	return "", errors.New("WSL is only supported on Windows")
+}
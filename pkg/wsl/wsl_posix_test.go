@@ -0,0 +1,15 @@
+//go:build !windows
+
+package wsl
+
+import (
+	"context"
+	"testing"
+)
+
+// TestCommandUnsupported tests that Command fails on non-Windows platforms.
+func TestCommandUnsupported(t *testing.T) {
+	if _, err := Command(context.Background(), "--list"); err == nil {
+		t.Error("command created unexpectedly")
+	}
+}
@@ -0,0 +1,30 @@
+package wsl
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+)
+
+// commandPathForPlatform searches for the wsl command in the user's path,
+// falling back to its standard location in the system directory.
+func commandPathForPlatform() (string, error) {
+	// First, attempt to find the wsl executable using the PATH environment
+	// variable. If that works, use that result.
+	path, err := exec.LookPath("wsl.exe")
+	if err == nil {
+		return path, nil
+	}
+
+	// Otherwise, check the system directory, since the PATH environment
+	// variable may have been modified.
+	if systemRoot := os.Getenv("SystemRoot"); systemRoot != "" {
+		candidate := filepath.Join(systemRoot, "System32", "wsl.exe")
+		if _, statErr := os.Stat(candidate); statErr == nil {
+			return candidate, nil
+		}
+	}
+
+	// Failure.
+	return "", err
+}
@@ -0,0 +1,44 @@
+package wsl
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+// TestCommandSystemRootFallback tests that Command falls back to the system
+// directory if wsl.exe isn't found in the user's path.
+func TestCommandSystemRootFallback(t *testing.T) {
+	// Create a system directory containing wsl.exe and ensure that the user's
+	// path doesn't contain it.
+	systemRoot := t.TempDir()
+	if err := os.Mkdir(filepath.Join(systemRoot, "System32"), 0700); err != nil {
+		t.Fatal("unable to create system directory:", err)
+	}
+	executable := filepath.Join(systemRoot, "System32", "wsl.exe")
+	if err := os.WriteFile(executable, nil, 0700); err != nil {
+		t.Fatal("unable to create executable:", err)
+	}
+	t.Setenv("PATH", t.TempDir())
+	t.Setenv("SystemRoot", systemRoot)
+
+	// Verify command construction.
+	command, err := Command(context.Background(), "--list")
+	if err != nil {
+		t.Fatal("unable to create command:", err)
+	} else if command.Path != executable {
+		t.Error("command path does not match expected:", command.Path, "!=", executable)
+	} else if len(command.Args) != 2 || command.Args[1] != "--list" {
+		t.Error("command arguments do not match expected:", command.Args)
+	}
+}
+
+// TestCommandMissing tests that Command fails if wsl.exe can't be found.
+func TestCommandMissing(t *testing.T) {
+	t.Setenv("PATH", t.TempDir())
+	t.Setenv("SystemRoot", "")
+	if _, err := Command(context.Background()); err == nil {
+		t.Error("command created unexpectedly")
+	}
+}
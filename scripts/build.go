@@ -3,6 +3,9 @@ package main
 import (
 	"archive/tar"
 	"compress/gzip"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
 	"errors"
 	"fmt"
 	"io"
@@ -484,8 +487,35 @@ func copyFile(sourcePath, destinationPath string) error {
 	return nil
 }
 
+// fileDigest computes the hex-encoded SHA-256 digest of the file at the
+// specified path.
+func fileDigest(path string) (string, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return "", fmt.Errorf("unable to open file: %w", err)
+	}
+	defer file.Close()
+	hasher := sha256.New()
+	if _, err := io.Copy(hasher, file); err != nil {
+		return "", fmt.Errorf("unable to read file: %w", err)
+	}
+	return hex.EncodeToString(hasher.Sum(nil)), nil
+}
+
+// containsTarget returns whether or not a target is present in a list of
+// targets.
+func containsTarget(targets []Target, target Target) bool {
+	for _, t := range targets {
+		if t == target {
+			return true
+		}
+	}
+	return false
+}
+
 var usage = `usage: build [-h|--help] [-m|--mode=<mode>]
        [--macos-codesign-identity=<identity>]
+       [--agent-platforms=<platforms>] [--agent-download-url=<template>]
 
 The mode flag accepts four values: 'local', 'slim', 'release', and
 'release-slim'. 'local' will build CLI and agent binaries only for the current
@@ -500,6 +530,16 @@ to perform code signing on all macOS binaries in a fashion suitable for
 notarization by Apple. The codesign utility must be able to access the
 associated certificate and private keys in Keychain Access without a password if
 this script is operated in a non-interactive mode.
+
+If --agent-platforms specifies a comma-separated list of GOOS/GOARCH platforms,
+then only agents for those platforms will be included in the agent bundle. All
+other agents built for the mode will only be listed (by digest) in the bundle
+manifest so that they can be downloaded on demand and verified. In release
+modes, the excluded agents are written to the release directory for publishing.
+The --agent-download-url flag specifies the URL template that will be recorded
+in the bundle manifest for such downloads. The template may use the {version},
+{goos}, and {goarch} placeholders and can be overridden at runtime using the
+MUTAGEN_AGENT_DOWNLOAD_URL environment variable.
 `
 
 // build is the primary entry point.
@@ -507,9 +547,12 @@ func build() error {
 	// Parse command line arguments.
 	flagSet := pflag.NewFlagSet("build", pflag.ContinueOnError)
 	flagSet.SetOutput(io.Discard)
-	var mode, macosCodesignIdentity string
+	var mode, macosCodesignIdentity, agentDownloadURL string
+	var agentPlatforms []string
 	flagSet.StringVarP(&mode, "mode", "m", "slim", "specify the build mode")
 	flagSet.StringVar(&macosCodesignIdentity, "macos-codesign-identity", "", "specify the macOS code signing identity")
+	flagSet.StringSliceVar(&agentPlatforms, "agent-platforms", nil, "specify the agent platforms to include in the bundle")
+	flagSet.StringVar(&agentDownloadURL, "agent-download-url", "", "specify the download URL template for excluded agents")
 	if err := flagSet.Parse(os.Args[1:]); err != nil {
 		if err == pflag.ErrHelp {
 			fmt.Fprint(os.Stdout, usage)
@@ -598,6 +641,32 @@ func build() error {
 		agentTargets = append(agentTargets, target)
 	}
 
+	// Compute the agent targets to include in the agent bundle. If no agent
+	// platforms have been specified, then all agent targets are included.
+	bundledAgentTargets := agentTargets
+	if len(agentPlatforms) > 0 {
+		selected := make(map[string]bool, len(agentPlatforms))
+		for _, platform := range agentPlatforms {
+			var found bool
+			for _, target := range agentTargets {
+				if target.String() == platform {
+					found = true
+					break
+				}
+			}
+			if !found {
+				return fmt.Errorf("agent platform not built in %s mode: %s", mode, platform)
+			}
+			selected[platform] = true
+		}
+		bundledAgentTargets = nil
+		for _, target := range agentTargets {
+			if selected[target.String()] {
+				bundledAgentTargets = append(bundledAgentTargets, target)
+			}
+		}
+	}
+
 	// Compute CLI targets.
 	var cliTargets []Target
 	for _, target := range targets {
@@ -643,14 +712,43 @@ func build() error {
 		}
 	}
 
-	// Build the agent bundle.
+	// Build the agent bundle manifest, which records digests for all agent
+	// binaries (including those excluded from the bundle).
+	log.Println("Building agent bundle manifest...")
+	manifest := &agent.BundleManifest{
+		DownloadURL: agentDownloadURL,
+		Digests:     make(map[string]string, len(agentTargets)),
+	}
+	for _, target := range agentTargets {
+		agentBuildPath := filepath.Join(agentBuildSubdirectoryPath, target.Name())
+		digest, err := fileDigest(agentBuildPath)
+		if err != nil {
+			return fmt.Errorf("unable to compute agent digest: %w", err)
+		}
+		manifest.Digests[target.Name()] = digest
+	}
+	manifestBytes, err := json.Marshal(manifest)
+	if err != nil {
+		return fmt.Errorf("unable to encode agent bundle manifest: %w", err)
+	}
+	manifestPath := filepath.Join(buildPath, agent.BundleManifestName)
+	if err := os.WriteFile(manifestPath, manifestBytes, 0600); err != nil {
+		return fmt.Errorf("unable to write agent bundle manifest: %w", err)
+	}
+
+	// Build the agent bundle. We add the manifest first so that it's
+	// encountered before any excluded platform is searched for.
 	log.Println("Building agent bundle...")
 	agentBundlePath := filepath.Join(buildPath, agent.BundleName)
 	agentBundleBuilder, err := NewArchiveBuilder(agentBundlePath)
 	if err != nil {
 		return fmt.Errorf("unable to create agent bundle archive builder: %w", err)
 	}
-	for _, target := range agentTargets {
+	if err := agentBundleBuilder.Add(agent.BundleManifestName, manifestPath, 0644); err != nil {
+		agentBundleBuilder.Close()
+		return fmt.Errorf("unable to add manifest to bundle: %w", err)
+	}
+	for _, target := range bundledAgentTargets {
 		agentBuildPath := filepath.Join(agentBuildSubdirectoryPath, target.Name())
 		if err := agentBundleBuilder.Add(target.Name(), agentBuildPath, 0755); err != nil {
 			agentBundleBuilder.Close()
@@ -688,6 +786,25 @@ func build() error {
 				return fmt.Errorf("unable to finalize release bundle: %w", err)
 			}
 		}
+
+		// Copy agents excluded from the agent bundle so that they can be
+		// published for on-demand download.
+		if len(bundledAgentTargets) != len(agentTargets) {
+			log.Println("Copying excluded agents...")
+			for _, target := range agentTargets {
+				if containsTarget(bundledAgentTargets, target) {
+					continue
+				}
+				agentBuildPath := filepath.Join(agentBuildSubdirectoryPath, target.Name())
+				agentReleasePath := filepath.Join(
+					releaseBuildSubdirectoryPath,
+					fmt.Sprintf("%s_%s_v%s", agentBaseName, target.Name(), mutagen.Version),
+				)
+				if err := copyFile(agentBuildPath, agentReleasePath); err != nil {
+					return fmt.Errorf("unable to copy excluded agent: %w", err)
+				}
+			}
+		}
 	}
 
 	// Relocate the CLI binary for the current platform.
@@ -28,7 +28,7 @@ const (
 	cacheFile    = "cache_test"
 )
 
-var usage = `scan_bench [-h|--help] [-p|--profile] [-i|--ignore=<pattern>] <path>
+var usage = `scan_bench [-h|--help] [-p|--profile] [-i|--ignore=<pattern>] [-c|--concurrency=<n>] <path>
 `
 
 // ignoreCachesIntersectionEqual compares two ignore caches, ensuring that keys
@@ -60,8 +60,10 @@ func main() {
 	flagSet.SetOutput(io.Discard)
 	var ignores []string
 	var enableProfile bool
+	var concurrency uint32
 	flagSet.StringSliceVarP(&ignores, "ignore", "i", nil, "specify ignore paths")
 	flagSet.BoolVarP(&enableProfile, "profile", "p", false, "enable profiling")
+	flagSet.Uint32VarP(&concurrency, "concurrency", "c", 1, "specify scan concurrency")
 	if err := flagSet.Parse(os.Args[1:]); err != nil {
 		if err == pflag.ErrHelp {
 			fmt.Fprint(os.Stdout, usage)
@@ -110,10 +112,13 @@ func main() {
 		ctx,
 		path,
 		nil, nil,
-		sha1.New(), nil,
+		sha1.New, nil,
 		ignores, nil,
 		behavior.ProbeMode_ProbeModeProbe,
 		core.SymbolicLinkMode_SymbolicLinkModePortable,
+		core.UnicodeNormalizationMode_UnicodeNormalizationModeNone,
+		0,
+		concurrency,
 	)
 	if err != nil {
 		cmd.Fatal(fmt.Errorf("unable to perform cold scan: %w", err))
@@ -144,10 +149,13 @@ func main() {
 		ctx,
 		path,
 		nil, nil,
-		sha1.New(), cache,
+		sha1.New, cache,
 		ignores, ignoreCache,
 		behavior.ProbeMode_ProbeModeProbe,
 		core.SymbolicLinkMode_SymbolicLinkModePortable,
+		core.UnicodeNormalizationMode_UnicodeNormalizationModeNone,
+		0,
+		concurrency,
 	)
 	if err != nil {
 		cmd.Fatal(fmt.Errorf("unable to perform warm scan: %w", err))
@@ -186,10 +194,13 @@ func main() {
 		ctx,
 		path,
 		nil, nil,
-		sha1.New(), cache,
+		sha1.New, cache,
 		ignores, ignoreCache,
 		behavior.ProbeMode_ProbeModeProbe,
 		core.SymbolicLinkMode_SymbolicLinkModePortable,
+		core.UnicodeNormalizationMode_UnicodeNormalizationModeNone,
+		0,
+		concurrency,
 	)
 	if err != nil {
 		cmd.Fatal(fmt.Errorf("unable to perform second warm scan: %w", err))
@@ -226,10 +237,13 @@ func main() {
 		ctx,
 		path,
 		snapshot, map[string]bool{"fake path": true},
-		sha1.New(), cache,
+		sha1.New, cache,
 		ignores, ignoreCache,
 		behavior.ProbeMode_ProbeModeProbe,
 		core.SymbolicLinkMode_SymbolicLinkModePortable,
+		core.UnicodeNormalizationMode_UnicodeNormalizationModeNone,
+		0,
+		concurrency,
 	)
 	if err != nil {
 		cmd.Fatal(fmt.Errorf("unable to perform accelerated scan (with re-check paths): %w", err))
@@ -264,10 +278,13 @@ func main() {
 		ctx,
 		path,
 		snapshot, nil,
-		sha1.New(), cache,
+		sha1.New, cache,
 		ignores, ignoreCache,
 		behavior.ProbeMode_ProbeModeProbe,
 		core.SymbolicLinkMode_SymbolicLinkModePortable,
+		core.UnicodeNormalizationMode_UnicodeNormalizationModeNone,
+		0,
+		concurrency,
 	)
 	if err != nil {
 		cmd.Fatal(fmt.Errorf("unable to perform accelerated scan (without re-check paths): %w", err))